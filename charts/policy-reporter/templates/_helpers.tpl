@@ -113,3 +113,22 @@ maxUnavailable: {{ .Values.podDisruptionBudget.maxUnavailable }}
     {{- .Release.Namespace -}}
 {{- end -}}
 {{- end -}}
+
+{{/* Name of the environment variable of a custom field from the downward API. */}}
+{{- define "policyreporter.customFieldEnv" -}}
+{{- printf "CUSTOM_FIELD_%s" (regexReplaceAll "[^A-Z0-9]" (upper .) "_") -}}
+{{- end -}}
+
+{{/* Name of the TLS secret of the admission webhook. */}}
+{{- define "policyreporter.admissionSecretName" -}}
+{{- default (printf "%s-admission-tls" (include "policyreporter.fullname" .)) .Values.admissionWebhook.tlsSecretName -}}
+{{- end -}}
+
+{{/* Probe with the HTTPS scheme if TLS is enabled. */}}
+{{- define "policyreporter.probe" -}}
+{{- $probe := .probe -}}
+{{- if and .tls $probe.httpGet -}}
+{{- $probe = merge (dict "httpGet" (merge (dict "scheme" "HTTPS") $probe.httpGet)) $probe -}}
+{{- end -}}
+{{- toYaml $probe -}}
+{{- end -}}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/kyverno/policy-reporter/cmd/get"
+)
+
+func newGetCMD() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Query results, summaries and targets of a running Policy Reporter",
+		Long:  "Queries the REST API of a running Policy Reporter, by default through the service proxy of the Kubernetes API without port-forwarding.",
+	}
+
+	get.AddClientFlags(cmd.PersistentFlags())
+	cmd.PersistentFlags().StringP("output", "o", get.Table, "output format: table, json or yaml")
+
+	cmd.AddCommand(get.NewResultsCMD())
+	cmd.AddCommand(get.NewSummaryCMD())
+	cmd.AddCommand(get.NewTargetsCMD())
+
+	return cmd
+}
@@ -0,0 +1,122 @@
+package get
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kyverno/policy-reporter/pkg/api/client"
+)
+
+// Output formats
+const (
+	Table = "table"
+	JSON  = "json"
+	YAML  = "yaml"
+)
+
+// AddClientFlags adds the flags used by NewClient to connect to the API
+func AddClientFlags(flags *pflag.FlagSet) {
+	flags.StringP("kubeconfig", "k", "", "absolute path to the kubeconfig file, defaults to the KUBECONFIG environment or ~/.kube/config")
+	flags.String("server", "", "URL of the Policy Reporter API, e.g. http://localhost:8080, skips the Kubernetes service proxy")
+	flags.String("service", "policy-reporter/policy-reporter:8080", "namespace/name:port of the Policy Reporter service")
+	flags.String("token", "", "bearer token of the API authentication")
+}
+
+// NewClient for the configured server or the service proxy of the Kubernetes API
+func NewClient(cmd *cobra.Command) (*client.Client, error) {
+	server, _ := cmd.Flags().GetString("server")
+	token, _ := cmd.Flags().GetString("token")
+
+	options := make([]client.Option, 0, 2)
+	if token != "" {
+		options = append(options, client.WithToken(token))
+	}
+
+	if server != "" {
+		return client.New(server, options...), nil
+	}
+
+	service, _ := cmd.Flags().GetString("service")
+	path, err := proxyPath(service)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeconfig, _ := cmd.Flags().GetString("kubeconfig")
+
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfig
+
+	k8sConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := rest.TransportFor(k8sConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	options = append(options, client.WithHTTPClient(&http.Client{Transport: transport}))
+
+	return client.New(strings.TrimSuffix(k8sConfig.Host, "/")+path, options...), nil
+}
+
+// proxyPath of the service in the format namespace/name:port
+func proxyPath(service string) (string, error) {
+	namespace, name, ok := strings.Cut(service, "/")
+	if !ok || namespace == "" || name == "" {
+		return "", fmt.Errorf("invalid service '%s', expected namespace/name:port", service)
+	}
+
+	return fmt.Sprintf("/api/v1/namespaces/%s/services/%s/proxy", namespace, name), nil
+}
+
+// write the value as JSON or YAML, table formats are written by the table function
+func write(cmd *cobra.Command, value interface{}, table func(w io.Writer)) error {
+	output, _ := cmd.Flags().GetString("output")
+
+	switch output {
+	case JSON:
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(value)
+	case YAML:
+		content, err := yaml.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		_, err = cmd.OutOrStdout().Write(content)
+		return err
+	case Table:
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+		table(w)
+
+		return w.Flush()
+	}
+
+	return fmt.Errorf("unknown output format '%s', expected one of table, json, yaml", output)
+}
+
+// row writes the tab separated columns, empty values are shown as "-"
+func row(w io.Writer, columns ...string) {
+	for i, column := range columns {
+		if column == "" {
+			columns[i] = "-"
+		}
+	}
+
+	fmt.Fprintln(w, strings.Join(columns, "\t"))
+}
@@ -0,0 +1,89 @@
+package get
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyverno/policy-reporter/pkg/api/client"
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+)
+
+type resultFlags struct {
+	namespaces    []string
+	clusterScoped bool
+	status        []string
+	severities    []string
+	policies      []string
+	sources       []string
+	kinds         []string
+	filter        string
+	limit         int
+}
+
+// NewResultsCMD lists namespaced or cluster scoped results
+func NewResultsCMD() *cobra.Command {
+	flags := resultFlags{}
+
+	cmd := &cobra.Command{
+		Use:     "results",
+		Aliases: []string{"result"},
+		Short:   "List the results of namespaced or cluster scoped resources",
+		Example: "policyreporter get results -n default --status fail --severity high -o json",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := NewClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			var list *v1.ResultList
+			if flags.clusterScoped {
+				list, err = c.ListClusterResults(cmd.Context(), &client.ListClusterResultsParams{
+					Status:     flags.status,
+					Severities: flags.severities,
+					Policies:   flags.policies,
+					Sources:    flags.sources,
+					Kinds:      flags.kinds,
+					Filter:     flags.filter,
+					Page:       1,
+					Offset:     flags.limit,
+				})
+			} else {
+				list, err = c.ListNamespacedResults(cmd.Context(), &client.ListNamespacedResultsParams{
+					Namespaces: flags.namespaces,
+					Status:     flags.status,
+					Severities: flags.severities,
+					Policies:   flags.policies,
+					Sources:    flags.sources,
+					Kinds:      flags.kinds,
+					Filter:     flags.filter,
+					Page:       1,
+					Offset:     flags.limit,
+				})
+			}
+			if err != nil {
+				return err
+			}
+
+			return write(cmd, list, func(w io.Writer) {
+				row(w, "NAMESPACE", "KIND", "NAME", "POLICY", "RULE", "STATUS", "SEVERITY")
+				for _, r := range list.Items {
+					row(w, r.Namespace, r.Kind, r.Name, r.Policy, r.Rule, r.Status, r.Severity)
+				}
+			})
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&flags.namespaces, "namespace", "n", nil, "namespaces of the results, defaults to all namespaces")
+	cmd.Flags().BoolVar(&flags.clusterScoped, "cluster-scoped", false, "list the results of cluster scoped resources")
+	cmd.Flags().StringSliceVar(&flags.status, "status", nil, "status of the results, e.g. fail,warn")
+	cmd.Flags().StringSliceVar(&flags.severities, "severity", nil, "severities of the results, e.g. high,critical")
+	cmd.Flags().StringSliceVar(&flags.policies, "policy", nil, "policies of the results")
+	cmd.Flags().StringSliceVar(&flags.sources, "source", nil, "sources of the results, e.g. kyverno")
+	cmd.Flags().StringSliceVar(&flags.kinds, "kind", nil, "resource kinds of the results")
+	cmd.Flags().StringVar(&flags.filter, "filter", "", "filter expression of the results")
+	cmd.Flags().IntVar(&flags.limit, "limit", 50, "maximum number of results, 0 lists all results")
+
+	return cmd
+}
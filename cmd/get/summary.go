@@ -0,0 +1,75 @@
+package get
+
+import (
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyverno/policy-reporter/pkg/api/client"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// NewSummaryCMD shows the result counts per namespace and status
+func NewSummaryCMD() *cobra.Command {
+	var namespaces, sources []string
+
+	cmd := &cobra.Command{
+		Use:     "summary",
+		Short:   "Show the result counts per namespace and status, cluster scoped results are shown without namespace",
+		Example: "policyreporter get summary --source kyverno",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := NewClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			counts, err := c.GetNamespacedStatusCounts(cmd.Context(), &client.GetNamespacedStatusCountsParams{
+				Namespaces:   namespaces,
+				Sources:      sources,
+				ClusterScope: "true",
+			})
+			if err != nil {
+				return err
+			}
+
+			return write(cmd, counts, func(w io.Writer) {
+				statuses := []string{v1alpha2.StatusPass, v1alpha2.StatusFail, v1alpha2.StatusWarn, v1alpha2.StatusError, v1alpha2.StatusSkip}
+				summary := make(map[string]map[string]int)
+
+				for _, count := range counts {
+					for _, item := range count.Items {
+						if _, ok := summary[item.Namespace]; !ok {
+							summary[item.Namespace] = make(map[string]int, len(statuses))
+						}
+
+						summary[item.Namespace][count.Status] += item.Count
+					}
+				}
+
+				names := make([]string, 0, len(summary))
+				for namespace := range summary {
+					names = append(names, namespace)
+				}
+				sort.Strings(names)
+
+				row(w, "NAMESPACE", "PASS", "FAIL", "WARN", "ERROR", "SKIP")
+				for _, namespace := range names {
+					columns := []string{namespace}
+					for _, status := range statuses {
+						columns = append(columns, strconv.Itoa(summary[namespace][status]))
+					}
+
+					row(w, columns...)
+				}
+			})
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&namespaces, "namespace", "n", nil, "namespaces of the summary, defaults to all namespaces")
+	cmd.Flags().StringSliceVar(&sources, "source", nil, "sources of the counted results, e.g. kyverno")
+
+	return cmd
+}
@@ -0,0 +1,52 @@
+package get
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// NewTargetsCMD lists the configured targets with their last delivery
+func NewTargetsCMD() *cobra.Command {
+	return &cobra.Command{
+		Use:     "targets",
+		Aliases: []string{"target"},
+		Short:   "List the configured targets with their filters and last delivery",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := NewClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			targets, err := c.ListTargetStatus(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			return write(cmd, targets, func(w io.Writer) {
+				row(w, "NAME", "MINIMUM PRIORITY", "SOURCES", "LAST SUCCESS", "LAST ERROR", "ERROR")
+				for _, t := range targets {
+					var lastSuccess, lastError *time.Time
+					var message string
+
+					if t.Delivery != nil {
+						lastSuccess, lastError, message = t.Delivery.LastSuccess, t.Delivery.LastError, t.Delivery.Error
+					}
+
+					row(w, t.Name, t.MinimumPriority, strings.Join(t.Sources, ","), timestamp(lastSuccess), timestamp(lastError), message)
+				}
+			})
+		},
+	}
+}
+
+func timestamp(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/kyverno/policy-reporter/pkg/bootstrap"
+	"github.com/kyverno/policy-reporter/pkg/config"
+)
+
+func newInitCMD() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Generate a validated configuration file",
+		Long: `Generates a configuration file with the selected targets, filters and store settings and validates it before it is written.
+Use --interactive to answer the options on the terminal and --apply to create the ServiceAccount and ClusterRole required to read the PolicyReports.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString("config")
+			force, _ := cmd.Flags().GetBool("force")
+
+			if _, err := os.Stat(output); err == nil && !force {
+				return fmt.Errorf("%s already exists, use --force to overwrite it", output)
+			}
+
+			options, err := initOptions(cmd)
+			if err != nil {
+				return err
+			}
+
+			if interactive, _ := cmd.Flags().GetBool("interactive"); interactive {
+				promptOptions(bufio.NewReader(cmd.InOrStdin()), cmd.OutOrStdout(), &options)
+			}
+
+			content, err := bootstrap.Render(options)
+			if err != nil {
+				return err
+			}
+
+			if err := writeConfig(output, content); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "configuration written to %s\n", output)
+
+			if apply, _ := cmd.Flags().GetBool("apply"); apply {
+				return applyRBAC(cmd)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("config", "c", "config.yaml", "path of the generated configuration file")
+	cmd.Flags().Bool("force", false, "overwrite an existing configuration file")
+	cmd.Flags().BoolP("interactive", "i", false, "ask for the options on the terminal, flags are used as defaults")
+	cmd.Flags().StringArray("target", nil, fmt.Sprintf("target as <type>=<url>, supported types: %s", strings.Join(bootstrap.TargetNames(), ", ")))
+	cmd.Flags().String("minimum-priority", "", "minimum priority of the results sent to the targets")
+	cmd.Flags().StringSlice("sources", nil, "sources of the results sent to the targets, all sources if empty")
+	cmd.Flags().Bool("skip-existing", true, "skip the results existing on startup")
+	cmd.Flags().String("database", "sqlite", fmt.Sprintf("database type, one of %s", strings.Join(bootstrap.Databases, ", ")))
+	cmd.Flags().String("database-dsn", "", "connection string of a postgres, mysql or mariadb database")
+	cmd.Flags().Bool("rest-enabled", false, "enable the REST API")
+	cmd.Flags().Bool("metrics-enabled", false, "enable the Prometheus metrics")
+	cmd.Flags().String("cluster-name", "", "name of the cluster used in notifications")
+	cmd.Flags().Bool("apply", false, "create the namespace, ServiceAccount, ClusterRole and ClusterRoleBinding in the cluster")
+	cmd.Flags().StringP("namespace", "n", "policy-reporter", "namespace of the ServiceAccount")
+	cmd.Flags().String("service-account", "policy-reporter", "name of the ServiceAccount, ClusterRole and ClusterRoleBinding")
+	cmd.Flags().StringP("kubeconfig", "k", "", "absolute path to the kubeconfig file")
+
+	return cmd
+}
+
+func initOptions(cmd *cobra.Command) (bootstrap.Options, error) {
+	options := bootstrap.Options{Targets: make(map[string]string)}
+
+	targets, _ := cmd.Flags().GetStringArray("target")
+	for _, t := range targets {
+		name, url, err := bootstrap.ParseTarget(t)
+		if err != nil {
+			return options, err
+		}
+
+		options.Targets[name] = url
+	}
+
+	options.MinimumPriority, _ = cmd.Flags().GetString("minimum-priority")
+	options.Sources, _ = cmd.Flags().GetStringSlice("sources")
+	options.SkipExisting, _ = cmd.Flags().GetBool("skip-existing")
+	options.Database, _ = cmd.Flags().GetString("database")
+	options.DatabaseDSN, _ = cmd.Flags().GetString("database-dsn")
+	options.REST, _ = cmd.Flags().GetBool("rest-enabled")
+	options.Metrics, _ = cmd.Flags().GetBool("metrics-enabled")
+	options.ClusterName, _ = cmd.Flags().GetString("cluster-name")
+
+	return options, nil
+}
+
+// promptOptions asks for each option, an empty answer keeps the current value
+func promptOptions(in *bufio.Reader, out io.Writer, options *bootstrap.Options) {
+	ask := func(question, current string) string {
+		fmt.Fprintf(out, "%s [%s]: ", question, current)
+
+		answer, _ := in.ReadString('\n')
+		if answer = strings.TrimSpace(answer); answer != "" {
+			return answer
+		}
+
+		return current
+	}
+	confirm := func(question string, current bool) bool {
+		def := "n"
+		if current {
+			def = "y"
+		}
+
+		answer := strings.ToLower(ask(question+" (y/n)", def))
+
+		return answer == "y" || answer == "yes"
+	}
+
+	options.ClusterName = ask("Cluster name", options.ClusterName)
+	for _, name := range bootstrap.TargetNames() {
+		if url := ask(fmt.Sprintf("URL of the %s target, empty to skip", name), options.Targets[name]); url != "" {
+			options.Targets[name] = url
+		}
+	}
+	options.MinimumPriority = ask("Minimum priority of sent results", options.MinimumPriority)
+	if sources := ask("Sources of sent results, comma separated", strings.Join(options.Sources, ",")); sources != "" {
+		options.Sources = strings.Split(sources, ",")
+	}
+	options.SkipExisting = confirm("Skip results existing on startup", options.SkipExisting)
+	options.Database = ask(fmt.Sprintf("Database (%s)", strings.Join(bootstrap.Databases, ", ")), options.Database)
+	if options.Database != "sqlite" && options.Database != "memory" {
+		options.DatabaseDSN = ask("Database connection string", options.DatabaseDSN)
+	}
+	options.REST = confirm("Enable the REST API", options.REST)
+	options.Metrics = confirm("Enable the Prometheus metrics", options.Metrics)
+}
+
+// writeConfig validates the configuration before it replaces the file
+func writeConfig(path string, content []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".policy-reporter-*.yaml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	c, err := config.LoadFile(tmp.Name())
+	if err != nil {
+		return err
+	}
+	if err := config.Validate(c); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func applyRBAC(cmd *cobra.Command) error {
+	kubeconfig, _ := cmd.Flags().GetString("kubeconfig")
+	namespace, _ := cmd.Flags().GetString("namespace")
+	name, _ := cmd.Flags().GetString("service-account")
+
+	var k8sConfig *rest.Config
+	var err error
+	if kubeconfig != "" {
+		k8sConfig, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	} else {
+		k8sConfig, err = rest.InClusterConfig()
+	}
+	if err != nil {
+		return err
+	}
+
+	client, err := k8s.NewForConfig(k8sConfig)
+	if err != nil {
+		return err
+	}
+
+	if err := bootstrap.Apply(cmd.Context(), client, namespace, name); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "serviceaccount %s/%s and clusterrole %s applied\n", namespace, name, name)
+
+	missing, err := bootstrap.MissingCRDs(client.Discovery())
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "the %s CRDs (%s) are not installed, they are provided by Kyverno or another PolicyReport producer\n", bootstrap.ReportGroupVersion, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/client/clientset/versioned"
+	"github.com/kyverno/policy-reporter/pkg/crd/client/clientset/versioned/fake"
+	"github.com/kyverno/policy-reporter/pkg/loadgen"
+)
+
+func newLoadGenCMD() *cobra.Command {
+	options := loadgen.Options{}
+	var kubeconfig string
+	var fakeClient, cleanup bool
+
+	cmd := &cobra.Command{
+		Use:   "loadgen",
+		Short: "Create synthetic PolicyReports for capacity planning",
+		Long: `Creates synthetic PolicyReports with the configured number of namespaces, reports and results at a fixed rate
+and updates them afterwards, to measure the resource usage of Policy Reporter for large clusters. Namespaces and
+reports are labeled with ` + loadgen.Label + `, generated namespaces can be removed with "kubectl delete ns -l ` + loadgen.Label + `".
+With --fake the reports are applied to an in-memory client to measure the generator itself.`,
+		Example: "policyreporter loadgen --namespaces 500 --reports 50000 --results 20 --rate 200 --updates 2",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newLoadGenClient(kubeconfig, fakeClient)
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			out := cmd.OutOrStdout()
+
+			if cleanup {
+				stats := loadgen.Cleanup(ctx, client, options)
+				_, err := fmt.Fprintf(out, "deleted %d reports in %s, %d errors\n", stats.Deleted, stats.Duration, stats.Errors)
+				return err
+			}
+
+			_, err = fmt.Fprintln(out, loadgen.Run(ctx, client, options))
+			return err
+		},
+	}
+
+	cmd.Flags().StringVarP(&kubeconfig, "kubeconfig", "k", "", "absolute path to the kubeconfig file, defaults to the KUBECONFIG environment or ~/.kube/config")
+	cmd.Flags().BoolVar(&fakeClient, "fake", false, "apply the reports to an in-memory client instead of a cluster")
+	cmd.Flags().BoolVar(&cleanup, "cleanup", false, "delete the generated reports instead of creating them")
+	cmd.Flags().IntVar(&options.Namespaces, "namespaces", loadgen.DefaultNamespaces, "number of namespaces the reports are distributed over")
+	cmd.Flags().IntVar(&options.Reports, "reports", loadgen.DefaultReports, "number of PolicyReports")
+	cmd.Flags().IntVar(&options.Results, "results", loadgen.DefaultResults, "number of results per PolicyReport")
+	cmd.Flags().Float64Var(&options.FailRatio, "fail-ratio", loadgen.DefaultFailRatio, "ratio of fail results")
+	cmd.Flags().Float64Var(&options.Rate, "rate", 0, "applied PolicyReports per second, unlimited if 0")
+	cmd.Flags().IntVar(&options.Updates, "updates", 0, "number of updates of all PolicyReports after they were created")
+	cmd.Flags().StringVar(&options.Source, "source", loadgen.DefaultSource, "source of the generated results")
+	cmd.Flags().StringVar(&options.Prefix, "prefix", loadgen.DefaultPrefix, "prefix of the generated namespaces")
+
+	return cmd
+}
+
+func newLoadGenClient(kubeconfig string, fakeClient bool) (loadgen.Client, error) {
+	if fakeClient {
+		return loadgen.NewCRDClient(fake.NewSimpleClientset().Wgpolicyk8sV1alpha2(), k8sfake.NewSimpleClientset().CoreV1().Namespaces()), nil
+	}
+
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfig
+
+	k8sConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// the default client side rate limit would limit the generated load
+	k8sConfig.QPS = 1000
+	k8sConfig.Burst = 2000
+
+	reports, err := versioned.NewForConfig(k8sConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	k8s, err := kubernetes.NewForConfig(k8sConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadgen.NewCRDClient(reports.Wgpolicyk8sV1alpha2(), k8s.CoreV1().Namespaces()), nil
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyverno/policy-reporter/cmd/get"
+	"github.com/kyverno/policy-reporter/pkg/api/client"
+)
+
+func newReplayCMD() *cobra.Command {
+	params := client.ReplayResultsParams{}
+
+	cmd := &cobra.Command{
+		Use:     "replay TARGET",
+		Short:   "Send stored results to a target again",
+		Long:    "Sends the stored results matching the filters and time range to a target of a running Policy Reporter again, e.g. to backfill a downstream system which lost data. The filters and the minimum priority of the target are applied.",
+		Example: "policyreporter replay Loki --from 2024-01-01T00:00:00Z --to 2024-01-02T00:00:00Z -n default --severity high,critical",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := get.NewClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			params.Target = args[0]
+
+			replay, err := c.ReplayResults(cmd.Context(), &params)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "sent %d of %d results to %s, %d skipped by the target filters\n", replay.Sent, replay.Results, replay.Name, replay.Skipped)
+			return err
+		},
+	}
+
+	get.AddClientFlags(cmd.Flags())
+	cmd.Flags().StringVar(&params.From, "from", "", "RFC3339 or unix timestamp of the oldest result, defaults to all results")
+	cmd.Flags().StringVar(&params.To, "to", "", "RFC3339 or unix timestamp of the newest result, defaults to now")
+	cmd.Flags().StringSliceVarP(&params.Namespaces, "namespace", "n", nil, "namespaces of the results, defaults to all namespaces")
+	cmd.Flags().StringSliceVar(&params.Status, "status", nil, "status of the results, e.g. fail,warn")
+	cmd.Flags().StringSliceVar(&params.Severities, "severity", nil, "severities of the results, e.g. high,critical")
+	cmd.Flags().StringSliceVar(&params.Policies, "policy", nil, "policies of the results")
+	cmd.Flags().StringSliceVar(&params.Sources, "source", nil, "sources of the results, e.g. kyverno")
+	cmd.Flags().StringVar(&params.Filter, "filter", "", "filter expression of the results")
+
+	return cmd
+}
@@ -15,6 +15,13 @@ func NewCLI() *cobra.Command {
 
 	rootCmd.AddCommand(newRunCMD())
 	rootCmd.AddCommand(newSendCMD())
+	rootCmd.AddCommand(newGetCMD())
+	rootCmd.AddCommand(newReplayCMD())
+	rootCmd.AddCommand(newWatchCMD())
+	rootCmd.AddCommand(newValidateConfigCMD())
+	rootCmd.AddCommand(newLoadGenCMD())
+	rootCmd.AddCommand(newStoreCMD())
+	rootCmd.AddCommand(newInitCMD())
 
 	return rootCmd
 }
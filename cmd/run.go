@@ -2,8 +2,15 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"flag"
-	"log"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"golang.org/x/sync/errgroup"
@@ -11,10 +18,29 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog"
 
+	"github.com/kyverno/policy-reporter/pkg/acknowledgment"
+	"github.com/kyverno/policy-reporter/pkg/admission"
+	"github.com/kyverno/policy-reporter/pkg/api"
+	"github.com/kyverno/policy-reporter/pkg/audit"
 	"github.com/kyverno/policy-reporter/pkg/config"
+	"github.com/kyverno/policy-reporter/pkg/exclusion"
+	"github.com/kyverno/policy-reporter/pkg/fields"
+	"github.com/kyverno/policy-reporter/pkg/health"
+	"github.com/kyverno/policy-reporter/pkg/kyverno"
 	"github.com/kyverno/policy-reporter/pkg/listener"
+	"github.com/kyverno/policy-reporter/pkg/listener/metrics"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/push"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/rpc"
+	"github.com/kyverno/policy-reporter/pkg/severity"
+	"github.com/kyverno/policy-reporter/pkg/sqlite3"
+	"github.com/kyverno/policy-reporter/pkg/timeformat"
+	"github.com/kyverno/policy-reporter/pkg/tracing"
 )
 
+var logger = logging.Component("cmd")
+
 func newRunCMD() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "run",
@@ -25,59 +51,578 @@ func newRunCMD() *cobra.Command {
 				return err
 			}
 
-			var k8sConfig *rest.Config
-			if c.K8sClient.Kubeconfig != "" {
-				k8sConfig, err = clientcmd.BuildConfigFromFlags("", c.K8sClient.Kubeconfig)
-			} else {
-				k8sConfig, err = rest.InClusterConfig()
-			}
+			logs, err := logging.Use(os.Stderr, c.Logging.Encoding, c.Logging.Level, c.Logging.Components)
 			if err != nil {
 				return err
 			}
 
-			k8sConfig.QPS = c.K8sClient.QPS
-			k8sConfig.Burst = c.K8sClient.Burst
+			if err := timeformat.Use(c.Timestamps.Format, c.Timestamps.Timezone); err != nil {
+				return err
+			}
+
+			if err := severity.Use(config.SeverityLevelsFromConfig(c.Severities)); err != nil {
+				return err
+			}
+
+			if err := fields.Use(config.FieldMappingsFromConfig(c.PropertyFields)); err != nil {
+				return err
+			}
+
+			if err := config.Validate(c); err != nil {
+				if strict, _ := cmd.Flags().GetBool("strict"); strict {
+					return err
+				}
+
+				logger.Warnf("%s", err)
+			}
+
+			var k8sConfig *rest.Config
+			if c.IsStandalone() {
+				// the cluster features would fail on each access without a cluster
+				if features := config.ClusterFeatures(c); len(features) > 0 {
+					return fmt.Errorf("standalone mode does not support %s", strings.Join(features, ", "))
+				}
+
+				logger.Infof("standalone mode, read PolicyReports from %s", c.Standalone.ReportsDir)
+			} else if c.IsAPIOnly() {
+				// the ingesting instance processes the PolicyReports and writes them to the shared database
+				if features := config.IngestionFeatures(c); len(features) > 0 {
+					return fmt.Errorf("api-only mode does not support %s", strings.Join(features, ", "))
+				}
+				if sqlite3.DialectFor(c.Database.Type) == sqlite3.SQLite {
+					return fmt.Errorf("api-only mode requires an external database, got %s", c.Database.Type)
+				}
+
+				logger.Infof("api-only mode, serve the API of the shared %s database", c.Database.Type)
+			}
+
+			// without informers the api-only mode only requires a cluster for the kyverno policies and the kubernetes authentication
+			if !c.IsStandalone() && (!c.IsAPIOnly() || c.Kyverno.Enabled || c.API.Auth.Enabled && c.API.Auth.Kubernetes.Enabled) {
+				if c.K8sClient.Kubeconfig != "" {
+					k8sConfig, err = clientcmd.BuildConfigFromFlags("", c.K8sClient.Kubeconfig)
+				} else {
+					k8sConfig, err = rest.InClusterConfig()
+				}
+				if err != nil {
+					return err
+				}
+
+				k8sConfig.QPS = c.K8sClient.QPS
+				k8sConfig.Burst = c.K8sClient.Burst
+			}
 
 			resolver := config.NewResolver(c, k8sConfig)
+			defer resolver.Close()
+
+			ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+
+			var client report.PolicyReportClient
+			synced := func() bool { return true }
+
+			if c.IsAPIOnly() {
+				if resolver.HasTargets() {
+					return errors.New("api-only mode does not send results to targets, configure them on the ingesting instance")
+				}
+			} else {
+				client, err = resolver.PolicyReportClient()
+				if err != nil {
+					return err
+				}
 
-			client, err := resolver.PolicyReportClient()
+				synced = client.HasSynced
+			}
+
+			server := resolver.APIServer(synced)
+
+			g := &errgroup.Group{}
+
+			certificate, err := resolver.APICertificate()
 			if err != nil {
 				return err
 			}
+			if certificate != nil {
+				version, err := api.TLSVersion(c.API.TLS.MinVersion)
+				if err != nil {
+					return err
+				}
 
-			server := resolver.APIServer(client.HasSynced)
+				logger.Infof("API served with TLS, certificate %s", c.API.TLS.CertFile)
+				server.RegisterTLS(certificate.TLSConfig(version))
 
-			g := &errgroup.Group{}
+				if c.API.TLS.ReloadInterval > 0 {
+					g.Go(func() error {
+						return certificate.Run(ctx, c.API.TLS.ReloadInterval)
+					})
+				}
+			}
+
+			var grpcServer *rpc.Server
+			var webhook *admission.Webhook
+
+			if c.Exclusions.Enabled {
+				dynamicClient, err := resolver.DynamicClient()
+				if err != nil {
+					return err
+				}
+
+				exclusions := resolver.ExclusionStore()
+				if err := exclusion.Load(ctx, dynamicClient, exclusions); err != nil {
+					logger.Errorf("failed to load result exclusions: %s", err)
+				}
+
+				logger.Info("result exclusions enabled")
+				g.Go(func() error {
+					return exclusion.Watch(ctx, dynamicClient, exclusions)
+				})
+			}
+
+			if c.PolicyMetadata.Enabled {
+				dynamicClient, err := resolver.DynamicClient()
+				if err != nil {
+					return err
+				}
+
+				metadata := resolver.PolicyMetadataStore()
+				if err := kyverno.Load(ctx, dynamicClient, metadata); err != nil {
+					logger.Errorf("failed to load kyverno policy metadata: %s", err)
+				}
+
+				logger.Info("kyverno policy metadata enabled")
+				g.Go(func() error {
+					return kyverno.Watch(ctx, dynamicClient, metadata)
+				})
+			}
+
+			if c.Kyverno.Enabled {
+				dynamicClient, err := resolver.DynamicClient()
+				if err != nil {
+					return err
+				}
+
+				catalog := resolver.KyvernoCatalog()
+				if err := kyverno.LoadCatalog(ctx, dynamicClient, catalog, c.Kyverno.ExceptionVersion); err != nil {
+					logger.Errorf("failed to load kyverno policies and exceptions: %s", err)
+				}
+
+				g.Go(func() error {
+					return kyverno.WatchCatalog(ctx, dynamicClient, catalog, c.Kyverno.ExceptionVersion)
+				})
+			}
+
+			if resolver.HasMemoryStore() && c.REST.Enabled {
+				store := resolver.MemoryStore()
+				resolver.RegisterStoreListener(store)
 
-			if c.REST.Enabled {
+				logger.Info("REST api enabled with in-memory store, only taxonomy, compliance score and stream endpoints are available")
+				server.RegisterV1TaxonomyHandler(store, resolver.Taxonomy())
+
+				resolver.RegisterResultStreamListener()
+				server.RegisterV2StreamHandler(resolver.ResultStreamBroker(), c.API.AllowedOrigins)
+
+				if c.Score.Enabled {
+					logger.Info("compliance score api enabled")
+					server.RegisterV1ScoreHandler(store, resolver.ScoreWeights())
+				}
+			} else if !resolver.HasMemoryStore() && (c.REST.Enabled || c.GRPC.Enabled || c.Escalation.Enabled || c.EmailReports.Subscriptions.Enabled || c.Snooze.Enabled) {
 				db, err := resolver.Database()
 				if err != nil {
 					return err
 				}
-				defer db.Close()
+
+				server.RegisterHealthCheck("database", true, health.Ping(db))
 
 				store, err := resolver.PolicyReportStore(db)
 				if err != nil {
 					return err
 				}
 
-				log.Println("[INFO] REST api enabled")
-				resolver.RegisterStoreListener(store)
-				server.RegisterV1Handler(store)
+				// subscriptions and snoozes only use the database to persist their state
+				if (c.REST.Enabled || c.GRPC.Enabled || c.Escalation.Enabled) && !c.IsAPIOnly() {
+					resolver.RegisterStoreListener(store)
+				}
+
+				if c.REST.Enabled {
+					logger.Info("REST api enabled")
+					server.RegisterV1Handler(store)
+					server.RegisterV2Handler(store)
+					if resolver.HasTargets() {
+						server.RegisterTargetReplayHandler(store, resolver.Mapper())
+					}
+					server.RegisterV1TaxonomyHandler(store, resolver.Taxonomy())
+
+					resolver.RegisterResultStreamListener()
+					server.RegisterV2StreamHandler(resolver.ResultStreamBroker(), c.API.AllowedOrigins)
+
+					if c.Score.Enabled {
+						logger.Info("compliance score api enabled")
+						server.RegisterV1ScoreHandler(store, resolver.ScoreWeights())
+					}
+
+					if c.Exclusions.Enabled {
+						server.RegisterV1ExclusionHandler(resolver.ExclusionStore(), store)
+					}
+
+					if c.Kyverno.Enabled {
+						logger.Info("kyverno policy api enabled")
+						server.RegisterKyvernoHandler(resolver.KyvernoCatalog(), store)
+					}
+
+					if c.Federation.Enabled {
+						logger.Info("federation enabled, receive PolicyReports of edge instances")
+						server.RegisterFederationHandler(resolver.FederationReceiver(store), store, c.Federation.ClusterName)
+
+						if c.Metrics.Enabled {
+							resolver.RegisterFederationMetrics(store)
+						}
+					}
+				}
+
+				if c.GRPC.Enabled {
+					logger.Info("gRPC api enabled")
+					grpcCertificate, err := resolver.GRPCCertificate()
+					if err != nil {
+						return err
+					}
+
+					grpcServer, err = resolver.GRPCServer(store, grpcCertificate)
+					if err != nil {
+						return err
+					}
+					resolver.RegisterGRPCWatchListener(grpcServer)
+
+					if grpcCertificate != nil {
+						logger.Infof("gRPC api served with TLS, certificate %s", c.GRPC.TLS.CertFile)
+
+						if c.GRPC.TLS.ReloadInterval > 0 {
+							g.Go(func() error {
+								return grpcCertificate.Run(ctx, c.GRPC.TLS.ReloadInterval)
+							})
+						}
+					}
+
+					g.Go(grpcServer.Start)
+				}
+
+				if c.History.Enabled {
+					if c.REST.Enabled {
+						logger.Info("history api enabled")
+						server.RegisterV1HistoryHandler(store)
+						server.RegisterV2HistoryHandler(store)
+					}
+
+					if !c.IsAPIOnly() {
+						g.Go(func() error {
+							return sqlite3.RunHistoryPruning(ctx, store, c.History.Retention, c.History.PruneInterval)
+						})
+					}
+				}
+
+				if c.Triage.Enabled && c.REST.Enabled {
+					logger.Info("result triage api enabled")
+					server.RegisterTriageHandler(store, store)
+				}
+
+				if c.Snooze.Enabled {
+					snoozes := resolver.SnoozeRegistry(store)
+					if err := snoozes.Load(); err != nil {
+						logger.Errorf("failed to load snoozes: %s", err)
+					}
+
+					logger.Info("result snooze enabled")
+					if c.REST.Enabled {
+						server.RegisterSnoozeHandler(snoozes)
+					}
+
+					g.Go(func() error {
+						return snoozes.Run(ctx, c.Snooze.RefreshInterval)
+					})
+				}
+
+				if c.Tombstones.Enabled {
+					if c.REST.Enabled {
+						logger.Info("tombstone api enabled")
+						server.RegisterV2TombstoneHandler(store)
+					}
+
+					if !c.IsAPIOnly() {
+						g.Go(func() error {
+							return sqlite3.RunTombstonePruning(ctx, store, c.Tombstones.Retention, c.Tombstones.PruneInterval)
+						})
+					}
+				}
+
+				if c.Pruning.Enabled && !c.IsAPIOnly() {
+					limits := resolver.StoreLimits()
+					observe := resolver.StorePruningObserver()
+
+					logger.Infof("store pruning enabled, check limits every %s", c.Pruning.Interval)
+					g.Go(func() error {
+						return sqlite3.RunPruning(ctx, store, limits, c.Pruning.Interval, observe)
+					})
+				}
+
+				if c.Escalation.Enabled {
+					escalator, err := resolver.Escalator(store)
+					if err != nil {
+						return err
+					}
+
+					logger.Infof("escalation enabled, evaluate %d rules every %s", len(c.Escalation.Rules), c.Escalation.Interval)
+					g.Go(func() error {
+						return escalator.Run(ctx, c.Escalation.Interval)
+					})
+				}
+
+				if c.EmailReports.Subscriptions.Enabled {
+					logger.Info("email subscriptions api enabled")
+					server.RegisterSubscriptionHandler(resolver.SubscriptionManager(store))
+				}
+
+				if c.SeverityAging.Enabled {
+					ager, err := resolver.Ager()
+					if err != nil {
+						return err
+					}
+
+					// the stored first seen times keep the age of the results across restarts
+					if err := ager.Load(store); err != nil {
+						logger.Errorf("failed to load the first seen times of unresolved results: %s", err)
+					}
+				}
 			}
 
-			if c.Metrics.Enabled {
-				log.Println("[INFO] metrics enabled")
+			if len(c.Sources.Disabled) > 0 || len(c.Sources.Precedence) > 0 {
+				logger.Infof("sources disabled: %v, precedence of overlapping sources: %v", c.Sources.Disabled, c.Sources.Precedence)
+				resolver.RegisterSourceFilterListener()
+			}
+
+			if c.SeverityAging.Enabled {
+				if err := resolver.RegisterAgingListener(); err != nil {
+					return err
+				}
+
+				ager, err := resolver.Ager()
+				if err != nil {
+					return err
+				}
+
+				logger.Infof("severity aging enabled, evaluate %d rules every %s", len(c.SeverityAging.Rules), c.SeverityAging.Interval)
+				g.Go(func() error {
+					return ager.Run(ctx, c.SeverityAging.Interval)
+				})
+			}
+
+			provider, err := resolver.TracerProvider()
+			if err != nil {
+				return err
+			}
+			if provider != nil {
+				logger.Infof("tracing enabled, export %.0f%% of the traces to %s", c.Tracing.SampleRatio*100, c.Tracing.Endpoint)
+				tracing.Use(provider)
+
+				g.Go(func() error {
+					<-ctx.Done()
+
+					shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+					defer cancel()
+
+					if err := provider.Shutdown(shutdownCtx); err != nil {
+						logger.Errorf("failed to export spans: %s", err)
+					}
+
+					return nil
+				})
+			}
+
+			auditLog, err := resolver.AuditLog()
+			if err != nil {
+				return err
+			}
+			if auditLog != nil {
+				logger.Infof("audit of target notifications enabled, write to %s", c.Audit.Path)
+				audit.SetLog(auditLog)
+				defer auditLog.Close()
+
+				server.RegisterAuditHandler(auditLog)
+			}
+
+			if tracker := resolver.AcknowledgmentTracker(); tracker != nil {
+				logger.Infof("delivery acknowledgments enabled, report deliveries unacknowledged after %s", c.Acknowledgments.Threshold)
+				acknowledgment.SetTracker(tracker)
+				server.RegisterAcknowledgmentHandler(tracker)
+				metrics.RegisterAcknowledgmentMetrics(tracker)
+
+				g.Go(func() error {
+					return tracker.Run(ctx, time.Minute)
+				})
+			}
+
+			pushgateway := resolver.MetricsPushgateway()
+			remoteWrite := resolver.MetricsRemoteWrite()
+
+			if c.Metrics.Enabled || pushgateway != nil || remoteWrite != nil {
 				resolver.RegisterMetricsListener()
-				server.RegisterMetricsHandler()
+				resolver.RegisterProcessingLagListener()
+			}
+
+			if c.Alerting.Enabled {
+				if err := resolver.RegisterAlertingListener(); err != nil {
+					return err
+				}
+
+				logger.Infof("alerting enabled, evaluate %d rules on new results", len(c.Alerting.Rules))
+			}
+
+			if c.Coverage.Enabled {
+				scanner, err := resolver.CoverageScanner()
+				if err != nil {
+					return err
+				}
+
+				resolver.RegisterCoverageListener()
+				server.RegisterCoverageHandler(scanner)
+
+				logger.Infof("coverage scanner enabled, scan for workloads without results every %s", c.Coverage.Interval)
+				g.Go(func() error {
+					return scanner.Run(ctx, c.Coverage.Interval)
+				})
+			}
+
+			if c.Metrics.Enabled {
+				logger.Info("metrics enabled")
+				server.RegisterMetricsHandler(resolver.MetricsGatherer())
+			}
+
+			if pushgateway != nil {
+				logger.Infof("push metrics to %s every %s", pushgateway.Name(), c.Metrics.Pushgateway.Interval)
+				g.Go(func() error {
+					return push.Run(ctx, pushgateway, c.Metrics.Pushgateway.Interval)
+				})
+			}
+
+			if remoteWrite != nil {
+				logger.Infof("push metrics to %s every %s", remoteWrite.Name(), c.Metrics.RemoteWrite.Interval)
+				g.Go(func() error {
+					return push.Run(ctx, remoteWrite, c.Metrics.RemoteWrite.Interval)
+				})
+			}
+
+			if c.Annotations.Enabled {
+				if len(c.Annotations.Namespaces) == 0 {
+					logger.Warn("violation annotations require at least one allowed namespace")
+				} else {
+					writer, err := resolver.AnnotationWriter()
+					if err != nil {
+						return err
+					}
+
+					logger.Infof("violation annotations enabled for namespaces %s", strings.Join(c.Annotations.Namespaces, ", "))
+					resolver.RegisterAnnotationWriterListener(writer)
+
+					g.Go(func() error {
+						return writer.Run(ctx, c.Annotations.Workers)
+					})
+				}
+			}
+
+			if c.Compliance.Enabled {
+				controller, err := resolver.ComplianceSummaryController()
+				if err != nil {
+					return err
+				}
+
+				logger.Infof("maintain ClusterComplianceSummary %s", c.Compliance.Name)
+				resolver.RegisterComplianceSummaryListener(controller)
+
+				g.Go(func() error {
+					return controller.Run(ctx)
+				})
+			}
+
+			if federationClient := resolver.FederationClient(); federationClient != nil {
+				logger.Infof("push PolicyReports of cluster %s to the central instance", c.Federation.ClusterName)
+				resolver.RegisterFederationListener(federationClient)
+
+				g.Go(func() error {
+					return federationClient.Sync(ctx, c.Federation.Central.SyncInterval)
+				})
+			}
+
+			if c.Ingestion.Enabled {
+				if !c.API.Auth.Enabled {
+					logger.Warn("result ingestion requires api.auth.enabled, ingestion disabled")
+				} else {
+					ingester, err := resolver.Ingester()
+					if err != nil {
+						return err
+					}
+
+					logger.Info("result ingestion enabled")
+					server.RegisterIngestionHandler(ingester)
+				}
+			}
+
+			if c.Maintenance.Enabled {
+				muter := resolver.Muter()
+
+				logger.Infof("maintenance windows enabled, %s notifications during maintenance", c.Maintenance.Mode)
+				server.RegisterMaintenanceHandler(resolver.MaintenanceSchedule())
+
+				g.Go(func() error {
+					return muter.Run(ctx, time.Minute)
+				})
 			}
 
 			if c.Profiling.Enabled {
-				log.Println("[INFO] pprof profiling enabled")
+				logger.Info("pprof profiling enabled")
 				server.RegisterProfilingHandler()
 			}
 
-			if resolver.HasTargets() && c.LeaderElection.Enabled {
+			if c.Logging.Endpoint {
+				logger.Info("log level endpoint enabled")
+				server.RegisterLoggingHandler(logs)
+			}
+
+			if resolver.HasTargets() {
+				notified, err := resolver.NotificationCache()
+				if err != nil {
+					return err
+				}
+				if notified != nil {
+					logger.Infof("notification cache enabled, sent notifications kept in the %s deduplication store", c.Deduplication.Type)
+				}
+			}
+
+			// the violation annotations and the ClusterComplianceSummary are written back by the owning replica only
+			coordinated := resolver.HasTargets() || c.Annotations.Enabled || c.Compliance.Enabled
+
+			if coordinated && c.Sharding.Enabled {
+				shards, err := resolver.ShardingClient()
+				if err != nil {
+					return err
+				}
+
+				shards.RegisterOnChange(func(members []string) {
+					klog.Infof("sharding members changed: %s", strings.Join(members, ", "))
+				})
+
+				// join the ring before the first results are dispatched, the client owns no keys until the members are listed
+				if err := shards.Renew(ctx); err != nil {
+					logger.Errorf("failed to renew shard lease: %s", err)
+				}
+				if err := shards.Refresh(ctx); err != nil {
+					logger.Errorf("failed to refresh shard members: %s", err)
+				}
+
+				if resolver.HasTargets() {
+					resolver.RegisterSendResultListener()
+				}
+
+				g.Go(func() error {
+					return shards.Run(ctx)
+				})
+			} else if coordinated && c.LeaderElection.Enabled {
 				elector, err := resolver.LeaderElectionClient()
 				if err != nil {
 					return err
@@ -86,7 +631,9 @@ func newRunCMD() *cobra.Command {
 				elector.RegisterOnStart(func(c context.Context) {
 					klog.Info("started leadership")
 
-					resolver.RegisterSendResultListener()
+					if resolver.HasTargets() {
+						resolver.RegisterSendResultListener()
+					}
 				}).RegisterOnNew(func(currentID, lockID string) {
 					if currentID != lockID {
 						klog.Infof("leadership by %s", currentID)
@@ -95,23 +642,130 @@ func newRunCMD() *cobra.Command {
 					klog.Info("stopped leadership")
 
 					resolver.EventPublisher().UnregisterListener(listener.NewResults)
+					resolver.EventPublisher().UnregisterListener(listener.ResolvedResults)
+					resolver.EventPublisher().UnregisterListener(listener.ReportChanges)
 				})
 
+				server.RegisterHealthCheck("leaderElection", false, elector.Check)
+
 				g.Go(func() error {
-					return elector.Run(cmd.Context())
+					return elector.Run(ctx)
 				})
 			} else if resolver.HasTargets() {
 				resolver.RegisterSendResultListener()
 			}
 
-			g.Go(server.Start)
+			if resolver.HasTargets() {
+				server.RegisterHealthCheck("targets", false, health.TargetDeliveries.Check)
+				metrics.RegisterTargetHealthMetrics(health.TargetDeliveries)
+
+				if th := c.TargetHealth; len(th.Critical) > 0 {
+					check := health.TargetDeliveries.Failing(th.Critical, th.Threshold)
+					if th.Action == config.TargetHealthRestart {
+						server.RegisterLivenessCheck("criticalTargets", check)
+					} else {
+						server.RegisterHealthCheck("criticalTargets", true, check)
+					}
+				}
+
+				if c.REST.Enabled {
+					server.RegisterTargetSimulationHandler(resolver.Mapper())
+				}
+			}
+
+			if c.DryRunTargets {
+				logger.Info("dry run enabled, target payloads are logged instead of sent")
+			}
+
+			if c.Reload.Enabled {
+				logger.Infof("configuration reload enabled, check for changes every %s", c.Reload.Interval)
+				g.Go(func() error {
+					return config.WatchFile(ctx, cmd, c, resolver.Reload)
+				})
+			}
+
+			if metadataCache, err := resolver.MetadataCache(); err == nil && !c.IsAPIOnly() {
+				g.Go(func() error {
+					return metadataCache.Run(ctx)
+				})
+			}
+
+			if c.Admission.Enabled {
+				logger.Infof("admission webhook enabled in %s mode", c.Admission.Mode)
+				webhook = resolver.AdmissionWebhook()
+
+				g.Go(func() error {
+					if err := webhook.Start(); err != nil && err != http.ErrServerClosed {
+						return err
+					}
+
+					return nil
+				})
+			}
+
+			g.Go(func() error {
+				if err := server.Start(); err != nil && err != http.ErrServerClosed {
+					return err
+				}
+
+				return nil
+			})
+
+			stop := make(chan struct{})
+			stopped := make(chan struct{})
 
 			g.Go(func() error {
-				stop := make(chan struct{})
-				defer close(stop)
-				log.Printf("[INFO] start client with %d workers", c.WorkerCount)
+				defer close(stopped)
+
+				if client == nil {
+					logger.Info("api-only mode, no informers started")
+					return nil
+				}
+
+				workers := c.WorkerCount
+				if c.Watch.Workers > 0 {
+					workers = c.Watch.Workers
+				}
+
+				logger.Infof("start client with %d workers", workers)
+
+				return client.Run(workers, stop)
+			})
+
+			g.Go(func() error {
+				<-ctx.Done()
+				logger.Info("shutdown started")
+
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), c.Dispatcher.DrainTimeout)
+				defer cancel()
+
+				// stop the informers and process the already queued reports
+				close(stop)
+				<-stopped
+
+				delivered := true
+				if resolver.HasTargets() {
+					if err := resolver.ResultDispatcher().Shutdown(shutdownCtx); err != nil {
+						logger.Warnf("failed to deliver all queued results before shutdown: %s", err)
+						delivered = false
+					}
+				}
+
+				if delivered && client != nil {
+					resolver.WriteCheckpoint()
+				}
+
+				if grpcServer != nil {
+					grpcServer.Stop()
+				}
+
+				if webhook != nil {
+					if err := webhook.Stop(shutdownCtx); err != nil {
+						logger.Errorf("failed to stop the admission webhook: %s", err)
+					}
+				}
 
-				return client.Run(c.WorkerCount, stop)
+				return server.Shutdown(shutdownCtx)
 			})
 
 			return g.Wait()
@@ -121,15 +775,21 @@ func newRunCMD() *cobra.Command {
 	// For local usage
 	cmd.PersistentFlags().StringP("kubeconfig", "k", "", "absolute path to the kubeconfig file")
 	cmd.PersistentFlags().StringP("config", "c", "", "target configuration file")
+	cmd.PersistentFlags().String("config-profile", "", "profile of the configuration file, merges the profiles.<name> section and the <config>.<name>.yaml overlay, defaults to $CONFIG_PROFILE")
+	cmd.PersistentFlags().StringSlice("overlay", nil, "configuration files merged on top of the configuration file in order")
 	cmd.PersistentFlags().IntP("port", "p", 8080, "http port for the optional rest api")
 	cmd.PersistentFlags().StringP("dbfile", "d", "sqlite-database.db", "path to the SQLite DB File")
 	cmd.PersistentFlags().BoolP("metrics-enabled", "m", false, "Enable Policy Reporter's Metrics API")
 	cmd.PersistentFlags().BoolP("rest-enabled", "r", false, "Enable Policy Reporter's REST API")
 	cmd.PersistentFlags().Bool("profile", false, "Enable application profiling with pprof")
+	cmd.PersistentFlags().Bool("dry-run-targets", false, "log the rendered target payloads instead of sending them")
 	cmd.PersistentFlags().String("lease-name", "policy-reporter", "name of the LeaseLock")
 	cmd.PersistentFlags().Int("worker", 5, "amount of queue worker")
 	cmd.PersistentFlags().Float32("qps", 20, "K8s RESTClient QPS")
 	cmd.PersistentFlags().Int("burst", 50, "K8s RESTClient burst")
+	cmd.PersistentFlags().String("mode", config.ModeCluster, "source of the PolicyReports, \"cluster\", \"standalone\" to read them from the files of --reports-dir or \"api-only\" to only serve the API of the shared database")
+	cmd.PersistentFlags().String("reports-dir", "", "directory of PolicyReport and ClusterPolicyReport files in JSON or YAML, watched in standalone mode")
+	cmd.PersistentFlags().Bool("strict", false, "fail on startup if the configuration is invalid instead of logging the problems")
 
 	flag.Parse()
 
@@ -17,6 +17,8 @@ func newSendCMD() *cobra.Command {
 	// For local usage
 	cmd.PersistentFlags().StringP("kubeconfig", "k", "", "absolute path to the kubeconfig file")
 	cmd.PersistentFlags().StringP("config", "c", "", "target configuration file")
+	cmd.PersistentFlags().String("config-profile", "", "profile of the configuration file, merges the profiles.<name> section and the <config>.<name>.yaml overlay, defaults to $CONFIG_PROFILE")
+	cmd.PersistentFlags().StringSlice("overlay", nil, "configuration files merged on top of the configuration file in order")
 	cmd.PersistentFlags().StringP("template-dir", "t", "./templates", "template directory for email reports")
 	cmd.AddCommand(send.NewSummaryCMD())
 	cmd.AddCommand(send.NewViolationsCMD())
@@ -1,7 +1,7 @@
 package send
 
 import (
-	"log"
+	"os"
 	"strings"
 	"sync"
 
@@ -10,9 +10,16 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 
 	"github.com/kyverno/policy-reporter/pkg/config"
+	"github.com/kyverno/policy-reporter/pkg/email"
 	"github.com/kyverno/policy-reporter/pkg/email/summary"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/severity"
+	"github.com/kyverno/policy-reporter/pkg/timeformat"
+	"github.com/kyverno/policy-reporter/pkg/validate"
 )
 
+var logger = logging.Component("cmd")
+
 func NewSummaryCMD() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "summary",
@@ -23,6 +30,18 @@ func NewSummaryCMD() *cobra.Command {
 				return err
 			}
 
+			if _, err := logging.Use(os.Stderr, c.Logging.Encoding, c.Logging.Level, c.Logging.Components); err != nil {
+				return err
+			}
+
+			if err := timeformat.Use(c.Timestamps.Format, c.Timestamps.Timezone); err != nil {
+				return err
+			}
+
+			if err := severity.Use(config.SeverityLevelsFromConfig(c.Severities)); err != nil {
+				return err
+			}
+
 			var k8sConfig *rest.Config
 			if c.K8sClient.Kubeconfig != "" {
 				k8sConfig, err = clientcmd.BuildConfigFromFlags("", c.K8sClient.Kubeconfig)
@@ -34,6 +53,7 @@ func NewSummaryCMD() *cobra.Command {
 			}
 
 			resolver := config.NewResolver(c, k8sConfig)
+			defer resolver.Close()
 
 			generator, err := resolver.SummaryGenerator()
 			if err != nil {
@@ -42,7 +62,7 @@ func NewSummaryCMD() *cobra.Command {
 
 			data, err := generator.GenerateData(cmd.Context())
 			if err != nil {
-				log.Printf("[ERROR] failed to generate report data: %s\n", err)
+				logger.Errorf("failed to generate report data: %s", err)
 				return err
 			}
 
@@ -55,23 +75,23 @@ func NewSummaryCMD() *cobra.Command {
 				defer wg.Done()
 
 				if len(c.EmailReports.Summary.To) == 0 {
-					log.Print("[INFO] skipped - no email configured")
+					logger.Info("skipped - no email configured")
 					return
 				}
 
-				report, err := reporter.Report(data, c.EmailReports.Summary.Format)
+				report, err := reporter.Report(data, c.EmailReports.Summary.Format, config.EmailReportOptionsFromConfig(c.EmailReports.Summary, nil))
 				if err != nil {
-					log.Printf("[ERROR] failed to create report: %s\n", err)
+					logger.Errorf("failed to create report: %s", err)
 					return
 				}
 
 				err = resolver.EmailClient().Send(report, c.EmailReports.Summary.To)
 				if err != nil {
-					log.Printf("[ERROR] failed to send report: %s\n", err)
+					logger.Errorf("failed to send report: %s", err)
 					return
 				}
 
-				log.Printf("[INFO] email sent to %s\n", strings.Join(c.EmailReports.Summary.To, ", "))
+				logger.Infof("email sent to %s", strings.Join(c.EmailReports.Summary.To, ", "))
 			}()
 
 			for _, ch := range c.EmailReports.Violations.Channels {
@@ -79,37 +99,91 @@ func NewSummaryCMD() *cobra.Command {
 					defer wg.Done()
 
 					if len(channel.To) == 0 {
-						log.Print("[INFO] skipped - no channel email configured")
+						logger.Info("skipped - no channel email configured")
 						return
 					}
 
-					sources := summary.FilterSources(data, config.EmailReportFilterFromConfig(channel.Filter), !channel.Filter.DisableClusterReports)
+					sources := summary.FilterSources(data, resolver.EmailReportFilter(channel.Filter), !channel.Filter.DisableClusterReports)
 					if len(sources) == 0 {
-						log.Printf("[INFO] skip email - no results to send")
+						logger.Infof("skip email - no results to send")
 						return
 					}
 
-					report, err := reporter.Report(sources, channel.Format)
+					report, err := reporter.Report(sources, channel.Format, config.EmailReportOptionsFromConfig(channel, &c.EmailReports.Summary))
 					if err != nil {
-						log.Printf("[ERROR] failed to create report: %s\n", err)
+						logger.Errorf("failed to create report: %s", err)
 						return
 					}
 
 					err = resolver.EmailClient().Send(report, channel.To)
 					if err != nil {
-						log.Printf("[ERROR] failed to send report: %s\n", err)
+						logger.Errorf("failed to send report: %s", err)
 						return
 					}
 
-					log.Printf("[INFO] email sent to %s\n", strings.Join(channel.To, ", "))
+					logger.Infof("email sent to %s", strings.Join(channel.To, ", "))
 				}(ch)
 			}
 
 			wg.Wait()
 
+			if c.EmailReports.Subscriptions.Enabled {
+				if err := sendSubscriptions(c, &resolver, reporter, data); err != nil {
+					logger.Errorf("failed to send the subscription digests: %s", err)
+					return err
+				}
+			}
+
 			return nil
 		},
 	}
 
 	return cmd
 }
+
+// sendSubscriptions sends the summary of each namespace to its confirmed subscribers
+func sendSubscriptions(c *config.Config, resolver *config.Resolver, reporter *summary.Reporter, data []summary.Source) error {
+	db, err := resolver.OpenDatabase()
+	if err != nil {
+		return err
+	}
+
+	store, err := resolver.PolicyReportStore(db)
+	if err != nil {
+		return err
+	}
+
+	manager := resolver.SubscriptionManager(store)
+
+	subscribers, err := manager.Subscribers()
+	if err != nil {
+		return err
+	}
+
+	for namespace, list := range subscribers {
+		filter := email.NewFilter(validate.RuleSets{Include: []string{namespace}}, validate.RuleSets{}, validate.RuleSets{})
+
+		sources := summary.FilterSources(data, filter, false)
+		if len(sources) == 0 {
+			logger.Infof("skip digest of namespace %s - no results to send", namespace)
+			continue
+		}
+
+		report, err := reporter.Report(sources, c.EmailReports.Summary.Format, config.EmailReportOptionsFromConfig(c.EmailReports.Summary, nil))
+		if err != nil {
+			logger.Errorf("failed to create the digest of namespace %s: %s", namespace, err)
+			continue
+		}
+
+		for _, sub := range list {
+			if err := resolver.EmailClient().Send(manager.WithUnsubscribeLink(report, sub), []string{sub.Email}); err != nil {
+				logger.Errorf("failed to send the digest of namespace %s: %s", namespace, err)
+				continue
+			}
+
+			logger.Infof("digest of namespace %s sent to %s", namespace, sub.Email)
+		}
+	}
+
+	return nil
+}
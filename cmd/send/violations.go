@@ -1,7 +1,7 @@
 package send
 
 import (
-	"log"
+	"os"
 	"strings"
 	"sync"
 
@@ -11,6 +11,9 @@ import (
 
 	"github.com/kyverno/policy-reporter/pkg/config"
 	"github.com/kyverno/policy-reporter/pkg/email/violations"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/severity"
+	"github.com/kyverno/policy-reporter/pkg/timeformat"
 )
 
 func NewViolationsCMD() *cobra.Command {
@@ -23,6 +26,18 @@ func NewViolationsCMD() *cobra.Command {
 				return err
 			}
 
+			if _, err := logging.Use(os.Stderr, c.Logging.Encoding, c.Logging.Level, c.Logging.Components); err != nil {
+				return err
+			}
+
+			if err := timeformat.Use(c.Timestamps.Format, c.Timestamps.Timezone); err != nil {
+				return err
+			}
+
+			if err := severity.Use(config.SeverityLevelsFromConfig(c.Severities)); err != nil {
+				return err
+			}
+
 			var k8sConfig *rest.Config
 			if c.K8sClient.Kubeconfig != "" {
 				k8sConfig, err = clientcmd.BuildConfigFromFlags("", c.K8sClient.Kubeconfig)
@@ -34,6 +49,7 @@ func NewViolationsCMD() *cobra.Command {
 			}
 
 			resolver := config.NewResolver(c, k8sConfig)
+			defer resolver.Close()
 
 			generator, err := resolver.ViolationsGenerator()
 			if err != nil {
@@ -42,7 +58,7 @@ func NewViolationsCMD() *cobra.Command {
 
 			data, err := generator.GenerateData(cmd.Context())
 			if err != nil {
-				log.Printf("[ERROR] failed to generate report data: %s\n", err)
+				logger.Errorf("failed to generate report data: %s", err)
 				return err
 			}
 
@@ -55,23 +71,23 @@ func NewViolationsCMD() *cobra.Command {
 				defer wg.Done()
 
 				if len(c.EmailReports.Violations.To) == 0 {
-					log.Print("[INFO] skipped - no email configured")
+					logger.Info("skipped - no email configured")
 					return
 				}
 
-				report, err := reporter.Report(data, c.EmailReports.Violations.Format)
+				report, err := reporter.Report(data, c.EmailReports.Violations.Format, config.EmailReportOptionsFromConfig(c.EmailReports.Violations, nil))
 				if err != nil {
-					log.Printf("[ERROR] failed to create report: %s\n", err)
+					logger.Errorf("failed to create report: %s", err)
 					return
 				}
 
 				err = resolver.EmailClient().Send(report, c.EmailReports.Violations.To)
 				if err != nil {
-					log.Printf("[ERROR] failed to send report: %s\n", err)
+					logger.Errorf("failed to send report: %s", err)
 					return
 				}
 
-				log.Printf("[INFO] email sent to %s\n", strings.Join(c.EmailReports.Violations.To, ", "))
+				logger.Infof("email sent to %s", strings.Join(c.EmailReports.Violations.To, ", "))
 			}()
 
 			for _, ch := range c.EmailReports.Violations.Channels {
@@ -79,29 +95,29 @@ func NewViolationsCMD() *cobra.Command {
 					defer wg.Done()
 
 					if len(channel.To) == 0 {
-						log.Print("[INFO] skipped - no channel email configured")
+						logger.Info("skipped - no channel email configured")
 						return
 					}
 
-					sources := violations.FilterSources(data, config.EmailReportFilterFromConfig(channel.Filter), !channel.Filter.DisableClusterReports)
+					sources := violations.FilterSources(data, resolver.EmailReportFilter(channel.Filter), !channel.Filter.DisableClusterReports)
 					if len(sources) == 0 {
-						log.Printf("[INFO] skip email - no results to send")
+						logger.Infof("skip email - no results to send")
 						return
 					}
 
-					report, err := reporter.Report(sources, channel.Format)
+					report, err := reporter.Report(sources, channel.Format, config.EmailReportOptionsFromConfig(channel, &c.EmailReports.Violations))
 					if err != nil {
-						log.Printf("[ERROR] failed to create report: %s\n", err)
+						logger.Errorf("failed to create report: %s", err)
 						return
 					}
 
 					err = resolver.EmailClient().Send(report, channel.To)
 					if err != nil {
-						log.Printf("[ERROR] failed to send report: %s\n", err)
+						logger.Errorf("failed to send report: %s", err)
 						return
 					}
 
-					log.Printf("[INFO] email sent to %s\n", strings.Join(channel.To, ", "))
+					logger.Infof("email sent to %s", strings.Join(channel.To, ", "))
 				}(ch)
 			}
 
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyverno/policy-reporter/pkg/config"
+	"github.com/kyverno/policy-reporter/pkg/sqlite3"
+)
+
+func newStoreCMD() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "store",
+		Short: "Export and import the result store",
+		Long: `Exports the result store with reports, results, history, sent notifications and tombstones into a portable archive
+and imports it into another instance or database backend, e.g. to migrate from SQLite to PostgreSQL.
+The database is configured with the configuration file like for the run command.`,
+	}
+
+	cmd.PersistentFlags().StringP("config", "c", "", "configuration file of the database")
+	cmd.PersistentFlags().String("config-profile", "", "profile of the configuration file, defaults to $CONFIG_PROFILE")
+	cmd.PersistentFlags().StringSlice("overlay", nil, "configuration files merged on top of the configuration file in order")
+	cmd.PersistentFlags().StringP("dbfile", "d", "sqlite-database.db", "path to the SQLite DB File")
+
+	cmd.AddCommand(newStoreExportCMD())
+	cmd.AddCommand(newStoreImportCMD())
+
+	return cmd
+}
+
+func newStoreExportCMD() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:          "export",
+		Short:        "Export the result store into an archive",
+		Example:      "policyreporter store export -c config.yaml -o store.ndjson.gz",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := config.Load(cmd)
+			if err != nil {
+				return err
+			}
+
+			resolver := config.NewResolver(c, nil)
+			defer resolver.Close()
+
+			db, err := resolver.OpenDatabase()
+			if err != nil {
+				return err
+			}
+
+			var out io.Writer = cmd.OutOrStdout()
+			if output != "" && output != "-" {
+				file, err := os.Create(output)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+
+				out = file
+			}
+
+			stats, err := sqlite3.Export(db, sqlite3.DialectFor(c.Database.Type), out)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintf(cmd.ErrOrStderr(), "exported %s\n", stats)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "-", "archive file, stdout if \"-\"")
+
+	return cmd
+}
+
+func newStoreImportCMD() *cobra.Command {
+	var input string
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import an archive into the result store",
+		Long: `Imports an archive created by "store export", the schema is created if it does not exist.
+Existing reports, results, notifications and tombstones are kept, stop Policy Reporter during the import.`,
+		Example:      "policyreporter store import -c config.yaml -i store.ndjson.gz",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := config.Load(cmd)
+			if err != nil {
+				return err
+			}
+
+			resolver := config.NewResolver(c, nil)
+			defer resolver.Close()
+
+			db, err := resolver.OpenDatabase()
+			if err != nil {
+				return err
+			}
+
+			var in io.Reader = cmd.InOrStdin()
+			if input != "" && input != "-" {
+				file, err := os.Open(input)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+
+				in = file
+			}
+
+			stats, err := sqlite3.Import(db, sqlite3.DialectFor(c.Database.Type), in)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintf(cmd.ErrOrStderr(), "imported %s\n", stats)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVarP(&input, "input", "i", "-", "archive file, stdin if \"-\"")
+
+	return cmd
+}
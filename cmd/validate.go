@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/kyverno/policy-reporter/pkg/config"
+)
+
+func newValidateConfigCMD() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "validate-config",
+		Short:         "Validate the configuration file",
+		Long:          "Parses the configuration and validates URLs, secretRefs, filter expressions and mutually exclusive options. Unknown options are reported as possible typos.",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, loadErr := config.Load(cmd)
+			if err := config.Validate(c); err != nil {
+				return err
+			}
+			if loadErr != nil {
+				return loadErr
+			}
+
+			checkSecrets, _ := cmd.Flags().GetBool("check-secrets")
+			if checkSecrets {
+				var k8sConfig *rest.Config
+				var err error
+				if c.K8sClient.Kubeconfig != "" {
+					k8sConfig, err = clientcmd.BuildConfigFromFlags("", c.K8sClient.Kubeconfig)
+				} else {
+					k8sConfig, err = rest.InClusterConfig()
+				}
+				if err != nil {
+					return err
+				}
+
+				resolver := config.NewResolver(c, k8sConfig)
+
+				client := resolver.SecretClient()
+				if client == nil {
+					return fmt.Errorf("failed to create the secret client")
+				}
+
+				if err := config.CheckSecretRefs(cmd.Context(), c, client); err != nil {
+					return err
+				}
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "configuration is valid")
+
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().StringP("kubeconfig", "k", "", "absolute path to the kubeconfig file")
+	cmd.PersistentFlags().StringP("config", "c", "", "target configuration file")
+	cmd.PersistentFlags().String("config-profile", "", "profile of the configuration file, merges the profiles.<name> section and the <config>.<name>.yaml overlay, defaults to $CONFIG_PROFILE")
+	cmd.PersistentFlags().StringSlice("overlay", nil, "configuration files merged on top of the configuration file in order")
+	cmd.PersistentFlags().Bool("check-secrets", false, "verify that all referenced secrets exist, requires access to the cluster")
+
+	return cmd
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyverno/policy-reporter/cmd/get"
+	"github.com/kyverno/policy-reporter/pkg/api/client"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/stream"
+)
+
+// reconnectDelay after the stream was closed by the server or the network
+const reconnectDelay = 5 * time.Second
+
+const (
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorGray   = "\033[90m"
+)
+
+var statusColors = map[string]string{
+	v1alpha2.StatusFail:  colorRed,
+	v1alpha2.StatusError: colorRed,
+	v1alpha2.StatusWarn:  colorYellow,
+	v1alpha2.StatusPass:  colorGreen,
+	v1alpha2.StatusSkip:  colorGray,
+}
+
+func newWatchCMD() *cobra.Command {
+	params := &client.StreamResultsParams{}
+	var output string
+	var noColor bool
+
+	cmd := &cobra.Command{
+		Use:     "watch",
+		Short:   "Stream new, updated and resolved results of a running Policy Reporter",
+		Long:    "Tails the result stream of the REST API, by default through the service proxy of the Kubernetes API, and reconnects if the stream is interrupted.",
+		Example: "policyreporter watch -n default --severity high,critical --status fail",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != get.Table && output != get.JSON {
+				return fmt.Errorf("unknown output format '%s', expected one of table, json", output)
+			}
+
+			c, err := get.NewClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			out := cmd.OutOrStdout()
+			color := !noColor && os.Getenv("NO_COLOR") == "" && isTerminal(out)
+
+			handle := func(event stream.Event) error {
+				if output == get.JSON {
+					return json.NewEncoder(out).Encode(event)
+				}
+
+				_, err := fmt.Fprintln(out, formatEvent(event, color))
+				return err
+			}
+
+			for {
+				err := c.StreamResults(ctx, params, handle)
+				if ctx.Err() != nil {
+					return nil
+				}
+
+				apiErr := &client.Error{}
+				if errors.As(err, &apiErr) {
+					return err
+				}
+
+				if err == nil {
+					err = errors.New("closed by the server")
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "result stream interrupted: %s, reconnecting in %s\n", err, reconnectDelay)
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(reconnectDelay):
+				}
+			}
+		},
+	}
+
+	get.AddClientFlags(cmd.Flags())
+	cmd.Flags().StringVarP(&output, "output", "o", get.Table, "output format: table or json, json prints one event per line")
+	cmd.Flags().BoolVar(&noColor, "no-color", false, "disable colored output, also disabled by the NO_COLOR environment or if the output is no terminal")
+	cmd.Flags().StringSliceVarP(&params.Namespaces, "namespace", "n", nil, "namespaces of the results, defaults to all namespaces")
+	cmd.Flags().StringSliceVar(&params.Status, "status", nil, "status of the results, e.g. fail,warn")
+	cmd.Flags().StringSliceVar(&params.Severities, "severity", nil, "severities of the results, e.g. high,critical")
+	cmd.Flags().StringSliceVar(&params.Policies, "policy", nil, "policies of the results")
+	cmd.Flags().StringSliceVar(&params.Sources, "source", nil, "sources of the results, e.g. kyverno")
+	cmd.Flags().StringSliceVar(&params.Kinds, "kind", nil, "resource kinds of the results")
+	cmd.Flags().StringSliceVar(&params.Types, "type", nil, "event types: new, updated or resolved, defaults to all types")
+	cmd.Flags().StringVar(&params.Filter, "filter", "", "filter expression of the results")
+
+	return cmd
+}
+
+// formatEvent as a single line: time, event type, status, severity, resource, policy/rule and message
+func formatEvent(event stream.Event, color bool) string {
+	r := event.Result
+
+	resource := r.Kind + "/" + r.Name
+	if r.Namespace != "" {
+		resource = r.Namespace + "/" + resource
+	}
+
+	policy := r.Policy
+	if r.Rule != "" {
+		policy += "/" + r.Rule
+	}
+
+	severity := r.Severity
+	if severity == "" {
+		severity = "-"
+	}
+
+	eventType := fmt.Sprintf("%-8s", strings.ToUpper(event.Type))
+	status := fmt.Sprintf("%-5s", strings.ToUpper(r.Status))
+
+	if color {
+		if event.Type == stream.Resolved {
+			eventType = colorGreen + eventType + colorReset
+		}
+		if c, ok := statusColors[r.Status]; ok {
+			status = c + status + colorReset
+		}
+	}
+
+	return fmt.Sprintf("%s %s %s %-8s %s %s: %s", time.Unix(event.Timestamp, 0).Format("15:04:05"), eventType, status, severity, resource, policy, r.Message)
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
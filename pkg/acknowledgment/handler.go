@@ -0,0 +1,89 @@
+package acknowledgment
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kyverno/policy-reporter/pkg/helper"
+)
+
+// AcknowledgePath receives the acknowledgments of downstream systems
+const AcknowledgePath = "/v1/acknowledgments"
+
+// UnacknowledgedPath reports the deliveries not acknowledged within the threshold
+const UnacknowledgedPath = "/v1/acknowledgments/unacknowledged"
+
+// Request acknowledges the results delivered to a target
+type Request struct {
+	Target    string   `json:"target"`
+	ResultIDs []string `json:"resultIds"`
+}
+
+// Response of an acknowledgment, Unknown lists the result IDs without recorded delivery to the target
+type Response struct {
+	Acknowledged int      `json:"acknowledged"`
+	Unknown      []string `json:"unknown"`
+}
+
+// Report of the unacknowledged deliveries
+type Report struct {
+	Threshold string         `json:"threshold"`
+	Total     int            `json:"total"`
+	Targets   map[string]int `json:"targets"`
+	Items     []Delivery     `json:"items"`
+}
+
+// AcknowledgeHandler of the requests sent to AcknowledgePath, a JSON Request body or the target and resultIds query parameters
+func (t *Tracker) AcknowledgeHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			helper.SendError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+			return
+		}
+
+		query := req.URL.Query()
+		request := Request{Target: query.Get("target"), ResultIDs: query["resultIds"]}
+
+		if strings.HasPrefix(req.Header.Get("Content-Type"), "application/json") {
+			if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, 1<<20)).Decode(&request); err != nil {
+				helper.SendBadRequest(w, err)
+				return
+			}
+		}
+		if request.Target == "" {
+			helper.SendBadRequest(w, fmt.Errorf("target is required"))
+			return
+		}
+		if len(request.ResultIDs) == 0 {
+			helper.SendBadRequest(w, fmt.Errorf("resultIds are required"))
+			return
+		}
+
+		count, unknown := t.Acknowledge(request.Target, request.ResultIDs)
+
+		helper.SendJSONResponse(w, Response{Acknowledged: count, Unknown: unknown}, nil)
+	}
+}
+
+// UnacknowledgedHandler of the requests sent to UnacknowledgedPath, filtered by the optional target query parameter
+func (t *Tracker) UnacknowledgedHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			helper.SendError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+			return
+		}
+
+		items := t.Unacknowledged(req.URL.Query().Get("target"))
+
+		targets := make(map[string]int)
+		for _, item := range items {
+			targets[item.Target]++
+		}
+
+		helper.SendJSONResponse(w, Report{Threshold: t.threshold.String(), Total: len(items), Targets: targets, Items: items}, nil)
+	}
+}
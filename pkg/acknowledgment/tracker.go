@@ -0,0 +1,221 @@
+// Package acknowledgment tracks the results delivered to the targets until downstream systems acknowledge them,
+// to verify that e.g. a SIEM really ingested every delivered result.
+package acknowledgment
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Delivery of a result to a target
+type Delivery struct {
+	Target       string     `json:"target"`
+	ResultID     string     `json:"resultId"`
+	Delivered    time.Time  `json:"delivered"`
+	Acknowledged *time.Time `json:"acknowledged,omitempty"`
+}
+
+// Tracker records the successful deliveries of the tracked targets and their acknowledgments,
+// the deliveries are kept in memory for the retention period
+type Tracker struct {
+	mx           *sync.RWMutex
+	targets      map[string]bool
+	threshold    time.Duration
+	retention    time.Duration
+	deliveries   map[string]map[string]*Delivery
+	acknowledged map[string]uint64
+}
+
+// Tracks reports if the deliveries of the target are tracked
+func (t *Tracker) Tracks(target string) bool {
+	return len(t.targets) == 0 || t.targets[target]
+}
+
+// Delivered records the delivery of the result, a delivery of an already acknowledged result has to be acknowledged again
+func (t *Tracker) Delivered(target, resultID string) {
+	if resultID == "" || !t.Tracks(target) {
+		return
+	}
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	if t.deliveries[target] == nil {
+		t.deliveries[target] = make(map[string]*Delivery)
+	}
+
+	t.deliveries[target][resultID] = &Delivery{Target: target, ResultID: resultID, Delivered: time.Now()}
+}
+
+// Acknowledge the deliveries of the results to the target, returns the number of acknowledged deliveries
+// and the result IDs without recorded delivery
+func (t *Tracker) Acknowledge(target string, resultIDs []string) (int, []string) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	now := time.Now()
+	count := 0
+	unknown := make([]string, 0)
+
+	for _, id := range resultIDs {
+		delivery, ok := t.deliveries[target][id]
+		if !ok {
+			unknown = append(unknown, id)
+			continue
+		}
+
+		if delivery.Acknowledged == nil {
+			delivery.Acknowledged = &now
+			t.acknowledged[target]++
+		}
+		count++
+	}
+
+	return count, unknown
+}
+
+// Unacknowledged returns the deliveries not acknowledged within the threshold, the oldest first.
+// An empty target returns the deliveries of all targets
+func (t *Tracker) Unacknowledged(target string) []Delivery {
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	deadline := time.Now().Add(-t.threshold)
+	list := make([]Delivery, 0)
+
+	for name, deliveries := range t.deliveries {
+		if target != "" && target != name {
+			continue
+		}
+
+		for _, delivery := range deliveries {
+			if delivery.Acknowledged == nil && !delivery.Delivered.After(deadline) {
+				list = append(list, *delivery)
+			}
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].Delivered.Equal(list[j].Delivered) {
+			return list[i].Delivered.Before(list[j].Delivered)
+		}
+		if list[i].Target != list[j].Target {
+			return list[i].Target < list[j].Target
+		}
+
+		return list[i].ResultID < list[j].ResultID
+	})
+
+	return list
+}
+
+// Overdue counts the unacknowledged deliveries older than the threshold per target
+func (t *Tracker) Overdue() map[string]int {
+	counts := make(map[string]int)
+
+	t.mx.RLock()
+	for target := range t.deliveries {
+		counts[target] = 0
+	}
+	t.mx.RUnlock()
+
+	for _, delivery := range t.Unacknowledged("") {
+		counts[delivery.Target]++
+	}
+
+	return counts
+}
+
+// Acknowledgments counts the acknowledged deliveries per target
+func (t *Tracker) Acknowledgments() map[string]uint64 {
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	counts := make(map[string]uint64, len(t.acknowledged))
+	for target, count := range t.acknowledged {
+		counts[target] = count
+	}
+
+	return counts
+}
+
+// Prune removes the deliveries older than the retention, acknowledged or not, and returns their number
+func (t *Tracker) Prune() int {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	deadline := time.Now().Add(-t.retention)
+	pruned := 0
+
+	for _, deliveries := range t.deliveries {
+		for id, delivery := range deliveries {
+			if !delivery.Delivered.After(deadline) {
+				delete(deliveries, id)
+				pruned++
+			}
+		}
+	}
+
+	return pruned
+}
+
+// Run prunes the deliveries in the interval until the context is done
+func (t *Tracker) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			t.Prune()
+		}
+	}
+}
+
+// NewTracker creates a Tracker of the targets, all targets if empty. Deliveries older than the threshold
+// are unacknowledged, deliveries older than the retention are removed
+func NewTracker(targets []string, threshold, retention time.Duration) *Tracker {
+	tracked := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		tracked[target] = true
+	}
+
+	return &Tracker{
+		mx:           new(sync.RWMutex),
+		targets:      tracked,
+		threshold:    threshold,
+		retention:    retention,
+		deliveries:   make(map[string]map[string]*Delivery),
+		acknowledged: make(map[string]uint64),
+	}
+}
+
+var (
+	current   *Tracker
+	currentMx sync.RWMutex
+)
+
+// SetTracker used by Record, a nil tracker disables the tracking
+func SetTracker(t *Tracker) {
+	currentMx.Lock()
+	defer currentMx.Unlock()
+
+	current = t
+}
+
+// Record the successful delivery of the result in the configured tracker
+func Record(target, resultID string) {
+	currentMx.RLock()
+	t := current
+	currentMx.RUnlock()
+
+	if t == nil {
+		return
+	}
+
+	t.Delivered(target, resultID)
+}
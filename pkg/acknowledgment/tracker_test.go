@@ -0,0 +1,182 @@
+package acknowledgment_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/acknowledgment"
+)
+
+func Test_Tracker(t *testing.T) {
+	t.Run("Acknowledge", func(t *testing.T) {
+		tracker := acknowledgment.NewTracker(nil, 0, time.Hour)
+		tracker.Delivered("SIEM", "1")
+		tracker.Delivered("SIEM", "2")
+		tracker.Delivered("Slack", "1")
+
+		count, unknown := tracker.Acknowledge("SIEM", []string{"1", "3"})
+		if count != 1 || len(unknown) != 1 || unknown[0] != "3" {
+			t.Errorf("expected 1 acknowledged and 3 unknown, got %d and %v", count, unknown)
+		}
+
+		list := tracker.Unacknowledged("")
+		if len(list) != 2 {
+			t.Fatalf("expected 2 unacknowledged deliveries, got %d", len(list))
+		}
+
+		list = tracker.Unacknowledged("SIEM")
+		if len(list) != 1 || list[0].ResultID != "2" {
+			t.Errorf("expected result 2 unacknowledged by SIEM, got %v", list)
+		}
+
+		overdue := tracker.Overdue()
+		if overdue["SIEM"] != 1 || overdue["Slack"] != 1 {
+			t.Errorf("unexpected overdue counts: %v", overdue)
+		}
+		if tracker.Acknowledgments()["SIEM"] != 1 {
+			t.Errorf("expected 1 acknowledgment of SIEM, got %v", tracker.Acknowledgments())
+		}
+	})
+
+	t.Run("Threshold", func(t *testing.T) {
+		tracker := acknowledgment.NewTracker(nil, time.Hour, 2*time.Hour)
+		tracker.Delivered("SIEM", "1")
+
+		if list := tracker.Unacknowledged(""); len(list) != 0 {
+			t.Errorf("expected no unacknowledged deliveries within the threshold, got %v", list)
+		}
+		if overdue := tracker.Overdue(); overdue["SIEM"] != 0 {
+			t.Errorf("expected no overdue deliveries, got %v", overdue)
+		}
+	})
+
+	t.Run("Redelivery", func(t *testing.T) {
+		tracker := acknowledgment.NewTracker(nil, 0, time.Hour)
+		tracker.Delivered("SIEM", "1")
+		tracker.Acknowledge("SIEM", []string{"1"})
+		tracker.Delivered("SIEM", "1")
+
+		if list := tracker.Unacknowledged("SIEM"); len(list) != 1 {
+			t.Errorf("expected a redelivered result to require a new acknowledgment, got %v", list)
+		}
+	})
+
+	t.Run("Targets", func(t *testing.T) {
+		tracker := acknowledgment.NewTracker([]string{"SIEM"}, 0, time.Hour)
+		tracker.Delivered("Slack", "1")
+		tracker.Delivered("SIEM", "")
+
+		if list := tracker.Unacknowledged(""); len(list) != 0 {
+			t.Errorf("expected only deliveries of tracked targets with result ID, got %v", list)
+		}
+	})
+
+	t.Run("Prune", func(t *testing.T) {
+		tracker := acknowledgment.NewTracker(nil, 0, 0)
+		tracker.Delivered("SIEM", "1")
+
+		if pruned := tracker.Prune(); pruned != 1 {
+			t.Errorf("expected 1 pruned delivery, got %d", pruned)
+		}
+		if list := tracker.Unacknowledged(""); len(list) != 0 {
+			t.Errorf("expected no deliveries after prune, got %v", list)
+		}
+	})
+
+	t.Run("Record", func(t *testing.T) {
+		tracker := acknowledgment.NewTracker(nil, 0, time.Hour)
+
+		acknowledgment.SetTracker(tracker)
+		defer acknowledgment.SetTracker(nil)
+
+		acknowledgment.Record("SIEM", "1")
+
+		if list := tracker.Unacknowledged(""); len(list) != 1 {
+			t.Errorf("expected the recorded delivery, got %v", list)
+		}
+	})
+}
+
+func Test_Handler(t *testing.T) {
+	tracker := acknowledgment.NewTracker(nil, 0, time.Hour)
+	tracker.Delivered("SIEM", "1")
+	tracker.Delivered("SIEM", "2")
+
+	t.Run("Acknowledge", func(t *testing.T) {
+		body, _ := json.Marshal(acknowledgment.Request{Target: "SIEM", ResultIDs: []string{"1", "3"}})
+
+		req := httptest.NewRequest(http.MethodPost, acknowledgment.AcknowledgePath, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		rr := httptest.NewRecorder()
+		tracker.AcknowledgeHandler().ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("unexpected status code: %d", rr.Code)
+		}
+
+		response := acknowledgment.Response{}
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		if response.Acknowledged != 1 || len(response.Unknown) != 1 {
+			t.Errorf("unexpected response: %+v", response)
+		}
+	})
+
+	t.Run("QueryParameters", func(t *testing.T) {
+		tracker.Delivered("SIEM", "4")
+
+		rr := httptest.NewRecorder()
+		tracker.AcknowledgeHandler().ServeHTTP(rr, httptest.NewRequest(http.MethodPost, acknowledgment.AcknowledgePath+"?target=SIEM&resultIds=1&resultIds=4", nil))
+
+		response := acknowledgment.Response{}
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		if response.Acknowledged != 2 || len(response.Unknown) != 0 {
+			t.Errorf("unexpected response: %+v", response)
+		}
+	})
+
+	t.Run("InvalidRequest", func(t *testing.T) {
+		for _, body := range []string{"{", `{"resultIds":["1"]}`, `{"target":"SIEM"}`} {
+			req := httptest.NewRequest(http.MethodPost, acknowledgment.AcknowledgePath, bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+			tracker.AcknowledgeHandler().ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("expected bad request for %s, got %d", body, rr.Code)
+			}
+		}
+
+		rr := httptest.NewRecorder()
+		tracker.AcknowledgeHandler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, acknowledgment.AcknowledgePath, nil))
+		if rr.Code != http.StatusMethodNotAllowed {
+			t.Errorf("expected method not allowed, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Unacknowledged", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		tracker.UnacknowledgedHandler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, acknowledgment.UnacknowledgedPath+"?target=SIEM", nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("unexpected status code: %d", rr.Code)
+		}
+
+		report := acknowledgment.Report{}
+		if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+		if report.Total != 1 || report.Targets["SIEM"] != 1 || report.Items[0].ResultID != "2" {
+			t.Errorf("unexpected report: %+v", report)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package admission
+
+import (
+	"fmt"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+// Modes of the webhook
+const (
+	// ModeWarn admits malformed PolicyReports and returns the problems as warnings to the producer
+	ModeWarn = "warn"
+	// ModeDeny rejects malformed PolicyReports
+	ModeDeny = "deny"
+)
+
+// Validate returns the problems of the PolicyReport: results without source, policy or with an unknown result or severity,
+// and a summary which does not match the results
+func Validate(rep v1alpha2.ReportInterface) []string {
+	problems := make([]string, 0)
+
+	for i, r := range rep.GetResults() {
+		if r.Source == "" {
+			problems = append(problems, fmt.Sprintf("results[%d]: missing source", i))
+		}
+		if r.Policy == "" {
+			problems = append(problems, fmt.Sprintf("results[%d]: missing policy", i))
+		}
+
+		switch r.Severity {
+		case "", v1alpha2.SeverityCritical, v1alpha2.SeverityHigh, v1alpha2.SeverityMedium, v1alpha2.SeverityLow, v1alpha2.SeverityInfo:
+		default:
+			problems = append(problems, fmt.Sprintf("results[%d]: invalid severity '%s'", i, r.Severity))
+		}
+
+		switch r.Result {
+		case v1alpha2.StatusPass, v1alpha2.StatusFail, v1alpha2.StatusWarn, v1alpha2.StatusError, v1alpha2.StatusSkip:
+		default:
+			problems = append(problems, fmt.Sprintf("results[%d]: unknown result '%s'", i, r.Result))
+		}
+	}
+
+	if summary, actual := report.Summarize(rep.GetResults()), rep.GetSummary(); actual != summary {
+		problems = append(problems, fmt.Sprintf(
+			"summary does not match the results: expected pass=%d fail=%d warn=%d error=%d skip=%d, got pass=%d fail=%d warn=%d error=%d skip=%d",
+			summary.Pass, summary.Fail, summary.Warn, summary.Error, summary.Skip,
+			actual.Pass, actual.Fail, actual.Warn, actual.Error, actual.Skip,
+		))
+	}
+
+	return problems
+}
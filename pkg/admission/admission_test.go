@@ -0,0 +1,134 @@
+package admission_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kyverno/policy-reporter/pkg/admission"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+var valid = &v1alpha2.PolicyReport{
+	ObjectMeta: metav1.ObjectMeta{Name: "polr-test", Namespace: "test"},
+	Summary:    v1alpha2.PolicyReportSummary{Pass: 1, Fail: 1},
+	Results: []v1alpha2.PolicyReportResult{
+		{Source: "kyverno", Policy: "require-requests", Result: v1alpha2.StatusPass, Severity: v1alpha2.SeverityHigh},
+		{Source: "kyverno", Policy: "require-limits", Result: v1alpha2.StatusFail},
+	},
+}
+
+var malformed = &v1alpha2.PolicyReport{
+	ObjectMeta: metav1.ObjectMeta{Name: "polr-test", Namespace: "test"},
+	Summary:    v1alpha2.PolicyReportSummary{Fail: 2},
+	Results: []v1alpha2.PolicyReportResult{
+		{Policy: "require-requests", Result: v1alpha2.StatusFail, Severity: "urgent"},
+		{Source: "kyverno", Policy: "require-limits", Result: "failed"},
+	},
+}
+
+func Test_Validate(t *testing.T) {
+	if problems := admission.Validate(valid); len(problems) != 0 {
+		t.Errorf("expected no problems, got %v", problems)
+	}
+
+	problems := admission.Validate(malformed)
+	expected := []string{
+		"results[0]: missing source",
+		"results[0]: invalid severity 'urgent'",
+		"results[1]: unknown result 'failed'",
+		"summary does not match the results: expected pass=0 fail=1 warn=0 error=0 skip=0, got pass=0 fail=2 warn=0 error=0 skip=0",
+	}
+
+	if len(problems) != len(expected) {
+		t.Fatalf("expected %d problems, got %v", len(expected), problems)
+	}
+	for i, problem := range expected {
+		if problems[i] != problem {
+			t.Errorf("expected problem '%s', got '%s'", problem, problems[i])
+		}
+	}
+}
+
+func review(t *testing.T, mode string, operation admissionv1.Operation, rep *v1alpha2.PolicyReport) *admissionv1.AdmissionResponse {
+	raw, err := json.Marshal(rep)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := json.Marshal(admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request: &admissionv1.AdmissionRequest{
+			UID:       "1234",
+			Kind:      metav1.GroupVersionKind{Group: "wgpolicyk8s.io", Version: "v1alpha2", Kind: "PolicyReport"},
+			Operation: operation,
+			Name:      rep.Name,
+			Namespace: rep.Namespace,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, admission.Path, bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	admission.NewWebhook(mode, 9443, "", "").Handler().ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+
+	result := admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Response == nil || result.Response.UID != "1234" {
+		t.Fatalf("expected the response of the request, got %+v", result.Response)
+	}
+
+	return result.Response
+}
+
+func Test_Webhook(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		response := review(t, admission.ModeDeny, admissionv1.Create, valid)
+		if !response.Allowed || len(response.Warnings) != 0 {
+			t.Errorf("expected valid report to be allowed, got %+v", response)
+		}
+	})
+
+	t.Run("Warn", func(t *testing.T) {
+		response := review(t, admission.ModeWarn, admissionv1.Update, malformed)
+		if !response.Allowed || len(response.Warnings) != 4 {
+			t.Errorf("expected malformed report to be allowed with warnings, got %+v", response)
+		}
+	})
+
+	t.Run("Deny", func(t *testing.T) {
+		response := review(t, admission.ModeDeny, admissionv1.Create, malformed)
+		if response.Allowed || response.Result == nil || response.Result.Code != http.StatusUnprocessableEntity {
+			t.Errorf("expected malformed report to be rejected, got %+v", response)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		response := review(t, admission.ModeDeny, admissionv1.Delete, malformed)
+		if !response.Allowed {
+			t.Errorf("expected deletion to be allowed, got %+v", response)
+		}
+	})
+
+	t.Run("InvalidReview", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, admission.Path, bytes.NewReader([]byte("{}")))
+		rr := httptest.NewRecorder()
+
+		admission.NewWebhook(admission.ModeDeny, 9443, "", "").Handler().ServeHTTP(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusBadRequest)
+		}
+	})
+}
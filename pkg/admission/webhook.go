@@ -0,0 +1,124 @@
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+)
+
+var logger = logging.Component("admission")
+
+// Path of the validating webhook
+const Path = "/validate"
+
+// Webhook validates created and updated PolicyReports and ClusterPolicyReports of AdmissionReviews
+type Webhook struct {
+	mode     string
+	certFile string
+	keyFile  string
+	server   *http.Server
+}
+
+// Handler reviews the AdmissionReview of the request, malformed PolicyReports are rejected in deny mode
+// and admitted with warnings otherwise
+func (w *Webhook) Handler() http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			rw.Header().Set("Allow", http.MethodPost)
+			http.Error(rw, fmt.Sprintf("method %s not allowed", req.Method), http.StatusMethodNotAllowed)
+			return
+		}
+
+		review := admissionv1.AdmissionReview{}
+		if err := json.NewDecoder(req.Body).Decode(&review); err != nil || review.Request == nil {
+			http.Error(rw, "invalid AdmissionReview", http.StatusBadRequest)
+			return
+		}
+
+		review.Response = w.Review(review.Request)
+		review.Request = nil
+
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(review)
+	}
+}
+
+// Review validates the PolicyReport of the request, deletions and other kinds are allowed
+func (w *Webhook) Review(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	response := &admissionv1.AdmissionResponse{UID: req.UID, Allowed: true}
+
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return response
+	}
+
+	var rep v1alpha2.ReportInterface
+	switch req.Kind.Kind {
+	case "PolicyReport":
+		rep = &v1alpha2.PolicyReport{}
+	case "ClusterPolicyReport":
+		rep = &v1alpha2.ClusterPolicyReport{}
+	default:
+		return response
+	}
+
+	if err := json.Unmarshal(req.Object.Raw, rep); err != nil {
+		response.Allowed = false
+		response.Result = &metav1.Status{Code: http.StatusBadRequest, Message: fmt.Sprintf("invalid %s: %s", req.Kind.Kind, err)}
+
+		return response
+	}
+
+	problems := Validate(rep)
+	if len(problems) == 0 {
+		return response
+	}
+
+	logger.Debugf("%s %s/%s is malformed: %s", req.Kind.Kind, req.Namespace, req.Name, strings.Join(problems, "; "))
+
+	if w.mode != ModeDeny {
+		response.Warnings = problems
+
+		return response
+	}
+
+	response.Allowed = false
+	response.Result = &metav1.Status{
+		Code:    http.StatusUnprocessableEntity,
+		Reason:  metav1.StatusReasonInvalid,
+		Message: fmt.Sprintf("malformed %s: %s", req.Kind.Kind, strings.Join(problems, "; ")),
+	}
+
+	return response
+}
+
+// Start serves the webhook with TLS, the CA of the certificate has to be the caBundle of the webhook configuration
+func (w *Webhook) Start() error {
+	logger.Infof("admission webhook listen on %s", w.server.Addr)
+
+	return w.server.ListenAndServeTLS(w.certFile, w.keyFile)
+}
+
+// Stop the webhook server
+func (w *Webhook) Stop(ctx context.Context) error {
+	return w.server.Shutdown(ctx)
+}
+
+// NewWebhook creates a Webhook served on the port with the TLS certificate and key files
+func NewWebhook(mode string, port int, certFile, keyFile string) *Webhook {
+	w := &Webhook{mode: mode, certFile: certFile, keyFile: keyFile}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(Path, w.Handler())
+
+	w.server = &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: mux}
+
+	return w
+}
@@ -0,0 +1,376 @@
+// Package aging raises the severity of results which stay unresolved longer than the configured durations,
+// e.g. medium to high after 7 days.
+//
+// The Ager maps each report before it is published and tracks when its unresolved results were first seen.
+// Evaluate republishes the reports with results which reached the next severity since the last evaluation,
+// so the metrics and the stored results use the raised severity, and sends the raised results to the targets again.
+package aging
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/escalation"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/query"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/timeformat"
+)
+
+var logger = logging.Component("aging")
+
+const (
+	// Listener name of the Ager, removes the deleted reports
+	Listener = "aging_listener"
+	// RuleKey property with the name of the last applied aging rule, added to each aged result
+	RuleKey = "aging"
+	// OriginalSeverityKey property with the reported severity, added to each aged result
+	OriginalSeverityKey = "originalSeverity"
+	// FirstSeenKey property with the formatted time the result was first seen, added to each aged result
+	FirstSeenKey = "firstSeen"
+)
+
+// DefaultInterval of the evaluation
+const DefaultInterval = 10 * time.Minute
+
+// Rule raises the severity of unresolved results after the given duration
+type Rule struct {
+	Name     string
+	Severity v1alpha2.PolicySeverity
+	RaiseTo  v1alpha2.PolicySeverity
+	After    time.Duration
+	// Sources of the aged results, all sources if empty
+	Sources []string
+}
+
+func (r Rule) matches(severity v1alpha2.PolicySeverity, source string) bool {
+	if !strings.EqualFold(string(r.Severity), string(severity)) {
+		return false
+	}
+	// rules may only raise the severity, so chained rules terminate
+	if query.SeverityRank(string(r.RaiseTo)) <= query.SeverityRank(string(severity)) {
+		return false
+	}
+
+	return len(r.Sources) == 0 || contains(r.Sources, source)
+}
+
+// Options of the Ager
+type Options struct {
+	Rules []Rule
+	// Publisher of the republished reports
+	Publisher report.EventPublisher
+	// Clients returns the current target clients, the raised results are sent again if set
+	Clients func() []target.Client
+	// Targets by name which receive the raised results, all targets if empty
+	Targets []string
+	Mapper  report.Mapper
+	// Owns filters the namespaces this instance notifies, e.g. with sharding, all namespaces if nil
+	Owns func(namespace string) bool
+	// Snoozed skips the notification of snoozed results, their raised severity is published anyway
+	Snoozed func(resultID string) bool
+}
+
+type tracked struct {
+	source    string
+	firstSeen time.Time
+	// severity of the report
+	severity v1alpha2.PolicySeverity
+	// effective severity after the aging rules
+	effective v1alpha2.PolicySeverity
+	rule      string
+}
+
+type entry struct {
+	report  v1alpha2.ReportInterface
+	results map[string]tracked
+}
+
+// Ager raises the severity of unresolved results by their age
+type Ager struct {
+	options Options
+	mx      *sync.Mutex
+	// reports with results matching a rule by the report ID
+	reports map[string]*entry
+	// seen are the first seen times loaded from the store by report and result ID
+	seen map[string]time.Time
+}
+
+// Load the first seen times of the unresolved results from the store, so the age of a result survives restarts
+func (a *Ager) Load(finder escalation.Finder) error {
+	results, err := finder.FetchUnresolvedResults(time.Now())
+	if err != nil {
+		return err
+	}
+
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	for _, res := range results {
+		a.seen[key(res.Report.GetID(), res.Result.GetID())] = res.FirstSeen
+	}
+
+	return nil
+}
+
+// Map applies the effective severity to the unresolved results of the report before it is published
+func (a *Ager) Map(r v1alpha2.ReportInterface) {
+	now := time.Now()
+	id := r.GetID()
+
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	previous := a.reports[id]
+	e := &entry{report: r, results: make(map[string]tracked)}
+
+	results := r.GetResults()
+	for i := range results {
+		res := &results[i]
+		if !unresolved(res.Result) || !a.candidate(res.Severity, res.Source) {
+			continue
+		}
+
+		resultID := res.GetID()
+
+		t, ok := tracked{}, false
+		if previous != nil {
+			t, ok = previous.results[resultID]
+		}
+		if !ok {
+			t.firstSeen = now
+			if seen, found := a.seen[key(id, resultID)]; found {
+				t.firstSeen = seen
+				delete(a.seen, key(id, resultID))
+			}
+		}
+
+		t.source = res.Source
+		t.severity = res.Severity
+		t.effective, t.rule = a.severity(t.severity, t.source, now.Sub(t.firstSeen))
+
+		apply(res, t)
+		e.results[resultID] = t
+	}
+
+	if len(e.results) == 0 {
+		delete(a.reports, id)
+		return
+	}
+
+	a.reports[id] = e
+}
+
+// Listen removes deleted reports
+func (a *Ager) Listen(event report.LifecycleEvent) {
+	if event.Type != report.Deleted {
+		return
+	}
+
+	a.mx.Lock()
+	delete(a.reports, event.PolicyReport.GetID())
+	a.mx.Unlock()
+}
+
+// Evaluate republishes the reports with results which reached a higher severity and sends these results to the targets
+func (a *Ager) Evaluate(now time.Time) {
+	type raised struct {
+		report  v1alpha2.ReportInterface
+		results []v1alpha2.PolicyReportResult
+	}
+
+	list := make([]raised, 0)
+
+	a.mx.Lock()
+	for _, e := range a.reports {
+		changed := make(map[string]bool)
+		for resultID, t := range e.results {
+			effective, rule := a.severity(t.severity, t.source, now.Sub(t.firstSeen))
+			if effective == t.effective {
+				continue
+			}
+
+			t.effective, t.rule = effective, rule
+			e.results[resultID] = t
+			changed[resultID] = true
+		}
+
+		if len(changed) == 0 {
+			continue
+		}
+
+		// published reports are shared with the listeners, so the raised severities are applied to a copy
+		r := copyReport(e.report)
+		if r == nil {
+			continue
+		}
+
+		item := raised{report: r}
+
+		results := r.GetResults()
+		for i := range results {
+			resultID := results[i].GetID()
+			if t, ok := e.results[resultID]; ok {
+				apply(&results[i], t)
+			}
+			if changed[resultID] {
+				item.results = append(item.results, results[i])
+			}
+		}
+
+		e.report = r
+		list = append(list, item)
+	}
+	a.mx.Unlock()
+
+	for _, item := range list {
+		logger.Infof("aging: %d results of report %s raised to a higher severity", len(item.results), item.report.GetName())
+
+		if a.options.Publisher != nil {
+			a.options.Publisher.Publish(report.LifecycleEvent{Type: report.Updated, PolicyReport: item.report, Observed: now})
+		}
+
+		a.notify(item.report, item.results)
+	}
+}
+
+func (a *Ager) notify(r v1alpha2.ReportInterface, results []v1alpha2.PolicyReportResult) {
+	if a.options.Clients == nil || (a.options.Owns != nil && !a.options.Owns(r.GetNamespace())) {
+		return
+	}
+
+	for _, c := range a.options.Clients() {
+		if _, ok := c.(target.ReportClient); ok {
+			continue
+		}
+		if len(a.options.Targets) > 0 && !contains(a.options.Targets, c.Name()) {
+			continue
+		}
+
+		for _, result := range results {
+			if a.options.Snoozed != nil && a.options.Snoozed(result.GetID()) {
+				continue
+			}
+
+			// priorities declared by the policy metadata take precedence over the mapping
+			if a.options.Mapper != nil && result.Result == v1alpha2.StatusFail && result.Priority == v1alpha2.DefaultPriority {
+				result.Priority = a.options.Mapper.ResolvePriority(result.Policy, result.Severity)
+			}
+
+			if c.Validate(r, result) {
+				c.Send(c.Scrubber().Scrub(result))
+			}
+		}
+	}
+}
+
+// Run evaluates the rules in the given interval until the context is canceled, defaults to DefaultInterval
+func (a *Ager) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			a.Evaluate(now)
+		}
+	}
+}
+
+// candidate returns true if any rule applies to the reported severity
+func (a *Ager) candidate(severity v1alpha2.PolicySeverity, source string) bool {
+	for _, rule := range a.options.Rules {
+		if rule.matches(severity, source) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// severity applies the rules until no rule matches the raised severity, e.g. medium to high to critical
+func (a *Ager) severity(severity v1alpha2.PolicySeverity, source string, age time.Duration) (v1alpha2.PolicySeverity, string) {
+	applied := ""
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, rule := range a.options.Rules {
+			if age >= rule.After && rule.matches(severity, source) {
+				severity, applied, changed = rule.RaiseTo, rule.Name, true
+			}
+		}
+	}
+
+	return severity, applied
+}
+
+// apply sets the effective severity of the result, aged results keep the reported severity as property
+func apply(res *v1alpha2.PolicyReportResult, t tracked) {
+	props := make(map[string]string, len(res.Properties)+3)
+	for k, v := range res.Properties {
+		props[k] = v
+	}
+
+	delete(props, RuleKey)
+	delete(props, OriginalSeverityKey)
+	delete(props, FirstSeenKey)
+
+	if t.rule != "" {
+		props[RuleKey] = t.rule
+		props[OriginalSeverityKey] = string(t.severity)
+		props[FirstSeenKey] = timeformat.Format(t.firstSeen)
+	}
+
+	res.Severity = t.effective
+	res.Properties = props
+}
+
+func copyReport(r v1alpha2.ReportInterface) v1alpha2.ReportInterface {
+	switch rep := r.(type) {
+	case *v1alpha2.PolicyReport:
+		return rep.DeepCopy()
+	case *v1alpha2.ClusterPolicyReport:
+		return rep.DeepCopy()
+	default:
+		return nil
+	}
+}
+
+func unresolved(status v1alpha2.PolicyResult) bool {
+	return status == v1alpha2.StatusFail || status == v1alpha2.StatusWarn || status == v1alpha2.StatusError
+}
+
+func key(reportID, resultID string) string {
+	return reportID + "/" + resultID
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewAger creates an Ager for the configured rules
+func NewAger(options Options) *Ager {
+	return &Ager{
+		options: options,
+		mx:      new(sync.Mutex),
+		reports: make(map[string]*entry),
+		seen:    make(map[string]time.Time),
+	}
+}
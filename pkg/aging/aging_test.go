@@ -0,0 +1,181 @@
+package aging_test
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/aging"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/escalation"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/target"
+)
+
+type finder struct {
+	results []escalation.Result
+}
+
+func (f *finder) FetchUnresolvedResults(time.Time) ([]escalation.Result, error) {
+	return f.results, nil
+}
+
+type client struct {
+	target.BaseClient
+	sent []v1alpha2.PolicyReportResult
+}
+
+func (c *client) Send(result v1alpha2.PolicyReportResult) {
+	c.sent = append(c.sent, result)
+}
+
+func newClient(name string) *client {
+	return &client{BaseClient: target.NewBaseClient(target.ClientOptions{Name: name})}
+}
+
+func result(id string, status v1alpha2.PolicyResult, severity v1alpha2.PolicySeverity) v1alpha2.PolicyReportResult {
+	return v1alpha2.PolicyReportResult{
+		ID:        id,
+		Policy:    "require-labels",
+		Source:    "kyverno",
+		Result:    status,
+		Severity:  severity,
+		Resources: []corev1.ObjectReference{{APIVersion: "v1", Kind: "Pod", Name: "nginx", Namespace: "test"}},
+	}
+}
+
+func newReport() *v1alpha2.PolicyReport {
+	return &v1alpha2.PolicyReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "polr-test", Namespace: "test"},
+		Results: []v1alpha2.PolicyReportResult{
+			result("1", v1alpha2.StatusFail, v1alpha2.SeverityMedium),
+			result("2", v1alpha2.StatusFail, v1alpha2.SeverityMedium),
+			result("3", v1alpha2.StatusPass, v1alpha2.SeverityMedium),
+			result("4", v1alpha2.StatusFail, v1alpha2.SeverityLow),
+		},
+	}
+}
+
+var rules = []aging.Rule{
+	{Name: "medium", Severity: v1alpha2.SeverityMedium, RaiseTo: v1alpha2.SeverityHigh, After: 7 * 24 * time.Hour},
+	{Name: "high", Severity: v1alpha2.SeverityHigh, RaiseTo: v1alpha2.SeverityCritical, After: 14 * 24 * time.Hour},
+}
+
+func Test_Map(t *testing.T) {
+	now := time.Now()
+	rep := newReport()
+
+	ager := aging.NewAger(aging.Options{Rules: rules})
+	ager.Load(&finder{results: []escalation.Result{
+		{Report: rep, Result: result("1", v1alpha2.StatusFail, v1alpha2.SeverityMedium), FirstSeen: now.Add(-15 * 24 * time.Hour)},
+		{Report: rep, Result: result("2", v1alpha2.StatusFail, v1alpha2.SeverityMedium), FirstSeen: now.Add(-8 * 24 * time.Hour)},
+	}})
+
+	ager.Map(rep)
+
+	if rep.Results[0].Severity != v1alpha2.SeverityCritical || rep.Results[0].Properties[aging.RuleKey] != "high" {
+		t.Errorf("expected result 1 raised to critical by the chained rules, got %s %v", rep.Results[0].Severity, rep.Results[0].Properties)
+	}
+	if rep.Results[1].Severity != v1alpha2.SeverityHigh || rep.Results[1].Properties[aging.OriginalSeverityKey] != v1alpha2.SeverityMedium {
+		t.Errorf("expected result 2 raised to high, got %s %v", rep.Results[1].Severity, rep.Results[1].Properties)
+	}
+	if rep.Results[2].Severity != v1alpha2.SeverityMedium || rep.Results[3].Severity != v1alpha2.SeverityLow {
+		t.Errorf("expected passed results and results without rule unchanged")
+	}
+
+	// the next version of the report keeps the first seen time of the results
+	next := newReport()
+	ager.Map(next)
+
+	if next.Results[1].Severity != v1alpha2.SeverityHigh {
+		t.Errorf("expected result 2 to keep its age, got %s", next.Results[1].Severity)
+	}
+}
+
+func Test_Evaluate(t *testing.T) {
+	now := time.Now()
+
+	published := make([]report.LifecycleEvent, 0)
+
+	publisher := report.NewEventPublisher()
+	publisher.RegisterListener("test", func(event report.LifecycleEvent) {
+		published = append(published, event)
+	})
+
+	slack := newClient("slack")
+	pager := newClient("pagerduty")
+
+	ager := aging.NewAger(aging.Options{
+		Rules:     rules,
+		Publisher: publisher,
+		Clients:   func() []target.Client { return []target.Client{slack, pager} },
+		Targets:   []string{"slack"},
+	})
+	publisher.RegisterListener(aging.Listener, ager.Listen)
+
+	rep := newReport()
+	ager.Map(rep)
+
+	if rep.Results[0].Severity != v1alpha2.SeverityMedium {
+		t.Fatalf("expected new results unchanged, got %s", rep.Results[0].Severity)
+	}
+
+	ager.Evaluate(now.Add(time.Hour))
+	if len(published) != 0 || len(slack.sent) != 0 {
+		t.Fatalf("expected no changes before the duration")
+	}
+
+	ager.Evaluate(now.Add(8 * 24 * time.Hour))
+	if len(published) != 1 || published[0].Type != report.Updated {
+		t.Fatalf("expected the report republished, got %v", published)
+	}
+
+	republished := published[0].PolicyReport.GetResults()
+	if republished[0].Severity != v1alpha2.SeverityHigh || republished[1].Severity != v1alpha2.SeverityHigh {
+		t.Errorf("expected the failed medium results raised to high, got %v", republished)
+	}
+	if rep.Results[0].Severity != v1alpha2.SeverityMedium {
+		t.Errorf("expected the published report unchanged")
+	}
+	if len(slack.sent) != 2 || slack.sent[0].Properties[aging.FirstSeenKey] == "" {
+		t.Errorf("expected 2 raised results sent again, got %v", slack.sent)
+	}
+	if len(pager.sent) != 0 {
+		t.Errorf("expected no results for targets which are not configured")
+	}
+
+	ager.Evaluate(now.Add(9 * 24 * time.Hour))
+	if len(published) != 1 || len(slack.sent) != 2 {
+		t.Errorf("expected raised results to be published once")
+	}
+
+	ager.Evaluate(now.Add(15 * 24 * time.Hour))
+	if len(published) != 2 || published[1].PolicyReport.GetResults()[0].Severity != v1alpha2.SeverityCritical {
+		t.Errorf("expected results raised to critical by the second rule")
+	}
+
+	publisher.Publish(report.LifecycleEvent{Type: report.Deleted, PolicyReport: rep})
+	ager.Evaluate(now.Add(30 * 24 * time.Hour))
+	if len(published) != 3 {
+		t.Errorf("expected deleted reports not to be evaluated, got %d events", len(published))
+	}
+}
+
+func Test_Owns(t *testing.T) {
+	slack := newClient("slack")
+
+	ager := aging.NewAger(aging.Options{
+		Rules:   rules,
+		Clients: func() []target.Client { return []target.Client{slack} },
+		Owns:    func(string) bool { return false },
+	})
+
+	ager.Map(newReport())
+	ager.Evaluate(time.Now().Add(8 * 24 * time.Hour))
+
+	if len(slack.sent) != 0 {
+		t.Errorf("expected no notification of results owned by another instance")
+	}
+}
@@ -0,0 +1,330 @@
+// Package alerting sends an aggregated alert to targets when more than the configured number of matching results
+// are reported within a time window, e.g. more than 5 critical failures in namespace prod within 10 minutes
+package alerting
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/email"
+	"github.com/kyverno/policy-reporter/pkg/expression"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/query"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+var logger = logging.Component("alerting")
+
+// Listener name of the new result listener counting the results of the rules
+const Listener = "alerting_listener"
+
+// Source of the alert results
+const Source = "Policy Reporter"
+
+// Property keys added to each alert
+const (
+	// RuleKey property with the name of the alert rule
+	RuleKey = "alertRule"
+	// CountKey property with the number of matching results within the window
+	CountKey = "alertCount"
+	// WindowKey property with the window of the alert rule
+	WindowKey = "alertWindow"
+)
+
+// maxListedResults in the message of an alert
+const maxListedResults = 10
+
+// Mailer sends the alert email
+type Mailer interface {
+	Send(report email.Report, to []string) error
+}
+
+// Rule fires an alert if more than Threshold matching results are reported within the Window
+type Rule struct {
+	Name string
+	// Namespaces of the counted results with wildcard and "regex:" support, all namespaces if empty
+	Namespaces []string
+	// Sources of the counted results with wildcard and "regex:" support, all sources if empty
+	Sources []string
+	// MinimumSeverity of the counted results, all severities if empty
+	MinimumSeverity string
+	// Status of the counted results, defaults to fail
+	Status     []string
+	Expression *expression.Expression
+	Threshold  int
+	Window     time.Duration
+	// PerNamespace counts the results of each namespace separately
+	PerNamespace bool
+	// Targets by name, receive the alert in addition to their regular results
+	Targets []string
+	// Email receivers of the alert with the counted results
+	Email []string
+}
+
+func (r Rule) matches(rep v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) bool {
+	status := r.Status
+	if len(status) == 0 {
+		status = []string{v1alpha2.StatusFail}
+	}
+
+	if !contains(status, string(result.Result)) {
+		return false
+	}
+	if len(r.Namespaces) > 0 && !validate.MatchAny(r.Namespaces, rep.GetNamespace()) {
+		return false
+	}
+	if len(r.Sources) > 0 && !validate.MatchAny(r.Sources, result.Source) {
+		return false
+	}
+	if r.MinimumSeverity != "" && query.SeverityRank(string(result.Severity)) < query.SeverityRank(r.MinimumSeverity) {
+		return false
+	}
+
+	return r.Expression == nil || r.Expression.Validate(rep, result)
+}
+
+// Options of the Alerter
+type Options struct {
+	Rules []Rule
+	// Clients returns the current target clients
+	Clients func() []target.Client
+	Mapper  report.Mapper
+	// Mailer for rules with email receivers, optional
+	Mailer      Mailer
+	ClusterName string
+	// Owns filters the namespaces this instance alerts, e.g. with sharding, all namespaces if nil
+	Owns func(namespace string) bool
+	// Paused suppresses alerts, e.g. during a maintenance window
+	Paused func(now time.Time) bool
+}
+
+type observed struct {
+	namespace string
+	result    v1alpha2.PolicyReportResult
+	time      time.Time
+}
+
+// Alerter counts the new results of each rule within its window, the window is reset after an alert fired
+type Alerter struct {
+	options Options
+	mx      *sync.Mutex
+	windows map[string][]observed
+}
+
+// Observe counts the new result for each matching rule, results which existed on startup are skipped
+func (a *Alerter) Observe(rep v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult, preExisted bool) {
+	if preExisted || len(a.options.Rules) == 0 {
+		return
+	}
+	if a.options.Owns != nil && !a.options.Owns(rep.GetNamespace()) {
+		return
+	}
+
+	now := time.Now()
+
+	for _, rule := range a.options.Rules {
+		if !rule.matches(rep, result) {
+			continue
+		}
+
+		key := rule.Name
+		if rule.PerNamespace {
+			key = rule.Name + "/" + rep.GetNamespace()
+		}
+
+		if fired := a.observe(key, rule, observed{namespace: rep.GetNamespace(), result: result, time: now}); fired != nil {
+			if a.options.Paused != nil && a.options.Paused(now) {
+				logger.Infof("alert %s suppressed during maintenance", rule.Name)
+				continue
+			}
+
+			logger.Infof("alert %s: %d results within %s", rule.Name, len(fired), rule.Window)
+
+			a.send(rule, fired)
+			a.mail(rule, fired)
+		}
+	}
+}
+
+// observe adds the result to the window of the key and returns the results of the window if the threshold is exceeded
+func (a *Alerter) observe(key string, rule Rule, o observed) []observed {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	since := o.time.Add(-rule.Window)
+
+	window := make([]observed, 0, len(a.windows[key])+1)
+	for _, item := range a.windows[key] {
+		if item.time.After(since) && item.result.GetID() != o.result.GetID() {
+			window = append(window, item)
+		}
+	}
+	window = append(window, o)
+
+	if len(window) <= rule.Threshold {
+		a.windows[key] = window
+		return nil
+	}
+
+	delete(a.windows, key)
+
+	return window
+}
+
+// alert result of the rule with the count of the observed results and the highest severity
+func alert(rule Rule, results []observed, clusterName string) v1alpha2.PolicyReportResult {
+	severity := v1alpha2.PolicySeverity("")
+	for _, o := range results {
+		if query.SeverityRank(string(o.result.Severity)) > query.SeverityRank(string(severity)) {
+			severity = o.result.Severity
+		}
+	}
+
+	scope := "the cluster"
+	if clusterName != "" {
+		scope = clusterName
+	}
+
+	namespace := ""
+	if rule.PerNamespace {
+		namespace = results[0].namespace
+		scope = "namespace " + namespace
+	}
+
+	now := time.Now()
+
+	return v1alpha2.PolicyReportResult{
+		Source:    Source,
+		Policy:    rule.Name,
+		Message:   fmt.Sprintf("%d results in %s within %s: %s", len(results), scope, rule.Window, strings.Join(listed(results), ", ")),
+		Result:    v1alpha2.StatusFail,
+		Severity:  severity,
+		Category:  "Alert",
+		Timestamp: v1.Timestamp{Seconds: now.Unix(), Nanos: int32(now.Nanosecond())},
+		Resources: namespaceResource(namespace),
+		Properties: map[string]string{
+			RuleKey:   rule.Name,
+			CountKey:  strconv.Itoa(len(results)),
+			WindowKey: rule.Window.String(),
+		},
+	}
+}
+
+func namespaceResource(namespace string) []corev1.ObjectReference {
+	if namespace == "" {
+		return nil
+	}
+
+	return []corev1.ObjectReference{{APIVersion: "v1", Kind: "Namespace", Name: namespace}}
+}
+
+// listed policies of the results with their count, sorted by count
+func listed(results []observed) []string {
+	counts := make(map[string]int)
+	for _, o := range results {
+		counts[o.result.Policy]++
+	}
+
+	policies := make([]string, 0, len(counts))
+	for policy := range counts {
+		policies = append(policies, policy)
+	}
+	sort.SliceStable(policies, func(i, j int) bool {
+		if counts[policies[i]] == counts[policies[j]] {
+			return policies[i] < policies[j]
+		}
+		return counts[policies[i]] > counts[policies[j]]
+	})
+
+	if len(policies) > maxListedResults {
+		policies = policies[:maxListedResults]
+	}
+
+	list := make([]string, 0, len(policies))
+	for _, policy := range policies {
+		list = append(list, fmt.Sprintf("%s (%d)", policy, counts[policy]))
+	}
+
+	return list
+}
+
+func (a *Alerter) send(rule Rule, results []observed) {
+	if len(rule.Targets) == 0 || a.options.Clients == nil {
+		return
+	}
+
+	result := alert(rule, results, a.options.ClusterName)
+	if a.options.Mapper != nil {
+		result.Priority = a.options.Mapper.ResolvePriority(result.Policy, result.Severity)
+	}
+
+	for _, c := range a.options.Clients() {
+		if contains(rule.Targets, c.Name()) {
+			c.Send(c.Scrubber().Scrub(result))
+		}
+	}
+}
+
+func (a *Alerter) mail(rule Rule, results []observed) {
+	if len(rule.Email) == 0 || a.options.Mailer == nil {
+		return
+	}
+
+	lines := make([]string, 0, len(results)+1)
+	lines = append(lines, fmt.Sprintf("%d results within %s:\n", len(results), rule.Window))
+
+	for _, o := range results {
+		r := o.result
+
+		resource := ""
+		if r.HasResource() {
+			resource = fmt.Sprintf(" %s %s", r.GetResource().Kind, strings.TrimPrefix(r.GetResource().Namespace+"/"+r.GetResource().Name, "/"))
+		}
+
+		lines = append(lines, fmt.Sprintf("- [%s] %s/%s:%s\n  %s", r.Severity, r.Policy, r.Rule, resource, r.Message))
+	}
+
+	title := fmt.Sprintf("Policy Reporter alert: %s", rule.Name)
+	if a.options.ClusterName != "" {
+		title = fmt.Sprintf("%s on %s", title, a.options.ClusterName)
+	}
+
+	err := a.options.Mailer.Send(email.Report{
+		Title:       title,
+		Message:     strings.Join(lines, "\n"),
+		Format:      "text",
+		ClusterName: a.options.ClusterName,
+	}, rule.Email)
+	if err != nil {
+		logger.Errorf("failed to send alert email %s: %s", rule.Name, err)
+	}
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewAlerter creates a new Alerter
+func NewAlerter(options Options) *Alerter {
+	return &Alerter{
+		options: options,
+		mx:      new(sync.Mutex),
+		windows: make(map[string][]observed),
+	}
+}
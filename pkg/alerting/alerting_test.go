@@ -0,0 +1,151 @@
+package alerting_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/alerting"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/email"
+	"github.com/kyverno/policy-reporter/pkg/target"
+)
+
+type client struct {
+	target.BaseClient
+	sent []v1alpha2.PolicyReportResult
+}
+
+func (c *client) Send(result v1alpha2.PolicyReportResult) {
+	c.sent = append(c.sent, result)
+}
+
+func newClient(name string) *client {
+	return &client{BaseClient: target.NewBaseClient(target.ClientOptions{Name: name})}
+}
+
+type mailer struct {
+	reports []email.Report
+	to      []string
+}
+
+func (m *mailer) Send(report email.Report, to []string) error {
+	m.reports = append(m.reports, report)
+	m.to = to
+
+	return nil
+}
+
+func report(namespace string) *v1alpha2.PolicyReport {
+	return &v1alpha2.PolicyReport{ObjectMeta: metav1.ObjectMeta{Name: "polr-" + namespace, Namespace: namespace}}
+}
+
+func result(id string, status v1alpha2.PolicyResult, severity v1alpha2.PolicySeverity) v1alpha2.PolicyReportResult {
+	return v1alpha2.PolicyReportResult{ID: id, Source: "kyverno", Policy: "require-labels", Result: status, Severity: severity}
+}
+
+func Test_Observe(t *testing.T) {
+	slack := newClient("slack")
+	loki := newClient("loki")
+	mail := &mailer{}
+
+	alerter := alerting.NewAlerter(alerting.Options{
+		Rules: []alerting.Rule{{
+			Name:            "critical-prod",
+			Namespaces:      []string{"prod"},
+			MinimumSeverity: v1alpha2.SeverityCritical,
+			Threshold:       2,
+			Window:          10 * time.Minute,
+			Targets:         []string{"slack"},
+			Email:           []string{"security@company.org"},
+		}},
+		Clients:     func() []target.Client { return []target.Client{slack, loki} },
+		Mailer:      mail,
+		ClusterName: "prod-cluster",
+	})
+
+	alerter.Observe(report("prod"), result("1", v1alpha2.StatusFail, v1alpha2.SeverityCritical), false)
+	alerter.Observe(report("prod"), result("2", v1alpha2.StatusFail, v1alpha2.SeverityHigh), false)
+	alerter.Observe(report("dev"), result("3", v1alpha2.StatusFail, v1alpha2.SeverityCritical), false)
+	alerter.Observe(report("prod"), result("4", v1alpha2.StatusWarn, v1alpha2.SeverityCritical), false)
+	alerter.Observe(report("prod"), result("5", v1alpha2.StatusFail, v1alpha2.SeverityCritical), true)
+	alerter.Observe(report("prod"), result("1", v1alpha2.StatusFail, v1alpha2.SeverityCritical), false)
+
+	if len(slack.sent) != 0 {
+		t.Fatalf("expected no alert below the threshold, got %d", len(slack.sent))
+	}
+
+	alerter.Observe(report("prod"), result("6", v1alpha2.StatusFail, v1alpha2.SeverityCritical), false)
+	alerter.Observe(report("prod"), result("7", v1alpha2.StatusFail, v1alpha2.SeverityCritical), false)
+
+	if len(slack.sent) != 1 || len(loki.sent) != 0 {
+		t.Fatalf("expected one alert to the rule targets, got %d and %d", len(slack.sent), len(loki.sent))
+	}
+
+	alert := slack.sent[0]
+	if alert.Policy != "critical-prod" || alert.Source != alerting.Source || alert.Severity != v1alpha2.SeverityCritical {
+		t.Errorf("unexpected alert %+v", alert)
+	}
+	if alert.Properties[alerting.CountKey] != "3" || alert.Properties[alerting.WindowKey] != "10m0s" {
+		t.Errorf("unexpected alert properties %v", alert.Properties)
+	}
+	if !strings.HasPrefix(alert.Message, "3 results in prod-cluster within 10m0s: require-labels (3)") {
+		t.Errorf("unexpected alert message %s", alert.Message)
+	}
+
+	if len(mail.reports) != 1 || mail.to[0] != "security@company.org" || mail.reports[0].Title != "Policy Reporter alert: critical-prod on prod-cluster" {
+		t.Errorf("unexpected alert email %v", mail.reports)
+	}
+
+	alerter.Observe(report("prod"), result("8", v1alpha2.StatusFail, v1alpha2.SeverityCritical), false)
+	if len(slack.sent) != 1 {
+		t.Error("expected the window to be reset after the alert")
+	}
+}
+
+func Test_ObserveWindow(t *testing.T) {
+	slack := newClient("slack")
+
+	alerter := alerting.NewAlerter(alerting.Options{
+		Rules:   []alerting.Rule{{Name: "failures", Threshold: 1, Window: 10 * time.Millisecond, PerNamespace: true, Targets: []string{"slack"}}},
+		Clients: func() []target.Client { return []target.Client{slack} },
+	})
+
+	alerter.Observe(report("prod"), result("1", v1alpha2.StatusFail, ""), false)
+	alerter.Observe(report("dev"), result("2", v1alpha2.StatusFail, ""), false)
+	if len(slack.sent) != 0 {
+		t.Fatal("expected results of each namespace to be counted separately")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	alerter.Observe(report("prod"), result("3", v1alpha2.StatusFail, ""), false)
+	if len(slack.sent) != 0 {
+		t.Fatal("expected expired results to be removed from the window")
+	}
+
+	alerter.Observe(report("prod"), result("4", v1alpha2.StatusFail, ""), false)
+	if len(slack.sent) != 1 {
+		t.Fatalf("expected an alert for namespace prod, got %d", len(slack.sent))
+	}
+	if res := slack.sent[0].GetResource(); res == nil || res.Kind != "Namespace" || res.Name != "prod" {
+		t.Errorf("expected the namespace as alert resource, got %v", res)
+	}
+}
+
+func Test_ObservePaused(t *testing.T) {
+	slack := newClient("slack")
+
+	alerter := alerting.NewAlerter(alerting.Options{
+		Rules:   []alerting.Rule{{Name: "failures", Window: time.Minute, Targets: []string{"slack"}}},
+		Clients: func() []target.Client { return []target.Client{slack} },
+		Paused:  func(time.Time) bool { return true },
+	})
+
+	alerter.Observe(report("prod"), result("1", v1alpha2.StatusFail, ""), false)
+	if len(slack.sent) != 0 {
+		t.Error("expected no alert during maintenance")
+	}
+}
@@ -0,0 +1,44 @@
+package annotation
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/restmapper"
+)
+
+type metadataPatcher struct {
+	client metadata.Interface
+	mapper *restmapper.DeferredDiscoveryRESTMapper
+}
+
+func (p *metadataPatcher) Patch(ctx context.Context, resource Resource, patch []byte) error {
+	gv, err := schema.ParseGroupVersion(resource.APIVersion)
+	if err != nil {
+		return err
+	}
+
+	mapping, err := p.mapper.RESTMapping(gv.WithKind(resource.Kind).GroupKind(), gv.Version)
+	if err != nil {
+		// reset the discovery cache for resources of new CRDs
+		p.mapper.Reset()
+		return err
+	}
+
+	_, err = p.client.Resource(mapping.Resource).Namespace(resource.Namespace).Patch(ctx, resource.Name, types.MergePatchType, patch, metav1.PatchOptions{FieldManager: "policy-reporter"})
+
+	return err
+}
+
+// NewPatcher patches the metadata of any resource kind, the resource of each kind is resolved with the discovery API
+func NewPatcher(client metadata.Interface, discoveryClient discovery.DiscoveryInterface) Patcher {
+	return &metadataPatcher{
+		client: client,
+		mapper: restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+	}
+}
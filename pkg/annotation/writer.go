@@ -0,0 +1,317 @@
+// Package annotation writes a summary of the violations of a resource as annotation onto the resource itself
+package annotation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+var logger = logging.Component("annotation")
+
+// Listener is the name of the PolicyReport listener feeding the Writer
+const Listener = "annotation_writer_listener"
+
+// DefaultName of the written annotation
+const DefaultName = "policy-reporter.io/violations"
+
+// ownershipInterval is the interval the ownership of the namespaces is checked with sharding or leader election
+const ownershipInterval = 10 * time.Second
+
+// summarized status in the order of the annotation value
+var statusOrder = []v1alpha2.PolicyResult{v1alpha2.StatusFail, v1alpha2.StatusError, v1alpha2.StatusWarn}
+
+// Resource identifies an annotated resource
+type Resource struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+}
+
+func (r Resource) String() string {
+	return fmt.Sprintf("%s/%s %s/%s", r.APIVersion, r.Kind, r.Namespace, r.Name)
+}
+
+type summary map[v1alpha2.PolicyResult]int
+
+// Patcher applies a JSON merge patch to a resource
+type Patcher interface {
+	Patch(ctx context.Context, resource Resource, patch []byte) error
+}
+
+// Writer tracks the violations of each resource and patches the annotation when the summary changes
+type Writer struct {
+	patcher    Patcher
+	name       string
+	namespaces validate.RuleSets
+	owns       func(namespace string) bool
+	mx         *sync.Mutex
+	reports    map[string]map[Resource]summary
+	written    map[Resource]string
+	owned      map[string]bool
+	stale      map[Resource]bool
+	queue      workqueue.RateLimitingInterface
+}
+
+// Listen is the PolicyReportListener of the Writer
+func (w *Writer) Listen(event report.LifecycleEvent) {
+	current := make(map[Resource]summary)
+	if event.Type != report.Deleted {
+		for _, r := range event.PolicyReport.GetResults() {
+			res := r.GetResource()
+			if res == nil {
+				res = event.PolicyReport.GetScope()
+			}
+			if !w.allowed(res) {
+				continue
+			}
+
+			key := Resource{APIVersion: res.APIVersion, Kind: res.Kind, Namespace: res.Namespace, Name: res.Name}
+			if _, ok := current[key]; !ok {
+				current[key] = summary{}
+			}
+
+			current[key][r.Result]++
+		}
+	}
+
+	w.mx.Lock()
+	previous := w.reports[event.PolicyReport.GetID()]
+	if len(current) == 0 {
+		delete(w.reports, event.PolicyReport.GetID())
+	} else {
+		w.reports[event.PolicyReport.GetID()] = current
+	}
+	w.mx.Unlock()
+
+	for key := range current {
+		w.queue.Add(key)
+	}
+	for key := range previous {
+		if _, ok := current[key]; !ok {
+			w.queue.Add(key)
+		}
+	}
+}
+
+func (w *Writer) allowed(res *corev1.ObjectReference) bool {
+	if res == nil || res.Name == "" || res.Kind == "" {
+		return false
+	}
+
+	// cluster scoped resources are not annotated
+	if res.Namespace == "" {
+		return false
+	}
+
+	return validate.MatchRuleSet(res.Namespace, w.namespaces)
+}
+
+// value of the annotation, empty if the resource has no violations
+func (w *Writer) value(resource Resource) string {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	total := summary{}
+	for _, resources := range w.reports {
+		for status, count := range resources[resource] {
+			total[status] += count
+		}
+	}
+
+	parts := make([]string, 0, len(statusOrder))
+	for _, status := range statusOrder {
+		if total[status] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", total[status], status))
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Resync takes over the resources of newly owned namespaces, their annotations are patched once even without change
+// since another replica may have written them. Resources of namespaces owned by another replica are forgotten
+func (w *Writer) Resync() {
+	if w.owns == nil {
+		return
+	}
+
+	w.mx.Lock()
+	namespaces := make(map[string][]Resource)
+	for _, resources := range w.reports {
+		for resource := range resources {
+			namespaces[resource.Namespace] = append(namespaces[resource.Namespace], resource)
+		}
+	}
+	for resource := range w.written {
+		namespaces[resource.Namespace] = append(namespaces[resource.Namespace], resource)
+	}
+
+	taken := make([]Resource, 0)
+	for namespace, resources := range namespaces {
+		owned := w.owns(namespace)
+		for _, resource := range resources {
+			if !owned {
+				delete(w.written, resource)
+				delete(w.stale, resource)
+			} else if !w.owned[namespace] {
+				w.stale[resource] = true
+				taken = append(taken, resource)
+			}
+		}
+
+		if owned {
+			w.owned[namespace] = true
+		} else {
+			delete(w.owned, namespace)
+		}
+	}
+	w.mx.Unlock()
+
+	for _, resource := range taken {
+		w.queue.Add(resource)
+	}
+}
+
+func (w *Writer) sync(ctx context.Context, resource Resource) error {
+	// with sharding or leader election only the owner of the namespace patches its resources
+	if w.owns != nil && !w.owns(resource.Namespace) {
+		return nil
+	}
+
+	value := w.value(resource)
+
+	w.mx.Lock()
+	written := w.written[resource]
+	stale := w.stale[resource]
+	w.owned[resource.Namespace] = true
+	w.mx.Unlock()
+
+	// resources without written annotation and violations are not touched
+	if written == value && !stale {
+		return nil
+	}
+
+	var annotation interface{} = value
+	if value == "" {
+		annotation = nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{w.name: annotation},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := w.patcher.Patch(ctx, resource, patch); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	w.mx.Lock()
+	if value == "" {
+		delete(w.written, resource)
+	} else {
+		w.written[resource] = value
+	}
+	delete(w.stale, resource)
+	w.mx.Unlock()
+
+	return nil
+}
+
+func (w *Writer) processNextItem(ctx context.Context) bool {
+	item, quit := w.queue.Get()
+	if quit {
+		return false
+	}
+	defer w.queue.Done(item)
+
+	resource := item.(Resource)
+
+	if err := w.sync(ctx, resource); err != nil {
+		if w.queue.NumRequeues(item) < 5 {
+			w.queue.AddRateLimited(item)
+			return true
+		}
+
+		logger.Errorf("failed to annotate %s: %s", resource, err)
+	}
+
+	w.queue.Forget(item)
+
+	return true
+}
+
+// Run processes the annotation changes with the given amount of workers until the context is canceled
+func (w *Writer) Run(ctx context.Context, workers int) error {
+	go func() {
+		<-ctx.Done()
+		w.queue.ShutDown()
+	}()
+
+	if w.owns != nil {
+		go func() {
+			ticker := time.NewTicker(ownershipInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					w.Resync()
+				}
+			}
+		}()
+	}
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for w.processNextItem(ctx) {
+			}
+		}()
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// NewWriter creates a Writer for resources in the allowed namespaces, the annotation name defaults to DefaultName.
+// With owns only resources of owned namespaces are patched, nil owns all namespaces
+func NewWriter(patcher Patcher, name string, namespaces []string, owns func(namespace string) bool) *Writer {
+	if name == "" {
+		name = DefaultName
+	}
+
+	return &Writer{
+		patcher:    patcher,
+		name:       name,
+		namespaces: validate.RuleSets{Include: namespaces},
+		owns:       owns,
+		mx:         new(sync.Mutex),
+		reports:    make(map[string]map[Resource]summary),
+		written:    make(map[Resource]string),
+		owned:      make(map[string]bool),
+		stale:      make(map[Resource]bool),
+		queue:      workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+	}
+}
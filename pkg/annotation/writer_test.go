@@ -0,0 +1,202 @@
+package annotation_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	metafake "k8s.io/client-go/metadata/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kyverno/policy-reporter/pkg/annotation"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+type patch struct {
+	resource annotation.Resource
+	value    *string
+}
+
+type patcher struct {
+	patches chan patch
+}
+
+func (p *patcher) Patch(_ context.Context, resource annotation.Resource, content []byte) error {
+	body := struct {
+		Metadata struct {
+			Annotations map[string]*string `json:"annotations"`
+		} `json:"metadata"`
+	}{}
+	if err := json.Unmarshal(content, &body); err != nil {
+		return err
+	}
+
+	p.patches <- patch{resource: resource, value: body.Metadata.Annotations[annotation.DefaultName]}
+
+	return nil
+}
+
+var nginx = corev1.ObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "test", Name: "nginx"}
+
+func newReport(namespace string, results ...v1alpha2.PolicyResult) *v1alpha2.PolicyReport {
+	res := nginx
+	res.Namespace = namespace
+
+	list := make([]v1alpha2.PolicyReportResult, 0, len(results))
+	for i, r := range results {
+		list = append(list, v1alpha2.PolicyReportResult{Policy: "policy", Rule: string(rune('a' + i)), Result: r, Resources: []corev1.ObjectReference{res}})
+	}
+
+	return &v1alpha2.PolicyReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "polr", Namespace: namespace},
+		Results:    list,
+	}
+}
+
+func next(t *testing.T, patches chan patch) patch {
+	select {
+	case p := <-patches:
+		return p
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected patch")
+	}
+
+	return patch{}
+}
+
+func Test_Writer(t *testing.T) {
+	p := &patcher{patches: make(chan patch, 10)}
+	writer := annotation.NewWriter(p, "", []string{"test"}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		writer.Run(ctx, 1)
+	}()
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	t.Run("Annotate Violations", func(t *testing.T) {
+		writer.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: newReport("test", v1alpha2.StatusFail, v1alpha2.StatusFail, v1alpha2.StatusWarn, v1alpha2.StatusPass)})
+
+		result := next(t, p.patches)
+		if result.resource.Name != "nginx" || result.resource.Kind != "Deployment" {
+			t.Errorf("unexpected resource %s", result.resource)
+		}
+		if result.value == nil || *result.value != "2 fail, 1 warn" {
+			t.Errorf("unexpected annotation %v", result.value)
+		}
+	})
+
+	t.Run("Remove Cleared Violations", func(t *testing.T) {
+		writer.Listen(report.LifecycleEvent{Type: report.Updated, PolicyReport: newReport("test", v1alpha2.StatusWarn, v1alpha2.StatusFail, v1alpha2.StatusFail)})
+		writer.Listen(report.LifecycleEvent{Type: report.Updated, PolicyReport: newReport("test", v1alpha2.StatusPass)})
+
+		result := next(t, p.patches)
+		if result.value != nil {
+			t.Errorf("expected removed annotation, got %s", *result.value)
+		}
+		if len(p.patches) != 0 {
+			t.Errorf("expected no further patches, got %d", len(p.patches))
+		}
+	})
+
+	t.Run("Skip Namespaces", func(t *testing.T) {
+		writer.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: newReport("kube-system", v1alpha2.StatusFail)})
+		writer.Listen(report.LifecycleEvent{Type: report.Deleted, PolicyReport: newReport("test")})
+
+		select {
+		case result := <-p.patches:
+			t.Errorf("unexpected patch of %s", result.resource)
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+}
+
+func Test_WriterOwnership(t *testing.T) {
+	p := &patcher{patches: make(chan patch, 10)}
+
+	owner := &sync.Mutex{}
+	owned := false
+	writer := annotation.NewWriter(p, "", []string{"test"}, func(string) bool {
+		owner.Lock()
+		defer owner.Unlock()
+		return owned
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		writer.Run(ctx, 1)
+	}()
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	writer.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: newReport("test", v1alpha2.StatusFail)})
+
+	select {
+	case result := <-p.patches:
+		t.Fatalf("unexpected patch of %s by a replica not owning the namespace", result.resource)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	owner.Lock()
+	owned = true
+	owner.Unlock()
+	writer.Resync()
+
+	result := next(t, p.patches)
+	if result.value == nil || *result.value != "1 fail" {
+		t.Errorf("expected annotation after the takeover, got %v", result.value)
+	}
+
+	writer.Resync()
+
+	select {
+	case result := <-p.patches:
+		t.Errorf("unexpected second patch of %s", result.resource)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func Test_Patcher(t *testing.T) {
+	kube := kubefake.NewSimpleClientset()
+	kube.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "apps/v1",
+		APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment", Namespaced: true}},
+	}}
+
+	scheme := metafake.NewTestScheme()
+	metav1.AddMetaToScheme(scheme)
+	client := metafake.NewSimpleMetadataClient(scheme)
+
+	var action k8stesting.PatchAction
+	client.PrependReactor("patch", "*", func(a k8stesting.Action) (bool, runtime.Object, error) {
+		action = a.(k8stesting.PatchAction)
+		return true, &metav1.PartialObjectMetadata{}, nil
+	})
+
+	err := annotation.NewPatcher(client, kube.Discovery()).Patch(context.Background(), annotation.Resource{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "test", Name: "nginx"}, []byte(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if action.GetResource().Resource != "deployments" || action.GetNamespace() != "test" || action.GetName() != "nginx" {
+		t.Errorf("unexpected patch action %v", action)
+	}
+}
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/api/auth"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/secret"
+)
+
+// AccessEntry of a single API request
+type AccessEntry struct {
+	Time       string  `json:"time"`
+	Level      string  `json:"level"`
+	Method     string  `json:"method"`
+	Path       string  `json:"path"`
+	Query      string  `json:"query,omitempty"`
+	Status     int     `json:"status"`
+	DurationMS float64 `json:"durationMs"`
+	Bytes      int     `json:"bytes"`
+	User       string  `json:"user,omitempty"`
+	RemoteAddr string  `json:"remoteAddr"`
+	Slow       bool    `json:"slow,omitempty"`
+}
+
+// AccessLog middleware logs the requests of the API, slow requests and server errors are always logged,
+// all other requests with the sample rate
+type AccessLog struct {
+	out        io.Writer
+	encoding   string
+	sampleRate float64
+	slow       time.Duration
+	exclude    map[string]bool
+	mx         *sync.Mutex
+	random     *rand.Rand
+}
+
+// Handler logs the requests of the next handler, excluded paths are not logged
+func (a *AccessLog) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if a.exclude[req.URL.Path] {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		start := time.Now()
+		ctx, identity := auth.WithIdentityRecorder(req.Context())
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, req.WithContext(ctx))
+
+		duration := time.Since(start)
+		slow := a.slow > 0 && duration >= a.slow
+		if !slow && recorder.status < http.StatusInternalServerError && !a.sample() {
+			return
+		}
+
+		level := logging.Info
+		if slow {
+			level = logging.Warning
+		}
+		if recorder.status >= http.StatusInternalServerError {
+			level = logging.Error
+		}
+
+		a.write(AccessEntry{
+			Time:       start.UTC().Format(time.RFC3339Nano),
+			Level:      level,
+			Method:     req.Method,
+			Path:       req.URL.Path,
+			Query:      secret.Redact(req.URL.RawQuery),
+			Status:     recorder.status,
+			DurationMS: float64(duration.Microseconds()) / 1000,
+			Bytes:      recorder.bytes,
+			User:       identity.Name,
+			RemoteAddr: remoteAddr(req),
+			Slow:       slow,
+		})
+	})
+}
+
+func (a *AccessLog) sample() bool {
+	if a.sampleRate >= 1 {
+		return true
+	}
+
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	return a.random.Float64() < a.sampleRate
+}
+
+func (a *AccessLog) write(e AccessEntry) {
+	var line []byte
+	if a.encoding == logging.Console {
+		query := ""
+		if e.Query != "" {
+			query = "?" + e.Query
+		}
+
+		line = []byte(fmt.Sprintf("%s [%s] %s %s%s status=%d duration=%.3fms bytes=%d user=%s remote=%s slow=%t",
+			time.Now().Format("2006/01/02 15:04:05"), strings.ToUpper(e.Level), e.Method, e.Path, query, e.Status, e.DurationMS, e.Bytes, e.User, e.RemoteAddr, e.Slow))
+	} else {
+		line, _ = json.Marshal(e)
+	}
+
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	a.out.Write(append(line, '\n'))
+}
+
+// remoteAddr of the caller, the first X-Forwarded-For address if the request passed a proxy
+func remoteAddr(req *http.Request) string {
+	if forwarded := req.Header.Get("X-Forwarded-For"); forwarded != "" {
+		return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+	}
+
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		return host
+	}
+
+	return req.RemoteAddr
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+
+	return n, err
+}
+
+// Flush the response of streaming handlers
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// NewAccessLog writes the requests with the console or json encoding to out, a sample rate of 0 or below 0 logs only slow
+// requests and server errors, a slow threshold of 0 disables the slow request logging
+func NewAccessLog(out io.Writer, encoding string, sampleRate float64, slow time.Duration, exclude []string) *AccessLog {
+	paths := make(map[string]bool, len(exclude))
+	for _, path := range exclude {
+		paths[path] = true
+	}
+
+	return &AccessLog{
+		out:        out,
+		encoding:   encoding,
+		sampleRate: sampleRate,
+		slow:       slow,
+		exclude:    paths,
+		mx:         new(sync.Mutex),
+		random:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
@@ -0,0 +1,88 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/api"
+	"github.com/kyverno/policy-reporter/pkg/api/auth"
+)
+
+type staticAuthenticator struct{}
+
+func (staticAuthenticator) Authenticate(*http.Request) (auth.Identity, error) {
+	return auth.Identity{Name: "policy-reporter-ui", Level: auth.Read}, nil
+}
+
+func Test_AccessLog(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/slow" {
+			time.Sleep(20 * time.Millisecond)
+		}
+		if req.URL.Path == "/error" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		w.Write([]byte("ok"))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		accessLog := api.NewAccessLog(out, "json", 1, time.Second, []string{"/ready"})
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/v1/policy-reports", auth.Handler(staticAuthenticator{}, auth.Read, handler))
+		mux.HandleFunc("/ready", handler)
+
+		for _, path := range []string{"/v1/policy-reports?namespaces=test", "/ready"} {
+			req := httptest.NewRequest("GET", path, nil)
+			req.Header.Set("X-Forwarded-For", "10.0.0.1, 10.0.0.2")
+			accessLog.Handler(mux).ServeHTTP(httptest.NewRecorder(), req)
+		}
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		if len(lines) != 1 {
+			t.Fatalf("expected one entry without the excluded path, got %d", len(lines))
+		}
+
+		entry := api.AccessEntry{}
+		if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+			t.Fatal(err)
+		}
+
+		if entry.Method != "GET" || entry.Path != "/v1/policy-reports" || entry.Query != "namespaces=test" {
+			t.Errorf("unexpected request fields: %+v", entry)
+		}
+		if entry.Status != http.StatusOK || entry.Bytes != 2 || entry.Level != "info" {
+			t.Errorf("unexpected response fields: %+v", entry)
+		}
+		if entry.User != "policy-reporter-ui" || entry.RemoteAddr != "10.0.0.1" {
+			t.Errorf("unexpected caller fields: %+v", entry)
+		}
+	})
+
+	t.Run("Sampling", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		accessLog := api.NewAccessLog(out, "console", 0, 10*time.Millisecond, nil)
+
+		for _, path := range []string{"/fast", "/slow", "/error"} {
+			accessLog.Handler(handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+		}
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("expected only the slow and the failed request, got %v", lines)
+		}
+		if !strings.Contains(lines[0], "[WARNING] GET /slow status=200") || !strings.Contains(lines[0], "slow=true") {
+			t.Errorf("unexpected slow request entry: %s", lines[0])
+		}
+		if !strings.Contains(lines[1], "[ERROR] GET /error status=500") {
+			t.Errorf("unexpected failed request entry: %s", lines[1])
+		}
+	})
+}
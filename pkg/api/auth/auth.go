@@ -0,0 +1,140 @@
+// Package auth authenticates requests of the REST API with static bearer tokens, basic auth or OIDC ID tokens
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+
+	"github.com/kyverno/policy-reporter/pkg/logging"
+)
+
+var logger = logging.Component(logging.API)
+
+// Level of authorization, a higher level includes all lower levels
+type Level int
+
+const (
+	// Public endpoints require no authentication
+	Public Level = iota
+	// Read endpoints require an identity with read or admin level
+	Read
+	// Admin endpoints require an identity with admin level
+	Admin
+)
+
+func (l Level) String() string {
+	switch l {
+	case Public:
+		return "public"
+	case Admin:
+		return "admin"
+	default:
+		return "read"
+	}
+}
+
+// ParseLevel maps the configured level, read is used for empty or unknown values
+func ParseLevel(level string) Level {
+	if strings.EqualFold(level, "admin") {
+		return Admin
+	}
+
+	return Read
+}
+
+// ErrNoCredentials is returned if the request has no credentials supported by the Authenticator
+var ErrNoCredentials = errors.New("no credentials")
+
+// Identity of an authenticated request
+type Identity struct {
+	Name string
+	// Groups of Kubernetes or OIDC users, used for SubjectAccessReviews
+	Groups []string
+	Level  Level
+}
+
+type identityKey struct{}
+
+// WithIdentity adds the authenticated identity to the context
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+type recorderKey struct{}
+
+// WithIdentityRecorder adds a slot to the context which receives the identity authenticated by the Handler, e.g. for access logs
+func WithIdentityRecorder(ctx context.Context) (context.Context, *Identity) {
+	identity := &Identity{}
+
+	return context.WithValue(ctx, recorderKey{}, identity), identity
+}
+
+// IdentityFrom returns the authenticated identity of the request context
+func IdentityFrom(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+
+	return identity, ok
+}
+
+// Authenticator validates the credentials of a request
+type Authenticator interface {
+	// Authenticate returns ErrNoCredentials if the request has no credentials of the supported type
+	Authenticate(req *http.Request) (Identity, error)
+}
+
+// Chain of Authenticators, the first Authenticator supporting the credentials of a request decides
+type Chain []Authenticator
+
+func (c Chain) Authenticate(req *http.Request) (Identity, error) {
+	for _, a := range c {
+		identity, err := a.Authenticate(req)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+
+		return identity, err
+	}
+
+	return Identity{}, ErrNoCredentials
+}
+
+// Handler requires an authenticated identity with at least the given level, requests are not authenticated without Authenticator
+func Handler(authenticator Authenticator, level Level, next http.HandlerFunc) http.HandlerFunc {
+	if authenticator == nil || level == Public {
+		return next
+	}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		identity, err := authenticator.Authenticate(req)
+		if err != nil {
+			if !errors.Is(err, ErrNoCredentials) {
+				logger.Warnf("failed to authenticate request to %s: %s", req.URL.Path, err)
+			}
+
+			w.Header().Set("WWW-Authenticate", `Bearer realm="policy-reporter", Basic realm="policy-reporter"`)
+			sendError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		if identity.Level < level {
+			sendError(w, http.StatusForbidden, fmt.Sprintf("%s requires %s access", identity.Name, level))
+			return
+		}
+
+		if recorder, ok := req.Context().Value(recorderKey{}).(*Identity); ok {
+			*recorder = identity
+		}
+
+		next(w, req.WithContext(WithIdentity(req.Context(), identity)))
+	}
+}
+
+func sendError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `{ "message": "%s" }`, html.EscapeString(message))
+}
@@ -0,0 +1,73 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/api/auth"
+)
+
+var authenticator = auth.Chain{
+	auth.NewTokenAuthenticator([]auth.Token{{Name: "ci", Token: "admin-token", Level: auth.Admin}, {Name: "ui", Token: "read-token", Level: auth.Read}}),
+	auth.NewBasicAuthenticator([]auth.User{{Username: "user", Password: "password", Level: auth.Read}}),
+}
+
+func ok(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func Test_Handler(t *testing.T) {
+	cases := []struct {
+		name   string
+		level  auth.Level
+		setup  func(req *http.Request)
+		status int
+	}{
+		{name: "Without Credentials", level: auth.Read, setup: func(req *http.Request) {}, status: http.StatusUnauthorized},
+		{name: "Public Endpoint", level: auth.Public, setup: func(req *http.Request) {}, status: http.StatusOK},
+		{name: "Read Token", level: auth.Read, setup: func(req *http.Request) { req.Header.Set("Authorization", "Bearer read-token") }, status: http.StatusOK},
+		{name: "Read Token on Admin Endpoint", level: auth.Admin, setup: func(req *http.Request) { req.Header.Set("Authorization", "Bearer read-token") }, status: http.StatusForbidden},
+		{name: "Admin Token on Admin Endpoint", level: auth.Admin, setup: func(req *http.Request) { req.Header.Set("Authorization", "bearer admin-token") }, status: http.StatusOK},
+		{name: "Unknown Token", level: auth.Read, setup: func(req *http.Request) { req.Header.Set("Authorization", "Bearer unknown") }, status: http.StatusUnauthorized},
+		{name: "Basic Auth", level: auth.Read, setup: func(req *http.Request) { req.SetBasicAuth("user", "password") }, status: http.StatusOK},
+		{name: "Invalid Basic Auth", level: auth.Read, setup: func(req *http.Request) { req.SetBasicAuth("user", "wrong") }, status: http.StatusUnauthorized},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/v1/targets", nil)
+			c.setup(req)
+
+			rr := httptest.NewRecorder()
+			auth.Handler(authenticator, c.level, ok).ServeHTTP(rr, req)
+
+			if rr.Code != c.status {
+				t.Errorf("expected status %d, got %d", c.status, rr.Code)
+			}
+			if rr.Code == http.StatusUnauthorized && rr.Header().Get("WWW-Authenticate") == "" {
+				t.Error("expected WWW-Authenticate header")
+			}
+		})
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/targets", nil)
+
+		rr := httptest.NewRecorder()
+		auth.Handler(nil, auth.Admin, ok).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("expected status 200 without authenticator, got %d", rr.Code)
+		}
+	})
+}
+
+func Test_ParseLevel(t *testing.T) {
+	if auth.ParseLevel("Admin") != auth.Admin {
+		t.Error("expected admin level")
+	}
+	if auth.ParseLevel("") != auth.Read {
+		t.Error("expected read level as default")
+	}
+}
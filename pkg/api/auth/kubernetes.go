@@ -0,0 +1,235 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	authenticationclient "k8s.io/client-go/kubernetes/typed/authentication/v1"
+	authorizationclient "k8s.io/client-go/kubernetes/typed/authorization/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+// PolicyReport API group of the SubjectAccessReviews
+const policyReportGroup = "wgpolicyk8s.io"
+
+// defaultTTL of cached reviews
+const defaultTTL = time.Minute
+
+type tokenReviewAuthenticator struct {
+	client      authenticationclient.TokenReviewInterface
+	audiences   []string
+	adminGroups []string
+	cache       *gocache.Cache
+}
+
+func (a *tokenReviewAuthenticator) Authenticate(req *http.Request) (Identity, error) {
+	token, ok := BearerToken(req)
+	if !ok {
+		return Identity{}, ErrNoCredentials
+	}
+
+	// the cache key is a hash to not keep the plain tokens in memory
+	sum := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(sum[:])
+
+	if cached, ok := a.cache.Get(key); ok {
+		if identity, ok := cached.(Identity); ok {
+			return identity, nil
+		}
+
+		return Identity{}, ErrNoCredentials
+	}
+
+	review, err := a.client.Create(req.Context(), &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token, Audiences: a.audiences},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return Identity{}, fmt.Errorf("failed to create TokenReview: %w", err)
+	}
+
+	if !review.Status.Authenticated {
+		a.cache.SetDefault(key, false)
+
+		// the token could be validated by a subsequent Authenticator
+		return Identity{}, ErrNoCredentials
+	}
+
+	identity := Identity{Name: review.Status.User.Username, Groups: review.Status.User.Groups, Level: Read}
+	for _, group := range identity.Groups {
+		if contains(a.adminGroups, group) {
+			identity.Level = Admin
+		}
+	}
+
+	a.cache.SetDefault(key, identity)
+
+	return identity, nil
+}
+
+// AccessReviewer checks whether an identity is allowed to view the PolicyReports of a namespace
+type AccessReviewer interface {
+	// CanView the PolicyReports of the namespace, an empty namespace checks the access to ClusterPolicyReports
+	CanView(ctx context.Context, identity Identity, namespace string) (bool, error)
+}
+
+type subjectAccessReviewer struct {
+	client authorizationclient.SubjectAccessReviewInterface
+	cache  *gocache.Cache
+}
+
+func (r *subjectAccessReviewer) CanView(ctx context.Context, identity Identity, namespace string) (bool, error) {
+	key := identity.Name + "|" + strings.Join(identity.Groups, ",") + "|" + namespace
+	if allowed, ok := r.cache.Get(key); ok {
+		return allowed.(bool), nil
+	}
+
+	resource := "policyreports"
+	if namespace == "" {
+		resource = "clusterpolicyreports"
+	}
+
+	review, err := r.client.Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   identity.Name,
+			Groups: identity.Groups,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "list",
+				Group:     policyReportGroup,
+				Resource:  resource,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to create SubjectAccessReview: %w", err)
+	}
+
+	r.cache.SetDefault(key, review.Status.Allowed)
+
+	return review.Status.Allowed, nil
+}
+
+// Scope of an endpoint for the access review
+type Scope int
+
+const (
+	// Unscoped endpoints are available for all authenticated identities
+	Unscoped Scope = iota
+	// Namespaced endpoints restrict the namespaces query parameter to the accessible namespaces
+	Namespaced
+	// Cluster endpoints require access to ClusterPolicyReports
+	Cluster
+)
+
+// NamespaceLister returns the namespaces with PolicyReports, used if a request does not filter by namespace
+type NamespaceLister func() ([]string, error)
+
+// Scoped restricts the request to the namespaces the authenticated identity is allowed to view,
+// admins and requests without AccessReviewer are not restricted
+func Scoped(reviewer AccessReviewer, lister NamespaceLister, scope Scope, next http.HandlerFunc) http.HandlerFunc {
+	if reviewer == nil || scope == Unscoped {
+		return next
+	}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		identity, ok := IdentityFrom(req.Context())
+		if !ok || identity.Level == Admin {
+			next(w, req)
+			return
+		}
+
+		if scope == Cluster {
+			allowed, err := reviewer.CanView(req.Context(), identity, "")
+			if err != nil {
+				sendError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			if !allowed {
+				sendError(w, http.StatusForbidden, fmt.Sprintf("%s is not allowed to view cluster scoped results", identity.Name))
+				return
+			}
+
+			next(w, req)
+			return
+		}
+
+		query := req.URL.Query()
+
+		namespaces := query["namespaces"]
+		if len(namespaces) == 0 {
+			list, err := lister()
+			if err != nil {
+				sendError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+
+			namespaces = list
+		}
+
+		accessible := make([]string, 0, len(namespaces))
+		for _, ns := range namespaces {
+			scope := ns
+			// the cluster scope pseudo namespace requires access to cluster scoped results
+			if ns == report.ClusterScope {
+				scope = ""
+			}
+
+			allowed, err := reviewer.CanView(req.Context(), identity, scope)
+			if err != nil {
+				sendError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			if allowed {
+				accessible = append(accessible, ns)
+			}
+		}
+
+		if len(accessible) == 0 {
+			sendError(w, http.StatusForbidden, fmt.Sprintf("%s is not allowed to view the results of any requested namespace", identity.Name))
+			return
+		}
+
+		query["namespaces"] = accessible
+		req.URL.RawQuery = query.Encode()
+
+		next(w, req)
+	}
+}
+
+// NewTokenReviewAuthenticator validates Kubernetes tokens like ServiceAccount tokens with TokenReviews,
+// users of the adminGroups get admin access, results are cached for the given TTL
+func NewTokenReviewAuthenticator(client authenticationclient.TokenReviewInterface, audiences, adminGroups []string, ttl time.Duration) Authenticator {
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+
+	return &tokenReviewAuthenticator{
+		client:      client,
+		audiences:   audiences,
+		adminGroups: adminGroups,
+		cache:       gocache.New(ttl, 2*ttl),
+	}
+}
+
+// NewSubjectAccessReviewer checks the list permission of PolicyReports and ClusterPolicyReports with SubjectAccessReviews,
+// results are cached for the given TTL
+func NewSubjectAccessReviewer(client authorizationclient.SubjectAccessReviewInterface, ttl time.Duration) AccessReviewer {
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+
+	return &subjectAccessReviewer{
+		client: client,
+		cache:  gocache.New(ttl, 2*ttl),
+	}
+}
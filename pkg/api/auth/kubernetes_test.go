@@ -0,0 +1,149 @@
+package auth_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kyverno/policy-reporter/pkg/api/auth"
+)
+
+func newFakeClient() (*fake.Clientset, *int) {
+	client := fake.NewSimpleClientset()
+	reviews := 0
+
+	client.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		if review.Spec.Token == "dev-token" {
+			review.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User:          authenticationv1.UserInfo{Username: "system:serviceaccount:team-a:dev", Groups: []string{"system:serviceaccounts"}},
+			}
+		}
+
+		return true, review, nil
+	})
+
+	client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		reviews++
+
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		review.Status.Allowed = review.Spec.ResourceAttributes.Namespace == "team-a" && review.Spec.ResourceAttributes.Resource == "policyreports"
+
+		return true, review, nil
+	})
+
+	return client, &reviews
+}
+
+func Test_TokenReviewAuthenticator(t *testing.T) {
+	client, _ := newFakeClient()
+	authenticator := auth.NewTokenReviewAuthenticator(client.AuthenticationV1().TokenReviews(), nil, nil, time.Minute)
+
+	t.Run("Valid Token", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "Bearer dev-token")
+
+		identity, err := authenticator.Authenticate(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if identity.Name != "system:serviceaccount:team-a:dev" || len(identity.Groups) != 1 || identity.Level != auth.Read {
+			t.Errorf("unexpected identity %v", identity)
+		}
+	})
+
+	t.Run("Invalid Token", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "Bearer invalid")
+
+		if _, err := authenticator.Authenticate(req); err != auth.ErrNoCredentials {
+			t.Errorf("expected ErrNoCredentials, got %v", err)
+		}
+	})
+}
+
+func Test_Scoped(t *testing.T) {
+	client, reviews := newFakeClient()
+
+	authenticator := auth.Chain{
+		auth.NewTokenAuthenticator([]auth.Token{{Name: "admin", Token: "admin-token", Level: auth.Admin}}),
+		auth.NewTokenReviewAuthenticator(client.AuthenticationV1().TokenReviews(), nil, nil, time.Minute),
+	}
+	reviewer := auth.NewSubjectAccessReviewer(client.AuthorizationV1().SubjectAccessReviews(), time.Minute)
+	lister := func() ([]string, error) { return []string{"team-a", "team-b"}, nil }
+
+	echo := func(w http.ResponseWriter, req *http.Request) {
+		json.NewEncoder(w).Encode(req.URL.Query()["namespaces"])
+	}
+
+	call := func(scope auth.Scope, token, query string) (int, []string) {
+		req, _ := http.NewRequest("GET", "/v1/namespaced-resources/results"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		rr := httptest.NewRecorder()
+		auth.Handler(authenticator, auth.Read, auth.Scoped(reviewer, lister, scope, echo)).ServeHTTP(rr, req)
+
+		namespaces := make([]string, 0)
+		json.Unmarshal(rr.Body.Bytes(), &namespaces)
+
+		return rr.Code, namespaces
+	}
+
+	t.Run("Without Namespace Filter", func(t *testing.T) {
+		status, namespaces := call(auth.Namespaced, "dev-token", "")
+		if status != http.StatusOK || len(namespaces) != 1 || namespaces[0] != "team-a" {
+			t.Errorf("expected only team-a, got %d: %v", status, namespaces)
+		}
+	})
+
+	t.Run("With Namespace Filter", func(t *testing.T) {
+		status, namespaces := call(auth.Namespaced, "dev-token", "?namespaces=team-b&namespaces=team-a")
+		if status != http.StatusOK || len(namespaces) != 1 || namespaces[0] != "team-a" {
+			t.Errorf("expected only team-a, got %d: %v", status, namespaces)
+		}
+	})
+
+	t.Run("No Accessible Namespace", func(t *testing.T) {
+		if status, _ := call(auth.Namespaced, "dev-token", "?namespaces=team-b"); status != http.StatusForbidden {
+			t.Errorf("expected status 403, got %d", status)
+		}
+	})
+
+	t.Run("Cluster Scope", func(t *testing.T) {
+		if status, _ := call(auth.Cluster, "dev-token", ""); status != http.StatusForbidden {
+			t.Errorf("expected status 403, got %d", status)
+		}
+	})
+
+	t.Run("Cluster Scope Pseudo Namespace", func(t *testing.T) {
+		status, namespaces := call(auth.Namespaced, "dev-token", "?namespaces=cluster&namespaces=team-a")
+		if status != http.StatusOK || len(namespaces) != 1 || namespaces[0] != "team-a" {
+			t.Errorf("expected cluster scope to require cluster access, got %d: %v", status, namespaces)
+		}
+	})
+
+	t.Run("Admin", func(t *testing.T) {
+		status, namespaces := call(auth.Cluster, "admin-token", "")
+		if status != http.StatusOK || len(namespaces) != 0 {
+			t.Errorf("expected unrestricted request, got %d: %v", status, namespaces)
+		}
+	})
+
+	t.Run("Cached Reviews", func(t *testing.T) {
+		before := *reviews
+		call(auth.Namespaced, "dev-token", "")
+
+		if *reviews != before {
+			t.Errorf("expected cached SubjectAccessReviews, got %d new reviews", *reviews-before)
+		}
+	})
+}
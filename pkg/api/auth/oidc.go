@@ -0,0 +1,411 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// OIDC configuration of the ID token validation
+type OIDC struct {
+	// Issuer URL, used to discover the JSON Web Key Set
+	Issuer string
+	// Audience has to be part of the aud claim, usually the client ID. Required, all tokens are rejected without audience
+	Audience string
+	// UsernameClaim identifies the user, defaults to sub
+	UsernameClaim string
+	// GroupsClaim contains the groups of the user, defaults to groups
+	GroupsClaim string
+	// AdminGroups get admin access, all other valid tokens get read access
+	AdminGroups []string
+}
+
+// clock skew tolerated for exp and nbf claims
+const leeway = time.Minute
+
+// minimum interval to refresh the keys for an unknown key ID
+const refreshInterval = time.Minute
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type oidcAuthenticator struct {
+	config    OIDC
+	client    *http.Client
+	mx        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	refreshed time.Time
+	// fetches shares a running refresh of the keys between concurrent requests
+	fetches singleflight.Group
+}
+
+func (a *oidcAuthenticator) Authenticate(req *http.Request) (Identity, error) {
+	token, ok := BearerToken(req)
+	if !ok || strings.Count(token, ".") != 2 {
+		return Identity{}, ErrNoCredentials
+	}
+
+	claims, err := a.verify(req.Context(), token)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	identity := Identity{Name: stringClaim(claims[a.config.UsernameClaim]), Groups: stringsClaim(claims[a.config.GroupsClaim]), Level: Read}
+
+	for _, group := range identity.Groups {
+		if contains(a.config.AdminGroups, group) {
+			identity.Level = Admin
+		}
+	}
+
+	return identity, nil
+}
+
+func (a *oidcAuthenticator) verify(ctx context.Context, token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+
+	header := struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}{}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("invalid token header: %w", err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token signature: %w", err)
+	}
+
+	key, err := a.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifySignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	claims := make(map[string]interface{})
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("invalid token claims: %w", err)
+	}
+
+	if stringClaim(claims["iss"]) != a.config.Issuer {
+		return nil, fmt.Errorf("unexpected issuer %s", stringClaim(claims["iss"]))
+	}
+
+	if a.config.Audience == "" || !contains(stringsClaim(claims["aud"]), a.config.Audience) {
+		return nil, errors.New("token was not issued for the configured audience")
+	}
+
+	now := time.Now()
+
+	exp, ok := claims["exp"].(float64)
+	if !ok || now.After(time.Unix(int64(exp), 0).Add(leeway)) {
+		return nil, errors.New("token is expired")
+	}
+
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(leeway).Before(time.Unix(int64(nbf), 0)) {
+		return nil, errors.New("token is not valid yet")
+	}
+
+	return claims, nil
+}
+
+// key returns the public key for the key ID, unknown key IDs refresh the cached JSON Web Key Set.
+// The keys are fetched without holding the lock, concurrent requests share a single fetch
+func (a *oidcAuthenticator) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	a.mx.Lock()
+	key, ok := a.find(kid)
+	a.mx.Unlock()
+
+	if ok {
+		return key, nil
+	}
+
+	result := a.fetches.DoChan("keys", func() (interface{}, error) {
+		a.mx.Lock()
+		if time.Since(a.refreshed) < refreshInterval {
+			a.mx.Unlock()
+			return nil, nil
+		}
+		a.refreshed = time.Now()
+		a.mx.Unlock()
+
+		// the fetch is shared, so it is not bound to the request which started it
+		keys, err := a.fetchKeys(context.Background())
+		if err != nil {
+			return nil, err
+		}
+
+		a.mx.Lock()
+		a.keys = keys
+		a.mx.Unlock()
+
+		return nil, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-result:
+		if r.Err != nil {
+			return nil, fmt.Errorf("failed to fetch keys of %s: %w", a.config.Issuer, r.Err)
+		}
+	}
+
+	a.mx.Lock()
+	key, ok = a.find(kid)
+	a.mx.Unlock()
+
+	if ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unknown key ID %s", kid)
+}
+
+// find the key by ID, tokens without key ID are accepted if the issuer has exactly one key
+func (a *oidcAuthenticator) find(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(a.keys) == 1 {
+		for _, key := range a.keys {
+			return key, true
+		}
+	}
+
+	key, ok := a.keys[kid]
+
+	return key, ok
+}
+
+func (a *oidcAuthenticator) fetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	discovery := struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}{}
+
+	if err := a.getJSON(ctx, strings.TrimSuffix(a.config.Issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, err
+	}
+
+	if discovery.Issuer != a.config.Issuer {
+		return nil, fmt.Errorf("discovered issuer %s does not match", discovery.Issuer)
+	}
+
+	set := struct {
+		Keys []jwk `json:"keys"`
+	}{}
+
+	if err := a.getJSON(ctx, discovery.JWKSURI, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		key, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+
+		keys[k.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func (a *oidcAuthenticator) getJSON(ctx context.Context, url string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded with %d", url, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+}
+
+func verifySignature(alg string, key crypto.PublicKey, content, signature []byte) error {
+	if len(alg) != 5 {
+		return fmt.Errorf("unsupported algorithm %s", alg)
+	}
+
+	var hash crypto.Hash
+	switch alg[2:] {
+	case "256":
+		hash = crypto.SHA256
+	case "384":
+		hash = crypto.SHA384
+	case "512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported algorithm %s", alg)
+	}
+
+	h := hash.New()
+	h.Write(content)
+	digest := h.Sum(nil)
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return fmt.Errorf("algorithm %s does not match the RSA key", alg)
+		}
+
+		if err := rsa.VerifyPKCS1v15(k, hash, digest, signature); err != nil {
+			return errors.New("invalid token signature")
+		}
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(alg, "ES") {
+			return fmt.Errorf("algorithm %s does not match the EC key", alg)
+		}
+
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("invalid token signature")
+		}
+
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(k, digest, r, s) {
+			return errors.New("invalid token signature")
+		}
+	default:
+		return errors.New("unsupported key")
+	}
+
+	return nil
+}
+
+func decodeSegment(segment string, result interface{}) error {
+	content, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(content, result)
+}
+
+func stringClaim(claim interface{}) string {
+	value, _ := claim.(string)
+
+	return value
+}
+
+// stringsClaim supports claims as single string or list of strings
+func stringsClaim(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		list := make([]string, 0, len(value))
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				list = append(list, s)
+			}
+		}
+
+		return list
+	}
+
+	return nil
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewOIDCAuthenticator validates ID tokens of the configured issuer,
+// the keys are discovered on the first request and refreshed for unknown key IDs
+func NewOIDCAuthenticator(config OIDC, client *http.Client) Authenticator {
+	if config.UsernameClaim == "" {
+		config.UsernameClaim = "sub"
+	}
+	if config.GroupsClaim == "" {
+		config.GroupsClaim = "groups"
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &oidcAuthenticator{
+		config: config,
+		client: client,
+		keys:   make(map[string]crypto.PublicKey),
+	}
+}
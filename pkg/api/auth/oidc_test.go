@@ -0,0 +1,205 @@
+package auth_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/api/auth"
+)
+
+func encode(value interface{}) string {
+	content, _ := json.Marshal(value)
+
+	return base64.RawURLEncoding.EncodeToString(content)
+}
+
+func signRSA(key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	content := encode(map[string]string{"alg": "RS256", "kid": kid}) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(content))
+
+	signature, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+
+	return content + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func signEC(key *ecdsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	content := encode(map[string]string{"alg": "ES256", "kid": kid}) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(content))
+
+	r, s, _ := ecdsa.Sign(rand.Reader, key, digest[:])
+
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	return content + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func Test_OIDCAuthenticator(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	var issuer string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"issuer": issuer, "jwks_uri": issuer + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa", "n": base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()), "e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes())},
+			{"kty": "EC", "kid": "ec", "crv": "P-256", "x": base64.RawURLEncoding.EncodeToString(ecKey.X.FillBytes(make([]byte, 32))), "y": base64.RawURLEncoding.EncodeToString(ecKey.Y.FillBytes(make([]byte, 32)))},
+		}})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	issuer = server.URL
+
+	authenticator := auth.NewOIDCAuthenticator(auth.OIDC{
+		Issuer:      issuer,
+		Audience:    "policy-reporter",
+		AdminGroups: []string{"admins"},
+	}, server.Client())
+
+	claims := func(modify func(map[string]interface{})) map[string]interface{} {
+		c := map[string]interface{}{
+			"iss":    issuer,
+			"sub":    "jane",
+			"aud":    []string{"policy-reporter", "other"},
+			"exp":    time.Now().Add(time.Hour).Unix(),
+			"groups": []string{"developers"},
+		}
+		modify(c)
+
+		return c
+	}
+
+	authenticate := func(token string) (auth.Identity, error) {
+		req, _ := http.NewRequest("GET", "/v1/targets", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		return authenticator.Authenticate(req)
+	}
+
+	t.Run("RSA Token", func(t *testing.T) {
+		identity, err := authenticate(signRSA(rsaKey, "rsa", claims(func(map[string]interface{}) {})))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if identity.Name != "jane" || identity.Level != auth.Read {
+			t.Errorf("expected read access for jane, got %v", identity)
+		}
+	})
+
+	t.Run("EC Token with Admin Group", func(t *testing.T) {
+		identity, err := authenticate(signEC(ecKey, "ec", claims(func(c map[string]interface{}) { c["groups"] = "admins" })))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if identity.Level != auth.Admin {
+			t.Errorf("expected admin access, got %v", identity.Level)
+		}
+	})
+
+	invalid := map[string]string{
+		"Expired":        signRSA(rsaKey, "rsa", claims(func(c map[string]interface{}) { c["exp"] = time.Now().Add(-time.Hour).Unix() })),
+		"Not Valid Yet":  signRSA(rsaKey, "rsa", claims(func(c map[string]interface{}) { c["nbf"] = time.Now().Add(time.Hour).Unix() })),
+		"Wrong Issuer":   signRSA(rsaKey, "rsa", claims(func(c map[string]interface{}) { c["iss"] = "https://example.com" })),
+		"Wrong Audience": signRSA(rsaKey, "rsa", claims(func(c map[string]interface{}) { c["aud"] = "other" })),
+		"Unknown Key":    signRSA(rsaKey, "unknown", claims(func(map[string]interface{}) {})),
+		"Key Mismatch":   signRSA(rsaKey, "ec", claims(func(map[string]interface{}) {})),
+	}
+
+	// replace the claims of a valid token
+	parts := strings.Split(signRSA(rsaKey, "rsa", claims(func(map[string]interface{}) {})), ".")
+	invalid["Modified Claims"] = parts[0] + "." + encode(claims(func(c map[string]interface{}) { c["groups"] = "admins" })) + "." + parts[2]
+
+	for name, token := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if _, err := authenticate(token); err == nil {
+				t.Error("expected invalid token")
+			}
+		})
+	}
+
+	t.Run("No ID Token", func(t *testing.T) {
+		if _, err := authenticate("static-token"); err != auth.ErrNoCredentials {
+			t.Errorf("expected ErrNoCredentials, got %v", err)
+		}
+	})
+
+	t.Run("Without Audience", func(t *testing.T) {
+		unrestricted := auth.NewOIDCAuthenticator(auth.OIDC{Issuer: issuer}, server.Client())
+
+		req, _ := http.NewRequest("GET", "/v1/targets", nil)
+		req.Header.Set("Authorization", "Bearer "+signRSA(rsaKey, "rsa", claims(func(map[string]interface{}) {})))
+
+		if _, err := unrestricted.Authenticate(req); err == nil {
+			t.Error("expected tokens to be rejected without configured audience")
+		}
+	})
+}
+
+func Test_OIDCConcurrentKeyFetch(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	var issuer string
+	var fetches int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"issuer": issuer, "jwks_uri": issuer + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		time.Sleep(100 * time.Millisecond)
+
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa", "n": base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()), "e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes())},
+		}})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	issuer = server.URL
+
+	authenticator := auth.NewOIDCAuthenticator(auth.OIDC{Issuer: issuer, Audience: "policy-reporter"}, server.Client())
+	token := signRSA(rsaKey, "rsa", map[string]interface{}{"iss": issuer, "sub": "jane", "aud": "policy-reporter", "exp": time.Now().Add(time.Hour).Unix()})
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			req, _ := http.NewRequest("GET", "/v1/targets", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			if _, err := authenticator.Authenticate(req); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if fetches != 1 {
+		t.Errorf("expected concurrent requests to share one key fetch, got %d", fetches)
+	}
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/kyverno/policy-reporter/pkg/secret"
+)
+
+// Token is a static bearer token
+type Token struct {
+	Name  string
+	Token secret.String
+	Level Level
+}
+
+// User of the basic auth
+type User struct {
+	Username string
+	Password secret.String
+	Level    Level
+}
+
+type tokenAuthenticator struct {
+	tokens []Token
+}
+
+func (a *tokenAuthenticator) Authenticate(req *http.Request) (Identity, error) {
+	token, ok := BearerToken(req)
+	if !ok {
+		return Identity{}, ErrNoCredentials
+	}
+
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(t.Token.Value()), []byte(token)) == 1 {
+			return Identity{Name: t.Name, Level: t.Level}, nil
+		}
+	}
+
+	// unknown tokens could be OIDC ID tokens
+	return Identity{}, ErrNoCredentials
+}
+
+type basicAuthenticator struct {
+	users []User
+}
+
+func (a *basicAuthenticator) Authenticate(req *http.Request) (Identity, error) {
+	username, password, ok := req.BasicAuth()
+	if !ok {
+		return Identity{}, ErrNoCredentials
+	}
+
+	for _, u := range a.users {
+		if u.Username == username && subtle.ConstantTimeCompare([]byte(u.Password.Value()), []byte(password)) == 1 {
+			return Identity{Name: u.Username, Level: u.Level}, nil
+		}
+	}
+
+	return Identity{}, errors.New("invalid username or password")
+}
+
+// BearerToken of the Authorization header
+func BearerToken(req *http.Request) (string, bool) {
+	header := req.Header.Get("Authorization")
+	if len(header) < 7 || !strings.EqualFold(header[:7], "bearer ") {
+		return "", false
+	}
+
+	token := strings.TrimSpace(header[7:])
+
+	return token, token != ""
+}
+
+// NewTokenAuthenticator validates static bearer tokens
+func NewTokenAuthenticator(tokens []Token) Authenticator {
+	return &tokenAuthenticator{tokens: tokens}
+}
+
+// NewBasicAuthenticator validates basic auth credentials
+func NewBasicAuthenticator(users []User) Authenticator {
+	return &basicAuthenticator{users: users}
+}
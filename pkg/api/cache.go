@@ -0,0 +1,129 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// maxCacheEntries limits the cached responses, further responses are not cached until the next invalidation
+const maxCacheEntries = 1000
+
+type cachedResponse struct {
+	contentType string
+	body        []byte
+	expires     time.Time
+}
+
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *recordingResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingResponseWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+
+	return w.ResponseWriter.Write(b)
+}
+
+// ResponseCache caches the responses of expensive aggregate APIs until the stored PolicyReports change or the TTL expires
+type ResponseCache struct {
+	ttl      time.Duration
+	requests *prometheus.CounterVec
+	mx       *sync.RWMutex
+	entries  map[string]cachedResponse
+	// generation is increased on each invalidation, so responses computed before are not cached
+	generation uint64
+}
+
+// Handler serves successful GET responses of the endpoint from the cache, the cache key is the path with the query,
+// so handlers restricted to the namespaces of the caller have to be wrapped after the restriction is applied
+func (c *ResponseCache) Handler(endpoint string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			next(w, req)
+			return
+		}
+
+		key := req.URL.Path + "?" + req.URL.RawQuery
+
+		c.mx.RLock()
+		entry, ok := c.entries[key]
+		generation := c.generation
+		c.mx.RUnlock()
+
+		if ok && time.Now().Before(entry.expires) {
+			c.count(endpoint, "hit")
+
+			w.Header().Set("Content-Type", entry.contentType)
+			w.Write(entry.body)
+			return
+		}
+
+		c.count(endpoint, "miss")
+
+		recorder := &recordingResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next(recorder, req)
+
+		if recorder.status != http.StatusOK {
+			return
+		}
+
+		c.mx.Lock()
+		defer c.mx.Unlock()
+
+		if generation != c.generation || len(c.entries) >= maxCacheEntries {
+			return
+		}
+
+		c.entries[key] = cachedResponse{
+			contentType: w.Header().Get("Content-Type"),
+			body:        recorder.body.Bytes(),
+			expires:     time.Now().Add(c.ttl),
+		}
+	}
+}
+
+// Invalidate removes all cached responses, called when the stored PolicyReports change
+func (c *ResponseCache) Invalidate() {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.generation++
+	if len(c.entries) > 0 {
+		c.entries = make(map[string]cachedResponse)
+	}
+}
+
+// invalidating clears the cache after each request of the handler, used for APIs which change the stored PolicyReports
+func invalidating(cache *ResponseCache, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		next(w, req)
+		cache.Invalidate()
+	}
+}
+
+func (c *ResponseCache) count(endpoint, result string) {
+	if c.requests != nil {
+		c.requests.WithLabelValues(endpoint, result).Inc()
+	}
+}
+
+// NewResponseCache creates a ResponseCache with the TTL of the cached responses, the hit and miss counter is optional
+func NewResponseCache(ttl time.Duration, requests *prometheus.CounterVec) *ResponseCache {
+	return &ResponseCache{
+		ttl:      ttl,
+		requests: requests,
+		mx:       new(sync.RWMutex),
+		entries:  make(map[string]cachedResponse),
+	}
+}
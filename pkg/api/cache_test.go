@@ -0,0 +1,97 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/kyverno/policy-reporter/pkg/api"
+)
+
+func Test_ResponseCache(t *testing.T) {
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "cache_requests"}, []string{"endpoint", "result"})
+
+	calls := 0
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		if req.URL.Query().Get("fail") != "" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"calls":%d}`, calls)
+	}
+
+	cache := api.NewResponseCache(time.Minute, requests)
+	cached := cache.Handler("/status-counts", handler)
+
+	get := func(url string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		cached(rr, httptest.NewRequest(http.MethodGet, url, nil))
+		return rr
+	}
+
+	t.Run("Cache responses", func(t *testing.T) {
+		get("/status-counts?namespaces=test")
+		rr := get("/status-counts?namespaces=test")
+
+		if calls != 1 {
+			t.Errorf("Expected cached response, handler called %d times", calls)
+		}
+		if rr.Body.String() != `{"calls":1}` {
+			t.Errorf("Unexpected cached body: %s", rr.Body.String())
+		}
+		if rr.Header().Get("Content-Type") != "application/json" {
+			t.Errorf("Expected cached content type, got %s", rr.Header().Get("Content-Type"))
+		}
+		if hits := testutil.ToFloat64(requests.WithLabelValues("/status-counts", "hit")); hits != 1 {
+			t.Errorf("Expected 1 cache hit, got %v", hits)
+		}
+	})
+
+	t.Run("Separate queries", func(t *testing.T) {
+		get("/status-counts?namespaces=default")
+
+		if calls != 2 {
+			t.Errorf("Expected separate cache entry per query, handler called %d times", calls)
+		}
+	})
+
+	t.Run("Invalidate", func(t *testing.T) {
+		cache.Invalidate()
+		rr := get("/status-counts?namespaces=test")
+
+		if rr.Body.String() != `{"calls":3}` {
+			t.Errorf("Expected recomputed response after invalidation, got %s", rr.Body.String())
+		}
+	})
+
+	t.Run("Skip errors", func(t *testing.T) {
+		get("/status-counts?fail=true")
+		get("/status-counts?fail=true")
+
+		if calls != 5 {
+			t.Errorf("Expected error responses not to be cached, handler called %d times", calls)
+		}
+		if misses := testutil.ToFloat64(requests.WithLabelValues("/status-counts", "miss")); misses != 5 {
+			t.Errorf("Expected 5 cache misses, got %v", misses)
+		}
+	})
+
+	t.Run("Expired responses", func(t *testing.T) {
+		expired := api.NewResponseCache(-time.Second, nil).Handler("/status-counts", handler)
+
+		expired(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/status-counts", nil))
+		expired(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/status-counts", nil))
+
+		if calls != 7 {
+			t.Errorf("Expected expired responses to be recomputed, handler called %d times", calls)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/logging"
+)
+
+var logger = logging.Component(logging.API)
+
+// Certificate of the TLS listeners, reloaded when the certificate or key file changes.
+// Files mounted from a Secret are replaced by symlink swaps, so the content is compared instead of file events
+type Certificate struct {
+	certFile string
+	keyFile  string
+	mx       *sync.RWMutex
+	cert     *tls.Certificate
+	checksum [32]byte
+}
+
+// GetCertificate returns the current certificate, used as tls.Config.GetCertificate
+func (c *Certificate) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	return c.cert, nil
+}
+
+// Reload loads the certificate if the files changed, the current certificate is kept if the new one is invalid
+func (c *Certificate) Reload() (bool, error) {
+	certPEM, err := os.ReadFile(c.certFile)
+	if err != nil {
+		return false, err
+	}
+	keyPEM, err := os.ReadFile(c.keyFile)
+	if err != nil {
+		return false, err
+	}
+
+	sum := sha256.Sum256(append(append([]byte{}, certPEM...), keyPEM...))
+
+	c.mx.RLock()
+	unchanged := c.cert != nil && sum == c.checksum
+	c.mx.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return false, err
+	}
+
+	c.mx.Lock()
+	c.cert = &cert
+	c.checksum = sum
+	c.mx.Unlock()
+
+	return true, nil
+}
+
+// Run reloads the certificate in the given interval until the context is canceled
+func (c *Certificate) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			reloaded, err := c.Reload()
+			if err != nil {
+				logger.Errorf("failed to reload the TLS certificate, keep the current certificate: %s", err)
+				continue
+			}
+			if reloaded {
+				logger.Infof("TLS certificate %s reloaded", c.certFile)
+			}
+		}
+	}
+}
+
+// TLSConfig serves the current certificate with the given minimum TLS version
+func (c *Certificate) TLSConfig(minVersion uint16) *tls.Config {
+	return &tls.Config{MinVersion: minVersion, GetCertificate: c.GetCertificate}
+}
+
+// TLSVersion parses the minimum TLS version 1.2 or 1.3, defaults to 1.2
+func TLSVersion(version string) (uint16, error) {
+	switch version {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS version '%s', expected 1.2 or 1.3", version)
+	}
+}
+
+// LoadCertificate loads the PEM encoded certificate and key files
+func LoadCertificate(certFile, keyFile string) (*Certificate, error) {
+	c := &Certificate{certFile: certFile, keyFile: keyFile, mx: new(sync.RWMutex)}
+	if _, err := c.Reload(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
@@ -0,0 +1,151 @@
+package api_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/api"
+	"github.com/kyverno/policy-reporter/pkg/target"
+)
+
+func writeCertificate(t *testing.T, dir, name string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+
+	return certFile, keyFile
+}
+
+func commonName(t *testing.T, c *api.Certificate) string {
+	cert, _ := c.GetCertificate(nil)
+
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return parsed.Subject.CommonName
+}
+
+func Test_Certificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCertificate(t, dir, "first")
+
+	c, err := api.LoadCertificate(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reloaded, err := c.Reload(); err != nil || reloaded {
+		t.Errorf("expected no reload of unchanged files, got %v, %v", reloaded, err)
+	}
+
+	writeCertificate(t, dir, "rotated")
+	if reloaded, err := c.Reload(); err != nil || !reloaded {
+		t.Errorf("expected a reload of the rotated certificate, got %v, %v", reloaded, err)
+	}
+	if name := commonName(t, c); name != "rotated" {
+		t.Errorf("expected the rotated certificate, got %s", name)
+	}
+
+	os.WriteFile(keyFile, []byte("invalid"), 0o600)
+	if _, err := c.Reload(); err == nil {
+		t.Error("expected an error for an invalid key")
+	}
+	if name := commonName(t, c); name != "rotated" {
+		t.Errorf("expected the current certificate to be kept, got %s", name)
+	}
+
+	if _, err := api.LoadCertificate(filepath.Join(dir, "missing.crt"), keyFile); err == nil {
+		t.Error("expected an error for a missing certificate")
+	}
+}
+
+func Test_TLSVersion(t *testing.T) {
+	if version, _ := api.TLSVersion(""); version != tls.VersionTLS12 {
+		t.Errorf("expected TLS 1.2 as default, got %d", version)
+	}
+	if version, _ := api.TLSVersion("1.3"); version != tls.VersionTLS13 {
+		t.Errorf("expected TLS 1.3, got %d", version)
+	}
+	if _, err := api.TLSVersion("1.0"); err == nil {
+		t.Error("expected an error for TLS 1.0")
+	}
+}
+
+func Test_TLSServer(t *testing.T) {
+	certFile, keyFile := writeCertificate(t, t.TempDir(), "policy-reporter")
+
+	c, err := api.LoadCertificate(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port := 30000 + int(time.Now().UnixNano()%10000)
+
+	server := api.NewServer(target.NewRegistry(make([]target.Client, 0)), api.Address("", port), "", func() bool { return true }, nil, nil)
+	server.RegisterTLS(c.TLSConfig(tls.VersionTLS12))
+
+	serviceDone := make(chan struct{})
+	go func() {
+		defer close(serviceDone)
+		server.Start()
+	}()
+	defer func() {
+		server.Shutdown(context.Background())
+		<-serviceDone
+	}()
+
+	client := http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+
+	var res *http.Response
+	for i := 0; i < 20; i++ {
+		if res, err = client.Get(fmt.Sprintf("https://localhost:%d/ready", port)); err == nil {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusOK || res.TLS == nil {
+		t.Errorf("expected a TLS response with status 200, got %d", res.StatusCode)
+	}
+}
@@ -0,0 +1,152 @@
+// Package client is a Go client for the Policy Reporter REST API,
+// the endpoint methods are generated from the OpenAPI routes with `go generate`
+package client
+
+//go:generate go run ./gen -o zz_generated.go
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+)
+
+// Error returned by the API
+type Error struct {
+	StatusCode int
+	Message    string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("policy reporter API responded with %d: %s", e.StatusCode, e.Message)
+}
+
+// Page of a cursor paginated endpoint
+type Page struct {
+	v2.Page
+	// TotalCount of items matching the filter
+	TotalCount int
+}
+
+// Option configures the Client
+type Option func(*Client)
+
+// WithHTTPClient replaces the default http.Client
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.http = client
+	}
+}
+
+// WithToken authenticates each request with the bearer token
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// Client for the Policy Reporter REST API
+type Client struct {
+	baseURL string
+	http    *http.Client
+	token   string
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, result interface{}) (http.Header, error) {
+	return c.do(ctx, http.MethodGet, path, query, result)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, result interface{}) (http.Header, error) {
+	resp, err := c.send(ctx, method, path, query, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return resp.Header, json.NewDecoder(resp.Body).Decode(result)
+}
+
+// send the request, error responses are closed and returned as *Error
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.URL.RawQuery = query.Encode()
+	req.Header.Set("Accept", accept)
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+
+		apiErr := &Error{StatusCode: resp.StatusCode}
+
+		body, _ := io.ReadAll(resp.Body)
+		if err := json.Unmarshal(body, apiErr); err != nil || apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(string(body))
+		}
+
+		return nil, apiErr
+	}
+
+	return resp, nil
+}
+
+func (c *Client) getPage(ctx context.Context, path string, query url.Values) (*Page, error) {
+	page := &Page{}
+
+	header, err := c.get(ctx, path, query, &page.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	page.TotalCount, _ = strconv.Atoi(header.Get(v2.TotalCountHeader))
+
+	return page, nil
+}
+
+func addString(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
+
+func addInt(query url.Values, key string, value int) {
+	if value != 0 {
+		query.Set(key, strconv.Itoa(value))
+	}
+}
+
+func addStrings(query url.Values, key string, values []string) {
+	for _, v := range values {
+		query.Add(key, v)
+	}
+}
+
+// New creates a Client for the API served at baseURL, e.g. http://policy-reporter:8080
+func New(baseURL string, options ...Option) *Client {
+	c := &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    http.DefaultClient,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
@@ -0,0 +1,133 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/api/client"
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/sqlite3"
+)
+
+var preport = &v1alpha2.PolicyReport{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:              "polr-test",
+		Namespace:         "test",
+		CreationTimestamp: metav1.Now(),
+	},
+	Results: []v1alpha2.PolicyReportResult{fixtures.FailResult, fixtures.PassPodResult},
+	Summary: v1alpha2.PolicyReportSummary{Fail: 1, Pass: 1},
+}
+
+func Test_Client(t *testing.T) {
+	db, err := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store, err := sqlite3.NewPolicyReportStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Add(preport)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/namespaced-resources/results", v1.NamespacedResourcesResultHandler(store))
+	mux.HandleFunc("/v2/namespaced-resources/results", v2.NamespacedResourcesResultHandler(store))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := client.New(server.URL+"/", client.WithHTTPClient(server.Client()))
+	ctx := context.Background()
+
+	t.Run("ListNamespacedResults", func(t *testing.T) {
+		list, err := c.ListNamespacedResults(ctx, &client.ListNamespacedResultsParams{Status: []string{"fail"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if list.Count != 1 || len(list.Items) != 1 {
+			t.Fatalf("expected 1 failed result, got %d", list.Count)
+		}
+		if list.Items[0].Status != v1alpha2.StatusFail {
+			t.Errorf("expected status fail, got %s", list.Items[0].Status)
+		}
+	})
+
+	t.Run("ListNamespacedResultPage", func(t *testing.T) {
+		page, err := c.ListNamespacedResultPage(ctx, &client.ListNamespacedResultPageParams{Limit: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if page.TotalCount != 2 {
+			t.Errorf("expected total count of 2, got %d", page.TotalCount)
+		}
+		if len(page.Items) != 1 || page.NextCursor == "" {
+			t.Errorf("expected 1 item and a cursor for the next page, got %d", len(page.Items))
+		}
+	})
+
+	t.Run("Nil Params", func(t *testing.T) {
+		list, err := c.ListNamespacedResults(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if list.Count != 2 {
+			t.Errorf("expected 2 results, got %d", list.Count)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		_, err := c.ListNamespacedResults(ctx, &client.ListNamespacedResultsParams{Filter: "severity>unknown"})
+
+		apiErr := &client.Error{}
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("expected client.Error, got %v", err)
+		}
+		if apiErr.StatusCode != http.StatusBadRequest || apiErr.Message == "" {
+			t.Errorf("expected bad request with message, got %d: %s", apiErr.StatusCode, apiErr.Message)
+		}
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		_, err := c.ListTargets(ctx)
+
+		apiErr := &client.Error{}
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			t.Errorf("expected not found error, got %v", err)
+		}
+	})
+}
+
+func Test_WithToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	if _, err := client.New(server.URL, client.WithHTTPClient(server.Client())).ListTargets(context.Background()); err == nil {
+		t.Error("expected unauthorized error without token")
+	}
+
+	if _, err := client.New(server.URL, client.WithHTTPClient(server.Client()), client.WithToken("secret")).ListTargets(context.Background()); err != nil {
+		t.Errorf("unexpected error with token: %s", err)
+	}
+}
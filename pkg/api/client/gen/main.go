@@ -0,0 +1,205 @@
+// gen generates the endpoint methods of the REST API client from the OpenAPI routes
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/kyverno/policy-reporter/pkg/api/openapi"
+)
+
+var packages = map[string]string{
+	"github.com/kyverno/policy-reporter/pkg/acknowledgment": "acknowledgment",
+	"github.com/kyverno/policy-reporter/pkg/api/v1":         "v1",
+	"github.com/kyverno/policy-reporter/pkg/api/v2":         "v2",
+	"github.com/kyverno/policy-reporter/pkg/audit":          "audit",
+	"github.com/kyverno/policy-reporter/pkg/coverage":       "coverage",
+	"github.com/kyverno/policy-reporter/pkg/federation":     "federation",
+	"github.com/kyverno/policy-reporter/pkg/health":         "health",
+	"github.com/kyverno/policy-reporter/pkg/score":          "score",
+	"github.com/kyverno/policy-reporter/pkg/snooze":         "snooze",
+	"github.com/kyverno/policy-reporter/pkg/subscription":   "subscription",
+	"github.com/kyverno/policy-reporter/pkg/triage":         "triage",
+}
+
+func main() {
+	output := flag.String("o", "zz_generated.go", "output file")
+	flag.Parse()
+
+	content, err := generate(openapi.Routes)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(*output, content, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func generate(routes []openapi.Route) ([]byte, error) {
+	body := &bytes.Buffer{}
+	imports := map[string]bool{"context": true, "net/url": true}
+
+	for _, route := range routes {
+		if route.Response == nil {
+			continue
+		}
+
+		name := exported(route.OperationID)
+		params := name + "Params"
+
+		method := route.Method
+		if method == "" {
+			method = "GET"
+		}
+
+		// path parameters are positional arguments, routes without query parameters have no params argument
+		signature := "ctx context.Context"
+		values := "nil"
+		path := fmt.Sprintf("%q", route.Path)
+
+		query := make([]openapi.Parameter, 0, len(route.Parameters))
+		for _, p := range route.Parameters {
+			if !p.InPath {
+				query = append(query, p)
+				continue
+			}
+
+			signature += fmt.Sprintf(", %s string", p.Name)
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", fmt.Sprintf("\"+url.PathEscape(%s)+\"", p.Name))
+		}
+		path = strings.TrimSuffix(path, "+\"\"")
+
+		if len(query) > 0 {
+			route.Parameters = query
+			signature += fmt.Sprintf(", params *%s", params)
+			values = "params.values()"
+			writeParams(body, route, params)
+		}
+
+		fmt.Fprintf(body, "// %s calls %s %s to %s\n", name, method, route.Path, lowerFirst(route.Summary))
+
+		if route.Paginated {
+			fmt.Fprintf(body, "func (c *Client) %s(%s) (*Page, error) {\n", name, signature)
+			fmt.Fprintf(body, "\treturn c.getPage(ctx, %s, %s)\n}\n\n", path, values)
+			continue
+		}
+
+		call := fmt.Sprintf("c.get(ctx, %s, %s", path, values)
+		if method != "GET" {
+			call = fmt.Sprintf("c.do(ctx, %q, %s, %s", method, path, values)
+		}
+
+		t := reflect.TypeOf(route.Response)
+		result := typeExpr(t, imports)
+		if t.Kind() == reflect.Struct {
+			fmt.Fprintf(body, "func (c *Client) %s(%s) (*%s, error) {\n", name, signature, result)
+			fmt.Fprintf(body, "\tresult := &%s{}\n", result)
+			fmt.Fprintf(body, "\tif _, err := %s, result); err != nil {\n\t\treturn nil, err\n\t}\n\n\treturn result, nil\n}\n\n", call)
+			continue
+		}
+
+		fmt.Fprintf(body, "func (c *Client) %s(%s) (%s, error) {\n", name, signature, result)
+		fmt.Fprintf(body, "\tvar result %s\n", result)
+		fmt.Fprintf(body, "\t_, err := %s, &result)\n\n\treturn result, err\n}\n\n", call)
+	}
+
+	out := &bytes.Buffer{}
+	fmt.Fprint(out, "// Code generated by pkg/api/client/gen. DO NOT EDIT.\n\npackage client\n\nimport (\n")
+
+	list := make([]string, 0, len(imports))
+	for i := range imports {
+		list = append(list, i)
+	}
+	sort.Strings(list)
+
+	// standard library first, followed by the repository packages
+	for _, i := range list {
+		if _, ok := packages[i]; !ok {
+			fmt.Fprintf(out, "\t%q\n", i)
+		}
+	}
+	fmt.Fprint(out, "\n")
+	for _, i := range list {
+		if alias, ok := packages[i]; ok {
+			fmt.Fprintf(out, "\t%s %q\n", alias, i)
+		}
+	}
+	fmt.Fprint(out, ")\n\n")
+	out.Write(body.Bytes())
+
+	return format.Source(out.Bytes())
+}
+
+func writeParams(body *bytes.Buffer, route openapi.Route, params string) {
+	fmt.Fprintf(body, "// %s are the query parameters of %s\n", params, route.Path)
+	fmt.Fprintf(body, "type %s struct {\n", params)
+	for _, p := range route.Parameters {
+		fmt.Fprintf(body, "\t%s %s\n", exported(p.Name), goType(p))
+	}
+	fmt.Fprint(body, "}\n\n")
+
+	fmt.Fprintf(body, "func (p *%s) values() url.Values {\n\tquery := url.Values{}\n", params)
+	fmt.Fprint(body, "\tif p == nil {\n\t\treturn query\n\t}\n\n")
+
+	for _, p := range route.Parameters {
+		switch {
+		case p.Array:
+			fmt.Fprintf(body, "\taddStrings(query, %q, p.%s)\n", p.Name, exported(p.Name))
+		case p.Type == "integer":
+			fmt.Fprintf(body, "\taddInt(query, %q, p.%s)\n", p.Name, exported(p.Name))
+		default:
+			fmt.Fprintf(body, "\taddString(query, %q, p.%s)\n", p.Name, exported(p.Name))
+		}
+	}
+	fmt.Fprint(body, "\n\treturn query\n}\n\n")
+}
+
+func goType(p openapi.Parameter) string {
+	t := "string"
+	if p.Type == "integer" {
+		t = "int"
+	}
+	if p.Array {
+		t = "[]" + t
+	}
+
+	return t
+}
+
+func typeExpr(t reflect.Type, imports map[string]bool) string {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + typeExpr(t.Elem(), imports)
+	case reflect.Slice:
+		return "[]" + typeExpr(t.Elem(), imports)
+	case reflect.Map:
+		return "map[" + typeExpr(t.Key(), imports) + "]" + typeExpr(t.Elem(), imports)
+	}
+
+	if alias, ok := packages[t.PkgPath()]; ok {
+		imports[t.PkgPath()] = true
+		return alias + "." + t.Name()
+	}
+
+	return t.String()
+}
+
+func exported(name string) string {
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+
+	return string(r)
+}
+
+func lowerFirst(s string) string {
+	return strings.ToLower(s[:1]) + s[1:]
+}
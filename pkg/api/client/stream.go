@@ -0,0 +1,90 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/kyverno/policy-reporter/pkg/stream"
+)
+
+// StreamResultsParams are the query parameters of /v2/results/stream
+type StreamResultsParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Filter     string
+	Types      []string
+}
+
+func (p *StreamResultsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addString(query, "filter", p.Filter)
+	addStrings(query, "types", p.Types)
+
+	return query
+}
+
+// StreamResults consumes the Server-Sent Events of GET /v2/results/stream and calls handle for each event
+// until the context is canceled, the stream is closed by the server or handle returns an error
+func (c *Client) StreamResults(ctx context.Context, params *StreamResultsParams, handle func(stream.Event) error) error {
+	resp, err := c.send(ctx, http.MethodGet, "/v2/results/stream", params.values(), "text/event-stream")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	data := &bytes.Buffer{}
+	for scanner.Scan() {
+		line := scanner.Bytes()
+
+		switch {
+		case len(line) == 0 && data.Len() > 0:
+			event := stream.Event{}
+			if err := json.Unmarshal(data.Bytes(), &event); err != nil {
+				return err
+			}
+			data.Reset()
+
+			if err := handle(event); err != nil {
+				return err
+			}
+		case bytes.HasPrefix(line, []byte("data:")):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.Write(bytes.TrimPrefix(bytes.TrimPrefix(line, []byte("data:")), []byte(" ")))
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return scanner.Err()
+}
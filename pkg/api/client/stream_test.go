@@ -0,0 +1,73 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/api/client"
+	"github.com/kyverno/policy-reporter/pkg/stream"
+)
+
+func Test_StreamResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Accept") != "text/event-stream" {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+		if req.URL.Query().Get("severities") != "high" || req.URL.Query().Get("types") != "new" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": ping\n\n")
+		fmt.Fprint(w, "event: new\ndata: {\"type\":\"new\",\"reportId\":\"1\",\"result\":{\"policy\":\"require-labels\",\"severity\":\"high\"}}\n\n")
+		fmt.Fprint(w, "event: new\ndata: {\"type\":\"new\",\"reportId\":\"2\",\"result\":{\"policy\":\"disallow-latest\",\"severity\":\"high\"}}\n\n")
+	}))
+	defer server.Close()
+
+	c := client.New(server.URL, client.WithHTTPClient(server.Client()))
+	params := &client.StreamResultsParams{Severities: []string{"high"}, Types: []string{"new"}}
+
+	t.Run("events", func(t *testing.T) {
+		events := make([]stream.Event, 0, 2)
+
+		err := c.StreamResults(context.Background(), params, func(event stream.Event) error {
+			events = append(events, event)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(events) != 2 || events[0].Result.Policy != "require-labels" || events[1].ReportID != "2" {
+			t.Errorf("unexpected events: %+v", events)
+		}
+	})
+
+	t.Run("handler error", func(t *testing.T) {
+		stop := errors.New("stop")
+		count := 0
+
+		err := c.StreamResults(context.Background(), params, func(event stream.Event) error {
+			count++
+			return stop
+		})
+		if !errors.Is(err, stop) || count != 1 {
+			t.Errorf("expected the stream to stop after the first event, got %d events and error %v", count, err)
+		}
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		err := c.StreamResults(context.Background(), nil, func(event stream.Event) error { return nil })
+
+		apiErr := &client.Error{}
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected bad request error, got %v", err)
+		}
+	})
+}
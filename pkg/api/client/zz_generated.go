@@ -0,0 +1,2281 @@
+// Code generated by pkg/api/client/gen. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"net/url"
+
+	acknowledgment "github.com/kyverno/policy-reporter/pkg/acknowledgment"
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+	audit "github.com/kyverno/policy-reporter/pkg/audit"
+	coverage "github.com/kyverno/policy-reporter/pkg/coverage"
+	federation "github.com/kyverno/policy-reporter/pkg/federation"
+	health "github.com/kyverno/policy-reporter/pkg/health"
+	score "github.com/kyverno/policy-reporter/pkg/score"
+	snooze "github.com/kyverno/policy-reporter/pkg/snooze"
+	subscription "github.com/kyverno/policy-reporter/pkg/subscription"
+	triage "github.com/kyverno/policy-reporter/pkg/triage"
+)
+
+// Healthz calls GET /healthz to check the liveness, returns an error until the informers are synced
+func (c *Client) Healthz(ctx context.Context) (*health.Report, error) {
+	result := &health.Report{}
+	if _, err := c.get(ctx, "/healthz", nil, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Ready calls GET /ready to check the readiness per component, returns an error if a critical component is unavailable
+func (c *Client) Ready(ctx context.Context) (*health.Report, error) {
+	result := &health.Report{}
+	if _, err := c.get(ctx, "/ready", nil, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListTargets calls GET /v1/targets to list the configured targets
+func (c *Client) ListTargets(ctx context.Context) ([]v1.Target, error) {
+	var result []v1.Target
+	_, err := c.get(ctx, "/v1/targets", nil, &result)
+
+	return result, err
+}
+
+// ListCategoriesParams are the query parameters of /v1/categories
+type ListCategoriesParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+}
+
+func (p *ListCategoriesParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+
+	return query
+}
+
+// ListCategories calls GET /v1/categories to list all policy categories
+func (c *Client) ListCategories(ctx context.Context, params *ListCategoriesParams) ([]string, error) {
+	var result []string
+	_, err := c.get(ctx, "/v1/categories", params.values(), &result)
+
+	return result, err
+}
+
+// GetTaxonomyParams are the query parameters of /v1/taxonomy
+type GetTaxonomyParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+}
+
+func (p *GetTaxonomyParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+
+	return query
+}
+
+// GetTaxonomy calls GET /v1/taxonomy to list the observed sources, categories, policies and rules with their result counts and the display names, groups and order of the taxonomy
+func (c *Client) GetTaxonomy(ctx context.Context, params *GetTaxonomyParams) (*v1.Taxonomy, error) {
+	result := &v1.Taxonomy{}
+	if _, err := c.get(ctx, "/v1/taxonomy", params.values(), result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListNamespacesParams are the query parameters of /v1/namespaces
+type ListNamespacesParams struct {
+	Namespaces   []string
+	Kinds        []string
+	Resources    []string
+	Sources      []string
+	Categories   []string
+	Severities   []string
+	Policies     []string
+	Rules        []string
+	Status       []string
+	Labels       []string
+	Cluster      string
+	Ids          []string
+	Teams        []string
+	Search       string
+	Filter       string
+	ClusterScope string
+}
+
+func (p *ListNamespacesParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+	addString(query, "clusterScope", p.ClusterScope)
+
+	return query
+}
+
+// ListNamespaces calls GET /v1/namespaces to list all namespaces with results
+func (c *Client) ListNamespaces(ctx context.Context, params *ListNamespacesParams) ([]string, error) {
+	var result []string
+	_, err := c.get(ctx, "/v1/namespaces", params.values(), &result)
+
+	return result, err
+}
+
+// GetRuleStatusCountsParams are the query parameters of /v1/rule-status-count
+type GetRuleStatusCountsParams struct {
+	Policy string
+	Rule   string
+}
+
+func (p *GetRuleStatusCountsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addString(query, "policy", p.Policy)
+	addString(query, "rule", p.Rule)
+
+	return query
+}
+
+// GetRuleStatusCounts calls GET /v1/rule-status-count to count the results of a policy rule per status
+func (c *Client) GetRuleStatusCounts(ctx context.Context, params *GetRuleStatusCountsParams) ([]v1.StatusCount, error) {
+	var result []v1.StatusCount
+	_, err := c.get(ctx, "/v1/rule-status-count", params.values(), &result)
+
+	return result, err
+}
+
+// ListPolicyReportsParams are the query parameters of /v1/policy-reports
+type ListPolicyReportsParams struct {
+	Namespaces []string
+	Sources    []string
+	Labels     []string
+	Cluster    string
+	Page       int
+	Offset     int
+	SortBy     []string
+	Direction  string
+}
+
+func (p *ListPolicyReportsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addInt(query, "page", p.Page)
+	addInt(query, "offset", p.Offset)
+	addStrings(query, "sortBy", p.SortBy)
+	addString(query, "direction", p.Direction)
+
+	return query
+}
+
+// ListPolicyReports calls GET /v1/policy-reports to list PolicyReports
+func (c *Client) ListPolicyReports(ctx context.Context, params *ListPolicyReportsParams) (*v1.PolicyReportList, error) {
+	result := &v1.PolicyReportList{}
+	if _, err := c.get(ctx, "/v1/policy-reports", params.values(), result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListClusterPolicyReportsParams are the query parameters of /v1/cluster-policy-reports
+type ListClusterPolicyReportsParams struct {
+	Namespaces []string
+	Sources    []string
+	Labels     []string
+	Cluster    string
+	Page       int
+	Offset     int
+	SortBy     []string
+	Direction  string
+}
+
+func (p *ListClusterPolicyReportsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addInt(query, "page", p.Page)
+	addInt(query, "offset", p.Offset)
+	addStrings(query, "sortBy", p.SortBy)
+	addString(query, "direction", p.Direction)
+
+	return query
+}
+
+// ListClusterPolicyReports calls GET /v1/cluster-policy-reports to list ClusterPolicyReports
+func (c *Client) ListClusterPolicyReports(ctx context.Context, params *ListClusterPolicyReportsParams) (*v1.PolicyReportList, error) {
+	result := &v1.PolicyReportList{}
+	if _, err := c.get(ctx, "/v1/cluster-policy-reports", params.values(), result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListNamespacedPoliciesParams are the query parameters of /v1/namespaced-resources/policies
+type ListNamespacedPoliciesParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+}
+
+func (p *ListNamespacedPoliciesParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+
+	return query
+}
+
+// ListNamespacedPolicies calls GET /v1/namespaced-resources/policies to list policies of namespaced results
+func (c *Client) ListNamespacedPolicies(ctx context.Context, params *ListNamespacedPoliciesParams) ([]string, error) {
+	var result []string
+	_, err := c.get(ctx, "/v1/namespaced-resources/policies", params.values(), &result)
+
+	return result, err
+}
+
+// ListNamespacedRulesParams are the query parameters of /v1/namespaced-resources/rules
+type ListNamespacedRulesParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+}
+
+func (p *ListNamespacedRulesParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+
+	return query
+}
+
+// ListNamespacedRules calls GET /v1/namespaced-resources/rules to list rules of namespaced results
+func (c *Client) ListNamespacedRules(ctx context.Context, params *ListNamespacedRulesParams) ([]string, error) {
+	var result []string
+	_, err := c.get(ctx, "/v1/namespaced-resources/rules", params.values(), &result)
+
+	return result, err
+}
+
+// ListNamespacedKindsParams are the query parameters of /v1/namespaced-resources/kinds
+type ListNamespacedKindsParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+}
+
+func (p *ListNamespacedKindsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+
+	return query
+}
+
+// ListNamespacedKinds calls GET /v1/namespaced-resources/kinds to list resource kinds of namespaced results
+func (c *Client) ListNamespacedKinds(ctx context.Context, params *ListNamespacedKindsParams) ([]string, error) {
+	var result []string
+	_, err := c.get(ctx, "/v1/namespaced-resources/kinds", params.values(), &result)
+
+	return result, err
+}
+
+// ListNamespacedResourcesParams are the query parameters of /v1/namespaced-resources/resources
+type ListNamespacedResourcesParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+}
+
+func (p *ListNamespacedResourcesParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+
+	return query
+}
+
+// ListNamespacedResources calls GET /v1/namespaced-resources/resources to list resources of namespaced results
+func (c *Client) ListNamespacedResources(ctx context.Context, params *ListNamespacedResourcesParams) ([]v1.Resource, error) {
+	var result []v1.Resource
+	_, err := c.get(ctx, "/v1/namespaced-resources/resources", params.values(), &result)
+
+	return result, err
+}
+
+// ListNamespacedSources calls GET /v1/namespaced-resources/sources to list sources of namespaced results
+func (c *Client) ListNamespacedSources(ctx context.Context) ([]string, error) {
+	var result []string
+	_, err := c.get(ctx, "/v1/namespaced-resources/sources", nil, &result)
+
+	return result, err
+}
+
+// ListNamespacedReportLabelsParams are the query parameters of /v1/namespaced-resources/report-labels
+type ListNamespacedReportLabelsParams struct {
+	Namespaces []string
+	Sources    []string
+	Labels     []string
+	Cluster    string
+}
+
+func (p *ListNamespacedReportLabelsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+
+	return query
+}
+
+// ListNamespacedReportLabels calls GET /v1/namespaced-resources/report-labels to list labels of PolicyReports
+func (c *Client) ListNamespacedReportLabels(ctx context.Context, params *ListNamespacedReportLabelsParams) (map[string][]string, error) {
+	var result map[string][]string
+	_, err := c.get(ctx, "/v1/namespaced-resources/report-labels", params.values(), &result)
+
+	return result, err
+}
+
+// GetNamespacedStatusCountsParams are the query parameters of /v1/namespaced-resources/status-counts
+type GetNamespacedStatusCountsParams struct {
+	Namespaces   []string
+	Kinds        []string
+	Resources    []string
+	Sources      []string
+	Categories   []string
+	Severities   []string
+	Policies     []string
+	Rules        []string
+	Status       []string
+	Labels       []string
+	Cluster      string
+	Ids          []string
+	Teams        []string
+	Search       string
+	Filter       string
+	ClusterScope string
+}
+
+func (p *GetNamespacedStatusCountsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+	addString(query, "clusterScope", p.ClusterScope)
+
+	return query
+}
+
+// GetNamespacedStatusCounts calls GET /v1/namespaced-resources/status-counts to count namespaced results per status and namespace
+func (c *Client) GetNamespacedStatusCounts(ctx context.Context, params *GetNamespacedStatusCountsParams) ([]v1.NamespacedStatusCount, error) {
+	var result []v1.NamespacedStatusCount
+	_, err := c.get(ctx, "/v1/namespaced-resources/status-counts", params.values(), &result)
+
+	return result, err
+}
+
+// ListNamespacedResultsParams are the query parameters of /v1/namespaced-resources/results
+type ListNamespacedResultsParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+	Page       int
+	Offset     int
+	SortBy     []string
+	Direction  string
+}
+
+func (p *ListNamespacedResultsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+	addInt(query, "page", p.Page)
+	addInt(query, "offset", p.Offset)
+	addStrings(query, "sortBy", p.SortBy)
+	addString(query, "direction", p.Direction)
+
+	return query
+}
+
+// ListNamespacedResults calls GET /v1/namespaced-resources/results to list namespaced results
+func (c *Client) ListNamespacedResults(ctx context.Context, params *ListNamespacedResultsParams) (*v1.ResultList, error) {
+	result := &v1.ResultList{}
+	if _, err := c.get(ctx, "/v1/namespaced-resources/results", params.values(), result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListClusterPoliciesParams are the query parameters of /v1/cluster-resources/policies
+type ListClusterPoliciesParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+}
+
+func (p *ListClusterPoliciesParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+
+	return query
+}
+
+// ListClusterPolicies calls GET /v1/cluster-resources/policies to list policies of cluster scoped results
+func (c *Client) ListClusterPolicies(ctx context.Context, params *ListClusterPoliciesParams) ([]string, error) {
+	var result []string
+	_, err := c.get(ctx, "/v1/cluster-resources/policies", params.values(), &result)
+
+	return result, err
+}
+
+// ListClusterRulesParams are the query parameters of /v1/cluster-resources/rules
+type ListClusterRulesParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+}
+
+func (p *ListClusterRulesParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+
+	return query
+}
+
+// ListClusterRules calls GET /v1/cluster-resources/rules to list rules of cluster scoped results
+func (c *Client) ListClusterRules(ctx context.Context, params *ListClusterRulesParams) ([]string, error) {
+	var result []string
+	_, err := c.get(ctx, "/v1/cluster-resources/rules", params.values(), &result)
+
+	return result, err
+}
+
+// ListClusterKindsParams are the query parameters of /v1/cluster-resources/kinds
+type ListClusterKindsParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+}
+
+func (p *ListClusterKindsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+
+	return query
+}
+
+// ListClusterKinds calls GET /v1/cluster-resources/kinds to list resource kinds of cluster scoped results
+func (c *Client) ListClusterKinds(ctx context.Context, params *ListClusterKindsParams) ([]string, error) {
+	var result []string
+	_, err := c.get(ctx, "/v1/cluster-resources/kinds", params.values(), &result)
+
+	return result, err
+}
+
+// ListClusterResourcesParams are the query parameters of /v1/cluster-resources/resources
+type ListClusterResourcesParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+}
+
+func (p *ListClusterResourcesParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+
+	return query
+}
+
+// ListClusterResources calls GET /v1/cluster-resources/resources to list resources of cluster scoped results
+func (c *Client) ListClusterResources(ctx context.Context, params *ListClusterResourcesParams) ([]v1.Resource, error) {
+	var result []v1.Resource
+	_, err := c.get(ctx, "/v1/cluster-resources/resources", params.values(), &result)
+
+	return result, err
+}
+
+// ListClusterSources calls GET /v1/cluster-resources/sources to list sources of cluster scoped results
+func (c *Client) ListClusterSources(ctx context.Context) ([]string, error) {
+	var result []string
+	_, err := c.get(ctx, "/v1/cluster-resources/sources", nil, &result)
+
+	return result, err
+}
+
+// ListClusterReportLabelsParams are the query parameters of /v1/cluster-resources/report-labels
+type ListClusterReportLabelsParams struct {
+	Namespaces []string
+	Sources    []string
+	Labels     []string
+	Cluster    string
+}
+
+func (p *ListClusterReportLabelsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+
+	return query
+}
+
+// ListClusterReportLabels calls GET /v1/cluster-resources/report-labels to list labels of ClusterPolicyReports
+func (c *Client) ListClusterReportLabels(ctx context.Context, params *ListClusterReportLabelsParams) (map[string][]string, error) {
+	var result map[string][]string
+	_, err := c.get(ctx, "/v1/cluster-resources/report-labels", params.values(), &result)
+
+	return result, err
+}
+
+// GetClusterStatusCountsParams are the query parameters of /v1/cluster-resources/status-counts
+type GetClusterStatusCountsParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+}
+
+func (p *GetClusterStatusCountsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+
+	return query
+}
+
+// GetClusterStatusCounts calls GET /v1/cluster-resources/status-counts to count cluster scoped results per status
+func (c *Client) GetClusterStatusCounts(ctx context.Context, params *GetClusterStatusCountsParams) ([]v1.StatusCount, error) {
+	var result []v1.StatusCount
+	_, err := c.get(ctx, "/v1/cluster-resources/status-counts", params.values(), &result)
+
+	return result, err
+}
+
+// ListClusterResultsParams are the query parameters of /v1/cluster-resources/results
+type ListClusterResultsParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+	Page       int
+	Offset     int
+	SortBy     []string
+	Direction  string
+}
+
+func (p *ListClusterResultsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+	addInt(query, "page", p.Page)
+	addInt(query, "offset", p.Offset)
+	addStrings(query, "sortBy", p.SortBy)
+	addString(query, "direction", p.Direction)
+
+	return query
+}
+
+// ListClusterResults calls GET /v1/cluster-resources/results to list cluster scoped results
+func (c *Client) ListClusterResults(ctx context.Context, params *ListClusterResultsParams) (*v1.ResultList, error) {
+	result := &v1.ResultList{}
+	if _, err := c.get(ctx, "/v1/cluster-resources/results", params.values(), result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetComplianceScoresParams are the query parameters of /v1/compliance-scores
+type GetComplianceScoresParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+}
+
+func (p *GetComplianceScoresParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+
+	return query
+}
+
+// GetComplianceScores calls GET /v1/compliance-scores to calculate the compliance scores of the cluster, each namespace and each policy category, requires complianceScore.enabled
+func (c *Client) GetComplianceScores(ctx context.Context, params *GetComplianceScoresParams) (*score.Scores, error) {
+	result := &score.Scores{}
+	if _, err := c.get(ctx, "/v1/compliance-scores", params.values(), result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListResultExclusions calls GET /v1/result-exclusions to list active ResultExclusions with the fail, warn and error results suppressed by each, requires resultExclusions.enabled
+func (c *Client) ListResultExclusions(ctx context.Context) ([]v1.ResultExclusion, error) {
+	var result []v1.ResultExclusion
+	_, err := c.get(ctx, "/v1/result-exclusions", nil, &result)
+
+	return result, err
+}
+
+// GetCoverageParams are the query parameters of /v1/coverage
+type GetCoverageParams struct {
+	Details string
+}
+
+func (p *GetCoverageParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addString(query, "details", p.Details)
+
+	return query
+}
+
+// GetCoverage calls GET /v1/coverage to count the workloads of the scanned kinds without results per source, requires coverage.enabled
+func (c *Client) GetCoverage(ctx context.Context, params *GetCoverageParams) (*coverage.Report, error) {
+	result := &coverage.Report{}
+	if _, err := c.get(ctx, "/v1/coverage", params.values(), result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListNotificationsParams are the query parameters of /v1/audit/notifications
+type ListNotificationsParams struct {
+	Target   string
+	ResultId string
+	Status   string
+	Since    string
+	Until    string
+	Limit    int
+}
+
+func (p *ListNotificationsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addString(query, "target", p.Target)
+	addString(query, "resultId", p.ResultId)
+	addString(query, "status", p.Status)
+	addString(query, "since", p.Since)
+	addString(query, "until", p.Until)
+	addInt(query, "limit", p.Limit)
+
+	return query
+}
+
+// ListNotifications calls GET /v1/audit/notifications to list the notifications sent to the targets, newest first, requires audit.enabled
+func (c *Client) ListNotifications(ctx context.Context, params *ListNotificationsParams) ([]audit.Entry, error) {
+	var result []audit.Entry
+	_, err := c.get(ctx, "/v1/audit/notifications", params.values(), &result)
+
+	return result, err
+}
+
+// AcknowledgeResultsParams are the query parameters of /v1/acknowledgments
+type AcknowledgeResultsParams struct {
+	Target    string
+	ResultIds []string
+}
+
+func (p *AcknowledgeResultsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addString(query, "target", p.Target)
+	addStrings(query, "resultIds", p.ResultIds)
+
+	return query
+}
+
+// AcknowledgeResults calls POST /v1/acknowledgments to acknowledge the receipt of results delivered to a target by a downstream system, requires acknowledgments.enabled
+func (c *Client) AcknowledgeResults(ctx context.Context, params *AcknowledgeResultsParams) (*acknowledgment.Response, error) {
+	result := &acknowledgment.Response{}
+	if _, err := c.do(ctx, "POST", "/v1/acknowledgments", params.values(), result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetUnacknowledgedParams are the query parameters of /v1/acknowledgments/unacknowledged
+type GetUnacknowledgedParams struct {
+	Target string
+}
+
+func (p *GetUnacknowledgedParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addString(query, "target", p.Target)
+
+	return query
+}
+
+// GetUnacknowledged calls GET /v1/acknowledgments/unacknowledged to report the deliveries not acknowledged within the threshold, oldest first, requires acknowledgments.enabled
+func (c *Client) GetUnacknowledged(ctx context.Context, params *GetUnacknowledgedParams) (*acknowledgment.Report, error) {
+	result := &acknowledgment.Report{}
+	if _, err := c.get(ctx, "/v1/acknowledgments/unacknowledged", params.values(), result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetStatusHistoryParams are the query parameters of /v1/history/status-counts
+type GetStatusHistoryParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+	Since      string
+	Interval   string
+}
+
+func (p *GetStatusHistoryParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+	addString(query, "since", p.Since)
+	addString(query, "interval", p.Interval)
+
+	return query
+}
+
+// GetStatusHistory calls GET /v1/history/status-counts to count results per status and namespace for each interval
+func (c *Client) GetStatusHistory(ctx context.Context, params *GetStatusHistoryParams) ([]v1.StatusHistory, error) {
+	var result []v1.StatusHistory
+	_, err := c.get(ctx, "/v1/history/status-counts", params.values(), &result)
+
+	return result, err
+}
+
+// ListResultTransitionsParams are the query parameters of /v1/history/result-transitions
+type ListResultTransitionsParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+	Since      string
+	Interval   string
+	Page       int
+	Offset     int
+	SortBy     []string
+	Direction  string
+}
+
+func (p *ListResultTransitionsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+	addString(query, "since", p.Since)
+	addString(query, "interval", p.Interval)
+	addInt(query, "page", p.Page)
+	addInt(query, "offset", p.Offset)
+	addStrings(query, "sortBy", p.SortBy)
+	addString(query, "direction", p.Direction)
+
+	return query
+}
+
+// ListResultTransitions calls GET /v1/history/result-transitions to list status transitions of results
+func (c *Client) ListResultTransitions(ctx context.Context, params *ListResultTransitionsParams) ([]v1.ResultTransition, error) {
+	var result []v1.ResultTransition
+	_, err := c.get(ctx, "/v1/history/result-transitions", params.values(), &result)
+
+	return result, err
+}
+
+// ListPolicyReportPageParams are the query parameters of /v2/policy-reports
+type ListPolicyReportPageParams struct {
+	Namespaces []string
+	Sources    []string
+	Labels     []string
+	Cluster    string
+	Limit      int
+	SortBy     string
+	Direction  string
+	Cursor     string
+	Fields     []string
+}
+
+func (p *ListPolicyReportPageParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addInt(query, "limit", p.Limit)
+	addString(query, "sortBy", p.SortBy)
+	addString(query, "direction", p.Direction)
+	addString(query, "cursor", p.Cursor)
+	addStrings(query, "fields", p.Fields)
+
+	return query
+}
+
+// ListPolicyReportPage calls GET /v2/policy-reports to list PolicyReports with cursor pagination
+func (c *Client) ListPolicyReportPage(ctx context.Context, params *ListPolicyReportPageParams) (*Page, error) {
+	return c.getPage(ctx, "/v2/policy-reports", params.values())
+}
+
+// ListClusterPolicyReportPageParams are the query parameters of /v2/cluster-policy-reports
+type ListClusterPolicyReportPageParams struct {
+	Namespaces []string
+	Sources    []string
+	Labels     []string
+	Cluster    string
+	Limit      int
+	SortBy     string
+	Direction  string
+	Cursor     string
+	Fields     []string
+}
+
+func (p *ListClusterPolicyReportPageParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addInt(query, "limit", p.Limit)
+	addString(query, "sortBy", p.SortBy)
+	addString(query, "direction", p.Direction)
+	addString(query, "cursor", p.Cursor)
+	addStrings(query, "fields", p.Fields)
+
+	return query
+}
+
+// ListClusterPolicyReportPage calls GET /v2/cluster-policy-reports to list ClusterPolicyReports with cursor pagination
+func (c *Client) ListClusterPolicyReportPage(ctx context.Context, params *ListClusterPolicyReportPageParams) (*Page, error) {
+	return c.getPage(ctx, "/v2/cluster-policy-reports", params.values())
+}
+
+// ListReportPageParams are the query parameters of /v2/reports
+type ListReportPageParams struct {
+	Namespaces []string
+	Sources    []string
+	Labels     []string
+	Cluster    string
+	Limit      int
+	SortBy     string
+	Direction  string
+	Cursor     string
+	Fields     []string
+}
+
+func (p *ListReportPageParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addInt(query, "limit", p.Limit)
+	addString(query, "sortBy", p.SortBy)
+	addString(query, "direction", p.Direction)
+	addString(query, "cursor", p.Cursor)
+	addStrings(query, "fields", p.Fields)
+
+	return query
+}
+
+// ListReportPage calls GET /v2/reports to list PolicyReports and ClusterPolicyReports with their labels, scope, summary and timestamps with cursor pagination, the cluster namespace selects ClusterPolicyReports
+func (c *Client) ListReportPage(ctx context.Context, params *ListReportPageParams) (*Page, error) {
+	return c.getPage(ctx, "/v2/reports", params.values())
+}
+
+// GetReportParams are the query parameters of /v2/reports/{id}
+type GetReportParams struct {
+	Raw string
+}
+
+func (p *GetReportParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addString(query, "raw", p.Raw)
+
+	return query
+}
+
+// GetReport calls GET /v2/reports/{id} to get a PolicyReport or ClusterPolicyReport with its labels, scope, summary and timestamps
+func (c *Client) GetReport(ctx context.Context, id string, params *GetReportParams) (*v2.Report, error) {
+	result := &v2.Report{}
+	if _, err := c.get(ctx, "/v2/reports/"+url.PathEscape(id), params.values(), result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListNamespacedResultPageParams are the query parameters of /v2/namespaced-resources/results
+type ListNamespacedResultPageParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+	Limit      int
+	SortBy     string
+	Direction  string
+	Cursor     string
+	Fields     []string
+}
+
+func (p *ListNamespacedResultPageParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+	addInt(query, "limit", p.Limit)
+	addString(query, "sortBy", p.SortBy)
+	addString(query, "direction", p.Direction)
+	addString(query, "cursor", p.Cursor)
+	addStrings(query, "fields", p.Fields)
+
+	return query
+}
+
+// ListNamespacedResultPage calls GET /v2/namespaced-resources/results to list namespaced results with cursor pagination
+func (c *Client) ListNamespacedResultPage(ctx context.Context, params *ListNamespacedResultPageParams) (*Page, error) {
+	return c.getPage(ctx, "/v2/namespaced-resources/results", params.values())
+}
+
+// ListClusterResultPageParams are the query parameters of /v2/cluster-resources/results
+type ListClusterResultPageParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+	Limit      int
+	SortBy     string
+	Direction  string
+	Cursor     string
+	Fields     []string
+}
+
+func (p *ListClusterResultPageParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+	addInt(query, "limit", p.Limit)
+	addString(query, "sortBy", p.SortBy)
+	addString(query, "direction", p.Direction)
+	addString(query, "cursor", p.Cursor)
+	addStrings(query, "fields", p.Fields)
+
+	return query
+}
+
+// ListClusterResultPage calls GET /v2/cluster-resources/results to list cluster scoped results with cursor pagination
+func (c *Client) ListClusterResultPage(ctx context.Context, params *ListClusterResultPageParams) (*Page, error) {
+	return c.getPage(ctx, "/v2/cluster-resources/results", params.values())
+}
+
+// ListVulnerabilitiesParams are the query parameters of /v2/vulnerabilities
+type ListVulnerabilitiesParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+	Fixable    string
+}
+
+func (p *ListVulnerabilitiesParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+	addString(query, "fixable", p.Fixable)
+
+	return query
+}
+
+// ListVulnerabilities calls GET /v2/vulnerabilities to group results with a vulnerabilityID property by CVE with the affected images, resources and fixed versions, requires sourceMappers.enabled for Trivy Operator reports
+func (c *Client) ListVulnerabilities(ctx context.Context, params *ListVulnerabilitiesParams) ([]v2.Vulnerability, error) {
+	var result []v2.Vulnerability
+	_, err := c.get(ctx, "/v2/vulnerabilities", params.values(), &result)
+
+	return result, err
+}
+
+// GetSeverityMatrixParams are the query parameters of /v2/findings/severities
+type GetSeverityMatrixParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+}
+
+func (p *GetSeverityMatrixParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+
+	return query
+}
+
+// GetSeverityMatrix calls GET /v2/findings/severities to count the findings by source and severity and by namespace and severity, counts fail, warn and error results without status filter
+func (c *Client) GetSeverityMatrix(ctx context.Context, params *GetSeverityMatrixParams) (*v2.SeverityMatrix, error) {
+	result := &v2.SeverityMatrix{}
+	if _, err := c.get(ctx, "/v2/findings/severities", params.values(), result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetResultDiffParams are the query parameters of /v2/namespaces/{namespace}/diff
+type GetResultDiffParams struct {
+	From string
+	To   string
+}
+
+func (p *GetResultDiffParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addString(query, "from", p.From)
+	addString(query, "to", p.To)
+
+	return query
+}
+
+// GetResultDiff calls GET /v2/namespaces/{namespace}/diff to compare the fail, warn and error results of a namespace between two points in time, requires history.enabled
+func (c *Client) GetResultDiff(ctx context.Context, namespace string, params *GetResultDiffParams) (*v2.ResultDiff, error) {
+	result := &v2.ResultDiff{}
+	if _, err := c.get(ctx, "/v2/namespaces/"+url.PathEscape(namespace)+"/diff", params.values(), result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListTombstonesParams are the query parameters of /v2/tombstones
+type ListTombstonesParams struct {
+	Namespaces []string
+	Sources    []string
+	Labels     []string
+	Cluster    string
+	Since      string
+}
+
+func (p *ListTombstonesParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addString(query, "since", p.Since)
+
+	return query
+}
+
+// ListTombstones calls GET /v2/tombstones to list the tombstones of deleted PolicyReports and ClusterPolicyReports with their deletion time and last summary, requires tombstones.enabled
+func (c *Client) ListTombstones(ctx context.Context, params *ListTombstonesParams) ([]v2.Tombstone, error) {
+	var result []v2.Tombstone
+	_, err := c.get(ctx, "/v2/tombstones", params.values(), &result)
+
+	return result, err
+}
+
+// ListNamespaceResourcesParams are the query parameters of /v2/namespaces/{namespace}/resources
+type ListNamespaceResourcesParams struct {
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Labels     []string
+	Cluster    string
+	Teams      []string
+	Search     string
+	Filter     string
+}
+
+func (p *ListNamespaceResourcesParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+
+	return query
+}
+
+// ListNamespaceResources calls GET /v2/namespaces/{namespace}/resources to list the resources of a namespace with the status counts of their results across all sources
+func (c *Client) ListNamespaceResources(ctx context.Context, namespace string, params *ListNamespaceResourcesParams) ([]v2.ResourceStatus, error) {
+	var result []v2.ResourceStatus
+	_, err := c.get(ctx, "/v2/namespaces/"+url.PathEscape(namespace)+"/resources", params.values(), &result)
+
+	return result, err
+}
+
+// GetResourceResultsParams are the query parameters of /v2/resources/{kind}/{name}/results
+type GetResourceResultsParams struct {
+	Namespace  string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Labels     []string
+	Cluster    string
+	Teams      []string
+	Search     string
+	Filter     string
+}
+
+func (p *GetResourceResultsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addString(query, "namespace", p.Namespace)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+
+	return query
+}
+
+// GetResourceResults calls GET /v2/resources/{kind}/{name}/results to get the results of a resource across all sources with its status counts
+func (c *Client) GetResourceResults(ctx context.Context, kind string, name string, params *GetResourceResultsParams) (*v2.ResourceResults, error) {
+	result := &v2.ResourceResults{}
+	if _, err := c.get(ctx, "/v2/resources/"+url.PathEscape(kind)+"/"+url.PathEscape(name)+"/results", params.values(), result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListNodesParams are the query parameters of /v2/nodes
+type ListNodesParams struct {
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Labels     []string
+	Cluster    string
+	Teams      []string
+	Search     string
+	Filter     string
+}
+
+func (p *ListNodesParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+
+	return query
+}
+
+// ListNodes calls GET /v2/nodes to list the nodes with results, e.g. of kube-bench, with the status counts of their results across all sources
+func (c *Client) ListNodes(ctx context.Context, params *ListNodesParams) ([]v2.ResourceStatus, error) {
+	var result []v2.ResourceStatus
+	_, err := c.get(ctx, "/v2/nodes", params.values(), &result)
+
+	return result, err
+}
+
+// GetNodeResultsParams are the query parameters of /v2/nodes/{node}/results
+type GetNodeResultsParams struct {
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Labels     []string
+	Cluster    string
+	Teams      []string
+	Search     string
+	Filter     string
+}
+
+func (p *GetNodeResultsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+
+	return query
+}
+
+// GetNodeResults calls GET /v2/nodes/{node}/results to get the results of a node across all sources with its status counts
+func (c *Client) GetNodeResults(ctx context.Context, node string, params *GetNodeResultsParams) (*v2.ResourceResults, error) {
+	result := &v2.ResourceResults{}
+	if _, err := c.get(ctx, "/v2/nodes/"+url.PathEscape(node)+"/results", params.values(), result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListPolicyStatusParams are the query parameters of /v2/policies
+type ListPolicyStatusParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Labels     []string
+	Cluster    string
+	Teams      []string
+	Search     string
+	Filter     string
+}
+
+func (p *ListPolicyStatusParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+
+	return query
+}
+
+// ListPolicyStatus calls GET /v2/policies to list the policies with their status counts, the severities of their fail, warn and error results and the affected namespaces and resources
+func (c *Client) ListPolicyStatus(ctx context.Context, params *ListPolicyStatusParams) ([]v2.PolicyStatus, error) {
+	var result []v2.PolicyStatus
+	_, err := c.get(ctx, "/v2/policies", params.values(), &result)
+
+	return result, err
+}
+
+// GetPolicyResultsParams are the query parameters of /v2/policies/{policy}/results
+type GetPolicyResultsParams struct {
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+}
+
+func (p *GetPolicyResultsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+
+	return query
+}
+
+// GetPolicyResults calls GET /v2/policies/{policy}/results to get the results of a policy with its status counts, severities and affected namespaces
+func (c *Client) GetPolicyResults(ctx context.Context, policy string, params *GetPolicyResultsParams) (*v2.PolicyResults, error) {
+	result := &v2.PolicyResults{}
+	if _, err := c.get(ctx, "/v2/policies/"+url.PathEscape(policy)+"/results", params.values(), result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SearchParams are the query parameters of /v2/search
+type SearchParams struct {
+	Q          string
+	Limit      int
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+}
+
+func (p *SearchParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addString(query, "q", p.Q)
+	addInt(query, "limit", p.Limit)
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+
+	return query
+}
+
+// Search calls GET /v2/search to search policies, namespaces, resources and result messages, the hits are ranked by how well they match the term
+func (c *Client) Search(ctx context.Context, params *SearchParams) ([]v2.SearchHit, error) {
+	var result []v2.SearchHit
+	_, err := c.get(ctx, "/v2/search", params.values(), &result)
+
+	return result, err
+}
+
+// ListClusterSummaries calls GET /v2/clusters to aggregate the PolicyReports per federated cluster, requires federation.enabled
+func (c *Client) ListClusterSummaries(ctx context.Context) ([]federation.ClusterSummary, error) {
+	var result []federation.ClusterSummary
+	_, err := c.get(ctx, "/v2/clusters", nil, &result)
+
+	return result, err
+}
+
+// ListTargetStatus calls GET /v2/targets to list the configured targets with their filters and last delivery
+func (c *Client) ListTargetStatus(ctx context.Context) ([]v2.Target, error) {
+	var result []v2.Target
+	_, err := c.get(ctx, "/v2/targets", nil, &result)
+
+	return result, err
+}
+
+// TestTarget calls POST /v2/targets/{name}/test to send a synthetic fail result to a target, ignoring its filters
+func (c *Client) TestTarget(ctx context.Context, name string) (*v2.TargetTest, error) {
+	result := &v2.TargetTest{}
+	if _, err := c.do(ctx, "POST", "/v2/targets/"+url.PathEscape(name)+"/test", nil, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ReplayResultsParams are the query parameters of /v2/targets/replay
+type ReplayResultsParams struct {
+	Target     string
+	From       string
+	To         string
+	Namespaces []string
+	Kinds      []string
+	Resources  []string
+	Sources    []string
+	Categories []string
+	Severities []string
+	Policies   []string
+	Rules      []string
+	Status     []string
+	Labels     []string
+	Cluster    string
+	Ids        []string
+	Teams      []string
+	Search     string
+	Filter     string
+}
+
+func (p *ReplayResultsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addString(query, "target", p.Target)
+	addString(query, "from", p.From)
+	addString(query, "to", p.To)
+	addStrings(query, "namespaces", p.Namespaces)
+	addStrings(query, "kinds", p.Kinds)
+	addStrings(query, "resources", p.Resources)
+	addStrings(query, "sources", p.Sources)
+	addStrings(query, "categories", p.Categories)
+	addStrings(query, "severities", p.Severities)
+	addStrings(query, "policies", p.Policies)
+	addStrings(query, "rules", p.Rules)
+	addStrings(query, "status", p.Status)
+	addStrings(query, "labels", p.Labels)
+	addString(query, "cluster", p.Cluster)
+	addStrings(query, "ids", p.Ids)
+	addStrings(query, "teams", p.Teams)
+	addString(query, "search", p.Search)
+	addString(query, "filter", p.Filter)
+
+	return query
+}
+
+// ReplayResults calls POST /v2/targets/replay to send the stored results matching the filter and time range to a target again, applying its filters
+func (c *Client) ReplayResults(ctx context.Context, params *ReplayResultsParams) (*v2.TargetReplay, error) {
+	result := &v2.TargetReplay{}
+	if _, err := c.do(ctx, "POST", "/v2/targets/replay", params.values(), result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// TriageResultParams are the query parameters of /v2/results/{id}/triage
+type TriageResultParams struct {
+	Status  string
+	Comment string
+}
+
+func (p *TriageResultParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addString(query, "status", p.Status)
+	addString(query, "comment", p.Comment)
+
+	return query
+}
+
+// TriageResult calls POST /v2/results/{id}/triage to set the triage status of a result and add a comment, the triage is included in the result lists and exports, requires triage.enabled
+func (c *Client) TriageResult(ctx context.Context, id string, params *TriageResultParams) (*triage.Triage, error) {
+	result := &triage.Triage{}
+	if _, err := c.do(ctx, "POST", "/v2/results/"+url.PathEscape(id)+"/triage", params.values(), result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SnoozeResultParams are the query parameters of /v2/snoozes
+type SnoozeResultParams struct {
+	ResultId string
+	Duration string
+	Reason   string
+}
+
+func (p *SnoozeResultParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addString(query, "resultId", p.ResultId)
+	addString(query, "duration", p.Duration)
+	addString(query, "reason", p.Reason)
+
+	return query
+}
+
+// SnoozeResult calls POST /v2/snoozes to snooze the notifications of a result for a duration, snoozed results stay in the store, requires snooze.enabled
+func (c *Client) SnoozeResult(ctx context.Context, params *SnoozeResultParams) (*snooze.Snooze, error) {
+	result := &snooze.Snooze{}
+	if _, err := c.do(ctx, "POST", "/v2/snoozes", params.values(), result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListSnoozeEventsParams are the query parameters of /v2/snoozes/events
+type ListSnoozeEventsParams struct {
+	ResultId string
+}
+
+func (p *ListSnoozeEventsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addString(query, "resultId", p.ResultId)
+
+	return query
+}
+
+// ListSnoozeEvents calls GET /v2/snoozes/events to list the audit trail of who snoozed or unsnoozed which result, latest first, requires snooze.enabled
+func (c *Client) ListSnoozeEvents(ctx context.Context, params *ListSnoozeEventsParams) ([]snooze.Event, error) {
+	var result []snooze.Event
+	_, err := c.get(ctx, "/v2/snoozes/events", params.values(), &result)
+
+	return result, err
+}
+
+// SubscribeNamespaceParams are the query parameters of /v2/namespaces/{namespace}/subscriptions
+type SubscribeNamespaceParams struct {
+	Email string
+}
+
+func (p *SubscribeNamespaceParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+
+	addString(query, "email", p.Email)
+
+	return query
+}
+
+// SubscribeNamespace calls POST /v2/namespaces/{namespace}/subscriptions to subscribe an email address to the summary digest of a namespace, the subscription is pending until the link of the confirmation email is opened, requires emailReports.subscriptions.enabled
+func (c *Client) SubscribeNamespace(ctx context.Context, namespace string, params *SubscribeNamespaceParams) (*subscription.Subscription, error) {
+	result := &subscription.Subscription{}
+	if _, err := c.do(ctx, "POST", "/v2/namespaces/"+url.PathEscape(namespace)+"/subscriptions", params.values(), result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -1,43 +1,39 @@
 package api
 
 import (
-	"fmt"
+	"context"
+	"encoding/json"
 	"net/http"
-)
-
-// HealthzHandler for the Halthz REST API
-func HealthzHandler(synced func() bool) http.HandlerFunc {
-	return func(w http.ResponseWriter, req *http.Request) {
-		if !synced() {
-			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
-			w.WriteHeader(http.StatusServiceUnavailable)
+	"time"
 
-			fmt.Fprint(w, `{ "error": "Informers not in sync" }`)
-
-			return
-		}
+	"github.com/kyverno/policy-reporter/pkg/health"
+)
 
-		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
-		w.WriteHeader(http.StatusOK)
+// HealthzHandler for the liveness REST API
+func HealthzHandler(checker *health.Checker) http.HandlerFunc {
+	return healthHandler(checker)
+}
 
-		fmt.Fprint(w, "{}")
-	}
+// ReadyHandler for the readiness REST API
+func ReadyHandler(checker *health.Checker) http.HandlerFunc {
+	return healthHandler(checker)
 }
 
-// ReadyHandler for the Halthz REST API
-func ReadyHandler(synced func() bool) http.HandlerFunc {
+// healthHandler responds with the status of each component, the status code is 503 if a critical component is not ok
+func healthHandler(checker *health.Checker) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		if !synced() {
-			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
-			w.WriteHeader(http.StatusServiceUnavailable)
+		ctx, cancel := context.WithTimeout(req.Context(), 5*time.Second)
+		defer cancel()
 
-			fmt.Fprint(w, `{ "error": "Informers not in sync" }`)
+		report := checker.Check(ctx)
 
-			return
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		if report.Status == health.Unavailable {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.WriteHeader(http.StatusOK)
 		}
 
-		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
-		w.WriteHeader(http.StatusOK)
-		fmt.Fprint(w, "{}")
+		json.NewEncoder(w).Encode(report)
 	}
 }
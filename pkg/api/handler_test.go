@@ -1,11 +1,14 @@
 package api_test
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/kyverno/policy-reporter/pkg/api"
+	"github.com/kyverno/policy-reporter/pkg/health"
 )
 
 func Test_HealthzAPI(t *testing.T) {
@@ -16,7 +19,7 @@ func Test_HealthzAPI(t *testing.T) {
 		}
 
 		rr := httptest.NewRecorder()
-		handler := api.HealthzHandler(func() bool { return true })
+		handler := api.HealthzHandler(health.NewChecker().Register("informers", true, health.Synced(func() bool { return true })))
 
 		handler.ServeHTTP(rr, req)
 
@@ -31,7 +34,7 @@ func Test_HealthzAPI(t *testing.T) {
 		}
 
 		rr := httptest.NewRecorder()
-		handler := api.HealthzHandler(func() bool { return false })
+		handler := api.HealthzHandler(health.NewChecker().Register("informers", true, health.Synced(func() bool { return false })))
 
 		handler.ServeHTTP(rr, req)
 
@@ -49,7 +52,7 @@ func Test_ReadyAPI(t *testing.T) {
 		}
 
 		rr := httptest.NewRecorder()
-		handler := api.ReadyHandler(func() bool { return true })
+		handler := api.ReadyHandler(health.NewChecker().Register("informers", true, health.Synced(func() bool { return true })))
 
 		handler.ServeHTTP(rr, req)
 
@@ -64,7 +67,7 @@ func Test_ReadyAPI(t *testing.T) {
 		}
 
 		rr := httptest.NewRecorder()
-		handler := api.ReadyHandler(func() bool { return false })
+		handler := api.ReadyHandler(health.NewChecker().Register("informers", true, health.Synced(func() bool { return false })))
 
 		handler.ServeHTTP(rr, req)
 
@@ -72,4 +75,32 @@ func Test_ReadyAPI(t *testing.T) {
 			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusServiceUnavailable)
 		}
 	})
+	t.Run("Degraded Response", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/ready", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checker := health.NewChecker().Register("targets", false, func(_ context.Context) health.Component {
+			return health.Component{Status: health.Degraded, Message: "last delivery failed"}
+		})
+
+		rr := httptest.NewRecorder()
+		handler := api.ReadyHandler(checker)
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		report := health.Report{}
+		if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+
+		if report.Status != health.Degraded || report.Components["targets"].Message != "last delivery failed" {
+			t.Errorf("unexpected report: %+v", report)
+		}
+	})
 }
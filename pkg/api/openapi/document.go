@@ -0,0 +1,245 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Version of the documented REST API
+const Version = "2.0.0"
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Tags       []Tag               `json:"tags"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type PathItem struct {
+	Get  *Operation `json:"get,omitempty"`
+	Post *Operation `json:"post,omitempty"`
+}
+
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary"`
+	Tags        []string            `json:"tags"`
+	Parameters  []ParameterObject   `json:"parameters,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+type ParameterObject struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Style       string  `json:"style,omitempty"`
+	Explode     bool    `json:"explode,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Description string  `json:"description"`
+	Schema      *Schema `json:"schema"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// NewDocument generates the OpenAPI 3 document of the given routes
+func NewDocument(routes []Route) *Document {
+	schemas := make(map[string]*Schema)
+	schemas["Error"] = &Schema{Type: "object", Properties: map[string]*Schema{"message": {Type: "string"}}, Required: []string{"message"}}
+
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "Policy Reporter API",
+			Description: "REST API of Policy Reporter, optional endpoints are only available if enabled",
+			Version:     Version,
+		},
+		Tags: []Tag{
+			{Name: TagLifecycle, Description: "Health and readiness of Policy Reporter"},
+			{Name: TagV1, Description: "Offset paginated REST API, requires rest.enabled"},
+			{Name: TagV2, Description: "Cursor paginated REST API and live result stream, requires rest.enabled"},
+			{Name: TagHistory, Description: "Historical results, requires history.enabled"},
+		},
+		Paths:      make(map[string]PathItem, len(routes)),
+		Components: Components{Schemas: schemas},
+	}
+
+	errorResponse := Response{Description: "error", Content: map[string]MediaType{"application/json": {Schema: &Schema{Ref: "#/components/schemas/Error"}}}}
+
+	for _, route := range routes {
+		op := &Operation{
+			OperationID: route.OperationID,
+			Summary:     route.Summary,
+			Tags:        []string{route.Tag},
+			Responses:   map[string]Response{"500": errorResponse},
+		}
+
+		for _, p := range route.Parameters {
+			schema := &Schema{Type: p.Type, Enum: p.Enum}
+			param := ParameterObject{Name: p.Name, In: "query", Description: p.Description, Schema: schema}
+			if p.InPath {
+				param.In = "path"
+				param.Required = true
+				op.Responses["404"] = errorResponse
+			}
+
+			if p.Array {
+				param.Schema = &Schema{Type: "array", Items: schema}
+				param.Style = "form"
+				param.Explode = true
+			}
+			if p.Name == "filter" {
+				op.Responses["400"] = errorResponse
+			}
+
+			op.Parameters = append(op.Parameters, param)
+		}
+
+		ok := Response{Description: "successful response"}
+		if route.Response != nil {
+			ok.Content = map[string]MediaType{"application/json": {Schema: schemaOf(reflect.TypeOf(route.Response), schemas)}}
+		} else {
+			ok.Content = map[string]MediaType{route.ContentType: {}}
+		}
+		if route.Paginated {
+			ok.Headers = map[string]Header{
+				"X-Total-Count": {Description: "total count of items matching the filter", Schema: &Schema{Type: "integer"}},
+				"X-Next-Cursor": {Description: "cursor of the next page, empty on the last page", Schema: &Schema{Type: "string"}},
+			}
+		}
+
+		op.Responses["200"] = ok
+		if route.Method == http.MethodPost {
+			doc.Paths[route.Path] = PathItem{Post: op}
+		} else {
+			doc.Paths[route.Path] = PathItem{Get: op}
+		}
+	}
+
+	return doc
+}
+
+// schemaOf creates the schema of a Go type, named structs are added to the component schemas
+func schemaOf(t reflect.Type, schemas map[string]*Schema) *Schema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaOf(t.Elem(), schemas)
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: schemaOf(t.Elem(), schemas)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaOf(t.Elem(), schemas)}
+	case reflect.Struct:
+		if _, ok := schemas[t.Name()]; !ok {
+			schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+			schemas[t.Name()] = schema
+
+			for i := 0; i < t.NumField(); i++ {
+				field := t.Field(i)
+				name, omitEmpty := jsonName(field)
+				if name == "" {
+					continue
+				}
+
+				schema.Properties[name] = schemaOf(field.Type, schemas)
+				if !omitEmpty {
+					schema.Required = append(schema.Required, name)
+				}
+			}
+		}
+
+		return &Schema{Ref: "#/components/schemas/" + t.Name()}
+	}
+
+	return &Schema{}
+}
+
+func jsonName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = field.Name
+	}
+
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			return name, true
+		}
+	}
+
+	return name, false
+}
+
+// Handler serves the OpenAPI document of all REST endpoints
+func Handler() http.HandlerFunc {
+	var once sync.Once
+	var content []byte
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		once.Do(func() {
+			content, _ = json.Marshal(NewDocument(Routes))
+		})
+
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.Write(content)
+	}
+}
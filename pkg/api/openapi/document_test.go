@@ -0,0 +1,103 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/api/openapi"
+)
+
+func Test_NewDocument(t *testing.T) {
+	doc := openapi.NewDocument(openapi.Routes)
+
+	t.Run("Paths", func(t *testing.T) {
+		if len(doc.Paths) != len(openapi.Routes) {
+			t.Errorf("expected %d paths, got %d", len(openapi.Routes), len(doc.Paths))
+		}
+
+		for _, route := range openapi.Routes {
+			item, ok := doc.Paths[route.Path]
+			op := item.Get
+			if route.Method == http.MethodPost {
+				op = item.Post
+			}
+
+			if !ok || op == nil {
+				t.Errorf("missing operation for %s", route.Path)
+				continue
+			}
+			if op.OperationID != route.OperationID {
+				t.Errorf("expected operationId %s, got %s", route.OperationID, op.OperationID)
+			}
+		}
+	})
+
+	t.Run("Schemas", func(t *testing.T) {
+		for _, name := range []string{"Error", "ListResult", "ResultList", "PolicyReport", "Page"} {
+			if _, ok := doc.Components.Schemas[name]; !ok {
+				t.Errorf("expected schema %s", name)
+			}
+		}
+
+		result := doc.Components.Schemas["ListResult"]
+		if result.Properties["timestamp"].Type != "integer" {
+			t.Errorf("expected integer timestamp, got %s", result.Properties["timestamp"].Type)
+		}
+		if result.Properties["properties"] == nil || result.Properties["properties"].AdditionalProperties == nil {
+			t.Error("expected properties as map schema")
+		}
+	})
+
+	t.Run("Responses", func(t *testing.T) {
+		results := doc.Paths["/v1/namespaced-resources/results"].Get
+		if _, ok := results.Responses["400"]; !ok {
+			t.Error("expected bad request response for routes with filter expression")
+		}
+		if results.Responses["200"].Content["application/json"].Schema.Ref != "#/components/schemas/ResultList" {
+			t.Error("expected ResultList response schema")
+		}
+
+		page := doc.Paths["/v2/namespaced-resources/results"].Get
+		if _, ok := page.Responses["200"].Headers["X-Total-Count"]; !ok {
+			t.Error("expected X-Total-Count header for paginated routes")
+		}
+
+		targets := doc.Paths["/v1/targets"].Get
+		if _, ok := targets.Responses["400"]; ok {
+			t.Error("unexpected bad request response without filter expression")
+		}
+
+		test := doc.Paths["/v2/targets/{name}/test"].Post
+		if test == nil || test.Parameters[0].In != "path" || !test.Parameters[0].Required {
+			t.Fatal("expected required path parameter for the target test")
+		}
+		if _, ok := test.Responses["404"]; !ok {
+			t.Error("expected not found response for routes with path parameters")
+		}
+	})
+}
+
+func Test_Handler(t *testing.T) {
+	req, err := http.NewRequest("GET", "/openapi.json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	openapi.Handler().ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	doc := openapi.Document{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.OpenAPI != "3.0.3" || doc.Info.Version != openapi.Version {
+		t.Errorf("unexpected document version %s / %s", doc.OpenAPI, doc.Info.Version)
+	}
+}
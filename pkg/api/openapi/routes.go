@@ -0,0 +1,247 @@
+package openapi
+
+import (
+	"github.com/kyverno/policy-reporter/pkg/acknowledgment"
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+	"github.com/kyverno/policy-reporter/pkg/audit"
+	"github.com/kyverno/policy-reporter/pkg/coverage"
+	"github.com/kyverno/policy-reporter/pkg/federation"
+	"github.com/kyverno/policy-reporter/pkg/health"
+	"github.com/kyverno/policy-reporter/pkg/score"
+	"github.com/kyverno/policy-reporter/pkg/snooze"
+	"github.com/kyverno/policy-reporter/pkg/subscription"
+	"github.com/kyverno/policy-reporter/pkg/triage"
+)
+
+// Parameter of a REST endpoint, query parameter unless InPath is set
+type Parameter struct {
+	Name        string
+	Description string
+	// Type is one of string, integer or boolean
+	Type  string
+	Array bool
+	Enum  []string
+	// InPath parameters replace the {Name} placeholder of the route path
+	InPath bool
+}
+
+// Route describes a REST endpoint, the OperationID is also the method name of the generated client
+type Route struct {
+	// Method of the endpoint, defaults to GET
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	Tag         string
+	Parameters  []Parameter
+	// Response is a value of the JSON response type, nil for non JSON endpoints
+	Response interface{}
+	// ContentType of non JSON responses
+	ContentType string
+	// Paginated routes return the X-Total-Count and X-Next-Cursor headers
+	Paginated bool
+}
+
+const (
+	TagLifecycle = "lifecycle"
+	TagV1        = "v1"
+	TagV2        = "v2"
+	TagHistory   = "history"
+)
+
+var filterParameters = []Parameter{
+	{Name: "namespaces", Type: "string", Array: true, Description: "filter by resource namespace, the cluster pseudo namespace selects cluster scoped results"},
+	{Name: "kinds", Type: "string", Array: true, Description: "filter by resource kind"},
+	{Name: "resources", Type: "string", Array: true, Description: "filter by resource name"},
+	{Name: "sources", Type: "string", Array: true, Description: "filter by result source"},
+	{Name: "categories", Type: "string", Array: true, Description: "filter by policy category"},
+	{Name: "severities", Type: "string", Array: true, Description: "filter by result severity", Enum: []string{"info", "low", "medium", "high", "critical"}},
+	{Name: "policies", Type: "string", Array: true, Description: "filter by policy"},
+	{Name: "rules", Type: "string", Array: true, Description: "filter by rule"},
+	{Name: "status", Type: "string", Array: true, Description: "filter by result status", Enum: []string{"pass", "fail", "warn", "error", "skip"}},
+	{Name: "labels", Type: "string", Array: true, Description: "filter by report label in the format key:value"},
+	{Name: "cluster", Type: "string", Description: "filter by the cluster of federated PolicyReports"},
+	{Name: "ids", Type: "string", Array: true, Description: "filter by result ID, e.g. the result_id exemplar of the metrics"},
+	{Name: "teams", Type: "string", Array: true, Description: "filter by the team property of the ownership mapping"},
+	{Name: "search", Type: "string", Description: "search in namespace, name, policy, rule, severity, status and kind"},
+	{Name: "filter", Type: "string", Description: "filter expression like severity>=high AND namespace!=kube-system"},
+}
+
+var reportFilterParameters = []Parameter{
+	{Name: "namespaces", Type: "string", Array: true, Description: "filter by report namespace"},
+	{Name: "sources", Type: "string", Array: true, Description: "filter by report source"},
+	{Name: "labels", Type: "string", Array: true, Description: "filter by report label in the format key:value"},
+	{Name: "cluster", Type: "string", Description: "filter by the cluster of federated PolicyReports"},
+}
+
+var paginationParameters = []Parameter{
+	{Name: "page", Type: "integer", Description: "page starting with 1, requires offset"},
+	{Name: "offset", Type: "integer", Description: "page size"},
+	{Name: "sortBy", Type: "string", Array: true, Description: "sort fields"},
+	{Name: "direction", Type: "string", Enum: []string{"asc", "desc"}},
+}
+
+var cursorParameters = []Parameter{
+	{Name: "limit", Type: "integer", Description: "page size, defaults to 50 with a maximum of 1000"},
+	{Name: "sortBy", Type: "string", Description: "sort field"},
+	{Name: "direction", Type: "string", Enum: []string{"asc", "desc"}},
+	{Name: "cursor", Type: "string", Description: "nextCursor of the previous page"},
+	{Name: "fields", Type: "string", Array: true, Description: "fields of each item, the id is always included"},
+}
+
+var clusterScopeParameters = []Parameter{
+	{Name: "clusterScope", Type: "boolean", Description: "include cluster scoped results as cluster pseudo namespace"},
+}
+
+var historyParameters = []Parameter{
+	{Name: "since", Type: "string", Description: "duration like 24h or 7d, defaults to 7d"},
+	{Name: "interval", Type: "string", Description: "duration like 1h or 1d, defaults to 1d"},
+}
+
+func join(groups ...[]Parameter) []Parameter {
+	params := make([]Parameter, 0)
+	for _, g := range groups {
+		params = append(params, g...)
+	}
+
+	return params
+}
+
+func without(params []Parameter, names ...string) []Parameter {
+	list := make([]Parameter, 0, len(params))
+	for _, p := range params {
+		excluded := false
+		for _, name := range names {
+			excluded = excluded || p.Name == name
+		}
+
+		if !excluded {
+			list = append(list, p)
+		}
+	}
+
+	return list
+}
+
+// resourceFilterParameters of the resource views, the resource is selected by the path and all status are counted
+var resourceFilterParameters = without(filterParameters, "namespaces", "kinds", "resources", "status", "ids")
+
+// Routes of all REST endpoints, optional endpoints are only served if enabled
+var Routes = []Route{
+	{Path: "/healthz", OperationID: "healthz", Summary: "Check the liveness, returns an error until the informers are synced", Tag: TagLifecycle, Response: health.Report{}},
+	{Path: "/ready", OperationID: "ready", Summary: "Check the readiness per component, returns an error if a critical component is unavailable", Tag: TagLifecycle, Response: health.Report{}},
+
+	{Path: "/v1/targets", OperationID: "listTargets", Summary: "List the configured targets", Tag: TagV1, Response: []v1.Target{}},
+	{Path: "/v1/categories", OperationID: "listCategories", Summary: "List all policy categories", Tag: TagV1, Parameters: filterParameters, Response: []string{}},
+	{Path: "/v1/taxonomy", OperationID: "getTaxonomy", Summary: "List the observed sources, categories, policies and rules with their result counts and the display names, groups and order of the taxonomy", Tag: TagV1, Parameters: filterParameters, Response: v1.Taxonomy{}},
+	{Path: "/v1/namespaces", OperationID: "listNamespaces", Summary: "List all namespaces with results", Tag: TagV1, Parameters: join(filterParameters, clusterScopeParameters), Response: []string{}},
+	{Path: "/v1/rule-status-count", OperationID: "getRuleStatusCounts", Summary: "Count the results of a policy rule per status", Tag: TagV1, Parameters: []Parameter{{Name: "policy", Type: "string"}, {Name: "rule", Type: "string"}}, Response: []v1.StatusCount{}},
+	{Path: "/v1/policy-reports", OperationID: "listPolicyReports", Summary: "List PolicyReports", Tag: TagV1, Parameters: join(reportFilterParameters, paginationParameters), Response: v1.PolicyReportList{}},
+	{Path: "/v1/cluster-policy-reports", OperationID: "listClusterPolicyReports", Summary: "List ClusterPolicyReports", Tag: TagV1, Parameters: join(reportFilterParameters, paginationParameters), Response: v1.PolicyReportList{}},
+
+	{Path: "/v1/namespaced-resources/policies", OperationID: "listNamespacedPolicies", Summary: "List policies of namespaced results", Tag: TagV1, Parameters: filterParameters, Response: []string{}},
+	{Path: "/v1/namespaced-resources/rules", OperationID: "listNamespacedRules", Summary: "List rules of namespaced results", Tag: TagV1, Parameters: filterParameters, Response: []string{}},
+	{Path: "/v1/namespaced-resources/kinds", OperationID: "listNamespacedKinds", Summary: "List resource kinds of namespaced results", Tag: TagV1, Parameters: filterParameters, Response: []string{}},
+	{Path: "/v1/namespaced-resources/resources", OperationID: "listNamespacedResources", Summary: "List resources of namespaced results", Tag: TagV1, Parameters: filterParameters, Response: []v1.Resource{}},
+	{Path: "/v1/namespaced-resources/sources", OperationID: "listNamespacedSources", Summary: "List sources of namespaced results", Tag: TagV1, Response: []string{}},
+	{Path: "/v1/namespaced-resources/report-labels", OperationID: "listNamespacedReportLabels", Summary: "List labels of PolicyReports", Tag: TagV1, Parameters: reportFilterParameters, Response: map[string][]string{}},
+	{Path: "/v1/namespaced-resources/status-counts", OperationID: "getNamespacedStatusCounts", Summary: "Count namespaced results per status and namespace", Tag: TagV1, Parameters: join(filterParameters, clusterScopeParameters), Response: []v1.NamespacedStatusCount{}},
+	{Path: "/v1/namespaced-resources/results", OperationID: "listNamespacedResults", Summary: "List namespaced results", Tag: TagV1, Parameters: join(filterParameters, paginationParameters), Response: v1.ResultList{}},
+
+	{Path: "/v1/cluster-resources/policies", OperationID: "listClusterPolicies", Summary: "List policies of cluster scoped results", Tag: TagV1, Parameters: filterParameters, Response: []string{}},
+	{Path: "/v1/cluster-resources/rules", OperationID: "listClusterRules", Summary: "List rules of cluster scoped results", Tag: TagV1, Parameters: filterParameters, Response: []string{}},
+	{Path: "/v1/cluster-resources/kinds", OperationID: "listClusterKinds", Summary: "List resource kinds of cluster scoped results", Tag: TagV1, Parameters: filterParameters, Response: []string{}},
+	{Path: "/v1/cluster-resources/resources", OperationID: "listClusterResources", Summary: "List resources of cluster scoped results", Tag: TagV1, Parameters: filterParameters, Response: []v1.Resource{}},
+	{Path: "/v1/cluster-resources/sources", OperationID: "listClusterSources", Summary: "List sources of cluster scoped results", Tag: TagV1, Response: []string{}},
+	{Path: "/v1/cluster-resources/report-labels", OperationID: "listClusterReportLabels", Summary: "List labels of ClusterPolicyReports", Tag: TagV1, Parameters: reportFilterParameters, Response: map[string][]string{}},
+	{Path: "/v1/cluster-resources/status-counts", OperationID: "getClusterStatusCounts", Summary: "Count cluster scoped results per status", Tag: TagV1, Parameters: filterParameters, Response: []v1.StatusCount{}},
+	{Path: "/v1/cluster-resources/results", OperationID: "listClusterResults", Summary: "List cluster scoped results", Tag: TagV1, Parameters: join(filterParameters, paginationParameters), Response: v1.ResultList{}},
+
+	{Path: "/v1/compliance-scores", OperationID: "getComplianceScores", Summary: "Calculate the compliance scores of the cluster, each namespace and each policy category, requires complianceScore.enabled", Tag: TagV1, Parameters: filterParameters, Response: score.Scores{}},
+	{Path: "/v1/result-exclusions", OperationID: "listResultExclusions", Summary: "List active ResultExclusions with the fail, warn and error results suppressed by each, requires resultExclusions.enabled", Tag: TagV1, Response: []v1.ResultExclusion{}},
+
+	{Path: "/v1/coverage", OperationID: "getCoverage", Summary: "Count the workloads of the scanned kinds without results per source, requires coverage.enabled", Tag: TagV1, Parameters: []Parameter{
+		{Name: "details", Description: "include the workloads without results", Type: "boolean"},
+	}, Response: coverage.Report{}},
+	{Path: "/v1/audit/notifications", OperationID: "listNotifications", Summary: "List the notifications sent to the targets, newest first, requires audit.enabled", Tag: TagV1, Parameters: []Parameter{
+		{Name: "target", Description: "filter by target name", Type: "string"},
+		{Name: "resultId", Description: "filter by result ID", Type: "string"},
+		{Name: "status", Description: "filter by delivery status", Type: "string", Enum: []string{"success", "failed"}},
+		{Name: "since", Description: "RFC3339 timestamp of the oldest notification", Type: "string"},
+		{Name: "until", Description: "RFC3339 timestamp of the newest notification", Type: "string"},
+		{Name: "limit", Description: "maximum of notifications, defaults to 100", Type: "integer"},
+	}, Response: []audit.Entry{}},
+	{Method: "POST", Path: "/v1/acknowledgments", OperationID: "acknowledgeResults", Summary: "Acknowledge the receipt of results delivered to a target by a downstream system, requires acknowledgments.enabled", Tag: TagV1, Parameters: []Parameter{
+		{Name: "target", Description: "name of the target", Type: "string"},
+		{Name: "resultIds", Description: "IDs of the received results", Type: "string", Array: true},
+	}, Response: acknowledgment.Response{}},
+	{Path: "/v1/acknowledgments/unacknowledged", OperationID: "getUnacknowledged", Summary: "Report the deliveries not acknowledged within the threshold, oldest first, requires acknowledgments.enabled", Tag: TagV1, Parameters: []Parameter{
+		{Name: "target", Description: "filter by target name", Type: "string"},
+	}, Response: acknowledgment.Report{}},
+
+	{Path: "/v1/history/status-counts", OperationID: "getStatusHistory", Summary: "Count results per status and namespace for each interval", Tag: TagHistory, Parameters: join(filterParameters, historyParameters), Response: []v1.StatusHistory{}},
+	{Path: "/v1/history/result-transitions", OperationID: "listResultTransitions", Summary: "List status transitions of results", Tag: TagHistory, Parameters: join(filterParameters, historyParameters, paginationParameters), Response: []v1.ResultTransition{}},
+
+	{Path: "/v2/policy-reports", OperationID: "listPolicyReportPage", Summary: "List PolicyReports with cursor pagination", Tag: TagV2, Parameters: join(reportFilterParameters, cursorParameters), Response: v2.Page{}, Paginated: true},
+	{Path: "/v2/cluster-policy-reports", OperationID: "listClusterPolicyReportPage", Summary: "List ClusterPolicyReports with cursor pagination", Tag: TagV2, Parameters: join(reportFilterParameters, cursorParameters), Response: v2.Page{}, Paginated: true},
+	{Path: "/v2/reports", OperationID: "listReportPage", Summary: "List PolicyReports and ClusterPolicyReports with their labels, scope, summary and timestamps with cursor pagination, the cluster namespace selects ClusterPolicyReports", Tag: TagV2, Parameters: join(reportFilterParameters, cursorParameters), Response: v2.Page{}, Paginated: true},
+	{Path: "/v2/reports/{id}", OperationID: "getReport", Summary: "Get a PolicyReport or ClusterPolicyReport with its labels, scope, summary and timestamps", Tag: TagV2, Parameters: []Parameter{
+		{Name: "id", Type: "string", InPath: true},
+		{Name: "raw", Description: "include the complete report with its results", Type: "boolean"},
+	}, Response: v2.Report{}},
+	{Path: "/v2/namespaced-resources/results", OperationID: "listNamespacedResultPage", Summary: "List namespaced results with cursor pagination", Tag: TagV2, Parameters: join(filterParameters, cursorParameters), Response: v2.Page{}, Paginated: true},
+	{Path: "/v2/cluster-resources/results", OperationID: "listClusterResultPage", Summary: "List cluster scoped results with cursor pagination", Tag: TagV2, Parameters: join(filterParameters, cursorParameters), Response: v2.Page{}, Paginated: true},
+	{Path: "/v2/vulnerabilities", OperationID: "listVulnerabilities", Summary: "Group results with a vulnerabilityID property by CVE with the affected images, resources and fixed versions, requires sourceMappers.enabled for Trivy Operator reports", Tag: TagV2, Parameters: join(filterParameters, []Parameter{{Name: "fixable", Description: "only vulnerabilities with or without a fixed version", Type: "boolean"}}), Response: []v2.Vulnerability{}},
+	{Path: "/v2/findings/severities", OperationID: "getSeverityMatrix", Summary: "Count the findings by source and severity and by namespace and severity, counts fail, warn and error results without status filter", Tag: TagV2, Parameters: filterParameters, Response: v2.SeverityMatrix{}},
+	{Path: "/v2/results/export", OperationID: "exportResults", Summary: "Export the filtered results of PolicyReports and ClusterPolicyReports as CSV or Excel file", Tag: TagV2, Parameters: join(filterParameters, []Parameter{{Name: "format", Description: "file format, defaults to csv", Type: "string", Enum: []string{"csv", "xlsx"}}}), ContentType: "text/csv"},
+	{Path: "/v2/sarif", OperationID: "exportSARIF", Summary: "Export the results as SARIF 2.1.0 log with one run per source and the policies as rules, e.g. for GitHub code scanning", Tag: TagV2, Parameters: join(filterParameters, []Parameter{{Name: "download", Description: "respond as sarif file attachment", Type: "boolean"}}), ContentType: "application/sarif+json"},
+	{Path: "/v2/namespaces/{namespace}/diff", OperationID: "getResultDiff", Summary: "Compare the fail, warn and error results of a namespace between two points in time, requires history.enabled", Tag: TagHistory, Parameters: []Parameter{
+		{Name: "namespace", Type: "string", InPath: true},
+		{Name: "from", Description: "RFC3339 or unix timestamp, defaults to one day before to", Type: "string"},
+		{Name: "to", Description: "RFC3339 or unix timestamp, defaults to now", Type: "string"},
+	}, Response: v2.ResultDiff{}},
+	{Path: "/v2/tombstones", OperationID: "listTombstones", Summary: "List the tombstones of deleted PolicyReports and ClusterPolicyReports with their deletion time and last summary, requires tombstones.enabled", Tag: TagHistory, Parameters: join(reportFilterParameters, []Parameter{
+		{Name: "since", Description: "RFC3339 or unix timestamp, defaults to all retained tombstones", Type: "string"},
+	}), Response: []v2.Tombstone{}},
+	{Path: "/v2/namespaces/{namespace}/resources", OperationID: "listNamespaceResources", Summary: "List the resources of a namespace with the status counts of their results across all sources", Tag: TagV2, Parameters: join([]Parameter{{Name: "namespace", Type: "string", InPath: true}}, resourceFilterParameters), Response: []v2.ResourceStatus{}},
+	{Path: "/v2/resources/{kind}/{name}/results", OperationID: "getResourceResults", Summary: "Get the results of a resource across all sources with its status counts", Tag: TagV2, Parameters: join([]Parameter{
+		{Name: "kind", Type: "string", InPath: true},
+		{Name: "name", Type: "string", InPath: true},
+		{Name: "namespace", Description: "namespace of the resource, cluster scoped resources without it", Type: "string"},
+	}, resourceFilterParameters), Response: v2.ResourceResults{}},
+	{Path: "/v2/nodes", OperationID: "listNodes", Summary: "List the nodes with results, e.g. of kube-bench, with the status counts of their results across all sources", Tag: TagV2, Parameters: resourceFilterParameters, Response: []v2.ResourceStatus{}},
+	{Path: "/v2/nodes/{node}/results", OperationID: "getNodeResults", Summary: "Get the results of a node across all sources with its status counts", Tag: TagV2, Parameters: join([]Parameter{{Name: "node", Type: "string", InPath: true}}, resourceFilterParameters), Response: v2.ResourceResults{}},
+	{Path: "/v2/policies", OperationID: "listPolicyStatus", Summary: "List the policies with their status counts, the severities of their fail, warn and error results and the affected namespaces and resources", Tag: TagV2, Parameters: without(filterParameters, "status", "ids"), Response: []v2.PolicyStatus{}},
+	{Path: "/v2/policies/{policy}/results", OperationID: "getPolicyResults", Summary: "Get the results of a policy with its status counts, severities and affected namespaces", Tag: TagV2, Parameters: join([]Parameter{{Name: "policy", Type: "string", InPath: true}}, filterParameters), Response: v2.PolicyResults{}},
+	{Path: "/v2/search", OperationID: "search", Summary: "Search policies, namespaces, resources and result messages, the hits are ranked by how well they match the term", Tag: TagV2, Parameters: join([]Parameter{
+		{Name: "q", Description: "search term", Type: "string"},
+		{Name: "limit", Description: "maximum number of hits, defaults to 20", Type: "integer"},
+	}, filterParameters), Response: []v2.SearchHit{}},
+	{Path: "/v2/clusters", OperationID: "listClusterSummaries", Summary: "Aggregate the PolicyReports per federated cluster, requires federation.enabled", Tag: TagV2, Response: []federation.ClusterSummary{}},
+	{Path: "/v2/targets", OperationID: "listTargetStatus", Summary: "List the configured targets with their filters and last delivery", Tag: TagV2, Response: []v2.Target{}},
+	{Method: "POST", Path: "/v2/targets/{name}/test", OperationID: "testTarget", Summary: "Send a synthetic fail result to a target, ignoring its filters", Tag: TagV2, Parameters: []Parameter{{Name: "name", Description: "name of the target", Type: "string", InPath: true}}, Response: v2.TargetTest{}},
+	{Method: "POST", Path: "/v2/targets/replay", OperationID: "replayResults", Summary: "Send the stored results matching the filter and time range to a target again, applying its filters", Tag: TagV2, Parameters: join([]Parameter{
+		{Name: "target", Description: "name of the target", Type: "string"},
+		{Name: "from", Description: "RFC3339 or unix timestamp, defaults to all results before to", Type: "string"},
+		{Name: "to", Description: "RFC3339 or unix timestamp, defaults to now", Type: "string"},
+	}, filterParameters), Response: v2.TargetReplay{}},
+	{Method: "POST", Path: "/v2/results/{id}/triage", OperationID: "triageResult", Summary: "Set the triage status of a result and add a comment, the triage is included in the result lists and exports, requires triage.enabled", Tag: TagV2, Parameters: []Parameter{
+		{Name: "id", Description: "result ID", Type: "string", InPath: true},
+		{Name: "status", Description: "triage status, keeps the current status if empty", Type: "string", Enum: []string{"open", "acknowledged", "false-positive", "fixed"}},
+		{Name: "comment", Description: "comment added to the triage", Type: "string"},
+	}, Response: triage.Triage{}},
+	{Method: "POST", Path: "/v2/snoozes", OperationID: "snoozeResult", Summary: "Snooze the notifications of a result for a duration, snoozed results stay in the store, requires snooze.enabled", Tag: TagV2, Parameters: []Parameter{
+		{Name: "resultId", Description: "result ID", Type: "string"},
+		{Name: "duration", Description: "duration like 24h, limited by snooze.maxDuration", Type: "string"},
+		{Name: "reason", Type: "string"},
+	}, Response: snooze.Snooze{}},
+	{Path: "/v2/snoozes/events", OperationID: "listSnoozeEvents", Summary: "List the audit trail of who snoozed or unsnoozed which result, latest first, requires snooze.enabled", Tag: TagV2, Parameters: []Parameter{
+		{Name: "resultId", Description: "filter by result ID", Type: "string"},
+	}, Response: []snooze.Event{}},
+	{Method: "POST", Path: "/v2/namespaces/{namespace}/subscriptions", OperationID: "subscribeNamespace", Summary: "Subscribe an email address to the summary digest of a namespace, the subscription is pending until the link of the confirmation email is opened, requires emailReports.subscriptions.enabled", Tag: TagV2, Parameters: []Parameter{
+		{Name: "namespace", Type: "string", InPath: true},
+		{Name: "email", Description: "email address of the subscriber", Type: "string"},
+	}, Response: subscription.Subscription{}},
+	{Path: "/v2/results/stream", OperationID: "streamResults", Summary: "Stream new, updated and resolved results as Server-Sent Events or over WebSocket", Tag: TagV2, Parameters: join(filterParameters, []Parameter{{Name: "types", Type: "string", Array: true, Enum: []string{"new", "updated", "resolved"}}}), ContentType: "text/event-stream"},
+}
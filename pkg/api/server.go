@@ -2,14 +2,37 @@ package api
 
 import (
 	"context"
-	"fmt"
+	"crypto/tls"
+	"net"
 	"net/http"
 	pprof "net/http/pprof"
+	"strconv"
+	"strings"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/kyverno/policy-reporter/pkg/acknowledgment"
+	"github.com/kyverno/policy-reporter/pkg/api/auth"
+	"github.com/kyverno/policy-reporter/pkg/api/openapi"
 	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+	"github.com/kyverno/policy-reporter/pkg/audit"
+	"github.com/kyverno/policy-reporter/pkg/coverage"
+	"github.com/kyverno/policy-reporter/pkg/federation"
+	"github.com/kyverno/policy-reporter/pkg/health"
+	"github.com/kyverno/policy-reporter/pkg/ingestion"
+	"github.com/kyverno/policy-reporter/pkg/kyverno"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/maintenance"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/score"
+	"github.com/kyverno/policy-reporter/pkg/snooze"
+	"github.com/kyverno/policy-reporter/pkg/stream"
+	"github.com/kyverno/policy-reporter/pkg/subscription"
 	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/taxonomy"
+	"github.com/kyverno/policy-reporter/pkg/triage"
 )
 
 // Server for the Lifecycle and optional HTTP REST API
@@ -20,89 +43,499 @@ type Server interface {
 	Shutdown(ctx context.Context) error
 	// RegisterLifecycleHandler adds healthy and readiness APIs
 	RegisterLifecycleHandler()
+	// RegisterHealthCheck adds a component to the readiness API, critical components make Policy Reporter unavailable if they are not ok
+	RegisterHealthCheck(name string, critical bool, check health.Check)
+	// RegisterLivenessCheck adds a critical component to the liveness API, Kubernetes restarts Policy Reporter if it is not ok
+	RegisterLivenessCheck(name string, check health.Check)
+	// RegisterOpenAPIHandler adds the OpenAPI document of all REST APIs
+	RegisterOpenAPIHandler()
 	// RegisterMetricsHandler adds the optional metrics endpoint
-	RegisterMetricsHandler()
+	RegisterMetricsHandler(prometheus.Gatherer)
 	// RegisterV1Handler adds the optional v1 REST APIs
 	RegisterV1Handler(v1.PolicyReportFinder)
+	// RegisterV2Handler adds the optional v2 REST APIs with cursor pagination
+	RegisterV2Handler(v2.PolicyReportFinder)
+	// RegisterV2StreamHandler adds the optional v2 live result stream, origins are allowed to open a WebSocket besides the API host
+	RegisterV2StreamHandler(broker *stream.Broker, origins []string)
+	// RegisterV1HistoryHandler adds the optional v1 REST APIs for historical results
+	RegisterV1HistoryHandler(v1.HistoryFinder)
+	// RegisterV2HistoryHandler adds the optional v2 REST API to compare the results of a namespace between two points in time
+	// and to track the results of a resource across recreations
+	RegisterV2HistoryHandler(v2.HistoryFinder)
+	// RegisterV2TombstoneHandler adds the optional v2 REST API for the tombstones of deleted reports
+	RegisterV2TombstoneHandler(v2.TombstoneFinder)
+	// RegisterV1TaxonomyHandler adds the v1 REST API for the observed sources, categories, policies and rules
+	RegisterV1TaxonomyHandler(v1.TaxonomyFinder, *taxonomy.Taxonomy)
+	// RegisterV1ScoreHandler adds the optional v1 REST API for compliance scores
+	RegisterV1ScoreHandler(v1.ScoreFinder, score.Weights)
+	// RegisterV1ExclusionHandler adds the optional v1 REST API for ResultExclusions
+	RegisterV1ExclusionHandler(v1.ExclusionFinder, v1.PolicyReportFinder)
+	// RegisterFederationHandler adds the optional APIs to receive PolicyReports of edge instances and to aggregate them per cluster
+	RegisterFederationHandler(*federation.Receiver, v2.PolicyReportFinder, string)
+	// RegisterIngestionHandler adds the optional APIs to receive results of external policy engines
+	RegisterIngestionHandler(*ingestion.Ingester)
+	// RegisterKyvernoHandler adds the optional APIs for the Kyverno policies and PolicyExceptions joined with their results
+	RegisterKyvernoHandler(*kyverno.Catalog, v2.PolicyReportFinder)
+	// RegisterTriageHandler adds the optional API to triage results with a status and comments
+	RegisterTriageHandler(v2.PolicyReportFinder, triage.Store)
+	// RegisterSnoozeHandler adds the optional API to snooze the notifications of results
+	RegisterSnoozeHandler(*snooze.Registry)
+	// RegisterMaintenanceHandler adds the optional API to manage maintenance windows
+	RegisterMaintenanceHandler(*maintenance.Schedule)
+	// RegisterSubscriptionHandler adds the optional APIs to subscribe email addresses to namespace digests
+	RegisterSubscriptionHandler(*subscription.Manager)
 	// RegisterProfilingHandler adds the optional pprof profiling APIs
 	RegisterProfilingHandler()
+	// RegisterCoverageHandler adds the optional API for the workloads without results
+	RegisterCoverageHandler(*coverage.Scanner)
+	// RegisterAuditHandler adds the optional API to query the notifications sent to the targets
+	RegisterAuditHandler(*audit.Log)
+	// RegisterAcknowledgmentHandler adds the optional APIs to acknowledge delivered results and to report unacknowledged deliveries
+	RegisterAcknowledgmentHandler(*acknowledgment.Tracker)
+	// RegisterLoggingHandler adds the optional API to change the log levels at runtime
+	RegisterLoggingHandler(*logging.Logger)
+	// RegisterTargetSimulationHandler adds the API to evaluate a result against the filters of the configured targets
+	RegisterTargetSimulationHandler(report.Mapper)
+	// RegisterTargetReplayHandler adds the API to send stored results to a target again
+	RegisterTargetReplayHandler(v2.PolicyReportFinder, report.Mapper)
+	// RegisterResponseCache caches the responses of the aggregate APIs, has to be registered before the APIs
+	RegisterResponseCache(*ResponseCache)
+	// RegisterAdminListener serves the admin, metrics and profiling APIs on a separate address, has to be registered before the APIs
+	RegisterAdminListener(address string)
+	// RegisterTLS serves the API and admin listeners with TLS
+	RegisterTLS(*tls.Config)
+	// RegisterAccessLog logs the requests of the API and admin listeners
+	RegisterAccessLog(*AccessLog)
 }
 
 type httpServer struct {
-	http    http.Server
-	mux     *http.ServeMux
-	targets []target.Client
-	synced  func() bool
+	http      http.Server
+	mux       *http.ServeMux
+	admin     *http.Server
+	adminMux  *http.ServeMux
+	cluster   string
+	accessLog *AccessLog
+	targets   *target.Registry
+	liveness  *health.Checker
+	readiness *health.Checker
+	auth      auth.Authenticator
+	access    auth.AccessReviewer
+	cache     *ResponseCache
+	// namespaced handlers of /v2/namespaces/{namespace}/{suffix} by suffix
+	namespaced map[string]http.HandlerFunc
+}
+
+// handle registers the handler with the required authorization level, admin handlers on the admin listener if registered
+func (s *httpServer) handle(path string, level auth.Level, handler http.HandlerFunc) {
+	if level == auth.Admin {
+		s.handleAdmin(path, level, handler)
+		return
+	}
+
+	s.mux.HandleFunc(path, auth.Handler(s.auth, level, handler))
+}
+
+// handleAdmin registers the handler on the admin listener if registered, otherwise on the API listener
+func (s *httpServer) handleAdmin(path string, level auth.Level, handler http.HandlerFunc) {
+	mux := s.mux
+	if s.adminMux != nil {
+		mux = s.adminMux
+	}
+
+	mux.HandleFunc(path, auth.Handler(s.auth, level, handler))
+}
+
+// withTargets creates the handler with the currently registered targets on each request
+func (s *httpServer) withTargets(handler func([]target.Client) http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		handler(s.targets.Clients())(w, req)
+	}
+}
+
+// scoped restricts the handler to the namespaces or cluster scoped results the caller is allowed to view
+func (s *httpServer) scoped(lister auth.NamespaceLister, scope auth.Scope, handler http.HandlerFunc) http.HandlerFunc {
+	return auth.Scoped(s.access, lister, scope, handler)
+}
+
+// cached serves the responses of the aggregate handler from the response cache if registered
+func (s *httpServer) cached(endpoint string, handler http.HandlerFunc) http.HandlerFunc {
+	if s.cache == nil {
+		return handler
+	}
+
+	return s.cache.Handler(endpoint, handler)
+}
+
+// pathNamespace sets the namespace of the path as namespaces filter, so the access is reviewed for it
+func pathNamespace(extract func(string) (string, bool), next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		namespace, ok := extract(req.URL.Path)
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+
+		query := req.URL.Query()
+		query["namespaces"] = []string{namespace}
+		req.URL.RawQuery = query.Encode()
+
+		next(w, req)
+	}
+}
+
+// handleNamespaced registers the handler for /v2/namespaces/{namespace}/{suffix}, the access is reviewed for the namespace of the path
+func (s *httpServer) handleNamespaced(suffix string, level auth.Level, handler http.HandlerFunc) {
+	if len(s.namespaced) == 0 {
+		s.mux.HandleFunc("/v2/namespaces/", func(w http.ResponseWriter, req *http.Request) {
+			next, ok := s.namespaced[req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]]
+			if !ok {
+				http.NotFound(w, req)
+				return
+			}
+
+			next(w, req)
+		})
+	}
+
+	extract := func(path string) (string, bool) { return v2.NamespaceFromPath(path, suffix) }
+
+	s.namespaced[suffix] = auth.Handler(s.auth, level, pathNamespace(extract, s.scoped(nil, auth.Namespaced, handler)))
+}
+
+// queryNamespaced reviews the access for the namespace query parameter, for cluster scoped results without it
+func (s *httpServer) queryNamespaced(handler http.HandlerFunc) http.HandlerFunc {
+	cluster := s.scoped(nil, auth.Cluster, handler)
+	namespaced := s.scoped(nil, auth.Namespaced, handler)
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+
+		namespace := query.Get("namespace")
+		if namespace == "" {
+			cluster(w, req)
+			return
+		}
+
+		query["namespaces"] = []string{namespace}
+		req.URL.RawQuery = query.Encode()
+
+		namespaced(w, req)
+	}
 }
 
 func (s *httpServer) RegisterLifecycleHandler() {
-	s.mux.HandleFunc("/healthz", HealthzHandler(s.synced))
-	s.mux.HandleFunc("/ready", ReadyHandler(s.synced))
+	s.mux.HandleFunc("/healthz", HealthzHandler(s.liveness))
+	s.mux.HandleFunc("/ready", ReadyHandler(s.readiness))
+}
+
+func (s *httpServer) RegisterHealthCheck(name string, critical bool, check health.Check) {
+	s.readiness.Register(name, critical, check)
+}
+
+func (s *httpServer) RegisterLivenessCheck(name string, check health.Check) {
+	s.liveness.Register(name, true, check)
+}
+
+func (s *httpServer) RegisterOpenAPIHandler() {
+	s.mux.HandleFunc("/openapi.json", Gzip(openapi.Handler()))
 }
 
 func (s *httpServer) RegisterV1Handler(finder v1.PolicyReportFinder) {
-	s.mux.HandleFunc("/v1/targets", Gzip(v1.TargetsHandler(s.targets)))
-	s.mux.HandleFunc("/v1/categories", Gzip(v1.CategoryListHandler(finder)))
-	s.mux.HandleFunc("/v1/namespaces", Gzip(v1.NamespaceListHandler(finder)))
-	s.mux.HandleFunc("/v1/rule-status-count", Gzip(v1.RuleStatusCountHandler(finder)))
+	namespaces := func() ([]string, error) { return finder.FetchNamespaces(v1.Filter{}) }
+	// categories of namespaced and cluster scoped results include the cluster pseudo namespace
+	scopes := func() ([]string, error) {
+		list, err := namespaces()
+		return append(list, report.ClusterScope), err
+	}
+
+	s.handle("/v1/targets", auth.Admin, Gzip(s.withTargets(v1.TargetsHandler)))
+	s.handle("/v1/categories", auth.Read, s.scoped(scopes, auth.Namespaced, Gzip(v1.CategoryListHandler(finder))))
+	s.handle("/v1/namespaces", auth.Read, s.scoped(namespaces, auth.Namespaced, Gzip(v1.NamespaceListHandler(finder))))
+	s.handle("/v1/rule-status-count", auth.Read, s.scoped(nil, auth.Cluster, Gzip(s.cached("/v1/rule-status-count", v1.RuleStatusCountHandler(finder)))))
+
+	s.handle("/v1/policy-reports", auth.Read, s.scoped(namespaces, auth.Namespaced, Gzip(v1.PolicyReportListHandler(finder))))
+	s.handle("/v1/cluster-policy-reports", auth.Read, s.scoped(nil, auth.Cluster, Gzip(v1.ClusterPolicyReportListHandler(finder))))
 
-	s.mux.HandleFunc("/v1/policy-reports", Gzip(v1.PolicyReportListHandler(finder)))
-	s.mux.HandleFunc("/v1/cluster-policy-reports", Gzip(v1.ClusterPolicyReportListHandler(finder)))
+	s.handle("/v1/namespaced-resources/policies", auth.Read, s.scoped(namespaces, auth.Namespaced, Gzip(v1.NamespacedResourcesPolicyListHandler(finder))))
+	s.handle("/v1/namespaced-resources/rules", auth.Read, s.scoped(namespaces, auth.Namespaced, Gzip(v1.NamespacedResourcesRuleListHandler(finder))))
+	s.handle("/v1/namespaced-resources/kinds", auth.Read, s.scoped(namespaces, auth.Namespaced, Gzip(v1.NamespacedResourcesKindListHandler(finder))))
+	s.handle("/v1/namespaced-resources/resources", auth.Read, s.scoped(namespaces, auth.Namespaced, Gzip(v1.NamespacedResourcesListHandler(finder))))
+	s.handle("/v1/namespaced-resources/sources", auth.Read, s.scoped(namespaces, auth.Namespaced, Gzip(v1.NamespacedSourceListHandler(finder))))
+	s.handle("/v1/namespaced-resources/report-labels", auth.Read, s.scoped(namespaces, auth.Namespaced, Gzip(v1.NamespacedReportLabelListHandler(finder))))
+	s.handle("/v1/namespaced-resources/status-counts", auth.Read, s.scoped(namespaces, auth.Namespaced, Gzip(s.cached("/v1/namespaced-resources/status-counts", v1.NamespacedResourcesStatusCountsHandler(finder)))))
+	s.handle("/v1/namespaced-resources/results", auth.Read, s.scoped(namespaces, auth.Namespaced, Gzip(v1.NamespacedResourcesResultHandler(finder))))
 
-	s.mux.HandleFunc("/v1/namespaced-resources/policies", Gzip(v1.NamespacedResourcesPolicyListHandler(finder)))
-	s.mux.HandleFunc("/v1/namespaced-resources/rules", Gzip(v1.NamespacedResourcesRuleListHandler(finder)))
-	s.mux.HandleFunc("/v1/namespaced-resources/kinds", Gzip(v1.NamespacedResourcesKindListHandler(finder)))
-	s.mux.HandleFunc("/v1/namespaced-resources/resources", Gzip(v1.NamespacedResourcesListHandler(finder)))
-	s.mux.HandleFunc("/v1/namespaced-resources/sources", Gzip(v1.NamespacedSourceListHandler(finder)))
-	s.mux.HandleFunc("/v1/namespaced-resources/report-labels", Gzip(v1.NamespacedReportLabelListHandler(finder)))
-	s.mux.HandleFunc("/v1/namespaced-resources/status-counts", Gzip(v1.NamespacedResourcesStatusCountsHandler(finder)))
-	s.mux.HandleFunc("/v1/namespaced-resources/results", Gzip(v1.NamespacedResourcesResultHandler(finder)))
+	s.handle("/v1/cluster-resources/policies", auth.Read, s.scoped(nil, auth.Cluster, Gzip(v1.ClusterResourcesPolicyListHandler(finder))))
+	s.handle("/v1/cluster-resources/rules", auth.Read, s.scoped(nil, auth.Cluster, Gzip(v1.ClusterResourcesRuleListHandler(finder))))
+	s.handle("/v1/cluster-resources/kinds", auth.Read, s.scoped(nil, auth.Cluster, Gzip(v1.ClusterResourcesKindListHandler(finder))))
+	s.handle("/v1/cluster-resources/resources", auth.Read, s.scoped(nil, auth.Cluster, Gzip(v1.ClusterResourcesListHandler(finder))))
+	s.handle("/v1/cluster-resources/sources", auth.Read, s.scoped(nil, auth.Cluster, Gzip(v1.ClusterResourcesSourceListHandler(finder))))
+	s.handle("/v1/cluster-resources/report-labels", auth.Read, s.scoped(nil, auth.Cluster, Gzip(v1.ClusterReportLabelListHandler(finder))))
+	s.handle("/v1/cluster-resources/status-counts", auth.Read, s.scoped(nil, auth.Cluster, Gzip(s.cached("/v1/cluster-resources/status-counts", v1.ClusterResourcesStatusCountHandler(finder)))))
+	s.handle("/v1/cluster-resources/results", auth.Read, s.scoped(nil, auth.Cluster, Gzip(v1.ClusterResourcesResultHandler(finder))))
+}
+
+func (s *httpServer) RegisterV2Handler(finder v2.PolicyReportFinder) {
+	namespaces := func() ([]string, error) { return finder.FetchNamespaces(v1.Filter{}) }
+	// scopes include the cluster pseudo namespace for endpoints of PolicyReports and ClusterPolicyReports
+	scopes := func() ([]string, error) {
+		list, err := namespaces()
+		return append(list, report.ClusterScope), err
+	}
 
-	s.mux.HandleFunc("/v1/cluster-resources/policies", Gzip(v1.ClusterResourcesPolicyListHandler(finder)))
-	s.mux.HandleFunc("/v1/cluster-resources/rules", Gzip(v1.ClusterResourcesRuleListHandler(finder)))
-	s.mux.HandleFunc("/v1/cluster-resources/kinds", Gzip(v1.ClusterResourcesKindListHandler(finder)))
-	s.mux.HandleFunc("/v1/cluster-resources/resources", Gzip(v1.ClusterResourcesListHandler(finder)))
-	s.mux.HandleFunc("/v1/cluster-resources/sources", Gzip(v1.ClusterResourcesSourceListHandler(finder)))
-	s.mux.HandleFunc("/v1/cluster-resources/report-labels", Gzip(v1.ClusterReportLabelListHandler(finder)))
-	s.mux.HandleFunc("/v1/cluster-resources/status-counts", Gzip(v1.ClusterResourcesStatusCountHandler(finder)))
-	s.mux.HandleFunc("/v1/cluster-resources/results", Gzip(v1.ClusterResourcesResultHandler(finder)))
+	s.handle("/v2/policy-reports", auth.Read, s.scoped(namespaces, auth.Namespaced, Gzip(v2.PolicyReportListHandler(finder))))
+	s.handle("/v2/cluster-policy-reports", auth.Read, s.scoped(nil, auth.Cluster, Gzip(v2.ClusterPolicyReportListHandler(finder))))
+	s.handle("/v2/reports", auth.Read, s.scoped(scopes, auth.Namespaced, Gzip(v2.ReportListHandler(finder))))
+	s.handle("/v2/reports/", auth.Read, s.scoped(scopes, auth.Namespaced, Gzip(v2.ReportHandler(finder))))
+	s.handle("/v2/namespaced-resources/results", auth.Read, s.scoped(namespaces, auth.Namespaced, Gzip(v2.NamespacedResourcesResultHandler(finder))))
+	s.handle("/v2/cluster-resources/results", auth.Read, s.scoped(nil, auth.Cluster, Gzip(v2.ClusterResourcesResultHandler(finder))))
+	s.handle("/v2/vulnerabilities", auth.Read, s.scoped(namespaces, auth.Namespaced, Gzip(s.cached("/v2/vulnerabilities", v2.VulnerabilityHandler(finder)))))
+	s.handle("/v2/findings/severities", auth.Read, s.scoped(namespaces, auth.Namespaced, Gzip(s.cached("/v2/findings/severities", v2.SeverityMatrixHandler(finder)))))
+	s.handle("/v2/results/export", auth.Read, s.scoped(namespaces, auth.Namespaced, Gzip(v2.ResultExportHandler(finder))))
+	s.handle("/v2/sarif", auth.Read, s.scoped(namespaces, auth.Namespaced, Gzip(v2.SARIFHandler(finder))))
+	s.handle("/v2/targets", auth.Admin, Gzip(s.withTargets(v2.TargetsHandler)))
+	s.handle("/v2/targets/", auth.Admin, s.withTargets(v2.TargetTestHandler))
+	s.handle("/v2/resources/", auth.Read, s.queryNamespaced(Gzip(v2.ResourceResultsHandler(finder))))
+	s.handleNamespaced("resources", auth.Read, Gzip(v2.NamespaceResourcesHandler(finder)))
+	s.handle("/v2/nodes", auth.Read, s.scoped(nil, auth.Cluster, Gzip(v2.NodeListHandler(finder))))
+	s.handle("/v2/nodes/", auth.Read, s.scoped(nil, auth.Cluster, Gzip(v2.NodeResultsHandler(finder))))
+	s.handle("/v2/policies", auth.Read, s.scoped(namespaces, auth.Namespaced, Gzip(s.cached("/v2/policies", v2.PolicyListHandler(finder)))))
+	s.handle("/v2/policies/", auth.Read, s.scoped(namespaces, auth.Namespaced, Gzip(v2.PolicyResultsHandler(finder))))
+	s.handle("/v2/search", auth.Read, s.scoped(namespaces, auth.Namespaced, Gzip(v2.SearchHandler(finder))))
 }
 
-func (s *httpServer) RegisterMetricsHandler() {
-	s.mux.Handle("/metrics", promhttp.Handler())
+func (s *httpServer) RegisterV2StreamHandler(broker *stream.Broker, origins []string) {
+	s.handle("/v2/results/stream", auth.Read, s.scoped(broker.Namespaces, auth.Namespaced, v2.ResultStreamHandler(broker, origins)))
+}
+
+func (s *httpServer) RegisterV1ScoreHandler(finder v1.ScoreFinder, weights score.Weights) {
+	s.handle("/v1/compliance-scores", auth.Read, s.scoped(nil, auth.Cluster, Gzip(s.cached("/v1/compliance-scores", v1.ComplianceScoreHandler(finder, weights)))))
+}
+
+func (s *httpServer) RegisterV1TaxonomyHandler(finder v1.TaxonomyFinder, t *taxonomy.Taxonomy) {
+	s.handle("/v1/taxonomy", auth.Read, s.scoped(nil, auth.Cluster, Gzip(s.cached("/v1/taxonomy", v1.TaxonomyHandler(finder, t)))))
+}
+
+func (s *httpServer) RegisterV1ExclusionHandler(exclusions v1.ExclusionFinder, finder v1.PolicyReportFinder) {
+	s.handle("/v1/result-exclusions", auth.Read, s.scoped(nil, auth.Cluster, Gzip(v1.ResultExclusionHandler(exclusions, finder))))
+}
+
+func (s *httpServer) RegisterV1HistoryHandler(finder v1.HistoryFinder) {
+	s.handle("/v1/history/status-counts", auth.Read, s.scoped(nil, auth.Cluster, Gzip(v1.StatusHistoryHandler(finder))))
+	s.handle("/v1/history/result-transitions", auth.Read, s.scoped(nil, auth.Cluster, Gzip(v1.ResultTransitionHandler(finder))))
+}
+
+func (s *httpServer) RegisterV2HistoryHandler(finder v2.HistoryFinder) {
+	s.handleNamespaced("diff", auth.Read, Gzip(v2.ResultDiffHandler(finder)))
+	s.handle("/v2/history/resources", auth.Read, s.queryNamespaced(Gzip(v2.ResourceHistoryHandler(finder))))
+}
+
+func (s *httpServer) RegisterV2TombstoneHandler(finder v2.TombstoneFinder) {
+	s.handle("/v2/tombstones", auth.Read, s.scoped(nil, auth.Cluster, Gzip(v2.TombstoneListHandler(finder))))
+}
+
+func (s *httpServer) RegisterFederationHandler(receiver *federation.Receiver, finder v2.PolicyReportFinder, local string) {
+	handler := receiver.Handler()
+	if s.cache != nil {
+		handler = invalidating(s.cache, handler)
+	}
+
+	s.handle(federation.ReportsPath, auth.Admin, handler)
+	s.handle("/v2/clusters", auth.Read, s.scoped(nil, auth.Cluster, Gzip(s.cached("/v2/clusters", v2.ClusterSummaryHandler(finder, local)))))
+}
+
+func (s *httpServer) RegisterIngestionHandler(ingester *ingestion.Ingester) {
+	s.handle(ingestion.ResultsPath, auth.Admin, ingester.Handler())
+	s.handle(ingestion.SARIFPath, auth.Admin, ingester.SARIFHandler())
+	s.handle(ingestion.FalcoPath, auth.Admin, ingester.FalcoHandler())
+	s.handle(ingestion.NDJSONPath, auth.Admin, ingester.NDJSONHandler())
+}
+
+func (s *httpServer) RegisterResponseCache(cache *ResponseCache) {
+	s.cache = cache
+}
+
+func (s *httpServer) RegisterTargetSimulationHandler(mapper report.Mapper) {
+	s.handle("/v2/targets/simulate", auth.Admin, s.withTargets(v2.TargetSimulationHandler(mapper)))
+}
+
+func (s *httpServer) RegisterTargetReplayHandler(finder v2.PolicyReportFinder, mapper report.Mapper) {
+	s.handle("/v2/targets/replay", auth.Admin, s.withTargets(v2.TargetReplayHandler(finder, mapper)))
+}
+
+func (s *httpServer) RegisterSubscriptionHandler(manager *subscription.Manager) {
+	s.handleNamespaced(subscription.Path, auth.Read, manager.Handler(func(path string) (string, bool) {
+		return v2.NamespaceFromPath(path, subscription.Path)
+	}))
+
+	// the links of the emails authenticate with the token of the subscription
+	s.handle(subscription.ConfirmPath, auth.Public, manager.ConfirmHandler())
+	s.handle(subscription.UnsubscribePath, auth.Public, manager.UnsubscribeHandler())
+}
+
+func (s *httpServer) RegisterKyvernoHandler(catalog *kyverno.Catalog, finder v2.PolicyReportFinder) {
+	namespaces := func() ([]string, error) { return finder.FetchNamespaces(v1.Filter{}) }
+
+	s.handle("/v2/kyverno/policies", auth.Read, s.scoped(namespaces, auth.Namespaced, Gzip(v2.KyvernoPolicyHandler(catalog, finder))))
+	s.handle("/v2/kyverno/exceptions", auth.Read, s.scoped(namespaces, auth.Namespaced, Gzip(v2.KyvernoExceptionHandler(catalog, finder))))
+}
+
+func (s *httpServer) RegisterTriageHandler(finder v2.PolicyReportFinder, store triage.Store) {
+	// scopes include the cluster pseudo namespace, so cluster scoped results can be triaged with access to cluster scoped results
+	scopes := func() ([]string, error) {
+		list, err := finder.FetchNamespaces(v1.Filter{})
+		return append(list, report.ClusterScope), err
+	}
+
+	s.handle("/v2/results/", auth.Read, s.scoped(scopes, auth.Namespaced, v2.ResultTriageHandler(finder, store)))
+}
+
+func (s *httpServer) RegisterSnoozeHandler(registry *snooze.Registry) {
+	s.handle(snooze.Path, auth.Admin, registry.Handler())
+	s.handle(snooze.EventsPath, auth.Admin, Gzip(registry.EventsHandler()))
+}
+
+func (s *httpServer) RegisterMaintenanceHandler(schedule *maintenance.Schedule) {
+	s.handle(maintenance.WindowsPath, auth.Admin, schedule.Handler())
+}
+
+func (s *httpServer) RegisterMetricsHandler(gatherer prometheus.Gatherer) {
+	// OpenMetrics is negotiated by the Accept header and required to expose exemplars
+	handler := promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer, promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{EnableOpenMetrics: true}))
+
+	s.handleAdmin("/metrics", auth.Read, handler.ServeHTTP)
 }
 
 func (s *httpServer) RegisterProfilingHandler() {
-	s.mux.HandleFunc("/debug/pprof/", pprof.Index)
-	s.mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
-	s.mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
-	s.mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
-	s.mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	s.handle("/debug/pprof/", auth.Admin, pprof.Index)
+	s.handle("/debug/pprof/cmdline", auth.Admin, pprof.Cmdline)
+	s.handle("/debug/pprof/profile", auth.Admin, pprof.Profile)
+	s.handle("/debug/pprof/symbol", auth.Admin, pprof.Symbol)
+	s.handle("/debug/pprof/trace", auth.Admin, pprof.Trace)
+}
+
+func (s *httpServer) RegisterCoverageHandler(scanner *coverage.Scanner) {
+	s.handle(coverage.Path, auth.Read, s.scoped(scanner.Namespaces, auth.Namespaced, scanner.Handler()))
+}
+
+func (s *httpServer) RegisterAuditHandler(log *audit.Log) {
+	s.handle(audit.NotificationsPath, auth.Admin, log.Handler())
+}
+
+func (s *httpServer) RegisterAcknowledgmentHandler(tracker *acknowledgment.Tracker) {
+	s.handle(acknowledgment.AcknowledgePath, auth.Admin, tracker.AcknowledgeHandler())
+	s.handle(acknowledgment.UnacknowledgedPath, auth.Read, tracker.UnacknowledgedHandler())
 }
 
+func (s *httpServer) RegisterLoggingHandler(logger *logging.Logger) {
+	s.handle(logging.LevelsPath, auth.Admin, logger.Handler())
+}
+
+func (s *httpServer) RegisterAdminListener(address string) {
+	s.adminMux = http.NewServeMux()
+	s.admin = &http.Server{
+		Addr:      address,
+		Handler:   withClusterHeader(s.cluster, s.adminMux),
+		TLSConfig: s.http.TLSConfig,
+	}
+
+	if s.accessLog != nil {
+		s.admin.Handler = s.accessLog.Handler(s.admin.Handler)
+	}
+}
+
+func (s *httpServer) RegisterAccessLog(accessLog *AccessLog) {
+	s.accessLog = accessLog
+	s.http.Handler = accessLog.Handler(s.http.Handler)
+	if s.admin != nil {
+		s.admin.Handler = accessLog.Handler(s.admin.Handler)
+	}
+}
+
+func (s *httpServer) RegisterTLS(config *tls.Config) {
+	s.http.TLSConfig = config
+	if s.admin != nil {
+		s.admin.TLSConfig = config
+	}
+}
+
+// Start the API and the optional admin listener, returns the error of the first stopped listener
 func (s *httpServer) Start() error {
-	return s.http.ListenAndServe()
+	if s.admin == nil {
+		return serve(&s.http)
+	}
+
+	errs := make(chan error, 2)
+	go func() { errs <- serve(s.admin) }()
+	go func() { errs <- serve(&s.http) }()
+
+	return <-errs
+}
+
+// serve with TLS if configured, the certificate is provided by the TLS config
+func serve(server *http.Server) error {
+	if server.TLSConfig != nil {
+		return server.ListenAndServeTLS("", "")
+	}
+
+	return server.ListenAndServe()
 }
 
 func (s *httpServer) Shutdown(ctx context.Context) error {
-	return s.http.Shutdown(ctx)
+	if s.admin == nil {
+		return s.http.Shutdown(ctx)
+	}
+
+	adminErr := s.admin.Shutdown(ctx)
+	if err := s.http.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	return adminErr
+}
+
+// Address to listen on, an empty host listens on all IPv4 and IPv6 interfaces and IPv6 hosts are enclosed in brackets
+func Address(host string, port int) string {
+	return net.JoinHostPort(strings.Trim(host, "[]"), strconv.Itoa(port))
+}
+
+// ClusterHeader contains the configured cluster name in each response
+const ClusterHeader = "X-Cluster-Name"
+
+func withClusterHeader(cluster string, handler http.Handler) http.Handler {
+	if cluster == "" {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set(ClusterHeader, cluster)
+		handler.ServeHTTP(w, req)
+	})
 }
 
-// NewServer constructor for a new API Server
-func NewServer(targets []target.Client, port int, synced func() bool) Server {
+// NewServer constructor for a new API Server, all endpoints except lifecycle and OpenAPI require authentication if an Authenticator is given,
+// the AccessReviewer optionally restricts the results to the namespaces the caller is allowed to view.
+// Each response contains the optional cluster name in the X-Cluster-Name header
+func NewServer(targets *target.Registry, address string, cluster string, synced func() bool, authenticator auth.Authenticator, reviewer auth.AccessReviewer) Server {
 	mux := http.NewServeMux()
 
 	s := &httpServer{
-		targets: targets,
-		synced:  synced,
-		mux:     mux,
+		targets:    targets,
+		liveness:   health.NewChecker().Register("informers", true, health.Synced(synced)),
+		readiness:  health.NewChecker().Register("informers", true, health.Synced(synced)),
+		auth:       authenticator,
+		access:     reviewer,
+		mux:        mux,
+		cluster:    cluster,
+		namespaced: make(map[string]http.HandlerFunc),
 		http: http.Server{
-			Addr:    fmt.Sprintf(":%d", port),
-			Handler: mux,
+			Addr:    address,
+			Handler: withClusterHeader(cluster, mux),
 		},
 	}
 
 	s.RegisterLifecycleHandler()
+	s.RegisterOpenAPIHandler()
 
 	return s
 }
@@ -8,7 +8,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/kyverno/policy-reporter/pkg/api"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/report"
 	"github.com/kyverno/policy-reporter/pkg/target"
 )
 
@@ -20,11 +24,19 @@ func Test_NewServer(t *testing.T) {
 
 	port := int(rnd * 10000)
 
-	server := api.NewServer(make([]target.Client, 0), port, func() bool { return true })
+	server := api.NewServer(target.NewRegistry(make([]target.Client, 0)), api.Address("", port), "test-cluster", func() bool { return true }, nil, nil)
 
-	server.RegisterMetricsHandler()
+	server.RegisterMetricsHandler(prometheus.DefaultGatherer)
 	server.RegisterV1Handler(nil)
+	server.RegisterV2Handler(nil)
+	server.RegisterV1TaxonomyHandler(nil, nil)
+	server.RegisterV1HistoryHandler(nil)
+	server.RegisterV2HistoryHandler(nil)
+	server.RegisterV2TombstoneHandler(nil)
 	server.RegisterProfilingHandler()
+	server.RegisterLoggingHandler(&logging.Logger{})
+	server.RegisterTargetSimulationHandler(report.NewMapper(nil))
+	server.RegisterTargetReplayHandler(nil, report.NewMapper(nil))
 
 	serviceRunning := make(chan struct{})
 	serviceDone := make(chan struct{})
@@ -60,6 +72,61 @@ func Test_NewServer(t *testing.T) {
 	if res.StatusCode != http.StatusOK {
 		t.Errorf("Unexpected Error Code: %d", res.StatusCode)
 	}
+	if cluster := res.Header.Get(api.ClusterHeader); cluster != "test-cluster" {
+		t.Errorf("Unexpected cluster header: %s", cluster)
+	}
 
 	<-serviceDone
 }
+
+func Test_AdminListener(t *testing.T) {
+	port := 20000 + rand.New(rand.NewSource(time.Now().UnixNano())).Intn(10000)
+
+	server := api.NewServer(target.NewRegistry(make([]target.Client, 0)), api.Address("", port), "", func() bool { return true }, nil, nil)
+	server.RegisterAdminListener(api.Address("::1", port+1))
+	server.RegisterMetricsHandler(prometheus.DefaultGatherer)
+	server.RegisterLoggingHandler(&logging.Logger{})
+
+	serviceDone := make(chan struct{})
+	go func() {
+		defer close(serviceDone)
+		server.Start()
+	}()
+	defer func() {
+		server.Shutdown(context.Background())
+		<-serviceDone
+	}()
+
+	get := func(url string) int {
+		for i := 0; i < 20; i++ {
+			res, err := http.Get(url)
+			if err == nil {
+				res.Body.Close()
+				return res.StatusCode
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+
+		return 0
+	}
+
+	if code := get(fmt.Sprintf("http://localhost:%d/ready", port)); code != http.StatusOK {
+		t.Errorf("expected the lifecycle API on the API listener, got %d", code)
+	}
+	if code := get(fmt.Sprintf("http://localhost:%d/metrics", port)); code != http.StatusNotFound {
+		t.Errorf("expected no metrics on the API listener, got %d", code)
+	}
+	if code := get(fmt.Sprintf("http://[::1]:%d/metrics", port+1)); code == 0 {
+		t.Skip("IPv6 loopback not available")
+	} else if code != http.StatusOK {
+		t.Errorf("expected the metrics on the admin listener, got %d", code)
+	}
+}
+
+func Test_Address(t *testing.T) {
+	for host, expected := range map[string]string{"": ":8080", "0.0.0.0": "0.0.0.0:8080", "::": "[::]:8080", "[fd00::1]": "[fd00::1]:8080"} {
+		if address := api.Address(host, 8080); address != expected {
+			t.Errorf("expected %s for host '%s', got %s", expected, host, address)
+		}
+	}
+}
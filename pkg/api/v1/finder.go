@@ -1,5 +1,13 @@
 package v1
 
+import (
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/exclusion/v1alpha1"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/score"
+)
+
 type Filter struct {
 	Kinds       []string
 	Categories  []string
@@ -10,8 +18,12 @@ type Filter struct {
 	Severities  []string
 	Status      []string
 	Resources   []string
+	IDs         []string
+	UIDs        []string
+	Teams       []string
 	ReportLabel map[string]string
 	Search      string
+	Expression  string
 }
 
 type Pagination struct {
@@ -21,6 +33,12 @@ type Pagination struct {
 	Direction string
 }
 
+type HistoryQuery struct {
+	Since    time.Time
+	Until    time.Time
+	Interval time.Duration
+}
+
 type PolicyReportFinder interface {
 	// FetchClusterPolicyReports by filter and pagination
 	FetchClusterPolicyReports(Filter, Pagination) ([]*PolicyReport, error)
@@ -43,7 +61,7 @@ type PolicyReportFinder interface {
 	// FetchClusterSources from current PolicyReportResults
 	FetchClusterSources() ([]string, error)
 	// FetchNamespacedSources from current PolicyReportResults with a Namespace
-	FetchNamespacedSources() ([]string, error)
+	FetchNamespacedSources(Filter) ([]string, error)
 	// FetchNamespacedKinds from current PolicyReportResults with a Namespace
 	FetchNamespacedKinds(Filter) ([]string, error)
 	// FetchNamespacedResources from current PolicyReportResults with a Namespace
@@ -73,3 +91,27 @@ type PolicyReportFinder interface {
 	// FetchNamespacedReportLabels from PolicyReports
 	FetchNamespacedReportLabels(Filter) (map[string][]string, error)
 }
+
+type ScoreFinder interface {
+	// FetchScoreCounts of current PolicyReportResults by namespace, category, severity and status
+	FetchScoreCounts(Filter) ([]score.Count, error)
+}
+
+type HistoryFinder interface {
+	// FetchStatusHistory of the result counts per namespace and status for each interval of the query
+	FetchStatusHistory(Filter, HistoryQuery) ([]*StatusHistory, error)
+	// FetchResultTransitions of PolicyReportResults within the query
+	FetchResultTransitions(Filter, HistoryQuery, Pagination) ([]*ResultTransition, error)
+}
+
+type TaxonomyFinder interface {
+	// FetchTaxonomy counts the current PolicyReportResults per source, category, policy and rule
+	FetchTaxonomy(Filter) (*Taxonomy, error)
+}
+
+type ExclusionFinder interface {
+	// Active ResultExclusions which are not expired
+	Active() []*v1alpha1.ResultExclusion
+	// Match returns the first active ResultExclusion of the result
+	Match(v1alpha2.ReportInterface, v1alpha2.PolicyReportResult) (*v1alpha1.ResultExclusion, bool)
+}
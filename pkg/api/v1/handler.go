@@ -2,11 +2,19 @@ package v1
 
 import (
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
 	"github.com/kyverno/policy-reporter/pkg/helper"
+	"github.com/kyverno/policy-reporter/pkg/query"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/score"
 	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/taxonomy"
+	"github.com/kyverno/policy-reporter/pkg/validate"
 )
 
 var defaultOrder = []string{"resource_namespace", "resource_name", "resource_uid", "policy", "rule", "message"}
@@ -26,7 +34,7 @@ func TargetsHandler(targets []target.Client) http.HandlerFunc {
 // PolicyReportListHandler REST API
 func PolicyReportListHandler(finder PolicyReportFinder) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		filter := buildFilter(req)
+		filter := BuildFilter(req)
 		count, _ := finder.CountPolicyReports(filter)
 		list, err := finder.FetchPolicyReports(filter, buildPaginatiomn(req, []string{"namespace", "name"}))
 		helper.SendJSONResponse(w, PolicyReportList{Items: list, Count: count}, err)
@@ -36,7 +44,7 @@ func PolicyReportListHandler(finder PolicyReportFinder) http.HandlerFunc {
 // PolicyReportListHandler REST API
 func ClusterPolicyReportListHandler(finder PolicyReportFinder) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		filter := buildFilter(req)
+		filter := BuildFilter(req)
 		count, _ := finder.CountClusterPolicyReports(filter)
 		list, err := finder.FetchClusterPolicyReports(filter, buildPaginatiomn(req, []string{"namespace", "name"}))
 		helper.SendJSONResponse(w, PolicyReportList{Items: list, Count: count}, err)
@@ -46,7 +54,7 @@ func ClusterPolicyReportListHandler(finder PolicyReportFinder) http.HandlerFunc
 // ClusterResourcesPolicyListHandler REST API
 func ClusterResourcesPolicyListHandler(finder PolicyReportFinder) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		list, err := finder.FetchClusterPolicies(buildFilter(req))
+		list, err := finder.FetchClusterPolicies(BuildFilter(req))
 		helper.SendJSONResponse(w, list, err)
 	}
 }
@@ -54,7 +62,7 @@ func ClusterResourcesPolicyListHandler(finder PolicyReportFinder) http.HandlerFu
 // ClusterResourcesRuleListHandler REST API
 func ClusterResourcesRuleListHandler(finder PolicyReportFinder) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		list, err := finder.FetchClusterRules(buildFilter(req))
+		list, err := finder.FetchClusterRules(BuildFilter(req))
 		helper.SendJSONResponse(w, list, err)
 	}
 }
@@ -62,7 +70,7 @@ func ClusterResourcesRuleListHandler(finder PolicyReportFinder) http.HandlerFunc
 // NamespacedResourcesPolicyListHandler REST API
 func NamespacedResourcesPolicyListHandler(finder PolicyReportFinder) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		list, err := finder.FetchNamespacedPolicies(buildFilter(req))
+		list, err := finder.FetchNamespacedPolicies(BuildFilter(req))
 		helper.SendJSONResponse(w, list, err)
 	}
 }
@@ -70,7 +78,7 @@ func NamespacedResourcesPolicyListHandler(finder PolicyReportFinder) http.Handle
 // NamespacedResourcesRuleListHandler REST API
 func NamespacedResourcesRuleListHandler(finder PolicyReportFinder) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		list, err := finder.FetchNamespacedRules(buildFilter(req))
+		list, err := finder.FetchNamespacedRules(BuildFilter(req))
 		helper.SendJSONResponse(w, list, err)
 	}
 }
@@ -78,7 +86,7 @@ func NamespacedResourcesRuleListHandler(finder PolicyReportFinder) http.HandlerF
 // CategoryListHandler REST API
 func CategoryListHandler(finder PolicyReportFinder) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		list, err := finder.FetchCategories(buildFilter(req))
+		list, err := finder.FetchCategories(BuildFilter(req))
 		helper.SendJSONResponse(w, list, err)
 	}
 }
@@ -86,7 +94,7 @@ func CategoryListHandler(finder PolicyReportFinder) http.HandlerFunc {
 // ClusterResourcesKindListHandler REST API
 func ClusterResourcesKindListHandler(finder PolicyReportFinder) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		list, err := finder.FetchClusterKinds(buildFilter(req))
+		list, err := finder.FetchClusterKinds(BuildFilter(req))
 		helper.SendJSONResponse(w, list, err)
 	}
 }
@@ -94,7 +102,7 @@ func ClusterResourcesKindListHandler(finder PolicyReportFinder) http.HandlerFunc
 // NamespacedResourcesKindListHandler REST API
 func NamespacedResourcesKindListHandler(finder PolicyReportFinder) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		list, err := finder.FetchNamespacedKinds(buildFilter(req))
+		list, err := finder.FetchNamespacedKinds(BuildFilter(req))
 		helper.SendJSONResponse(w, list, err)
 	}
 }
@@ -102,7 +110,7 @@ func NamespacedResourcesKindListHandler(finder PolicyReportFinder) http.HandlerF
 // ClusterResourcesListHandler REST API
 func ClusterResourcesListHandler(finder PolicyReportFinder) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		list, err := finder.FetchClusterResources(buildFilter(req))
+		list, err := finder.FetchClusterResources(BuildFilter(req))
 		helper.SendJSONResponse(w, list, err)
 	}
 }
@@ -110,7 +118,7 @@ func ClusterResourcesListHandler(finder PolicyReportFinder) http.HandlerFunc {
 // NamespacedResourcesListHandler REST API
 func NamespacedResourcesListHandler(finder PolicyReportFinder) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		list, err := finder.FetchNamespacedResources(buildFilter(req))
+		list, err := finder.FetchNamespacedResources(BuildFilter(req))
 		helper.SendJSONResponse(w, list, err)
 	}
 }
@@ -126,7 +134,7 @@ func ClusterResourcesSourceListHandler(finder PolicyReportFinder) http.HandlerFu
 // NamespacedSourceListHandler REST API
 func NamespacedSourceListHandler(finder PolicyReportFinder) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		list, err := finder.FetchNamespacedSources()
+		list, err := finder.FetchNamespacedSources(BuildFilter(req))
 		helper.SendJSONResponse(w, list, err)
 	}
 }
@@ -134,7 +142,7 @@ func NamespacedSourceListHandler(finder PolicyReportFinder) http.HandlerFunc {
 // NamespacedReportLabelListHandler REST API
 func NamespacedReportLabelListHandler(finder PolicyReportFinder) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		list, err := finder.FetchNamespacedReportLabels(buildFilter(req))
+		list, err := finder.FetchNamespacedReportLabels(BuildFilter(req))
 		helper.SendJSONResponse(w, list, err)
 	}
 }
@@ -142,7 +150,7 @@ func NamespacedReportLabelListHandler(finder PolicyReportFinder) http.HandlerFun
 // ClusterReportLabelListHandler REST API
 func ClusterReportLabelListHandler(finder PolicyReportFinder) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		list, err := finder.FetchClusterReportLabels(buildFilter(req))
+		list, err := finder.FetchClusterReportLabels(BuildFilter(req))
 		helper.SendJSONResponse(w, list, err)
 	}
 }
@@ -150,7 +158,12 @@ func ClusterReportLabelListHandler(finder PolicyReportFinder) http.HandlerFunc {
 // ClusterResourcesStatusCountHandler REST API
 func ClusterResourcesStatusCountHandler(finder PolicyReportFinder) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		list, err := finder.FetchStatusCounts(buildFilter(req))
+		filter := BuildFilter(req)
+		if !ValidateFilter(w, filter) {
+			return
+		}
+
+		list, err := finder.FetchStatusCounts(filter)
 		helper.SendJSONResponse(w, list, err)
 	}
 }
@@ -158,7 +171,19 @@ func ClusterResourcesStatusCountHandler(finder PolicyReportFinder) http.HandlerF
 // NamespacedResourcesStatusCountsHandler REST API
 func NamespacedResourcesStatusCountsHandler(finder PolicyReportFinder) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		list, err := finder.FetchNamespacedStatusCounts(buildFilter(req))
+		filter := BuildFilter(req)
+		if !ValidateFilter(w, filter) {
+			return
+		}
+
+		list, err := finder.FetchNamespacedStatusCounts(filter)
+		if err == nil && includeClusterScope(req, filter) {
+			var counts []StatusCount
+
+			counts, err = finder.FetchStatusCounts(filter)
+			list = appendClusterScopeCounts(list, counts)
+		}
+
 		helper.SendJSONResponse(w, list, err)
 	}
 }
@@ -177,7 +202,11 @@ func RuleStatusCountHandler(finder PolicyReportFinder) http.HandlerFunc {
 // NamespacedResourcesResultHandler REST API
 func NamespacedResourcesResultHandler(finder PolicyReportFinder) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		filter := buildFilter(req)
+		filter := BuildFilter(req)
+		if !ValidateFilter(w, filter) {
+			return
+		}
+
 		count, _ := finder.CountNamespacedResults(filter)
 		list, err := finder.FetchNamespacedResults(filter, buildPaginatiomn(req, defaultOrder))
 		helper.SendJSONResponse(w, ResultList{Items: list, Count: count}, err)
@@ -187,7 +216,11 @@ func NamespacedResourcesResultHandler(finder PolicyReportFinder) http.HandlerFun
 // ClusterResourcesResultHandler REST API
 func ClusterResourcesResultHandler(finder PolicyReportFinder) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		filter := buildFilter(req)
+		filter := BuildFilter(req)
+		if !ValidateFilter(w, filter) {
+			return
+		}
+
 		count, _ := finder.CountClusterResults(filter)
 		list, err := finder.FetchClusterResults(filter, buildPaginatiomn(req, defaultOrder))
 		helper.SendJSONResponse(w, ResultList{Items: list, Count: count}, err)
@@ -197,16 +230,167 @@ func ClusterResourcesResultHandler(finder PolicyReportFinder) http.HandlerFunc {
 // NamespaceListHandler REST API
 func NamespaceListHandler(finder PolicyReportFinder) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		list, err := finder.FetchNamespaces(Filter{
+		filter := Filter{
+			Namespaces: namespaces(req),
 			Sources:    req.URL.Query()["sources"],
 			Categories: req.URL.Query()["categories"],
 			Policies:   req.URL.Query()["policies"],
 			Rules:      req.URL.Query()["rules"],
-		})
+		}
+
+		list, err := finder.FetchNamespaces(filter)
+		if err == nil && includeClusterScope(req, filter) {
+			var count int
+
+			count, err = finder.CountClusterResults(filter)
+			if count > 0 {
+				list = append(list, report.ClusterScope)
+			}
+		}
+
+		helper.SendJSONResponse(w, list, err)
+	}
+}
+
+// StatusHistoryHandler REST API
+func StatusHistoryHandler(finder HistoryFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		list, err := finder.FetchStatusHistory(BuildFilter(req), buildHistoryQuery(req))
+		helper.SendJSONResponse(w, list, err)
+	}
+}
+
+// ResultTransitionHandler REST API
+func ResultTransitionHandler(finder HistoryFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		list, err := finder.FetchResultTransitions(BuildFilter(req), buildHistoryQuery(req), buildPaginatiomn(req, []string{"timestamp"}))
 		helper.SendJSONResponse(w, list, err)
 	}
 }
 
+// ComplianceScoreHandler REST API
+func ComplianceScoreHandler(finder ScoreFinder, weights score.Weights) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		filter := BuildFilter(req)
+		if !ValidateFilter(w, filter) {
+			return
+		}
+
+		counts, err := finder.FetchScoreCounts(filter)
+		if err != nil {
+			helper.SendJSONResponse(w, nil, err)
+			return
+		}
+
+		helper.SendJSONResponse(w, score.Calculate(counts, weights), nil)
+	}
+}
+
+// TaxonomyHandler lists the observed sources, categories, policies and rules with their result counts,
+// sources and categories with the display names, groups and order of the taxonomy
+func TaxonomyHandler(finder TaxonomyFinder, t *taxonomy.Taxonomy) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		filter := BuildFilter(req)
+		if !ValidateFilter(w, filter) {
+			return
+		}
+
+		result, err := finder.FetchTaxonomy(filter)
+		if err != nil {
+			helper.SendJSONResponse(w, nil, err)
+			return
+		}
+
+		if !t.Empty() {
+			result.Sources = displayItems(result.Sources, t.Sources)
+			result.Categories = displayItems(result.Categories, t.Categories)
+		}
+
+		helper.SendJSONResponse(w, result, nil)
+	}
+}
+
+func displayItems(items []TaxonomyItem, dimension taxonomy.Dimension) []TaxonomyItem {
+	for i, item := range items {
+		items[i].DisplayName = dimension.Name(item.Name)
+		items[i].Group = dimension.Group(item.Name)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return dimension.Less(items[i].Name, items[j].Name)
+	})
+
+	return items
+}
+
+// ResultExclusionHandler lists the active ResultExclusions with the current fail, warn and error results suppressed by each
+func ResultExclusionHandler(exclusions ExclusionFinder, finder PolicyReportFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		active := exclusions.Active()
+
+		list := make([]*ResultExclusion, 0, len(active))
+		index := make(map[string]*ResultExclusion, len(active))
+		namespaces := make([]string, 0, len(active))
+
+		for _, e := range active {
+			item := mapResultExclusion(e)
+			list = append(list, item)
+			index[e.Namespace+"/"+e.Name] = item
+
+			if !helper.Contains(e.Namespace, namespaces) {
+				namespaces = append(namespaces, e.Namespace)
+			}
+		}
+
+		if len(namespaces) > 0 {
+			results, err := finder.FetchNamespacedResults(Filter{
+				Namespaces: namespaces,
+				Status:     []string{v1alpha2.StatusFail, v1alpha2.StatusWarn, v1alpha2.StatusError},
+			}, Pagination{SortBy: defaultOrder, Direction: "ASC"})
+			if err != nil {
+				helper.SendJSONResponse(w, nil, err)
+				return
+			}
+
+			for _, r := range results {
+				e, ok := exclusions.Match(nil, mapListResult(r))
+				if !ok {
+					continue
+				}
+
+				if item, ok := index[e.Namespace+"/"+e.Name]; ok {
+					item.Suppressed = append(item.Suppressed, r)
+				}
+			}
+		}
+
+		helper.SendJSONResponse(w, list, nil)
+	}
+}
+
+func buildHistoryQuery(req *http.Request) HistoryQuery {
+	until := time.Now()
+
+	return HistoryQuery{
+		Since:    until.Add(-parseDuration(req.URL.Query().Get("since"), 7*24*time.Hour)),
+		Until:    until,
+		Interval: parseDuration(req.URL.Query().Get("interval"), 24*time.Hour),
+	}
+}
+
+// parseDuration supports days in addition to the time.Duration format, e.g. 30d
+func parseDuration(value string, fallback time.Duration) time.Duration {
+	if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && strings.HasSuffix(value, "d") && days > 0 {
+		return time.Duration(days) * 24 * time.Hour
+	}
+
+	if duration, err := time.ParseDuration(value); err == nil && duration > 0 {
+		return duration
+	}
+
+	return fallback
+}
+
 func buildPaginatiomn(req *http.Request, defaultOrder []string) Pagination {
 	page, err := strconv.Atoi(req.URL.Query().Get("page"))
 	if err != nil || page < 1 {
@@ -233,7 +417,34 @@ func buildPaginatiomn(req *http.Request, defaultOrder []string) Pagination {
 	}
 }
 
-func buildFilter(req *http.Request) Filter {
+// ValidateFilter responds with 400 and returns false if the filter expression or a regex filter value can not be parsed
+func ValidateFilter(w http.ResponseWriter, filter Filter) bool {
+	for _, values := range [][]string{
+		filter.Namespaces, filter.Kinds, filter.Resources, filter.Sources, filter.Categories,
+		filter.Severities, filter.Policies, filter.Rules, filter.Status, filter.Teams,
+	} {
+		for _, value := range values {
+			if err := validate.ValidatePattern(value); err != nil {
+				helper.SendBadRequest(w, err)
+				return false
+			}
+		}
+	}
+
+	if filter.Expression == "" {
+		return true
+	}
+
+	if _, err := query.Parse(filter.Expression); err != nil {
+		helper.SendBadRequest(w, err)
+		return false
+	}
+
+	return true
+}
+
+// BuildFilter parses the common filter query parameters of the REST APIs
+func BuildFilter(req *http.Request) Filter {
 	labels := map[string]string{}
 
 	for _, label := range req.URL.Query()["labels"] {
@@ -245,8 +456,12 @@ func buildFilter(req *http.Request) Filter {
 		labels[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
 	}
 
+	if cluster := req.URL.Query().Get("cluster"); cluster != "" {
+		labels[report.ClusterLabel] = cluster
+	}
+
 	return Filter{
-		Namespaces:  req.URL.Query()["namespaces"],
+		Namespaces:  namespaces(req),
 		Kinds:       req.URL.Query()["kinds"],
 		Resources:   req.URL.Query()["resources"],
 		Sources:     req.URL.Query()["sources"],
@@ -255,7 +470,44 @@ func buildFilter(req *http.Request) Filter {
 		Policies:    req.URL.Query()["policies"],
 		Rules:       req.URL.Query()["rules"],
 		Status:      req.URL.Query()["status"],
+		IDs:         req.URL.Query()["ids"],
+		UIDs:        req.URL.Query()["uids"],
+		Teams:       req.URL.Query()["teams"],
 		ReportLabel: labels,
 		Search:      req.URL.Query().Get("search"),
+		Expression:  req.URL.Query().Get("filter"),
 	}
 }
+
+// namespaces filter of the request, the cluster scope pseudo namespace selects the cluster scoped results
+func namespaces(req *http.Request) []string {
+	list := req.URL.Query()["namespaces"]
+	for i, namespace := range list {
+		if namespace == report.ClusterScope {
+			list[i] = ""
+		}
+	}
+
+	return list
+}
+
+// includeClusterScope adds the cluster scoped results as pseudo namespace if requested and not excluded by the namespace filter
+func includeClusterScope(req *http.Request, filter Filter) bool {
+	if clusterScope, _ := strconv.ParseBool(req.URL.Query().Get("clusterScope")); !clusterScope {
+		return false
+	}
+
+	return len(filter.Namespaces) == 0 || helper.Contains("", filter.Namespaces)
+}
+
+func appendClusterScopeCounts(list []NamespacedStatusCount, counts []StatusCount) []NamespacedStatusCount {
+	for i, item := range list {
+		for _, count := range counts {
+			if count.Status == item.Status && count.Count > 0 {
+				list[i].Items = append(list[i].Items, NamespaceCount{Namespace: report.ClusterScope, Count: count.Count})
+			}
+		}
+	}
+
+	return list
+}
@@ -3,6 +3,7 @@ package v1_test
 import (
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -11,10 +12,14 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/exclusion/v1alpha1"
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/exclusion"
+	"github.com/kyverno/policy-reporter/pkg/score"
 	"github.com/kyverno/policy-reporter/pkg/sqlite3"
 	"github.com/kyverno/policy-reporter/pkg/target"
 	"github.com/kyverno/policy-reporter/pkg/target/loki"
+	"github.com/kyverno/policy-reporter/pkg/taxonomy"
 )
 
 var seconds = time.Date(2022, 9, 6, 0, 0, 0, 0, time.UTC).Unix()
@@ -121,7 +126,7 @@ var creport = &v1alpha2.ClusterPolicyReport{
 }
 
 func Test_V1_API(t *testing.T) {
-	db, err := sqlite3.NewDatabase("test.db")
+	db, err := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "test.db"))
 	if err != nil {
 		t.Error(err)
 	}
@@ -398,6 +403,32 @@ func Test_V1_API(t *testing.T) {
 		}
 	})
 
+	t.Run("NamespacedStatusCountHandler with cluster scope", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v1/namespaced-status-counts?status=pass&clusterScope=true", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := v1.NamespacedResourcesStatusCountsHandler(store)
+		handler.ServeHTTP(rr, req)
+
+		expected := `[{"status":"pass","items":[{"namespace":"test","count":1},{"namespace":"cluster","count":1}]}]`
+		if !strings.Contains(rr.Body.String(), expected) {
+			t.Errorf("handler returned unexpected body: got %v want %v", rr.Body.String(), expected)
+		}
+
+		req, _ = http.NewRequest("GET", "/v1/namespaced-status-counts?status=pass&clusterScope=true&namespaces=cluster", nil)
+
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		expected = `[{"status":"pass","items":[{"namespace":"cluster","count":1}]}]`
+		if !strings.Contains(rr.Body.String(), expected) {
+			t.Errorf("handler returned unexpected body: got %v want %v", rr.Body.String(), expected)
+		}
+	})
+
 	t.Run("RuleStatusCountHandler", func(t *testing.T) {
 		req, err := http.NewRequest("GET", "/v1/rule-status-count?policy=require-requests-and-limits-required&rule=autogen-check-for-requests-and-limits", nil)
 		if err != nil {
@@ -468,6 +499,105 @@ func Test_V1_API(t *testing.T) {
 		}
 	})
 
+	t.Run("ClusterResultHandler with invalid filter", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v1/cluster-results?filter=severity>=unknown", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := v1.ClusterResourcesResultHandler(store)
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusBadRequest {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("ComplianceScoreHandler", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v1/compliance-scores", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := v1.ComplianceScoreHandler(store, score.DefaultWeights)
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		expected := `"namespaces":[{"name":"test","score":11.11,"results":2,"violations":1}]`
+		if !strings.Contains(rr.Body.String(), expected) {
+			t.Errorf("handler returned unexpected body: got %v want %v", rr.Body.String(), expected)
+		}
+	})
+	t.Run("ComplianceScoreHandler with invalid filter", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v1/compliance-scores?filter=severity>unknown", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := v1.ComplianceScoreHandler(store, score.DefaultWeights)
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusBadRequest {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+		}
+	})
+	t.Run("TaxonomyHandler", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v1/taxonomy", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := v1.TaxonomyHandler(store, &taxonomy.Taxonomy{
+			Categories: taxonomy.Dimension{{Name: "Conventions", Match: []string{"convention*"}, Group: "Style", Order: 1}},
+		})
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		expected := `"categories":[{"name":"Convention","displayName":"Conventions","group":"Style","count":2},{"name":"Best Practices","displayName":"Best Practices","count":2}]`
+		if !strings.Contains(rr.Body.String(), expected) {
+			t.Errorf("handler returned unexpected body: got %v want %v", rr.Body.String(), expected)
+		}
+
+		expected = `{"name":"check-for-labels-on-namespace","displayName":"check-for-labels-on-namespace","group":"require-ns-labels","count":2}`
+		if !strings.Contains(rr.Body.String(), expected) {
+			t.Errorf("handler returned unexpected body: got %v want %v", rr.Body.String(), expected)
+		}
+	})
+	t.Run("ResultExclusionHandler", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v1/result-exclusions", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exclusions := exclusion.NewStore(nil)
+		exclusions.Set(&v1alpha1.ResultExclusion{
+			ObjectMeta: metav1.ObjectMeta{Name: "requests", Namespace: "test"},
+			Spec:       v1alpha1.ResultExclusionSpec{Policy: "require-requests-*", Justification: "accepted"},
+		})
+
+		rr := httptest.NewRecorder()
+		handler := v1.ResultExclusionHandler(exclusions, store)
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		expected := `[{"namespace":"test","name":"requests","policy":"require-requests-*","justification":"accepted","suppressed":[{"id":"123"`
+		if !strings.HasPrefix(rr.Body.String(), expected) {
+			t.Errorf("handler returned unexpected body: got %v want %v", rr.Body.String(), expected)
+		}
+	})
 	t.Run("NamespaceListHandler", func(t *testing.T) {
 		req, err := http.NewRequest("GET", "/v1/namespaces", nil)
 		if err != nil {
@@ -486,6 +616,16 @@ func Test_V1_API(t *testing.T) {
 		if !strings.Contains(rr.Body.String(), expected) {
 			t.Errorf("handler returned unexpected body: got %v want %v", rr.Body.String(), expected)
 		}
+
+		req, _ = http.NewRequest("GET", "/v1/namespaces?clusterScope=true", nil)
+
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		expected = `["test","cluster"]`
+		if !strings.Contains(rr.Body.String(), expected) {
+			t.Errorf("handler returned unexpected body: got %v want %v", rr.Body.String(), expected)
+		}
 	})
 
 	t.Run("ClusterReportLabelListHandler", func(t *testing.T) {
@@ -620,3 +760,57 @@ func Test_TargetsAPI(t *testing.T) {
 		}
 	})
 }
+
+func Test_V1_History_API(t *testing.T) {
+	db, err := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store, err := sqlite3.NewPolicyReportStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.EnableHistory()
+	store.Add(preport)
+
+	t.Run("StatusHistoryHandler", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v1/history/status-counts?namespaces=test&status=fail&since=2d", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := v1.StatusHistoryHandler(store)
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		expected := `"namespace":"test","status":"fail","items":[{"timestamp":`
+		if !strings.Contains(rr.Body.String(), expected) {
+			t.Errorf("handler returned unexpected body: got %v want %v", rr.Body.String(), expected)
+		}
+	})
+	t.Run("ResultTransitionHandler", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v1/history/result-transitions?namespaces=test", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := v1.ResultTransitionHandler(store)
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		expected := `"previousStatus":"","status":"fail"`
+		if !strings.Contains(rr.Body.String(), expected) {
+			t.Errorf("handler returned unexpected body: got %v want %v", rr.Body.String(), expected)
+		}
+	})
+}
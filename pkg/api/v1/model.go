@@ -1,8 +1,14 @@
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/exclusion/v1alpha1"
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fields"
 	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/triage"
 )
 
 type PolicyReport struct {
@@ -56,12 +62,22 @@ type ListResult struct {
 	Name       string            `json:"name"`
 	Message    string            `json:"message"`
 	Category   string            `json:"category,omitempty"`
+	Source     string            `json:"source,omitempty"`
 	Policy     string            `json:"policy"`
 	Rule       string            `json:"rule"`
 	Status     string            `json:"status"`
 	Severity   string            `json:"severity,omitempty"`
 	Timestamp  int               `json:"timestamp,omitempty"`
 	Properties map[string]string `json:"properties,omitempty"`
+	// Triage of the result, only set if triage is enabled and the result was triaged
+	Triage *triage.Triage `json:"triage,omitempty"`
+}
+
+// MarshalJSON adds the configured properties as typed top-level fields
+func (r ListResult) MarshalJSON() ([]byte, error) {
+	type result ListResult
+
+	return fields.Marshal(result(r), fields.Map(r.Properties))
 }
 
 // Target API Model
@@ -85,3 +101,101 @@ func mapTarget(t target.Client) Target {
 		SkipExistingOnStartup: t.SkipExistingOnStartup(),
 	}
 }
+
+type HistoryItem struct {
+	Timestamp int64 `json:"timestamp"`
+	Count     int   `json:"count"`
+}
+
+type StatusHistory struct {
+	Namespace string        `json:"namespace,omitempty"`
+	Status    string        `json:"status"`
+	Items     []HistoryItem `json:"items"`
+}
+
+type ResultTransition struct {
+	ReportID       string `json:"reportId"`
+	ResultID       string `json:"resultId"`
+	Namespace      string `json:"namespace,omitempty"`
+	Kind           string `json:"kind"`
+	Name           string `json:"name"`
+	UID            string `json:"uid,omitempty"`
+	Source         string `json:"source"`
+	Policy         string `json:"policy"`
+	Rule           string `json:"rule"`
+	PreviousStatus string `json:"previousStatus"`
+	Status         string `json:"status"`
+	Timestamp      int64  `json:"timestamp"`
+}
+
+type ExclusionResource struct {
+	Kinds    []string `json:"kinds,omitempty"`
+	Names    []string `json:"names,omitempty"`
+	Selector string   `json:"selector,omitempty"`
+}
+
+type ResultExclusion struct {
+	Namespace     string             `json:"namespace"`
+	Name          string             `json:"name"`
+	Policy        string             `json:"policy"`
+	Rule          string             `json:"rule,omitempty"`
+	Resource      *ExclusionResource `json:"resource,omitempty"`
+	ExpiresAt     *int64             `json:"expiresAt,omitempty"`
+	Justification string             `json:"justification"`
+	Suppressed    []*ListResult      `json:"suppressed"`
+}
+
+func mapResultExclusion(e *v1alpha1.ResultExclusion) *ResultExclusion {
+	item := &ResultExclusion{
+		Namespace:     e.Namespace,
+		Name:          e.Name,
+		Policy:        e.Spec.Policy,
+		Rule:          e.Spec.Rule,
+		Justification: e.Spec.Justification,
+		Suppressed:    make([]*ListResult, 0),
+	}
+
+	if e.Spec.ExpiresAt != nil {
+		expires := e.Spec.ExpiresAt.Unix()
+		item.ExpiresAt = &expires
+	}
+
+	if r := e.Spec.Resource; r != nil {
+		item.Resource = &ExclusionResource{Kinds: r.Kinds, Names: r.Names}
+		if r.Selector != nil {
+			item.Resource.Selector = metav1.FormatLabelSelector(r.Selector)
+		}
+	}
+
+	return item
+}
+
+func mapListResult(r *ListResult) v1alpha2.PolicyReportResult {
+	return v1alpha2.PolicyReportResult{
+		Policy: r.Policy,
+		Rule:   r.Rule,
+		Result: v1alpha2.PolicyResult(r.Status),
+		Resources: []corev1.ObjectReference{{
+			APIVersion: r.APIVersion,
+			Kind:       r.Kind,
+			Name:       r.Name,
+			Namespace:  r.Namespace,
+		}},
+	}
+}
+
+// TaxonomyItem observed value with its display name and the count of results
+type TaxonomyItem struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Group       string `json:"group,omitempty"`
+	Count       int    `json:"count"`
+}
+
+// Taxonomy of the observed values, rules are grouped by their policy
+type Taxonomy struct {
+	Sources    []TaxonomyItem `json:"sources"`
+	Categories []TaxonomyItem `json:"categories"`
+	Policies   []TaxonomyItem `json:"policies"`
+	Rules      []TaxonomyItem `json:"rules"`
+}
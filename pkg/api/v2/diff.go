@@ -0,0 +1,82 @@
+package v2
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/helper"
+)
+
+// defaultDiffRange is used without from parameter
+const defaultDiffRange = 24 * time.Hour
+
+// ResultDiffHandler compares the results of the namespace of the path /v2/namespaces/{namespace}/diff,
+// from and to are RFC3339 or unix timestamps, to defaults to now and from to one day before to
+func ResultDiffHandler(finder HistoryFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		namespace, ok := DiffNamespace(req.URL.Path)
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+
+		query := req.URL.Query()
+
+		to, err := parseTime(query.Get("to"), time.Now())
+		if err != nil {
+			helper.SendBadRequest(w, err)
+			return
+		}
+
+		from, err := parseTime(query.Get("from"), to.Add(-defaultDiffRange))
+		if err != nil {
+			helper.SendBadRequest(w, err)
+			return
+		}
+
+		if !from.Before(to) {
+			helper.SendBadRequest(w, fmt.Errorf("from has to be before to"))
+			return
+		}
+
+		diff, err := finder.FetchResultDiff(namespace, from, to)
+		helper.SendJSONResponse(w, diff, err)
+	}
+}
+
+// DiffNamespace extracts the namespace of the path /v2/namespaces/{namespace}/diff
+func DiffNamespace(path string) (string, bool) {
+	return NamespaceFromPath(path, "diff")
+}
+
+// NamespaceFromPath extracts the namespace of the path /v2/namespaces/{namespace}/{suffix}
+func NamespaceFromPath(path, suffix string) (string, bool) {
+	namespace := strings.TrimPrefix(path, "/v2/namespaces/")
+	if namespace == path || !strings.HasSuffix(namespace, "/"+suffix) {
+		return "", false
+	}
+
+	namespace = strings.TrimSuffix(namespace, "/"+suffix)
+
+	return namespace, namespace != "" && !strings.Contains(namespace, "/")
+}
+
+func parseTime(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return t, fmt.Errorf("invalid time '%s', expected RFC3339 or unix timestamp", value)
+	}
+
+	return t, nil
+}
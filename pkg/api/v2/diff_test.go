@@ -0,0 +1,86 @@
+package v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+)
+
+type diffFinder struct {
+	namespace string
+	from, to  time.Time
+	resource  v2.ResourceQuery
+}
+
+func (f *diffFinder) FetchResultDiff(namespace string, from, to time.Time) (*v2.ResultDiff, error) {
+	f.namespace, f.from, f.to = namespace, from, to
+
+	return &v2.ResultDiff{Namespace: namespace, From: from.Unix(), To: to.Unix(), Added: []v2.DiffResult{{ResultID: "123", Status: "fail"}}}, nil
+}
+
+func (f *diffFinder) FetchResourceHistory(query v2.ResourceQuery) ([]*v2.ResourceHistory, error) {
+	f.resource = query
+	if query.Name == "unknown" {
+		return []*v2.ResourceHistory{}, nil
+	}
+
+	return []*v2.ResourceHistory{{UID: "a1", Kind: "Pod", Name: "nginx"}, {UID: "b2", Kind: "Pod", Name: "nginx"}}, nil
+}
+
+func Test_ResultDiffHandler(t *testing.T) {
+	finder := &diffFinder{}
+	handler := v2.ResultDiffHandler(finder)
+
+	t.Run("Diff", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/v2/namespaces/test/diff?from=2024-03-01T10:00:00Z&to=1709301600", nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("unexpected status code %d: %s", rr.Code, rr.Body.String())
+		}
+		if finder.namespace != "test" || finder.from.Unix() != 1709287200 || finder.to.Unix() != 1709301600 {
+			t.Errorf("unexpected diff query: %s %s %s", finder.namespace, finder.from, finder.to)
+		}
+
+		diff := v2.ResultDiff{}
+		if err := json.Unmarshal(rr.Body.Bytes(), &diff); err != nil {
+			t.Fatal(err)
+		}
+		if len(diff.Added) != 1 || diff.Added[0].ResultID != "123" {
+			t.Errorf("unexpected diff %+v", diff)
+		}
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/v2/namespaces/test/diff", nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("unexpected status code %d", rr.Code)
+		}
+		if finder.to.Sub(finder.from) != 24*time.Hour {
+			t.Errorf("expected a default range of one day, got %s", finder.to.Sub(finder.from))
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, path := range []string{"/v2/namespaces/test/diff?from=yesterday", "/v2/namespaces/test/diff?from=1709301600&to=1709287200"} {
+			rr := httptest.NewRecorder()
+			handler(rr, httptest.NewRequest("GET", path, nil))
+
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("expected bad request for %s, got %d", path, rr.Code)
+			}
+		}
+
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/v2/namespaces/test/changes", nil))
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected not found for unknown paths, got %d", rr.Code)
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package v2
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/helper"
+	"github.com/kyverno/policy-reporter/pkg/timeformat"
+	"github.com/kyverno/policy-reporter/pkg/triage"
+)
+
+const (
+	CSVContentType  = "text/csv; charset=UTF-8"
+	XLSXContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+var exportColumns = []string{"ID", "Namespace", "Kind", "APIVersion", "Name", "Source", "Policy", "Rule", "Status", "Severity", "Category", "Message", "Timestamp", "Properties", "Triage", "Comments"}
+
+// ResultExportHandler REST API, exports the filtered results as CSV or Excel file
+func ResultExportHandler(finder PolicyReportFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		filter := v1.BuildFilter(req)
+		if !v1.ValidateFilter(w, filter) {
+			return
+		}
+
+		format := req.URL.Query().Get("format")
+		if format == "" {
+			format = "csv"
+		}
+		if format != "csv" && format != "xlsx" {
+			helper.SendBadRequest(w, fmt.Errorf("unknown format '%s', expected csv or xlsx", format))
+			return
+		}
+
+		list, err := finder.FetchResults(filter)
+		if err != nil {
+			helper.SendJSONResponse(w, nil, err)
+			return
+		}
+
+		filename := fmt.Sprintf("policy-reporter-results-%s.%s", time.Now().Format("2006-01-02"), format)
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+		if format == "xlsx" {
+			w.Header().Set("Content-Type", XLSXContentType)
+			err = writeXLSX(w, exportColumns, exportRows(list))
+		} else {
+			w.Header().Set("Content-Type", CSVContentType)
+			err = writeCSV(w, exportColumns, exportRows(list))
+		}
+
+		if err != nil {
+			// headers are already sent, the client receives a truncated file
+			logger.Errorf("failed to export results: %s", err)
+		}
+	}
+}
+
+// exportRows calls the row callback for each result in the order of the export columns
+func exportRows(list []*v1.ListResult) func(func([]string) error) error {
+	return func(row func([]string) error) error {
+		for _, r := range list {
+			timestamp := ""
+			if r.Timestamp > 0 {
+				timestamp = timeformat.Format(time.Unix(int64(r.Timestamp), 0))
+			}
+
+			status, comments := "", ""
+			if r.Triage != nil {
+				status, comments = string(r.Triage.Status), joinComments(r.Triage.Comments)
+			}
+
+			err := row([]string{r.ID, r.Namespace, r.Kind, r.APIVersion, r.Name, r.Source, r.Policy, r.Rule, r.Status, r.Severity, r.Category, r.Message, timestamp, joinProperties(r.Properties), status, comments})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func joinProperties(properties map[string]string) string {
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+properties[key])
+	}
+
+	return strings.Join(pairs, "; ")
+}
+
+func joinComments(comments []triage.Comment) string {
+	list := make([]string, 0, len(comments))
+	for _, c := range comments {
+		if c.Author != "" {
+			list = append(list, c.Author+": "+c.Text)
+		} else {
+			list = append(list, c.Text)
+		}
+	}
+
+	return strings.Join(list, "; ")
+}
+
+func writeCSV(w io.Writer, header []string, rows func(func([]string) error) error) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	err := rows(func(row []string) error {
+		for i, value := range row {
+			row[i] = escapeFormula(value)
+		}
+
+		return writer.Write(row)
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// escapeFormula prevents spreadsheet applications from evaluating cell values as formulas
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsAny(value[:1], "=+-@\t\r") {
+		return "'" + value
+	}
+
+	return value
+}
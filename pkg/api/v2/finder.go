@@ -0,0 +1,76 @@
+package v2
+
+import (
+	"time"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/federation"
+)
+
+// Cursor points to the last item of a page, the next page starts behind it
+type Cursor struct {
+	// Value of the sort field of the last item
+	Value interface{} `json:"v"`
+	// Keys uniquely identify the last item
+	Keys []string `json:"k"`
+}
+
+type Pagination struct {
+	Limit     int
+	SortBy    string
+	Direction string
+	Cursor    *Cursor
+}
+
+type PolicyReportFinder interface {
+	// FetchNamespaces from current PolicyReports
+	FetchNamespaces(v1.Filter) ([]string, error)
+	// FetchPolicyReportPage by filter and cursor pagination
+	FetchPolicyReportPage(v1.Filter, Pagination) ([]*v1.PolicyReport, *Cursor, error)
+	// FetchClusterPolicyReportPage by filter and cursor pagination
+	FetchClusterPolicyReportPage(v1.Filter, Pagination) ([]*v1.PolicyReport, *Cursor, error)
+	// FetchNamespacedResultPage from current PolicyReportResults with a Namespace by filter and cursor pagination
+	FetchNamespacedResultPage(v1.Filter, Pagination) ([]*v1.ListResult, *Cursor, error)
+	// FetchClusterResultPage from current PolicyReportResults by filter and cursor pagination
+	FetchClusterResultPage(v1.Filter, Pagination) ([]*v1.ListResult, *Cursor, error)
+	// FetchReportPage of PolicyReports and ClusterPolicyReports with their metadata by filter and cursor pagination
+	FetchReportPage(v1.Filter, Pagination) ([]*Report, *Cursor, error)
+	// FetchReport with its metadata by ID, raw includes the complete report. Returns nil if the report does not exist
+	FetchReport(id string, raw bool) (*Report, error)
+	// CountReports of PolicyReports and ClusterPolicyReports by filter
+	CountReports(v1.Filter) (int, error)
+	// CountPolicyReports by filter
+	CountPolicyReports(v1.Filter) (int, error)
+	// CountClusterPolicyReports by filter
+	CountClusterPolicyReports(v1.Filter) (int, error)
+	// CountNamespacedResults from current PolicyReportResults with a Namespace
+	CountNamespacedResults(v1.Filter) (int, error)
+	// CountClusterResults from current PolicyReportResults
+	CountClusterResults(v1.Filter) (int, error)
+	// FetchVulnerabilityResults from current PolicyReportResults with a vulnerabilityID property
+	FetchVulnerabilityResults(v1.Filter) ([]*v1.ListResult, error)
+	// FetchResults of PolicyReports and ClusterPolicyReports by filter
+	FetchResults(v1.Filter) ([]*v1.ListResult, error)
+	// FetchClusterSummaries aggregates the PolicyReports by their cluster label
+	FetchClusterSummaries() ([]federation.ClusterSummary, error)
+	// FetchResourceStatus groups the results by resource with the status counts across all sources
+	FetchResourceStatus(v1.Filter) ([]*ResourceStatus, error)
+	// FetchPolicyStatus groups the results by policy with the status and severity counts and the affected namespaces
+	FetchPolicyStatus(v1.Filter) ([]*PolicyStatus, error)
+	// FetchSeverityMatrix counts the results by source and severity and by namespace and severity
+	FetchSeverityMatrix(v1.Filter) (*SeverityMatrix, error)
+	// Search the policies, namespaces, resources and results containing the term, up to limit hits per type
+	Search(term string, filter v1.Filter, limit int) ([]*SearchHit, error)
+}
+
+type HistoryFinder interface {
+	// FetchResultDiff compares the recorded results of the namespace at from and to
+	FetchResultDiff(namespace string, from, to time.Time) (*ResultDiff, error)
+	// FetchResourceHistory returns the recorded transitions of the resource grouped by UID, oldest first
+	FetchResourceHistory(query ResourceQuery) ([]*ResourceHistory, error)
+}
+
+type TombstoneFinder interface {
+	// FetchTombstones of the reports deleted after since by namespace, source and report label filter
+	FetchTombstones(filter v1.Filter, since time.Time) ([]*Tombstone, error)
+}
@@ -0,0 +1,101 @@
+package v2
+
+import (
+	"net/http"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/helper"
+	"github.com/kyverno/policy-reporter/pkg/severity"
+)
+
+// SeverityUnknown counts results without a severity
+const SeverityUnknown = "unknown"
+
+// MatrixSeverities returns the columns of the SeverityMatrix, from the highest to the lowest severity including custom levels
+func MatrixSeverities() []string {
+	levels := severity.List()
+
+	list := make([]string, 0, len(levels)+1)
+	for i := len(levels) - 1; i >= 0; i-- {
+		if levels[i] != SeverityUnknown {
+			list = append(list, levels[i])
+		}
+	}
+
+	return append(list, SeverityUnknown)
+}
+
+// SeverityRow counts the results of a source or namespace by severity
+type SeverityRow struct {
+	Name       string         `json:"name"`
+	Severities map[string]int `json:"severities"`
+	Total      int            `json:"total"`
+}
+
+// SeverityMatrix counts the findings by source and severity and by namespace and severity,
+// cluster scoped results are only part of the source rows
+type SeverityMatrix struct {
+	Severities []string       `json:"severities"`
+	Sources    []*SeverityRow `json:"sources"`
+	Namespaces []*SeverityRow `json:"namespaces"`
+}
+
+// AddSource adds the count of a source and severity, the counts have to be added ordered by source
+func (m *SeverityMatrix) AddSource(source, severity string, count int) {
+	m.Sources = addSeverityCount(m.Sources, source, severity, count)
+}
+
+// AddNamespace adds the count of a namespace and severity, the counts have to be added ordered by namespace
+func (m *SeverityMatrix) AddNamespace(namespace, severity string, count int) {
+	m.Namespaces = addSeverityCount(m.Namespaces, namespace, severity, count)
+}
+
+func addSeverityCount(rows []*SeverityRow, name, severity string, count int) []*SeverityRow {
+	if len(rows) == 0 || rows[len(rows)-1].Name != name {
+		columns := MatrixSeverities()
+
+		row := &SeverityRow{Name: name, Severities: make(map[string]int, len(columns))}
+		for _, s := range columns {
+			row.Severities[s] = 0
+		}
+
+		rows = append(rows, row)
+	}
+
+	if _, ok := rows[len(rows)-1].Severities[severity]; !ok {
+		severity = SeverityUnknown
+	}
+
+	rows[len(rows)-1].Severities[severity] += count
+	rows[len(rows)-1].Total += count
+
+	return rows
+}
+
+// NewSeverityMatrix creates an empty SeverityMatrix
+func NewSeverityMatrix() *SeverityMatrix {
+	return &SeverityMatrix{
+		Severities: MatrixSeverities(),
+		Sources:    []*SeverityRow{},
+		Namespaces: []*SeverityRow{},
+	}
+}
+
+// SeverityMatrixHandler counts the findings by source and severity and by namespace and severity,
+// without status filter the fail, warn and error results are counted
+func SeverityMatrixHandler(finder PolicyReportFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		filter := v1.BuildFilter(req)
+		if !v1.ValidateFilter(w, filter) {
+			return
+		}
+
+		if len(filter.Status) == 0 {
+			filter.Status = []string{v1alpha2.StatusFail, v1alpha2.StatusWarn, v1alpha2.StatusError}
+		}
+
+		matrix, err := finder.FetchSeverityMatrix(filter)
+		helper.SendJSONResponse(w, matrix, err)
+	}
+}
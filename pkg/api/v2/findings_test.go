@@ -0,0 +1,66 @@
+package v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+)
+
+type matrixFinder struct {
+	v2.PolicyReportFinder
+	filter v1.Filter
+}
+
+func (f *matrixFinder) FetchSeverityMatrix(filter v1.Filter) (*v2.SeverityMatrix, error) {
+	f.filter = filter
+
+	matrix := v2.NewSeverityMatrix()
+	matrix.AddSource("kyverno", "high", 2)
+	matrix.AddSource("kyverno", "", 1)
+	matrix.AddSource("trivy", "critical", 3)
+	matrix.AddNamespace("test", "high", 2)
+
+	return matrix, nil
+}
+
+func Test_SeverityMatrixHandler(t *testing.T) {
+	t.Run("Default status", func(t *testing.T) {
+		finder := &matrixFinder{}
+
+		rr := httptest.NewRecorder()
+		v2.SeverityMatrixHandler(finder)(rr, httptest.NewRequest("GET", "/v2/findings/severities?sources=kyverno", nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("unexpected status code %d: %s", rr.Code, rr.Body.String())
+		}
+		if len(finder.filter.Status) != 3 || len(finder.filter.Sources) != 1 {
+			t.Errorf("expected fail, warn and error results with the remaining filters, got %+v", finder.filter)
+		}
+
+		matrix := v2.SeverityMatrix{}
+		if err := json.Unmarshal(rr.Body.Bytes(), &matrix); err != nil {
+			t.Fatal(err)
+		}
+		if len(matrix.Severities) != 6 || len(matrix.Sources) != 2 || len(matrix.Namespaces) != 1 {
+			t.Fatalf("unexpected matrix %s", rr.Body.String())
+		}
+		if matrix.Sources[0].Total != 3 || matrix.Sources[0].Severities[v2.SeverityUnknown] != 1 || matrix.Sources[0].Severities["critical"] != 0 {
+			t.Errorf("unexpected source row %+v", matrix.Sources[0])
+		}
+	})
+
+	t.Run("Status filter", func(t *testing.T) {
+		finder := &matrixFinder{}
+
+		rr := httptest.NewRecorder()
+		v2.SeverityMatrixHandler(finder)(rr, httptest.NewRequest("GET", "/v2/findings/severities?status=pass", nil))
+
+		if len(finder.filter.Status) != 1 || finder.filter.Status[0] != "pass" {
+			t.Errorf("expected the status filter to be kept, got %v", finder.filter.Status)
+		}
+	})
+}
@@ -0,0 +1,186 @@
+package v2
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/federation"
+	"github.com/kyverno/policy-reporter/pkg/helper"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 1000
+)
+
+// PolicyReportListHandler REST API
+func PolicyReportListHandler(finder PolicyReportFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		filter := v1.BuildFilter(req)
+		count, _ := finder.CountPolicyReports(filter)
+		list, cursor, err := finder.FetchPolicyReportPage(filter, buildPagination(req))
+		sendPage(w, req, list, cursor, count, err)
+	}
+}
+
+// ClusterPolicyReportListHandler REST API
+func ClusterPolicyReportListHandler(finder PolicyReportFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		filter := v1.BuildFilter(req)
+		count, _ := finder.CountClusterPolicyReports(filter)
+		list, cursor, err := finder.FetchClusterPolicyReportPage(filter, buildPagination(req))
+		sendPage(w, req, list, cursor, count, err)
+	}
+}
+
+// NamespacedResourcesResultHandler REST API
+func NamespacedResourcesResultHandler(finder PolicyReportFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		filter := v1.BuildFilter(req)
+		if !v1.ValidateFilter(w, filter) {
+			return
+		}
+
+		count, _ := finder.CountNamespacedResults(filter)
+		list, cursor, err := finder.FetchNamespacedResultPage(filter, buildPagination(req))
+		sendPage(w, req, list, cursor, count, err)
+	}
+}
+
+// ClusterResourcesResultHandler REST API
+func ClusterResourcesResultHandler(finder PolicyReportFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		filter := v1.BuildFilter(req)
+		if !v1.ValidateFilter(w, filter) {
+			return
+		}
+
+		count, _ := finder.CountClusterResults(filter)
+		list, cursor, err := finder.FetchClusterResultPage(filter, buildPagination(req))
+		sendPage(w, req, list, cursor, count, err)
+	}
+}
+
+// VulnerabilityHandler REST API, groups the results with a vulnerabilityID property by CVE
+func VulnerabilityHandler(finder PolicyReportFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		filter := v1.BuildFilter(req)
+		if !v1.ValidateFilter(w, filter) {
+			return
+		}
+
+		results, err := finder.FetchVulnerabilityResults(filter)
+		if err != nil {
+			helper.SendJSONResponse(w, nil, err)
+			return
+		}
+
+		list := AggregateVulnerabilities(results)
+
+		if fixable, err := strconv.ParseBool(req.URL.Query().Get("fixable")); err == nil {
+			filtered := make([]*Vulnerability, 0, len(list))
+			for _, vuln := range list {
+				if vuln.Fixable == fixable {
+					filtered = append(filtered, vuln)
+				}
+			}
+
+			list = filtered
+		}
+
+		helper.SendJSONResponse(w, list, nil)
+	}
+}
+
+// ClusterSummaryHandler REST API, aggregates the PolicyReports per federated cluster,
+// reports without cluster label are listed as the local cluster
+func ClusterSummaryHandler(finder PolicyReportFinder, local string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		list, err := federation.Summaries(finder, local)
+		helper.SendJSONResponse(w, list, err)
+	}
+}
+
+func sendPage(w http.ResponseWriter, req *http.Request, list interface{}, cursor *Cursor, count int, err error) {
+	if err != nil {
+		helper.SendJSONResponse(w, nil, err)
+		return
+	}
+
+	items, err := selectFields(list, req.URL.Query()["fields"])
+	if err != nil {
+		helper.SendJSONResponse(w, nil, err)
+		return
+	}
+
+	next := EncodeCursor(cursor)
+
+	w.Header().Set(TotalCountHeader, strconv.Itoa(count))
+	w.Header().Set(NextCursorHeader, next)
+
+	helper.SendJSONResponse(w, Page{Items: items, NextCursor: next}, nil)
+}
+
+// selectFields reduces each item to the requested JSON fields, the id is always included
+func selectFields(list interface{}, fields []string) ([]map[string]interface{}, error) {
+	items := make([]map[string]interface{}, 0)
+
+	content, err := json.Marshal(list)
+	if err != nil {
+		return items, err
+	}
+
+	if err := json.Unmarshal(content, &items); err != nil || items == nil {
+		return make([]map[string]interface{}, 0), err
+	}
+
+	selected := make(map[string]bool)
+	for _, field := range fields {
+		for _, f := range strings.Split(field, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				selected[f] = true
+			}
+		}
+	}
+
+	if len(selected) == 0 {
+		return items, nil
+	}
+
+	selected["id"] = true
+
+	for _, item := range items {
+		for key := range item {
+			if !selected[key] {
+				delete(item, key)
+			}
+		}
+	}
+
+	return items, nil
+}
+
+func buildPagination(req *http.Request) Pagination {
+	limit, err := strconv.Atoi(req.URL.Query().Get("limit"))
+	if err != nil || limit < 1 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	direction := "ASC"
+	if strings.ToLower(req.URL.Query().Get("direction")) == "desc" {
+		direction = "DESC"
+	}
+
+	return Pagination{
+		Limit:     limit,
+		SortBy:    req.URL.Query().Get("sortBy"),
+		Direction: direction,
+		Cursor:    DecodeCursor(req.URL.Query().Get("cursor")),
+	}
+}
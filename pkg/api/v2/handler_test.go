@@ -0,0 +1,290 @@
+package v2_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/sarif"
+	"github.com/kyverno/policy-reporter/pkg/sqlite3"
+)
+
+var preport = &v1alpha2.PolicyReport{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:              "polr-test",
+		Namespace:         "test",
+		CreationTimestamp: metav1.Now(),
+	},
+	Results: []v1alpha2.PolicyReportResult{fixtures.FailResult, fixtures.PassPodResult},
+	Summary: v1alpha2.PolicyReportSummary{Fail: 1, Pass: 1},
+}
+
+func Test_V2_API(t *testing.T) {
+	db, err := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store, err := sqlite3.NewPolicyReportStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Add(preport)
+
+	t.Run("NamespacedResourcesResultHandler", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v2/namespaced-resources/results?limit=1&sortBy=severity&direction=desc&fields=policy,status", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := v2.NamespacedResourcesResultHandler(store)
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+		if count := rr.Header().Get(v2.TotalCountHeader); count != "2" {
+			t.Errorf("Expected total count of 2, got %s", count)
+		}
+
+		page := v2.Page{}
+		if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(page.Items) != 1 {
+			t.Fatalf("Expected 1 item, got %d", len(page.Items))
+		}
+		if len(page.Items[0]) != 3 {
+			t.Errorf("Expected only id, policy and status fields, got %v", page.Items[0])
+		}
+		if page.NextCursor == "" || rr.Header().Get(v2.NextCursorHeader) != page.NextCursor {
+			t.Fatal("Expected cursor for the next page")
+		}
+
+		req, _ = http.NewRequest("GET", "/v2/namespaced-resources/results?limit=1&sortBy=severity&direction=desc&cursor="+page.NextCursor, nil)
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		next := v2.Page{}
+		json.Unmarshal(rr.Body.Bytes(), &next)
+
+		if len(next.Items) != 1 || next.NextCursor != "" {
+			t.Errorf("Expected last page with 1 item, got %d", len(next.Items))
+		}
+	})
+	t.Run("PolicyReportListHandler", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v2/policy-reports", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := v2.PolicyReportListHandler(store)
+		handler.ServeHTTP(rr, req)
+
+		if count := rr.Header().Get(v2.TotalCountHeader); count != "1" {
+			t.Errorf("Expected total count of 1, got %s", count)
+		}
+	})
+	t.Run("SARIFHandler", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v2/sarif?namespaces=test", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		v2.SARIFHandler(store).ServeHTTP(rr, req)
+
+		if contentType := rr.Header().Get("Content-Type"); contentType != v2.SARIFContentType {
+			t.Errorf("Unexpected content type %s", contentType)
+		}
+
+		log := sarif.Log{}
+		if err := json.Unmarshal(rr.Body.Bytes(), &log); err != nil {
+			t.Fatal(err)
+		}
+
+		if log.Version != sarif.Version || len(log.Runs) != 1 {
+			t.Fatalf("Expected SARIF log with 1 run, got %+v", log)
+		}
+		if results := log.Runs[0].Results; len(results) != 2 || results[0].PartialFingerprints[sarif.FingerprintKey] == "" {
+			t.Errorf("Expected 2 results with fingerprint, got %+v", results)
+		}
+	})
+	t.Run("ResultExportHandler", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v2/results/export?format=csv&status=fail", nil)
+		rr := httptest.NewRecorder()
+		v2.ResultExportHandler(store).ServeHTTP(rr, req)
+
+		if contentType := rr.Header().Get("Content-Type"); contentType != v2.CSVContentType {
+			t.Errorf("Unexpected content type %s", contentType)
+		}
+		if !strings.HasPrefix(rr.Header().Get("Content-Disposition"), "attachment; filename=") {
+			t.Errorf("Expected attachment, got %s", rr.Header().Get("Content-Disposition"))
+		}
+
+		rows, err := csv.NewReader(rr.Body).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != 2 || rows[0][0] != "ID" || rows[1][8] != "fail" {
+			t.Errorf("Expected header and one fail result, got %v", rows)
+		}
+
+		req, _ = http.NewRequest("GET", "/v2/results/export?format=xlsx", nil)
+		rr = httptest.NewRecorder()
+		v2.ResultExportHandler(store).ServeHTTP(rr, req)
+
+		archive, err := zip.NewReader(bytes.NewReader(rr.Body.Bytes()), int64(rr.Body.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var sheet string
+		for _, f := range archive.File {
+			if f.Name == "xl/worksheets/sheet1.xml" {
+				content, _ := f.Open()
+				body, _ := io.ReadAll(content)
+				sheet = string(body)
+			}
+		}
+		if strings.Count(sheet, "<row ") != 3 || !strings.Contains(sheet, `<c r="I2" t="inlineStr">`) {
+			t.Errorf("Expected header and two result rows, got %s", sheet)
+		}
+
+		req, _ = http.NewRequest("GET", "/v2/results/export?format=pdf", nil)
+		rr = httptest.NewRecorder()
+		v2.ResultExportHandler(store).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected bad request for unknown format, got %d", rr.Code)
+		}
+	})
+	t.Run("ClusterResourcesResultHandler", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v2/cluster-resources/results", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := v2.ClusterResourcesResultHandler(store)
+		handler.ServeHTTP(rr, req)
+
+		if body := rr.Body.String(); body != "{\"items\":[]}\n" {
+			t.Errorf("Expected empty page, got %s", body)
+		}
+	})
+}
+
+func Test_VulnerabilityHandler(t *testing.T) {
+	db, err := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store, err := sqlite3.NewPolicyReportStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vulnerability := func(id, name, image, fixed string, severity v1alpha2.PolicySeverity) v1alpha2.PolicyReportResult {
+		return v1alpha2.PolicyReportResult{
+			Source:   "Trivy Vulnerability",
+			Policy:   "CVE-2022-1234",
+			Rule:     "openssl",
+			Result:   v1alpha2.StatusFail,
+			Severity: severity,
+			Resources: []corev1.ObjectReference{{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       name,
+				Namespace:  "test",
+				UID:        types.UID(id),
+			}},
+			Properties: map[string]string{
+				v1alpha2.VulnerabilityIDKey: "CVE-2022-1234",
+				v1alpha2.CVSSScoreKey:       "7.5",
+				"resource":                  "openssl",
+				"installedVersion":          "1.1.1",
+				"fixedVersion":              fixed,
+				"artifact.repository":       image,
+				"artifact.tag":              "1.0",
+			},
+		}
+	}
+
+	store.Add(&v1alpha2.PolicyReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "trivy-vuln", Namespace: "test", CreationTimestamp: metav1.Now()},
+		Results: []v1alpha2.PolicyReportResult{
+			vulnerability("1", "nginx", "library/nginx", "1.1.2", v1alpha2.SeverityHigh),
+			vulnerability("2", "apache", "library/httpd", "", v1alpha2.SeverityHigh),
+			fixtures.FailResult,
+		},
+		Summary: v1alpha2.PolicyReportSummary{Fail: 3},
+	})
+
+	t.Run("Aggregate by CVE", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v2/vulnerabilities?severities=high", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		v2.VulnerabilityHandler(store).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		list := []*v2.Vulnerability{}
+		if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(list) != 1 {
+			t.Fatalf("Expected 1 vulnerability, got %d", len(list))
+		}
+
+		vuln := list[0]
+		if vuln.ID != "CVE-2022-1234" || vuln.Results != 2 || vuln.AffectedImages != 2 || len(vuln.Resources) != 2 {
+			t.Errorf("Unexpected aggregation: %+v", vuln)
+		}
+		if !vuln.Fixable || len(vuln.FixedVersions) != 1 || vuln.FixedVersions[0] != "1.1.2" {
+			t.Errorf("Expected fixed version 1.1.2, got %v", vuln.FixedVersions)
+		}
+		if vuln.CVSSScore != 7.5 {
+			t.Errorf("Expected CVSS score 7.5, got %v", vuln.CVSSScore)
+		}
+	})
+	t.Run("Filter fixable", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v2/vulnerabilities?fixable=false", nil)
+
+		rr := httptest.NewRecorder()
+		v2.VulnerabilityHandler(store).ServeHTTP(rr, req)
+
+		list := []*v2.Vulnerability{}
+		json.Unmarshal(rr.Body.Bytes(), &list)
+
+		if len(list) != 0 {
+			t.Errorf("Expected no vulnerability without fixed version, got %d", len(list))
+		}
+	})
+}
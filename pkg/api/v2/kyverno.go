@@ -0,0 +1,140 @@
+package v2
+
+import (
+	"net/http"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/helper"
+	"github.com/kyverno/policy-reporter/pkg/kyverno"
+)
+
+// KyvernoPolicy is a Kyverno policy with the status of its results and the exceptions referencing it
+type KyvernoPolicy struct {
+	kyverno.Policy
+	Status     *PolicyStatus `json:"status,omitempty"`
+	Exceptions []string      `json:"exceptions"`
+}
+
+// KyvernoException is a PolicyException with the status of the results of the exempted policies
+type KyvernoException struct {
+	kyverno.Exception
+	Status []*PolicyStatus `json:"status"`
+}
+
+// KyvernoPolicyHandler lists the Kyverno policies of the catalog joined with the status of their results,
+// namespaced Policies are restricted to the namespaces filter
+func KyvernoPolicyHandler(catalog *kyverno.Catalog, finder PolicyReportFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		filter := v1.BuildFilter(req)
+		if !v1.ValidateFilter(w, filter) {
+			return
+		}
+
+		status, err := kyvernoStatus(finder, filter)
+		if err != nil {
+			helper.SendJSONResponse(w, nil, err)
+			return
+		}
+
+		exceptions := make(map[string][]string)
+		for _, e := range catalog.Exceptions() {
+			for _, p := range e.Policies {
+				exceptions[p.Policy] = append(exceptions[p.Policy], e.Namespace+"/"+e.Name)
+			}
+		}
+
+		list := make([]KyvernoPolicy, 0)
+		for _, p := range catalog.Policies() {
+			if p.Namespace != "" && !contains(filter.Namespaces, p.Namespace) {
+				continue
+			}
+
+			names := exceptions[p.Key()]
+			if names == nil {
+				names = make([]string, 0)
+			}
+
+			list = append(list, KyvernoPolicy{Policy: p, Status: policyStatus(status, p), Exceptions: names})
+		}
+
+		helper.SendJSONResponse(w, list, nil)
+	}
+}
+
+// KyvernoExceptionHandler lists the PolicyExceptions of the catalog joined with the status of the exempted policies,
+// exceptions are restricted to the namespaces filter
+func KyvernoExceptionHandler(catalog *kyverno.Catalog, finder PolicyReportFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		filter := v1.BuildFilter(req)
+		if !v1.ValidateFilter(w, filter) {
+			return
+		}
+
+		status, err := kyvernoStatus(finder, filter)
+		if err != nil {
+			helper.SendJSONResponse(w, nil, err)
+			return
+		}
+
+		list := make([]KyvernoException, 0)
+		for _, e := range catalog.Exceptions() {
+			if !contains(filter.Namespaces, e.Namespace) {
+				continue
+			}
+
+			exception := KyvernoException{Exception: e, Status: make([]*PolicyStatus, 0, len(e.Policies))}
+			for _, p := range e.Policies {
+				if s, ok := status[p.Policy]; ok {
+					exception.Status = append(exception.Status, s)
+				}
+			}
+
+			list = append(list, exception)
+		}
+
+		helper.SendJSONResponse(w, list, nil)
+	}
+}
+
+// kyvernoStatus returns the status of the Kyverno results by policy
+func kyvernoStatus(finder PolicyReportFinder, filter v1.Filter) (map[string]*PolicyStatus, error) {
+	filter.Sources = []string{"kyverno"}
+	filter.Status = nil
+	filter.IDs = nil
+
+	list, err := finder.FetchPolicyStatus(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	status := make(map[string]*PolicyStatus, len(list))
+	for _, s := range list {
+		status[s.Policy] = s
+	}
+
+	return status, nil
+}
+
+// policyStatus returns the status of the policy, results of namespaced Policies reference them by namespace/name or by name
+func policyStatus(status map[string]*PolicyStatus, p kyverno.Policy) *PolicyStatus {
+	if s, ok := status[p.Key()]; ok {
+		return s
+	}
+
+	return status[p.Name]
+}
+
+// contains reports if the value is part of the list, an empty list contains all values
+func contains(list []string, value string) bool {
+	if len(list) == 0 {
+		return true
+	}
+
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,85 @@
+package v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+	"github.com/kyverno/policy-reporter/pkg/kyverno"
+)
+
+func kyvernoCatalog() *kyverno.Catalog {
+	catalog := kyverno.NewCatalog(kyverno.DefaultAnnotations)
+
+	for _, obj := range []map[string]interface{}{
+		{"kind": "ClusterPolicy", "metadata": map[string]interface{}{"name": "require-labels"}},
+		{"kind": "Policy", "metadata": map[string]interface{}{"name": "require-team", "namespace": "dev"}},
+	} {
+		catalog.SetPolicy(&unstructured.Unstructured{Object: obj})
+	}
+
+	catalog.SetException(&unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":     "PolicyException",
+		"metadata": map[string]interface{}{"name": "allow-nginx", "namespace": "test"},
+		"spec": map[string]interface{}{
+			"exceptions": []interface{}{map[string]interface{}{"policyName": "require-labels", "ruleNames": []interface{}{"check-app"}}},
+		},
+	}})
+
+	return catalog
+}
+
+func Test_KyvernoPolicyHandler(t *testing.T) {
+	finder := &policyFinder{}
+
+	rr := httptest.NewRecorder()
+	v2.KyvernoPolicyHandler(kyvernoCatalog(), finder)(rr, httptest.NewRequest("GET", "/v2/kyverno/policies?namespaces=test", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status code %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(finder.filter.Sources) != 1 || finder.filter.Sources[0] != "kyverno" {
+		t.Errorf("expected the kyverno source filter, got %+v", finder.filter)
+	}
+
+	list := make([]v2.KyvernoPolicy, 0)
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("expected the Policy of another namespace to be filtered, got %+v", list)
+	}
+	if list[0].Status == nil || list[0].Status.Fail != 2 {
+		t.Errorf("expected the status of the results, got %+v", list[0].Status)
+	}
+	if len(list[0].Exceptions) != 1 || list[0].Exceptions[0] != "test/allow-nginx" {
+		t.Errorf("expected the referencing exception, got %+v", list[0].Exceptions)
+	}
+}
+
+func Test_KyvernoExceptionHandler(t *testing.T) {
+	rr := httptest.NewRecorder()
+	v2.KyvernoExceptionHandler(kyvernoCatalog(), &policyFinder{})(rr, httptest.NewRequest("GET", "/v2/kyverno/exceptions", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status code %d: %s", rr.Code, rr.Body.String())
+	}
+
+	list := make([]v2.KyvernoException, 0)
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Name != "allow-nginx" || len(list[0].Status) != 1 || list[0].Status[0].Pass != 3 {
+		t.Errorf("unexpected exceptions %s", rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	v2.KyvernoExceptionHandler(kyvernoCatalog(), &policyFinder{})(rr, httptest.NewRequest("GET", "/v2/kyverno/exceptions?namespaces=dev", nil))
+	if rr.Body.String() != "[]" && rr.Body.String() != "[]\n" {
+		t.Errorf("expected no exceptions of other namespaces, got %s", rr.Body.String())
+	}
+}
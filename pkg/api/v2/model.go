@@ -0,0 +1,226 @@
+package v2
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/health"
+)
+
+const (
+	// TotalCountHeader contains the total count of items matching the filter
+	TotalCountHeader = "X-Total-Count"
+	// NextCursorHeader contains the cursor of the next page, empty on the last page
+	NextCursorHeader = "X-Next-Cursor"
+)
+
+type Page struct {
+	Items      []map[string]interface{} `json:"items"`
+	NextCursor string                   `json:"nextCursor,omitempty"`
+}
+
+// EncodeCursor into an URL safe string
+func EncodeCursor(cursor *Cursor) string {
+	if cursor == nil {
+		return ""
+	}
+
+	value, err := json.Marshal(cursor)
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(value)
+}
+
+// DecodeCursor from an URL safe string, returns nil for empty or invalid cursors
+func DecodeCursor(value string) *Cursor {
+	if value == "" {
+		return nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(raw)))
+	decoder.UseNumber()
+
+	cursor := &Cursor{}
+	if err := decoder.Decode(cursor); err != nil {
+		return nil
+	}
+
+	if number, ok := cursor.Value.(json.Number); ok {
+		if integer, err := number.Int64(); err == nil {
+			cursor.Value = integer
+		} else if float, err := number.Float64(); err == nil {
+			cursor.Value = float
+		}
+	}
+
+	return cursor
+}
+
+// Target with its filters and the last recorded delivery
+type Target struct {
+	Name                  string           `json:"name"`
+	MinimumPriority       string           `json:"minimumPriority"`
+	Sources               []string         `json:"sources,omitempty"`
+	SkipExistingOnStartup bool             `json:"skipExistingOnStartup"`
+	Concurrency           int              `json:"concurrency,omitempty"`
+	Delivery              *health.Delivery `json:"delivery,omitempty"`
+}
+
+// TargetTest is the delivery status of a synthetic test result
+type TargetTest struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// TargetReplay counts the stored results within the time range which were sent to the target or skipped by its filters
+type TargetReplay struct {
+	Name    string `json:"name"`
+	Results int    `json:"results"`
+	Sent    int    `json:"sent"`
+	Skipped int    `json:"skipped"`
+}
+
+// TargetSimulationRequest is a result evaluated against the filters of all targets, the PolicyReport
+// is scoped to the namespace of the result resource
+type TargetSimulationRequest struct {
+	ReportLabels map[string]string           `json:"reportLabels,omitempty"`
+	Result       v1alpha2.PolicyReportResult `json:"result"`
+}
+
+// TargetSimulation is the evaluation of a simulated result by a target
+type TargetSimulation struct {
+	Name     string `json:"name"`
+	Matched  bool   `json:"matched"`
+	Priority string `json:"priority,omitempty"`
+}
+
+// DiffResult is a result of a ResultDiff with its status at the start and the end of the diff,
+// Status is empty for results removed from their PolicyReport
+type DiffResult struct {
+	ReportID       string `json:"reportId"`
+	ResultID       string `json:"resultId"`
+	Kind           string `json:"kind"`
+	Name           string `json:"name"`
+	Source         string `json:"source"`
+	Policy         string `json:"policy"`
+	Rule           string `json:"rule"`
+	PreviousStatus string `json:"previousStatus"`
+	Status         string `json:"status"`
+	// Timestamp of the last status change
+	Timestamp int64 `json:"timestamp"`
+}
+
+// ResultDiff of the fail, warn and error results of a namespace between two points in time
+type ResultDiff struct {
+	Namespace    string       `json:"namespace"`
+	From         int64        `json:"from"`
+	To           int64        `json:"to"`
+	Added        []DiffResult `json:"added"`
+	Resolved     []DiffResult `json:"resolved"`
+	StillFailing []DiffResult `json:"stillFailing"`
+	// Deleted reports of the namespace within the diff, requires tombstones.enabled
+	Deleted []*Tombstone `json:"deleted"`
+}
+
+// ResourceQuery selects the history of a resource by kind, name and namespace or by its UID across name changes
+type ResourceQuery struct {
+	Kind      string
+	Name      string
+	Namespace string
+	UID       string
+}
+
+// ResourceHistory of one incarnation of a resource, a resource recreated with the same name has a new UID and history
+type ResourceHistory struct {
+	UID         string                 `json:"uid"`
+	Kind        string                 `json:"kind"`
+	Name        string                 `json:"name"`
+	Namespace   string                 `json:"namespace,omitempty"`
+	FirstSeen   int64                  `json:"firstSeen"`
+	LastSeen    int64                  `json:"lastSeen"`
+	Transitions []*v1.ResultTransition `json:"transitions"`
+}
+
+// Tombstone of a deleted PolicyReport or ClusterPolicyReport with its last summary
+type Tombstone struct {
+	ReportID  string            `json:"reportId"`
+	Kind      string            `json:"kind"`
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace,omitempty"`
+	Source    string            `json:"source"`
+	Labels    map[string]string `json:"labels"`
+	Pass      int               `json:"pass"`
+	Skip      int               `json:"skip"`
+	Warn      int               `json:"warn"`
+	Fail      int               `json:"fail"`
+	Error     int               `json:"error"`
+	// Created is the creation timestamp of the deleted report
+	Created int64 `json:"created"`
+	// Deleted is the time the report was removed
+	Deleted int64 `json:"deleted"`
+}
+
+// ResourceStatus is a resource with the status counts of its results across all sources
+type ResourceStatus struct {
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	Name       string   `json:"name"`
+	Namespace  string   `json:"namespace,omitempty"`
+	UID        string   `json:"uid,omitempty"`
+	Sources    []string `json:"sources"`
+	Pass       int      `json:"pass"`
+	Skip       int      `json:"skip"`
+	Warn       int      `json:"warn"`
+	Fail       int      `json:"fail"`
+	Error      int      `json:"error"`
+}
+
+// PolicyStatus is a policy with the status counts of its results, the severities of its fail, warn and error results
+// and the namespaces and number of resources affected by them
+type PolicyStatus struct {
+	Policy     string         `json:"policy"`
+	Sources    []string       `json:"sources"`
+	Pass       int            `json:"pass"`
+	Skip       int            `json:"skip"`
+	Warn       int            `json:"warn"`
+	Fail       int            `json:"fail"`
+	Error      int            `json:"error"`
+	Severities map[string]int `json:"severities"`
+	Namespaces []string       `json:"namespaces"`
+	Resources  int            `json:"resources"`
+}
+
+// Report is a PolicyReport or ClusterPolicyReport with its metadata and summary,
+// Raw contains the complete report with its results if requested
+type Report struct {
+	ID        string                  `json:"id"`
+	Kind      string                  `json:"kind"`
+	Name      string                  `json:"name"`
+	Namespace string                  `json:"namespace,omitempty"`
+	Source    string                  `json:"source"`
+	Labels    map[string]string       `json:"labels"`
+	Scope     *corev1.ObjectReference `json:"scope,omitempty"`
+	Pass      int                     `json:"pass"`
+	Skip      int                     `json:"skip"`
+	Warn      int                     `json:"warn"`
+	Fail      int                     `json:"fail"`
+	Error     int                     `json:"error"`
+	// Created is the creation timestamp of the report
+	Created int64 `json:"created"`
+	// Updated is the time of the last update of the report
+	Updated int64           `json:"updated,omitempty"`
+	Raw     json.RawMessage `json:"raw,omitempty"`
+}
@@ -0,0 +1,80 @@
+package v2
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/helper"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+// NodeListHandler lists the nodes with results, e.g. CIS benchmark results of kube-bench,
+// with their status counts across all sources
+func NodeListHandler(finder PolicyReportFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		filter := v1.BuildFilter(req)
+		if !v1.ValidateFilter(w, filter) {
+			return
+		}
+
+		filter.Kinds = []string{report.NodeKind}
+		filter.Namespaces = []string{""}
+		filter.Status = nil
+		filter.IDs = nil
+
+		list, err := finder.FetchResourceStatus(filter)
+		helper.SendJSONResponse(w, list, err)
+	}
+}
+
+// NodeResultsHandler returns the results of the node of the path /v2/nodes/{node}/results
+func NodeResultsHandler(finder PolicyReportFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		node, ok := NodeFromPath(req.URL.Path)
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+
+		filter := v1.BuildFilter(req)
+		if !v1.ValidateFilter(w, filter) {
+			return
+		}
+
+		filter.Kinds = []string{report.NodeKind}
+		filter.Resources = []string{node}
+		filter.Namespaces = []string{""}
+		filter.Status = nil
+		filter.IDs = nil
+
+		resources, err := finder.FetchResourceStatus(filter)
+		if err != nil {
+			helper.SendJSONResponse(w, nil, err)
+			return
+		}
+		if len(resources) == 0 {
+			helper.SendError(w, http.StatusNotFound, fmt.Errorf("no results found for node %s", node))
+			return
+		}
+
+		results, err := finder.FetchResults(filter)
+		helper.SendJSONResponse(w, ResourceResults{Resource: resources[0], Results: results}, err)
+	}
+}
+
+// NodeFromPath extracts the node of the path /v2/nodes/{node}/results
+func NodeFromPath(path string) (string, bool) {
+	node := strings.TrimPrefix(path, "/v2/nodes/")
+	if node == path || !strings.HasSuffix(node, "/results") {
+		return "", false
+	}
+
+	node = strings.TrimSuffix(node, "/results")
+	if node == "" || strings.Contains(node, "/") {
+		return "", false
+	}
+
+	return node, true
+}
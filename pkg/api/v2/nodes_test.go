@@ -0,0 +1,66 @@
+package v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+)
+
+func Test_NodeListHandler(t *testing.T) {
+	finder := &resourceFinder{}
+
+	rr := httptest.NewRecorder()
+	v2.NodeListHandler(finder)(rr, httptest.NewRequest("GET", "/v2/nodes?kinds=Pod&sources=kube-bench", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status code %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(finder.filter.Kinds) != 1 || finder.filter.Kinds[0] != "Node" {
+		t.Errorf("expected node kind filter, got %v", finder.filter.Kinds)
+	}
+	if len(finder.filter.Namespaces) != 1 || finder.filter.Namespaces[0] != "" {
+		t.Errorf("expected cluster scoped filter, got %v", finder.filter.Namespaces)
+	}
+	if len(finder.filter.Sources) != 1 {
+		t.Errorf("expected the remaining filters, got %+v", finder.filter)
+	}
+}
+
+func Test_NodeResultsHandler(t *testing.T) {
+	finder := &resourceFinder{}
+	handler := v2.NodeResultsHandler(finder)
+
+	t.Run("Results", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/v2/nodes/worker-1/results", nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("unexpected status code %d: %s", rr.Code, rr.Body.String())
+		}
+		if finder.filter.Kinds[0] != "Node" || finder.filter.Resources[0] != "worker-1" {
+			t.Errorf("unexpected filter %+v", finder.filter)
+		}
+
+		res := v2.ResourceResults{}
+		if err := json.Unmarshal(rr.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		if res.Resource == nil || len(res.Results) != 1 {
+			t.Errorf("unexpected node results %+v", res)
+		}
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		for _, path := range []string{"/v2/nodes/unknown/results", "/v2/nodes/results", "/v2/nodes/worker-1/status", "/v2/nodes/worker/1/results"} {
+			rr := httptest.NewRecorder()
+			handler(rr, httptest.NewRequest("GET", path, nil))
+
+			if rr.Code != http.StatusNotFound {
+				t.Errorf("expected not found for %s, got %d", path, rr.Code)
+			}
+		}
+	})
+}
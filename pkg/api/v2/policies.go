@@ -0,0 +1,80 @@
+package v2
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/helper"
+)
+
+// PolicyResults are the results of a single policy with its status
+type PolicyResults struct {
+	Policy  *PolicyStatus    `json:"policy"`
+	Results []*v1.ListResult `json:"results"`
+}
+
+// PolicyListHandler lists the policies with their status counts, severities and affected namespaces
+func PolicyListHandler(finder PolicyReportFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		filter := v1.BuildFilter(req)
+		if !v1.ValidateFilter(w, filter) {
+			return
+		}
+
+		filter.Status = nil
+		filter.IDs = nil
+
+		list, err := finder.FetchPolicyStatus(filter)
+		helper.SendJSONResponse(w, list, err)
+	}
+}
+
+// PolicyResultsHandler returns the results of the policy of the path /v2/policies/{policy}/results with its status,
+// the filter parameters only restrict the listed results
+func PolicyResultsHandler(finder PolicyReportFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		policy, ok := PolicyFromPath(req.URL.Path)
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+
+		filter := v1.BuildFilter(req)
+		if !v1.ValidateFilter(w, filter) {
+			return
+		}
+
+		filter.Policies = []string{policy}
+
+		statusFilter := filter
+		statusFilter.Status = nil
+		statusFilter.IDs = nil
+
+		policies, err := finder.FetchPolicyStatus(statusFilter)
+		if err != nil {
+			helper.SendJSONResponse(w, nil, err)
+			return
+		}
+		if len(policies) == 0 {
+			helper.SendError(w, http.StatusNotFound, fmt.Errorf("no results found for policy %s", policy))
+			return
+		}
+
+		results, err := finder.FetchResults(filter)
+		helper.SendJSONResponse(w, PolicyResults{Policy: policies[0], Results: results}, err)
+	}
+}
+
+// PolicyFromPath extracts the policy of the path /v2/policies/{policy}/results
+func PolicyFromPath(path string) (string, bool) {
+	policy := strings.TrimPrefix(path, "/v2/policies/")
+	if policy == path || !strings.HasSuffix(policy, "/results") {
+		return "", false
+	}
+
+	policy = strings.TrimSuffix(policy, "/results")
+
+	return policy, policy != "" && !strings.Contains(policy, "/")
+}
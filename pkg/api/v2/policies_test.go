@@ -0,0 +1,99 @@
+package v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+)
+
+type policyFinder struct {
+	v2.PolicyReportFinder
+	filter        v1.Filter
+	resultsFilter v1.Filter
+}
+
+func (f *policyFinder) FetchPolicyStatus(filter v1.Filter) ([]*v2.PolicyStatus, error) {
+	f.filter = filter
+	if len(filter.Policies) > 0 && filter.Policies[0] == "unknown" {
+		return []*v2.PolicyStatus{}, nil
+	}
+
+	return []*v2.PolicyStatus{{Policy: "require-labels", Sources: []string{"kyverno"}, Pass: 3, Fail: 2, Severities: map[string]int{"high": 2}, Namespaces: []string{"dev", "test"}, Resources: 2}}, nil
+}
+
+func (f *policyFinder) FetchResults(filter v1.Filter) ([]*v1.ListResult, error) {
+	f.resultsFilter = filter
+
+	return []*v1.ListResult{{ID: "123", Kind: "Pod", Name: "nginx", Namespace: "test", Policy: "require-labels", Status: "fail"}}, nil
+}
+
+func Test_PolicyListHandler(t *testing.T) {
+	finder := &policyFinder{}
+
+	rr := httptest.NewRecorder()
+	v2.PolicyListHandler(finder)(rr, httptest.NewRequest("GET", "/v2/policies?status=fail&namespaces=test", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status code %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(finder.filter.Status) != 0 || len(finder.filter.Namespaces) != 1 {
+		t.Errorf("expected all status counted with the remaining filters, got %+v", finder.filter)
+	}
+
+	list := make([]v2.PolicyStatus, 0)
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Severities["high"] != 2 || len(list[0].Namespaces) != 2 {
+		t.Errorf("unexpected policies %+v", list)
+	}
+}
+
+func Test_PolicyResultsHandler(t *testing.T) {
+	t.Run("Results", func(t *testing.T) {
+		finder := &policyFinder{}
+
+		rr := httptest.NewRecorder()
+		v2.PolicyResultsHandler(finder)(rr, httptest.NewRequest("GET", "/v2/policies/require-labels/results?status=fail&policies=other", nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("unexpected status code %d: %s", rr.Code, rr.Body.String())
+		}
+		if len(finder.filter.Policies) != 1 || finder.filter.Policies[0] != "require-labels" || len(finder.filter.Status) != 0 {
+			t.Errorf("expected the policy of the path without status filter, got %+v", finder.filter)
+		}
+		if len(finder.resultsFilter.Status) != 1 || finder.resultsFilter.Policies[0] != "require-labels" {
+			t.Errorf("expected the status filter for the results, got %+v", finder.resultsFilter)
+		}
+
+		result := v2.PolicyResults{}
+		if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+		if result.Policy.Policy != "require-labels" || len(result.Results) != 1 {
+			t.Errorf("unexpected response %s", rr.Body.String())
+		}
+	})
+
+	t.Run("Unknown policy", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		v2.PolicyResultsHandler(&policyFinder{})(rr, httptest.NewRequest("GET", "/v2/policies/unknown/results", nil))
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected not found, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Invalid path", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		v2.PolicyResultsHandler(&policyFinder{})(rr, httptest.NewRequest("GET", "/v2/policies/require-labels", nil))
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected not found, got %d", rr.Code)
+		}
+	})
+}
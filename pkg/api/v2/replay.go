@@ -0,0 +1,114 @@
+package v2
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/helper"
+	"github.com/kyverno/policy-reporter/pkg/listener"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/target"
+)
+
+// TargetReplayHandler sends the stored results matching the filter and the time range of from and to to the target
+// of the target parameter, used to backfill a target which lost data. The filters and the priority mapping of the target are applied,
+// from and to are RFC3339 or unix timestamps, to defaults to now and from includes all results before to
+func TargetReplayHandler(finder PolicyReportFinder, mapper report.Mapper) func([]target.Client) http.HandlerFunc {
+	return func(targets []target.Client) http.HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request) {
+			if req.Method != http.MethodPost {
+				w.Header().Set("Allow", http.MethodPost)
+				helper.SendError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+				return
+			}
+
+			query := req.URL.Query()
+
+			name := query.Get("target")
+			if name == "" {
+				helper.SendBadRequest(w, fmt.Errorf("the target parameter is required"))
+				return
+			}
+
+			var client target.Client
+			for _, t := range targets {
+				if t.Name() == name {
+					client = t
+					break
+				}
+			}
+
+			if client == nil {
+				helper.SendError(w, http.StatusNotFound, fmt.Errorf("target %s not found", name))
+				return
+			}
+
+			to, err := parseTime(query.Get("to"), time.Now())
+			if err != nil {
+				helper.SendBadRequest(w, err)
+				return
+			}
+
+			from, err := parseTime(query.Get("from"), time.Time{})
+			if err != nil {
+				helper.SendBadRequest(w, err)
+				return
+			}
+
+			if !from.Before(to) {
+				helper.SendBadRequest(w, fmt.Errorf("from has to be before to"))
+				return
+			}
+
+			filter := v1.BuildFilter(req)
+			if !v1.ValidateFilter(w, filter) {
+				return
+			}
+
+			list, err := finder.FetchResults(filter)
+			if err != nil {
+				helper.SendJSONResponse(w, nil, err)
+				return
+			}
+
+			helper.SendJSONResponse(w, Replay(client, mapper, list, from, to), nil)
+		}
+	}
+}
+
+// Replay sends the results with a timestamp within from and to which pass the filters of the client,
+// results without timestamp are skipped because their time is unknown
+func Replay(client target.Client, mapper report.Mapper, list []*v1.ListResult, from, to time.Time) TargetReplay {
+	replay := TargetReplay{Name: client.Name()}
+
+	for _, r := range list {
+		if r.Timestamp == 0 {
+			continue
+		}
+
+		timestamp := time.Unix(int64(r.Timestamp), 0)
+		if timestamp.Before(from) || timestamp.After(to) {
+			continue
+		}
+
+		replay.Results++
+
+		result := mapResult(r)
+		result.Timestamp = metav1.Timestamp{Seconds: int64(r.Timestamp)}
+
+		result, ok := listener.Matches(client, mapper, simulationReport(nil, result), result)
+		if !ok {
+			replay.Skipped++
+			continue
+		}
+
+		client.Send(client.Scrubber().Scrub(result))
+		replay.Sent++
+	}
+
+	return replay
+}
@@ -0,0 +1,116 @@
+package v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/webhook"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+type replayFinder struct {
+	v2.PolicyReportFinder
+	filter v1.Filter
+}
+
+func (f *replayFinder) FetchResults(filter v1.Filter) ([]*v1.ListResult, error) {
+	f.filter = filter
+
+	return []*v1.ListResult{
+		{ID: "1", Namespace: "test", Kind: "Pod", Name: "nginx", Policy: "require-requests", Status: "fail", Severity: "high", Timestamp: 1700000100},
+		{ID: "2", Namespace: "kube-system", Kind: "Pod", Name: "coredns", Policy: "require-requests", Status: "fail", Severity: "high", Timestamp: 1700000200},
+		{ID: "3", Namespace: "test", Kind: "Pod", Name: "redis", Policy: "require-requests", Status: "fail", Severity: "high", Timestamp: 1600000000},
+		{ID: "4", Namespace: "test", Kind: "Pod", Name: "postgres", Policy: "require-requests", Status: "fail", Severity: "high"},
+	}, nil
+}
+
+func Test_TargetReplayHandler(t *testing.T) {
+	var received int
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		received++
+	}))
+	defer receiver.Close()
+
+	targets := []target.Client{
+		webhook.NewClient(webhook.Options{
+			ClientOptions: target.ClientOptions{
+				Name:         "Webhook",
+				ResultFilter: target.NewResultFilter(validate.RuleSets{Exclude: []string{"kube-system"}}, validate.RuleSets{}, validate.RuleSets{}, "", nil),
+			},
+			Host:       receiver.URL,
+			HTTPClient: receiver.Client(),
+		}),
+	}
+
+	finder := &replayFinder{}
+
+	replay := func(t *testing.T, method, query string) (*httptest.ResponseRecorder, v2.TargetReplay) {
+		req, err := http.NewRequest(method, "/v2/targets/replay?"+query, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		v2.TargetReplayHandler(finder, report.NewMapper(nil))(targets).ServeHTTP(rr, req)
+
+		result := v2.TargetReplay{}
+		if rr.Code == http.StatusOK {
+			if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return rr, result
+	}
+
+	t.Run("Replay", func(t *testing.T) {
+		rr, result := replay(t, "POST", "target=Webhook&from=1700000000&to=2023-11-15T00:00:00Z&severities=high")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v: %s", rr.Code, http.StatusOK, rr.Body.String())
+		}
+		if result.Results != 2 || result.Sent != 1 || result.Skipped != 1 {
+			t.Errorf("expected 1 of 2 results sent and 1 skipped, got %+v", result)
+		}
+		if received != 1 {
+			t.Errorf("expected 1 result to be sent, got %d requests", received)
+		}
+		if len(finder.filter.Severities) != 1 || finder.filter.Severities[0] != "high" {
+			t.Errorf("expected the severity filter to be applied, got %+v", finder.filter)
+		}
+	})
+
+	t.Run("UnknownTarget", func(t *testing.T) {
+		rr, _ := replay(t, "POST", "target=Slack")
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("MissingTarget", func(t *testing.T) {
+		rr, _ := replay(t, "POST", "")
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("InvalidRange", func(t *testing.T) {
+		rr, _ := replay(t, "POST", "target=Webhook&from=1700000200&to=1700000100")
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		rr, _ := replay(t, "GET", "target=Webhook")
+		if rr.Code != http.StatusMethodNotAllowed {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusMethodNotAllowed)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package v2
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/helper"
+)
+
+// ReportListHandler lists the PolicyReports and ClusterPolicyReports with their metadata and summary,
+// the cluster namespace selects ClusterPolicyReports
+func ReportListHandler(finder PolicyReportFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		filter := v1.BuildFilter(req)
+		count, _ := finder.CountReports(filter)
+		list, cursor, err := finder.FetchReportPage(filter, buildPagination(req))
+		sendPage(w, req, list, cursor, count, err)
+	}
+}
+
+// ReportHandler returns the report of the path /v2/reports/{id} with its metadata,
+// the raw query parameter includes the complete report with its results
+func ReportHandler(finder PolicyReportFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		id, ok := ReportIDFromPath(req.URL.Path)
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+
+		raw, _ := strconv.ParseBool(req.URL.Query().Get("raw"))
+
+		r, err := finder.FetchReport(id, raw)
+		if err != nil {
+			helper.SendJSONResponse(w, nil, err)
+			return
+		}
+
+		// namespaces is restricted to the accessible namespaces if the access is reviewed
+		filter := v1.BuildFilter(req)
+		if r == nil || (len(filter.Namespaces) > 0 && !helper.Contains(r.Namespace, filter.Namespaces)) {
+			helper.SendError(w, http.StatusNotFound, fmt.Errorf("report %s not found", id))
+			return
+		}
+
+		helper.SendJSONResponse(w, r, nil)
+	}
+}
+
+// ReportIDFromPath extracts the report ID of the path /v2/reports/{id}
+func ReportIDFromPath(path string) (string, bool) {
+	id := strings.TrimPrefix(path, "/v2/reports/")
+	if id == path || id == "" || strings.Contains(id, "/") {
+		return "", false
+	}
+
+	return id, true
+}
@@ -0,0 +1,104 @@
+package v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+)
+
+type reportFinder struct {
+	v2.PolicyReportFinder
+	filter v1.Filter
+	raw    bool
+}
+
+func (f *reportFinder) FetchReportPage(filter v1.Filter, _ v2.Pagination) ([]*v2.Report, *v2.Cursor, error) {
+	f.filter = filter
+
+	return []*v2.Report{{ID: "1", Kind: "PolicyReport", Name: "polr-test", Namespace: "test", Source: "kyverno", Fail: 1}}, nil, nil
+}
+
+func (f *reportFinder) CountReports(filter v1.Filter) (int, error) {
+	return 1, nil
+}
+
+func (f *reportFinder) FetchReport(id string, raw bool) (*v2.Report, error) {
+	f.raw = raw
+	if id == "unknown" {
+		return nil, nil
+	}
+
+	r := &v2.Report{ID: id, Kind: "PolicyReport", Name: "polr-test", Namespace: "test", Source: "kyverno"}
+	if raw {
+		r.Raw = json.RawMessage(`{"kind":"PolicyReport"}`)
+	}
+
+	return r, nil
+}
+
+func Test_ReportListHandler(t *testing.T) {
+	finder := &reportFinder{}
+
+	rr := httptest.NewRecorder()
+	v2.ReportListHandler(finder)(rr, httptest.NewRequest("GET", "/v2/reports?namespaces=test&namespaces=cluster&sources=kyverno", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status code %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(finder.filter.Namespaces) != 2 || finder.filter.Namespaces[1] != "" {
+		t.Errorf("expected the cluster namespace to select cluster scoped reports, got %v", finder.filter.Namespaces)
+	}
+	if rr.Header().Get(v2.TotalCountHeader) != "1" {
+		t.Errorf("expected total count header, got %s", rr.Header().Get(v2.TotalCountHeader))
+	}
+}
+
+func Test_ReportHandler(t *testing.T) {
+	finder := &reportFinder{}
+	handler := v2.ReportHandler(finder)
+
+	t.Run("Report", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/v2/reports/123", nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("unexpected status code %d: %s", rr.Code, rr.Body.String())
+		}
+
+		r := v2.Report{}
+		if err := json.Unmarshal(rr.Body.Bytes(), &r); err != nil {
+			t.Fatal(err)
+		}
+		if r.ID != "123" || r.Raw != nil || finder.raw {
+			t.Errorf("unexpected report %+v", r)
+		}
+	})
+
+	t.Run("Raw", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/v2/reports/123?raw=true", nil))
+
+		r := v2.Report{}
+		if err := json.Unmarshal(rr.Body.Bytes(), &r); err != nil {
+			t.Fatal(err)
+		}
+		if string(r.Raw) != `{"kind":"PolicyReport"}` {
+			t.Errorf("expected raw report, got %s", r.Raw)
+		}
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		for _, path := range []string{"/v2/reports/unknown", "/v2/reports/", "/v2/reports/123/results", "/v2/reports/123?namespaces=other"} {
+			rr := httptest.NewRecorder()
+			handler(rr, httptest.NewRequest("GET", path, nil))
+
+			if rr.Code != http.StatusNotFound {
+				t.Errorf("expected not found for %s, got %d", path, rr.Code)
+			}
+		}
+	})
+}
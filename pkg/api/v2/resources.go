@@ -0,0 +1,134 @@
+package v2
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/helper"
+)
+
+// ResourceResults are the results of a single resource across all sources
+type ResourceResults struct {
+	Resource *ResourceStatus  `json:"resource"`
+	Results  []*v1.ListResult `json:"results"`
+	// UIDs of all resources with the name, if the resource was recreated and the results of several UIDs exist
+	UIDs []string `json:"uids,omitempty"`
+}
+
+// NamespaceResourcesHandler lists the resources of the namespace of the path /v2/namespaces/{namespace}/resources
+// with their status counts across all sources
+func NamespaceResourcesHandler(finder PolicyReportFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		namespace, ok := NamespaceFromPath(req.URL.Path, "resources")
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+
+		filter := v1.BuildFilter(req)
+		if !v1.ValidateFilter(w, filter) {
+			return
+		}
+
+		filter.Namespaces = []string{namespace}
+		filter.Status = nil
+		filter.IDs = nil
+
+		list, err := finder.FetchResourceStatus(filter)
+		helper.SendJSONResponse(w, list, err)
+	}
+}
+
+// ResourceResultsHandler returns the results of the resource of the path /v2/resources/{kind}/{name}/results,
+// the namespace query parameter selects namespaced resources and the uid query parameter one of several resources with the name
+func ResourceResultsHandler(finder PolicyReportFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		kind, name, ok := ResourceFromPath(req.URL.Path)
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+
+		filter := v1.BuildFilter(req)
+		if !v1.ValidateFilter(w, filter) {
+			return
+		}
+
+		filter.Kinds = []string{kind}
+		filter.Resources = []string{name}
+		filter.Namespaces = []string{req.URL.Query().Get("namespace")}
+		filter.Status = nil
+		filter.IDs = nil
+		filter.UIDs = nil
+		if uid := req.URL.Query().Get("uid"); uid != "" {
+			filter.UIDs = []string{uid}
+		}
+
+		resources, err := finder.FetchResourceStatus(filter)
+		if err != nil {
+			helper.SendJSONResponse(w, nil, err)
+			return
+		}
+		if len(resources) == 0 {
+			helper.SendError(w, http.StatusNotFound, fmt.Errorf("no results found for %s %s", kind, name))
+			return
+		}
+
+		response := ResourceResults{Resource: resources[0]}
+		if len(resources) > 1 {
+			for _, r := range resources {
+				response.UIDs = append(response.UIDs, r.UID)
+			}
+
+			filter.UIDs = []string{resources[0].UID}
+		}
+
+		response.Results, err = finder.FetchResults(filter)
+		helper.SendJSONResponse(w, response, err)
+	}
+}
+
+// ResourceFromPath extracts kind and name of the path /v2/resources/{kind}/{name}/results
+func ResourceFromPath(path string) (string, string, bool) {
+	resource := strings.TrimPrefix(path, "/v2/resources/")
+	if resource == path || !strings.HasSuffix(resource, "/results") {
+		return "", "", false
+	}
+
+	parts := strings.Split(strings.TrimSuffix(resource, "/results"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+
+	return parts[0], parts[1], true
+}
+
+// ResourceHistoryHandler returns the history of a resource, selected by the kind, name and namespace query parameters
+// or by the uid query parameter, each recreation of the resource with a new UID is returned as separate entry
+func ResourceHistoryHandler(finder HistoryFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		params := req.URL.Query()
+
+		query := ResourceQuery{
+			Kind:      params.Get("kind"),
+			Name:      params.Get("name"),
+			Namespace: params.Get("namespace"),
+			UID:       params.Get("uid"),
+		}
+
+		if query.UID == "" && (query.Kind == "" || query.Name == "") {
+			helper.SendBadRequest(w, fmt.Errorf("either uid or kind and name are required"))
+			return
+		}
+
+		list, err := finder.FetchResourceHistory(query)
+		if err == nil && len(list) == 0 {
+			helper.SendError(w, http.StatusNotFound, fmt.Errorf("no history found for the resource"))
+			return
+		}
+
+		helper.SendJSONResponse(w, list, err)
+	}
+}
@@ -0,0 +1,189 @@
+package v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+)
+
+type resourceFinder struct {
+	v2.PolicyReportFinder
+	filter  v1.Filter
+	results v1.Filter
+}
+
+func (f *resourceFinder) FetchResourceStatus(filter v1.Filter) ([]*v2.ResourceStatus, error) {
+	f.filter = filter
+	if len(filter.Resources) > 0 && filter.Resources[0] == "unknown" {
+		return []*v2.ResourceStatus{}, nil
+	}
+	if len(filter.Resources) > 0 && filter.Resources[0] == "recreated" && len(filter.UIDs) == 0 {
+		return []*v2.ResourceStatus{{Kind: "Pod", Name: "recreated", UID: "a1"}, {Kind: "Pod", Name: "recreated", UID: "b2"}}, nil
+	}
+
+	return []*v2.ResourceStatus{{Kind: "Pod", Name: "nginx", Namespace: "test", Sources: []string{"kyverno", "trivy"}, Pass: 2, Fail: 1}}, nil
+}
+
+func (f *resourceFinder) FetchResults(filter v1.Filter) ([]*v1.ListResult, error) {
+	f.results = filter
+	return []*v1.ListResult{{ID: "123", Kind: "Pod", Name: "nginx", Status: "fail"}}, nil
+}
+
+func Test_NamespaceResourcesHandler(t *testing.T) {
+	finder := &resourceFinder{}
+	handler := v2.NamespaceResourcesHandler(finder)
+
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest("GET", "/v2/namespaces/test/resources?namespaces=other&status=fail&sources=kyverno", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status code %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(finder.filter.Namespaces) != 1 || finder.filter.Namespaces[0] != "test" {
+		t.Errorf("expected the namespace of the path as filter, got %v", finder.filter.Namespaces)
+	}
+	if len(finder.filter.Status) != 0 || len(finder.filter.Sources) != 1 {
+		t.Errorf("expected all status counted with the remaining filters, got %+v", finder.filter)
+	}
+
+	list := make([]v2.ResourceStatus, 0)
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Pass != 2 || len(list[0].Sources) != 2 {
+		t.Errorf("unexpected resources %+v", list)
+	}
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest("GET", "/v2/namespaces/test/other", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected not found for unknown paths, got %d", rr.Code)
+	}
+}
+
+func Test_ResourceResultsHandler(t *testing.T) {
+	finder := &resourceFinder{}
+	handler := v2.ResourceResultsHandler(finder)
+
+	t.Run("Results", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/v2/resources/Pod/nginx/results?namespace=test", nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("unexpected status code %d: %s", rr.Code, rr.Body.String())
+		}
+		if finder.filter.Kinds[0] != "Pod" || finder.filter.Resources[0] != "nginx" || finder.filter.Namespaces[0] != "test" {
+			t.Errorf("unexpected filter %+v", finder.filter)
+		}
+
+		res := v2.ResourceResults{}
+		if err := json.Unmarshal(rr.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		if res.Resource == nil || res.Resource.Fail != 1 || len(res.Results) != 1 {
+			t.Errorf("unexpected resource results %+v", res)
+		}
+	})
+
+	t.Run("Cluster Scoped", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/v2/resources/Namespace/test/results", nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("unexpected status code %d", rr.Code)
+		}
+		if len(finder.filter.Namespaces) != 1 || finder.filter.Namespaces[0] != "" {
+			t.Errorf("expected an empty namespace filter for cluster scoped resources, got %v", finder.filter.Namespaces)
+		}
+	})
+
+	t.Run("Recreated", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/v2/resources/Pod/recreated/results?namespace=test", nil))
+
+		res := v2.ResourceResults{}
+		if err := json.Unmarshal(rr.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		if len(res.UIDs) != 2 || res.Resource.UID != "a1" {
+			t.Errorf("expected the first of both UIDs, got %+v", res)
+		}
+		if len(finder.results.UIDs) != 1 || finder.results.UIDs[0] != "a1" {
+			t.Errorf("expected the results filtered by the UID of the resource, got %v", finder.results.UIDs)
+		}
+
+		rr = httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/v2/resources/Pod/recreated/results?namespace=test&uid=b2", nil))
+
+		if len(finder.filter.UIDs) != 1 || finder.filter.UIDs[0] != "b2" {
+			t.Errorf("expected the uid parameter as filter, got %v", finder.filter.UIDs)
+		}
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		for _, path := range []string{"/v2/resources/Pod/unknown/results", "/v2/resources/Pod/results", "/v2/resources/Pod/nginx/status"} {
+			rr := httptest.NewRecorder()
+			handler(rr, httptest.NewRequest("GET", path, nil))
+
+			if rr.Code != http.StatusNotFound {
+				t.Errorf("expected not found for %s, got %d", path, rr.Code)
+			}
+		}
+	})
+}
+
+func Test_ResourceHistoryHandler(t *testing.T) {
+	finder := &diffFinder{}
+	handler := v2.ResourceHistoryHandler(finder)
+
+	t.Run("By Name", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/v2/history/resources?kind=Pod&name=nginx&namespace=test", nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("unexpected status code %d: %s", rr.Code, rr.Body.String())
+		}
+		if finder.resource != (v2.ResourceQuery{Kind: "Pod", Name: "nginx", Namespace: "test"}) {
+			t.Errorf("unexpected query %+v", finder.resource)
+		}
+
+		list := make([]v2.ResourceHistory, 0)
+		if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+			t.Fatal(err)
+		}
+		if len(list) != 2 || list[1].UID != "b2" {
+			t.Errorf("unexpected history %+v", list)
+		}
+	})
+
+	t.Run("By UID", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/v2/history/resources?uid=b2", nil))
+
+		if rr.Code != http.StatusOK || finder.resource.UID != "b2" {
+			t.Errorf("unexpected response %d for query %+v", rr.Code, finder.resource)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/v2/history/resources?kind=Pod", nil))
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected bad request without name or uid, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/v2/history/resources?kind=Pod&name=unknown", nil))
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected not found, got %d", rr.Code)
+		}
+	})
+}
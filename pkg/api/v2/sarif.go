@@ -0,0 +1,70 @@
+package v2
+
+import (
+	"encoding/json"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/helper"
+	"github.com/kyverno/policy-reporter/pkg/sarif"
+)
+
+// SARIFContentType of the exported SARIF log
+const SARIFContentType = "application/sarif+json"
+
+// SARIFHandler REST API, exports the filtered results as SARIF log with one run per source and the policies as rules
+func SARIFHandler(finder PolicyReportFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		filter := v1.BuildFilter(req)
+		if !v1.ValidateFilter(w, filter) {
+			return
+		}
+
+		list, err := finder.FetchResults(filter)
+		if err != nil {
+			helper.SendJSONResponse(w, nil, err)
+			return
+		}
+
+		results := make([]v1alpha2.PolicyReportResult, 0, len(list))
+		for _, r := range list {
+			results = append(results, mapResult(r))
+		}
+
+		w.Header().Set("Content-Type", SARIFContentType)
+		if req.URL.Query().Get("download") == "true" {
+			w.Header().Set("Content-Disposition", `attachment; filename="policy-reporter.sarif"`)
+		}
+
+		_ = json.NewEncoder(w).Encode(sarif.NewLog(results))
+	}
+}
+
+// mapResult converts a stored result back into a PolicyReportResult
+func mapResult(r *v1.ListResult) v1alpha2.PolicyReportResult {
+	result := v1alpha2.PolicyReportResult{
+		ID:         r.ID,
+		Source:     r.Source,
+		Policy:     r.Policy,
+		Rule:       r.Rule,
+		Message:    r.Message,
+		Result:     v1alpha2.PolicyResult(r.Status),
+		Severity:   v1alpha2.PolicySeverity(r.Severity),
+		Category:   r.Category,
+		Properties: r.Properties,
+	}
+
+	if r.Name != "" {
+		result.Resources = []corev1.ObjectReference{{
+			APIVersion: r.APIVersion,
+			Kind:       r.Kind,
+			Name:       r.Name,
+			Namespace:  r.Namespace,
+		}}
+	}
+
+	return result
+}
@@ -0,0 +1,123 @@
+package v2
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/helper"
+)
+
+// Types of the search hits
+const (
+	SearchTypePolicy    = "policy"
+	SearchTypeNamespace = "namespace"
+	SearchTypeResource  = "resource"
+	SearchTypeResult    = "result"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// SearchHit is a policy, namespace, resource or result matching the search term
+type SearchHit struct {
+	// Type discriminates the hit: policy, namespace, resource or result
+	Type string `json:"type"`
+	// Name of the policy, namespace or resource, the policy of result hits
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Kind      string `json:"kind,omitempty"`
+	Source    string `json:"source,omitempty"`
+	// Results of the policy, namespace or resource
+	Results int `json:"results,omitempty"`
+	// Result of result hits, matched by rule or message
+	Result *v1.ListResult `json:"result,omitempty"`
+	// Score of the hit, exact matches rank before prefix, word and substring matches
+	Score int `json:"score"`
+	// Match is the matched value, e.g. the message of result hits
+	Match string `json:"-"`
+}
+
+var typeRank = map[string]int{
+	SearchTypePolicy:    3,
+	SearchTypeNamespace: 2,
+	SearchTypeResource:  1,
+	SearchTypeResult:    0,
+}
+
+// RankSearchHits scores the hits by how well their matched value fits the term and returns the best hits up to the limit,
+// equal scores are ordered by type and name
+func RankSearchHits(term string, hits []*SearchHit, limit int) []*SearchHit {
+	term = strings.ToLower(term)
+
+	for _, hit := range hits {
+		hit.Score = matchScore(term, strings.ToLower(hit.Match))*10 + typeRank[hit.Type]
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+
+		return hits[i].Name < hits[j].Name
+	})
+
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	return hits
+}
+
+func matchScore(term, value string) int {
+	switch {
+	case value == term:
+		return 10
+	case strings.HasPrefix(value, term):
+		return 6
+	case strings.Contains(value, " "+term) || strings.Contains(value, "-"+term) || strings.Contains(value, "/"+term) || strings.Contains(value, "."+term):
+		return 4
+	case strings.Contains(value, term):
+		return 2
+	default:
+		return 0
+	}
+}
+
+// SearchHandler searches policies, namespaces, resources and result messages for the term of the q parameter,
+// the filter parameters restrict the searched results
+func SearchHandler(finder PolicyReportFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		term := strings.TrimSpace(req.URL.Query().Get("q"))
+		if term == "" {
+			helper.SendBadRequest(w, fmt.Errorf("the search term q is required"))
+			return
+		}
+
+		filter := v1.BuildFilter(req)
+		if !v1.ValidateFilter(w, filter) {
+			return
+		}
+
+		limit, err := strconv.Atoi(req.URL.Query().Get("limit"))
+		if err != nil || limit < 1 {
+			limit = defaultSearchLimit
+		}
+		if limit > maxSearchLimit {
+			limit = maxSearchLimit
+		}
+
+		hits, err := finder.Search(term, filter, limit)
+		if err != nil {
+			helper.SendJSONResponse(w, nil, err)
+			return
+		}
+
+		helper.SendJSONResponse(w, RankSearchHits(term, hits, limit), nil)
+	}
+}
@@ -0,0 +1,82 @@
+package v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+)
+
+type searchFinder struct {
+	v2.PolicyReportFinder
+	term   string
+	filter v1.Filter
+	limit  int
+}
+
+func (f *searchFinder) Search(term string, filter v1.Filter, limit int) ([]*v2.SearchHit, error) {
+	f.term = term
+	f.filter = filter
+	f.limit = limit
+
+	return []*v2.SearchHit{
+		{Type: v2.SearchTypeResult, Name: "disallow-latest-tag", Match: "image uses the registry docker.io"},
+		{Type: v2.SearchTypeResource, Name: "registry-cache", Match: "registry-cache"},
+		{Type: v2.SearchTypePolicy, Name: "restrict-image-registry", Match: "restrict-image-registry"},
+		{Type: v2.SearchTypeNamespace, Name: "registry", Match: "registry"},
+	}, nil
+}
+
+func Test_RankSearchHits(t *testing.T) {
+	finder := &searchFinder{}
+	hits, _ := finder.Search("", v1.Filter{}, 0)
+
+	ranked := v2.RankSearchHits("Registry", hits, 3)
+	if len(ranked) != 3 {
+		t.Fatalf("Expected hits to be limited, got %d", len(ranked))
+	}
+
+	for i, name := range []string{"registry", "registry-cache", "restrict-image-registry"} {
+		if ranked[i].Name != name {
+			t.Errorf("Expected %s at position %d, got %s", name, i, ranked[i].Name)
+		}
+	}
+	if ranked[0].Score <= ranked[1].Score {
+		t.Errorf("Expected exact match to score higher than prefix match")
+	}
+}
+
+func Test_SearchHandler(t *testing.T) {
+	t.Run("Ranked hits", func(t *testing.T) {
+		finder := &searchFinder{}
+
+		rr := httptest.NewRecorder()
+		v2.SearchHandler(finder)(rr, httptest.NewRequest("GET", "/v2/search?q=registry&limit=2&namespaces=test", nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("unexpected status code %d: %s", rr.Code, rr.Body.String())
+		}
+		if finder.term != "registry" || finder.limit != 2 || len(finder.filter.Namespaces) != 1 {
+			t.Errorf("unexpected search arguments: %s, %d, %+v", finder.term, finder.limit, finder.filter)
+		}
+
+		hits := []*v2.SearchHit{}
+		json.Unmarshal(rr.Body.Bytes(), &hits)
+
+		if len(hits) != 2 || hits[0].Type != v2.SearchTypeNamespace {
+			t.Errorf("unexpected hits: %s", rr.Body.String())
+		}
+	})
+
+	t.Run("Missing term", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		v2.SearchHandler(&searchFinder{})(rr, httptest.NewRequest("GET", "/v2/search?q=%20", nil))
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected bad request, got %d", rr.Code)
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package v2
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/helper"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/stream"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+var logger = logging.Component(logging.API)
+
+// keepAlive interval of idle streams, prevents proxies from closing the connection
+var keepAlive = 15 * time.Second
+
+// checkOrigin allows WebSocket upgrades from the host of the API and the allowed origins with wildcard support,
+// so other sites can not open the stream with the credentials the browser attaches to cross-site requests.
+// Requests without Origin header are not sent by browsers and allowed
+func checkOrigin(origins []string) func(req *http.Request) bool {
+	return func(req *http.Request) bool {
+		origin := req.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil {
+			return false
+		}
+		if strings.EqualFold(u.Host, req.Host) {
+			return true
+		}
+
+		return validate.MatchAny(origins, origin)
+	}
+}
+
+// ResultStreamHandler streams new, updated and resolved results over WebSocket or Server-Sent Events,
+// origins lists the origins besides the API host allowed to open a WebSocket
+func ResultStreamHandler(broker *stream.Broker, origins []string) http.HandlerFunc {
+	upgrader := websocket.Upgrader{CheckOrigin: checkOrigin(origins)}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		types := make([]string, 0)
+		for _, t := range req.URL.Query()["types"] {
+			types = append(types, strings.Split(t, ",")...)
+		}
+
+		filter, err := stream.NewFilter(v1.BuildFilter(req), types)
+		if err != nil {
+			helper.SendBadRequest(w, err)
+			return
+		}
+
+		if websocket.IsWebSocketUpgrade(req) {
+			streamWebSocket(w, req, upgrader, broker, filter)
+			return
+		}
+
+		streamEvents(w, req, broker, filter)
+	}
+}
+
+func streamEvents(w http.ResponseWriter, req *http.Request, broker *stream.Broker, filter stream.Filter) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		helper.SendJSONResponse(w, nil, fmt.Errorf("streaming is not supported"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	id, events := broker.Subscribe()
+	defer broker.Unsubscribe(id)
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case <-ticker.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case event := <-events:
+			if !filter.Match(event) {
+				continue
+			}
+
+			data, err := json.Marshal(event)
+			if err != nil {
+				logger.Errorf("failed to encode stream event: %s", err)
+				continue
+			}
+
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+		}
+
+		flusher.Flush()
+	}
+}
+
+func streamWebSocket(w http.ResponseWriter, req *http.Request, upgrader websocket.Upgrader, broker *stream.Broker, filter stream.Filter) {
+	conn, err := upgrader.Upgrade(w, req, nil)
+	if err != nil {
+		logger.Errorf("failed to upgrade result stream: %s", err)
+		return
+	}
+	defer conn.Close()
+
+	id, events := broker.Subscribe()
+	defer broker.Unsubscribe(id)
+
+	// the reader detects closed connections, messages of the client are ignored
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(keepAlive)); err != nil {
+				return
+			}
+		case event := <-events:
+			if !filter.Match(event) {
+				continue
+			}
+
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		}
+	}
+}
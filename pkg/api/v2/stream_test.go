@@ -0,0 +1,139 @@
+package v2_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/stream"
+)
+
+// publish until the test client is subscribed and received an event
+func publish(broker *stream.Broker, done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		broker.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: &v1alpha2.PolicyReport{
+			ObjectMeta: metav1.ObjectMeta{Name: "polr-test", Namespace: "test", UID: "uid"},
+			Results:    []v1alpha2.PolicyReportResult{fixtures.PassPodResult, fixtures.FailResult},
+		}})
+		broker.Listen(report.LifecycleEvent{Type: report.Deleted, PolicyReport: &v1alpha2.PolicyReport{
+			ObjectMeta: metav1.ObjectMeta{Name: "polr-test", Namespace: "test", UID: "uid"},
+		}})
+	}
+}
+
+func Test_ResultStream(t *testing.T) {
+	broker := stream.NewBroker(10)
+	server := httptest.NewServer(v2.ResultStreamHandler(broker, []string{"https://*.example.com"}))
+	defer server.Close()
+
+	t.Run("Server-Sent Events", func(t *testing.T) {
+		done := make(chan struct{})
+		defer close(done)
+
+		client := &http.Client{Timeout: 5 * time.Second}
+		resp, err := client.Get(server.URL + "?types=new&status=fail")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+			t.Errorf("unexpected content type %s", ct)
+		}
+
+		go publish(broker, done)
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+
+			event := stream.Event{}
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err != nil {
+				t.Fatal(err)
+			}
+			if event.Type != stream.New || event.Result.Status != v1alpha2.StatusFail {
+				t.Errorf("expected only new failed results, got %s with %s", event.Type, event.Result.Status)
+			}
+
+			return
+		}
+
+		t.Fatalf("stream closed without event: %v", scanner.Err())
+	})
+
+	t.Run("WebSocket", func(t *testing.T) {
+		done := make(chan struct{})
+		defer close(done)
+
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"?types=resolved", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		go publish(broker, done)
+
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+		event := stream.Event{}
+		if err := conn.ReadJSON(&event); err != nil {
+			t.Fatal(err)
+		}
+		if event.Type != stream.Resolved {
+			t.Errorf("expected resolved event, got %s", event.Type)
+		}
+	})
+
+	t.Run("WebSocket Origin", func(t *testing.T) {
+		url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+		for origin, allowed := range map[string]bool{
+			server.URL:                    true,
+			"https://ui.example.com":      true,
+			"https://attacker.example.io": false,
+		} {
+			conn, resp, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": []string{origin}})
+			if allowed && err != nil {
+				t.Errorf("expected origin %s to be allowed: %s", origin, err)
+			}
+			if !allowed && (err == nil || resp == nil || resp.StatusCode != http.StatusForbidden) {
+				t.Errorf("expected origin %s to be rejected", origin)
+			}
+			if conn != nil {
+				conn.Close()
+			}
+		}
+	})
+
+	t.Run("Invalid Filter", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "?filter=severity>unknown")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", resp.StatusCode)
+		}
+	})
+}
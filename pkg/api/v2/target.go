@@ -0,0 +1,205 @@
+package v2
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/health"
+	"github.com/kyverno/policy-reporter/pkg/helper"
+	"github.com/kyverno/policy-reporter/pkg/listener"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/target"
+)
+
+const (
+	// TestPolicy is the policy name of the synthetic test results
+	TestPolicy = "policy-reporter-test"
+
+	TestStatusSuccess = "success"
+	TestStatusFailed  = "failed"
+	// TestStatusUnknown is returned for targets which do not record their deliveries
+	TestStatusUnknown = "unknown"
+)
+
+// TargetsHandler lists the configured targets with their filters and last delivery
+func TargetsHandler(targets []target.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		deliveries := health.TargetDeliveries.List()
+
+		list := make([]Target, 0, len(targets))
+		for _, t := range targets {
+			item := mapTarget(t)
+			if delivery, ok := deliveries[t.Name()]; ok {
+				item.Delivery = &delivery
+			}
+
+			list = append(list, item)
+		}
+
+		helper.SendJSONResponse(w, list, nil)
+	}
+}
+
+// TargetTestHandler sends a synthetic fail result to the target of the path /v2/targets/{name}/test,
+// the target filters are ignored
+func TargetTestHandler(targets []target.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		name, ok := testTargetName(req.URL.Path)
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			helper.SendError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+			return
+		}
+
+		var client target.Client
+		for _, t := range targets {
+			if t.Name() == name {
+				client = t
+				break
+			}
+		}
+
+		if client == nil {
+			helper.SendError(w, http.StatusNotFound, fmt.Errorf("target %s not found", name))
+			return
+		}
+
+		started := time.Now()
+		client.Send(NewTestResult(started))
+
+		helper.SendJSONResponse(w, testDelivery(name, started), nil)
+	}
+}
+
+// TargetSimulationHandler evaluates the posted result against the filters of all targets without sending it,
+// the priority is mapped like for results of a PolicyReport
+func TargetSimulationHandler(mapper report.Mapper) func([]target.Client) http.HandlerFunc {
+	return func(targets []target.Client) http.HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request) {
+			if req.Method != http.MethodPost {
+				w.Header().Set("Allow", http.MethodPost)
+				helper.SendError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+				return
+			}
+
+			simulation := TargetSimulationRequest{}
+			if err := json.NewDecoder(req.Body).Decode(&simulation); err != nil {
+				helper.SendBadRequest(w, fmt.Errorf("invalid simulation: %w", err))
+				return
+			}
+
+			result := simulation.Result
+			if result.Result == "" || result.Policy == "" {
+				helper.SendBadRequest(w, fmt.Errorf("invalid simulation: result.policy and result.result are required"))
+				return
+			}
+
+			rep := simulationReport(simulation.ReportLabels, result)
+
+			list := make([]TargetSimulation, 0, len(targets))
+			for _, t := range targets {
+				mapped, matched := listener.Matches(t, mapper, rep, result)
+
+				item := TargetSimulation{Name: t.Name(), Matched: matched}
+				if mapped.Priority != v1alpha2.DefaultPriority {
+					item.Priority = mapped.Priority.String()
+				}
+
+				list = append(list, item)
+			}
+
+			helper.SendJSONResponse(w, list, nil)
+		}
+	}
+}
+
+// simulationReport creates the PolicyReport of the result resource namespace or a ClusterPolicyReport without it
+func simulationReport(labels map[string]string, result v1alpha2.PolicyReportResult) v1alpha2.ReportInterface {
+	meta := metav1.ObjectMeta{Name: "policy-reporter-simulation", Labels: labels}
+
+	if result.HasResource() && result.GetResource().Namespace != "" {
+		meta.Namespace = result.GetResource().Namespace
+
+		return &v1alpha2.PolicyReport{ObjectMeta: meta, Results: []v1alpha2.PolicyReportResult{result}}
+	}
+
+	return &v1alpha2.ClusterPolicyReport{ObjectMeta: meta, Results: []v1alpha2.PolicyReportResult{result}}
+}
+
+// NewTestResult creates the synthetic result send by the target test
+func NewTestResult(timestamp time.Time) v1alpha2.PolicyReportResult {
+	return v1alpha2.PolicyReportResult{
+		Source:   "Policy Reporter",
+		Policy:   TestPolicy,
+		Rule:     "test-notification",
+		Message:  "Test notification sent by Policy Reporter to verify the target configuration",
+		Result:   v1alpha2.StatusFail,
+		Severity: v1alpha2.SeverityHigh,
+		Priority: v1alpha2.ErrorPriority,
+		Category: "Test",
+		Resources: []corev1.ObjectReference{{
+			APIVersion: "v1",
+			Kind:       "Namespace",
+			Name:       "policy-reporter-test",
+		}},
+		Timestamp: metav1.Timestamp{Seconds: timestamp.Unix()},
+	}
+}
+
+// testTargetName extracts the target name of the path /v2/targets/{name}/test
+func testTargetName(path string) (string, bool) {
+	name := strings.TrimPrefix(path, "/v2/targets/")
+	if name == path || !strings.HasSuffix(name, "/test") {
+		return "", false
+	}
+
+	name = strings.TrimSuffix(name, "/test")
+
+	return name, name != ""
+}
+
+// testDelivery evaluates the delivery recorded since the test result was sent
+func testDelivery(name string, started time.Time) TargetTest {
+	test := TargetTest{Name: name, Status: TestStatusUnknown}
+
+	delivery, ok := health.TargetDeliveries.List()[name]
+	if !ok {
+		return test
+	}
+
+	if delivery.LastError != nil && !delivery.LastError.Before(started) {
+		test.Status = TestStatusFailed
+		test.Error = delivery.Error
+	} else if delivery.LastSuccess != nil && !delivery.LastSuccess.Before(started) {
+		test.Status = TestStatusSuccess
+	}
+
+	return test
+}
+
+func mapTarget(t target.Client) Target {
+	minPrio := t.MinimumPriority()
+	if minPrio == "" {
+		minPrio = v1alpha2.DebugPriority.String()
+	}
+
+	return Target{
+		Name:                  t.Name(),
+		MinimumPriority:       minPrio,
+		Sources:               t.Sources(),
+		SkipExistingOnStartup: t.SkipExistingOnStartup(),
+		Concurrency:           t.Concurrency(),
+	}
+}
@@ -0,0 +1,200 @@
+package v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	targethttp "github.com/kyverno/policy-reporter/pkg/target/http"
+	"github.com/kyverno/policy-reporter/pkg/target/loki"
+	"github.com/kyverno/policy-reporter/pkg/target/webhook"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+func Test_TargetHandler(t *testing.T) {
+	var received int
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		received++
+		if req.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer receiver.Close()
+
+	targets := []target.Client{
+		webhook.NewClient(webhook.Options{
+			ClientOptions: target.ClientOptions{Name: "Webhook Success", Concurrency: 3},
+			Host:          receiver.URL + "/success",
+			HTTPClient:    receiver.Client(),
+		}),
+		webhook.NewClient(webhook.Options{
+			ClientOptions: target.ClientOptions{Name: "Webhook Fail"},
+			Host:          receiver.URL + "/fail",
+			HTTPClient:    receiver.Client(),
+		}),
+	}
+
+	test := func(t *testing.T, method, path string) (*httptest.ResponseRecorder, v2.TargetTest) {
+		req, err := http.NewRequest(method, path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		v2.TargetTestHandler(targets).ServeHTTP(rr, req)
+
+		result := v2.TargetTest{}
+		if rr.Code == http.StatusOK {
+			if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return rr, result
+	}
+
+	t.Run("TestSuccess", func(t *testing.T) {
+		rr, result := test(t, "POST", "/v2/targets/Webhook%20Success/test")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+		if result.Status != v2.TestStatusSuccess {
+			t.Errorf("expected status success, got %s", result.Status)
+		}
+		if received != 1 {
+			t.Errorf("expected the test result to be sent, got %d requests", received)
+		}
+	})
+
+	t.Run("TestFailure", func(t *testing.T) {
+		_, result := test(t, "POST", "/v2/targets/Webhook%20Fail/test")
+		if result.Status != v2.TestStatusFailed || result.Error == "" {
+			t.Errorf("expected status failed with error, got %s: %s", result.Status, result.Error)
+		}
+	})
+
+	t.Run("UnknownTarget", func(t *testing.T) {
+		rr, _ := test(t, "POST", "/v2/targets/Slack/test")
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		rr, _ := test(t, "GET", "/v2/targets/Webhook%20Success/test")
+		if rr.Code != http.StatusMethodNotAllowed {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusMethodNotAllowed)
+		}
+	})
+
+	t.Run("ListTargets", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v2/targets", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		v2.TargetsHandler(targets).ServeHTTP(rr, req)
+
+		list := make([]v2.Target, 0)
+		if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(list) != 2 {
+			t.Fatalf("expected 2 targets, got %d", len(list))
+		}
+		if list[0].Concurrency != 3 || list[0].MinimumPriority != "debug" {
+			t.Errorf("unexpected target filters: %+v", list[0])
+		}
+		if list[0].Delivery == nil || list[0].Delivery.LastSuccess == nil {
+			t.Error("expected the last successful delivery of the test")
+		}
+		if list[1].Delivery == nil || list[1].Delivery.Error == "" {
+			t.Error("expected the last failed delivery of the test")
+		}
+	})
+}
+
+func Test_TargetSimulationHandler(t *testing.T) {
+	targets := []target.Client{
+		loki.NewClient(loki.Options{
+			ClientOptions: target.ClientOptions{
+				Name:         "Loki",
+				ResultFilter: target.NewResultFilter(validate.RuleSets{Include: []string{"test"}}, validate.RuleSets{}, validate.RuleSets{}, "", nil),
+			},
+			Host:       "http://localhost:3100",
+			HTTPClient: targethttp.NewDryRunClient("Loki"),
+		}),
+		loki.NewClient(loki.Options{
+			ClientOptions: target.ClientOptions{
+				Name:         "Loki Error",
+				ResultFilter: target.NewResultFilter(validate.RuleSets{}, validate.RuleSets{}, validate.RuleSets{}, "error", nil),
+			},
+			Host:       "http://localhost:3100",
+			HTTPClient: targethttp.NewDryRunClient("Loki Error"),
+		}),
+	}
+
+	simulate := func(t *testing.T, method, body string) (*httptest.ResponseRecorder, []v2.TargetSimulation) {
+		req, err := http.NewRequest(method, "/v2/targets/simulate", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		v2.TargetSimulationHandler(report.NewMapper(nil))(targets).ServeHTTP(rr, req)
+
+		list := make([]v2.TargetSimulation, 0)
+		if rr.Code == http.StatusOK {
+			if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return rr, list
+	}
+
+	t.Run("Matched", func(t *testing.T) {
+		rr, list := simulate(t, "POST", `{"result":{"policy":"require-labels","result":"fail","severity":"medium","resources":[{"kind":"Pod","name":"nginx","namespace":"test"}]}}`)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+		if len(list) != 2 {
+			t.Fatalf("expected 2 simulated targets, got %d", len(list))
+		}
+		if !list[0].Matched || list[0].Priority != "warning" {
+			t.Errorf("expected Loki to match with priority warning, got %+v", list[0])
+		}
+		if list[1].Matched {
+			t.Errorf("expected Loki Error to skip results below its minimum priority, got %+v", list[1])
+		}
+	})
+
+	t.Run("NamespaceFilter", func(t *testing.T) {
+		_, list := simulate(t, "POST", `{"result":{"policy":"require-labels","result":"fail","severity":"high","resources":[{"kind":"Pod","name":"nginx","namespace":"default"}]}}`)
+		if list[0].Matched || !list[1].Matched {
+			t.Errorf("expected only Loki Error to match, got %+v", list)
+		}
+	})
+
+	t.Run("InvalidResult", func(t *testing.T) {
+		rr, _ := simulate(t, "POST", `{"result":{"message":"missing policy"}}`)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		rr, _ := simulate(t, "GET", "")
+		if rr.Code != http.StatusMethodNotAllowed {
+			t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusMethodNotAllowed)
+		}
+	})
+}
@@ -0,0 +1,24 @@
+package v2
+
+import (
+	"net/http"
+	"time"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/helper"
+)
+
+// TombstoneListHandler lists the tombstones of deleted reports, since is a RFC3339 or unix timestamp
+// and defaults to all retained tombstones
+func TombstoneListHandler(finder TombstoneFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		since, err := parseTime(req.URL.Query().Get("since"), time.Time{})
+		if err != nil {
+			helper.SendBadRequest(w, err)
+			return
+		}
+
+		list, err := finder.FetchTombstones(v1.BuildFilter(req), since)
+		helper.SendJSONResponse(w, list, err)
+	}
+}
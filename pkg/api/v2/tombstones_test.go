@@ -0,0 +1,48 @@
+package v2_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+)
+
+type tombstoneFinder struct {
+	filter v1.Filter
+	since  time.Time
+}
+
+func (f *tombstoneFinder) FetchTombstones(filter v1.Filter, since time.Time) ([]*v2.Tombstone, error) {
+	f.filter, f.since = filter, since
+
+	return []*v2.Tombstone{{ReportID: "123", Kind: "PolicyReport", Name: "polr-test", Namespace: "test"}}, nil
+}
+
+func Test_TombstoneListHandler(t *testing.T) {
+	finder := &tombstoneFinder{}
+	handler := v2.TombstoneListHandler(finder)
+
+	t.Run("List", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/v2/tombstones?since=1709301600&namespaces=test", nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("unexpected status code %d: %s", rr.Code, rr.Body.String())
+		}
+		if finder.since.Unix() != 1709301600 || len(finder.filter.Namespaces) != 1 {
+			t.Errorf("unexpected query %s %+v", finder.since, finder.filter)
+		}
+	})
+
+	t.Run("Invalid Since", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/v2/tombstones?since=yesterday", nil))
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected bad request, got %d", rr.Code)
+		}
+	})
+}
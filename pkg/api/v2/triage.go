@@ -0,0 +1,89 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/api/auth"
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/helper"
+	"github.com/kyverno/policy-reporter/pkg/triage"
+)
+
+// ResultFromTriagePath returns the result ID of the path /v2/results/{id}/triage
+func ResultFromTriagePath(path string) (string, bool) {
+	id := strings.TrimPrefix(path, "/v2/results/")
+	if id == path || !strings.HasSuffix(id, "/triage") {
+		return "", false
+	}
+
+	id = strings.TrimSuffix(id, "/triage")
+
+	return id, id != "" && !strings.Contains(id, "/")
+}
+
+// ResultTriageHandler REST API, GET returns the triage of the result, POST updates it with the status and comment query parameters.
+// Results are looked up within the namespaces filter, so the triage is restricted to the namespaces the caller is allowed to view
+func ResultTriageHandler(finder PolicyReportFinder, store triage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		id, ok := ResultFromTriagePath(req.URL.Path)
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+
+		if req.Method != http.MethodGet && req.Method != http.MethodPost {
+			w.Header().Set("Allow", "GET, POST")
+			helper.SendError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+			return
+		}
+
+		// the status query parameter is the triage status, so only the namespaces filter applies to the lookup
+		results, err := finder.FetchResults(v1.Filter{Namespaces: req.URL.Query()["namespaces"], IDs: []string{id}})
+		if err != nil {
+			helper.SendJSONResponse(w, nil, err)
+			return
+		}
+		if len(results) == 0 {
+			helper.SendError(w, http.StatusNotFound, fmt.Errorf("result %s not found", id))
+			return
+		}
+
+		current, err := store.FetchTriage(id)
+		if errors.Is(err, triage.ErrNotFound) {
+			current = triage.Triage{ResultID: id, Status: triage.Open}
+		} else if err != nil {
+			helper.SendJSONResponse(w, nil, err)
+			return
+		}
+
+		if req.Method == http.MethodGet {
+			helper.SendJSONResponse(w, current, nil)
+			return
+		}
+
+		var status triage.Status
+		if value := req.URL.Query().Get("status"); value != "" {
+			if status, err = triage.ParseStatus(value); err != nil {
+				helper.SendBadRequest(w, err)
+				return
+			}
+		}
+
+		author := ""
+		if identity, ok := auth.IdentityFrom(req.Context()); ok {
+			author = identity.Name
+		}
+
+		updated, err := triage.Update(current, status, req.URL.Query().Get("comment"), author, time.Now())
+		if err != nil {
+			helper.SendBadRequest(w, err)
+			return
+		}
+
+		helper.SendJSONResponse(w, updated, store.SaveTriage(updated))
+	}
+}
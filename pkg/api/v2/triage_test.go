@@ -0,0 +1,109 @@
+package v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+	"github.com/kyverno/policy-reporter/pkg/sqlite3"
+	"github.com/kyverno/policy-reporter/pkg/triage"
+)
+
+func Test_ResultTriageHandler(t *testing.T) {
+	db, err := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "triage.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store, err := sqlite3.NewPolicyReportStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.EnableTriage()
+	store.Add(preport)
+
+	id := preport.Results[0].GetID()
+	handler := v2.ResultTriageHandler(store, store)
+
+	t.Run("Default", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/v2/results/"+id+"/triage", nil))
+
+		result := triage.Triage{}
+		json.Unmarshal(rr.Body.Bytes(), &result)
+
+		if rr.Code != http.StatusOK || result.Status != triage.Open || result.ResultID != id {
+			t.Errorf("expected open triage, got %d %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("POST", "/v2/results/"+id+"/triage?status=false-positive&comment=expected+by+design", nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("unexpected status code %d: %s", rr.Code, rr.Body.String())
+		}
+
+		rr = httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("POST", "/v2/results/"+id+"/triage?comment=confirmed", nil))
+
+		result := triage.Triage{}
+		json.Unmarshal(rr.Body.Bytes(), &result)
+
+		if result.Status != triage.FalsePositive || len(result.Comments) != 2 {
+			t.Errorf("expected false positive with 2 comments, got %+v", result)
+		}
+	})
+
+	t.Run("Result Lists", func(t *testing.T) {
+		list, err := store.FetchResults(v1.Filter{IDs: []string{id}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list) != 1 || list[0].Triage == nil || list[0].Triage.Status != triage.FalsePositive {
+			t.Errorf("expected the triage in the result list, got %+v", list)
+		}
+
+		rr := httptest.NewRecorder()
+		v2.ResultExportHandler(store)(rr, httptest.NewRequest("GET", "/v2/results/export", nil))
+
+		if !strings.Contains(rr.Body.String(), "false-positive") || !strings.Contains(rr.Body.String(), "expected by design") {
+			t.Errorf("expected the triage in the export, got %s", rr.Body.String())
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("POST", "/v2/results/"+id+"/triage?status=ignored", nil))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected bad request for unknown status, got %d", rr.Code)
+		}
+
+		rr = httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("POST", "/v2/results/"+id+"/triage", nil))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected bad request without status and comment, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/v2/results/unknown/triage", nil))
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404 for unknown results, got %d", rr.Code)
+		}
+
+		rr = httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/v2/results/"+id+"/triage?namespaces=other", nil))
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404 for results outside of the namespaces filter, got %d", rr.Code)
+		}
+	})
+}
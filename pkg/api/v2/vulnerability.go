@@ -0,0 +1,165 @@
+package v2
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// AffectedResource of a Vulnerability
+type AffectedResource struct {
+	Namespace string `json:"namespace,omitempty"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+}
+
+// Vulnerability aggregates the results of a single CVE across all workloads
+type Vulnerability struct {
+	ID                string             `json:"id"`
+	Severity          string             `json:"severity,omitempty"`
+	CVSSScore         float64            `json:"cvssScore,omitempty"`
+	Message           string             `json:"message,omitempty"`
+	Fixable           bool               `json:"fixable"`
+	Packages          []string           `json:"packages"`
+	InstalledVersions []string           `json:"installedVersions"`
+	FixedVersions     []string           `json:"fixedVersions"`
+	Images            []string           `json:"images"`
+	AffectedImages    int                `json:"affectedImages"`
+	Namespaces        []string           `json:"namespaces"`
+	Resources         []AffectedResource `json:"resources"`
+	Results           int                `json:"results"`
+}
+
+// AggregateVulnerabilities groups the results by their vulnerabilityID property,
+// sorted by the CVSS score and ID, the fixed versions and images are read from the Trivy Operator properties
+func AggregateVulnerabilities(results []*v1.ListResult) []*Vulnerability {
+	index := make(map[string]*Vulnerability)
+	resources := make(map[string]map[string]bool)
+
+	for _, result := range results {
+		id := result.Properties[v1alpha2.VulnerabilityIDKey]
+		if id == "" {
+			continue
+		}
+
+		vuln, ok := index[id]
+		if !ok {
+			vuln = &Vulnerability{
+				ID:                id,
+				Message:           result.Message,
+				Packages:          make([]string, 0),
+				InstalledVersions: make([]string, 0),
+				FixedVersions:     make([]string, 0),
+				Images:            make([]string, 0),
+				Namespaces:        make([]string, 0),
+				Resources:         make([]AffectedResource, 0),
+			}
+			index[id] = vuln
+			resources[id] = make(map[string]bool)
+		}
+
+		vuln.Results++
+
+		if severityWeight(result.Severity) > severityWeight(vuln.Severity) {
+			vuln.Severity = result.Severity
+		}
+		if score, err := strconv.ParseFloat(result.Properties[v1alpha2.CVSSScoreKey], 64); err == nil && score > vuln.CVSSScore {
+			vuln.CVSSScore = score
+		}
+
+		vuln.Packages = appendUnique(vuln.Packages, property(result, "resource", "pkgName", "package"))
+		vuln.InstalledVersions = appendUnique(vuln.InstalledVersions, property(result, "installedVersion"))
+		vuln.FixedVersions = appendUnique(vuln.FixedVersions, property(result, "fixedVersion"))
+		vuln.Images = appendUnique(vuln.Images, image(result))
+		vuln.Namespaces = appendUnique(vuln.Namespaces, result.Namespace)
+
+		key := result.Namespace + "/" + result.Kind + "/" + result.Name
+		if result.Name != "" && !resources[id][key] {
+			resources[id][key] = true
+			vuln.Resources = append(vuln.Resources, AffectedResource{Namespace: result.Namespace, Kind: result.Kind, Name: result.Name})
+		}
+	}
+
+	list := make([]*Vulnerability, 0, len(index))
+	for _, vuln := range index {
+		vuln.Fixable = len(vuln.FixedVersions) > 0
+		vuln.AffectedImages = len(vuln.Images)
+
+		list = append(list, vuln)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].CVSSScore != list[j].CVSSScore {
+			return list[i].CVSSScore > list[j].CVSSScore
+		}
+
+		return list[i].ID < list[j].ID
+	})
+
+	return list
+}
+
+func image(result *v1.ListResult) string {
+	if image := property(result, "image"); image != "" {
+		return image
+	}
+
+	repository := property(result, "artifact.repository")
+	if repository == "" {
+		return ""
+	}
+
+	if server := property(result, "registry.server"); server != "" {
+		repository = server + "/" + repository
+	}
+
+	if tag := property(result, "artifact.tag"); tag != "" {
+		repository += ":" + tag
+	}
+
+	return repository
+}
+
+func property(result *v1.ListResult, keys ...string) string {
+	for _, key := range keys {
+		if value := strings.TrimSpace(result.Properties[key]); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+func appendUnique(list []string, value string) []string {
+	if value == "" {
+		return list
+	}
+
+	for _, item := range list {
+		if item == value {
+			return list
+		}
+	}
+
+	return append(list, value)
+}
+
+func severityWeight(severity string) int {
+	switch severity {
+	case v1alpha2.SeverityCritical:
+		return 5
+	case v1alpha2.SeverityHigh:
+		return 4
+	case v1alpha2.SeverityMedium:
+		return 3
+	case v1alpha2.SeverityLow:
+		return 2
+	case v1alpha2.SeverityInfo:
+		return 1
+	default:
+		return 0
+	}
+}
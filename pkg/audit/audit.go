@@ -0,0 +1,269 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/acknowledgment"
+	"github.com/kyverno/policy-reporter/pkg/health"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/secret"
+)
+
+var logger = logging.Component("audit")
+
+// Status of a notification
+const (
+	Success = "success"
+	Failed  = "failed"
+)
+
+// Entry of a single notification sent to a target
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Target string    `json:"target"`
+	// ResultID is empty for notifications of whole PolicyReports
+	ResultID string `json:"resultId,omitempty"`
+	Status   string `json:"status"`
+	// StatusCode of the HTTP response, zero for targets without HTTP API
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Query of the audit log, empty fields match all entries
+type Query struct {
+	Target   string
+	ResultID string
+	Status   string
+	Since    time.Time
+	Until    time.Time
+	// Limit of the returned entries, zero returns all
+	Limit int
+}
+
+func (q Query) matches(e Entry) bool {
+	switch {
+	case q.Target != "" && q.Target != e.Target:
+		return false
+	case q.ResultID != "" && q.ResultID != e.ResultID:
+		return false
+	case q.Status != "" && q.Status != e.Status:
+		return false
+	case !q.Since.IsZero() && e.Time.Before(q.Since):
+		return false
+	case !q.Until.IsZero() && e.Time.After(q.Until):
+		return false
+	}
+
+	return true
+}
+
+// Log is an append only file of notification entries, rotated when it exceeds the max size
+type Log struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	mx         *sync.Mutex
+	file       *os.File
+	size       int64
+}
+
+// Append the entry, the file is rotated before it exceeds the max size
+func (l *Log) Append(e Entry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(len(line)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+
+	return err
+}
+
+// Query the entries of the current file and its backups, the newest entries first
+func (l *Log) Query(q Query) ([]Entry, error) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	list := make([]Entry, 0)
+
+	for i := 0; i <= l.maxBackups; i++ {
+		entries, err := read(l.backup(i), q)
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, entries...)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Time.After(list[j].Time)
+	})
+
+	if q.Limit > 0 && len(list) > q.Limit {
+		list = list[:q.Limit]
+	}
+
+	return list, nil
+}
+
+// Close the current file
+func (l *Log) Close() error {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	return l.file.Close()
+}
+
+// backup file name, the current file for zero
+func (l *Log) backup(i int) string {
+	if i == 0 {
+		return l.path
+	}
+
+	return fmt.Sprintf("%s.%d", l.path, i)
+}
+
+// rotate shifts the backups, the oldest backup is removed
+func (l *Log) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+
+	if l.maxBackups == 0 {
+		if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	for i := l.maxBackups; i > 0; i-- {
+		if err := os.Rename(l.backup(i-1), l.backup(i)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return l.open()
+}
+
+func (l *Log) open() error {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	l.file = file
+	l.size = info.Size()
+
+	return nil
+}
+
+func read(path string, q Query) ([]Entry, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	list := make([]Entry, 0)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		e := Entry{}
+		// incomplete lines of an interrupted write are skipped
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+
+		if q.matches(e) {
+			list = append(list, e)
+		}
+	}
+
+	return list, scanner.Err()
+}
+
+// NewLog opens or creates the log file, maxSize in bytes of zero disables the rotation
+func NewLog(path string, maxSize int64, maxBackups int) (*Log, error) {
+	if maxBackups < 0 {
+		maxBackups = 0
+	}
+
+	l := &Log{path: path, maxSize: maxSize, maxBackups: maxBackups, mx: new(sync.Mutex)}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+var (
+	notifications   *Log
+	notificationsMx sync.RWMutex
+)
+
+// SetLog used by Record and Delivery, a nil log disables the audit
+func SetLog(l *Log) {
+	notificationsMx.Lock()
+	defer notificationsMx.Unlock()
+
+	notifications = l
+}
+
+// Record the notification in the configured log
+func Record(target, resultID string, statusCode int, err error) {
+	notificationsMx.RLock()
+	l := notifications
+	notificationsMx.RUnlock()
+
+	if l == nil {
+		return
+	}
+
+	e := Entry{Time: time.Now(), Target: target, ResultID: resultID, Status: Success, StatusCode: statusCode}
+	if err != nil {
+		e.Status = Failed
+		e.Error = err.Error()
+	}
+
+	if err := l.Append(e); err != nil {
+		logger.Errorf("failed to write audit log entry of target %s: %s", target, err)
+	}
+}
+
+// Delivery records the delivery result of the target in health.TargetDeliveries and the audit log,
+// successful deliveries are tracked until their acknowledgment if enabled
+func Delivery(target, resultID string, statusCode int, err error) {
+	err = secret.RedactError(err)
+
+	health.TargetDeliveries.RecordStatus(target, statusCode, err)
+	Record(target, resultID, statusCode, err)
+
+	if err == nil {
+		acknowledgment.Record(target, resultID)
+	}
+}
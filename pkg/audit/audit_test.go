@@ -0,0 +1,132 @@
+package audit_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/audit"
+	"github.com/kyverno/policy-reporter/pkg/health"
+)
+
+func Test_Log(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	l, err := audit.NewLog(path, 0, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer l.Close()
+
+	now := time.Now()
+
+	_ = l.Append(audit.Entry{Time: now.Add(-time.Hour), Target: "Slack", ResultID: "1", Status: audit.Success, StatusCode: 200})
+	_ = l.Append(audit.Entry{Time: now, Target: "Loki", ResultID: "1", Status: audit.Failed, StatusCode: 500, Error: "status code 500"})
+	_ = l.Append(audit.Entry{Time: now, Target: "Slack", ResultID: "2", Status: audit.Success, StatusCode: 200})
+
+	list, _ := l.Query(audit.Query{})
+	if len(list) != 3 || list[2].ResultID != "1" || list[2].Target != "Slack" {
+		t.Errorf("expected all entries newest first, got %v", list)
+	}
+
+	list, _ = l.Query(audit.Query{Target: "Slack", Since: now.Add(-time.Minute)})
+	if len(list) != 1 || list[0].ResultID != "2" {
+		t.Errorf("expected filtered entry, got %v", list)
+	}
+
+	list, _ = l.Query(audit.Query{ResultID: "1", Status: audit.Failed})
+	if len(list) != 1 || list[0].Target != "Loki" {
+		t.Errorf("expected failed entry, got %v", list)
+	}
+
+	list, _ = l.Query(audit.Query{Limit: 1})
+	if len(list) != 1 {
+		t.Errorf("expected limited entries, got %d", len(list))
+	}
+}
+
+func Test_Rotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	l, _ := audit.NewLog(path, 200, 1)
+	defer l.Close()
+
+	for i := 0; i < 10; i++ {
+		_ = l.Append(audit.Entry{Time: time.Now(), Target: "Webhook", ResultID: "result", Status: audit.Success})
+	}
+
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Errorf("expected rotated file: %s", err)
+	}
+	if _, err := os.Stat(path + ".2"); !os.IsNotExist(err) {
+		t.Error("expected only one backup")
+	}
+
+	info, _ := os.Stat(path)
+	if info.Size() > 200 {
+		t.Errorf("expected file within the max size, got %d", info.Size())
+	}
+
+	list, _ := l.Query(audit.Query{})
+	if len(list) == 0 || len(list) == 10 {
+		t.Errorf("expected the entries of the current file and the backup, got %d", len(list))
+	}
+}
+
+func Test_Delivery(t *testing.T) {
+	l, _ := audit.NewLog(filepath.Join(t.TempDir(), "audit.log"), 0, 0)
+	defer l.Close()
+
+	audit.Delivery("Audit", "1", 0, errors.New("upload failed"))
+
+	audit.SetLog(l)
+	defer audit.SetLog(nil)
+
+	audit.Delivery("Audit", "2", 502, errors.New("status code 502"))
+	audit.Record("Audit", "3", 0, nil)
+
+	list, _ := l.Query(audit.Query{Target: "Audit"})
+	if len(list) != 2 || list[1].Status != audit.Failed || list[1].StatusCode != 502 || list[0].Status != audit.Success {
+		t.Errorf("expected entries recorded after the log was set, got %v", list)
+	}
+
+	if delivery := health.TargetDeliveries.List()["Audit"]; delivery.Error != "status code 502" {
+		t.Errorf("expected recorded delivery health, got %v", delivery)
+	}
+}
+
+func Test_Handler(t *testing.T) {
+	l, _ := audit.NewLog(filepath.Join(t.TempDir(), "audit.log"), 0, 0)
+	defer l.Close()
+
+	_ = l.Append(audit.Entry{Time: time.Now(), Target: "Slack", ResultID: "1", Status: audit.Success, StatusCode: 200})
+
+	handler := l.Handler()
+
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodGet, audit.NotificationsPath+"?target=Slack&since=2024-01-01T00:00:00Z", nil))
+
+	list := []audit.Entry{}
+	if err := json.NewDecoder(rr.Body).Decode(&list); err != nil || len(list) != 1 {
+		t.Errorf("expected the recorded entry, got %v (%v)", list, err)
+	}
+
+	for _, query := range []string{"?since=yesterday", "?limit=0"} {
+		rr = httptest.NewRecorder()
+		handler(rr, httptest.NewRequest(http.MethodGet, audit.NotificationsPath+query, nil))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400 for %s, got %d", query, rr.Code)
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodDelete, audit.NotificationsPath, nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", rr.Code)
+	}
+}
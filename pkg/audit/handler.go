@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/helper"
+)
+
+// NotificationsPath lists the recorded notifications
+const NotificationsPath = "/v1/audit/notifications"
+
+// DefaultLimit of the listed notifications
+const DefaultLimit = 100
+
+// Handler of the requests sent to NotificationsPath, filtered by the target, resultId, status, since and until (RFC3339) query parameters
+func (l *Log) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			helper.SendError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+			return
+		}
+
+		query, err := parseQuery(req)
+		if err != nil {
+			helper.SendBadRequest(w, err)
+			return
+		}
+
+		list, err := l.Query(query)
+
+		helper.SendJSONResponse(w, list, err)
+	}
+}
+
+func parseQuery(req *http.Request) (Query, error) {
+	values := req.URL.Query()
+
+	q := Query{
+		Target:   values.Get("target"),
+		ResultID: values.Get("resultId"),
+		Status:   values.Get("status"),
+		Limit:    DefaultLimit,
+	}
+
+	for name, t := range map[string]*time.Time{"since": &q.Since, "until": &q.Until} {
+		if value := values.Get(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return q, fmt.Errorf("invalid %s parameter: %w", name, err)
+			}
+
+			*t = parsed
+		}
+	}
+
+	if value := values.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return q, fmt.Errorf("invalid limit parameter '%s'", value)
+		}
+
+		q.Limit = limit
+	}
+
+	return q, nil
+}
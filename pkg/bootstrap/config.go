@@ -0,0 +1,132 @@
+// Package bootstrap generates the configuration and the RBAC resources to run the policy-reporter binary
+// without the Helm chart.
+package bootstrap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Targets supported by the generated configuration and the option holding their URL
+var Targets = map[string]string{
+	"loki":          "host",
+	"elasticsearch": "host",
+	"slack":         "webhook",
+	"discord":       "webhook",
+	"teams":         "webhook",
+	"googleChat":    "webhook",
+	"webhook":       "host",
+	"ui":            "host",
+}
+
+// Databases supported by the generated configuration
+var Databases = []string{"sqlite", "memory", "postgres", "mysql", "mariadb"}
+
+// Options of the generated configuration
+type Options struct {
+	ClusterName string
+	// Targets by type with their URL
+	Targets         map[string]string
+	MinimumPriority string
+	Sources         []string
+	SkipExisting    bool
+	// Database type, sqlite if empty
+	Database    string
+	DatabaseDSN string
+	REST        bool
+	Metrics     bool
+}
+
+// TargetNames returns the sorted names of the supported targets
+func TargetNames() []string {
+	names := make([]string, 0, len(Targets))
+	for name := range Targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ParseTarget parses a target flag in the format type=url
+func ParseTarget(value string) (string, string, error) {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid target '%s', expected <type>=<url>", value)
+	}
+
+	name := strings.ToLower(strings.TrimSpace(parts[0]))
+	if _, ok := Targets[name]; !ok {
+		return "", "", fmt.Errorf("unsupported target '%s', expected one of %s", parts[0], strings.Join(TargetNames(), ", "))
+	}
+
+	return name, strings.TrimSpace(parts[1]), nil
+}
+
+// Render returns the configuration file of the options
+func Render(options Options) ([]byte, error) {
+	values := map[string]interface{}{
+		"rest":    map[string]interface{}{"enabled": options.REST},
+		"metrics": map[string]interface{}{"enabled": options.Metrics},
+	}
+
+	if options.ClusterName != "" {
+		values["clusterName"] = options.ClusterName
+	}
+
+	database := strings.ToLower(options.Database)
+	if database == "" {
+		database = "sqlite"
+	}
+	if !contains(Databases, database) {
+		return nil, fmt.Errorf("unsupported database '%s', expected one of %s", options.Database, strings.Join(Databases, ", "))
+	}
+	if database != "sqlite" {
+		db := map[string]interface{}{"type": database}
+		if options.DatabaseDSN != "" {
+			db["dsn"] = options.DatabaseDSN
+		}
+
+		values["database"] = db
+	}
+
+	for name, url := range options.Targets {
+		option, ok := Targets[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported target '%s', expected one of %s", name, strings.Join(TargetNames(), ", "))
+		}
+
+		target := map[string]interface{}{
+			option:                  url,
+			"skipExistingOnStartup": options.SkipExisting,
+		}
+		if options.MinimumPriority != "" {
+			target["minimumPriority"] = options.MinimumPriority
+		}
+		if len(options.Sources) > 0 {
+			target["sources"] = options.Sources
+		}
+
+		values[name] = target
+	}
+
+	content, err := yaml.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte("# generated by policy-reporter init\n"), content...), nil
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
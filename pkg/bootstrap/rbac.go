@@ -0,0 +1,105 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+// ReportGroupVersion of the PolicyReport CRDs, installed by Kyverno or other report producers
+const ReportGroupVersion = "wgpolicyk8s.io/v1alpha2"
+
+var labels = map[string]string{"app.kubernetes.io/name": "policy-reporter", "app.kubernetes.io/managed-by": "policy-reporter-init"}
+
+// ClusterRole to read the PolicyReports and ClusterPolicyReports
+func ClusterRole(name string) *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Rules: []rbacv1.PolicyRule{{
+			APIGroups: []string{"*"},
+			Resources: []string{"policyreports", "policyreports/status", "clusterpolicyreports", "clusterpolicyreports/status"},
+			Verbs:     []string{"get", "list", "watch"},
+		}},
+	}
+}
+
+// Apply creates or updates the namespace, the ServiceAccount and the ClusterRole with its binding, all resources use the given name
+func Apply(ctx context.Context, client k8s.Interface, namespace, name string) error {
+	_, err := client.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}, metav1.CreateOptions{})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create namespace %s: %w", namespace, err)
+	}
+
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels}}
+	_, err = client.CoreV1().ServiceAccounts(namespace).Create(ctx, sa, metav1.CreateOptions{})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create serviceaccount %s: %w", name, err)
+	}
+
+	role := ClusterRole(name)
+	_, err = client.RbacV1().ClusterRoles().Create(ctx, role, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		var existing *rbacv1.ClusterRole
+		existing, err = client.RbacV1().ClusterRoles().Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			existing.Rules = role.Rules
+			_, err = client.RbacV1().ClusterRoles().Update(ctx, existing, metav1.UpdateOptions{})
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply clusterrole %s: %w", name, err)
+	}
+
+	binding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: name},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: name, Namespace: namespace}},
+	}
+	_, err = client.RbacV1().ClusterRoleBindings().Create(ctx, binding, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		var existing *rbacv1.ClusterRoleBinding
+		existing, err = client.RbacV1().ClusterRoleBindings().Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			existing.Subjects = binding.Subjects
+			_, err = client.RbacV1().ClusterRoleBindings().Update(ctx, existing, metav1.UpdateOptions{})
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply clusterrolebinding %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// MissingCRDs returns the PolicyReport resources not served by the cluster
+func MissingCRDs(client discovery.DiscoveryInterface) ([]string, error) {
+	missing := []string{"policyreports", "clusterpolicyreports"}
+
+	list, err := client.ServerResourcesForGroupVersion(ReportGroupVersion)
+	if errors.IsNotFound(err) {
+		return missing, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	served := make(map[string]bool, len(list.APIResources))
+	for _, r := range list.APIResources {
+		served[r.Name] = true
+	}
+
+	result := make([]string, 0, len(missing))
+	for _, name := range missing {
+		if !served[name] {
+			result = append(result, name)
+		}
+	}
+
+	return result, nil
+}
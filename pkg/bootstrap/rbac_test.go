@@ -0,0 +1,69 @@
+package bootstrap_test
+
+import (
+	"context"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kyverno/policy-reporter/pkg/bootstrap"
+)
+
+func Test_Apply(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "policy-reporter"}})
+
+	for i := 0; i < 2; i++ {
+		if err := bootstrap.Apply(ctx, client, "policy-reporter", "policy-reporter"); err != nil {
+			t.Fatalf("apply %d failed: %s", i, err)
+		}
+	}
+
+	if _, err := client.CoreV1().ServiceAccounts("policy-reporter").Get(ctx, "policy-reporter", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected serviceaccount: %s", err)
+	}
+
+	role, err := client.RbacV1().ClusterRoles().Get(ctx, "policy-reporter", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(role.Rules) != 1 {
+		t.Errorf("expected the existing clusterrole to be updated, got %d rules", len(role.Rules))
+	}
+
+	binding, err := client.RbacV1().ClusterRoleBindings().Get(ctx, "policy-reporter", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if binding.Subjects[0].Namespace != "policy-reporter" || binding.RoleRef.Name != "policy-reporter" {
+		t.Errorf("unexpected clusterrolebinding: %+v", binding)
+	}
+}
+
+func Test_MissingCRDs(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	missing, err := bootstrap.MissingCRDs(client.Discovery())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 2 {
+		t.Errorf("expected both CRDs missing, got %v", missing)
+	}
+
+	client.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{
+		GroupVersion: bootstrap.ReportGroupVersion,
+		APIResources: []metav1.APIResource{{Name: "policyreports"}},
+	}}
+
+	missing, err = bootstrap.MissingCRDs(client.Discovery())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 1 || missing[0] != "clusterpolicyreports" {
+		t.Errorf("expected clusterpolicyreports missing, got %v", missing)
+	}
+}
@@ -1,9 +1,24 @@
 package cache
 
-import "github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+import (
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
 
 type Cache interface {
 	RemoveReport(id string)
 	AddReport(report v1alpha2.ReportInterface)
 	GetResults(id string) []string
 }
+
+// Deduplicator remembers processed result IDs independent of their PolicyReport,
+// so results of deleted and recreated reports are not processed again within the TTL
+type Deduplicator interface {
+	// Has checks if the result ID was processed within the TTL
+	Has(id string) bool
+	// Add the given result IDs with the configured TTL
+	Add(ids ...string)
+	// AddWithTTL adds the given IDs with an individual TTL
+	AddWithTTL(ttl time.Duration, ids ...string)
+}
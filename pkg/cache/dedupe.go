@@ -0,0 +1,167 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+	gocache "github.com/patrickmn/go-cache"
+
+	"github.com/kyverno/policy-reporter/pkg/logging"
+)
+
+var logger = logging.Component("cache")
+
+type inMemoryDeduplicator struct {
+	cache *gocache.Cache
+	ttl   time.Duration
+}
+
+func (d *inMemoryDeduplicator) Has(id string) bool {
+	_, ok := d.cache.Get(id)
+
+	return ok
+}
+
+func (d *inMemoryDeduplicator) Add(ids ...string) {
+	d.AddWithTTL(d.ttl, ids...)
+}
+
+func (d *inMemoryDeduplicator) AddWithTTL(ttl time.Duration, ids ...string) {
+	for _, id := range ids {
+		d.cache.Set(id, true, ttl)
+	}
+}
+
+// NewInMemoryDeduplicator creates a Deduplicator which does not survive restarts
+func NewInMemoryDeduplicator(ttl time.Duration) Deduplicator {
+	return &inMemoryDeduplicator{
+		cache: gocache.New(ttl, 5*time.Minute),
+		ttl:   ttl,
+	}
+}
+
+type redisDeduplicator struct {
+	rdb    *goredis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+func (d *redisDeduplicator) Has(id string) bool {
+	count, err := d.rdb.Exists(context.Background(), d.generateKey(id)).Result()
+	if err != nil {
+		logger.Errorf("Failed to check deduplication key: %s", err)
+		return false
+	}
+
+	return count > 0
+}
+
+func (d *redisDeduplicator) Add(ids ...string) {
+	d.AddWithTTL(d.ttl, ids...)
+}
+
+func (d *redisDeduplicator) AddWithTTL(ttl time.Duration, ids ...string) {
+	if len(ids) == 0 {
+		return
+	}
+
+	pipe := d.rdb.Pipeline()
+	for _, id := range ids {
+		pipe.Set(context.Background(), d.generateKey(id), 1, ttl)
+	}
+
+	if _, err := pipe.Exec(context.Background()); err != nil {
+		logger.Errorf("Failed to set deduplication keys: %s", err)
+	}
+}
+
+func (d *redisDeduplicator) generateKey(id string) string {
+	return d.prefix + ":dedupe:" + id
+}
+
+// NewRedisDeduplicator creates a Deduplicator shared between replicas and restarts
+func NewRedisDeduplicator(prefix string, rdb *goredis.Client, ttl time.Duration) Deduplicator {
+	return &redisDeduplicator{rdb: rdb, prefix: prefix, ttl: ttl}
+}
+
+const dedupeSQL = `CREATE TABLE IF NOT EXISTS result_dedupe (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "expires" INTEGER NOT NULL
+  );`
+
+type sqliteDeduplicator struct {
+	db  *sql.DB
+	ttl time.Duration
+}
+
+func (d *sqliteDeduplicator) Has(id string) bool {
+	var expires int64
+
+	err := d.db.QueryRow("SELECT expires FROM result_dedupe WHERE id=$1", id).Scan(&expires)
+	if err == sql.ErrNoRows {
+		return false
+	} else if err != nil {
+		logger.Errorf("Failed to check deduplication entry: %s", err)
+		return false
+	}
+
+	return expires > time.Now().Unix()
+}
+
+func (d *sqliteDeduplicator) Add(ids ...string) {
+	d.AddWithTTL(d.ttl, ids...)
+}
+
+func (d *sqliteDeduplicator) AddWithTTL(ttl time.Duration, ids ...string) {
+	if len(ids) == 0 {
+		return
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		logger.Errorf("Failed to start deduplication transaction: %s", err)
+		return
+	}
+
+	stmt, err := tx.Prepare("INSERT OR REPLACE INTO result_dedupe(id, expires) VALUES(?,?)")
+	if err != nil {
+		logger.Errorf("Failed to prepare deduplication statement: %s", err)
+		tx.Rollback()
+		return
+	}
+	defer stmt.Close()
+
+	expires := time.Now().Add(ttl).Unix()
+	for _, id := range ids {
+		if _, err := stmt.Exec(id, expires); err != nil {
+			logger.Errorf("Failed to persist deduplication entry: %s", err)
+			tx.Rollback()
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Errorf("Failed to commit deduplication entries: %s", err)
+	}
+}
+
+// CleanUp removes all expired entries
+func (d *sqliteDeduplicator) CleanUp() error {
+	_, err := d.db.Exec("DELETE FROM result_dedupe WHERE expires <= $1", time.Now().Unix())
+
+	return err
+}
+
+// NewSQLiteDeduplicator creates a Deduplicator persisted in the given database,
+// the database has to persist restarts to keep the deduplication state
+func NewSQLiteDeduplicator(db *sql.DB, ttl time.Duration) (Deduplicator, error) {
+	d := &sqliteDeduplicator{db: db, ttl: ttl}
+
+	if _, err := db.Exec(dedupeSQL); err != nil {
+		return nil, err
+	}
+
+	return d, d.CleanUp()
+}
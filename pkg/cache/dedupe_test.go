@@ -0,0 +1,71 @@
+package cache_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/kyverno/policy-reporter/pkg/cache"
+)
+
+func Test_InMemoryDeduplicator(t *testing.T) {
+	dedupe := cache.NewInMemoryDeduplicator(time.Minute)
+	dedupe.Add("123", "124")
+
+	if !dedupe.Has("123") || !dedupe.Has("124") {
+		t.Error("Expected added IDs to be deduplicated")
+	}
+	if dedupe.Has("125") {
+		t.Error("Expected unknown ID not to be deduplicated")
+	}
+}
+
+func Test_SQLiteDeduplicator(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file::memory:?cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	t.Run("Deduplicate within TTL", func(t *testing.T) {
+		dedupe, err := cache.NewSQLiteDeduplicator(db, time.Minute)
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		dedupe.Add("123")
+
+		if !dedupe.Has("123") {
+			t.Error("Expected added ID to be deduplicated")
+		}
+		if dedupe.Has("124") {
+			t.Error("Expected unknown ID not to be deduplicated")
+		}
+	})
+
+	t.Run("Ignore expired entries", func(t *testing.T) {
+		dedupe, err := cache.NewSQLiteDeduplicator(db, -time.Minute)
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		dedupe.Add("126")
+
+		if dedupe.Has("126") {
+			t.Error("Expected expired ID not to be deduplicated")
+		}
+	})
+
+	t.Run("Restore state from existing database", func(t *testing.T) {
+		dedupe, err := cache.NewSQLiteDeduplicator(db, time.Minute)
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		if !dedupe.Has("123") {
+			t.Error("Expected persisted ID to be deduplicated")
+		}
+	})
+}
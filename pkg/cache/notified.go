@@ -0,0 +1,38 @@
+package cache
+
+import "time"
+
+// Notified remembers the results sent per target within the TTL of each target. The state is kept in the configured
+// Deduplicator, so it survives restarts with the sqlite or redis deduplication and is shared between replicas with redis
+type Notified struct {
+	dedupe Deduplicator
+	ttl    func(target string) time.Duration
+}
+
+// Has checks if the result was sent to the target within its TTL
+func (n *Notified) Has(target, id string) bool {
+	if n.ttl(target) <= 0 {
+		return false
+	}
+
+	return n.dedupe.Has(notifiedKey(target, id))
+}
+
+// Add remembers the result as sent to the target for the TTL of the target
+func (n *Notified) Add(target, id string) {
+	ttl := n.ttl(target)
+	if ttl <= 0 {
+		return
+	}
+
+	n.dedupe.AddWithTTL(ttl, notifiedKey(target, id))
+}
+
+func notifiedKey(target, id string) string {
+	return "notified:" + target + ":" + id
+}
+
+// NewNotified creates a Notified cache with the TTL per target, a TTL of zero disables the cache for the target
+func NewNotified(ttl func(target string) time.Duration, dedupe Deduplicator) *Notified {
+	return &Notified{dedupe: dedupe, ttl: ttl}
+}
@@ -0,0 +1,108 @@
+package cache_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/cache"
+)
+
+func ttl(target string) time.Duration {
+	switch target {
+	case "slack":
+		return time.Hour
+	case "loki":
+		return time.Minute
+	default:
+		return 0
+	}
+}
+
+func Test_Notified(t *testing.T) {
+	t.Run("Remember results per target", func(t *testing.T) {
+		notified := cache.NewNotified(ttl, cache.NewInMemoryDeduplicator(time.Hour))
+		notified.Add("slack", "123")
+
+		if !notified.Has("slack", "123") {
+			t.Error("Expected result to be notified for slack")
+		}
+		if notified.Has("webhook", "123") {
+			t.Error("Expected result not to be notified for webhook")
+		}
+	})
+
+	t.Run("Disabled target", func(t *testing.T) {
+		notified := cache.NewNotified(ttl, cache.NewInMemoryDeduplicator(time.Hour))
+		notified.Add("webhook", "123")
+
+		if notified.Has("webhook", "123") {
+			t.Error("Expected target without TTL not to be cached")
+		}
+	})
+
+	t.Run("Separate from deduplicated results", func(t *testing.T) {
+		dedupe := cache.NewInMemoryDeduplicator(time.Hour)
+		cache.NewNotified(ttl, dedupe).Add("slack", "123")
+
+		if dedupe.Has("123") {
+			t.Error("Expected the notification not to deduplicate the result")
+		}
+	})
+
+	t.Run("Persist in the SQLite deduplicator", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "deduplication.db")
+
+		db, err := sql.Open("sqlite3", file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dedupe, err := cache.NewSQLiteDeduplicator(db, time.Hour)
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		notified := cache.NewNotified(ttl, dedupe)
+		notified.Add("slack", "123")
+		notified.Add("slack", "456")
+		db.Close()
+
+		db, err = sql.Open("sqlite3", file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+
+		dedupe, err = cache.NewSQLiteDeduplicator(db, time.Hour)
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		notified = cache.NewNotified(ttl, dedupe)
+		if !notified.Has("slack", "123") || !notified.Has("slack", "456") {
+			t.Error("Expected the notifications to survive a restart")
+		}
+	})
+
+	t.Run("Expire with the target TTL", func(t *testing.T) {
+		db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "deduplication.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+
+		dedupe, err := cache.NewSQLiteDeduplicator(db, time.Hour)
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		notified := cache.NewNotified(func(string) time.Duration { return time.Nanosecond }, dedupe)
+		notified.Add("loki", "123")
+
+		if notified.Has("loki", "123") {
+			t.Error("Expected result older than the target TTL not to be notified")
+		}
+	})
+}
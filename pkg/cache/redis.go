@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"time"
 
 	goredis "github.com/go-redis/redis/v8"
@@ -27,14 +26,23 @@ func (r *redisCache) AddReport(report v1alpha2.ReportInterface) {
 }
 
 func (r *redisCache) RemoveReport(id string) {
-	r.rdb.Del(context.Background(), r.generateKey(id))
+	if r.ttl <= 0 {
+		r.rdb.Del(context.Background(), r.generateKey(id))
+		return
+	}
+
+	// don't remove it directly to prevent sending results from instantly recreated reports
+	r.rdb.Expire(context.Background(), r.generateKey(id), r.ttl)
 }
 
 func (r *redisCache) GetResults(id string) []string {
 	list, err := r.rdb.Get(context.Background(), r.generateKey(id)).Result()
 	results := make([]string, 0)
-	if err != nil {
-		log.Printf("[ERROR] Failed to set result: %s\n", err)
+	if err == goredis.Nil {
+		return results
+	} else if err != nil {
+		logger.Errorf("Failed to get results: %s", err)
+		return results
 	}
 
 	json.Unmarshal([]byte(list), &results)
@@ -46,6 +54,8 @@ func (r *redisCache) generateKey(id string) string {
 	return fmt.Sprintf("%s:%s", r.prefix, id)
 }
 
+// NewRedisCache creates a Cache shared between multiple replicas,
+// results of removed reports are kept for the given TTL
 func NewRedisCache(prefix string, rdb *goredis.Client, ttl time.Duration) Cache {
 	return &redisCache{rdb: rdb, prefix: prefix, ttl: ttl}
 }
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+const cacheSQL = `CREATE TABLE IF NOT EXISTS result_cache (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "namespace" TEXT NOT NULL,
+    "name" TEXT NOT NULL,
+    "results" TEXT NOT NULL,
+    "expires" INTEGER NOT NULL
+  );`
+
+// ReportKeys lists the namespace/name keys of the cached reports, used to reconcile reports removed during a restart
+type ReportKeys interface {
+	ReportKeys() []string
+}
+
+type sqliteCache struct {
+	db *sql.DB
+}
+
+func (c *sqliteCache) AddReport(report v1alpha2.ReportInterface) {
+	value, _ := json.Marshal(reportResultsIds(report))
+
+	_, err := c.db.Exec(
+		"INSERT OR REPLACE INTO result_cache(id, namespace, name, results, expires) VALUES(?,?,?,?,0)",
+		report.GetID(), report.GetNamespace(), report.GetName(), string(value),
+	)
+	if err != nil {
+		logger.Errorf("Failed to persist cached results: %s", err)
+	}
+}
+
+func (c *sqliteCache) RemoveReport(id string) {
+	// don't remove it directly to prevent sending results from instantly recreated reports
+	_, err := c.db.Exec("UPDATE result_cache SET expires=$1 WHERE id=$2 AND expires=0", time.Now().Add(5*time.Minute).Unix(), id)
+	if err != nil {
+		logger.Errorf("Failed to expire cached results: %s", err)
+	}
+}
+
+func (c *sqliteCache) GetResults(id string) []string {
+	results := make([]string, 0)
+
+	var list string
+
+	err := c.db.QueryRow("SELECT results FROM result_cache WHERE id=$1 AND (expires=0 OR expires>$2)", id, time.Now().Unix()).Scan(&list)
+	if err == sql.ErrNoRows {
+		return results
+	} else if err != nil {
+		logger.Errorf("Failed to get results: %s", err)
+		return results
+	}
+
+	json.Unmarshal([]byte(list), &results)
+
+	return results
+}
+
+func (c *sqliteCache) ReportKeys() []string {
+	keys := make([]string, 0)
+
+	rows, err := c.db.Query("SELECT namespace, name FROM result_cache WHERE expires=0")
+	if err != nil {
+		logger.Errorf("Failed to list cached reports: %s", err)
+		return keys
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var namespace, name string
+		if err := rows.Scan(&namespace, &name); err != nil {
+			logger.Errorf("Failed to scan cached report: %s", err)
+			continue
+		}
+
+		if namespace == "" {
+			keys = append(keys, name)
+		} else {
+			keys = append(keys, namespace+"/"+name)
+		}
+	}
+
+	return keys
+}
+
+// CleanUp removes all expired entries
+func (c *sqliteCache) CleanUp() error {
+	_, err := c.db.Exec("DELETE FROM result_cache WHERE expires > 0 AND expires <= $1", time.Now().Unix())
+
+	return err
+}
+
+// NewSQLiteCache creates a Cache persisted in the given database,
+// the database has to persist restarts to reconcile the results of existing reports
+func NewSQLiteCache(db *sql.DB) (Cache, error) {
+	c := &sqliteCache{db: db}
+
+	if _, err := db.Exec(cacheSQL); err != nil {
+		return nil, err
+	}
+
+	return c, c.CleanUp()
+}
@@ -0,0 +1,71 @@
+package cache_test
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/cache"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+)
+
+func Test_SQLiteCache(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file::memory:?cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	polr := &v1alpha2.PolicyReport{
+		ObjectMeta: v1.ObjectMeta{Name: "polr-test", Namespace: "test"},
+		Results:    []v1alpha2.PolicyReportResult{fixtures.FailResult},
+	}
+	cpolr := &v1alpha2.ClusterPolicyReport{
+		ObjectMeta: v1.ObjectMeta{Name: "cpolr-test"},
+		Results:    []v1alpha2.PolicyReportResult{fixtures.FailPodResult},
+	}
+
+	rcache, err := cache.NewSQLiteCache(db)
+	if err != nil {
+		t.Fatalf("Unexpected Error: %s", err)
+	}
+
+	rcache.AddReport(polr)
+	rcache.AddReport(cpolr)
+
+	t.Run("Restore Results", func(t *testing.T) {
+		restored, err := cache.NewSQLiteCache(db)
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		results := restored.GetResults(polr.GetID())
+		if len(results) != 1 || results[0] != fixtures.FailResult.GetID() {
+			t.Errorf("Expected restored FailResult, got %v", results)
+		}
+
+		keys := restored.(cache.ReportKeys).ReportKeys()
+		if len(keys) != 2 {
+			t.Fatalf("Expected 2 report keys, got %v", keys)
+		}
+		if !(keys[0] == "test/polr-test" || keys[1] == "test/polr-test") || !(keys[0] == "cpolr-test" || keys[1] == "cpolr-test") {
+			t.Errorf("Unexpected report keys: %v", keys)
+		}
+	})
+
+	t.Run("Keep Results of removed Reports", func(t *testing.T) {
+		rcache.RemoveReport(polr.GetID())
+
+		if len(rcache.GetResults(polr.GetID())) != 1 {
+			t.Error("Expected removed report results to be kept within the grace period")
+		}
+
+		keys := rcache.(cache.ReportKeys).ReportKeys()
+		if len(keys) != 1 || keys[0] != "cpolr-test" {
+			t.Errorf("Expected removed report not to be listed, got %v", keys)
+		}
+	})
+}
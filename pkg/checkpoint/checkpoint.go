@@ -0,0 +1,30 @@
+package checkpoint
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// Write stores the time up to which all results were delivered
+func Write(path string, t time.Time) error {
+	return os.WriteFile(path, []byte(t.UTC().Format(time.RFC3339Nano)), 0o600)
+}
+
+// Read returns the time of the last checkpoint and removes it,
+// so an unexpected termination after the next start does not restore an outdated checkpoint
+func Read(path string) (time.Time, bool) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	os.Remove(path)
+
+	t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(content)))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
@@ -0,0 +1,45 @@
+package checkpoint_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/checkpoint"
+)
+
+func Test_Checkpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint")
+
+	t.Run("Missing Checkpoint", func(t *testing.T) {
+		if _, ok := checkpoint.Read(path); ok {
+			t.Error("Expected no checkpoint")
+		}
+	})
+
+	t.Run("Read written Checkpoint once", func(t *testing.T) {
+		now := time.Now()
+
+		if err := checkpoint.Write(path, now); err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		restored, ok := checkpoint.Read(path)
+		if !ok || !restored.Equal(now) {
+			t.Errorf("Expected checkpoint %s, got %s", now, restored)
+		}
+
+		if _, ok := checkpoint.Read(path); ok {
+			t.Error("Expected checkpoint to be removed after reading")
+		}
+	})
+
+	t.Run("Invalid Checkpoint", func(t *testing.T) {
+		os.WriteFile(path, []byte("invalid"), 0o600)
+
+		if _, ok := checkpoint.Read(path); ok {
+			t.Error("Expected invalid checkpoint to be ignored")
+		}
+	})
+}
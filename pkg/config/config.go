@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 type ValueFilter struct {
 	Include []string `mapstructure:"include"`
 	Exclude []string `mapstructure:"exclude"`
@@ -9,21 +11,56 @@ type EmailReportFilter struct {
 	DisableClusterReports bool        `mapstructure:"disableClusterReports"`
 	Namespaces            ValueFilter `mapstructure:"namespaces"`
 	Sources               ValueFilter `mapstructure:"sources"`
+	Severities            ValueFilter `mapstructure:"severities"`
+	Teams                 ValueFilter `mapstructure:"teams"`
 }
 
 type TargetFilter struct {
-	Namespaces   ValueFilter `mapstructure:"namespaces"`
-	Priorities   ValueFilter `mapstructure:"priorities"`
-	Policies     ValueFilter `mapstructure:"policies"`
-	ReportLabels ValueFilter `mapstructure:"reportLabels"`
+	Namespaces          ValueFilter    `mapstructure:"namespaces"`
+	Priorities          ValueFilter    `mapstructure:"priorities"`
+	Policies            ValueFilter    `mapstructure:"policies"`
+	Teams               ValueFilter    `mapstructure:"teams"`
+	ReportLabels        ValueFilter    `mapstructure:"reportLabels"`
+	ResourceLabels      ValueFilter    `mapstructure:"resourceLabels"`
+	ResourceAnnotations ValueFilter    `mapstructure:"resourceAnnotations"`
+	Expression          string         `mapstructure:"expression"`
+	Sampling            []SamplingRule `mapstructure:"sampling"`
+	GroupBy             []string       `mapstructure:"groupBy"`
+	GroupWindow         time.Duration  `mapstructure:"groupWindow"`
+	DeliveryWindows     []QuietHours   `mapstructure:"deliveryWindows"`
+	Scrub               []ScrubRule    `mapstructure:"scrub"`
+	// SendExistingResults on startup: true, false or since=<duration> to only send results newer than the duration,
+	// overrides skipExistingOnStartup if set
+	SendExistingResults string `mapstructure:"sendExistingResults"`
+}
+
+// ScrubRule replaces the matches of the regex pattern in the message and properties before the result is sent,
+// the optional CEL condition restricts the rule to matching results
+type ScrubRule struct {
+	Pattern     string   `mapstructure:"pattern"`
+	Replacement string   `mapstructure:"replacement"`
+	Properties  []string `mapstructure:"properties"`
+	Condition   string   `mapstructure:"condition"`
+}
+
+// SamplingRule limits the results sent per policy within the window, further results are summarized when the window ends
+type SamplingRule struct {
+	Policies []string      `mapstructure:"policies"`
+	Limit    int           `mapstructure:"limit"`
+	Window   time.Duration `mapstructure:"window"`
 }
 
 type MetricsFilter struct {
-	Namespaces ValueFilter `mapstructure:"namespaces"`
-	Policies   ValueFilter `mapstructure:"policies"`
-	Severities ValueFilter `mapstructure:"severities"`
-	Status     ValueFilter `mapstructure:"status"`
-	Sources    ValueFilter `mapstructure:"sources"`
+	Namespaces          ValueFilter `mapstructure:"namespaces"`
+	Policies            ValueFilter `mapstructure:"policies"`
+	Severities          ValueFilter `mapstructure:"severities"`
+	Status              ValueFilter `mapstructure:"status"`
+	Sources             ValueFilter `mapstructure:"sources"`
+	Teams               ValueFilter `mapstructure:"teams"`
+	ReportLabels        ValueFilter `mapstructure:"reportLabels"`
+	ResourceLabels      ValueFilter `mapstructure:"resourceLabels"`
+	ResourceAnnotations ValueFilter `mapstructure:"resourceAnnotations"`
+	Expression          string      `mapstructure:"expression"`
 }
 
 // Loki configuration
@@ -36,9 +73,12 @@ type Loki struct {
 	SecretRef       string            `mapstructure:"secretRef"`
 	CustomLabels    map[string]string `mapstructure:"customLabels"`
 	SkipExisting    bool              `mapstructure:"skipExistingOnStartup"`
+	Concurrency     int               `mapstructure:"concurrency"`
+	NotificationTTL time.Duration     `mapstructure:"notificationTTL"`
 	MinimumPriority string            `mapstructure:"minimumPriority"`
 	Filter          TargetFilter      `mapstructure:"filter"`
 	Sources         []string          `mapstructure:"sources"`
+	HTTP            HTTPClient        `mapstructure:"http"`
 	Channels        []Loki            `mapstructure:"channels"`
 }
 
@@ -55,77 +95,439 @@ type Elasticsearch struct {
 	SecretRef       string            `mapstructure:"secretRef"`
 	CustomFields    map[string]string `mapstructure:"customFields"`
 	SkipExisting    bool              `mapstructure:"skipExistingOnStartup"`
+	Concurrency     int               `mapstructure:"concurrency"`
+	NotificationTTL time.Duration     `mapstructure:"notificationTTL"`
 	MinimumPriority string            `mapstructure:"minimumPriority"`
 	Filter          TargetFilter      `mapstructure:"filter"`
 	Sources         []string          `mapstructure:"sources"`
+	HTTP            HTTPClient        `mapstructure:"http"`
 	Channels        []Elasticsearch   `mapstructure:"channels"`
 }
 
 // Slack configuration
 type Slack struct {
+	Name                string            `mapstructure:"name"`
+	Webhook             string            `mapstructure:"webhook"`
+	SecretRef           string            `mapstructure:"secretRef"`
+	CustomFields        map[string]string `mapstructure:"customFields"`
+	RemediationTemplate string            `mapstructure:"remediationTemplate"`
+	SkipExisting        bool              `mapstructure:"skipExistingOnStartup"`
+	Concurrency         int               `mapstructure:"concurrency"`
+	NotificationTTL     time.Duration     `mapstructure:"notificationTTL"`
+	MinimumPriority     string            `mapstructure:"minimumPriority"`
+	Filter              TargetFilter      `mapstructure:"filter"`
+	Sources             []string          `mapstructure:"sources"`
+	HTTP                HTTPClient        `mapstructure:"http"`
+	Channels            []Slack           `mapstructure:"channels"`
+	// Go templates of the message header and text
+	TitleTemplate   string   `mapstructure:"titleTemplate"`
+	MessageTemplate string   `mapstructure:"messageTemplate"`
+	Actions         []Action `mapstructure:"actions"`
+	// Token and Channel use the Slack Web API instead of the webhook to group the results of a policy in threads
+	Token     string        `mapstructure:"token"`
+	Channel   string        `mapstructure:"channel"`
+	ThreadTTL time.Duration `mapstructure:"threadTTL"`
+}
+
+// Action is a button of a chat message, the URL is a Go template with the fields of the result and its .Resource
+type Action struct {
+	Text string `mapstructure:"text"`
+	URL  string `mapstructure:"url"`
+}
+
+// Discord configuration
+type Discord struct {
+	Name                string            `mapstructure:"name"`
+	Webhook             string            `mapstructure:"webhook"`
+	SecretRef           string            `mapstructure:"secretRef"`
+	CustomFields        map[string]string `mapstructure:"customFields"`
+	RemediationTemplate string            `mapstructure:"remediationTemplate"`
+	SkipExisting        bool              `mapstructure:"skipExistingOnStartup"`
+	Concurrency         int               `mapstructure:"concurrency"`
+	NotificationTTL     time.Duration     `mapstructure:"notificationTTL"`
+	MinimumPriority     string            `mapstructure:"minimumPriority"`
+	Filter              TargetFilter      `mapstructure:"filter"`
+	Sources             []string          `mapstructure:"sources"`
+	HTTP                HTTPClient        `mapstructure:"http"`
+	Channels            []Discord         `mapstructure:"channels"`
+	// Mentions maps severities to the IDs of the roles mentioned in the message, e.g. critical: ["123456789"]
+	Mentions map[string][]string `mapstructure:"mentions"`
+}
+
+// Teams configuration
+type Teams struct {
+	Name                string            `mapstructure:"name"`
+	Webhook             string            `mapstructure:"webhook"`
+	SecretRef           string            `mapstructure:"secretRef"`
+	CustomFields        map[string]string `mapstructure:"customFields"`
+	RemediationTemplate string            `mapstructure:"remediationTemplate"`
+	SkipTLS             bool              `mapstructure:"skipTLS"`
+	Certificate         string            `mapstructure:"certificate"`
+	SkipExisting        bool              `mapstructure:"skipExistingOnStartup"`
+	Concurrency         int               `mapstructure:"concurrency"`
+	NotificationTTL     time.Duration     `mapstructure:"notificationTTL"`
+	MinimumPriority     string            `mapstructure:"minimumPriority"`
+	Filter              TargetFilter      `mapstructure:"filter"`
+	Sources             []string          `mapstructure:"sources"`
+	HTTP                HTTPClient        `mapstructure:"http"`
+	Channels            []Teams           `mapstructure:"channels"`
+	// Format messageCard for Office 365 connectors or adaptiveCard for Power Automate workflow webhooks
+	Format  string   `mapstructure:"format"`
+	Actions []Action `mapstructure:"actions"`
+}
+
+// GoogleChat configuration
+type GoogleChat struct {
+	Name                string            `mapstructure:"name"`
+	Webhook             string            `mapstructure:"webhook"`
+	SecretRef           string            `mapstructure:"secretRef"`
+	CustomFields        map[string]string `mapstructure:"customFields"`
+	RemediationTemplate string            `mapstructure:"remediationTemplate"`
+	SkipExisting        bool              `mapstructure:"skipExistingOnStartup"`
+	Concurrency         int               `mapstructure:"concurrency"`
+	NotificationTTL     time.Duration     `mapstructure:"notificationTTL"`
+	MinimumPriority     string            `mapstructure:"minimumPriority"`
+	Filter              TargetFilter      `mapstructure:"filter"`
+	Sources             []string          `mapstructure:"sources"`
+	HTTP                HTTPClient        `mapstructure:"http"`
+	Channels            []GoogleChat      `mapstructure:"channels"`
+}
+
+// UI configuration
+type UI struct {
+	Host            string        `mapstructure:"host"`
+	SkipTLS         bool          `mapstructure:"skipTLS"`
+	Certificate     string        `mapstructure:"certificate"`
+	SkipExisting    bool          `mapstructure:"skipExistingOnStartup"`
+	Concurrency     int           `mapstructure:"concurrency"`
+	NotificationTTL time.Duration `mapstructure:"notificationTTL"`
+	MinimumPriority string        `mapstructure:"minimumPriority"`
+	Sources         []string      `mapstructure:"sources"`
+	HTTP            HTTPClient    `mapstructure:"http"`
+}
+
+// HTTPClient configures the timeout and the connection pool of an HTTP based target,
+// unset options are inherited from the parent target and the global httpClient configuration
+type HTTPClient struct {
+	Timeout             time.Duration `mapstructure:"timeout"`
+	MaxIdleConns        int           `mapstructure:"maxIdleConns"`
+	MaxIdleConnsPerHost int           `mapstructure:"maxIdleConnsPerHost"`
+	IdleConnTimeout     time.Duration `mapstructure:"idleConnTimeout"`
+	KeepAlive           time.Duration `mapstructure:"keepAlive"`
+	DisableKeepAlives   bool          `mapstructure:"disableKeepAlives"`
+}
+
+// merge fills the unset options with the options of the parent
+func (c HTTPClient) merge(parent HTTPClient) HTTPClient {
+	if c.Timeout == 0 {
+		c.Timeout = parent.Timeout
+	}
+	if c.MaxIdleConns == 0 {
+		c.MaxIdleConns = parent.MaxIdleConns
+	}
+	if c.MaxIdleConnsPerHost == 0 {
+		c.MaxIdleConnsPerHost = parent.MaxIdleConnsPerHost
+	}
+	if c.IdleConnTimeout == 0 {
+		c.IdleConnTimeout = parent.IdleConnTimeout
+	}
+	if c.KeepAlive == 0 {
+		c.KeepAlive = parent.KeepAlive
+	}
+	if !c.DisableKeepAlives {
+		c.DisableKeepAlives = parent.DisableKeepAlives
+	}
+
+	return c
+}
+
+// Webhook configuration
+type Webhook struct {
 	Name            string            `mapstructure:"name"`
-	Webhook         string            `mapstructure:"webhook"`
+	Host            string            `mapstructure:"host"`
+	SkipTLS         bool              `mapstructure:"skipTLS"`
+	Certificate     string            `mapstructure:"certificate"`
+	Headers         map[string]string `mapstructure:"headers"`
 	SecretRef       string            `mapstructure:"secretRef"`
 	CustomFields    map[string]string `mapstructure:"customFields"`
+	Payload         string            `mapstructure:"payload"`
 	SkipExisting    bool              `mapstructure:"skipExistingOnStartup"`
+	Concurrency     int               `mapstructure:"concurrency"`
+	NotificationTTL time.Duration     `mapstructure:"notificationTTL"`
 	MinimumPriority string            `mapstructure:"minimumPriority"`
 	Filter          TargetFilter      `mapstructure:"filter"`
 	Sources         []string          `mapstructure:"sources"`
-	Channels        []Slack           `mapstructure:"channels"`
+	HTTP            HTTPClient        `mapstructure:"http"`
+	Channels        []Webhook         `mapstructure:"channels"`
+	Auth            WebhookAuth       `mapstructure:"auth"`
+	Signing         WebhookSigning    `mapstructure:"signing"`
+	CloudEvents     CloudEvents       `mapstructure:"cloudEvents"`
 }
 
-// Discord configuration
-type Discord struct {
+// WebhookAuth renders an authentication header with a static token or an OAuth2 client credentials access token
+type WebhookAuth struct {
+	// Header defaults to Authorization
+	Header string `mapstructure:"header"`
+	// Template of the header value with .Token, defaults to "Bearer {{ .Token }}"
+	Template string `mapstructure:"template"`
+	Token    string `mapstructure:"token"`
+	OAuth2   OAuth2 `mapstructure:"oauth2"`
+}
+
+// Enabled if a token or an OAuth2 token URL is configured
+func (a WebhookAuth) Enabled() bool {
+	return a.Token != "" || a.OAuth2.TokenURL != ""
+}
+
+// OAuth2 client credentials configuration
+type OAuth2 struct {
+	TokenURL     string   `mapstructure:"tokenURL"`
+	ClientID     string   `mapstructure:"clientID"`
+	ClientSecret string   `mapstructure:"clientSecret"`
+	Scopes       []string `mapstructure:"scopes"`
+}
+
+// WebhookSigning adds the HMAC-SHA256 signature of the request body
+type WebhookSigning struct {
+	Secret string `mapstructure:"secret"`
+	// Header defaults to X-Policy-Reporter-Signature
+	Header string `mapstructure:"header"`
+}
+
+// CloudEvents configuration, wraps the payloads in CloudEvents 1.0 envelopes
+type CloudEvents struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Mode is structured or binary, binary sends the attributes as ce- headers and is only supported by HTTP targets
+	Mode string `mapstructure:"mode"`
+	// Source attribute of the events, defaults to policy-reporter
+	Source string `mapstructure:"source"`
+}
+
+// Grafana configuration
+type Grafana struct {
 	Name            string            `mapstructure:"name"`
-	Webhook         string            `mapstructure:"webhook"`
+	Host            string            `mapstructure:"host"`
+	Token           string            `mapstructure:"token"`
+	DashboardUID    string            `mapstructure:"dashboardUID"`
+	PanelID         int               `mapstructure:"panelID"`
+	Tags            []string          `mapstructure:"tags"`
+	SkipTLS         bool              `mapstructure:"skipTLS"`
+	Certificate     string            `mapstructure:"certificate"`
+	Headers         map[string]string `mapstructure:"headers"`
 	SecretRef       string            `mapstructure:"secretRef"`
-	CustomFields    map[string]string `mapstructure:"customFields"`
 	SkipExisting    bool              `mapstructure:"skipExistingOnStartup"`
+	Concurrency     int               `mapstructure:"concurrency"`
+	NotificationTTL time.Duration     `mapstructure:"notificationTTL"`
 	MinimumPriority string            `mapstructure:"minimumPriority"`
 	Filter          TargetFilter      `mapstructure:"filter"`
 	Sources         []string          `mapstructure:"sources"`
-	Channels        []Discord         `mapstructure:"channels"`
+	HTTP            HTTPClient        `mapstructure:"http"`
+	Channels        []Grafana         `mapstructure:"channels"`
 }
 
-// Teams configuration
-type Teams struct {
+// GitHub configuration
+type GitHub struct {
+	Name                 string        `mapstructure:"name"`
+	Host                 string        `mapstructure:"host"`
+	Token                string        `mapstructure:"token"`
+	Repository           string        `mapstructure:"repository"`
+	RepositoryAnnotation string        `mapstructure:"repositoryAnnotation"`
+	Labels               []string      `mapstructure:"labels"`
+	RemediationTemplate  string        `mapstructure:"remediationTemplate"`
+	SkipTLS              bool          `mapstructure:"skipTLS"`
+	Certificate          string        `mapstructure:"certificate"`
+	SecretRef            string        `mapstructure:"secretRef"`
+	SkipExisting         bool          `mapstructure:"skipExistingOnStartup"`
+	Concurrency          int           `mapstructure:"concurrency"`
+	NotificationTTL      time.Duration `mapstructure:"notificationTTL"`
+	MinimumPriority      string        `mapstructure:"minimumPriority"`
+	Filter               TargetFilter  `mapstructure:"filter"`
+	Sources              []string      `mapstructure:"sources"`
+	HTTP                 HTTPClient    `mapstructure:"http"`
+	Channels             []GitHub      `mapstructure:"channels"`
+}
+
+// DefectDojo configuration
+type DefectDojo struct {
+	Name                string            `mapstructure:"name"`
+	Host                string            `mapstructure:"host"`
+	APIKey              string            `mapstructure:"apiKey"`
+	Product             string            `mapstructure:"product"`
+	Engagement          string            `mapstructure:"engagement"`
+	TestType            string            `mapstructure:"testType"`
+	CustomFields        map[string]string `mapstructure:"customFields"`
+	RemediationTemplate string            `mapstructure:"remediationTemplate"`
+	SkipTLS             bool              `mapstructure:"skipTLS"`
+	Certificate         string            `mapstructure:"certificate"`
+	SecretRef           string            `mapstructure:"secretRef"`
+	SkipExisting        bool              `mapstructure:"skipExistingOnStartup"`
+	Concurrency         int               `mapstructure:"concurrency"`
+	NotificationTTL     time.Duration     `mapstructure:"notificationTTL"`
+	MinimumPriority     string            `mapstructure:"minimumPriority"`
+	Filter              TargetFilter      `mapstructure:"filter"`
+	Sources             []string          `mapstructure:"sources"`
+	HTTP                HTTPClient        `mapstructure:"http"`
+	Channels            []DefectDojo      `mapstructure:"channels"`
+}
+
+// Alertmanager configuration
+type Alertmanager struct {
 	Name            string            `mapstructure:"name"`
-	Webhook         string            `mapstructure:"webhook"`
-	SecretRef       string            `mapstructure:"secretRef"`
+	Host            string            `mapstructure:"host"`
+	Token           string            `mapstructure:"token"`
+	Expiry          time.Duration     `mapstructure:"expiry"`
 	CustomFields    map[string]string `mapstructure:"customFields"`
+	Headers         map[string]string `mapstructure:"headers"`
 	SkipTLS         bool              `mapstructure:"skipTLS"`
 	Certificate     string            `mapstructure:"certificate"`
+	SecretRef       string            `mapstructure:"secretRef"`
 	SkipExisting    bool              `mapstructure:"skipExistingOnStartup"`
+	Concurrency     int               `mapstructure:"concurrency"`
+	NotificationTTL time.Duration     `mapstructure:"notificationTTL"`
 	MinimumPriority string            `mapstructure:"minimumPriority"`
 	Filter          TargetFilter      `mapstructure:"filter"`
 	Sources         []string          `mapstructure:"sources"`
-	Channels        []Teams           `mapstructure:"channels"`
+	HTTP            HTTPClient        `mapstructure:"http"`
+	Channels        []Alertmanager    `mapstructure:"channels"`
 }
 
-// UI configuration
-type UI struct {
-	Host            string   `mapstructure:"host"`
-	SkipTLS         bool     `mapstructure:"skipTLS"`
-	Certificate     string   `mapstructure:"certificate"`
-	SkipExisting    bool     `mapstructure:"skipExistingOnStartup"`
-	MinimumPriority string   `mapstructure:"minimumPriority"`
-	Sources         []string `mapstructure:"sources"`
+// Exec configuration of an external process target, each result is written as JSON to the stdin of the command
+type Exec struct {
+	Name            string            `mapstructure:"name"`
+	Command         string            `mapstructure:"command"`
+	Args            []string          `mapstructure:"args"`
+	Env             map[string]string `mapstructure:"env"`
+	Timeout         time.Duration     `mapstructure:"timeout"`
+	CustomFields    map[string]string `mapstructure:"customFields"`
+	SkipExisting    bool              `mapstructure:"skipExistingOnStartup"`
+	Concurrency     int               `mapstructure:"concurrency"`
+	NotificationTTL time.Duration     `mapstructure:"notificationTTL"`
+	MinimumPriority string            `mapstructure:"minimumPriority"`
+	Filter          TargetFilter      `mapstructure:"filter"`
+	Sources         []string          `mapstructure:"sources"`
+	Channels        []Exec            `mapstructure:"channels"`
 }
 
-// Webhook configuration
-type Webhook struct {
+// Telegram configuration
+type Telegram struct {
+	Name                string            `mapstructure:"name"`
+	Host                string            `mapstructure:"host"`
+	Token               string            `mapstructure:"token"`
+	ChatID              string            `mapstructure:"chatID"`
+	SecretRef           string            `mapstructure:"secretRef"`
+	CustomFields        map[string]string `mapstructure:"customFields"`
+	RemediationTemplate string            `mapstructure:"remediationTemplate"`
+	SkipTLS             bool              `mapstructure:"skipTLS"`
+	Certificate         string            `mapstructure:"certificate"`
+	SkipExisting        bool              `mapstructure:"skipExistingOnStartup"`
+	Concurrency         int               `mapstructure:"concurrency"`
+	NotificationTTL     time.Duration     `mapstructure:"notificationTTL"`
+	MinimumPriority     string            `mapstructure:"minimumPriority"`
+	Filter              TargetFilter      `mapstructure:"filter"`
+	Sources             []string          `mapstructure:"sources"`
+	HTTP                HTTPClient        `mapstructure:"http"`
+	Channels            []Telegram        `mapstructure:"channels"`
+}
+
+// SecurityHub configuration
+type SecurityHub struct {
+	Name            string            `mapstructure:"name"`
+	AccountID       string            `mapstructure:"accountID"`
+	Region          string            `mapstructure:"region"`
+	Endpoint        string            `mapstructure:"endpoint"`
+	AccessKeyID     string            `mapstructure:"accessKeyID"`
+	SecretAccessKey string            `mapstructure:"secretAccessKey"`
+	ProductARN      string            `mapstructure:"productARN"`
+	ClusterARN      string            `mapstructure:"clusterARN"`
+	SecretRef       string            `mapstructure:"secretRef"`
+	CustomFields    map[string]string `mapstructure:"customFields"`
+	SkipExisting    bool              `mapstructure:"skipExistingOnStartup"`
+	Concurrency     int               `mapstructure:"concurrency"`
+	NotificationTTL time.Duration     `mapstructure:"notificationTTL"`
+	MinimumPriority string            `mapstructure:"minimumPriority"`
+	Filter          TargetFilter      `mapstructure:"filter"`
+	Sources         []string          `mapstructure:"sources"`
+	Channels        []SecurityHub     `mapstructure:"channels"`
+}
+
+// SecurityCenter configuration of the GCP Security Command Center
+type SecurityCenter struct {
 	Name            string            `mapstructure:"name"`
 	Host            string            `mapstructure:"host"`
-	SkipTLS         bool              `mapstructure:"skipTLS"`
-	Certificate     string            `mapstructure:"certificate"`
-	Headers         map[string]string `mapstructure:"headers"`
+	OrganizationID  string            `mapstructure:"organizationID"`
+	Source          string            `mapstructure:"source"`
+	SourceName      string            `mapstructure:"sourceName"`
+	ProjectID       string            `mapstructure:"projectID"`
+	Location        string            `mapstructure:"location"`
+	Cluster         string            `mapstructure:"cluster"`
+	Credentials     string            `mapstructure:"credentials"`
 	SecretRef       string            `mapstructure:"secretRef"`
 	CustomFields    map[string]string `mapstructure:"customFields"`
 	SkipExisting    bool              `mapstructure:"skipExistingOnStartup"`
+	Concurrency     int               `mapstructure:"concurrency"`
+	NotificationTTL time.Duration     `mapstructure:"notificationTTL"`
 	MinimumPriority string            `mapstructure:"minimumPriority"`
 	Filter          TargetFilter      `mapstructure:"filter"`
 	Sources         []string          `mapstructure:"sources"`
-	Channels        []Webhook         `mapstructure:"channels"`
+	HTTP            HTTPClient        `mapstructure:"http"`
+	Channels        []SecurityCenter  `mapstructure:"channels"`
+}
+
+// Defender configuration of Microsoft Defender for Cloud
+type Defender struct {
+	Name            string            `mapstructure:"name"`
+	Host            string            `mapstructure:"host"`
+	TenantID        string            `mapstructure:"tenantID"`
+	ClientID        string            `mapstructure:"clientID"`
+	ClientSecret    string            `mapstructure:"clientSecret"`
+	SecretRef       string            `mapstructure:"secretRef"`
+	SubscriptionID  string            `mapstructure:"subscriptionID"`
+	ResourceID      string            `mapstructure:"resourceID"`
+	CustomFields    map[string]string `mapstructure:"customFields"`
+	SkipExisting    bool              `mapstructure:"skipExistingOnStartup"`
+	Concurrency     int               `mapstructure:"concurrency"`
+	NotificationTTL time.Duration     `mapstructure:"notificationTTL"`
+	MinimumPriority string            `mapstructure:"minimumPriority"`
+	Filter          TargetFilter      `mapstructure:"filter"`
+	Sources         []string          `mapstructure:"sources"`
+	HTTP            HTTPClient        `mapstructure:"http"`
+	Channels        []Defender        `mapstructure:"channels"`
+}
+
+// GitLab configuration
+type GitLab struct {
+	Name                 string        `mapstructure:"name"`
+	Host                 string        `mapstructure:"host"`
+	Token                string        `mapstructure:"token"`
+	Repository           string        `mapstructure:"repository"`
+	RepositoryAnnotation string        `mapstructure:"repositoryAnnotation"`
+	Labels               []string      `mapstructure:"labels"`
+	RemediationTemplate  string        `mapstructure:"remediationTemplate"`
+	SkipTLS              bool          `mapstructure:"skipTLS"`
+	Certificate          string        `mapstructure:"certificate"`
+	SecretRef            string        `mapstructure:"secretRef"`
+	SkipExisting         bool          `mapstructure:"skipExistingOnStartup"`
+	Concurrency          int           `mapstructure:"concurrency"`
+	NotificationTTL      time.Duration `mapstructure:"notificationTTL"`
+	MinimumPriority      string        `mapstructure:"minimumPriority"`
+	Filter               TargetFilter  `mapstructure:"filter"`
+	Sources              []string      `mapstructure:"sources"`
+	HTTP                 HTTPClient    `mapstructure:"http"`
+	Channels             []GitLab      `mapstructure:"channels"`
+}
+
+// KubernetesEvents configuration
+type KubernetesEvents struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	OnNamespace     bool          `mapstructure:"onNamespace"`
+	QPS             float32       `mapstructure:"qps"`
+	Burst           int           `mapstructure:"burst"`
+	SkipExisting    bool          `mapstructure:"skipExistingOnStartup"`
+	Concurrency     int           `mapstructure:"concurrency"`
+	NotificationTTL time.Duration `mapstructure:"notificationTTL"`
+	MinimumPriority string        `mapstructure:"minimumPriority"`
+	Filter          TargetFilter  `mapstructure:"filter"`
+	Sources         []string      `mapstructure:"sources"`
 }
 
 // S3 configuration
@@ -138,9 +540,16 @@ type S3 struct {
 	Prefix          string            `mapstructure:"prefix"`
 	Bucket          string            `mapstructure:"bucket"`
 	PathStyle       bool              `mapstructure:"pathStyle"`
+	Format          string            `mapstructure:"format"`
+	FlushInterval   time.Duration     `mapstructure:"flushInterval"`
+	FlushSize       int               `mapstructure:"flushSize"`
+	Encryption      string            `mapstructure:"serverSideEncryption"`
+	KMSKeyID        string            `mapstructure:"kmsKeyID"`
 	SecretRef       string            `mapstructure:"secretRef"`
 	CustomFields    map[string]string `mapstructure:"customFields"`
 	SkipExisting    bool              `mapstructure:"skipExistingOnStartup"`
+	Concurrency     int               `mapstructure:"concurrency"`
+	NotificationTTL time.Duration     `mapstructure:"notificationTTL"`
 	MinimumPriority string            `mapstructure:"minimumPriority"`
 	Filter          TargetFilter      `mapstructure:"filter"`
 	Sources         []string          `mapstructure:"sources"`
@@ -155,12 +564,19 @@ type Kinesis struct {
 	Region          string            `mapstructure:"region"`
 	Endpoint        string            `mapstructure:"endpoint"`
 	StreamName      string            `mapstructure:"streamName"`
+	DeliveryStream  string            `mapstructure:"deliveryStream"`
+	PartitionKey    string            `mapstructure:"partitionKey"`
+	Aggregation     bool              `mapstructure:"aggregation"`
+	FlushInterval   time.Duration     `mapstructure:"flushInterval"`
 	SecretRef       string            `mapstructure:"secretRef"`
 	CustomFields    map[string]string `mapstructure:"customFields"`
 	SkipExisting    bool              `mapstructure:"skipExistingOnStartup"`
+	Concurrency     int               `mapstructure:"concurrency"`
+	NotificationTTL time.Duration     `mapstructure:"notificationTTL"`
 	MinimumPriority string            `mapstructure:"minimumPriority"`
 	Filter          TargetFilter      `mapstructure:"filter"`
 	Sources         []string          `mapstructure:"sources"`
+	CloudEvents     CloudEvents       `mapstructure:"cloudEvents"`
 	Channels        []Kinesis         `mapstructure:"channels"`
 }
 
@@ -176,10 +592,12 @@ type SMTP struct {
 
 // EmailReport configuration
 type EmailReport struct {
-	To       []string          `mapstructure:"to"`
-	Format   string            `mapstructure:"format"`
-	Filter   EmailReportFilter `mapstructure:"filter"`
-	Channels []EmailReport     `mapstructure:"channels"`
+	To          []string          `mapstructure:"to"`
+	Format      string            `mapstructure:"format"`
+	Template    string            `mapstructure:"template"`
+	Attachments []string          `mapstructure:"attachments"`
+	Filter      EmailReportFilter `mapstructure:"filter"`
+	Channels    []EmailReport     `mapstructure:"channels"`
 }
 
 // EmailReport configuration
@@ -187,18 +605,127 @@ type EmailTemplates struct {
 	Dir string `mapstructure:"dir"`
 }
 
+// EmailSubscriptions configuration of the namespace digests teams subscribe to with the API,
+// the summary report sends the digests of the confirmed subscriptions
+type EmailSubscriptions struct {
+	Enabled bool `mapstructure:"enabled"`
+	// URL of the Policy Reporter API used for the confirmation and unsubscribe links
+	URL string `mapstructure:"url"`
+	// Domains of the allowed email addresses, all domains if empty
+	Domains []string `mapstructure:"domains"`
+}
+
 // EmailReports configuration
 type EmailReports struct {
-	SMTP        SMTP           `mapstructure:"smtp"`
-	Templates   EmailTemplates `mapstructure:"templates"`
-	Summary     EmailReport    `mapstructure:"summary"`
-	Violations  EmailReport    `mapstructure:"violations"`
-	ClusterName string         `mapstructure:"clusterName"`
+	SMTP          SMTP               `mapstructure:"smtp"`
+	Templates     EmailTemplates     `mapstructure:"templates"`
+	Summary       EmailReport        `mapstructure:"summary"`
+	Violations    EmailReport        `mapstructure:"violations"`
+	Subscriptions EmailSubscriptions `mapstructure:"subscriptions"`
+	ClusterName   string             `mapstructure:"clusterName"`
+}
+
+// APIToken configuration of a static bearer token
+type APIToken struct {
+	Name      string `mapstructure:"name"`
+	Token     string `mapstructure:"token"`
+	SecretRef string `mapstructure:"secretRef"`
+	Level     string `mapstructure:"level"`
+}
+
+// APIUser configuration of a basic auth user
+type APIUser struct {
+	Username  string `mapstructure:"username"`
+	Password  string `mapstructure:"password"`
+	SecretRef string `mapstructure:"secretRef"`
+	Level     string `mapstructure:"level"`
+}
+
+// OIDC configuration of the ID token validation
+type OIDC struct {
+	Enabled       bool     `mapstructure:"enabled"`
+	Issuer        string   `mapstructure:"issuer"`
+	Audience      string   `mapstructure:"audience"`
+	UsernameClaim string   `mapstructure:"usernameClaim"`
+	GroupsClaim   string   `mapstructure:"groupsClaim"`
+	AdminGroups   []string `mapstructure:"adminGroups"`
+}
+
+// KubernetesAuth configuration of the TokenReview authentication and SubjectAccessReview namespace scoping
+type KubernetesAuth struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	Audiences   []string      `mapstructure:"audiences"`
+	AdminGroups []string      `mapstructure:"adminGroups"`
+	CacheTTL    time.Duration `mapstructure:"cacheTTL"`
+}
+
+// APIAuth configuration
+type APIAuth struct {
+	Enabled    bool           `mapstructure:"enabled"`
+	Tokens     []APIToken     `mapstructure:"tokens"`
+	BasicAuth  []APIUser      `mapstructure:"basicAuth"`
+	OIDC       OIDC           `mapstructure:"oidc"`
+	Kubernetes KubernetesAuth `mapstructure:"kubernetes"`
 }
 
 // API configuration
 type API struct {
-	Port int `mapstructure:"port"`
+	// Host to bind to, e.g. "::" or "0.0.0.0", all IPv4 and IPv6 interfaces if empty
+	Host      string       `mapstructure:"host"`
+	Port      int          `mapstructure:"port"`
+	Admin     APIAdmin     `mapstructure:"admin"`
+	TLS       APITLS       `mapstructure:"tls"`
+	AccessLog APIAccessLog `mapstructure:"accessLog"`
+	Auth      APIAuth      `mapstructure:"auth"`
+	Filter    APIFilter    `mapstructure:"filter"`
+	Cache     APICache     `mapstructure:"cache"`
+	// AllowedOrigins of WebSocket result streams besides the API host, supports wildcards
+	AllowedOrigins []string `mapstructure:"allowedOrigins"`
+}
+
+// APIAdmin configuration, serves the admin, metrics and profiling APIs on a separate listener
+// so network policies can restrict them independently of the query APIs, disabled without port
+type APIAdmin struct {
+	Host string `mapstructure:"host"`
+	Port int    `mapstructure:"port"`
+}
+
+// APITLS configuration, serves the API and admin listeners with TLS, e.g. with the certificate of a mounted Secret
+type APITLS struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	CertFile string `mapstructure:"certFile"`
+	KeyFile  string `mapstructure:"keyFile"`
+	// MinVersion of TLS, 1.2 or 1.3
+	MinVersion string `mapstructure:"minVersion"`
+	// ReloadInterval in which the files are checked for a rotated certificate
+	ReloadInterval time.Duration `mapstructure:"reloadInterval"`
+}
+
+// APIAccessLog configuration of the request logging of the API, e.g. to find the queries with a high load on the store
+type APIAccessLog struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Encoding of the entries, json or console
+	Encoding string `mapstructure:"encoding"`
+	// SampleRate of the logged requests between 0 and 1, slow requests and server errors are always logged
+	SampleRate float64 `mapstructure:"sampleRate"`
+	// SlowThreshold from which a request is logged as slow, disabled if zero
+	SlowThreshold time.Duration `mapstructure:"slowThreshold"`
+	// ExcludePaths which are not logged, e.g. the lifecycle and metrics endpoints
+	ExcludePaths []string `mapstructure:"excludePaths"`
+}
+
+// APICache configuration, the responses of aggregate APIs are cached until the stored PolicyReports change or the TTL expires
+type APICache struct {
+	Enabled bool          `mapstructure:"enabled"`
+	TTL     time.Duration `mapstructure:"ttl"`
+}
+
+// APIFilter configuration, only matching results are persisted and served by the API
+type APIFilter struct {
+	ReportLabels        ValueFilter `mapstructure:"reportLabels"`
+	ResourceLabels      ValueFilter `mapstructure:"resourceLabels"`
+	ResourceAnnotations ValueFilter `mapstructure:"resourceAnnotations"`
+	Expression          string      `mapstructure:"expression"`
 }
 
 // REST configuration
@@ -206,12 +733,289 @@ type REST struct {
 	Enabled bool `mapstructure:"enabled"`
 }
 
+// GRPC configuration
+type GRPC struct {
+	Enabled bool `mapstructure:"enabled"`
+	// BindAddress of the listener, e.g. "127.0.0.1", all IPv4 and IPv6 interfaces if empty
+	BindAddress string `mapstructure:"bindAddress"`
+	Port        int    `mapstructure:"port"`
+	// TLS of the listener, configured like the TLS of the REST API
+	TLS  APITLS   `mapstructure:"tls"`
+	Auth GRPCAuth `mapstructure:"auth"`
+}
+
+// GRPCAuth configuration, authenticates the calls with the authentication of the REST API and restricts them
+// to the namespaces of the SubjectAccessReviews, required if api.auth is enabled
+type GRPCAuth struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// AdmissionWebhook configuration of the validating webhook for PolicyReports and ClusterPolicyReports
+type AdmissionWebhook struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	Port     int    `mapstructure:"port"`
+	Mode     string `mapstructure:"mode"`
+	CertFile string `mapstructure:"certFile"`
+	KeyFile  string `mapstructure:"keyFile"`
+}
+
+// MetricsRelabel configuration, evaluated on the labels of each result like the Prometheus relabel_config
+type MetricsRelabel struct {
+	Action       string   `mapstructure:"action"`
+	SourceLabels []string `mapstructure:"sourceLabels"`
+	Separator    string   `mapstructure:"separator"`
+	Regex        string   `mapstructure:"regex"`
+	TargetLabel  string   `mapstructure:"targetLabel"`
+	Replacement  string   `mapstructure:"replacement"`
+}
+
+// MetricsGauge configuration of an additional result gauge
+type MetricsGauge struct {
+	Name        string           `mapstructure:"name"`
+	Help        string           `mapstructure:"help"`
+	Labels      []string         `mapstructure:"labels"`
+	Aggregation string           `mapstructure:"aggregation"`
+	Relabel     []MetricsRelabel `mapstructure:"relabel"`
+}
+
+// MetricsSource configuration of a dedicated metric family of a source, its results are not counted in the
+// generic result gauges. The name defaults to the prefixed source name, e.g. trivy_vulnerability_results
+type MetricsSource struct {
+	Source string   `mapstructure:"source"`
+	Name   string   `mapstructure:"name"`
+	Help   string   `mapstructure:"help"`
+	Labels []string `mapstructure:"labels"`
+}
+
+// MetricsPushgateway configuration to push the metrics to a Prometheus Pushgateway
+type MetricsPushgateway struct {
+	URL       string            `mapstructure:"url"`
+	Job       string            `mapstructure:"job"`
+	Grouping  map[string]string `mapstructure:"grouping"`
+	Username  string            `mapstructure:"username"`
+	Password  string            `mapstructure:"password"`
+	SecretRef string            `mapstructure:"secretRef"`
+	Interval  time.Duration     `mapstructure:"interval"`
+}
+
+// MetricsRemoteWrite configuration to send the metrics to a Prometheus remote-write endpoint
+type MetricsRemoteWrite struct {
+	URL       string            `mapstructure:"url"`
+	Headers   map[string]string `mapstructure:"headers"`
+	Labels    map[string]string `mapstructure:"labels"`
+	Username  string            `mapstructure:"username"`
+	Password  string            `mapstructure:"password"`
+	Token     string            `mapstructure:"token"`
+	SecretRef string            `mapstructure:"secretRef"`
+	Interval  time.Duration     `mapstructure:"interval"`
+}
+
 // Metrics configuration
 type Metrics struct {
-	Filter       MetricsFilter `mapstructure:"filter"`
-	CustomLabels []string      `mapstructure:"customLabels"`
-	Mode         string        `mapstructure:"mode"`
-	Enabled      bool          `mapstructure:"enabled"`
+	Filter       MetricsFilter      `mapstructure:"filter"`
+	CustomLabels []string           `mapstructure:"customLabels"`
+	Mode         string             `mapstructure:"mode"`
+	Enabled      bool               `mapstructure:"enabled"`
+	Gauges       []MetricsGauge     `mapstructure:"gauges"`
+	Prefix       string             `mapstructure:"prefix"`
+	Sources      []MetricsSource    `mapstructure:"sources"`
+	Exemplars    bool               `mapstructure:"exemplars"`
+	Pushgateway  MetricsPushgateway `mapstructure:"pushgateway"`
+	RemoteWrite  MetricsRemoteWrite `mapstructure:"remoteWrite"`
+	ClusterLabel bool               `mapstructure:"clusterLabel"`
+	// SeriesLimit per metric family, labels with the most distinct values are dropped above the limit, zero disables the guard
+	SeriesLimit int `mapstructure:"seriesLimit"`
+}
+
+// ResultExclusions configuration, matching results of active ResultExclusions are suppressed
+type ResultExclusions struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// SeverityFloor configuration, results below the minimum severity of their namespace are not sent to any target
+type SeverityFloor struct {
+	// Namespaces maps namespaces to their minimum severity, namespaces support wildcards and "regex:" expressions
+	Namespaces map[string]string `mapstructure:"namespaces"`
+}
+
+// CustomSeverity configuration, an additional severity level respected by filters, metrics, sorting and targets
+type CustomSeverity struct {
+	Name string `mapstructure:"name"`
+	// Above is the severity ranked directly below the custom severity, empty ranks it below all other severities
+	Above string `mapstructure:"above"`
+	// Color like "#9e9e9e" used by Slack and Discord messages
+	Color string `mapstructure:"color"`
+	// Priority of results with the custom severity, the priorityMapping severities take precedence
+	Priority string `mapstructure:"priority"`
+}
+
+// Timestamps configuration of the rendered timestamps in targets, email reports and exports,
+// the format is rfc3339, rfc3339nano, unix, unixmilli or a Go time layout and the timezone an IANA name
+type Timestamps struct {
+	Format   string `mapstructure:"format"`
+	Timezone string `mapstructure:"timezone"`
+}
+
+// Summaries configuration, PolicyReports with a summary which does not match their results are counted by the drift metric
+type Summaries struct {
+	// Recompute replaces the summary with the counts of the results before the PolicyReport is published
+	Recompute bool `mapstructure:"recompute"`
+	// Patch corrects the summary of the PolicyReport in the cluster
+	Patch bool `mapstructure:"patch"`
+}
+
+// ResultAge configuration, results with a timestamp older than the maximum age of their source are not sent to any target
+type ResultAge struct {
+	MaxAge time.Duration `mapstructure:"maxAge"`
+	// Sources overrides the maximum age per source, 0 disables the check for the source
+	Sources map[string]time.Duration `mapstructure:"sources"`
+	// DropFromStore removes the stale results from the PolicyReports before they are stored
+	DropFromStore bool `mapstructure:"dropFromStore"`
+}
+
+// GitOps configuration, attaches the ArgoCD Application or Flux Kustomization / HelmRelease of the resource to each result
+type GitOps struct {
+	Enabled     bool   `mapstructure:"enabled"`
+	ArgoCDLabel string `mapstructure:"argoCDLabel"`
+}
+
+// OwnershipTeam maps namespaces to a team, the namespaces support wildcards
+type OwnershipTeam struct {
+	Name       string   `mapstructure:"name"`
+	Namespaces []string `mapstructure:"namespaces"`
+}
+
+// Ownership configuration, attaches the responsible team as team property to each result
+type Ownership struct {
+	Enabled             bool            `mapstructure:"enabled"`
+	NamespaceLabel      string          `mapstructure:"namespaceLabel"`
+	NamespaceAnnotation string          `mapstructure:"namespaceAnnotation"`
+	Teams               []OwnershipTeam `mapstructure:"teams"`
+}
+
+// OwnerResolution configuration, attributes results of Pods, ReplicaSets and Jobs to their top level controller
+type OwnerResolution struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	Deduplicate bool          `mapstructure:"deduplicate"`
+	TTL         time.Duration `mapstructure:"ttl"`
+}
+
+// PolicyMetadataAnnotations read from the Kyverno policies, empty keys are ignored
+type PolicyMetadataAnnotations struct {
+	Title       string `mapstructure:"title"`
+	Description string `mapstructure:"description"`
+	Remediation string `mapstructure:"remediation"`
+	Category    string `mapstructure:"category"`
+	Severity    string `mapstructure:"severity"`
+	Priority    string `mapstructure:"priority"`
+	URL         string `mapstructure:"url"`
+}
+
+// PolicyMetadata configuration, enriches Kyverno results with the annotations of the related ClusterPolicy or Policy
+type PolicyMetadata struct {
+	Enabled     bool                      `mapstructure:"enabled"`
+	Annotations PolicyMetadataAnnotations `mapstructure:"annotations"`
+}
+
+// Kyverno configuration, serves the Kyverno policies and PolicyExceptions joined with their results,
+// so the Kyverno plugin of the UI requires no separate backend
+type Kyverno struct {
+	Enabled bool `mapstructure:"enabled"`
+	// ExceptionVersion of the PolicyException API, defaults to v2beta1
+	ExceptionVersion string `mapstructure:"exceptionVersion"`
+}
+
+// SourceMappers configuration, normalizes the properties of known sources like Trivy, Falco and kube-bench
+type SourceMappers struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Mappers to apply, all included mappers if empty
+	Mappers []string `mapstructure:"mappers"`
+}
+
+// ErrorRouting configuration, sends the results with status error, e.g. of broken policies or engine failures,
+// to dedicated operations targets with the engine error in the errorDetails property
+type ErrorRouting struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Targets by name which receive only the results with status error
+	Targets []string `mapstructure:"targets"`
+	// Exclusive removes the results with status error from all other targets
+	Exclusive bool `mapstructure:"exclusive"`
+	// Properties read in order for the engine error, the message is used if none is set
+	Properties []string `mapstructure:"properties"`
+}
+
+// CVSSThresholds are the minimum CVSS scores of each severity, lower scores are info
+type CVSSThresholds struct {
+	Critical float64 `mapstructure:"critical"`
+	High     float64 `mapstructure:"high"`
+	Medium   float64 `mapstructure:"medium"`
+	Low      float64 `mapstructure:"low"`
+}
+
+// CVSSSeverity configuration, derives the severity of results from their CVSS score property
+type CVSSSeverity struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Sources of the results, all sources if empty
+	Sources []string `mapstructure:"sources"`
+	// Properties read in order until a valid score is found, defaults to cvssScore, score, cvss and nvd.v3Score
+	Properties []string       `mapstructure:"properties"`
+	Thresholds CVSSThresholds `mapstructure:"thresholds"`
+	// Override replaces severities inconsistent with the score, otherwise only missing severities are set
+	Override bool `mapstructure:"override"`
+}
+
+// Sources configuration of the ingested sources
+type Sources struct {
+	// Disabled sources are removed on ingestion, their results are neither stored nor sent to targets
+	Disabled []string `mapstructure:"disabled"`
+	// Precedence of overlapping sources from highest to lowest, a policy and resource pair reported by several of
+	// these sources is kept only for the source with the highest precedence
+	Precedence []string `mapstructure:"precedence"`
+}
+
+// UILinks configuration, adds deep links into an external Policy Reporter UI to the results sent to targets
+type UILinks struct {
+	Enabled bool `mapstructure:"enabled"`
+	// BaseURL of the UI as reachable by the receivers of the notifications
+	BaseURL string `mapstructure:"baseURL"`
+	// Path template of the links, rendered with the ID, Source, Namespace, Kind, Name, Policy, Rule, Category and Severity of the result
+	Path string `mapstructure:"path"`
+}
+
+// TerminatingResources configuration, results of deleted resources and terminating namespaces are not sent to targets
+type TerminatingResources struct {
+	Suppress bool `mapstructure:"suppress"`
+}
+
+// OrphanedResults configuration, results of resources which no longer exist are flagged with the orphaned property
+type OrphanedResults struct {
+	Enabled bool `mapstructure:"enabled"`
+	// ExcludeFromTargets does not send orphaned results to any target
+	ExcludeFromTargets bool `mapstructure:"excludeFromTargets"`
+	// ExcludeFromMetrics does not count orphaned results in the metrics
+	ExcludeFromMetrics bool `mapstructure:"excludeFromMetrics"`
+}
+
+// ViolationAnnotations configuration, resources are only annotated in the allowed namespaces
+type ViolationAnnotations struct {
+	Enabled    bool     `mapstructure:"enabled"`
+	Name       string   `mapstructure:"name"`
+	Namespaces []string `mapstructure:"namespaces"`
+	Workers    int      `mapstructure:"workers"`
+}
+
+// ComplianceSummary configuration of the ClusterComplianceSummary resource maintained with the counts and scores of all results
+type ComplianceSummary struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Name    string `mapstructure:"name"`
+	// Interval the resource is updated at most, changes within an interval are written at once
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+// ComplianceScore configuration, weights of unconfigured severities fall back to the default weights
+type ComplianceScore struct {
+	Enabled bool               `mapstructure:"enabled"`
+	Weights map[string]float64 `mapstructure:"weights"`
 }
 
 // Profiling configuration
@@ -219,9 +1023,80 @@ type Profiling struct {
 	Enabled bool `mapstructure:"enabled"`
 }
 
+// Audit configuration of the notification log file
+type Audit struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path"`
+	// MaxSize in megabytes before the file is rotated
+	MaxSize    int `mapstructure:"maxSize"`
+	MaxBackups int `mapstructure:"maxBackups"`
+}
+
+// Acknowledgments configuration of the delivered results acknowledged by downstream systems
+type Acknowledgments struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Targets which deliveries are tracked, all targets if empty
+	Targets []string `mapstructure:"targets"`
+	// Threshold after which an unacknowledged delivery is reported
+	Threshold time.Duration `mapstructure:"threshold"`
+	// Retention of the tracked deliveries, acknowledged or not
+	Retention time.Duration `mapstructure:"retention"`
+}
+
+// Tracing configuration of the OpenTelemetry spans of the result pipeline, exported with OTLP/HTTP
+type Tracing struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Endpoint of the collector traces API, e.g. http://otel-collector:4318/v1/traces
+	Endpoint    string            `mapstructure:"endpoint"`
+	Headers     map[string]string `mapstructure:"headers"`
+	ServiceName string            `mapstructure:"serviceName"`
+	// SampleRatio of the traced report changes between 0 and 1
+	SampleRatio float64       `mapstructure:"sampleRatio"`
+	Interval    time.Duration `mapstructure:"interval"`
+	// QueueSize of the spans waiting for export, further spans are dropped
+	QueueSize int `mapstructure:"queueSize"`
+}
+
+// Logging configuration, components maps listener, targets, api, store or a package name to its level
+type Logging struct {
+	Encoding   string            `mapstructure:"encoding"`
+	Level      string            `mapstructure:"level"`
+	Components map[string]string `mapstructure:"components"`
+	// Endpoint enables the admin API to change the levels at runtime
+	Endpoint bool `mapstructure:"endpoint"`
+}
+
+// ResultID configuration of the fields used to identify the results of a source, an empty source applies to all other sources
+type ResultID struct {
+	Source string   `mapstructure:"source"`
+	Fields []string `mapstructure:"fields"`
+}
+
+// PropertyField promotes a result property to a typed top-level field of API responses and target payloads,
+// the type is string, int, float or bool and defaults to string
+type PropertyField struct {
+	Property string `mapstructure:"property"`
+	Field    string `mapstructure:"field"`
+	Type     string `mapstructure:"type"`
+}
+
 // PriorityMap configuration
 type PriorityMap = map[string]string
 
+// PriorityRule overrides the priority of policies with the exact name or matching the regular expression pattern
+type PriorityRule struct {
+	Policy   string `mapstructure:"policy"`
+	Pattern  string `mapstructure:"pattern"`
+	Priority string `mapstructure:"priority"`
+}
+
+// PriorityMapping configuration, the rules are evaluated in order after the priorityMap
+type PriorityMapping struct {
+	// Severities maps a result severity to a priority, replaces the default mapping like high to error
+	Severities map[string]string `mapstructure:"severities"`
+	Rules      []PriorityRule    `mapstructure:"rules"`
+}
+
 // ClusterReportFilter configuration
 type ClusterReportFilter struct {
 	Disabled bool `mapstructure:"disabled"`
@@ -233,14 +1108,357 @@ type ReportFilter struct {
 	ClusterReports ClusterReportFilter `mapstructure:"clusterReports"`
 }
 
+// Watch configuration of the PolicyReport informers, filters are applied to the list/watch requests
+type Watch struct {
+	ResyncPeriod  time.Duration `mapstructure:"resyncPeriod"`
+	LabelSelector string        `mapstructure:"labelSelector"`
+	// Namespaces without wildcard support, exclude will be ignored if an include filter exists
+	Namespaces ValueFilter `mapstructure:"namespaces"`
+	// Workers of the report queue, overrides the worker flag if greater than zero
+	Workers int `mapstructure:"workers"`
+	// InterimReports watches the intermediate admission and background scan reports of Kyverno as well
+	InterimReports bool `mapstructure:"interimReports"`
+	// InterimLabels identify intermediate reports, defaults to the labels used by Kyverno
+	InterimLabels []string `mapstructure:"interimLabels"`
+}
+
+// Dispatcher configuration of the target delivery queues, the concurrency of a target overrides the default workers
+type Dispatcher struct {
+	Workers      int           `mapstructure:"workers"`
+	QueueSize    int           `mapstructure:"queueSize"`
+	DrainTimeout time.Duration `mapstructure:"drainTimeout"`
+}
+
+// RedisTLS configuration
+type RedisTLS struct {
+	Enabled     bool   `mapstructure:"enabled"`
+	SkipTLS     bool   `mapstructure:"skipTLS"`
+	Certificate string `mapstructure:"certificate"`
+	ServerName  string `mapstructure:"serverName"`
+}
+
 // Redis configuration
 type Redis struct {
-	Enabled  bool   `mapstructure:"enabled"`
-	Address  string `mapstructure:"address"`
-	Prefix   string `mapstructure:"prefix"`
-	Username string `mapstructure:"username"`
-	Password string `mapstructure:"password"`
-	Database int    `mapstructure:"database"`
+	Enabled   bool          `mapstructure:"enabled"`
+	Address   string        `mapstructure:"address"`
+	Prefix    string        `mapstructure:"prefix"`
+	Username  string        `mapstructure:"username"`
+	Password  string        `mapstructure:"password"`
+	Database  int           `mapstructure:"database"`
+	TTL       time.Duration `mapstructure:"ttl"`
+	SecretRef string        `mapstructure:"secretRef"`
+	TLS       RedisTLS      `mapstructure:"tls"`
+}
+
+// Database configuration of an external PolicyReport store, uses the embedded SQLite database if no type is configured
+type Database struct {
+	Type      string `mapstructure:"type"`
+	DSN       string `mapstructure:"dsn"`
+	Host      string `mapstructure:"host"`
+	Database  string `mapstructure:"database"`
+	Username  string `mapstructure:"username"`
+	Password  string `mapstructure:"password"`
+	SSLMode   string `mapstructure:"sslMode"`
+	SecretRef string `mapstructure:"secretRef"`
+	// Encryption of the result messages and property values at rest
+	Encryption DatabaseEncryption `mapstructure:"encryption"`
+}
+
+// DatabaseEncryption configuration, the key is read from the encryptionKey of the secretRef if set
+type DatabaseEncryption struct {
+	Enabled   bool   `mapstructure:"enabled"`
+	Key       string `mapstructure:"key"`
+	SecretRef string `mapstructure:"secretRef"`
+}
+
+// History configuration of the PolicyReport store
+type History struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Retention     time.Duration `mapstructure:"retention"`
+	PruneInterval time.Duration `mapstructure:"pruneInterval"`
+}
+
+// Tombstones of deleted PolicyReports, retained with their deletion time and last summary
+type Tombstones struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Retention     time.Duration `mapstructure:"retention"`
+	PruneInterval time.Duration `mapstructure:"pruneInterval"`
+}
+
+// Triage of results with a status and comments, managed with the API and included in the result lists and exports
+type Triage struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// Snooze of individual results, snoozed results are excluded from the notifications until the snooze expires
+type Snooze struct {
+	Enabled bool `mapstructure:"enabled"`
+	// MaxDuration of a single snooze
+	MaxDuration time.Duration `mapstructure:"maxDuration"`
+	// ExcludeFromSummaries removes snoozed results from the email reports, requires a database shared with the email report jobs
+	ExcludeFromSummaries bool `mapstructure:"excludeFromSummaries"`
+	// RefreshInterval of the snoozes created by other instances, expired snoozes are removed in the same interval
+	RefreshInterval time.Duration `mapstructure:"refreshInterval"`
+}
+
+// Pruning of the PolicyReport store, limits without value are disabled
+type Pruning struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Interval time.Duration `mapstructure:"interval"`
+	MaxAge   time.Duration `mapstructure:"maxAge"`
+	MaxRows  int           `mapstructure:"maxRows"`
+	// MaxSize as quantity like 500Mi, only supported by the SQLite database
+	MaxSize string `mapstructure:"maxSize"`
+}
+
+// DebounceWindow overrides the debounce window for reports of the matching sources and namespaces,
+// both support wildcards and match all values if empty
+type DebounceWindow struct {
+	Sources    []string      `mapstructure:"sources"`
+	Namespaces []string      `mapstructure:"namespaces"`
+	Window     time.Duration `mapstructure:"window"`
+	MaxDelay   time.Duration `mapstructure:"maxDelay"`
+}
+
+// Debounce configuration of PolicyReport update events
+type Debounce struct {
+	// EmptyUpdates delays updates without results, they are dropped if an update with results follows
+	EmptyUpdates time.Duration `mapstructure:"emptyUpdates"`
+	// Window coalesces rapid updates of a report, disabled by default
+	Window time.Duration `mapstructure:"window"`
+	// MaxDelay until coalesced updates are published, defaults to the window
+	MaxDelay time.Duration    `mapstructure:"maxDelay"`
+	Windows  []DebounceWindow `mapstructure:"windows"`
+}
+
+// Deduplication configuration
+type Deduplication struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Type    string        `mapstructure:"type"`
+	TTL     time.Duration `mapstructure:"ttl"`
+	DBFile  string        `mapstructure:"dbfile"`
+}
+
+// NotificationCache configuration, the results sent to each target are kept in the deduplication store
+// and not sent again within the TTL of the target
+type NotificationCache struct {
+	Enabled bool          `mapstructure:"enabled"`
+	TTL     time.Duration `mapstructure:"ttl"`
+}
+
+// Reconciliation configuration, the processed results are persisted to diff the existing reports against them on startup
+type Reconciliation struct {
+	Enabled bool   `mapstructure:"enabled"`
+	DBFile  string `mapstructure:"dbfile"`
+}
+
+// Target health action when a critical target fails for longer than the threshold
+const (
+	TargetHealthUnready = "unready"
+	TargetHealthRestart = "restart"
+)
+
+// TargetHealth configuration, critical targets failing for longer than the threshold make Policy Reporter unready
+// or fail the liveness probe to restart it
+type TargetHealth struct {
+	// Critical target names, e.g. "Slack" or the name of a channel
+	Critical  []string      `mapstructure:"critical"`
+	Threshold time.Duration `mapstructure:"threshold"`
+	// Action is unready or restart, defaults to unready
+	Action string `mapstructure:"action"`
+}
+
+// Shutdown configuration, the checkpoint is written after all queued results were delivered
+// and the next start only skips results of reports created before it
+type Shutdown struct {
+	Checkpoint string `mapstructure:"checkpoint"`
+}
+
+// Reload configuration, the configuration file is checked for changes in the given interval.
+// Targets and metric filters are replaced at runtime, all other options require a restart
+type Reload struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+// FederationCentral configuration of the central Policy Reporter, edge instances push their PolicyReports to it.
+// All reports are pushed again in the sync interval to recover from failed pushes or a restarted central instance
+type FederationCentral struct {
+	URL          string        `mapstructure:"url"`
+	Token        string        `mapstructure:"token"`
+	SecretRef    string        `mapstructure:"secretRef"`
+	Certificate  string        `mapstructure:"certificate"`
+	SkipTLS      bool          `mapstructure:"skipTLS"`
+	SyncInterval time.Duration `mapstructure:"syncInterval"`
+}
+
+// Federation configuration of the multi cluster aggregation. Edge instances configure the central URL,
+// the central instance enables the API to receive the pushed PolicyReports
+type Federation struct {
+	Enabled     bool              `mapstructure:"enabled"`
+	ClusterName string            `mapstructure:"clusterName"`
+	Central     FederationCentral `mapstructure:"central"`
+}
+
+// Ingestion configuration of the API to receive results of external policy engines, requires api.auth.enabled
+type Ingestion struct {
+	Enabled bool            `mapstructure:"enabled"`
+	Falco   FalcoIngestion  `mapstructure:"falco"`
+	NDJSON  NDJSONIngestion `mapstructure:"ndjson"`
+}
+
+// FalcoIngestion configuration of the retained Falco events
+type FalcoIngestion struct {
+	Retention  time.Duration `mapstructure:"retention"`
+	MaxResults int           `mapstructure:"maxResults"`
+}
+
+// NDJSONIngestion configuration of the NDJSON streaming ingestion for batch importers
+type NDJSONIngestion struct {
+	// MaxStreams processed concurrently, further streams are rejected with 429 Too Many Requests
+	MaxStreams int `mapstructure:"maxStreams"`
+	// MaxResults of a single stream
+	MaxResults int `mapstructure:"maxResults"`
+	// IdempotencyTTL is the time the response of a stream is replayed for repeated streams with the same Idempotency-Key header
+	IdempotencyTTL time.Duration `mapstructure:"idempotencyTTL"`
+}
+
+// EscalationRule escalates matching results which stay unresolved longer than the configured duration
+// to the referenced targets and email receivers, the referenced targets receive only escalated results
+type EscalationRule struct {
+	Name            string        `mapstructure:"name"`
+	MinimumSeverity string        `mapstructure:"minimumSeverity"`
+	Status          []string      `mapstructure:"status"`
+	Expression      string        `mapstructure:"expression"`
+	After           time.Duration `mapstructure:"after"`
+	Targets         []string      `mapstructure:"targets"`
+	Email           []string      `mapstructure:"email"`
+}
+
+// Escalation configuration, the rules are evaluated against the store in the given interval
+type Escalation struct {
+	Enabled  bool             `mapstructure:"enabled"`
+	Interval time.Duration    `mapstructure:"interval"`
+	Rules    []EscalationRule `mapstructure:"rules"`
+}
+
+// AgingRule raises the severity of results which stay unresolved longer than After, e.g. medium to high after 7 days
+type AgingRule struct {
+	Name     string        `mapstructure:"name"`
+	Severity string        `mapstructure:"severity"`
+	RaiseTo  string        `mapstructure:"raiseTo"`
+	After    time.Duration `mapstructure:"after"`
+	Sources  []string      `mapstructure:"sources"`
+}
+
+// SeverityAging configuration, the age of the results is evaluated in the given interval
+type SeverityAging struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Interval time.Duration `mapstructure:"interval"`
+	// Notify sends the results again with the raised severity
+	Notify bool `mapstructure:"notify"`
+	// Targets receiving the raised results, all targets if empty
+	Targets []string    `mapstructure:"targets"`
+	Rules   []AgingRule `mapstructure:"rules"`
+}
+
+// AlertRule fires an aggregated alert if more than Threshold matching results are reported within the Window
+type AlertRule struct {
+	Name            string        `mapstructure:"name"`
+	Namespaces      []string      `mapstructure:"namespaces"`
+	Sources         []string      `mapstructure:"sources"`
+	MinimumSeverity string        `mapstructure:"minimumSeverity"`
+	Status          []string      `mapstructure:"status"`
+	Expression      string        `mapstructure:"expression"`
+	Threshold       int           `mapstructure:"threshold"`
+	Window          time.Duration `mapstructure:"window"`
+	PerNamespace    bool          `mapstructure:"perNamespace"`
+	Targets         []string      `mapstructure:"targets"`
+	Email           []string      `mapstructure:"email"`
+}
+
+// Alerting configuration, the rules are evaluated on each new result
+type Alerting struct {
+	Enabled bool        `mapstructure:"enabled"`
+	Rules   []AlertRule `mapstructure:"rules"`
+}
+
+// QuietHours recurring daily, from and to in the format HH:MM, a range ending before its start spans midnight
+type QuietHours struct {
+	Days     []string `mapstructure:"days"`
+	From     string   `mapstructure:"from"`
+	To       string   `mapstructure:"to"`
+	Timezone string   `mapstructure:"timezone"`
+}
+
+// MaintenanceWindow planned in advance, start and end in RFC3339 format
+type MaintenanceWindow struct {
+	Name   string `mapstructure:"name"`
+	Start  string `mapstructure:"start"`
+	End    string `mapstructure:"end"`
+	Reason string `mapstructure:"reason"`
+}
+
+// Maintenance configuration, target notifications are suppressed or queued during quiet hours and maintenance windows.
+// Additional windows can be managed with the REST API
+type Maintenance struct {
+	Enabled    bool                `mapstructure:"enabled"`
+	Mode       string              `mapstructure:"mode"`
+	QuietHours []QuietHours        `mapstructure:"quietHours"`
+	Windows    []MaintenanceWindow `mapstructure:"windows"`
+}
+
+// Coverage configuration, lists the workloads of the kinds and counts the workloads without results per source
+type Coverage struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Kinds as apiVersion/kind, e.g. apps/v1/Deployment, defaults to Deployments, StatefulSets, DaemonSets and CronJobs
+	Kinds []string `mapstructure:"kinds"`
+	// Sources expected to evaluate each workload, defaults to all sources with results
+	Sources      []string      `mapstructure:"sources"`
+	Namespaces   ValueFilter   `mapstructure:"namespaces"`
+	Interval     time.Duration `mapstructure:"interval"`
+	MaxResources int           `mapstructure:"maxResources"`
+}
+
+// WasmModule configuration of a WASI filter or transformer module
+type WasmModule struct {
+	Name    string   `mapstructure:"name"`
+	Path    string   `mapstructure:"path"`
+	Args    []string `mapstructure:"args"`
+	Sources []string `mapstructure:"sources"`
+}
+
+// WasmPlugins configuration, the modules are applied to each new result before it is dispatched to the targets
+type WasmPlugins struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Timeout time.Duration `mapstructure:"timeout"`
+	// CacheDir persists the compiled modules across restarts
+	CacheDir string       `mapstructure:"cacheDir"`
+	Modules  []WasmModule `mapstructure:"modules"`
+}
+
+// TaxonomyEntry maps all matching values to one display name, match supports wildcards and defaults to the name
+type TaxonomyEntry struct {
+	Name  string   `mapstructure:"name"`
+	Match []string `mapstructure:"match"`
+	Group string   `mapstructure:"group"`
+	Order int      `mapstructure:"order"`
+}
+
+// Taxonomy configuration of the display names, groups and order of sources and categories,
+// used in metrics, email reports and the REST API
+type Taxonomy struct {
+	Sources    []TaxonomyEntry `mapstructure:"sources"`
+	Categories []TaxonomyEntry `mapstructure:"categories"`
+}
+
+// CustomFields configuration, added to the payload of all targets and optionally as labels to all metrics
+type CustomFields struct {
+	// Values are static fields, e.g. environment: production
+	Values map[string]string `mapstructure:"values"`
+	// Env maps fields to environment variables, e.g. provided by the downward API
+	Env          map[string]string `mapstructure:"env"`
+	MetricLabels bool              `mapstructure:"metricLabels"`
 }
 
 // LeaderElection configuration
@@ -255,6 +1473,29 @@ type LeaderElection struct {
 	Enabled         bool   `mapstructure:"enabled"`
 }
 
+// Sharding configuration
+type Sharding struct {
+	Enabled       bool `mapstructure:"enabled"`
+	LeaseDuration int  `mapstructure:"leaseDuration"`
+	RenewPeriod   int  `mapstructure:"renewPeriod"`
+	VirtualNodes  int  `mapstructure:"virtualNodes"`
+}
+
+// Modes of the PolicyReport source
+const (
+	ModeCluster    = "cluster"
+	ModeStandalone = "standalone"
+	// ModeAPIOnly serves the API of the shared database, the PolicyReports are ingested by another instance
+	ModeAPIOnly = "api-only"
+)
+
+// Standalone configuration, reads the PolicyReports from files on disk instead of a cluster
+type Standalone struct {
+	// ReportsDir with PolicyReport and ClusterPolicyReport files in JSON or YAML, scanned recursively for changes
+	ReportsDir string        `mapstructure:"reportsDir"`
+	Interval   time.Duration `mapstructure:"interval"`
+}
+
 // K8sClient config struct
 type K8sClient struct {
 	QPS        float32 `mapstructure:"qps"`
@@ -264,26 +1505,117 @@ type K8sClient struct {
 
 // Config of the PolicyReporter
 type Config struct {
-	Namespace      string         `mapstructure:"namespace"`
-	Loki           Loki           `mapstructure:"loki"`
-	Elasticsearch  Elasticsearch  `mapstructure:"elasticsearch"`
-	Slack          Slack          `mapstructure:"slack"`
-	Discord        Discord        `mapstructure:"discord"`
-	Teams          Teams          `mapstructure:"teams"`
-	S3             S3             `mapstructure:"s3"`
-	Kinesis        Kinesis        `mapstructure:"kinesis"`
-	UI             UI             `mapstructure:"ui"`
-	Webhook        Webhook        `mapstructure:"webhook"`
-	API            API            `mapstructure:"api"`
-	WorkerCount    int            `mapstructure:"worker"`
-	DBFile         string         `mapstructure:"dbfile"`
-	Metrics        Metrics        `mapstructure:"metrics"`
-	REST           REST           `mapstructure:"rest"`
-	PriorityMap    PriorityMap    `mapstructure:"priorityMap"`
-	ReportFilter   ReportFilter   `mapstructure:"reportFilter"`
-	Redis          Redis          `mapstructure:"redis"`
-	Profiling      Profiling      `mapstructure:"profiling"`
-	EmailReports   EmailReports   `mapstructure:"emailReports"`
-	LeaderElection LeaderElection `mapstructure:"leaderElection"`
-	K8sClient      K8sClient      `mapstructure:"k8sClient"`
+	Namespace       string               `mapstructure:"namespace"`
+	ClusterName     string               `mapstructure:"clusterName"`
+	CustomFields    CustomFields         `mapstructure:"customFields"`
+	Loki            Loki                 `mapstructure:"loki"`
+	Elasticsearch   Elasticsearch        `mapstructure:"elasticsearch"`
+	Slack           Slack                `mapstructure:"slack"`
+	Discord         Discord              `mapstructure:"discord"`
+	Teams           Teams                `mapstructure:"teams"`
+	GoogleChat      GoogleChat           `mapstructure:"googleChat"`
+	S3              S3                   `mapstructure:"s3"`
+	Kinesis         Kinesis              `mapstructure:"kinesis"`
+	UI              UI                   `mapstructure:"ui"`
+	Webhook         Webhook              `mapstructure:"webhook"`
+	Grafana         Grafana              `mapstructure:"grafana"`
+	GitHub          GitHub               `mapstructure:"github"`
+	GitLab          GitLab               `mapstructure:"gitlab"`
+	Telegram        Telegram             `mapstructure:"telegram"`
+	SecurityHub     SecurityHub          `mapstructure:"securityHub"`
+	SecurityCenter  SecurityCenter       `mapstructure:"securityCommandCenter"`
+	Defender        Defender             `mapstructure:"defenderForCloud"`
+	DefectDojo      DefectDojo           `mapstructure:"defectDojo"`
+	Alertmanager    Alertmanager         `mapstructure:"alertmanager"`
+	Exec            Exec                 `mapstructure:"exec"`
+	HTTPClient      HTTPClient           `mapstructure:"httpClient"`
+	Events          KubernetesEvents     `mapstructure:"kubernetesEvents"`
+	Annotations     ViolationAnnotations `mapstructure:"violationAnnotations"`
+	Score           ComplianceScore      `mapstructure:"complianceScore"`
+	Compliance      ComplianceSummary    `mapstructure:"complianceSummary"`
+	Exclusions      ResultExclusions     `mapstructure:"resultExclusions"`
+	Terminating     TerminatingResources `mapstructure:"terminatingResources"`
+	Orphaned        OrphanedResults      `mapstructure:"orphanedResults"`
+	SeverityFloor   SeverityFloor        `mapstructure:"severityFloor"`
+	Severities      []CustomSeverity     `mapstructure:"customSeverities"`
+	ResultAge       ResultAge            `mapstructure:"resultAge"`
+	Summaries       Summaries            `mapstructure:"summaries"`
+	SourceMappers   SourceMappers        `mapstructure:"sourceMappers"`
+	Sources         Sources              `mapstructure:"sources"`
+	CVSSSeverity    CVSSSeverity         `mapstructure:"cvssSeverity"`
+	ErrorRouting    ErrorRouting         `mapstructure:"errorRouting"`
+	UILinks         UILinks              `mapstructure:"uiLinks"`
+	PolicyMetadata  PolicyMetadata       `mapstructure:"policyMetadata"`
+	Kyverno         Kyverno              `mapstructure:"kyverno"`
+	Owners          OwnerResolution      `mapstructure:"ownerResolution"`
+	Ownership       Ownership            `mapstructure:"ownership"`
+	GitOps          GitOps               `mapstructure:"gitops"`
+	API             API                  `mapstructure:"api"`
+	WorkerCount     int                  `mapstructure:"worker"`
+	DryRunTargets   bool                 `mapstructure:"dryRunTargets"`
+	DBFile          string               `mapstructure:"dbfile"`
+	Database        Database             `mapstructure:"database"`
+	History         History              `mapstructure:"history"`
+	Tombstones      Tombstones           `mapstructure:"tombstones"`
+	Triage          Triage               `mapstructure:"triage"`
+	Snooze          Snooze               `mapstructure:"snooze"`
+	Pruning         Pruning              `mapstructure:"pruning"`
+	Metrics         Metrics              `mapstructure:"metrics"`
+	REST            REST                 `mapstructure:"rest"`
+	GRPC            GRPC                 `mapstructure:"grpc"`
+	Admission       AdmissionWebhook     `mapstructure:"admissionWebhook"`
+	PriorityMap     PriorityMap          `mapstructure:"priorityMap"`
+	Priorities      PriorityMapping      `mapstructure:"priorityMapping"`
+	ResultIDs       []ResultID           `mapstructure:"resultIDs"`
+	PropertyFields  []PropertyField      `mapstructure:"propertyFields"`
+	ReportFilter    ReportFilter         `mapstructure:"reportFilter"`
+	Watch           Watch                `mapstructure:"watch"`
+	Dispatcher      Dispatcher           `mapstructure:"dispatcher"`
+	TargetHealth    TargetHealth         `mapstructure:"targetHealth"`
+	Redis           Redis                `mapstructure:"redis"`
+	Deduplication   Deduplication        `mapstructure:"deduplication"`
+	Notifications   NotificationCache    `mapstructure:"notificationCache"`
+	Debounce        Debounce             `mapstructure:"debounce"`
+	Reconciliation  Reconciliation       `mapstructure:"reconciliation"`
+	Shutdown        Shutdown             `mapstructure:"shutdown"`
+	Reload          Reload               `mapstructure:"reload"`
+	Federation      Federation           `mapstructure:"federation"`
+	Ingestion       Ingestion            `mapstructure:"ingestion"`
+	Profiling       Profiling            `mapstructure:"profiling"`
+	Logging         Logging              `mapstructure:"logging"`
+	Timestamps      Timestamps           `mapstructure:"timestamps"`
+	Tracing         Tracing              `mapstructure:"tracing"`
+	Audit           Audit                `mapstructure:"audit"`
+	Acknowledgments Acknowledgments      `mapstructure:"acknowledgments"`
+	EmailReports    EmailReports         `mapstructure:"emailReports"`
+	Escalation      Escalation           `mapstructure:"escalation"`
+	SeverityAging   SeverityAging        `mapstructure:"severityAging"`
+	Alerting        Alerting             `mapstructure:"alerting"`
+	Maintenance     Maintenance          `mapstructure:"maintenance"`
+	WasmPlugins     WasmPlugins          `mapstructure:"wasmPlugins"`
+	Coverage        Coverage             `mapstructure:"coverage"`
+	Taxonomy        Taxonomy             `mapstructure:"taxonomy"`
+	LeaderElection  LeaderElection       `mapstructure:"leaderElection"`
+	Sharding        Sharding             `mapstructure:"sharding"`
+	K8sClient       K8sClient            `mapstructure:"k8sClient"`
+	Mode            string               `mapstructure:"mode"`
+	Standalone      Standalone           `mapstructure:"standalone"`
+	Profiles        Profiles             `mapstructure:"profiles"`
+
+	// unknownKeys of the configuration file, reported by Validate
+	unknownKeys []string
+	// file of the loaded configuration, empty if no file was found
+	file string
+	// overlays merged on top of the configuration file, including the file of the profile
+	overlays []string
+}
+
+// IsStandalone returns true if the PolicyReports are read from files instead of a cluster
+func (c *Config) IsStandalone() bool {
+	return c.Mode == ModeStandalone
+}
+
+// IsAPIOnly returns true if only the API of the shared database is served, without informers and targets
+func (c *Config) IsAPIOnly() bool {
+	return c.Mode == ModeAPIOnly
 }
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"regexp"
+)
+
+// metricLabel is the valid format of Prometheus label names
+var metricLabel = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// CustomFieldsFromConfig resolves the configured static and environment fields, environment fields take precedence,
+// fields of unset environment variables are skipped
+func CustomFieldsFromConfig(c CustomFields, lookupEnv func(string) (string, bool)) map[string]string {
+	fields := make(map[string]string, len(c.Values)+len(c.Env))
+	for key, value := range c.Values {
+		fields[key] = value
+	}
+
+	for key, env := range c.Env {
+		value, ok := lookupEnv(env)
+		if !ok {
+			logger.Warnf("custom field %s: environment variable %s is not set", key, env)
+			continue
+		}
+
+		fields[key] = value
+	}
+
+	return fields
+}
@@ -0,0 +1,15 @@
+package config
+
+import (
+	"github.com/kyverno/policy-reporter/pkg/fields"
+)
+
+// FieldMappingsFromConfig maps the configured properties to their typed fields
+func FieldMappingsFromConfig(c []PropertyField) []fields.Mapping {
+	mappings := make([]fields.Mapping, 0, len(c))
+	for _, field := range c {
+		mappings = append(mappings, fields.Mapping{Property: field.Property, Field: field.Field, Type: field.Type})
+	}
+
+	return mappings
+}
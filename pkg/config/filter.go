@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/expression"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+// ValidateExpressions compiles all configured filter expressions to fail on startup instead of on the first result
+func ValidateExpressions(c *Config) error {
+	var err error
+
+	walkFields(reflect.ValueOf(c).Elem(), "", func(field reflect.StructField, value reflect.Value, path string) {
+		if err != nil || field.Name != "Expression" || field.Type.Kind() != reflect.String || value.String() == "" {
+			return
+		}
+
+		if _, cerr := expression.Compile(value.String()); cerr != nil {
+			err = fmt.Errorf("%s: %w", path, cerr)
+		}
+	})
+
+	return err
+}
+
+func addExpressionFilter(filter *report.ResultFilter, source string) {
+	if source == "" {
+		return
+	}
+
+	expr, err := expression.Compile(source)
+	if err != nil {
+		logger.Errorf("%s", err)
+		return
+	}
+
+	filter.AddReportValidation(expr.Validate)
+}
+
+// addLabelFilters validates the labels of the report and the labels and annotations of the result resource
+func addLabelFilters(filter *report.ResultFilter, metadata report.ResourceMetadata, reportLabels, resourceLabels, resourceAnnotations ValueFilter) {
+	if rules := ToRuleSet(reportLabels); rules.Count() > 0 {
+		filter.AddReportValidation(func(r v1alpha2.ReportInterface, _ v1alpha2.PolicyReportResult) bool {
+			if r == nil {
+				return true
+			}
+
+			return validate.MatchLabels(r.GetLabels(), rules)
+		})
+	}
+
+	labels := ToRuleSet(resourceLabels)
+	annotations := ToRuleSet(resourceAnnotations)
+	if labels.Count() == 0 && annotations.Count() == 0 {
+		return
+	}
+
+	if metadata == nil {
+		logger.Warnf("resource label filters are ignored without a metadata cache")
+		return
+	}
+
+	filter.AddValidation(func(r v1alpha2.PolicyReportResult) bool {
+		meta, ok := metadata.Get(r.GetResource())
+		if !ok {
+			meta = &metav1.PartialObjectMetadata{}
+		}
+
+		return validate.MatchLabels(meta.GetLabels(), labels) && validate.MatchLabels(meta.GetAnnotations(), annotations)
+	})
+}
+
+func hasValues(filters ...ValueFilter) bool {
+	for _, f := range filters {
+		if len(f.Include)+len(f.Exclude) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addTeamFilter validates the team property of the result, results without team only pass exclude rules
+func addTeamFilter(filter *report.ResultFilter, teams ValueFilter) {
+	rules := ToRuleSet(teams)
+	if rules.Count() == 0 {
+		return
+	}
+
+	filter.AddValidation(func(r v1alpha2.PolicyReportResult) bool {
+		return validate.MatchRuleSet(r.Properties[v1alpha2.TeamKey], rules)
+	})
+}
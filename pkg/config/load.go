@@ -1,9 +1,10 @@
 package config
 
 import (
-	"log"
+	"os"
 	"strings"
 
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -16,6 +17,111 @@ func Load(cmd *cobra.Command) (*Config, error) {
 	v.SetDefault("leaderElection.renewDeadline", 10)
 	v.SetDefault("leaderElection.retryPeriod", 2)
 
+	v.SetDefault("sharding.leaseDuration", 15)
+	v.SetDefault("sharding.renewPeriod", 5)
+	v.SetDefault("sharding.virtualNodes", 64)
+
+	v.SetDefault("grpc.port", 9090)
+	v.SetDefault("grpc.tls.certFile", "/grpc-tls/tls.crt")
+	v.SetDefault("grpc.tls.keyFile", "/grpc-tls/tls.key")
+	v.SetDefault("grpc.tls.minVersion", "1.2")
+	v.SetDefault("grpc.tls.reloadInterval", "1m")
+	v.SetDefault("admissionWebhook.port", 9443)
+	v.SetDefault("admissionWebhook.mode", "warn")
+	v.SetDefault("admissionWebhook.certFile", "/tls/tls.crt")
+	v.SetDefault("admissionWebhook.keyFile", "/tls/tls.key")
+
+	v.SetDefault("api.auth.kubernetes.cacheTTL", "1m")
+	v.SetDefault("api.cache.ttl", "5m")
+	v.SetDefault("api.tls.certFile", "/api-tls/tls.crt")
+	v.SetDefault("api.tls.keyFile", "/api-tls/tls.key")
+	v.SetDefault("api.tls.minVersion", "1.2")
+	v.SetDefault("api.tls.reloadInterval", "1m")
+	v.SetDefault("api.accessLog.encoding", "json")
+	v.SetDefault("api.accessLog.sampleRate", 1)
+	v.SetDefault("api.accessLog.slowThreshold", "1s")
+	v.SetDefault("api.accessLog.excludePaths", []string{"/healthz", "/ready", "/metrics"})
+
+	v.SetDefault("metrics.pushgateway.job", "policy-reporter")
+	v.SetDefault("metrics.pushgateway.interval", "1m")
+	v.SetDefault("metrics.remoteWrite.interval", "1m")
+
+	v.SetDefault("kubernetesEvents.qps", 5)
+	v.SetDefault("kubernetesEvents.burst", 25)
+
+	v.SetDefault("violationAnnotations.name", "policy-reporter.io/violations")
+	v.SetDefault("violationAnnotations.workers", 2)
+
+	v.SetDefault("complianceSummary.name", "cluster")
+	v.SetDefault("complianceSummary.interval", "30s")
+
+	v.SetDefault("errorRouting.exclusive", true)
+	v.SetDefault("cvssSeverity.thresholds.critical", 9.0)
+	v.SetDefault("cvssSeverity.thresholds.high", 7.0)
+	v.SetDefault("cvssSeverity.thresholds.medium", 4.0)
+	v.SetDefault("cvssSeverity.thresholds.low", 0.1)
+	v.SetDefault("policyMetadata.annotations.title", "policies.kyverno.io/title")
+	v.SetDefault("policyMetadata.annotations.description", "policies.kyverno.io/description")
+	v.SetDefault("policyMetadata.annotations.remediation", "policy-reporter.kyverno.io/remediation")
+	v.SetDefault("policyMetadata.annotations.category", "policies.kyverno.io/category")
+	v.SetDefault("policyMetadata.annotations.severity", "policies.kyverno.io/severity")
+	v.SetDefault("policyMetadata.annotations.priority", "policy-reporter.kyverno.io/priority")
+	v.SetDefault("policyMetadata.annotations.url", "policy-reporter.kyverno.io/url")
+
+	v.SetDefault("ownerResolution.deduplicate", true)
+	v.SetDefault("ownerResolution.ttl", "2h")
+
+	v.SetDefault("watch.resyncPeriod", "15m")
+
+	v.SetDefault("dispatcher.workers", 2)
+	v.SetDefault("dispatcher.queueSize", 100)
+	v.SetDefault("dispatcher.drainTimeout", "30s")
+	v.SetDefault("targetHealth.threshold", "15m")
+	v.SetDefault("targetHealth.action", "unready")
+
+	v.SetDefault("redis.prefix", "policy-reporter")
+	v.SetDefault("redis.ttl", "2h")
+
+	v.SetDefault("audit.path", "audit.log")
+	v.SetDefault("audit.maxSize", 10)
+	v.SetDefault("audit.maxBackups", 5)
+
+	v.SetDefault("acknowledgments.threshold", "1h")
+	v.SetDefault("acknowledgments.retention", "168h")
+
+	v.SetDefault("tracing.serviceName", "policy-reporter")
+	v.SetDefault("tracing.sampleRatio", 1)
+	v.SetDefault("tracing.interval", "5s")
+	v.SetDefault("tracing.queueSize", 2048)
+
+	v.SetDefault("escalation.interval", "5m")
+	v.SetDefault("severityAging.interval", "10m")
+	v.SetDefault("severityAging.notify", true)
+	v.SetDefault("maintenance.mode", "suppress")
+	v.SetDefault("coverage.interval", "10m")
+
+	v.SetDefault("history.retention", "720h")
+	v.SetDefault("history.pruneInterval", "1h")
+	v.SetDefault("tombstones.retention", "168h")
+	v.SetDefault("tombstones.pruneInterval", "1h")
+	v.SetDefault("snooze.maxDuration", "720h")
+	v.SetDefault("snooze.refreshInterval", "1m")
+	v.SetDefault("pruning.interval", "10m")
+
+	v.SetDefault("deduplication.type", "memory")
+	v.SetDefault("deduplication.ttl", "2h")
+	v.SetDefault("deduplication.dbfile", "deduplication.db")
+	v.SetDefault("notificationCache.ttl", "24h")
+
+	v.SetDefault("debounce.emptyUpdates", "1m")
+
+	v.SetDefault("reconciliation.dbfile", "reconciliation.db")
+
+	v.SetDefault("reload.interval", "10s")
+	v.SetDefault("mode", ModeCluster)
+	v.SetDefault("standalone.interval", "2s")
+	v.SetDefault("federation.central.syncInterval", "5m")
+
 	cfgFile := ""
 
 	configFlag := cmd.Flags().Lookup("config")
@@ -34,7 +140,22 @@ func Load(cmd *cobra.Command) (*Config, error) {
 	v.AutomaticEnv()
 
 	if err := v.ReadInConfig(); err != nil {
-		log.Println("[INFO] No configuration file found")
+		logger.Info("No configuration file found")
+	}
+
+	profile := os.Getenv(ProfileEnv)
+	if flag := cmd.Flags().Lookup("config-profile"); flag != nil && flag.Value.String() != "" {
+		profile = flag.Value.String()
+	}
+
+	var overlays []string
+	if flag := cmd.Flags().Lookup("overlay"); flag != nil {
+		overlays, _ = cmd.Flags().GetStringSlice("overlay")
+	}
+
+	overlays, err := mergeOverlays(v, profile, overlays)
+	if err != nil {
+		return nil, err
 	}
 
 	if flag := cmd.Flags().Lookup("worker"); flag != nil {
@@ -69,6 +190,10 @@ func Load(cmd *cobra.Command) (*Config, error) {
 		v.BindPFlag("profiling.enabled", flag)
 	}
 
+	if flag := cmd.Flags().Lookup("dry-run-targets"); flag != nil {
+		v.BindPFlag("dryRunTargets", flag)
+	}
+
 	if flag := cmd.Flags().Lookup("dbfile"); flag != nil {
 		v.BindPFlag("dbfile", flag)
 	}
@@ -77,20 +202,28 @@ func Load(cmd *cobra.Command) (*Config, error) {
 		v.BindPFlag("emailReports.templates.dir", flag)
 	}
 
+	if flag := cmd.Flags().Lookup("mode"); flag != nil {
+		v.BindPFlag("mode", flag)
+	}
+
+	if flag := cmd.Flags().Lookup("reports-dir"); flag != nil {
+		v.BindPFlag("standalone.reportsDir", flag)
+	}
+
 	if flag := cmd.Flags().Lookup("lease-name"); flag != nil {
 		v.BindPFlag("leaderElection.lockName", flag)
 	}
 
 	if err := v.BindEnv("leaderElection.podName", "POD_NAME"); err != nil {
-		log.Printf("[WARNING] failed to bind env POD_NAME")
+		logger.Warnf("failed to bind env POD_NAME")
 	}
 
 	if err := v.BindEnv("leaderElection.namespace", "POD_NAMESPACE"); err != nil {
-		log.Printf("[WARNING] failed to bind env POD_NAMESPACE")
+		logger.Warnf("failed to bind env POD_NAMESPACE")
 	}
 
 	if err := v.BindEnv("namespace", "POD_NAMESPACE"); err != nil {
-		log.Printf("[WARNING] failed to bind env POD_NAMESPACE")
+		logger.Warnf("failed to bind env POD_NAMESPACE")
 	}
 
 	// bind SMTP config from environment vars, if existing
@@ -100,18 +233,59 @@ func Load(cmd *cobra.Command) (*Config, error) {
 	_ = v.BindEnv("emailReports.smtp.host", "EMAIL_REPORTS_SMTP_HOST")
 	_ = v.BindEnv("emailReports.smtp.port", "EMAIL_REPORTS_SMTP_PORT")
 	_ = v.BindEnv("emailReports.smtp.from", "EMAIL_REPORTS_SMTP_FROM")
+	// bind redis credentials from environment vars, if existing
+	_ = v.BindEnv("redis.username", "REDIS_USERNAME")
+	_ = v.BindEnv("redis.password", "REDIS_PASSWORD")
+	// bind database credentials from environment vars, if existing
+	_ = v.BindEnv("database.username", "DATABASE_USERNAME")
+	_ = v.BindEnv("database.password", "DATABASE_PASSWORD")
 	// bind slack webhook from environment vars, if existing
 	_ = v.BindEnv("slack.webhook", "SLACK_WEBHOOK")
 	// bind ui host from environment vars, if existing
 	_ = v.BindEnv("ui.host", "UI_HOST")
+	// bind the cluster name from environment vars, if existing
+	_ = v.BindEnv("clusterName", "CLUSTER_NAME")
 
 	c := &Config{}
+	metadata := &mapstructure.Metadata{}
 
-	err := v.Unmarshal(c)
+	err = v.Unmarshal(c, func(dc *mapstructure.DecoderConfig) { dc.Metadata = metadata })
+	c.unknownKeys = sortedKeys(metadata.Unused)
+	c.file = v.ConfigFileUsed()
+	c.overlays = overlays
+
+	if err == nil {
+		err = ValidateExpressions(c)
+	}
+	if err == nil {
+		_, _, err = PriorityRulesFromConfig(c.Priorities)
+	}
+	if err == nil {
+		_, err = IDMapperFromConfig(c.ResultIDs)
+	}
 
 	if c.DBFile == "" {
 		c.DBFile = "sqlite-database.db"
 	}
 
+	// the global cluster name is the default of the component specific names
+	if c.EmailReports.ClusterName == "" {
+		c.EmailReports.ClusterName = c.ClusterName
+	}
+	if c.Federation.ClusterName == "" {
+		c.Federation.ClusterName = c.ClusterName
+	}
+	if c.DefectDojo.Engagement == "" {
+		c.DefectDojo.Engagement = c.ClusterName
+	}
+
 	return c, err
 }
+
+// LoadFile loads the configuration file without command line flags
+func LoadFile(path string) (*Config, error) {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("config", path, "")
+
+	return Load(cmd)
+}
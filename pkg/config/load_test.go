@@ -1,11 +1,15 @@
 package config_test
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
 
 	"github.com/kyverno/policy-reporter/pkg/config"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
 )
 
 func createCMD() *cobra.Command {
@@ -19,6 +23,8 @@ func createCMD() *cobra.Command {
 	cmd.Flags().BoolP("rest-enabled", "r", false, "Enable Policy Reporter's REST API")
 	cmd.Flags().Bool("profile", false, "Enable application profiling with pprof")
 	cmd.Flags().StringP("template-dir", "t", "./templates", "template directory for email reports")
+	cmd.Flags().String("config-profile", "", "profile of the configuration file")
+	cmd.Flags().StringSlice("overlay", nil, "configuration files merged on top of the configuration file")
 
 	return cmd
 }
@@ -61,3 +67,153 @@ func Test_Load(t *testing.T) {
 		t.Errorf("Unexpected DBFile Config: %s", c.DBFile)
 	}
 }
+
+func writeConfig(t *testing.T, file, content string) string {
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return file
+}
+
+func Test_LoadProfile(t *testing.T) {
+	dir := t.TempDir()
+	base := writeConfig(t, filepath.Join(dir, "config.yaml"), `
+slack:
+  webhook: http://slack
+  minimumPriority: warning
+  filter:
+    namespaces:
+      exclude: ["kube-system"]
+  channels:
+  - webhook: http://team-a
+profiles:
+  prod:
+    slack:
+      minimumPriority: critical
+`)
+	writeConfig(t, filepath.Join(dir, "config.prod.yaml"), `
+slack:
+  channels:
+  - webhook: http://prod
+`)
+	overlay := writeConfig(t, filepath.Join(dir, "overlay.yaml"), `
+slack:
+  filter:
+    policies:
+      include: ["require-*"]
+`)
+
+	t.Run("Profile", func(t *testing.T) {
+		cmd := createCMD()
+		_ = cmd.Flags().Set("config", base)
+		_ = cmd.Flags().Set("config-profile", "prod")
+		_ = cmd.Flags().Set("overlay", overlay)
+
+		c, err := config.Load(cmd)
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		if c.Slack.Webhook != "http://slack" || c.Slack.MinimumPriority != "critical" {
+			t.Errorf("expected the profile to be merged into the base configuration, got %+v", c.Slack)
+		}
+		if len(c.Slack.Channels) != 1 || c.Slack.Channels[0].Webhook != "http://prod" {
+			t.Errorf("expected the channels to be replaced by the profile file, got %+v", c.Slack.Channels)
+		}
+		if len(c.Slack.Filter.Namespaces.Exclude) != 1 || len(c.Slack.Filter.Policies.Include) != 1 {
+			t.Errorf("expected the filters to be merged, got %+v", c.Slack.Filter)
+		}
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		cmd := createCMD()
+		_ = cmd.Flags().Set("config", base)
+
+		c, err := config.Load(cmd)
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		if c.Slack.MinimumPriority != "warning" || c.Slack.Channels[0].Webhook != "http://team-a" {
+			t.Errorf("expected the base configuration without profile, got %+v", c.Slack)
+		}
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		cmd := createCMD()
+		_ = cmd.Flags().Set("config", base)
+		_ = cmd.Flags().Set("config-profile", "stage")
+
+		if _, err := config.Load(cmd); err == nil {
+			t.Error("expected an error for an unknown profile")
+		}
+	})
+}
+
+func Test_ValidateExpressions(t *testing.T) {
+	t.Run("Valid Expressions", func(t *testing.T) {
+		err := config.ValidateExpressions(&config.Config{
+			Loki:    config.Loki{Filter: config.TargetFilter{Expression: `result.severity == "high"`}},
+			Metrics: config.Metrics{Filter: config.MetricsFilter{Expression: `result.status != "skip"`}},
+		})
+		if err != nil {
+			t.Errorf("Unexpected Error: %s", err)
+		}
+	})
+	t.Run("Invalid Channel Expression", func(t *testing.T) {
+		err := config.ValidateExpressions(&config.Config{
+			Slack: config.Slack{Channels: []config.Slack{{Filter: config.TargetFilter{Expression: `result.severity ==`}}}},
+		})
+		if err == nil {
+			t.Fatal("Expected invalid expression error")
+		}
+		if !strings.HasPrefix(err.Error(), "slack.channels[0].filter.expression") {
+			t.Errorf("Expected error with config path, got: %s", err)
+		}
+	})
+	t.Run("Invalid API Expression", func(t *testing.T) {
+		err := config.ValidateExpressions(&config.Config{API: config.API{Filter: config.APIFilter{Expression: `size(report.labels)`}}})
+		if err == nil {
+			t.Fatal("Expected non bool expression error")
+		}
+	})
+}
+
+func Test_PriorityRulesFromConfig(t *testing.T) {
+	t.Run("Valid Mapping", func(t *testing.T) {
+		severities, rules, err := config.PriorityRulesFromConfig(config.PriorityMapping{
+			Severities: map[string]string{"high": "critical"},
+			Rules: []config.PriorityRule{
+				{Policy: "require-labels", Priority: "info"},
+				{Pattern: "^disallow-", Priority: "error"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+		if severities[v1alpha2.SeverityHigh] != v1alpha2.CriticalPriority {
+			t.Errorf("Expected severity high mapped to critical, got %s", severities[v1alpha2.SeverityHigh])
+		}
+		if len(rules) != 2 || !rules[1].Matches("disallow-latest-tag") {
+			t.Errorf("Expected 2 rules with compiled pattern, got %d", len(rules))
+		}
+	})
+	t.Run("Invalid Pattern", func(t *testing.T) {
+		_, rules, err := config.PriorityRulesFromConfig(config.PriorityMapping{
+			Rules: []config.PriorityRule{{Pattern: "(", Priority: "error"}, {Policy: "test", Priority: "info"}},
+		})
+		if err == nil || !strings.HasPrefix(err.Error(), "priorityMapping.rules[0].pattern") {
+			t.Errorf("Expected invalid pattern error, got %v", err)
+		}
+		if len(rules) != 1 {
+			t.Errorf("Expected invalid rule to be skipped, got %d rules", len(rules))
+		}
+	})
+	t.Run("Invalid Priority", func(t *testing.T) {
+		_, _, err := config.PriorityRulesFromConfig(config.PriorityMapping{Severities: map[string]string{"high": "urgent"}})
+		if err == nil {
+			t.Error("Expected unknown priority error")
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/maintenance"
+)
+
+// MaintenanceWindowFromConfig parses the start and end of the window
+func MaintenanceWindowFromConfig(w MaintenanceWindow) (maintenance.Window, error) {
+	window := maintenance.Window{Name: w.Name, Reason: w.Reason}
+
+	var err error
+	if window.Start, err = time.Parse(time.RFC3339, w.Start); err != nil {
+		return window, fmt.Errorf("invalid start '%s', expected RFC3339", w.Start)
+	}
+	if window.End, err = time.Parse(time.RFC3339, w.End); err != nil {
+		return window, fmt.Errorf("invalid end '%s', expected RFC3339", w.End)
+	}
+
+	return window, window.Validate()
+}
+
+// ScheduleFromConfig creates the maintenance Schedule, invalid quiet hours and windows are skipped and the last error is returned
+func ScheduleFromConfig(c Maintenance) (*maintenance.Schedule, error) {
+	var err error
+
+	quietHours := make([]maintenance.QuietHours, 0, len(c.QuietHours))
+	for i, q := range c.QuietHours {
+		parsed, perr := maintenance.ParseQuietHours(q.Days, q.From, q.To, q.Timezone)
+		if perr != nil {
+			err = fmt.Errorf("maintenance.quietHours[%d]: %w", i, perr)
+			continue
+		}
+
+		quietHours = append(quietHours, parsed)
+	}
+
+	windows := make([]maintenance.Window, 0, len(c.Windows))
+	for i, w := range c.Windows {
+		window, werr := MaintenanceWindowFromConfig(w)
+		if werr != nil {
+			err = fmt.Errorf("maintenance.windows[%d]: %w", i, werr)
+			continue
+		}
+
+		windows = append(windows, window)
+	}
+
+	return maintenance.NewSchedule(quietHours, windows), err
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+var priorities = []string{"debug", "info", "warning", "error", "critical"}
+
+// PriorityRulesFromConfig validates and maps the priority mapping, invalid rules are skipped and returned as error
+func PriorityRulesFromConfig(mapping PriorityMapping) (map[v1alpha2.PolicySeverity]v1alpha2.Priority, []report.PriorityRule, error) {
+	var err error
+
+	severities := make(map[v1alpha2.PolicySeverity]v1alpha2.Priority, len(mapping.Severities))
+	for severity, priority := range mapping.Severities {
+		if !validPriority(priority) {
+			err = fmt.Errorf("priorityMapping.severities.%s: unknown priority '%s'", severity, priority)
+			continue
+		}
+
+		severities[v1alpha2.PolicySeverity(severity)] = v1alpha2.NewPriority(priority)
+	}
+
+	rules := make([]report.PriorityRule, 0, len(mapping.Rules))
+	for i, r := range mapping.Rules {
+		if !validPriority(r.Priority) {
+			err = fmt.Errorf("priorityMapping.rules[%d]: unknown priority '%s'", i, r.Priority)
+			continue
+		}
+		if r.Policy == "" && r.Pattern == "" {
+			err = fmt.Errorf("priorityMapping.rules[%d]: policy or pattern required", i)
+			continue
+		}
+
+		rule := report.PriorityRule{Policy: r.Policy, Priority: v1alpha2.NewPriority(r.Priority)}
+
+		if r.Pattern != "" {
+			pattern, perr := regexp.Compile(r.Pattern)
+			if perr != nil {
+				err = fmt.Errorf("priorityMapping.rules[%d].pattern: %w", i, perr)
+				continue
+			}
+
+			rule.Pattern = pattern
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return severities, rules, err
+}
+
+func validPriority(priority string) bool {
+	for _, p := range priorities {
+		if p == priority {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// ProfileEnv selects the configuration profile if the config-profile flag is not set
+const ProfileEnv = "CONFIG_PROFILE"
+
+// Profiles of the configuration file, each profile is an overlay of the configuration selected by its name
+type Profiles map[string]interface{}
+
+// ProfileFile returns the overlay file of the profile next to the configuration file, e.g. config.prod.yaml for config.yaml
+func ProfileFile(file, profile string) string {
+	ext := filepath.Ext(file)
+
+	return strings.TrimSuffix(file, ext) + "." + profile + ext
+}
+
+// mergeOverlays merges the layers on top of the loaded configuration file in order: the profiles.<profile> section
+// of the configuration file, the overlay file of the profile and the overlay files. Maps like targets and filters are
+// merged recursively, lists and values of a later layer replace the previous ones. Returns the merged files
+func mergeOverlays(v *viper.Viper, profile string, overlays []string) ([]string, error) {
+	files := make([]string, 0, len(overlays)+1)
+
+	if profile != "" {
+		found := false
+
+		if v.IsSet("profiles." + profile) {
+			if err := v.MergeConfigMap(v.GetStringMap("profiles." + profile)); err != nil {
+				return nil, fmt.Errorf("failed to merge profile %s: %w", profile, err)
+			}
+
+			found = true
+		}
+
+		if base := v.ConfigFileUsed(); base != "" {
+			if file := ProfileFile(base, profile); fileExists(file) {
+				overlays = append([]string{file}, overlays...)
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("unknown configuration profile %s", profile)
+		}
+	}
+
+	for _, file := range overlays {
+		o := viper.New()
+		o.SetConfigFile(file)
+
+		if err := o.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read configuration overlay %s: %w", file, err)
+		}
+		if err := v.MergeConfigMap(o.AllSettings()); err != nil {
+			return nil, fmt.Errorf("failed to merge configuration overlay %s: %w", file, err)
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+func fileExists(file string) bool {
+	info, err := os.Stat(file)
+
+	return err == nil && !info.IsDir()
+}
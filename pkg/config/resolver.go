@@ -1,61 +1,412 @@
 package config
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	goredis "github.com/go-redis/redis/v8"
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/prometheus/client_golang/prometheus"
 	mail "github.com/xhit/go-simple-mail/v2"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/dynamic"
 	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/client-go/util/workqueue"
 
+	"github.com/kyverno/policy-reporter/pkg/acknowledgment"
+	"github.com/kyverno/policy-reporter/pkg/admission"
+	"github.com/kyverno/policy-reporter/pkg/aging"
+	"github.com/kyverno/policy-reporter/pkg/alerting"
+	"github.com/kyverno/policy-reporter/pkg/annotation"
 	"github.com/kyverno/policy-reporter/pkg/api"
+	"github.com/kyverno/policy-reporter/pkg/api/auth"
+	"github.com/kyverno/policy-reporter/pkg/audit"
 	"github.com/kyverno/policy-reporter/pkg/cache"
+	"github.com/kyverno/policy-reporter/pkg/checkpoint"
+	"github.com/kyverno/policy-reporter/pkg/coverage"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
 	"github.com/kyverno/policy-reporter/pkg/crd/client/clientset/versioned"
 	wgpolicyk8sv1alpha2 "github.com/kyverno/policy-reporter/pkg/crd/client/clientset/versioned/typed/policyreport/v1alpha2"
 	"github.com/kyverno/policy-reporter/pkg/email"
 	"github.com/kyverno/policy-reporter/pkg/email/summary"
 	"github.com/kyverno/policy-reporter/pkg/email/violations"
+	"github.com/kyverno/policy-reporter/pkg/enrichment"
+	"github.com/kyverno/policy-reporter/pkg/escalation"
+	"github.com/kyverno/policy-reporter/pkg/exclusion"
+	"github.com/kyverno/policy-reporter/pkg/expression"
+	"github.com/kyverno/policy-reporter/pkg/federation"
+	"github.com/kyverno/policy-reporter/pkg/gitops"
+	"github.com/kyverno/policy-reporter/pkg/helper"
+	"github.com/kyverno/policy-reporter/pkg/ingestion"
 	"github.com/kyverno/policy-reporter/pkg/kubernetes"
 	"github.com/kyverno/policy-reporter/pkg/kubernetes/secrets"
+	"github.com/kyverno/policy-reporter/pkg/kyverno"
 	"github.com/kyverno/policy-reporter/pkg/leaderelection"
 	"github.com/kyverno/policy-reporter/pkg/listener"
 	"github.com/kyverno/policy-reporter/pkg/listener/metrics"
+	"github.com/kyverno/policy-reporter/pkg/maintenance"
+	"github.com/kyverno/policy-reporter/pkg/memory"
+	"github.com/kyverno/policy-reporter/pkg/owner"
+	"github.com/kyverno/policy-reporter/pkg/ownership"
+	"github.com/kyverno/policy-reporter/pkg/push"
 	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/rollup"
+	"github.com/kyverno/policy-reporter/pkg/rpc"
+	"github.com/kyverno/policy-reporter/pkg/score"
+	"github.com/kyverno/policy-reporter/pkg/secret"
+	"github.com/kyverno/policy-reporter/pkg/sharding"
+	"github.com/kyverno/policy-reporter/pkg/snooze"
 	"github.com/kyverno/policy-reporter/pkg/sqlite3"
+	"github.com/kyverno/policy-reporter/pkg/standalone"
+	"github.com/kyverno/policy-reporter/pkg/stream"
+	"github.com/kyverno/policy-reporter/pkg/subscription"
 	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/events"
+	targethttp "github.com/kyverno/policy-reporter/pkg/target/http"
+	"github.com/kyverno/policy-reporter/pkg/taxonomy"
+	"github.com/kyverno/policy-reporter/pkg/tracing"
 	"github.com/kyverno/policy-reporter/pkg/validate"
+	"github.com/kyverno/policy-reporter/pkg/wasm"
 )
 
 // Resolver manages dependencies
 type Resolver struct {
-	config             *Config
+	config *Config
+	// mx guards the target state replaced by Reload: targets, targetClients, targetsCreated, targetRegistry and dispatcher
+	mx *sync.RWMutex
+	// targets is the configuration of the reloaded targets and metrics filter, the startup config is used until the first reload
+	targets            *Config
 	k8sConfig          *rest.Config
 	mapper             report.Mapper
 	publisher          report.EventPublisher
 	policyStore        sqlite3.PolicyReportStore
+	acknowledgments    *acknowledgment.Tracker
+	uiLinks            *enrichment.Links
+	sourceFilter       *report.SourceFilter
+	memoryStore        *memory.Store
 	policyReportClient report.PolicyReportClient
 	leaderElector      *leaderelection.Client
+	shardingClient     *sharding.Client
+	grpcServer         *rpc.Server
+	admissionWebhook   *admission.Webhook
+	annotationWriter   *annotation.Writer
+	complianceSummary  *rollup.Controller
+	dispatcher         *listener.Dispatcher
+	metadataCache      *kubernetes.MetadataCache
+	exclusionStore     *exclusion.Store
+	policyMetadata     *kyverno.Store
+	kyvernoCatalog     *kyverno.Catalog
+	teamResolver       *ownership.Resolver
+	resultBroker       *stream.Broker
 	targetClients      []target.Client
+	targetRegistry     *target.Registry
+	eventsClient       target.Client
+	metricsFilter      *atomic.Pointer[report.ResultFilter]
 	resultCache        cache.Cache
+	cacheRestored      bool
+	startUp            time.Time
+	databases          []*sql.DB
+	deduplicator       cache.Deduplicator
+	notified           *cache.Notified
+	responseCache      *api.ResponseCache
+	redisClient        *goredis.Client
+	federationClient   *federation.Client
+	muter              *maintenance.Muter
+	schedule           *maintenance.Schedule
+	taxonomy           *taxonomy.Taxonomy
+	coverageIndex      *coverage.Index
+	ager               *aging.Ager
+	snoozes            *snooze.Registry
+	metricsGatherer    prometheus.Gatherer
+	customFields       map[string]string
 	targetsCreated     bool
 }
 
 // APIServer resolver method
 func (r *Resolver) APIServer(synced func() bool) api.Server {
-	return api.NewServer(
-		r.TargetClients(),
-		r.config.API.Port,
+	server := api.NewServer(
+		r.TargetRegistry(),
+		api.Address(r.config.API.Host, r.config.API.Port),
+		r.config.ClusterName,
 		synced,
+		r.APIAuthenticator(),
+		r.APIAccessReviewer(),
 	)
+
+	if admin := r.config.API.Admin; admin.Port != 0 {
+		server.RegisterAdminListener(api.Address(admin.Host, admin.Port))
+	}
+
+	if accessLog := r.config.API.AccessLog; accessLog.Enabled {
+		server.RegisterAccessLog(api.NewAccessLog(os.Stderr, accessLog.Encoding, accessLog.SampleRate, accessLog.SlowThreshold, accessLog.ExcludePaths))
+	}
+
+	if responseCache := r.ResponseCache(); responseCache != nil {
+		server.RegisterResponseCache(responseCache)
+	}
+
+	return server
+}
+
+// APICertificate resolver method, returns nil if TLS is disabled
+func (r *Resolver) APICertificate() (*api.Certificate, error) {
+	if !r.config.API.TLS.Enabled {
+		return nil, nil
+	}
+
+	return api.LoadCertificate(r.config.API.TLS.CertFile, r.config.API.TLS.KeyFile)
+}
+
+// ResponseCache resolver method, returns nil if the API response cache is disabled
+func (r *Resolver) ResponseCache() *api.ResponseCache {
+	if !r.config.API.Cache.Enabled {
+		return nil
+	}
+	if r.responseCache != nil {
+		return r.responseCache
+	}
+
+	r.responseCache = api.NewResponseCache(r.config.API.Cache.TTL, metrics.RegisterAPICacheMetrics().Requests)
+
+	return r.responseCache
+}
+
+// APIAccessReviewer resolver method, returns nil if the Kubernetes API authentication is disabled
+func (r *Resolver) APIAccessReviewer() auth.AccessReviewer {
+	config := r.config.API.Auth
+	if !config.Enabled || !config.Kubernetes.Enabled {
+		return nil
+	}
+
+	clientset, err := k8s.NewForConfig(r.k8sConfig)
+	if err != nil {
+		logger.Errorf("failed to create SubjectAccessReview client: %s", err)
+		return nil
+	}
+
+	return auth.NewSubjectAccessReviewer(clientset.AuthorizationV1().SubjectAccessReviews(), config.Kubernetes.CacheTTL)
+}
+
+// APIAuthenticator resolver method, returns nil if the API authentication is disabled
+func (r *Resolver) APIAuthenticator() auth.Authenticator {
+	config := r.config.API.Auth
+	if !config.Enabled {
+		return nil
+	}
+
+	var client secrets.Client
+
+	tokens := make([]auth.Token, 0, len(config.Tokens))
+	for _, t := range config.Tokens {
+		if t.SecretRef != "" {
+			if client == nil {
+				client = r.SecretClient()
+			}
+			if client != nil {
+				values, err := client.Get(context.Background(), t.SecretRef)
+				if err != nil {
+					logger.Warnf("failed to get api token secret reference: %s", err)
+				}
+				if values.Token != "" {
+					t.Token = values.Token
+				}
+			}
+			if t.Name == "" {
+				t.Name = t.SecretRef
+			}
+		}
+		if t.Token == "" {
+			logger.Warnf("skip api token %s without value", t.Name)
+			continue
+		}
+
+		tokens = append(tokens, auth.Token{Name: t.Name, Token: secret.New(t.Token), Level: auth.ParseLevel(t.Level)})
+	}
+
+	users := make([]auth.User, 0, len(config.BasicAuth))
+	for _, u := range config.BasicAuth {
+		if u.SecretRef != "" {
+			if client == nil {
+				client = r.SecretClient()
+			}
+			if client != nil {
+				values, err := client.Get(context.Background(), u.SecretRef)
+				if err != nil {
+					logger.Warnf("failed to get basic auth secret reference: %s", err)
+				}
+				if values.Username != "" {
+					u.Username = values.Username
+				}
+				if values.Password != "" {
+					u.Password = values.Password
+				}
+			}
+		}
+		if u.Username == "" || u.Password == "" {
+			logger.Warnf("skip basic auth user %s without credentials", u.Username)
+			continue
+		}
+
+		users = append(users, auth.User{Username: u.Username, Password: secret.New(u.Password), Level: auth.ParseLevel(u.Level)})
+	}
+
+	chain := auth.Chain{}
+	if len(tokens) > 0 {
+		chain = append(chain, auth.NewTokenAuthenticator(tokens))
+	}
+	if len(users) > 0 {
+		chain = append(chain, auth.NewBasicAuthenticator(users))
+	}
+	if config.Kubernetes.Enabled {
+		clientset, err := k8s.NewForConfig(r.k8sConfig)
+		if err != nil {
+			logger.Errorf("failed to create TokenReview client: %s", err)
+		} else {
+			chain = append(chain, auth.NewTokenReviewAuthenticator(
+				clientset.AuthenticationV1().TokenReviews(),
+				config.Kubernetes.Audiences,
+				config.Kubernetes.AdminGroups,
+				config.Kubernetes.CacheTTL,
+			))
+		}
+	}
+	if config.OIDC.Enabled {
+		if config.OIDC.Audience == "" {
+			logger.Error("api.auth.oidc.audience is required, all OIDC tokens are rejected")
+		}
+
+		chain = append(chain, auth.NewOIDCAuthenticator(auth.OIDC{
+			Issuer:        config.OIDC.Issuer,
+			Audience:      config.OIDC.Audience,
+			UsernameClaim: config.OIDC.UsernameClaim,
+			GroupsClaim:   config.OIDC.GroupsClaim,
+			AdminGroups:   config.OIDC.AdminGroups,
+		}, nil))
+	}
+
+	if len(chain) == 0 {
+		logger.Warn("api authentication enabled without tokens, users, Kubernetes or OIDC, all requests are rejected")
+	}
+
+	return chain
 }
 
-// Database resolver method
+// Database resolver method, the SQLite database is recreated on startup unless it persists history, tombstones, subscriptions, triage or snoozes
 func (r *Resolver) Database() (*sql.DB, error) {
-	return sqlite3.NewDatabase(r.config.DBFile)
+	dialect := sqlite3.DialectFor(r.config.Database.Type)
+	if dialect == sqlite3.SQLite && !r.persistDatabase() {
+		return r.trackDatabase(sqlite3.NewDatabase(r.config.DBFile))
+	}
+
+	return r.OpenDatabase()
+}
+
+// persistDatabase reports if the SQLite database is kept across restarts to persist the history, tombstones,
+// subscriptions, triage or snoozes
+func (r *Resolver) persistDatabase() bool {
+	c := r.config
+	return c.History.Enabled || c.Tombstones.Enabled || c.EmailReports.Subscriptions.Enabled || c.Triage.Enabled || c.Snooze.Enabled
+}
+
+// OpenDatabase opens the configured database, an existing SQLite database is kept
+func (r *Resolver) OpenDatabase() (*sql.DB, error) {
+	dialect := sqlite3.DialectFor(r.config.Database.Type)
+	if dialect == sqlite3.SQLite {
+		return r.trackDatabase(sqlite3.OpenDatabase(r.config.DBFile))
+	}
+
+	config := r.config.Database
+
+	if config.SecretRef != "" {
+		if client := r.SecretClient(); client != nil {
+			values, err := client.Get(context.Background(), config.SecretRef)
+			if err != nil {
+				logger.Warnf("failed to get database secret reference: %s", err)
+			}
+			if values.Host != "" {
+				config.Host = values.Host
+			}
+			if values.Database != "" {
+				config.Database = values.Database
+			}
+			if values.Username != "" {
+				config.Username = values.Username
+			}
+			if values.Password != "" {
+				config.Password = values.Password
+			}
+			if values.DSN != "" {
+				config.DSN = values.DSN
+			}
+		}
+	}
+
+	logger.Infof("use external %s database as PolicyReport store", dialect.Name())
+
+	return sqlite3.NewExternalDatabase(dialect, sqlite3.Connection{
+		Host:     config.Host,
+		Database: config.Database,
+		Username: config.Username,
+		Password: secret.New(config.Password),
+		SSLMode:  config.SSLMode,
+		DSN:      secret.New(config.DSN),
+	})
+}
+
+// HasMemoryStore reports if the in-memory store is configured instead of a database
+func (r *Resolver) HasMemoryStore() bool {
+	return strings.EqualFold(r.config.Database.Type, memory.Type)
+}
+
+// MemoryStore resolver method
+func (r *Resolver) MemoryStore() *memory.Store {
+	if r.memoryStore != nil {
+		return r.memoryStore
+	}
+
+	r.memoryStore = memory.NewStore()
+
+	return r.memoryStore
+}
+
+func (r *Resolver) databaseEncryptionKey() string {
+	config := r.config.Database.Encryption
+	if config.SecretRef == "" {
+		return config.Key
+	}
+
+	client := r.SecretClient()
+	if client == nil {
+		return config.Key
+	}
+
+	values, err := client.Get(context.Background(), config.SecretRef)
+	if err != nil {
+		logger.Warnf("failed to get database encryption secret reference: %s", err)
+	}
+	if values.EncryptionKey != "" {
+		return values.EncryptionKey
+	}
+
+	return config.Key
 }
 
 // PolicyReportStore resolver method
@@ -64,9 +415,46 @@ func (r *Resolver) PolicyReportStore(db *sql.DB) (sqlite3.PolicyReportStore, err
 		return r.policyStore, nil
 	}
 
-	s, err := sqlite3.NewPolicyReportStore(db)
+	dialect := sqlite3.DialectFor(r.config.Database.Type)
+
+	s, err := sqlite3.NewPolicyReportStoreWithDialect(db, dialect)
 	r.policyStore = s
 
+	if err == nil && r.config.Database.Encryption.Enabled {
+		var cipher *sqlite3.FieldCipher
+
+		cipher, err = sqlite3.NewFieldCipher(secret.New(r.databaseEncryptionKey()))
+		if err != nil {
+			return r.policyStore, fmt.Errorf("failed to enable database encryption: %w", err)
+		}
+
+		s.EnableEncryption(cipher)
+		logger.Info("database encryption of result messages and property values enabled")
+	}
+
+	if err == nil {
+		writes := metrics.RegisterStoreMetrics().Writes
+
+		s.ObserveWrites(func(operation string, duration time.Duration) {
+			writes.WithLabelValues(operation).Observe(duration.Seconds())
+		})
+	}
+
+	if err == nil && r.config.History.Enabled {
+		s.EnableHistory()
+	}
+	if err == nil && r.config.Tombstones.Enabled {
+		s.EnableTombstones()
+	}
+	if err == nil && r.config.Triage.Enabled {
+		s.EnableTriage()
+	}
+
+	// the current state of a kept SQLite database is restored by the informers
+	if err == nil && dialect == sqlite3.SQLite && r.persistDatabase() {
+		err = s.CleanUp()
+	}
+
 	return r.policyStore, err
 }
 
@@ -95,6 +483,29 @@ func (r *Resolver) LeaderElectionClient() (*leaderelection.Client, error) {
 	return r.leaderElector, nil
 }
 
+// ShardingClient resolver method
+func (r *Resolver) ShardingClient() (*sharding.Client, error) {
+	if r.shardingClient != nil {
+		return r.shardingClient, nil
+	}
+
+	clientset, err := k8s.NewForConfig(r.k8sConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	r.shardingClient = sharding.New(
+		clientset.CoordinationV1().Leases(r.config.LeaderElection.Namespace),
+		r.config.LeaderElection.LockName,
+		r.config.LeaderElection.PodName,
+		time.Duration(r.config.Sharding.LeaseDuration)*time.Second,
+		time.Duration(r.config.Sharding.RenewPeriod)*time.Second,
+		r.config.Sharding.VirtualNodes,
+	)
+
+	return r.shardingClient, nil
+}
+
 // EventPublisher resolver method
 func (r *Resolver) EventPublisher() report.EventPublisher {
 	if r.publisher != nil {
@@ -114,105 +525,1556 @@ func (r *Resolver) Queue() (*kubernetes.Queue, error) {
 		return nil, err
 	}
 
-	return kubernetes.NewQueue(
-		kubernetes.NewDebouncer(1*time.Minute, r.EventPublisher()),
+	workqueue.SetProvider(metrics.RegisterWorkqueueProvider())
+
+	queue := kubernetes.NewQueue(
+		kubernetes.NewConfiguredDebouncer(r.DebounceOptions(), r.EventPublisher()),
 		workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "report-queue"),
 		client,
-	), nil
+	)
+
+	mappers, err := r.reportMappers()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, mapper := range mappers {
+		queue.RegisterMapper(mapper)
+	}
+
+	if sources := r.SourceFilter(); sources != nil {
+		sources.Requeue(queue.AddKey)
+	}
+
+	return queue, nil
 }
 
-// RegisterSendResultListener resolver method
-func (r *Resolver) RegisterSendResultListener() {
-	targets := r.TargetClients()
-	if len(targets) > 0 {
-		newResultListener := listener.NewResultListener(r.SkipExistingOnStartup(), r.ResultCache(), time.Now())
-		newResultListener.RegisterListener(listener.NewSendResultListener(targets, r.Mapper()))
+// DebounceOptions resolver method
+func (r *Resolver) DebounceOptions() kubernetes.DebounceOptions {
+	config := r.config.Debounce
+	m := metrics.RegisterDebounceMetrics()
+
+	windows := make([]kubernetes.DebounceWindow, 0, len(config.Windows))
+	for _, w := range config.Windows {
+		windows = append(windows, kubernetes.DebounceWindow{
+			Sources:    w.Sources,
+			Namespaces: w.Namespaces,
+			Window:     w.Window,
+			MaxDelay:   w.MaxDelay,
+		})
+	}
 
-		r.EventPublisher().RegisterListener(listener.NewResults, newResultListener.Listen)
+	return kubernetes.DebounceOptions{
+		EmptyWait: config.EmptyUpdates,
+		Window:    config.Window,
+		MaxDelay:  config.MaxDelay,
+		Windows:   windows,
+		Coalesced: func(source string) {
+			m.Coalesced.WithLabelValues(source).Inc()
+		},
+		Pending: func(count int) {
+			m.Pending.Set(float64(count))
+		},
 	}
 }
 
-// RegisterSendResultListener resolver method
-func (r *Resolver) RegisterStoreListener(store report.PolicyReportStore) {
-	r.EventPublisher().RegisterListener(listener.Store, listener.NewStoreListener(store))
+// reportMappers applied to each PolicyReport before it is published
+func (r *Resolver) reportMappers() ([]func(v1alpha2.ReportInterface), error) {
+	mappers := make([]func(v1alpha2.ReportInterface), 0, 10)
+	// the scope resource is required by the resource based mappers and the result IDs
+	mappers = append(mappers, report.MapScope, report.MapNode)
+	// Kyverno reports ValidatingAdmissionPolicy results with varying sources, the normalized source applies to all filters
+	mappers = append(mappers, enrichment.NewEnricher(enrichment.ValidatingAdmissionPolicy{}).Enrich)
+
+	if corrector, err := r.SummaryCorrector(); err != nil {
+		return nil, err
+	} else if corrector != nil {
+		mappers = append(mappers, corrector.Map)
+	}
+
+	if sources := r.SourceFilter(); sources != nil {
+		mappers = append(mappers, sources.Map)
+	}
+
+	if r.config.Orphaned.Enabled {
+		cache, err := r.MetadataCache()
+		if err != nil {
+			return nil, err
+		}
+
+		mappers = append(mappers, report.NewOrphanDetector(cache).Map)
+	}
+
+	if age := r.ResultAge(); age != nil && r.config.ResultAge.DropFromStore {
+		mappers = append(mappers, age.Map)
+	}
+
+	if r.config.ErrorRouting.Enabled {
+		mappers = append(mappers, enrichment.NewErrorDetails(r.config.ErrorRouting.Properties).Map)
+	}
+
+	// custom thresholds take precedence over the fixed CVSS bands of the source mappers
+	if r.config.CVSSSeverity.Enabled {
+		mappers = append(mappers, r.CVSSSeverity().Map)
+	}
+
+	if r.config.SourceMappers.Enabled {
+		mappers = append(mappers, r.Enricher().Enrich)
+	}
+
+	if r.config.PolicyMetadata.Enabled {
+		mappers = append(mappers, r.PolicyMetadataStore().Enrich)
+	}
+
+	if r.config.Owners.Enabled {
+		if metadata := r.resourceMetadata(); metadata != nil {
+			mappers = append(mappers, owner.NewResolver(metadata).Map)
+		}
+	}
+
+	if r.config.GitOps.Enabled {
+		if metadata := r.resourceMetadata(); metadata != nil {
+			mappers = append(mappers, gitops.NewResolver(metadata, r.config.GitOps.ArgoCDLabel).Map)
+		}
+	}
+
+	if r.config.Ownership.Enabled {
+		mappers = append(mappers, r.TeamResolver().Map)
+	}
+
+	if len(r.config.ResultIDs) > 0 {
+		mapper, err := IDMapperFromConfig(r.config.ResultIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		mappers = append(mappers, mapper.Map)
+	}
+
+	// the aging tracks the results by their final ID
+	if r.config.SeverityAging.Enabled {
+		ager, err := r.Ager()
+		if err != nil {
+			return nil, err
+		}
+
+		mappers = append(mappers, ager.Map)
+	}
+
+	// the links reference the results by their final ID
+	if links, err := r.UILinks(); err != nil {
+		return nil, err
+	} else if links != nil {
+		mappers = append(mappers, links.Map)
+	}
+
+	return mappers, nil
 }
 
-// RegisterMetricsListener resolver method
-func (r *Resolver) RegisterMetricsListener() {
-	r.EventPublisher().RegisterListener(listener.Metrics, listener.NewMetricsListener(
-		metrics.NewResultFilter(
-			ToRuleSet(r.config.Metrics.Filter.Namespaces),
-			ToRuleSet(r.config.Metrics.Filter.Status),
-			ToRuleSet(r.config.Metrics.Filter.Policies),
-			ToRuleSet(r.config.Metrics.Filter.Sources),
-			ToRuleSet(r.config.Metrics.Filter.Severities),
-		),
-		metrics.NewReportFilter(
-			ToRuleSet(r.config.Metrics.Filter.Namespaces),
-			ToRuleSet(r.config.Metrics.Filter.Sources),
-		),
-		r.config.Metrics.Mode,
-		r.config.Metrics.CustomLabels,
-	))
+// Ingester resolver method
+func (r *Resolver) Ingester() (*ingestion.Ingester, error) {
+	mappers, err := r.reportMappers()
+	if err != nil {
+		return nil, err
+	}
+
+	return ingestion.NewIngester(r.EventPublisher(), r.ReportFilter(), mappers, ingestion.FalcoOptions{
+		Retention:  r.config.Ingestion.Falco.Retention,
+		MaxResults: r.config.Ingestion.Falco.MaxResults,
+	}, ingestion.NDJSONOptions{
+		MaxStreams:     r.config.Ingestion.NDJSON.MaxStreams,
+		MaxResults:     r.config.Ingestion.NDJSON.MaxResults,
+		IdempotencyTTL: r.config.Ingestion.NDJSON.IdempotencyTTL,
+	}), nil
 }
 
-// Mapper resolver method
-func (r *Resolver) Mapper() report.Mapper {
-	if r.mapper != nil {
-		return r.mapper
+// Enricher resolver method
+func (r *Resolver) Enricher() *enrichment.Enricher {
+	return enrichment.NewEnricher(enrichment.Builtin(r.config.SourceMappers.Mappers...)...)
+}
+
+// SourceFilter resolver method, returns nil if no source is disabled or prioritized
+func (r *Resolver) SourceFilter() *report.SourceFilter {
+	if r.sourceFilter != nil {
+		return r.sourceFilter
+	}
+
+	c := r.config.Sources
+	if len(c.Disabled) == 0 && len(c.Precedence) == 0 {
+		return nil
 	}
 
-	mapper := report.NewMapper(r.config.PriorityMap)
+	r.sourceFilter = report.NewSourceFilter(c.Disabled, c.Precedence)
 
-	r.mapper = mapper
+	return r.sourceFilter
+}
 
-	return mapper
+// RegisterSourceFilterListener resolver method, removes the policy and resource pairs of deleted reports from the source precedence
+// and queues the reports with the superseded findings again
+func (r *Resolver) RegisterSourceFilterListener() {
+	if filter := r.SourceFilter(); filter != nil && len(r.config.Sources.Precedence) > 0 {
+		r.EventPublisher().RegisterListener(report.SourceListener, filter.Listen)
+	}
 }
 
-// SecretClient resolver method
-func (r *Resolver) SecretClient() secrets.Client {
-	clientset, err := k8s.NewForConfig(r.k8sConfig)
+// UILinks resolver method, returns nil if the deep links are disabled
+func (r *Resolver) UILinks() (*enrichment.Links, error) {
+	if r.uiLinks != nil {
+		return r.uiLinks, nil
+	}
+	if !r.config.UILinks.Enabled {
+		return nil, nil
+	}
+
+	links, err := enrichment.NewLinks(r.config.UILinks.BaseURL, r.config.UILinks.Path)
 	if err != nil {
+		return nil, err
+	}
+
+	r.uiLinks = links
+
+	return r.uiLinks, nil
+}
+
+// CVSSSeverity resolver method
+func (r *Resolver) CVSSSeverity() *enrichment.CVSSSeverity {
+	c := r.config.CVSSSeverity
+
+	return enrichment.NewCVSSSeverity(c.Sources, c.Properties, enrichment.Thresholds{
+		Critical: c.Thresholds.Critical,
+		High:     c.Thresholds.High,
+		Medium:   c.Thresholds.Medium,
+		Low:      c.Thresholds.Low,
+	}, c.Override)
+}
+
+// TeamResolver resolver method
+func (r *Resolver) TeamResolver() *ownership.Resolver {
+	if r.teamResolver != nil {
+		return r.teamResolver
+	}
+
+	mappings := make([]ownership.Mapping, 0, len(r.config.Ownership.Teams))
+	for _, team := range r.config.Ownership.Teams {
+		mappings = append(mappings, ownership.Mapping{Team: team.Name, Namespaces: team.Namespaces})
+	}
+
+	var metadata report.ResourceMetadata
+	if r.config.Ownership.NamespaceLabel != "" || r.config.Ownership.NamespaceAnnotation != "" {
+		metadata = r.resourceMetadata()
+	}
+
+	r.teamResolver = ownership.NewResolver(metadata, ownership.Options{
+		NamespaceLabel:      r.config.Ownership.NamespaceLabel,
+		NamespaceAnnotation: r.config.Ownership.NamespaceAnnotation,
+		Mappings:            mappings,
+	})
+
+	return r.teamResolver
+}
+
+// PolicyMetadataStore resolver method
+func (r *Resolver) PolicyMetadataStore() *kyverno.Store {
+	if r.policyMetadata != nil {
+		return r.policyMetadata
+	}
+
+	a := r.config.PolicyMetadata.Annotations
+
+	r.policyMetadata = kyverno.NewStore(kyverno.Annotations{
+		Title:       a.Title,
+		Description: a.Description,
+		Remediation: a.Remediation,
+		Category:    a.Category,
+		Severity:    a.Severity,
+		Priority:    a.Priority,
+		URL:         a.URL,
+	})
+
+	return r.policyMetadata
+}
+
+// KyvernoCatalog resolver method
+func (r *Resolver) KyvernoCatalog() *kyverno.Catalog {
+	if r.kyvernoCatalog != nil {
+		return r.kyvernoCatalog
+	}
+
+	a := r.config.PolicyMetadata.Annotations
+
+	r.kyvernoCatalog = kyverno.NewCatalog(kyverno.Annotations{
+		Title:       a.Title,
+		Description: a.Description,
+		Category:    a.Category,
+		Severity:    a.Severity,
+	})
+
+	return r.kyvernoCatalog
+}
+
+// ResultDispatcher resolver method
+func (r *Resolver) ResultDispatcher() *listener.Dispatcher {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if r.dispatcher != nil {
+		return r.dispatcher
+	}
+
+	r.dispatcher = listener.NewDispatcher(r.resolveTargetClients(), r.Mapper(), metrics.RegisterDispatcherMetrics(), listener.DispatcherOptions{
+		Workers:   r.config.Dispatcher.Workers,
+		QueueSize: r.config.Dispatcher.QueueSize,
+		Notified:  r.notified,
+	})
+
+	return r.dispatcher
+}
+
+// RegisterSendResultListener resolver method
+func (r *Resolver) RegisterSendResultListener() {
+	targets := r.TargetClients()
+	if len(targets) > 0 {
+		newResultListener := listener.NewResultListener(r.SkipExistingOnStartup(), r.ResultCache(), r.StartUp())
+		if r.config.Deduplication.Enabled {
+			dedupe, err := r.Deduplicator()
+			if err != nil {
+				logger.Errorf("failed to create deduplicator, deduplication disabled: %s", err)
+			} else {
+				newResultListener.RegisterDeduplicator(dedupe)
+			}
+		}
+
+		if r.config.Owners.Enabled && r.config.Owners.Deduplicate {
+			newResultListener.RegisterOwnerDeduplicator(cache.NewInMemoryDeduplicator(r.config.Owners.TTL), owner.Key)
+		}
+
+		if r.config.Reconciliation.Enabled {
+			newResultListener.EnableReconciliation(r.cacheRestored)
+		}
+
+		sendResultListener := r.ResultDispatcher().Dispatch
+		if r.config.Sharding.Enabled {
+			shards, err := r.ShardingClient()
+			if err != nil {
+				logger.Errorf("failed to create sharding client, process all namespaces: %s", err)
+			} else {
+				sendResultListener = listener.NewShardedResultListener(shards, sendResultListener)
+			}
+		}
+
+		if r.snoozes != nil {
+			sendResultListener = r.snoozes.Results(sendResultListener)
+		}
+
+		if r.config.Maintenance.Enabled {
+			sendResultListener = r.Muter().Results(listener.NewResults, sendResultListener)
+		}
+
+		if pipeline := r.WasmPipeline(); pipeline != nil {
+			sendResultListener = pipeline.Results(sendResultListener)
+		}
+
+		newResultListener.RegisterListener(sendResultListener)
+
+		r.EventPublisher().RegisterListener(listener.NewResults, newResultListener.Listen)
+
+		if hasResolvingClients(targets) {
+			resolveListener := listener.NewResolveResultListener(r.TargetRegistry().Clients, r.Mapper())
+			if r.config.Sharding.Enabled {
+				if shards, err := r.ShardingClient(); err == nil {
+					resolveListener = listener.NewShardedResultListener(shards, resolveListener)
+				}
+			}
+
+			if r.snoozes != nil {
+				resolveListener = r.snoozes.Results(resolveListener)
+			}
+
+			if r.config.Maintenance.Enabled {
+				resolveListener = r.Muter().Results(listener.ResolvedResults, resolveListener)
+			}
+
+			resolvedResultListener := listener.NewResolvedResultListener()
+			resolvedResultListener.RegisterListener(resolveListener)
+
+			r.EventPublisher().RegisterListener(listener.ResolvedResults, resolvedResultListener.Listen)
+		}
+
+		if hasReportClients(targets) {
+			reportListener := listener.NewReportChangeListener(r.TargetRegistry().Clients, r.StartUp())
+			if r.config.Sharding.Enabled {
+				if shards, err := r.ShardingClient(); err == nil {
+					reportListener = listener.NewShardedReportListener(shards, reportListener)
+				}
+			}
+
+			if r.config.Maintenance.Enabled {
+				reportListener = r.Muter().Reports(listener.ReportChanges, reportListener)
+			}
+
+			r.EventPublisher().RegisterListener(listener.ReportChanges, reportListener)
+		}
+	}
+}
+
+// MaintenanceSchedule resolver method, invalid quiet hours and windows are skipped
+func (r *Resolver) MaintenanceSchedule() *maintenance.Schedule {
+	if r.schedule != nil {
+		return r.schedule
+	}
+
+	schedule, err := ScheduleFromConfig(r.config.Maintenance)
+	if err != nil {
+		logger.Errorf("invalid maintenance configuration: %s", err)
+	}
+
+	r.schedule = schedule
+
+	return r.schedule
+}
+
+// SnoozeRegistry resolver method, the snoozed results are excluded from the result notifications registered afterwards
+func (r *Resolver) SnoozeRegistry(store snooze.Store) *snooze.Registry {
+	if r.snoozes != nil {
+		return r.snoozes
+	}
+
+	r.snoozes = snooze.NewRegistry(store, r.config.Snooze.MaxDuration)
+
+	return r.snoozes
+}
+
+// Muter resolver method
+func (r *Resolver) Muter() *maintenance.Muter {
+	if r.muter != nil {
+		return r.muter
+	}
+
+	r.muter = maintenance.NewMuter(r.MaintenanceSchedule(), r.config.Maintenance.Mode)
+
+	return r.muter
+}
+
+// CoverageIndex resolver method
+func (r *Resolver) CoverageIndex() *coverage.Index {
+	if r.coverageIndex != nil {
+		return r.coverageIndex
+	}
+
+	r.coverageIndex = coverage.NewIndex()
+
+	return r.coverageIndex
+}
+
+// RegisterCoverageListener resolver method
+func (r *Resolver) RegisterCoverageListener() {
+	r.EventPublisher().RegisterListener(coverage.Listener, r.CoverageIndex().Listen)
+}
+
+// CoverageScanner resolver method, updates the coverage gauges with each scan if metrics are enabled
+func (r *Resolver) CoverageScanner() (*coverage.Scanner, error) {
+	client, err := metadata.NewForConfig(r.k8sConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := k8s.NewForConfig(r.k8sConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	config := r.config.Coverage
+
+	kinds := make([]coverage.Kind, 0, len(config.Kinds))
+	for _, value := range config.Kinds {
+		kind, err := coverage.ParseKind(value)
+		if err != nil {
+			return nil, err
+		}
+
+		kinds = append(kinds, kind)
+	}
+
+	var observer func(coverage.Report)
+	if r.config.Metrics.Enabled {
+		gauges := metrics.RegisterCoverageGauges()
+
+		observer = func(rep coverage.Report) {
+			gauges.Resources.Reset()
+			gauges.Unevaluated.Reset()
+			gauges.Uncovered.Reset()
+
+			for _, kind := range rep.Kinds {
+				if kind.Error != "" {
+					continue
+				}
+
+				gauges.Resources.WithLabelValues(kind.Kind.Kind).Set(float64(kind.Resources))
+				gauges.Unevaluated.WithLabelValues(kind.Kind.Kind).Set(float64(kind.Unevaluated))
+
+				for _, source := range kind.Sources {
+					gauges.Uncovered.WithLabelValues(kind.Kind.Kind, source.Source).Set(float64(source.Uncovered))
+				}
+			}
+		}
+	}
+
+	return coverage.NewScanner(
+		coverage.NewMetadataLister(client, clientset.Discovery()),
+		r.CoverageIndex(),
+		coverage.Options{
+			Kinds:        kinds,
+			Sources:      config.Sources,
+			Namespaces:   ToRuleSet(config.Namespaces),
+			MaxResources: config.MaxResources,
+		},
+		observer,
+	), nil
+}
+
+// WasmPipeline resolver method, returns nil if the WASM plugins are disabled
+func (r *Resolver) WasmPipeline() *wasm.Pipeline {
+	if !r.config.WasmPlugins.Enabled {
+		return nil
+	}
+
+	modules := make([]wasm.Module, 0, len(r.config.WasmPlugins.Modules))
+	for i, m := range r.config.WasmPlugins.Modules {
+		name := m.Name
+		if name == "" {
+			name = fmt.Sprintf("module-%d", i)
+		}
+
+		modules = append(modules, wasm.Module{Name: name, Path: m.Path, Args: m.Args, Sources: m.Sources})
+	}
+
+	return wasm.NewPipeline(wasm.Options{
+		Timeout:  r.config.WasmPlugins.Timeout,
+		CacheDir: r.config.WasmPlugins.CacheDir,
+		Modules:  modules,
+	})
+}
+
+func hasTarget(clients []target.Client, name string) bool {
+	for _, c := range clients {
+		if c.Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasResolvingClients(clients []target.Client) bool {
+	for _, c := range clients {
+		if _, ok := c.(target.ResolvingClient); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasReportClients(clients []target.Client) bool {
+	for _, c := range clients {
+		if _, ok := c.(target.ReportClient); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ResultStreamBroker resolver method
+func (r *Resolver) ResultStreamBroker() *stream.Broker {
+	if r.resultBroker != nil {
+		return r.resultBroker
+	}
+
+	r.resultBroker = stream.NewBroker(100)
+
+	return r.resultBroker
+}
+
+// RegisterResultStreamListener resolver method
+func (r *Resolver) RegisterResultStreamListener() {
+	r.EventPublisher().RegisterListener(stream.Listener, r.ResultStreamBroker().Listen)
+}
+
+// AnnotationWriter resolver method, with sharding or leader election only the owner of a namespace patches its resources
+func (r *Resolver) AnnotationWriter() (*annotation.Writer, error) {
+	if r.annotationWriter != nil {
+		return r.annotationWriter, nil
+	}
+
+	client, err := metadata.NewForConfig(r.k8sConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := k8s.NewForConfig(r.k8sConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	owns, err := r.ownedNamespaces()
+	if err != nil {
+		return nil, err
+	}
+
+	r.annotationWriter = annotation.NewWriter(
+		annotation.NewPatcher(client, clientset.Discovery()),
+		r.config.Annotations.Name,
+		r.config.Annotations.Namespaces,
+		owns,
+	)
+
+	return r.annotationWriter, nil
+}
+
+// ComplianceSummaryController resolver method, with sharding or leader election only the leader or the owner of the cluster scoped results writes the summary
+func (r *Resolver) ComplianceSummaryController() (*rollup.Controller, error) {
+	if r.complianceSummary != nil {
+		return r.complianceSummary, nil
+	}
+
+	client, err := r.DynamicClient()
+	if err != nil {
+		return nil, err
+	}
+
+	owns, err := r.ownedNamespaces()
+	if err != nil {
+		return nil, err
+	}
+
+	var leading func() bool
+	if owns != nil {
+		leading = func() bool { return owns("") }
+	}
+
+	r.complianceSummary = rollup.NewController(client, r.config.Compliance.Name, r.ScoreWeights(), r.config.Compliance.Interval, leading)
+
+	return r.complianceSummary, nil
+}
+
+// RegisterComplianceSummaryListener resolver method
+func (r *Resolver) RegisterComplianceSummaryListener(controller *rollup.Controller) {
+	r.EventPublisher().RegisterListener(rollup.Listener, controller.Listen)
+}
+
+// RegisterAnnotationWriterListener resolver method
+func (r *Resolver) RegisterAnnotationWriterListener(writer *annotation.Writer) {
+	r.EventPublisher().RegisterListener(annotation.Listener, writer.Listen)
+}
+
+// GRPCCertificate resolver method, returns nil if TLS is disabled
+func (r *Resolver) GRPCCertificate() (*api.Certificate, error) {
+	if !r.config.GRPC.TLS.Enabled {
+		return nil, nil
+	}
+
+	return api.LoadCertificate(r.config.GRPC.TLS.CertFile, r.config.GRPC.TLS.KeyFile)
+}
+
+// GRPCServer resolver method, refuses to serve the results unauthenticated if the REST API requires authentication
+func (r *Resolver) GRPCServer(finder rpc.Finder, certificate *api.Certificate) (*rpc.Server, error) {
+	if r.grpcServer != nil {
+		return r.grpcServer, nil
+	}
+
+	c := r.config.GRPC
+	if r.config.API.Auth.Enabled && !c.Auth.Enabled {
+		return nil, errors.New("grpc.auth.enabled is required with api.auth.enabled, the gRPC api would serve the results unauthenticated")
+	}
+
+	var options []grpc.ServerOption
+	if certificate != nil {
+		version, err := api.TLSVersion(c.TLS.MinVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, grpc.Creds(credentials.NewTLS(certificate.TLSConfig(version))))
+	}
+
+	var access auth.AccessReviewer
+	if c.Auth.Enabled {
+		authenticator := r.APIAuthenticator()
+		if authenticator == nil {
+			return nil, errors.New("grpc.auth.enabled requires api.auth.enabled")
+		}
+
+		access = r.APIAccessReviewer()
+		options = append(options, grpc.UnaryInterceptor(rpc.UnaryAuthenticator(authenticator)), grpc.StreamInterceptor(rpc.StreamAuthenticator(authenticator)))
+	}
+
+	r.grpcServer = rpc.NewServer(finder, api.Address(c.BindAddress, c.Port), access, options...)
+
+	return r.grpcServer, nil
+}
+
+// AdmissionWebhook resolver method
+func (r *Resolver) AdmissionWebhook() *admission.Webhook {
+	if r.admissionWebhook != nil {
+		return r.admissionWebhook
+	}
+
+	c := r.config.Admission
+	r.admissionWebhook = admission.NewWebhook(c.Mode, c.Port, c.CertFile, c.KeyFile)
+
+	return r.admissionWebhook
+}
+
+// RegisterGRPCWatchListener resolver method
+func (r *Resolver) RegisterGRPCWatchListener(server *rpc.Server) {
+	newResultListener := listener.NewResultListener(true, cache.NewInMermoryCache(), time.Now())
+	newResultListener.RegisterListener(server.Publish)
+
+	r.EventPublisher().RegisterListener(rpc.WatchListener, newResultListener.Listen)
+}
+
+// RegisterSendResultListener resolver method
+func (r *Resolver) RegisterStoreListener(store report.PolicyReportStore) {
+	var filter *report.ResultFilter
+
+	config := r.config.API.Filter
+	if config.Expression != "" || hasValues(config.ReportLabels, config.ResourceLabels, config.ResourceAnnotations) {
+		filter = report.NewResultFilter()
+		addLabelFilters(filter, r.resourceMetadata(), config.ReportLabels, config.ResourceLabels, config.ResourceAnnotations)
+		addExpressionFilter(filter, config.Expression)
+	}
+
+	storeListener := listener.NewStoreListener(store, filter)
+
+	// cached API responses are invalidated after the change is stored
+	if responseCache := r.ResponseCache(); responseCache != nil {
+		r.EventPublisher().RegisterListener(listener.Store, func(event report.LifecycleEvent) {
+			storeListener(event)
+			responseCache.Invalidate()
+		})
+		return
+	}
+
+	r.EventPublisher().RegisterListener(listener.Store, storeListener)
+}
+
+// RegisterProcessingLagListener resolver method
+func (r *Resolver) RegisterProcessingLagListener() {
+	r.EventPublisher().RegisterListener(listener.ProcessingLag, listener.NewProcessingLagListener(metrics.RegisterProcessingLag()))
+}
+
+// RegisterMetricsListener resolver method, the result filter is replaced on configuration reloads
+func (r *Resolver) RegisterMetricsListener() {
+	r.metricsFilter = new(atomic.Pointer[report.ResultFilter])
+	r.metricsFilter.Store(r.metricsResultFilter())
+
+	filter := report.NewResultFilter()
+	filter.AddReportValidation(func(rep v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) bool {
+		return r.metricsFilter.Load().ValidateReportResult(rep, result)
+	})
+
+	display := r.Taxonomy()
+
+	// results of sources with dedicated metric families are not counted in the generic result gauges
+	generic := filter
+	if dedicated := r.dedicatedMetricSources(); len(dedicated) > 0 {
+		generic = report.NewResultFilter()
+		generic.AddReportValidation(func(rep v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) bool {
+			return !dedicated[strings.ToLower(result.Source)] && r.metricsFilter.Load().ValidateReportResult(rep, result)
+		})
+	}
+
+	r.EventPublisher().RegisterListener(listener.Metrics, display.Reports(listener.NewMetricsListener(
+		generic,
+		metrics.NewReportFilter(
+			ToRuleSet(r.config.Metrics.Filter.Namespaces),
+			ToRuleSet(r.config.Metrics.Filter.Sources),
+			ToRuleSet(r.config.Metrics.Filter.ReportLabels),
+		),
+		r.config.Metrics.Mode,
+		r.config.Metrics.CustomLabels,
+	)))
+
+	if pipelines := r.MetricsPipelines(); len(pipelines) > 0 {
+		r.EventPublisher().RegisterListener(listener.MetricsPipeline, display.Reports(listener.NewMetricsPipelineListener(filter, pipelines)))
+	}
+
+	if r.config.Metrics.Exemplars {
+		r.EventPublisher().RegisterListener(listener.MetricsExemplar, display.Reports(listener.NewResultCounterListener(filter)))
+	}
+
+	if r.config.Score.Enabled {
+		r.EventPublisher().RegisterListener(listener.MetricsScore, display.Reports(listener.NewScoreListener(filter, r.ScoreWeights())))
+	}
+}
+
+func (r *Resolver) metricsResultFilter() *report.ResultFilter {
+	r.mx.RLock()
+	config := r.reloadable().Metrics.Filter
+	r.mx.RUnlock()
+
+	filter := metrics.NewResultFilter(
+		ToRuleSet(config.Namespaces),
+		ToRuleSet(config.Status),
+		ToRuleSet(config.Policies),
+		ToRuleSet(config.Sources),
+		ToRuleSet(config.Severities),
+	)
+
+	if r.config.Exclusions.Enabled {
+		filter.AddReportValidation(r.ExclusionStore().Validate)
+	}
+
+	if r.config.Orphaned.Enabled && r.config.Orphaned.ExcludeFromMetrics {
+		filter.AddValidation(notOrphaned)
+	}
+
+	addLabelFilters(filter, r.resourceMetadata(), config.ReportLabels, config.ResourceLabels, config.ResourceAnnotations)
+	addTeamFilter(filter, config.Teams)
+	addExpressionFilter(filter, config.Expression)
+
+	return filter
+}
+
+// MetricsGatherer resolver method, adds the cluster label and the custom fields to all metrics if enabled
+// and limits the series of each metric family by the series limit
+func (r *Resolver) MetricsGatherer() prometheus.Gatherer {
+	if r.metricsGatherer != nil {
+		return r.metricsGatherer
+	}
+
+	var gatherer prometheus.Gatherer = prometheus.DefaultGatherer
+	if r.config.Metrics.SeriesLimit > 0 {
+		gatherer = metrics.NewCardinalityGuard(gatherer, r.config.Metrics.SeriesLimit, metrics.RegisterCardinalityMetrics())
+	}
+
+	labels := make(map[string]string)
+	if r.config.CustomFields.MetricLabels {
+		for key, value := range r.CustomFields() {
+			labels[key] = value
+		}
+	}
+	if r.config.Metrics.ClusterLabel && r.config.ClusterName != "" {
+		labels[ClusterField] = r.config.ClusterName
+	}
+
+	if len(labels) > 0 {
+		gatherer = metrics.NewLabelGatherer(gatherer, labels)
+	}
+
+	r.metricsGatherer = gatherer
+
+	return gatherer
+}
+
+// MetricsPushgateway resolver method, returns nil without configured URL
+func (r *Resolver) MetricsPushgateway() push.Pusher {
+	config := r.config.Metrics.Pushgateway
+	if config.URL == "" {
+		return nil
+	}
+
+	if config.SecretRef != "" {
+		r.resolvePushSecret(config.SecretRef, &config.Username, &config.Password, nil)
+	}
+
+	return push.NewPushgateway(
+		config.URL,
+		config.Job,
+		config.Grouping,
+		push.BasicAuth{Username: config.Username, Password: secret.New(config.Password)},
+		r.MetricsGatherer(),
+		&http.Client{Timeout: 30 * time.Second},
+	)
+}
+
+// MetricsRemoteWrite resolver method, returns nil without configured URL
+func (r *Resolver) MetricsRemoteWrite() push.Pusher {
+	config := r.config.Metrics.RemoteWrite
+	if config.URL == "" {
+		return nil
+	}
+
+	if config.SecretRef != "" {
+		r.resolvePushSecret(config.SecretRef, &config.Username, &config.Password, &config.Token)
+	}
+
+	headers := make(map[string]string, len(config.Headers)+1)
+	for name, value := range config.Headers {
+		headers[name] = value
+	}
+	if config.Token != "" {
+		headers["Authorization"] = "Bearer " + secret.New(config.Token).Value()
+	}
+
+	return push.NewRemoteWrite(
+		config.URL,
+		headers,
+		config.Labels,
+		push.BasicAuth{Username: config.Username, Password: secret.New(config.Password)},
+		r.MetricsGatherer(),
+		&http.Client{Timeout: 30 * time.Second},
+	)
+}
+
+// AuditLog resolver method, returns nil if the audit is disabled
+func (r *Resolver) AuditLog() (*audit.Log, error) {
+	config := r.config.Audit
+	if !config.Enabled {
+		return nil, nil
+	}
+
+	return audit.NewLog(config.Path, int64(config.MaxSize)*1024*1024, config.MaxBackups)
+}
+
+// AcknowledgmentTracker resolver method, returns nil if the acknowledgments are disabled
+func (r *Resolver) AcknowledgmentTracker() *acknowledgment.Tracker {
+	if r.acknowledgments != nil {
+		return r.acknowledgments
+	}
+
+	config := r.config.Acknowledgments
+	if !config.Enabled {
+		return nil
+	}
+
+	for _, name := range config.Targets {
+		if !hasTarget(r.TargetClients(), name) {
+			logger.Warnf("acknowledgments of unknown target %s", name)
+		}
+	}
+
+	r.acknowledgments = acknowledgment.NewTracker(config.Targets, config.Threshold, config.Retention)
+
+	return r.acknowledgments
+}
+
+// TracerProvider resolver method, returns nil if tracing is disabled
+func (r *Resolver) TracerProvider() (*sdktrace.TracerProvider, error) {
+	config := r.config.Tracing
+	if !config.Enabled {
+		return nil, nil
+	}
+
+	return tracing.NewProvider(tracing.Options{
+		Endpoint:    config.Endpoint,
+		Headers:     config.Headers,
+		ServiceName: config.ServiceName,
+		SampleRatio: config.SampleRatio,
+		Interval:    config.Interval,
+		QueueSize:   config.QueueSize,
+	})
+}
+
+func (r *Resolver) resolvePushSecret(ref string, username, password, token *string) {
+	client := r.SecretClient()
+	if client == nil {
+		return
+	}
+
+	values, err := client.Get(context.Background(), ref)
+	if err != nil {
+		logger.Warnf("failed to get metrics push secret reference: %s", err)
+		return
+	}
+
+	if values.Username != "" {
+		*username = values.Username
+	}
+	if values.Password != "" {
+		*password = values.Password
+	}
+	if token != nil && values.Token != "" {
+		*token = values.Token
+	}
+}
+
+// ScoreWeights resolver method
+func (r *Resolver) ScoreWeights() score.Weights {
+	weights := make(score.Weights, len(score.DefaultWeights))
+	for severity, weight := range score.DefaultWeights {
+		weights[severity] = weight
+	}
+	for severity, weight := range r.config.Score.Weights {
+		weights[severity] = weight
+	}
+
+	return weights
+}
+
+// MetricsPipelines resolver method, gauges with invalid relabel rules are skipped, each dedicated source gets its own gauge
+func (r *Resolver) MetricsPipelines() []metrics.Pipeline {
+	pipelines := make([]metrics.Pipeline, 0, len(r.config.Metrics.Gauges))
+
+gauges:
+	for _, g := range r.config.Metrics.Gauges {
+		rules := make([]metrics.RelabelRule, 0, len(g.Relabel))
+		for _, rl := range g.Relabel {
+			rule, err := metrics.NewRelabelRule(rl.Action, rl.SourceLabels, rl.Separator, rl.Regex, rl.TargetLabel, rl.Replacement)
+			if err != nil {
+				logger.Errorf("skip metric %s: %s", g.Name, err)
+				continue gauges
+			}
+
+			rules = append(rules, rule)
+		}
+
+		pipelines = append(pipelines, metrics.Pipeline{
+			Name:        r.config.Metrics.Prefix + g.Name,
+			Help:        g.Help,
+			Labels:      g.Labels,
+			Aggregation: g.Aggregation,
+			Relabel:     rules,
+		})
+	}
+
+	for _, s := range r.config.Metrics.Sources {
+		name := metrics.SourceMetricName(r.config.Metrics.Prefix, s.Source)
+		if s.Name != "" {
+			name = r.config.Metrics.Prefix + s.Name
+		}
+
+		help := s.Help
+		if help == "" {
+			help = "Gauge of the " + s.Source + " Results"
+		}
+
+		labels := s.Labels
+		if len(labels) == 0 {
+			labels = metrics.SourceLabels
+		}
+
+		pipelines = append(pipelines, metrics.Pipeline{
+			Name:    name,
+			Help:    help,
+			Labels:  labels,
+			Sources: []string{s.Source},
+		})
+	}
+
+	return pipelines
+}
+
+// dedicatedMetricSources with their own metric family, lowercased
+func (r *Resolver) dedicatedMetricSources() map[string]bool {
+	sources := make(map[string]bool, len(r.config.Metrics.Sources))
+	for _, s := range r.config.Metrics.Sources {
+		sources[strings.ToLower(s.Source)] = true
+	}
+
+	return sources
+}
+
+// Mapper resolver method
+func (r *Resolver) Mapper() report.Mapper {
+	if r.mapper != nil {
+		return r.mapper
+	}
+
+	severities, rules, err := PriorityRulesFromConfig(r.config.Priorities)
+	if err != nil {
+		logger.Errorf("invalid priority mapping: %s", err)
+	}
+	for _, s := range r.config.Severities {
+		if _, ok := severities[v1alpha2.PolicySeverity(s.Name)]; !ok && validPriority(s.Priority) {
+			severities[v1alpha2.PolicySeverity(s.Name)] = v1alpha2.NewPriority(s.Priority)
+		}
+	}
+
+	mapper := report.NewRuleMapper(r.config.PriorityMap, severities, rules)
+
+	r.mapper = mapper
+
+	return mapper
+}
+
+// Taxonomy resolver method
+func (r *Resolver) Taxonomy() *taxonomy.Taxonomy {
+	if r.taxonomy != nil {
+		return r.taxonomy
+	}
+
+	r.taxonomy = TaxonomyFromConfig(r.config.Taxonomy)
+
+	return r.taxonomy
+}
+
+// CustomFields resolver method, the global fields added to all target payloads
+func (r *Resolver) CustomFields() map[string]string {
+	if r.customFields != nil {
+		return r.customFields
+	}
+
+	r.customFields = CustomFieldsFromConfig(r.config.CustomFields, os.LookupEnv)
+
+	return r.customFields
+}
+
+// SecretClient resolver method
+func (r *Resolver) SecretClient() secrets.Client {
+	// secret references can not be resolved without a cluster in standalone mode
+	if r.k8sConfig == nil {
+		return nil
+	}
+
+	clientset, err := k8s.NewForConfig(r.k8sConfig)
+	if err != nil {
+		return nil
+	}
+
+	return secrets.NewClient(clientset.CoreV1().Secrets(r.config.Namespace))
+}
+
+func (r *Resolver) TargetFactory() *TargetFactory {
+	factory := &TargetFactory{
+		namespace:    r.config.Namespace,
+		cluster:      r.config.ClusterName,
+		customFields: r.CustomFields(),
+		secretClient: r.SecretClient(),
+		dryRun:       r.config.DryRunTargets,
+		http:         r.config.HTTPClient,
+	}
+
+	if r.config.Metrics.Enabled {
+		counter := metrics.RegisterTargetConnections()
+
+		factory.connections = func(target string, reused bool) {
+			counter.WithLabelValues(target, strconv.FormatBool(reused)).Inc()
+		}
+	}
+
+	if cache := r.resourceMetadata(); cache != nil {
+		factory.metadata = cache
+		factory.terminating = r.config.Terminating.Suppress
+	}
+
+	factory.orphaned = r.config.Orphaned.Enabled && r.config.Orphaned.ExcludeFromTargets
+
+	if len(r.config.SeverityFloor.Namespaces) > 0 {
+		factory.floor = report.NewSeverityFloor(r.config.SeverityFloor.Namespaces)
+	}
+
+	factory.age = r.ResultAge()
+	factory.errorRouting = r.config.ErrorRouting
+
+	if r.config.Exclusions.Enabled {
+		factory.exclusions = r.ExclusionStore().Validate
+	}
+
+	return factory
+}
+
+// ResultAge resolver method, nil without maximum age
+func (r *Resolver) ResultAge() *report.ResultAge {
+	if r.config.ResultAge.MaxAge <= 0 && len(r.config.ResultAge.Sources) == 0 {
+		return nil
+	}
+
+	return report.NewResultAge(r.config.ResultAge.MaxAge, r.config.ResultAge.Sources)
+}
+
+// SummaryCorrector resolver method, nil if the summaries are neither corrected nor counted by the drift metric
+func (r *Resolver) SummaryCorrector() (*report.SummaryCorrector, error) {
+	c := r.config.Summaries
+
+	drift := make([]func(v1alpha2.ReportInterface, v1alpha2.PolicyReportSummary), 0, 2)
+	if r.config.Metrics.Enabled {
+		counter := metrics.RegisterSummaryDrift()
+
+		drift = append(drift, func(rep v1alpha2.ReportInterface, _ v1alpha2.PolicyReportSummary) {
+			counter.WithLabelValues(rep.GetSource()).Inc()
+		})
+	}
+
+	if c.Patch {
+		client, err := r.DynamicClient()
+		if err != nil {
+			return nil, err
+		}
+
+		patcher := kubernetes.NewSummaryPatcher(client, 10*time.Second)
+
+		drift = append(drift, func(rep v1alpha2.ReportInterface, summary v1alpha2.PolicyReportSummary) {
+			go patcher.Patch(rep.GetNamespace(), rep.GetName(), summary)
+		})
+	}
+
+	if !c.Recompute && len(drift) == 0 {
+		return nil, nil
+	}
+
+	return report.NewSummaryCorrector(c.Recompute, drift...), nil
+}
+
+// ExclusionStore resolver method
+func (r *Resolver) ExclusionStore() *exclusion.Store {
+	if r.exclusionStore != nil {
+		return r.exclusionStore
+	}
+
+	r.exclusionStore = exclusion.NewStore(r.resourceMetadata())
+
+	return r.exclusionStore
+}
+
+// DynamicClient resolver method
+func (r *Resolver) DynamicClient() (dynamic.Interface, error) {
+	return dynamic.NewForConfig(r.k8sConfig)
+}
+
+// loadExclusions fills the ExclusionStore and the snoozes once, used by the email reports
+func (r *Resolver) loadExclusions(filter email.Filter) (email.Filter, error) {
+	validations := make([]report.ReportResultValidation, 0, 2)
+
+	if r.config.Exclusions.Enabled {
+		client, err := r.DynamicClient()
+		if err != nil {
+			return filter, err
+		}
+
+		store := r.ExclusionStore()
+		if err := exclusion.Load(context.Background(), client, store); err != nil {
+			return filter, err
+		}
+
+		validations = append(validations, store.Validate)
+	}
+
+	if r.config.Snooze.Enabled && r.config.Snooze.ExcludeFromSummaries {
+		registry, err := r.loadSnoozes()
+		if err != nil {
+			return filter, err
+		}
+
+		validations = append(validations, registry.Validate)
+	}
+
+	switch len(validations) {
+	case 0:
+		return filter, nil
+	case 1:
+		return filter.WithResultValidation(validations[0]), nil
+	}
+
+	return filter.WithResultValidation(func(rep v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) bool {
+		for _, validate := range validations {
+			if !validate(rep, result) {
+				return false
+			}
+		}
+
+		return true
+	}), nil
+}
+
+// loadSnoozes reads the active snoozes from the database, used by the email reports
+func (r *Resolver) loadSnoozes() (*snooze.Registry, error) {
+	if r.snoozes != nil {
+		return r.snoozes, nil
+	}
+
+	db, err := r.OpenDatabase()
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := r.PolicyReportStore(db)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := r.SnoozeRegistry(store)
+
+	return registry, registry.Load()
+}
+
+// MetadataCache resolver method
+func (r *Resolver) MetadataCache() (*kubernetes.MetadataCache, error) {
+	if r.metadataCache != nil {
+		return r.metadataCache, nil
+	}
+	if r.k8sConfig == nil {
+		return nil, errors.New("resource metadata requires a cluster")
+	}
+
+	client, err := metadata.NewForConfig(r.k8sConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := k8s.NewForConfig(r.k8sConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	r.metadataCache = kubernetes.NewMetadataCache(client, clientset.Discovery())
+
+	return r.metadataCache, nil
+}
+
+func (r *Resolver) resourceMetadata() report.ResourceMetadata {
+	if r.k8sConfig == nil {
+		return nil
+	}
+
+	cache, err := r.MetadataCache()
+	if err != nil {
+		logger.Errorf("failed to create metadata cache: %s", err)
+		return nil
+	}
+
+	return cache
+}
+
+// TargetClients resolver method
+func (r *Resolver) TargetClients() []target.Client {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	return r.resolveTargetClients()
+}
+
+// reloadable returns the configuration of the targets and the metrics filter, the caller holds r.mx
+func (r *Resolver) reloadable() *Config {
+	if r.targets != nil {
+		return r.targets
+	}
+
+	return r.config
+}
+
+// resolveTargetClients creates the target clients of the current configuration once, the caller holds r.mx
+func (r *Resolver) resolveTargetClients() []target.Client {
+	if r.targetsCreated {
+		return r.targetClients
+	}
+
+	config := r.reloadable()
+	factory := r.TargetFactory()
+
+	clients := make([]target.Client, 0)
+
+	clients = append(clients, factory.LokiClients(config.Loki)...)
+	clients = append(clients, factory.ElasticsearchClients(config.Elasticsearch)...)
+	clients = append(clients, factory.SlackClients(config.Slack)...)
+	clients = append(clients, factory.DiscordClients(config.Discord)...)
+	clients = append(clients, factory.TeamsClients(config.Teams)...)
+	clients = append(clients, factory.GoogleChatClients(config.GoogleChat)...)
+	clients = append(clients, factory.S3Clients(config.S3)...)
+	clients = append(clients, factory.KinesisClients(config.Kinesis)...)
+	clients = append(clients, factory.WebhookClients(config.Webhook)...)
+	clients = append(clients, factory.GrafanaClients(config.Grafana)...)
+	clients = append(clients, factory.GitHubClients(config.GitHub)...)
+	clients = append(clients, factory.GitLabClients(config.GitLab)...)
+	clients = append(clients, factory.TelegramClients(config.Telegram)...)
+	clients = append(clients, factory.SecurityHubClients(config.SecurityHub)...)
+	clients = append(clients, factory.SecurityCenterClients(config.SecurityCenter)...)
+	clients = append(clients, factory.DefenderClients(config.Defender)...)
+	clients = append(clients, factory.DefectDojoClients(config.DefectDojo)...)
+	clients = append(clients, factory.AlertmanagerClients(config.Alertmanager)...)
+	clients = append(clients, factory.ExecClients(config.Exec)...)
+
+	if ui := factory.UIClient(config.UI); ui != nil {
+		clients = append(clients, ui)
+	}
+
+	if events := r.KubernetesEventsClient(); events != nil {
+		clients = append(clients, events)
+	}
+
+	if r.config.Escalation.Enabled {
+		clients = escalation.Exclusive(clients, r.EscalationRules())
+	}
+
+	if r.config.ErrorRouting.Enabled {
+		for _, name := range r.config.ErrorRouting.Targets {
+			if !hasTarget(clients, name) {
+				logger.Warnf("errorRouting target %s is not configured", name)
+			}
+		}
+	}
+
+	r.targetClients = clients
+	r.targetsCreated = true
+
+	return r.targetClients
+}
+
+// KubernetesEventsClient resolver method, repeated events of the same resource are aggregated by the event correlator
+func (r *Resolver) KubernetesEventsClient() target.Client {
+	config := r.config.Events
+	if !config.Enabled {
+		return nil
+	}
+
+	if r.eventsClient != nil {
+		return r.eventsClient
+	}
+
+	var recorder record.EventRecorder
+	if r.config.DryRunTargets {
+		recorder = events.NewDryRunRecorder("Kubernetes Events")
+	} else {
+		clientset, err := k8s.NewForConfig(r.k8sConfig)
+		if err != nil {
+			logger.Errorf("failed to create kubernetes events client: %s", err)
+			return nil
+		}
+
+		broadcaster := record.NewBroadcaster()
+		broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: clientset.CoreV1().Events("")})
+
+		recorder = broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "policy-reporter"})
+	}
+
+	var limiter flowcontrol.RateLimiter
+	if config.QPS > 0 {
+		limiter = flowcontrol.NewTokenBucketRateLimiter(config.QPS, config.Burst)
+	}
+
+	logger.Info("Kubernetes Events configured")
+
+	r.eventsClient = events.NewClient(events.Options{
+		ClientOptions: target.ClientOptions{
+			Name:                  "Kubernetes Events",
+			SkipExistingOnStartup: config.SkipExisting,
+			Concurrency:           config.Concurrency,
+			NotificationTTL:       config.NotificationTTL,
+			ResultFilter:          r.TargetFactory().createResultFilter("Kubernetes Events", config.Filter, config.MinimumPriority, config.Sources),
+			ReportFilter:          createReprotFilter(config.Filter),
+			Sampler:               createSampler(config.Filter),
+			Grouper:               createGrouper(config.Filter),
+		},
+		Recorder:    recorder,
+		RateLimiter: limiter,
+		OnNamespace: config.OnNamespace,
+	})
+
+	return r.eventsClient
+}
+
+// FederationClient resolver method, returns nil without configured central URL
+func (r *Resolver) FederationClient() *federation.Client {
+	if r.federationClient != nil {
+		return r.federationClient
+	}
+
+	config := r.config.Federation.Central
+	if config.URL == "" && config.SecretRef == "" {
+		return nil
+	}
+
+	if config.SecretRef != "" {
+		if client := r.SecretClient(); client != nil {
+			values, err := client.Get(context.Background(), config.SecretRef)
+			if err != nil {
+				logger.Warnf("failed to get federation secret reference: %s", err)
+			}
+			if values.Host != "" {
+				config.URL = values.Host
+			}
+			if values.Token != "" {
+				config.Token = values.Token
+			}
+		}
+	}
+
+	if config.URL == "" {
+		logger.Warn("federation requires the URL of the central instance")
 		return nil
 	}
 
-	return secrets.NewClient(clientset.CoreV1().Secrets(r.config.Namespace))
+	r.federationClient = federation.NewClient(
+		config.URL,
+		config.Token,
+		r.config.Federation.ClusterName,
+		targethttp.NewClient(config.Certificate, config.SkipTLS),
+	)
+
+	return r.federationClient
 }
 
-func (r *Resolver) TargetFactory() *TargetFactory {
-	return &TargetFactory{
-		namespace:    r.config.Namespace,
-		secretClient: r.SecretClient(),
-	}
+// RegisterFederationListener pushes all PolicyReport events to the central instance
+func (r *Resolver) RegisterFederationListener(client *federation.Client) {
+	r.EventPublisher().RegisterListener(federation.Listener, client.Listen)
 }
 
-// TargetClients resolver method
-func (r *Resolver) TargetClients() []target.Client {
-	if r.targetsCreated {
-		return r.targetClients
+// FederationReceiver resolver method
+func (r *Resolver) FederationReceiver(store report.PolicyReportStore) *federation.Receiver {
+	return federation.NewReceiver(store)
+}
+
+// RegisterFederationMetrics exposes the summary of each federated cluster
+func (r *Resolver) RegisterFederationMetrics(finder federation.SummaryFinder) {
+	prometheus.MustRegister(federation.NewCollector(finder, r.config.Federation.ClusterName))
+}
+
+// TargetRegistry resolver method
+func (r *Resolver) TargetRegistry() *target.Registry {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if r.targetRegistry != nil {
+		return r.targetRegistry
 	}
 
-	factory := r.TargetFactory()
+	r.targetRegistry = target.NewRegistry(r.resolveTargetClients())
 
-	clients := make([]target.Client, 0)
+	return r.targetRegistry
+}
 
-	clients = append(clients, factory.LokiClients(r.config.Loki)...)
-	clients = append(clients, factory.ElasticsearchClients(r.config.Elasticsearch)...)
-	clients = append(clients, factory.SlackClients(r.config.Slack)...)
-	clients = append(clients, factory.DiscordClients(r.config.Discord)...)
-	clients = append(clients, factory.TeamsClients(r.config.Teams)...)
-	clients = append(clients, factory.S3Clients(r.config.S3)...)
-	clients = append(clients, factory.KinesisClients(r.config.Kinesis)...)
-	clients = append(clients, factory.WebhookClients(r.config.Webhook)...)
+// Reload replaces the target clients and the metrics result filter with the given configuration,
+// results already queued for the previous targets are still delivered. Other options require a restart
+func (r *Resolver) Reload(c *Config) {
+	r.mx.Lock()
+
+	config := *r.reloadable()
+	config.Loki = c.Loki
+	config.Elasticsearch = c.Elasticsearch
+	config.Slack = c.Slack
+	config.Discord = c.Discord
+	config.Teams = c.Teams
+	config.GoogleChat = c.GoogleChat
+	config.S3 = c.S3
+	config.Kinesis = c.Kinesis
+	config.UI = c.UI
+	config.Webhook = c.Webhook
+	config.Grafana = c.Grafana
+	config.GitHub = c.GitHub
+	config.GitLab = c.GitLab
+	config.Telegram = c.Telegram
+	config.SecurityHub = c.SecurityHub
+	config.SecurityCenter = c.SecurityCenter
+	config.Defender = c.Defender
+	config.DefectDojo = c.DefectDojo
+	config.Alertmanager = c.Alertmanager
+	config.Exec = c.Exec
+	config.Metrics.Filter = c.Metrics.Filter
+
+	r.targets = &config
+	r.targetsCreated = false
+
+	clients := r.resolveTargetClients()
+	registry, dispatcher := r.targetRegistry, r.dispatcher
+
+	r.mx.Unlock()
+
+	if registry == nil {
+		registry = r.TargetRegistry()
+	} else if previous := registry.Swap(clients); len(previous) == 0 && len(clients) > 0 {
+		logger.Warn("targets added to a configuration without targets on startup require a restart")
+	}
 
-	if ui := factory.UIClient(r.config.UI); ui != nil {
-		clients = append(clients, ui)
+	if dispatcher != nil {
+		dispatcher.Update(clients)
 	}
 
-	r.targetClients = clients
-	r.targetsCreated = true
+	if r.metricsFilter != nil {
+		r.metricsFilter.Store(r.metricsResultFilter())
+	}
 
-	return r.targetClients
+	logger.Infof("configuration reloaded with %d targets", len(clients))
 }
 
 func (r *Resolver) HasTargets() bool {
@@ -254,9 +2116,14 @@ func (r *Resolver) SummaryGenerator() (*summary.Generator, error) {
 		return nil, err
 	}
 
+	filter, err := r.loadExclusions(r.EmailReportFilter(r.config.EmailReports.Summary.Filter))
+	if err != nil {
+		return nil, err
+	}
+
 	return summary.NewGenerator(
 		client,
-		EmailReportFilterFromConfig(r.config.EmailReports.Summary.Filter),
+		filter,
 		!r.config.EmailReports.Summary.Filter.DisableClusterReports,
 	), nil
 }
@@ -265,6 +2132,7 @@ func (r *Resolver) SummaryReporter() *summary.Reporter {
 	return summary.NewReporter(
 		r.config.EmailReports.Templates.Dir,
 		r.config.EmailReports.ClusterName,
+		r.Taxonomy(),
 	)
 }
 
@@ -274,17 +2142,33 @@ func (r *Resolver) ViolationsGenerator() (*violations.Generator, error) {
 		return nil, err
 	}
 
-	return violations.NewGenerator(
+	filter, err := r.loadExclusions(r.EmailReportFilter(r.config.EmailReports.Violations.Filter))
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := r.UILinks()
+	if err != nil {
+		return nil, err
+	}
+
+	generator := violations.NewGenerator(
 		client,
-		EmailReportFilterFromConfig(r.config.EmailReports.Violations.Filter),
+		filter,
 		!r.config.EmailReports.Violations.Filter.DisableClusterReports,
-	), nil
+	)
+	if links != nil {
+		generator.EnableLinks(links)
+	}
+
+	return generator, nil
 }
 
 func (r *Resolver) ViolationsReporter() *violations.Reporter {
 	return violations.NewReporter(
 		r.config.EmailReports.Templates.Dir,
 		r.config.EmailReports.ClusterName,
+		r.Taxonomy(),
 	)
 }
 
@@ -293,7 +2177,7 @@ func (r *Resolver) SMTPServer() *mail.SMTPServer {
 	server.Host = r.config.EmailReports.SMTP.Host
 	server.Port = r.config.EmailReports.SMTP.Port
 	server.Username = r.config.EmailReports.SMTP.Username
-	server.Password = r.config.EmailReports.SMTP.Password
+	server.Password = secret.New(r.config.EmailReports.SMTP.Password).Value()
 	server.ConnectTimeout = 10 * time.Second
 	server.SendTimeout = 10 * time.Second
 	server.Encryption = email.EncryptionFromString(r.config.EmailReports.SMTP.Encryption)
@@ -305,11 +2189,328 @@ func (r *Resolver) EmailClient() *email.Client {
 	return email.NewClient(r.config.EmailReports.SMTP.From, r.SMTPServer())
 }
 
+// SubscriptionManager resolver method
+func (r *Resolver) SubscriptionManager(store subscription.Store) *subscription.Manager {
+	options := subscription.Options{
+		URL:         r.config.EmailReports.Subscriptions.URL,
+		Domains:     r.config.EmailReports.Subscriptions.Domains,
+		ClusterName: r.config.EmailReports.ClusterName,
+	}
+
+	if r.config.EmailReports.SMTP.Host != "" {
+		options.Mailer = r.EmailClient()
+	}
+
+	return subscription.NewManager(store, options)
+}
+
+// EscalationRules maps the configured rules, rules with an invalid expression are skipped
+func (r *Resolver) EscalationRules() []escalation.Rule {
+	rules := make([]escalation.Rule, 0, len(r.config.Escalation.Rules))
+
+	for _, rule := range r.config.Escalation.Rules {
+		var expr *expression.Expression
+		if rule.Expression != "" {
+			compiled, err := expression.Compile(rule.Expression)
+			if err != nil {
+				logger.Errorf("escalation %s skipped: %s", rule.Name, err)
+				continue
+			}
+
+			expr = compiled
+		}
+
+		rules = append(rules, escalation.Rule{
+			Name:            rule.Name,
+			MinimumSeverity: rule.MinimumSeverity,
+			Status:          rule.Status,
+			Expression:      expr,
+			After:           rule.After,
+			Targets:         rule.Targets,
+			Email:           rule.Email,
+		})
+	}
+
+	return rules
+}
+
+// AlertRules maps the configured rules, rules with an invalid expression are skipped
+func (r *Resolver) AlertRules() []alerting.Rule {
+	rules := make([]alerting.Rule, 0, len(r.config.Alerting.Rules))
+
+	for _, rule := range r.config.Alerting.Rules {
+		var expr *expression.Expression
+		if rule.Expression != "" {
+			compiled, err := expression.Compile(rule.Expression)
+			if err != nil {
+				logger.Errorf("alert %s skipped: %s", rule.Name, err)
+				continue
+			}
+
+			expr = compiled
+		}
+
+		rules = append(rules, alerting.Rule{
+			Name:            rule.Name,
+			Namespaces:      rule.Namespaces,
+			Sources:         rule.Sources,
+			MinimumSeverity: rule.MinimumSeverity,
+			Status:          rule.Status,
+			Expression:      expr,
+			Threshold:       rule.Threshold,
+			Window:          rule.Window,
+			PerNamespace:    rule.PerNamespace,
+			Targets:         rule.Targets,
+			Email:           rule.Email,
+		})
+	}
+
+	return rules
+}
+
+// StoreLimits resolver method
+func (r *Resolver) StoreLimits() sqlite3.Limits {
+	config := r.config.Pruning
+
+	limits := sqlite3.Limits{
+		MaxAge:  config.MaxAge,
+		MaxRows: config.MaxRows,
+	}
+
+	if config.MaxSize != "" {
+		size, err := resource.ParseQuantity(config.MaxSize)
+		if err != nil {
+			logger.Errorf("invalid pruning.maxSize, size limit disabled: %s", err)
+		} else {
+			limits.MaxSize = size.Value()
+		}
+	}
+
+	return limits
+}
+
+// StorePruningObserver reports the pruned reports and the store size as metrics
+func (r *Resolver) StorePruningObserver() func(sqlite3.Pruned, sqlite3.StoreStats) {
+	m := metrics.RegisterStoreMetrics()
+
+	return func(pruned sqlite3.Pruned, stats sqlite3.StoreStats) {
+		m.Pruned.WithLabelValues("age").Add(float64(pruned.Age))
+		m.Pruned.WithLabelValues("rows").Add(float64(pruned.Rows))
+		m.Pruned.WithLabelValues("size").Add(float64(pruned.Size))
+
+		for table, count := range stats.Rows {
+			m.Rows.WithLabelValues(table).Set(float64(count))
+		}
+
+		m.Size.Set(float64(stats.Size))
+	}
+}
+
+// Escalator resolver method, with sharding or leader election only the owning instance escalates a result
+func (r *Resolver) Escalator(finder escalation.Finder) (*escalation.Escalator, error) {
+	options := escalation.Options{
+		Rules:       r.EscalationRules(),
+		Clients:     r.TargetRegistry().Clients,
+		Mapper:      r.Mapper(),
+		ClusterName: r.config.ClusterName,
+	}
+
+	if r.config.EmailReports.SMTP.Host != "" {
+		options.Mailer = r.EmailClient()
+	}
+
+	if r.config.Maintenance.Enabled {
+		options.Paused = r.MaintenanceSchedule().Active
+	}
+
+	owns, err := r.notifiedNamespaces()
+	if err != nil {
+		return nil, err
+	}
+
+	options.Owns = owns
+
+	if r.config.Snooze.Enabled {
+		options.Snoozed = r.snoozed
+	}
+
+	return escalation.NewEscalator(finder, options), nil
+}
+
+// snoozed reports if the result is snoozed, the registry is created with the store
+func (r *Resolver) snoozed(resultID string) bool {
+	return r.snoozes != nil && r.snoozes.Snoozed(resultID)
+}
+
+// notifiedNamespaces returns the namespaces this instance notifies with sharding or leader election, nil for all namespaces
+func (r *Resolver) notifiedNamespaces() (func(namespace string) bool, error) {
+	if !r.HasTargets() {
+		return nil, nil
+	}
+
+	return r.ownedNamespaces()
+}
+
+// ownedNamespaces returns the namespaces this instance is responsible for with sharding or leader election, nil for all namespaces.
+// The leader owns all namespaces, the shard of the empty namespace owns the cluster scoped resources
+func (r *Resolver) ownedNamespaces() (func(namespace string) bool, error) {
+	if r.config.Sharding.Enabled {
+		shards, err := r.ShardingClient()
+		if err != nil {
+			return nil, err
+		}
+
+		return shards.Owns, nil
+	} else if r.config.LeaderElection.Enabled {
+		elector, err := r.LeaderElectionClient()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(string) bool {
+			_, leader := elector.Leader()
+			return leader
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// Ager resolver method, raises the severity of long unresolved results.
+// With sharding or leader election only the owning instance sends the raised results
+func (r *Resolver) Ager() (*aging.Ager, error) {
+	if r.ager != nil {
+		return r.ager, nil
+	}
+
+	rules := make([]aging.Rule, 0, len(r.config.SeverityAging.Rules))
+	for i, rule := range r.config.SeverityAging.Rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("%s-to-%s-after-%s", rule.Severity, rule.RaiseTo, rule.After)
+		}
+
+		if rule.After <= 0 {
+			logger.Errorf("severity aging rule %d skipped: after is required", i)
+			continue
+		}
+
+		rules = append(rules, aging.Rule{
+			Name:     name,
+			Severity: v1alpha2.PolicySeverity(rule.Severity),
+			RaiseTo:  v1alpha2.PolicySeverity(rule.RaiseTo),
+			After:    rule.After,
+			Sources:  rule.Sources,
+		})
+	}
+
+	options := aging.Options{
+		Rules:     rules,
+		Publisher: r.EventPublisher(),
+		Targets:   r.config.SeverityAging.Targets,
+		Mapper:    r.Mapper(),
+	}
+
+	if r.config.SeverityAging.Notify {
+		// the targets are created on the first evaluation, the ager is created with the report mappers
+		options.Clients = func() []target.Client { return r.TargetRegistry().Clients() }
+
+		owns, err := r.notifiedNamespaces()
+		if err != nil {
+			return nil, err
+		}
+
+		options.Owns = owns
+	}
+
+	if r.config.Snooze.Enabled {
+		options.Snoozed = r.snoozed
+	}
+
+	r.ager = aging.NewAger(options)
+
+	return r.ager, nil
+}
+
+// RegisterAgingListener resolver method
+func (r *Resolver) RegisterAgingListener() error {
+	ager, err := r.Ager()
+	if err != nil {
+		return err
+	}
+
+	r.EventPublisher().RegisterListener(aging.Listener, ager.Listen)
+
+	return nil
+}
+
+// RegisterAlertingListener resolver method, counts the new results of the alert rules.
+// With sharding or leader election only the owning instance alerts
+func (r *Resolver) RegisterAlertingListener() error {
+	options := alerting.Options{
+		Rules:       r.AlertRules(),
+		Clients:     r.TargetRegistry().Clients,
+		Mapper:      r.Mapper(),
+		ClusterName: r.config.ClusterName,
+	}
+
+	if r.config.EmailReports.SMTP.Host != "" {
+		options.Mailer = r.EmailClient()
+	}
+
+	if r.config.Maintenance.Enabled {
+		options.Paused = r.MaintenanceSchedule().Active
+	}
+
+	if r.config.Sharding.Enabled && r.HasTargets() {
+		shards, err := r.ShardingClient()
+		if err != nil {
+			return err
+		}
+
+		options.Owns = shards.Owns
+	} else if r.config.LeaderElection.Enabled && r.HasTargets() {
+		elector, err := r.LeaderElectionClient()
+		if err != nil {
+			return err
+		}
+
+		options.Owns = func(string) bool {
+			_, leader := elector.Leader()
+			return leader
+		}
+	}
+
+	newResultListener := listener.NewResultListener(true, cache.NewInMermoryCache(), r.StartUp())
+	newResultListener.RegisterListener(alerting.NewAlerter(options).Observe)
+
+	r.EventPublisher().RegisterListener(alerting.Listener, newResultListener.Listen)
+
+	return nil
+}
+
 func (r *Resolver) PolicyReportClient() (report.PolicyReportClient, error) {
 	if r.policyReportClient != nil {
 		return r.policyReportClient, nil
 	}
 
+	if r.config.IsStandalone() {
+		mappers, err := r.reportMappers()
+		if err != nil {
+			return nil, err
+		}
+
+		r.policyReportClient = standalone.NewPolicyReportClient(
+			r.config.Standalone.ReportsDir,
+			r.config.Standalone.Interval,
+			r.ReportFilter(),
+			r.EventPublisher(),
+			mappers,
+		)
+
+		return r.policyReportClient, nil
+	}
+
 	client, err := r.CRDMetadataClient()
 	if err != nil {
 		return nil, err
@@ -320,7 +2521,7 @@ func (r *Resolver) PolicyReportClient() (report.PolicyReportClient, error) {
 		return nil, err
 	}
 
-	r.policyReportClient = kubernetes.NewPolicyReportClient(client, r.ReportFilter(), queue)
+	r.policyReportClient = kubernetes.NewPolicyReportClient(client, r.ReportFilter(), queue, r.WatchOptions())
 
 	return r.policyReportClient, nil
 }
@@ -332,6 +2533,26 @@ func (r *Resolver) ReportFilter() *report.Filter {
 	)
 }
 
+// WatchOptions resolver method
+func (r *Resolver) WatchOptions() kubernetes.WatchOptions {
+	var exclude []string
+	if !r.config.Watch.InterimReports {
+		exclude = r.config.Watch.InterimLabels
+		if len(exclude) == 0 {
+			exclude = kyverno.InterimLabels
+		}
+	}
+
+	return kubernetes.WatchOptions{
+		ResyncPeriod:      r.config.Watch.ResyncPeriod,
+		LabelSelector:     r.config.Watch.LabelSelector,
+		ExcludeLabels:     exclude,
+		Namespaces:        r.config.Watch.Namespaces.Include,
+		ExcludeNamespaces: r.config.Watch.Namespaces.Exclude,
+		PersistedReports:  r.PersistedReports(),
+	}
+}
+
 // ResultCache resolver method
 func (r *Resolver) ResultCache() cache.Cache {
 	if r.resultCache != nil {
@@ -339,16 +2560,15 @@ func (r *Resolver) ResultCache() cache.Cache {
 	}
 
 	if r.config.Redis.Enabled {
+		logger.Info("use redis as shared result cache")
+
 		r.resultCache = cache.NewRedisCache(
 			r.config.Redis.Prefix,
-			goredis.NewClient(&goredis.Options{
-				Addr:     r.config.Redis.Address,
-				Username: r.config.Redis.Username,
-				Password: r.config.Redis.Password,
-				DB:       r.config.Redis.Database,
-			}),
-			2*time.Hour,
+			r.RedisClient(),
+			r.config.Redis.TTL,
 		)
+	} else if r.config.Reconciliation.Enabled {
+		r.resultCache = r.persistentResultCache()
 	} else {
 		r.resultCache = cache.NewInMermoryCache()
 	}
@@ -356,16 +2576,246 @@ func (r *Resolver) ResultCache() cache.Cache {
 	return r.resultCache
 }
 
+func (r *Resolver) persistentResultCache() cache.Cache {
+	db, err := sql.Open("sqlite3", r.config.Reconciliation.DBFile)
+	if err != nil {
+		logger.Errorf("failed to open reconciliation database, reconciliation disabled: %s", err)
+		return cache.NewInMermoryCache()
+	}
+
+	c, err := cache.NewSQLiteCache(db)
+	if err != nil {
+		db.Close()
+		logger.Errorf("failed to create persistent result cache, reconciliation disabled: %s", err)
+		return cache.NewInMermoryCache()
+	}
+
+	logger.Info("use sqlite as persistent result cache")
+	r.databases = append(r.databases, db)
+	r.cacheRestored = len(c.(cache.ReportKeys).ReportKeys()) > 0
+
+	return c
+}
+
+// PersistedReports returns the keys of the reports processed before a restart, nil if the result cache is not persistent
+func (r *Resolver) PersistedReports() func() []string {
+	if !r.config.Reconciliation.Enabled {
+		return nil
+	}
+
+	if keys, ok := r.ResultCache().(cache.ReportKeys); ok {
+		return keys.ReportKeys
+	}
+
+	return nil
+}
+
+// RedisClient resolver method
+func (r *Resolver) RedisClient() *goredis.Client {
+	if r.redisClient != nil {
+		return r.redisClient
+	}
+
+	config := r.config.Redis
+
+	if config.SecretRef != "" {
+		if client := r.SecretClient(); client != nil {
+			values, err := client.Get(context.Background(), config.SecretRef)
+			if err != nil {
+				logger.Warnf("failed to get redis secret reference: %s", err)
+			}
+			if values.Username != "" {
+				config.Username = values.Username
+			}
+			if values.Password != "" {
+				config.Password = values.Password
+			}
+		}
+	}
+
+	options := &goredis.Options{
+		Addr:     config.Address,
+		Username: config.Username,
+		Password: secret.New(config.Password).Value(),
+		DB:       config.Database,
+	}
+
+	if config.TLS.Enabled {
+		options.TLSConfig = helper.NewTLSConfig(config.TLS.Certificate, config.TLS.SkipTLS)
+		options.TLSConfig.ServerName = config.TLS.ServerName
+	}
+
+	r.redisClient = goredis.NewClient(options)
+
+	return r.redisClient
+}
+
+// Deduplicator resolver method
+func (r *Resolver) Deduplicator() (cache.Deduplicator, error) {
+	if r.deduplicator != nil {
+		return r.deduplicator, nil
+	}
+
+	ttl := r.config.Deduplication.TTL
+	if ttl <= 0 {
+		ttl = 2 * time.Hour
+	}
+
+	switch r.config.Deduplication.Type {
+	case "redis":
+		r.deduplicator = cache.NewRedisDeduplicator(r.config.Redis.Prefix, r.RedisClient(), ttl)
+	case "sqlite":
+		db, err := sql.Open("sqlite3", r.config.Deduplication.DBFile)
+		if err != nil {
+			return nil, err
+		}
+
+		dedupe, err := cache.NewSQLiteDeduplicator(db, ttl)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+
+		r.deduplicator = dedupe
+		r.databases = append(r.databases, db)
+	default:
+		r.deduplicator = cache.NewInMemoryDeduplicator(ttl)
+	}
+
+	return r.deduplicator, nil
+}
+
+// NotificationCache resolver method, returns nil if disabled.
+// The notifications are kept in the Deduplicator, they survive restarts with the sqlite or redis deduplication type.
+// Has to be resolved before the ResultDispatcher to skip already sent results
+func (r *Resolver) NotificationCache() (*cache.Notified, error) {
+	if !r.config.Notifications.Enabled {
+		return nil, nil
+	}
+	if r.notified != nil {
+		return r.notified, nil
+	}
+
+	dedupe, err := r.Deduplicator()
+	if err != nil {
+		return nil, err
+	}
+
+	registry := r.TargetRegistry()
+	ttl := r.config.Notifications.TTL
+
+	r.notified = cache.NewNotified(func(name string) time.Duration {
+		for _, client := range registry.Clients() {
+			if client.Name() == name && client.NotificationTTL() != 0 {
+				return client.NotificationTTL()
+			}
+		}
+
+		return ttl
+	}, dedupe)
+
+	return r.notified, nil
+}
+
+func (r *Resolver) trackDatabase(db *sql.DB, err error) (*sql.DB, error) {
+	if err == nil {
+		r.databases = append(r.databases, db)
+	}
+
+	return db, err
+}
+
+// Close flushes the pending results of batching targets, flushes and closes the SQLite databases opened by the resolver
+func (r *Resolver) Close() {
+	r.mx.RLock()
+	clients := r.targetClients
+	r.mx.RUnlock()
+
+	for _, client := range clients {
+		if flusher, ok := client.(target.FlushClient); ok {
+			flusher.Flush()
+		}
+	}
+
+	for _, db := range r.databases {
+		if err := sqlite3.CloseDatabase(db); err != nil {
+			logger.Errorf("failed to close database: %s", err)
+		}
+	}
+
+	r.databases = nil
+}
+
+// StartUp resolver method, results of reports created before are handled as pre existing
+func (r *Resolver) StartUp() time.Time {
+	if !r.startUp.IsZero() {
+		return r.startUp
+	}
+
+	r.startUp = time.Now()
+
+	if r.config.Shutdown.Checkpoint != "" {
+		if t, ok := checkpoint.Read(r.config.Shutdown.Checkpoint); ok {
+			logger.Infof("restored shutdown checkpoint from %s", t.Format(time.RFC3339))
+			r.startUp = t
+		}
+	}
+
+	return r.startUp
+}
+
+// WriteCheckpoint resolver method
+func (r *Resolver) WriteCheckpoint() {
+	if r.config.Shutdown.Checkpoint == "" {
+		return
+	}
+
+	if err := checkpoint.Write(r.config.Shutdown.Checkpoint, time.Now()); err != nil {
+		logger.Errorf("failed to write shutdown checkpoint: %s", err)
+	}
+}
+
 // NewResolver constructor function
 func NewResolver(config *Config, k8sConfig *rest.Config) Resolver {
 	return Resolver{
 		config:    config,
+		mx:        new(sync.RWMutex),
 		k8sConfig: k8sConfig,
 	}
 }
 
 func EmailReportFilterFromConfig(config EmailReportFilter) email.Filter {
-	return email.NewFilter(ToRuleSet(config.Namespaces), ToRuleSet(config.Sources))
+	return email.NewFilter(ToRuleSet(config.Namespaces), ToRuleSet(config.Sources), ToRuleSet(config.Severities))
+}
+
+// EmailReportFilter resolver method, adds the team filter if ownership is enabled
+func (r *Resolver) EmailReportFilter(config EmailReportFilter) email.Filter {
+	filter := EmailReportFilterFromConfig(config)
+	if !r.config.Ownership.Enabled {
+		return filter
+	}
+
+	return filter.WithTeams(ToRuleSet(config.Teams), r.TeamResolver().Team)
+}
+
+// EmailReportOptionsFromConfig maps the template and attachment configuration, channels inherit unset values from the parent report
+func EmailReportOptionsFromConfig(config EmailReport, parent *EmailReport) email.ReportOptions {
+	options := email.ReportOptions{
+		Template:    config.Template,
+		Attachments: config.Attachments,
+	}
+
+	if parent == nil {
+		return options
+	}
+	if options.Template == "" {
+		options.Template = parent.Template
+	}
+	if len(options.Attachments) == 0 {
+		options.Attachments = parent.Attachments
+	}
+
+	return options
 }
 
 func ToRuleSet(filter ValueFilter) validate.RuleSets {
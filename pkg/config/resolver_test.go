@@ -1,12 +1,26 @@
 package config_test
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/lib/pq"
 	"k8s.io/client-go/rest"
 
+	"github.com/kyverno/policy-reporter/pkg/alerting"
+	"github.com/kyverno/policy-reporter/pkg/api/auth"
+	"github.com/kyverno/policy-reporter/pkg/cache"
 	"github.com/kyverno/policy-reporter/pkg/config"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/kyverno"
+	"github.com/kyverno/policy-reporter/pkg/listener"
 	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/rpc"
 )
 
 var testConfig = &config.Config{
@@ -49,6 +63,15 @@ var testConfig = &config.Config{
 			Webhook: "http://localhost:9200",
 		}},
 	},
+	GoogleChat: config.GoogleChat{
+		Webhook:         "http://hook.googlechat:80",
+		SkipExisting:    true,
+		MinimumPriority: "debug",
+		CustomFields:    map[string]string{"field": "value"},
+		Channels: []config.GoogleChat{{
+			Webhook: "http://localhost:9200",
+		}},
+	},
 	Teams: config.Teams{
 		Webhook:         "http://hook.teams:80",
 		SkipTLS:         true,
@@ -80,6 +103,16 @@ var testConfig = &config.Config{
 			},
 		}},
 	},
+	Grafana: config.Grafana{
+		Host:            "http://localhost:3000",
+		Token:           "token",
+		DashboardUID:    "compliance",
+		SkipExisting:    true,
+		MinimumPriority: "critical",
+		Channels: []config.Grafana{{
+			DashboardUID: "security",
+		}},
+	},
 	S3: config.S3{
 		AccessKeyID:     "AccessKey",
 		SecretAccessKey: "SecretAccessKey",
@@ -122,8 +155,109 @@ var testConfig = &config.Config{
 func Test_ResolveTargets(t *testing.T) {
 	resolver := config.NewResolver(testConfig, &rest.Config{})
 
-	if count := len(resolver.TargetClients()); count != 17 {
-		t.Errorf("Expected 17 Clients, got %d", count)
+	if count := len(resolver.TargetClients()); count != 21 {
+		t.Errorf("Expected 21 Clients, got %d", count)
+	}
+}
+
+func Test_ReloadTargets(t *testing.T) {
+	resolver := config.NewResolver(&config.Config{Slack: config.Slack{Webhook: "http://slack"}}, &rest.Config{})
+	registry := resolver.TargetRegistry()
+	resolver.RegisterSendResultListener()
+
+	resolver.Reload(&config.Config{
+		Slack:   config.Slack{Webhook: "http://slack"},
+		Webhook: config.Webhook{Host: "http://webhook"},
+	})
+
+	if count := len(registry.Clients()); count != 2 {
+		t.Errorf("Expected 2 Clients after reload, got %d", count)
+	}
+	if count := len(resolver.TargetClients()); count != 2 {
+		t.Errorf("Expected 2 resolved Clients after reload, got %d", count)
+	}
+}
+
+func Test_ReloadTargetsConcurrently(t *testing.T) {
+	resolver := config.NewResolver(&config.Config{Slack: config.Slack{Webhook: "http://slack"}}, &rest.Config{})
+
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 20; i++ {
+			resolver.Reload(&config.Config{Webhook: config.Webhook{Host: "http://webhook"}})
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 20; i++ {
+			resolver.RegisterSendResultListener()
+			resolver.TargetClients()
+			resolver.Close()
+		}
+	}()
+
+	wg.Wait()
+
+	if count := len(resolver.TargetClients()); count != 1 {
+		t.Errorf("Expected 1 resolved Client after reload, got %d", count)
+	}
+}
+
+func Test_ResolveErrorRouting(t *testing.T) {
+	errorResult := fixtures.FailResult
+	errorResult.Result = v1alpha2.StatusError
+
+	for _, exclusive := range []bool{true, false} {
+		resolver := config.NewResolver(&config.Config{
+			Slack:        config.Slack{Webhook: "http://slack"},
+			Webhook:      config.Webhook{Host: "http://webhook"},
+			ErrorRouting: config.ErrorRouting{Enabled: true, Targets: []string{"Webhook"}, Exclusive: exclusive},
+		}, &rest.Config{})
+
+		for _, client := range resolver.TargetClients() {
+			ops := client.Name() == "Webhook"
+
+			if client.Validate(fixtures.DefaultPolicyReport, fixtures.FailResult) == ops {
+				t.Errorf("%s: unexpected validation of the fail result with exclusive %v", client.Name(), exclusive)
+			}
+			if valid := client.Validate(fixtures.DefaultPolicyReport, errorResult); valid != (ops || !exclusive) {
+				t.Errorf("%s: unexpected validation of the error result with exclusive %v", client.Name(), exclusive)
+			}
+		}
+	}
+}
+
+func Test_ResolveDryRunTargets(t *testing.T) {
+	var received int
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		received++
+	}))
+	defer receiver.Close()
+
+	resolver := config.NewResolver(&config.Config{
+		DryRunTargets: true,
+		Webhook:       config.Webhook{Host: receiver.URL},
+		Events:        config.KubernetesEvents{Enabled: true},
+	}, &rest.Config{})
+
+	clients := resolver.TargetClients()
+	if len(clients) != 2 {
+		t.Fatalf("Expected 2 Clients, got %d", len(clients))
+	}
+
+	for _, client := range clients {
+		client.Send(fixtures.FailResult)
+	}
+
+	if received != 0 {
+		t.Errorf("Expected no requests in dry run mode, got %d", received)
 	}
 }
 
@@ -171,7 +305,7 @@ func Test_ResolveSkipExistingOnStartup(t *testing.T) {
 }
 
 func Test_ResolvePolicyClient(t *testing.T) {
-	resolver := config.NewResolver(&config.Config{DBFile: "test.db"}, &rest.Config{})
+	resolver := config.NewResolver(&config.Config{DBFile: filepath.Join(t.TempDir(), "test.db")}, &rest.Config{})
 
 	client1, err := resolver.PolicyReportClient()
 	if err != nil {
@@ -185,7 +319,7 @@ func Test_ResolvePolicyClient(t *testing.T) {
 }
 
 func Test_ResolveLeaderElectionClient(t *testing.T) {
-	resolver := config.NewResolver(&config.Config{DBFile: "test.db"}, &rest.Config{})
+	resolver := config.NewResolver(&config.Config{DBFile: filepath.Join(t.TempDir(), "test.db")}, &rest.Config{})
 
 	client1, err := resolver.LeaderElectionClient()
 	if err != nil {
@@ -198,8 +332,36 @@ func Test_ResolveLeaderElectionClient(t *testing.T) {
 	}
 }
 
+func Test_ResolveShardingClient(t *testing.T) {
+	resolver := config.NewResolver(&config.Config{DBFile: filepath.Join(t.TempDir(), "test.db")}, &rest.Config{})
+
+	client1, err := resolver.ShardingClient()
+	if err != nil {
+		t.Errorf("Unexpected Error: %s", err)
+	}
+
+	client2, _ := resolver.ShardingClient()
+	if client1 != client2 {
+		t.Error("A second call resolver.ShardingClient() should return the cached first client")
+	}
+}
+
+func Test_ResolveExternalDatabase(t *testing.T) {
+	resolver := config.NewResolver(&config.Config{Database: config.Database{Type: "postgres", Host: "localhost:5432", Database: "reporter"}}, &rest.Config{})
+
+	db, err := resolver.Database()
+	if err != nil {
+		t.Fatalf("Unexpected Error: %s", err)
+	}
+	defer db.Close()
+
+	if _, ok := db.Driver().(*pq.Driver); !ok {
+		t.Error("Expected a database driver for postgres")
+	}
+}
+
 func Test_ResolvePolicyStore(t *testing.T) {
-	resolver := config.NewResolver(&config.Config{DBFile: "test.db"}, &rest.Config{})
+	resolver := config.NewResolver(&config.Config{DBFile: filepath.Join(t.TempDir(), "test.db")}, &rest.Config{})
 	db, _ := resolver.Database()
 	defer db.Close()
 
@@ -214,6 +376,31 @@ func Test_ResolvePolicyStore(t *testing.T) {
 	}
 }
 
+func Test_ResolvePersistedPolicyStore(t *testing.T) {
+	c := &config.Config{DBFile: filepath.Join(t.TempDir(), "test.db"), Tombstones: config.Tombstones{Enabled: true}}
+
+	resolver := config.NewResolver(c, &rest.Config{})
+	db, _ := resolver.Database()
+	store, err := resolver.PolicyReportStore(db)
+	if err != nil {
+		t.Fatalf("Unexpected Error: %s", err)
+	}
+	store.Add(fixtures.DefaultPolicyReport)
+	db.Close()
+
+	restarted := config.NewResolver(c, &rest.Config{})
+	db, _ = restarted.Database()
+	defer db.Close()
+
+	store, err = restarted.PolicyReportStore(db)
+	if err != nil {
+		t.Fatalf("Unexpected Error: %s", err)
+	}
+	if _, ok := store.Get(fixtures.DefaultPolicyReport.GetID()); ok {
+		t.Error("expected the PolicyReports of a kept database to be cleaned up on startup")
+	}
+}
+
 func Test_ResolveAPIServer(t *testing.T) {
 	resolver := config.NewResolver(&config.Config{}, &rest.Config{})
 
@@ -223,6 +410,131 @@ func Test_ResolveAPIServer(t *testing.T) {
 	}
 }
 
+func Test_ResolveAPIAuthenticator(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		resolver := config.NewResolver(&config.Config{}, &rest.Config{})
+
+		if resolver.APIAuthenticator() != nil {
+			t.Error("Error: Should return no Authenticator if disabled")
+		}
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		resolver := config.NewResolver(&config.Config{API: config.API{Auth: config.APIAuth{
+			Enabled:   true,
+			Tokens:    []config.APIToken{{Name: "ci", Token: "secret-token", Level: "admin"}, {Name: "empty"}},
+			BasicAuth: []config.APIUser{{Username: "ui", Password: "password"}},
+		}}}, &rest.Config{})
+
+		authenticator := resolver.APIAuthenticator()
+		if authenticator == nil {
+			t.Fatal("Error: Should return Authenticator")
+		}
+
+		req, _ := http.NewRequest("GET", "/v1/targets", nil)
+		req.Header.Set("Authorization", "Bearer secret-token")
+
+		identity, err := authenticator.Authenticate(req)
+		if err != nil || identity.Level != auth.Admin {
+			t.Errorf("expected admin identity, got %v with %v", identity, err)
+		}
+
+		req, _ = http.NewRequest("GET", "/v1/targets", nil)
+		req.SetBasicAuth("ui", "password")
+
+		identity, err = authenticator.Authenticate(req)
+		if err != nil || identity.Level != auth.Read {
+			t.Errorf("expected read identity, got %v with %v", identity, err)
+		}
+	})
+}
+
+func Test_ResolveAPIAccessReviewer(t *testing.T) {
+	resolver := config.NewResolver(&config.Config{API: config.API{Auth: config.APIAuth{Enabled: true}}}, &rest.Config{})
+	if resolver.APIAccessReviewer() != nil {
+		t.Error("Error: Should return no AccessReviewer if Kubernetes auth is disabled")
+	}
+
+	resolver = config.NewResolver(&config.Config{API: config.API{Auth: config.APIAuth{Enabled: true, Kubernetes: config.KubernetesAuth{Enabled: true}}}}, &rest.Config{})
+	if resolver.APIAccessReviewer() == nil {
+		t.Error("Error: Should return AccessReviewer")
+	}
+}
+
+func Test_ResolveMetricsPipelines(t *testing.T) {
+	resolver := config.NewResolver(&config.Config{Metrics: config.Metrics{Gauges: []config.MetricsGauge{
+		{Name: "policy_report_policy_result", Labels: []string{"policy", "status"}, Relabel: []config.MetricsRelabel{{Action: "drop", SourceLabels: []string{"status"}, Regex: "pass"}}},
+		{Name: "policy_report_invalid_result", Labels: []string{"policy"}, Relabel: []config.MetricsRelabel{{Action: "keep", SourceLabels: []string{"policy"}, Regex: "("}}},
+	}}}, &rest.Config{})
+
+	pipelines := resolver.MetricsPipelines()
+	if len(pipelines) != 1 {
+		t.Fatalf("expected 1 valid pipeline, got %d", len(pipelines))
+	}
+	if pipelines[0].Name != "policy_report_policy_result" || len(pipelines[0].Relabel) != 1 {
+		t.Errorf("unexpected pipeline %v", pipelines[0])
+	}
+}
+
+func Test_ResolveMetricsSourcePipelines(t *testing.T) {
+	resolver := config.NewResolver(&config.Config{Metrics: config.Metrics{
+		Prefix: "cluster_",
+		Gauges: []config.MetricsGauge{{Name: "policy_result", Labels: []string{"policy"}}},
+		Sources: []config.MetricsSource{
+			{Source: "Trivy Vulnerability"},
+			{Source: "kyverno", Name: "kyverno_policy_results", Labels: []string{"policy", "status"}},
+		},
+	}}, &rest.Config{})
+
+	pipelines := resolver.MetricsPipelines()
+	if len(pipelines) != 3 {
+		t.Fatalf("expected 3 pipelines, got %d", len(pipelines))
+	}
+	if pipelines[0].Name != "cluster_policy_result" {
+		t.Errorf("expected prefixed gauge name, got %s", pipelines[0].Name)
+	}
+	if pipelines[1].Name != "cluster_trivy_vulnerability_results" || len(pipelines[1].Labels) == 0 || pipelines[1].Sources[0] != "Trivy Vulnerability" {
+		t.Errorf("unexpected source pipeline %+v", pipelines[1])
+	}
+	if pipelines[2].Name != "cluster_kyverno_policy_results" || len(pipelines[2].Labels) != 2 {
+		t.Errorf("unexpected source pipeline %+v", pipelines[2])
+	}
+}
+
+func Test_ResolveAlertRules(t *testing.T) {
+	resolver := config.NewResolver(&config.Config{Alerting: config.Alerting{Enabled: true, Rules: []config.AlertRule{
+		{Name: "critical-prod", Namespaces: []string{"prod"}, Threshold: 5, Window: 10 * time.Minute, Expression: "result.severity == 'critical'", Targets: []string{"slack"}},
+		{Name: "invalid", Expression: "severity >="},
+	}}}, &rest.Config{})
+
+	rules := resolver.AlertRules()
+	if len(rules) != 1 {
+		t.Fatalf("expected 1 valid rule, got %d", len(rules))
+	}
+	if rules[0].Name != "critical-prod" || rules[0].Expression == nil || rules[0].Threshold != 5 || rules[0].Window != 10*time.Minute {
+		t.Errorf("unexpected rule %+v", rules[0])
+	}
+
+	if err := resolver.RegisterAlertingListener(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := resolver.EventPublisher().GetListener()[alerting.Listener]; !ok {
+		t.Error("expected the alerting listener to be registered")
+	}
+}
+
+func Test_ResolveWasmPipeline(t *testing.T) {
+	resolver := config.NewResolver(&config.Config{}, &rest.Config{})
+	if resolver.WasmPipeline() != nil {
+		t.Error("expected no pipeline if the plugins are disabled")
+	}
+
+	resolver = config.NewResolver(&config.Config{WasmPlugins: config.WasmPlugins{Enabled: true, Modules: []config.WasmModule{{Path: "/plugins/enrich.wasm"}}}}, &rest.Config{})
+	if resolver.WasmPipeline() == nil {
+		t.Error("expected a pipeline for the configured modules")
+	}
+}
+
 func Test_ResolveCache(t *testing.T) {
 	t.Run("InMemory", func(t *testing.T) {
 		resolver := config.NewResolver(testConfig, &rest.Config{})
@@ -253,6 +565,96 @@ func Test_ResolveCache(t *testing.T) {
 			t.Error("Error: Should return ResultCache")
 		}
 	})
+
+	t.Run("Redis with TLS", func(t *testing.T) {
+		redisConfig := &config.Config{
+			Redis: config.Redis{
+				Enabled: true,
+				Address: "localhost:6379",
+				TTL:     5 * time.Minute,
+				TLS: config.RedisTLS{
+					Enabled:    true,
+					SkipTLS:    true,
+					ServerName: "redis",
+				},
+			},
+		}
+
+		resolver := config.NewResolver(redisConfig, &rest.Config{})
+
+		client := resolver.RedisClient()
+		if client.Options().TLSConfig == nil {
+			t.Fatal("Error: Expected TLS config for Redis client")
+		}
+		if !client.Options().TLSConfig.InsecureSkipVerify || client.Options().TLSConfig.ServerName != "redis" {
+			t.Error("Error: Unexpected TLS config for Redis client")
+		}
+		if resolver.RedisClient() != client {
+			t.Error("A second call resolver.RedisClient() should return the cached first client")
+		}
+	})
+}
+
+func Test_ResolveDeduplicator(t *testing.T) {
+	t.Run("InMemory", func(t *testing.T) {
+		resolver := config.NewResolver(testConfig, &rest.Config{})
+
+		dedupe1, err := resolver.Deduplicator()
+		if err != nil {
+			t.Errorf("Unexpected Error: %s", err)
+		}
+
+		dedupe2, _ := resolver.Deduplicator()
+		if dedupe1 != dedupe2 {
+			t.Error("A second call resolver.Deduplicator() should return the cached first deduplicator")
+		}
+	})
+
+	t.Run("SQLite", func(t *testing.T) {
+		resolver := config.NewResolver(&config.Config{
+			Deduplication: config.Deduplication{Type: "sqlite", DBFile: filepath.Join(t.TempDir(), "dedupe-test.db")},
+		}, &rest.Config{})
+
+		dedupe, err := resolver.Deduplicator()
+		if err != nil {
+			t.Errorf("Unexpected Error: %s", err)
+		}
+		if dedupe == nil {
+			t.Error("Error: Should return Deduplicator")
+		}
+	})
+
+	t.Run("Redis", func(t *testing.T) {
+		resolver := config.NewResolver(&config.Config{
+			Redis:         config.Redis{Enabled: true, Address: "localhost:6379"},
+			Deduplication: config.Deduplication{Type: "redis"},
+		}, &rest.Config{})
+
+		dedupe, _ := resolver.Deduplicator()
+		if dedupe == nil {
+			t.Error("Error: Should return Deduplicator")
+		}
+	})
+}
+
+func Test_ResolveNotificationCache(t *testing.T) {
+	resolver := config.NewResolver(&config.Config{
+		Slack:         config.Slack{Webhook: "http://slack"},
+		Notifications: config.NotificationCache{Enabled: true, TTL: time.Hour},
+		Deduplication: config.Deduplication{Type: "sqlite", DBFile: filepath.Join(t.TempDir(), "dedupe-test.db")},
+	}, &rest.Config{})
+
+	notified, err := resolver.NotificationCache()
+	if err != nil {
+		t.Fatalf("Unexpected Error: %s", err)
+	}
+
+	notified.Add("Slack", "123")
+
+	dedupe, _ := resolver.Deduplicator()
+	if !cache.NewNotified(func(string) time.Duration { return time.Hour }, dedupe).Has("Slack", "123") {
+		t.Error("Expected the notification to be kept in the deduplication store")
+	}
 }
 
 func Test_ResolveMapper(t *testing.T) {
@@ -278,6 +680,40 @@ func Test_ResolveReportFilter(t *testing.T) {
 	}
 }
 
+func Test_ResolveWatchOptions(t *testing.T) {
+	resolver := config.NewResolver(&config.Config{
+		Watch: config.Watch{
+			LabelSelector: "app.kubernetes.io/managed-by=kyverno",
+			Namespaces:    config.ValueFilter{Exclude: []string{"kube-system"}},
+		},
+	}, &rest.Config{})
+
+	options := resolver.WatchOptions()
+	if options.LabelSelector != "app.kubernetes.io/managed-by=kyverno" {
+		t.Errorf("unexpected label selector: %s", options.LabelSelector)
+	}
+	if len(options.ExcludeNamespaces) != 1 || options.ExcludeNamespaces[0] != "kube-system" {
+		t.Errorf("unexpected excluded namespaces: %v", options.ExcludeNamespaces)
+	}
+	if len(options.ExcludeLabels) != 1 || options.ExcludeLabels[0] != kyverno.SourceLabel {
+		t.Errorf("expected interim reports of Kyverno to be excluded, got %v", options.ExcludeLabels)
+	}
+}
+
+func Test_ResolveWatchOptionsWithInterimReports(t *testing.T) {
+	resolver := config.NewResolver(&config.Config{Watch: config.Watch{InterimReports: true}}, &rest.Config{})
+
+	if options := resolver.WatchOptions(); len(options.ExcludeLabels) != 0 {
+		t.Errorf("expected no excluded labels, got %v", options.ExcludeLabels)
+	}
+
+	resolver = config.NewResolver(&config.Config{Watch: config.Watch{InterimLabels: []string{"example.com/partial"}}}, &rest.Config{})
+
+	if options := resolver.WatchOptions(); len(options.ExcludeLabels) != 1 || options.ExcludeLabels[0] != "example.com/partial" {
+		t.Errorf("expected the configured interim labels, got %v", options.ExcludeLabels)
+	}
+}
+
 func Test_ResolveClientWithInvalidK8sConfig(t *testing.T) {
 	k8sConfig := &rest.Config{}
 	k8sConfig.Host = "invalid/url"
@@ -366,6 +802,15 @@ func Test_RegisterMetricsListener(t *testing.T) {
 	})
 }
 
+func Test_RegisterProcessingLagListener(t *testing.T) {
+	resolver := config.NewResolver(testConfig, &rest.Config{})
+	resolver.RegisterProcessingLagListener()
+
+	if _, ok := resolver.EventPublisher().GetListener()[listener.ProcessingLag]; !ok {
+		t.Error("Expected the processing lag listener to be registered")
+	}
+}
+
 func Test_RegisterSendResultListener(t *testing.T) {
 	t.Run("Register SendResultListener with Targets", func(t *testing.T) {
 		resolver := config.NewResolver(testConfig, &rest.Config{})
@@ -464,3 +909,135 @@ func Test_SMTP(t *testing.T) {
 		}
 	})
 }
+
+func Test_ResolveGRPCServer(t *testing.T) {
+	resolver := config.NewResolver(&config.Config{DBFile: filepath.Join(t.TempDir(), "test.db"), GRPC: config.GRPC{Enabled: true, Port: 9090}}, &rest.Config{})
+
+	db, _ := resolver.Database()
+	defer db.Close()
+
+	store, _ := resolver.PolicyReportStore(db)
+
+	server1, _ := resolver.GRPCServer(store, nil)
+	server2, _ := resolver.GRPCServer(store, nil)
+	if server1 != server2 {
+		t.Error("A second call resolver.GRPCServer() should return the cached first server")
+	}
+
+	resolver.RegisterGRPCWatchListener(server1)
+	if _, ok := resolver.EventPublisher().GetListener()[rpc.WatchListener]; !ok {
+		t.Error("expected the gRPC watch listener to be registered")
+	}
+}
+
+func Test_ResolveGRPCServerWithoutAuth(t *testing.T) {
+	resolver := config.NewResolver(&config.Config{
+		DBFile: filepath.Join(t.TempDir(), "test.db"),
+		API:    config.API{Auth: config.APIAuth{Enabled: true}},
+		GRPC:   config.GRPC{Enabled: true, Port: 9090},
+	}, &rest.Config{})
+
+	db, _ := resolver.Database()
+	defer db.Close()
+
+	store, _ := resolver.PolicyReportStore(db)
+
+	if _, err := resolver.GRPCServer(store, nil); err == nil {
+		t.Error("expected an error for a gRPC server without auth while the REST api requires auth")
+	}
+}
+
+func Test_ResolveMetricsPushers(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		resolver := config.NewResolver(&config.Config{}, &rest.Config{})
+
+		if resolver.MetricsPushgateway() != nil {
+			t.Error("expected no pushgateway without URL")
+		}
+		if resolver.MetricsRemoteWrite() != nil {
+			t.Error("expected no remote-write without URL")
+		}
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		resolver := config.NewResolver(&config.Config{Metrics: config.Metrics{
+			Pushgateway: config.MetricsPushgateway{URL: "http://pushgateway:9091", Job: "policy-reporter"},
+			RemoteWrite: config.MetricsRemoteWrite{URL: "http://prometheus:9090/api/v1/write", Token: "token"},
+		}}, &rest.Config{})
+
+		if resolver.MetricsPushgateway() == nil {
+			t.Error("expected pushgateway")
+		}
+		if resolver.MetricsRemoteWrite() == nil {
+			t.Error("expected remote-write")
+		}
+	})
+}
+
+func Test_ResolveKubernetesEventsClient(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		resolver := config.NewResolver(&config.Config{}, &rest.Config{})
+
+		if resolver.KubernetesEventsClient() != nil {
+			t.Error("expected no client if disabled")
+		}
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		resolver := config.NewResolver(&config.Config{Events: config.KubernetesEvents{Enabled: true, QPS: 5, Burst: 25}}, &rest.Config{})
+
+		client := resolver.KubernetesEventsClient()
+		if client == nil {
+			t.Fatal("expected kubernetes events client")
+		}
+		if client.Name() != "Kubernetes Events" {
+			t.Errorf("unexpected name %s", client.Name())
+		}
+	})
+}
+
+func Test_ResolveAnnotationWriter(t *testing.T) {
+	resolver := config.NewResolver(&config.Config{Annotations: config.ViolationAnnotations{Enabled: true, Namespaces: []string{"team-*"}}}, &rest.Config{})
+
+	writer1, err := resolver.AnnotationWriter()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	writer2, _ := resolver.AnnotationWriter()
+	if writer1 != writer2 {
+		t.Error("A second call resolver.AnnotationWriter() should return the cached first writer")
+	}
+}
+
+func Test_ResolveScoreWeights(t *testing.T) {
+	resolver := config.NewResolver(&config.Config{Score: config.ComplianceScore{Enabled: true, Weights: map[string]float64{"critical": 100}}}, &rest.Config{})
+
+	weights := resolver.ScoreWeights()
+	if weights["critical"] != 100 {
+		t.Errorf("expected configured weight for critical, got %v", weights["critical"])
+	}
+	if weights["high"] != 8 {
+		t.Errorf("expected default weight for high, got %v", weights["high"])
+	}
+}
+
+func Test_EmailReportOptionsFromConfig(t *testing.T) {
+	parent := config.EmailReport{Template: "custom/violations.html", Attachments: []string{"csv"}}
+
+	t.Run("Report", func(t *testing.T) {
+		options := config.EmailReportOptionsFromConfig(parent, nil)
+		if options.Template != "custom/violations.html" || len(options.Attachments) != 1 {
+			t.Errorf("unexpected options: %+v", options)
+		}
+	})
+	t.Run("Channel Inherits Parent", func(t *testing.T) {
+		options := config.EmailReportOptionsFromConfig(config.EmailReport{Attachments: []string{"json"}}, &parent)
+		if options.Template != "custom/violations.html" {
+			t.Errorf("expected inherited template, got %s", options.Template)
+		}
+		if len(options.Attachments) != 1 || options.Attachments[0] != "json" {
+			t.Errorf("expected channel attachments, got %v", options.Attachments)
+		}
+	})
+}
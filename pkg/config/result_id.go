@@ -0,0 +1,24 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+// IDMapperFromConfig creates an IDMapper with the configured strategy of each source
+func IDMapperFromConfig(ids []ResultID) (*report.IDMapper, error) {
+	mapper := report.NewIDMapper()
+
+	for i, id := range ids {
+		strategy, err := report.NewIDStrategy(id.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("resultIDs[%d]: %w", i, err)
+		}
+
+		mapper.Register(strings.TrimSpace(id.Source), strategy)
+	}
+
+	return mapper, nil
+}
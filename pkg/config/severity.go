@@ -0,0 +1,15 @@
+package config
+
+import (
+	"github.com/kyverno/policy-reporter/pkg/severity"
+)
+
+// SeverityLevelsFromConfig maps the configured custom severities to their severity levels
+func SeverityLevelsFromConfig(c []CustomSeverity) []severity.Level {
+	levels := make([]severity.Level, 0, len(c))
+	for _, s := range c {
+		levels = append(levels, severity.Level{Name: s.Name, Above: s.Above, Color: s.Color})
+	}
+
+	return levels
+}
@@ -3,32 +3,68 @@ package config
 import (
 	"context"
 	"fmt"
-	"log"
+	"regexp"
+	"strings"
 
-	_ "github.com/mattn/go-sqlite3"
+	"golang.org/x/oauth2"
 
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/expression"
 	"github.com/kyverno/policy-reporter/pkg/helper"
 	"github.com/kyverno/policy-reporter/pkg/kubernetes/secrets"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/maintenance"
 	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/secret"
 	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/alertmanager"
+	"github.com/kyverno/policy-reporter/pkg/target/cloudevents"
+	"github.com/kyverno/policy-reporter/pkg/target/defectdojo"
+	"github.com/kyverno/policy-reporter/pkg/target/defender"
 	"github.com/kyverno/policy-reporter/pkg/target/discord"
 	"github.com/kyverno/policy-reporter/pkg/target/elasticsearch"
+	"github.com/kyverno/policy-reporter/pkg/target/exec"
+	"github.com/kyverno/policy-reporter/pkg/target/github"
+	"github.com/kyverno/policy-reporter/pkg/target/gitlab"
+	"github.com/kyverno/policy-reporter/pkg/target/googlechat"
+	"github.com/kyverno/policy-reporter/pkg/target/grafana"
 	"github.com/kyverno/policy-reporter/pkg/target/http"
+	"github.com/kyverno/policy-reporter/pkg/target/issues"
 	"github.com/kyverno/policy-reporter/pkg/target/kinesis"
 	"github.com/kyverno/policy-reporter/pkg/target/loki"
 	"github.com/kyverno/policy-reporter/pkg/target/s3"
+	"github.com/kyverno/policy-reporter/pkg/target/scc"
+	"github.com/kyverno/policy-reporter/pkg/target/securityhub"
 	"github.com/kyverno/policy-reporter/pkg/target/slack"
 	"github.com/kyverno/policy-reporter/pkg/target/teams"
+	"github.com/kyverno/policy-reporter/pkg/target/telegram"
 	"github.com/kyverno/policy-reporter/pkg/target/ui"
 	"github.com/kyverno/policy-reporter/pkg/target/webhook"
 )
 
+var logger = logging.Component("config")
+
 // TargetFactory manages target creation
 type TargetFactory struct {
 	secretClient secrets.Client
+	metadata     report.ResourceMetadata
+	exclusions   report.ReportResultValidation
 	namespace    string
+	cluster      string
+	customFields map[string]string
+	terminating  bool
+	orphaned     bool
+	dryRun       bool
+	floor        *report.SeverityFloor
+	age          *report.ResultAge
+	errorRouting ErrorRouting
+	http         HTTPClient
+	connections  func(target string, reused bool)
 }
 
+// ClusterField is the custom field or label with the global cluster name, added to each target payload
+const ClusterField = "cluster"
+
 // LokiClients resolver method
 func (f *TargetFactory) LokiClients(config Loki) []target.Client {
 	clients := make([]target.Client, 0)
@@ -124,6 +160,29 @@ func (f *TargetFactory) DiscordClients(config Discord) []target.Client {
 	return clients
 }
 
+// GoogleChatClients resolver method
+func (f *TargetFactory) GoogleChatClients(config GoogleChat) []target.Client {
+	clients := make([]target.Client, 0)
+	if config.Name == "" {
+		config.Name = "Google Chat"
+	}
+
+	if es := f.createGoogleChatClient(config, GoogleChat{}); es != nil {
+		clients = append(clients, es)
+	}
+	for i, channel := range config.Channels {
+		if channel.Name == "" {
+			channel.Name = fmt.Sprintf("Google Chat Space %d", i+1)
+		}
+
+		if es := f.createGoogleChatClient(channel, config); es != nil {
+			clients = append(clients, es)
+		}
+	}
+
+	return clients
+}
+
 // TeamsClients resolver method
 func (f *TargetFactory) TeamsClients(config Teams) []target.Client {
 	clients := make([]target.Client, 0)
@@ -170,22 +229,260 @@ func (f *TargetFactory) WebhookClients(config Webhook) []target.Client {
 	return clients
 }
 
+// AlertmanagerClients resolver method
+func (f *TargetFactory) AlertmanagerClients(config Alertmanager) []target.Client {
+	clients := make([]target.Client, 0)
+	if config.Name == "" {
+		config.Name = "Alertmanager"
+	}
+
+	if am := f.createAlertmanagerClient(config, Alertmanager{}); am != nil {
+		clients = append(clients, am)
+	}
+	for i, channel := range config.Channels {
+		if channel.Name == "" {
+			channel.Name = fmt.Sprintf("Alertmanager Channel %d", i+1)
+		}
+
+		if am := f.createAlertmanagerClient(channel, config); am != nil {
+			clients = append(clients, am)
+		}
+	}
+
+	return clients
+}
+
+// ExecClients resolver method
+func (f *TargetFactory) ExecClients(config Exec) []target.Client {
+	clients := make([]target.Client, 0)
+	if config.Name == "" {
+		config.Name = "Exec"
+	}
+
+	if c := f.createExecClient(config, Exec{}); c != nil {
+		clients = append(clients, c)
+	}
+	for i, channel := range config.Channels {
+		if channel.Name == "" {
+			channel.Name = fmt.Sprintf("Exec Channel %d", i+1)
+		}
+
+		if c := f.createExecClient(channel, config); c != nil {
+			clients = append(clients, c)
+		}
+	}
+
+	return clients
+}
+
+// GrafanaClients resolver method
+func (f *TargetFactory) GrafanaClients(config Grafana) []target.Client {
+	clients := make([]target.Client, 0)
+	if config.Name == "" {
+		config.Name = "Grafana"
+	}
+
+	if es := f.createGrafanaClient(config, Grafana{}); es != nil {
+		clients = append(clients, es)
+	}
+	for i, channel := range config.Channels {
+		if channel.Name == "" {
+			channel.Name = fmt.Sprintf("Grafana Channel %d", i+1)
+		}
+
+		if es := f.createGrafanaClient(channel, config); es != nil {
+			clients = append(clients, es)
+		}
+	}
+
+	return clients
+}
+
+// GitHubClients resolver method
+func (f *TargetFactory) GitHubClients(config GitHub) []target.Client {
+	clients := make([]target.Client, 0)
+	if config.Name == "" {
+		config.Name = "GitHub"
+	}
+	if config.Host == "" {
+		config.Host = "https://api.github.com"
+	}
+
+	if gh := f.createGitHubClient(config, GitHub{}); gh != nil {
+		clients = append(clients, gh)
+	}
+	for i, channel := range config.Channels {
+		if channel.Name == "" {
+			channel.Name = fmt.Sprintf("GitHub Channel %d", i+1)
+		}
+
+		if gh := f.createGitHubClient(channel, config); gh != nil {
+			clients = append(clients, gh)
+		}
+	}
+
+	return clients
+}
+
+// GitLabClients resolver method
+func (f *TargetFactory) GitLabClients(config GitLab) []target.Client {
+	clients := make([]target.Client, 0)
+	if config.Name == "" {
+		config.Name = "GitLab"
+	}
+	if config.Host == "" {
+		config.Host = "https://gitlab.com"
+	}
+
+	if gl := f.createGitLabClient(config, GitLab{}); gl != nil {
+		clients = append(clients, gl)
+	}
+	for i, channel := range config.Channels {
+		if channel.Name == "" {
+			channel.Name = fmt.Sprintf("GitLab Channel %d", i+1)
+		}
+
+		if gl := f.createGitLabClient(channel, config); gl != nil {
+			clients = append(clients, gl)
+		}
+	}
+
+	return clients
+}
+
+// TelegramClients resolver method
+func (f *TargetFactory) TelegramClients(config Telegram) []target.Client {
+	clients := make([]target.Client, 0)
+	if config.Name == "" {
+		config.Name = "Telegram"
+	}
+
+	if tg := f.createTelegramClient(config, Telegram{}); tg != nil {
+		clients = append(clients, tg)
+	}
+	for i, channel := range config.Channels {
+		if channel.Name == "" {
+			channel.Name = fmt.Sprintf("Telegram Channel %d", i+1)
+		}
+
+		if tg := f.createTelegramClient(channel, config); tg != nil {
+			clients = append(clients, tg)
+		}
+	}
+
+	return clients
+}
+
+// SecurityHubClients resolver method
+func (f *TargetFactory) SecurityHubClients(config SecurityHub) []target.Client {
+	clients := make([]target.Client, 0)
+	if config.Name == "" {
+		config.Name = "SecurityHub"
+	}
+
+	if sh := f.createSecurityHubClient(config, SecurityHub{}); sh != nil {
+		clients = append(clients, sh)
+	}
+	for i, channel := range config.Channels {
+		if channel.Name == "" {
+			channel.Name = fmt.Sprintf("SecurityHub Channel %d", i+1)
+		}
+
+		if sh := f.createSecurityHubClient(channel, config); sh != nil {
+			clients = append(clients, sh)
+		}
+	}
+
+	return clients
+}
+
+// SecurityCenterClients resolver method
+func (f *TargetFactory) SecurityCenterClients(config SecurityCenter) []target.Client {
+	clients := make([]target.Client, 0)
+	if config.Name == "" {
+		config.Name = "SecurityCommandCenter"
+	}
+
+	if scc := f.createSecurityCenterClient(config, SecurityCenter{}); scc != nil {
+		clients = append(clients, scc)
+	}
+	for i, channel := range config.Channels {
+		if channel.Name == "" {
+			channel.Name = fmt.Sprintf("SecurityCommandCenter Channel %d", i+1)
+		}
+
+		if scc := f.createSecurityCenterClient(channel, config); scc != nil {
+			clients = append(clients, scc)
+		}
+	}
+
+	return clients
+}
+
+// DefenderClients resolver method
+func (f *TargetFactory) DefenderClients(config Defender) []target.Client {
+	clients := make([]target.Client, 0)
+	if config.Name == "" {
+		config.Name = "DefenderForCloud"
+	}
+
+	if d := f.createDefenderClient(config, Defender{}); d != nil {
+		clients = append(clients, d)
+	}
+	for i, channel := range config.Channels {
+		if channel.Name == "" {
+			channel.Name = fmt.Sprintf("DefenderForCloud Channel %d", i+1)
+		}
+
+		if d := f.createDefenderClient(channel, config); d != nil {
+			clients = append(clients, d)
+		}
+	}
+
+	return clients
+}
+
+// DefectDojoClients resolver method
+func (f *TargetFactory) DefectDojoClients(config DefectDojo) []target.Client {
+	clients := make([]target.Client, 0)
+	if config.Name == "" {
+		config.Name = "DefectDojo"
+	}
+
+	if dd := f.createDefectDojoClient(config, DefectDojo{}); dd != nil {
+		clients = append(clients, dd)
+	}
+	for i, channel := range config.Channels {
+		if channel.Name == "" {
+			channel.Name = fmt.Sprintf("DefectDojo Channel %d", i+1)
+		}
+
+		if dd := f.createDefectDojoClient(channel, config); dd != nil {
+			clients = append(clients, dd)
+		}
+	}
+
+	return clients
+}
+
 // UIClient resolver method
 func (f *TargetFactory) UIClient(config UI) target.Client {
 	if config.Host == "" {
 		return nil
 	}
 
-	log.Println("[INFO] UI configured")
+	logger.Info("UI configured")
 
 	return ui.NewClient(ui.Options{
 		ClientOptions: target.ClientOptions{
 			Name:                  "UI",
 			SkipExistingOnStartup: config.SkipExisting,
-			ResultFilter:          createResultFilter(TargetFilter{}, config.MinimumPriority, config.Sources),
+			Concurrency:           config.Concurrency,
+			NotificationTTL:       config.NotificationTTL,
+			ResultFilter:          f.createResultFilter("UI", TargetFilter{}, config.MinimumPriority, config.Sources),
 		},
 		Host:       config.Host,
-		HTTPClient: http.NewClient(config.Certificate, config.SkipTLS),
+		HTTPClient: f.httpClient("UI", config.Certificate, config.SkipTLS, config.HTTP),
 	})
 }
 
@@ -240,7 +537,12 @@ func (f *TargetFactory) createSlackClient(config Slack, parent Slack) target.Cli
 		f.mapSecretValues(&config, config.SecretRef)
 	}
 
-	if config.Webhook == "" {
+	if config.Token != "" && config.Channel == "" {
+		logger.Warnf("%s: token requires a channel, using the webhook", config.Name)
+		config.Token = ""
+	}
+
+	if config.Webhook == "" && config.Token == "" {
 		return nil
 	}
 
@@ -252,18 +554,60 @@ func (f *TargetFactory) createSlackClient(config Slack, parent Slack) target.Cli
 		config.SkipExisting = parent.SkipExisting
 	}
 
-	log.Printf("[INFO] %s configured", config.Name)
+	if config.Concurrency == 0 {
+		config.Concurrency = parent.Concurrency
+	}
+
+	if config.NotificationTTL == 0 {
+		config.NotificationTTL = parent.NotificationTTL
+	}
+
+	if config.RemediationTemplate == "" {
+		config.RemediationTemplate = parent.RemediationTemplate
+	}
+
+	if config.TitleTemplate == "" {
+		config.TitleTemplate = parent.TitleTemplate
+	}
+
+	if config.MessageTemplate == "" {
+		config.MessageTemplate = parent.MessageTemplate
+	}
+
+	if len(config.Actions) == 0 {
+		config.Actions = parent.Actions
+	}
+
+	if config.ThreadTTL == 0 {
+		config.ThreadTTL = parent.ThreadTTL
+	}
+
+	logger.Infof("%s configured", config.Name)
 
 	return slack.NewClient(slack.Options{
 		ClientOptions: target.ClientOptions{
 			Name:                  config.Name,
 			SkipExistingOnStartup: config.SkipExisting,
-			ResultFilter:          createResultFilter(config.Filter, config.MinimumPriority, config.Sources),
+			Concurrency:           config.Concurrency,
+			NotificationTTL:       config.NotificationTTL,
+			ResultFilter:          f.createResultFilter(config.Name, config.Filter, config.MinimumPriority, config.Sources),
 			ReportFilter:          createReprotFilter(config.Filter),
+			Sampler:               createSampler(config.Filter),
+			Grouper:               createGrouper(config.Filter),
+			Schedule:              createSchedule(config.Name, config.Filter),
+			Scrubber:              createScrubber(config.Name, config.Filter),
+			Backfill:              createBackfill(config.Name, config.Filter),
 		},
-		Webhook:      config.Webhook,
-		CustomFields: config.CustomFields,
-		HTTPClient:   http.NewClient("", false),
+		Webhook:      secret.New(config.Webhook),
+		CustomFields: f.withGlobalFields(config.CustomFields),
+		Remediation:  remediationTemplate(config.Name, config.RemediationTemplate),
+		HTTPClient:   f.httpClient(config.Name, "", false, config.HTTP.merge(parent.HTTP)),
+		Title:        messageTemplate(config.Name, "titleTemplate", config.TitleTemplate),
+		Message:      messageTemplate(config.Name, "messageTemplate", config.MessageTemplate),
+		Actions:      messageActions(config.Name, config.Actions),
+		Token:        secret.New(config.Token),
+		Channel:      config.Channel,
+		ThreadTTL:    config.ThreadTTL,
 	})
 }
 
@@ -290,6 +634,14 @@ func (f *TargetFactory) createLokiClient(config Loki, parent Loki) target.Client
 		config.SkipExisting = parent.SkipExisting
 	}
 
+	if config.Concurrency == 0 {
+		config.Concurrency = parent.Concurrency
+	}
+
+	if config.NotificationTTL == 0 {
+		config.NotificationTTL = parent.NotificationTTL
+	}
+
 	if config.MinimumPriority == "" {
 		config.MinimumPriority = parent.MinimumPriority
 	}
@@ -298,18 +650,25 @@ func (f *TargetFactory) createLokiClient(config Loki, parent Loki) target.Client
 		config.Path = parent.Path
 	}
 
-	log.Printf("[INFO] %s configured", config.Name)
+	logger.Infof("%s configured", config.Name)
 
 	return loki.NewClient(loki.Options{
 		ClientOptions: target.ClientOptions{
 			Name:                  config.Name,
 			SkipExistingOnStartup: config.SkipExisting,
-			ResultFilter:          createResultFilter(config.Filter, config.MinimumPriority, config.Sources),
+			Concurrency:           config.Concurrency,
+			NotificationTTL:       config.NotificationTTL,
+			ResultFilter:          f.createResultFilter(config.Name, config.Filter, config.MinimumPriority, config.Sources),
 			ReportFilter:          createReprotFilter(config.Filter),
+			Sampler:               createSampler(config.Filter),
+			Grouper:               createGrouper(config.Filter),
+			Schedule:              createSchedule(config.Name, config.Filter),
+			Scrubber:              createScrubber(config.Name, config.Filter),
+			Backfill:              createBackfill(config.Name, config.Filter),
 		},
-		Host:         config.Host + config.Path,
-		CustomLabels: config.CustomLabels,
-		HTTPClient:   http.NewClient(config.Certificate, config.SkipTLS),
+		Host:         secret.New(config.Host + config.Path),
+		CustomLabels: f.withGlobalFields(config.CustomLabels),
+		HTTPClient:   f.httpClient(config.Name, config.Certificate, config.SkipTLS, config.HTTP.merge(parent.HTTP)),
 	})
 }
 
@@ -360,22 +719,37 @@ func (f *TargetFactory) createElasticsearchClient(config Elasticsearch, parent E
 		config.SkipExisting = parent.SkipExisting
 	}
 
-	log.Printf("[INFO] %s configured", config.Name)
+	if config.Concurrency == 0 {
+		config.Concurrency = parent.Concurrency
+	}
+
+	if config.NotificationTTL == 0 {
+		config.NotificationTTL = parent.NotificationTTL
+	}
+
+	logger.Infof("%s configured", config.Name)
 
 	return elasticsearch.NewClient(elasticsearch.Options{
 		ClientOptions: target.ClientOptions{
 			Name:                  config.Name,
 			SkipExistingOnStartup: config.SkipExisting,
-			ResultFilter:          createResultFilter(config.Filter, config.MinimumPriority, config.Sources),
+			Concurrency:           config.Concurrency,
+			NotificationTTL:       config.NotificationTTL,
+			ResultFilter:          f.createResultFilter(config.Name, config.Filter, config.MinimumPriority, config.Sources),
 			ReportFilter:          createReprotFilter(config.Filter),
+			Sampler:               createSampler(config.Filter),
+			Grouper:               createGrouper(config.Filter),
+			Schedule:              createSchedule(config.Name, config.Filter),
+			Scrubber:              createScrubber(config.Name, config.Filter),
+			Backfill:              createBackfill(config.Name, config.Filter),
 		},
 		Host:         config.Host,
 		Username:     config.Username,
-		Password:     config.Password,
+		Password:     secret.New(config.Password),
 		Rotation:     config.Rotation,
 		Index:        config.Index,
-		CustomFields: config.CustomFields,
-		HTTPClient:   http.NewClient(config.Certificate, config.SkipTLS),
+		CustomFields: f.withGlobalFields(config.CustomFields),
+		HTTPClient:   f.httpClient(config.Name, config.Certificate, config.SkipTLS, config.HTTP.merge(parent.HTTP)),
 	})
 }
 
@@ -396,18 +770,95 @@ func (f *TargetFactory) createDiscordClient(config Discord, parent Discord) targ
 		config.SkipExisting = parent.SkipExisting
 	}
 
-	log.Printf("[INFO] %s configured", config.Name)
+	if config.Concurrency == 0 {
+		config.Concurrency = parent.Concurrency
+	}
+
+	if config.NotificationTTL == 0 {
+		config.NotificationTTL = parent.NotificationTTL
+	}
+
+	if config.RemediationTemplate == "" {
+		config.RemediationTemplate = parent.RemediationTemplate
+	}
+
+	if len(config.Mentions) == 0 {
+		config.Mentions = parent.Mentions
+	}
+
+	logger.Infof("%s configured", config.Name)
 
 	return discord.NewClient(discord.Options{
 		ClientOptions: target.ClientOptions{
 			Name:                  config.Name,
 			SkipExistingOnStartup: config.SkipExisting,
-			ResultFilter:          createResultFilter(config.Filter, config.MinimumPriority, config.Sources),
+			Concurrency:           config.Concurrency,
+			NotificationTTL:       config.NotificationTTL,
+			ResultFilter:          f.createResultFilter(config.Name, config.Filter, config.MinimumPriority, config.Sources),
+			ReportFilter:          createReprotFilter(config.Filter),
+			Sampler:               createSampler(config.Filter),
+			Grouper:               createGrouper(config.Filter),
+			Schedule:              createSchedule(config.Name, config.Filter),
+			Scrubber:              createScrubber(config.Name, config.Filter),
+			Backfill:              createBackfill(config.Name, config.Filter),
+		},
+		Webhook:      secret.New(config.Webhook),
+		CustomFields: f.withGlobalFields(config.CustomFields),
+		Remediation:  remediationTemplate(config.Name, config.RemediationTemplate),
+		HTTPClient:   f.httpClient(config.Name, "", false, config.HTTP.merge(parent.HTTP)),
+		Mentions:     config.Mentions,
+	})
+}
+
+func (f *TargetFactory) createGoogleChatClient(config GoogleChat, parent GoogleChat) target.Client {
+	if config.SecretRef != "" && f.secretClient != nil {
+		f.mapSecretValues(&config, config.SecretRef)
+	}
+
+	if config.Webhook == "" {
+		return nil
+	}
+
+	if config.MinimumPriority == "" {
+		config.MinimumPriority = parent.MinimumPriority
+	}
+
+	if !config.SkipExisting {
+		config.SkipExisting = parent.SkipExisting
+	}
+
+	if config.Concurrency == 0 {
+		config.Concurrency = parent.Concurrency
+	}
+
+	if config.NotificationTTL == 0 {
+		config.NotificationTTL = parent.NotificationTTL
+	}
+
+	if config.RemediationTemplate == "" {
+		config.RemediationTemplate = parent.RemediationTemplate
+	}
+
+	logger.Infof("%s configured", config.Name)
+
+	return googlechat.NewClient(googlechat.Options{
+		ClientOptions: target.ClientOptions{
+			Name:                  config.Name,
+			SkipExistingOnStartup: config.SkipExisting,
+			Concurrency:           config.Concurrency,
+			NotificationTTL:       config.NotificationTTL,
+			ResultFilter:          f.createResultFilter(config.Name, config.Filter, config.MinimumPriority, config.Sources),
 			ReportFilter:          createReprotFilter(config.Filter),
+			Sampler:               createSampler(config.Filter),
+			Grouper:               createGrouper(config.Filter),
+			Schedule:              createSchedule(config.Name, config.Filter),
+			Scrubber:              createScrubber(config.Name, config.Filter),
+			Backfill:              createBackfill(config.Name, config.Filter),
 		},
-		Webhook:      config.Webhook,
-		CustomFields: config.CustomFields,
-		HTTPClient:   http.NewClient("", false),
+		Webhook:      secret.New(config.Webhook),
+		CustomFields: f.withGlobalFields(config.CustomFields),
+		Remediation:  remediationTemplate(config.Name, config.RemediationTemplate),
+		HTTPClient:   f.httpClient(config.Name, "", false, config.HTTP.merge(parent.HTTP)),
 	})
 }
 
@@ -436,22 +887,52 @@ func (f *TargetFactory) createTeamsClient(config Teams, parent Teams) target.Cli
 		config.SkipExisting = parent.SkipExisting
 	}
 
+	if config.Concurrency == 0 {
+		config.Concurrency = parent.Concurrency
+	}
+
+	if config.NotificationTTL == 0 {
+		config.NotificationTTL = parent.NotificationTTL
+	}
+
+	if config.RemediationTemplate == "" {
+		config.RemediationTemplate = parent.RemediationTemplate
+	}
+
 	if !config.SkipTLS {
 		config.SkipTLS = parent.SkipTLS
 	}
 
-	log.Printf("[INFO] %s configured", config.Name)
+	if config.Format == "" {
+		config.Format = parent.Format
+	}
+
+	if len(config.Actions) == 0 {
+		config.Actions = parent.Actions
+	}
+
+	logger.Infof("%s configured", config.Name)
 
 	return teams.NewClient(teams.Options{
 		ClientOptions: target.ClientOptions{
 			Name:                  config.Name,
 			SkipExistingOnStartup: config.SkipExisting,
-			ResultFilter:          createResultFilter(config.Filter, config.MinimumPriority, config.Sources),
+			Concurrency:           config.Concurrency,
+			NotificationTTL:       config.NotificationTTL,
+			ResultFilter:          f.createResultFilter(config.Name, config.Filter, config.MinimumPriority, config.Sources),
 			ReportFilter:          createReprotFilter(config.Filter),
+			Sampler:               createSampler(config.Filter),
+			Grouper:               createGrouper(config.Filter),
+			Schedule:              createSchedule(config.Name, config.Filter),
+			Scrubber:              createScrubber(config.Name, config.Filter),
+			Backfill:              createBackfill(config.Name, config.Filter),
 		},
-		Webhook:      config.Webhook,
-		CustomFields: config.CustomFields,
-		HTTPClient:   http.NewClient(config.Certificate, config.SkipTLS),
+		Webhook:      secret.New(config.Webhook),
+		CustomFields: f.withGlobalFields(config.CustomFields),
+		Remediation:  remediationTemplate(config.Name, config.RemediationTemplate),
+		HTTPClient:   f.httpClient(config.Name, config.Certificate, config.SkipTLS, config.HTTP.merge(parent.HTTP)),
+		Format:       config.Format,
+		Actions:      messageActions(config.Name, config.Actions),
 	})
 }
 
@@ -480,6 +961,18 @@ func (f *TargetFactory) createWebhookClient(config Webhook, parent Webhook) targ
 		config.SkipExisting = parent.SkipExisting
 	}
 
+	if config.Concurrency == 0 {
+		config.Concurrency = parent.Concurrency
+	}
+
+	if config.NotificationTTL == 0 {
+		config.NotificationTTL = parent.NotificationTTL
+	}
+
+	if config.Payload == "" {
+		config.Payload = parent.Payload
+	}
+
 	if len(parent.Headers) > 0 {
 		headers := map[string]string{}
 		for header, value := range parent.Headers {
@@ -492,65 +985,1003 @@ func (f *TargetFactory) createWebhookClient(config Webhook, parent Webhook) targ
 		config.Headers = headers
 	}
 
-	log.Printf("[INFO] %s configured", config.Name)
+	if !config.Auth.Enabled() {
+		config.Auth = parent.Auth
+	}
+
+	if config.Signing.Secret == "" {
+		config.Signing = parent.Signing
+	}
+
+	client := f.httpClient(config.Name, config.Certificate, config.SkipTLS, config.HTTP.merge(parent.HTTP))
+
+	var auth *webhook.Auth
+	if config.Auth.Enabled() {
+		a, err := webhook.NewAuth(config.Auth.Header, config.Auth.Template, secret.New(config.Auth.Token), webhook.OAuth2{
+			TokenURL:     config.Auth.OAuth2.TokenURL,
+			ClientID:     config.Auth.OAuth2.ClientID,
+			ClientSecret: secret.New(config.Auth.OAuth2.ClientSecret),
+			Scopes:       config.Auth.OAuth2.Scopes,
+			Client:       client,
+		})
+		if err != nil {
+			logger.Errorf("%s: invalid auth.template, the target is disabled: %s", config.Name, err)
+			return nil
+		}
+
+		auth = a
+	}
+
+	var signer *webhook.Signer
+	if config.Signing.Secret != "" {
+		signer = webhook.NewSigner(secret.New(config.Signing.Secret), config.Signing.Header)
+	}
+
+	if !config.CloudEvents.Enabled {
+		config.CloudEvents = parent.CloudEvents
+	}
+
+	logger.Infof("%s configured", config.Name)
 
 	return webhook.NewClient(webhook.Options{
 		ClientOptions: target.ClientOptions{
 			Name:                  config.Name,
 			SkipExistingOnStartup: config.SkipExisting,
-			ResultFilter:          createResultFilter(config.Filter, config.MinimumPriority, config.Sources),
+			Concurrency:           config.Concurrency,
+			NotificationTTL:       config.NotificationTTL,
+			ResultFilter:          f.createResultFilter(config.Name, config.Filter, config.MinimumPriority, config.Sources),
+			ReportFilter:          createReprotFilter(config.Filter),
+			Sampler:               createSampler(config.Filter),
+			Grouper:               createGrouper(config.Filter),
+			Schedule:              createSchedule(config.Name, config.Filter),
+			Scrubber:              createScrubber(config.Name, config.Filter),
+			Backfill:              createBackfill(config.Name, config.Filter),
+		},
+		Host:         config.Host,
+		Headers:      secretHeaders(config.Headers),
+		CustomFields: f.withGlobalFields(config.CustomFields),
+		Payload:      config.Payload,
+		HTTPClient:   client,
+		Auth:         auth,
+		Signer:       signer,
+		CloudEvents:  cloudEventOptions(config.CloudEvents),
+	})
+}
+
+func (f *TargetFactory) createGrafanaClient(config Grafana, parent Grafana) target.Client {
+	if config.SecretRef != "" && f.secretClient != nil {
+		f.mapSecretValues(&config, config.SecretRef)
+	}
+
+	if config.Host == "" && parent.Host == "" {
+		return nil
+	} else if config.Host == "" {
+		config.Host = parent.Host
+	}
+
+	if config.Token == "" {
+		config.Token = parent.Token
+	}
+
+	if config.DashboardUID == "" {
+		config.DashboardUID = parent.DashboardUID
+	}
+
+	if config.Certificate == "" {
+		config.Certificate = parent.Certificate
+	}
+
+	if !config.SkipTLS {
+		config.SkipTLS = parent.SkipTLS
+	}
+
+	if config.MinimumPriority == "" {
+		config.MinimumPriority = parent.MinimumPriority
+	}
+
+	if !config.SkipExisting {
+		config.SkipExisting = parent.SkipExisting
+	}
+
+	if config.Concurrency == 0 {
+		config.Concurrency = parent.Concurrency
+	}
+
+	if config.NotificationTTL == 0 {
+		config.NotificationTTL = parent.NotificationTTL
+	}
+
+	if len(config.Tags) == 0 {
+		config.Tags = parent.Tags
+	}
+
+	if f.cluster != "" {
+		config.Tags = append(append(make([]string, 0, len(config.Tags)+1), config.Tags...), ClusterField+":"+f.cluster)
+	}
+
+	if len(parent.Headers) > 0 {
+		headers := map[string]string{}
+		for header, value := range parent.Headers {
+			headers[header] = value
+		}
+		for header, value := range config.Headers {
+			headers[header] = value
+		}
+
+		config.Headers = headers
+	}
+
+	logger.Infof("%s configured", config.Name)
+
+	return grafana.NewClient(grafana.Options{
+		ClientOptions: target.ClientOptions{
+			Name:                  config.Name,
+			SkipExistingOnStartup: config.SkipExisting,
+			Concurrency:           config.Concurrency,
+			NotificationTTL:       config.NotificationTTL,
+			ResultFilter:          f.createResultFilter(config.Name, config.Filter, config.MinimumPriority, config.Sources),
+			ReportFilter:          createReprotFilter(config.Filter),
+			Sampler:               createSampler(config.Filter),
+			Grouper:               createGrouper(config.Filter),
+			Schedule:              createSchedule(config.Name, config.Filter),
+			Scrubber:              createScrubber(config.Name, config.Filter),
+			Backfill:              createBackfill(config.Name, config.Filter),
+		},
+		Host:         strings.TrimSuffix(config.Host, "/"),
+		Token:        secret.New(config.Token),
+		DashboardUID: config.DashboardUID,
+		PanelID:      config.PanelID,
+		Tags:         config.Tags,
+		Headers:      secretHeaders(config.Headers),
+		HTTPClient:   f.httpClient(config.Name, config.Certificate, config.SkipTLS, config.HTTP.merge(parent.HTTP)),
+	})
+}
+
+func (f *TargetFactory) createS3Client(config S3, parent S3) target.Client {
+	if config.SecretRef != "" && f.secretClient != nil {
+		f.mapSecretValues(&config, config.SecretRef)
+	}
+
+	if config.Endpoint == "" && parent.Endpoint == "" {
+		return nil
+	} else if config.Endpoint == "" {
+		config.Endpoint = parent.Endpoint
+	}
+
+	if config.AccessKeyID == "" && parent.AccessKeyID == "" {
+		logger.Errorf("%s.AccessKeyID has not been declared", config.Name)
+		return nil
+	} else if config.AccessKeyID == "" {
+		config.AccessKeyID = parent.AccessKeyID
+	}
+
+	if config.SecretAccessKey == "" && parent.SecretAccessKey == "" {
+		logger.Errorf("%s.SecretAccessKey has not been declared", config.Name)
+		return nil
+	} else if config.SecretAccessKey == "" {
+		config.SecretAccessKey = parent.SecretAccessKey
+	}
+
+	if config.Region == "" && parent.Region == "" {
+		logger.Errorf("%s.Region has not been declared", config.Name)
+		return nil
+	} else if config.Region == "" {
+		config.Region = parent.Region
+	}
+
+	if config.Bucket == "" && parent.Bucket == "" {
+		logger.Errorf("%s.Bucket has not been declared", config.Name)
+		return nil
+	} else if config.Bucket == "" {
+		config.Bucket = parent.Bucket
+	}
+
+	if config.Prefix == "" && parent.Prefix == "" {
+		config.Prefix = "policy-reporter"
+	} else if config.Prefix == "" {
+		config.Prefix = parent.Prefix
+	}
+
+	if config.MinimumPriority == "" {
+		config.MinimumPriority = parent.MinimumPriority
+	}
+
+	if config.Format == "" {
+		config.Format = parent.Format
+	}
+
+	if config.FlushInterval == 0 {
+		config.FlushInterval = parent.FlushInterval
+	}
+
+	if config.FlushSize == 0 {
+		config.FlushSize = parent.FlushSize
+	}
+
+	if config.Encryption == "" {
+		config.Encryption = parent.Encryption
+	}
+
+	if config.KMSKeyID == "" {
+		config.KMSKeyID = parent.KMSKeyID
+	}
+
+	if !config.SkipExisting {
+		config.SkipExisting = parent.SkipExisting
+	}
+
+	if config.Concurrency == 0 {
+		config.Concurrency = parent.Concurrency
+	}
+
+	if config.NotificationTTL == 0 {
+		config.NotificationTTL = parent.NotificationTTL
+	}
+
+	s3Client := helper.NewS3Client(
+		config.AccessKeyID,
+		secret.New(config.SecretAccessKey),
+		config.Region,
+		config.Endpoint,
+		config.Bucket,
+		config.PathStyle,
+		helper.S3Encryption{Algorithm: config.Encryption, KMSKeyID: config.KMSKeyID},
+	)
+	if f.dryRun {
+		s3Client = helper.NewDryRunClient(config.Name)
+	}
+
+	logger.Infof("%s configured", config.Name)
+
+	return s3.NewClient(s3.Options{
+		ClientOptions: target.ClientOptions{
+			Name:                  config.Name,
+			SkipExistingOnStartup: config.SkipExisting,
+			Concurrency:           config.Concurrency,
+			NotificationTTL:       config.NotificationTTL,
+			ResultFilter:          f.createResultFilter(config.Name, config.Filter, config.MinimumPriority, config.Sources),
+			ReportFilter:          createReprotFilter(config.Filter),
+			Sampler:               createSampler(config.Filter),
+			Grouper:               createGrouper(config.Filter),
+			Schedule:              createSchedule(config.Name, config.Filter),
+			Scrubber:              createScrubber(config.Name, config.Filter),
+			Backfill:              createBackfill(config.Name, config.Filter),
+		},
+		S3:            s3Client,
+		CustomFields:  f.withGlobalFields(config.CustomFields),
+		Prefix:        config.Prefix,
+		Format:        config.Format,
+		FlushInterval: config.FlushInterval,
+		FlushSize:     config.FlushSize,
+	})
+}
+
+func (f *TargetFactory) createKinesisClient(config Kinesis, parent Kinesis) target.Client {
+	if config.SecretRef != "" && f.secretClient != nil {
+		f.mapSecretValues(&config, config.SecretRef)
+	}
+
+	if config.Endpoint == "" && parent.Endpoint == "" {
+		return nil
+	} else if config.Endpoint == "" {
+		config.Endpoint = parent.Endpoint
+	}
+
+	if config.AccessKeyID == "" && parent.AccessKeyID == "" {
+		logger.Errorf("%s.AccessKeyID has not been declared", config.Name)
+		return nil
+	} else if config.AccessKeyID == "" {
+		config.AccessKeyID = parent.AccessKeyID
+	}
+
+	if config.SecretAccessKey == "" && parent.SecretAccessKey == "" {
+		logger.Errorf("%s.SecretAccessKey has not been declared", config.Name)
+		return nil
+	} else if config.SecretAccessKey == "" {
+		config.SecretAccessKey = parent.SecretAccessKey
+	}
+
+	if config.Region == "" && parent.Region == "" {
+		logger.Errorf("%s.Region has not been declared", config.Name)
+		return nil
+	} else if config.Region == "" {
+		config.Region = parent.Region
+	}
+
+	if config.StreamName == "" && config.DeliveryStream == "" {
+		config.StreamName = parent.StreamName
+		config.DeliveryStream = parent.DeliveryStream
+	}
+
+	if config.StreamName == "" && config.DeliveryStream == "" {
+		logger.Errorf("%s.StreamName or %s.DeliveryStream has not been declared", config.Name, config.Name)
+		return nil
+	}
+
+	if config.PartitionKey == "" {
+		config.PartitionKey = parent.PartitionKey
+	}
+
+	if !config.Aggregation {
+		config.Aggregation = parent.Aggregation
+	}
+
+	if config.FlushInterval == 0 {
+		config.FlushInterval = parent.FlushInterval
+	}
+
+	if config.MinimumPriority == "" {
+		config.MinimumPriority = parent.MinimumPriority
+	}
+
+	if !config.SkipExisting {
+		config.SkipExisting = parent.SkipExisting
+	}
+
+	if config.Concurrency == 0 {
+		config.Concurrency = parent.Concurrency
+	}
+
+	if config.NotificationTTL == 0 {
+		config.NotificationTTL = parent.NotificationTTL
+	}
+
+	var kinesisClient helper.AWSClient
+	if config.DeliveryStream != "" {
+		kinesisClient = helper.NewFirehoseClient(
+			config.AccessKeyID,
+			secret.New(config.SecretAccessKey),
+			config.Region,
+			config.Endpoint,
+			config.DeliveryStream,
+		)
+	} else {
+		kinesisClient = helper.NewKinesisClient(
+			config.AccessKeyID,
+			secret.New(config.SecretAccessKey),
+			config.Region,
+			config.Endpoint,
+			config.StreamName,
+		)
+	}
+	if f.dryRun {
+		kinesisClient = helper.NewDryRunClient(config.Name)
+	}
+
+	if !config.CloudEvents.Enabled {
+		config.CloudEvents = parent.CloudEvents
+	}
+
+	logger.Infof("%s configured", config.Name)
+
+	return kinesis.NewClient(kinesis.Options{
+		ClientOptions: target.ClientOptions{
+			Name:                  config.Name,
+			SkipExistingOnStartup: config.SkipExisting,
+			Concurrency:           config.Concurrency,
+			NotificationTTL:       config.NotificationTTL,
+			ResultFilter:          f.createResultFilter(config.Name, config.Filter, config.MinimumPriority, config.Sources),
+			ReportFilter:          createReprotFilter(config.Filter),
+			Sampler:               createSampler(config.Filter),
+			Grouper:               createGrouper(config.Filter),
+			Schedule:              createSchedule(config.Name, config.Filter),
+			Scrubber:              createScrubber(config.Name, config.Filter),
+			Backfill:              createBackfill(config.Name, config.Filter),
+		},
+		CustomFields:  f.withGlobalFields(config.CustomFields),
+		Kinesis:       kinesisClient,
+		PartitionKey:  messageTemplate(config.Name, "partitionKey", config.PartitionKey),
+		Aggregate:     config.Aggregation,
+		Firehose:      config.DeliveryStream != "",
+		FlushInterval: config.FlushInterval,
+		CloudEvents:   cloudEventOptions(config.CloudEvents),
+	})
+}
+
+func (f *TargetFactory) createGitHubClient(config GitHub, parent GitHub) target.Client {
+	if config.SecretRef != "" && f.secretClient != nil {
+		f.mapSecretValues(&config, config.SecretRef)
+	}
+
+	if config.Token == "" && parent.Token == "" {
+		return nil
+	} else if config.Token == "" {
+		config.Token = parent.Token
+	}
+
+	if config.Host == "" {
+		config.Host = parent.Host
+	}
+
+	if config.Repository == "" {
+		config.Repository = parent.Repository
+	}
+
+	if config.RepositoryAnnotation == "" {
+		config.RepositoryAnnotation = parent.RepositoryAnnotation
+	}
+
+	if len(config.Labels) == 0 {
+		config.Labels = parent.Labels
+	}
+
+	if config.RemediationTemplate == "" {
+		config.RemediationTemplate = parent.RemediationTemplate
+	}
+
+	if config.Certificate == "" {
+		config.Certificate = parent.Certificate
+	}
+
+	if !config.SkipTLS {
+		config.SkipTLS = parent.SkipTLS
+	}
+
+	if config.MinimumPriority == "" {
+		config.MinimumPriority = parent.MinimumPriority
+	}
+
+	if !config.SkipExisting {
+		config.SkipExisting = parent.SkipExisting
+	}
+
+	if config.Concurrency == 0 {
+		config.Concurrency = parent.Concurrency
+	}
+
+	if config.NotificationTTL == 0 {
+		config.NotificationTTL = parent.NotificationTTL
+	}
+
+	logger.Infof("%s configured", config.Name)
+
+	return github.NewClient(github.Options{
+		ClientOptions: target.ClientOptions{
+			Name:                  config.Name,
+			SkipExistingOnStartup: config.SkipExisting,
+			Concurrency:           config.Concurrency,
+			NotificationTTL:       config.NotificationTTL,
+			ResultFilter:          f.createResultFilter(config.Name, config.Filter, config.MinimumPriority, config.Sources),
+			ReportFilter:          createReprotFilter(config.Filter),
+			Sampler:               createSampler(config.Filter),
+			Grouper:               createGrouper(config.Filter),
+			Schedule:              createSchedule(config.Name, config.Filter),
+			Scrubber:              createScrubber(config.Name, config.Filter),
+			Backfill:              createBackfill(config.Name, config.Filter),
+		},
+		Host:        strings.TrimSuffix(config.Host, "/"),
+		Token:       secret.New(config.Token),
+		Labels:      config.Labels,
+		Repository:  f.repositoryResolver(config.RepositoryAnnotation, config.Repository),
+		Remediation: remediationTemplate(config.Name, config.RemediationTemplate),
+		HTTPClient:  f.httpClient(config.Name, config.Certificate, config.SkipTLS, config.HTTP.merge(parent.HTTP)),
+	})
+}
+
+func (f *TargetFactory) createGitLabClient(config GitLab, parent GitLab) target.Client {
+	if config.SecretRef != "" && f.secretClient != nil {
+		f.mapSecretValues(&config, config.SecretRef)
+	}
+
+	if config.Token == "" && parent.Token == "" {
+		return nil
+	} else if config.Token == "" {
+		config.Token = parent.Token
+	}
+
+	if config.Host == "" {
+		config.Host = parent.Host
+	}
+
+	if config.Repository == "" {
+		config.Repository = parent.Repository
+	}
+
+	if config.RepositoryAnnotation == "" {
+		config.RepositoryAnnotation = parent.RepositoryAnnotation
+	}
+
+	if len(config.Labels) == 0 {
+		config.Labels = parent.Labels
+	}
+
+	if config.RemediationTemplate == "" {
+		config.RemediationTemplate = parent.RemediationTemplate
+	}
+
+	if config.Certificate == "" {
+		config.Certificate = parent.Certificate
+	}
+
+	if !config.SkipTLS {
+		config.SkipTLS = parent.SkipTLS
+	}
+
+	if config.MinimumPriority == "" {
+		config.MinimumPriority = parent.MinimumPriority
+	}
+
+	if !config.SkipExisting {
+		config.SkipExisting = parent.SkipExisting
+	}
+
+	if config.Concurrency == 0 {
+		config.Concurrency = parent.Concurrency
+	}
+
+	if config.NotificationTTL == 0 {
+		config.NotificationTTL = parent.NotificationTTL
+	}
+
+	logger.Infof("%s configured", config.Name)
+
+	return gitlab.NewClient(gitlab.Options{
+		ClientOptions: target.ClientOptions{
+			Name:                  config.Name,
+			SkipExistingOnStartup: config.SkipExisting,
+			Concurrency:           config.Concurrency,
+			NotificationTTL:       config.NotificationTTL,
+			ResultFilter:          f.createResultFilter(config.Name, config.Filter, config.MinimumPriority, config.Sources),
+			ReportFilter:          createReprotFilter(config.Filter),
+			Sampler:               createSampler(config.Filter),
+			Grouper:               createGrouper(config.Filter),
+			Schedule:              createSchedule(config.Name, config.Filter),
+			Scrubber:              createScrubber(config.Name, config.Filter),
+			Backfill:              createBackfill(config.Name, config.Filter),
+		},
+		Host:        strings.TrimSuffix(config.Host, "/"),
+		Token:       secret.New(config.Token),
+		Labels:      config.Labels,
+		Repository:  f.repositoryResolver(config.RepositoryAnnotation, config.Repository),
+		Remediation: remediationTemplate(config.Name, config.RemediationTemplate),
+		HTTPClient:  f.httpClient(config.Name, config.Certificate, config.SkipTLS, config.HTTP.merge(parent.HTTP)),
+	})
+}
+
+func (f *TargetFactory) createTelegramClient(config Telegram, parent Telegram) target.Client {
+	if config.SecretRef != "" && f.secretClient != nil {
+		f.mapSecretValues(&config, config.SecretRef)
+	}
+
+	if config.Token == "" {
+		config.Token = parent.Token
+	}
+
+	if config.Token == "" || config.ChatID == "" {
+		return nil
+	}
+
+	if config.Host == "" {
+		config.Host = parent.Host
+	}
+
+	if config.Certificate == "" {
+		config.Certificate = parent.Certificate
+	}
+
+	if !config.SkipTLS {
+		config.SkipTLS = parent.SkipTLS
+	}
+
+	if config.MinimumPriority == "" {
+		config.MinimumPriority = parent.MinimumPriority
+	}
+
+	if !config.SkipExisting {
+		config.SkipExisting = parent.SkipExisting
+	}
+
+	if config.Concurrency == 0 {
+		config.Concurrency = parent.Concurrency
+	}
+
+	if config.NotificationTTL == 0 {
+		config.NotificationTTL = parent.NotificationTTL
+	}
+
+	if config.RemediationTemplate == "" {
+		config.RemediationTemplate = parent.RemediationTemplate
+	}
+
+	logger.Infof("%s configured", config.Name)
+
+	return telegram.NewClient(telegram.Options{
+		ClientOptions: target.ClientOptions{
+			Name:                  config.Name,
+			SkipExistingOnStartup: config.SkipExisting,
+			Concurrency:           config.Concurrency,
+			NotificationTTL:       config.NotificationTTL,
+			ResultFilter:          f.createResultFilter(config.Name, config.Filter, config.MinimumPriority, config.Sources),
+			ReportFilter:          createReprotFilter(config.Filter),
+			Sampler:               createSampler(config.Filter),
+			Grouper:               createGrouper(config.Filter),
+			Schedule:              createSchedule(config.Name, config.Filter),
+			Scrubber:              createScrubber(config.Name, config.Filter),
+			Backfill:              createBackfill(config.Name, config.Filter),
+		},
+		Host:         config.Host,
+		Token:        secret.New(config.Token),
+		ChatID:       config.ChatID,
+		CustomFields: f.withGlobalFields(config.CustomFields),
+		Remediation:  remediationTemplate(config.Name, config.RemediationTemplate),
+		HTTPClient:   f.httpClient(config.Name, config.Certificate, config.SkipTLS, config.HTTP.merge(parent.HTTP)),
+	})
+}
+
+func (f *TargetFactory) createSecurityHubClient(config SecurityHub, parent SecurityHub) target.Client {
+	if config.SecretRef != "" && f.secretClient != nil {
+		f.mapSecretValues(&config, config.SecretRef)
+	}
+
+	if config.AccountID == "" {
+		config.AccountID = parent.AccountID
+	}
+
+	if config.AccountID == "" {
+		return nil
+	}
+
+	if config.Region == "" && parent.Region == "" {
+		logger.Errorf("%s.Region has not been declared", config.Name)
+		return nil
+	} else if config.Region == "" {
+		config.Region = parent.Region
+	}
+
+	if config.Endpoint == "" {
+		config.Endpoint = parent.Endpoint
+	}
+
+	if config.AccessKeyID == "" {
+		config.AccessKeyID = parent.AccessKeyID
+	}
+
+	if config.SecretAccessKey == "" {
+		config.SecretAccessKey = parent.SecretAccessKey
+	}
+
+	if config.ProductARN == "" {
+		config.ProductARN = parent.ProductARN
+	}
+
+	if config.ClusterARN == "" {
+		config.ClusterARN = parent.ClusterARN
+	}
+
+	if config.MinimumPriority == "" {
+		config.MinimumPriority = parent.MinimumPriority
+	}
+
+	if !config.SkipExisting {
+		config.SkipExisting = parent.SkipExisting
+	}
+
+	if config.Concurrency == 0 {
+		config.Concurrency = parent.Concurrency
+	}
+
+	if config.NotificationTTL == 0 {
+		config.NotificationTTL = parent.NotificationTTL
+	}
+
+	var api securityhub.API
+	if f.dryRun {
+		api = securityhub.NewDryRunAPI(config.Name)
+	} else if sh := helper.NewSecurityHubClient(config.AccessKeyID, secret.New(config.SecretAccessKey), config.Region, config.Endpoint); sh != nil {
+		api = sh
+	} else {
+		return nil
+	}
+
+	logger.Infof("%s configured", config.Name)
+
+	return securityhub.NewClient(securityhub.Options{
+		ClientOptions: target.ClientOptions{
+			Name:                  config.Name,
+			SkipExistingOnStartup: config.SkipExisting,
+			Concurrency:           config.Concurrency,
+			NotificationTTL:       config.NotificationTTL,
+			ResultFilter:          f.createResultFilter(config.Name, config.Filter, config.MinimumPriority, config.Sources),
+			ReportFilter:          createReprotFilter(config.Filter),
+			Sampler:               createSampler(config.Filter),
+			Grouper:               createGrouper(config.Filter),
+			Schedule:              createSchedule(config.Name, config.Filter),
+			Scrubber:              createScrubber(config.Name, config.Filter),
+			Backfill:              createBackfill(config.Name, config.Filter),
+		},
+		AccountID:    config.AccountID,
+		Region:       config.Region,
+		ProductARN:   config.ProductARN,
+		ClusterARN:   config.ClusterARN,
+		CustomFields: f.withGlobalFields(config.CustomFields),
+		SecurityHub:  api,
+	})
+}
+
+func (f *TargetFactory) createSecurityCenterClient(config SecurityCenter, parent SecurityCenter) target.Client {
+	if config.SecretRef != "" && f.secretClient != nil {
+		f.mapSecretValues(&config, config.SecretRef)
+	}
+
+	if config.OrganizationID == "" {
+		config.OrganizationID = parent.OrganizationID
+	}
+
+	if config.OrganizationID == "" {
+		return nil
+	}
+
+	if config.Host == "" {
+		config.Host = parent.Host
+	}
+
+	if config.Source == "" && config.SourceName == "" {
+		config.Source = parent.Source
+		config.SourceName = parent.SourceName
+	}
+
+	if config.ProjectID == "" {
+		config.ProjectID = parent.ProjectID
+	}
+
+	if config.Location == "" {
+		config.Location = parent.Location
+	}
+
+	if config.Cluster == "" {
+		config.Cluster = parent.Cluster
+	}
+
+	if config.Credentials == "" {
+		config.Credentials = parent.Credentials
+	}
+
+	if config.MinimumPriority == "" {
+		config.MinimumPriority = parent.MinimumPriority
+	}
+
+	if !config.SkipExisting {
+		config.SkipExisting = parent.SkipExisting
+	}
+
+	if config.Concurrency == 0 {
+		config.Concurrency = parent.Concurrency
+	}
+
+	if config.NotificationTTL == 0 {
+		config.NotificationTTL = parent.NotificationTTL
+	}
+
+	var tokens oauth2.TokenSource
+	if !f.dryRun {
+		var err error
+		if tokens, err = scc.NewTokenSource(secret.New(config.Credentials)); err != nil {
+			logger.Errorf("%s: %s", config.Name, err)
+			return nil
+		}
+	} else if config.Source == "" {
+		config.Source = fmt.Sprintf("organizations/%s/sources/dry-run", config.OrganizationID)
+	}
+
+	logger.Infof("%s configured", config.Name)
+
+	return scc.NewClient(scc.Options{
+		ClientOptions: target.ClientOptions{
+			Name:                  config.Name,
+			SkipExistingOnStartup: config.SkipExisting,
+			Concurrency:           config.Concurrency,
+			NotificationTTL:       config.NotificationTTL,
+			ResultFilter:          f.createResultFilter(config.Name, config.Filter, config.MinimumPriority, config.Sources),
+			ReportFilter:          createReprotFilter(config.Filter),
+			Sampler:               createSampler(config.Filter),
+			Grouper:               createGrouper(config.Filter),
+			Schedule:              createSchedule(config.Name, config.Filter),
+			Scrubber:              createScrubber(config.Name, config.Filter),
+			Backfill:              createBackfill(config.Name, config.Filter),
+		},
+		Host:           config.Host,
+		OrganizationID: config.OrganizationID,
+		Source:         config.Source,
+		SourceName:     config.SourceName,
+		Cluster:        scc.Cluster{ProjectID: config.ProjectID, Location: config.Location, Name: config.Cluster},
+		CustomFields:   f.withGlobalFields(config.CustomFields),
+		TokenSource:    tokens,
+		HTTPClient:     f.httpClient(config.Name, "", false, config.HTTP.merge(parent.HTTP)),
+	})
+}
+
+func (f *TargetFactory) createDefenderClient(config Defender, parent Defender) target.Client {
+	if config.SecretRef != "" && f.secretClient != nil {
+		f.mapSecretValues(&config, config.SecretRef)
+	}
+
+	if config.SubscriptionID == "" {
+		config.SubscriptionID = parent.SubscriptionID
+	}
+
+	if config.SubscriptionID == "" {
+		return nil
+	}
+
+	if config.Host == "" {
+		config.Host = parent.Host
+	}
+
+	if config.ResourceID == "" {
+		config.ResourceID = parent.ResourceID
+	}
+
+	if config.TenantID == "" {
+		config.TenantID = parent.TenantID
+	}
+
+	if config.ClientID == "" {
+		config.ClientID = parent.ClientID
+	}
+
+	if config.ClientSecret == "" {
+		config.ClientSecret = parent.ClientSecret
+	}
+
+	if config.MinimumPriority == "" {
+		config.MinimumPriority = parent.MinimumPriority
+	}
+
+	if !config.SkipExisting {
+		config.SkipExisting = parent.SkipExisting
+	}
+
+	if config.Concurrency == 0 {
+		config.Concurrency = parent.Concurrency
+	}
+
+	if config.NotificationTTL == 0 {
+		config.NotificationTTL = parent.NotificationTTL
+	}
+
+	var tokens oauth2.TokenSource
+	if !f.dryRun {
+		var err error
+		if tokens, err = defender.NewTokenSource(config.TenantID, config.ClientID, secret.New(config.ClientSecret)); err != nil {
+			logger.Errorf("%s: %s", config.Name, err)
+			return nil
+		}
+	}
+
+	logger.Infof("%s configured", config.Name)
+
+	return defender.NewClient(defender.Options{
+		ClientOptions: target.ClientOptions{
+			Name:                  config.Name,
+			SkipExistingOnStartup: config.SkipExisting,
+			Concurrency:           config.Concurrency,
+			NotificationTTL:       config.NotificationTTL,
+			ResultFilter:          f.createResultFilter(config.Name, config.Filter, config.MinimumPriority, config.Sources),
+			ReportFilter:          createReprotFilter(config.Filter),
+			Sampler:               createSampler(config.Filter),
+			Grouper:               createGrouper(config.Filter),
+			Schedule:              createSchedule(config.Name, config.Filter),
+			Scrubber:              createScrubber(config.Name, config.Filter),
+			Backfill:              createBackfill(config.Name, config.Filter),
+		},
+		Host:           config.Host,
+		SubscriptionID: config.SubscriptionID,
+		ResourceID:     config.ResourceID,
+		CustomFields:   f.withGlobalFields(config.CustomFields),
+		TokenSource:    tokens,
+		HTTPClient:     f.httpClient(config.Name, "", false, config.HTTP.merge(parent.HTTP)),
+	})
+}
+
+func (f *TargetFactory) createDefectDojoClient(config DefectDojo, parent DefectDojo) target.Client {
+	if config.SecretRef != "" && f.secretClient != nil {
+		f.mapSecretValues(&config, config.SecretRef)
+	}
+
+	if config.Host == "" {
+		config.Host = parent.Host
+	}
+
+	if config.APIKey == "" {
+		config.APIKey = parent.APIKey
+	}
+
+	if config.Product == "" {
+		config.Product = parent.Product
+	}
+
+	if config.Host == "" || config.APIKey == "" || config.Product == "" {
+		return nil
+	}
+
+	if config.Engagement == "" {
+		config.Engagement = parent.Engagement
+	}
+
+	if config.TestType == "" {
+		config.TestType = parent.TestType
+	}
+
+	if config.RemediationTemplate == "" {
+		config.RemediationTemplate = parent.RemediationTemplate
+	}
+
+	if config.Certificate == "" {
+		config.Certificate = parent.Certificate
+	}
+
+	if !config.SkipTLS {
+		config.SkipTLS = parent.SkipTLS
+	}
+
+	if config.MinimumPriority == "" {
+		config.MinimumPriority = parent.MinimumPriority
+	}
+
+	if !config.SkipExisting {
+		config.SkipExisting = parent.SkipExisting
+	}
+
+	if config.Concurrency == 0 {
+		config.Concurrency = parent.Concurrency
+	}
+
+	if config.NotificationTTL == 0 {
+		config.NotificationTTL = parent.NotificationTTL
+	}
+
+	logger.Infof("%s configured", config.Name)
+
+	return defectdojo.NewClient(defectdojo.Options{
+		ClientOptions: target.ClientOptions{
+			Name:                  config.Name,
+			SkipExistingOnStartup: config.SkipExisting,
+			Concurrency:           config.Concurrency,
+			NotificationTTL:       config.NotificationTTL,
+			ResultFilter:          f.createResultFilter(config.Name, config.Filter, config.MinimumPriority, config.Sources),
 			ReportFilter:          createReprotFilter(config.Filter),
+			Sampler:               createSampler(config.Filter),
+			Grouper:               createGrouper(config.Filter),
+			Schedule:              createSchedule(config.Name, config.Filter),
+			Scrubber:              createScrubber(config.Name, config.Filter),
+			Backfill:              createBackfill(config.Name, config.Filter),
 		},
 		Host:         config.Host,
-		Headers:      config.Headers,
-		CustomFields: config.CustomFields,
-		HTTPClient:   http.NewClient(config.Certificate, config.SkipTLS),
+		APIKey:       secret.New(config.APIKey),
+		Product:      config.Product,
+		Engagement:   config.Engagement,
+		TestType:     config.TestType,
+		CustomFields: f.withGlobalFields(config.CustomFields),
+		Remediation:  remediationTemplate(config.Name, config.RemediationTemplate),
+		HTTPClient:   f.httpClient(config.Name, config.Certificate, config.SkipTLS, config.HTTP.merge(parent.HTTP)),
 	})
 }
 
-func (f *TargetFactory) createS3Client(config S3, parent S3) target.Client {
+func (f *TargetFactory) createAlertmanagerClient(config Alertmanager, parent Alertmanager) target.Client {
 	if config.SecretRef != "" && f.secretClient != nil {
 		f.mapSecretValues(&config, config.SecretRef)
 	}
 
-	if config.Endpoint == "" && parent.Endpoint == "" {
-		return nil
-	} else if config.Endpoint == "" {
-		config.Endpoint = parent.Endpoint
-	}
-
-	if config.AccessKeyID == "" && parent.AccessKeyID == "" {
-		log.Printf("[ERROR] %s.AccessKeyID has not been declared", config.Name)
+	if config.Host == "" && parent.Host == "" {
 		return nil
-	} else if config.AccessKeyID == "" {
-		config.AccessKeyID = parent.AccessKeyID
+	} else if config.Host == "" {
+		config.Host = parent.Host
 	}
 
-	if config.SecretAccessKey == "" && parent.SecretAccessKey == "" {
-		log.Printf("[ERROR] %s.SecretAccessKey has not been declared", config.Name)
-		return nil
-	} else if config.SecretAccessKey == "" {
-		config.SecretAccessKey = parent.SecretAccessKey
+	if config.Token == "" {
+		config.Token = parent.Token
 	}
 
-	if config.Region == "" && parent.Region == "" {
-		log.Printf("[ERROR] %s.Region has not been declared", config.Name)
-		return nil
-	} else if config.Region == "" {
-		config.Region = parent.Region
+	if config.Expiry == 0 {
+		config.Expiry = parent.Expiry
 	}
 
-	if config.Bucket == "" && parent.Bucket == "" {
-		log.Printf("[ERROR] %s.Bucket has not been declared", config.Name)
-		return nil
-	} else if config.Bucket == "" {
-		config.Bucket = parent.Bucket
+	if config.Certificate == "" {
+		config.Certificate = parent.Certificate
 	}
 
-	if config.Prefix == "" && parent.Prefix == "" {
-		config.Prefix = "policy-reporter"
-	} else if config.Prefix == "" {
-		config.Prefix = parent.Prefix
+	if !config.SkipTLS {
+		config.SkipTLS = parent.SkipTLS
 	}
 
 	if config.MinimumPriority == "" {
@@ -561,103 +1992,148 @@ func (f *TargetFactory) createS3Client(config S3, parent S3) target.Client {
 		config.SkipExisting = parent.SkipExisting
 	}
 
-	s3Client := helper.NewS3Client(
-		config.AccessKeyID,
-		config.SecretAccessKey,
-		config.Region,
-		config.Endpoint,
-		config.Bucket,
-		config.PathStyle,
-	)
+	if config.Concurrency == 0 {
+		config.Concurrency = parent.Concurrency
+	}
 
-	log.Printf("[INFO] %s configured", config.Name)
+	if config.NotificationTTL == 0 {
+		config.NotificationTTL = parent.NotificationTTL
+	}
 
-	return s3.NewClient(s3.Options{
+	if len(parent.Headers) > 0 {
+		headers := map[string]string{}
+		for header, value := range parent.Headers {
+			headers[header] = value
+		}
+		for header, value := range config.Headers {
+			headers[header] = value
+		}
+
+		config.Headers = headers
+	}
+
+	logger.Infof("%s configured", config.Name)
+
+	return alertmanager.NewClient(alertmanager.Options{
 		ClientOptions: target.ClientOptions{
 			Name:                  config.Name,
 			SkipExistingOnStartup: config.SkipExisting,
-			ResultFilter:          createResultFilter(config.Filter, config.MinimumPriority, config.Sources),
+			Concurrency:           config.Concurrency,
+			NotificationTTL:       config.NotificationTTL,
+			ResultFilter:          f.createResultFilter(config.Name, config.Filter, config.MinimumPriority, config.Sources),
 			ReportFilter:          createReprotFilter(config.Filter),
+			Sampler:               createSampler(config.Filter),
+			Grouper:               createGrouper(config.Filter),
+			Schedule:              createSchedule(config.Name, config.Filter),
+			Scrubber:              createScrubber(config.Name, config.Filter),
+			Backfill:              createBackfill(config.Name, config.Filter),
 		},
-		S3:           s3Client,
-		CustomFields: config.CustomFields,
-		Prefix:       config.Prefix,
+		Host:         config.Host,
+		Token:        secret.New(config.Token),
+		Expiry:       config.Expiry,
+		CustomFields: f.withGlobalFields(config.CustomFields),
+		Headers:      secretHeaders(config.Headers),
+		HTTPClient:   f.httpClient(config.Name, config.Certificate, config.SkipTLS, config.HTTP.merge(parent.HTTP)),
 	})
 }
 
-func (f *TargetFactory) createKinesisClient(config Kinesis, parent Kinesis) target.Client {
-	if config.SecretRef != "" && f.secretClient != nil {
-		f.mapSecretValues(&config, config.SecretRef)
-	}
-
-	if config.Endpoint == "" && parent.Endpoint == "" {
+func (f *TargetFactory) createExecClient(config Exec, parent Exec) target.Client {
+	if config.Command == "" && parent.Command == "" {
 		return nil
-	} else if config.Endpoint == "" {
-		config.Endpoint = parent.Endpoint
+	} else if config.Command == "" {
+		config.Command = parent.Command
+		config.Args = parent.Args
 	}
 
-	if config.AccessKeyID == "" && parent.AccessKeyID == "" {
-		log.Printf("[ERROR] %s.AccessKeyID has not been declared", config.Name)
-		return nil
-	} else if config.AccessKeyID == "" {
-		config.AccessKeyID = parent.AccessKeyID
+	if config.Timeout == 0 {
+		config.Timeout = parent.Timeout
 	}
 
-	if config.SecretAccessKey == "" && parent.SecretAccessKey == "" {
-		log.Printf("[ERROR] %s.SecretAccessKey has not been declared", config.Name)
-		return nil
-	} else if config.SecretAccessKey == "" {
-		config.SecretAccessKey = parent.SecretAccessKey
+	if config.MinimumPriority == "" {
+		config.MinimumPriority = parent.MinimumPriority
 	}
 
-	if config.Region == "" && parent.Region == "" {
-		log.Printf("[ERROR] %s.Region has not been declared", config.Name)
-		return nil
-	} else if config.Region == "" {
-		config.Region = parent.Region
+	if !config.SkipExisting {
+		config.SkipExisting = parent.SkipExisting
 	}
 
-	if config.StreamName == "" && parent.StreamName == "" {
-		log.Printf("[ERROR] %s.StreamName has not been declared", config.Name)
-		return nil
-	} else if config.StreamName == "" {
-		config.StreamName = parent.StreamName
+	if config.Concurrency == 0 {
+		config.Concurrency = parent.Concurrency
 	}
 
-	if config.MinimumPriority == "" {
-		config.MinimumPriority = parent.MinimumPriority
+	if config.NotificationTTL == 0 {
+		config.NotificationTTL = parent.NotificationTTL
 	}
 
-	if !config.SkipExisting {
-		config.SkipExisting = parent.SkipExisting
+	if len(parent.Env) > 0 {
+		env := map[string]string{}
+		for name, value := range parent.Env {
+			env[name] = value
+		}
+		for name, value := range config.Env {
+			env[name] = value
+		}
+
+		config.Env = env
 	}
 
-	kinesisClient := helper.NewKinesisClient(
-		config.AccessKeyID,
-		config.SecretAccessKey,
-		config.Region,
-		config.Endpoint,
-		config.StreamName,
-	)
+	var runner exec.Runner
+	if f.dryRun {
+		runner = exec.NewDryRunRunner(config.Name)
+	}
 
-	log.Printf("[INFO] %s configured", config.Name)
+	logger.Infof("%s configured", config.Name)
 
-	return kinesis.NewClient(kinesis.Options{
+	return exec.NewClient(exec.Options{
 		ClientOptions: target.ClientOptions{
 			Name:                  config.Name,
 			SkipExistingOnStartup: config.SkipExisting,
-			ResultFilter:          createResultFilter(config.Filter, config.MinimumPriority, config.Sources),
+			Concurrency:           config.Concurrency,
+			NotificationTTL:       config.NotificationTTL,
+			ResultFilter:          f.createResultFilter(config.Name, config.Filter, config.MinimumPriority, config.Sources),
 			ReportFilter:          createReprotFilter(config.Filter),
+			Sampler:               createSampler(config.Filter),
+			Grouper:               createGrouper(config.Filter),
+			Schedule:              createSchedule(config.Name, config.Filter),
+			Scrubber:              createScrubber(config.Name, config.Filter),
+			Backfill:              createBackfill(config.Name, config.Filter),
 		},
-		CustomFields: config.CustomFields,
-		Kinesis:      kinesisClient,
+		Command:      config.Command,
+		Args:         config.Args,
+		Env:          config.Env,
+		CustomFields: f.withGlobalFields(config.CustomFields),
+		Timeout:      config.Timeout,
+		Runner:       runner,
 	})
 }
 
+// repositoryResolver resolves the repository from the annotation, the annotation defaults to issues.RepositoryAnnotation
+func (f *TargetFactory) repositoryResolver(annotation, repository string) *issues.RepositoryResolver {
+	if annotation == "" {
+		annotation = issues.RepositoryAnnotation
+	}
+
+	return issues.NewRepositoryResolver(f.metadata, annotation, repository)
+}
+
+// secretHeaders of a target, header values like API keys are redacted as well
+func secretHeaders(headers map[string]string) map[string]secret.String {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	values := make(map[string]secret.String, len(headers))
+	for header, value := range headers {
+		values[header] = secret.New(value)
+	}
+
+	return values
+}
+
 func (f *TargetFactory) mapSecretValues(config any, ref string) {
 	values, err := f.secretClient.Get(context.Background(), ref)
 	if err != nil {
-		log.Printf("[WARNING] failed to get secret reference: %s\n", err)
+		logger.Warnf("failed to get secret reference: %s", err)
 		return
 	}
 
@@ -671,6 +2147,9 @@ func (f *TargetFactory) mapSecretValues(config any, ref string) {
 		if values.Webhook != "" {
 			c.Webhook = values.Webhook
 		}
+		if values.Token != "" && c.Channel != "" {
+			c.Token = values.Token
+		}
 
 	case *Discord:
 		if values.Webhook != "" {
@@ -682,6 +2161,11 @@ func (f *TargetFactory) mapSecretValues(config any, ref string) {
 			c.Webhook = values.Webhook
 		}
 
+	case *GoogleChat:
+		if values.Webhook != "" {
+			c.Webhook = values.Webhook
+		}
+
 	case *Elasticsearch:
 		if values.Host != "" {
 			c.Host = values.Host
@@ -720,17 +2204,224 @@ func (f *TargetFactory) mapSecretValues(config any, ref string) {
 
 			c.Headers["Authorization"] = values.Token
 		}
+		if values.SigningSecret != "" {
+			c.Signing.Secret = values.SigningSecret
+		}
+		if values.ClientSecret != "" {
+			c.Auth.OAuth2.ClientSecret = values.ClientSecret
+		}
+
+	case *Grafana:
+		if values.Host != "" {
+			c.Host = values.Host
+		}
+		if values.Token != "" {
+			c.Token = values.Token
+		}
+
+	case *GitHub:
+		if values.Host != "" {
+			c.Host = values.Host
+		}
+		if values.Token != "" {
+			c.Token = values.Token
+		}
+
+	case *GitLab:
+		if values.Host != "" {
+			c.Host = values.Host
+		}
+		if values.Token != "" {
+			c.Token = values.Token
+		}
+
+	case *Telegram:
+		if values.Host != "" {
+			c.Host = values.Host
+		}
+		if values.Token != "" {
+			c.Token = values.Token
+		}
+
+	case *SecurityHub:
+		if values.AccessKeyID != "" {
+			c.AccessKeyID = values.AccessKeyID
+		}
+		if values.SecretAccessKey != "" {
+			c.SecretAccessKey = values.SecretAccessKey
+		}
+
+	case *SecurityCenter:
+		if values.Credentials != "" {
+			c.Credentials = values.Credentials
+		}
+
+	case *Defender:
+		if values.ClientSecret != "" {
+			c.ClientSecret = values.ClientSecret
+		}
+
+	case *DefectDojo:
+		if values.Host != "" {
+			c.Host = values.Host
+		}
+		if values.Token != "" {
+			c.APIKey = values.Token
+		}
+
+	case *Alertmanager:
+		if values.Host != "" {
+			c.Host = values.Host
+		}
+		if values.Token != "" {
+			c.Token = values.Token
+		}
+	}
+}
+
+// httpClient of the target, in dry run mode the requests are logged instead of sent.
+// Unset options are inherited from the global httpClient configuration
+func (f *TargetFactory) httpClient(name, certificate string, skipTLS bool, options HTTPClient) http.Client {
+	if f.dryRun {
+		return http.NewDryRunClient(name)
+	}
+
+	options = options.merge(f.http)
+
+	var client http.Client = http.NewClientWithOptions(certificate, skipTLS, http.ClientOptions{
+		Timeout:             options.Timeout,
+		MaxIdleConns:        options.MaxIdleConns,
+		MaxIdleConnsPerHost: options.MaxIdleConnsPerHost,
+		IdleConnTimeout:     options.IdleConnTimeout,
+		KeepAlive:           options.KeepAlive,
+		DisableKeepAlives:   options.DisableKeepAlives,
+	})
+
+	if f.connections != nil {
+		client = http.WithConnectionTrace(name, client, f.connections)
+	}
+
+	return client
+}
+
+// withGlobalFields adds the cluster name and the global custom fields, fields of the target take precedence
+func (f *TargetFactory) withGlobalFields(fields map[string]string) map[string]string {
+	if f.cluster == "" && len(f.customFields) == 0 {
+		return fields
 	}
+
+	result := make(map[string]string, len(fields)+len(f.customFields)+1)
+	if f.cluster != "" {
+		result[ClusterField] = f.cluster
+	}
+	for key, value := range f.customFields {
+		result[key] = value
+	}
+	for key, value := range fields {
+		result[key] = value
+	}
+
+	return result
 }
 
-func createResultFilter(filter TargetFilter, minimumPriority string, sources []string) *report.ResultFilter {
-	return target.NewResultFilter(
+func (f *TargetFactory) createResultFilter(name string, filter TargetFilter, minimumPriority string, sources []string) *report.ResultFilter {
+	rf := target.NewResultFilter(
 		ToRuleSet(filter.Namespaces),
 		ToRuleSet(filter.Priorities),
 		ToRuleSet(filter.Policies),
 		minimumPriority,
 		sources,
 	)
+
+	if f.floor != nil {
+		rf.AddValidation(f.floor.Validate)
+	}
+
+	if f.age != nil {
+		rf.AddValidation(f.age.Validate)
+	}
+
+	if f.exclusions != nil {
+		rf.AddReportValidation(f.exclusions)
+	}
+
+	if f.terminating {
+		rf.AddValidation(func(r v1alpha2.PolicyReportResult) bool {
+			return !report.Terminating(f.metadata, r.GetResource())
+		})
+	}
+
+	if f.orphaned {
+		rf.AddValidation(notOrphaned)
+	}
+
+	if f.errorRouting.Enabled {
+		addErrorRouting(rf, f.errorRouting, name)
+	}
+
+	addLabelFilters(rf, f.metadata, ValueFilter{}, filter.ResourceLabels, filter.ResourceAnnotations)
+	addTeamFilter(rf, filter.Teams)
+	addExpressionFilter(rf, filter.Expression)
+
+	return rf
+}
+
+// addErrorRouting restricts the operations targets to results with status error,
+// the exclusive routing removes them from all other targets
+func addErrorRouting(rf *report.ResultFilter, routing ErrorRouting, name string) {
+	ops := false
+	for _, t := range routing.Targets {
+		if t == name {
+			ops = true
+			break
+		}
+	}
+
+	if ops {
+		rf.AddValidation(func(r v1alpha2.PolicyReportResult) bool {
+			return r.Result == v1alpha2.StatusError
+		})
+	} else if routing.Exclusive {
+		rf.AddValidation(func(r v1alpha2.PolicyReportResult) bool {
+			return r.Result != v1alpha2.StatusError
+		})
+	}
+}
+
+// notOrphaned skips results flagged as orphaned
+func notOrphaned(r v1alpha2.PolicyReportResult) bool {
+	return !report.Orphaned(r)
+}
+
+// messageTemplate ignores an invalid template, the default of the message is used instead
+func messageTemplate(name, option, text string) *target.Template {
+	tmpl, err := target.ParseTemplate(option, text)
+	if err != nil {
+		logger.Errorf("%s: invalid %s, using the default: %s", name, option, err)
+	}
+
+	return tmpl
+}
+
+// messageActions parses the URL templates of the actions
+func messageActions(name string, config []Action) []target.Action {
+	actions := make([]target.Action, 0, len(config))
+	for _, action := range config {
+		actions = append(actions, target.Action{Text: action.Text, URL: messageTemplate(name, "actions", action.URL)})
+	}
+
+	return actions
+}
+
+// remediationTemplate falls back to the default template if the configured one is invalid
+func remediationTemplate(name, text string) *target.RemediationTemplate {
+	tmpl, err := target.ParseRemediationTemplate(text)
+	if err != nil {
+		logger.Errorf("%s: invalid remediationTemplate, using the default: %s", name, err)
+		tmpl, _ = target.ParseRemediationTemplate("")
+	}
+
+	return tmpl
 }
 
 func createReprotFilter(filter TargetFilter) *report.ReportFilter {
@@ -739,6 +2430,77 @@ func createReprotFilter(filter TargetFilter) *report.ReportFilter {
 	)
 }
 
+// cloudEventOptions of the envelopes, nil if disabled
+func cloudEventOptions(config CloudEvents) *cloudevents.Options {
+	if !config.Enabled {
+		return nil
+	}
+
+	return &cloudevents.Options{Mode: config.Mode, Source: config.Source}
+}
+
+func createSampler(filter TargetFilter) *target.Sampler {
+	rules := make([]target.SamplingRule, 0, len(filter.Sampling))
+	for _, rule := range filter.Sampling {
+		rules = append(rules, target.SamplingRule{Policies: rule.Policies, Limit: rule.Limit, Window: rule.Window})
+	}
+
+	return target.NewSampler(rules)
+}
+
+func createGrouper(filter TargetFilter) *target.Grouper {
+	return target.NewGrouper(filter.GroupBy, filter.GroupWindow)
+}
+
+func createSchedule(name string, filter TargetFilter) *target.Schedule {
+	windows := make([]maintenance.QuietHours, 0, len(filter.DeliveryWindows))
+	for i, w := range filter.DeliveryWindows {
+		parsed, err := maintenance.ParseQuietHours(w.Days, w.From, w.To, w.Timezone)
+		if err != nil {
+			logger.Errorf("%s: invalid filter.deliveryWindows[%d]: %s", name, i, err)
+			continue
+		}
+
+		windows = append(windows, parsed)
+	}
+
+	return target.NewSchedule(name, windows)
+}
+
+// createScrubber skips invalid rules, so a broken rule doesn't block the delivery of the other rules
+func createScrubber(name string, filter TargetFilter) *target.Scrubber {
+	rules := make([]target.ScrubRule, 0, len(filter.Scrub))
+	for i, r := range filter.Scrub {
+		pattern, err := regexp.Compile(r.Pattern)
+		if err != nil || r.Pattern == "" {
+			logger.Errorf("%s: invalid filter.scrub[%d].pattern '%s'", name, i, r.Pattern)
+			continue
+		}
+
+		rule := target.ScrubRule{Pattern: pattern, Replacement: r.Replacement, Properties: r.Properties}
+		if r.Condition != "" {
+			if rule.Condition, err = expression.Compile(r.Condition); err != nil {
+				logger.Errorf("%s: invalid filter.scrub[%d].condition: %s", name, i, err)
+				continue
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return target.NewScrubber(rules)
+}
+
+// createBackfill logs an invalid sendExistingResults option and keeps the skipExistingOnStartup behavior
+func createBackfill(name string, filter TargetFilter) *target.Backfill {
+	backfill, err := target.ParseBackfill(filter.SendExistingResults)
+	if err != nil {
+		logger.Errorf("%s: invalid filter.sendExistingResults: %s", name, err)
+	}
+
+	return backfill
+}
+
 func NewTargetFactory(namespace string, secretClient secrets.Client) *TargetFactory {
 	return &TargetFactory{namespace: namespace, secretClient: secretClient}
 }
@@ -3,14 +3,18 @@ package config_test
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
 
 	"github.com/kyverno/policy-reporter/pkg/config"
 	"github.com/kyverno/policy-reporter/pkg/kubernetes/secrets"
+	"github.com/kyverno/policy-reporter/pkg/secret"
+	"github.com/kyverno/policy-reporter/pkg/target"
 )
 
 const secretName = "secret-values"
@@ -60,6 +64,12 @@ func Test_ResolveTarget(t *testing.T) {
 			t.Error("Expected Client, got nil")
 		}
 	})
+	t.Run("GoogleChat", func(t *testing.T) {
+		clients := factory.GoogleChatClients(testConfig.GoogleChat)
+		if len(clients) != 2 {
+			t.Errorf("Expected 2 Client, got %d clients", len(clients))
+		}
+	})
 	t.Run("Teams", func(t *testing.T) {
 		clients := factory.TeamsClients(testConfig.Teams)
 		if len(clients) != 2 {
@@ -72,6 +82,12 @@ func Test_ResolveTarget(t *testing.T) {
 			t.Errorf("Expected 2 Client, got %d clients", len(clients))
 		}
 	})
+	t.Run("Grafana", func(t *testing.T) {
+		clients := factory.GrafanaClients(testConfig.Grafana)
+		if len(clients) != 2 {
+			t.Errorf("Expected 2 Client, got %d clients", len(clients))
+		}
+	})
 	t.Run("S3", func(t *testing.T) {
 		clients := factory.S3Clients(testConfig.S3)
 		if len(clients) != 2 {
@@ -84,6 +100,106 @@ func Test_ResolveTarget(t *testing.T) {
 			t.Errorf("Expected 2 Client, got %d clients", len(clients))
 		}
 	})
+	t.Run("HTTPClient", func(t *testing.T) {
+		clients := factory.WebhookClients(config.Webhook{
+			Host:     "http://localhost:8080",
+			HTTP:     config.HTTPClient{Timeout: 5 * time.Second},
+			Channels: []config.Webhook{{Host: "http://localhost:8081"}, {Host: "http://localhost:8082", HTTP: config.HTTPClient{Timeout: time.Second}}},
+		})
+
+		for i, expected := range []time.Duration{5 * time.Second, 5 * time.Second, time.Second} {
+			client := reflect.ValueOf(clients[i]).Elem().FieldByName("client").Elem().Elem()
+			if timeout := time.Duration(client.FieldByName("Timeout").Int()); timeout != expected {
+				t.Errorf("Expected timeout %s for client %d, got %s", expected, i, timeout)
+			}
+		}
+	})
+	t.Run("Telegram", func(t *testing.T) {
+		clients := factory.TelegramClients(config.Telegram{
+			Token:    "123:token",
+			ChatID:   "-100123",
+			Channels: []config.Telegram{{ChatID: "-100456"}, {}},
+		})
+		if len(clients) != 2 {
+			t.Errorf("Expected 2 Client, got %d clients", len(clients))
+		}
+	})
+	t.Run("SecurityHub", func(t *testing.T) {
+		clients := factory.SecurityHubClients(config.SecurityHub{
+			AccountID: "123456789012",
+			Region:    "eu-central-1",
+			Channels:  []config.SecurityHub{{ProductARN: "arn:aws:securityhub:eu-central-1::product/custom/default"}},
+		})
+		if len(clients) != 2 {
+			t.Errorf("Expected 2 Client, got %d clients", len(clients))
+		}
+		if _, ok := clients[0].(target.ResolvingClient); !ok {
+			t.Error("Expected SecurityHub to resolve findings")
+		}
+	})
+	t.Run("SecurityCenter", func(t *testing.T) {
+		clients := factory.SecurityCenterClients(config.SecurityCenter{
+			OrganizationID: "123",
+			Source:         "organizations/123/sources/456",
+			Channels:       []config.SecurityCenter{{SourceName: "Policy Reporter Channel"}},
+		})
+		if len(clients) != 2 {
+			t.Errorf("Expected 2 Client, got %d clients", len(clients))
+		}
+		if _, ok := clients[0].(target.ResolvingClient); !ok {
+			t.Error("Expected SecurityCenter to resolve findings")
+		}
+	})
+	t.Run("Defender", func(t *testing.T) {
+		clients := factory.DefenderClients(config.Defender{
+			SubscriptionID: "00000000-0000-0000-0000-000000000001",
+			TenantID:       "00000000-0000-0000-0000-000000000002",
+			ClientID:       "00000000-0000-0000-0000-000000000003",
+			ClientSecret:   "secret",
+			Channels:       []config.Defender{{ResourceID: "/subscriptions/00000000-0000-0000-0000-000000000001/resourceGroups/rg"}},
+		})
+		if len(clients) != 2 {
+			t.Errorf("Expected 2 Client, got %d clients", len(clients))
+		}
+		if _, ok := clients[0].(target.ResolvingClient); !ok {
+			t.Error("Expected Defender to resolve assessments")
+		}
+	})
+	t.Run("DefectDojo", func(t *testing.T) {
+		clients := factory.DefectDojoClients(config.DefectDojo{
+			Host:     "https://defectdojo.local",
+			APIKey:   "key",
+			Product:  "platform",
+			Channels: []config.DefectDojo{{Engagement: "staging"}},
+		})
+		if len(clients) != 2 {
+			t.Errorf("Expected 2 Client, got %d clients", len(clients))
+		}
+		if _, ok := clients[0].(target.ResolvingClient); !ok {
+			t.Error("Expected DefectDojo to mitigate findings")
+		}
+	})
+	t.Run("Alertmanager", func(t *testing.T) {
+		clients := factory.AlertmanagerClients(config.Alertmanager{
+			Host:     "http://alertmanager:9093",
+			Channels: []config.Alertmanager{{Headers: map[string]string{"X-Scope-OrgID": "tenant"}}},
+		})
+		if len(clients) != 2 {
+			t.Errorf("Expected 2 Client, got %d clients", len(clients))
+		}
+		if _, ok := clients[0].(target.ResolvingClient); !ok {
+			t.Error("Expected Alertmanager to resolve alerts")
+		}
+	})
+	t.Run("Exec", func(t *testing.T) {
+		clients := factory.ExecClients(config.Exec{
+			Command:  "/plugins/notify",
+			Channels: []config.Exec{{Env: map[string]string{"CHANNEL": "security"}}},
+		})
+		if len(clients) != 2 {
+			t.Errorf("Expected 2 Client, got %d clients", len(clients))
+		}
+	})
 }
 
 func Test_ResolveTargetWithoutHost(t *testing.T) {
@@ -109,6 +225,11 @@ func Test_ResolveTargetWithoutHost(t *testing.T) {
 			t.Error("Expected Client to be nil if no host is configured")
 		}
 	})
+	t.Run("GoogleChat", func(t *testing.T) {
+		if len(factory.GoogleChatClients(config.GoogleChat{})) != 0 {
+			t.Error("Expected Client to be nil if no host is configured")
+		}
+	})
 	t.Run("Teams", func(t *testing.T) {
 		if len(factory.TeamsClients(config.Teams{})) != 0 {
 			t.Error("Expected Client to be nil if no host is configured")
@@ -119,6 +240,36 @@ func Test_ResolveTargetWithoutHost(t *testing.T) {
 			t.Error("Expected Client to be nil if no host is configured")
 		}
 	})
+	t.Run("Grafana", func(t *testing.T) {
+		if len(factory.GrafanaClients(config.Grafana{})) != 0 {
+			t.Error("Expected Client to be nil if no host is configured")
+		}
+	})
+	t.Run("Telegram", func(t *testing.T) {
+		if len(factory.TelegramClients(config.Telegram{Token: "123:token"})) != 0 {
+			t.Error("Expected Client to be nil if no chatID is configured")
+		}
+	})
+	t.Run("SecurityHub.Region", func(t *testing.T) {
+		if len(factory.SecurityHubClients(config.SecurityHub{AccountID: "123456789012"})) != 0 {
+			t.Error("Expected Client to be nil if no region is configured")
+		}
+	})
+	t.Run("SecurityCenter.OrganizationID", func(t *testing.T) {
+		if len(factory.SecurityCenterClients(config.SecurityCenter{Source: "organizations/123/sources/456"})) != 0 {
+			t.Error("Expected Client to be nil if no organizationID is configured")
+		}
+	})
+	t.Run("Defender.SubscriptionID", func(t *testing.T) {
+		if len(factory.DefenderClients(config.Defender{TenantID: "tenant", ClientID: "client", ClientSecret: "secret"})) != 0 {
+			t.Error("Expected Client to be nil if no subscriptionID is configured")
+		}
+	})
+	t.Run("DefectDojo.Product", func(t *testing.T) {
+		if len(factory.DefectDojoClients(config.DefectDojo{Host: "https://defectdojo.local", APIKey: "key"})) != 0 {
+			t.Error("Expected Client to be nil if no product is configured")
+		}
+	})
 	t.Run("S3.Endoint", func(t *testing.T) {
 		if len(factory.S3Clients(config.S3{})) != 0 {
 			t.Error("Expected Client to be nil if no endpoint is configured")
@@ -210,6 +361,20 @@ func Test_GetValuesFromSecret(t *testing.T) {
 		}
 	})
 
+	t.Run("Get GoogleChat values from Secret", func(t *testing.T) {
+		clients := factory.GoogleChatClients(config.GoogleChat{SecretRef: secretName})
+		if len(clients) != 1 {
+			t.Error("Expected one client created")
+		}
+
+		client := reflect.ValueOf(clients[0]).Elem()
+
+		webhook := client.FieldByName("webhook").String()
+		if webhook != "http://localhost:9200/webhook?messageReplyOption=REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD" {
+			t.Errorf("Expected webhook from secret, got %s", webhook)
+		}
+	})
+
 	t.Run("Get Discord values from Secret", func(t *testing.T) {
 		clients := factory.DiscordClients(config.Discord{SecretRef: secretName})
 		if len(clients) != 1 {
@@ -238,6 +403,19 @@ func Test_GetValuesFromSecret(t *testing.T) {
 		}
 	})
 
+	t.Run("Get Telegram Token from Secret", func(t *testing.T) {
+		clients := factory.TelegramClients(config.Telegram{SecretRef: secretName, ChatID: "-100123"})
+		if len(clients) != 1 {
+			t.Fatal("Expected one client created")
+		}
+
+		client := reflect.ValueOf(clients[0]).Elem()
+
+		if token := client.FieldByName("token").String(); token != "token" {
+			t.Errorf("Expected token from secret, got %s", token)
+		}
+	})
+
 	t.Run("Get Slack values from Secret", func(t *testing.T) {
 		clients := factory.SlackClients(config.Slack{SecretRef: secretName})
 		if len(clients) != 1 {
@@ -266,6 +444,19 @@ func Test_GetValuesFromSecret(t *testing.T) {
 		}
 	})
 
+	t.Run("Get Grafana values from Secret", func(t *testing.T) {
+		clients := factory.GrafanaClients(config.Grafana{SecretRef: secretName})
+		if len(clients) != 1 {
+			t.Error("Expected one client created")
+		}
+
+		client := reflect.ValueOf(clients[0]).Elem()
+
+		if token := client.FieldByName("token").String(); token != "token" {
+			t.Errorf("Expected token from secret, got %s", token)
+		}
+	})
+
 	t.Run("Get S3 values from Secret", func(t *testing.T) {
 		clients := factory.S3Clients(config.S3{SecretRef: secretName, Endpoint: "endoint", Bucket: "bucket", Region: "region"})
 		if len(clients) != 1 {
@@ -392,3 +583,84 @@ func Test_GetValuesFromSecret(t *testing.T) {
 		}
 	})
 }
+
+func Test_ClusterNameInCustomFields(t *testing.T) {
+	resolver := config.NewResolver(&config.Config{ClusterName: "edge-a"}, &rest.Config{})
+	factory := resolver.TargetFactory()
+
+	t.Run("Added to CustomFields", func(t *testing.T) {
+		clients := factory.WebhookClients(config.Webhook{CustomFields: map[string]string{"field": "value"}, Host: "http://localhost"})
+
+		fields := reflect.ValueOf(clients[0]).Elem().FieldByName("customFields")
+		if value := fields.MapIndex(reflect.ValueOf(config.ClusterField)); !value.IsValid() || value.String() != "edge-a" {
+			t.Errorf("Expected cluster field with the cluster name")
+		}
+		if fields.Len() != 2 {
+			t.Errorf("Expected configured customFields are kept")
+		}
+	})
+	t.Run("Configured field takes precedence", func(t *testing.T) {
+		clients := factory.SlackClients(config.Slack{CustomFields: map[string]string{config.ClusterField: "custom"}, Webhook: "http://localhost"})
+
+		fields := reflect.ValueOf(clients[0]).Elem().FieldByName("customFields")
+		if value := fields.MapIndex(reflect.ValueOf(config.ClusterField)); value.String() != "custom" {
+			t.Errorf("Expected configured cluster field, got %s", value.String())
+		}
+	})
+	t.Run("Added to Loki labels", func(t *testing.T) {
+		clients := factory.LokiClients(config.Loki{Host: "http://localhost"})
+
+		labels := reflect.ValueOf(clients[0]).Elem().FieldByName("customLabels")
+		if value := labels.MapIndex(reflect.ValueOf(config.ClusterField)); !value.IsValid() || value.String() != "edge-a" {
+			t.Errorf("Expected cluster label with the cluster name")
+		}
+	})
+}
+
+func Test_GlobalCustomFields(t *testing.T) {
+	t.Setenv("TEST_CLOUD_REGION", "eu-central-1")
+
+	resolver := config.NewResolver(&config.Config{
+		ClusterName: "edge-a",
+		CustomFields: config.CustomFields{
+			Values: map[string]string{"environment": "production", "tier": "default"},
+			Env:    map[string]string{"region": "TEST_CLOUD_REGION", "node": "TEST_UNSET_NODE_NAME"},
+		},
+	}, &rest.Config{})
+	factory := resolver.TargetFactory()
+
+	clients := factory.WebhookClients(config.Webhook{CustomFields: map[string]string{"tier": "gold"}, Host: "http://localhost"})
+
+	fields := reflect.ValueOf(clients[0]).Elem().FieldByName("customFields")
+	for key, expected := range map[string]string{"environment": "production", "region": "eu-central-1", "tier": "gold", config.ClusterField: "edge-a"} {
+		if value := fields.MapIndex(reflect.ValueOf(key)); !value.IsValid() || value.String() != expected {
+			t.Errorf("Expected field %s with value %s", key, expected)
+		}
+	}
+	if fields.Len() != 4 {
+		t.Errorf("Expected fields of unset environment variables are skipped, got %d fields", fields.Len())
+	}
+}
+
+func Test_RegisterTargetCredentials(t *testing.T) {
+	factory := config.NewTargetFactory("", nil)
+
+	factory.WebhookClients(config.Webhook{
+		Host:    "http://localhost:8080",
+		Headers: map[string]string{"X-API-Key": "webhook-header-key"},
+		Signing: config.WebhookSigning{Secret: "webhook-signing-key"},
+	})
+	factory.S3Clients(config.S3{
+		Endpoint:        "https://storage.yandexcloud.net",
+		AccessKeyID:     "AccessKey",
+		SecretAccessKey: "s3-secret-access-key",
+		Region:          "ru-central1",
+		Bucket:          "test",
+	})
+
+	for _, value := range []string{"webhook-header-key", "webhook-signing-key", "s3-secret-access-key"} {
+		if redacted := secret.Redact("failed with " + value); redacted != "failed with "+secret.Redacted {
+			t.Errorf("expected %s to be redacted, got %s", value, redacted)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"github.com/kyverno/policy-reporter/pkg/taxonomy"
+)
+
+// TaxonomyFromConfig creates the Taxonomy of the configured sources and categories
+func TaxonomyFromConfig(c Taxonomy) *taxonomy.Taxonomy {
+	return &taxonomy.Taxonomy{
+		Sources:    dimensionFromConfig(c.Sources),
+		Categories: dimensionFromConfig(c.Categories),
+	}
+}
+
+func dimensionFromConfig(entries []TaxonomyEntry) taxonomy.Dimension {
+	dimension := make(taxonomy.Dimension, 0, len(entries))
+	for _, e := range entries {
+		dimension = append(dimension, taxonomy.Entry{Name: e.Name, Match: e.Match, Group: e.Group, Order: e.Order})
+	}
+
+	return dimension
+}
@@ -0,0 +1,1209 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/kyverno/policy-reporter/pkg/admission"
+	"github.com/kyverno/policy-reporter/pkg/coverage"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/enrichment"
+	"github.com/kyverno/policy-reporter/pkg/expression"
+	"github.com/kyverno/policy-reporter/pkg/fields"
+	"github.com/kyverno/policy-reporter/pkg/kubernetes/secrets"
+	"github.com/kyverno/policy-reporter/pkg/listener/metrics"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/maintenance"
+	"github.com/kyverno/policy-reporter/pkg/memory"
+	"github.com/kyverno/policy-reporter/pkg/secret"
+	"github.com/kyverno/policy-reporter/pkg/severity"
+	"github.com/kyverno/policy-reporter/pkg/sqlite3"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/cloudevents"
+	"github.com/kyverno/policy-reporter/pkg/target/s3"
+	"github.com/kyverno/policy-reporter/pkg/target/scc"
+	"github.com/kyverno/policy-reporter/pkg/target/teams"
+	"github.com/kyverno/policy-reporter/pkg/target/webhook"
+	"github.com/kyverno/policy-reporter/pkg/timeformat"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+// Problem of the configuration at the path of the invalid option
+type Problem struct {
+	Path    string
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", p.Path, p.Message)
+}
+
+// ValidationError contains all problems of an invalid configuration
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Problems)+1)
+	lines = append(lines, fmt.Sprintf("invalid configuration, %d problem(s) found:", len(e.Problems)))
+	for _, p := range e.Problems {
+		lines = append(lines, "  - "+p.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// endpoint of a target, channels without own endpoint inherit it from the parent if inherit is set
+type endpoint struct {
+	field   string
+	inherit bool
+	// alternative option which enables the target without the endpoint
+	alternative string
+	// required options if the endpoint is configured, inherited like the endpoint
+	required []string
+}
+
+var (
+	accountID      = regexp.MustCompile(`^\d{12}$`)
+	organizationID = regexp.MustCompile(`^\d+$`)
+	sccSource      = regexp.MustCompile(`^organizations/\d+/sources/\d+$`)
+	guid           = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hexColor       = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+)
+
+var targetEndpoints = map[string]endpoint{
+	"loki":          {field: "Host", inherit: true},
+	"elasticsearch": {field: "Host", inherit: true},
+	"slack":         {field: "Webhook", alternative: "Token"},
+	"discord":       {field: "Webhook"},
+	"teams":         {field: "Webhook"},
+	"googleChat":    {field: "Webhook"},
+	"webhook":       {field: "Host"},
+	"ui":            {field: "Host"},
+	"grafana":       {field: "Host", inherit: true},
+	"s3":            {field: "Endpoint", inherit: true, required: []string{"AccessKeyID", "SecretAccessKey", "Region", "Bucket"}},
+	"kinesis":       {field: "Endpoint", inherit: true, required: []string{"AccessKeyID", "SecretAccessKey", "Region"}},
+	"defectDojo":    {field: "Host", inherit: true, required: []string{"APIKey", "Product"}},
+	"alertmanager":  {field: "Host", inherit: true},
+	"exec":          {field: "Command", inherit: true},
+}
+
+type validator struct {
+	problems []Problem
+}
+
+func (v *validator) add(path, format string, args ...interface{}) {
+	v.problems = append(v.problems, Problem{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) url(path, value string) {
+	u, err := url.Parse(value)
+	if err != nil {
+		v.add(path, "invalid URL '%s': %s", value, err)
+		return
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		v.add(path, "invalid URL '%s', expected an http or https scheme", value)
+		return
+	}
+	if u.Host == "" {
+		v.add(path, "invalid URL '%s', missing host", value)
+	}
+}
+
+func (v *validator) host(path, value string) {
+	if value == "" || net.ParseIP(strings.Trim(value, "[]")) != nil {
+		return
+	}
+	for _, msg := range validation.IsDNS1123Subdomain(value) {
+		v.add(path, "invalid host '%s', expected an IP address or hostname: %s", value, msg)
+	}
+}
+
+func (v *validator) port(path string, value int) {
+	if value < 0 || value > 65535 {
+		v.add(path, "invalid port %d, expected a port up to 65535", value)
+	}
+}
+
+func (v *validator) oneOf(path, value string, allowed ...string) {
+	if value == "" {
+		return
+	}
+
+	for _, a := range allowed {
+		if a == value {
+			return
+		}
+	}
+
+	v.add(path, "unknown value '%s', expected one of %s", value, strings.Join(allowed, ", "))
+}
+
+// debounce validates a debounce window and its max delay
+func (v *validator) debounce(path string, window, maxDelay time.Duration) {
+	if window < 0 {
+		v.add(path+".window", "must not be negative")
+	}
+	if maxDelay != 0 && maxDelay < window {
+		v.add(path+".maxDelay", "must not be shorter than the window")
+	}
+}
+
+// webhookAuth validates the header template and the OAuth2 client credentials
+func (v *validator) s3(path string, config S3) {
+	v.oneOf(path+".format", config.Format, "json", s3.FormatSARIF, s3.FormatNDJSON, s3.FormatParquet)
+	v.oneOf(path+".serverSideEncryption", config.Encryption, "AES256", "aws:kms")
+
+	if config.KMSKeyID != "" && config.Encryption == "AES256" {
+		v.add(path+".kmsKeyID", "requires serverSideEncryption aws:kms")
+	}
+	if config.FlushInterval < 0 {
+		v.add(path+".flushInterval", "must not be negative")
+	}
+	if config.FlushSize < 0 {
+		v.add(path+".flushSize", "must not be negative")
+	}
+}
+
+func (v *validator) kinesis(path string, config, parent Kinesis) {
+	if config.StreamName != "" && config.DeliveryStream != "" {
+		v.add(path+".deliveryStream", "mutually exclusive with streamName")
+	}
+	if config.Endpoint != "" || parent.Endpoint != "" {
+		if config.StreamName == "" && config.DeliveryStream == "" && parent.StreamName == "" && parent.DeliveryStream == "" && config.SecretRef == "" {
+			v.add(path+".streamName", "streamName or deliveryStream required, the target is disabled without it")
+		}
+	}
+	if config.FlushInterval < 0 {
+		v.add(path+".flushInterval", "must not be negative")
+	}
+	v.cloudEvents(path+".cloudEvents", config.CloudEvents, cloudevents.ModeStructured)
+}
+
+// cloudEvents validates the mode against the supported modes of the target and the source as URI reference
+func (v *validator) cloudEvents(path string, config CloudEvents, modes ...string) {
+	v.oneOf(path+".mode", config.Mode, modes...)
+
+	if _, err := url.Parse(config.Source); err != nil {
+		v.add(path+".source", "expected a URI reference like //cluster-a/policy-reporter: %s", err)
+	}
+}
+
+func (v *validator) webhookAuth(path string, auth WebhookAuth) {
+	if auth.Template != "" {
+		if _, err := webhook.NewAuth(auth.Header, auth.Template, "", webhook.OAuth2{}); err != nil {
+			v.add(path+".template", "%s", err)
+		}
+	}
+
+	if auth.Token != "" && auth.OAuth2.TokenURL != "" {
+		v.add(path+".token", "token and oauth2 are mutually exclusive")
+	}
+
+	oauth := auth.OAuth2
+	if oauth.TokenURL == "" && (oauth.ClientID != "" || oauth.ClientSecret != "") {
+		v.add(path+".oauth2.tokenURL", "required")
+	}
+	if oauth.TokenURL != "" {
+		v.url(path+".oauth2.tokenURL", oauth.TokenURL)
+
+		if oauth.ClientID == "" {
+			v.add(path+".oauth2.clientID", "required")
+		}
+	}
+}
+
+// target validates the endpoint, the priority and the channels of a target
+func (v *validator) target(path string, t reflect.Value, e endpoint, parent reflect.Value) {
+	key := tagName(fieldOf(t.Type(), e.field))
+	value := t.FieldByName(e.field).String()
+	secretRef := ""
+	if ref := t.FieldByName("SecretRef"); ref.IsValid() {
+		secretRef = ref.String()
+	}
+
+	inherited := e.inherit && parent.IsValid() && parent.FieldByName(e.field).String() != ""
+	alternative := e.alternative != "" && t.FieldByName(e.alternative).String() != ""
+
+	switch {
+	case value != "":
+		v.url(path+"."+key, value)
+	case parent.IsValid() && !inherited && secretRef == "" && !alternative:
+		v.add(path, "channel is disabled without %s or secretRef", key)
+	}
+
+	if (value != "" || inherited) && secretRef == "" {
+		for _, name := range e.required {
+			if t.FieldByName(name).String() != "" || (parent.IsValid() && parent.FieldByName(name).String() != "") {
+				continue
+			}
+
+			v.add(path+"."+tagName(fieldOf(t.Type(), name)), "required, the target is disabled without it")
+		}
+	}
+
+	if prio := t.FieldByName("MinimumPriority"); prio.IsValid() {
+		v.oneOf(path+".minimumPriority", prio.String(), priorities...)
+	}
+
+	channels := t.FieldByName("Channels")
+	if !channels.IsValid() {
+		return
+	}
+
+	for i := 0; i < channels.Len(); i++ {
+		v.target(fmt.Sprintf("%s.channels[%d]", path, i), channels.Index(i), e, t)
+	}
+}
+
+// Validate checks the configured URLs, secretRefs, filter expressions, regex filter values and mutually exclusive options,
+// unknown options of the loaded configuration file are reported as possible typos
+func Validate(c *Config) error {
+	v := &validator{}
+
+	for _, key := range c.unknownKeys {
+		v.add(key, "unknown option, check the spelling and indentation")
+	}
+
+	config := reflect.ValueOf(c).Elem()
+	for i := 0; i < config.NumField(); i++ {
+		field := config.Type().Field(i)
+		if e, ok := targetEndpoints[tagName(field)]; ok {
+			v.target(tagName(field), config.Field(i), e, reflect.Value{})
+		}
+	}
+
+	walkFields(config, "", func(field reflect.StructField, value reflect.Value, path string) {
+		if rules, ok := value.Interface().([]SamplingRule); ok {
+			for i, rule := range rules {
+				if rule.Limit < 0 {
+					v.add(fmt.Sprintf("%s[%d].limit", path, i), "must not be negative")
+				}
+				if rule.Window < 0 {
+					v.add(fmt.Sprintf("%s[%d].window", path, i), "must not be negative")
+				}
+			}
+		}
+		if filter, ok := value.Interface().(TargetFilter); ok {
+			for i, key := range filter.GroupBy {
+				if key == "" {
+					v.add(fmt.Sprintf("%s.groupBy[%d]", path, i), "must not be empty")
+				}
+			}
+			if filter.GroupWindow < 0 {
+				v.add(path+".groupWindow", "must not be negative")
+			}
+			for i, w := range filter.DeliveryWindows {
+				if _, err := maintenance.ParseQuietHours(w.Days, w.From, w.To, w.Timezone); err != nil {
+					v.add(fmt.Sprintf("%s.deliveryWindows[%d]", path, i), "%s", err)
+				}
+			}
+			for i, rule := range filter.Scrub {
+				if _, err := regexp.Compile(rule.Pattern); err != nil || rule.Pattern == "" {
+					v.add(fmt.Sprintf("%s.scrub[%d].pattern", path, i), "must be a valid regular expression")
+				}
+				if rule.Condition == "" {
+					continue
+				}
+				if _, err := expression.Compile(rule.Condition); err != nil {
+					v.add(fmt.Sprintf("%s.scrub[%d].condition", path, i), "%s", err)
+				}
+			}
+			if _, err := target.ParseBackfill(filter.SendExistingResults); err != nil {
+				v.add(path+".sendExistingResults", "%s", err)
+			}
+		}
+		if options, ok := value.Interface().(HTTPClient); ok {
+			if options.Timeout < 0 {
+				v.add(path+".timeout", "must not be negative")
+			}
+			if options.MaxIdleConns < 0 {
+				v.add(path+".maxIdleConns", "must not be negative")
+			}
+			if options.MaxIdleConnsPerHost < 0 {
+				v.add(path+".maxIdleConnsPerHost", "must not be negative")
+			}
+			if options.IdleConnTimeout < 0 {
+				v.add(path+".idleConnTimeout", "must not be negative")
+			}
+			if options.KeepAlive < 0 {
+				v.add(path+".keepAlive", "must not be negative")
+			}
+		}
+		if actions, ok := value.Interface().([]Action); ok {
+			for i, action := range actions {
+				if action.Text == "" {
+					v.add(fmt.Sprintf("%s[%d].text", path, i), "required")
+				}
+				if _, err := target.ParseTemplate("url", action.URL); err != nil {
+					v.add(fmt.Sprintf("%s[%d].url", path, i), "%s", err)
+				}
+			}
+		}
+		if field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.String {
+			for i := 0; i < value.Len(); i++ {
+				if err := validate.ValidatePattern(value.Index(i).String()); err != nil {
+					v.add(fmt.Sprintf("%s[%d]", path, i), "%s", err)
+				}
+			}
+		}
+		if field.Type.Kind() != reflect.String || value.String() == "" {
+			return
+		}
+
+		switch field.Name {
+		case "Expression":
+			if _, err := expression.Compile(value.String()); err != nil {
+				v.add(path, "%s", err)
+			}
+		case "RemediationTemplate":
+			if _, err := target.ParseRemediationTemplate(value.String()); err != nil {
+				v.add(path, "%s", err)
+			}
+		case "TitleTemplate", "MessageTemplate", "PartitionKey":
+			if _, err := target.ParseTemplate(field.Name, value.String()); err != nil {
+				v.add(path, "%s", err)
+			}
+		case "SecretRef":
+			for _, msg := range validation.IsDNS1123Subdomain(value.String()) {
+				v.add(path, "invalid secret name '%s': %s", value.String(), msg)
+			}
+		}
+	})
+
+	levels, err := severity.Order(SeverityLevelsFromConfig(c.Severities))
+	if err != nil {
+		v.add("customSeverities", "%s", err)
+	}
+	for i, s := range c.Severities {
+		path := fmt.Sprintf("customSeverities[%d]", i)
+
+		if s.Color != "" && !hexColor.MatchString(s.Color) {
+			v.add(path+".color", "invalid color '%s', expected a hex color like #9e9e9e", s.Color)
+		}
+		v.oneOf(path+".priority", s.Priority, priorities...)
+	}
+
+	severities := make([]string, 0, len(c.PriorityMap))
+	for severity := range c.PriorityMap {
+		severities = append(severities, severity)
+	}
+	for _, severity := range sortedKeys(severities) {
+		v.oneOf("priorityMap."+severity, c.PriorityMap[severity], priorities...)
+	}
+	if _, _, err := PriorityRulesFromConfig(c.Priorities); err != nil {
+		v.add("priorityMapping", "%s", err)
+	}
+	promoted := make(map[string]bool, len(c.PropertyFields))
+	for i, field := range c.PropertyFields {
+		path := fmt.Sprintf("propertyFields[%d]", i)
+
+		if field.Property == "" {
+			v.add(path+".property", "required")
+		}
+		switch {
+		case field.Field == "":
+			v.add(path+".field", "required")
+		case fields.IsReserved(field.Field):
+			v.add(path+".field", "%s is a reserved result field", field.Field)
+		case promoted[field.Field]:
+			v.add(path+".field", "%s is already mapped", field.Field)
+		}
+		if field.Type != "" {
+			v.oneOf(path+".type", field.Type, fields.Types...)
+		}
+
+		promoted[field.Field] = true
+	}
+
+	if _, err := IDMapperFromConfig(c.ResultIDs); err != nil {
+		v.add("resultIDs", "%s", err)
+	}
+
+	if c.Metrics.Pushgateway.URL != "" {
+		v.url("metrics.pushgateway.url", c.Metrics.Pushgateway.URL)
+	}
+	if c.Metrics.RemoteWrite.URL != "" {
+		v.url("metrics.remoteWrite.url", c.Metrics.RemoteWrite.URL)
+	}
+	if c.Federation.Central.URL != "" {
+		v.url("federation.central.url", c.Federation.Central.URL)
+	}
+	if c.API.Auth.OIDC.Enabled {
+		v.url("api.auth.oidc.issuer", c.API.Auth.OIDC.Issuer)
+		if c.API.Auth.OIDC.Audience == "" {
+			v.add("api.auth.oidc.audience", "required, tokens issued for other clients of the issuer are rejected")
+		}
+	}
+	if c.Tracing.Enabled {
+		v.url("tracing.endpoint", c.Tracing.Endpoint)
+	}
+	if c.Audit.Enabled && c.Audit.Path == "" {
+		v.add("audit.path", "required")
+	}
+	if c.Audit.MaxSize < 0 {
+		v.add("audit.maxSize", "must not be negative")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		v.add("tracing.sampleRatio", "must be between 0 and 1")
+	}
+
+	v.oneOf("metrics.mode", c.Metrics.Mode, metrics.Simple, metrics.Custom, metrics.Detailed)
+	if c.Metrics.SeriesLimit < 0 {
+		v.add("metrics.seriesLimit", "must not be negative")
+	}
+	if c.Metrics.Prefix != "" && !metrics.ValidName(c.Metrics.Prefix) {
+		v.add("metrics.prefix", "must be a valid metric name prefix")
+	}
+	metricSources := make(map[string]bool, len(c.Metrics.Sources))
+	for i, s := range c.Metrics.Sources {
+		path := fmt.Sprintf("metrics.sources[%d]", i)
+		if s.Source == "" {
+			v.add(path+".source", "is required")
+		} else if metricSources[strings.ToLower(s.Source)] {
+			v.add(path+".source", fmt.Sprintf("duplicate source %s", s.Source))
+		}
+		if s.Name != "" && !metrics.ValidName(s.Name) {
+			v.add(path+".name", "must be a valid metric name")
+		}
+		for _, l := range s.Labels {
+			if _, ok := metrics.LabelGeneratorMapping[l]; !ok && !strings.HasPrefix(l, metrics.ReportLabelPrefix) {
+				v.add(path+".labels", fmt.Sprintf("unknown label %s", l))
+			}
+		}
+
+		metricSources[strings.ToLower(s.Source)] = true
+	}
+	v.oneOf("database.type", strings.ToLower(c.Database.Type), "sqlite", "postgres", "postgresql", "mysql", "mariadb", memory.Type)
+	v.oneOf("deduplication.type", c.Deduplication.Type, "memory", "redis", "sqlite")
+	v.s3("s3", c.S3)
+	for i, channel := range c.S3.Channels {
+		v.s3(fmt.Sprintf("s3.channels[%d]", i), channel)
+	}
+	v.oneOf("webhook.payload", c.Webhook.Payload, webhook.PayloadResult, webhook.PayloadReport, webhook.PayloadSummary)
+	v.webhookAuth("webhook.auth", c.Webhook.Auth)
+	v.cloudEvents("webhook.cloudEvents", c.Webhook.CloudEvents, cloudevents.ModeStructured, cloudevents.ModeBinary)
+	v.kinesis("kinesis", c.Kinesis, Kinesis{})
+	for i, channel := range c.Kinesis.Channels {
+		v.kinesis(fmt.Sprintf("kinesis.channels[%d]", i), channel, c.Kinesis)
+	}
+	for i, channel := range c.Webhook.Channels {
+		v.oneOf(fmt.Sprintf("webhook.channels[%d].payload", i), channel.Payload, webhook.PayloadResult, webhook.PayloadReport, webhook.PayloadSummary)
+		v.webhookAuth(fmt.Sprintf("webhook.channels[%d].auth", i), channel.Auth)
+		v.cloudEvents(fmt.Sprintf("webhook.channels[%d].cloudEvents", i), channel.CloudEvents, cloudevents.ModeStructured, cloudevents.ModeBinary)
+	}
+
+	floors := make([]string, 0, len(c.SeverityFloor.Namespaces))
+	for namespace := range c.SeverityFloor.Namespaces {
+		floors = append(floors, namespace)
+	}
+	for _, namespace := range sortedKeys(floors) {
+		path := "severityFloor.namespaces." + namespace
+
+		if err := validate.ValidatePattern(namespace); err != nil {
+			v.add(path, "%s", err)
+		}
+		v.oneOf(path, c.SeverityFloor.Namespaces[namespace], levels...)
+	}
+
+	slackChannel := func(path string, s Slack) {
+		if s.Token != "" && s.Channel == "" {
+			v.add(path+".channel", "required with a token")
+		}
+	}
+	slackChannel("slack", c.Slack)
+	for i, channel := range c.Slack.Channels {
+		slackChannel(fmt.Sprintf("slack.channels[%d]", i), channel)
+	}
+
+	v.oneOf("teams.format", c.Teams.Format, teams.FormatMessageCard, teams.FormatAdaptiveCard)
+	for i, channel := range c.Teams.Channels {
+		v.oneOf(fmt.Sprintf("teams.channels[%d].format", i), channel.Format, teams.FormatMessageCard, teams.FormatAdaptiveCard)
+	}
+
+	discordMentions := func(path string, mentions map[string][]string) {
+		severities := make([]string, 0, len(mentions))
+		for severity := range mentions {
+			severities = append(severities, severity)
+		}
+		for _, severity := range sortedKeys(severities) {
+			v.oneOf(path+".mentions."+severity, severity, levels...)
+		}
+	}
+	discordMentions("discord", c.Discord.Mentions)
+	for i, channel := range c.Discord.Channels {
+		discordMentions(fmt.Sprintf("discord.channels[%d]", i), channel.Mentions)
+	}
+
+	telegramChat := func(path string, t Telegram, inherited bool) {
+		if (t.Token != "" || t.SecretRef != "" || inherited) && t.ChatID == "" {
+			v.add(path+".chatID", "required, the target is disabled without it")
+		}
+		if t.Host != "" {
+			v.url(path+".host", t.Host)
+		}
+	}
+	securityHub := func(path string, sh SecurityHub, parent SecurityHub) {
+		if sh.AccountID != "" && !accountID.MatchString(sh.AccountID) {
+			v.add(path+".accountID", "invalid AWS account ID '%s', expected 12 digits", sh.AccountID)
+		}
+		if (sh.AccountID != "" || parent.AccountID != "") && sh.Region == "" && parent.Region == "" {
+			v.add(path+".region", "required, the target is disabled without it")
+		}
+		if sh.Endpoint != "" {
+			v.url(path+".endpoint", sh.Endpoint)
+		}
+		accessKey, secretKey := sh.AccessKeyID+parent.AccessKeyID, sh.SecretAccessKey+parent.SecretAccessKey
+		if (accessKey == "") != (secretKey == "") && sh.SecretRef == "" && parent.SecretRef == "" {
+			v.add(path, "accessKeyID and secretAccessKey are required together, the default credentials are used without both")
+		}
+	}
+	securityCenter := func(path string, sc SecurityCenter, parent SecurityCenter) {
+		if sc.OrganizationID != "" && !organizationID.MatchString(sc.OrganizationID) {
+			v.add(path+".organizationID", "invalid organization ID '%s', expected digits", sc.OrganizationID)
+		}
+		if sc.Source != "" && !sccSource.MatchString(sc.Source) {
+			v.add(path+".source", "invalid source '%s', expected organizations/<id>/sources/<id>", sc.Source)
+		}
+		if sc.Host != "" {
+			v.url(path+".host", sc.Host)
+		}
+		if sc.Credentials != "" {
+			if _, err := scc.NewTokenSource(secret.String(sc.Credentials)); err != nil {
+				v.add(path+".credentials", "%s", err)
+			}
+		}
+
+		cluster := []string{sc.ProjectID + parent.ProjectID, sc.Location + parent.Location, sc.Cluster + parent.Cluster}
+		if strings.Join(cluster, "") != "" && (cluster[0] == "" || cluster[1] == "" || cluster[2] == "") {
+			v.add(path, "projectID, location and cluster are required together, resources are mapped to the organization without them")
+		}
+	}
+	securityCenter("securityCommandCenter", c.SecurityCenter, SecurityCenter{})
+	for i, channel := range c.SecurityCenter.Channels {
+		securityCenter(fmt.Sprintf("securityCommandCenter.channels[%d]", i), channel, c.SecurityCenter)
+	}
+
+	defender := func(path string, d Defender, parent Defender) {
+		for _, field := range [][2]string{{"subscriptionID", d.SubscriptionID}, {"tenantID", d.TenantID}, {"clientID", d.ClientID}} {
+			if field[1] != "" && !guid.MatchString(field[1]) {
+				v.add(path+"."+field[0], "invalid %s '%s', expected a GUID", field[0], field[1])
+			}
+		}
+		if d.Host != "" {
+			v.url(path+".host", d.Host)
+		}
+
+		subscription := d.SubscriptionID
+		if subscription == "" {
+			subscription = parent.SubscriptionID
+		}
+		if d.ResourceID != "" && !strings.HasPrefix(strings.ToLower(d.ResourceID), strings.ToLower("/subscriptions/"+subscription+"/")) {
+			v.add(path+".resourceID", "resource '%s' is not part of the subscription '%s'", d.ResourceID, subscription)
+		}
+	}
+	defender("defenderForCloud", c.Defender, Defender{})
+	for i, channel := range c.Defender.Channels {
+		defender(fmt.Sprintf("defenderForCloud.channels[%d]", i), channel, c.Defender)
+	}
+
+	securityHub("securityHub", c.SecurityHub, SecurityHub{})
+	for i, channel := range c.SecurityHub.Channels {
+		securityHub(fmt.Sprintf("securityHub.channels[%d]", i), channel, c.SecurityHub)
+	}
+
+	telegramChat("telegram", c.Telegram, false)
+	for i, channel := range c.Telegram.Channels {
+		telegramChat(fmt.Sprintf("telegram.channels[%d]", i), channel, c.Telegram.Token != "" || c.Telegram.SecretRef != "")
+	}
+
+	v.oneOf("admissionWebhook.mode", c.Admission.Mode, admission.ModeWarn, admission.ModeDeny)
+	if c.Admission.Enabled {
+		if c.Admission.CertFile == "" {
+			v.add("admissionWebhook.certFile", "required")
+		}
+		if c.Admission.KeyFile == "" {
+			v.add("admissionWebhook.keyFile", "required")
+		}
+	}
+
+	v.oneOf("targetHealth.action", c.TargetHealth.Action, TargetHealthUnready, TargetHealthRestart)
+	if len(c.TargetHealth.Critical) > 0 && c.TargetHealth.Threshold <= 0 {
+		v.add("targetHealth.threshold", "required for critical targets")
+	}
+
+	if !c.Orphaned.Enabled && (c.Orphaned.ExcludeFromTargets || c.Orphaned.ExcludeFromMetrics) {
+		v.add("orphanedResults.enabled", "excluding orphaned results requires orphanedResults.enabled")
+	}
+
+	disabled := make(map[string]bool, len(c.Sources.Disabled))
+	for _, source := range c.Sources.Disabled {
+		disabled[strings.ToLower(source)] = true
+	}
+	prioritized := make(map[string]bool, len(c.Sources.Precedence))
+	for i, source := range c.Sources.Precedence {
+		path := fmt.Sprintf("sources.precedence[%d]", i)
+		if disabled[strings.ToLower(source)] {
+			v.add(path, "source %s is disabled", source)
+		}
+		if prioritized[strings.ToLower(source)] {
+			v.add(path, "duplicated source %s", source)
+		}
+		prioritized[strings.ToLower(source)] = true
+	}
+	if len(c.Sources.Precedence) == 1 {
+		v.add("sources.precedence", "requires at least two overlapping sources")
+	}
+
+	if c.ResultAge.MaxAge < 0 {
+		v.add("resultAge.maxAge", "must not be negative")
+	}
+	ages := make([]string, 0, len(c.ResultAge.Sources))
+	for source := range c.ResultAge.Sources {
+		ages = append(ages, source)
+	}
+	for _, source := range sortedKeys(ages) {
+		if c.ResultAge.Sources[source] < 0 {
+			v.add("resultAge.sources."+source, "must not be negative")
+		}
+	}
+	if c.ResultAge.DropFromStore && c.ResultAge.MaxAge <= 0 && len(c.ResultAge.Sources) == 0 {
+		v.add("resultAge.dropFromStore", "requires maxAge or a maximum age per source")
+	}
+
+	for i, rule := range c.Escalation.Rules {
+		path := fmt.Sprintf("escalation.rules[%d]", i)
+
+		v.oneOf(path+".minimumSeverity", rule.MinimumSeverity, levels...)
+		for j, status := range rule.Status {
+			v.oneOf(fmt.Sprintf("%s.status[%d]", path, j), status, v1alpha2.StatusFail, v1alpha2.StatusWarn, v1alpha2.StatusError)
+		}
+		if rule.Name == "" {
+			v.add(path+".name", "required to identify the escalation")
+		}
+		if rule.After <= 0 {
+			v.add(path+".after", "required, the duration a result stays unresolved before it is escalated")
+		}
+		if len(rule.Targets) == 0 && len(rule.Email) == 0 {
+			v.add(path, "escalation without targets or email receivers")
+		}
+		if len(rule.Email) > 0 && c.EmailReports.SMTP.Host == "" {
+			v.add(path+".email", "requires emailReports.smtp")
+		}
+	}
+
+	if c.EmailReports.Subscriptions.Enabled {
+		if c.EmailReports.Subscriptions.URL == "" {
+			v.add("emailReports.subscriptions.url", "required for the confirmation and unsubscribe links")
+		} else if u, err := url.Parse(c.EmailReports.Subscriptions.URL); err != nil || u.Scheme == "" || u.Host == "" {
+			v.add("emailReports.subscriptions.url", "expected an absolute URL like https://policy-reporter.example.com")
+		}
+		if c.EmailReports.SMTP.Host == "" {
+			v.add("emailReports.subscriptions.enabled", "requires emailReports.smtp")
+		}
+	}
+
+	if c.Snooze.MaxDuration < 0 {
+		v.add("snooze.maxDuration", "must not be negative")
+	}
+	if c.Snooze.Enabled && c.Snooze.RefreshInterval <= 0 {
+		v.add("snooze.refreshInterval", "must be positive")
+	}
+
+	if c.SeverityAging.Enabled && len(c.SeverityAging.Rules) == 0 {
+		v.add("severityAging.rules", "no rules configured")
+	}
+	if c.SeverityAging.Interval < 0 {
+		v.add("severityAging.interval", "must not be negative")
+	}
+	for i, rule := range c.SeverityAging.Rules {
+		path := fmt.Sprintf("severityAging.rules[%d]", i)
+
+		v.oneOf(path+".severity", rule.Severity, levels...)
+		v.oneOf(path+".raiseTo", rule.RaiseTo, levels...)
+		if rule.Severity == "" {
+			v.add(path+".severity", "required, the severity of the aged results")
+		}
+		if rule.RaiseTo == "" {
+			v.add(path+".raiseTo", "required, the raised severity")
+		} else if severity.RankIn(levels, rule.Severity) >= 0 && severity.RankIn(levels, rule.RaiseTo) <= severity.RankIn(levels, rule.Severity) {
+			v.add(path+".raiseTo", "must be higher than %s", rule.Severity)
+		}
+		if rule.After <= 0 {
+			v.add(path+".after", "required, the duration a result stays unresolved before its severity is raised")
+		}
+	}
+
+	alerts := make(map[string]bool, len(c.Alerting.Rules))
+	for i, rule := range c.Alerting.Rules {
+		path := fmt.Sprintf("alerting.rules[%d]", i)
+
+		v.oneOf(path+".minimumSeverity", rule.MinimumSeverity, levels...)
+		for j, status := range rule.Status {
+			v.oneOf(fmt.Sprintf("%s.status[%d]", path, j), status, v1alpha2.StatusFail, v1alpha2.StatusWarn, v1alpha2.StatusError)
+		}
+		if rule.Name == "" {
+			v.add(path+".name", "required to identify the alert")
+		} else if alerts[rule.Name] {
+			v.add(path+".name", "duplicate alert %s", rule.Name)
+		}
+		if rule.Threshold < 0 {
+			v.add(path+".threshold", "must not be negative")
+		}
+		if rule.Window <= 0 {
+			v.add(path+".window", "required, the duration the results are counted")
+		}
+		if len(rule.Targets) == 0 && len(rule.Email) == 0 {
+			v.add(path, "alert without targets or email receivers")
+		}
+		if len(rule.Email) > 0 && c.EmailReports.SMTP.Host == "" {
+			v.add(path+".email", "requires emailReports.smtp")
+		}
+
+		alerts[rule.Name] = true
+	}
+
+	v.oneOf("logging.encoding", c.Logging.Encoding, logging.Console, logging.JSON)
+	v.oneOf("logging.level", strings.ToLower(c.Logging.Level), logging.Levels()...)
+	components := make([]string, 0, len(c.Logging.Components))
+	for component := range c.Logging.Components {
+		components = append(components, component)
+	}
+	for _, component := range sortedKeys(components) {
+		v.oneOf("logging.components."+component, strings.ToLower(c.Logging.Components[component]), logging.Levels()...)
+	}
+
+	if _, err := timeformat.New(c.Timestamps.Format, ""); err != nil {
+		v.add("timestamps.format", "%s", err)
+	}
+	if _, err := timeformat.New("", c.Timestamps.Timezone); err != nil {
+		v.add("timestamps.timezone", "%s", err)
+	}
+
+	v.oneOf("maintenance.mode", c.Maintenance.Mode, maintenance.Suppress, maintenance.Queue)
+	for i, q := range c.Maintenance.QuietHours {
+		if _, err := maintenance.ParseQuietHours(q.Days, q.From, q.To, q.Timezone); err != nil {
+			v.add(fmt.Sprintf("maintenance.quietHours[%d]", i), "%s", err)
+		}
+	}
+	for i, w := range c.Maintenance.Windows {
+		if _, err := MaintenanceWindowFromConfig(w); err != nil {
+			v.add(fmt.Sprintf("maintenance.windows[%d]", i), "%s", err)
+		}
+	}
+
+	if c.WasmPlugins.Enabled && len(c.WasmPlugins.Modules) == 0 {
+		v.add("wasmPlugins.modules", "no modules configured")
+	}
+	for i, m := range c.WasmPlugins.Modules {
+		if m.Path == "" {
+			v.add(fmt.Sprintf("wasmPlugins.modules[%d].path", i), "required, the path of the WASI module")
+		}
+	}
+
+	for i, e := range c.Taxonomy.Sources {
+		if e.Name == "" {
+			v.add(fmt.Sprintf("taxonomy.sources[%d].name", i), "required as display name")
+		}
+	}
+	for i, e := range c.Taxonomy.Categories {
+		if e.Name == "" {
+			v.add(fmt.Sprintf("taxonomy.categories[%d].name", i), "required as display name")
+		}
+	}
+
+	if c.Sharding.Enabled && c.LeaderElection.Enabled {
+		v.add("sharding.enabled", "sharding and leaderElection are mutually exclusive, leaderElection is ignored")
+	}
+	if strings.EqualFold(c.Database.Type, memory.Type) {
+		for _, feature := range []struct {
+			path    string
+			enabled bool
+		}{
+			{"history.enabled", c.History.Enabled},
+			{"tombstones.enabled", c.Tombstones.Enabled},
+			{"triage.enabled", c.Triage.Enabled},
+			{"snooze.enabled", c.Snooze.Enabled},
+			{"escalation.enabled", c.Escalation.Enabled},
+			{"emailReports.subscriptions.enabled", c.EmailReports.Subscriptions.Enabled},
+			{"grpc.enabled", c.GRPC.Enabled},
+			{"federation.enabled", c.Federation.Enabled},
+			{"pruning.enabled", c.Pruning.Enabled},
+			{"kyverno.enabled", c.Kyverno.Enabled},
+		} {
+			if feature.enabled {
+				v.add(feature.path, "not supported by the in-memory store, requires a database")
+			}
+		}
+	}
+	v.debounce("debounce", c.Debounce.Window, c.Debounce.MaxDelay)
+	for i, w := range c.Debounce.Windows {
+		v.debounce(fmt.Sprintf("debounce.windows[%d]", i), w.Window, w.MaxDelay)
+	}
+	if c.Pruning.MaxSize != "" {
+		if _, err := resource.ParseQuantity(c.Pruning.MaxSize); err != nil {
+			v.add("pruning.maxSize", "invalid quantity: %s", err)
+		}
+		if sqlite3.DialectFor(c.Database.Type) != sqlite3.SQLite {
+			v.add("pruning.maxSize", "only supported by the embedded SQLite database")
+		}
+	}
+	if c.Database.Encryption.Enabled && c.Database.Encryption.Key == "" && c.Database.Encryption.SecretRef == "" {
+		v.add("database.encryption.key", "required, or a secretRef with an encryptionKey")
+	}
+	if c.Database.DSN != "" && c.Database.Host != "" {
+		v.add("database.dsn", "dsn and host are mutually exclusive, host, database, username and password are ignored")
+	}
+	if len(c.Watch.Namespaces.Include) > 0 && len(c.Watch.Namespaces.Exclude) > 0 {
+		v.add("watch.namespaces", "include and exclude are mutually exclusive, exclude is ignored")
+	}
+	if (c.Federation.Central.URL != "" || c.Federation.Central.SecretRef != "") && c.Federation.ClusterName == "" {
+		v.add("federation.clusterName", "required to push PolicyReports to the central instance")
+	}
+	for _, group := range []struct {
+		path   string
+		fields map[string]string
+	}{
+		{"customFields.values", c.CustomFields.Values},
+		{"customFields.env", c.CustomFields.Env},
+	} {
+		keys := make([]string, 0, len(group.fields))
+		for key := range group.fields {
+			keys = append(keys, key)
+		}
+		for _, key := range sortedKeys(keys) {
+			if c.CustomFields.MetricLabels && !metricLabel.MatchString(key) {
+				v.add(group.path+"."+key, "invalid metric label name")
+			}
+			if group.path == "customFields.env" && group.fields[key] == "" {
+				v.add(group.path+"."+key, "required, the name of the environment variable")
+			}
+		}
+	}
+	if c.Metrics.ClusterLabel && c.ClusterName == "" {
+		v.add("metrics.clusterLabel", "requires the global clusterName")
+	}
+	if c.Federation.Enabled && !c.REST.Enabled {
+		v.add("federation.enabled", "receiving PolicyReports of edge instances requires rest.enabled")
+	}
+	if c.GRPC.Enabled {
+		v.host("grpc.bindAddress", c.GRPC.BindAddress)
+		if c.API.Auth.Enabled && !c.GRPC.Auth.Enabled {
+			v.add("grpc.auth.enabled", "required with api.auth.enabled, the gRPC api would serve the results unauthenticated")
+		}
+		if c.GRPC.Auth.Enabled && !c.API.Auth.Enabled {
+			v.add("grpc.auth.enabled", "requires api.auth.enabled, the gRPC api uses the authentication of the REST api")
+		}
+		if c.GRPC.TLS.Enabled {
+			if c.GRPC.TLS.CertFile == "" {
+				v.add("grpc.tls.certFile", "required, the path of the PEM encoded certificate")
+			}
+			if c.GRPC.TLS.KeyFile == "" {
+				v.add("grpc.tls.keyFile", "required, the path of the PEM encoded private key")
+			}
+			v.oneOf("grpc.tls.minVersion", c.GRPC.TLS.MinVersion, "1.2", "1.3")
+			if c.GRPC.TLS.ReloadInterval < 0 {
+				v.add("grpc.tls.reloadInterval", "must not be negative")
+			}
+		}
+	}
+	if c.Ingestion.Enabled && !c.API.Auth.Enabled {
+		v.add("ingestion.enabled", "receiving results requires api.auth.enabled to authenticate the senders")
+	}
+	if c.Ingestion.Falco.Retention < 0 {
+		v.add("ingestion.falco.retention", "must not be negative")
+	}
+	if c.Ingestion.Falco.MaxResults < 0 {
+		v.add("ingestion.falco.maxResults", "must not be negative")
+	}
+	if c.Ingestion.NDJSON.MaxStreams < 0 {
+		v.add("ingestion.ndjson.maxStreams", "must not be negative")
+	}
+	if c.Ingestion.NDJSON.MaxResults < 0 {
+		v.add("ingestion.ndjson.maxResults", "must not be negative")
+	}
+	if c.Ingestion.NDJSON.IdempotencyTTL < 0 {
+		v.add("ingestion.ndjson.idempotencyTTL", "must not be negative")
+	}
+	v.host("api.host", c.API.Host)
+	v.port("api.port", c.API.Port)
+	if c.API.Admin.Port != 0 {
+		v.host("api.admin.host", c.API.Admin.Host)
+		v.port("api.admin.port", c.API.Admin.Port)
+		if c.API.Admin.Port == c.API.Port {
+			v.add("api.admin.port", "must differ from the API port %d", c.API.Port)
+		}
+	}
+	if c.API.TLS.Enabled {
+		if c.API.TLS.CertFile == "" {
+			v.add("api.tls.certFile", "required, the path of the PEM encoded certificate")
+		}
+		if c.API.TLS.KeyFile == "" {
+			v.add("api.tls.keyFile", "required, the path of the PEM encoded private key")
+		}
+		v.oneOf("api.tls.minVersion", c.API.TLS.MinVersion, "1.2", "1.3")
+		if c.API.TLS.ReloadInterval < 0 {
+			v.add("api.tls.reloadInterval", "must not be negative")
+		}
+	}
+	if c.API.AccessLog.Enabled {
+		v.oneOf("api.accessLog.encoding", c.API.AccessLog.Encoding, logging.JSON, logging.Console)
+		if c.API.AccessLog.SampleRate < 0 || c.API.AccessLog.SampleRate > 1 {
+			v.add("api.accessLog.sampleRate", "must be between 0 and 1")
+		}
+		if c.API.AccessLog.SlowThreshold < 0 {
+			v.add("api.accessLog.slowThreshold", "must not be negative")
+		}
+	}
+	if c.API.Cache.Enabled && c.API.Cache.TTL <= 0 {
+		v.add("api.cache.ttl", "required, the maximum age of cached API responses")
+	}
+	if c.Notifications.Enabled && c.Notifications.TTL <= 0 {
+		v.add("notificationCache.ttl", "required, the time a sent result is not sent again")
+	}
+	if (c.Deduplication.Enabled || c.Notifications.Enabled) && c.Deduplication.Type == "redis" && !c.Redis.Enabled {
+		v.add("deduplication.type", "redis deduplication requires redis.enabled")
+	}
+
+	if c.Coverage.Enabled {
+		for i, value := range c.Coverage.Kinds {
+			if _, err := coverage.ParseKind(value); err != nil {
+				v.add(fmt.Sprintf("coverage.kinds[%d]", i), "%s", err)
+			}
+		}
+		if c.Coverage.Interval < 0 {
+			v.add("coverage.interval", "must not be negative")
+		}
+	}
+
+	if c.Acknowledgments.Enabled {
+		if c.Acknowledgments.Threshold <= 0 {
+			v.add("acknowledgments.threshold", "must be positive")
+		}
+		if c.Acknowledgments.Retention < c.Acknowledgments.Threshold {
+			v.add("acknowledgments.retention", "must not be shorter than the threshold")
+		}
+	}
+
+	if c.ErrorRouting.Enabled && len(c.ErrorRouting.Targets) == 0 {
+		v.add("errorRouting.targets", "required, the names of the operations targets")
+	}
+
+	if c.UILinks.Enabled {
+		if c.UILinks.BaseURL == "" {
+			v.add("uiLinks.baseURL", "required, the external URL of the Policy Reporter UI")
+		} else {
+			v.url("uiLinks.baseURL", c.UILinks.BaseURL)
+		}
+		if _, err := enrichment.NewLinks(c.UILinks.BaseURL, c.UILinks.Path); err != nil {
+			v.add("uiLinks.path", "invalid template: %s", err)
+		}
+	}
+
+	if c.Compliance.Enabled {
+		if c.Compliance.Interval <= 0 {
+			v.add("complianceSummary.interval", "must be positive")
+		}
+		for _, msg := range validation.IsDNS1123Subdomain(c.Compliance.Name) {
+			v.add("complianceSummary.name", "invalid resource name '%s': %s", c.Compliance.Name, msg)
+		}
+	}
+
+	if c.CVSSSeverity.Enabled {
+		t := c.CVSSSeverity.Thresholds
+		for _, threshold := range []struct {
+			path  string
+			value float64
+		}{
+			{"critical", t.Critical}, {"high", t.High}, {"medium", t.Medium}, {"low", t.Low},
+		} {
+			if threshold.value < 0 || threshold.value > 10 {
+				v.add("cvssSeverity.thresholds."+threshold.path, "must be between 0 and 10")
+			}
+		}
+		if !(t.Critical >= t.High && t.High >= t.Medium && t.Medium >= t.Low) {
+			v.add("cvssSeverity.thresholds", "must be descending from critical to low")
+		}
+	}
+
+	if c.Kyverno.Enabled {
+		v.oneOf("kyverno.exceptionVersion", c.Kyverno.ExceptionVersion, "v2alpha1", "v2beta1", "v2")
+		if !c.REST.Enabled {
+			v.add("kyverno.enabled", "the Kyverno policy APIs require rest.enabled")
+		}
+	}
+
+	v.oneOf("mode", c.Mode, ModeCluster, ModeStandalone, ModeAPIOnly)
+	if c.IsStandalone() {
+		if c.Standalone.ReportsDir == "" {
+			v.add("standalone.reportsDir", "required, the directory of the PolicyReport files")
+		}
+		if c.Standalone.Interval < 0 {
+			v.add("standalone.interval", "must not be negative")
+		}
+		for _, path := range ClusterFeatures(c) {
+			v.add(path, "requires a cluster, not available in standalone mode")
+		}
+	}
+	if c.IsAPIOnly() {
+		if sqlite3.DialectFor(c.Database.Type) == sqlite3.SQLite {
+			v.add("database.type", "api-only mode requires an external database shared with the ingesting instance")
+		}
+		if !c.REST.Enabled && !c.GRPC.Enabled {
+			v.add("mode", "api-only mode requires rest.enabled or grpc.enabled")
+		}
+		for _, path := range IngestionFeatures(c) {
+			v.add(path, "requires the ingestion of PolicyReports, not available in api-only mode")
+		}
+	}
+
+	if len(v.problems) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Problems: v.problems}
+}
+
+// ClusterFeatures returns the paths of the enabled features which require access to a cluster
+func ClusterFeatures(c *Config) []string {
+	paths := make([]string, 0)
+	for _, feature := range []struct {
+		path    string
+		enabled bool
+	}{
+		{"leaderElection.enabled", c.LeaderElection.Enabled},
+		{"sharding.enabled", c.Sharding.Enabled},
+		{"resultExclusions.enabled", c.Exclusions.Enabled},
+		{"policyMetadata.enabled", c.PolicyMetadata.Enabled},
+		{"orphanedResults.enabled", c.Orphaned.Enabled},
+		{"ownerResolution.enabled", c.Owners.Enabled},
+		{"gitops.enabled", c.GitOps.Enabled},
+		{"violationAnnotations.enabled", c.Annotations.Enabled},
+		{"admissionWebhook.enabled", c.Admission.Enabled},
+		{"kubernetesEvents.enabled", c.Events.Enabled},
+		{"summaries.patch", c.Summaries.Patch},
+		{"api.auth.kubernetes.enabled", c.API.Auth.Enabled && c.API.Auth.Kubernetes.Enabled},
+		{"coverage.enabled", c.Coverage.Enabled},
+		{"kyverno.enabled", c.Kyverno.Enabled},
+	} {
+		if feature.enabled {
+			paths = append(paths, feature.path)
+		}
+	}
+
+	return paths
+}
+
+// IngestionFeatures returns the paths of the enabled features which process or write the PolicyReports,
+// they run on the ingesting instance and are not available in api-only mode
+func IngestionFeatures(c *Config) []string {
+	paths := make([]string, 0)
+	for _, feature := range []struct {
+		path    string
+		enabled bool
+	}{
+		{"leaderElection.enabled", c.LeaderElection.Enabled},
+		{"sharding.enabled", c.Sharding.Enabled},
+		{"resultExclusions.enabled", c.Exclusions.Enabled},
+		{"policyMetadata.enabled", c.PolicyMetadata.Enabled},
+		{"orphanedResults.enabled", c.Orphaned.Enabled},
+		{"ownerResolution.enabled", c.Owners.Enabled},
+		{"gitops.enabled", c.GitOps.Enabled},
+		{"violationAnnotations.enabled", c.Annotations.Enabled},
+		{"admissionWebhook.enabled", c.Admission.Enabled},
+		{"kubernetesEvents.enabled", c.Events.Enabled},
+		{"summaries.patch", c.Summaries.Patch},
+		{"coverage.enabled", c.Coverage.Enabled},
+		{"federation.enabled", c.Federation.Enabled},
+		{"federation.central.url", c.Federation.Central.URL != "" || c.Federation.Central.SecretRef != ""},
+		{"ingestion.enabled", c.Ingestion.Enabled},
+		{"escalation.enabled", c.Escalation.Enabled},
+		{"severityAging.enabled", c.SeverityAging.Enabled},
+		{"alerting.enabled", c.Alerting.Enabled},
+		{"maintenance.enabled", c.Maintenance.Enabled},
+		{"acknowledgments.enabled", c.Acknowledgments.Enabled},
+	} {
+		if feature.enabled {
+			paths = append(paths, feature.path)
+		}
+	}
+
+	return paths
+}
+
+// CheckSecretRefs verifies that all referenced secrets exist and are readable
+func CheckSecretRefs(ctx context.Context, c *Config, client secrets.Client) error {
+	v := &validator{}
+
+	walkFields(reflect.ValueOf(c).Elem(), "", func(field reflect.StructField, value reflect.Value, path string) {
+		if field.Name != "SecretRef" || value.String() == "" {
+			return
+		}
+
+		if _, err := client.Get(ctx, value.String()); err != nil {
+			v.add(path, "failed to read secret '%s': %s", value.String(), err)
+		}
+	})
+
+	if len(v.problems) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Problems: v.problems}
+}
+
+// walkFields calls fn for each exported field with its mapstructure path, slice items are indexed like channels[0]
+func walkFields(v reflect.Value, path string, fn func(field reflect.StructField, value reflect.Value, path string)) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			fieldPath := tagName(field)
+			if path != "" {
+				fieldPath = path + "." + fieldPath
+			}
+
+			fn(field, v.Field(i), fieldPath)
+			walkFields(v.Field(i), fieldPath, fn)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			walkFields(v.Index(i), fmt.Sprintf("%s[%d]", path, i), fn)
+		}
+	}
+}
+
+func tagName(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]; name != "" {
+		return name
+	}
+
+	return field.Name
+}
+
+func fieldOf(t reflect.Type, name string) reflect.StructField {
+	field, _ := t.FieldByName(name)
+
+	return field
+}
+
+func sortedKeys(keys []string) []string {
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,1017 @@
+package config_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/config"
+	"github.com/kyverno/policy-reporter/pkg/kubernetes/secrets"
+	"github.com/kyverno/policy-reporter/pkg/target/s3"
+	"github.com/kyverno/policy-reporter/pkg/target/teams"
+)
+
+func problems(t *testing.T, err error) map[string]string {
+	list := make(map[string]string)
+	if err == nil {
+		return list
+	}
+
+	verr := &config.ValidationError{}
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected ValidationError, got %s", err)
+	}
+
+	for _, p := range verr.Problems {
+		list[p.Path] = p.Message
+	}
+
+	return list
+}
+
+func Test_Validate(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		c := &config.Config{
+			Slack: config.Slack{
+				Webhook:         "https://hooks.slack.com/services/test",
+				MinimumPriority: "warning",
+				Channels:        []config.Slack{{SecretRef: "slack-channel"}},
+			},
+			Loki: config.Loki{
+				Host:     "http://loki:3100",
+				Channels: []config.Loki{{Sources: []string{"Kyverno"}}},
+			},
+		}
+
+		if err := config.Validate(c); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Targets", func(t *testing.T) {
+		c := &config.Config{
+			Slack: config.Slack{
+				Webhook:         "hooks.slack.com/services/test",
+				MinimumPriority: "warn",
+				Channels:        []config.Slack{{Name: "Team A"}},
+			},
+			Webhook: config.Webhook{Host: "http://", Payload: "reports"},
+			S3:      config.S3{Endpoint: "https://s3.amazonaws.com", Region: "eu-central-1", AccessKeyID: "id", SecretAccessKey: "secret"},
+			Kinesis: config.Kinesis{
+				Channels: []config.Kinesis{{Endpoint: "https://kinesis.eu-central-1.amazonaws.com", SecretRef: "kinesis"}},
+			},
+		}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"slack.webhook", "slack.minimumPriority", "slack.channels[0]", "webhook.host", "webhook.payload", "s3.bucket"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s", path)
+			}
+		}
+		if len(list) != 6 {
+			t.Errorf("expected 6 problems, got %v", list)
+		}
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		c := &config.Config{
+			PriorityMap: config.PriorityMap{"kyverno": "urgent"},
+			Metrics:     config.Metrics{Filter: config.MetricsFilter{Expression: "severity >="}, Mode: "verbose"},
+			Priorities:  config.PriorityMapping{Rules: []config.PriorityRule{{Pattern: "(", Priority: "error"}}},
+			ResultIDs:   []config.ResultID{{Fields: []string{"unknown"}}},
+			Loki:        config.Loki{SecretRef: "Loki_Secret"},
+			Teams:       config.Teams{RemediationTemplate: "{{ .Remediation ", Sources: []string{"kyverno", "regex:(falco"}},
+			CustomFields: config.CustomFields{
+				Values:       map[string]string{"cloud-region": "eu"},
+				Env:          map[string]string{"node": ""},
+				MetricLabels: true,
+			},
+		}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"priorityMap.kyverno", "metrics.filter.expression", "metrics.mode", "priorityMapping", "resultIDs", "loki.secretRef", "teams.remediationTemplate", "teams.sources[1]", "customFields.values.cloud-region", "customFields.env.node"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("Escalation", func(t *testing.T) {
+		c := &config.Config{
+			Escalation: config.Escalation{Enabled: true, Rules: []config.EscalationRule{
+				{Name: "critical", MinimumSeverity: "urgent", Status: []string{"pass"}, Email: []string{"oncall@example.com"}},
+				{Expression: "severity >="},
+			}},
+		}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"escalation.rules[0].minimumSeverity", "escalation.rules[0].status[0]", "escalation.rules[0].after", "escalation.rules[0].email", "escalation.rules[1]", "escalation.rules[1].name", "escalation.rules[1].expression"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("SeverityAging", func(t *testing.T) {
+		c := &config.Config{
+			SeverityAging: config.SeverityAging{Enabled: true, Rules: []config.AgingRule{
+				{Severity: "medium", RaiseTo: "urgent"},
+				{Severity: "high", RaiseTo: "medium", After: time.Hour},
+			}},
+		}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"severityAging.rules[0].raiseTo", "severityAging.rules[0].after", "severityAging.rules[1].raiseTo"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("EmailSubscriptions", func(t *testing.T) {
+		c := &config.Config{
+			EmailReports: config.EmailReports{Subscriptions: config.EmailSubscriptions{Enabled: true, URL: "policy-reporter.example.com"}},
+		}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"emailReports.subscriptions.url", "emailReports.subscriptions.enabled"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("CustomSeverities", func(t *testing.T) {
+		c := &config.Config{
+			Severities:    []config.CustomSeverity{{Name: "unknown", Color: "grey", Priority: "urgent"}},
+			SeverityFloor: config.SeverityFloor{Namespaces: map[string]string{"dev": "unknown", "prod": "negligible"}},
+		}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"customSeverities[0].color", "customSeverities[0].priority", "severityFloor.namespaces.prod"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+		if _, ok := list["severityFloor.namespaces.dev"]; ok {
+			t.Errorf("expected custom severity to be valid, got %v", list)
+		}
+
+		list = problems(t, config.Validate(&config.Config{Severities: []config.CustomSeverity{{Name: "negligible", Above: "unknown"}}}))
+		if _, ok := list["customSeverities"]; !ok {
+			t.Errorf("expected problem for unknown above severity, got %v", list)
+		}
+	})
+
+	t.Run("Snooze", func(t *testing.T) {
+		c := &config.Config{
+			Database: config.Database{Type: "memory"},
+			Snooze:   config.Snooze{Enabled: true, MaxDuration: -time.Hour},
+		}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"snooze.maxDuration", "snooze.refreshInterval", "snooze.enabled"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("Alerting", func(t *testing.T) {
+		c := &config.Config{
+			Alerting: config.Alerting{Enabled: true, Rules: []config.AlertRule{
+				{Name: "critical", MinimumSeverity: "urgent", Threshold: -1, Email: []string{"oncall@example.com"}},
+				{Name: "critical", Window: time.Minute, Expression: "severity >="},
+			}},
+		}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"alerting.rules[0].minimumSeverity", "alerting.rules[0].threshold", "alerting.rules[0].window", "alerting.rules[0].email", "alerting.rules[1]", "alerting.rules[1].name", "alerting.rules[1].expression"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("Maintenance", func(t *testing.T) {
+		c := &config.Config{
+			Maintenance: config.Maintenance{
+				Enabled:    true,
+				Mode:       "digest",
+				QuietHours: []config.QuietHours{{Days: []string{"saturday"}, From: "22:00", To: "6am"}},
+				Windows:    []config.MaintenanceWindow{{Name: "upgrade", Start: "2024-03-01T22:00:00Z", End: "2024-03-01T20:00:00Z"}},
+			},
+		}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"maintenance.mode", "maintenance.quietHours[0]", "maintenance.windows[0]"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("WasmPlugins", func(t *testing.T) {
+		c := &config.Config{
+			WasmPlugins: config.WasmPlugins{Enabled: true, Modules: []config.WasmModule{{Name: "enrich"}}},
+		}
+
+		list := problems(t, config.Validate(c))
+
+		if _, ok := list["wasmPlugins.modules[0].path"]; !ok {
+			t.Errorf("expected problem for wasmPlugins.modules[0].path, got %v", list)
+		}
+	})
+
+	t.Run("Standalone", func(t *testing.T) {
+		c := &config.Config{
+			Mode:           config.ModeStandalone,
+			LeaderElection: config.LeaderElection{Enabled: true},
+			Exclusions:     config.ResultExclusions{Enabled: true},
+		}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"standalone.reportsDir", "leaderElection.enabled", "resultExclusions.enabled"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+
+		list = problems(t, config.Validate(&config.Config{Mode: "local"}))
+		if _, ok := list["mode"]; !ok {
+			t.Errorf("expected problem for mode, got %v", list)
+		}
+	})
+
+	t.Run("APIOnly", func(t *testing.T) {
+		c := &config.Config{
+			Mode:     config.ModeAPIOnly,
+			Database: config.Database{Type: "sqlite"},
+			Alerting: config.Alerting{Enabled: true},
+		}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"mode", "database.type", "alerting.enabled"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+
+		c = &config.Config{
+			Mode:     config.ModeAPIOnly,
+			Database: config.Database{Type: "postgres", Host: "postgres:5432", Database: "policy-reporter"},
+			REST:     config.REST{Enabled: true},
+		}
+
+		list = problems(t, config.Validate(c))
+		for _, path := range []string{"mode", "database.type"} {
+			if _, ok := list[path]; ok {
+				t.Errorf("unexpected problem for %s: %s", path, list[path])
+			}
+		}
+	})
+
+	t.Run("CVSSSeverity", func(t *testing.T) {
+		c := &config.Config{
+			CVSSSeverity: config.CVSSSeverity{Enabled: true, Thresholds: config.CVSSThresholds{Critical: 11, High: 7, Medium: 8, Low: 0.1}},
+		}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"cvssSeverity.thresholds.critical", "cvssSeverity.thresholds"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("ErrorRouting", func(t *testing.T) {
+		list := problems(t, config.Validate(&config.Config{ErrorRouting: config.ErrorRouting{Enabled: true}}))
+
+		if _, ok := list["errorRouting.targets"]; !ok {
+			t.Errorf("expected problem for errorRouting.targets, got %v", list)
+		}
+	})
+
+	t.Run("APIListeners", func(t *testing.T) {
+		c := &config.Config{
+			API: config.API{Host: "fd00::1::2", Port: 8080, Admin: config.APIAdmin{Host: "::", Port: 8080}},
+		}
+
+		list := problems(t, config.Validate(c))
+		for _, path := range []string{"api.host", "api.admin.port"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+		if _, ok := list["api.admin.host"]; ok {
+			t.Errorf("expected no problem for the IPv6 admin host, got %v", list)
+		}
+	})
+
+	t.Run("APITLS", func(t *testing.T) {
+		c := &config.Config{
+			API: config.API{TLS: config.APITLS{Enabled: true, CertFile: "/api-tls/tls.crt", MinVersion: "1.1"}},
+		}
+
+		list := problems(t, config.Validate(c))
+		for _, path := range []string{"api.tls.keyFile", "api.tls.minVersion"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("GRPC", func(t *testing.T) {
+		c := &config.Config{
+			API:  config.API{Auth: config.APIAuth{Enabled: true}},
+			GRPC: config.GRPC{Enabled: true, Port: 9090, TLS: config.APITLS{Enabled: true, KeyFile: "/grpc-tls/tls.key"}},
+		}
+
+		list := problems(t, config.Validate(c))
+		for _, path := range []string{"grpc.auth.enabled", "grpc.tls.certFile"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("APIAccessLog", func(t *testing.T) {
+		c := &config.Config{
+			API: config.API{AccessLog: config.APIAccessLog{Enabled: true, Encoding: "text", SampleRate: 1.5}},
+		}
+
+		list := problems(t, config.Validate(c))
+		for _, path := range []string{"api.accessLog.encoding", "api.accessLog.sampleRate"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("Sources", func(t *testing.T) {
+		c := &config.Config{
+			Sources: config.Sources{Disabled: []string{"Falco"}, Precedence: []string{"kyverno", "falco", "Kyverno"}},
+		}
+
+		list := problems(t, config.Validate(c))
+		for _, path := range []string{"sources.precedence[1]", "sources.precedence[2]"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("UILinks", func(t *testing.T) {
+		c := &config.Config{
+			UILinks: config.UILinks{Enabled: true, BaseURL: "policy-reporter-ui:8080", Path: "{{ .ID "},
+		}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"uiLinks.baseURL", "uiLinks.path"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("ComplianceSummary", func(t *testing.T) {
+		c := &config.Config{
+			Compliance: config.ComplianceSummary{Enabled: true, Name: "Cluster"},
+		}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"complianceSummary.name", "complianceSummary.interval"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("Audit", func(t *testing.T) {
+		c := &config.Config{
+			Audit: config.Audit{Enabled: true, MaxSize: -1},
+		}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"audit.path", "audit.maxSize"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("Acknowledgments", func(t *testing.T) {
+		c := &config.Config{
+			Acknowledgments: config.Acknowledgments{Enabled: true, Threshold: 2 * time.Hour, Retention: time.Hour},
+		}
+
+		list := problems(t, config.Validate(c))
+		if _, ok := list["acknowledgments.retention"]; !ok {
+			t.Errorf("expected problem for acknowledgments.retention, got %v", list)
+		}
+
+		c.Acknowledgments.Threshold = 0
+		list = problems(t, config.Validate(c))
+		if _, ok := list["acknowledgments.threshold"]; !ok {
+			t.Errorf("expected problem for acknowledgments.threshold, got %v", list)
+		}
+	})
+
+	t.Run("Tracing", func(t *testing.T) {
+		c := &config.Config{
+			Tracing: config.Tracing{Enabled: true, Endpoint: "otel-collector:4318", SampleRatio: 1.5},
+		}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"tracing.endpoint", "tracing.sampleRatio"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("Logging", func(t *testing.T) {
+		c := &config.Config{
+			Logging: config.Logging{
+				Encoding:   "text",
+				Level:      "DEBUG",
+				Components: map[string]string{"targets": "verbose"},
+			},
+		}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"logging.encoding", "logging.components.targets"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+		if _, ok := list["logging.level"]; ok {
+			t.Errorf("expected case insensitive level, got %v", list)
+		}
+	})
+
+	t.Run("Taxonomy", func(t *testing.T) {
+		c := &config.Config{
+			Taxonomy: config.Taxonomy{
+				Sources:    []config.TaxonomyEntry{{Match: []string{"kyverno"}}},
+				Categories: []config.TaxonomyEntry{{Name: "Pod Security Standards", Match: []string{"Pod Security Standards*"}}},
+			},
+		}
+
+		list := problems(t, config.Validate(c))
+
+		if _, ok := list["taxonomy.sources[0].name"]; !ok {
+			t.Errorf("expected problem for the missing display name, got %v", list)
+		}
+		if _, ok := list["taxonomy.categories[0].name"]; ok {
+			t.Errorf("expected no problem for a valid entry")
+		}
+	})
+
+	t.Run("MemoryStore", func(t *testing.T) {
+		c := &config.Config{
+			Database: config.Database{Type: "memory"},
+			History:  config.History{Enabled: true},
+			GRPC:     config.GRPC{Enabled: true},
+		}
+
+		list := problems(t, config.Validate(c))
+
+		if _, ok := list["database.type"]; ok {
+			t.Errorf("expected memory to be a valid database type")
+		}
+		if _, ok := list["history.enabled"]; !ok {
+			t.Errorf("expected problem for history with the in-memory store, got %v", list)
+		}
+		if _, ok := list["grpc.enabled"]; !ok {
+			t.Errorf("expected problem for gRPC with the in-memory store, got %v", list)
+		}
+		if _, ok := list["escalation.enabled"]; ok {
+			t.Errorf("expected no problem for disabled escalation")
+		}
+	})
+
+	t.Run("DatabaseEncryption", func(t *testing.T) {
+		list := problems(t, config.Validate(&config.Config{Database: config.Database{Encryption: config.DatabaseEncryption{Enabled: true}}}))
+
+		if _, ok := list["database.encryption.key"]; !ok {
+			t.Errorf("expected problem for the missing encryption key, got %v", list)
+		}
+
+		list = problems(t, config.Validate(&config.Config{Database: config.Database{Encryption: config.DatabaseEncryption{Enabled: true, SecretRef: "db-key"}}}))
+
+		if _, ok := list["database.encryption.key"]; ok {
+			t.Errorf("expected secretRef to provide the key")
+		}
+	})
+
+	t.Run("Pruning", func(t *testing.T) {
+		list := problems(t, config.Validate(&config.Config{Database: config.Database{Type: "postgres"}, Pruning: config.Pruning{MaxSize: "500Mi"}}))
+
+		if _, ok := list["pruning.maxSize"]; !ok {
+			t.Errorf("expected problem for a size limit of an external database, got %v", list)
+		}
+
+		list = problems(t, config.Validate(&config.Config{Pruning: config.Pruning{MaxSize: "500Mi"}}))
+
+		if _, ok := list["pruning.maxSize"]; ok {
+			t.Errorf("expected valid size limit for SQLite, got %v", list)
+		}
+	})
+
+	t.Run("SeverityFloor", func(t *testing.T) {
+		c := &config.Config{SeverityFloor: config.SeverityFloor{Namespaces: map[string]string{"kube-system": "urgent", "regex:prod-(": "high", "prod-*": "medium"}}}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"severityFloor.namespaces.kube-system", "severityFloor.namespaces.regex:prod-("} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+		if _, ok := list["severityFloor.namespaces.prod-*"]; ok {
+			t.Errorf("expected valid severity floor, got %v", list)
+		}
+	})
+
+	t.Run("PropertyFields", func(t *testing.T) {
+		c := &config.Config{PropertyFields: []config.PropertyField{
+			{Property: "cvss", Field: "score", Type: "float"},
+			{Property: "score", Field: "score"},
+			{Property: "id", Field: "id", Type: "decimal"},
+			{Field: "image"},
+		}}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"propertyFields[1].field", "propertyFields[2].field", "propertyFields[2].type", "propertyFields[3].property"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+		if _, ok := list["propertyFields[0].field"]; ok {
+			t.Errorf("expected valid property field, got %v", list)
+		}
+	})
+
+	t.Run("OrphanedResults", func(t *testing.T) {
+		list := problems(t, config.Validate(&config.Config{Orphaned: config.OrphanedResults{ExcludeFromTargets: true}}))
+		if _, ok := list["orphanedResults.enabled"]; !ok {
+			t.Errorf("expected problem for orphanedResults.enabled, got %v", list)
+		}
+	})
+
+	t.Run("AdmissionWebhook", func(t *testing.T) {
+		list := problems(t, config.Validate(&config.Config{Admission: config.AdmissionWebhook{Enabled: true, Mode: "reject"}}))
+
+		for _, path := range []string{"admissionWebhook.mode", "admissionWebhook.certFile", "admissionWebhook.keyFile"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("SlackBlockKit", func(t *testing.T) {
+		list := problems(t, config.Validate(&config.Config{Slack: config.Slack{
+			Token:         "xoxb-token",
+			TitleTemplate: "{{ .Policy",
+			Actions:       []config.Action{{URL: "{{ .Resource.Name }}"}},
+			Channels:      []config.Slack{{Token: "xoxb-token", Channel: "C123"}},
+		}}))
+
+		for _, path := range []string{"slack.channel", "slack.titleTemplate", "slack.actions[0].text"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+		for _, path := range []string{"slack.actions[0].url", "slack.channels[0]"} {
+			if _, ok := list[path]; ok {
+				t.Errorf("unexpected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("TeamsFormat", func(t *testing.T) {
+		list := problems(t, config.Validate(&config.Config{Teams: config.Teams{
+			Format:   "card",
+			Channels: []config.Teams{{Webhook: "https://workflow.example.com", Format: teams.FormatAdaptiveCard}},
+		}}))
+
+		if _, ok := list["teams.format"]; !ok {
+			t.Errorf("expected problem for teams.format, got %v", list)
+		}
+		if _, ok := list["teams.channels[0].format"]; ok {
+			t.Errorf("unexpected problem for teams.channels[0].format, got %v", list)
+		}
+	})
+
+	t.Run("DiscordMentions", func(t *testing.T) {
+		list := problems(t, config.Validate(&config.Config{Discord: config.Discord{
+			Mentions: map[string][]string{"urgent": {"123"}, "critical": {"456"}},
+		}}))
+
+		if _, ok := list["discord.mentions.urgent"]; !ok {
+			t.Errorf("expected problem for discord.mentions.urgent, got %v", list)
+		}
+		if _, ok := list["discord.mentions.critical"]; ok {
+			t.Errorf("unexpected problem for discord.mentions.critical, got %v", list)
+		}
+	})
+
+	t.Run("Telegram", func(t *testing.T) {
+		list := problems(t, config.Validate(&config.Config{Telegram: config.Telegram{
+			Token:    "123:token",
+			Host:     "telegram.local",
+			Channels: []config.Telegram{{Name: "Team A"}, {ChatID: "-100123"}},
+		}}))
+
+		for _, path := range []string{"telegram.chatID", "telegram.host", "telegram.channels[0].chatID"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+		if _, ok := list["telegram.channels[1].chatID"]; ok {
+			t.Errorf("unexpected problem for telegram.channels[1].chatID, got %v", list)
+		}
+	})
+
+	t.Run("WebhookAuth", func(t *testing.T) {
+		list := problems(t, config.Validate(&config.Config{Webhook: config.Webhook{
+			Host: "https://receiver.example.com",
+			Auth: config.WebhookAuth{Template: "Bearer {{ .Token", Token: "1234", OAuth2: config.OAuth2{TokenURL: "https://auth.example.com/token"}},
+			Channels: []config.Webhook{{
+				Host: "https://receiver.example.com/b",
+				Auth: config.WebhookAuth{OAuth2: config.OAuth2{ClientID: "policy-reporter"}},
+			}},
+		}}))
+
+		for _, path := range []string{"webhook.auth.template", "webhook.auth.token", "webhook.auth.oauth2.clientID", "webhook.channels[0].auth.oauth2.tokenURL"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("SecurityHub", func(t *testing.T) {
+		list := problems(t, config.Validate(&config.Config{
+			SecurityHub: config.SecurityHub{
+				AccountID:   "1234",
+				AccessKeyID: "id",
+				Channels:    []config.SecurityHub{{Endpoint: "localhost:4566"}},
+			},
+		}))
+
+		for _, path := range []string{"securityHub.accountID", "securityHub.region", "securityHub", "securityHub.channels[0].endpoint"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("SecurityCenter", func(t *testing.T) {
+		list := problems(t, config.Validate(&config.Config{
+			SecurityCenter: config.SecurityCenter{
+				OrganizationID: "org",
+				Source:         "sources/456",
+				Credentials:    "{}",
+				ProjectID:      "project",
+				Channels:       []config.SecurityCenter{{Host: "localhost"}},
+			},
+		}))
+
+		for _, path := range []string{"securityCommandCenter.organizationID", "securityCommandCenter.source", "securityCommandCenter.credentials", "securityCommandCenter", "securityCommandCenter.channels[0].host"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("Defender", func(t *testing.T) {
+		list := problems(t, config.Validate(&config.Config{
+			Defender: config.Defender{
+				SubscriptionID: "00000000-0000-0000-0000-000000000001",
+				TenantID:       "contoso",
+				ResourceID:     "/subscriptions/00000000-0000-0000-0000-000000000009/resourceGroups/rg",
+				Channels:       []config.Defender{{Host: "management.azure.com", ClientID: "client"}},
+			},
+		}))
+
+		for _, path := range []string{"defenderForCloud.tenantID", "defenderForCloud.resourceID", "defenderForCloud.channels[0].host", "defenderForCloud.channels[0].clientID"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("DefectDojo", func(t *testing.T) {
+		list := problems(t, config.Validate(&config.Config{
+			DefectDojo: config.DefectDojo{
+				Host:     "defectdojo.local",
+				APIKey:   "key",
+				Channels: []config.DefectDojo{{Product: "platform"}},
+			},
+		}))
+
+		for _, path := range []string{"defectDojo.host", "defectDojo.product"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+		if _, ok := list["defectDojo.channels[0].product"]; ok {
+			t.Errorf("expected no problem for the channel product, got %v", list)
+		}
+	})
+
+	t.Run("Kinesis", func(t *testing.T) {
+		list := problems(t, config.Validate(&config.Config{
+			Kinesis: config.Kinesis{
+				Endpoint: "https://kinesis.eu-central-1.amazonaws.com", Region: "eu-central-1", AccessKeyID: "id", SecretAccessKey: "secret",
+				PartitionKey: "{{ .Policy",
+				Channels:     []config.Kinesis{{StreamName: "results", DeliveryStream: "results"}},
+			},
+		}))
+
+		for _, path := range []string{"kinesis.streamName", "kinesis.partitionKey", "kinesis.channels[0].deliveryStream"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("S3", func(t *testing.T) {
+		list := problems(t, config.Validate(&config.Config{
+			S3: config.S3{
+				Endpoint: "https://s3.amazonaws.com", Region: "eu-central-1", AccessKeyID: "id", SecretAccessKey: "secret", Bucket: "reports",
+				Format:     "csv",
+				Encryption: "AES256",
+				KMSKeyID:   "key",
+				Channels:   []config.S3{{Format: s3.FormatParquet, FlushSize: -1, Encryption: "aws:kms"}},
+			},
+		}))
+
+		for _, path := range []string{"s3.format", "s3.kmsKeyID", "s3.channels[0].flushSize"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+		if _, ok := list["s3.channels[0].format"]; ok {
+			t.Errorf("unexpected problem for parquet format: %v", list)
+		}
+	})
+
+	t.Run("HTTPClient", func(t *testing.T) {
+		list := problems(t, config.Validate(&config.Config{
+			HTTPClient: config.HTTPClient{Timeout: -time.Second},
+			Loki:       config.Loki{Host: "http://loki:3100", Channels: []config.Loki{{HTTP: config.HTTPClient{MaxIdleConnsPerHost: -1}}}},
+		}))
+
+		for _, path := range []string{"httpClient.timeout", "loki.channels[0].http.maxIdleConnsPerHost"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("TargetHealth", func(t *testing.T) {
+		list := problems(t, config.Validate(&config.Config{TargetHealth: config.TargetHealth{Critical: []string{"Slack"}, Action: "crash"}}))
+
+		for _, path := range []string{"targetHealth.threshold", "targetHealth.action"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("Timestamps", func(t *testing.T) {
+		list := problems(t, config.Validate(&config.Config{Timestamps: config.Timestamps{Format: "iso", Timezone: "Mars/Olympus"}}))
+
+		for _, path := range []string{"timestamps.format", "timestamps.timezone"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("ResultAge", func(t *testing.T) {
+		c := &config.Config{ResultAge: config.ResultAge{MaxAge: -time.Hour, Sources: map[string]time.Duration{"trivy": -time.Minute, "kube-bench": 0}}}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"resultAge.maxAge", "resultAge.sources.trivy"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+		if _, ok := list["resultAge.sources.kube-bench"]; ok {
+			t.Errorf("expected disabled source to be valid, got %v", list)
+		}
+
+		list = problems(t, config.Validate(&config.Config{ResultAge: config.ResultAge{DropFromStore: true}}))
+		if _, ok := list["resultAge.dropFromStore"]; !ok {
+			t.Errorf("expected problem for dropFromStore without maximum age, got %v", list)
+		}
+	})
+
+	t.Run("Sampling", func(t *testing.T) {
+		c := &config.Config{Slack: config.Slack{Channels: []config.Slack{{Filter: config.TargetFilter{
+			Sampling: []config.SamplingRule{{Policies: []string{"audit-*"}, Limit: -1, Window: -time.Hour}},
+		}}}}}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"slack.channels[0].filter.sampling[0].limit", "slack.channels[0].filter.sampling[0].window"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("Grouping", func(t *testing.T) {
+		c := &config.Config{Slack: config.Slack{Filter: config.TargetFilter{GroupBy: []string{"policy", ""}, GroupWindow: -time.Minute}}}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"slack.filter.groupBy[1]", "slack.filter.groupWindow"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("DeliveryWindows", func(t *testing.T) {
+		c := &config.Config{Slack: config.Slack{Filter: config.TargetFilter{DeliveryWindows: []config.QuietHours{
+			{Days: []string{"monday"}, From: "09:00", To: "17:00", Timezone: "Europe/Berlin"},
+			{Days: []string{"someday"}, From: "09:00", To: "17:00"},
+		}}}}
+
+		list := problems(t, config.Validate(c))
+
+		if _, ok := list["slack.filter.deliveryWindows[1]"]; !ok {
+			t.Errorf("expected problem for slack.filter.deliveryWindows[1], got %v", list)
+		}
+		if _, ok := list["slack.filter.deliveryWindows[0]"]; ok {
+			t.Errorf("expected no problem for slack.filter.deliveryWindows[0], got %v", list)
+		}
+	})
+
+	t.Run("Scrub", func(t *testing.T) {
+		c := &config.Config{Slack: config.Slack{Filter: config.TargetFilter{Scrub: []config.ScrubRule{
+			{Pattern: "[a-z]+@example\\.com", Condition: "result.source == 'kyverno'"},
+			{Pattern: "secret ("},
+			{Pattern: "secret", Condition: "result.source =="},
+		}}}}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"slack.filter.scrub[1].pattern", "slack.filter.scrub[2].condition"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+		for _, path := range []string{"slack.filter.scrub[0].pattern", "slack.filter.scrub[0].condition"} {
+			if _, ok := list[path]; ok {
+				t.Errorf("expected no problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("SendExistingResults", func(t *testing.T) {
+		c := &config.Config{
+			Slack:   config.Slack{Filter: config.TargetFilter{SendExistingResults: "since=1d"}},
+			Discord: config.Discord{Filter: config.TargetFilter{SendExistingResults: "since=24h"}},
+		}
+
+		list := problems(t, config.Validate(c))
+
+		if _, ok := list["slack.filter.sendExistingResults"]; !ok {
+			t.Errorf("expected problem for slack.filter.sendExistingResults, got %v", list)
+		}
+		if _, ok := list["discord.filter.sendExistingResults"]; ok {
+			t.Errorf("expected no problem for discord.filter.sendExistingResults, got %v", list)
+		}
+	})
+
+	t.Run("Debounce", func(t *testing.T) {
+		c := &config.Config{Debounce: config.Debounce{
+			Window:   time.Minute,
+			MaxDelay: time.Second,
+			Windows:  []config.DebounceWindow{{Sources: []string{"kyverno"}, Window: -time.Second}},
+		}}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"debounce.maxDelay", "debounce.windows[0].window"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("MetricSources", func(t *testing.T) {
+		c := &config.Config{Metrics: config.Metrics{
+			Prefix: "policy-reporter",
+			Sources: []config.MetricsSource{
+				{Source: "Trivy Vulnerability", Name: "trivy-vulnerability"},
+				{Source: "trivy vulnerability"},
+				{Labels: []string{"unknown"}},
+			},
+		}}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"metrics.prefix", "metrics.sources[0].name", "metrics.sources[1].source", "metrics.sources[2].source", "metrics.sources[2].labels"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("MutuallyExclusive", func(t *testing.T) {
+		c := &config.Config{
+			Sharding:       config.Sharding{Enabled: true},
+			LeaderElection: config.LeaderElection{Enabled: true},
+			Database:       config.Database{Type: "postgres", DSN: "postgres://db", Host: "db"},
+			Watch:          config.Watch{Namespaces: config.ValueFilter{Include: []string{"a"}, Exclude: []string{"b"}}},
+			Deduplication:  config.Deduplication{Enabled: true, Type: "redis"},
+			Federation:     config.Federation{Enabled: true, Central: config.FederationCentral{URL: "https://central"}},
+			Metrics:        config.Metrics{ClusterLabel: true},
+			Ingestion:      config.Ingestion{Enabled: true, Falco: config.FalcoIngestion{MaxResults: -1}},
+			Notifications:  config.NotificationCache{Enabled: true},
+			API:            config.API{Cache: config.APICache{Enabled: true}},
+		}
+
+		list := problems(t, config.Validate(c))
+
+		for _, path := range []string{"sharding.enabled", "database.dsn", "watch.namespaces", "deduplication.type", "federation.clusterName", "federation.enabled", "metrics.clusterLabel", "ingestion.enabled", "ingestion.falco.maxResults", "notificationCache.ttl", "api.cache.ttl"} {
+			if _, ok := list[path]; !ok {
+				t.Errorf("expected problem for %s, got %v", path, list)
+			}
+		}
+	})
+
+	t.Run("UnknownKeys", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "config.yaml")
+		content := "slack:\n  webhok: https://hooks.slack.com/services/test\n  channels:\n  - webhook: https://hooks.slack.com/services/channel\n    minimumPriorty: warning\n"
+		if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		cmd := createCMD()
+		_ = cmd.Flags().Set("config", file)
+
+		c, err := config.Load(cmd)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		list := problems(t, config.Validate(c))
+		if _, ok := list["slack.webhok"]; !ok {
+			t.Errorf("expected unknown option slack.webhok, got %v", list)
+		}
+		if _, ok := list["slack.channels[0].minimumpriorty"]; !ok {
+			t.Errorf("expected unknown option slack.channels[0].minimumpriorty, got %v", list)
+		}
+	})
+}
+
+type secretClient struct{}
+
+func (c secretClient) Get(_ context.Context, name string) (secrets.Values, error) {
+	if name == "existing" {
+		return secrets.Values{}, nil
+	}
+
+	return secrets.Values{}, errors.New("not found")
+}
+
+func Test_CheckSecretRefs(t *testing.T) {
+	c := &config.Config{
+		Slack:    config.Slack{SecretRef: "existing"},
+		Database: config.Database{SecretRef: "missing"},
+	}
+
+	list := problems(t, config.CheckSecretRefs(context.Background(), c, secretClient{}))
+	if len(list) != 1 || list["database.secretRef"] == "" {
+		t.Errorf("expected missing database secret, got %v", list)
+	}
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// WatchFile checks the loaded configuration file and its overlays for changes and calls onChange with each new configuration,
+// configurations failing to load or to validate in strict mode are logged and skipped.
+// Files mounted from a ConfigMap or Secret are replaced by symlink swaps, so the content is compared instead of file events
+func WatchFile(ctx context.Context, cmd *cobra.Command, current *Config, onChange func(*Config)) error {
+	if current.file == "" {
+		logger.Warn("configuration reload requires a configuration file")
+		return nil
+	}
+
+	interval := current.Reload.Interval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	files := append([]string{current.file}, current.overlays...)
+
+	checksum, _ := fileChecksum(files...)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			sum, err := fileChecksum(files...)
+			if err != nil {
+				logger.Errorf("failed to read configuration file: %s", err)
+				continue
+			}
+			if sum == checksum {
+				continue
+			}
+
+			checksum = sum
+
+			c, err := Load(cmd)
+			if err != nil {
+				logger.Errorf("changed configuration not applied: %s", err)
+				continue
+			}
+
+			if err := Validate(c); err != nil {
+				if strict, _ := cmd.Flags().GetBool("strict"); strict {
+					logger.Errorf("changed configuration not applied: %s", err)
+					continue
+				}
+
+				logger.Warnf("%s", err)
+			}
+
+			logger.Info("configuration file changed, reload targets and metric filters")
+			onChange(c)
+		}
+	}
+}
+
+func fileChecksum(files ...string) ([32]byte, error) {
+	h := sha256.New()
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return [32]byte{}, err
+		}
+
+		h.Write(content)
+	}
+
+	var sum [32]byte
+	copy(sum[:], h.Sum(nil))
+
+	return sum, nil
+}
@@ -0,0 +1,48 @@
+package config_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/config"
+)
+
+func Test_WatchFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(file, []byte("reload:\n  interval: 10ms\nslack:\n  webhook: http://slack\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := createCMD()
+	_ = cmd.Flags().Set("config", file)
+
+	c, err := config.Load(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	changed := make(chan *config.Config, 1)
+	go config.WatchFile(ctx, cmd, c, func(c *config.Config) {
+		changed <- c
+	})
+
+	time.Sleep(50 * time.Millisecond)
+	if err := os.WriteFile(file, []byte("reload:\n  interval: 10ms\nslack:\n  webhook: http://changed\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case c := <-changed:
+		if c.Slack.Webhook != "http://changed" {
+			t.Errorf("expected changed webhook, got %s", c.Slack.Webhook)
+		}
+	case <-ctx.Done():
+		t.Error("expected reload of the changed configuration")
+	}
+}
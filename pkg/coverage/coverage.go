@@ -0,0 +1,321 @@
+// Package coverage finds scanning gaps: workloads of the selected kinds without any result of a source.
+//
+// The Index collects the resources of all results per source from the PolicyReport events, the Scanner lists the
+// workloads of the selected kinds periodically and counts the workloads which are not referenced by any result.
+package coverage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+var logger = logging.Component("coverage")
+
+// Listener name of the Index
+const Listener = "coverage_listener"
+
+// DefaultKinds are scanned if no kinds are configured
+var DefaultKinds = []string{"apps/v1/Deployment", "apps/v1/StatefulSet", "apps/v1/DaemonSet", "batch/v1/CronJob"}
+
+// DefaultInterval of the scans
+const DefaultInterval = 10 * time.Minute
+
+// DefaultMaxResources listed per kind and source in the API response
+const DefaultMaxResources = 100
+
+// Kind of the scanned workloads
+type Kind struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+}
+
+func (k Kind) String() string {
+	return k.APIVersion + "/" + k.Kind
+}
+
+// ParseKind parses apiVersion/kind values like apps/v1/Deployment or v1/Pod
+func ParseKind(value string) (Kind, error) {
+	index := strings.LastIndex(value, "/")
+	if index <= 0 || index == len(value)-1 {
+		return Kind{}, fmt.Errorf("invalid kind %q, expected apiVersion/kind like apps/v1/Deployment", value)
+	}
+
+	return Kind{APIVersion: value[:index], Kind: value[index+1:]}, nil
+}
+
+// Resource without results
+type Resource struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+func resourceKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// Index of the resources with results per source
+type Index struct {
+	mx *sync.RWMutex
+	// reports maps the report ID to the resource keys of its results per source
+	reports map[string]map[string]map[string]bool
+}
+
+// Listen updates the resources of the report
+func (i *Index) Listen(event report.LifecycleEvent) {
+	id := event.PolicyReport.GetID()
+
+	if event.Type == report.Deleted {
+		i.mx.Lock()
+		delete(i.reports, id)
+		i.mx.Unlock()
+		return
+	}
+
+	sources := make(map[string]map[string]bool)
+	for _, result := range event.PolicyReport.GetResults() {
+		for _, resource := range result.Resources {
+			if sources[result.Source] == nil {
+				sources[result.Source] = make(map[string]bool)
+			}
+
+			sources[result.Source][resourceKey(resource.Kind, resource.Namespace, resource.Name)] = true
+		}
+	}
+
+	i.mx.Lock()
+	i.reports[id] = sources
+	i.mx.Unlock()
+}
+
+// Sources returns the sorted sources with results
+func (i *Index) Sources() []string {
+	i.mx.RLock()
+	defer i.mx.RUnlock()
+
+	unique := make(map[string]bool)
+	for _, sources := range i.reports {
+		for source := range sources {
+			unique[source] = true
+		}
+	}
+
+	list := make([]string, 0, len(unique))
+	for source := range unique {
+		list = append(list, source)
+	}
+
+	sort.Strings(list)
+
+	return list
+}
+
+// covered returns the resource keys with results per source
+func (i *Index) covered() map[string]map[string]bool {
+	i.mx.RLock()
+	defer i.mx.RUnlock()
+
+	covered := make(map[string]map[string]bool)
+	for _, sources := range i.reports {
+		for source, keys := range sources {
+			if covered[source] == nil {
+				covered[source] = make(map[string]bool, len(keys))
+			}
+			for key := range keys {
+				covered[source][key] = true
+			}
+		}
+	}
+
+	return covered
+}
+
+// NewIndex creates an empty Index
+func NewIndex() *Index {
+	return &Index{mx: new(sync.RWMutex), reports: make(map[string]map[string]map[string]bool)}
+}
+
+// Lister lists the workloads of a kind
+type Lister interface {
+	List(ctx context.Context, kind Kind) ([]Resource, error)
+}
+
+// SourceCoverage of a kind
+type SourceCoverage struct {
+	Source    string `json:"source"`
+	Covered   int    `json:"covered"`
+	Uncovered int    `json:"uncovered"`
+	// Resources without results of the source, limited to the configured maximum
+	Resources []Resource `json:"resources,omitempty"`
+}
+
+// KindCoverage counts the workloads of a kind without results
+type KindCoverage struct {
+	Kind
+	Resources int `json:"resources"`
+	// Unevaluated workloads have no result of any source
+	Unevaluated int              `json:"unevaluated"`
+	Sources     []SourceCoverage `json:"sources"`
+	Error       string           `json:"error,omitempty"`
+}
+
+// Report of a scan
+type Report struct {
+	Scanned time.Time      `json:"scanned"`
+	Kinds   []KindCoverage `json:"kinds"`
+}
+
+// Options of the Scanner
+type Options struct {
+	Kinds []Kind
+	// Sources expected to evaluate each workload, all sources of the Index if empty
+	Sources    []string
+	Namespaces validate.RuleSets
+	// MaxResources listed per kind and source
+	MaxResources int
+}
+
+// Scanner counts the workloads without results
+type Scanner struct {
+	lister   Lister
+	index    *Index
+	options  Options
+	observer func(Report)
+
+	mx   *sync.RWMutex
+	last Report
+}
+
+// Scan lists the workloads of all kinds and compares them with the results of the Index
+func (s *Scanner) Scan(ctx context.Context) Report {
+	sources := s.options.Sources
+	if len(sources) == 0 {
+		sources = s.index.Sources()
+	}
+
+	covered := s.index.covered()
+	rep := Report{Scanned: time.Now(), Kinds: make([]KindCoverage, 0, len(s.options.Kinds))}
+
+	for _, kind := range s.options.Kinds {
+		kc := KindCoverage{Kind: kind, Sources: make([]SourceCoverage, 0, len(sources))}
+
+		resources, err := s.lister.List(ctx, kind)
+		if err != nil {
+			logger.Errorf("coverage: failed to list %s: %s", kind, err)
+			kc.Error = err.Error()
+			rep.Kinds = append(rep.Kinds, kc)
+			continue
+		}
+
+		keys := make([]string, 0, len(resources))
+		filtered := make([]Resource, 0, len(resources))
+		for _, resource := range resources {
+			if resource.Namespace != "" && !validate.Namespace(resource.Namespace, s.options.Namespaces) {
+				continue
+			}
+
+			filtered = append(filtered, resource)
+			keys = append(keys, resourceKey(kind.Kind, resource.Namespace, resource.Name))
+		}
+
+		kc.Resources = len(filtered)
+
+		for i := range filtered {
+			evaluated := false
+			for _, c := range covered {
+				if c[keys[i]] {
+					evaluated = true
+					break
+				}
+			}
+			if !evaluated {
+				kc.Unevaluated++
+			}
+		}
+
+		for _, source := range sources {
+			sc := SourceCoverage{Source: source}
+			for i, resource := range filtered {
+				if covered[source][keys[i]] {
+					sc.Covered++
+					continue
+				}
+
+				sc.Uncovered++
+				if len(sc.Resources) < s.options.MaxResources {
+					sc.Resources = append(sc.Resources, resource)
+				}
+			}
+
+			kc.Sources = append(kc.Sources, sc)
+		}
+
+		rep.Kinds = append(rep.Kinds, kc)
+	}
+
+	s.mx.Lock()
+	s.last = rep
+	s.mx.Unlock()
+
+	if s.observer != nil {
+		s.observer(rep)
+	}
+
+	return rep
+}
+
+// Report returns the last scan
+func (s *Scanner) Report() Report {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	return s.last
+}
+
+// Run scans the workloads in the interval until the context is done, the first scan waits one interval for the initial reports
+func (s *Scanner) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.Scan(ctx)
+		}
+	}
+}
+
+// NewScanner creates a Scanner, the observer is called with each scan, e.g. to update the metrics
+func NewScanner(lister Lister, index *Index, options Options, observer func(Report)) *Scanner {
+	if len(options.Kinds) == 0 {
+		for _, value := range DefaultKinds {
+			kind, _ := ParseKind(value)
+			options.Kinds = append(options.Kinds, kind)
+		}
+	}
+	if options.MaxResources <= 0 {
+		options.MaxResources = DefaultMaxResources
+	}
+
+	return &Scanner{
+		lister:   lister,
+		index:    index,
+		options:  options,
+		observer: observer,
+		mx:       new(sync.RWMutex),
+		last:     Report{Kinds: []KindCoverage{}},
+	}
+}
@@ -0,0 +1,193 @@
+package coverage_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	metafake "k8s.io/client-go/metadata/fake"
+
+	"github.com/kyverno/policy-reporter/pkg/coverage"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+func deployment(namespace, name string) *metav1.PartialObjectMetadata {
+	return &metav1.PartialObjectMetadata{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+}
+
+func newLister() coverage.Lister {
+	kube := kubefake.NewSimpleClientset()
+	kube.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "apps/v1",
+		APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment", Namespaced: true}},
+	}}
+
+	scheme := metafake.NewTestScheme()
+	metav1.AddMetaToScheme(scheme)
+
+	client := metafake.NewSimpleMetadataClient(scheme,
+		deployment("test", "nginx"),
+		deployment("test", "redis"),
+		deployment("kube-system", "coredns"),
+	)
+
+	return coverage.NewMetadataLister(client, kube.Discovery())
+}
+
+func newReport(name, source string, resources ...string) *v1alpha2.PolicyReport {
+	results := make([]v1alpha2.PolicyReportResult, 0, len(resources))
+	for _, resource := range resources {
+		results = append(results, v1alpha2.PolicyReportResult{
+			Source:    source,
+			Policy:    "require-labels",
+			Result:    v1alpha2.StatusPass,
+			Resources: []corev1.ObjectReference{{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "test", Name: resource}},
+		})
+	}
+
+	return &v1alpha2.PolicyReport{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"}, Results: results}
+}
+
+func Test_ParseKind(t *testing.T) {
+	kind, err := coverage.ParseKind("apps/v1/Deployment")
+	if err != nil || kind.APIVersion != "apps/v1" || kind.Kind != "Deployment" {
+		t.Errorf("unexpected kind %+v: %v", kind, err)
+	}
+
+	kind, err = coverage.ParseKind("v1/Pod")
+	if err != nil || kind.APIVersion != "v1" || kind.Kind != "Pod" {
+		t.Errorf("unexpected kind %+v: %v", kind, err)
+	}
+
+	for _, value := range []string{"Deployment", "apps/v1/", "/Deployment"} {
+		if _, err := coverage.ParseKind(value); err == nil {
+			t.Errorf("expected error for %s", value)
+		}
+	}
+}
+
+func Test_Scanner(t *testing.T) {
+	index := coverage.NewIndex()
+	index.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: newReport("kyverno", "kyverno", "nginx", "redis")})
+	index.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: newReport("trivy", "trivy", "nginx")})
+
+	var observed coverage.Report
+
+	scanner := coverage.NewScanner(newLister(), index, coverage.Options{
+		Kinds: []coverage.Kind{{APIVersion: "apps/v1", Kind: "Deployment"}, {APIVersion: "example.io/v1", Kind: "Unknown"}},
+	}, func(rep coverage.Report) { observed = rep })
+
+	rep := scanner.Scan(context.Background())
+	if len(observed.Kinds) != 2 {
+		t.Fatalf("expected the observer to be called with 2 kinds, got %+v", observed)
+	}
+
+	deployments := rep.Kinds[0]
+	if deployments.Resources != 3 || deployments.Unevaluated != 1 {
+		t.Errorf("expected 3 deployments with 1 unevaluated, got %+v", deployments)
+	}
+	if len(deployments.Sources) != 2 {
+		t.Fatalf("expected coverage of 2 sources, got %+v", deployments.Sources)
+	}
+
+	kyverno, trivy := deployments.Sources[0], deployments.Sources[1]
+	if kyverno.Source != "kyverno" || kyverno.Covered != 2 || kyverno.Uncovered != 1 {
+		t.Errorf("unexpected kyverno coverage %+v", kyverno)
+	}
+	if trivy.Source != "trivy" || trivy.Covered != 1 || trivy.Uncovered != 2 {
+		t.Errorf("unexpected trivy coverage %+v", trivy)
+	}
+
+	if rep.Kinds[1].Error == "" {
+		t.Error("expected error for an unknown kind")
+	}
+
+	t.Run("Deleted Report", func(t *testing.T) {
+		index.Listen(report.LifecycleEvent{Type: report.Deleted, PolicyReport: newReport("trivy", "trivy")})
+
+		rep := scanner.Scan(context.Background())
+		if len(rep.Kinds[0].Sources) != 1 {
+			t.Errorf("expected only the kyverno source, got %+v", rep.Kinds[0].Sources)
+		}
+	})
+
+	t.Run("Namespaces and Sources", func(t *testing.T) {
+		scanner := coverage.NewScanner(newLister(), index, coverage.Options{
+			Kinds:        []coverage.Kind{{APIVersion: "apps/v1", Kind: "Deployment"}},
+			Sources:      []string{"kyverno", "kubescape"},
+			Namespaces:   validate.RuleSets{Exclude: []string{"kube-*"}},
+			MaxResources: 1,
+		}, nil)
+
+		rep := scanner.Scan(context.Background())
+
+		deployments := rep.Kinds[0]
+		if deployments.Resources != 2 || deployments.Unevaluated != 0 {
+			t.Errorf("expected 2 deployments without kube-system, got %+v", deployments)
+		}
+		if kubescape := deployments.Sources[1]; kubescape.Uncovered != 2 || len(kubescape.Resources) != 1 {
+			t.Errorf("expected 2 uncovered deployments with 1 listed resource, got %+v", kubescape)
+		}
+	})
+}
+
+func Test_Handler(t *testing.T) {
+	index := coverage.NewIndex()
+	scanner := coverage.NewScanner(newLister(), index, coverage.Options{Sources: []string{"kyverno"}}, nil)
+
+	handler := scanner.Handler()
+
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodGet, coverage.Path, nil))
+
+	rep := coverage.Report{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &rep); err != nil || len(rep.Kinds) != 0 {
+		t.Errorf("expected empty report before the first scan, got %s", rr.Body.String())
+	}
+
+	scanner.Scan(context.Background())
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodGet, coverage.Path, nil))
+	json.Unmarshal(rr.Body.Bytes(), &rep)
+
+	if len(rep.Kinds) != len(coverage.DefaultKinds) || rep.Kinds[0].Sources[0].Uncovered != 3 || len(rep.Kinds[0].Sources[0].Resources) != 0 {
+		t.Errorf("expected default kinds without resource details, got %s", rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodGet, coverage.Path+"?details=true", nil))
+	json.Unmarshal(rr.Body.Bytes(), &rep)
+
+	if len(rep.Kinds[0].Sources[0].Resources) != 3 {
+		t.Errorf("expected uncovered resources with details, got %s", rr.Body.String())
+	}
+
+	if namespaces, _ := scanner.Namespaces(); len(namespaces) != 2 || namespaces[0] != "kube-system" || namespaces[1] != "test" {
+		t.Errorf("expected the namespaces of the uncovered resources, got %v", namespaces)
+	}
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodGet, coverage.Path+"?details=true&namespaces=test", nil))
+	json.Unmarshal(rr.Body.Bytes(), &rep)
+
+	if resources := rep.Kinds[0].Sources[0].Resources; len(resources) != 2 || resources[0].Namespace != "test" || rep.Kinds[0].Sources[0].Uncovered != 3 {
+		t.Errorf("expected only the uncovered resources of the namespace filter, got %s", rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodPost, coverage.Path, nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,105 @@
+package coverage
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/kyverno/policy-reporter/pkg/helper"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+// Path of the coverage API
+const Path = "/v1/coverage"
+
+// Handler responds with the last scan, the resources without results are only included with the details query parameter
+func (s *Scanner) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.Header().Set("Allow", "GET")
+			helper.SendError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+			return
+		}
+
+		rep := s.Report()
+
+		if req.URL.Query().Get("details") != "true" {
+			kinds := make([]KindCoverage, 0, len(rep.Kinds))
+			for _, kind := range rep.Kinds {
+				sources := make([]SourceCoverage, 0, len(kind.Sources))
+				for _, source := range kind.Sources {
+					source.Resources = nil
+					sources = append(sources, source)
+				}
+
+				kind.Sources = sources
+				kinds = append(kinds, kind)
+			}
+
+			rep.Kinds = kinds
+		} else if namespaces := req.URL.Query()["namespaces"]; len(namespaces) > 0 {
+			rep = withNamespaces(rep, namespaces)
+		}
+
+		helper.SendJSONResponse(w, rep, nil)
+	}
+}
+
+// Namespaces of the uncovered resources of the last scan, cluster scoped resources are listed as report.ClusterScope
+func (s *Scanner) Namespaces() ([]string, error) {
+	set := make(map[string]bool)
+	for _, kind := range s.Report().Kinds {
+		for _, source := range kind.Sources {
+			for _, resource := range source.Resources {
+				set[namespaceOf(resource)] = true
+			}
+		}
+	}
+
+	list := make([]string, 0, len(set))
+	for namespace := range set {
+		list = append(list, namespace)
+	}
+	sort.Strings(list)
+
+	return list, nil
+}
+
+// withNamespaces keeps only the uncovered resources of the namespaces, the counts of the scan are not changed
+func withNamespaces(rep Report, namespaces []string) Report {
+	allowed := make(map[string]bool, len(namespaces))
+	for _, namespace := range namespaces {
+		allowed[namespace] = true
+	}
+
+	kinds := make([]KindCoverage, 0, len(rep.Kinds))
+	for _, kind := range rep.Kinds {
+		sources := make([]SourceCoverage, 0, len(kind.Sources))
+		for _, source := range kind.Sources {
+			resources := make([]Resource, 0, len(source.Resources))
+			for _, resource := range source.Resources {
+				if allowed[namespaceOf(resource)] {
+					resources = append(resources, resource)
+				}
+			}
+
+			source.Resources = resources
+			sources = append(sources, source)
+		}
+
+		kind.Sources = sources
+		kinds = append(kinds, kind)
+	}
+
+	rep.Kinds = kinds
+
+	return rep
+}
+
+func namespaceOf(resource Resource) string {
+	if resource.Namespace == "" {
+		return report.ClusterScope
+	}
+
+	return resource.Namespace
+}
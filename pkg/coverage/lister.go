@@ -0,0 +1,66 @@
+package coverage
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/restmapper"
+)
+
+type metadataLister struct {
+	client metadata.Interface
+	mapper *restmapper.DeferredDiscoveryRESTMapper
+}
+
+// List the metadata of all workloads of the kind
+func (l *metadataLister) List(ctx context.Context, kind Kind) ([]Resource, error) {
+	gv, err := schema.ParseGroupVersion(kind.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping, err := l.mapper.RESTMapping(gv.WithKind(kind.Kind).GroupKind(), gv.Version)
+	if err != nil {
+		// reset the discovery cache for resources of new CRDs
+		l.mapper.Reset()
+		return nil, err
+	}
+
+	var client metadata.ResourceInterface = l.client.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		client = l.client.Resource(mapping.Resource).Namespace(metav1.NamespaceAll)
+	}
+
+	resources := make([]Resource, 0)
+	options := metav1.ListOptions{Limit: 500}
+
+	for {
+		list, err := client.List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range list.Items {
+			resources = append(resources, Resource{Namespace: item.Namespace, Name: item.Name})
+		}
+
+		if list.Continue == "" {
+			return resources, nil
+		}
+
+		options.Continue = list.Continue
+	}
+}
+
+// NewMetadataLister lists the workloads with the metadata API, the resource of each kind is resolved with the discovery API
+func NewMetadataLister(client metadata.Interface, discoveryClient discovery.DiscoveryInterface) Lister {
+	return &metadataLister{
+		client: client,
+		mapper: restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+	}
+}
@@ -0,0 +1,5 @@
+package compliance
+
+const (
+	GroupName = "policy-reporter.io"
+)
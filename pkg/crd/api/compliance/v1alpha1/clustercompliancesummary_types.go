@@ -0,0 +1,73 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=ccs
+// +kubebuilder:printcolumn:name="Score",type=number,JSONPath=`.summary.score`
+// +kubebuilder:printcolumn:name="Fail",type=integer,JSONPath=`.summary.results.fail`
+// +kubebuilder:printcolumn:name="Warn",type=integer,JSONPath=`.summary.results.warn`
+// +kubebuilder:printcolumn:name="Updated",type="date",JSONPath=".summary.lastUpdated"
+
+// ClusterComplianceSummary is the roll-up of the PolicyReport results of the cluster, maintained by Policy Reporter
+type ClusterComplianceSummary struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Summary ComplianceSummary `json:"summary"`
+}
+
+// ComplianceSummary of all results with the counts and scores per namespace and source
+type ComplianceSummary struct {
+	// Score from 0 to 100 of the evaluated results weighted by severity
+	Score float64 `json:"score"`
+
+	// Results counted by status
+	Results StatusCounts `json:"results"`
+
+	// Namespaces of the namespaced results
+	// +optional
+	Namespaces []NamedSummary `json:"namespaces,omitempty"`
+
+	// Sources of the results
+	// +optional
+	Sources []NamedSummary `json:"sources,omitempty"`
+
+	// LastUpdated is the time the counts changed
+	LastUpdated metav1.Time `json:"lastUpdated"`
+}
+
+// NamedSummary of the results of a namespace or source
+type NamedSummary struct {
+	Name string `json:"name"`
+
+	// Score from 0 to 100 of the evaluated results weighted by severity
+	Score float64 `json:"score"`
+
+	// Results counted by status
+	Results StatusCounts `json:"results"`
+}
+
+// StatusCounts of results
+type StatusCounts struct {
+	Pass  int `json:"pass"`
+	Fail  int `json:"fail"`
+	Warn  int `json:"warn"`
+	Error int `json:"error"`
+	Skip  int `json:"skip"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterComplianceSummaryList contains a list of ClusterComplianceSummary
+type ClusterComplianceSummaryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterComplianceSummary `json:"items"`
+}
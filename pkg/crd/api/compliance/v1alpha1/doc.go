@@ -0,0 +1,5 @@
+// Package v1alpha1 contains the ClusterComplianceSummary API with the roll-up of the results of the cluster
+// +k8s:deepcopy-gen=package
+// +groupName=policy-reporter.io
+
+package v1alpha1
@@ -0,0 +1,5 @@
+package exclusion
+
+const (
+	GroupName = "policy-reporter.io"
+)
@@ -0,0 +1,5 @@
+// Package v1alpha1 contains the ResultExclusion API to acknowledge accepted risks
+// +k8s:deepcopy-gen=package
+// +groupName=policy-reporter.io
+
+package v1alpha1
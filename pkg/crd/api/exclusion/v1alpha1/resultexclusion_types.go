@@ -0,0 +1,74 @@
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Policy",type=string,JSONPath=`.spec.policy`
+// +kubebuilder:printcolumn:name="Rule",type=string,JSONPath=`.spec.rule`
+// +kubebuilder:printcolumn:name="Expires",type="date",JSONPath=".spec.expiresAt"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:shortName=rex
+
+// ResultExclusion acknowledges an accepted risk, matching results of its namespace are suppressed
+type ResultExclusion struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ResultExclusionSpec `json:"spec"`
+}
+
+// ResultExclusionSpec defines the excluded results
+type ResultExclusionSpec struct {
+	// Policy of the excluded results, supports wildcards and "regex:" expressions
+	Policy string `json:"policy"`
+
+	// Rule of the excluded results, supports wildcards and "regex:" expressions, empty matches all rules of the policy
+	// +optional
+	Rule string `json:"rule,omitempty"`
+
+	// Resource selects the resources of the excluded results, empty matches all resources of the namespace
+	// +optional
+	Resource *ResourceSelector `json:"resource,omitempty"`
+
+	// ExpiresAt ends the exclusion, empty never expires
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// Justification of the accepted risk
+	Justification string `json:"justification"`
+}
+
+// ResourceSelector matches result resources, all configured fields have to match
+type ResourceSelector struct {
+	// Kinds of the resources
+	// +optional
+	Kinds []string `json:"kinds,omitempty"`
+
+	// Names of the resources, supports wildcards and "regex:" expressions
+	// +optional
+	Names []string `json:"names,omitempty"`
+
+	// Selector of the resource labels
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// IsExpired reports whether the exclusion expired at the given time
+func (e *ResultExclusion) IsExpired(now time.Time) bool {
+	return e.Spec.ExpiresAt != nil && !now.Before(e.Spec.ExpiresAt.Time)
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ResultExclusionList contains a list of ResultExclusion
+type ResultExclusionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ResultExclusion `json:"items"`
+}
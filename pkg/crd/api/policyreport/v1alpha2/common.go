@@ -25,6 +25,57 @@ import (
 
 const ResultIDKey = "resultID"
 
+// Property keys of the normalized data provided by the source mappers
+const (
+	VulnerabilityIDKey = "vulnerabilityID"
+	CVSSScoreKey       = "cvssScore"
+	CheckIDKey         = "checkID"
+)
+
+// ErrorDetailsKey of the engine error of results with status error, added by the error routing
+const ErrorDetailsKey = "errorDetails"
+
+// Property keys of the policy metadata, e.g. added from the annotations of Kyverno policies
+const (
+	PolicyTitleKey       = "policyTitle"
+	PolicyDescriptionKey = "policyDescription"
+	RemediationKey       = "remediation"
+	PolicyURLKey         = "policyURL"
+)
+
+// Property keys of the top level controller of the result resource
+const (
+	OwnerAPIVersionKey = "ownerAPIVersion"
+	OwnerKindKey       = "ownerKind"
+	OwnerNameKey       = "ownerName"
+	OwnerUIDKey        = "ownerUID"
+)
+
+// Property keys of the GitOps application managing the result resource
+const (
+	GitOpsToolKey      = "gitopsTool"
+	GitOpsKindKey      = "gitopsKind"
+	GitOpsAppKey       = "gitopsApp"
+	GitOpsNamespaceKey = "gitopsAppNamespace"
+)
+
+// TeamKey of the property with the team responsible for the result
+const TeamKey = "team"
+
+// BindingKey of the property with the ValidatingAdmissionPolicyBinding of VAP results
+const BindingKey = "binding"
+
+// NodeKey of the property with the node of host level results without resource, e.g. of kube-bench
+const NodeKey = "node"
+
+// ResultURLKey of the property with the deep link of the result into the Policy Reporter UI
+const ResultURLKey = "resultURL"
+
+// IsPolicyGuidanceKey reports whether the property is shown as dedicated field by chat targets
+func IsPolicyGuidanceKey(key string) bool {
+	return key == PolicyDescriptionKey || key == RemediationKey || key == PolicyURLKey || key == ResultURLKey
+}
+
 // Status specifies state of a policy result
 const (
 	StatusPass  = "pass"
@@ -226,6 +277,9 @@ type PolicyReportResult struct {
 	Severity PolicySeverity `json:"severity,omitempty"`
 
 	Priority Priority `json:"-"`
+
+	// TraceParent of the pipeline span which processes the result, propagated into target requests
+	TraceParent string `json:"-"`
 }
 
 func (r *PolicyReportResult) GetResource() *corev1.ObjectReference {
@@ -0,0 +1,73 @@
+package email
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// TemplatePath resolves the template file for a report, falling back to the built-in template
+func TemplatePath(templateDir, custom, fallback string) string {
+	if custom == "" {
+		return filepath.Join(templateDir, fallback)
+	}
+	if filepath.IsAbs(custom) {
+		return custom
+	}
+
+	return filepath.Join(templateDir, custom)
+}
+
+func CSVAttachment(name string, header []string, rows [][]string) (Attachment, error) {
+	b := new(bytes.Buffer)
+	w := csv.NewWriter(b)
+
+	if err := w.Write(header); err != nil {
+		return Attachment{}, err
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return Attachment{}, err
+	}
+
+	return Attachment{Name: name + ".csv", MimeType: "text/csv", Data: b.Bytes()}, nil
+}
+
+func JSONAttachment(name string, value interface{}) (Attachment, error) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	return Attachment{Name: name + ".json", MimeType: "application/json", Data: data}, nil
+}
+
+// CreateAttachments renders the requested attachment formats for the given records
+func CreateAttachments(name string, formats []string, header []string, rows [][]string, value interface{}) ([]Attachment, error) {
+	attachments := make([]Attachment, 0, len(formats))
+
+	for _, format := range formats {
+		var (
+			attachment Attachment
+			err        error
+		)
+
+		switch strings.ToLower(format) {
+		case AttachmentCSV:
+			attachment, err = CSVAttachment(name, header, rows)
+		case AttachmentJSON:
+			attachment, err = JSONAttachment(name, value)
+		default:
+			err = fmt.Errorf("unsupported attachment format: %s", format)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, nil
+}
@@ -45,6 +45,10 @@ func (c *Client) Send(report Report, to []string) error {
 			msg.SetBody(mail.TextPlain, report.Message)
 		}
 
+		for _, a := range report.Attachments {
+			msg.Attach(&mail.File{Name: a.Name, MimeType: a.MimeType, Data: a.Data})
+		}
+
 		if msg.Error != nil {
 			return msg.Error
 		}
@@ -1,22 +1,90 @@
 package email
 
 import (
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
 	"github.com/kyverno/policy-reporter/pkg/validate"
 )
 
 type Filter struct {
-	namespace validate.RuleSets
-	sources   validate.RuleSets
+	namespace  validate.RuleSets
+	sources    validate.RuleSets
+	severities validate.RuleSets
+	teams      validate.RuleSets
+	team       func(namespace string) string
+	result     report.ReportResultValidation
 }
 
 func (f Filter) ValidateSource(source string) bool {
-	return validate.ContainsRuleSet(source, f.sources)
+	return validate.MatchRuleSet(source, f.sources)
 }
 
 func (f Filter) ValidateNamespace(namespace string) bool {
-	return validate.Namespace(namespace, f.namespace)
+	if !validate.Namespace(namespace, f.namespace) {
+		return false
+	}
+	if namespace == "" || f.team == nil || f.teams.Count() == 0 {
+		return true
+	}
+
+	return validate.MatchRuleSet(f.team(namespace), f.teams)
+}
+
+// ValidateClusterScope validates cluster scoped results as the cluster pseudo namespace against the namespace filter
+func (f Filter) ValidateClusterScope() bool {
+	return validate.MatchRuleSet(report.ClusterScope, f.namespace)
+}
+
+func (f Filter) ValidateSeverity(severity string) bool {
+	return validate.MatchRuleSet(severity, f.severities)
+}
+
+// WithTeams returns a copy of the filter which additionally validates the team of each namespace
+func (f Filter) WithTeams(teams validate.RuleSets, team func(namespace string) string) Filter {
+	f.teams = teams
+	f.team = team
+	return f
+}
+
+// WithResultValidation returns a copy of the filter which additionally validates each result, e.g. against ResultExclusions
+func (f Filter) WithResultValidation(validation report.ReportResultValidation) Filter {
+	f.result = validation
+	return f
+}
+
+func (f Filter) ValidateResult(rep v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) bool {
+	return f.result == nil || f.result(rep, result)
+}
+
+// Summary of the report, recalculated from the validated results if a result validation is configured
+func (f Filter) Summary(rep v1alpha2.ReportInterface) v1alpha2.PolicyReportSummary {
+	if f.result == nil {
+		return rep.GetSummary()
+	}
+
+	summary := v1alpha2.PolicyReportSummary{}
+	for _, result := range rep.GetResults() {
+		if !f.result(rep, result) {
+			continue
+		}
+
+		switch result.Result {
+		case v1alpha2.StatusPass:
+			summary.Pass++
+		case v1alpha2.StatusSkip:
+			summary.Skip++
+		case v1alpha2.StatusWarn:
+			summary.Warn++
+		case v1alpha2.StatusFail:
+			summary.Fail++
+		case v1alpha2.StatusError:
+			summary.Error++
+		}
+	}
+
+	return summary
 }
 
-func NewFilter(namespaces, sources validate.RuleSets) Filter {
-	return Filter{namespaces, sources}
+func NewFilter(namespaces, sources, severities validate.RuleSets) Filter {
+	return Filter{namespace: namespaces, sources: sources, severities: severities}
 }
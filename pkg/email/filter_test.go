@@ -3,13 +3,15 @@ package email_test
 import (
 	"testing"
 
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
 	"github.com/kyverno/policy-reporter/pkg/email"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
 	"github.com/kyverno/policy-reporter/pkg/validate"
 )
 
 func Test_Filters(t *testing.T) {
 	t.Run("Validate Default", func(t *testing.T) {
-		filter := email.NewFilter(validate.RuleSets{}, validate.RuleSets{})
+		filter := email.NewFilter(validate.RuleSets{}, validate.RuleSets{}, validate.RuleSets{})
 
 		if !filter.ValidateNamespace("test") {
 			t.Errorf("Unexpected Validation Result without configured rules")
@@ -17,5 +19,83 @@ func Test_Filters(t *testing.T) {
 		if !filter.ValidateSource("Kyverno") {
 			t.Errorf("Unexpected Validation Result without configured rules")
 		}
+		if !filter.ValidateSeverity("high") {
+			t.Errorf("Unexpected Validation Result without configured rules")
+		}
+	})
+	t.Run("Validate Severity", func(t *testing.T) {
+		filter := email.NewFilter(validate.RuleSets{}, validate.RuleSets{}, validate.RuleSets{Include: []string{"critical"}})
+
+		if filter.ValidateSeverity("low") {
+			t.Errorf("Expected severity low to be filtered")
+		}
+		if !filter.ValidateSeverity("critical") {
+			t.Errorf("Expected severity critical to pass")
+		}
+	})
+	t.Run("Validate Teams", func(t *testing.T) {
+		teams := map[string]string{"payments": "team-a", "checkout": "team-b"}
+
+		filter := email.NewFilter(validate.RuleSets{}, validate.RuleSets{}, validate.RuleSets{}).
+			WithTeams(validate.RuleSets{Include: []string{"team-a"}}, func(namespace string) string {
+				return teams[namespace]
+			})
+
+		if !filter.ValidateNamespace("payments") {
+			t.Errorf("Expected namespace of team-a to pass")
+		}
+		if filter.ValidateNamespace("checkout") || filter.ValidateNamespace("unknown") {
+			t.Errorf("Expected namespaces of other teams to be filtered")
+		}
+		if !filter.ValidateNamespace("") {
+			t.Errorf("Expected cluster scoped results to pass")
+		}
+	})
+	t.Run("Validate Cluster Scope", func(t *testing.T) {
+		filter := email.NewFilter(validate.RuleSets{Include: []string{"team-*"}}, validate.RuleSets{}, validate.RuleSets{})
+		if filter.ValidateClusterScope() {
+			t.Errorf("Expected cluster scoped results to be filtered by the namespace include rules")
+		}
+
+		filter = email.NewFilter(validate.RuleSets{Include: []string{"team-*", "cluster"}}, validate.RuleSets{}, validate.RuleSets{})
+		if !filter.ValidateClusterScope() {
+			t.Errorf("Expected cluster scoped results to pass with the cluster pseudo namespace")
+		}
+
+		filter = email.NewFilter(validate.RuleSets{Exclude: []string{"kube-*"}}, validate.RuleSets{}, validate.RuleSets{})
+		if !filter.ValidateClusterScope() {
+			t.Errorf("Expected cluster scoped results to pass the exclude rules")
+		}
+	})
+	t.Run("Validate Result", func(t *testing.T) {
+		filter := email.NewFilter(validate.RuleSets{}, validate.RuleSets{}, validate.RuleSets{}).
+			WithResultValidation(func(_ v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) bool {
+				return result.Policy != fixtures.FailResult.Policy
+			})
+
+		if filter.ValidateResult(fixtures.DefaultPolicyReport, fixtures.FailResult) {
+			t.Errorf("Expected result to be filtered")
+		}
+		if !filter.ValidateResult(fixtures.DefaultPolicyReport, fixtures.FailDisallowRuleResult) {
+			t.Errorf("Expected result to pass")
+		}
+	})
+	t.Run("Summary", func(t *testing.T) {
+		report := &v1alpha2.PolicyReport{
+			Summary: v1alpha2.PolicyReportSummary{Pass: 1, Fail: 2},
+			Results: []v1alpha2.PolicyReportResult{fixtures.PassResult, fixtures.FailResult, fixtures.FailDisallowRuleResult},
+		}
+
+		filter := email.NewFilter(validate.RuleSets{}, validate.RuleSets{}, validate.RuleSets{})
+		if summary := filter.Summary(report); summary.Fail != 2 {
+			t.Errorf("Expected report summary without result validation, got %d failures", summary.Fail)
+		}
+
+		filter = filter.WithResultValidation(func(_ v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) bool {
+			return result.Policy != fixtures.FailResult.Policy
+		})
+		if summary := filter.Summary(report); summary.Fail != 1 || summary.Pass != 0 {
+			t.Errorf("Expected recalculated summary, got %d failures and %d passes", summary.Fail, summary.Pass)
+		}
 	})
 }
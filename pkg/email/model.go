@@ -4,11 +4,31 @@ import (
 	"context"
 )
 
+const (
+	AttachmentCSV  = "csv"
+	AttachmentJSON = "json"
+)
+
+type Attachment struct {
+	Name     string
+	MimeType string
+	Data     []byte
+}
+
 type Report struct {
 	Title       string
 	Message     string
 	Format      string
 	ClusterName string
+	Attachments []Attachment
+}
+
+// ReportOptions customize the rendered report
+type ReportOptions struct {
+	// Template path of a custom Go HTML template, relative paths are resolved against the template dir
+	Template string
+	// Attachments list of attachment formats, supported are csv and json
+	Attachments []string
 }
 
 type Reporter interface {
@@ -2,7 +2,6 @@ package summary
 
 import (
 	"context"
-	"log"
 	"sync"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -10,8 +9,11 @@ import (
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
 	api "github.com/kyverno/policy-reporter/pkg/crd/client/clientset/versioned/typed/policyreport/v1alpha2"
 	"github.com/kyverno/policy-reporter/pkg/email"
+	"github.com/kyverno/policy-reporter/pkg/logging"
 )
 
+var logger = logging.Component("email")
+
 type Generator struct {
 	client         api.Wgpolicyk8sV1alpha2Interface
 	filter         email.Filter
@@ -24,7 +26,9 @@ func (o *Generator) GenerateData(ctx context.Context) ([]Source, error) {
 	sources := make(map[string]*Source)
 	wg := &sync.WaitGroup{}
 
-	if o.clusterReports {
+	clusterScope := o.clusterReports && o.filter.ValidateClusterScope()
+
+	if clusterScope {
 		clusterReports, err := o.client.ClusterPolicyReports().List(ctx, v1.ListOptions{})
 		if err != nil {
 			return make([]Source, 0, 0), err
@@ -48,14 +52,14 @@ func (o *Generator) GenerateData(ctx context.Context) ([]Source, error) {
 				mx.Lock()
 				s, ok := sources[rs]
 				if !ok {
-					s = NewSource(rs, o.clusterReports)
+					s = NewSource(rs, clusterScope)
 					sources[rs] = s
 				}
 				mx.Unlock()
 
-				s.AddClusterSummary(report.Summary)
+				s.AddClusterSummary(o.filter.Summary(&report))
 
-				log.Printf("[INFO] Processed ClusterPolicyReport '%s'\n", report.Name)
+				logger.Infof("Processed ClusterPolicyReport '%s'", report.Name)
 			}(rep)
 		}
 	}
@@ -83,14 +87,14 @@ func (o *Generator) GenerateData(ctx context.Context) ([]Source, error) {
 			mx.Lock()
 			s, ok := sources[rs]
 			if !ok {
-				s = NewSource(rs, o.clusterReports)
+				s = NewSource(rs, clusterScope)
 				sources[rs] = s
 			}
 			mx.Unlock()
 
-			s.AddNamespacedSummary(report.Namespace, report.Summary)
+			s.AddNamespacedSummary(report.Namespace, o.filter.Summary(&report))
 
-			log.Printf("[INFO] Processed PolicyReport '%s'\n", report.Name)
+			logger.Infof("Processed PolicyReport '%s'", report.Name)
 		}(rep)
 	}
 
@@ -110,6 +114,7 @@ func NewGenerator(client api.Wgpolicyk8sV1alpha2Interface, filter email.Filter,
 
 func FilterSources(sources []Source, filter email.Filter, clusterReports bool) []Source {
 	newSources := make([]Source, 0)
+	clusterReports = clusterReports && filter.ValidateClusterScope()
 
 	mx := sync.Mutex{}
 	wg := &sync.WaitGroup{}
@@ -81,7 +81,7 @@ func Test_GenerateDataWithSourceFilter(t *testing.T) {
 	_, _ = cClient.Create(ctx, fixtures.EmptyClusterPolicyReport, v1.CreateOptions{})
 	_, _ = cClient.Create(ctx, fixtures.KyvernoClusterPolicyReport, v1.CreateOptions{})
 
-	generator := summary.NewGenerator(client, email.NewFilter(validate.RuleSets{}, validate.RuleSets{Include: []string{"test"}}), true)
+	generator := summary.NewGenerator(client, email.NewFilter(validate.RuleSets{}, validate.RuleSets{Include: []string{"test"}}, validate.RuleSets{}), true)
 
 	data, err := generator.GenerateData(ctx)
 	if err != nil {
@@ -113,7 +113,7 @@ func Test_FilterSourcesBySource(t *testing.T) {
 		t.Fatalf("unexpected error: %s", err)
 	}
 
-	data = summary.FilterSources(data, email.NewFilter(validate.RuleSets{}, validate.RuleSets{Include: []string{"Kyverno"}}), true)
+	data = summary.FilterSources(data, email.NewFilter(validate.RuleSets{}, validate.RuleSets{Include: []string{"Kyverno"}}, validate.RuleSets{}), true)
 	if len(data) != 1 {
 		t.Fatalf("expected one source left, got: %d", len(data))
 	}
@@ -139,7 +139,7 @@ func Test_FilterSourcesByNamespace(t *testing.T) {
 		t.Fatalf("unexpected error: %s", err)
 	}
 
-	data = summary.FilterSources(data, email.NewFilter(validate.RuleSets{Exclude: []string{"kyverno"}}, validate.RuleSets{}), true)
+	data = summary.FilterSources(data, email.NewFilter(validate.RuleSets{Exclude: []string{"kyverno"}}, validate.RuleSets{}, validate.RuleSets{}), true)
 	source := data[0]
 	if source.Name != "Kyverno" {
 		source = data[1]
@@ -170,7 +170,7 @@ func Test_RemoveEmptySource(t *testing.T) {
 		t.Fatalf("unexpected error: %s", err)
 	}
 
-	data = summary.FilterSources(data, email.NewFilter(validate.RuleSets{Exclude: []string{"kyverno"}}, validate.RuleSets{}), false)
+	data = summary.FilterSources(data, email.NewFilter(validate.RuleSets{Exclude: []string{"kyverno"}}, validate.RuleSets{}, validate.RuleSets{}), false)
 	if len(data) != 1 {
 		t.Fatalf("expected one source left, got: %d", len(data))
 	}
@@ -14,6 +14,14 @@ type Summary struct {
 	Error int
 }
 
+func (s *Summary) add(o *Summary) {
+	s.Skip += o.Skip
+	s.Pass += o.Pass
+	s.Warn += o.Warn
+	s.Fail += o.Fail
+	s.Error += o.Error
+}
+
 type Source struct {
 	Name                  string
 	ClusterScopeSummary   *Summary
@@ -24,6 +32,8 @@ type Source struct {
 }
 
 func (s *Source) AddClusterSummary(sum v1alpha2.PolicyReportSummary) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
 	s.ClusterScopeSummary.Skip += sum.Skip
 	s.ClusterScopeSummary.Pass += sum.Pass
 	s.ClusterScopeSummary.Warn += sum.Warn
@@ -2,21 +2,43 @@ package summary
 
 import (
 	"html/template"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/kyverno/policy-reporter/pkg/email"
+	"github.com/kyverno/policy-reporter/pkg/taxonomy"
+	"github.com/kyverno/policy-reporter/pkg/timeformat"
 )
 
+var attachmentHeader = []string{"source", "namespace", "pass", "fail", "warn", "error", "skip"}
+
+type attachmentRow struct {
+	Source    string `json:"source"`
+	Namespace string `json:"namespace,omitempty"`
+	Pass      int    `json:"pass"`
+	Fail      int    `json:"fail"`
+	Warn      int    `json:"warn"`
+	Error     int    `json:"error"`
+	Skip      int    `json:"skip"`
+}
+
 type Reporter struct {
 	templateDir string
 	clusterName string
+	taxonomy    *taxonomy.Taxonomy
 }
 
-func (o *Reporter) Report(sources []Source, format string) (email.Report, error) {
+func (o *Reporter) Report(sources []Source, format string, options email.ReportOptions) (email.Report, error) {
 	b := new(strings.Builder)
 
-	templ, err := template.ParseFiles(o.templateDir + "/summary.html")
+	sources = displaySources(sources, o.taxonomy)
+
+	path := email.TemplatePath(o.templateDir, options.Template, "summary.html")
+
+	templ, err := template.New(filepath.Base(path)).ParseFiles(path)
 	if err != nil {
 		return email.Report{}, err
 	}
@@ -29,14 +51,105 @@ func (o *Reporter) Report(sources []Source, format string) (email.Report, error)
 		return email.Report{}, err
 	}
 
+	var attachments []email.Attachment
+	if len(options.Attachments) > 0 {
+		rows := attachmentRows(sources)
+
+		records := make([][]string, 0, len(rows))
+		for _, r := range rows {
+			records = append(records, []string{
+				r.Source,
+				r.Namespace,
+				strconv.Itoa(r.Pass),
+				strconv.Itoa(r.Fail),
+				strconv.Itoa(r.Warn),
+				strconv.Itoa(r.Error),
+				strconv.Itoa(r.Skip),
+			})
+		}
+
+		attachments, err = email.CreateAttachments("summary", options.Attachments, attachmentHeader, records, rows)
+		if err != nil {
+			return email.Report{}, err
+		}
+	}
+
 	return email.Report{
 		ClusterName: o.clusterName,
-		Title:       "Summary Report from " + time.Now().Format("2006-01-02"),
+		Title:       "Summary Report from " + timeformat.In(time.Now()).Format("2006-01-02"),
 		Message:     b.String(),
 		Format:      format,
+		Attachments: attachments,
 	}, nil
 }
 
-func NewReporter(templateDir, clusterName string) *Reporter {
-	return &Reporter{templateDir, clusterName}
+func attachmentRows(sources []Source) []attachmentRow {
+	rows := make([]attachmentRow, 0)
+
+	for _, source := range sources {
+		if source.ClusterReports && source.ClusterScopeSummary != nil {
+			sum := source.ClusterScopeSummary
+			rows = append(rows, attachmentRow{Source: source.Name, Pass: sum.Pass, Fail: sum.Fail, Warn: sum.Warn, Error: sum.Error, Skip: sum.Skip})
+		}
+
+		namespaces := make([]string, 0, len(source.NamespaceScopeSummary))
+		for ns := range source.NamespaceScopeSummary {
+			namespaces = append(namespaces, ns)
+		}
+		sort.Strings(namespaces)
+
+		for _, ns := range namespaces {
+			sum := source.NamespaceScopeSummary[ns]
+			rows = append(rows, attachmentRow{Source: source.Name, Namespace: ns, Pass: sum.Pass, Fail: sum.Fail, Warn: sum.Warn, Error: sum.Error, Skip: sum.Skip})
+		}
+	}
+
+	return rows
+}
+
+// displaySources renames the sources with their display names, merges sources with the same display name and sorts them
+func displaySources(sources []Source, t *taxonomy.Taxonomy) []Source {
+	if t.Empty() {
+		return sources
+	}
+
+	merged := make(map[string]*Source, len(sources))
+	names := make([]string, 0, len(sources))
+
+	for _, source := range sources {
+		name := t.Sources.Name(source.Name)
+
+		m, ok := merged[name]
+		if !ok {
+			m = NewSource(name, source.ClusterReports)
+			merged[name] = m
+			names = append(names, source.Name)
+		}
+
+		if source.ClusterScopeSummary != nil {
+			m.ClusterScopeSummary.add(source.ClusterScopeSummary)
+		}
+
+		for ns, sum := range source.NamespaceScopeSummary {
+			if _, ok := m.NamespaceScopeSummary[ns]; !ok {
+				m.NamespaceScopeSummary[ns] = &Summary{}
+			}
+
+			m.NamespaceScopeSummary[ns].add(sum)
+		}
+	}
+
+	t.Sources.Sort(names)
+
+	list := make([]Source, 0, len(names))
+	for _, name := range names {
+		list = append(list, *merged[t.Sources.Name(name)])
+	}
+
+	return list
+}
+
+// NewReporter creates a Reporter, the taxonomy is optional and maps the source names
+func NewReporter(templateDir, clusterName string, taxonomy *taxonomy.Taxonomy) *Reporter {
+	return &Reporter{templateDir, clusterName, taxonomy}
 }
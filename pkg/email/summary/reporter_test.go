@@ -4,12 +4,17 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/email"
 	"github.com/kyverno/policy-reporter/pkg/email/summary"
 	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/taxonomy"
 )
 
 func Test_CreateReport(t *testing.T) {
@@ -38,8 +43,8 @@ func Test_CreateReport(t *testing.T) {
 
 	fmt.Println(path)
 
-	reporter := summary.NewReporter("../../../templates", "Cluster")
-	report, err := reporter.Report(data, "html")
+	reporter := summary.NewReporter("../../../templates", "Cluster", nil)
+	report, err := reporter.Report(data, "html", email.ReportOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	}
@@ -54,3 +59,91 @@ func Test_CreateReport(t *testing.T) {
 		t.Fatal("expected format to be set")
 	}
 }
+
+func Test_CreateReportWithCustomTemplate(t *testing.T) {
+	ctx := context.Background()
+
+	client, pClient, _ := NewFakeClient()
+
+	_, _ = pClient.Create(ctx, fixtures.DefaultPolicyReport, v1.CreateOptions{})
+
+	generator := summary.NewGenerator(client, filter, true)
+	data, err := generator.GenerateData(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "custom.html"), []byte("<h1>{{ .ClusterName }}</h1>{{ range .Sources }}{{ .Name }}{{ end }}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	reporter := summary.NewReporter(dir, "Cluster", nil)
+
+	t.Run("Custom Template", func(t *testing.T) {
+		report, err := reporter.Report(data, "html", email.ReportOptions{Template: "custom.html"})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if !strings.HasPrefix(report.Message, "<h1>Cluster</h1>") {
+			t.Errorf("expected custom template to be rendered, got: %s", report.Message)
+		}
+	})
+
+	t.Run("Attachments", func(t *testing.T) {
+		report, err := reporter.Report(data, "html", email.ReportOptions{Template: filepath.Join(dir, "custom.html"), Attachments: []string{"csv", "json"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if len(report.Attachments) != 2 {
+			t.Fatalf("expected 2 attachments, got %d", len(report.Attachments))
+		}
+		if report.Attachments[0].Name != "summary.csv" || report.Attachments[0].MimeType != "text/csv" {
+			t.Errorf("unexpected csv attachment: %s", report.Attachments[0].Name)
+		}
+		if !strings.HasPrefix(string(report.Attachments[0].Data), "source,namespace,pass,fail,warn,error,skip\n") {
+			t.Errorf("unexpected csv header: %s", report.Attachments[0].Data)
+		}
+		if report.Attachments[1].Name != "summary.json" || report.Attachments[1].MimeType != "application/json" {
+			t.Errorf("unexpected json attachment: %s", report.Attachments[1].Name)
+		}
+	})
+
+	t.Run("Unsupported Attachment", func(t *testing.T) {
+		_, err := reporter.Report(data, "html", email.ReportOptions{Template: "custom.html", Attachments: []string{"xml"}})
+		if err == nil {
+			t.Error("expected error for unsupported attachment format")
+		}
+	})
+}
+
+func Test_CreateReportWithTaxonomy(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "custom.html"), []byte("{{ range .Sources }}[{{ .Name }}:{{ (index .NamespaceScopeSummary \"test\").Fail }}]{{ end }}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	vulnerabilities := summary.NewSource("Trivy Vulnerability", false)
+	vulnerabilities.AddNamespacedSummary("test", v1alpha2.PolicyReportSummary{Fail: 2})
+
+	audit := summary.NewSource("Trivy ConfigAudit", false)
+	audit.AddNamespacedSummary("test", v1alpha2.PolicyReportSummary{Fail: 3})
+
+	kyverno := summary.NewSource("kyverno", false)
+	kyverno.AddNamespacedSummary("test", v1alpha2.PolicyReportSummary{Fail: 1})
+
+	reporter := summary.NewReporter(dir, "Cluster", &taxonomy.Taxonomy{
+		Sources: taxonomy.Dimension{{Name: "Kyverno", Order: 1}, {Name: "Trivy", Match: []string{"trivy*"}, Order: 2}},
+	})
+
+	report, err := reporter.Report([]summary.Source{*vulnerabilities, *kyverno, *audit}, "html", email.ReportOptions{Template: "custom.html"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if report.Message != "[Kyverno:1][Trivy:5]" {
+		t.Errorf("expected merged and ordered sources with display names, got %s", report.Message)
+	}
+}
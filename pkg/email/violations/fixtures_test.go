@@ -7,7 +7,7 @@ import (
 	"github.com/kyverno/policy-reporter/pkg/validate"
 )
 
-var filter = email.NewFilter(validate.RuleSets{}, validate.RuleSets{})
+var filter = email.NewFilter(validate.RuleSets{}, validate.RuleSets{}, validate.RuleSets{})
 
 func NewFakeClient() (v1alpha2client.Wgpolicyk8sV1alpha2Interface, v1alpha2client.PolicyReportInterface, v1alpha2client.ClusterPolicyReportInterface) {
 	client := fake.NewSimpleClientset().Wgpolicyk8sV1alpha2()
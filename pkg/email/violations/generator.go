@@ -2,7 +2,6 @@ package violations
 
 import (
 	"context"
-	"log"
 	"sync"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -10,12 +9,31 @@ import (
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
 	api "github.com/kyverno/policy-reporter/pkg/crd/client/clientset/versioned/typed/policyreport/v1alpha2"
 	"github.com/kyverno/policy-reporter/pkg/email"
+	"github.com/kyverno/policy-reporter/pkg/enrichment"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	preport "github.com/kyverno/policy-reporter/pkg/report"
 )
 
+var logger = logging.Component("email")
+
 type Generator struct {
 	client         api.Wgpolicyk8sV1alpha2Interface
 	filter         email.Filter
 	clusterReports bool
+	links          *enrichment.Links
+}
+
+// EnableLinks adds deep links into the Policy Reporter UI to the results
+func (o *Generator) EnableLinks(links *enrichment.Links) {
+	o.links = links
+}
+
+func (o *Generator) link(rep v1alpha2.ReportInterface, result *v1alpha2.PolicyReportResult) string {
+	if o.links == nil {
+		return ""
+	}
+
+	return o.links.URL(rep, result)
 }
 
 func (o *Generator) GenerateData(ctx context.Context) ([]Source, error) {
@@ -24,7 +42,9 @@ func (o *Generator) GenerateData(ctx context.Context) ([]Source, error) {
 	sources := make(map[string]*Source)
 	wg := &sync.WaitGroup{}
 
-	if o.clusterReports {
+	clusterScope := o.clusterReports && o.filter.ValidateClusterScope()
+
+	if clusterScope {
 		clusterReports, err := o.client.ClusterPolicyReports().List(ctx, v1.ListOptions{})
 		if err != nil {
 			return make([]Source, 0, 0), err
@@ -49,26 +69,34 @@ func (o *Generator) GenerateData(ctx context.Context) ([]Source, error) {
 				mx.Lock()
 				s, ok := sources[rs]
 				if !ok {
-					s = NewSource(rs, o.clusterReports)
+					s = NewSource(rs, clusterScope)
 					sources[rs] = s
 				}
 				mx.Unlock()
 
-				s.AddClusterPassed(report.Summary.Pass)
+				s.AddClusterPassed(o.filter.Summary(&report).Pass)
 
-				defer log.Printf("[INFO] Processed ClusterPolicyReport '%s'\n", report.Name)
+				defer logger.Infof("Processed ClusterPolicyReport '%s'", report.Name)
 
 				length := len(report.Results)
 				if length == 0 || length == report.Summary.Pass+report.Summary.Skip {
 					return
 				}
 
+				preport.MapScope(&report)
+				preport.MapNode(&report)
+
 				for _, result := range report.Results {
-					if result.Result == v1alpha2.StatusPass || result.Result == v1alpha2.StatusSkip {
+					if result.Result == v1alpha2.StatusPass || result.Result == v1alpha2.StatusSkip || !o.filter.ValidateSeverity(string(result.Severity)) || !o.filter.ValidateResult(&report, result) {
+						continue
+					}
+
+					if node := preport.NodeName(result); node != "" {
+						s.AddNodeResults(node, mapResult(result, o.link(&report, &result)))
 						continue
 					}
 
-					s.AddClusterResults(mapResult(result))
+					s.AddClusterResults(mapResult(result, o.link(&report, &result)))
 				}
 			}(rep)
 		}
@@ -98,14 +126,14 @@ func (o *Generator) GenerateData(ctx context.Context) ([]Source, error) {
 			mx.Lock()
 			s, ok := sources[rs]
 			if !ok {
-				s = NewSource(rs, o.clusterReports)
+				s = NewSource(rs, clusterScope)
 				sources[rs] = s
 			}
 			mx.Unlock()
 
-			s.AddNamespacedPassed(report.Namespace, report.Summary.Pass)
+			s.AddNamespacedPassed(report.Namespace, o.filter.Summary(&report).Pass)
 
-			defer log.Printf("[INFO] Processed PolicyReport '%s'\n", report.Name)
+			defer logger.Infof("Processed PolicyReport '%s'", report.Name)
 
 			length := len(report.Results)
 			if length == 0 || length == report.Summary.Pass+report.Summary.Skip {
@@ -113,11 +141,13 @@ func (o *Generator) GenerateData(ctx context.Context) ([]Source, error) {
 				return
 			}
 
+			preport.MapScope(&report)
+
 			for _, result := range report.Results {
-				if result.Result == v1alpha2.StatusPass || result.Result == v1alpha2.StatusSkip {
+				if result.Result == v1alpha2.StatusPass || result.Result == v1alpha2.StatusSkip || !o.filter.ValidateSeverity(string(result.Severity)) || !o.filter.ValidateResult(&report, result) {
 					continue
 				}
-				s.AddNamespacedResults(report.Namespace, mapResult(result))
+				s.AddNamespacedResults(report.Namespace, mapResult(result, o.link(&report, &result)))
 			}
 		}(rep)
 	}
@@ -133,11 +163,12 @@ func (o *Generator) GenerateData(ctx context.Context) ([]Source, error) {
 }
 
 func NewGenerator(client api.Wgpolicyk8sV1alpha2Interface, filter email.Filter, clusterReports bool) *Generator {
-	return &Generator{client, filter, clusterReports}
+	return &Generator{client: client, filter: filter, clusterReports: clusterReports}
 }
 
 func FilterSources(sources []Source, filter email.Filter, clusterReports bool) []Source {
 	newSources := make([]Source, 0)
+	clusterReports = clusterReports && filter.ValidateClusterScope()
 
 	mx := sync.Mutex{}
 	wg := &sync.WaitGroup{}
@@ -155,7 +186,11 @@ func FilterSources(sources []Source, filter email.Filter, clusterReports bool) [
 
 			if clusterReports {
 				newSource.ClusterPassed = source.ClusterPassed
-				newSource.ClusterResults = source.ClusterResults
+				newSource.ClusterResults = filterResults(source.ClusterResults, filter)
+
+				for node, results := range source.NodeResults {
+					newSource.NodeResults[node] = filterResults(results, filter)
+				}
 			}
 
 			for ns, passed := range source.NamespacePassed {
@@ -171,7 +206,7 @@ func FilterSources(sources []Source, filter email.Filter, clusterReports bool) [
 					continue
 				}
 
-				newSource.NamespaceResults[ns] = results
+				newSource.NamespaceResults[ns] = filterResults(results, filter)
 			}
 
 			if !clusterReports && len(newSource.NamespaceResults) == 0 {
@@ -188,3 +223,19 @@ func FilterSources(sources []Source, filter email.Filter, clusterReports bool) [
 
 	return newSources
 }
+
+func filterResults(results map[string][]Result, filter email.Filter) map[string][]Result {
+	filtered := make(map[string][]Result, len(results))
+
+	for status, list := range results {
+		filtered[status] = make([]Result, 0, len(list))
+
+		for _, result := range list {
+			if filter.ValidateSeverity(result.Severity) {
+				filtered[status] = append(filtered[status], result)
+			}
+		}
+	}
+
+	return filtered
+}
@@ -105,7 +105,7 @@ func Test_GenerateDataWithSourceFilter(t *testing.T) {
 	_, _ = cClient.Create(ctx, fixtures.EmptyClusterPolicyReport, v1.CreateOptions{})
 	_, _ = cClient.Create(ctx, fixtures.KyvernoClusterPolicyReport, v1.CreateOptions{})
 
-	generator := violations.NewGenerator(client, email.NewFilter(validate.RuleSets{}, validate.RuleSets{Include: []string{"test"}}), true)
+	generator := violations.NewGenerator(client, email.NewFilter(validate.RuleSets{}, validate.RuleSets{Include: []string{"test"}}, validate.RuleSets{}), true)
 
 	data, err := generator.GenerateData(ctx)
 	if err != nil {
@@ -137,7 +137,7 @@ func Test_FilterSourcesBySource(t *testing.T) {
 		t.Fatalf("unexpected error: %s", err)
 	}
 
-	data = violations.FilterSources(data, email.NewFilter(validate.RuleSets{}, validate.RuleSets{Include: []string{"Kyverno"}}), true)
+	data = violations.FilterSources(data, email.NewFilter(validate.RuleSets{}, validate.RuleSets{Include: []string{"Kyverno"}}, validate.RuleSets{}), true)
 	if len(data) != 1 {
 		t.Fatalf("expected one source left, got: %d", len(data))
 	}
@@ -163,7 +163,7 @@ func Test_FilterSourcesByNamespace(t *testing.T) {
 		t.Fatalf("unexpected error: %s", err)
 	}
 
-	data = violations.FilterSources(data, email.NewFilter(validate.RuleSets{Exclude: []string{"kyverno"}}, validate.RuleSets{}), true)
+	data = violations.FilterSources(data, email.NewFilter(validate.RuleSets{Exclude: []string{"kyverno"}}, validate.RuleSets{}, validate.RuleSets{}), true)
 	source := data[0]
 	if source.Name != "Kyverno" {
 		source = data[1]
@@ -174,6 +174,38 @@ func Test_FilterSourcesByNamespace(t *testing.T) {
 	}
 }
 
+func Test_FilterSourcesBySeverity(t *testing.T) {
+	ctx := context.Background()
+
+	client, pClient, _ := NewFakeClient()
+
+	_, _ = pClient.Create(ctx, fixtures.DefaultPolicyReport, v1.CreateOptions{})
+
+	generator := violations.NewGenerator(client, filter, false)
+
+	data, err := generator.GenerateData(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	t.Run("Include Severity", func(t *testing.T) {
+		sources := violations.FilterSources(data, email.NewFilter(validate.RuleSets{}, validate.RuleSets{}, validate.RuleSets{Include: []string{"high"}}), false)
+		if len(sources[0].NamespaceResults["test"]["fail"]) == 0 {
+			t.Error("expected high severity results to be kept")
+		}
+	})
+	t.Run("Exclude Severity", func(t *testing.T) {
+		sources := violations.FilterSources(data, email.NewFilter(validate.RuleSets{}, validate.RuleSets{}, validate.RuleSets{Exclude: []string{"high"}}), false)
+		for _, results := range sources[0].NamespaceResults["test"] {
+			for _, r := range results {
+				if r.Severity == "high" {
+					t.Errorf("expected high severity results to be removed, got %+v", r)
+				}
+			}
+		}
+	})
+}
+
 func Test_RemoveEmptySource(t *testing.T) {
 	ctx := context.Background()
 
@@ -194,7 +226,7 @@ func Test_RemoveEmptySource(t *testing.T) {
 		t.Fatalf("unexpected error: %s", err)
 	}
 
-	data = violations.FilterSources(data, email.NewFilter(validate.RuleSets{Exclude: []string{"kyverno"}}, validate.RuleSets{}), false)
+	data = violations.FilterSources(data, email.NewFilter(validate.RuleSets{Exclude: []string{"kyverno"}}, validate.RuleSets{}, validate.RuleSets{}), false)
 	if len(data) != 1 {
 		t.Fatalf("expected one source left, got: %d", len(data))
 	}
@@ -7,14 +7,16 @@ import (
 )
 
 type Result struct {
-	Policy string
-	Rule   string
-	Kind   string
-	Name   string
-	Status string
+	Policy   string
+	Rule     string
+	Kind     string
+	Name     string
+	Status   string
+	Severity string
+	URL      string
 }
 
-func mapResult(res v1alpha2.PolicyReportResult) []Result {
+func mapResult(res v1alpha2.PolicyReportResult, url string) []Result {
 	count := len(res.Resources)
 	rule := res.Rule
 	if rule == "" {
@@ -23,20 +25,24 @@ func mapResult(res v1alpha2.PolicyReportResult) []Result {
 
 	if count == 0 {
 		return []Result{{
-			Policy: res.Policy,
-			Rule:   rule,
-			Status: string(res.Result),
+			Policy:   res.Policy,
+			Rule:     rule,
+			Status:   string(res.Result),
+			Severity: string(res.Severity),
+			URL:      url,
 		}}
 	}
 
 	list := make([]Result, 0, count)
 	for _, re := range res.Resources {
 		list = append(list, Result{
-			Policy: res.Policy,
-			Rule:   rule,
-			Name:   re.Name,
-			Kind:   re.Kind,
-			Status: string(res.Result),
+			Policy:   res.Policy,
+			Rule:     rule,
+			Name:     re.Name,
+			Kind:     re.Kind,
+			Status:   string(res.Result),
+			Severity: string(res.Severity),
+			URL:      url,
 		})
 	}
 
@@ -49,11 +55,13 @@ type Source struct {
 	NamespacePassed  map[string]int
 	ClusterResults   map[string][]Result
 	NamespaceResults map[string]map[string][]Result
+	NodeResults      map[string]map[string][]Result
 	ClusterReports   bool
 
 	passMX *sync.Mutex
 	crMX   *sync.Mutex
 	nrMX   *sync.Mutex
+	nodeMX *sync.Mutex
 }
 
 func (s *Source) AddClusterResults(result []Result) {
@@ -62,7 +70,24 @@ func (s *Source) AddClusterResults(result []Result) {
 	s.ClusterResults[result[0].Status] = append(s.ClusterResults[result[0].Status], result...)
 }
 
+// AddNodeResults groups the results of node scoped resources per node, e.g. CIS benchmark results of kube-bench
+func (s *Source) AddNodeResults(node string, result []Result) {
+	s.nodeMX.Lock()
+	defer s.nodeMX.Unlock()
+	if _, ok := s.NodeResults[node]; !ok {
+		s.NodeResults[node] = map[string][]Result{
+			v1alpha2.StatusWarn:  make([]Result, 0),
+			v1alpha2.StatusFail:  make([]Result, 0),
+			v1alpha2.StatusError: make([]Result, 0),
+		}
+	}
+
+	s.NodeResults[node][result[0].Status] = append(s.NodeResults[node][result[0].Status], result...)
+}
+
 func (s *Source) AddClusterPassed(results int) {
+	s.passMX.Lock()
+	defer s.passMX.Unlock()
 	s.ClusterPassed += results
 }
 
@@ -111,8 +136,10 @@ func NewSource(name string, clusterReports bool) *Source {
 		},
 		NamespaceResults: map[string]map[string][]Result{},
 		NamespacePassed:  map[string]int{},
+		NodeResults:      map[string]map[string][]Result{},
 		passMX:           new(sync.Mutex),
 		crMX:             new(sync.Mutex),
 		nrMX:             new(sync.Mutex),
+		nodeMX:           new(sync.Mutex),
 	}
 }
@@ -39,6 +39,29 @@ func Test_Source(t *testing.T) {
 			t.Errorf("Unexpected amount of failing Cluster Results: %d", len(source.ClusterResults["fail"]))
 		}
 	})
+	t.Run("Source.AddNodeResults", func(t *testing.T) {
+		source.AddNodeResults("worker-1", []violations.Result{{
+			Name:   "worker-1",
+			Kind:   "Node",
+			Policy: "cis-1.8",
+			Rule:   "4.1.1",
+			Status: "fail",
+		}})
+		source.AddNodeResults("worker-1", []violations.Result{{
+			Name:   "worker-1",
+			Kind:   "Node",
+			Policy: "cis-1.8",
+			Rule:   "4.1.2",
+			Status: "warn",
+		}})
+
+		if len(source.NodeResults["worker-1"]["fail"]) != 1 || len(source.NodeResults["worker-1"]["warn"]) != 1 {
+			t.Errorf("Unexpected Node Results: %v", source.NodeResults["worker-1"])
+		}
+		if source.NodeResults["worker-1"]["error"] == nil {
+			t.Errorf("Expected error map is initialized")
+		}
+	})
 	t.Run("Source.AddNamespacedPassed", func(t *testing.T) {
 		source.AddNamespacedPassed("test", 2)
 
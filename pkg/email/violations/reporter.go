@@ -2,21 +2,42 @@ package violations
 
 import (
 	"html/template"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/kyverno/policy-reporter/pkg/email"
+	"github.com/kyverno/policy-reporter/pkg/taxonomy"
+	"github.com/kyverno/policy-reporter/pkg/timeformat"
 )
 
+var attachmentHeader = []string{"source", "namespace", "status", "policy", "rule", "kind", "name"}
+
+type attachmentRow struct {
+	Source    string `json:"source"`
+	Namespace string `json:"namespace,omitempty"`
+	Status    string `json:"status"`
+	Policy    string `json:"policy"`
+	Rule      string `json:"rule"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+}
+
 type Reporter struct {
 	templateDir string
 	clusterName string
+	taxonomy    *taxonomy.Taxonomy
 }
 
-func (o *Reporter) Report(sources []Source, format string) (email.Report, error) {
+func (o *Reporter) Report(sources []Source, format string, options email.ReportOptions) (email.Report, error) {
 	b := new(strings.Builder)
 
-	vioTempl := template.New("violations.html").Funcs(template.FuncMap{
+	sources = displaySources(sources, o.taxonomy)
+
+	path := email.TemplatePath(o.templateDir, options.Template, "violations.html")
+
+	vioTempl := template.New(filepath.Base(path)).Funcs(template.FuncMap{
 		"color": email.ColorFromStatus,
 		"title": strings.Title,
 		"hasViolations": func(results map[string][]Result) bool {
@@ -27,28 +48,122 @@ func (o *Reporter) Report(sources []Source, format string) (email.Report, error)
 		},
 	})
 
-	templ, err := vioTempl.ParseFiles(o.templateDir + "/violations.html")
+	templ, err := vioTempl.ParseFiles(path)
 	if err != nil {
 		return email.Report{}, err
 	}
 
+	status := []string{"warn", "fail", "error"}
+
 	err = templ.Execute(b, struct {
 		Sources     []Source
 		Status      []string
 		ClusterName string
-	}{Sources: sources, Status: []string{"warn", "fail", "error"}, ClusterName: o.clusterName})
+	}{Sources: sources, Status: status, ClusterName: o.clusterName})
 	if err != nil {
 		return email.Report{}, err
 	}
 
+	var attachments []email.Attachment
+	if len(options.Attachments) > 0 {
+		rows := attachmentRows(sources, status)
+
+		records := make([][]string, 0, len(rows))
+		for _, r := range rows {
+			records = append(records, []string{r.Source, r.Namespace, r.Status, r.Policy, r.Rule, r.Kind, r.Name})
+		}
+
+		attachments, err = email.CreateAttachments("violations", options.Attachments, attachmentHeader, records, rows)
+		if err != nil {
+			return email.Report{}, err
+		}
+	}
+
 	return email.Report{
 		ClusterName: o.clusterName,
-		Title:       "Summary Report from " + time.Now().Format("2006-01-02"),
+		Title:       "Summary Report from " + timeformat.In(time.Now()).Format("2006-01-02"),
 		Message:     b.String(),
 		Format:      format,
+		Attachments: attachments,
 	}, nil
 }
 
-func NewReporter(templateDir string, clusterName string) *Reporter {
-	return &Reporter{templateDir, clusterName}
+func attachmentRows(sources []Source, status []string) []attachmentRow {
+	rows := make([]attachmentRow, 0)
+
+	for _, source := range sources {
+		for _, s := range status {
+			for _, r := range source.ClusterResults[s] {
+				rows = append(rows, attachmentRow{Source: source.Name, Status: r.Status, Policy: r.Policy, Rule: r.Rule, Kind: r.Kind, Name: r.Name})
+			}
+		}
+
+		namespaces := make([]string, 0, len(source.NamespaceResults))
+		for ns := range source.NamespaceResults {
+			namespaces = append(namespaces, ns)
+		}
+		sort.Strings(namespaces)
+
+		for _, ns := range namespaces {
+			for _, s := range status {
+				for _, r := range source.NamespaceResults[ns][s] {
+					rows = append(rows, attachmentRow{Source: source.Name, Namespace: ns, Status: r.Status, Policy: r.Policy, Rule: r.Rule, Kind: r.Kind, Name: r.Name})
+				}
+			}
+		}
+	}
+
+	return rows
+}
+
+// displaySources renames the sources with their display names, merges sources with the same display name and sorts them
+func displaySources(sources []Source, t *taxonomy.Taxonomy) []Source {
+	if t.Empty() {
+		return sources
+	}
+
+	merged := make(map[string]*Source, len(sources))
+	names := make([]string, 0, len(sources))
+
+	for _, source := range sources {
+		name := t.Sources.Name(source.Name)
+
+		m, ok := merged[name]
+		if !ok {
+			m = NewSource(name, source.ClusterReports)
+			merged[name] = m
+			names = append(names, source.Name)
+		}
+
+		m.ClusterPassed += source.ClusterPassed
+		for status, results := range source.ClusterResults {
+			m.ClusterResults[status] = append(m.ClusterResults[status], results...)
+		}
+
+		for ns, passed := range source.NamespacePassed {
+			m.NamespacePassed[ns] += passed
+		}
+
+		for ns, statusResults := range source.NamespaceResults {
+			m.InitResults(ns)
+
+			for status, results := range statusResults {
+				m.NamespaceResults[ns][status] = append(m.NamespaceResults[ns][status], results...)
+			}
+		}
+	}
+
+	t.Sources.Sort(names)
+
+	list := make([]Source, 0, len(names))
+	for _, name := range names {
+		list = append(list, *merged[t.Sources.Name(name)])
+	}
+
+	return list
+}
+
+// NewReporter creates a Reporter, the taxonomy is optional and maps the source names
+func NewReporter(templateDir string, clusterName string, taxonomy *taxonomy.Taxonomy) *Reporter {
+	return &Reporter{templateDir, clusterName, taxonomy}
 }
@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/kyverno/policy-reporter/pkg/email"
 	"github.com/kyverno/policy-reporter/pkg/email/violations"
 	"github.com/kyverno/policy-reporter/pkg/fixtures"
 )
@@ -38,8 +41,8 @@ func Test_CreateReport(t *testing.T) {
 
 	fmt.Println(path)
 
-	reporter := violations.NewReporter("../../../templates", "Cluster")
-	report, err := reporter.Report(data, "html")
+	reporter := violations.NewReporter("../../../templates", "Cluster", nil)
+	report, err := reporter.Report(data, "html", email.ReportOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	}
@@ -54,3 +57,62 @@ func Test_CreateReport(t *testing.T) {
 		t.Fatal("expected format to be set")
 	}
 }
+
+func Test_CreateReportWithCustomTemplate(t *testing.T) {
+	ctx := context.Background()
+
+	client, pClient, _ := NewFakeClient()
+
+	_, _ = pClient.Create(ctx, fixtures.DefaultPolicyReport, v1.CreateOptions{})
+
+	generator := violations.NewGenerator(client, filter, true)
+	data, err := generator.GenerateData(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "custom.html"), []byte("<h1>{{ .ClusterName }}</h1>{{ range .Sources }}{{ .Name }}{{ end }}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	reporter := violations.NewReporter(dir, "Cluster", nil)
+
+	t.Run("Custom Template", func(t *testing.T) {
+		report, err := reporter.Report(data, "html", email.ReportOptions{Template: "custom.html"})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if !strings.HasPrefix(report.Message, "<h1>Cluster</h1>") {
+			t.Errorf("expected custom template to be rendered, got: %s", report.Message)
+		}
+	})
+
+	t.Run("Attachments", func(t *testing.T) {
+		report, err := reporter.Report(data, "html", email.ReportOptions{Template: filepath.Join(dir, "custom.html"), Attachments: []string{"csv", "json"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if len(report.Attachments) != 2 {
+			t.Fatalf("expected 2 attachments, got %d", len(report.Attachments))
+		}
+		if report.Attachments[0].Name != "violations.csv" || report.Attachments[0].MimeType != "text/csv" {
+			t.Errorf("unexpected csv attachment: %s", report.Attachments[0].Name)
+		}
+		if !strings.HasPrefix(string(report.Attachments[0].Data), "source,namespace,status,policy,rule,kind,name\n") {
+			t.Errorf("unexpected csv header: %s", report.Attachments[0].Data)
+		}
+		if report.Attachments[1].Name != "violations.json" || report.Attachments[1].MimeType != "application/json" {
+			t.Errorf("unexpected json attachment: %s", report.Attachments[1].Name)
+		}
+	})
+
+	t.Run("Unsupported Attachment", func(t *testing.T) {
+		_, err := reporter.Report(data, "html", email.ReportOptions{Template: "custom.html", Attachments: []string{"xml"}})
+		if err == nil {
+			t.Error("expected error for unsupported attachment format")
+		}
+	})
+}
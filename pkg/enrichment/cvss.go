@@ -0,0 +1,95 @@
+package enrichment
+
+import (
+	"strings"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// DefaultScoreProperties are read in order until a valid CVSS score is found
+var DefaultScoreProperties = []string{v1alpha2.CVSSScoreKey, "score", "cvss", "nvd.v3Score"}
+
+// Thresholds are the minimum CVSS scores of each severity, lower scores are info
+type Thresholds struct {
+	Critical float64
+	High     float64
+	Medium   float64
+	Low      float64
+}
+
+// DefaultThresholds of the CVSS v3 qualitative severity rating
+var DefaultThresholds = Thresholds{Critical: 9, High: 7, Medium: 4, Low: 0.1}
+
+// Severity of the score
+func (t Thresholds) Severity(score float64) v1alpha2.PolicySeverity {
+	switch {
+	case score >= t.Critical:
+		return v1alpha2.SeverityCritical
+	case score >= t.High:
+		return v1alpha2.SeverityHigh
+	case score >= t.Medium:
+		return v1alpha2.SeverityMedium
+	case score >= t.Low:
+		return v1alpha2.SeverityLow
+	default:
+		return v1alpha2.SeverityInfo
+	}
+}
+
+// CVSSSeverity derives the severity of results from their CVSS score property,
+// so filters and the priority mapping work the same for all scanners
+type CVSSSeverity struct {
+	sources    []string
+	properties []string
+	thresholds Thresholds
+	override   bool
+}
+
+// Map sets the severity of each result with a CVSS score, results without source use the source of the report
+func (c *CVSSSeverity) Map(rep v1alpha2.ReportInterface) {
+	results := rep.GetResults()
+
+	for i := range results {
+		source := results[i].Source
+		if source == "" {
+			source = rep.GetSource()
+		}
+		if !c.matches(source) {
+			continue
+		}
+
+		score, ok := parseScore(property(&results[i], c.properties...))
+		if !ok {
+			continue
+		}
+
+		severity := c.thresholds.Severity(score)
+		if results[i].Severity == "" || (c.override && results[i].Severity != severity) {
+			results[i].Severity = severity
+		}
+	}
+}
+
+func (c *CVSSSeverity) matches(source string) bool {
+	if len(c.sources) == 0 {
+		return true
+	}
+
+	for _, s := range c.sources {
+		if strings.EqualFold(s, source) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewCVSSSeverity creates a CVSSSeverity for the sources, all sources if empty. Properties default to DefaultScoreProperties,
+// override replaces severities inconsistent with the score, otherwise only missing severities are set
+func NewCVSSSeverity(sources, properties []string, thresholds Thresholds, override bool) *CVSSSeverity {
+	if len(properties) == 0 {
+		properties = DefaultScoreProperties
+	}
+
+	return &CVSSSeverity{sources: sources, properties: properties, thresholds: thresholds, override: override}
+}
@@ -0,0 +1,49 @@
+package enrichment_test
+
+import (
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/enrichment"
+)
+
+func Test_CVSSSeverity(t *testing.T) {
+	newReport := func() *v1alpha2.PolicyReport {
+		return &v1alpha2.PolicyReport{Results: []v1alpha2.PolicyReportResult{
+			{Source: "grype", Policy: "CVE-2022-1", Properties: map[string]string{"cvss": "7.5"}},
+			{Source: "grype", Policy: "CVE-2022-2", Severity: v1alpha2.SeverityLow, Properties: map[string]string{"cvssScore": "9.1"}},
+			{Source: "grype", Policy: "CVE-2022-3", Properties: map[string]string{"cvssScore": "invalid"}},
+			{Source: "kyverno", Policy: "require-labels", Properties: map[string]string{"score": "5"}},
+		}}
+	}
+
+	t.Run("Missing severities", func(t *testing.T) {
+		rep := newReport()
+		enrichment.NewCVSSSeverity([]string{"Grype"}, nil, enrichment.DefaultThresholds, false).Map(rep)
+
+		if rep.Results[0].Severity != v1alpha2.SeverityHigh {
+			t.Errorf("expected high severity, got %s", rep.Results[0].Severity)
+		}
+		if rep.Results[1].Severity != v1alpha2.SeverityLow {
+			t.Errorf("expected the existing severity to be kept, got %s", rep.Results[1].Severity)
+		}
+		if rep.Results[2].Severity != "" {
+			t.Errorf("expected no severity for an invalid score, got %s", rep.Results[2].Severity)
+		}
+		if rep.Results[3].Severity != "" {
+			t.Errorf("expected results of other sources to be skipped, got %s", rep.Results[3].Severity)
+		}
+	})
+
+	t.Run("Override inconsistent severities", func(t *testing.T) {
+		rep := newReport()
+		enrichment.NewCVSSSeverity(nil, nil, enrichment.Thresholds{Critical: 9.5, High: 7, Medium: 5, Low: 1}, true).Map(rep)
+
+		if rep.Results[1].Severity != v1alpha2.SeverityHigh {
+			t.Errorf("expected severity of the custom thresholds, got %s", rep.Results[1].Severity)
+		}
+		if rep.Results[3].Severity != v1alpha2.SeverityMedium {
+			t.Errorf("expected all sources without source filter, got %s", rep.Results[3].Severity)
+		}
+	})
+}
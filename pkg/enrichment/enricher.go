@@ -0,0 +1,104 @@
+// Package enrichment normalizes the source specific properties of results,
+// e.g. CVE IDs, CVSS scores and check IDs, into the well known result properties.
+package enrichment
+
+import (
+	"strings"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+)
+
+var logger = logging.Component("enrichment")
+
+// Mapper normalizes the results of a specific source
+type Mapper interface {
+	// Name of the mapper used in the configuration
+	Name() string
+	// Matches reports whether the mapper handles results of the source
+	Matches(source string) bool
+	// Map the result in place
+	Map(result *v1alpha2.PolicyReportResult)
+}
+
+// Enricher applies the first matching mapper to each result of a report
+type Enricher struct {
+	mappers []Mapper
+}
+
+// Register an additional mapper, mappers are matched in registration order
+func (e *Enricher) Register(mapper Mapper) {
+	e.mappers = append(e.mappers, mapper)
+}
+
+// Enrich maps the results of the report in place, results without source use the source of the report
+func (e *Enricher) Enrich(rep v1alpha2.ReportInterface) {
+	results := rep.GetResults()
+
+	for i := range results {
+		source := results[i].Source
+		if source == "" {
+			source = rep.GetSource()
+		}
+
+		for _, mapper := range e.mappers {
+			if mapper.Matches(source) {
+				mapper.Map(&results[i])
+				break
+			}
+		}
+	}
+}
+
+// NewEnricher creates an Enricher with the given mappers
+func NewEnricher(mappers ...Mapper) *Enricher {
+	return &Enricher{mappers: mappers}
+}
+
+// Builtin returns the included mappers with the given names, all mappers without names
+func Builtin(names ...string) []Mapper {
+	mappers := []Mapper{Trivy{}, Falco{}, KubeBench{}}
+	if len(names) == 0 {
+		return mappers
+	}
+
+	list := make([]Mapper, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, mapper := range mappers {
+			if strings.EqualFold(mapper.Name(), name) {
+				list = append(list, mapper)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			logger.Warnf("unknown source mapper '%s'", name)
+		}
+	}
+
+	return list
+}
+
+func property(result *v1alpha2.PolicyReportResult, keys ...string) string {
+	for _, key := range keys {
+		if value := strings.TrimSpace(result.Properties[key]); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+func setProperty(result *v1alpha2.PolicyReportResult, key, value string) {
+	if value == "" {
+		return
+	}
+
+	if result.Properties == nil {
+		result.Properties = make(map[string]string, 1)
+	}
+
+	result.Properties[key] = value
+}
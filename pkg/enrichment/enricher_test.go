@@ -0,0 +1,125 @@
+package enrichment_test
+
+import (
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/enrichment"
+)
+
+func Test_Enricher(t *testing.T) {
+	t.Run("Map Trivy vulnerability", func(t *testing.T) {
+		rep := &v1alpha2.PolicyReport{Results: []v1alpha2.PolicyReportResult{{
+			Source:     "Trivy Vulnerability",
+			Policy:     "cve-2022-1234",
+			Rule:       "openssl",
+			Properties: map[string]string{"score": "9.8"},
+		}}}
+
+		enrichment.NewEnricher(enrichment.Builtin()...).Enrich(rep)
+
+		result := rep.Results[0]
+		if result.Properties[v1alpha2.VulnerabilityIDKey] != "CVE-2022-1234" {
+			t.Errorf("expected normalized vulnerability ID, got %s", result.Properties[v1alpha2.VulnerabilityIDKey])
+		}
+		if result.Properties[v1alpha2.CVSSScoreKey] != "9.8" {
+			t.Errorf("expected CVSS score 9.8, got %s", result.Properties[v1alpha2.CVSSScoreKey])
+		}
+		if result.Severity != v1alpha2.SeverityCritical {
+			t.Errorf("expected severity from CVSS score, got %s", result.Severity)
+		}
+	})
+	t.Run("Map Trivy config audit", func(t *testing.T) {
+		rep := &v1alpha2.PolicyReport{Results: []v1alpha2.PolicyReportResult{{
+			Source:   "Trivy ConfigAudit",
+			Policy:   "Root file system is not read-only",
+			Rule:     "KSV014",
+			Severity: v1alpha2.SeverityLow,
+		}}}
+
+		enrichment.NewEnricher(enrichment.Builtin()...).Enrich(rep)
+
+		result := rep.Results[0]
+		if result.Properties[v1alpha2.CheckIDKey] != "KSV014" {
+			t.Errorf("expected check ID KSV014, got %s", result.Properties[v1alpha2.CheckIDKey])
+		}
+		if _, ok := result.Properties[v1alpha2.VulnerabilityIDKey]; ok {
+			t.Error("expected no vulnerability ID")
+		}
+		if result.Severity != v1alpha2.SeverityLow {
+			t.Errorf("expected unchanged severity, got %s", result.Severity)
+		}
+	})
+	t.Run("Map Falco event", func(t *testing.T) {
+		rep := &v1alpha2.PolicyReport{Results: []v1alpha2.PolicyReportResult{{
+			Source:     "Falco",
+			Policy:     "Falco Events",
+			Rule:       "Terminal shell in container",
+			Properties: map[string]string{"priority": "Warning"},
+		}}}
+
+		enrichment.NewEnricher(enrichment.Builtin()...).Enrich(rep)
+
+		result := rep.Results[0]
+		if result.Properties[v1alpha2.CheckIDKey] != "Terminal shell in container" {
+			t.Errorf("expected falco rule as check ID, got %s", result.Properties[v1alpha2.CheckIDKey])
+		}
+		if result.Severity != v1alpha2.SeverityMedium {
+			t.Errorf("expected severity from falco priority, got %s", result.Severity)
+		}
+	})
+	t.Run("Map kube-bench result", func(t *testing.T) {
+		rep := &v1alpha2.ClusterPolicyReport{Results: []v1alpha2.PolicyReportResult{{
+			Source: "kube-bench",
+			Policy: "Control Plane Configuration",
+			Rule:   "1.2.16 Ensure that the admission control plugin PodSecurityPolicy is set",
+		}}}
+
+		enrichment.NewEnricher(enrichment.Builtin()...).Enrich(rep)
+
+		if id := rep.Results[0].Properties[v1alpha2.CheckIDKey]; id != "1.2.16" {
+			t.Errorf("expected benchmark number as check ID, got %s", id)
+		}
+	})
+	t.Run("Map ValidatingAdmissionPolicy result", func(t *testing.T) {
+		rep := &v1alpha2.ClusterPolicyReport{Results: []v1alpha2.PolicyReportResult{
+			{Source: "ValidatingAdmissionPolicy", Policy: "check-replicas", Properties: map[string]string{"binding": "check-replicas-binding"}},
+			{Source: "kyverno-vap", Policy: "check-labels/check-labels-binding", Category: "Best Practices"},
+		}}
+
+		enrichment.NewEnricher(enrichment.ValidatingAdmissionPolicy{}).Enrich(rep)
+
+		for _, result := range rep.Results {
+			if result.Source != enrichment.ValidatingAdmissionPolicySource {
+				t.Errorf("expected normalized source, got %s", result.Source)
+			}
+			if result.Properties[v1alpha2.BindingKey] != result.Policy+"-binding" {
+				t.Errorf("expected binding of policy %s, got %s", result.Policy, result.Properties[v1alpha2.BindingKey])
+			}
+		}
+		if rep.Results[0].Category != enrichment.ValidatingAdmissionPolicyCategory || rep.Results[1].Category != "Best Practices" {
+			t.Errorf("expected default category, got %s and %s", rep.Results[0].Category, rep.Results[1].Category)
+		}
+		if rep.Results[1].Policy != "check-labels" {
+			t.Errorf("expected VAP name as policy, got %s", rep.Results[1].Policy)
+		}
+	})
+	t.Run("Ignore unknown sources", func(t *testing.T) {
+		rep := &v1alpha2.PolicyReport{Results: []v1alpha2.PolicyReportResult{{
+			Source: "Kyverno",
+			Policy: "CVE-2022-1234",
+		}}}
+
+		enrichment.NewEnricher(enrichment.Builtin()...).Enrich(rep)
+
+		if rep.Results[0].Properties != nil {
+			t.Error("expected results of unknown sources to be unchanged")
+		}
+	})
+	t.Run("Builtin by name", func(t *testing.T) {
+		mappers := enrichment.Builtin("Trivy", "unknown")
+		if len(mappers) != 1 || mappers[0].Name() != "trivy" {
+			t.Errorf("expected only the trivy mapper, got %d mappers", len(mappers))
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package enrichment
+
+import (
+	"strings"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// DefaultErrorProperties are read in order until the engine error of a result is found
+var DefaultErrorProperties = []string{"error", "engine.error", "reason", "details"}
+
+// ErrorDetails adds the engine error of results with status error as ErrorDetailsKey property,
+// so operations targets render the cause of broken policies and engine failures the same for all sources
+type ErrorDetails struct {
+	properties []string
+}
+
+// Map sets the error details of each result with status error, the message is used if no property is set
+func (e *ErrorDetails) Map(rep v1alpha2.ReportInterface) {
+	results := rep.GetResults()
+
+	for i := range results {
+		if results[i].Result != v1alpha2.StatusError || results[i].Properties[v1alpha2.ErrorDetailsKey] != "" {
+			continue
+		}
+
+		details := property(&results[i], e.properties...)
+		if details == "" {
+			details = strings.TrimSpace(results[i].Message)
+		}
+
+		setProperty(&results[i], v1alpha2.ErrorDetailsKey, details)
+	}
+}
+
+// NewErrorDetails creates ErrorDetails reading the given properties, defaults to DefaultErrorProperties
+func NewErrorDetails(properties []string) *ErrorDetails {
+	if len(properties) == 0 {
+		properties = DefaultErrorProperties
+	}
+
+	return &ErrorDetails{properties: properties}
+}
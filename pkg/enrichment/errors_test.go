@@ -0,0 +1,28 @@
+package enrichment_test
+
+import (
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/enrichment"
+)
+
+func Test_ErrorDetails(t *testing.T) {
+	rep := &v1alpha2.PolicyReport{Results: []v1alpha2.PolicyReportResult{
+		{Result: v1alpha2.StatusError, Message: "policy evaluation failed", Properties: map[string]string{"error": "failed to substitute variables: JMESPath query failed"}},
+		{Result: v1alpha2.StatusError, Message: " failed to load context "},
+		{Result: v1alpha2.StatusFail, Message: "label app is required", Properties: map[string]string{"error": "unrelated"}},
+	}}
+
+	enrichment.NewErrorDetails(nil).Map(rep)
+
+	if details := rep.Results[0].Properties[v1alpha2.ErrorDetailsKey]; details != "failed to substitute variables: JMESPath query failed" {
+		t.Errorf("expected the engine error of the properties, got %s", details)
+	}
+	if details := rep.Results[1].Properties[v1alpha2.ErrorDetailsKey]; details != "failed to load context" {
+		t.Errorf("expected the message without properties, got %s", details)
+	}
+	if _, ok := rep.Results[2].Properties[v1alpha2.ErrorDetailsKey]; ok {
+		t.Error("expected no error details for results without status error")
+	}
+}
@@ -0,0 +1,44 @@
+package enrichment
+
+import (
+	"strings"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// Falco maps the runtime events reported by Falcosidekick
+type Falco struct{}
+
+func (Falco) Name() string {
+	return "falco"
+}
+
+func (Falco) Matches(source string) bool {
+	return strings.EqualFold(source, "falco")
+}
+
+// Map uses the Falco rule as check ID and the Falco priority as fallback severity
+func (Falco) Map(result *v1alpha2.PolicyReportResult) {
+	check := property(result, v1alpha2.CheckIDKey, "rule")
+	if check == "" {
+		check = result.Rule
+	}
+	setProperty(result, v1alpha2.CheckIDKey, check)
+
+	if result.Severity != "" {
+		return
+	}
+
+	switch strings.ToLower(property(result, "priority")) {
+	case "emergency", "alert", "critical":
+		result.Severity = v1alpha2.SeverityCritical
+	case "error":
+		result.Severity = v1alpha2.SeverityHigh
+	case "warning":
+		result.Severity = v1alpha2.SeverityMedium
+	case "notice":
+		result.Severity = v1alpha2.SeverityLow
+	case "informational", "info", "debug":
+		result.Severity = v1alpha2.SeverityInfo
+	}
+}
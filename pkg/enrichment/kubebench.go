@@ -0,0 +1,38 @@
+package enrichment
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+var benchmarkPattern = regexp.MustCompile(`^(\d+(\.\d+)+)\s`)
+
+// KubeBench maps the CIS benchmark results of the kube-bench adapter
+type KubeBench struct{}
+
+func (KubeBench) Name() string {
+	return "kube-bench"
+}
+
+func (KubeBench) Matches(source string) bool {
+	source = strings.ToLower(source)
+
+	return source == "kube-bench" || source == "kube bench" || source == "kubebench"
+}
+
+// Map extracts the benchmark number like 1.2.3 as check ID
+func (KubeBench) Map(result *v1alpha2.PolicyReportResult) {
+	check := property(result, v1alpha2.CheckIDKey, "index", "test_number", "testNumber")
+	if check == "" {
+		for _, value := range []string{result.Rule, result.Policy} {
+			if match := benchmarkPattern.FindStringSubmatch(value + " "); match != nil {
+				check = match[1]
+				break
+			}
+		}
+	}
+
+	setProperty(result, v1alpha2.CheckIDKey, check)
+}
@@ -0,0 +1,83 @@
+package enrichment
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// DefaultLinkPath opens the filtered PolicyReports or ClusterPolicyReports view of the Policy Reporter UI
+const DefaultLinkPath = `{{ if .Namespace }}/#/policy-reports?namespaces={{ urlquery .Namespace }}&{{ else }}/#/cluster-policy-reports?{{ end }}policies={{ urlquery .Policy }}&id={{ urlquery .ID }}`
+
+// LinkData available in the path template of the deep links
+type LinkData struct {
+	ID        string
+	Source    string
+	Namespace string
+	Kind      string
+	Name      string
+	Policy    string
+	Rule      string
+	Category  string
+	Severity  string
+}
+
+// Links renders deep links of the results into an external Policy Reporter UI
+type Links struct {
+	baseURL string
+	path    *template.Template
+}
+
+// URL of the result, namespace and resource fall back to the scope of the report if the result has no resource
+func (l *Links) URL(rep v1alpha2.ReportInterface, result *v1alpha2.PolicyReportResult) string {
+	data := LinkData{
+		ID:       result.GetID(),
+		Source:   result.Source,
+		Policy:   result.Policy,
+		Rule:     result.Rule,
+		Category: result.Category,
+		Severity: string(result.Severity),
+	}
+
+	if res := result.GetResource(); res != nil {
+		data.Namespace, data.Kind, data.Name = res.Namespace, res.Kind, res.Name
+	} else if rep != nil {
+		data.Namespace = rep.GetNamespace()
+		if scope := rep.GetScope(); scope != nil {
+			data.Kind, data.Name = scope.Kind, scope.Name
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := l.path.Execute(&buf, data); err != nil {
+		logger.Errorf("failed to render the deep link of result %s: %s", data.ID, err)
+		return ""
+	}
+
+	return l.baseURL + strings.TrimSpace(buf.String())
+}
+
+// Map adds the deep link of each result as ResultURLKey property, has to run after the result IDs are final
+func (l *Links) Map(rep v1alpha2.ReportInterface) {
+	results := rep.GetResults()
+
+	for i := range results {
+		setProperty(&results[i], v1alpha2.ResultURLKey, l.URL(rep, &results[i]))
+	}
+}
+
+// NewLinks creates Links of the UI at the baseURL, the path is a Go template with the LinkData, defaults to DefaultLinkPath
+func NewLinks(baseURL, path string) (*Links, error) {
+	if path == "" {
+		path = DefaultLinkPath
+	}
+
+	tmpl, err := template.New("link").Option("missingkey=zero").Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Links{baseURL: strings.TrimSuffix(baseURL, "/"), path: tmpl}, nil
+}
@@ -0,0 +1,61 @@
+package enrichment_test
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/enrichment"
+)
+
+func Test_Links(t *testing.T) {
+	links, err := enrichment.NewLinks("https://policy-reporter.example.com/", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Namespaced", func(t *testing.T) {
+		result := &v1alpha2.PolicyReportResult{ID: "123", Policy: "require labels", Resources: []corev1.ObjectReference{{Namespace: "test", Kind: "Pod", Name: "nginx"}}}
+
+		url := links.URL(&v1alpha2.PolicyReport{}, result)
+		if url != "https://policy-reporter.example.com/#/policy-reports?namespaces=test&policies=require+labels&id=123" {
+			t.Errorf("unexpected link %s", url)
+		}
+	})
+
+	t.Run("Cluster", func(t *testing.T) {
+		result := &v1alpha2.PolicyReportResult{ID: "456", Policy: "disallow-latest"}
+
+		url := links.URL(&v1alpha2.ClusterPolicyReport{}, result)
+		if url != "https://policy-reporter.example.com/#/cluster-policy-reports?policies=disallow-latest&id=456" {
+			t.Errorf("unexpected link %s", url)
+		}
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		rep := &v1alpha2.PolicyReport{Results: []v1alpha2.PolicyReportResult{{ID: "789", Policy: "require-probes"}}}
+		rep.Namespace = "default"
+
+		links.Map(rep)
+
+		if url := rep.Results[0].Properties[v1alpha2.ResultURLKey]; url != "https://policy-reporter.example.com/#/policy-reports?namespaces=default&policies=require-probes&id=789" {
+			t.Errorf("expected the link of the report namespace, got %s", url)
+		}
+	})
+}
+
+func Test_LinksCustomPath(t *testing.T) {
+	links, err := enrichment.NewLinks("https://ui.example.com", "/results/{{ .Source }}/{{ .ID }}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if url := links.URL(nil, &v1alpha2.PolicyReportResult{ID: "123", Source: "kyverno"}); url != "https://ui.example.com/results/kyverno/123" {
+		t.Errorf("unexpected link %s", url)
+	}
+
+	if _, err := enrichment.NewLinks("https://ui.example.com", "{{ .ID "); err == nil {
+		t.Error("expected an error for an invalid path template")
+	}
+}
@@ -0,0 +1,81 @@
+package enrichment
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+var (
+	vulnerabilityPattern = regexp.MustCompile(`(?i)^(CVE-\d{4}-\d+|GHSA(-[a-z0-9]{4}){3})$`)
+	trivyCheckPattern    = regexp.MustCompile(`^(AVD-[A-Z]+-\d+|K[SC]V\d+)$`)
+)
+
+// Trivy maps the vulnerability and config audit results of the Trivy Operator
+type Trivy struct{}
+
+func (Trivy) Name() string {
+	return "trivy"
+}
+
+func (Trivy) Matches(source string) bool {
+	return strings.HasPrefix(strings.ToLower(source), "trivy")
+}
+
+func (Trivy) Map(result *v1alpha2.PolicyReportResult) {
+	id := property(result, v1alpha2.VulnerabilityIDKey, "vulnerabilityId", "vulnerability")
+	if id == "" && vulnerabilityPattern.MatchString(result.Policy) {
+		id = result.Policy
+	}
+	setProperty(result, v1alpha2.VulnerabilityIDKey, strings.ToUpper(id))
+
+	if score, ok := parseScore(property(result, v1alpha2.CVSSScoreKey, "score", "cvss", "nvd.v3Score")); ok {
+		setProperty(result, v1alpha2.CVSSScoreKey, strconv.FormatFloat(score, 'f', -1, 64))
+
+		if result.Severity == "" {
+			result.Severity = SeverityFromCVSS(score)
+		}
+	}
+
+	check := property(result, v1alpha2.CheckIDKey, "checkId", "avdID", "id")
+	if check == "" {
+		for _, value := range []string{result.Rule, result.Policy} {
+			if trivyCheckPattern.MatchString(value) {
+				check = value
+				break
+			}
+		}
+	}
+	setProperty(result, v1alpha2.CheckIDKey, check)
+}
+
+// SeverityFromCVSS maps a CVSS v3 score to the result severity
+func SeverityFromCVSS(score float64) v1alpha2.PolicySeverity {
+	switch {
+	case score >= 9:
+		return v1alpha2.SeverityCritical
+	case score >= 7:
+		return v1alpha2.SeverityHigh
+	case score >= 4:
+		return v1alpha2.SeverityMedium
+	case score > 0:
+		return v1alpha2.SeverityLow
+	default:
+		return v1alpha2.SeverityInfo
+	}
+}
+
+func parseScore(value string) (float64, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	score, err := strconv.ParseFloat(value, 64)
+	if err != nil || score < 0 || score > 10 {
+		return 0, false
+	}
+
+	return score, true
+}
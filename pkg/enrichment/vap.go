@@ -0,0 +1,47 @@
+package enrichment
+
+import (
+	"strings"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// ValidatingAdmissionPolicySource is the normalized source of the results of ValidatingAdmissionPolicies
+const ValidatingAdmissionPolicySource = "ValidatingAdmissionPolicy"
+
+// ValidatingAdmissionPolicyCategory of VAP results without category
+const ValidatingAdmissionPolicyCategory = "Validating Admission Policy"
+
+// ValidatingAdmissionPolicy maps the results Kyverno reports for ValidatingAdmissionPolicies
+type ValidatingAdmissionPolicy struct{}
+
+func (ValidatingAdmissionPolicy) Name() string {
+	return "validating-admission-policy"
+}
+
+func (ValidatingAdmissionPolicy) Matches(source string) bool {
+	switch strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(source)) {
+	case "validatingadmissionpolicy", "vap", "kyvernovap":
+		return true
+	default:
+		return false
+	}
+}
+
+// Map normalizes the source, uses the VAP name as policy and keeps the binding as property
+func (ValidatingAdmissionPolicy) Map(result *v1alpha2.PolicyReportResult) {
+	result.Source = ValidatingAdmissionPolicySource
+
+	binding := property(result, v1alpha2.BindingKey, "validatingAdmissionPolicyBinding", "bindingName", "policyBinding")
+	if policy, name, ok := strings.Cut(result.Policy, "/"); ok {
+		result.Policy = policy
+		if binding == "" {
+			binding = name
+		}
+	}
+	setProperty(result, v1alpha2.BindingKey, binding)
+
+	if result.Category == "" {
+		result.Category = ValidatingAdmissionPolicyCategory
+	}
+}
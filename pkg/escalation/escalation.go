@@ -0,0 +1,304 @@
+// Package escalation sends results which stay unresolved longer than the configured duration to additional targets
+package escalation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/email"
+	"github.com/kyverno/policy-reporter/pkg/expression"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/query"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/timeformat"
+)
+
+var logger = logging.Component("escalation")
+
+const (
+	// RuleKey property with the name of the escalation rule, added to each escalated result
+	RuleKey = "escalation"
+	// FirstSeenKey property with the formatted time the result was first seen, added to each escalated result
+	FirstSeenKey = "firstSeen"
+)
+
+// Result stored with the time it was first seen
+type Result struct {
+	Report    v1alpha2.ReportInterface
+	Result    v1alpha2.PolicyReportResult
+	FirstSeen time.Time
+}
+
+// Finder of unresolved results
+type Finder interface {
+	// FetchUnresolvedResults with a fail, warn or error status first seen before the given time
+	FetchUnresolvedResults(firstSeenBefore time.Time) ([]Result, error)
+}
+
+// Mailer sends the escalation email
+type Mailer interface {
+	Send(report email.Report, to []string) error
+}
+
+// Rule escalates matching results which are unresolved for the given duration
+type Rule struct {
+	Name string
+	// MinimumSeverity of the escalated results, all severities if empty
+	MinimumSeverity string
+	// Status of the escalated results, defaults to fail
+	Status     []string
+	Expression *expression.Expression
+	After      time.Duration
+	// Targets by name, the targets receive only escalated results
+	Targets []string
+	// Email receivers of a summary of the escalated results
+	Email []string
+}
+
+func (r Rule) matches(res Result) bool {
+	status := r.Status
+	if len(status) == 0 {
+		status = []string{v1alpha2.StatusFail}
+	}
+
+	if !contains(status, string(res.Result.Result)) {
+		return false
+	}
+
+	if r.MinimumSeverity != "" && query.SeverityRank(string(res.Result.Severity)) < query.SeverityRank(r.MinimumSeverity) {
+		return false
+	}
+
+	return r.Expression == nil || r.Expression.Validate(res.Report, res.Result)
+}
+
+// Options of the Escalator
+type Options struct {
+	Rules []Rule
+	// Clients returns the current target clients
+	Clients func() []target.Client
+	Mapper  report.Mapper
+	// Mailer for rules with email receivers, optional
+	Mailer      Mailer
+	ClusterName string
+	// Owns filters the namespaces this instance escalates, e.g. with sharding, all namespaces if nil
+	Owns func(namespace string) bool
+	// Paused skips the evaluation, e.g. during a maintenance window, results are escalated after it
+	Paused func(now time.Time) bool
+	// Snoozed skips snoozed results, they are escalated after the snooze expired
+	Snoozed func(resultID string) bool
+}
+
+// Escalator evaluates the rules periodically, each result is escalated once per rule until it is resolved
+type Escalator struct {
+	finder    Finder
+	options   Options
+	mx        *sync.Mutex
+	escalated map[string]bool
+}
+
+// Evaluate escalates all results unresolved for longer than the duration of a matching rule
+func (e *Escalator) Evaluate(now time.Time) {
+	if len(e.options.Rules) == 0 || (e.options.Paused != nil && e.options.Paused(now)) {
+		return
+	}
+
+	after := e.options.Rules[0].After
+	for _, rule := range e.options.Rules {
+		if rule.After < after {
+			after = rule.After
+		}
+	}
+
+	results, err := e.finder.FetchUnresolvedResults(now.Add(-after))
+	if err != nil {
+		logger.Errorf("failed to fetch unresolved results for escalation: %s", err)
+		return
+	}
+
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	current := make(map[string]bool, len(e.escalated))
+
+	for _, rule := range e.options.Rules {
+		escalate := make([]Result, 0)
+
+		for _, res := range results {
+			if res.FirstSeen.After(now.Add(-rule.After)) || !rule.matches(res) {
+				continue
+			}
+			if e.options.Owns != nil && !e.options.Owns(res.Report.GetNamespace()) {
+				continue
+			}
+			if e.options.Snoozed != nil && e.options.Snoozed(res.Result.GetID()) {
+				continue
+			}
+
+			key := rule.Name + "/" + res.Result.GetID()
+			current[key] = true
+
+			if e.escalated[key] {
+				continue
+			}
+
+			escalate = append(escalate, res)
+		}
+
+		if len(escalate) == 0 {
+			continue
+		}
+
+		logger.Infof("escalation %s: %d results unresolved for more than %s", rule.Name, len(escalate), rule.After)
+
+		e.send(rule, escalate)
+		e.mail(rule, escalate)
+	}
+
+	// resolved results are removed to escalate them again if they fail again
+	e.escalated = current
+}
+
+func (e *Escalator) send(rule Rule, results []Result) {
+	if len(rule.Targets) == 0 || e.options.Clients == nil {
+		return
+	}
+
+	for _, c := range e.options.Clients() {
+		if ex, ok := c.(*exclusive); ok {
+			c = ex.Client
+		}
+		if !contains(rule.Targets, c.Name()) {
+			continue
+		}
+
+		for _, res := range results {
+			result := escalated(rule, res)
+			if e.options.Mapper != nil {
+				result.Priority = e.options.Mapper.ResolvePriority(result.Policy, result.Severity)
+			}
+
+			if c.Validate(res.Report, result) {
+				c.Send(result)
+			}
+		}
+	}
+}
+
+func (e *Escalator) mail(rule Rule, results []Result) {
+	if len(rule.Email) == 0 || e.options.Mailer == nil {
+		return
+	}
+
+	lines := make([]string, 0, len(results)+1)
+	lines = append(lines, fmt.Sprintf("%d results are unresolved for more than %s:\n", len(results), rule.After))
+
+	for _, res := range results {
+		r := res.Result
+
+		resource := ""
+		if r.HasResource() {
+			resource = fmt.Sprintf(" %s %s", r.GetResource().Kind, strings.TrimPrefix(r.GetResource().Namespace+"/"+r.GetResource().Name, "/"))
+		}
+
+		lines = append(lines, fmt.Sprintf("- [%s] %s/%s:%s since %s\n  %s", r.Severity, r.Policy, r.Rule, resource, timeformat.Format(res.FirstSeen), r.Message))
+	}
+
+	title := fmt.Sprintf("Policy Reporter escalation: %s", rule.Name)
+	if e.options.ClusterName != "" {
+		title = fmt.Sprintf("%s on %s", title, e.options.ClusterName)
+	}
+
+	err := e.options.Mailer.Send(email.Report{
+		Title:       title,
+		Message:     strings.Join(lines, "\n"),
+		Format:      "text",
+		ClusterName: e.options.ClusterName,
+	}, rule.Email)
+	if err != nil {
+		logger.Errorf("failed to send escalation email %s: %s", rule.Name, err)
+	}
+}
+
+// Run evaluates the rules in the given interval until the context is canceled
+func (e *Escalator) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			e.Evaluate(now)
+		}
+	}
+}
+
+func escalated(rule Rule, res Result) v1alpha2.PolicyReportResult {
+	result := res.Result
+
+	props := make(map[string]string, len(result.Properties)+2)
+	for key, value := range result.Properties {
+		props[key] = value
+	}
+	props[RuleKey] = rule.Name
+	props[FirstSeenKey] = timeformat.Format(res.FirstSeen)
+
+	result.Properties = props
+
+	return result
+}
+
+// NewEscalator creates an Escalator for the unresolved results of the finder
+func NewEscalator(finder Finder, options Options) *Escalator {
+	return &Escalator{
+		finder:    finder,
+		options:   options,
+		mx:        new(sync.Mutex),
+		escalated: make(map[string]bool),
+	}
+}
+
+type exclusive struct {
+	target.Client
+}
+
+// Validate excludes all regular results
+func (e *exclusive) Validate(rep v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) bool {
+	return false
+}
+
+// Exclusive wraps the targets referenced by a rule, so they receive only escalated results
+func Exclusive(clients []target.Client, rules []Rule) []target.Client {
+	names := make([]string, 0)
+	for _, rule := range rules {
+		names = append(names, rule.Targets...)
+	}
+
+	list := make([]target.Client, 0, len(clients))
+	for _, c := range clients {
+		if contains(names, c.Name()) {
+			c = &exclusive{c}
+		}
+
+		list = append(list, c)
+	}
+
+	return list
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
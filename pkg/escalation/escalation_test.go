@@ -0,0 +1,158 @@
+package escalation_test
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/email"
+	"github.com/kyverno/policy-reporter/pkg/escalation"
+	"github.com/kyverno/policy-reporter/pkg/target"
+)
+
+type finder struct {
+	results []escalation.Result
+}
+
+func (f *finder) FetchUnresolvedResults(before time.Time) ([]escalation.Result, error) {
+	list := make([]escalation.Result, 0)
+	for _, res := range f.results {
+		if !res.FirstSeen.After(before) {
+			list = append(list, res)
+		}
+	}
+
+	return list, nil
+}
+
+type client struct {
+	target.BaseClient
+	sent []v1alpha2.PolicyReportResult
+}
+
+func (c *client) Send(result v1alpha2.PolicyReportResult) {
+	c.sent = append(c.sent, result)
+}
+
+func newClient(name string) *client {
+	return &client{BaseClient: target.NewBaseClient(target.ClientOptions{Name: name})}
+}
+
+type mailer struct {
+	reports []email.Report
+	to      []string
+}
+
+func (m *mailer) Send(report email.Report, to []string) error {
+	m.reports = append(m.reports, report)
+	m.to = to
+
+	return nil
+}
+
+var rep = &v1alpha2.PolicyReport{ObjectMeta: metav1.ObjectMeta{Name: "polr-test", Namespace: "test"}}
+
+func result(id string, status v1alpha2.PolicyResult, severity v1alpha2.PolicySeverity) v1alpha2.PolicyReportResult {
+	return v1alpha2.PolicyReportResult{
+		ID:        id,
+		Policy:    "require-labels",
+		Result:    status,
+		Severity:  severity,
+		Resources: []corev1.ObjectReference{{APIVersion: "v1", Kind: "Pod", Name: "nginx", Namespace: "test"}},
+	}
+}
+
+func Test_Evaluate(t *testing.T) {
+	now := time.Now()
+
+	f := &finder{results: []escalation.Result{
+		{Report: rep, Result: result("1", v1alpha2.StatusFail, v1alpha2.SeverityHigh), FirstSeen: now.Add(-5 * time.Hour)},
+		{Report: rep, Result: result("2", v1alpha2.StatusFail, v1alpha2.SeverityLow), FirstSeen: now.Add(-5 * time.Hour)},
+		{Report: rep, Result: result("3", v1alpha2.StatusWarn, v1alpha2.SeverityCritical), FirstSeen: now.Add(-5 * time.Hour)},
+		{Report: rep, Result: result("4", v1alpha2.StatusFail, v1alpha2.SeverityCritical), FirstSeen: now.Add(-time.Hour)},
+	}}
+
+	pager := newClient("pagerduty")
+	slack := newClient("slack")
+	mail := &mailer{}
+
+	rules := []escalation.Rule{{Name: "critical", MinimumSeverity: v1alpha2.SeverityHigh, After: 4 * time.Hour, Targets: []string{"pagerduty"}, Email: []string{"oncall@example.com"}}}
+
+	clients := escalation.Exclusive([]target.Client{pager, slack}, rules)
+
+	escalator := escalation.NewEscalator(f, escalation.Options{
+		Rules:   rules,
+		Clients: func() []target.Client { return clients },
+		Mailer:  mail,
+	})
+
+	escalator.Evaluate(now)
+
+	if len(pager.sent) != 1 || pager.sent[0].GetID() != "1" {
+		t.Fatalf("expected result 1 to be escalated, got %v", pager.sent)
+	}
+	if pager.sent[0].Properties[escalation.RuleKey] != "critical" || pager.sent[0].Properties[escalation.FirstSeenKey] == "" {
+		t.Errorf("expected escalation properties, got %v", pager.sent[0].Properties)
+	}
+	if len(slack.sent) != 0 {
+		t.Errorf("expected no escalation to unreferenced targets")
+	}
+	if len(mail.reports) != 1 || mail.to[0] != "oncall@example.com" {
+		t.Errorf("expected one escalation email, got %d", len(mail.reports))
+	}
+
+	escalator.Evaluate(now.Add(time.Minute))
+	if len(pager.sent) != 1 || len(mail.reports) != 1 {
+		t.Errorf("expected results to be escalated once")
+	}
+
+	// result 1 resolved and failed again
+	resolved := f.results
+	f.results = nil
+	escalator.Evaluate(now.Add(2 * time.Minute))
+
+	f.results = resolved
+	escalator.Evaluate(now.Add(3 * time.Minute))
+	if len(pager.sent) != 2 {
+		t.Errorf("expected result to be escalated again after it was resolved, got %d", len(pager.sent))
+	}
+
+	escalator.Evaluate(now.Add(4 * time.Hour))
+	if len(pager.sent) != 3 || pager.sent[2].GetID() != "4" {
+		t.Errorf("expected result 4 to be escalated after the duration")
+	}
+}
+
+func Test_Owns(t *testing.T) {
+	now := time.Now()
+	f := &finder{results: []escalation.Result{{Report: rep, Result: result("1", v1alpha2.StatusFail, v1alpha2.SeverityHigh), FirstSeen: now.Add(-time.Hour)}}}
+
+	pager := newClient("pagerduty")
+
+	escalator := escalation.NewEscalator(f, escalation.Options{
+		Rules:   []escalation.Rule{{Name: "all", After: time.Minute, Targets: []string{"pagerduty"}}},
+		Clients: func() []target.Client { return []target.Client{pager} },
+		Owns:    func(string) bool { return false },
+	})
+
+	escalator.Evaluate(now)
+	if len(pager.sent) != 0 {
+		t.Errorf("expected no escalation of results owned by another instance")
+	}
+}
+
+func Test_Exclusive(t *testing.T) {
+	clients := escalation.Exclusive([]target.Client{newClient("pagerduty"), newClient("slack")}, []escalation.Rule{{Targets: []string{"pagerduty"}}})
+
+	res := result("1", v1alpha2.StatusFail, v1alpha2.SeverityHigh)
+
+	if clients[0].Validate(rep, res) {
+		t.Errorf("expected escalation targets to skip regular results")
+	}
+	if !clients[1].Validate(rep, res) {
+		t.Errorf("expected other targets to receive regular results")
+	}
+}
@@ -0,0 +1,84 @@
+package exclusion
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/exclusion/v1alpha1"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+)
+
+var logger = logging.Component("exclusion")
+
+var GVR = v1alpha1.SchemeGroupVersion.WithResource("resultexclusions")
+
+// Watch keeps the store in sync with the ResultExclusions of the cluster until the context is done
+func Watch(ctx context.Context, client dynamic.Interface, store *Store) error {
+	informer := dynamicinformer.NewFilteredDynamicInformer(client, GVR, metav1.NamespaceAll, 0, cache.Indexers{}, nil).Informer()
+
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if exclusion := convert(obj); exclusion != nil {
+				store.Set(exclusion)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if exclusion := convert(obj); exclusion != nil {
+				store.Set(exclusion)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+
+			if exclusion := convert(obj); exclusion != nil {
+				store.Delete(exclusion)
+			}
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	informer.Run(ctx.Done())
+
+	return nil
+}
+
+// Load the current ResultExclusions once, used by one-shot commands like the email reports
+func Load(ctx context.Context, client dynamic.Interface, store *Store) error {
+	list, err := client.Resource(GVR).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for i := range list.Items {
+		if exclusion := convert(&list.Items[i]); exclusion != nil {
+			store.Set(exclusion)
+		}
+	}
+
+	return nil
+}
+
+func convert(obj interface{}) *v1alpha1.ResultExclusion {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+
+	exclusion := &v1alpha1.ResultExclusion{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), exclusion); err != nil {
+		logger.Errorf("failed to convert ResultExclusion %s/%s: %s", u.GetNamespace(), u.GetName(), err)
+		return nil
+	}
+
+	return exclusion
+}
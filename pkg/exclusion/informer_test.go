@@ -0,0 +1,94 @@
+package exclusion_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/kyverno/policy-reporter/pkg/exclusion"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+)
+
+func newUnstructured(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "policy-reporter.io/v1alpha1",
+		"kind":       "ResultExclusion",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "test"},
+		"spec": map[string]interface{}{
+			"policy":        "require-requests-and-limits-required",
+			"justification": "accepted risk",
+			"resource":      map[string]interface{}{"kinds": []interface{}{"Deployment"}},
+		},
+	}}
+}
+
+func newClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		exclusion.GVR: "ResultExclusionList",
+	}, objects...)
+}
+
+func Test_Load(t *testing.T) {
+	store := exclusion.NewStore(nil)
+
+	if err := exclusion.Load(context.Background(), newClient(newUnstructured("requests")), store); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	active := store.Active()
+	if len(active) != 1 {
+		t.Fatalf("expected 1 exclusion, got %d", len(active))
+	}
+	if active[0].Spec.Justification != "accepted risk" {
+		t.Errorf("expected converted justification, got %s", active[0].Spec.Justification)
+	}
+	if store.Validate(fixtures.DefaultPolicyReport, fixtures.FailResult) {
+		t.Error("expected result to be excluded")
+	}
+}
+
+func Test_Watch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := newClient()
+	store := exclusion.NewStore(nil)
+
+	done := make(chan error)
+	go func() {
+		done <- exclusion.Watch(ctx, client, store)
+	}()
+
+	waitFor := func(count int) {
+		for i := 0; i < 50; i++ {
+			if len(store.Active()) == count {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		t.Fatalf("expected %d exclusions, got %d", count, len(store.Active()))
+	}
+
+	_, err := client.Resource(exclusion.GVR).Namespace("test").Create(ctx, newUnstructured("requests"), metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	waitFor(1)
+
+	err = client.Resource(exclusion.GVR).Namespace("test").Delete(ctx, "requests", metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	waitFor(0)
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
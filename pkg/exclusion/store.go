@@ -0,0 +1,169 @@
+package exclusion
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/exclusion/v1alpha1"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+type entry struct {
+	exclusion *v1alpha1.ResultExclusion
+	selector  labels.Selector
+}
+
+// Store of the known ResultExclusions
+type Store struct {
+	items    map[string]entry
+	metadata report.ResourceMetadata
+	mx       *sync.RWMutex
+	now      func() time.Time
+}
+
+// Set adds or updates the exclusion, exclusions with an invalid selector are removed
+func (s *Store) Set(exclusion *v1alpha1.ResultExclusion) {
+	e := entry{exclusion: exclusion}
+
+	if exclusion.Spec.Resource != nil && exclusion.Spec.Resource.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(exclusion.Spec.Resource.Selector)
+		if err != nil {
+			logger.Errorf("invalid resource selector of ResultExclusion %s/%s: %s", exclusion.Namespace, exclusion.Name, err)
+			s.Delete(exclusion)
+			return
+		}
+
+		e.selector = selector
+	}
+
+	s.mx.Lock()
+	s.items[key(exclusion)] = e
+	s.mx.Unlock()
+}
+
+// Delete removes the exclusion
+func (s *Store) Delete(exclusion *v1alpha1.ResultExclusion) {
+	s.mx.Lock()
+	delete(s.items, key(exclusion))
+	s.mx.Unlock()
+}
+
+// Active exclusions which are not expired, sorted by namespace and name
+func (s *Store) Active() []*v1alpha1.ResultExclusion {
+	now := s.now()
+
+	s.mx.RLock()
+	list := make([]*v1alpha1.ResultExclusion, 0, len(s.items))
+	for _, e := range s.items {
+		if !e.exclusion.IsExpired(now) {
+			list = append(list, e.exclusion)
+		}
+	}
+	s.mx.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		return key(list[i]) < key(list[j])
+	})
+
+	return list
+}
+
+// Match returns the first active exclusion of the result
+func (s *Store) Match(rep v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) (*v1alpha1.ResultExclusion, bool) {
+	namespace := ""
+	if res := result.GetResource(); res != nil && res.Namespace != "" {
+		namespace = res.Namespace
+	} else if rep != nil {
+		namespace = rep.GetNamespace()
+	}
+
+	if namespace == "" {
+		return nil, false
+	}
+
+	now := s.now()
+
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	for _, e := range s.items {
+		if e.exclusion.Namespace != namespace || e.exclusion.IsExpired(now) {
+			continue
+		}
+
+		if s.matches(e, result) {
+			return e.exclusion, true
+		}
+	}
+
+	return nil, false
+}
+
+// Validate is a report.ReportResultValidation which rejects excluded results
+func (s *Store) Validate(rep v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) bool {
+	_, excluded := s.Match(rep, result)
+
+	return !excluded
+}
+
+func (s *Store) matches(e entry, result v1alpha2.PolicyReportResult) bool {
+	spec := e.exclusion.Spec
+
+	if !validate.Match(spec.Policy, result.Policy) {
+		return false
+	}
+	if spec.Rule != "" && !validate.Match(spec.Rule, result.Rule) {
+		return false
+	}
+	if spec.Resource == nil {
+		return true
+	}
+
+	res := result.GetResource()
+	if res == nil {
+		return false
+	}
+
+	if len(spec.Resource.Kinds) > 0 && !validate.MatchAny(spec.Resource.Kinds, res.Kind) {
+		return false
+	}
+
+	if len(spec.Resource.Names) > 0 && !validate.MatchAny(spec.Resource.Names, res.Name) {
+		return false
+	}
+
+	if e.selector == nil {
+		return true
+	}
+
+	if s.metadata == nil {
+		return false
+	}
+
+	meta, ok := s.metadata.Get(res)
+	if !ok {
+		return false
+	}
+
+	return e.selector.Matches(labels.Set(meta.GetLabels()))
+}
+
+func key(exclusion *v1alpha1.ResultExclusion) string {
+	return exclusion.Namespace + "/" + exclusion.Name
+}
+
+// NewStore creates an empty store, the metadata is required for exclusions with a resource label selector
+func NewStore(metadata report.ResourceMetadata) *Store {
+	return &Store{
+		items:    make(map[string]entry),
+		metadata: metadata,
+		mx:       new(sync.RWMutex),
+		now:      time.Now,
+	}
+}
@@ -0,0 +1,141 @@
+package exclusion_test
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/exclusion/v1alpha1"
+	"github.com/kyverno/policy-reporter/pkg/exclusion"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+)
+
+type metadata map[string]map[string]string
+
+func (m metadata) Get(res *corev1.ObjectReference) (*metav1.PartialObjectMetadata, bool) {
+	labels, ok := m[res.Name]
+	if !ok {
+		return nil, false
+	}
+
+	return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: res.Name, Labels: labels}}, true
+}
+
+func newExclusion(name string, spec v1alpha1.ResultExclusionSpec) *v1alpha1.ResultExclusion {
+	return &v1alpha1.ResultExclusion{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Spec:       spec,
+	}
+}
+
+func Test_Store(t *testing.T) {
+	t.Run("Match Policy and Rule", func(t *testing.T) {
+		store := exclusion.NewStore(nil)
+		store.Set(newExclusion("requests", v1alpha1.ResultExclusionSpec{Policy: "require-requests-*", Rule: "autogen-*"}))
+
+		e, ok := store.Match(fixtures.DefaultPolicyReport, fixtures.FailResult)
+		if !ok || e.Name != "requests" {
+			t.Fatal("expected result to be excluded")
+		}
+		if !store.Validate(fixtures.DefaultPolicyReport, fixtures.FailDisallowRuleResult) {
+			t.Error("expected result of another policy to be valid")
+		}
+	})
+	t.Run("Match Resource Kinds and Names", func(t *testing.T) {
+		store := exclusion.NewStore(nil)
+		store.Set(newExclusion("pods", v1alpha1.ResultExclusionSpec{
+			Policy:   "*",
+			Resource: &v1alpha1.ResourceSelector{Kinds: []string{"Pod"}, Names: []string{"ngi*"}},
+		}))
+
+		if store.Validate(fixtures.DefaultPolicyReport, fixtures.FailPodResult) {
+			t.Error("expected pod result to be excluded")
+		}
+		if !store.Validate(fixtures.DefaultPolicyReport, fixtures.FailResult) {
+			t.Error("expected deployment result to be valid")
+		}
+		if !store.Validate(fixtures.DefaultPolicyReport, fixtures.FailResultWithoutResource) {
+			t.Error("expected result without resource to be valid")
+		}
+	})
+	t.Run("Match Resource Selector", func(t *testing.T) {
+		selector := &v1alpha1.ResourceSelector{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}}
+
+		store := exclusion.NewStore(metadata{"nginx": {"team": "a"}})
+		store.Set(newExclusion("team", v1alpha1.ResultExclusionSpec{Policy: "*", Resource: selector}))
+
+		if store.Validate(fixtures.DefaultPolicyReport, fixtures.FailResult) {
+			t.Error("expected result of the labeled resource to be excluded")
+		}
+
+		store = exclusion.NewStore(metadata{"nginx": {"team": "b"}})
+		store.Set(newExclusion("team", v1alpha1.ResultExclusionSpec{Policy: "*", Resource: selector}))
+
+		if !store.Validate(fixtures.DefaultPolicyReport, fixtures.FailResult) {
+			t.Error("expected result of a resource with other labels to be valid")
+		}
+
+		store = exclusion.NewStore(nil)
+		store.Set(newExclusion("team", v1alpha1.ResultExclusionSpec{Policy: "*", Resource: selector}))
+
+		if !store.Validate(fixtures.DefaultPolicyReport, fixtures.FailResult) {
+			t.Error("expected result to be valid without metadata")
+		}
+	})
+	t.Run("Ignore other Namespaces", func(t *testing.T) {
+		store := exclusion.NewStore(nil)
+		e := newExclusion("other", v1alpha1.ResultExclusionSpec{Policy: "*"})
+		e.Namespace = "default"
+		store.Set(e)
+
+		if !store.Validate(fixtures.DefaultPolicyReport, fixtures.FailResult) {
+			t.Error("expected result of another namespace to be valid")
+		}
+		if !store.Validate(fixtures.ClusterPolicyReport, fixtures.FailNamespaceResult) {
+			t.Error("expected cluster scoped result to be valid")
+		}
+	})
+	t.Run("Ignore expired Exclusions", func(t *testing.T) {
+		store := exclusion.NewStore(nil)
+		store.Set(newExclusion("expired", v1alpha1.ResultExclusionSpec{Policy: "*", ExpiresAt: &metav1.Time{Time: time.Now().Add(-time.Hour)}}))
+		store.Set(newExclusion("active", v1alpha1.ResultExclusionSpec{Policy: "*", ExpiresAt: &metav1.Time{Time: time.Now().Add(time.Hour)}}))
+
+		active := store.Active()
+		if len(active) != 1 || active[0].Name != "active" {
+			t.Fatalf("expected only the active exclusion, got %d", len(active))
+		}
+
+		e, ok := store.Match(fixtures.DefaultPolicyReport, fixtures.FailResult)
+		if !ok || e.Name != "active" {
+			t.Error("expected result to be excluded by the active exclusion")
+		}
+	})
+	t.Run("Delete", func(t *testing.T) {
+		store := exclusion.NewStore(nil)
+		e := newExclusion("requests", v1alpha1.ResultExclusionSpec{Policy: "*"})
+		store.Set(e)
+		store.Delete(e)
+
+		if len(store.Active()) != 0 {
+			t.Error("expected exclusion to be deleted")
+		}
+		if !store.Validate(fixtures.DefaultPolicyReport, fixtures.FailResult) {
+			t.Error("expected result to be valid")
+		}
+	})
+	t.Run("Invalid Selector", func(t *testing.T) {
+		store := exclusion.NewStore(nil)
+		store.Set(newExclusion("invalid", v1alpha1.ResultExclusionSpec{
+			Policy: "*",
+			Resource: &v1alpha1.ResourceSelector{Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "team", Operator: "Unknown"},
+			}}},
+		}))
+
+		if len(store.Active()) != 0 {
+			t.Error("expected exclusion with invalid selector to be ignored")
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// Expression is a compiled CEL filter expression, evaluated with the variables "result" and "report"
+type Expression struct {
+	source  string
+	program cel.Program
+}
+
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Match evaluates the expression for the given result, the report can be nil
+func (e *Expression) Match(rep v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) (bool, error) {
+	out, _, err := e.program.Eval(map[string]interface{}{
+		"result": mapResult(result),
+		"report": mapReport(rep),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	match, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q evaluated to %v instead of bool", e.source, out.Value())
+	}
+
+	return match, nil
+}
+
+// Validate reports whether the result matches, evaluation errors like missing map keys are treated as no match
+func (e *Expression) Validate(rep v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) bool {
+	match, err := e.Match(rep, result)
+	if err != nil {
+		return false
+	}
+
+	return match
+}
+
+// Compile parses and type checks the expression, the output type has to be bool
+func Compile(source string) (*Expression, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil, fmt.Errorf("empty expression")
+	}
+
+	env, err := cel.NewEnv(
+		cel.Variable("result", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("report", cel.MapType(cel.StringType, cel.DynType)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ast, issues := env.Compile(source)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", source, issues.Err())
+	}
+
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("invalid expression %q: expected bool result, got %s", source, ast.OutputType())
+	}
+
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", source, err)
+	}
+
+	return &Expression{source: source, program: program}, nil
+}
+
+func mapResult(result v1alpha2.PolicyReportResult) map[string]interface{} {
+	properties := make(map[string]interface{}, len(result.Properties))
+	for k, v := range result.Properties {
+		properties[k] = v
+	}
+
+	values := map[string]interface{}{
+		"id":         result.GetID(),
+		"source":     result.Source,
+		"policy":     result.Policy,
+		"rule":       result.Rule,
+		"message":    result.Message,
+		"status":     string(result.Result),
+		"scored":     result.Scored,
+		"category":   result.Category,
+		"severity":   string(result.Severity),
+		"priority":   result.Priority.String(),
+		"timestamp":  result.Timestamp.Seconds,
+		"properties": properties,
+		"resource":   mapResource(result.GetResource()),
+	}
+
+	return values
+}
+
+func mapResource(res *corev1.ObjectReference) map[string]interface{} {
+	if res == nil {
+		return map[string]interface{}{}
+	}
+
+	return map[string]interface{}{
+		"apiVersion": res.APIVersion,
+		"kind":       res.Kind,
+		"name":       res.Name,
+		"namespace":  res.Namespace,
+		"uid":        string(res.UID),
+	}
+}
+
+func mapReport(rep v1alpha2.ReportInterface) map[string]interface{} {
+	if rep == nil {
+		return map[string]interface{}{
+			"name":        "",
+			"namespace":   "",
+			"labels":      map[string]interface{}{},
+			"annotations": map[string]interface{}{},
+		}
+	}
+
+	return map[string]interface{}{
+		"name":        rep.GetName(),
+		"namespace":   rep.GetNamespace(),
+		"labels":      toMap(rep.GetLabels()),
+		"annotations": toMap(rep.GetAnnotations()),
+	}
+}
+
+func toMap(values map[string]string) map[string]interface{} {
+	m := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		m[k] = v
+	}
+
+	return m
+}
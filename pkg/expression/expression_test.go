@@ -0,0 +1,85 @@
+package expression_test
+
+import (
+	"testing"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/expression"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+)
+
+var preport = &v1alpha2.PolicyReport{
+	ObjectMeta: v1.ObjectMeta{
+		Name:      "polr-test",
+		Namespace: "test",
+		Labels:    map[string]string{"team": "a"},
+	},
+}
+
+func Test_Compile(t *testing.T) {
+	t.Run("Valid Expression", func(t *testing.T) {
+		expr, err := expression.Compile(`result.severity == "high"`)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if expr.String() != `result.severity == "high"` {
+			t.Errorf("unexpected source: %s", expr.String())
+		}
+	})
+	t.Run("Syntax Error", func(t *testing.T) {
+		if _, err := expression.Compile(`result.severity ==`); err == nil {
+			t.Error("expected syntax error")
+		}
+	})
+	t.Run("Unknown Variable", func(t *testing.T) {
+		if _, err := expression.Compile(`resource.kind == "Pod"`); err == nil {
+			t.Error("expected error for undeclared variable")
+		}
+	})
+	t.Run("Non Bool Expression", func(t *testing.T) {
+		if _, err := expression.Compile(`"high"`); err == nil {
+			t.Error("expected error for non bool expression")
+		}
+	})
+	t.Run("Empty Expression", func(t *testing.T) {
+		if _, err := expression.Compile(" "); err == nil {
+			t.Error("expected error for empty expression")
+		}
+	})
+}
+
+func Test_Match(t *testing.T) {
+	result := fixtures.FailResult
+	result.Properties = map[string]string{"version": "1.2"}
+
+	cases := map[string]bool{
+		`result.status == "fail" && result.severity == "high"`:     true,
+		`result.resource.kind == "Deployment"`:                     true,
+		`result.resource.namespace.startsWith("team-")`:            false,
+		`result.properties["version"] == "1.2"`:                    true,
+		`report.labels["team"] == "a"`:                             true,
+		`"team" in report.labels && report.namespace == "default"`: false,
+		`result.policy.matches("^require-.*")`:                     true,
+		`result.properties["missing"] == "x"`:                      false,
+	}
+
+	for source, expected := range cases {
+		expr, err := expression.Compile(source)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", source, err)
+		}
+
+		if match := expr.Validate(preport, result); match != expected {
+			t.Errorf("expected %s to be %v, got %v", source, expected, match)
+		}
+	}
+
+	t.Run("Without Report", func(t *testing.T) {
+		expr, _ := expression.Compile(`!("team" in report.labels)`)
+		if !expr.Validate(nil, result) {
+			t.Error("expected expression to match without report")
+		}
+	})
+}
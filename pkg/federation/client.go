@@ -0,0 +1,153 @@
+package federation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+var logger = logging.Component("federation")
+
+// ReportsPath of the central API receiving the pushed PolicyReport events
+const ReportsPath = "/v2/federation/reports"
+
+const pushTimeout = 30 * time.Second
+
+// Client pushes the PolicyReports of an edge instance to the central Policy Reporter
+type Client struct {
+	url     string
+	token   string
+	cluster string
+	client  *http.Client
+
+	mx      *sync.Mutex
+	reports map[string]Event
+}
+
+// Cluster identifier added to each pushed PolicyReport
+func (c *Client) Cluster() string {
+	return c.cluster
+}
+
+// Push sends a single event to the central instance
+func (c *Client) Push(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("User-Agent", "Policy-Reporter")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		content, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("central instance responded with %d: %s", resp.StatusCode, content)
+	}
+
+	return nil
+}
+
+// Listen pushes each PolicyReport event, the latest version of each report is retained for the periodic sync
+func (c *Client) Listen(event report.LifecycleEvent) {
+	e := NewEvent(c.cluster, event)
+	if e.Report() == nil {
+		return
+	}
+
+	c.mx.Lock()
+	if event.Type == report.Deleted {
+		delete(c.reports, event.PolicyReport.GetID())
+	} else {
+		c.reports[event.PolicyReport.GetID()] = e
+	}
+	c.mx.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), pushTimeout)
+	defer cancel()
+
+	if err := c.Push(ctx, e); err != nil {
+		logger.Errorf("failed to push PolicyReport %s to the central instance: %s", event.PolicyReport.GetName(), err)
+	}
+}
+
+// Sync pushes all retained PolicyReports in the given interval until the context is canceled,
+// this recovers from failed pushes and a restarted central instance without persistent database
+func (c *Client) Sync(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			c.sync(ctx)
+		}
+	}
+}
+
+func (c *Client) sync(ctx context.Context) {
+	c.mx.Lock()
+	events := make([]Event, 0, len(c.reports))
+	for _, e := range c.reports {
+		events = append(events, e)
+	}
+	c.mx.Unlock()
+
+	var failed int
+	var lastErr error
+
+	for _, e := range events {
+		e.Type = report.Updated.String()
+
+		pushCtx, cancel := context.WithTimeout(ctx, pushTimeout)
+		if err := c.Push(pushCtx, e); err != nil {
+			failed++
+			lastErr = err
+		}
+		cancel()
+	}
+
+	if failed > 0 {
+		logger.Errorf("failed to sync %d of %d PolicyReports to the central instance: %s", failed, len(events), lastErr)
+	}
+}
+
+// NewClient pushes to the Policy Reporter API with the given base URL, the token is sent as bearer token
+func NewClient(url, token, cluster string, client *http.Client) *Client {
+	return &Client{
+		url:     strings.TrimSuffix(url, "/") + ReportsPath,
+		token:   token,
+		cluster: cluster,
+		client:  client,
+		mx:      new(sync.Mutex),
+		reports: make(map[string]Event),
+	}
+}
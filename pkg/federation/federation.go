@@ -0,0 +1,90 @@
+// Package federation aggregates the PolicyReports of multiple clusters, edge instances push their reports
+// with a cluster identifier to a central Policy Reporter which stores and exposes them per cluster
+package federation
+
+import (
+	"strconv"
+
+	"github.com/segmentio/fasthash/fnv1a"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+// Listener name of the edge listener
+const Listener = "federation_listener"
+
+// Event of a PolicyReport pushed from an edge to the central instance, exactly one report is set
+type Event struct {
+	Cluster             string                        `json:"cluster"`
+	Type                string                        `json:"type"`
+	PolicyReport        *v1alpha2.PolicyReport        `json:"policyReport,omitempty"`
+	ClusterPolicyReport *v1alpha2.ClusterPolicyReport `json:"clusterPolicyReport,omitempty"`
+}
+
+// Report returns the pushed PolicyReport or ClusterPolicyReport, nil if none is set
+func (e Event) Report() v1alpha2.ReportInterface {
+	if e.PolicyReport != nil {
+		return e.PolicyReport
+	}
+	if e.ClusterPolicyReport != nil {
+		return e.ClusterPolicyReport
+	}
+
+	return nil
+}
+
+// NewEvent of the PolicyReport lifecycle event
+func NewEvent(cluster string, event report.LifecycleEvent) Event {
+	e := Event{Cluster: cluster, Type: event.Type.String()}
+
+	switch r := event.PolicyReport.(type) {
+	case *v1alpha2.PolicyReport:
+		e.PolicyReport = r
+	case *v1alpha2.ClusterPolicyReport:
+		e.ClusterPolicyReport = r
+	}
+
+	return e
+}
+
+// ReportID of a pushed PolicyReport, reports with the same name in different clusters get different IDs
+func ReportID(cluster, id string) string {
+	h1 := fnv1a.Init64
+	h1 = fnv1a.AddString64(h1, cluster)
+	h1 = fnv1a.AddString64(h1, id)
+
+	return strconv.FormatUint(h1, 10)
+}
+
+type clusterReport struct {
+	v1alpha2.ReportInterface
+	id string
+}
+
+func (r clusterReport) GetID() string {
+	return r.id
+}
+
+// WithCluster returns a copy of the PolicyReport with the cluster label and the cluster specific ID
+func WithCluster(cluster string, polr v1alpha2.ReportInterface) v1alpha2.ReportInterface {
+	var labeled v1alpha2.ReportInterface
+
+	switch r := polr.(type) {
+	case *v1alpha2.PolicyReport:
+		labeled = r.DeepCopy()
+	case *v1alpha2.ClusterPolicyReport:
+		labeled = r.DeepCopy()
+	default:
+		labeled = polr
+	}
+
+	labels := make(map[string]string, len(labeled.GetLabels())+1)
+	for key, value := range labeled.GetLabels() {
+		labels[key] = value
+	}
+	labels[report.ClusterLabel] = cluster
+	labeled.SetLabels(labels)
+
+	return clusterReport{ReportInterface: labeled, id: ReportID(cluster, polr.GetID())}
+}
@@ -0,0 +1,165 @@
+package federation_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/federation"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/sqlite3"
+)
+
+var preport = &v1alpha2.PolicyReport{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:              "polr-test",
+		Namespace:         "test",
+		Labels:            map[string]string{"app": "policy-reporter"},
+		CreationTimestamp: metav1.Now(),
+	},
+	Results: []v1alpha2.PolicyReportResult{fixtures.FailResult, fixtures.PassPodResult},
+	Summary: v1alpha2.PolicyReportSummary{Fail: 1, Pass: 1},
+}
+
+func newStore(t *testing.T) sqlite3.PolicyReportStore {
+	db, err := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	store, err := sqlite3.NewPolicyReportStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return store
+}
+
+func Test_Federation(t *testing.T) {
+	store := newStore(t)
+	store.Add(preport)
+
+	receiver := federation.NewReceiver(store)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != federation.ReportsPath || req.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		receiver.Handler()(w, req)
+	}))
+	defer server.Close()
+
+	edgeA := federation.NewClient(server.URL+"/", "token", "edge-a", server.Client())
+	edgeB := federation.NewClient(server.URL, "token", "edge-b", server.Client())
+
+	t.Run("Push", func(t *testing.T) {
+		edgeA.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: preport})
+		edgeB.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: preport})
+
+		if _, ok := store.Get(federation.ReportID("edge-a", preport.GetID())); !ok {
+			t.Error("expected stored report of edge-a")
+		}
+		if _, ok := store.Get(preport.GetID()); !ok {
+			t.Error("expected local report to be unchanged")
+		}
+
+		count, _ := store.CountPolicyReports(v1.Filter{ReportLabel: map[string]string{report.ClusterLabel: "edge-b"}})
+		if count != 1 {
+			t.Errorf("expected 1 report of edge-b, got %d", count)
+		}
+
+		results, _ := store.CountNamespacedResults(v1.Filter{ReportLabel: map[string]string{report.ClusterLabel: "edge-a"}})
+		if results != 2 {
+			t.Errorf("expected 2 results of edge-a, got %d", results)
+		}
+	})
+
+	t.Run("Summaries", func(t *testing.T) {
+		list, err := federation.Summaries(store, "central")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list) != 3 {
+			t.Fatalf("expected 3 clusters, got %v", list)
+		}
+
+		names := make([]string, 0, len(list))
+		for _, s := range list {
+			names = append(names, s.Name)
+			if s.Reports != 1 || s.Fail != 1 || s.Pass != 1 {
+				t.Errorf("unexpected summary %+v", s)
+			}
+		}
+
+		if strings.Join(names, ",") != "central,edge-a,edge-b" {
+			t.Errorf("unexpected clusters %v", names)
+		}
+
+		if count := testutil.CollectAndCount(federation.NewCollector(store, "central")); count != 15 {
+			t.Errorf("expected 15 metrics, got %d", count)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		edgeA.Listen(report.LifecycleEvent{Type: report.Deleted, PolicyReport: preport})
+
+		if _, ok := store.Get(federation.ReportID("edge-a", preport.GetID())); ok {
+			t.Error("expected removed report of edge-a")
+		}
+		if _, ok := store.Get(federation.ReportID("edge-b", preport.GetID())); !ok {
+			t.Error("expected report of edge-b to be unchanged")
+		}
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		client := federation.NewClient(server.URL, "invalid", "edge-c", server.Client())
+
+		err := client.Push(context.Background(), federation.NewEvent("edge-c", report.LifecycleEvent{Type: report.Added, PolicyReport: preport}))
+		if err == nil {
+			t.Error("expected error for rejected push")
+		}
+	})
+}
+
+func Test_ReceiverHandler(t *testing.T) {
+	handler := federation.NewReceiver(newStore(t)).Handler()
+
+	t.Run("Method", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest(http.MethodGet, federation.ReportsPath, nil))
+
+		if rr.Code != http.StatusMethodNotAllowed {
+			t.Errorf("expected 405, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest(http.MethodPost, federation.ReportsPath, strings.NewReader(`{"cluster":"edge","type":"add"}`)))
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+
+	t.Run("UnknownType", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest(http.MethodPost, federation.ReportsPath, strings.NewReader(`{"cluster":"edge","type":"patch","policyReport":{"metadata":{"name":"polr","namespace":"test"}}}`)))
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+}
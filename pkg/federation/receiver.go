@@ -0,0 +1,82 @@
+package federation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/kyverno/policy-reporter/pkg/helper"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+// maxEventSize limits the request body of a pushed event
+const maxEventSize = 32 << 20
+
+// Receiver stores the PolicyReports pushed by edge instances with their cluster label
+type Receiver struct {
+	store report.PolicyReportStore
+}
+
+// Receive applies the event to the store, added and updated reports replace the stored version
+func (r *Receiver) Receive(event Event) error {
+	if err := validate(event); err != nil {
+		return err
+	}
+
+	if event.Type == report.Deleted.String() {
+		return r.store.Remove(ReportID(event.Cluster, event.Report().GetID()))
+	}
+
+	return r.store.Add(WithCluster(event.Cluster, event.Report()))
+}
+
+// Handler of the events pushed to ReportsPath
+func (r *Receiver) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			helper.SendError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+			return
+		}
+
+		event := Event{}
+		if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, maxEventSize)).Decode(&event); err != nil {
+			helper.SendBadRequest(w, err)
+			return
+		}
+
+		if err := validate(event); err != nil {
+			helper.SendBadRequest(w, err)
+			return
+		}
+
+		if err := r.Receive(event); err != nil {
+			helper.SendJSONResponse(w, nil, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func validate(event Event) error {
+	if event.Cluster == "" {
+		return errors.New("missing cluster")
+	}
+	if event.Report() == nil {
+		return errors.New("missing policyReport or clusterPolicyReport")
+	}
+
+	switch event.Type {
+	case report.Added.String(), report.Updated.String(), report.Deleted.String():
+		return nil
+	}
+
+	return fmt.Errorf("unknown event type '%s'", event.Type)
+}
+
+// NewReceiver stores the pushed PolicyReports in the given store
+func NewReceiver(store report.PolicyReportStore) *Receiver {
+	return &Receiver{store: store}
+}
@@ -0,0 +1,73 @@
+package federation
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ClusterSummary of the PolicyReports of a single cluster
+type ClusterSummary struct {
+	Name    string `json:"name"`
+	Reports int    `json:"reports"`
+	Pass    int    `json:"pass"`
+	Skip    int    `json:"skip"`
+	Warn    int    `json:"warn"`
+	Fail    int    `json:"fail"`
+	Error   int    `json:"error"`
+}
+
+// SummaryFinder aggregates the stored PolicyReports by their cluster label,
+// reports without label are returned with an empty name
+type SummaryFinder interface {
+	FetchClusterSummaries() ([]ClusterSummary, error)
+}
+
+// Summaries of all clusters, reports without cluster label are assigned to the local cluster
+func Summaries(finder SummaryFinder, local string) ([]ClusterSummary, error) {
+	list, err := finder.FetchClusterSummaries()
+	if err != nil {
+		return list, err
+	}
+
+	for i := range list {
+		if list[i].Name == "" {
+			list[i].Name = local
+		}
+	}
+
+	return list, nil
+}
+
+var summaryDesc = prometheus.NewDesc(
+	"policy_report_cluster_summary",
+	"Summary of all PolicyReports and ClusterPolicyReports per federated cluster",
+	[]string{"cluster", "status"},
+	nil,
+)
+
+type collector struct {
+	finder SummaryFinder
+	local  string
+}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- summaryDesc
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	list, err := Summaries(c.finder, c.local)
+	if err != nil {
+		logger.Errorf("failed to collect cluster summaries: %s", err)
+		return
+	}
+
+	for _, s := range list {
+		for status, count := range map[string]int{"Pass": s.Pass, "Skip": s.Skip, "Warn": s.Warn, "Fail": s.Fail, "Error": s.Error} {
+			ch <- prometheus.MustNewConstMetric(summaryDesc, prometheus.GaugeValue, float64(count), s.Name, status)
+		}
+	}
+}
+
+// NewCollector exposes the status counts of each cluster, queried from the store on each scrape
+func NewCollector(finder SummaryFinder, local string) prometheus.Collector {
+	return &collector{finder: finder, local: local}
+}
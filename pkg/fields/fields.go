@@ -0,0 +1,219 @@
+package fields
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kyverno/policy-reporter/pkg/logging"
+)
+
+var logger = logging.Component("fields")
+
+// Types of the promoted fields
+const (
+	String = "string"
+	Int    = "int"
+	Float  = "float"
+	Bool   = "bool"
+)
+
+// Types supported by Coerce
+var Types = []string{String, Int, Float, Bool}
+
+// Reserved names of the result fields in API responses and target payloads, properties can not be promoted to them
+var Reserved = []string{
+	"apiVersion", "category", "creationTimestamp", "id", "kind", "message", "name", "namespace", "policy",
+	"priority", "properties", "resource", "rule", "scored", "severity", "source", "status", "timestamp",
+}
+
+// Mapping promotes a result property to a typed top-level field
+type Mapping struct {
+	Property string
+	Field    string
+	Type     string
+}
+
+// Mapper promotes the configured properties to typed fields
+type Mapper struct {
+	mappings []Mapping
+}
+
+// Map the properties to the typed fields, properties which can not be converted into the type of their field are skipped.
+// Returns nil if no property is promoted
+func (m *Mapper) Map(properties map[string]string) map[string]interface{} {
+	if m == nil || len(properties) == 0 {
+		return nil
+	}
+
+	var fields map[string]interface{}
+	for _, mapping := range m.mappings {
+		value, ok := properties[mapping.Property]
+		if !ok {
+			continue
+		}
+
+		typed, err := Coerce(value, mapping.Type)
+		if err != nil {
+			logger.Debugf("skip property %s for field %s: %s", mapping.Property, mapping.Field, err)
+			continue
+		}
+
+		if fields == nil {
+			fields = make(map[string]interface{}, len(m.mappings))
+		}
+
+		fields[mapping.Field] = typed
+	}
+
+	return fields
+}
+
+// Coerce the property value into the type
+func Coerce(value, typ string) (interface{}, error) {
+	trimmed := strings.TrimSpace(value)
+
+	switch typ {
+	case String, "":
+		return value, nil
+	case Int:
+		i, err := strconv.ParseInt(trimmed, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid int '%s'", value)
+		}
+
+		return i, nil
+	case Float:
+		f, err := strconv.ParseFloat(trimmed, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid float '%s'", value)
+		}
+
+		return f, nil
+	case Bool:
+		b, err := strconv.ParseBool(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bool '%s'", value)
+		}
+
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unknown type '%s'", typ)
+	}
+}
+
+// NewMapper validates the mappings, the type defaults to string
+func NewMapper(mappings []Mapping) (*Mapper, error) {
+	list := make([]Mapping, 0, len(mappings))
+	names := make(map[string]bool, len(mappings))
+
+	for _, mapping := range mappings {
+		if mapping.Type == "" {
+			mapping.Type = String
+		}
+
+		if err := validate(mapping, names); err != nil {
+			return nil, err
+		}
+
+		names[mapping.Field] = true
+		list = append(list, mapping)
+	}
+
+	return &Mapper{mappings: list}, nil
+}
+
+func validate(mapping Mapping, names map[string]bool) error {
+	if mapping.Property == "" || mapping.Field == "" {
+		return fmt.Errorf("property and field are required")
+	}
+	if !contains(Types, mapping.Type) {
+		return fmt.Errorf("unknown type '%s', expected one of %s", mapping.Type, strings.Join(Types, ", "))
+	}
+	if IsReserved(mapping.Field) {
+		return fmt.Errorf("field '%s' is reserved", mapping.Field)
+	}
+	if names[mapping.Field] {
+		return fmt.Errorf("duplicate field '%s'", mapping.Field)
+	}
+
+	return nil
+}
+
+// IsReserved returns if the field is a result field of API responses and target payloads
+func IsReserved(field string) bool {
+	return contains(Reserved, field)
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Marshal the value as JSON object with the fields added on the top level, fields of the value take precedence
+func Marshal(value interface{}, fields map[string]interface{}) ([]byte, error) {
+	raw, err := json.Marshal(value)
+	if err != nil || len(fields) == 0 {
+		return raw, err
+	}
+
+	object := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, err
+	}
+
+	for field, v := range fields {
+		if _, ok := object[field]; ok {
+			continue
+		}
+
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+
+		object[field] = encoded
+	}
+
+	return json.Marshal(object)
+}
+
+var (
+	global   *Mapper
+	globalMx sync.RWMutex
+)
+
+// Use the mappings for the package functions, no mappings disable the promotion
+func Use(mappings []Mapping) error {
+	var mapper *Mapper
+	if len(mappings) > 0 {
+		m, err := NewMapper(mappings)
+		if err != nil {
+			return err
+		}
+
+		mapper = m
+	}
+
+	globalMx.Lock()
+	defer globalMx.Unlock()
+
+	global = mapper
+
+	return nil
+}
+
+// Map the properties with the configured mappings
+func Map(properties map[string]string) map[string]interface{} {
+	globalMx.RLock()
+	defer globalMx.RUnlock()
+
+	return global.Map(properties)
+}
@@ -0,0 +1,79 @@
+package fields_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/fields"
+)
+
+func Test_Mapper(t *testing.T) {
+	mapper, err := fields.NewMapper([]fields.Mapping{
+		{Property: "cvss", Field: "score", Type: fields.Float},
+		{Property: "count", Field: "occurrences", Type: fields.Int},
+		{Property: "fixable", Field: "fixable", Type: fields.Bool},
+		{Property: "image", Field: "image"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := mapper.Map(map[string]string{"cvss": "7.5", "count": "three", "fixable": "true", "image": "nginx:1.25"})
+
+	if result["score"] != 7.5 || result["fixable"] != true || result["image"] != "nginx:1.25" {
+		t.Errorf("unexpected fields %v", result)
+	}
+	if _, ok := result["occurrences"]; ok {
+		t.Errorf("expected invalid int to be skipped, got %v", result["occurrences"])
+	}
+	if mapper.Map(map[string]string{"other": "value"}) != nil {
+		t.Error("expected nil without promoted properties")
+	}
+}
+
+func Test_NewMapper(t *testing.T) {
+	invalid := [][]fields.Mapping{
+		{{Property: "cvss", Field: "score", Type: "decimal"}},
+		{{Property: "cvss", Field: "status"}},
+		{{Property: "cvss", Field: "score"}, {Property: "score", Field: "score"}},
+		{{Property: "", Field: "score"}},
+	}
+
+	for _, mappings := range invalid {
+		if _, err := fields.NewMapper(mappings); err == nil {
+			t.Errorf("expected error for %+v", mappings)
+		}
+	}
+}
+
+func Test_Marshal(t *testing.T) {
+	value := struct {
+		Policy string `json:"policy"`
+	}{Policy: "require-labels"}
+
+	raw, err := fields.Marshal(value, map[string]interface{}{"score": 7.5, "policy": "other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	object := make(map[string]interface{})
+	json.Unmarshal(raw, &object)
+
+	if object["score"] != 7.5 || object["policy"] != "require-labels" {
+		t.Errorf("expected typed field next to the existing fields, got %s", raw)
+	}
+}
+
+func Test_Use(t *testing.T) {
+	if err := fields.Use([]fields.Mapping{{Property: "cvss", Field: "score", Type: fields.Float}}); err != nil {
+		t.Fatal(err)
+	}
+	defer fields.Use(nil)
+
+	if fields.Map(map[string]string{"cvss": "9.8"})["score"] != 9.8 {
+		t.Error("expected the configured mapping")
+	}
+	if err := fields.Use([]fields.Mapping{{Property: "cvss", Field: "id"}}); err == nil {
+		t.Error("expected error for reserved field")
+	}
+}
@@ -0,0 +1,131 @@
+// Package gitops resolves the ArgoCD Application or Flux Kustomization / HelmRelease managing the resource of a result
+// from the tracking labels and annotations the tools add to each applied resource
+package gitops
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+const (
+	ArgoCD = "argocd"
+	Flux   = "flux"
+
+	argoTrackingAnnotation = "argocd.argoproj.io/tracking-id"
+	fluxKustomizationName  = "kustomize.toolkit.fluxcd.io/name"
+	fluxKustomizationNS    = "kustomize.toolkit.fluxcd.io/namespace"
+	fluxHelmReleaseName    = "helm.toolkit.fluxcd.io/name"
+	fluxHelmReleaseNS      = "helm.toolkit.fluxcd.io/namespace"
+)
+
+// App managing a resource
+type App struct {
+	Tool      string
+	Kind      string
+	Name      string
+	Namespace string
+}
+
+// Resolver of the GitOps App of a resource
+type Resolver struct {
+	metadata report.ResourceMetadata
+	// argoLabel for the label based resource tracking of ArgoCD, disabled if empty
+	argoLabel string
+}
+
+// FromMetadata reads the App from the tracking labels and annotations, false if the resource is not managed by a known tool
+func (r *Resolver) FromMetadata(labels, annotations map[string]string) (App, bool) {
+	if id := annotations[argoTrackingAnnotation]; id != "" {
+		// <app>:<group>/<kind>:<namespace>/<name>, the app is prefixed with its namespace if apps in any namespace are used
+		app := strings.SplitN(id, ":", 2)[0]
+		if ns, name, ok := strings.Cut(app, "_"); ok {
+			return App{Tool: ArgoCD, Kind: "Application", Name: name, Namespace: ns}, true
+		}
+
+		return App{Tool: ArgoCD, Kind: "Application", Name: app}, true
+	}
+
+	if name := labels[fluxKustomizationName]; name != "" {
+		return App{Tool: Flux, Kind: "Kustomization", Name: name, Namespace: labels[fluxKustomizationNS]}, true
+	}
+
+	if name := labels[fluxHelmReleaseName]; name != "" {
+		return App{Tool: Flux, Kind: "HelmRelease", Name: name, Namespace: labels[fluxHelmReleaseNS]}, true
+	}
+
+	if r.argoLabel != "" {
+		if name := labels[r.argoLabel]; name != "" {
+			return App{Tool: ArgoCD, Kind: "Application", Name: name}, true
+		}
+	}
+
+	return App{}, false
+}
+
+// Resolve the App of the resource
+func (r *Resolver) Resolve(resource *corev1.ObjectReference) (App, bool) {
+	if r.metadata == nil || resource == nil {
+		return App{}, false
+	}
+
+	meta, ok := r.metadata.Get(resource)
+	if !ok {
+		return App{}, false
+	}
+
+	return r.FromMetadata(meta.GetLabels(), meta.GetAnnotations())
+}
+
+// Map adds the App of the result resource as properties, falls back to the owner properties of the result
+// because Pods created by a controller have no tracking labels
+func (r *Resolver) Map(rep v1alpha2.ReportInterface) {
+	results := rep.GetResults()
+
+	for i := range results {
+		if !results[i].HasResource() || results[i].Properties[v1alpha2.GitOpsAppKey] != "" {
+			continue
+		}
+
+		app, ok := r.Resolve(results[i].GetResource())
+		if !ok {
+			app, ok = r.Resolve(owner(results[i]))
+		}
+		if !ok {
+			continue
+		}
+
+		if results[i].Properties == nil {
+			results[i].Properties = make(map[string]string)
+		}
+
+		results[i].Properties[v1alpha2.GitOpsToolKey] = app.Tool
+		results[i].Properties[v1alpha2.GitOpsKindKey] = app.Kind
+		results[i].Properties[v1alpha2.GitOpsAppKey] = app.Name
+		if app.Namespace != "" {
+			results[i].Properties[v1alpha2.GitOpsNamespaceKey] = app.Namespace
+		}
+	}
+}
+
+func owner(result v1alpha2.PolicyReportResult) *corev1.ObjectReference {
+	name := result.Properties[v1alpha2.OwnerNameKey]
+	if name == "" {
+		return nil
+	}
+
+	return &corev1.ObjectReference{
+		APIVersion: result.Properties[v1alpha2.OwnerAPIVersionKey],
+		Kind:       result.Properties[v1alpha2.OwnerKindKey],
+		Name:       name,
+		Namespace:  result.GetResource().Namespace,
+	}
+}
+
+// NewResolver creates a Resolver, argoLabel enables the label based resource tracking of ArgoCD, e.g. app.kubernetes.io/instance
+func NewResolver(metadata report.ResourceMetadata, argoLabel string) *Resolver {
+	return &Resolver{metadata: metadata, argoLabel: argoLabel}
+}
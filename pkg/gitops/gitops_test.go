@@ -0,0 +1,104 @@
+package gitops_test
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/gitops"
+)
+
+type metadata map[string]*metav1.PartialObjectMetadata
+
+func (m metadata) Get(resource *corev1.ObjectReference) (*metav1.PartialObjectMetadata, bool) {
+	meta, ok := m[resource.Kind+"/"+resource.Name]
+
+	return meta, ok
+}
+
+func Test_FromMetadata(t *testing.T) {
+	resolver := gitops.NewResolver(nil, "app.kubernetes.io/instance")
+
+	cases := []struct {
+		name        string
+		labels      map[string]string
+		annotations map[string]string
+		expected    gitops.App
+	}{
+		{
+			name:        "ArgoCD annotation",
+			annotations: map[string]string{"argocd.argoproj.io/tracking-id": "shop:apps/Deployment:shop/nginx"},
+			expected:    gitops.App{Tool: gitops.ArgoCD, Kind: "Application", Name: "shop"},
+		},
+		{
+			name:        "ArgoCD app in any namespace",
+			annotations: map[string]string{"argocd.argoproj.io/tracking-id": "team-a_shop:apps/Deployment:shop/nginx"},
+			expected:    gitops.App{Tool: gitops.ArgoCD, Kind: "Application", Name: "shop", Namespace: "team-a"},
+		},
+		{
+			name:     "ArgoCD label",
+			labels:   map[string]string{"app.kubernetes.io/instance": "shop"},
+			expected: gitops.App{Tool: gitops.ArgoCD, Kind: "Application", Name: "shop"},
+		},
+		{
+			name:     "Flux Kustomization",
+			labels:   map[string]string{"kustomize.toolkit.fluxcd.io/name": "apps", "kustomize.toolkit.fluxcd.io/namespace": "flux-system"},
+			expected: gitops.App{Tool: gitops.Flux, Kind: "Kustomization", Name: "apps", Namespace: "flux-system"},
+		},
+		{
+			name:     "Flux HelmRelease",
+			labels:   map[string]string{"helm.toolkit.fluxcd.io/name": "nginx", "helm.toolkit.fluxcd.io/namespace": "web"},
+			expected: gitops.App{Tool: gitops.Flux, Kind: "HelmRelease", Name: "nginx", Namespace: "web"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			app, ok := resolver.FromMetadata(c.labels, c.annotations)
+			if !ok {
+				t.Fatal("expected app")
+			}
+			if app != c.expected {
+				t.Errorf("expected %+v, got %+v", c.expected, app)
+			}
+		})
+	}
+
+	if _, ok := gitops.NewResolver(nil, "").FromMetadata(map[string]string{"app.kubernetes.io/instance": "shop"}, nil); ok {
+		t.Error("expected label tracking to be disabled without label")
+	}
+}
+
+func Test_Map(t *testing.T) {
+	cache := metadata{
+		"Deployment/nginx": {ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"kustomize.toolkit.fluxcd.io/name": "apps", "kustomize.toolkit.fluxcd.io/namespace": "flux-system"}}},
+		"Pod/nginx-abcde":  {},
+	}
+
+	rep := &v1alpha2.PolicyReport{
+		Results: []v1alpha2.PolicyReportResult{
+			{Policy: "require-labels", Resources: []corev1.ObjectReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "nginx", Namespace: "test"}}},
+			{
+				Policy:     "require-labels",
+				Resources:  []corev1.ObjectReference{{APIVersion: "v1", Kind: "Pod", Name: "nginx-abcde", Namespace: "test"}},
+				Properties: map[string]string{v1alpha2.OwnerAPIVersionKey: "apps/v1", v1alpha2.OwnerKindKey: "Deployment", v1alpha2.OwnerNameKey: "nginx"},
+			},
+			{Policy: "require-labels", Resources: []corev1.ObjectReference{{APIVersion: "v1", Kind: "Pod", Name: "standalone", Namespace: "test"}}},
+		},
+	}
+
+	gitops.NewResolver(cache, "").Map(rep)
+
+	for i := 0; i < 2; i++ {
+		props := rep.Results[i].Properties
+		if props[v1alpha2.GitOpsToolKey] != gitops.Flux || props[v1alpha2.GitOpsKindKey] != "Kustomization" || props[v1alpha2.GitOpsAppKey] != "apps" || props[v1alpha2.GitOpsNamespaceKey] != "flux-system" {
+			t.Errorf("unexpected gitops properties of result %d: %v", i, props)
+		}
+	}
+
+	if len(rep.Results[2].Properties) > 0 {
+		t.Errorf("expected no gitops properties, got %v", rep.Results[2].Properties)
+	}
+}
@@ -0,0 +1,150 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Delivery is the last delivery result of a target
+type Delivery struct {
+	LastSuccess *time.Time `json:"lastSuccess,omitempty"`
+	LastError   *time.Time `json:"lastError,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	// ConsecutiveFailures since the last successful delivery
+	ConsecutiveFailures int `json:"consecutiveFailures,omitempty"`
+	// FailingSince is the time of the first failure after the last successful delivery
+	FailingSince *time.Time `json:"failingSince,omitempty"`
+}
+
+// Deliveries records the last delivery success and error per target
+type Deliveries struct {
+	mx      *sync.RWMutex
+	targets map[string]Delivery
+	codes   map[string]map[string]uint64
+}
+
+// Record the delivery result of a target, a nil error is recorded as success
+func (d *Deliveries) Record(target string, err error) {
+	d.RecordStatus(target, 0, err)
+}
+
+// RecordStatus records the delivery result of a target with the HTTP status code of the response,
+// deliveries without status code are counted as "success" or "error"
+func (d *Deliveries) RecordStatus(target string, statusCode int, err error) {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	now := time.Now()
+	delivery := d.targets[target]
+
+	if err == nil {
+		delivery.LastSuccess = &now
+		delivery.ConsecutiveFailures = 0
+		delivery.FailingSince = nil
+	} else {
+		delivery.LastError = &now
+		delivery.Error = err.Error()
+		delivery.ConsecutiveFailures++
+		if delivery.FailingSince == nil {
+			delivery.FailingSince = &now
+		}
+	}
+
+	d.targets[target] = delivery
+
+	code := strconv.Itoa(statusCode)
+	if statusCode == 0 && err == nil {
+		code = "success"
+	} else if statusCode == 0 {
+		code = "error"
+	}
+
+	if d.codes[target] == nil {
+		d.codes[target] = make(map[string]uint64)
+	}
+	d.codes[target][code]++
+}
+
+// List the recorded deliveries per target
+func (d *Deliveries) List() map[string]Delivery {
+	d.mx.RLock()
+	defer d.mx.RUnlock()
+
+	list := make(map[string]Delivery, len(d.targets))
+	for name, delivery := range d.targets {
+		list[name] = delivery
+	}
+
+	return list
+}
+
+// StatusCodes counts the deliveries per target and status code
+func (d *Deliveries) StatusCodes() map[string]map[string]uint64 {
+	d.mx.RLock()
+	defer d.mx.RUnlock()
+
+	list := make(map[string]map[string]uint64, len(d.codes))
+	for name, codes := range d.codes {
+		list[name] = make(map[string]uint64, len(codes))
+		for code, count := range codes {
+			list[name][code] = count
+		}
+	}
+
+	return list
+}
+
+// Check is degraded if the last delivery of any target failed
+func (d *Deliveries) Check(_ context.Context) Component {
+	list := d.List()
+
+	for _, delivery := range list {
+		if delivery.LastError != nil && (delivery.LastSuccess == nil || delivery.LastError.After(*delivery.LastSuccess)) {
+			return Component{Status: Degraded, Message: "last delivery failed", Details: list}
+		}
+	}
+
+	return Component{Status: OK, Details: list}
+}
+
+// Failing checks if any of the targets fails for longer than the threshold
+func (d *Deliveries) Failing(targets []string, threshold time.Duration) Check {
+	return func(_ context.Context) Component {
+		list := d.List()
+		failing := make([]string, 0)
+		details := make(map[string]Delivery, len(targets))
+
+		for _, target := range targets {
+			delivery, ok := list[target]
+			if !ok {
+				continue
+			}
+
+			details[target] = delivery
+			if delivery.FailingSince != nil && time.Since(*delivery.FailingSince) > threshold {
+				failing = append(failing, target)
+			}
+		}
+
+		if len(failing) > 0 {
+			sort.Strings(failing)
+
+			return Component{Status: Unavailable, Message: fmt.Sprintf("failing for more than %s: %s", threshold, strings.Join(failing, ", ")), Details: details}
+		}
+
+		return Component{Status: OK, Details: details}
+	}
+}
+
+// NewDeliveries creates an empty delivery recorder
+func NewDeliveries() *Deliveries {
+	return &Deliveries{mx: new(sync.RWMutex), targets: make(map[string]Delivery), codes: make(map[string]map[string]uint64)}
+}
+
+// TargetDeliveries are recorded by all targets
+var TargetDeliveries = NewDeliveries()
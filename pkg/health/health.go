@@ -0,0 +1,116 @@
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Status of a component or of the whole application
+type Status string
+
+const (
+	OK          Status = "ok"
+	Degraded    Status = "degraded"
+	Unavailable Status = "unavailable"
+)
+
+// Component status with an optional error message and component specific details
+type Component struct {
+	Status  Status      `json:"status"`
+	Message string      `json:"message,omitempty"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// Check returns the current status of a component
+type Check = func(ctx context.Context) Component
+
+// Report of all checked components, the status is unavailable if a critical component is not ok
+// and degraded if any other component is not ok
+type Report struct {
+	Status     Status               `json:"status"`
+	Components map[string]Component `json:"components"`
+}
+
+type check struct {
+	name     string
+	critical bool
+	check    Check
+}
+
+// Checker runs the registered component checks
+type Checker struct {
+	mx     *sync.RWMutex
+	checks []check
+}
+
+// Register a component check, critical components make the application unavailable if they are not ok
+func (c *Checker) Register(name string, critical bool, fn Check) *Checker {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.checks = append(c.checks, check{name: name, critical: critical, check: fn})
+
+	sort.SliceStable(c.checks, func(i, j int) bool {
+		return c.checks[i].name < c.checks[j].name
+	})
+
+	return c
+}
+
+// Check all registered components
+func (c *Checker) Check(ctx context.Context) Report {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	report := Report{Status: OK, Components: make(map[string]Component, len(c.checks))}
+
+	for _, ch := range c.checks {
+		component := ch.check(ctx)
+		report.Components[ch.name] = component
+
+		if component.Status == OK {
+			continue
+		}
+
+		if ch.critical {
+			report.Status = Unavailable
+		} else if report.Status == OK {
+			report.Status = Degraded
+		}
+	}
+
+	return report
+}
+
+// NewChecker creates an empty Checker, a Report without components is ok
+func NewChecker() *Checker {
+	return &Checker{mx: new(sync.RWMutex)}
+}
+
+// Synced is unavailable until the informers are synced
+func Synced(synced func() bool) Check {
+	return func(_ context.Context) Component {
+		if !synced() {
+			return Component{Status: Unavailable, Message: "Informers not in sync"}
+		}
+
+		return Component{Status: OK}
+	}
+}
+
+// Pinger is implemented by sql.DB
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// Ping is unavailable if the connection check fails
+func Ping(pinger Pinger) Check {
+	return func(ctx context.Context) Component {
+		if err := pinger.PingContext(ctx); err != nil {
+			return Component{Status: Unavailable, Message: err.Error()}
+		}
+
+		return Component{Status: OK}
+	}
+}
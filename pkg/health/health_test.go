@@ -0,0 +1,98 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/health"
+)
+
+type pinger struct {
+	err error
+}
+
+func (p pinger) PingContext(_ context.Context) error {
+	return p.err
+}
+
+func Test_Checker(t *testing.T) {
+	t.Run("Empty Checker", func(t *testing.T) {
+		if report := health.NewChecker().Check(context.Background()); report.Status != health.OK {
+			t.Errorf("Expected ok status, got %s", report.Status)
+		}
+	})
+	t.Run("Unavailable critical Component", func(t *testing.T) {
+		report := health.NewChecker().
+			Register("informers", true, health.Synced(func() bool { return true })).
+			Register("database", true, health.Ping(pinger{errors.New("connection refused")})).
+			Check(context.Background())
+
+		if report.Status != health.Unavailable {
+			t.Errorf("Expected unavailable status, got %s", report.Status)
+		}
+		if report.Components["database"].Message != "connection refused" {
+			t.Errorf("Expected database error message, got %s", report.Components["database"].Message)
+		}
+	})
+	t.Run("Degraded Component", func(t *testing.T) {
+		deliveries := health.NewDeliveries()
+		deliveries.Record("Slack", errors.New("status code 500"))
+
+		report := health.NewChecker().
+			Register("database", true, health.Ping(pinger{})).
+			Register("targets", false, deliveries.Check).
+			Check(context.Background())
+
+		if report.Status != health.Degraded {
+			t.Errorf("Expected degraded status, got %s", report.Status)
+		}
+	})
+}
+
+func Test_Deliveries(t *testing.T) {
+	deliveries := health.NewDeliveries()
+	deliveries.Record("Slack", errors.New("status code 500"))
+	deliveries.Record("Slack", nil)
+
+	if status := deliveries.Check(context.Background()).Status; status != health.OK {
+		t.Errorf("Expected recovered target to be ok, got %s", status)
+	}
+
+	delivery := deliveries.List()["Slack"]
+	if delivery.LastError == nil || delivery.LastSuccess == nil || delivery.Error != "status code 500" {
+		t.Errorf("Unexpected delivery: %+v", delivery)
+	}
+}
+
+func Test_DeliveryFailures(t *testing.T) {
+	deliveries := health.NewDeliveries()
+	deliveries.RecordStatus("Slack", 500, errors.New("status code 500"))
+	deliveries.RecordStatus("Slack", 503, errors.New("status code 503"))
+	deliveries.RecordStatus("Loki", 204, nil)
+	deliveries.Record("Kinesis", nil)
+
+	if failures := deliveries.List()["Slack"].ConsecutiveFailures; failures != 2 {
+		t.Errorf("Expected 2 consecutive failures, got %d", failures)
+	}
+
+	codes := deliveries.StatusCodes()
+	if codes["Slack"]["500"] != 1 || codes["Loki"]["204"] != 1 || codes["Kinesis"]["success"] != 1 {
+		t.Errorf("Unexpected status codes: %v", codes)
+	}
+
+	if status := deliveries.Failing([]string{"Slack"}, time.Hour)(context.Background()).Status; status != health.OK {
+		t.Errorf("Expected target failing within the threshold to be ok, got %s", status)
+	}
+
+	component := deliveries.Failing([]string{"Slack", "Loki", "Teams"}, 0)(context.Background())
+	if component.Status != health.Unavailable || component.Message != "failing for more than 0s: Slack" {
+		t.Errorf("Expected failing critical target, got %+v", component)
+	}
+
+	deliveries.Record("Slack", nil)
+	if delivery := deliveries.List()["Slack"]; delivery.ConsecutiveFailures != 0 || delivery.FailingSince != nil {
+		t.Errorf("Expected recovered target to reset the failures, got %+v", delivery)
+	}
+}
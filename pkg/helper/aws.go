@@ -3,40 +3,80 @@ package helper
 import (
 	"bytes"
 	"io"
-	"log"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/firehose"
 	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/securityhub"
+
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/secret"
 )
 
+var logger = logging.Component("helper")
+
 type AWSClient interface {
 	// Upload given Data the configured AWS storage
 	Upload(body *bytes.Buffer, key string) error
 }
 
+type dryRunClient struct {
+	target string
+}
+
+func (c *dryRunClient) Upload(body *bytes.Buffer, key string) error {
+	logger.Infof("%s DRY RUN : upload %s payload: %s", c.target, key, bytes.TrimSpace(body.Bytes()))
+
+	return nil
+}
+
+// NewDryRunClient creates an AWSClient which logs the uploads of the target instead of sending them
+func NewDryRunClient(target string) AWSClient {
+	return &dryRunClient{target: target}
+}
+
 type s3Client struct {
-	bucket   string
-	uploader *s3manager.Uploader
+	bucket     string
+	uploader   *s3manager.Uploader
+	encryption S3Encryption
 }
 
 func (s *s3Client) Upload(body *bytes.Buffer, key string) error {
-	_, err := s.uploader.Upload(&s3manager.UploadInput{
+	input := &s3manager.UploadInput{
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(key),
 		Body:   body,
-	})
+	}
+	if s.encryption.Algorithm != "" {
+		input.ServerSideEncryption = aws.String(s.encryption.Algorithm)
+	}
+	if s.encryption.KMSKeyID != "" {
+		input.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAwsKms)
+		input.SSEKMSKeyId = aws.String(s.encryption.KMSKeyID)
+	}
+
+	_, err := s.uploader.Upload(input)
 	return err
 }
 
+// S3Encryption configures the server-side encryption of the uploaded objects, empty uses the default of the bucket
+type S3Encryption struct {
+	// Algorithm AES256 or aws:kms
+	Algorithm string
+	// KMSKeyID of the aws:kms encryption, empty uses the AWS managed key. Implies aws:kms
+	KMSKeyID string
+}
+
 // NewS3Client creates a new S3.client to send Results to S3
-func NewS3Client(accessKeyID, secretAccessKey, region, endpoint, bucket string, pathStyle bool) AWSClient {
+func NewS3Client(accessKeyID string, secretAccessKey secret.String, region, endpoint, bucket string, pathStyle bool, encryption S3Encryption) AWSClient {
 	config := &aws.Config{
 		Region:      aws.String(region),
 		Endpoint:    aws.String(endpoint),
-		Credentials: credentials.NewStaticCredentials(accessKeyID, secretAccessKey, ""),
+		Credentials: credentials.NewStaticCredentials(accessKeyID, secretAccessKey.Value(), ""),
 	}
 	if pathStyle {
 		config.S3ForcePathStyle = &pathStyle
@@ -44,13 +84,14 @@ func NewS3Client(accessKeyID, secretAccessKey, region, endpoint, bucket string,
 
 	sess, err := session.NewSession(config)
 	if err != nil {
-		log.Printf("[ERROR]: %v\n", "Error while creating S3 Session")
+		logger.Errorf("%v", "Error while creating S3 Session")
 		return nil
 	}
 
 	return &s3Client{
 		bucket,
 		s3manager.NewUploader(sess),
+		encryption,
 	}
 }
 
@@ -74,14 +115,14 @@ func (k *kinesisClient) Upload(body *bytes.Buffer, key string) error {
 }
 
 // NewKinesisClient creates a new S3.client to send Results to S3
-func NewKinesisClient(accessKeyID, secretAccessKey, region, endpoint, streamName string) AWSClient {
+func NewKinesisClient(accessKeyID string, secretAccessKey secret.String, region, endpoint, streamName string) AWSClient {
 	sess, err := session.NewSession(&aws.Config{
 		Region:      aws.String(region),
 		Endpoint:    aws.String(endpoint),
-		Credentials: credentials.NewStaticCredentials(accessKeyID, secretAccessKey, ""),
+		Credentials: credentials.NewStaticCredentials(accessKeyID, secretAccessKey.Value(), ""),
 	})
 	if err != nil {
-		log.Printf("[ERROR]: %v\n", "Error while creating S3 Session")
+		logger.Errorf("%v", "Error while creating S3 Session")
 		return nil
 	}
 
@@ -90,3 +131,53 @@ func NewKinesisClient(accessKeyID, secretAccessKey, region, endpoint, streamName
 		kinesis.New(sess),
 	}
 }
+
+type firehoseClient struct {
+	deliveryStream string
+	firehose       *firehose.Firehose
+}
+
+func (f *firehoseClient) Upload(body *bytes.Buffer, _ string) error {
+	_, err := f.firehose.PutRecord(&firehose.PutRecordInput{
+		DeliveryStreamName: aws.String(f.deliveryStream),
+		Record:             &firehose.Record{Data: body.Bytes()},
+	})
+	return err
+}
+
+// NewFirehoseClient creates a new client to send Results to an AWS Firehose delivery stream
+func NewFirehoseClient(accessKeyID string, secretAccessKey secret.String, region, endpoint, deliveryStream string) AWSClient {
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String(region),
+		Endpoint:    aws.String(endpoint),
+		Credentials: credentials.NewStaticCredentials(accessKeyID, secretAccessKey.Value(), ""),
+	})
+	if err != nil {
+		logger.Error("Error while creating Firehose Session")
+		return nil
+	}
+
+	return &firehoseClient{
+		deliveryStream,
+		firehose.New(sess),
+	}
+}
+
+// NewSecurityHubClient creates a new Security Hub client, the default credential chain is used without access keys
+func NewSecurityHubClient(accessKeyID string, secretAccessKey secret.String, region, endpoint string) *securityhub.SecurityHub {
+	config := &aws.Config{Region: aws.String(region)}
+	if endpoint != "" {
+		config.Endpoint = aws.String(endpoint)
+	}
+	if accessKeyID != "" && secretAccessKey != "" {
+		config.Credentials = credentials.NewStaticCredentials(accessKeyID, secretAccessKey.Value(), "")
+	}
+
+	sess, err := session.NewSession(config)
+	if err != nil {
+		logger.Error("Error while creating Security Hub Session")
+		return nil
+	}
+
+	return securityhub.New(sess)
+}
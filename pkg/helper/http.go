@@ -4,10 +4,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"html"
-	"log"
 	"net/http"
 )
 
+// SendBadRequest responds with 400 and the error message
+func SendBadRequest(w http.ResponseWriter, err error) {
+	SendError(w, http.StatusBadRequest, err)
+}
+
+// SendError responds with the given status code and the error message
+func SendError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `{ "message": "%s" }`, html.EscapeString(err.Error()))
+}
+
 func SendJSONResponse(w http.ResponseWriter, list interface{}, err error) {
 	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
 	if err != nil {
@@ -18,7 +29,7 @@ func SendJSONResponse(w http.ResponseWriter, list interface{}, err error) {
 	}
 
 	if err := json.NewEncoder(w).Encode(list); err != nil {
-		log.Println(err)
+		logger.Error(err)
 		w.WriteHeader(http.StatusInternalServerError)
 		fmt.Fprintf(w, `{ "message": "%s" }`, html.EscapeString(err.Error()))
 	}
@@ -0,0 +1,31 @@
+package helper
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+)
+
+// NewTLSConfig creates a client TLS configuration with an optional custom CA certificate
+func NewTLSConfig(certificatePath string, skipTLS bool) *tls.Config {
+	config := &tls.Config{
+		InsecureSkipVerify: skipTLS,
+	}
+
+	if certificatePath == "" {
+		return config
+	}
+
+	caCert, err := os.ReadFile(certificatePath)
+	if err != nil {
+		logger.Errorf("failed to read certificate: %s", certificatePath)
+		return config
+	}
+
+	caCertPool := x509.NewCertPool()
+	caCertPool.AppendCertsFromPEM(caCert)
+
+	config.RootCAs = caCertPool
+
+	return config
+}
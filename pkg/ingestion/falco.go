@@ -0,0 +1,204 @@
+package ingestion
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/segmentio/fasthash/fnv1a"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// FalcoSource of the results of ingested Falco events
+const FalcoSource = "falco"
+
+// Defaults of the retained Falco events
+const (
+	DefaultFalcoRetention  = 24 * time.Hour
+	DefaultFalcoMaxResults = 1000
+)
+
+// FalcoEvent as sent by the Falco http output and the Falcosidekick webhook output
+type FalcoEvent struct {
+	UUID         string                 `json:"uuid,omitempty"`
+	Output       string                 `json:"output"`
+	Priority     string                 `json:"priority"`
+	Rule         string                 `json:"rule"`
+	Time         time.Time              `json:"time"`
+	OutputFields map[string]interface{} `json:"output_fields"`
+	Source       string                 `json:"source,omitempty"`
+	Tags         []string               `json:"tags,omitempty"`
+	Hostname     string                 `json:"hostname,omitempty"`
+}
+
+// FalcoOptions configure the retention of the ingested Falco events, each event is kept as result
+// of the report of its namespace until it expires or the report exceeds MaxResults
+type FalcoOptions struct {
+	Retention  time.Duration
+	MaxResults int
+}
+
+// falcoOutputPrefix is the time and priority prefix of the default Falco output
+var falcoOutputPrefix = regexp.MustCompile(`^\d{2}:\d{2}:\d{2}\.\d+: \w+ `)
+
+// FromFalco maps the Falco event to a request of the namespace of the pod. The result ID is derived from the
+// rule and the pod or host, so repeated events of the same rule and pod update a single result
+func FromFalco(event FalcoEvent) (Request, error) {
+	if event.Rule == "" {
+		return Request{}, errors.New("missing rule")
+	}
+
+	fields := make(map[string]string, len(event.OutputFields))
+	for key, value := range event.OutputFields {
+		if value == nil {
+			continue
+		}
+		fields[key] = fmt.Sprint(value)
+	}
+
+	result := v1alpha2.PolicyReportResult{
+		Source:     FalcoSource,
+		Policy:     event.Rule,
+		Message:    falcoOutputPrefix.ReplaceAllString(event.Output, ""),
+		Result:     falcoStatus(event.Priority),
+		Severity:   falcoSeverity(event.Priority),
+		Properties: fields,
+	}
+	if !event.Time.IsZero() {
+		result.Timestamp = v1.Timestamp{Seconds: event.Time.Unix(), Nanos: int32(event.Time.Nanosecond())}
+	}
+	if len(event.Tags) > 0 {
+		result.Category = event.Tags[0]
+		fields["tags"] = strings.Join(event.Tags, ",")
+	}
+	if event.Hostname != "" {
+		fields["hostname"] = event.Hostname
+	}
+	if event.Source != "" {
+		fields["eventSource"] = event.Source
+	}
+	fields["priority"] = event.Priority
+
+	namespace := fields["k8s.ns.name"]
+	subject := event.Hostname
+	if pod := fields["k8s.pod.name"]; pod != "" && namespace != "" {
+		result.Resources = []corev1.ObjectReference{{APIVersion: "v1", Kind: "Pod", Name: pod, Namespace: namespace}}
+		subject = namespace + "/" + pod
+	}
+
+	h := fnv1a.HashString64(FalcoSource)
+	h = fnv1a.AddString64(h, event.Rule)
+	h = fnv1a.AddString64(h, subject)
+	result.ID = strconv.FormatUint(h, 10)
+
+	return Request{Source: FalcoSource, Namespace: namespace, Results: []v1alpha2.PolicyReportResult{result}}, nil
+}
+
+// falcoStatus maps the priority, events of priority Warning and above are failures
+func falcoStatus(priority string) v1alpha2.PolicyResult {
+	switch strings.ToLower(priority) {
+	case "emergency", "alert", "critical", "error", "warning":
+		return v1alpha2.StatusFail
+	default:
+		return v1alpha2.StatusWarn
+	}
+}
+
+func falcoSeverity(priority string) v1alpha2.PolicySeverity {
+	switch strings.ToLower(priority) {
+	case "emergency", "alert", "critical":
+		return v1alpha2.SeverityCritical
+	case "error":
+		return v1alpha2.SeverityHigh
+	case "warning":
+		return v1alpha2.SeverityMedium
+	case "notice":
+		return v1alpha2.SeverityLow
+	default:
+		return v1alpha2.SeverityInfo
+	}
+}
+
+// falcoEvents retains the results of the Falco events per namespace
+type falcoEvents struct {
+	options FalcoOptions
+	mx      *sync.Mutex
+	results map[string]map[string]v1alpha2.PolicyReportResult
+}
+
+// add the results of the request and returns all retained results of its namespace, sorted by time
+func (f *falcoEvents) add(req Request) []v1alpha2.PolicyReportResult {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	retained, ok := f.results[req.Namespace]
+	if !ok {
+		retained = make(map[string]v1alpha2.PolicyReportResult)
+		f.results[req.Namespace] = retained
+	}
+
+	for _, r := range req.Results {
+		if r.Timestamp.Seconds == 0 {
+			r.Timestamp = v1.Timestamp{Seconds: time.Now().Unix()}
+		}
+		retained[r.GetID()] = r
+	}
+
+	expired := time.Now().Add(-f.options.Retention).Unix()
+
+	results := make([]v1alpha2.PolicyReportResult, 0, len(retained))
+	for id, r := range retained {
+		if r.Timestamp.Seconds < expired {
+			delete(retained, id)
+			continue
+		}
+		results = append(results, r)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Timestamp.Seconds == results[j].Timestamp.Seconds {
+			return results[i].Timestamp.Nanos > results[j].Timestamp.Nanos
+		}
+		return results[i].Timestamp.Seconds > results[j].Timestamp.Seconds
+	})
+
+	if len(results) > f.options.MaxResults {
+		for _, r := range results[f.options.MaxResults:] {
+			delete(retained, r.GetID())
+		}
+		results = results[:f.options.MaxResults]
+	}
+
+	return results
+}
+
+func newFalcoEvents(options FalcoOptions) *falcoEvents {
+	if options.Retention <= 0 {
+		options.Retention = DefaultFalcoRetention
+	}
+	if options.MaxResults <= 0 {
+		options.MaxResults = DefaultFalcoMaxResults
+	}
+
+	return &falcoEvents{options: options, mx: new(sync.Mutex), results: make(map[string]map[string]v1alpha2.PolicyReportResult)}
+}
+
+// IngestFalco publishes the Falco event together with the retained events of its namespace as PolicyReport
+func (i *Ingester) IngestFalco(event FalcoEvent) (Response, error) {
+	req, err := FromFalco(event)
+	if err != nil {
+		return Response{}, err
+	}
+
+	req.Results = i.falco.add(req)
+
+	return i.Ingest(req)
+}
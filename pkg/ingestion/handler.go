@@ -0,0 +1,130 @@
+package ingestion
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/kyverno/policy-reporter/pkg/helper"
+	"github.com/kyverno/policy-reporter/pkg/sarif"
+)
+
+const (
+	// ResultsPath accepts results in the PolicyReportResult schema
+	ResultsPath = "/v2/results"
+	// SARIFPath accepts SARIF logs, the report namespace and name are query parameters
+	SARIFPath = "/v2/results/sarif"
+	// FalcoPath accepts the events of the Falco http output and the Falcosidekick webhook output
+	FalcoPath = "/v2/results/falco"
+	// NDJSONPath accepts NDJSON streams with a result per line, the source, namespace and report are query parameters
+	NDJSONPath = "/v2/results/ndjson"
+)
+
+// maxRequestSize limits the request body of ingested results
+const maxRequestSize = 32 << 20
+
+// Handler of the requests sent to ResultsPath
+func (i *Ingester) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if !allowPost(w, req) {
+			return
+		}
+
+		request := Request{}
+		if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, maxRequestSize)).Decode(&request); err != nil {
+			helper.SendBadRequest(w, err)
+			return
+		}
+
+		response, err := i.Ingest(request)
+		if err != nil {
+			helper.SendBadRequest(w, err)
+			return
+		}
+
+		sendAccepted(w, response)
+	}
+}
+
+// SARIFHandler of the SARIF logs sent to SARIFPath, each run is ingested as its own report
+func (i *Ingester) SARIFHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if !allowPost(w, req) {
+			return
+		}
+
+		log := sarif.Log{}
+		if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, maxRequestSize)).Decode(&log); err != nil {
+			helper.SendBadRequest(w, err)
+			return
+		}
+
+		query := req.URL.Query()
+
+		requests, err := FromSARIF(log, query.Get("namespace"), query.Get("report"))
+		if err != nil {
+			helper.SendBadRequest(w, err)
+			return
+		}
+
+		responses := make([]Response, 0, len(requests))
+		for _, r := range requests {
+			if err := validate(r); err != nil {
+				helper.SendBadRequest(w, err)
+				return
+			}
+		}
+		for _, r := range requests {
+			response, err := i.Ingest(r)
+			if err != nil {
+				helper.SendBadRequest(w, err)
+				return
+			}
+
+			responses = append(responses, response)
+		}
+
+		sendAccepted(w, responses)
+	}
+}
+
+// FalcoHandler of the Falco events sent to FalcoPath
+func (i *Ingester) FalcoHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if !allowPost(w, req) {
+			return
+		}
+
+		event := FalcoEvent{}
+		if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, maxRequestSize)).Decode(&event); err != nil {
+			helper.SendBadRequest(w, err)
+			return
+		}
+
+		response, err := i.IngestFalco(event)
+		if err != nil {
+			helper.SendBadRequest(w, err)
+			return
+		}
+
+		sendAccepted(w, response)
+	}
+}
+
+func allowPost(w http.ResponseWriter, req *http.Request) bool {
+	if req.Method == http.MethodPost {
+		return true
+	}
+
+	w.Header().Set("Allow", http.MethodPost)
+	helper.SendError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+
+	return false
+}
+
+func sendAccepted(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusAccepted)
+
+	_ = json.NewEncoder(w).Encode(response)
+}
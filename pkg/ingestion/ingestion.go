@@ -0,0 +1,203 @@
+// Package ingestion receives results of external policy engines which can't write PolicyReports,
+// the results are published as synthetic PolicyReports through the same listeners as the watched CRDs
+package ingestion
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+// IngestedLabel marks the synthetic PolicyReports of ingested results
+const IngestedLabel = "policy-reporter.kyverno.io/ingested"
+
+// Request with the results of a source, the results replace the results of previous requests for the same report,
+// a request without results deletes the report
+type Request struct {
+	Source string `json:"source"`
+	// Namespace of the PolicyReport, a ClusterPolicyReport is used if empty
+	Namespace string `json:"namespace,omitempty"`
+	// Report name, defaults to ingest-<source>
+	Report  string                        `json:"report,omitempty"`
+	Results []v1alpha2.PolicyReportResult `json:"results"`
+}
+
+// Response of an ingestion request
+type Response struct {
+	Report    string `json:"report"`
+	Namespace string `json:"namespace,omitempty"`
+	Results   int    `json:"results"`
+	// Filtered is set if the report is excluded by the report filter and was not processed
+	Filtered bool `json:"filtered,omitempty"`
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// ReportName of the synthetic PolicyReport of a source
+func ReportName(source string) string {
+	name := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(source), "-"), "-.")
+
+	return "ingest-" + name
+}
+
+// Ingester publishes the ingested results as PolicyReport lifecycle events
+type Ingester struct {
+	publisher report.EventPublisher
+	filter    *report.Filter
+	mappers   []func(v1alpha2.ReportInterface)
+	falco     *falcoEvents
+	ndjson    NDJSONOptions
+	streams   chan struct{}
+	// responses of NDJSON streams by idempotency key
+	idempotency *idempotencyCache
+	mx          *sync.Mutex
+	// creation time of the published reports by ID
+	reports map[string]time.Time
+}
+
+// Ingest validates the results of the request and publishes them as a PolicyReport or ClusterPolicyReport
+func (i *Ingester) Ingest(req Request) (Response, error) {
+	if err := validate(req); err != nil {
+		return Response{}, err
+	}
+
+	polr := i.newReport(req)
+
+	response := Response{Report: polr.GetName(), Namespace: polr.GetNamespace(), Results: len(req.Results)}
+
+	if (polr.GetNamespace() == "" && i.filter.DisableClusterReports()) || !i.filter.AllowReport(polr) {
+		response.Results = 0
+		response.Filtered = true
+
+		return response, nil
+	}
+
+	i.mx.Lock()
+	defer i.mx.Unlock()
+
+	created, exists := i.reports[polr.GetID()]
+
+	if len(req.Results) == 0 {
+		if exists {
+			delete(i.reports, polr.GetID())
+			i.publisher.Publish(report.LifecycleEvent{Type: report.Deleted, PolicyReport: polr})
+		}
+
+		return response, nil
+	}
+
+	event := report.Updated
+	if !exists {
+		event = report.Added
+		created = time.Now()
+		i.reports[polr.GetID()] = created
+	}
+
+	polr.SetCreationTimestamp(v1.NewTime(created))
+
+	for _, mapper := range i.mappers {
+		mapper(polr)
+	}
+
+	i.publisher.Publish(report.LifecycleEvent{Type: event, PolicyReport: polr})
+
+	return response, nil
+}
+
+func (i *Ingester) newReport(req Request) v1alpha2.ReportInterface {
+	name := req.Report
+	if name == "" {
+		name = ReportName(req.Source)
+	}
+
+	now := time.Now()
+	results := make([]v1alpha2.PolicyReportResult, 0, len(req.Results))
+
+	for _, r := range req.Results {
+		if r.Source == "" {
+			r.Source = req.Source
+		}
+		if r.Timestamp.Seconds == 0 {
+			r.Timestamp = v1.Timestamp{Seconds: now.Unix()}
+		}
+
+		results = append(results, r)
+	}
+
+	summary := report.Summarize(results)
+
+	meta := v1.ObjectMeta{
+		Name:      name,
+		Namespace: req.Namespace,
+		Labels:    map[string]string{IngestedLabel: "true"},
+	}
+
+	if req.Namespace == "" {
+		return &v1alpha2.ClusterPolicyReport{ObjectMeta: meta, Summary: summary, Results: results}
+	}
+
+	return &v1alpha2.PolicyReport{ObjectMeta: meta, Summary: summary, Results: results}
+}
+
+func validate(req Request) error {
+	if req.Source == "" {
+		return errors.New("missing source")
+	}
+	if req.Report == "" && ReportName(req.Source) == "ingest-" {
+		return fmt.Errorf("invalid source '%s', a report name is required", req.Source)
+	}
+
+	for i, r := range req.Results {
+		if err := validateResult(r); err != nil {
+			return fmt.Errorf("results[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func validateResult(r v1alpha2.PolicyReportResult) error {
+	if r.Policy == "" {
+		return errors.New("missing policy")
+	}
+
+	switch r.Result {
+	case v1alpha2.StatusPass, v1alpha2.StatusFail, v1alpha2.StatusWarn, v1alpha2.StatusError, v1alpha2.StatusSkip:
+		return nil
+	default:
+		return fmt.Errorf("unknown result '%s'", r.Result)
+	}
+}
+
+// NewIngester publishes ingested results allowed by the report filter, the mappers are applied to each report like to watched PolicyReports
+func NewIngester(publisher report.EventPublisher, filter *report.Filter, mappers []func(v1alpha2.ReportInterface), falco FalcoOptions, ndjson NDJSONOptions) *Ingester {
+	if ndjson.MaxStreams <= 0 {
+		ndjson.MaxStreams = 2
+	}
+	if ndjson.MaxResults <= 0 {
+		ndjson.MaxResults = 100000
+	}
+	if ndjson.IdempotencyTTL <= 0 {
+		ndjson.IdempotencyTTL = 24 * time.Hour
+	}
+
+	return &Ingester{
+		publisher:   publisher,
+		filter:      filter,
+		mappers:     mappers,
+		falco:       newFalcoEvents(falco),
+		ndjson:      ndjson,
+		streams:     make(chan struct{}, ndjson.MaxStreams),
+		idempotency: newIdempotencyCache(ndjson.IdempotencyTTL),
+		mx:          new(sync.Mutex),
+		reports:     make(map[string]time.Time),
+	}
+}
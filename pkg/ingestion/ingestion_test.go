@@ -0,0 +1,311 @@
+package ingestion_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/ingestion"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+type events struct {
+	mx   sync.Mutex
+	list []report.LifecycleEvent
+}
+
+func (e *events) listen(event report.LifecycleEvent) {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	e.list = append(e.list, event)
+}
+
+func newIngester(filter *report.Filter) (*ingestion.Ingester, *events) {
+	received := &events{}
+
+	publisher := report.NewEventPublisher()
+	publisher.RegisterListener("test", received.listen)
+
+	return ingestion.NewIngester(publisher, filter, nil, ingestion.FalcoOptions{MaxResults: 2}, ingestion.NDJSONOptions{MaxResults: 3}), received
+}
+
+func Test_Ingest(t *testing.T) {
+	ingester, received := newIngester(report.NewFilter(false, validate.RuleSets{Exclude: []string{"kube-system"}}))
+
+	req := ingestion.Request{
+		Source:    "Scanner",
+		Namespace: "test",
+		Results: []v1alpha2.PolicyReportResult{
+			{Policy: "no-root", Result: v1alpha2.StatusFail, Severity: v1alpha2.SeverityHigh},
+			{Policy: "limits", Result: v1alpha2.StatusPass},
+		},
+	}
+
+	t.Run("Added", func(t *testing.T) {
+		response, err := ingester.Ingest(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if response.Report != "ingest-scanner" || response.Results != 2 {
+			t.Errorf("unexpected response: %+v", response)
+		}
+
+		event := received.list[0]
+		if event.Type != report.Added {
+			t.Errorf("expected added event, got %s", event.Type)
+		}
+		if event.PolicyReport.GetSummary().Fail != 1 || event.PolicyReport.GetSource() != "Scanner" {
+			t.Errorf("unexpected report: %+v", event.PolicyReport)
+		}
+		if event.PolicyReport.GetLabels()[ingestion.IngestedLabel] != "true" {
+			t.Error("expected ingested label")
+		}
+	})
+
+	t.Run("Updated", func(t *testing.T) {
+		if _, err := ingester.Ingest(req); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if received.list[1].Type != report.Updated {
+			t.Errorf("expected updated event, got %s", received.list[1].Type)
+		}
+		if received.list[1].PolicyReport.GetCreationTimestamp() != received.list[0].PolicyReport.GetCreationTimestamp() {
+			t.Error("expected unchanged creation timestamp")
+		}
+	})
+
+	t.Run("Deleted", func(t *testing.T) {
+		if _, err := ingester.Ingest(ingestion.Request{Source: "Scanner", Namespace: "test"}); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if received.list[2].Type != report.Deleted {
+			t.Errorf("expected deleted event, got %s", received.list[2].Type)
+		}
+	})
+
+	t.Run("Filtered", func(t *testing.T) {
+		response, err := ingester.Ingest(ingestion.Request{Source: "Scanner", Namespace: "kube-system", Results: req.Results})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !response.Filtered || len(received.list) != 3 {
+			t.Errorf("expected filtered report, got %+v", response)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		if _, err := ingester.Ingest(ingestion.Request{Source: "Scanner", Results: []v1alpha2.PolicyReportResult{{Policy: "test", Result: "unknown"}}}); err == nil {
+			t.Error("expected error for unknown result")
+		}
+		if _, err := ingester.Ingest(ingestion.Request{Results: req.Results}); err == nil {
+			t.Error("expected error for missing source")
+		}
+	})
+}
+
+func Test_SARIFHandler(t *testing.T) {
+	ingester, received := newIngester(report.NewFilter(false, validate.RuleSets{}))
+
+	body := `{
+		"version": "2.1.0",
+		"runs": [{
+			"tool": {"driver": {"name": "Semgrep", "rules": [{"id": "hardcoded-secret", "name": "Hardcoded Secret", "properties": {"tags": ["security"]}}]}},
+			"results": [
+				{"ruleId": "hardcoded-secret", "level": "error", "message": {"text": "secret found"}, "locations": [{"physicalLocation": {"artifactLocation": {"uri": "main.go"}, "region": {"startLine": 12}}}]},
+				{"ruleId": "hardcoded-secret", "kind": "pass"}
+			]
+		}]
+	}`
+
+	rr := httptest.NewRecorder()
+	ingester.SARIFHandler()(rr, httptest.NewRequest(http.MethodPost, ingestion.SARIFPath+"?namespace=test", strings.NewReader(body)))
+
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("unexpected status code %d: %s", rr.Code, rr.Body.String())
+	}
+
+	responses := []ingestion.Response{}
+	if err := json.NewDecoder(rr.Body).Decode(&responses); err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != 1 || responses[0].Report != "ingest-semgrep" || responses[0].Results != 2 {
+		t.Errorf("unexpected responses: %+v", responses)
+	}
+
+	results := received.list[0].PolicyReport.GetResults()
+	if results[0].Result != v1alpha2.StatusFail || results[0].Severity != v1alpha2.SeverityHigh || results[0].Category != "security" {
+		t.Errorf("unexpected result: %+v", results[0])
+	}
+	if results[0].Properties["location"] != "main.go" || results[0].Properties["line"] != "12" {
+		t.Errorf("unexpected location: %+v", results[0].Properties)
+	}
+	if results[1].Result != v1alpha2.StatusPass {
+		t.Errorf("expected pass result, got %s", results[1].Result)
+	}
+
+	rr = httptest.NewRecorder()
+	ingester.Handler()(rr, httptest.NewRequest(http.MethodGet, ingestion.ResultsPath, nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected method not allowed, got %d", rr.Code)
+	}
+}
+
+func Test_FalcoHandler(t *testing.T) {
+	ingester, received := newIngester(report.NewFilter(false, validate.RuleSets{}))
+
+	send := func(rule, pod string, offset time.Duration) *httptest.ResponseRecorder {
+		event := fmt.Sprintf(`{
+			"output": "16:31:56.746609046: Warning Shell spawned in a container (user=root)",
+			"priority": "Warning",
+			"rule": %q,
+			"time": %q,
+			"output_fields": {"k8s.ns.name": "test", "k8s.pod.name": %q, "proc.pid": 42, "user.name": null},
+			"source": "syscall",
+			"tags": ["shell", "mitre_execution"],
+			"hostname": "node-1"
+		}`, rule, time.Now().Add(offset).UTC().Format(time.RFC3339Nano), pod)
+
+		rr := httptest.NewRecorder()
+		ingester.FalcoHandler()(rr, httptest.NewRequest(http.MethodPost, ingestion.FalcoPath, strings.NewReader(event)))
+
+		return rr
+	}
+
+	if rr := send("Terminal shell in container", "nginx", -time.Minute); rr.Code != http.StatusAccepted {
+		t.Fatalf("unexpected status code %d: %s", rr.Code, rr.Body.String())
+	}
+
+	polr := received.list[0].PolicyReport
+	if polr.GetNamespace() != "test" || polr.GetName() != "ingest-falco" {
+		t.Errorf("unexpected report %s/%s", polr.GetNamespace(), polr.GetName())
+	}
+
+	result := polr.GetResults()[0]
+	if result.Source != ingestion.FalcoSource || result.Result != v1alpha2.StatusFail || result.Severity != v1alpha2.SeverityMedium || result.Category != "shell" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if result.Message != "Shell spawned in a container (user=root)" {
+		t.Errorf("unexpected message: %s", result.Message)
+	}
+	if res := result.GetResource(); res == nil || res.Kind != "Pod" || res.Name != "nginx" {
+		t.Errorf("unexpected resource: %+v", res)
+	}
+	if result.Properties["proc.pid"] != "42" || result.Properties["hostname"] != "node-1" {
+		t.Errorf("unexpected properties: %+v", result.Properties)
+	}
+	if _, ok := result.Properties["user.name"]; ok {
+		t.Error("expected null fields to be skipped")
+	}
+
+	send("Terminal shell in container", "nginx", 0)
+	if results := received.list[1].PolicyReport.GetResults(); len(results) != 1 {
+		t.Errorf("expected repeated events to update the result, got %d results", len(results))
+	}
+
+	send("Terminal shell in container", "redis", time.Second)
+	send("Read sensitive file", "redis", 2*time.Second)
+
+	results := received.list[3].PolicyReport.GetResults()
+	if len(results) != 2 || results[0].Policy != "Read sensitive file" || results[1].GetResource().Name != "redis" {
+		t.Errorf("expected the latest events up to maxResults, got %+v", results)
+	}
+
+	if rr := send("", "nginx", 0); rr.Code != http.StatusBadRequest {
+		t.Errorf("expected bad request without rule, got %d", rr.Code)
+	}
+}
+
+func Test_NDJSONHandler(t *testing.T) {
+	ingester, received := newIngester(report.NewFilter(false, validate.RuleSets{}))
+
+	send := func(query, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, ingestion.NDJSONPath+query, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(ingestion.IdempotencyHeader, key)
+		}
+
+		rr := httptest.NewRecorder()
+		ingester.NDJSONHandler()(rr, req)
+
+		return rr
+	}
+
+	stream := `{"policy": "no-root", "result": "fail", "severity": "high"}
+
+{"policy": "limits", "result": "pass"}
+{"policy": "no-root", "result": "fail", "namespace": "prod"}
+`
+
+	t.Run("Ingested", func(t *testing.T) {
+		rr := send("?source=ci&namespace=test", "build-1", stream)
+		if rr.Code != http.StatusAccepted {
+			t.Fatalf("unexpected status code %d: %s", rr.Code, rr.Body.String())
+		}
+
+		response := ingestion.StreamResponse{}
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if response.Results != 3 || len(response.Reports) != 2 {
+			t.Fatalf("unexpected response: %+v", response)
+		}
+		if response.Reports[0].Namespace != "test" || response.Reports[0].Results != 2 || response.Reports[1].Namespace != "prod" {
+			t.Errorf("expected results grouped by namespace, got %+v", response.Reports)
+		}
+		if len(received.list) != 2 || received.list[0].PolicyReport.GetSource() != "ci" {
+			t.Errorf("expected 2 published reports of source ci, got %d", len(received.list))
+		}
+	})
+
+	t.Run("Idempotency", func(t *testing.T) {
+		rr := send("?source=ci&namespace=test", "build-1", stream)
+		if rr.Code != http.StatusAccepted || rr.Header().Get("Idempotent-Replayed") != "true" {
+			t.Fatalf("expected replayed response, got %d", rr.Code)
+		}
+		if len(received.list) != 2 {
+			t.Errorf("expected no further reports for a repeated stream, got %d", len(received.list))
+		}
+	})
+
+	t.Run("Invalid lines", func(t *testing.T) {
+		rr := send("?source=ci", "build-2", "{\"policy\": \"a\", \"result\": \"pass\"}\n{\"result\": \"fail\"}\nnot json\n")
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected bad request, got %d", rr.Code)
+		}
+
+		response := ingestion.StreamResponse{}
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if len(response.Errors) != 2 || response.Errors[0].Line != 2 || response.Errors[1].Line != 3 || len(response.Reports) != 0 {
+			t.Errorf("expected errors of line 2 and 3, got %+v", response)
+		}
+		if len(received.list) != 2 {
+			t.Errorf("expected invalid streams not to be ingested")
+		}
+
+		// failed streams do not keep their idempotency key
+		if rr := send("?source=ci", "build-2", `{"policy": "a", "result": "pass"}`); rr.Code != http.StatusAccepted {
+			t.Errorf("expected retry with the same key to be accepted, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Limits", func(t *testing.T) {
+		if rr := send("", "", `{"policy": "a", "result": "pass"}`); rr.Code != http.StatusBadRequest {
+			t.Errorf("expected bad request without source, got %d", rr.Code)
+		}
+		if rr := send("?source=ci", "", strings.Repeat("{\"policy\": \"a\", \"result\": \"pass\"}\n", 4)); rr.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("expected too large for streams above the maximum, got %d", rr.Code)
+		}
+	})
+}
@@ -0,0 +1,273 @@
+package ingestion
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/api/auth"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/helper"
+)
+
+// IdempotencyHeader of NDJSON streams, a repeated stream with the same key replays the response without ingesting it again
+const IdempotencyHeader = "Idempotency-Key"
+
+const (
+	// maxStreamSize limits the body of a NDJSON stream
+	maxStreamSize = 256 << 20
+	// maxLineSize limits a single line of a NDJSON stream
+	maxLineSize = 1 << 20
+	// maxLineErrors limits the reported validation errors of a stream
+	maxLineErrors = 100
+)
+
+// NDJSONOptions of the NDJSON streaming ingestion
+type NDJSONOptions struct {
+	// MaxStreams processed concurrently, further streams are rejected with 429 Too Many Requests, defaults to 2
+	MaxStreams int
+	// MaxResults of a single stream, larger streams are rejected with 413 Request Entity Too Large, defaults to 100000
+	MaxResults int
+	// IdempotencyTTL is the time the response of a stream is replayed for its idempotency key, defaults to 24h
+	IdempotencyTTL time.Duration
+}
+
+// Line of an NDJSON stream, a result with an optional namespace and report name which override the query parameters
+type Line struct {
+	v1alpha2.PolicyReportResult
+	Namespace string `json:"namespace,omitempty"`
+	Report    string `json:"report,omitempty"`
+}
+
+// LineError is the validation error of a line, lines are counted from 1
+type LineError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// StreamResponse of a NDJSON stream, Reports is empty if any line is invalid
+type StreamResponse struct {
+	Results int         `json:"results"`
+	Reports []Response  `json:"reports"`
+	Errors  []LineError `json:"errors,omitempty"`
+}
+
+type streamKey struct {
+	namespace string
+	report    string
+}
+
+// NDJSONHandler of the NDJSON streams sent to NDJSONPath. Each line is a result, the source query parameter is required
+// and the namespace and report query parameters are the defaults of the lines. The results of a stream replace the results
+// of its reports and a stream with an invalid line is rejected with the errors of all invalid lines
+func (i *Ingester) NDJSONHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if !allowPost(w, req) {
+			return
+		}
+
+		select {
+		case i.streams <- struct{}{}:
+			defer func() { <-i.streams }()
+		default:
+			w.Header().Set("Retry-After", "1")
+			helper.SendError(w, http.StatusTooManyRequests, errors.New("too many concurrent streams, retry later"))
+			return
+		}
+
+		key := req.Header.Get(IdempotencyHeader)
+		if key != "" {
+			if identity, ok := auth.IdentityFrom(req.Context()); ok {
+				key = identity.Name + "/" + key
+			}
+
+			response, status := i.idempotency.begin(key)
+			switch status {
+			case idempotencyPending:
+				helper.SendError(w, http.StatusConflict, errors.New("a stream with the same idempotency key is in progress"))
+				return
+			case idempotencyDone:
+				w.Header().Set("Idempotent-Replayed", "true")
+				sendAccepted(w, response)
+				return
+			}
+		}
+
+		response, status, err := i.ingestStream(http.MaxBytesReader(w, req.Body, maxStreamSize), req.URL.Query().Get("source"), req.URL.Query().Get("namespace"), req.URL.Query().Get("report"))
+		if key != "" {
+			i.idempotency.finish(key, response, err == nil && len(response.Errors) == 0)
+		}
+
+		if err != nil {
+			helper.SendError(w, status, err)
+			return
+		}
+		if len(response.Errors) > 0 {
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+			w.WriteHeader(http.StatusBadRequest)
+
+			_ = json.NewEncoder(w).Encode(response)
+			return
+		}
+
+		sendAccepted(w, response)
+	}
+}
+
+func (i *Ingester) ingestStream(body io.Reader, source, namespace, report string) (StreamResponse, int, error) {
+	response := StreamResponse{Reports: []Response{}}
+
+	if source == "" {
+		return response, http.StatusBadRequest, errors.New("missing source query parameter")
+	}
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	order := make([]streamKey, 0, 1)
+	requests := make(map[streamKey]*Request)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		if response.Results++; response.Results > i.ndjson.MaxResults {
+			return response, http.StatusRequestEntityTooLarge, fmt.Errorf("stream exceeds the maximum of %d results", i.ndjson.MaxResults)
+		}
+
+		l := Line{}
+		if err := json.Unmarshal([]byte(text), &l); err != nil {
+			response.addError(line, err)
+			continue
+		}
+		if err := validateResult(l.PolicyReportResult); err != nil {
+			response.addError(line, err)
+			continue
+		}
+
+		k := streamKey{namespace: namespace, report: report}
+		if l.Namespace != "" {
+			k.namespace = l.Namespace
+		}
+		if l.Report != "" {
+			k.report = l.Report
+		}
+
+		r, ok := requests[k]
+		if !ok {
+			r = &Request{Source: source, Namespace: k.namespace, Report: k.report}
+			requests[k] = r
+			order = append(order, k)
+		}
+
+		r.Results = append(r.Results, l.PolicyReportResult)
+	}
+	if err := scanner.Err(); err != nil {
+		var maxBytes *http.MaxBytesError
+		if errors.As(err, &maxBytes) {
+			return response, http.StatusRequestEntityTooLarge, err
+		}
+
+		return response, http.StatusBadRequest, fmt.Errorf("line %d: %w", line+1, err)
+	}
+
+	if len(response.Errors) > 0 {
+		return response, http.StatusBadRequest, nil
+	}
+
+	for _, k := range order {
+		if err := validate(*requests[k]); err != nil {
+			return response, http.StatusBadRequest, err
+		}
+	}
+	for _, k := range order {
+		r, err := i.Ingest(*requests[k])
+		if err != nil {
+			return response, http.StatusBadRequest, err
+		}
+
+		response.Reports = append(response.Reports, r)
+	}
+
+	return response, http.StatusAccepted, nil
+}
+
+func (r *StreamResponse) addError(line int, err error) {
+	if len(r.Errors) < maxLineErrors {
+		r.Errors = append(r.Errors, LineError{Line: line, Error: err.Error()})
+	}
+}
+
+type idempotencyStatus int
+
+const (
+	idempotencyNew idempotencyStatus = iota
+	idempotencyPending
+	idempotencyDone
+)
+
+type idempotencyEntry struct {
+	response StreamResponse
+	done     bool
+	expires  time.Time
+}
+
+// idempotencyCache keeps the responses of successful streams by their idempotency key
+type idempotencyCache struct {
+	ttl     time.Duration
+	mx      *sync.Mutex
+	entries map[string]*idempotencyEntry
+}
+
+// begin returns the status of the key, a new key is marked as pending
+func (c *idempotencyCache) begin(key string) (StreamResponse, idempotencyStatus) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	now := time.Now()
+	for k, e := range c.entries {
+		if e.done && now.After(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+
+	if e, ok := c.entries[key]; ok {
+		if e.done {
+			return e.response, idempotencyDone
+		}
+
+		return StreamResponse{}, idempotencyPending
+	}
+
+	c.entries[key] = &idempotencyEntry{}
+
+	return StreamResponse{}, idempotencyNew
+}
+
+// finish keeps the response of a successful stream, the key of a failed stream can be retried
+func (c *idempotencyCache) finish(key string, response StreamResponse, success bool) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if !success {
+		delete(c.entries, key)
+		return
+	}
+
+	c.entries[key] = &idempotencyEntry{response: response, done: true, expires: time.Now().Add(c.ttl)}
+}
+
+func newIdempotencyCache(ttl time.Duration) *idempotencyCache {
+	return &idempotencyCache{ttl: ttl, mx: new(sync.Mutex), entries: make(map[string]*idempotencyEntry)}
+}
@@ -0,0 +1,98 @@
+package ingestion
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/sarif"
+)
+
+// FromSARIF maps each run of the SARIF log to a request, the tool name is used as source
+func FromSARIF(log sarif.Log, namespace, name string) ([]Request, error) {
+	requests := make([]Request, 0, len(log.Runs))
+
+	for i, run := range log.Runs {
+		source := run.Tool.Driver.Name
+		if source == "" {
+			return nil, fmt.Errorf("runs[%d]: missing tool.driver.name", i)
+		}
+
+		rules := make(map[string]sarif.Rule, len(run.Tool.Driver.Rules))
+		for _, rule := range run.Tool.Driver.Rules {
+			rules[rule.ID] = rule
+		}
+
+		req := Request{Source: source, Namespace: namespace, Report: name, Results: make([]v1alpha2.PolicyReportResult, 0, len(run.Results))}
+		if name != "" && len(log.Runs) > 1 {
+			req.Report = fmt.Sprintf("%s-%d", name, i)
+		}
+
+		for _, r := range run.Results {
+			rule := rules[r.RuleID]
+
+			result := v1alpha2.PolicyReportResult{
+				Source:     source,
+				Policy:     r.RuleID,
+				Rule:       rule.Name,
+				Message:    r.Message.Text,
+				Result:     sarifStatus(r.Kind, r.Level),
+				Severity:   sarifSeverity(r.Level),
+				Properties: map[string]string{},
+			}
+			if result.Message == "" && rule.ShortDescription != nil {
+				result.Message = rule.ShortDescription.Text
+			}
+			if rule.Properties != nil && len(rule.Properties.Tags) > 0 {
+				result.Category = rule.Properties.Tags[0]
+			}
+			if len(r.Locations) > 0 && r.Locations[0].PhysicalLocation != nil {
+				location := r.Locations[0].PhysicalLocation
+				result.Properties["location"] = location.ArtifactLocation.URI
+				if location.Region != nil && location.Region.StartLine > 0 {
+					result.Properties["line"] = strconv.Itoa(location.Region.StartLine)
+				}
+			}
+
+			req.Results = append(req.Results, result)
+		}
+
+		requests = append(requests, req)
+	}
+
+	return requests, nil
+}
+
+// sarifStatus maps the result kind and level, results without kind are failures with warning level by default
+func sarifStatus(kind, level string) v1alpha2.PolicyResult {
+	switch kind {
+	case "pass":
+		return v1alpha2.StatusPass
+	case "notApplicable":
+		return v1alpha2.StatusSkip
+	case "review", "open", "informational":
+		return v1alpha2.StatusWarn
+	}
+
+	switch level {
+	case "error":
+		return v1alpha2.StatusFail
+	case "none":
+		return v1alpha2.StatusPass
+	default:
+		return v1alpha2.StatusWarn
+	}
+}
+
+func sarifSeverity(level string) v1alpha2.PolicySeverity {
+	switch level {
+	case "error":
+		return v1alpha2.SeverityHigh
+	case "note":
+		return v1alpha2.SeverityLow
+	case "none":
+		return v1alpha2.SeverityInfo
+	default:
+		return v1alpha2.SeverityMedium
+	}
+}
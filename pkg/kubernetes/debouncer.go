@@ -5,65 +5,230 @@ import (
 	"time"
 
 	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/validate"
 )
 
 type Debouncer interface {
 	Add(e report.LifecycleEvent)
+	// Flush publishes all delayed events
+	Flush()
+}
+
+// DebounceWindow overrides the default window for reports of the matching sources and namespaces,
+// both support wildcards and regular expressions and an empty list matches all values
+type DebounceWindow struct {
+	Sources    []string
+	Namespaces []string
+	Window     time.Duration
+	// MaxDelay defaults to the Window
+	MaxDelay time.Duration
+}
+
+func (w DebounceWindow) matches(source, namespace string) bool {
+	return matchAny(w.Sources, source) && matchAny(w.Namespaces, namespace)
+}
+
+// DebounceOptions of the Debouncer
+type DebounceOptions struct {
+	// EmptyWait delays updates without results, they are dropped if an update with results or a delete follows
+	EmptyWait time.Duration
+	// Window coalesces rapid updates of a report, only the latest update is published after the window passed without further updates
+	Window time.Duration
+	// MaxDelay after the first coalesced update until the latest update is published, defaults to the Window
+	MaxDelay time.Duration
+	// Windows per source and namespace, the first matching window is used
+	Windows []DebounceWindow
+	// Coalesced is called with the report source for each update replaced by a newer one
+	Coalesced func(source string)
+	// Pending is called with the number of delayed events after each change
+	Pending func(count int)
+}
+
+type pendingEvent struct {
+	event    report.LifecycleEvent
+	empty    bool
+	first    time.Time
+	deadline time.Time
+	timer    *time.Timer
 }
 
 type debouncer struct {
-	waitDuration time.Duration
-	events       map[string]report.LifecycleEvent
-	publisher    report.EventPublisher
-	mutx         *sync.Mutex
+	options   DebounceOptions
+	events    map[string]*pendingEvent
+	publisher report.EventPublisher
+	mutx      *sync.Mutex
 }
 
 func (d *debouncer) Add(event report.LifecycleEvent) {
-	_, ok := d.events[event.PolicyReport.GetID()]
-	if event.Type != report.Updated && ok {
-		d.mutx.Lock()
-		delete(d.events, event.PolicyReport.GetID())
-		d.mutx.Unlock()
-	}
+	id := event.PolicyReport.GetID()
+
+	d.mutx.Lock()
+	pending, ok := d.events[id]
 
 	if event.Type != report.Updated {
+		if ok {
+			d.remove(id, pending)
+		}
+		d.mutx.Unlock()
+
 		d.publisher.Publish(event)
 		return
 	}
 
-	if len(event.PolicyReport.GetResults()) == 0 && !ok {
-		d.mutx.Lock()
-		d.events[event.PolicyReport.GetID()] = event
-		d.mutx.Unlock()
+	now := time.Now()
+	empty := len(event.PolicyReport.GetResults()) == 0
+
+	if ok {
+		d.coalesced(pending.event)
 
-		go func() {
-			time.Sleep(d.waitDuration)
+		if observed := pending.event.Observed; !observed.IsZero() && observed.Before(event.Observed) {
+			event.Observed = observed
+		}
 
-			d.mutx.Lock()
-			if event, ok := d.events[event.PolicyReport.GetID()]; ok {
-				d.publisher.Publish(event)
-				delete(d.events, event.PolicyReport.GetID())
-			}
+		switch {
+		case pending.empty && empty:
+			pending.event = event
 			d.mutx.Unlock()
-		}()
+			return
+		case !pending.empty && !empty:
+			window, maxDelay := d.window(event)
 
+			pending.event = event
+			d.schedule(pending, minTime(now.Add(window), pending.first.Add(maxDelay)))
+			d.mutx.Unlock()
+			return
+		}
+
+		d.remove(id, pending)
+	}
+
+	if empty {
+		d.hold(id, &pendingEvent{event: event, empty: true, first: now}, now.Add(d.options.EmptyWait))
+		d.mutx.Unlock()
 		return
 	}
 
-	if len(event.PolicyReport.GetResults()) > 0 && ok {
-		d.mutx.Lock()
-		delete(d.events, event.PolicyReport.GetID())
+	if window, maxDelay := d.window(event); window > 0 {
+		d.hold(id, &pendingEvent{event: event, first: now}, now.Add(minDuration(window, maxDelay)))
 		d.mutx.Unlock()
+		return
 	}
+	d.mutx.Unlock()
 
 	d.publisher.Publish(event)
 }
 
+func (d *debouncer) Flush() {
+	d.mutx.Lock()
+	defer d.mutx.Unlock()
+
+	for id, pending := range d.events {
+		d.remove(id, pending)
+		d.publisher.Publish(pending.event)
+	}
+}
+
+// window and max delay for the report of the event
+func (d *debouncer) window(event report.LifecycleEvent) (time.Duration, time.Duration) {
+	window, maxDelay := d.options.Window, d.options.MaxDelay
+
+	for _, w := range d.options.Windows {
+		if w.matches(event.PolicyReport.GetSource(), event.PolicyReport.GetNamespace()) {
+			window, maxDelay = w.Window, w.MaxDelay
+			break
+		}
+	}
+
+	if maxDelay <= 0 {
+		maxDelay = window
+	}
+
+	return window, maxDelay
+}
+
+func (d *debouncer) hold(id string, pending *pendingEvent, deadline time.Time) {
+	d.events[id] = pending
+	d.schedule(pending, deadline)
+	d.pending()
+}
+
+func (d *debouncer) schedule(pending *pendingEvent, deadline time.Time) {
+	pending.deadline = deadline
+
+	if pending.timer != nil {
+		pending.timer.Reset(time.Until(deadline))
+		return
+	}
+
+	pending.timer = time.AfterFunc(time.Until(deadline), func() {
+		d.mutx.Lock()
+		defer d.mutx.Unlock()
+
+		id := pending.event.PolicyReport.GetID()
+		// the timer was reset or the event replaced while waiting for the lock
+		if d.events[id] != pending || time.Now().Before(pending.deadline) {
+			return
+		}
+
+		delete(d.events, id)
+		d.pending()
+		d.publisher.Publish(pending.event)
+	})
+}
+
+func (d *debouncer) remove(id string, pending *pendingEvent) {
+	pending.timer.Stop()
+	delete(d.events, id)
+	d.pending()
+}
+
+func (d *debouncer) pending() {
+	if d.options.Pending != nil {
+		d.options.Pending(len(d.events))
+	}
+}
+
+func (d *debouncer) coalesced(event report.LifecycleEvent) {
+	if d.options.Coalesced != nil {
+		d.options.Coalesced(event.PolicyReport.GetSource())
+	}
+}
+
+func matchAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	return validate.MatchAny(patterns, value)
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+
+	return b
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+// NewDebouncer delays empty updates by the wait duration and publishes all other events immediately
 func NewDebouncer(waitDuration time.Duration, publisher report.EventPublisher) Debouncer {
+	return NewConfiguredDebouncer(DebounceOptions{EmptyWait: waitDuration}, publisher)
+}
+
+// NewConfiguredDebouncer with coalescing of updates per source and namespace
+func NewConfiguredDebouncer(options DebounceOptions, publisher report.EventPublisher) Debouncer {
 	return &debouncer{
-		waitDuration: waitDuration,
-		events:       make(map[string]report.LifecycleEvent),
-		mutx:         new(sync.Mutex),
-		publisher:    publisher,
+		options:   options,
+		events:    make(map[string]*pendingEvent),
+		mutx:      new(sync.Mutex),
+		publisher: publisher,
 	}
 }
@@ -116,4 +116,128 @@ func Test_Debouncer(t *testing.T) {
 			t.Error("Expected to skip the empty modify event")
 		}
 	})
+
+	t.Run("Flush delayed Updates", func(t *testing.T) {
+		counter := 0
+
+		publisher := report.NewEventPublisher()
+		publisher.RegisterListener("test", func(event report.LifecycleEvent) {
+			counter++
+		})
+
+		debouncer := kubernetes.NewDebouncer(time.Minute, publisher)
+
+		debouncer.Add(report.LifecycleEvent{
+			Type:         report.Updated,
+			PolicyReport: fixtures.MinPolicyReport,
+		})
+
+		debouncer.Flush()
+
+		if counter != 1 {
+			t.Error("Expected to publish the delayed update event on flush")
+		}
+	})
+	t.Run("Coalesce rapid Updates", func(t *testing.T) {
+		events := make(chan report.LifecycleEvent, 5)
+		coalesced := 0
+
+		publisher := report.NewEventPublisher()
+		publisher.RegisterListener("test", func(event report.LifecycleEvent) {
+			events <- event
+		})
+
+		debouncer := kubernetes.NewConfiguredDebouncer(kubernetes.DebounceOptions{
+			Windows: []kubernetes.DebounceWindow{{Namespaces: []string{"te*"}, Window: 50 * time.Millisecond}},
+			Coalesced: func(source string) {
+				coalesced++
+			},
+		}, publisher)
+
+		for i := 0; i < 3; i++ {
+			debouncer.Add(report.LifecycleEvent{Type: report.Updated, PolicyReport: fixtures.DefaultPolicyReport})
+		}
+
+		event := <-events
+		if event.PolicyReport.GetID() != fixtures.DefaultPolicyReport.GetID() {
+			t.Error("Expected the latest update to be published")
+		}
+
+		time.Sleep(60 * time.Millisecond)
+
+		if len(events) != 0 {
+			t.Errorf("Expected a single coalesced update, got %d additional events", len(events))
+		}
+		if coalesced != 2 {
+			t.Errorf("Expected 2 coalesced updates, got %d", coalesced)
+		}
+	})
+
+	t.Run("Publish coalesced Updates after max delay", func(t *testing.T) {
+		published := make(chan time.Time, 5)
+
+		publisher := report.NewEventPublisher()
+		publisher.RegisterListener("test", func(event report.LifecycleEvent) {
+			published <- time.Now()
+		})
+
+		debouncer := kubernetes.NewConfiguredDebouncer(kubernetes.DebounceOptions{
+			Window:   40 * time.Millisecond,
+			MaxDelay: 100 * time.Millisecond,
+		}, publisher)
+
+		start := time.Now()
+		for i := 0; i < 10; i++ {
+			debouncer.Add(report.LifecycleEvent{Type: report.Updated, PolicyReport: fixtures.DefaultPolicyReport})
+			time.Sleep(20 * time.Millisecond)
+		}
+
+		select {
+		case at := <-published:
+			if at.Sub(start) > 150*time.Millisecond {
+				t.Errorf("Expected the update to be published after the max delay, got %s", at.Sub(start))
+			}
+		case <-time.After(time.Second):
+			t.Error("Expected the coalesced update to be published")
+		}
+	})
+
+	t.Run("Publish Updates of other sources immediately", func(t *testing.T) {
+		counter := 0
+
+		publisher := report.NewEventPublisher()
+		publisher.RegisterListener("test", func(event report.LifecycleEvent) {
+			counter++
+		})
+
+		debouncer := kubernetes.NewConfiguredDebouncer(kubernetes.DebounceOptions{
+			Windows: []kubernetes.DebounceWindow{{Sources: []string{"trivy"}, Window: time.Minute}},
+		}, publisher)
+
+		debouncer.Add(report.LifecycleEvent{Type: report.Updated, PolicyReport: fixtures.DefaultPolicyReport})
+
+		if counter != 1 {
+			t.Error("Expected to publish the update without a matching window immediately")
+		}
+	})
+	t.Run("Report pending Events", func(t *testing.T) {
+		pending := -1
+
+		debouncer := kubernetes.NewConfiguredDebouncer(kubernetes.DebounceOptions{
+			EmptyWait: time.Minute,
+			Pending: func(count int) {
+				pending = count
+			},
+		}, report.NewEventPublisher())
+
+		debouncer.Add(report.LifecycleEvent{Type: report.Updated, PolicyReport: fixtures.MinPolicyReport})
+		if pending != 1 {
+			t.Errorf("Expected 1 pending event, got %d", pending)
+		}
+
+		debouncer.Flush()
+		if pending != 0 {
+			t.Errorf("Expected no pending events after flush, got %d", pending)
+		}
+	})
 }
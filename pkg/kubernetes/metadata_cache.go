@@ -0,0 +1,141 @@
+package kubernetes
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/cache"
+)
+
+// MetadataCache provides the labels and annotations of result resources.
+// A metadata informer is started for each resource kind on its first lookup.
+type MetadataCache struct {
+	client      metadata.Interface
+	mapper      *restmapper.DeferredDiscoveryRESTMapper
+	informers   map[schema.GroupVersionResource]informers.GenericInformer
+	mx          *sync.Mutex
+	stop        chan struct{}
+	syncTimeout time.Duration
+}
+
+// Get the metadata of the resource, false if the resource or its kind is unknown
+func (c *MetadataCache) Get(resource *corev1.ObjectReference) (*metav1.PartialObjectMetadata, bool) {
+	if resource == nil || resource.Name == "" || resource.Kind == "" {
+		return nil, false
+	}
+
+	informer := c.informer(resource)
+	if informer == nil || !informer.Informer().HasSynced() {
+		return nil, false
+	}
+
+	var (
+		obj runtime.Object
+		err error
+	)
+
+	if resource.Namespace == "" {
+		obj, err = informer.Lister().Get(resource.Name)
+	} else {
+		obj, err = informer.Lister().ByNamespace(resource.Namespace).Get(resource.Name)
+	}
+	if err != nil {
+		return nil, false
+	}
+
+	meta, ok := obj.(*metav1.PartialObjectMetadata)
+
+	return meta, ok
+}
+
+// Exists reports whether the resource exists, known is false if the kind is unknown or its informer is not synced
+func (c *MetadataCache) Exists(resource *corev1.ObjectReference) (exists bool, known bool) {
+	if resource == nil || resource.Name == "" || resource.Kind == "" {
+		return false, false
+	}
+
+	informer := c.informer(resource)
+	if informer == nil || !informer.Informer().HasSynced() {
+		return false, false
+	}
+
+	var err error
+	if resource.Namespace == "" {
+		_, err = informer.Lister().Get(resource.Name)
+	} else {
+		_, err = informer.Lister().ByNamespace(resource.Namespace).Get(resource.Name)
+	}
+
+	if errors.IsNotFound(err) {
+		return false, true
+	}
+
+	return err == nil, err == nil
+}
+
+func (c *MetadataCache) informer(resource *corev1.ObjectReference) informers.GenericInformer {
+	gv, err := schema.ParseGroupVersion(resource.APIVersion)
+	if err != nil {
+		return nil
+	}
+
+	mapping, err := c.mapper.RESTMapping(gv.WithKind(resource.Kind).GroupKind(), gv.Version)
+	if err != nil {
+		// reset the discovery cache for resources of new CRDs
+		c.mapper.Reset()
+		return nil
+	}
+
+	c.mx.Lock()
+	if informer, ok := c.informers[mapping.Resource]; ok {
+		c.mx.Unlock()
+		return informer
+	}
+
+	informer := metadatainformer.NewFilteredMetadataInformer(c.client, mapping.Resource, metav1.NamespaceAll, 0, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, nil)
+	c.informers[mapping.Resource] = informer
+	c.mx.Unlock()
+
+	go informer.Informer().Run(c.stop)
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.syncTimeout)
+	defer cancel()
+
+	if !cache.WaitForCacheSync(ctx.Done(), informer.Informer().HasSynced) {
+		logger.Warnf("metadata cache for %s not synced within %s", mapping.Resource.String(), c.syncTimeout)
+	}
+
+	return informer
+}
+
+// Run blocks until the context is done and stops all started informers
+func (c *MetadataCache) Run(ctx context.Context) error {
+	<-ctx.Done()
+	close(c.stop)
+
+	return nil
+}
+
+// NewMetadataCache creates a lazy metadata cache, the resource of each kind is resolved with the discovery API
+func NewMetadataCache(client metadata.Interface, discoveryClient discovery.DiscoveryInterface) *MetadataCache {
+	return &MetadataCache{
+		client:      client,
+		mapper:      restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+		informers:   make(map[schema.GroupVersionResource]informers.GenericInformer),
+		mx:          new(sync.Mutex),
+		stop:        make(chan struct{}),
+		syncTimeout: 10 * time.Second,
+	}
+}
@@ -0,0 +1,68 @@
+package kubernetes_test
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	metafake "k8s.io/client-go/metadata/fake"
+
+	"github.com/kyverno/policy-reporter/pkg/kubernetes"
+)
+
+func Test_MetadataCache(t *testing.T) {
+	kube := kubefake.NewSimpleClientset()
+	kube.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "apps/v1",
+		APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment", Namespaced: true}},
+	}}
+
+	scheme := metafake.NewTestScheme()
+	metav1.AddMetaToScheme(scheme)
+
+	client := metafake.NewSimpleMetadataClient(scheme, &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "nginx",
+			Namespace:   "test",
+			Labels:      map[string]string{"policy-exemption": "true"},
+			Annotations: map[string]string{"owner": "team-a"},
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cache := kubernetes.NewMetadataCache(client, kube.Discovery())
+	go cache.Run(ctx)
+
+	t.Run("Known Resource", func(t *testing.T) {
+		meta, ok := cache.Get(&corev1.ObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "test", Name: "nginx"})
+		if !ok {
+			t.Fatal("expected resource metadata to be found")
+		}
+		if meta.GetLabels()["policy-exemption"] != "true" {
+			t.Errorf("unexpected labels: %v", meta.GetLabels())
+		}
+		if meta.GetAnnotations()["owner"] != "team-a" {
+			t.Errorf("unexpected annotations: %v", meta.GetAnnotations())
+		}
+	})
+	t.Run("Unknown Resource", func(t *testing.T) {
+		if _, ok := cache.Get(&corev1.ObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "test", Name: "apache"}); ok {
+			t.Error("expected unknown resource to be not found")
+		}
+	})
+	t.Run("Unknown Kind", func(t *testing.T) {
+		if _, ok := cache.Get(&corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Namespace: "test", Name: "nginx"}); ok {
+			t.Error("expected resource of an unknown kind to be not found")
+		}
+	})
+	t.Run("Without Resource", func(t *testing.T) {
+		if _, ok := cache.Get(nil); ok {
+			t.Error("expected nil resource to be not found")
+		}
+	})
+}
@@ -2,6 +2,7 @@ package kubernetes
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,15 +16,67 @@ import (
 	"github.com/kyverno/policy-reporter/pkg/report"
 )
 
+// WatchOptions restricts the list/watch requests of the PolicyReport informers
+type WatchOptions struct {
+	// ResyncPeriod of the informers, defaults to 15 minutes
+	ResyncPeriod time.Duration
+	// LabelSelector applied to PolicyReports and ClusterPolicyReports
+	LabelSelector string
+	// ExcludeLabels ignores reports with any of these labels, e.g. the intermediate reports of Kyverno
+	ExcludeLabels []string
+	// Namespaces to watch PolicyReports in, all namespaces if empty
+	Namespaces []string
+	// ExcludeNamespaces are ignored by the PolicyReport informer if no Namespaces are configured
+	ExcludeNamespaces []string
+	// PersistedReports returns the keys of the reports processed before a restart,
+	// reports which no longer exist are processed as deleted after the initial sync
+	PersistedReports func() []string
+}
+
+func (o WatchOptions) resyncPeriod() time.Duration {
+	if o.ResyncPeriod <= 0 {
+		return 15 * time.Minute
+	}
+
+	return o.ResyncPeriod
+}
+
+func (o WatchOptions) tweakListOptions(namespaced bool) metadatainformer.TweakListOptionsFunc {
+	selectors := make([]string, 0, len(o.ExcludeNamespaces))
+	if namespaced && len(o.Namespaces) == 0 {
+		for _, ns := range o.ExcludeNamespaces {
+			selectors = append(selectors, "metadata.namespace!="+ns)
+		}
+	}
+
+	labels := make([]string, 0, len(o.ExcludeLabels)+1)
+	if o.LabelSelector != "" {
+		labels = append(labels, o.LabelSelector)
+	}
+	for _, label := range o.ExcludeLabels {
+		labels = append(labels, "!"+label)
+	}
+
+	return func(options *v1.ListOptions) {
+		if len(labels) > 0 {
+			options.LabelSelector = strings.Join(labels, ",")
+		}
+		if len(selectors) > 0 {
+			options.FieldSelector = strings.Join(selectors, ",")
+		}
+	}
+}
+
 type k8sPolicyReportClient struct {
 	queue        *Queue
-	fatcory      metadatainformer.SharedInformerFactory
-	polr         informers.GenericInformer
+	factories    []metadatainformer.SharedInformerFactory
+	polr         []informers.GenericInformer
 	cpolr        informers.GenericInformer
 	metaClient   metadata.Interface
 	synced       bool
 	mx           *sync.Mutex
 	reportFilter *report.Filter
+	persisted    func() []string
 }
 
 func (k *k8sPolicyReportClient) HasSynced() bool {
@@ -31,29 +84,73 @@ func (k *k8sPolicyReportClient) HasSynced() bool {
 }
 
 func (k *k8sPolicyReportClient) Sync(stopper chan struct{}) error {
-	var cpolrInformer cache.SharedIndexInformer
+	synced := make([]cache.InformerSynced, 0, len(k.polr)+1)
 
-	polrInformer := k.configureInformer(k.polr.Informer())
-
-	if !k.reportFilter.DisableClusterReports() {
-		cpolrInformer = k.configureInformer(k.cpolr.Informer())
+	for _, polr := range k.polr {
+		synced = append(synced, k.configureInformer(polr.Informer()).HasSynced)
 	}
 
-	k.fatcory.Start(stopper)
+	if k.cpolr != nil {
+		synced = append(synced, k.configureInformer(k.cpolr.Informer()).HasSynced)
+	}
 
-	if !cache.WaitForCacheSync(stopper, polrInformer.HasSynced) {
-		return fmt.Errorf("failed to sync policy reports")
+	for _, factory := range k.factories {
+		factory.Start(stopper)
 	}
 
-	if cpolrInformer != nil && !cache.WaitForCacheSync(stopper, cpolrInformer.HasSynced) {
-		return fmt.Errorf("failed to sync cluster policy reports")
+	if !cache.WaitForCacheSync(stopper, synced...) {
+		return fmt.Errorf("failed to sync policy reports")
 	}
 
 	k.synced = true
 
+	if k.persisted != nil {
+		k.reconcile()
+	}
+
 	return nil
 }
 
+// reconcile queues persisted reports which were removed while Policy Reporter was not running
+func (k *k8sPolicyReportClient) reconcile() {
+	existing := make(map[string]bool)
+
+	list := append([]informers.GenericInformer{}, k.polr...)
+	if k.cpolr != nil {
+		list = append(list, k.cpolr)
+	}
+
+	for _, informer := range list {
+		for _, key := range informer.Informer().GetStore().ListKeys() {
+			existing[key] = true
+		}
+	}
+
+	var removed int
+	for _, key := range k.persisted() {
+		if existing[key] {
+			continue
+		}
+
+		namespace, _, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil || (namespace == "" && k.cpolr == nil) || !k.reportFilter.AllowReport(namespacedKey(namespace)) {
+			continue
+		}
+
+		k.queue.AddKey(key)
+		removed++
+	}
+
+	if removed > 0 {
+		logger.Infof("reconcile %d reports removed since the last run", removed)
+	}
+}
+
+type namespacedKey string
+
+func (n namespacedKey) GetNamespace() string {
+	return string(n)
+}
 func (k *k8sPolicyReportClient) Run(worker int, stopper chan struct{}) error {
 	if err := k.Sync(stopper); err != nil {
 		return err
@@ -97,17 +194,39 @@ func (k *k8sPolicyReportClient) configureInformer(informer cache.SharedIndexInfo
 }
 
 // NewPolicyReportClient new Client for Policy Report Kubernetes API
-func NewPolicyReportClient(metaClient metadata.Interface, reportFilter *report.Filter, queue *Queue) report.PolicyReportClient {
-	fatcory := metadatainformer.NewSharedInformerFactory(metaClient, 15*time.Minute)
-	polr := fatcory.ForResource(pr.SchemeGroupVersion.WithResource("policyreports"))
-	cpolr := fatcory.ForResource(pr.SchemeGroupVersion.WithResource("clusterpolicyreports"))
+func NewPolicyReportClient(metaClient metadata.Interface, reportFilter *report.Filter, queue *Queue, options WatchOptions) report.PolicyReportClient {
+	polrResource := pr.SchemeGroupVersion.WithResource("policyreports")
+
+	namespaces := options.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{v1.NamespaceAll}
+	}
+
+	factories := make([]metadatainformer.SharedInformerFactory, 0, len(namespaces)+1)
+	polr := make([]informers.GenericInformer, 0, len(namespaces))
+
+	for _, ns := range namespaces {
+		factory := metadatainformer.NewFilteredSharedInformerFactory(metaClient, options.resyncPeriod(), ns, options.tweakListOptions(true))
+
+		factories = append(factories, factory)
+		polr = append(polr, factory.ForResource(polrResource))
+	}
+
+	var cpolr informers.GenericInformer
+	if !reportFilter.DisableClusterReports() {
+		factory := metadatainformer.NewFilteredSharedInformerFactory(metaClient, options.resyncPeriod(), v1.NamespaceAll, options.tweakListOptions(false))
+
+		factories = append(factories, factory)
+		cpolr = factory.ForResource(pr.SchemeGroupVersion.WithResource("clusterpolicyreports"))
+	}
 
 	return &k8sPolicyReportClient{
-		fatcory:      fatcory,
+		factories:    factories,
 		polr:         polr,
 		cpolr:        cpolr,
 		mx:           &sync.Mutex{},
 		queue:        queue,
 		reportFilter: reportFilter,
+		persisted:    options.PersistedReports,
 	}
 }
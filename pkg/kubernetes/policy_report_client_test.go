@@ -41,7 +41,7 @@ func Test_PolicyReportWatcher(t *testing.T) {
 	)
 
 	kclient, rclient, _ := NewFakeMetaClient()
-	client := kubernetes.NewPolicyReportClient(kclient, filter, queue)
+	client := kubernetes.NewPolicyReportClient(kclient, filter, queue, kubernetes.WatchOptions{})
 
 	go func() {
 		err := client.Run(1, stop)
@@ -91,7 +91,7 @@ func Test_ClusterPolicyReportWatcher(t *testing.T) {
 	)
 
 	kclient, _, rclient := NewFakeMetaClient()
-	client := kubernetes.NewPolicyReportClient(kclient, filter, queue)
+	client := kubernetes.NewPolicyReportClient(kclient, filter, queue, kubernetes.WatchOptions{})
 
 	go func() {
 		err := client.Run(1, stop)
@@ -131,7 +131,7 @@ func Test_HasSynced(t *testing.T) {
 	)
 
 	kclient, _, _ := NewFakeMetaClient()
-	client := kubernetes.NewPolicyReportClient(kclient, filter, queue)
+	client := kubernetes.NewPolicyReportClient(kclient, filter, queue, kubernetes.WatchOptions{})
 
 	err := client.Sync(stop)
 	if err != nil {
@@ -142,3 +142,91 @@ func Test_HasSynced(t *testing.T) {
 		t.Errorf("Should synced")
 	}
 }
+
+func Test_NamespacedPolicyReportWatcher(t *testing.T) {
+	ctx := context.Background()
+	stop := make(chan struct{})
+	defer close(stop)
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+
+	store := newStore(1)
+	publisher := report.NewEventPublisher()
+	publisher.RegisterListener("test", func(event report.LifecycleEvent) {
+		store.Add(event)
+		wg.Done()
+	})
+
+	restClient, polrClient, _ := NewFakeClient()
+
+	queue := kubernetes.NewQueue(
+		kubernetes.NewDebouncer(0, publisher),
+		workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test-queue"),
+		restClient.Wgpolicyk8sV1alpha2(),
+	)
+
+	kclient, rclient, _ := NewFakeMetaClient()
+	client := kubernetes.NewPolicyReportClient(kclient, report.NewFilter(true, validate.RuleSets{}), queue, kubernetes.WatchOptions{
+		Namespaces:   []string{"test"},
+		ResyncPeriod: time.Minute,
+	})
+
+	go func() {
+		err := client.Run(1, stop)
+		if err != nil {
+			t.Error(err)
+		}
+	}()
+
+	polrClient.Create(ctx, fixtures.DefaultPolicyReport, metav1.CreateOptions{})
+	rclient.CreateFake(fixtures.DefaultMeta, metav1.CreateOptions{})
+
+	wg.Wait()
+
+	if len(store.List()) != 1 {
+		t.Error("Should receive the Added Event of the watched namespace")
+	}
+}
+
+func Test_ReconcileRemovedPolicyReports(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+
+	store := newStore(1)
+	publisher := report.NewEventPublisher()
+	publisher.RegisterListener("test", func(event report.LifecycleEvent) {
+		store.Add(event)
+		wg.Done()
+	})
+
+	restClient, _, _ := NewFakeClient()
+
+	queue := kubernetes.NewQueue(
+		kubernetes.NewDebouncer(0, publisher),
+		workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test-queue"),
+		restClient.Wgpolicyk8sV1alpha2(),
+	)
+
+	kclient, _, _ := NewFakeMetaClient()
+	client := kubernetes.NewPolicyReportClient(kclient, filter, queue, kubernetes.WatchOptions{
+		PersistedReports: func() []string { return []string{"test/removed-polr"} },
+	})
+
+	go func() {
+		err := client.Run(1, stop)
+		if err != nil {
+			t.Error(err)
+		}
+	}()
+
+	wg.Wait()
+
+	event := store.Get(0)
+	if event.Type != report.Deleted || event.PolicyReport.GetName() != "removed-polr" {
+		t.Errorf("Should receive a Deleted Event for the removed report, got %v", event.Type)
+	}
+}
@@ -2,29 +2,40 @@ package kubernetes
 
 import (
 	"context"
-	"log"
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 
 	pr "github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
 	"github.com/kyverno/policy-reporter/pkg/crd/client/clientset/versioned/typed/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/logging"
 	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/tracing"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
+var logger = logging.Component("kubernetes")
+
 type Queue struct {
 	queue     workqueue.RateLimitingInterface
 	client    v1alpha2.Wgpolicyk8sV1alpha2Interface
 	debouncer Debouncer
 	lock      *sync.Mutex
 	cache     sets.Set[string]
+	observed  map[string]time.Time
+	mappers   []func(pr.ReportInterface)
+}
+
+// RegisterMapper adds a function which is applied to each fetched report before it is published
+func (q *Queue) RegisterMapper(mapper func(pr.ReportInterface)) {
+	q.mappers = append(q.mappers, mapper)
 }
 
 func (q *Queue) Add(obj *v1.PartialObjectMetadata) error {
@@ -33,21 +44,53 @@ func (q *Queue) Add(obj *v1.PartialObjectMetadata) error {
 		return err
 	}
 
-	q.queue.Add(key)
+	q.AddKey(key)
 
 	return nil
 }
 
+// AddKey queues a report by its namespace/name key
+func (q *Queue) AddKey(key string) {
+	q.lock.Lock()
+	if _, ok := q.observed[key]; !ok {
+		q.observed[key] = time.Now()
+	}
+	q.lock.Unlock()
+
+	q.queue.Add(key)
+}
+
+// observedAt returns and resets the time the change of the report was first observed
+func (q *Queue) observedAt(key string) time.Time {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	observed := q.observed[key]
+	delete(q.observed, key)
+
+	return observed
+}
+
+// Run processes the queued reports until the stop channel is closed,
+// the already queued reports are processed and delayed events are published before it returns
 func (q *Queue) Run(workers int, stopCh chan struct{}) {
 	defer runtime.HandleCrash()
 
-	defer q.queue.ShutDown()
-
+	wg := &sync.WaitGroup{}
 	for i := 0; i < workers; i++ {
-		go wait.Until(q.runWorker, time.Second, stopCh)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.runWorker()
+		}()
 	}
 
 	<-stopCh
+
+	q.queue.ShutDown()
+	wg.Wait()
+
+	q.debouncer.Flush()
 }
 
 func (q *Queue) runWorker() {
@@ -63,12 +106,18 @@ func (q *Queue) processNextItem() bool {
 	key := obj.(string)
 	defer q.queue.Done(key)
 
+	observed := q.observedAt(key)
+
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
 		q.queue.Forget(key)
 		return true
 	}
 
+	span := tracing.Start(trace.SpanContext{}, "report.receive")
+	span.SetAttributes(attribute.String("report.key", key))
+	defer span.End()
+
 	var polr pr.ReportInterface
 
 	if namespace == "" {
@@ -98,7 +147,8 @@ func (q *Queue) processNextItem() bool {
 			defer q.lock.Unlock()
 			q.cache.Delete(key)
 		}()
-		q.debouncer.Add(report.LifecycleEvent{Type: report.Deleted, PolicyReport: polr})
+		span.SetAttributes(attribute.String("report.event", report.Deleted.String()))
+		q.debouncer.Add(report.LifecycleEvent{Type: report.Deleted, PolicyReport: polr, Observed: observed, Trace: span.SpanContext()})
 
 		return true
 	}
@@ -117,7 +167,15 @@ func (q *Queue) processNextItem() bool {
 
 	q.handleErr(err, key)
 
-	q.debouncer.Add(report.LifecycleEvent{Type: event, PolicyReport: polr})
+	if err == nil {
+		for _, mapper := range q.mappers {
+			mapper(polr)
+		}
+	}
+
+	tracing.RecordError(span, err)
+	span.SetAttributes(attribute.String("report.event", event.String()))
+	q.debouncer.Add(report.LifecycleEvent{Type: event, PolicyReport: polr, Observed: observed, Trace: span.SpanContext()})
 
 	return true
 }
@@ -129,7 +187,7 @@ func (q *Queue) handleErr(err error, key interface{}) {
 	}
 
 	if q.queue.NumRequeues(key) < 5 {
-		log.Printf("[ERROR] process report %v: %v", key, err)
+		logger.Errorf("process report %v: %v", key, err)
 
 		q.queue.AddRateLimited(key)
 		return
@@ -138,7 +196,7 @@ func (q *Queue) handleErr(err error, key interface{}) {
 	q.queue.Forget(key)
 
 	runtime.HandleError(err)
-	log.Printf("[WARNING] Dropping report %q out of the queue: %v", key, err)
+	logger.Warnf("Dropping report %q out of the queue: %v", key, err)
 }
 
 func NewQueue(debouncer Debouncer, queue workqueue.RateLimitingInterface, client v1alpha2.Wgpolicyk8sV1alpha2Interface) *Queue {
@@ -147,6 +205,7 @@ func NewQueue(debouncer Debouncer, queue workqueue.RateLimitingInterface, client
 		queue:     queue,
 		client:    client,
 		cache:     sets.New[string](),
+		observed:  make(map[string]time.Time),
 		lock:      &sync.Mutex{},
 	}
 }
@@ -15,6 +15,12 @@ type Values struct {
 	AccessKeyID     string
 	SecretAccessKey string
 	Token           string
+	Database        string
+	DSN             string
+	EncryptionKey   string
+	SigningSecret   string
+	ClientSecret    string
+	Credentials     string
 }
 
 type Client interface {
@@ -60,6 +66,30 @@ func (c *k8sClient) Get(ctx context.Context, name string) (Values, error) {
 		values.Token = string(token)
 	}
 
+	if database, ok := secret.Data["database"]; ok {
+		values.Database = string(database)
+	}
+
+	if dsn, ok := secret.Data["dsn"]; ok {
+		values.DSN = string(dsn)
+	}
+
+	if encryptionKey, ok := secret.Data["encryptionKey"]; ok {
+		values.EncryptionKey = string(encryptionKey)
+	}
+
+	if signingSecret, ok := secret.Data["signingSecret"]; ok {
+		values.SigningSecret = string(signingSecret)
+	}
+
+	if clientSecret, ok := secret.Data["clientSecret"]; ok {
+		values.ClientSecret = string(clientSecret)
+	}
+
+	if credentials, ok := secret.Data["credentials"]; ok {
+		values.Credentials = string(credentials)
+	}
+
 	return values, nil
 }
 
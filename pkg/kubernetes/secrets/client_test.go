@@ -29,6 +29,9 @@ func newFakeClient() v1.SecretInterface {
 			"accessKeyID":     []byte("accessKeyID"),
 			"secretAccessKey": []byte("secretAccessKey"),
 			"token":           []byte("token"),
+			"database":        []byte("database"),
+			"dsn":             []byte("postgres://localhost:5432/database"),
+			"encryptionKey":   []byte("encryption-key"),
 		},
 	}).CoreV1().Secrets("default")
 }
@@ -69,6 +72,18 @@ func Test_Client(t *testing.T) {
 		if values.Token != "token" {
 			t.Errorf("Unexpected Token: %s", values.Token)
 		}
+
+		if values.Database != "database" {
+			t.Errorf("Unexpected Database: %s", values.Database)
+		}
+
+		if values.DSN != "postgres://localhost:5432/database" {
+			t.Errorf("Unexpected DSN: %s", values.DSN)
+		}
+
+		if values.EncryptionKey != "encryption-key" {
+			t.Errorf("Unexpected EncryptionKey: %s", values.EncryptionKey)
+		}
 	})
 
 	t.Run("Get values from not existing secret", func(t *testing.T) {
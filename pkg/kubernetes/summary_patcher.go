@@ -0,0 +1,46 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	pr "github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// SummaryPatcher corrects the summary of PolicyReports and ClusterPolicyReports in the cluster
+type SummaryPatcher struct {
+	client  dynamic.Interface
+	timeout time.Duration
+}
+
+// Patch replaces the summary of the PolicyReport with a merge patch, ClusterPolicyReports have no namespace, failures are logged
+func (p *SummaryPatcher) Patch(namespace, name string, summary pr.PolicyReportSummary) {
+	patch, err := json.Marshal(map[string]interface{}{"summary": summary})
+	if err != nil {
+		logger.Errorf("failed to create summary patch: %s", err)
+		return
+	}
+
+	var resource dynamic.ResourceInterface = p.client.Resource(pr.SchemeGroupVersion.WithResource("clusterpolicyreports"))
+	if namespace != "" {
+		resource = p.client.Resource(pr.SchemeGroupVersion.WithResource("policyreports")).Namespace(namespace)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	_, err = resource.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{FieldManager: "policy-reporter"})
+	if err != nil {
+		logger.Errorf("failed to patch the summary of report %s in namespace '%s': %s", name, namespace, err)
+	}
+}
+
+// NewSummaryPatcher creates a SummaryPatcher, each patch is canceled after the timeout
+func NewSummaryPatcher(client dynamic.Interface, timeout time.Duration) *SummaryPatcher {
+	return &SummaryPatcher{client: client, timeout: timeout}
+}
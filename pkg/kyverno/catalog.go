@@ -0,0 +1,223 @@
+package kyverno
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Rule types of a Kyverno policy
+const (
+	RuleValidate     = "validate"
+	RuleMutate       = "mutate"
+	RuleGenerate     = "generate"
+	RuleVerifyImages = "verifyImages"
+)
+
+// Rule of a policy
+type Rule struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Policy definition of a ClusterPolicy or Policy
+type Policy struct {
+	Kind                    string `json:"kind"`
+	Name                    string `json:"name"`
+	Namespace               string `json:"namespace,omitempty"`
+	Title                   string `json:"title,omitempty"`
+	Description             string `json:"description,omitempty"`
+	Category                string `json:"category,omitempty"`
+	Severity                string `json:"severity,omitempty"`
+	ValidationFailureAction string `json:"validationFailureAction"`
+	Background              bool   `json:"background"`
+	Ready                   bool   `json:"ready"`
+	Rules                   []Rule `json:"rules"`
+}
+
+// Key of the policy as used in the exceptions, namespace/name for Policies
+func (p Policy) Key() string {
+	return key(p.Namespace, p.Name)
+}
+
+// ExceptionPolicy is a policy and its rules exempted by an exception
+type ExceptionPolicy struct {
+	Policy string   `json:"policy"`
+	Rules  []string `json:"rules"`
+}
+
+// Exception of a PolicyException
+type Exception struct {
+	Name       string            `json:"name"`
+	Namespace  string            `json:"namespace"`
+	Background bool              `json:"background"`
+	Policies   []ExceptionPolicy `json:"policies"`
+}
+
+// Catalog of the Kyverno policies and exceptions of the cluster
+type Catalog struct {
+	annotations Annotations
+	mx          *sync.RWMutex
+	policies    map[string]Policy
+	exceptions  map[string]Exception
+}
+
+// SetPolicy adds or replaces the definition of a ClusterPolicy or Policy
+func (c *Catalog) SetPolicy(obj *unstructured.Unstructured) {
+	annotations := obj.GetAnnotations()
+
+	p := Policy{
+		Kind:                    obj.GetKind(),
+		Name:                    obj.GetName(),
+		Namespace:               obj.GetNamespace(),
+		Title:                   value(annotations, c.annotations.Title),
+		Description:             value(annotations, c.annotations.Description),
+		Category:                value(annotations, c.annotations.Category),
+		Severity:                strings.ToLower(value(annotations, c.annotations.Severity)),
+		ValidationFailureAction: "Audit",
+		Background:              true,
+		Rules:                   make([]Rule, 0),
+	}
+
+	if action, ok, _ := unstructured.NestedString(obj.Object, "spec", "validationFailureAction"); ok && action != "" {
+		p.ValidationFailureAction = action
+	}
+	if background, ok, _ := unstructured.NestedBool(obj.Object, "spec", "background"); ok {
+		p.Background = background
+	}
+	if ready, ok, _ := unstructured.NestedBool(obj.Object, "status", "ready"); ok {
+		p.Ready = ready
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, condition := range conditions {
+		if m, ok := condition.(map[string]interface{}); ok && m["type"] == "Ready" {
+			p.Ready = m["status"] == "True"
+		}
+	}
+
+	rules, _, _ := unstructured.NestedSlice(obj.Object, "spec", "rules")
+	for _, rule := range rules {
+		m, ok := rule.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name, _ := m["name"].(string)
+		p.Rules = append(p.Rules, Rule{Name: name, Type: ruleType(m)})
+	}
+
+	c.mx.Lock()
+	c.policies[p.Key()] = p
+	c.mx.Unlock()
+}
+
+// DeletePolicy removes the definition of a ClusterPolicy or Policy
+func (c *Catalog) DeletePolicy(namespace, name string) {
+	c.mx.Lock()
+	delete(c.policies, key(namespace, name))
+	c.mx.Unlock()
+}
+
+// SetException adds or replaces a PolicyException
+func (c *Catalog) SetException(obj *unstructured.Unstructured) {
+	e := Exception{
+		Name:       obj.GetName(),
+		Namespace:  obj.GetNamespace(),
+		Background: true,
+		Policies:   make([]ExceptionPolicy, 0),
+	}
+
+	if background, ok, _ := unstructured.NestedBool(obj.Object, "spec", "background"); ok {
+		e.Background = background
+	}
+
+	exceptions, _, _ := unstructured.NestedSlice(obj.Object, "spec", "exceptions")
+	for _, exception := range exceptions {
+		m, ok := exception.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		policy, _ := m["policyName"].(string)
+		rules, _, _ := unstructured.NestedStringSlice(m, "ruleNames")
+		if rules == nil {
+			rules = make([]string, 0)
+		}
+
+		e.Policies = append(e.Policies, ExceptionPolicy{Policy: policy, Rules: rules})
+	}
+
+	c.mx.Lock()
+	c.exceptions[key(e.Namespace, e.Name)] = e
+	c.mx.Unlock()
+}
+
+// DeleteException removes a PolicyException
+func (c *Catalog) DeleteException(namespace, name string) {
+	c.mx.Lock()
+	delete(c.exceptions, key(namespace, name))
+	c.mx.Unlock()
+}
+
+// Policies returns the ClusterPolicies and Policies ordered by namespace and name, ClusterPolicies first
+func (c *Catalog) Policies() []Policy {
+	c.mx.RLock()
+	list := make([]Policy, 0, len(c.policies))
+	for _, p := range c.policies {
+		list = append(list, p)
+	}
+	c.mx.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Namespace != list[j].Namespace {
+			return list[i].Namespace < list[j].Namespace
+		}
+
+		return list[i].Name < list[j].Name
+	})
+
+	return list
+}
+
+// Exceptions returns the PolicyExceptions ordered by namespace and name
+func (c *Catalog) Exceptions() []Exception {
+	c.mx.RLock()
+	list := make([]Exception, 0, len(c.exceptions))
+	for _, e := range c.exceptions {
+		list = append(list, e)
+	}
+	c.mx.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Namespace != list[j].Namespace {
+			return list[i].Namespace < list[j].Namespace
+		}
+
+		return list[i].Name < list[j].Name
+	})
+
+	return list
+}
+
+func ruleType(rule map[string]interface{}) string {
+	for _, t := range []string{RuleValidate, RuleMutate, RuleGenerate, RuleVerifyImages} {
+		if _, ok := rule[t]; ok {
+			return t
+		}
+	}
+
+	return ""
+}
+
+// NewCatalog creates an empty Catalog reading the title, description, category and severity from the given annotations
+func NewCatalog(annotations Annotations) *Catalog {
+	return &Catalog{
+		annotations: annotations,
+		mx:          new(sync.RWMutex),
+		policies:    make(map[string]Policy),
+		exceptions:  make(map[string]Exception),
+	}
+}
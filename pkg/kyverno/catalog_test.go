@@ -0,0 +1,74 @@
+package kyverno_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kyverno/policy-reporter/pkg/kyverno"
+)
+
+func newPolicy(kind, namespace, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetAnnotations(annotations)
+
+	return obj
+}
+
+func Test_Catalog(t *testing.T) {
+	t.Run("Policies", func(t *testing.T) {
+		catalog := kyverno.NewCatalog(kyverno.DefaultAnnotations)
+		catalog.SetPolicy(newPolicy("Policy", "default", "require-team", map[string]interface{}{}))
+		catalog.SetPolicy(newPolicy("ClusterPolicy", "", "require-labels", map[string]interface{}{
+			"validationFailureAction": "Enforce",
+			"background":              false,
+			"rules": []interface{}{
+				map[string]interface{}{"name": "check-app", "validate": map[string]interface{}{}},
+				map[string]interface{}{"name": "add-team", "mutate": map[string]interface{}{}},
+			},
+		}))
+
+		list := catalog.Policies()
+		if len(list) != 2 || list[0].Name != "require-labels" || list[1].Key() != "default/require-team" {
+			t.Fatalf("expected ClusterPolicy before Policy, got %+v", list)
+		}
+
+		p := list[0]
+		if p.ValidationFailureAction != "Enforce" || p.Background || p.Severity != "medium" || p.Title != "Require Labels" {
+			t.Errorf("unexpected policy %+v", p)
+		}
+		if len(p.Rules) != 2 || p.Rules[0].Type != kyverno.RuleValidate || p.Rules[1].Type != kyverno.RuleMutate {
+			t.Errorf("unexpected rules %+v", p.Rules)
+		}
+		if list[1].ValidationFailureAction != "Audit" || !list[1].Background {
+			t.Errorf("expected the defaults of Kyverno, got %+v", list[1])
+		}
+
+		catalog.DeletePolicy("default", "require-team")
+		if len(catalog.Policies()) != 1 {
+			t.Error("expected deleted Policy")
+		}
+	})
+
+	t.Run("Exceptions", func(t *testing.T) {
+		catalog := kyverno.NewCatalog(kyverno.DefaultAnnotations)
+		catalog.SetException(newPolicy("PolicyException", "kyverno", "allow-nginx", map[string]interface{}{
+			"exceptions": []interface{}{
+				map[string]interface{}{"policyName": "require-labels", "ruleNames": []interface{}{"check-app"}},
+			},
+		}))
+
+		list := catalog.Exceptions()
+		if len(list) != 1 || len(list[0].Policies) != 1 || list[0].Policies[0].Policy != "require-labels" || list[0].Policies[0].Rules[0] != "check-app" {
+			t.Fatalf("unexpected exceptions %+v", list)
+		}
+
+		catalog.DeleteException("kyverno", "allow-nginx")
+		if len(catalog.Exceptions()) != 0 {
+			t.Error("expected deleted exception")
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package kyverno
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+var (
+	ClusterPolicyGVR = schema.GroupVersionResource{Group: "kyverno.io", Version: "v1", Resource: "clusterpolicies"}
+	PolicyGVR        = schema.GroupVersionResource{Group: "kyverno.io", Version: "v1", Resource: "policies"}
+)
+
+// Watch keeps the store in sync with the ClusterPolicies and Policies of the cluster until the context is done
+func Watch(ctx context.Context, client dynamic.Interface, store *Store) error {
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(client, 0)
+
+	for _, gvr := range []schema.GroupVersionResource{ClusterPolicyGVR, PolicyGVR} {
+		_, err := factory.ForResource(gvr).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				if policy, ok := obj.(metav1.Object); ok {
+					store.Set(policy.GetNamespace(), policy.GetName(), policy.GetAnnotations())
+				}
+			},
+			UpdateFunc: func(_, obj interface{}) {
+				if policy, ok := obj.(metav1.Object); ok {
+					store.Set(policy.GetNamespace(), policy.GetName(), policy.GetAnnotations())
+				}
+			},
+			DeleteFunc: func(obj interface{}) {
+				if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = tombstone.Obj
+				}
+
+				if policy, ok := obj.(metav1.Object); ok {
+					store.Delete(policy.GetNamespace(), policy.GetName())
+				}
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	factory.Start(ctx.Done())
+	<-ctx.Done()
+
+	return nil
+}
+
+// Load the current ClusterPolicies and Policies once, the first error is returned after loading all available policies
+func Load(ctx context.Context, client dynamic.Interface, store *Store) error {
+	var first error
+
+	for _, gvr := range []schema.GroupVersionResource{ClusterPolicyGVR, PolicyGVR} {
+		list, err := client.Resource(gvr).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+
+		for _, item := range list.Items {
+			store.Set(item.GetNamespace(), item.GetName(), item.GetAnnotations())
+		}
+	}
+
+	return first
+}
+
+// DefaultExceptionVersion of the PolicyException API, served since Kyverno 1.11
+const DefaultExceptionVersion = "v2beta1"
+
+// ExceptionGVR of the PolicyExceptions in the given API version
+func ExceptionGVR(version string) schema.GroupVersionResource {
+	if version == "" {
+		version = DefaultExceptionVersion
+	}
+
+	return schema.GroupVersionResource{Group: "kyverno.io", Version: version, Resource: "policyexceptions"}
+}
+
+// WatchCatalog keeps the catalog in sync with the ClusterPolicies, Policies and PolicyExceptions of the cluster until the context is done
+func WatchCatalog(ctx context.Context, client dynamic.Interface, catalog *Catalog, exceptionVersion string) error {
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(client, 0)
+
+	handlers := map[schema.GroupVersionResource]catalogHandler{
+		ClusterPolicyGVR:               {catalog.SetPolicy, catalog.DeletePolicy},
+		PolicyGVR:                      {catalog.SetPolicy, catalog.DeletePolicy},
+		ExceptionGVR(exceptionVersion): {catalog.SetException, catalog.DeleteException},
+	}
+
+	for gvr, handler := range handlers {
+		if _, err := factory.ForResource(gvr).Informer().AddEventHandler(handler.funcs()); err != nil {
+			return err
+		}
+	}
+
+	factory.Start(ctx.Done())
+	<-ctx.Done()
+
+	return nil
+}
+
+// LoadCatalog loads the current ClusterPolicies, Policies and PolicyExceptions once,
+// the first error is returned after loading all available resources
+func LoadCatalog(ctx context.Context, client dynamic.Interface, catalog *Catalog, exceptionVersion string) error {
+	var first error
+
+	for gvr, set := range map[schema.GroupVersionResource]func(*unstructured.Unstructured){
+		ClusterPolicyGVR:               catalog.SetPolicy,
+		PolicyGVR:                      catalog.SetPolicy,
+		ExceptionGVR(exceptionVersion): catalog.SetException,
+	} {
+		list, err := client.Resource(gvr).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+
+		for i := range list.Items {
+			set(&list.Items[i])
+		}
+	}
+
+	return first
+}
+
+type catalogHandler struct {
+	set    func(*unstructured.Unstructured)
+	delete func(namespace, name string)
+}
+
+func (h catalogHandler) funcs() cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if u, ok := obj.(*unstructured.Unstructured); ok {
+				h.set(u)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if u, ok := obj.(*unstructured.Unstructured); ok {
+				h.set(u)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+
+			if u, ok := obj.(metav1.Object); ok {
+				h.delete(u.GetNamespace(), u.GetName())
+			}
+		},
+	}
+}
@@ -0,0 +1,188 @@
+// Package kyverno enriches the results of Kyverno policies with the metadata annotated on the ClusterPolicy and Policy objects,
+// e.g. description, remediation guidance, category, notification priority and a link to the documentation
+package kyverno
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// Annotations of the policy metadata, empty keys are ignored
+type Annotations struct {
+	Title       string
+	Description string
+	Remediation string
+	Category    string
+	Severity    string
+	Priority    string
+	URL         string
+}
+
+// DefaultAnnotations of the Kyverno policy library, remediation and URL are Policy Reporter specific
+var DefaultAnnotations = Annotations{
+	Title:       "policies.kyverno.io/title",
+	Description: "policies.kyverno.io/description",
+	Remediation: "policy-reporter.kyverno.io/remediation",
+	Category:    "policies.kyverno.io/category",
+	Severity:    "policies.kyverno.io/severity",
+	Priority:    "policy-reporter.kyverno.io/priority",
+	URL:         "policy-reporter.kyverno.io/url",
+}
+
+// Metadata of a policy
+type Metadata struct {
+	Title       string
+	Description string
+	Remediation string
+	Category    string
+	Severity    string
+	Priority    string
+	URL         string
+}
+
+func (m Metadata) empty() bool {
+	return m == Metadata{}
+}
+
+// Store of the policy metadata, ClusterPolicies are stored by name and Policies by namespace/name
+type Store struct {
+	annotations Annotations
+	mx          *sync.RWMutex
+	items       map[string]Metadata
+}
+
+// Set the metadata of a policy from its annotations, policies without metadata are removed
+func (s *Store) Set(namespace, name string, annotations map[string]string) {
+	m := Metadata{
+		Title:       value(annotations, s.annotations.Title),
+		Description: value(annotations, s.annotations.Description),
+		Remediation: value(annotations, s.annotations.Remediation),
+		Category:    value(annotations, s.annotations.Category),
+		Severity:    strings.ToLower(value(annotations, s.annotations.Severity)),
+		Priority:    strings.ToLower(value(annotations, s.annotations.Priority)),
+		URL:         value(annotations, s.annotations.URL),
+	}
+
+	if m.empty() {
+		s.Delete(namespace, name)
+		return
+	}
+
+	s.mx.Lock()
+	s.items[key(namespace, name)] = m
+	s.mx.Unlock()
+}
+
+// Delete the metadata of a policy
+func (s *Store) Delete(namespace, name string) {
+	s.mx.Lock()
+	delete(s.items, key(namespace, name))
+	s.mx.Unlock()
+}
+
+// Get the metadata of a policy, namespaced policies of a report are preferred over ClusterPolicies with the same name
+func (s *Store) Get(namespace, policy string) (Metadata, bool) {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	if namespace != "" && !strings.Contains(policy, "/") {
+		if m, ok := s.items[key(namespace, policy)]; ok {
+			return m, true
+		}
+	}
+
+	m, ok := s.items[policy]
+
+	return m, ok
+}
+
+// Enrich adds the metadata of the Kyverno policy to each result of the report,
+// category and severity are only set if the result has none. The priority annotation is also read
+// from the report labels for results of all sources, the priority of the policy takes precedence
+func (s *Store) Enrich(rep v1alpha2.ReportInterface) {
+	results := rep.GetResults()
+	priority := v1alpha2.NewPriority(strings.ToLower(value(rep.GetLabels(), s.annotations.Priority)))
+
+	for i := range results {
+		if priority != v1alpha2.DefaultPriority {
+			results[i].Priority = priority
+		}
+
+		source := results[i].Source
+		if source == "" {
+			source = rep.GetSource()
+		}
+		if !strings.EqualFold(source, "kyverno") {
+			continue
+		}
+
+		m, ok := s.Get(rep.GetNamespace(), results[i].Policy)
+		if !ok {
+			continue
+		}
+
+		Apply(&results[i], m)
+	}
+}
+
+// Apply the metadata to the result, a valid priority overrides the severity based priority mapping
+func Apply(result *v1alpha2.PolicyReportResult, m Metadata) {
+	if priority := v1alpha2.NewPriority(m.Priority); priority != v1alpha2.DefaultPriority {
+		result.Priority = priority
+	}
+	if result.Category == "" {
+		result.Category = m.Category
+	}
+	if result.Severity == "" {
+		switch m.Severity {
+		case v1alpha2.SeverityCritical, v1alpha2.SeverityHigh, v1alpha2.SeverityMedium, v1alpha2.SeverityLow, v1alpha2.SeverityInfo:
+			result.Severity = v1alpha2.PolicySeverity(m.Severity)
+		}
+	}
+
+	properties := map[string]string{
+		v1alpha2.PolicyTitleKey:       m.Title,
+		v1alpha2.PolicyDescriptionKey: m.Description,
+		v1alpha2.RemediationKey:       m.Remediation,
+		v1alpha2.PolicyURLKey:         m.URL,
+	}
+
+	for property, value := range properties {
+		if value == "" {
+			continue
+		}
+		if result.Properties == nil {
+			result.Properties = make(map[string]string)
+		}
+		if _, ok := result.Properties[property]; !ok {
+			result.Properties[property] = value
+		}
+	}
+}
+
+func value(annotations map[string]string, key string) string {
+	if key == "" {
+		return ""
+	}
+
+	return strings.TrimSpace(annotations[key])
+}
+
+func key(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+
+	return namespace + "/" + name
+}
+
+// NewStore creates an empty Store reading the given annotations
+func NewStore(annotations Annotations) *Store {
+	return &Store{
+		annotations: annotations,
+		mx:          new(sync.RWMutex),
+		items:       make(map[string]Metadata),
+	}
+}
@@ -0,0 +1,128 @@
+package kyverno_test
+
+import (
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/kyverno"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var annotations = map[string]string{
+	"policies.kyverno.io/title":              "Require Labels",
+	"policies.kyverno.io/description":        "Resources must define the app label.",
+	"policy-reporter.kyverno.io/remediation": "Add the app label to the resource.",
+	"policies.kyverno.io/category":           "Best Practices",
+	"policies.kyverno.io/severity":           "Medium",
+	"policy-reporter.kyverno.io/url":         "https://kyverno.io/policies/require-labels",
+}
+
+func Test_Store(t *testing.T) {
+	t.Run("Set and Get", func(t *testing.T) {
+		store := kyverno.NewStore(kyverno.DefaultAnnotations)
+		store.Set("", "require-labels", annotations)
+
+		m, ok := store.Get("default", "require-labels")
+		if !ok {
+			t.Fatal("expected metadata of the ClusterPolicy")
+		}
+		if m.Severity != "medium" {
+			t.Errorf("expected lower case severity, got %s", m.Severity)
+		}
+		if m.Remediation != "Add the app label to the resource." {
+			t.Errorf("unexpected remediation: %s", m.Remediation)
+		}
+	})
+
+	t.Run("Prefer namespaced Policy", func(t *testing.T) {
+		store := kyverno.NewStore(kyverno.DefaultAnnotations)
+		store.Set("", "require-labels", annotations)
+		store.Set("default", "require-labels", map[string]string{"policies.kyverno.io/description": "namespaced"})
+
+		if m, _ := store.Get("default", "require-labels"); m.Description != "namespaced" {
+			t.Errorf("expected namespaced policy, got %s", m.Description)
+		}
+		if m, _ := store.Get("test", "require-labels"); m.Description != annotations["policies.kyverno.io/description"] {
+			t.Errorf("expected ClusterPolicy, got %s", m.Description)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		store := kyverno.NewStore(kyverno.DefaultAnnotations)
+		store.Set("", "require-labels", annotations)
+		store.Delete("", "require-labels")
+
+		if _, ok := store.Get("", "require-labels"); ok {
+			t.Error("expected deleted metadata")
+		}
+	})
+
+	t.Run("Ignore policies without metadata", func(t *testing.T) {
+		store := kyverno.NewStore(kyverno.DefaultAnnotations)
+		store.Set("", "require-labels", map[string]string{"other": "value"})
+
+		if _, ok := store.Get("", "require-labels"); ok {
+			t.Error("expected no metadata")
+		}
+	})
+}
+
+func Test_Enrich(t *testing.T) {
+	store := kyverno.NewStore(kyverno.DefaultAnnotations)
+	store.Set("", "require-labels", annotations)
+
+	rep := &v1alpha2.PolicyReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "polr", Namespace: "default"},
+		Results: []v1alpha2.PolicyReportResult{
+			{Source: "Kyverno", Policy: "require-labels", Severity: v1alpha2.SeverityHigh, Properties: map[string]string{v1alpha2.RemediationKey: "custom"}},
+			{Source: "Trivy", Policy: "require-labels"},
+		},
+	}
+
+	store.Enrich(rep)
+
+	result := rep.Results[0]
+	if result.Category != "Best Practices" {
+		t.Errorf("expected category from annotation, got %s", result.Category)
+	}
+	if result.Severity != v1alpha2.SeverityHigh {
+		t.Errorf("expected severity of the result to be kept, got %s", result.Severity)
+	}
+	if result.Properties[v1alpha2.RemediationKey] != "custom" {
+		t.Errorf("expected existing property to be kept, got %s", result.Properties[v1alpha2.RemediationKey])
+	}
+	if result.Properties[v1alpha2.PolicyURLKey] != "https://kyverno.io/policies/require-labels" {
+		t.Errorf("unexpected url: %s", result.Properties[v1alpha2.PolicyURLKey])
+	}
+
+	if other := rep.Results[1]; other.Category != "" || len(other.Properties) > 0 {
+		t.Error("expected results of other sources to be unchanged")
+	}
+}
+
+func Test_EnrichPriority(t *testing.T) {
+	store := kyverno.NewStore(kyverno.DefaultAnnotations)
+	store.Set("", "require-labels", map[string]string{"policy-reporter.kyverno.io/priority": "Critical"})
+	store.Set("", "disallow-latest", map[string]string{"policy-reporter.kyverno.io/priority": "unknown"})
+
+	rep := &v1alpha2.PolicyReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "polr", Namespace: "default", Labels: map[string]string{"policy-reporter.kyverno.io/priority": "warning"}},
+		Results: []v1alpha2.PolicyReportResult{
+			{Source: "Kyverno", Policy: "require-labels", Severity: v1alpha2.SeverityLow},
+			{Source: "Kyverno", Policy: "disallow-latest"},
+			{Source: "Trivy", Policy: "CVE-2022-0001"},
+		},
+	}
+
+	store.Enrich(rep)
+
+	if p := rep.Results[0].Priority; p != v1alpha2.CriticalPriority {
+		t.Errorf("expected priority of the policy annotation, got %s", p)
+	}
+	if p := rep.Results[1].Priority; p != v1alpha2.WarningPriority {
+		t.Errorf("expected invalid policy priority to fall back to the report label, got %s", p)
+	}
+	if p := rep.Results[2].Priority; p != v1alpha2.WarningPriority {
+		t.Errorf("expected priority of the report label for other sources, got %s", p)
+	}
+}
@@ -0,0 +1,8 @@
+package kyverno
+
+// SourceLabel of the intermediate reports Kyverno creates for admission requests and background scans,
+// their results are aggregated into the final PolicyReports, so processing both counts each result twice
+const SourceLabel = "audit.kyverno.io/source"
+
+// InterimLabels identify the intermediate reports of Kyverno, final reports have none of these labels
+var InterimLabels = []string{SourceLabel}
@@ -3,12 +3,15 @@ package leaderelection
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/client-go/kubernetes/typed/coordination/v1"
 	k8sleaderelection "k8s.io/client-go/tools/leaderelection"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"github.com/kyverno/policy-reporter/pkg/health"
 )
 
 type Client struct {
@@ -24,6 +27,17 @@ type Client struct {
 	onStartedLeading func(c context.Context)
 	onStoppedLeading func()
 	onNewLeader      func(currentID, lockID string)
+
+	mx     sync.RWMutex
+	leader string
+}
+
+// Leader returns the identity of the current leader and if this instance is the leader
+func (c *Client) Leader() (string, bool) {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	return c.leader, c.leader != "" && c.leader == c.identity
 }
 
 func (c *Client) RegisterOnStart(callback func(c context.Context)) *Client {
@@ -55,11 +69,19 @@ func (c *Client) Run(ctx context.Context) error {
 			OnStartedLeading: c.onStartedLeading,
 			OnStoppedLeading: c.onStoppedLeading,
 			OnNewLeader: func(identity string) {
+				c.mx.Lock()
+				c.leader = identity
+				c.mx.Unlock()
+
 				c.onNewLeader(identity, c.identity)
 			},
 		},
 	})
 
+	if ctx.Err() != nil {
+		return nil
+	}
+
 	return errors.New("leaderelection stopped")
 }
 
@@ -87,16 +109,28 @@ func New(
 	releaseOnCancel bool,
 ) *Client {
 	return &Client{
-		client,
-		lockName,
-		namespace,
-		identity,
-		leaseDuration,
-		renewDeadline,
-		retryPeriod,
-		releaseOnCancel,
-		func(c context.Context) {},
-		func() {},
-		func(currentID, lockID string) {},
+		client:           client,
+		lockName:         lockName,
+		namespace:        namespace,
+		identity:         identity,
+		leaseDuration:    leaseDuration,
+		renewDeadline:    renewDeadline,
+		retryPeriod:      retryPeriod,
+		releaseOnCancel:  releaseOnCancel,
+		onStartedLeading: func(c context.Context) {},
+		onStoppedLeading: func() {},
+		onNewLeader:      func(currentID, lockID string) {},
+	}
+}
+
+// Check is degraded as long as no leader is elected
+func (c *Client) Check(_ context.Context) health.Component {
+	leader, isLeader := c.Leader()
+
+	details := map[string]interface{}{"leader": leader, "isLeader": isLeader}
+	if leader == "" {
+		return health.Component{Status: health.Degraded, Message: "no leader elected", Details: details}
 	}
+
+	return health.Component{Status: health.OK, Details: details}
 }
@@ -0,0 +1,35 @@
+package listener_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/cache"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/listener"
+	"github.com/kyverno/policy-reporter/pkg/loadgen"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+// BenchmarkResultListener processes updates of reports with 50 results, about a tenth of the results changed
+func BenchmarkResultListener(b *testing.B) {
+	options := loadgen.Options{Reports: 1000, Results: 50}
+
+	reports := make([]report.LifecycleEvent, 0, 2*options.Reports)
+	for i := 0; i < options.Reports; i++ {
+		reports = append(reports,
+			report.LifecycleEvent{Type: report.Added, PolicyReport: options.Report(i, 0)},
+			report.LifecycleEvent{Type: report.Updated, PolicyReport: options.Report(i, 1)},
+		)
+	}
+
+	l := listener.NewResultListener(false, cache.NewInMermoryCache(), time.Now().Add(-time.Hour))
+	l.RegisterListener(func(_ v1alpha2.ReportInterface, _ v1alpha2.PolicyReportResult, _ bool) {})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		l.Listen(reports[i%len(reports)])
+	}
+}
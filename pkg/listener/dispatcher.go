@@ -0,0 +1,204 @@
+package listener
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/kyverno/policy-reporter/pkg/cache"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/listener/metrics"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/tracing"
+)
+
+// DispatcherOptions of the target delivery queues
+type DispatcherOptions struct {
+	// Workers per target without a configured concurrency
+	Workers int
+	// QueueSize per target, Dispatch blocks while the queue of a target is full, 0 blocks until a worker is free.
+	// Queued results are delivered by priority, results with the same priority in the order they were dispatched
+	QueueSize int
+	// Notified skips results already sent to a target within its TTL, optional
+	Notified *cache.Notified
+}
+
+type targetQueue struct {
+	client target.Client
+	queue  *priorityQueue
+}
+
+// Dispatcher delivers results to the targets with a bounded worker pool per target
+type Dispatcher struct {
+	queues  []*targetQueue
+	mapper  report.Mapper
+	metrics metrics.DispatcherMetrics
+	options DispatcherOptions
+	mx      *sync.RWMutex
+	closed  bool
+	wg      *sync.WaitGroup
+}
+
+// Dispatch validates the result for each target and adds it to the target queues, blocks while a queue is full
+func (d *Dispatcher) Dispatch(rep v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult, preExisted bool) {
+	span := tracing.Start(tracing.Extract(r.TraceParent), "result.dispatch")
+	span.SetAttributes(attribute.String("result.policy", r.Policy))
+	defer span.End()
+
+	r.TraceParent = tracing.TraceParent(span.SpanContext())
+
+	d.mx.RLock()
+	defer d.mx.RUnlock()
+
+	var targets int
+
+	for _, q := range d.queues {
+		result, ok := prepareResult(q.client, d.mapper, rep, r, preExisted)
+		if !ok {
+			continue
+		}
+		if d.options.Notified != nil && d.options.Notified.Has(q.client.Name(), result.GetID()) {
+			continue
+		}
+		if !sample(q.client, result) {
+			d.metrics.Sampled.WithLabelValues(q.client.Name(), result.Policy).Inc()
+			continue
+		}
+		if group(q.client, result) {
+			if d.options.Notified != nil {
+				d.options.Notified.Add(q.client.Name(), result.GetID())
+			}
+			continue
+		}
+
+		targets++
+
+		if d.closed {
+			d.metrics.Dropped.WithLabelValues(q.client.Name()).Inc()
+			continue
+		}
+
+		if q.queue.Push(result) {
+			d.metrics.Blocked.WithLabelValues(q.client.Name()).Inc()
+		}
+
+		d.metrics.QueueLength.WithLabelValues(q.client.Name()).Set(float64(q.queue.Len()))
+	}
+
+	span.SetAttributes(attribute.Int("result.targets", targets))
+}
+
+func (d *Dispatcher) work(q *targetQueue) {
+	defer d.wg.Done()
+
+	for {
+		result, ok := q.queue.Pop()
+		if !ok {
+			return
+		}
+
+		d.metrics.QueueLength.WithLabelValues(q.client.Name()).Set(float64(q.queue.Len()))
+
+		start := time.Now()
+		send(q.client, result)
+		d.metrics.SendLatency.WithLabelValues(q.client.Name()).Observe(time.Since(start).Seconds())
+
+		if d.options.Notified != nil {
+			d.options.Notified.Add(q.client.Name(), result.GetID())
+		}
+	}
+}
+
+// Shutdown stops accepting results and waits until the queued results are delivered or the context is done
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	d.mx.Lock()
+	if !d.closed {
+		d.closed = true
+		for _, q := range d.queues {
+			q.queue.Close()
+		}
+	}
+	d.mx.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Update replaces the targets, results already queued for the previous targets are still delivered by their workers
+func (d *Dispatcher) Update(clients []target.Client) {
+	queues := d.start(clients)
+
+	d.mx.Lock()
+	if d.closed {
+		d.mx.Unlock()
+		for _, q := range queues {
+			q.queue.Close()
+		}
+		return
+	}
+
+	previous := d.queues
+	d.queues = queues
+	d.mx.Unlock()
+
+	for _, q := range previous {
+		q.queue.Close()
+	}
+}
+
+// start creates the queues and workers of the clients, a target concurrency of zero falls back to the default workers
+func (d *Dispatcher) start(clients []target.Client) []*targetQueue {
+	queues := make([]*targetQueue, 0, len(clients))
+
+	for _, client := range clients {
+		q := &targetQueue{client: client, queue: newPriorityQueue(d.options.QueueSize)}
+		queues = append(queues, q)
+
+		workers := client.Concurrency()
+		if workers <= 0 {
+			workers = d.options.Workers
+		}
+
+		d.wg.Add(workers)
+		for i := 0; i < workers; i++ {
+			go d.work(q)
+		}
+	}
+
+	return queues
+}
+
+// NewDispatcher starts the workers of each target
+func NewDispatcher(clients []target.Client, mapper report.Mapper, m metrics.DispatcherMetrics, options DispatcherOptions) *Dispatcher {
+	if options.Workers <= 0 {
+		options.Workers = 1
+	}
+	if options.QueueSize < 0 {
+		options.QueueSize = 0
+	}
+
+	d := &Dispatcher{
+		mapper:  mapper,
+		metrics: m,
+		options: options,
+		mx:      new(sync.RWMutex),
+		wg:      new(sync.WaitGroup),
+	}
+
+	d.queues = d.start(clients)
+
+	return d
+}
@@ -0,0 +1,308 @@
+package listener_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kyverno/policy-reporter/pkg/cache"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/listener"
+	"github.com/kyverno/policy-reporter/pkg/listener/metrics"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/tracing"
+)
+
+type countingClient struct {
+	client
+	mx    *sync.Mutex
+	sent  int
+	delay time.Duration
+}
+
+func (c *countingClient) Send(result v1alpha2.PolicyReportResult) {
+	time.Sleep(c.delay)
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.sent++
+}
+
+func (c *countingClient) Sent() int {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	return c.sent
+}
+
+type orderingClient struct {
+	client
+	started chan struct{}
+	release chan struct{}
+	mx      *sync.Mutex
+	sent    []v1alpha2.PolicySeverity
+}
+
+func (c *orderingClient) Send(result v1alpha2.PolicyReportResult) {
+	c.started <- struct{}{}
+	<-c.release
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.sent = append(c.sent, result.Severity)
+}
+
+type tracingClient struct {
+	client
+	traceParent chan string
+}
+
+func (c *tracingClient) Send(result v1alpha2.PolicyReportResult) {
+	c.traceParent <- result.TraceParent
+}
+
+func Test_DispatcherTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracing.Use(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer tracing.Use(trace.NewNoopTracerProvider())
+
+	parent := tracing.Start(trace.SpanContext{}, "report.results")
+
+	result := fixtures.FailResult
+	result.TraceParent = tracing.TraceParent(parent.SpanContext())
+
+	c := &tracingClient{client: client{validated: true}, traceParent: make(chan string, 1)}
+
+	dispatcher := listener.NewDispatcher([]target.Client{c}, report.NewMapper(make(map[string]string)), metrics.RegisterDispatcherMetrics(), listener.DispatcherOptions{Workers: 1})
+	dispatcher.Dispatch(preport1, result, false)
+	dispatcher.Shutdown(context.Background())
+
+	sent := tracing.Extract(<-c.traceParent)
+	if !sent.IsValid() || sent.TraceID() != parent.SpanContext().TraceID() || sent.SpanID() == parent.SpanContext().SpanID() {
+		t.Fatalf("expected the traceparent of the delivery span within the trace, got %v", sent)
+	}
+
+	names := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		names[span.Name()] = span
+	}
+
+	if dispatch, ok := names["result.dispatch"]; !ok || dispatch.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("expected dispatch span as child of the result span")
+	}
+
+	send, ok := names["target.send"]
+	if !ok || send.SpanContext().SpanID() != sent.SpanID() || send.SpanKind() != trace.SpanKindClient {
+		t.Fatalf("expected ended delivery span, got %v", names)
+	}
+	if attributes := send.Attributes(); len(attributes) != 1 || attributes[0] != attribute.String("target.name", c.Name()) {
+		t.Errorf("unexpected attributes of the delivery span: %v", attributes)
+	}
+}
+
+func Test_Dispatcher(t *testing.T) {
+	options := listener.DispatcherOptions{Workers: 2, QueueSize: 1}
+
+	t.Run("Deliver queued results on shutdown", func(t *testing.T) {
+		c := &countingClient{client: client{validated: true}, mx: new(sync.Mutex), delay: 10 * time.Millisecond}
+
+		dispatcher := listener.NewDispatcher([]target.Client{c}, report.NewMapper(make(map[string]string)), metrics.RegisterDispatcherMetrics(), options)
+		for i := 0; i < 5; i++ {
+			dispatcher.Dispatch(preport1, fixtures.FailResult, false)
+		}
+
+		if err := dispatcher.Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if c.Sent() != 5 {
+			t.Errorf("Expected 5 delivered results, got %d", c.Sent())
+		}
+	})
+	t.Run("Deliver results by priority", func(t *testing.T) {
+		c := &orderingClient{client: client{validated: true}, started: make(chan struct{}, 5), release: make(chan struct{}), mx: new(sync.Mutex)}
+
+		dispatcher := listener.NewDispatcher([]target.Client{c}, report.NewMapper(make(map[string]string)), metrics.RegisterDispatcherMetrics(), listener.DispatcherOptions{Workers: 1, QueueSize: 10})
+
+		// the first result blocks the worker until the others are queued
+		for i, severity := range []v1alpha2.PolicySeverity{v1alpha2.SeverityLow, v1alpha2.SeverityInfo, v1alpha2.SeverityMedium, v1alpha2.SeverityCritical, v1alpha2.SeverityInfo} {
+			result := fixtures.FailResult
+			result.Severity = severity
+			result.Priority = v1alpha2.DefaultPriority
+			result.ID = string(severity)
+
+			dispatcher.Dispatch(preport1, result, false)
+			if i == 0 {
+				<-c.started
+			}
+		}
+
+		close(c.release)
+		if err := dispatcher.Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []v1alpha2.PolicySeverity{v1alpha2.SeverityLow, v1alpha2.SeverityCritical, v1alpha2.SeverityMedium, v1alpha2.SeverityInfo, v1alpha2.SeverityInfo}
+		if len(c.sent) != len(expected) {
+			t.Fatalf("Expected %d delivered results, got %v", len(expected), c.sent)
+		}
+		for i, severity := range expected {
+			if c.sent[i] != severity {
+				t.Errorf("Expected delivery order %v, got %v", expected, c.sent)
+				break
+			}
+		}
+	})
+	t.Run("Block until a worker is free without queue", func(t *testing.T) {
+		c := &orderingClient{client: client{validated: true}, started: make(chan struct{}, 2), release: make(chan struct{}), mx: new(sync.Mutex)}
+
+		dispatcher := listener.NewDispatcher([]target.Client{c}, report.NewMapper(make(map[string]string)), metrics.RegisterDispatcherMetrics(), listener.DispatcherOptions{Workers: 1, QueueSize: 0})
+
+		dispatcher.Dispatch(preport1, fixtures.FailResult, false)
+		<-c.started
+
+		done := make(chan struct{})
+		go func() {
+			dispatcher.Dispatch(preport1, fixtures.FailResult, false)
+			close(done)
+		}()
+
+		select {
+		case <-done:
+			t.Fatal("Expected Dispatch to block while the worker is busy")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(c.release)
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Expected Dispatch to return after the worker was freed")
+		}
+
+		if err := dispatcher.Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if len(c.sent) != 2 {
+			t.Errorf("Expected 2 delivered results, got %d", len(c.sent))
+		}
+	})
+	t.Run("Skip invalid results", func(t *testing.T) {
+		c := &countingClient{client: client{validated: false}, mx: new(sync.Mutex)}
+
+		dispatcher := listener.NewDispatcher([]target.Client{c}, report.NewMapper(make(map[string]string)), metrics.RegisterDispatcherMetrics(), options)
+		dispatcher.Dispatch(preport1, fixtures.FailResult, false)
+		dispatcher.Shutdown(context.Background())
+
+		if c.Sent() != 0 {
+			t.Error("Expected Send not to be called")
+		}
+	})
+	t.Run("Drop results after shutdown", func(t *testing.T) {
+		c := &countingClient{client: client{validated: true}, mx: new(sync.Mutex)}
+
+		dispatcher := listener.NewDispatcher([]target.Client{c}, report.NewMapper(make(map[string]string)), metrics.RegisterDispatcherMetrics(), options)
+		dispatcher.Shutdown(context.Background())
+		dispatcher.Dispatch(preport1, fixtures.FailResult, false)
+
+		if c.Sent() != 0 {
+			t.Error("Expected Send not to be called after shutdown")
+		}
+	})
+	t.Run("Update targets", func(t *testing.T) {
+		previous := &countingClient{client: client{validated: true}, mx: new(sync.Mutex), delay: 10 * time.Millisecond}
+		next := &countingClient{client: client{validated: true}, mx: new(sync.Mutex)}
+
+		dispatcher := listener.NewDispatcher([]target.Client{previous}, report.NewMapper(make(map[string]string)), metrics.RegisterDispatcherMetrics(), options)
+		dispatcher.Dispatch(preport1, fixtures.FailResult, false)
+		dispatcher.Update([]target.Client{next})
+		dispatcher.Dispatch(preport1, fixtures.FailResult, false)
+
+		if err := dispatcher.Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if previous.Sent() != 1 {
+			t.Errorf("Expected the queued result to be delivered to the previous target, got %d", previous.Sent())
+		}
+		if next.Sent() != 1 {
+			t.Errorf("Expected 1 delivered result to the updated target, got %d", next.Sent())
+		}
+	})
+	t.Run("Shutdown timeout", func(t *testing.T) {
+		c := &countingClient{client: client{validated: true}, mx: new(sync.Mutex), delay: 200 * time.Millisecond}
+
+		dispatcher := listener.NewDispatcher([]target.Client{c}, report.NewMapper(make(map[string]string)), metrics.RegisterDispatcherMetrics(), options)
+		dispatcher.Dispatch(preport1, fixtures.FailResult, false)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		if err := dispatcher.Shutdown(ctx); err == nil {
+			t.Error("Expected a timeout error")
+		}
+	})
+	t.Run("Skip already notified results", func(t *testing.T) {
+		c := &countingClient{client: client{validated: true}, mx: new(sync.Mutex)}
+		notified := cache.NewNotified(func(string) time.Duration { return time.Hour }, cache.NewInMemoryDeduplicator(time.Hour))
+
+		dispatcher := listener.NewDispatcher([]target.Client{c}, report.NewMapper(make(map[string]string)), metrics.RegisterDispatcherMetrics(), listener.DispatcherOptions{Workers: 1, Notified: notified})
+		dispatcher.Dispatch(preport1, fixtures.FailResult, false)
+		if err := dispatcher.Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if !notified.Has(c.Name(), fixtures.FailResult.GetID()) {
+			t.Error("Expected sent result to be notified")
+		}
+
+		dispatcher = listener.NewDispatcher([]target.Client{c}, report.NewMapper(make(map[string]string)), metrics.RegisterDispatcherMetrics(), listener.DispatcherOptions{Workers: 1, Notified: notified})
+		dispatcher.Dispatch(preport1, fixtures.FailResult, false)
+		dispatcher.Shutdown(context.Background())
+
+		if c.Sent() != 1 {
+			t.Errorf("Expected notified result not to be sent again, got %d", c.Sent())
+		}
+	})
+	t.Run("Sample results per policy", func(t *testing.T) {
+		c := &countingClient{client: client{validated: true, sampler: target.NewSampler([]target.SamplingRule{{Limit: 1, Window: time.Hour}})}, mx: new(sync.Mutex)}
+
+		dispatcher := listener.NewDispatcher([]target.Client{c}, report.NewMapper(make(map[string]string)), metrics.RegisterDispatcherMetrics(), options)
+		for i := 0; i < 3; i++ {
+			dispatcher.Dispatch(preport1, fixtures.FailResult, false)
+		}
+		dispatcher.Shutdown(context.Background())
+
+		if c.Sent() != 1 {
+			t.Errorf("Expected 1 delivered result within the sampling limit, got %d", c.Sent())
+		}
+	})
+	t.Run("Group results", func(t *testing.T) {
+		c := &countingClient{client: client{validated: true, grouper: target.NewGrouper([]string{"policy"}, 20*time.Millisecond)}, mx: new(sync.Mutex)}
+
+		second := fixtures.FailResult
+		second.ID = "second"
+
+		dispatcher := listener.NewDispatcher([]target.Client{c}, report.NewMapper(make(map[string]string)), metrics.RegisterDispatcherMetrics(), options)
+		dispatcher.Dispatch(preport1, fixtures.FailResult, false)
+		dispatcher.Dispatch(preport1, second, false)
+		dispatcher.Shutdown(context.Background())
+
+		if c.Sent() != 0 {
+			t.Errorf("Expected no delivery within the grouping window, got %d", c.Sent())
+		}
+
+		time.Sleep(50 * time.Millisecond)
+
+		if c.Sent() != 1 {
+			t.Errorf("Expected 1 grouped delivery, got %d", c.Sent())
+		}
+	})
+}
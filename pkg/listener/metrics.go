@@ -1,18 +1,27 @@
 package listener
 
 import (
-	"strings"
-
 	"github.com/kyverno/policy-reporter/pkg/listener/metrics"
+	"github.com/kyverno/policy-reporter/pkg/logging"
 	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/score"
 )
 
+var logger = logging.Component(logging.Listener)
+
 var (
 	ResultGaugeName        = "policy_report_result"
 	ClusterResultGaugeName = "cluster_policy_report_result"
+	ResultCounterName      = "policy_report_results_total"
 )
 
-const Metrics = "metric_listener"
+const (
+	Metrics         = "metric_listener"
+	MetricsPipeline = "metric_pipeline_listener"
+	MetricsExemplar = "metric_exemplar_listener"
+	MetricsScore    = "metric_score_listener"
+	ProcessingLag   = "processing_lag_listener"
+)
 
 // NewMetricsListener for PolicyReport watch.Events
 func NewMetricsListener(
@@ -32,6 +41,36 @@ func NewMetricsListener(
 	}
 }
 
+// NewMetricsPipelineListener for the configured result gauges, invalid pipelines are skipped
+func NewMetricsPipelineListener(filter *report.ResultFilter, pipelines []metrics.Pipeline) report.PolicyReportListener {
+	listeners := make([]report.PolicyReportListener, 0, len(pipelines))
+	for _, p := range pipelines {
+		l, err := metrics.CreatePipelineMetricsListener(filter, p)
+		if err != nil {
+			logger.Errorf("skip metric %s: %s", p.Name, err)
+			continue
+		}
+
+		listeners = append(listeners, l)
+	}
+
+	return func(event report.LifecycleEvent) {
+		for _, l := range listeners {
+			l(event)
+		}
+	}
+}
+
+// NewResultCounterListener counts new results with their result ID as exemplar
+func NewResultCounterListener(filter *report.ResultFilter) report.PolicyReportListener {
+	return metrics.CreateResultCounterListener(filter, metrics.RegisterResultCounter(ResultCounterName))
+}
+
+// NewScoreListener sets the compliance score gauges of the cluster, each namespace and each category
+func NewScoreListener(filter *report.ResultFilter, weights score.Weights) report.PolicyReportListener {
+	return metrics.CreateScoreListener(filter, metrics.RegisterScoreGauges(), weights)
+}
+
 func ResultListeners(
 	filter *report.ResultFilter,
 	reportFilter *report.ReportFilter,
@@ -62,11 +101,7 @@ func ResultListeners(
 		labelNames := make([]string, 0, len(labels))
 
 		for _, label := range labels {
-			labelName := label
-			if strings.HasPrefix(label, metrics.ReportLabelPrefix) {
-				replacer := strings.NewReplacer(".", "_", "/", "_", ":", "_", "-", "_", ";", "_")
-				labelName = replacer.Replace(strings.TrimPrefix(label, metrics.ReportLabelPrefix))
-			}
+			labelName := metrics.LabelName(label)
 
 			labelNames = append(labelNames, labelName)
 
@@ -0,0 +1,20 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// APICacheMetrics of the API response cache
+type APICacheMetrics struct {
+	Requests *prometheus.CounterVec
+}
+
+// RegisterAPICacheMetrics registers the hit and miss counter of the API response cache, existing metrics are reused
+func RegisterAPICacheMetrics() APICacheMetrics {
+	return APICacheMetrics{
+		Requests: registerGauge(prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "policy_reporter_api_cache_requests_total",
+			Help: "Requests of cached aggregate APIs by endpoint and result hit or miss",
+		}, []string{"endpoint", "result"})).(*prometheus.CounterVec),
+	}
+}
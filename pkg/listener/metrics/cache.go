@@ -25,11 +25,14 @@ type Cache struct {
 func (c *Cache) AddReport(polr v1alpha2.ReportInterface) {
 	labels := map[string]*CacheItem{}
 	for _, res := range polr.GetResults() {
-		if !c.filter.Validate(res) {
+		if !c.filter.ValidateReportResult(polr, res) {
 			continue
 		}
 
 		l := c.labelGenerator(polr, res)
+		if l == nil {
+			continue
+		}
 
 		hash := labelHash(l)
 
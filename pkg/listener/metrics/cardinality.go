@@ -0,0 +1,192 @@
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/kyverno/policy-reporter/pkg/logging"
+)
+
+var logger = logging.Component(logging.Listener)
+
+// cardinalityPrefix of the meta metrics, which are never degraded
+const cardinalityPrefix = "policy_reporter_metric_"
+
+// CardinalityMetrics expose the active series per metric family and the labels dropped by the guard
+type CardinalityMetrics struct {
+	Series  *prometheus.GaugeVec
+	Dropped *prometheus.GaugeVec
+}
+
+// RegisterCardinalityMetrics registers the meta metrics of the cardinality guard, existing metrics are reused
+func RegisterCardinalityMetrics() CardinalityMetrics {
+	return CardinalityMetrics{
+		Series: registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: cardinalityPrefix + "series",
+			Help: "Active series per metric family before labels were dropped by the cardinality guard",
+		}, []string{"metric"})).(*prometheus.GaugeVec),
+		Dropped: registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: cardinalityPrefix + "dropped_labels",
+			Help: "Labels dropped from a metric family because it exceeded the series limit",
+		}, []string{"metric", "label"})).(*prometheus.GaugeVec),
+	}
+}
+
+type cardinalityGuard struct {
+	gatherer prometheus.Gatherer
+	limit    int
+	metrics  CardinalityMetrics
+	mx       *sync.Mutex
+	dropped  map[string][]string
+}
+
+func (g *cardinalityGuard) Gather() ([]*dto.MetricFamily, error) {
+	families, err := g.gatherer.Gather()
+
+	g.mx.Lock()
+	defer g.mx.Unlock()
+
+	g.metrics.Series.Reset()
+	dropped := make(map[string][]string)
+
+	for _, family := range families {
+		name := family.GetName()
+		if strings.HasPrefix(name, cardinalityPrefix) {
+			continue
+		}
+
+		g.metrics.Series.WithLabelValues(name).Set(float64(len(family.GetMetric())))
+
+		if len(family.GetMetric()) <= g.limit || !aggregatable(family.GetType()) {
+			continue
+		}
+
+		labels := make([]string, 0)
+		for len(family.GetMetric()) > g.limit {
+			label := highestCardinality(family.GetMetric())
+			if label == "" {
+				break
+			}
+
+			family.Metric = dropLabel(family.GetMetric(), label, family.GetType())
+			labels = append(labels, label)
+		}
+
+		dropped[name] = labels
+	}
+
+	for name, labels := range dropped {
+		if strings.Join(g.dropped[name], ",") != strings.Join(labels, ",") {
+			logger.Warnf("metric %s exceeds the limit of %d series, dropped the labels %s", name, g.limit, strings.Join(labels, ", "))
+		}
+	}
+	for name := range g.dropped {
+		if _, ok := dropped[name]; !ok {
+			logger.Infof("metric %s is within the limit of %d series again, all labels are exposed", name, g.limit)
+		}
+	}
+
+	g.metrics.Dropped.Reset()
+	for name, labels := range dropped {
+		for _, label := range labels {
+			g.metrics.Dropped.WithLabelValues(name, label).Set(1)
+		}
+	}
+
+	g.dropped = dropped
+
+	return families, err
+}
+
+// aggregatable types can be summed up when a label is dropped, histograms and summaries are never degraded
+func aggregatable(t dto.MetricType) bool {
+	return t == dto.MetricType_GAUGE || t == dto.MetricType_COUNTER || t == dto.MetricType_UNTYPED
+}
+
+// highestCardinality returns the label with the most distinct values, empty if no label has more than one value
+func highestCardinality(metrics []*dto.Metric) string {
+	values := make(map[string]map[string]bool)
+	for _, m := range metrics {
+		for _, l := range m.GetLabel() {
+			if _, ok := values[l.GetName()]; !ok {
+				values[l.GetName()] = make(map[string]bool)
+			}
+
+			values[l.GetName()][l.GetValue()] = true
+		}
+	}
+
+	label, count := "", 1
+	for name, v := range values {
+		if len(v) > count || (len(v) == count && count > 1 && name < label) {
+			label, count = name, len(v)
+		}
+	}
+
+	return label
+}
+
+// dropLabel removes the label and sums up the values of the series which are equal without it
+func dropLabel(metrics []*dto.Metric, label string, t dto.MetricType) []*dto.Metric {
+	result := make([]*dto.Metric, 0, len(metrics))
+	series := make(map[string]*dto.Metric, len(metrics))
+
+	for _, m := range metrics {
+		labels := make([]*dto.LabelPair, 0, len(m.GetLabel()))
+		keys := make([]string, 0, len(m.GetLabel()))
+		for _, l := range m.GetLabel() {
+			if l.GetName() == label {
+				continue
+			}
+
+			labels = append(labels, l)
+			keys = append(keys, l.GetName()+"="+l.GetValue())
+		}
+
+		sort.Strings(keys)
+		key := strings.Join(keys, "\xff")
+
+		existing, ok := series[key]
+		if !ok {
+			m.Label = labels
+			if m.Counter != nil {
+				m.Counter.Exemplar = nil
+			}
+
+			series[key] = m
+			result = append(result, m)
+			continue
+		}
+
+		switch t {
+		case dto.MetricType_GAUGE:
+			*existing.Gauge.Value += m.GetGauge().GetValue()
+		case dto.MetricType_COUNTER:
+			*existing.Counter.Value += m.GetCounter().GetValue()
+		case dto.MetricType_UNTYPED:
+			*existing.Untyped.Value += m.GetUntyped().GetValue()
+		}
+	}
+
+	return result
+}
+
+// NewCardinalityGuard limits the series of each metric family, the labels with the most distinct values are dropped
+// and the series summed up until the family is within the limit. A limit of zero disables the guard
+func NewCardinalityGuard(gatherer prometheus.Gatherer, limit int, metrics CardinalityMetrics) prometheus.Gatherer {
+	if limit <= 0 {
+		return gatherer
+	}
+
+	return &cardinalityGuard{
+		gatherer: gatherer,
+		limit:    limit,
+		metrics:  metrics,
+		mx:       new(sync.Mutex),
+		dropped:  make(map[string][]string),
+	}
+}
@@ -0,0 +1,70 @@
+package metrics_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/kyverno/policy-reporter/pkg/listener/metrics"
+)
+
+func Test_CardinalityGuard(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_results"}, []string{"name", "status"})
+	small := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_small"}, []string{"status"})
+	registry.MustRegister(gauge, small)
+
+	for i := 0; i < 10; i++ {
+		gauge.WithLabelValues(fmt.Sprintf("pod-%d", i), "fail").Set(1)
+		gauge.WithLabelValues(fmt.Sprintf("pod-%d", i), "pass").Set(2)
+	}
+	small.WithLabelValues("fail").Set(1)
+
+	m := metrics.RegisterCardinalityMetrics()
+
+	if metrics.NewCardinalityGuard(registry, 0, m) != registry {
+		t.Error("expected the gatherer unchanged without limit")
+	}
+
+	families, err := metrics.NewCardinalityGuard(registry, 5, m).Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, family := range families {
+		switch family.GetName() {
+		case "test_results":
+			if len(family.GetMetric()) != 2 {
+				t.Fatalf("expected 2 series after dropping the name label, got %d", len(family.GetMetric()))
+			}
+
+			for _, series := range family.GetMetric() {
+				if len(series.GetLabel()) != 1 || series.GetLabel()[0].GetName() != "status" {
+					t.Errorf("expected only the status label, got %v", series.GetLabel())
+				}
+
+				expected := 10.0
+				if series.GetLabel()[0].GetValue() == "pass" {
+					expected = 20
+				}
+				if series.GetGauge().GetValue() != expected {
+					t.Errorf("expected the summed value %v, got %v", expected, series.GetGauge().GetValue())
+				}
+			}
+		case "test_small":
+			if len(family.GetMetric()) != 1 || len(family.GetMetric()[0].GetLabel()) != 1 {
+				t.Errorf("expected metrics within the limit to be unchanged")
+			}
+		}
+	}
+
+	if value := testutil.ToFloat64(m.Series.WithLabelValues("test_results")); value != 20 {
+		t.Errorf("expected 20 active series before degradation, got %v", value)
+	}
+	if value := testutil.ToFloat64(m.Dropped.WithLabelValues("test_results", "name")); value != 1 {
+		t.Errorf("expected the dropped name label to be exposed, got %v", value)
+	}
+}
@@ -40,7 +40,7 @@ func Test_ClusterPolicyReportMetricGeneration(t *testing.T) {
 		Results: []v1alpha2.PolicyReportResult{{Source: "Kube Bench"}},
 	}
 
-	filter := metrics.NewReportFilter(validate.RuleSets{}, validate.RuleSets{Exclude: []string{"Kube Bench"}})
+	filter := metrics.NewReportFilter(validate.RuleSets{}, validate.RuleSets{Exclude: []string{"Kube Bench"}}, validate.RuleSets{})
 	handler := metrics.CreateClusterPolicyReportMetricsListener(filter)
 
 	t.Run("Added Metric", func(t *testing.T) {
@@ -24,7 +24,7 @@ func CreateDetailedClusterResultMetricListener(filter *report.ResultFilter, gaug
 		switch event.Type {
 		case report.Added:
 			for _, result := range newReport.GetResults() {
-				if !filter.Validate(result) {
+				if !filter.ValidateReportResult(newReport, result) {
 					continue
 				}
 
@@ -39,7 +39,7 @@ func CreateDetailedClusterResultMetricListener(filter *report.ResultFilter, gaug
 			}
 
 			for _, result := range newReport.GetResults() {
-				if !filter.Validate(result) {
+				if !filter.ValidateReportResult(newReport, result) {
 					continue
 				}
 
@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CoverageGauges of the workloads and the workloads without results per kind and source
+type CoverageGauges struct {
+	Resources   *prometheus.GaugeVec
+	Unevaluated *prometheus.GaugeVec
+	Uncovered   *prometheus.GaugeVec
+}
+
+// RegisterCoverageGauges registers the coverage gauges, existing gauges are reused
+func RegisterCoverageGauges() CoverageGauges {
+	return CoverageGauges{
+		Resources: registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "policy_reporter_coverage_resources",
+			Help: "Scanned workloads per kind",
+		}, []string{"kind"})).(*prometheus.GaugeVec),
+		Unevaluated: registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "policy_reporter_coverage_unevaluated_resources",
+			Help: "Workloads per kind without any result of any source",
+		}, []string{"kind"})).(*prometheus.GaugeVec),
+		Uncovered: registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "policy_reporter_coverage_uncovered_resources",
+			Help: "Workloads per kind without any result of the source",
+		}, []string{"kind", "source"})).(*prometheus.GaugeVec),
+	}
+}
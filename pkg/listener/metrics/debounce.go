@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DebounceMetrics of the delayed PolicyReport update events
+type DebounceMetrics struct {
+	Coalesced *prometheus.CounterVec
+	Pending   prometheus.Gauge
+}
+
+// RegisterDebounceMetrics registers the coalesced updates per source and the pending events, existing metrics are reused
+func RegisterDebounceMetrics() DebounceMetrics {
+	return DebounceMetrics{
+		Coalesced: registerGauge(prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "policy_reporter_debounce_coalesced_events_total",
+			Help: "PolicyReport update events replaced by a newer update of the same report before they were published",
+		}, []string{"source"})).(*prometheus.CounterVec),
+		Pending: registerGauge(prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "policy_reporter_debounce_pending_events",
+			Help: "PolicyReport events delayed by the debouncer",
+		})).(prometheus.Gauge),
+	}
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DispatcherMetrics of the target delivery queues
+type DispatcherMetrics struct {
+	QueueLength *prometheus.GaugeVec
+	Blocked     *prometheus.CounterVec
+	Dropped     *prometheus.CounterVec
+	Sampled     *prometheus.CounterVec
+	SendLatency *prometheus.HistogramVec
+}
+
+// RegisterDispatcherMetrics registers the delivery queue metrics per target, existing metrics are reused
+func RegisterDispatcherMetrics() DispatcherMetrics {
+	m := NewDispatcherMetrics()
+
+	return DispatcherMetrics{
+		QueueLength: registerGauge(m.QueueLength).(*prometheus.GaugeVec),
+		Blocked:     registerGauge(m.Blocked).(*prometheus.CounterVec),
+		Dropped:     registerGauge(m.Dropped).(*prometheus.CounterVec),
+		Sampled:     registerGauge(m.Sampled).(*prometheus.CounterVec),
+		SendLatency: registerGauge(m.SendLatency).(*prometheus.HistogramVec),
+	}
+}
+
+// NewDispatcherMetrics creates the delivery queue metrics without registering them,
+// to register them with a custom prometheus.Registerer when embedding the dispatcher
+func NewDispatcherMetrics() DispatcherMetrics {
+	return DispatcherMetrics{
+		QueueLength: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "policy_reporter_target_queue_length",
+			Help: "Results waiting for the delivery to a target",
+		}, []string{"target"}),
+		Blocked: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "policy_reporter_target_queue_blocked_total",
+			Help: "Results which had to wait for free space in the delivery queue of a target",
+		}, []string{"target"}),
+		Dropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "policy_reporter_target_queue_dropped_total",
+			Help: "Results not delivered to a target because the delivery queue was shut down",
+		}, []string{"target"}),
+		Sampled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "policy_reporter_target_sampled_total",
+			Help: "Results not sent to a target because the sampling limit of the policy was reached",
+		}, []string{"target", "policy"}),
+		SendLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "policy_reporter_target_send_duration_seconds",
+			Help:    "Duration of the delivery of a result to a target",
+			Buckets: prometheus.ExponentialBuckets(0.005, 2, 12),
+		}, []string{"target"}),
+	}
+}
+
+// Collectors of the delivery queue metrics
+func (m DispatcherMetrics) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.QueueLength, m.Blocked, m.Dropped, m.Sampled, m.SendLatency}
+}
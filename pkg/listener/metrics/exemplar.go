@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"sync"
+	"unicode/utf8"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+const (
+	// ResultIDExemplar is the exemplar label of the result ID, used to link from a metric to the results API
+	ResultIDExemplar = "result_id"
+	// ReportIDExemplar is the exemplar label of the PolicyReport ID
+	ReportIDExemplar = "report_id"
+)
+
+// OpenMetrics limits the combined length of exemplar label names and values
+const maxExemplarRunes = 128
+
+func RegisterResultCounter(name string) *prometheus.CounterVec {
+	return promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: name,
+		Help: "Counter of observed Results with the result ID as exemplar",
+	}, []string{"namespace", "policy", "status", "severity", "source"})
+}
+
+// exemplar of the result, labels exceeding the OpenMetrics limit are omitted
+func exemplar(rep v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) prometheus.Labels {
+	labels := prometheus.Labels{}
+	size := 0
+
+	for _, l := range [][2]string{{ResultIDExemplar, result.GetID()}, {ReportIDExemplar, rep.GetID()}} {
+		runes := utf8.RuneCountInString(l[0]) + utf8.RuneCountInString(l[1])
+		if size+runes > maxExemplarRunes {
+			break
+		}
+
+		labels[l[0]] = l[1]
+		size += runes
+	}
+
+	return labels
+}
+
+// CreateResultCounterListener counts each new result of a report once, the exemplar links the increment to the result
+func CreateResultCounterListener(filter *report.ResultFilter, counter *prometheus.CounterVec) report.PolicyReportListener {
+	mx := new(sync.Mutex)
+	known := make(map[string]map[string]bool)
+
+	return func(event report.LifecycleEvent) {
+		rep := event.PolicyReport
+
+		mx.Lock()
+		defer mx.Unlock()
+
+		if event.Type == report.Deleted {
+			delete(known, rep.GetID())
+			return
+		}
+
+		previous := known[rep.GetID()]
+		current := make(map[string]bool, len(rep.GetResults()))
+
+		for _, result := range rep.GetResults() {
+			if !filter.ValidateReportResult(rep, result) {
+				continue
+			}
+
+			id := result.GetID()
+			current[id] = true
+
+			if previous[id] {
+				continue
+			}
+
+			c := counter.With(prometheus.Labels{
+				"namespace": rep.GetNamespace(),
+				"policy":    result.Policy,
+				"status":    string(result.Result),
+				"severity":  string(result.Severity),
+				"source":    result.Source,
+			})
+
+			if adder, ok := c.(prometheus.ExemplarAdder); ok {
+				adder.AddWithExemplar(1, exemplar(rep, result))
+			} else {
+				c.Inc()
+			}
+		}
+
+		known[rep.GetID()] = current
+	}
+}
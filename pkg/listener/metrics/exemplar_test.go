@@ -0,0 +1,57 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/listener/metrics"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+func Test_ResultCounterExemplars(t *testing.T) {
+	counter := metrics.RegisterResultCounter("policy_report_results_exemplar_total")
+	filter := metrics.NewResultFilter(validate.RuleSets{}, validate.RuleSets{}, validate.RuleSets{}, validate.RuleSets{}, validate.RuleSets{})
+
+	handler := metrics.CreateResultCounterListener(filter, counter)
+
+	preport := &v1alpha2.PolicyReport{
+		ObjectMeta: v1.ObjectMeta{Name: "polr-test", Namespace: "test"},
+		Results:    []v1alpha2.PolicyReportResult{fixtures.FailResult},
+	}
+
+	handler(report.LifecycleEvent{Type: report.Added, PolicyReport: preport})
+	// unchanged results are not counted again
+	handler(report.LifecycleEvent{Type: report.Updated, PolicyReport: preport})
+
+	metricFam, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Errorf("unexpected Error: %s", err)
+	}
+
+	results := findMetric(metricFam, "policy_report_results_exemplar_total")
+	if results == nil {
+		t.Fatalf("Metric not found: policy_report_results_exemplar_total")
+	}
+
+	c := results.GetMetric()[0].GetCounter()
+	if c.GetValue() != 1 {
+		t.Errorf("expected a count of 1, got %v", c.GetValue())
+	}
+
+	labels := make(map[string]string)
+	for _, l := range c.GetExemplar().GetLabel() {
+		labels[l.GetName()] = l.GetValue()
+	}
+
+	if labels[metrics.ResultIDExemplar] != fixtures.FailResult.GetID() {
+		t.Errorf("expected result ID exemplar %s, got %v", fixtures.FailResult.GetID(), labels)
+	}
+	if labels[metrics.ReportIDExemplar] != preport.GetID() {
+		t.Errorf("expected report ID exemplar %s, got %v", preport.GetID(), labels)
+	}
+}
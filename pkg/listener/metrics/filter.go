@@ -45,8 +45,14 @@ func NewResultFilter(namespace, status, policy, source, severity validate.RuleSe
 	return f
 }
 
-func NewReportFilter(namespace, source validate.RuleSets) *report.ReportFilter {
+func NewReportFilter(namespace, source, labels validate.RuleSets) *report.ReportFilter {
 	f := &report.ReportFilter{}
+	if labels.Count() > 0 {
+		f.AddValidation(func(r v1alpha2.ReportInterface) bool {
+			return validate.MatchLabels(r.GetLabels(), labels)
+		})
+	}
+
 	if namespace.Count() > 0 {
 		f.AddValidation(func(r v1alpha2.ReportInterface) bool {
 			return validate.Namespace(r.GetNamespace(), namespace)
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+type labelGatherer struct {
+	gatherer prometheus.Gatherer
+	labels   []*dto.LabelPair
+}
+
+func (g *labelGatherer) Gather() ([]*dto.MetricFamily, error) {
+	families, err := g.gatherer.Gather()
+
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			m.Label = addLabels(m.GetLabel(), g.labels)
+		}
+	}
+
+	return families, err
+}
+
+// addLabels keeps labels with a value, empty labels are equal to missing labels in Prometheus and replaced
+func addLabels(labels, additional []*dto.LabelPair) []*dto.LabelPair {
+	result := make([]*dto.LabelPair, 0, len(labels)+len(additional))
+	existing := make(map[string]bool, len(labels))
+
+	for _, l := range labels {
+		if l.GetValue() == "" {
+			continue
+		}
+
+		existing[l.GetName()] = true
+		result = append(result, l)
+	}
+
+	for _, l := range additional {
+		if !existing[l.GetName()] {
+			result = append(result, l)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].GetName() < result[j].GetName() })
+
+	return result
+}
+
+// NewLabelGatherer adds the labels to all gathered metrics, labels with a value set by the metric itself are kept
+func NewLabelGatherer(gatherer prometheus.Gatherer, labels map[string]string) prometheus.Gatherer {
+	pairs := make([]*dto.LabelPair, 0, len(labels))
+	for name, value := range labels {
+		name, value := name, value
+		pairs = append(pairs, &dto.LabelPair{Name: &name, Value: &value})
+	}
+
+	return &labelGatherer{gatherer: gatherer, labels: pairs}
+}
@@ -0,0 +1,41 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kyverno/policy-reporter/pkg/listener/metrics"
+)
+
+func Test_LabelGatherer(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_gauge"}, []string{"name", "cluster"})
+	registry.MustRegister(gauge)
+
+	gauge.WithLabelValues("a", "").Set(1)
+	gauge.WithLabelValues("b", "other").Set(2)
+
+	families, err := metrics.NewLabelGatherer(registry, map[string]string{"cluster": "edge-a"}).Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clusters := make(map[string]string)
+	for _, m := range families[0].GetMetric() {
+		labels := make(map[string]string)
+		for _, l := range m.GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+
+		clusters[labels["name"]] = labels["cluster"]
+	}
+
+	if clusters["b"] != "other" {
+		t.Errorf("expected existing cluster label to be kept, got %s", clusters["b"])
+	}
+	if clusters["a"] != "edge-a" {
+		t.Errorf("expected empty label to be replaced, got %s", clusters["a"])
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
 )
 
 type Mode = string
@@ -57,6 +58,12 @@ var LabelGeneratorMapping = map[string]LabelCallback{
 	"status": func(m map[string]string, _ v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult) {
 		m["status"] = string(r.Result)
 	},
+	"team": func(m map[string]string, _ v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult) {
+		m["team"] = r.Properties[v1alpha2.TeamKey]
+	},
+	"node": func(m map[string]string, _ v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult) {
+		m["node"] = report.NodeName(r)
+	},
 }
 
 func CreateLabelGenerator(labels []string, names []string) LabelGenerator {
@@ -52,6 +52,11 @@ func Test_LabelMappings(t *testing.T) {
 		t.Errorf("expected result for status label not found: %s", val)
 	}
 
+	metrics.LabelGeneratorMapping["node"](results, preport, fixtures.FailPodResult)
+	if val, ok := results["node"]; !ok || val != "" {
+		t.Errorf("expected empty node label for pod results, got: %s", val)
+	}
+
 	metrics.LabelGeneratorMapping["name"](results, preport, fixtures.TrivyResult)
 	if val, ok := results["name"]; !ok && val != "" {
 		t.Errorf("expected empty name without resource, got: %s", val)
@@ -0,0 +1,284 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+type RelabelAction = string
+
+const (
+	// Replace sets the target label to the expanded replacement if the regex matches
+	Replace RelabelAction = "replace"
+	// Keep drops all results whose source labels do not match the regex
+	Keep RelabelAction = "keep"
+	// Drop drops all results whose source labels match the regex
+	Drop RelabelAction = "drop"
+)
+
+type Aggregation = string
+
+const (
+	// AggregateAll counts the results of PolicyReports and ClusterPolicyReports in one gauge
+	AggregateAll Aggregation = "all"
+	// AggregateNamespaced counts only the results of PolicyReports
+	AggregateNamespaced Aggregation = "namespaced"
+	// AggregateCluster counts only the results of ClusterPolicyReports
+	AggregateCluster Aggregation = "cluster"
+)
+
+var metricName = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_:]+`)
+
+// SourceLabels of the dedicated metric family of a source without configured labels
+var SourceLabels = []string{"namespace", "policy", "rule", "kind", "name", "severity", "category", "status"}
+
+// ValidName reports whether the name is a valid Prometheus metric name or prefix
+func ValidName(name string) bool {
+	return metricName.MatchString(name)
+}
+
+// SourceMetricName of the dedicated metric family of a source, e.g. trivy_vulnerability_results for "Trivy Vulnerability"
+func SourceMetricName(prefix, source string) string {
+	name := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(source), "_"), "_")
+
+	return prefix + name + "_results"
+}
+
+// RelabelRule is evaluated on the labels of each result before it is counted, like the Prometheus relabel_config
+type RelabelRule struct {
+	Action       RelabelAction
+	SourceLabels []string
+	Separator    string
+	Regex        *regexp.Regexp
+	TargetLabel  string
+	Replacement  string
+}
+
+// apply the rule, returns false if the result should be dropped
+func (r RelabelRule) apply(labels map[string]string) bool {
+	values := make([]string, 0, len(r.SourceLabels))
+	for _, l := range r.SourceLabels {
+		values = append(values, labels[l])
+	}
+
+	value := strings.Join(values, r.Separator)
+
+	switch r.Action {
+	case Keep:
+		return r.Regex.MatchString(value)
+	case Drop:
+		return !r.Regex.MatchString(value)
+	}
+
+	match := r.Regex.FindStringSubmatchIndex(value)
+	if match == nil {
+		return true
+	}
+
+	labels[r.TargetLabel] = string(r.Regex.ExpandString(nil, r.Replacement, value, match))
+
+	return true
+}
+
+// Pipeline describes a result gauge with the configured labels, aggregation and relabel rules
+type Pipeline struct {
+	Name        string
+	Help        string
+	Labels      []string
+	Aggregation Aggregation
+	Relabel     []RelabelRule
+	// Sources counted by the gauge, all sources if empty
+	Sources []string
+}
+
+// Matches the aggregation of the pipeline
+func (p Pipeline) Matches(r v1alpha2.ReportInterface) bool {
+	switch p.Aggregation {
+	case AggregateNamespaced:
+		return r.GetNamespace() != ""
+	case AggregateCluster:
+		return r.GetNamespace() == ""
+	}
+
+	return true
+}
+
+// LabelNames of the gauge, report labels are sanitized to valid label names
+func (p Pipeline) LabelNames() []string {
+	names := make([]string, 0, len(p.Labels))
+	for _, l := range p.Labels {
+		names = append(names, LabelName(l))
+	}
+
+	return names
+}
+
+// LabelName of a configured label, report labels are sanitized to valid label names
+func LabelName(label string) string {
+	if !strings.HasPrefix(label, ReportLabelPrefix) {
+		return label
+	}
+
+	replacer := strings.NewReplacer(".", "_", "/", "_", ":", "_", "-", "_", ";", "_")
+
+	return replacer.Replace(strings.TrimPrefix(label, ReportLabelPrefix))
+}
+
+// NewRelabelRule validates the rule and applies the Prometheus defaults for empty values
+func NewRelabelRule(action RelabelAction, sourceLabels []string, separator, regex, targetLabel, replacement string) (RelabelRule, error) {
+	if action == "" {
+		action = Replace
+	}
+	if action != Replace && action != Keep && action != Drop {
+		return RelabelRule{}, fmt.Errorf("unknown relabel action %s", action)
+	}
+	if len(sourceLabels) == 0 {
+		return RelabelRule{}, errors.New("relabel rule requires sourceLabels")
+	}
+	if action == Replace && targetLabel == "" {
+		return RelabelRule{}, errors.New("replace rule requires a targetLabel")
+	}
+	if separator == "" {
+		separator = ";"
+	}
+	if regex == "" {
+		regex = "(.*)"
+	}
+	if replacement == "" {
+		replacement = "$1"
+	}
+
+	// anchored like the Prometheus relabeling
+	re, err := regexp.Compile("^(?:" + regex + ")$")
+	if err != nil {
+		return RelabelRule{}, fmt.Errorf("invalid relabel regex: %w", err)
+	}
+
+	return RelabelRule{
+		Action:       action,
+		SourceLabels: sourceLabels,
+		Separator:    separator,
+		Regex:        re,
+		TargetLabel:  targetLabel,
+		Replacement:  replacement,
+	}, nil
+}
+
+// Validate the gauge name, labels and relabel targets of the pipeline
+func (p Pipeline) Validate() error {
+	if !metricName.MatchString(p.Name) {
+		return fmt.Errorf("invalid metric name %s", p.Name)
+	}
+	if len(p.Labels) == 0 {
+		return fmt.Errorf("metric %s requires at least one label", p.Name)
+	}
+	if p.Aggregation != "" && p.Aggregation != AggregateAll && p.Aggregation != AggregateNamespaced && p.Aggregation != AggregateCluster {
+		return fmt.Errorf("unknown aggregation %s of metric %s", p.Aggregation, p.Name)
+	}
+
+	names := make(map[string]bool, len(p.Labels))
+	for _, l := range p.Labels {
+		if _, ok := LabelGeneratorMapping[l]; !ok && !strings.HasPrefix(l, ReportLabelPrefix) {
+			return fmt.Errorf("unknown label %s of metric %s", l, p.Name)
+		}
+
+		names[LabelName(l)] = true
+	}
+
+	for _, r := range p.Relabel {
+		if r.Action == Replace && !names[r.TargetLabel] {
+			return fmt.Errorf("relabel target %s is not a label of metric %s", r.TargetLabel, p.Name)
+		}
+	}
+
+	return nil
+}
+
+// RelabelGenerator applies the relabel rules on the generated labels, dropped results have no labels
+func RelabelGenerator(generator LabelGenerator, rules []RelabelRule) LabelGenerator {
+	if len(rules) == 0 {
+		return generator
+	}
+
+	return func(pr v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult) map[string]string {
+		labels := generator(pr, r)
+		for _, rule := range rules {
+			if !rule.apply(labels) {
+				return nil
+			}
+		}
+
+		return labels
+	}
+}
+
+// SourceGenerator drops the results of other sources, all results are kept without sources
+func SourceGenerator(generator LabelGenerator, sources []string) LabelGenerator {
+	if len(sources) == 0 {
+		return generator
+	}
+
+	return func(pr v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult) map[string]string {
+		for _, s := range sources {
+			if strings.EqualFold(s, r.Source) {
+				return generator(pr, r)
+			}
+		}
+
+		return nil
+	}
+}
+
+// RegisterPipelineGauge registers the gauge of the pipeline, an existing gauge with the same name is reused
+func RegisterPipelineGauge(p Pipeline) (*prometheus.GaugeVec, error) {
+	help := p.Help
+	if help == "" {
+		help = "Gauge of Results by " + strings.Join(p.LabelNames(), ", ")
+	}
+
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: p.Name, Help: help}, p.LabelNames())
+
+	if err := prometheus.Register(gauge); err != nil {
+		are := prometheus.AlreadyRegisteredError{}
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(*prometheus.GaugeVec); ok {
+				return existing, nil
+			}
+		}
+
+		return nil, err
+	}
+
+	return gauge, nil
+}
+
+// CreatePipelineMetricsListener counts the results matching the aggregation with the relabeled labels of the pipeline
+func CreatePipelineMetricsListener(filter *report.ResultFilter, p Pipeline) (report.PolicyReportListener, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	gauge, err := RegisterPipelineGauge(p)
+	if err != nil {
+		return nil, err
+	}
+
+	generator := RelabelGenerator(CreateLabelGenerator(p.Labels, p.LabelNames()), p.Relabel)
+
+	listener := CreateCustomResultMetricsListener(filter, gauge, SourceGenerator(generator, p.Sources))
+
+	return func(event report.LifecycleEvent) {
+		if p.Matches(event.PolicyReport) {
+			listener(event)
+		}
+	}, nil
+}
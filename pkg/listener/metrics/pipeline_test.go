@@ -0,0 +1,164 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/listener/metrics"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+func Test_PipelineMetricGeneration(t *testing.T) {
+	drop, _ := metrics.NewRelabelRule(metrics.Drop, []string{"status"}, "", "pass|skip", "", "")
+	replace, _ := metrics.NewRelabelRule("", []string{"category"}, "", "Best (.*)", "category", "best-$1")
+
+	pipeline := metrics.Pipeline{
+		Name:        "policy_report_category_result",
+		Labels:      []string{"category", "severity", "status"},
+		Aggregation: metrics.AggregateNamespaced,
+		Relabel:     []metrics.RelabelRule{drop, replace},
+	}
+
+	preport := &v1alpha2.PolicyReport{
+		ObjectMeta: v1.ObjectMeta{Name: "polr-test", Namespace: "test"},
+		Results:    []v1alpha2.PolicyReportResult{fixtures.PassResult, fixtures.FailResult, fixtures.FailPodResult, fixtures.FailDisallowRuleResult},
+	}
+	creport := &v1alpha2.ClusterPolicyReport{
+		ObjectMeta: v1.ObjectMeta{Name: "cpolr-test"},
+		Results:    []v1alpha2.PolicyReportResult{fixtures.FailResult},
+	}
+
+	filter := metrics.NewResultFilter(validate.RuleSets{}, validate.RuleSets{}, validate.RuleSets{}, validate.RuleSets{}, validate.RuleSets{})
+
+	handler, err := metrics.CreatePipelineMetricsListener(filter, pipeline)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Added Metric", func(t *testing.T) {
+		handler(report.LifecycleEvent{Type: report.Added, PolicyReport: preport})
+		handler(report.LifecycleEvent{Type: report.Added, PolicyReport: creport})
+
+		metricFam, err := prometheus.DefaultGatherer.Gather()
+		if err != nil {
+			t.Errorf("unexpected Error: %s", err)
+		}
+
+		results := findMetric(metricFam, "policy_report_category_result")
+		if results == nil {
+			t.Fatalf("Metric not found: policy_report_category_result")
+		}
+
+		values := make(map[string]float64)
+		for _, m := range results.GetMetric() {
+			labels := make(map[string]string)
+			for _, l := range m.Label {
+				labels[*l.Name] = *l.Value
+			}
+			if labels["status"] == "pass" {
+				t.Error("expected pass results to be dropped")
+			}
+
+			values[labels["category"]] = *m.Gauge.Value
+		}
+
+		if values["resources"] != 2 {
+			t.Errorf("expected 2 failed resources results of the PolicyReport, got %v", values["resources"])
+		}
+		if values["best-Practices"] != 1 {
+			t.Errorf("expected relabeled category with 1 result, got %v", values)
+		}
+	})
+
+	t.Run("Deleted Metric", func(t *testing.T) {
+		handler(report.LifecycleEvent{Type: report.Deleted, PolicyReport: preport})
+
+		metricFam, _ := prometheus.DefaultGatherer.Gather()
+		if results := findMetric(metricFam, "policy_report_category_result"); results != nil && len(results.GetMetric()) > 0 {
+			t.Errorf("expected no remaining series, got %d", len(results.GetMetric()))
+		}
+	})
+}
+
+func Test_SourcePipeline(t *testing.T) {
+	pipeline := metrics.Pipeline{
+		Name:    metrics.SourceMetricName("test_", "Trivy Vulnerability"),
+		Labels:  []string{"policy", "status"},
+		Sources: []string{"trivy vulnerability"},
+	}
+	if pipeline.Name != "test_trivy_vulnerability_results" {
+		t.Fatalf("unexpected source metric name %s", pipeline.Name)
+	}
+
+	trivy := fixtures.FailResult
+	trivy.Source = "Trivy Vulnerability"
+	trivy.Policy = "CVE-2022-1234"
+
+	preport := &v1alpha2.PolicyReport{
+		ObjectMeta: v1.ObjectMeta{Name: "polr-source", Namespace: "test"},
+		Results:    []v1alpha2.PolicyReportResult{fixtures.FailResult, trivy},
+	}
+
+	filter := metrics.NewResultFilter(validate.RuleSets{}, validate.RuleSets{}, validate.RuleSets{}, validate.RuleSets{}, validate.RuleSets{})
+
+	handler, err := metrics.CreatePipelineMetricsListener(filter, pipeline)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler(report.LifecycleEvent{Type: report.Added, PolicyReport: preport})
+
+	metricFam, _ := prometheus.DefaultGatherer.Gather()
+	results := findMetric(metricFam, pipeline.Name)
+	if results == nil {
+		t.Fatalf("Metric not found: %s", pipeline.Name)
+	}
+	if len(results.GetMetric()) != 1 || *results.GetMetric()[0].Label[0].Value != "CVE-2022-1234" {
+		t.Errorf("expected only the results of the source, got %v", results.GetMetric())
+	}
+}
+
+func Test_PipelineValidation(t *testing.T) {
+	t.Run("Invalid Name", func(t *testing.T) {
+		if err := (metrics.Pipeline{Name: "invalid-name", Labels: []string{"policy"}}).Validate(); err == nil {
+			t.Error("expected invalid metric name error")
+		}
+	})
+	t.Run("Unknown Label", func(t *testing.T) {
+		if err := (metrics.Pipeline{Name: "valid", Labels: []string{"unknown"}}).Validate(); err == nil {
+			t.Error("expected unknown label error")
+		}
+	})
+	t.Run("Unknown Aggregation", func(t *testing.T) {
+		if err := (metrics.Pipeline{Name: "valid", Labels: []string{"policy"}, Aggregation: "rule"}).Validate(); err == nil {
+			t.Error("expected unknown aggregation error")
+		}
+	})
+	t.Run("Unknown Relabel Target", func(t *testing.T) {
+		rule, _ := metrics.NewRelabelRule(metrics.Replace, []string{"policy"}, "", "", "rule", "")
+		if err := (metrics.Pipeline{Name: "valid", Labels: []string{"policy", "label:app.kubernetes.io/name"}, Relabel: []metrics.RelabelRule{rule}}).Validate(); err == nil {
+			t.Error("expected unknown relabel target error")
+		}
+	})
+	t.Run("Valid", func(t *testing.T) {
+		if err := (metrics.Pipeline{Name: "valid", Labels: []string{"policy", "label:app.kubernetes.io/name"}}).Validate(); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	})
+	t.Run("Invalid Relabel Rules", func(t *testing.T) {
+		if _, err := metrics.NewRelabelRule("labelmap", []string{"policy"}, "", "", "", ""); err == nil {
+			t.Error("expected unknown action error")
+		}
+		if _, err := metrics.NewRelabelRule(metrics.Keep, nil, "", "", "", ""); err == nil {
+			t.Error("expected missing sourceLabels error")
+		}
+		if _, err := metrics.NewRelabelRule(metrics.Keep, []string{"policy"}, "", "(", "", ""); err == nil {
+			t.Error("expected invalid regex error")
+		}
+	})
+}
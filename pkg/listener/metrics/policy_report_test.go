@@ -40,7 +40,7 @@ func Test_PolicyReportMetricGeneration(t *testing.T) {
 		Summary: v1alpha2.PolicyReportSummary{Pass: 0, Fail: 1, Warn: 3},
 	}
 
-	filter := metrics.NewReportFilter(validate.RuleSets{Exclude: []string{"dev"}}, validate.RuleSets{Exclude: []string{"Test"}})
+	filter := metrics.NewReportFilter(validate.RuleSets{Exclude: []string{"dev"}}, validate.RuleSets{Exclude: []string{"Test"}}, validate.RuleSets{})
 
 	t.Run("Added Metric", func(t *testing.T) {
 		handler := metrics.CreatePolicyReportMetricsListener(filter)
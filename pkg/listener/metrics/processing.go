@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// RegisterProcessingLag registers the histogram of the time between the observed change of a PolicyReport
+// and the dispatch of its event to the listeners, an existing histogram is reused
+func RegisterProcessingLag() prometheus.Histogram {
+	return registerGauge(prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "policy_reporter_report_processing_lag_seconds",
+		Help:    "Time between the observed change of a PolicyReport and the dispatch of its event, including queue and debounce delays",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 14),
+	})).(prometheus.Histogram)
+}
+
+// WorkqueueProvider exposes the metrics of the informer event queues, e.g. the queue depth
+type WorkqueueProvider struct {
+	depth          *prometheus.GaugeVec
+	adds           *prometheus.CounterVec
+	latency        *prometheus.HistogramVec
+	workDuration   *prometheus.HistogramVec
+	unfinished     *prometheus.GaugeVec
+	longestRunning *prometheus.GaugeVec
+	retries        *prometheus.CounterVec
+}
+
+func (p *WorkqueueProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return p.depth.WithLabelValues(name)
+}
+
+func (p *WorkqueueProvider) NewAddsMetric(name string) workqueue.CounterMetric {
+	return p.adds.WithLabelValues(name)
+}
+
+func (p *WorkqueueProvider) NewLatencyMetric(name string) workqueue.HistogramMetric {
+	return p.latency.WithLabelValues(name)
+}
+
+func (p *WorkqueueProvider) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
+	return p.workDuration.WithLabelValues(name)
+}
+
+func (p *WorkqueueProvider) NewUnfinishedWorkSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return p.unfinished.WithLabelValues(name)
+}
+
+func (p *WorkqueueProvider) NewLongestRunningProcessorSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return p.longestRunning.WithLabelValues(name)
+}
+
+func (p *WorkqueueProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
+	return p.retries.WithLabelValues(name)
+}
+
+// RegisterWorkqueueProvider registers the workqueue metrics labeled by the queue name, existing metrics are reused
+func RegisterWorkqueueProvider() *WorkqueueProvider {
+	buckets := prometheus.ExponentialBuckets(0.001, 2, 16)
+
+	return &WorkqueueProvider{
+		depth: registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "policy_reporter_workqueue_depth",
+			Help: "Current depth of the informer event queue",
+		}, []string{"name"})).(*prometheus.GaugeVec),
+		adds: registerGauge(prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "policy_reporter_workqueue_adds_total",
+			Help: "Events added to the informer event queue",
+		}, []string{"name"})).(*prometheus.CounterVec),
+		latency: registerGauge(prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "policy_reporter_workqueue_queue_duration_seconds",
+			Help:    "Time an event stays in the informer event queue before it is processed",
+			Buckets: buckets,
+		}, []string{"name"})).(*prometheus.HistogramVec),
+		workDuration: registerGauge(prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "policy_reporter_workqueue_work_duration_seconds",
+			Help:    "Processing duration of an event of the informer event queue",
+			Buckets: buckets,
+		}, []string{"name"})).(*prometheus.HistogramVec),
+		unfinished: registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "policy_reporter_workqueue_unfinished_work_seconds",
+			Help: "Processing time of the events in progress",
+		}, []string{"name"})).(*prometheus.GaugeVec),
+		longestRunning: registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "policy_reporter_workqueue_longest_running_processor_seconds",
+			Help: "Processing time of the longest running event",
+		}, []string{"name"})).(*prometheus.GaugeVec),
+		retries: registerGauge(prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "policy_reporter_workqueue_retries_total",
+			Help: "Retries of failed events of the informer event queue",
+		}, []string{"name"})).(*prometheus.CounterVec),
+	}
+}
@@ -34,11 +34,16 @@ func CreateCustomResultMetricsListener(
 		switch event.Type {
 		case report.Added:
 			for _, result := range newReport.GetResults() {
-				if !filter.Validate(result) {
+				if !filter.ValidateReportResult(newReport, result) {
 					continue
 				}
 
-				gauge.With(labelGenerator(newReport, result)).Inc()
+				labels := labelGenerator(newReport, result)
+				if labels == nil {
+					continue
+				}
+
+				gauge.With(labels).Inc()
 			}
 
 			cache.AddReport(newReport)
@@ -49,11 +54,16 @@ func CreateCustomResultMetricsListener(
 			}
 
 			for _, result := range newReport.GetResults() {
-				if !filter.Validate(result) {
+				if !filter.ValidateReportResult(newReport, result) {
+					continue
+				}
+
+				labels := labelGenerator(newReport, result)
+				if labels == nil {
 					continue
 				}
 
-				gauge.With(labelGenerator(newReport, result)).Inc()
+				gauge.With(labels).Inc()
 			}
 
 			cache.AddReport(newReport)
@@ -24,7 +24,7 @@ func CreateDetailedResultMetricListener(filter *report.ResultFilter, gauge *prom
 		switch event.Type {
 		case report.Added:
 			for _, result := range newReport.GetResults() {
-				if !filter.Validate(result) {
+				if !filter.ValidateReportResult(newReport, result) {
 					continue
 				}
 
@@ -39,7 +39,7 @@ func CreateDetailedResultMetricListener(filter *report.ResultFilter, gauge *prom
 			}
 
 			for _, result := range newReport.GetResults() {
-				if !filter.Validate(result) {
+				if !filter.ValidateReportResult(newReport, result) {
 					continue
 				}
 
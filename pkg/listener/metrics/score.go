@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/score"
+)
+
+// ScoreGauges of the compliance scores from 0 to 100
+type ScoreGauges struct {
+	Cluster    prometheus.Gauge
+	Namespaces *prometheus.GaugeVec
+	Categories *prometheus.GaugeVec
+}
+
+func registerGauge(gauge prometheus.Collector) prometheus.Collector {
+	if err := prometheus.Register(gauge); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector
+		}
+	}
+
+	return gauge
+}
+
+// RegisterScoreGauges registers the cluster, namespace and category compliance score gauges, existing gauges are reused
+func RegisterScoreGauges() ScoreGauges {
+	return ScoreGauges{
+		Cluster: registerGauge(prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "policy_report_cluster_compliance_score",
+			Help: "Compliance score from 0 to 100 of all results, weighted by severity",
+		})).(prometheus.Gauge),
+		Namespaces: registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "policy_report_namespace_compliance_score",
+			Help: "Compliance score from 0 to 100 of the namespaced results, weighted by severity",
+		}, []string{"namespace"})).(*prometheus.GaugeVec),
+		Categories: registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "policy_report_category_compliance_score",
+			Help: "Compliance score from 0 to 100 of the results of a policy category, weighted by severity",
+		}, []string{"category"})).(*prometheus.GaugeVec),
+	}
+}
+
+type scoreKey struct {
+	namespace string
+	category  string
+	severity  string
+	status    string
+}
+
+// CreateScoreListener recalculates the compliance scores with the counted results of all reports on each change
+func CreateScoreListener(filter *report.ResultFilter, gauges ScoreGauges, weights score.Weights) report.PolicyReportListener {
+	mx := new(sync.Mutex)
+	reports := make(map[string]map[scoreKey]int)
+
+	return func(event report.LifecycleEvent) {
+		rep := event.PolicyReport
+
+		current := make(map[scoreKey]int)
+		if event.Type != report.Deleted {
+			for _, result := range rep.GetResults() {
+				if !filter.ValidateReportResult(rep, result) {
+					continue
+				}
+
+				current[scoreKey{rep.GetNamespace(), result.Category, string(result.Severity), string(result.Result)}]++
+			}
+		}
+
+		mx.Lock()
+		defer mx.Unlock()
+
+		if len(current) == 0 {
+			delete(reports, rep.GetID())
+		} else {
+			reports[rep.GetID()] = current
+		}
+
+		totals := make(map[scoreKey]int)
+		for _, counts := range reports {
+			for key, count := range counts {
+				totals[key] += count
+			}
+		}
+
+		counts := make([]score.Count, 0, len(totals))
+		for key, count := range totals {
+			counts = append(counts, score.Count{Namespace: key.namespace, Category: key.category, Severity: key.severity, Status: key.status, Count: count})
+		}
+
+		scores := score.Calculate(counts, weights)
+
+		gauges.Cluster.Set(scores.Cluster.Score)
+
+		gauges.Namespaces.Reset()
+		for _, s := range scores.Namespaces {
+			gauges.Namespaces.WithLabelValues(s.Name).Set(s.Score)
+		}
+
+		gauges.Categories.Reset()
+		for _, s := range scores.Categories {
+			gauges.Categories.WithLabelValues(s.Name).Set(s.Score)
+		}
+	}
+}
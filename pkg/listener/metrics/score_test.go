@@ -0,0 +1,56 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/listener/metrics"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/score"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+func Test_ScoreListener(t *testing.T) {
+	gauges := metrics.RegisterScoreGauges()
+	filter := metrics.NewResultFilter(validate.RuleSets{}, validate.RuleSets{}, validate.RuleSets{}, validate.RuleSets{}, validate.RuleSets{})
+
+	handler := metrics.CreateScoreListener(filter, gauges, score.Weights{"": 1})
+
+	preport := &v1alpha2.PolicyReport{
+		ObjectMeta: v1.ObjectMeta{Name: "polr-test", Namespace: "test"},
+		Results:    []v1alpha2.PolicyReportResult{fixtures.FailResult, fixtures.PassResult},
+	}
+
+	handler(report.LifecycleEvent{Type: report.Added, PolicyReport: preport})
+
+	metricFam, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Errorf("unexpected Error: %s", err)
+	}
+
+	namespaces := findMetric(metricFam, "policy_report_namespace_compliance_score")
+	if namespaces == nil {
+		t.Fatalf("Metric not found: policy_report_namespace_compliance_score")
+	}
+
+	if value := namespaces.GetMetric()[0].GetGauge().GetValue(); value != 50 {
+		t.Errorf("expected a score of 50, got %v", value)
+	}
+
+	handler(report.LifecycleEvent{Type: report.Deleted, PolicyReport: preport})
+
+	metricFam, _ = prometheus.DefaultGatherer.Gather()
+
+	if namespaces := findMetric(metricFam, "policy_report_namespace_compliance_score"); namespaces != nil && len(namespaces.GetMetric()) > 0 {
+		t.Error("expected namespace scores are removed with the report")
+	}
+
+	cluster := findMetric(metricFam, "policy_report_cluster_compliance_score")
+	if value := cluster.GetMetric()[0].GetGauge().GetValue(); value != 100 {
+		t.Errorf("expected a cluster score of 100 without results, got %v", value)
+	}
+}
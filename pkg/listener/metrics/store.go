@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// StoreMetrics of the PolicyReport store size, pruning and write durations
+type StoreMetrics struct {
+	Rows   *prometheus.GaugeVec
+	Size   prometheus.Gauge
+	Pruned *prometheus.CounterVec
+	Writes *prometheus.HistogramVec
+}
+
+// RegisterStoreMetrics registers the store size, pruning and write metrics, existing metrics are reused
+func RegisterStoreMetrics() StoreMetrics {
+	return StoreMetrics{
+		Rows: registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "policy_reporter_store_rows",
+			Help: "Rows per table of the PolicyReport store",
+		}, []string{"table"})).(*prometheus.GaugeVec),
+		Size: registerGauge(prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "policy_reporter_store_size_bytes",
+			Help: "Used size of the PolicyReport store database",
+		})).(prometheus.Gauge),
+		Pruned: registerGauge(prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "policy_reporter_store_pruned_reports_total",
+			Help: "PolicyReports removed from the store because they exceeded the configured limit",
+		}, []string{"limit"})).(*prometheus.CounterVec),
+		Writes: registerGauge(prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "policy_reporter_store_write_duration_seconds",
+			Help:    "Duration of the add, update and remove operations of the PolicyReport store",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 14),
+		}, []string{"operation"})).(*prometheus.HistogramVec),
+	}
+}
@@ -0,0 +1,13 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RegisterSummaryDrift registers the counter of PolicyReports with a summary which does not match their results, an existing counter is reused
+func RegisterSummaryDrift() *prometheus.CounterVec {
+	return registerGauge(prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "policy_reporter_summary_drift_total",
+		Help: "PolicyReports with a summary which does not match their results, by the source of the producer",
+	}, []string{"source"})).(*prometheus.CounterVec)
+}
@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kyverno/policy-reporter/pkg/acknowledgment"
+	"github.com/kyverno/policy-reporter/pkg/health"
+)
+
+var (
+	lastSuccessDesc = prometheus.NewDesc(
+		"policy_reporter_target_last_success_timestamp_seconds",
+		"Unix time of the last successful delivery to a target",
+		[]string{"target"},
+		nil,
+	)
+	consecutiveFailuresDesc = prometheus.NewDesc(
+		"policy_reporter_target_consecutive_failures",
+		"Failed deliveries to a target since its last successful delivery",
+		[]string{"target"},
+		nil,
+	)
+	deliveriesDesc = prometheus.NewDesc(
+		"policy_reporter_target_deliveries_total",
+		"Deliveries to a target by HTTP status code, success or error for targets without HTTP response",
+		[]string{"target", "code"},
+		nil,
+	)
+)
+
+type targetHealthCollector struct {
+	deliveries *health.Deliveries
+}
+
+func (c *targetHealthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- lastSuccessDesc
+	ch <- consecutiveFailuresDesc
+	ch <- deliveriesDesc
+}
+
+func (c *targetHealthCollector) Collect(ch chan<- prometheus.Metric) {
+	for target, delivery := range c.deliveries.List() {
+		if delivery.LastSuccess != nil {
+			ch <- prometheus.MustNewConstMetric(lastSuccessDesc, prometheus.GaugeValue, float64(delivery.LastSuccess.UnixNano())/1e9, target)
+		}
+
+		ch <- prometheus.MustNewConstMetric(consecutiveFailuresDesc, prometheus.GaugeValue, float64(delivery.ConsecutiveFailures), target)
+	}
+
+	for target, codes := range c.deliveries.StatusCodes() {
+		for code, count := range codes {
+			ch <- prometheus.MustNewConstMetric(deliveriesDesc, prometheus.CounterValue, float64(count), target, code)
+		}
+	}
+}
+
+// RegisterTargetHealthMetrics exposes the last successful delivery, the consecutive failures and the status codes
+// of the recorded deliveries per target, an existing collector is reused
+func RegisterTargetHealthMetrics(deliveries *health.Deliveries) prometheus.Collector {
+	return registerGauge(&targetHealthCollector{deliveries: deliveries})
+}
+
+// RegisterTargetConnections registers the counter of the connections used for requests to HTTP based targets,
+// reused is true for pooled keep-alive connections. An existing counter is reused
+func RegisterTargetConnections() *prometheus.CounterVec {
+	return registerGauge(prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "policy_reporter_target_connections_total",
+		Help: "Connections used for requests to a target, reused is true for pooled keep-alive connections",
+	}, []string{"target", "reused"})).(*prometheus.CounterVec)
+}
+
+var (
+	unacknowledgedDesc = prometheus.NewDesc(
+		"policy_reporter_target_unacknowledged_results",
+		"Results delivered to a target and not acknowledged by the downstream system within the threshold",
+		[]string{"target"},
+		nil,
+	)
+	acknowledgedDesc = prometheus.NewDesc(
+		"policy_reporter_target_acknowledged_results_total",
+		"Results delivered to a target and acknowledged by the downstream system",
+		[]string{"target"},
+		nil,
+	)
+)
+
+type acknowledgmentCollector struct {
+	tracker *acknowledgment.Tracker
+}
+
+func (c *acknowledgmentCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- unacknowledgedDesc
+	ch <- acknowledgedDesc
+}
+
+func (c *acknowledgmentCollector) Collect(ch chan<- prometheus.Metric) {
+	for target, count := range c.tracker.Overdue() {
+		ch <- prometheus.MustNewConstMetric(unacknowledgedDesc, prometheus.GaugeValue, float64(count), target)
+	}
+
+	for target, count := range c.tracker.Acknowledgments() {
+		ch <- prometheus.MustNewConstMetric(acknowledgedDesc, prometheus.CounterValue, float64(count), target)
+	}
+}
+
+// RegisterAcknowledgmentMetrics exposes the unacknowledged deliveries older than the threshold and the acknowledged deliveries per target,
+// an existing collector is reused
+func RegisterAcknowledgmentMetrics(tracker *acknowledgment.Tracker) prometheus.Collector {
+	return registerGauge(&acknowledgmentCollector{tracker: tracker})
+}
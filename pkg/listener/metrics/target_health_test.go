@@ -0,0 +1,53 @@
+package metrics_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kyverno/policy-reporter/pkg/health"
+	"github.com/kyverno/policy-reporter/pkg/listener/metrics"
+)
+
+func Test_TargetHealthMetrics(t *testing.T) {
+	deliveries := health.NewDeliveries()
+	deliveries.RecordStatus("Webhook", 200, nil)
+	deliveries.RecordStatus("Webhook", 502, errors.New("status code 502"))
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics.RegisterTargetHealthMetrics(deliveries))
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			key := family.GetName()
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "code" {
+					key += "/" + l.GetValue()
+				}
+			}
+
+			if m.GetCounter() != nil {
+				values[key] = m.GetCounter().GetValue()
+			} else {
+				values[key] = m.GetGauge().GetValue()
+			}
+		}
+	}
+
+	if values["policy_reporter_target_consecutive_failures"] != 1 {
+		t.Errorf("expected 1 consecutive failure, got %v", values)
+	}
+	if values["policy_reporter_target_deliveries_total/502"] != 1 || values["policy_reporter_target_deliveries_total/200"] != 1 {
+		t.Errorf("expected deliveries by status code, got %v", values)
+	}
+	if values["policy_reporter_target_last_success_timestamp_seconds"] == 0 {
+		t.Errorf("expected last success timestamp, got %v", values)
+	}
+}
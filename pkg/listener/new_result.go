@@ -4,10 +4,13 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/kyverno/policy-reporter/pkg/cache"
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
 	"github.com/kyverno/policy-reporter/pkg/helper"
 	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/tracing"
 )
 
 const NewResults = "new_results_listener"
@@ -16,7 +19,32 @@ type ResultListener struct {
 	skipExisting bool
 	listener     []report.PolicyReportResultListener
 	cache        cache.Cache
+	dedupe       cache.Deduplicator
+	ownerDedupe  cache.Deduplicator
+	ownerKey     func(v1alpha2.PolicyReportResult) string
 	startUp      time.Time
+	reconcile    bool
+	restored     bool
+}
+
+// RegisterDeduplicator skips results already processed within the deduplication window,
+// even if their PolicyReport was deleted and recreated in the meantime
+func (l *ResultListener) RegisterDeduplicator(dedupe cache.Deduplicator) {
+	l.dedupe = dedupe
+}
+
+// RegisterOwnerDeduplicator sends results grouped by the key only once within the deduplication window,
+// e.g. the results of all replicas of a Deployment, results with an empty key are not grouped
+func (l *ResultListener) RegisterOwnerDeduplicator(dedupe cache.Deduplicator, key func(v1alpha2.PolicyReportResult) string) {
+	l.ownerDedupe = dedupe
+	l.ownerKey = key
+}
+
+// EnableReconciliation diffs pre existing reports against the cached results instead of skipping them,
+// reports without cached results are handled as new if the cache was restored from a previous run
+func (l *ResultListener) EnableReconciliation(restored bool) {
+	l.reconcile = true
+	l.restored = restored
 }
 
 func (l *ResultListener) RegisterListener(listener report.PolicyReportResultListener) {
@@ -35,24 +63,51 @@ func (l *ResultListener) Listen(event report.LifecycleEvent) {
 
 	var preExisted bool
 
+	existing := l.cache.GetResults(event.PolicyReport.GetID())
+
 	if event.Type == report.Added {
 		preExisted = event.PolicyReport.GetCreationTimestamp().Local().Before(l.startUp)
 
+		if preExisted && l.reconcile && (l.restored || len(existing) > 0) {
+			preExisted = false
+		}
+
 		if l.skipExisting && preExisted {
 			l.cache.AddReport(event.PolicyReport)
+			l.markProcessed(event.PolicyReport.GetResults())
 			return
 		}
 	}
 
-	wg := sync.WaitGroup{}
+	span := tracing.Start(event.Trace, "report.results")
+	defer span.End()
 
-	existing := l.cache.GetResults(event.PolicyReport.GetID())
+	var count int
+
+	wg := sync.WaitGroup{}
 
 	for _, r := range event.PolicyReport.GetResults() {
 		if helper.Contains(r.GetID(), existing) {
 			continue
 		}
 
+		if l.dedupe != nil && l.dedupe.Has(r.GetID()) {
+			continue
+		}
+
+		if l.ownerDedupe != nil {
+			if key := l.ownerKey(r); key != "" {
+				if l.ownerDedupe.Has(key) {
+					continue
+				}
+
+				l.ownerDedupe.Add(key)
+			}
+		}
+
+		count++
+		r.TraceParent = tracing.TraceParent(span.SpanContext())
+
 		wg.Add(len(l.listener))
 
 		for _, cb := range l.listener {
@@ -64,10 +119,26 @@ func (l *ResultListener) Listen(event report.LifecycleEvent) {
 	}
 
 	l.cache.AddReport(event.PolicyReport)
+	l.markProcessed(event.PolicyReport.GetResults())
+
+	span.SetAttributes(attribute.Int("results.new", count))
 
 	wg.Wait()
 }
 
+func (l *ResultListener) markProcessed(results []v1alpha2.PolicyReportResult) {
+	if l.dedupe == nil {
+		return
+	}
+
+	ids := make([]string, 0, len(results))
+	for _, r := range results {
+		ids = append(ids, r.GetID())
+	}
+
+	l.dedupe.Add(ids...)
+}
+
 func NewResultListener(skipExisting bool, rcache cache.Cache, startUp time.Time) *ResultListener {
 	return &ResultListener{
 		skipExisting: skipExisting,
@@ -4,6 +4,10 @@ import (
 	"testing"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
 	"github.com/kyverno/policy-reporter/pkg/cache"
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
 	"github.com/kyverno/policy-reporter/pkg/fixtures"
@@ -92,4 +96,112 @@ func Test_ResultListener(t *testing.T) {
 			t.Error("Expected Listener not be called with empty results")
 		}
 	})
+
+	t.Run("Ignore deduplicated Results of recreated Reports", func(t *testing.T) {
+		var called bool
+
+		slistener := listener.NewResultListener(false, cache.NewInMermoryCache(), time.Now())
+		slistener.RegisterDeduplicator(cache.NewInMemoryDeduplicator(time.Minute))
+		slistener.RegisterListener(func(_ v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult, b bool) {
+			called = true
+		})
+
+		slistener.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: preport1})
+		slistener.Listen(report.LifecycleEvent{Type: report.Deleted, PolicyReport: preport1})
+
+		called = false
+
+		slistener.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: preport1})
+
+		if called {
+			t.Error("Expected Listener not be called for deduplicated results")
+		}
+	})
+
+	t.Run("Deduplicate Results of the same Owner", func(t *testing.T) {
+		called := make([]string, 0)
+
+		replica := func(name string) *v1alpha2.PolicyReport {
+			result := fixtures.FailResult
+			result.ID = ""
+			result.Resources = []corev1.ObjectReference{{APIVersion: "v1", Kind: "Pod", Name: name, Namespace: "test", UID: types.UID(name)}}
+			result.Properties = map[string]string{v1alpha2.OwnerUIDKey: "deployment-uid"}
+
+			return &v1alpha2.PolicyReport{
+				ObjectMeta: v1.ObjectMeta{Name: "polr-" + name, Namespace: "test", CreationTimestamp: v1.Now()},
+				Results:    []v1alpha2.PolicyReportResult{result},
+			}
+		}
+
+		slistener := listener.NewResultListener(false, cache.NewInMermoryCache(), time.Now())
+		slistener.RegisterOwnerDeduplicator(cache.NewInMemoryDeduplicator(time.Minute), func(r v1alpha2.PolicyReportResult) string {
+			return r.Properties[v1alpha2.OwnerUIDKey]
+		})
+		slistener.RegisterListener(func(_ v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult, b bool) {
+			called = append(called, r.GetResource().Name)
+		})
+
+		slistener.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: replica("pod-a")})
+		slistener.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: replica("pod-b")})
+
+		if len(called) != 1 || called[0] != "pod-a" {
+			t.Errorf("Expected Listener to be called once for all replicas, got %v", called)
+		}
+	})
+
+	t.Run("Reconcile pre existing Reports with cached Results", func(t *testing.T) {
+		called := make([]string, 0)
+		var preExisted bool
+
+		rcache := cache.NewInMermoryCache()
+		rcache.AddReport(preport1)
+
+		slistener := listener.NewResultListener(true, rcache, time.Now().Add(time.Hour))
+		slistener.EnableReconciliation(false)
+		slistener.RegisterListener(func(_ v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult, b bool) {
+			called = append(called, r.GetID())
+			preExisted = b
+		})
+
+		slistener.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: preport2})
+
+		if len(called) != 1 || called[0] != fixtures.FailPodResult.GetID() {
+			t.Errorf("Expected Listener to be called only with the new FailPodResult, got %v", called)
+		}
+		if preExisted {
+			t.Error("Expected reconciled Result not to be marked as pre existing")
+		}
+	})
+
+	t.Run("Skip pre existing Reports without cached Results", func(t *testing.T) {
+		var called bool
+
+		slistener := listener.NewResultListener(true, cache.NewInMermoryCache(), time.Now().Add(time.Hour))
+		slistener.EnableReconciliation(false)
+		slistener.RegisterListener(func(_ v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult, b bool) {
+			called = true
+		})
+
+		slistener.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: preport1})
+
+		if called {
+			t.Error("Expected Listener not be called without a restored cache")
+		}
+	})
+
+	t.Run("Publish Reports created during a restart", func(t *testing.T) {
+		var called bool
+
+		slistener := listener.NewResultListener(true, cache.NewInMermoryCache(), time.Now().Add(time.Hour))
+		slistener.EnableReconciliation(true)
+		slistener.RegisterListener(func(_ v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult, b bool) {
+			called = true
+		})
+
+		slistener.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: preport1})
+
+		if !called {
+			t.Error("Expected Listener to be called for a report unknown to the restored cache")
+		}
+	})
 }
@@ -0,0 +1,131 @@
+package listener
+
+import (
+	"container/heap"
+	"sync"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+type queuedResult struct {
+	result v1alpha2.PolicyReportResult
+	seq    uint64
+}
+
+// resultHeap orders the results by priority, results with the same priority in the order they were queued
+type resultHeap []queuedResult
+
+func (h resultHeap) Len() int { return len(h) }
+
+func (h resultHeap) Less(i, j int) bool {
+	if h[i].result.Priority != h[j].result.Priority {
+		return h[i].result.Priority > h[j].result.Priority
+	}
+
+	return h[i].seq < h[j].seq
+}
+
+func (h resultHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *resultHeap) Push(x interface{}) { *h = append(*h, x.(queuedResult)) }
+
+func (h *resultHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = queuedResult{}
+	*h = old[:n-1]
+
+	return item
+}
+
+// priorityQueue is a bounded queue of results, the result with the highest priority is delivered first
+// so critical results do not wait behind a burst of info results
+type priorityQueue struct {
+	mx       *sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	items    resultHeap
+	size     int
+	seq      uint64
+	closed   bool
+	// idle workers waiting in Pop, each can take a result in addition to the queue size
+	idle int
+}
+
+// Push adds the result, blocks while the queue and all idle workers are busy and returns whether it was blocked.
+// A queue of size 0 hands the result over to an idle worker
+func (q *priorityQueue) Push(result v1alpha2.PolicyReportResult) bool {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	blocked := false
+	for len(q.items) >= q.size+q.idle && !q.closed {
+		blocked = true
+		q.notFull.Wait()
+	}
+
+	if q.closed {
+		return blocked
+	}
+
+	q.seq++
+	heap.Push(&q.items, queuedResult{result: result, seq: q.seq})
+	q.notEmpty.Signal()
+
+	return blocked
+}
+
+// Pop removes the result with the highest priority, blocks while the queue is empty.
+// Returns false if the queue is closed and all results were removed
+func (q *priorityQueue) Pop() (v1alpha2.PolicyReportResult, bool) {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	for len(q.items) == 0 && !q.closed {
+		q.idle++
+		q.notFull.Signal()
+		q.notEmpty.Wait()
+		q.idle--
+	}
+
+	if len(q.items) == 0 {
+		return v1alpha2.PolicyReportResult{}, false
+	}
+
+	item := heap.Pop(&q.items).(queuedResult)
+	q.notFull.Signal()
+
+	return item.result, true
+}
+
+// Len of the queued results
+func (q *priorityQueue) Len() int {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	return len(q.items)
+}
+
+// Close the queue, queued results are still returned by Pop
+func (q *priorityQueue) Close() {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	q.closed = true
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+}
+
+// newPriorityQueue creates a queue for up to size results, with size 0 Push blocks until a worker is free
+func newPriorityQueue(size int) *priorityQueue {
+	mx := new(sync.Mutex)
+
+	return &priorityQueue{
+		mx:       mx,
+		notEmpty: sync.NewCond(mx),
+		notFull:  sync.NewCond(mx),
+		items:    make(resultHeap, 0, size),
+		size:     size,
+	}
+}
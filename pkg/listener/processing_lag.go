@@ -0,0 +1,21 @@
+package listener
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+// NewProcessingLagListener observes the time between the observed change of a PolicyReport and the dispatch of its event,
+// events without observed time, e.g. of ingested reports, are ignored
+func NewProcessingLagListener(lag prometheus.Observer) report.PolicyReportListener {
+	return func(event report.LifecycleEvent) {
+		if event.Observed.IsZero() {
+			return
+		}
+
+		lag.Observe(time.Since(event.Observed).Seconds())
+	}
+}
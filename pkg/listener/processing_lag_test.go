@@ -0,0 +1,33 @@
+package listener_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kyverno/policy-reporter/pkg/listener"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+type observer []float64
+
+func (o *observer) Observe(value float64) {
+	*o = append(*o, value)
+}
+
+func Test_ProcessingLagListener(t *testing.T) {
+	var lag observer
+
+	slistener := listener.NewProcessingLagListener(prometheus.Observer(&lag))
+
+	slistener(report.LifecycleEvent{Type: report.Added, PolicyReport: preport1, Observed: time.Now().Add(-2 * time.Second)})
+	slistener(report.LifecycleEvent{Type: report.Added, PolicyReport: preport1})
+
+	if len(lag) != 1 {
+		t.Fatalf("expected only events with observed time, got %d observations", len(lag))
+	}
+	if lag[0] < 2 {
+		t.Errorf("expected lag of at least 2 seconds, got %f", lag[0])
+	}
+}
@@ -0,0 +1,53 @@
+package listener
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/target"
+)
+
+const ReportChanges = "report_changes_listener"
+
+// NewReportChangeListener sends each PolicyReport event with the summary of the previous version to the ReportClients,
+// PolicyReports created before the startup are skipped for clients with SkipExistingOnStartup
+func NewReportChangeListener(clients func() []target.Client, startUp time.Time) report.PolicyReportListener {
+	mx := new(sync.Mutex)
+	summaries := make(map[string]v1alpha2.PolicyReportSummary)
+
+	return func(event report.LifecycleEvent) {
+		id := event.PolicyReport.GetID()
+
+		mx.Lock()
+		previous := summaries[id]
+		if event.Type == report.Deleted {
+			delete(summaries, id)
+		} else {
+			summaries[id] = event.PolicyReport.GetSummary()
+		}
+		mx.Unlock()
+
+		preExisted := event.Type == report.Added && event.PolicyReport.GetCreationTimestamp().Local().Before(startUp)
+
+		wg := &sync.WaitGroup{}
+
+		for _, t := range clients() {
+			client, ok := t.(target.ReportClient)
+			if !ok || (preExisted && client.SkipExistingOnStartup()) || !client.ValidateReport(event.PolicyReport) {
+				continue
+			}
+
+			wg.Add(1)
+
+			go func(client target.ReportClient) {
+				defer wg.Done()
+
+				client.SendReport(event, previous)
+			}(client)
+		}
+
+		wg.Wait()
+	}
+}
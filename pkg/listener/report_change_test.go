@@ -0,0 +1,78 @@
+package listener_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/listener"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/target"
+)
+
+type reportClient struct {
+	client
+	mx       sync.Mutex
+	events   []report.Event
+	previous []v1alpha2.PolicyReportSummary
+}
+
+func (c *reportClient) SendReport(event report.LifecycleEvent, previous v1alpha2.PolicyReportSummary) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.events = append(c.events, event.Type)
+	c.previous = append(c.previous, previous)
+}
+
+func (c *reportClient) ValidateReport(rep v1alpha2.ReportInterface) bool {
+	return c.validated
+}
+
+func Test_ReportChangeListener(t *testing.T) {
+	c := &reportClient{client: client{validated: true}}
+	skipped := &reportClient{client: client{validated: false}}
+	resultClient := &client{validated: true}
+
+	rlistener := listener.NewReportChangeListener(func() []target.Client {
+		return []target.Client{c, skipped, resultClient}
+	}, time.Now().Add(-time.Hour))
+
+	rep := preport1.DeepCopy()
+	rlistener(report.LifecycleEvent{Type: report.Added, PolicyReport: rep})
+
+	updated := rep.DeepCopy()
+	updated.Summary = v1alpha2.PolicyReportSummary{Pass: 1}
+	rlistener(report.LifecycleEvent{Type: report.Updated, PolicyReport: updated})
+	rlistener(report.LifecycleEvent{Type: report.Deleted, PolicyReport: updated})
+
+	if len(c.events) != 3 || c.events[0] != report.Added || c.events[2] != report.Deleted {
+		t.Fatalf("unexpected events: %v", c.events)
+	}
+	if c.previous[0] != (v1alpha2.PolicyReportSummary{}) || c.previous[1] != rep.Summary || c.previous[2] != updated.Summary {
+		t.Errorf("unexpected previous summaries: %v", c.previous)
+	}
+	if len(skipped.events) > 0 || resultClient.Called {
+		t.Error("expected only validated ReportClients to be called")
+	}
+
+	t.Run("Skip existing", func(t *testing.T) {
+		c := &reportClient{client: client{validated: true, skipExistingOnStartup: true}}
+
+		rlistener := listener.NewReportChangeListener(func() []target.Client { return []target.Client{c} }, time.Now().Add(time.Hour))
+		rlistener(report.LifecycleEvent{Type: report.Added, PolicyReport: rep})
+
+		if len(c.events) > 0 {
+			t.Error("expected pre existing report to be skipped")
+		}
+	})
+	t.Run("Results are not sent", func(t *testing.T) {
+		c := &reportClient{client: client{validated: true}}
+
+		listener.NewSendResultListener([]target.Client{c}, report.NewMapper(make(map[string]string)))(rep, rep.Results[0], false)
+		if c.Called {
+			t.Error("expected no results for a ReportClient")
+		}
+	})
+}
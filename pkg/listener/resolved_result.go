@@ -0,0 +1,115 @@
+package listener
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/target"
+)
+
+const ResolvedResults = "resolved_results_listener"
+
+// ResolvedResultListener tracks the violations of each PolicyReport and calls the registered listeners
+// with the previous result if a violation passed, changed or was removed from its PolicyReport
+type ResolvedResultListener struct {
+	mx         *sync.Mutex
+	violations map[string]map[string]v1alpha2.PolicyReportResult
+	listener   []report.PolicyReportResultListener
+}
+
+func (l *ResolvedResultListener) RegisterListener(listener report.PolicyReportResultListener) {
+	l.listener = append(l.listener, listener)
+}
+
+func (l *ResolvedResultListener) Listen(event report.LifecycleEvent) {
+	current := make(map[string]v1alpha2.PolicyReportResult)
+	if event.Type != report.Deleted {
+		for _, r := range event.PolicyReport.GetResults() {
+			if isViolation(r) {
+				current[violationKey(event.PolicyReport, r)] = r
+			}
+		}
+	}
+
+	l.mx.Lock()
+	previous := l.violations[event.PolicyReport.GetID()]
+	if len(current) > 0 {
+		l.violations[event.PolicyReport.GetID()] = current
+	} else {
+		delete(l.violations, event.PolicyReport.GetID())
+	}
+	l.mx.Unlock()
+
+	wg := sync.WaitGroup{}
+
+	for key, r := range previous {
+		// a changed message is a new violation, the previous one is resolved
+		if c, ok := current[key]; ok && c.GetID() == r.GetID() {
+			continue
+		}
+
+		wg.Add(len(l.listener))
+
+		for _, cb := range l.listener {
+			go func(callback report.PolicyReportResultListener, result v1alpha2.PolicyReportResult) {
+				callback(event.PolicyReport, result, false)
+				wg.Done()
+			}(cb, r)
+		}
+	}
+
+	wg.Wait()
+}
+
+func isViolation(r v1alpha2.PolicyReportResult) bool {
+	return r.Result == v1alpha2.StatusFail || r.Result == v1alpha2.StatusWarn || r.Result == v1alpha2.StatusError
+}
+
+// violationKey identifies a result of a resource, policy and rule independent of its status and message
+func violationKey(rep v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult) string {
+	res := r.GetResource()
+	if res == nil {
+		res = rep.GetScope()
+	}
+	if res == nil {
+		return strings.Join([]string{r.Source, r.Policy, r.Rule}, "/")
+	}
+
+	return strings.Join([]string{r.Source, r.Policy, r.Rule, res.Kind, res.Name, string(res.UID)}, "/")
+}
+
+func NewResolvedResultListener() *ResolvedResultListener {
+	return &ResolvedResultListener{
+		mx:         new(sync.Mutex),
+		violations: make(map[string]map[string]v1alpha2.PolicyReportResult),
+		listener:   make([]report.PolicyReportResultListener, 0),
+	}
+}
+
+// NewResolveResultListener calls Resolve of each ResolvingClient the resolved result was validated for
+func NewResolveResultListener(clients func() []target.Client, mapper report.Mapper) report.PolicyReportResultListener {
+	return func(rep v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult, e bool) {
+		wg := &sync.WaitGroup{}
+
+		for _, t := range clients() {
+			client, ok := t.(target.ResolvingClient)
+			if !ok {
+				continue
+			}
+
+			wg.Add(1)
+
+			go func(client target.ResolvingClient) {
+				defer wg.Done()
+
+				if result, ok := prepareResult(client, mapper, rep, r, false); ok {
+					client.Resolve(client.Scrubber().Scrub(result))
+				}
+			}(client)
+		}
+
+		wg.Wait()
+	}
+}
@@ -0,0 +1,68 @@
+package listener_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/listener"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/target"
+)
+
+type resolvingClient struct {
+	client
+	resolved []string
+	mx       sync.Mutex
+}
+
+func (c *resolvingClient) Resolve(result v1alpha2.PolicyReportResult) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.resolved = append(c.resolved, result.GetID())
+}
+
+func Test_ResolvedResultListener(t *testing.T) {
+	c := &resolvingClient{client: client{validated: true}}
+
+	rlistener := listener.NewResolvedResultListener()
+	rlistener.RegisterListener(listener.NewResolveResultListener(func() []target.Client {
+		return []target.Client{&client{validated: true}, c}
+	}, report.NewMapper(make(map[string]string))))
+
+	rep := preport1.DeepCopy()
+	rlistener.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: rep})
+	if len(c.resolved) != 0 {
+		t.Fatalf("expected no resolved results, got %v", c.resolved)
+	}
+
+	rlistener.Listen(report.LifecycleEvent{Type: report.Updated, PolicyReport: rep})
+	if len(c.resolved) != 0 {
+		t.Fatalf("expected no resolved results for an unchanged report, got %v", c.resolved)
+	}
+
+	passed := rep.DeepCopy()
+	passed.Results[0].Result = v1alpha2.StatusPass
+	passed.Results[0].ID = "456"
+
+	rlistener.Listen(report.LifecycleEvent{Type: report.Updated, PolicyReport: passed})
+	if len(c.resolved) != 1 || c.resolved[0] != fixtures.FailResult.GetID() {
+		t.Fatalf("expected resolved fail result, got %v", c.resolved)
+	}
+
+	rlistener.Listen(report.LifecycleEvent{Type: report.Updated, PolicyReport: rep})
+	rlistener.Listen(report.LifecycleEvent{Type: report.Deleted, PolicyReport: rep})
+	if len(c.resolved) != 2 {
+		t.Fatalf("expected resolved result of the deleted report, got %v", c.resolved)
+	}
+
+	c.validated = false
+
+	rlistener.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: rep})
+	rlistener.Listen(report.LifecycleEvent{Type: report.Deleted, PolicyReport: rep})
+	if len(c.resolved) != 2 {
+		t.Errorf("expected no resolve call for a filtered result, got %v", c.resolved)
+	}
+}
@@ -3,15 +3,95 @@ package listener
 import (
 	"sync"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
 	"github.com/kyverno/policy-reporter/pkg/report"
 	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/tracing"
 
 	corev1 "k8s.io/api/core/v1"
 )
 
 const SendResults = "send_results_listener"
 
+// prepareResult maps the priority and scope of the result and validates it against the target,
+// ReportClients receive changed PolicyReports instead of single results
+func prepareResult(client target.Client, mapper report.Mapper, rep v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult, preExisted bool) (v1alpha2.PolicyReportResult, bool) {
+	if _, ok := client.(target.ReportClient); ok {
+		return result, false
+	}
+
+	// priorities declared by the policy metadata take precedence over the mapping
+	if result.Result == v1alpha2.StatusFail && result.Priority == v1alpha2.DefaultPriority {
+		result.Priority = mapper.ResolvePriority(result.Policy, result.Severity)
+	}
+
+	if !result.HasResource() && rep.GetScope() != nil {
+		result.Resources = []corev1.ObjectReference{*rep.GetScope()}
+	}
+
+	if (preExisted && (client.SkipExistingOnStartup() || !client.Backfill().Send(rep, result))) || !client.Validate(rep, result) {
+		return result, false
+	}
+
+	return result, true
+}
+
+// Matches evaluates a new result against the filters of the client without sending it,
+// ReportClients are evaluated by their report filter
+func Matches(client target.Client, mapper report.Mapper, rep v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) (v1alpha2.PolicyReportResult, bool) {
+	if c, ok := client.(target.ReportClient); ok {
+		return result, c.ValidateReport(rep)
+	}
+
+	return prepareResult(client, mapper, rep, result, false)
+}
+
+// send the scrubbed result to the client within a span of the delivery, the span is propagated by the TraceParent of the result
+func send(client target.Client, result v1alpha2.PolicyReportResult) {
+	span := tracing.StartClient(tracing.Extract(result.TraceParent), "target.send")
+	span.SetAttributes(attribute.String("target.name", client.Name()))
+	defer span.End()
+
+	result = client.Scrubber().Scrub(result)
+	result.TraceParent = tracing.TraceParent(span.SpanContext())
+
+	client.Send(result)
+}
+
+// sample applies the sampling rules of the client, the summary of the sampled results is sent when the window ends
+func sample(client target.Client, result v1alpha2.PolicyReportResult) bool {
+	sampler := client.Sampler()
+
+	return sampler == nil || sampler.Sample(result, func(summary v1alpha2.PolicyReportResult) {
+		send(client, summary)
+	})
+}
+
+// hold keeps the result until the next delivery window of the client, the digest of the held results is sent when the window starts
+func hold(client target.Client, result v1alpha2.PolicyReportResult) bool {
+	schedule := client.Schedule()
+
+	return schedule != nil && schedule.Hold(result, func(digest v1alpha2.PolicyReportResult) {
+		send(client, digest)
+	})
+}
+
+// group adds the result to its group if the client groups results, the merged result is sent when the window ends
+func group(client target.Client, result v1alpha2.PolicyReportResult) bool {
+	grouper := client.Grouper()
+	if grouper == nil {
+		return false
+	}
+
+	grouper.Group(result, func(merged v1alpha2.PolicyReportResult) {
+		send(client, merged)
+	})
+
+	return true
+}
+
 func NewSendResultListener(clients []target.Client, mapper report.Mapper) report.PolicyReportResultListener {
 	return func(rep v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult, e bool) {
 		wg := &sync.WaitGroup{}
@@ -21,19 +101,9 @@ func NewSendResultListener(clients []target.Client, mapper report.Mapper) report
 			go func(target target.Client, re v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult, preExisted bool) {
 				defer wg.Done()
 
-				if result.Result == v1alpha2.StatusFail {
-					result.Priority = mapper.ResolvePriority(result.Policy, result.Severity)
-				}
-
-				if !result.HasResource() && re.GetScope() != nil {
-					result.Resources = []corev1.ObjectReference{*re.GetScope()}
-				}
-
-				if (preExisted && target.SkipExistingOnStartup()) || !target.Validate(re, result) {
-					return
+				if result, ok := prepareResult(target, mapper, re, result, preExisted); ok && sample(target, result) && !hold(target, result) && !group(target, result) {
+					send(target, result)
 				}
-
-				target.Send(result)
 			}(t, rep, r, e)
 		}
 
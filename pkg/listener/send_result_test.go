@@ -2,10 +2,12 @@ package listener_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
 	"github.com/kyverno/policy-reporter/pkg/fixtures"
 	"github.com/kyverno/policy-reporter/pkg/listener"
+	"github.com/kyverno/policy-reporter/pkg/maintenance"
 	"github.com/kyverno/policy-reporter/pkg/report"
 	"github.com/kyverno/policy-reporter/pkg/target"
 )
@@ -14,6 +16,11 @@ type client struct {
 	Called                bool
 	skipExistingOnStartup bool
 	validated             bool
+	sampler               *target.Sampler
+	grouper               *target.Grouper
+	schedule              *target.Schedule
+	scrubber              *target.Scrubber
+	backfill              *target.Backfill
 }
 
 func (c *client) Send(result v1alpha2.PolicyReportResult) {
@@ -36,6 +43,34 @@ func (c *client) SkipExistingOnStartup() bool {
 	return c.skipExistingOnStartup
 }
 
+func (c *client) Concurrency() int {
+	return 0
+}
+
+func (c *client) NotificationTTL() time.Duration {
+	return 0
+}
+
+func (c *client) Sampler() *target.Sampler {
+	return c.sampler
+}
+
+func (c *client) Grouper() *target.Grouper {
+	return c.grouper
+}
+
+func (c *client) Schedule() *target.Schedule {
+	return c.schedule
+}
+
+func (c *client) Backfill() *target.Backfill {
+	return c.backfill
+}
+
+func (c *client) Scrubber() *target.Scrubber {
+	return c.scrubber
+}
+
 func (c client) Validate(rep v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) bool {
 	return c.validated
 }
@@ -59,6 +94,19 @@ func Test_SendResultListener(t *testing.T) {
 			t.Error("Expected Send not to be called")
 		}
 	})
+	t.Run("Hold Result outside of the delivery windows", func(t *testing.T) {
+		tomorrow := time.Now().UTC().AddDate(0, 0, 1).Weekday()
+		c := &client{validated: true, schedule: target.NewSchedule("test", []maintenance.QuietHours{{Days: []time.Weekday{tomorrow}, From: 0, To: 24 * 60, Location: time.UTC}})}
+		slistener := listener.NewSendResultListener([]target.Client{c}, report.NewMapper(make(map[string]string)))
+		slistener(preport1, fixtures.FailResult, false)
+
+		if c.Called {
+			t.Error("Expected Send not to be called")
+		}
+		if c.schedule.Held() != 1 {
+			t.Errorf("Expected 1 held result, got %d", c.schedule.Held())
+		}
+	})
 	t.Run("Don't Send pre existing Result when skipExistingOnStartup is true", func(t *testing.T) {
 		c := &client{skipExistingOnStartup: true}
 		slistener := listener.NewSendResultListener([]target.Client{c}, report.NewMapper(make(map[string]string)))
@@ -68,4 +116,46 @@ func Test_SendResultListener(t *testing.T) {
 			t.Error("Expected Send not to be called")
 		}
 	})
+	t.Run("Send pre existing Result within the backfill window", func(t *testing.T) {
+		backfill, _ := target.ParseBackfill("since=24h")
+
+		c := &client{validated: true, skipExistingOnStartup: backfill.None(), backfill: backfill}
+		slistener := listener.NewSendResultListener([]target.Client{c}, report.NewMapper(make(map[string]string)))
+
+		result := fixtures.FailResult
+		result.Timestamp.Seconds = time.Now().Add(-48 * time.Hour).Unix()
+		slistener(preport1, result, true)
+
+		if c.Called {
+			t.Error("Expected Send not to be called for a result before the window")
+		}
+
+		result.Timestamp.Seconds = time.Now().Add(-time.Hour).Unix()
+		slistener(preport1, result, true)
+
+		if !c.Called {
+			t.Error("Expected Send to be called for a result within the window")
+		}
+	})
+}
+
+func Test_MatchesPriority(t *testing.T) {
+	c := &client{validated: true}
+	mapper := report.NewMapper(map[string]string{fixtures.FailResult.Policy: "warning"})
+
+	t.Run("Mapped Priority", func(t *testing.T) {
+		result, ok := listener.Matches(c, mapper, preport1, fixtures.FailResult)
+		if !ok || result.Priority != v1alpha2.WarningPriority {
+			t.Errorf("Expected matched result with mapped priority, got %s", result.Priority)
+		}
+	})
+	t.Run("Declared Priority", func(t *testing.T) {
+		declared := fixtures.FailResult
+		declared.Priority = v1alpha2.CriticalPriority
+
+		result, _ := listener.Matches(c, mapper, preport1, declared)
+		if result.Priority != v1alpha2.CriticalPriority {
+			t.Errorf("Expected declared priority to take precedence, got %s", result.Priority)
+		}
+	})
 }
@@ -0,0 +1,30 @@
+package listener
+
+import (
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/sharding"
+)
+
+// NewShardedResultListener forwards only results of namespaces the current replica is responsible for,
+// ClusterPolicyReport results are handled by the owner of the empty namespace
+func NewShardedResultListener(sharder sharding.Sharder, callback report.PolicyReportResultListener) report.PolicyReportResultListener {
+	return func(rep v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult, preExisted bool) {
+		if !sharder.Owns(rep.GetNamespace()) {
+			return
+		}
+
+		callback(rep, r, preExisted)
+	}
+}
+
+// NewShardedReportListener forwards only PolicyReports of namespaces the current replica is responsible for
+func NewShardedReportListener(sharder sharding.Sharder, callback report.PolicyReportListener) report.PolicyReportListener {
+	return func(event report.LifecycleEvent) {
+		if !sharder.Owns(event.PolicyReport.GetNamespace()) {
+			return
+		}
+
+		callback(event)
+	}
+}
@@ -0,0 +1,44 @@
+package listener_test
+
+import (
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/listener"
+)
+
+type sharder struct {
+	owned string
+}
+
+func (s sharder) Owns(key string) bool {
+	return s.owned == key
+}
+
+func Test_ShardedResultListener(t *testing.T) {
+	t.Run("Forward Result of owned Namespace", func(t *testing.T) {
+		var called bool
+
+		slistener := listener.NewShardedResultListener(sharder{owned: preport1.GetNamespace()}, func(_ v1alpha2.ReportInterface, _ v1alpha2.PolicyReportResult, _ bool) {
+			called = true
+		})
+		slistener(preport1, fixtures.FailResult, false)
+
+		if !called {
+			t.Error("Expected callback to be called")
+		}
+	})
+	t.Run("Skip Result of foreign Namespace", func(t *testing.T) {
+		var called bool
+
+		slistener := listener.NewShardedResultListener(sharder{owned: "other"}, func(_ v1alpha2.ReportInterface, _ v1alpha2.PolicyReportResult, _ bool) {
+			called = true
+		})
+		slistener(preport1, fixtures.FailResult, false)
+
+		if called {
+			t.Error("Expected callback not to be called")
+		}
+	})
+}
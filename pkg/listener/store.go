@@ -1,31 +1,87 @@
 package listener
 
 import (
-	"log"
+	"go.opentelemetry.io/otel/attribute"
 
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
 	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/tracing"
 )
 
 const Store = "store_listener"
 
-func NewStoreListener(store report.PolicyReportStore) report.PolicyReportListener {
+// NewStoreListener persists reports in the store, with a filter only the matching results are stored
+func NewStoreListener(store report.PolicyReportStore, filter *report.ResultFilter) report.PolicyReportListener {
 	return func(event report.LifecycleEvent) {
+		span := tracing.Start(event.Trace, "store.write")
+		span.SetAttributes(attribute.String("store.operation", event.Type.String()))
+		defer span.End()
+
 		if event.Type == report.Deleted {
-			logOnError("remove", event.PolicyReport.GetName(), store.Remove(event.PolicyReport.GetID()))
+			tracing.RecordError(span, logOnError("remove", event.PolicyReport.GetName(), store.Remove(event.PolicyReport.GetID())))
 			return
 		}
 
+		polr := filterReport(event.PolicyReport, filter)
+
 		if event.Type == report.Updated {
-			logOnError("update", event.PolicyReport.GetName(), store.Update(event.PolicyReport))
+			tracing.RecordError(span, logOnError("update", polr.GetName(), store.Update(polr)))
 			return
 		}
 
-		logOnError("add", event.PolicyReport.GetName(), store.Add(event.PolicyReport))
+		tracing.RecordError(span, logOnError("add", polr.GetName(), store.Add(polr)))
+	}
+}
+
+func filterReport(polr v1alpha2.ReportInterface, filter *report.ResultFilter) v1alpha2.ReportInterface {
+	if filter == nil {
+		return polr
+	}
+
+	results := make([]v1alpha2.PolicyReportResult, 0, len(polr.GetResults()))
+	summary := v1alpha2.PolicyReportSummary{}
+
+	for _, result := range polr.GetResults() {
+		if !filter.ValidateReportResult(polr, result) {
+			continue
+		}
+
+		results = append(results, result)
+
+		switch result.Result {
+		case v1alpha2.StatusPass:
+			summary.Pass++
+		case v1alpha2.StatusSkip:
+			summary.Skip++
+		case v1alpha2.StatusWarn:
+			summary.Warn++
+		case v1alpha2.StatusFail:
+			summary.Fail++
+		case v1alpha2.StatusError:
+			summary.Error++
+		}
+	}
+
+	switch r := polr.(type) {
+	case *v1alpha2.PolicyReport:
+		rep := r.DeepCopy()
+		rep.Results = results
+		rep.Summary = summary
+		return rep
+	case *v1alpha2.ClusterPolicyReport:
+		rep := r.DeepCopy()
+		rep.Results = results
+		rep.Summary = summary
+		return rep
 	}
+
+	return polr
 }
 
-func logOnError(operation, name string, err error) {
+func logOnError(operation, name string, err error) error {
 	if err != nil {
-		log.Printf("[ERROR] Failed to %s Policy Report %s (%s)\n", operation, name, err.Error())
+		logger.Errorf("Failed to %s Policy Report %s (%s)", operation, name, err.Error())
 	}
+
+	return err
 }
@@ -3,6 +3,7 @@ package listener_test
 import (
 	"testing"
 
+	"github.com/kyverno/policy-reporter/pkg/expression"
 	"github.com/kyverno/policy-reporter/pkg/listener"
 	"github.com/kyverno/policy-reporter/pkg/report"
 )
@@ -11,7 +12,7 @@ func Test_StoreListener(t *testing.T) {
 	store := report.NewPolicyReportStore()
 
 	t.Run("Save New Report", func(t *testing.T) {
-		slistener := listener.NewStoreListener(store)
+		slistener := listener.NewStoreListener(store, nil)
 		slistener(report.LifecycleEvent{Type: report.Added, PolicyReport: preport1})
 
 		if _, ok := store.Get(preport1.GetID()); !ok {
@@ -19,7 +20,7 @@ func Test_StoreListener(t *testing.T) {
 		}
 	})
 	t.Run("Update Modified Report", func(t *testing.T) {
-		slistener := listener.NewStoreListener(store)
+		slistener := listener.NewStoreListener(store, nil)
 		slistener(report.LifecycleEvent{Type: report.Updated, PolicyReport: preport2})
 
 		if preport, ok := store.Get(preport2.GetID()); !ok && len(preport.GetResults()) == 2 {
@@ -27,7 +28,7 @@ func Test_StoreListener(t *testing.T) {
 		}
 	})
 	t.Run("Remove Deleted Report", func(t *testing.T) {
-		slistener := listener.NewStoreListener(store)
+		slistener := listener.NewStoreListener(store, nil)
 		slistener(report.LifecycleEvent{Type: report.Deleted, PolicyReport: preport2})
 
 		if _, ok := store.Get(preport2.GetID()); ok {
@@ -35,3 +36,29 @@ func Test_StoreListener(t *testing.T) {
 		}
 	})
 }
+
+func Test_FilteredStoreListener(t *testing.T) {
+	store := report.NewPolicyReportStore()
+
+	expr, _ := expression.Compile(`result.resource.kind == "Deployment"`)
+
+	filter := report.NewResultFilter()
+	filter.AddReportValidation(expr.Validate)
+
+	slistener := listener.NewStoreListener(store, filter)
+	slistener(report.LifecycleEvent{Type: report.Added, PolicyReport: preport2})
+
+	stored, ok := store.Get(preport2.GetID())
+	if !ok {
+		t.Fatal("Expected Report to be stored")
+	}
+	if len(stored.GetResults()) != 1 {
+		t.Errorf("Expected only the matching result to be stored, got %d", len(stored.GetResults()))
+	}
+	if stored.GetSummary().Fail != 1 || stored.GetSummary().Pass != 0 {
+		t.Errorf("Expected summary to match the stored results, got %+v", stored.GetSummary())
+	}
+	if len(preport2.GetResults()) != 2 {
+		t.Error("Expected the original report to be unchanged")
+	}
+}
@@ -0,0 +1,92 @@
+package loadgen
+
+import (
+	"context"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	wgpolicyk8sv1alpha2 "github.com/kyverno/policy-reporter/pkg/crd/client/clientset/versioned/typed/policyreport/v1alpha2"
+)
+
+type crdClient struct {
+	reports    wgpolicyk8sv1alpha2.Wgpolicyk8sV1alpha2Interface
+	namespaces v1.NamespaceInterface
+	mx         *sync.Mutex
+	existing   map[string]bool
+}
+
+// Apply creates the report or updates the existing report, the namespace is created if it does not exist
+func (c *crdClient) Apply(ctx context.Context, report *v1alpha2.PolicyReport) (bool, error) {
+	if err := c.ensureNamespace(ctx, report.Namespace); err != nil {
+		return false, err
+	}
+
+	polr := c.reports.PolicyReports(report.Namespace)
+
+	_, err := polr.Create(ctx, report, metav1.CreateOptions{})
+	if err == nil {
+		return true, nil
+	}
+	if !errors.IsAlreadyExists(err) {
+		return false, err
+	}
+
+	existing, err := polr.Get(ctx, report.Name, metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	report.ResourceVersion = existing.ResourceVersion
+	_, err = polr.Update(ctx, report, metav1.UpdateOptions{})
+
+	return false, err
+}
+
+func (c *crdClient) Delete(ctx context.Context, namespace, name string) error {
+	err := c.reports.PolicyReports(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (c *crdClient) ensureNamespace(ctx context.Context, name string) error {
+	if c.namespaces == nil {
+		return nil
+	}
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if c.existing[name] {
+		return nil
+	}
+
+	_, err := c.namespaces.Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{Label: "true"}},
+	}, metav1.CreateOptions{})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	c.existing[name] = true
+
+	return nil
+}
+
+// NewCRDClient creates a Client applying the reports with the PolicyReport CRD client.
+// Missing namespaces are created with the loadgen label if a namespace client is given
+func NewCRDClient(reports wgpolicyk8sv1alpha2.Wgpolicyk8sV1alpha2Interface, namespaces v1.NamespaceInterface) Client {
+	return &crdClient{
+		reports:    reports,
+		namespaces: namespaces,
+		mx:         new(sync.Mutex),
+		existing:   make(map[string]bool),
+	}
+}
@@ -0,0 +1,262 @@
+// Package loadgen generates synthetic PolicyReports at a configurable rate and size, for capacity planning
+// of large clusters and as input of the benchmarks of the processing pipeline
+package loadgen
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/fasthash/fnv1a"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// Label of all generated PolicyReports and namespaces
+const Label = "policy-reporter.io/loadgen"
+
+// Defaults of the Options
+const (
+	DefaultNamespaces = 10
+	DefaultReports    = 100
+	DefaultResults    = 10
+	DefaultFailRatio  = 0.2
+	DefaultSource     = "loadgen"
+	DefaultPrefix     = "loadgen-"
+)
+
+// policies per generated source, results of a report are distributed over these policies
+const policies = 25
+
+var severities = []v1alpha2.PolicySeverity{
+	v1alpha2.SeverityInfo,
+	v1alpha2.SeverityLow,
+	v1alpha2.SeverityMedium,
+	v1alpha2.SeverityHigh,
+	v1alpha2.SeverityCritical,
+}
+
+// Options of the generated load
+type Options struct {
+	// Namespaces the reports are distributed over
+	Namespaces int
+	// Reports in total, each report belongs to one resource
+	Reports int
+	// Results of each report
+	Results int
+	// FailRatio of the results, the other results pass
+	FailRatio float64
+	// Rate of the applied reports per second, unlimited if 0
+	Rate float64
+	// Updates of all reports after they were created, each update changes the status of some results
+	Updates int
+	Source  string
+	// Prefix of the namespaces
+	Prefix string
+}
+
+// WithDefaults returns the options with the defaults of all unset values
+func (o Options) WithDefaults() Options {
+	if o.Namespaces <= 0 {
+		o.Namespaces = DefaultNamespaces
+	}
+	if o.Reports <= 0 {
+		o.Reports = DefaultReports
+	}
+	if o.Results <= 0 {
+		o.Results = DefaultResults
+	}
+	if o.FailRatio <= 0 || o.FailRatio > 1 {
+		o.FailRatio = DefaultFailRatio
+	}
+	if o.Source == "" {
+		o.Source = DefaultSource
+	}
+	if o.Prefix == "" {
+		o.Prefix = DefaultPrefix
+	}
+
+	return o
+}
+
+// Namespace of the report with the index
+func (o Options) Namespace(index int) string {
+	o = o.WithDefaults()
+
+	return o.Prefix + strconv.Itoa(index%o.Namespaces)
+}
+
+// Report with the index in the given revision, the same index and revision always create the same report.
+// Each revision changes the status of about a tenth of the results
+func (o Options) Report(index, revision int) *v1alpha2.PolicyReport {
+	o = o.WithDefaults()
+
+	namespace := o.Namespace(index)
+	resource := corev1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Name:       fmt.Sprintf("pod-%d", index),
+		Namespace:  namespace,
+		UID:        types.UID(fmt.Sprintf("%s-%d", o.Source, index)),
+	}
+
+	now := time.Now()
+	results := make([]v1alpha2.PolicyReportResult, 0, o.Results)
+	summary := v1alpha2.PolicyReportSummary{}
+
+	for i := 0; i < o.Results; i++ {
+		status := v1alpha2.PolicyResult(v1alpha2.StatusPass)
+		if o.fails(index, i, revision) {
+			status = v1alpha2.StatusFail
+			summary.Fail++
+		} else {
+			summary.Pass++
+		}
+
+		policy := i % policies
+
+		results = append(results, v1alpha2.PolicyReportResult{
+			Source:    o.Source,
+			Policy:    fmt.Sprintf("%s-policy-%d", o.Source, policy),
+			Rule:      fmt.Sprintf("rule-%d", i),
+			Message:   fmt.Sprintf("synthetic result %d of %s", i, resource.Name),
+			Result:    status,
+			Severity:  severities[policy%len(severities)],
+			Category:  "Load Generation",
+			Scored:    true,
+			Resources: []corev1.ObjectReference{resource},
+			Timestamp: metav1.Timestamp{Seconds: now.Unix()},
+		})
+	}
+
+	return &v1alpha2.PolicyReport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%d", o.Source, index),
+			Namespace: namespace,
+			Labels:    map[string]string{Label: "true"},
+		},
+		Scope:   &resource,
+		Summary: summary,
+		Results: results,
+	}
+}
+
+// fails decides the status of the result, a revision flips the status of the results whose hash matches the revision
+func (o Options) fails(index, result, revision int) bool {
+	h := fnv1a.HashUint64(uint64(index))
+	h = fnv1a.AddUint64(h, uint64(result))
+
+	fail := float64(h%1000) < o.FailRatio*1000
+	if revision > 0 && (h/1000)%10 == uint64(revision%10) {
+		fail = !fail
+	}
+
+	return fail
+}
+
+// Client applies the generated PolicyReports
+type Client interface {
+	// Apply creates or updates the report, returns true if it was created
+	Apply(ctx context.Context, report *v1alpha2.PolicyReport) (bool, error)
+	Delete(ctx context.Context, namespace, name string) error
+}
+
+// Stats of a run
+type Stats struct {
+	Created  int
+	Updated  int
+	Deleted  int
+	Results  int
+	Errors   int
+	Duration time.Duration
+}
+
+// Rate of the applied reports per second
+func (s Stats) Rate() float64 {
+	if s.Duration <= 0 {
+		return 0
+	}
+
+	return float64(s.Created+s.Updated) / s.Duration.Seconds()
+}
+
+func (s Stats) String() string {
+	return fmt.Sprintf("created %d and updated %d reports with %d results in %s (%.1f reports/s), %d errors", s.Created, s.Updated, s.Results, s.Duration.Round(time.Millisecond), s.Rate(), s.Errors)
+}
+
+// Run creates all reports and updates them Updates times with the configured rate, errors are counted and skipped.
+// A canceled context stops the run and returns the stats so far
+func Run(ctx context.Context, client Client, options Options) Stats {
+	options = options.WithDefaults()
+
+	var tick <-chan time.Time
+	if options.Rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / options.Rate))
+		defer ticker.Stop()
+
+		tick = ticker.C
+	}
+
+	stats := Stats{}
+	started := time.Now()
+
+	for revision := 0; revision <= options.Updates; revision++ {
+		for i := 0; i < options.Reports; i++ {
+			if tick != nil {
+				select {
+				case <-ctx.Done():
+				case <-tick:
+				}
+			}
+			if ctx.Err() != nil {
+				stats.Duration = time.Since(started)
+				return stats
+			}
+
+			created, err := client.Apply(ctx, options.Report(i, revision))
+			switch {
+			case err != nil:
+				stats.Errors++
+			case created:
+				stats.Created++
+				stats.Results += options.Results
+			default:
+				stats.Updated++
+				stats.Results += options.Results
+			}
+		}
+	}
+
+	stats.Duration = time.Since(started)
+
+	return stats
+}
+
+// Cleanup deletes all reports of the options
+func Cleanup(ctx context.Context, client Client, options Options) Stats {
+	options = options.WithDefaults()
+
+	stats := Stats{}
+	started := time.Now()
+
+	for i := 0; i < options.Reports; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if err := client.Delete(ctx, options.Namespace(i), fmt.Sprintf("%s-%d", options.Source, i)); err != nil {
+			stats.Errors++
+			continue
+		}
+
+		stats.Deleted++
+	}
+
+	stats.Duration = time.Since(started)
+
+	return stats
+}
@@ -0,0 +1,68 @@
+package loadgen_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/client/clientset/versioned/fake"
+	"github.com/kyverno/policy-reporter/pkg/loadgen"
+)
+
+func Test_Report(t *testing.T) {
+	options := loadgen.Options{Namespaces: 3, Results: 50, FailRatio: 0.5}
+
+	report := options.Report(4, 0)
+	if report.Namespace != "loadgen-1" || report.Name != "loadgen-4" || report.Labels[loadgen.Label] != "true" {
+		t.Errorf("unexpected report %s/%s", report.Namespace, report.Name)
+	}
+	if len(report.Results) != 50 || report.Summary.Pass+report.Summary.Fail != 50 {
+		t.Errorf("expected 50 results in the summary, got %+v", report.Summary)
+	}
+	if report.Summary.Fail == 0 || report.Summary.Pass == 0 {
+		t.Errorf("expected pass and fail results, got %+v", report.Summary)
+	}
+
+	if again := options.Report(4, 0); again.Summary != report.Summary {
+		t.Error("expected the same report for the same index and revision")
+	}
+
+	changed := 0
+	for i, r := range options.Report(4, 1).Results {
+		if r.Result != report.Results[i].Result {
+			changed++
+		}
+	}
+	if changed == 0 || changed == 50 {
+		t.Errorf("expected an update to change some results, got %d changes", changed)
+	}
+}
+
+func Test_Run(t *testing.T) {
+	client := loadgen.NewCRDClient(fake.NewSimpleClientset().Wgpolicyk8sV1alpha2(), k8sfake.NewSimpleClientset().CoreV1().Namespaces())
+	options := loadgen.Options{Namespaces: 2, Reports: 10, Results: 5, Updates: 1}
+
+	stats := loadgen.Run(context.Background(), client, options)
+	if stats.Created != 10 || stats.Updated != 10 || stats.Results != 100 || stats.Errors != 0 {
+		t.Errorf("unexpected stats %s", stats)
+	}
+
+	stats = loadgen.Cleanup(context.Background(), client, options)
+	if stats.Deleted != 10 || stats.Errors != 0 {
+		t.Errorf("unexpected cleanup stats %+v", stats)
+	}
+}
+
+func Test_RunRate(t *testing.T) {
+	client := loadgen.NewCRDClient(fake.NewSimpleClientset().Wgpolicyk8sV1alpha2(), nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	stats := loadgen.Run(ctx, client, loadgen.Options{Reports: 100, Rate: 50})
+	if stats.Created == 0 || stats.Created > 10 {
+		t.Errorf("expected the rate to limit the created reports, got %d", stats.Created)
+	}
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// LevelsPath lists and changes the log levels per component
+const LevelsPath = "/admin/log-levels"
+
+// LevelChange of a component, an empty component changes the default level
+type LevelChange struct {
+	Component string `json:"component"`
+	Level     string `json:"level"`
+}
+
+// Handler of the requests sent to LevelsPath, PUT expects a LevelChange
+func (l *Logger) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			respond(w, http.StatusOK, l.ComponentLevels())
+		case http.MethodPut:
+			change := LevelChange{}
+			if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, 1<<20)).Decode(&change); err != nil {
+				respond(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+				return
+			}
+
+			if change.Component == "" {
+				change.Component = Default
+			}
+
+			if err := l.SetLevel(change.Component, change.Level); err != nil {
+				respond(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+				return
+			}
+
+			l.Component(Default).Infof("log level of component %s changed to %s", change.Component, change.Level)
+
+			respond(w, http.StatusOK, l.ComponentLevels())
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			respond(w, http.StatusMethodNotAllowed, map[string]string{"message": fmt.Sprintf("method %s not allowed", req.Method)})
+		}
+	}
+}
+
+// respond with the JSON encoded body, the helper package logs with this package and can not be used here
+func respond(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,267 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/kyverno/policy-reporter/pkg/secret"
+)
+
+// Encodings of the log output
+const (
+	Console = "console"
+	JSON    = "json"
+)
+
+// Levels in ascending order
+const (
+	Debug   = "debug"
+	Info    = "info"
+	Warning = "warning"
+	Error   = "error"
+)
+
+// Default is the component name of the level used for all components without an explicit level
+const Default = "default"
+
+// Components with a dedicated name, all other packages of the module use their package directory as component
+const (
+	Listener = "listener"
+	Targets  = "targets"
+	API      = "api"
+	Store    = "store"
+)
+
+var levels = map[string]zapcore.Level{
+	Debug:   zapcore.DebugLevel,
+	Info:    zapcore.InfoLevel,
+	Warning: zapcore.WarnLevel,
+	Error:   zapcore.ErrorLevel,
+}
+
+// Levels returns all supported levels in ascending order
+func Levels() []string {
+	return []string{Debug, Info, Warning, Error}
+}
+
+func parseLevel(level string) (zapcore.Level, error) {
+	if l, ok := levels[strings.ToLower(level)]; ok {
+		return l, nil
+	}
+
+	return zapcore.InfoLevel, fmt.Errorf("unknown log level '%s', expected one of %s", level, strings.Join(Levels(), ", "))
+}
+
+func levelName(level zapcore.Level) string {
+	if level == zapcore.WarnLevel {
+		return Warning
+	}
+
+	return level.String()
+}
+
+// Logger writes the entries of the component loggers with the configured encoding,
+// each component has an AtomicLevel to change its level at runtime
+type Logger struct {
+	mx     sync.RWMutex
+	core   zapcore.Core
+	level  zap.AtomicLevel
+	levels map[string]zap.AtomicLevel
+}
+
+// levelOf the component, components without explicit level use the Default level
+func (l *Logger) levelOf(component string) zap.AtomicLevel {
+	l.mx.RLock()
+	defer l.mx.RUnlock()
+
+	if level, ok := l.levels[component]; ok {
+		return level
+	}
+
+	return l.level
+}
+
+// SetLevel of the component, the Default component changes the level of all components without explicit level
+func (l *Logger) SetLevel(component, level string) error {
+	s, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	if component == "" || component == Default {
+		l.level.SetLevel(s)
+		return nil
+	}
+
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	if current, ok := l.levels[component]; ok {
+		current.SetLevel(s)
+		return nil
+	}
+
+	l.levels[component] = zap.NewAtomicLevelAt(s)
+
+	return nil
+}
+
+// ComponentLevels returns the current level of the Default and each configured component
+func (l *Logger) ComponentLevels() map[string]string {
+	l.mx.RLock()
+	defer l.mx.RUnlock()
+
+	list := map[string]string{Default: levelName(l.level.Level())}
+	for component, level := range l.levels {
+		list[component] = levelName(level.Level())
+	}
+
+	return list
+}
+
+// Component returns the named logger of the component
+func (l *Logger) Component(name string) *zap.SugaredLogger {
+	return newComponent(name, func() *Logger { return l })
+}
+
+// componentCore filters the entries by the level of its component and writes them with the core of the current Logger,
+// so package level loggers created before Use follow the configured output
+type componentCore struct {
+	name   string
+	logger func() *Logger
+	fields []zapcore.Field
+}
+
+func (c *componentCore) Enabled(level zapcore.Level) bool {
+	return c.logger().levelOf(c.name).Enabled(level)
+}
+
+func (c *componentCore) With(fields []zapcore.Field) zapcore.Core {
+	return &componentCore{
+		name:   c.name,
+		logger: c.logger,
+		fields: append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+func (c *componentCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+
+	return checked
+}
+
+func (c *componentCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = secret.Redact(entry.Message)
+
+	return c.logger().core.Write(entry, append(c.fields[:len(c.fields):len(c.fields)], fields...))
+}
+
+func (c *componentCore) Sync() error {
+	return c.logger().core.Sync()
+}
+
+func newComponent(name string, logger func() *Logger) *zap.SugaredLogger {
+	return zap.New(&componentCore{name: name, logger: logger}, zap.AddCaller()).Named(name).Sugar()
+}
+
+func encoder(encoding string) zapcore.Encoder {
+	if encoding == JSON {
+		return zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+			TimeKey:        "time",
+			LevelKey:       "level",
+			NameKey:        "component",
+			CallerKey:      "caller",
+			MessageKey:     "msg",
+			LineEnding:     zapcore.DefaultLineEnding,
+			EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
+			EncodeDuration: zapcore.StringDurationEncoder,
+			EncodeCaller:   zapcore.ShortCallerEncoder,
+			EncodeLevel: func(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+				enc.AppendString(levelName(level))
+			},
+		})
+	}
+
+	return zapcore.NewConsoleEncoder(zapcore.EncoderConfig{
+		TimeKey:          "time",
+		LevelKey:         "level",
+		NameKey:          "component",
+		MessageKey:       "msg",
+		LineEnding:       zapcore.DefaultLineEnding,
+		ConsoleSeparator: " ",
+		EncodeTime:       zapcore.TimeEncoderOfLayout("2006/01/02 15:04:05"),
+		EncodeDuration:   zapcore.StringDurationEncoder,
+		EncodeLevel: func(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+			enc.AppendString("[" + strings.ToUpper(levelName(level)) + "]")
+		},
+	})
+}
+
+// New Logger with the given encoding and levels, components maps a component to its level
+func New(out io.Writer, encoding, level string, components map[string]string) (*Logger, error) {
+	if encoding == "" {
+		encoding = Console
+	}
+	if encoding != Console && encoding != JSON {
+		return nil, fmt.Errorf("unknown log encoding '%s', expected one of console, json", encoding)
+	}
+	if level == "" {
+		level = Info
+	}
+
+	logger := &Logger{
+		core:   zapcore.NewCore(encoder(encoding), zapcore.Lock(zapcore.AddSync(out)), zapcore.DebugLevel),
+		level:  zap.NewAtomicLevel(),
+		levels: make(map[string]zap.AtomicLevel, len(components)),
+	}
+
+	if err := logger.SetLevel(Default, level); err != nil {
+		return nil, err
+	}
+
+	for component, l := range components {
+		if err := logger.SetLevel(component, l); err != nil {
+			return nil, fmt.Errorf("component %s: %w", component, err)
+		}
+	}
+
+	return logger, nil
+}
+
+var current atomic.Value
+
+func init() {
+	logger, _ := New(os.Stderr, Console, Info, nil)
+	current.Store(logger)
+}
+
+func currentLogger() *Logger {
+	return current.Load().(*Logger)
+}
+
+// Component returns the named logger of the component, it writes with the Logger configured by Use
+func Component(name string) *zap.SugaredLogger {
+	return newComponent(name, currentLogger)
+}
+
+// Use a new Logger for all component loggers, the output of the std log package is logged as info of the Default component
+func Use(out io.Writer, encoding, level string, components map[string]string) (*Logger, error) {
+	logger, err := New(out, encoding, level, components)
+	if err != nil {
+		return nil, err
+	}
+
+	current.Store(logger)
+	zap.RedirectStdLog(Component(Default).Desugar())
+
+	return logger, nil
+}
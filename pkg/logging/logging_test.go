@@ -0,0 +1,180 @@
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/secret"
+)
+
+func Test_Levels(t *testing.T) {
+	out := new(bytes.Buffer)
+
+	logger, err := logging.New(out, logging.Console, logging.Info, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	l := logger.Component(logging.Targets)
+	l.Debug("hidden")
+	l.Warn("visible")
+
+	if strings.Contains(out.String(), "hidden") {
+		t.Errorf("expected debug message to be dropped, got %s", out.String())
+	}
+	if !strings.Contains(out.String(), "[WARNING] targets visible\n") {
+		t.Errorf("expected warning message, got %s", out.String())
+	}
+
+	if err := logger.SetLevel(logging.Targets, logging.Debug); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	l.Debug("component debug")
+	logger.Component(logging.Store).Debug("other component")
+	if !strings.Contains(out.String(), "component debug") {
+		t.Errorf("expected debug message of the component, got %s", out.String())
+	}
+	if strings.Contains(out.String(), "other component") {
+		t.Errorf("expected debug message of other components to be dropped, got %s", out.String())
+	}
+
+	if err := logger.SetLevel(logging.Default, logging.Error); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	logger.Component(logging.Store).Warn("below default")
+	if strings.Contains(out.String(), "below default") {
+		t.Errorf("expected the changed default level, got %s", out.String())
+	}
+
+	if err := logger.SetLevel(logging.Targets, "trace"); err == nil {
+		t.Error("expected error for unknown level")
+	}
+}
+
+func Test_JSONEncoding(t *testing.T) {
+	out := new(bytes.Buffer)
+
+	logger, _ := logging.New(out, logging.JSON, logging.Warning, map[string]string{logging.Targets: logging.Debug})
+	logger.Component(logging.Targets).Errorf("%s : PUSH failed", "Slack")
+
+	entry := map[string]string{}
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatalf("expected JSON output, got %s", out.String())
+	}
+
+	if entry["level"] != logging.Error || entry["component"] != logging.Targets || entry["msg"] != "Slack : PUSH failed" {
+		t.Errorf("unexpected entry: %v", entry)
+	}
+	if !strings.HasPrefix(entry["caller"], "logging/logging_test.go:") {
+		t.Errorf("unexpected caller: %s", entry["caller"])
+	}
+}
+
+func Test_Use(t *testing.T) {
+	l := logging.Component(logging.Listener)
+
+	out := new(bytes.Buffer)
+	logger, err := logging.Use(out, logging.Console, logging.Warning, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer logging.Use(os.Stderr, logging.Console, logging.Info, nil)
+
+	l.Info("hidden")
+	l.Warn("configured output")
+	log.Print("std log")
+
+	if strings.Contains(out.String(), "hidden") || !strings.Contains(out.String(), "[WARNING] listener configured output") {
+		t.Errorf("expected the component logger to use the configured Logger, got %s", out.String())
+	}
+	if strings.Contains(out.String(), "std log") {
+		t.Errorf("expected std log messages logged as info, got %s", out.String())
+	}
+
+	logger.SetLevel(logging.Default, logging.Info)
+	log.Print("std log")
+
+	if !strings.Contains(out.String(), "[INFO] default std log") {
+		t.Errorf("expected std log message, got %s", out.String())
+	}
+}
+
+func Test_New(t *testing.T) {
+	if _, err := logging.New(nil, "text", logging.Info, nil); err == nil {
+		t.Error("expected error for unknown encoding")
+	}
+	if _, err := logging.New(nil, logging.Console, "verbose", nil); err == nil {
+		t.Error("expected error for unknown level")
+	}
+	if _, err := logging.New(nil, logging.Console, logging.Info, map[string]string{"store": "verbose"}); err == nil {
+		t.Error("expected error for unknown component level")
+	}
+}
+
+func Test_Handler(t *testing.T) {
+	logger, _ := logging.New(new(bytes.Buffer), "", "", nil)
+	handler := logger.Handler()
+
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodPut, logging.LevelsPath, strings.NewReader(`{"component": "targets", "level": "debug"}`)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodPut, logging.LevelsPath, strings.NewReader(`{"level": "verbose"}`)))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an unknown level, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodGet, logging.LevelsPath, nil))
+
+	levels := map[string]string{}
+	if err := json.NewDecoder(rr.Body).Decode(&levels); err != nil || levels["targets"] != logging.Debug || levels[logging.Default] != logging.Info {
+		t.Errorf("unexpected levels %v (%v)", levels, err)
+	}
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodDelete, logging.LevelsPath, nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", rr.Code)
+	}
+}
+
+func Test_RedactSecrets(t *testing.T) {
+	out := new(bytes.Buffer)
+
+	logger, err := logging.New(out, logging.Console, logging.Info, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	secret.Register("https://hooks.slack.com/services/T000/B000/logging")
+
+	logger.Component(logging.Targets).Errorf("Slack PUSH failed: Post %q: timeout", "https://hooks.slack.com/services/T000/B000/logging")
+
+	if strings.Contains(out.String(), "T000") || !strings.Contains(out.String(), secret.Redacted) {
+		t.Errorf("expected the webhook to be redacted, got %s", out.String())
+	}
+}
+
+func BenchmarkDroppedDebug(b *testing.B) {
+	logger, _ := logging.New(io.Discard, logging.JSON, logging.Info, map[string]string{logging.Store: logging.Warning})
+	l := logger.Component(logging.Store)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Debugf("stored report %s with token=abc", "test/polr-123")
+	}
+}
@@ -0,0 +1,50 @@
+package maintenance
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/helper"
+)
+
+// WindowsPath lists, creates and removes maintenance windows
+const WindowsPath = "/v1/maintenance-windows"
+
+// Handler of the requests sent to WindowsPath, DELETE expects the window name as query parameter
+func (s *Schedule) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			helper.SendJSONResponse(w, s.Windows(time.Now()), nil)
+		case http.MethodPost:
+			window := Window{}
+			if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, 1<<20)).Decode(&window); err != nil {
+				helper.SendBadRequest(w, err)
+				return
+			}
+
+			if err := s.Add(window); err != nil {
+				helper.SendBadRequest(w, err)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+			w.WriteHeader(http.StatusCreated)
+
+			_ = json.NewEncoder(w).Encode(window)
+		case http.MethodDelete:
+			name := req.URL.Query().Get("name")
+			if !s.Remove(name) {
+				helper.SendError(w, http.StatusNotFound, fmt.Errorf("maintenance window '%s' not found", name))
+				return
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Allow", "GET, POST, DELETE")
+			helper.SendError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+		}
+	}
+}
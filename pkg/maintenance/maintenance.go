@@ -0,0 +1,218 @@
+// Package maintenance mutes target notifications during recurring quiet hours and ad-hoc maintenance windows
+package maintenance
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// QuietHours recurring daily, a range ending before its start spans midnight
+type QuietHours struct {
+	// Days the quiet hours start on, every day if empty
+	Days []time.Weekday
+	// From and To as minutes after midnight
+	From     int
+	To       int
+	Location *time.Location
+}
+
+// Contains reports if the time is within the quiet hours
+func (q QuietHours) Contains(t time.Time) bool {
+	if q.Location != nil {
+		t = t.In(q.Location)
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+
+	if q.From <= q.To {
+		return q.on(t.Weekday()) && minute >= q.From && minute < q.To
+	}
+
+	if minute >= q.From {
+		return q.on(t.Weekday())
+	}
+
+	return minute < q.To && q.on(t.AddDate(0, 0, -1).Weekday())
+}
+
+func (q QuietHours) on(day time.Weekday) bool {
+	if len(q.Days) == 0 {
+		return true
+	}
+
+	for _, d := range q.Days {
+		if d == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseQuietHours parses the days by name, from and to as HH:MM and an optional IANA timezone, defaults to UTC
+func ParseQuietHours(days []string, from, to, timezone string) (QuietHours, error) {
+	q := QuietHours{Location: time.UTC}
+
+	for _, day := range days {
+		weekday, ok := weekdays[strings.ToLower(day)]
+		if !ok {
+			return q, fmt.Errorf("unknown day '%s'", day)
+		}
+
+		q.Days = append(q.Days, weekday)
+	}
+
+	var err error
+	if q.From, err = parseClock(from); err != nil {
+		return q, err
+	}
+	if q.To, err = parseClock(to); err != nil {
+		return q, err
+	}
+	if q.From == q.To {
+		return q, fmt.Errorf("from and to are equal")
+	}
+
+	if timezone != "" {
+		if q.Location, err = time.LoadLocation(timezone); err != nil {
+			return q, err
+		}
+	}
+
+	return q, nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time '%s', expected HH:MM", value)
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Window of a planned maintenance
+type Window struct {
+	Name   string    `json:"name"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// Contains reports if the time is within the window
+func (w Window) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// Validate the name and the time range of the window
+func (w Window) Validate() error {
+	if w.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if !w.End.After(w.Start) {
+		return fmt.Errorf("end has to be after start")
+	}
+
+	return nil
+}
+
+// Schedule of the quiet hours and maintenance windows, windows can be changed at runtime
+type Schedule struct {
+	mx         *sync.RWMutex
+	quietHours []QuietHours
+	windows    map[string]Window
+}
+
+// Active reports if notifications are muted at the given time
+func (s *Schedule) Active(now time.Time) bool {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	for _, w := range s.windows {
+		if w.Contains(now) {
+			return true
+		}
+	}
+
+	for _, q := range s.quietHours {
+		if q.Contains(now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Add a window, an existing window with the same name is replaced
+func (s *Schedule) Add(w Window) error {
+	if err := w.Validate(); err != nil {
+		return err
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.windows[w.Name] = w
+
+	return nil
+}
+
+// Remove the window with the given name, false if it does not exist
+func (s *Schedule) Remove(name string) bool {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	_, ok := s.windows[name]
+	delete(s.windows, name)
+
+	return ok
+}
+
+// Windows returns the windows which are not yet ended sorted by start, ended windows are removed
+func (s *Schedule) Windows(now time.Time) []Window {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	list := make([]Window, 0, len(s.windows))
+	for name, w := range s.windows {
+		if !now.Before(w.End) {
+			delete(s.windows, name)
+			continue
+		}
+
+		list = append(list, w)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Start.Equal(list[j].Start) {
+			return list[i].Name < list[j].Name
+		}
+
+		return list[i].Start.Before(list[j].Start)
+	})
+
+	return list
+}
+
+// NewSchedule creates a Schedule with the static quiet hours and windows
+func NewSchedule(quietHours []QuietHours, windows []Window) *Schedule {
+	s := &Schedule{mx: new(sync.RWMutex), quietHours: quietHours, windows: make(map[string]Window, len(windows))}
+	for _, w := range windows {
+		s.windows[w.Name] = w
+	}
+
+	return s
+}
@@ -0,0 +1,179 @@
+package maintenance_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/maintenance"
+)
+
+func Test_QuietHours(t *testing.T) {
+	q, err := maintenance.ParseQuietHours([]string{"Friday"}, "22:00", "06:00", "Europe/Berlin")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+
+	cases := []struct {
+		time     time.Time
+		expected bool
+	}{
+		{time.Date(2024, 3, 1, 23, 0, 0, 0, berlin), true},
+		{time.Date(2024, 3, 2, 5, 59, 0, 0, berlin), true},
+		{time.Date(2024, 3, 2, 6, 0, 0, 0, berlin), false},
+		{time.Date(2024, 3, 2, 23, 0, 0, 0, berlin), false},
+		{time.Date(2024, 3, 1, 21, 30, 0, 0, time.UTC), true},
+		{time.Date(2024, 3, 1, 20, 30, 0, 0, time.UTC), false},
+	}
+
+	for _, c := range cases {
+		if q.Contains(c.time) != c.expected {
+			t.Errorf("expected %s to be quiet: %v", c.time, c.expected)
+		}
+	}
+
+	for _, invalid := range [][]string{{"funday", "22:00", "06:00"}, {"", "25:00", "06:00"}, {"", "06:00", "06:00"}} {
+		days := []string{}
+		if invalid[0] != "" {
+			days = append(days, invalid[0])
+		}
+		if _, err := maintenance.ParseQuietHours(days, invalid[1], invalid[2], ""); err == nil {
+			t.Errorf("expected error for %v", invalid)
+		}
+	}
+}
+
+func Test_Schedule(t *testing.T) {
+	now := time.Now()
+	schedule := maintenance.NewSchedule(nil, []maintenance.Window{{Name: "upgrade", Start: now.Add(-time.Hour), End: now.Add(time.Hour)}})
+
+	if !schedule.Active(now) {
+		t.Error("expected schedule to be active during the window")
+	}
+	if schedule.Active(now.Add(2 * time.Hour)) {
+		t.Error("expected schedule to be inactive after the window")
+	}
+
+	if err := schedule.Add(maintenance.Window{Name: "invalid", Start: now, End: now}); err == nil {
+		t.Error("expected error for a window without duration")
+	}
+	if err := schedule.Add(maintenance.Window{Name: "ended", Start: now.Add(-2 * time.Hour), End: now.Add(-time.Hour)}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if windows := schedule.Windows(now); len(windows) != 1 || windows[0].Name != "upgrade" {
+		t.Errorf("expected ended windows to be removed, got %v", windows)
+	}
+
+	if !schedule.Remove("upgrade") || schedule.Remove("upgrade") {
+		t.Error("expected window to be removed once")
+	}
+	if schedule.Active(now) {
+		t.Error("expected schedule to be inactive after the window was removed")
+	}
+}
+
+func Test_Muter(t *testing.T) {
+	rep := &v1alpha2.PolicyReport{ObjectMeta: metav1.ObjectMeta{Name: "polr", Namespace: "test"}}
+	result := v1alpha2.PolicyReportResult{ID: "1", Policy: "require-labels", Result: v1alpha2.StatusFail}
+
+	t.Run("Suppress", func(t *testing.T) {
+		schedule := maintenance.NewSchedule(nil, nil)
+		muter := maintenance.NewMuter(schedule, maintenance.Suppress)
+
+		called := 0
+		listener := muter.Results("results", func(rep v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult, e bool) { called++ })
+
+		listener(rep, result, false)
+		schedule.Add(maintenance.Window{Name: "upgrade", Start: time.Now().Add(-time.Minute), End: time.Now().Add(time.Hour)})
+		listener(rep, result, false)
+
+		schedule.Remove("upgrade")
+		muter.Flush()
+
+		if called != 1 || muter.Queued() != 0 {
+			t.Errorf("expected notification during the window to be suppressed, called %d times", called)
+		}
+	})
+
+	t.Run("Queue", func(t *testing.T) {
+		schedule := maintenance.NewSchedule(nil, []maintenance.Window{{Name: "upgrade", Start: time.Now().Add(-time.Minute), End: time.Now().Add(time.Hour)}})
+		muter := maintenance.NewMuter(schedule, maintenance.Queue)
+
+		messages := make([]string, 0)
+		listener := muter.Results("results", func(rep v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult, e bool) {
+			messages = append(messages, r.Message)
+		})
+
+		result.Message = "first"
+		listener(rep, result, false)
+		result.Message = "second"
+		listener(rep, result, false)
+
+		if muter.Queued() != 1 {
+			t.Fatalf("expected one queued notification per result, got %d", muter.Queued())
+		}
+
+		muter.Flush()
+		if len(messages) != 0 {
+			t.Fatal("expected no delivery during the window")
+		}
+
+		schedule.Remove("upgrade")
+		muter.Flush()
+
+		if len(messages) != 1 || messages[0] != "second" {
+			t.Errorf("expected the latest notification to be delivered after the window, got %v", messages)
+		}
+		if muter.Queued() != 0 {
+			t.Error("expected empty queue after flush")
+		}
+	})
+}
+
+func Test_Handler(t *testing.T) {
+	schedule := maintenance.NewSchedule(nil, nil)
+	handler := schedule.Handler()
+
+	body, _ := json.Marshal(maintenance.Window{Name: "upgrade", Start: time.Now(), End: time.Now().Add(time.Hour), Reason: "cluster upgrade"})
+
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodPost, maintenance.WindowsPath, bytes.NewReader(body)))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodPost, maintenance.WindowsPath, bytes.NewReader([]byte(`{"name": "invalid"}`))))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for a window without time range, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodGet, maintenance.WindowsPath, nil))
+
+	windows := []maintenance.Window{}
+	if err := json.NewDecoder(rr.Body).Decode(&windows); err != nil || len(windows) != 1 || windows[0].Reason != "cluster upgrade" {
+		t.Errorf("expected the created window, got %v (%v)", windows, err)
+	}
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodDelete, maintenance.WindowsPath+"?name=upgrade", nil))
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("expected status 204, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodDelete, maintenance.WindowsPath+"?name=upgrade", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", rr.Code)
+	}
+}
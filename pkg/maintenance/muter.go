@@ -0,0 +1,126 @@
+package maintenance
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+var logger = logging.Component("maintenance")
+
+const (
+	// Suppress drops the notifications during a window
+	Suppress = "suppress"
+	// Queue delivers the notifications after the window ended, only the latest notification of a result is kept
+	Queue = "queue"
+)
+
+// Muter holds back the notifications of the wrapped listeners while the schedule is active
+type Muter struct {
+	schedule *Schedule
+	mode     string
+	now      func() time.Time
+	mx       *sync.Mutex
+	keys     []string
+	queued   map[string]func()
+}
+
+// Results wraps a result listener, name separates the queued notifications of different listeners
+func (m *Muter) Results(name string, callback report.PolicyReportResultListener) report.PolicyReportResultListener {
+	return func(rep v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult, preExisted bool) {
+		if !m.schedule.Active(m.now()) {
+			callback(rep, r, preExisted)
+			return
+		}
+
+		m.enqueue(name+"/"+rep.GetID()+"/"+r.GetID(), func() { callback(rep, r, preExisted) })
+	}
+}
+
+// Reports wraps a report listener, name separates the queued notifications of different listeners
+func (m *Muter) Reports(name string, callback report.PolicyReportListener) report.PolicyReportListener {
+	return func(event report.LifecycleEvent) {
+		if !m.schedule.Active(m.now()) {
+			callback(event)
+			return
+		}
+
+		m.enqueue(name+"/"+event.PolicyReport.GetID(), func() { callback(event) })
+	}
+}
+
+func (m *Muter) enqueue(key string, send func()) {
+	if m.mode != Queue {
+		return
+	}
+
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	if _, ok := m.queued[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+
+	m.queued[key] = send
+}
+
+// Queued returns the amount of notifications waiting for the end of the window
+func (m *Muter) Queued() int {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	return len(m.keys)
+}
+
+// Flush delivers the queued notifications in their original order if the schedule is not active
+func (m *Muter) Flush() {
+	if m.schedule.Active(m.now()) {
+		return
+	}
+
+	m.mx.Lock()
+	keys, queued := m.keys, m.queued
+	m.keys, m.queued = make([]string, 0), make(map[string]func())
+	m.mx.Unlock()
+
+	if len(keys) == 0 {
+		return
+	}
+
+	logger.Infof("maintenance ended, deliver %d queued notifications", len(keys))
+
+	for _, key := range keys {
+		queued[key]()
+	}
+}
+
+// Run flushes the queue in the given interval until the context is canceled
+func (m *Muter) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			m.Flush()
+		}
+	}
+}
+
+// NewMuter creates a Muter, unknown modes suppress the notifications
+func NewMuter(schedule *Schedule, mode string) *Muter {
+	return &Muter{
+		schedule: schedule,
+		mode:     mode,
+		now:      time.Now,
+		mx:       new(sync.Mutex),
+		keys:     make([]string, 0),
+		queued:   make(map[string]func()),
+	}
+}
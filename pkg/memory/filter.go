@@ -0,0 +1,144 @@
+package memory
+
+import (
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	api "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/query"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+var logger = logging.Component(logging.Store)
+
+// filter evaluates the API filter in memory, like the WHERE conditions of the database store
+type filter struct {
+	api.Filter
+	node    query.Node
+	invalid bool
+}
+
+func (f filter) matchReport(r v1alpha2.ReportInterface) bool {
+	if f.invalid {
+		return false
+	}
+
+	labels := r.GetLabels()
+	for key, value := range f.ReportLabel {
+		if labels[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f filter) match(r v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) bool {
+	res := resource(r, result)
+
+	checks := []struct {
+		values []string
+		value  string
+	}{
+		{f.Namespaces, r.GetNamespace()},
+		{f.Kinds, res.Kind},
+		{f.Resources, res.Name},
+		{f.Sources, result.Source},
+		{f.Categories, result.Category},
+		{f.Severities, string(result.Severity)},
+		{f.Policies, result.Policy},
+		{f.Rules, result.Rule},
+		{f.Status, string(result.Result)},
+		{f.IDs, result.GetID()},
+		{f.UIDs, string(res.UID)},
+		{f.Teams, result.Properties[v1alpha2.TeamKey]},
+	}
+
+	for _, check := range checks {
+		if len(check.values) > 0 && !validate.MatchAny(check.values, check.value) {
+			return false
+		}
+	}
+
+	if f.Search != "" && !search(f.Search, r, result, res) {
+		return false
+	}
+
+	if f.node == nil {
+		return true
+	}
+
+	return query.Match(f.node, func(field string) string {
+		switch field {
+		case "namespace":
+			return r.GetNamespace()
+		case "kind":
+			return res.Kind
+		case "name":
+			return res.Name
+		case "policy":
+			return result.Policy
+		case "rule":
+			return result.Rule
+		case "severity":
+			return string(result.Severity)
+		case "status":
+			return string(result.Result)
+		case "category":
+			return result.Category
+		case "source":
+			return result.Source
+		case "message":
+			return result.Message
+		case "timestamp":
+			return strconv.FormatInt(result.Timestamp.Seconds, 10)
+		}
+
+		return ""
+	})
+}
+
+// search matches the prefix of the namespace, resource name, policy or rule and the exact severity, status or kind
+func search(value string, r v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult, res *corev1.ObjectReference) bool {
+	prefix := strings.ToLower(value)
+
+	for _, field := range []string{r.GetNamespace(), res.Name, result.Policy, result.Rule} {
+		if strings.HasPrefix(strings.ToLower(field), prefix) {
+			return true
+		}
+	}
+
+	return string(result.Severity) == value || string(result.Result) == value || strings.EqualFold(res.Kind, value)
+}
+
+// resource of the result, falls back to the scope of the report
+func resource(r v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) *corev1.ObjectReference {
+	if res := result.GetResource(); res != nil {
+		return res
+	}
+	if scope := r.GetScope(); scope != nil {
+		return scope
+	}
+
+	return &corev1.ObjectReference{}
+}
+
+func newFilter(f api.Filter) filter {
+	result := filter{Filter: f}
+
+	if f.Expression != "" {
+		node, err := query.Parse(f.Expression)
+		if err != nil {
+			logger.Errorf("invalid filter expression %q: %s", f.Expression, err)
+			result.invalid = true
+		}
+
+		result.node = node
+	}
+
+	return result
+}
@@ -0,0 +1,198 @@
+// Package memory provides a PolicyReport store without database.
+// It keeps the current PolicyReports in memory and serves only the taxonomy and compliance score APIs
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	api "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/score"
+)
+
+// Type of the database configuration which selects the in-memory store
+const Type = "memory"
+
+// Store of the current PolicyReports
+type Store struct {
+	reports map[string]v1alpha2.ReportInterface
+	rwm     *sync.RWMutex
+}
+
+func (s *Store) CreateSchemas() error {
+	return nil
+}
+
+// Get a PolicyReport by ID
+func (s *Store) Get(id string) (v1alpha2.ReportInterface, bool) {
+	s.rwm.RLock()
+	defer s.rwm.RUnlock()
+
+	r, ok := s.reports[id]
+
+	return r, ok
+}
+
+// Add a PolicyReport to the Store
+func (s *Store) Add(r v1alpha2.ReportInterface) error {
+	s.rwm.Lock()
+	defer s.rwm.Unlock()
+
+	s.reports[r.GetID()] = r
+
+	return nil
+}
+
+// Update a PolicyReport of the Store
+func (s *Store) Update(r v1alpha2.ReportInterface) error {
+	return s.Add(r)
+}
+
+// Remove a PolicyReport with the given ID from the Store
+func (s *Store) Remove(id string) error {
+	s.rwm.Lock()
+	defer s.rwm.Unlock()
+
+	delete(s.reports, id)
+
+	return nil
+}
+
+// CleanUp removes all PolicyReports
+func (s *Store) CleanUp() error {
+	s.rwm.Lock()
+	defer s.rwm.Unlock()
+
+	s.reports = make(map[string]v1alpha2.ReportInterface)
+
+	return nil
+}
+
+// FetchScoreCounts of current PolicyReportResults by namespace, category, severity and status
+func (s *Store) FetchScoreCounts(filter api.Filter) ([]score.Count, error) {
+	filter.Status = nil
+	filter.Resources = nil
+
+	f := newFilter(filter)
+
+	index := make(map[score.Count]int)
+
+	s.each(f, func(r v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) {
+		index[score.Count{
+			Namespace: r.GetNamespace(),
+			Category:  result.Category,
+			Severity:  string(result.Severity),
+			Status:    string(result.Result),
+		}]++
+	})
+
+	counts := make([]score.Count, 0, len(index))
+	for count, value := range index {
+		count.Count = value
+		counts = append(counts, count)
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		a, b := counts[i], counts[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		if a.Severity != b.Severity {
+			return a.Severity < b.Severity
+		}
+
+		return a.Status < b.Status
+	})
+
+	return counts, nil
+}
+
+// FetchTaxonomy counts the current PolicyReportResults per source, category, policy and rule
+func (s *Store) FetchTaxonomy(filter api.Filter) (*api.Taxonomy, error) {
+	taxonomy := &api.Taxonomy{
+		Sources:    make([]api.TaxonomyItem, 0),
+		Categories: make([]api.TaxonomyItem, 0),
+		Policies:   make([]api.TaxonomyItem, 0),
+		Rules:      make([]api.TaxonomyItem, 0),
+	}
+
+	f := newFilter(filter)
+
+	type key struct{ group, name string }
+
+	sources := make(map[key]int)
+	categories := make(map[key]int)
+	policies := make(map[key]int)
+	rules := make(map[key]int)
+
+	s.each(f, func(_ v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) {
+		for _, c := range []struct {
+			counts map[key]int
+			key    key
+		}{
+			{sources, key{name: result.Source}},
+			{categories, key{name: result.Category}},
+			{policies, key{name: result.Policy}},
+			{rules, key{group: result.Policy, name: result.Rule}},
+		} {
+			if c.key.name != "" {
+				c.counts[c.key]++
+			}
+		}
+	})
+
+	for _, c := range []struct {
+		counts map[key]int
+		items  *[]api.TaxonomyItem
+	}{
+		{sources, &taxonomy.Sources},
+		{categories, &taxonomy.Categories},
+		{policies, &taxonomy.Policies},
+		{rules, &taxonomy.Rules},
+	} {
+		for k, count := range c.counts {
+			*c.items = append(*c.items, api.TaxonomyItem{Name: k.name, DisplayName: k.name, Group: k.group, Count: count})
+		}
+
+		items := *c.items
+		sort.Slice(items, func(i, j int) bool {
+			if items[i].Group != items[j].Group {
+				return items[i].Group < items[j].Group
+			}
+
+			return items[i].Name < items[j].Name
+		})
+	}
+
+	return taxonomy, nil
+}
+
+// each calls the callback for all results which pass the filter
+func (s *Store) each(f filter, callback func(v1alpha2.ReportInterface, v1alpha2.PolicyReportResult)) {
+	s.rwm.RLock()
+	defer s.rwm.RUnlock()
+
+	for _, r := range s.reports {
+		if !f.matchReport(r) {
+			continue
+		}
+
+		for _, result := range r.GetResults() {
+			if f.match(r, result) {
+				callback(r, result)
+			}
+		}
+	}
+}
+
+// NewStore creates an empty in-memory Store
+func NewStore() *Store {
+	return &Store{
+		reports: make(map[string]v1alpha2.ReportInterface),
+		rwm:     new(sync.RWMutex),
+	}
+}
@@ -0,0 +1,104 @@
+package memory_test
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/memory"
+)
+
+var preport = &v1alpha2.PolicyReport{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "polr-test",
+		Namespace: "test",
+		Labels:    map[string]string{"app": "policy-reporter"},
+	},
+	Results: []v1alpha2.PolicyReportResult{fixtures.FailResult, fixtures.PassPodResult},
+}
+
+var creport = &v1alpha2.ClusterPolicyReport{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "cpolr",
+	},
+	Results: []v1alpha2.PolicyReportResult{fixtures.PassNamespaceResult, fixtures.FailNamespaceResult},
+}
+
+func Test_Store(t *testing.T) {
+	store := memory.NewStore()
+
+	t.Run("Add/Get/Remove", func(t *testing.T) {
+		_ = store.Add(preport)
+
+		if _, ok := store.Get(preport.GetID()); !ok {
+			t.Fatal("expected report to be found after add")
+		}
+
+		_ = store.Remove(preport.GetID())
+
+		if _, ok := store.Get(preport.GetID()); ok {
+			t.Fatal("expected report to be removed")
+		}
+	})
+
+	_ = store.Add(preport)
+	_ = store.Add(creport)
+
+	t.Run("FetchScoreCounts", func(t *testing.T) {
+		counts, _ := store.FetchScoreCounts(v1.Filter{Namespaces: []string{"test"}, Status: []string{"fail"}})
+		if len(counts) != 2 {
+			t.Fatalf("expected 2 counts of the namespace, the status filter is ignored, got %d", len(counts))
+		}
+
+		counts, _ = store.FetchScoreCounts(v1.Filter{})
+		if len(counts) != 4 {
+			t.Errorf("expected 4 counts, got %d", len(counts))
+		}
+	})
+
+	t.Run("FetchTaxonomy", func(t *testing.T) {
+		taxonomy, _ := store.FetchTaxonomy(v1.Filter{})
+		if len(taxonomy.Sources) != 1 || taxonomy.Sources[0].Count != 4 {
+			t.Errorf("expected one source with 4 results, got %v", taxonomy.Sources)
+		}
+		if len(taxonomy.Categories) != 3 {
+			t.Errorf("expected 3 categories, got %v", taxonomy.Categories)
+		}
+		if len(taxonomy.Rules) != 2 || taxonomy.Rules[0].Group != "require-ns-GetLabels()" {
+			t.Errorf("expected rules grouped by policy, got %v", taxonomy.Rules)
+		}
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		taxonomy, _ := store.FetchTaxonomy(v1.Filter{ReportLabel: map[string]string{"app": "policy-reporter"}, Kinds: []string{"Pod"}})
+		if len(taxonomy.Policies) != 1 || taxonomy.Policies[0].Count != 1 {
+			t.Errorf("expected the pod result of the labeled report, got %v", taxonomy.Policies)
+		}
+
+		taxonomy, _ = store.FetchTaxonomy(v1.Filter{Expression: "status = fail AND severity >= high"})
+		if len(taxonomy.Policies) != 2 {
+			t.Errorf("expected 2 policies of failed high severity results, got %v", taxonomy.Policies)
+		}
+
+		taxonomy, _ = store.FetchTaxonomy(v1.Filter{Search: "ngi"})
+		if len(taxonomy.Categories) != 2 {
+			t.Errorf("expected categories of the nginx results, got %v", taxonomy.Categories)
+		}
+
+		taxonomy, _ = store.FetchTaxonomy(v1.Filter{Expression: "status ="})
+		if len(taxonomy.Sources) != 0 {
+			t.Errorf("expected no results for an invalid expression, got %v", taxonomy.Sources)
+		}
+	})
+
+	t.Run("CleanUp", func(t *testing.T) {
+		_ = store.CleanUp()
+
+		if _, ok := store.Get(creport.GetID()); ok {
+			t.Fatal("expected store to be empty")
+		}
+	})
+}
@@ -0,0 +1,98 @@
+// Package owner attributes results of Pods, ReplicaSets and Jobs to their top level controller,
+// e.g. a Deployment, StatefulSet or CronJob, by following the controller owner references
+package owner
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+// maxDepth of the followed owner references, Pod > ReplicaSet > Deployment needs two
+const maxDepth = 5
+
+// Resolver of the top level controller of a resource
+type Resolver struct {
+	metadata report.ResourceMetadata
+}
+
+// Resolve follows the controller owner references of the resource,
+// false if the resource has no controller or its metadata is unknown
+func (r *Resolver) Resolve(resource *corev1.ObjectReference) (*corev1.ObjectReference, bool) {
+	if r.metadata == nil || resource == nil {
+		return nil, false
+	}
+
+	current := resource
+	for i := 0; i < maxDepth; i++ {
+		meta, ok := r.metadata.Get(current)
+		if !ok {
+			break
+		}
+
+		ref := metav1.GetControllerOfNoCopy(meta)
+		if ref == nil {
+			break
+		}
+
+		current = &corev1.ObjectReference{
+			APIVersion: ref.APIVersion,
+			Kind:       ref.Kind,
+			Name:       ref.Name,
+			Namespace:  resource.Namespace,
+			UID:        ref.UID,
+		}
+	}
+
+	if current == resource {
+		return nil, false
+	}
+
+	return current, true
+}
+
+// Map adds the top level controller of the result resource as owner properties,
+// results which already have an owner are skipped
+func (r *Resolver) Map(rep v1alpha2.ReportInterface) {
+	results := rep.GetResults()
+
+	for i := range results {
+		if !results[i].HasResource() || results[i].Properties[v1alpha2.OwnerUIDKey] != "" {
+			continue
+		}
+
+		owner, ok := r.Resolve(results[i].GetResource())
+		if !ok {
+			continue
+		}
+
+		if results[i].Properties == nil {
+			results[i].Properties = make(map[string]string)
+		}
+
+		results[i].Properties[v1alpha2.OwnerAPIVersionKey] = owner.APIVersion
+		results[i].Properties[v1alpha2.OwnerKindKey] = owner.Kind
+		results[i].Properties[v1alpha2.OwnerNameKey] = owner.Name
+		results[i].Properties[v1alpha2.OwnerUIDKey] = string(owner.UID)
+	}
+}
+
+// Key groups the results of all replicas of a controller by the owner, policy, rule and status,
+// empty if the result has no owner
+func Key(result v1alpha2.PolicyReportResult) string {
+	uid := result.Properties[v1alpha2.OwnerUIDKey]
+	if uid == "" {
+		return ""
+	}
+
+	return strings.Join([]string{"owner", uid, result.Policy, result.Rule, string(result.Result)}, "/")
+}
+
+// NewResolver creates a Resolver reading the owner references from the resource metadata
+func NewResolver(metadata report.ResourceMetadata) *Resolver {
+	return &Resolver{metadata: metadata}
+}
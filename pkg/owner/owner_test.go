@@ -0,0 +1,88 @@
+package owner_test
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/owner"
+)
+
+type metadata map[string]*metav1.PartialObjectMetadata
+
+func (m metadata) Get(resource *corev1.ObjectReference) (*metav1.PartialObjectMetadata, bool) {
+	meta, ok := m[resource.Kind+"/"+resource.Name]
+
+	return meta, ok
+}
+
+func controlledBy(apiVersion, kind, name string) *metav1.PartialObjectMetadata {
+	controller := true
+
+	return &metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: apiVersion, Kind: kind, Name: name, UID: types.UID("uid-" + name), Controller: &controller}},
+		},
+	}
+}
+
+var cache = metadata{
+	"Pod/nginx-5d4f-abcde":    controlledBy("apps/v1", "ReplicaSet", "nginx-5d4f"),
+	"ReplicaSet/nginx-5d4f":   controlledBy("apps/v1", "Deployment", "nginx"),
+	"Deployment/nginx":        {},
+	"Pod/backup-28000-xyz":    controlledBy("batch/v1", "Job", "backup-28000"),
+	"Pod/standalone":          {},
+	"Job/backup-28000":        controlledBy("batch/v1", "CronJob", "backup"),
+	"Pod/unknown-owner-abcde": controlledBy("apps/v1", "ReplicaSet", "unknown"),
+}
+
+func Test_Resolve(t *testing.T) {
+	resolver := owner.NewResolver(cache)
+
+	for pod, expected := range map[string]string{"nginx-5d4f-abcde": "Deployment/nginx", "backup-28000-xyz": "CronJob/backup", "unknown-owner-abcde": "ReplicaSet/unknown"} {
+		ref, ok := resolver.Resolve(&corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Name: pod, Namespace: "test"})
+		if !ok {
+			t.Errorf("expected owner of %s", pod)
+			continue
+		}
+		if ref.Kind+"/"+ref.Name != expected {
+			t.Errorf("expected %s as owner of %s, got %s/%s", expected, pod, ref.Kind, ref.Name)
+		}
+		if ref.Namespace != "test" {
+			t.Errorf("expected namespace of the resource, got %s", ref.Namespace)
+		}
+	}
+
+	if _, ok := resolver.Resolve(&corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Name: "standalone", Namespace: "test"}); ok {
+		t.Error("expected no owner for a standalone pod")
+	}
+}
+
+func Test_Map(t *testing.T) {
+	rep := &v1alpha2.PolicyReport{
+		Results: []v1alpha2.PolicyReportResult{
+			{Policy: "require-labels", Result: v1alpha2.StatusFail, Resources: []corev1.ObjectReference{{APIVersion: "v1", Kind: "Pod", Name: "nginx-5d4f-abcde", Namespace: "test"}}},
+			{Policy: "require-labels", Result: v1alpha2.StatusFail, Resources: []corev1.ObjectReference{{APIVersion: "v1", Kind: "Pod", Name: "standalone", Namespace: "test"}}},
+		},
+	}
+
+	owner.NewResolver(cache).Map(rep)
+
+	props := rep.Results[0].Properties
+	if props[v1alpha2.OwnerKindKey] != "Deployment" || props[v1alpha2.OwnerNameKey] != "nginx" || props[v1alpha2.OwnerAPIVersionKey] != "apps/v1" || props[v1alpha2.OwnerUIDKey] != "uid-nginx" {
+		t.Errorf("unexpected owner properties: %v", props)
+	}
+	if len(rep.Results[1].Properties) > 0 {
+		t.Errorf("expected no owner properties, got %v", rep.Results[1].Properties)
+	}
+
+	if key := owner.Key(rep.Results[0]); key != "owner/uid-nginx/require-labels//fail" {
+		t.Errorf("unexpected key: %s", key)
+	}
+	if key := owner.Key(rep.Results[1]); key != "" {
+		t.Errorf("expected empty key, got %s", key)
+	}
+}
@@ -0,0 +1,106 @@
+// Package ownership attaches the responsible team to each result,
+// resolved from a label or annotation of the namespace or from the configured namespace mapping
+package ownership
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+// Mapping of namespaces to a team, the namespaces support wildcards
+type Mapping struct {
+	Team       string
+	Namespaces []string
+}
+
+// Options of the team resolution
+type Options struct {
+	// NamespaceLabel with the team of the namespace
+	NamespaceLabel string
+	// NamespaceAnnotation with the team of the namespace, used if the label is not set
+	NamespaceAnnotation string
+	// Mappings used if the namespace has neither label nor annotation, the first match wins
+	Mappings []Mapping
+}
+
+// Resolver of the team of a namespace
+type Resolver struct {
+	metadata report.ResourceMetadata
+	options  Options
+}
+
+// Team of the namespace, empty if no team is responsible
+func (r *Resolver) Team(namespace string) string {
+	if namespace == "" {
+		return ""
+	}
+
+	if r.metadata != nil && (r.options.NamespaceLabel != "" || r.options.NamespaceAnnotation != "") {
+		if meta, ok := r.metadata.Get(&corev1.ObjectReference{APIVersion: "v1", Kind: "Namespace", Name: namespace}); ok {
+			if team := meta.GetLabels()[r.options.NamespaceLabel]; r.options.NamespaceLabel != "" && team != "" {
+				return team
+			}
+			if team := meta.GetAnnotations()[r.options.NamespaceAnnotation]; r.options.NamespaceAnnotation != "" && team != "" {
+				return team
+			}
+		}
+	}
+
+	for _, m := range r.options.Mappings {
+		if validate.MatchRuleSet(namespace, validate.RuleSets{Include: m.Namespaces}) {
+			return m.Team
+		}
+	}
+
+	return ""
+}
+
+// TeamOf the result, the team property is preferred over the namespace of the result resource or the report
+func (r *Resolver) TeamOf(rep v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) string {
+	if team := result.Properties[v1alpha2.TeamKey]; team != "" {
+		return team
+	}
+
+	return r.Team(namespace(rep, result))
+}
+
+// Map sets the team property of each result, results with a team property are kept
+func (r *Resolver) Map(rep v1alpha2.ReportInterface) {
+	results := rep.GetResults()
+
+	for i := range results {
+		if results[i].Properties[v1alpha2.TeamKey] != "" {
+			continue
+		}
+
+		team := r.Team(namespace(rep, results[i]))
+		if team == "" {
+			continue
+		}
+
+		if results[i].Properties == nil {
+			results[i].Properties = make(map[string]string)
+		}
+
+		results[i].Properties[v1alpha2.TeamKey] = team
+	}
+}
+
+func namespace(rep v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) string {
+	if result.HasResource() && result.GetResource().Namespace != "" {
+		return result.GetResource().Namespace
+	}
+	if rep == nil {
+		return ""
+	}
+
+	return rep.GetNamespace()
+}
+
+// NewResolver creates a team Resolver, the metadata is required for the namespace label and annotation
+func NewResolver(metadata report.ResourceMetadata, options Options) *Resolver {
+	return &Resolver{metadata: metadata, options: options}
+}
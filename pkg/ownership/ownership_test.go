@@ -0,0 +1,92 @@
+package ownership_test
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/ownership"
+)
+
+type namespaces map[string]*metav1.PartialObjectMetadata
+
+func (n namespaces) Get(resource *corev1.ObjectReference) (*metav1.PartialObjectMetadata, bool) {
+	if resource.Kind != "Namespace" {
+		return nil, false
+	}
+
+	meta, ok := n[resource.Name]
+
+	return meta, ok
+}
+
+var metadata = namespaces{
+	"payments":  {ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "payments-team"}}},
+	"checkout":  {ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"owner": "checkout-team"}}},
+	"frontend":  {},
+	"team-a-ui": {},
+}
+
+var options = ownership.Options{
+	NamespaceLabel:      "team",
+	NamespaceAnnotation: "owner",
+	Mappings: []ownership.Mapping{
+		{Team: "team-a", Namespaces: []string{"team-a-*"}},
+		{Team: "platform", Namespaces: []string{"kube-*", "frontend"}},
+	},
+}
+
+func Test_Team(t *testing.T) {
+	resolver := ownership.NewResolver(metadata, options)
+
+	for namespace, expected := range map[string]string{
+		"payments":    "payments-team",
+		"checkout":    "checkout-team",
+		"frontend":    "platform",
+		"team-a-ui":   "team-a",
+		"kube-system": "platform",
+		"unknown":     "",
+		"":            "",
+	} {
+		if team := resolver.Team(namespace); team != expected {
+			t.Errorf("expected team %q for namespace %q, got %q", expected, namespace, team)
+		}
+	}
+}
+
+func Test_Map(t *testing.T) {
+	rep := &v1alpha2.PolicyReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "polr", Namespace: "payments"},
+		Results: []v1alpha2.PolicyReportResult{
+			{Policy: "require-labels"},
+			{Policy: "require-labels", Resources: []corev1.ObjectReference{{Kind: "Pod", Name: "nginx", Namespace: "frontend"}}},
+			{Policy: "require-labels", Properties: map[string]string{v1alpha2.TeamKey: "custom"}},
+		},
+	}
+
+	resolver := ownership.NewResolver(metadata, options)
+	resolver.Map(rep)
+
+	for i, expected := range []string{"payments-team", "platform", "custom"} {
+		if team := rep.Results[i].Properties[v1alpha2.TeamKey]; team != expected {
+			t.Errorf("expected team %q for result %d, got %q", expected, i, team)
+		}
+	}
+
+	if team := resolver.TeamOf(rep, v1alpha2.PolicyReportResult{}); team != "payments-team" {
+		t.Errorf("expected team of the report namespace, got %q", team)
+	}
+}
+
+func Test_WithoutMetadata(t *testing.T) {
+	resolver := ownership.NewResolver(nil, options)
+
+	if team := resolver.Team("payments"); team != "" {
+		t.Errorf("expected no team without metadata, got %q", team)
+	}
+	if team := resolver.Team("team-a-api"); team != "team-a" {
+		t.Errorf("expected team of the mapping, got %q", team)
+	}
+}
@@ -0,0 +1,128 @@
+// Package pipeline embeds the result dispatch of Policy Reporter in other controllers.
+//
+// A Pipeline receives the lifecycle events of PolicyReports, detects new results and delivers them to the
+// registered targets through the per target delivery queues. Targets are any target.Client, custom targets
+// embed target.BaseClient created with target.NewBaseClient to get the configured filters and options.
+// Metrics are only registered with the configured Registerer. The targets still use the process wide settings
+// of the severity, timeformat, fields and secret packages and the audit log set with audit.SetLog, the
+// Pipelines of one process share them.
+package pipeline
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kyverno/policy-reporter/pkg/cache"
+	"github.com/kyverno/policy-reporter/pkg/listener"
+	"github.com/kyverno/policy-reporter/pkg/listener/metrics"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/target"
+)
+
+// Options of a Pipeline, zero values use the defaults
+type Options struct {
+	// Targets registered on creation, more targets can be added with Register
+	Targets []target.Client
+	// Mapper sets the priority of the results, defaults to the severity based priorities
+	Mapper report.Mapper
+	// Cache of the processed results, defaults to an in memory cache
+	Cache cache.Cache
+	// StartUp is the time before which reports are handled as pre existing, defaults to the creation of the Pipeline
+	StartUp time.Time
+	// SkipExisting skips the results of pre existing reports for all targets
+	SkipExisting bool
+	// Workers per target without own concurrency, defaults to 1
+	Workers int
+	// QueueSize of each target, 0 blocks until a worker is free
+	QueueSize int
+	// Registerer of the delivery queue metrics, the metrics are not registered if nil
+	Registerer prometheus.Registerer
+}
+
+// Pipeline dispatches the new results of PolicyReports to the registered targets
+type Pipeline struct {
+	publisher  report.EventPublisher
+	results    *listener.ResultListener
+	dispatcher *listener.Dispatcher
+	registry   *target.Registry
+}
+
+// Publish processes the lifecycle event of a PolicyReport and returns after all listeners handled it
+func (p *Pipeline) Publish(event report.LifecycleEvent) {
+	p.publisher.Publish(event)
+}
+
+// Publisher of the Pipeline, to add further PolicyReport listeners or to connect a report.PolicyReportClient
+func (p *Pipeline) Publisher() report.EventPublisher {
+	return p.publisher
+}
+
+// RegisterResultListener adds a listener for each new result, e.g. to wrap the results with custom logic
+func (p *Pipeline) RegisterResultListener(l report.PolicyReportResultListener) {
+	p.results.RegisterListener(l)
+}
+
+// Register adds targets to the Pipeline, results already queued are still delivered to the existing targets
+func (p *Pipeline) Register(clients ...target.Client) {
+	if len(clients) == 0 {
+		return
+	}
+
+	current := p.registry.Clients()
+
+	list := make([]target.Client, 0, len(current)+len(clients))
+	list = append(list, current...)
+	list = append(list, clients...)
+
+	p.registry.Swap(list)
+	p.dispatcher.Update(list)
+}
+
+// Targets currently registered
+func (p *Pipeline) Targets() []target.Client {
+	return p.registry.Clients()
+}
+
+// Shutdown stops accepting results and waits until the queued results are delivered or the context is done
+func (p *Pipeline) Shutdown(ctx context.Context) error {
+	return p.dispatcher.Shutdown(ctx)
+}
+
+// New creates a Pipeline with the given options
+func New(options Options) (*Pipeline, error) {
+	if options.Mapper == nil {
+		options.Mapper = report.NewMapper(nil)
+	}
+	if options.Cache == nil {
+		options.Cache = cache.NewInMermoryCache()
+	}
+	if options.StartUp.IsZero() {
+		options.StartUp = time.Now()
+	}
+
+	m := metrics.NewDispatcherMetrics()
+	if options.Registerer != nil {
+		for _, c := range m.Collectors() {
+			if err := options.Registerer.Register(c); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	p := &Pipeline{
+		publisher: report.NewEventPublisher(),
+		results:   listener.NewResultListener(options.SkipExisting, options.Cache, options.StartUp),
+		registry:  target.NewRegistry(options.Targets),
+		dispatcher: listener.NewDispatcher(options.Targets, options.Mapper, m, listener.DispatcherOptions{
+			Workers:   options.Workers,
+			QueueSize: options.QueueSize,
+		}),
+	}
+
+	p.results.RegisterListener(p.dispatcher.Dispatch)
+	p.publisher.RegisterListener(listener.NewResults, p.results.Listen)
+
+	return p, nil
+}
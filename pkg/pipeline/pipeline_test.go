@@ -0,0 +1,109 @@
+package pipeline_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/pipeline"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/target"
+)
+
+type customTarget struct {
+	target.BaseClient
+	mx   sync.Mutex
+	sent []string
+}
+
+func (c *customTarget) Send(result v1alpha2.PolicyReportResult) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.sent = append(c.sent, result.GetID())
+}
+
+func (c *customTarget) Sent() int {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	return len(c.sent)
+}
+
+func newTarget(name string) *customTarget {
+	return &customTarget{BaseClient: target.NewBaseClient(target.ClientOptions{Name: name})}
+}
+
+func Test_Pipeline(t *testing.T) {
+	results := len(fixtures.DefaultPolicyReport.GetResults())
+
+	t.Run("Dispatch", func(t *testing.T) {
+		custom := newTarget("Custom")
+
+		p, err := pipeline.New(pipeline.Options{Targets: []target.Client{custom}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		p.Publish(report.LifecycleEvent{Type: report.Added, PolicyReport: fixtures.DefaultPolicyReport})
+		p.Publish(report.LifecycleEvent{Type: report.Updated, PolicyReport: fixtures.DefaultPolicyReport})
+
+		if err := p.Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if custom.Sent() != results {
+			t.Errorf("expected %d results sent once, got %d", results, custom.Sent())
+		}
+	})
+
+	t.Run("Register", func(t *testing.T) {
+		first := newTarget("First")
+		second := newTarget("Second")
+
+		p, err := pipeline.New(pipeline.Options{Targets: []target.Client{first}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		p.Register(second)
+		if len(p.Targets()) != 2 {
+			t.Fatalf("expected 2 targets, got %d", len(p.Targets()))
+		}
+
+		p.Publish(report.LifecycleEvent{Type: report.Added, PolicyReport: fixtures.DefaultPolicyReport})
+		p.Shutdown(context.Background())
+
+		if first.Sent() != results || second.Sent() != results {
+			t.Errorf("expected %d results for each target, got %d and %d", results, first.Sent(), second.Sent())
+		}
+	})
+
+	t.Run("SkipExisting", func(t *testing.T) {
+		custom := newTarget("Custom")
+
+		p, _ := pipeline.New(pipeline.Options{Targets: []target.Client{custom}, SkipExisting: true})
+		p.Publish(report.LifecycleEvent{Type: report.Added, PolicyReport: fixtures.DefaultPolicyReport})
+		p.Shutdown(context.Background())
+
+		if custom.Sent() != 0 {
+			t.Errorf("expected results of pre existing reports to be skipped, got %d", custom.Sent())
+		}
+	})
+
+	t.Run("Registerer", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+
+		if _, err := pipeline.New(pipeline.Options{Registerer: registry}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := pipeline.New(pipeline.Options{Registerer: prometheus.NewRegistry()}); err != nil {
+			t.Errorf("expected independent registries per pipeline, got %s", err)
+		}
+		if _, err := pipeline.New(pipeline.Options{Registerer: registry}); err == nil {
+			t.Error("expected an error for metrics already registered")
+		}
+	})
+}
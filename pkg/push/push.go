@@ -0,0 +1,53 @@
+// Package push sends the metrics of the Prometheus registry to a Pushgateway or a remote-write endpoint,
+// for environments where the Policy Reporter pod can not be scraped
+package push
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/secret"
+)
+
+var logger = logging.Component("push")
+
+// Pusher sends the current metrics to its endpoint
+type Pusher interface {
+	Name() string
+	Push(ctx context.Context) error
+}
+
+// BasicAuth credentials of the push endpoint
+type BasicAuth struct {
+	Username string
+	Password secret.String
+}
+
+func (b BasicAuth) apply(req *http.Request) {
+	if b.Username != "" {
+		req.SetBasicAuth(b.Username, b.Password.Value())
+	}
+}
+
+// Run pushes the metrics on each interval until the context is canceled, failed pushes are retried on the next interval
+func Run(ctx context.Context, pusher Pusher, interval time.Duration) error {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := pusher.Push(ctx); err != nil {
+				logger.Errorf("failed to push metrics to %s: %s", pusher.Name(), err)
+			}
+		}
+	}
+}
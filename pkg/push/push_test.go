@@ -0,0 +1,180 @@
+package push_test
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/kyverno/policy-reporter/pkg/push"
+)
+
+type sample struct {
+	labels map[string]string
+	value  float64
+}
+
+func field(b []byte) (protowire.Number, protowire.Type, []byte, []byte) {
+	num, typ, n := protowire.ConsumeTag(b)
+	b = b[n:]
+	n = protowire.ConsumeFieldValue(num, typ, b)
+
+	return num, typ, b[:n], b[n:]
+}
+
+// decode the remote-write request into samples
+func decode(t *testing.T, body []byte) []sample {
+	samples := make([]sample, 0)
+
+	for len(body) > 0 {
+		_, _, value, rest := field(body)
+		body = rest
+
+		ts, _ := protowire.ConsumeBytes(value)
+		s := sample{labels: map[string]string{}}
+
+		for len(ts) > 0 {
+			num, _, value, rest := field(ts)
+			ts = rest
+
+			content, _ := protowire.ConsumeBytes(value)
+			switch num {
+			case 1:
+				_, _, n, r := field(content)
+				_, _, v, _ := field(r)
+				name, _ := protowire.ConsumeString(n)
+				val, _ := protowire.ConsumeString(v)
+				s.labels[name] = val
+			case 2:
+				_, _, v, _ := field(content)
+				bits, _ := protowire.ConsumeFixed64(v)
+				s.value = math.Float64frombits(bits)
+			}
+		}
+
+		samples = append(samples, s)
+	}
+
+	if len(samples) == 0 {
+		t.Fatal("expected samples in the write request")
+	}
+
+	return samples
+}
+
+func newRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "policy_report_result"}, []string{"policy", "status"})
+	gauge.WithLabelValues("require-labels", "fail").Set(2)
+	registry.MustRegister(gauge)
+
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "push_duration_seconds", Buckets: []float64{1}})
+	histogram.Observe(0.5)
+	registry.MustRegister(histogram)
+
+	return registry
+}
+
+func Test_RemoteWrite(t *testing.T) {
+	var samples []sample
+	var header http.Header
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		header = req.Header
+
+		body, _ := io.ReadAll(req.Body)
+		content, err := snappy.Decode(nil, body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		samples = decode(t, content)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	pusher := push.NewRemoteWrite(server.URL, map[string]string{"X-Scope-OrgID": "tenant"}, map[string]string{"cluster": "dev"}, push.BasicAuth{Username: "user", Password: "pass"}, newRegistry(), server.Client())
+
+	if err := pusher.Push(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if header.Get("Content-Encoding") != "snappy" || header.Get("X-Prometheus-Remote-Write-Version") != "0.1.0" {
+		t.Errorf("unexpected remote-write headers %v", header)
+	}
+	if header.Get("X-Scope-OrgID") != "tenant" {
+		t.Error("expected configured header")
+	}
+	if user, pass, ok := (&http.Request{Header: header}).BasicAuth(); !ok || user != "user" || pass != "pass" {
+		t.Error("expected basic auth")
+	}
+
+	names := map[string]sample{}
+	for _, s := range samples {
+		if s.labels["cluster"] != "dev" {
+			t.Errorf("expected external label on %v", s.labels)
+		}
+		names[s.labels["__name__"]+s.labels["le"]] = s
+	}
+
+	if s := names["policy_report_result"]; s.value != 2 || s.labels["policy"] != "require-labels" {
+		t.Errorf("unexpected gauge sample %v", s)
+	}
+	if s := names["push_duration_seconds_bucket+Inf"]; s.value != 1 {
+		t.Errorf("unexpected +Inf bucket %v", s)
+	}
+	if _, ok := names["push_duration_seconds_sum"]; !ok {
+		t.Error("expected histogram sum")
+	}
+	if _, ok := names["push_duration_seconds_count"]; !ok {
+		t.Error("expected histogram count")
+	}
+
+	t.Run("Error Response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			http.Error(w, "out of order sample", http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		err := push.NewRemoteWrite(server.URL, nil, nil, push.BasicAuth{}, newRegistry(), server.Client()).Push(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "out of order sample") {
+			t.Errorf("expected response error, got %v", err)
+		}
+	})
+}
+
+func Test_Pushgateway(t *testing.T) {
+	var method, path, body string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		content, _ := io.ReadAll(req.Body)
+
+		method, path, body = req.Method, req.URL.Path, string(content)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	pusher := push.NewPushgateway(server.URL, "policy-reporter", map[string]string{"cluster": "dev"}, push.BasicAuth{}, newRegistry(), server.Client())
+
+	if err := pusher.Push(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if method != http.MethodPut {
+		t.Errorf("expected PUT to replace the group, got %s", method)
+	}
+	if path != "/metrics/job/policy-reporter/cluster/dev" {
+		t.Errorf("unexpected grouping path %s", path)
+	}
+	if body == "" {
+		t.Error("expected pushed metrics")
+	}
+}
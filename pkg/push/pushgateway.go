@@ -0,0 +1,35 @@
+package push
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+type pushgateway struct {
+	pusher *push.Pusher
+}
+
+func (p *pushgateway) Name() string {
+	return "pushgateway"
+}
+
+func (p *pushgateway) Push(ctx context.Context) error {
+	// PUT replaces all metrics of the group, resolved results are removed from the Pushgateway
+	return p.pusher.PushContext(ctx)
+}
+
+// NewPushgateway pushes all metrics of the gatherer to the Pushgateway with the job and grouping labels
+func NewPushgateway(url, job string, grouping map[string]string, auth BasicAuth, gatherer prometheus.Gatherer, client *http.Client) Pusher {
+	pusher := push.New(url, job).Gatherer(gatherer).Client(client)
+	for name, value := range grouping {
+		pusher = pusher.Grouping(name, value)
+	}
+	if auth.Username != "" {
+		pusher = pusher.BasicAuth(auth.Username, auth.Password.Value())
+	}
+
+	return &pushgateway{pusher: pusher}
+}
@@ -0,0 +1,175 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type label struct {
+	name  string
+	value string
+}
+
+type series struct {
+	labels []label
+	value  float64
+}
+
+type remoteWrite struct {
+	url      string
+	headers  map[string]string
+	labels   map[string]string
+	auth     BasicAuth
+	gatherer prometheus.Gatherer
+	client   *http.Client
+}
+
+func (r *remoteWrite) Name() string {
+	return "remote-write " + r.url
+}
+
+func (r *remoteWrite) Push(ctx context.Context) error {
+	families, err := r.gatherer.Gather()
+	if err != nil {
+		return err
+	}
+
+	body := snappy.Encode(nil, encodeWriteRequest(toSeries(families, r.labels), time.Now().UnixMilli()))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	req.Header.Set("User-Agent", "Policy-Reporter")
+	for name, value := range r.headers {
+		req.Header.Set(name, value)
+	}
+	r.auth.apply(req)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		content, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("remote-write responded with %d: %s", resp.StatusCode, content)
+	}
+
+	return nil
+}
+
+// toSeries flattens the metric families into samples, histograms and summaries are expanded like the text exposition
+func toSeries(families []*dto.MetricFamily, external map[string]string) []series {
+	list := make([]series, 0)
+
+	for _, family := range families {
+		name := family.GetName()
+
+		for _, m := range family.GetMetric() {
+			base := make([]label, 0, len(m.GetLabel())+len(external)+1)
+			for n, v := range external {
+				base = append(base, label{n, v})
+			}
+			for _, l := range m.GetLabel() {
+				base = append(base, label{l.GetName(), l.GetValue()})
+			}
+
+			add := func(suffix string, value float64, extra ...label) {
+				labels := append(append([]label{{"__name__", name + suffix}}, base...), extra...)
+				sort.Slice(labels, func(i, j int) bool { return labels[i].name < labels[j].name })
+
+				list = append(list, series{labels: labels, value: value})
+			}
+
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				add("", m.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				add("", m.GetGauge().GetValue())
+			case dto.MetricType_UNTYPED:
+				add("", m.GetUntyped().GetValue())
+			case dto.MetricType_SUMMARY:
+				for _, q := range m.GetSummary().GetQuantile() {
+					add("", q.GetValue(), label{"quantile", strconv.FormatFloat(q.GetQuantile(), 'g', -1, 64)})
+				}
+				add("_sum", m.GetSummary().GetSampleSum())
+				add("_count", float64(m.GetSummary().GetSampleCount()))
+			case dto.MetricType_HISTOGRAM:
+				for _, b := range m.GetHistogram().GetBucket() {
+					add("_bucket", float64(b.GetCumulativeCount()), label{"le", strconv.FormatFloat(b.GetUpperBound(), 'g', -1, 64)})
+				}
+				add("_bucket", float64(m.GetHistogram().GetSampleCount()), label{"le", "+Inf"})
+				add("_sum", m.GetHistogram().GetSampleSum())
+				add("_count", float64(m.GetHistogram().GetSampleCount()))
+			}
+		}
+	}
+
+	return list
+}
+
+// encodeWriteRequest encodes the prometheus.WriteRequest protobuf message:
+// WriteRequest{1: repeated TimeSeries}, TimeSeries{1: repeated Label, 2: repeated Sample},
+// Label{1: name, 2: value}, Sample{1: double value, 2: int64 timestamp}
+func encodeWriteRequest(list []series, timestamp int64) []byte {
+	var request []byte
+
+	for _, s := range list {
+		var ts []byte
+
+		for _, l := range s.labels {
+			var lb []byte
+			lb = protowire.AppendTag(lb, 1, protowire.BytesType)
+			lb = protowire.AppendString(lb, l.name)
+			lb = protowire.AppendTag(lb, 2, protowire.BytesType)
+			lb = protowire.AppendString(lb, l.value)
+
+			ts = protowire.AppendTag(ts, 1, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, lb)
+		}
+
+		var sample []byte
+		sample = protowire.AppendTag(sample, 1, protowire.Fixed64Type)
+		sample = protowire.AppendFixed64(sample, math.Float64bits(s.value))
+		sample = protowire.AppendTag(sample, 2, protowire.VarintType)
+		sample = protowire.AppendVarint(sample, uint64(timestamp))
+
+		ts = protowire.AppendTag(ts, 2, protowire.BytesType)
+		ts = protowire.AppendBytes(ts, sample)
+
+		request = protowire.AppendTag(request, 1, protowire.BytesType)
+		request = protowire.AppendBytes(request, ts)
+	}
+
+	return request
+}
+
+// NewRemoteWrite sends all metrics of the gatherer to the remote-write endpoint, the external labels are added to each series
+func NewRemoteWrite(url string, headers, labels map[string]string, auth BasicAuth, gatherer prometheus.Gatherer, client *http.Client) Pusher {
+	return &remoteWrite{
+		url:      url,
+		headers:  headers,
+		labels:   labels,
+		auth:     auth,
+		gatherer: gatherer,
+		client:   client,
+	}
+}
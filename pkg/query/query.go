@@ -0,0 +1,399 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/kyverno/policy-reporter/pkg/severity"
+)
+
+const (
+	And = "AND"
+	Or  = "OR"
+)
+
+// Fields supported by filter expressions
+var Fields = map[string]bool{
+	"namespace": true,
+	"kind":      true,
+	"name":      true,
+	"policy":    true,
+	"rule":      true,
+	"severity":  true,
+	"status":    true,
+	"category":  true,
+	"source":    true,
+	"message":   true,
+	"timestamp": true,
+}
+
+// Node of a parsed filter expression
+type Node interface {
+	String() string
+}
+
+// Binary combines two expressions with AND or OR
+type Binary struct {
+	Operator string
+	Left     Node
+	Right    Node
+}
+
+func (b *Binary) String() string {
+	return fmt.Sprintf("(%s %s %s)", b.Left, b.Operator, b.Right)
+}
+
+// Not negates an expression
+type Not struct {
+	Node Node
+}
+
+func (n *Not) String() string {
+	return fmt.Sprintf("NOT %s", n.Node)
+}
+
+// Comparison of a field with a value, supported operators are =, !=, >, >=, <, <=, ~ (wildcard match) and !~
+type Comparison struct {
+	Field    string
+	Operator string
+	Value    string
+}
+
+func (c *Comparison) String() string {
+	return fmt.Sprintf("%s%s%q", c.Field, c.Operator, c.Value)
+}
+
+// Term is a full-text search over message, policy, rule, resource name and namespace
+type Term struct {
+	Value string
+}
+
+func (t *Term) String() string {
+	return strconv.Quote(t.Value)
+}
+
+// SeverityRank returns the position of the severity in the ascending severities including custom levels, -1 for unknown severities
+func SeverityRank(value string) int {
+	return severity.Rank(value)
+}
+
+// Parse a filter expression like `severity>=high AND policy~"*registry*" AND namespace!=kube-system`
+func Parse(input string) (Node, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.peek().value, p.peek().position)
+	}
+
+	return node, nil
+}
+
+type tokenKind int
+
+const (
+	word tokenKind = iota
+	quoted
+	operator
+	open
+	closed
+)
+
+type token struct {
+	kind     tokenKind
+	value    string
+	position int
+}
+
+func tokenize(input string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		char := runes[i]
+
+		switch {
+		case unicode.IsSpace(char):
+			i++
+		case char == '(':
+			tokens = append(tokens, token{kind: open, value: "(", position: i})
+			i++
+		case char == ')':
+			tokens = append(tokens, token{kind: closed, value: ")", position: i})
+			i++
+		case char == '"' || char == '\'':
+			value := strings.Builder{}
+			start := i
+			i++
+
+			for ; i < len(runes) && runes[i] != char; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				value.WriteRune(runes[i])
+			}
+
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+
+			tokens = append(tokens, token{kind: quoted, value: value.String(), position: start})
+			i++
+		case strings.ContainsRune("=!<>~", char):
+			start := i
+			op := string(char)
+			if i+1 < len(runes) && (runes[i+1] == '=' || (char == '!' && runes[i+1] == '~')) {
+				op += string(runes[i+1])
+			}
+
+			switch op {
+			case "=", "!=", ">", ">=", "<", "<=", "~", "!~":
+			default:
+				return nil, fmt.Errorf("invalid operator %q at position %d", op, start)
+			}
+
+			tokens = append(tokens, token{kind: operator, value: op, position: start})
+			i += len(op)
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("()=!<>~\"'", runes[i]) {
+				i++
+			}
+
+			tokens = append(tokens, token{kind: word, value: string(runes[start:i]), position: start})
+		}
+	}
+
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	index  int
+}
+
+func (p *parser) done() bool {
+	return p.index >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.index]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.index]
+	p.index++
+
+	return t
+}
+
+func (p *parser) keyword(value string) bool {
+	if p.done() {
+		return false
+	}
+
+	t := p.peek()
+
+	return t.kind == word && strings.EqualFold(t.value, value)
+}
+
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.keyword(Or) {
+		p.next()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Binary{Operator: Or, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+// parseAnd handles explicit AND and implicit AND between adjacent expressions
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for !p.done() && !p.keyword(Or) && p.peek().kind != closed {
+		if p.keyword(And) {
+			p.next()
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Binary{Operator: And, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if p.done() {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	if p.keyword("NOT") {
+		p.next()
+
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &Not{Node: node}, nil
+	}
+
+	t := p.next()
+
+	switch t.kind {
+	case open:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.done() || p.peek().kind != closed {
+			return nil, fmt.Errorf("missing closing parenthesis for position %d", t.position)
+		}
+		p.next()
+
+		return node, nil
+	case quoted:
+		return &Term{Value: t.value}, nil
+	case word:
+		if p.done() || p.peek().kind != operator {
+			return &Term{Value: t.value}, nil
+		}
+
+		return p.parseComparison(t)
+	}
+
+	return nil, fmt.Errorf("unexpected %q at position %d", t.value, t.position)
+}
+
+func (p *parser) parseComparison(field token) (Node, error) {
+	name := strings.ToLower(field.value)
+	if !Fields[name] {
+		return nil, fmt.Errorf("unknown field %q at position %d", field.value, field.position)
+	}
+
+	op := p.next()
+
+	if p.done() || (p.peek().kind != word && p.peek().kind != quoted) {
+		return nil, fmt.Errorf("missing value for %s%s", field.value, op.value)
+	}
+
+	value := p.next().value
+
+	if name == "timestamp" {
+		if op.value == "~" || op.value == "!~" {
+			return nil, fmt.Errorf("operator %s is not supported for field %s", op.value, name)
+		}
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, fmt.Errorf("timestamp must be a unix timestamp, got %q", value)
+		}
+
+		return &Comparison{Field: name, Operator: op.value, Value: value}, nil
+	}
+
+	switch op.value {
+	case ">", ">=", "<", "<=":
+		if name != "severity" {
+			return nil, fmt.Errorf("operator %s is not supported for field %s", op.value, name)
+		}
+		if SeverityRank(value) < 0 {
+			return nil, fmt.Errorf("unknown severity %q", value)
+		}
+	}
+
+	return &Comparison{Field: name, Operator: op.value, Value: value}, nil
+}
+
+// Match evaluates the expression in memory, value returns the value of a field for the evaluated item
+func Match(node Node, value func(field string) string) bool {
+	switch n := node.(type) {
+	case *Binary:
+		if n.Operator == Or {
+			return Match(n.Left, value) || Match(n.Right, value)
+		}
+
+		return Match(n.Left, value) && Match(n.Right, value)
+	case *Not:
+		return !Match(n.Node, value)
+	case *Term:
+		for _, field := range []string{"message", "policy", "rule", "name", "namespace"} {
+			if strings.Contains(strings.ToLower(value(field)), strings.ToLower(n.Value)) {
+				return true
+			}
+		}
+
+		return false
+	case *Comparison:
+		return compare(n, value(n.Field))
+	}
+
+	return true
+}
+
+func compare(c *Comparison, actual string) bool {
+	switch c.Operator {
+	case "~":
+		return wildcard(c.Value).MatchString(actual)
+	case "!~":
+		return !wildcard(c.Value).MatchString(actual)
+	case "=":
+		return strings.EqualFold(actual, c.Value)
+	case "!=":
+		return !strings.EqualFold(actual, c.Value)
+	}
+
+	var left, right int64
+	if c.Field == "severity" {
+		left, right = int64(SeverityRank(actual)), int64(SeverityRank(c.Value))
+	} else {
+		left, _ = strconv.ParseInt(actual, 10, 64)
+		right, _ = strconv.ParseInt(c.Value, 10, 64)
+	}
+
+	switch c.Operator {
+	case ">":
+		return left > right
+	case ">=":
+		return left >= right
+	case "<":
+		return left < right
+	}
+
+	return left <= right
+}
+
+func wildcard(pattern string) *regexp.Regexp {
+	expression := strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(regexp.QuoteMeta(pattern))
+
+	return regexp.MustCompile("(?is)^" + expression + "$")
+}
@@ -0,0 +1,126 @@
+package query_test
+
+import (
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/query"
+)
+
+func Test_Parse(t *testing.T) {
+	t.Run("Comparisons with AND", func(t *testing.T) {
+		node, err := query.Parse(`severity>=high AND policy~"*registry*" AND namespace!=kube-system`)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		expected := `((severity>="high" AND policy~"*registry*") AND namespace!="kube-system")`
+		if node.String() != expected {
+			t.Errorf("expected %s, got %s", expected, node.String())
+		}
+	})
+
+	t.Run("Precedence of OR and NOT", func(t *testing.T) {
+		node, err := query.Parse(`status=fail OR NOT (kind=Pod and name='nginx')`)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		expected := `(status="fail" OR NOT (kind="Pod" AND name="nginx"))`
+		if node.String() != expected {
+			t.Errorf("expected %s, got %s", expected, node.String())
+		}
+	})
+
+	t.Run("Implicit AND with full-text terms", func(t *testing.T) {
+		node, err := query.Parse(`"requests and limits" Status=fail`)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		binary, ok := node.(*query.Binary)
+		if !ok || binary.Operator != query.And {
+			t.Fatalf("expected AND expression, got %s", node)
+		}
+		if term, ok := binary.Left.(*query.Term); !ok || term.Value != "requests and limits" {
+			t.Errorf("expected full-text term, got %s", binary.Left)
+		}
+		if comparison, ok := binary.Right.(*query.Comparison); !ok || comparison.Field != "status" {
+			t.Errorf("expected lower cased status field, got %s", binary.Right)
+		}
+	})
+
+	t.Run("Escaped quotes", func(t *testing.T) {
+		node, err := query.Parse(`message~"*\"test\"*"`)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if comparison := node.(*query.Comparison); comparison.Value != `*"test"*` {
+			t.Errorf("unexpected value %s", comparison.Value)
+		}
+	})
+
+	t.Run("Invalid expressions", func(t *testing.T) {
+		for _, expression := range []string{
+			``,
+			`unknown=value`,
+			`severity>=unknown`,
+			`policy>test`,
+			`timestamp>yesterday`,
+			`timestamp~"1*"`,
+			`status=`,
+			`(status=fail`,
+			`status=fail)`,
+			`policy="test`,
+			`status=>fail`,
+			`status=fail AND`,
+		} {
+			if _, err := query.Parse(expression); err == nil {
+				t.Errorf("expected error for %q", expression)
+			}
+		}
+	})
+}
+
+func Test_SeverityRank(t *testing.T) {
+	if query.SeverityRank("critical") <= query.SeverityRank("high") {
+		t.Error("expected critical to rank higher then high")
+	}
+	if query.SeverityRank("unknown") != -1 {
+		t.Error("expected -1 for unknown severities")
+	}
+}
+
+func Test_Match(t *testing.T) {
+	values := map[string]string{
+		"namespace": "kube-system",
+		"policy":    "restrict-image-registries",
+		"severity":  "high",
+		"status":    "fail",
+		"message":   "image registry not allowed",
+		"timestamp": "1662422400",
+	}
+
+	value := func(field string) string { return values[field] }
+
+	cases := map[string]bool{
+		`severity>=high AND policy~"*registry*"`:  false,
+		`severity>=high AND policy~"*registries"`: true,
+		`namespace!=kube-system OR status=FAIL`:   true,
+		`NOT severity<medium`:                     true,
+		`policy!~"restrict-*"`:                    false,
+		`"not allowed" timestamp>=1662422400`:     true,
+		`timestamp<1662422400`:                    false,
+	}
+
+	for expression, expected := range cases {
+		node, err := query.Parse(expression)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %s", expression, err)
+		}
+
+		if query.Match(node, value) != expected {
+			t.Errorf("expected %q to match %v", expression, expected)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package report
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
 	"github.com/kyverno/policy-reporter/pkg/validate"
 )
@@ -26,25 +29,69 @@ func NewFilter(disableClusterReports bool, namespace validate.RuleSets) *Filter
 	return &Filter{disableClusterReports, namespace}
 }
 
+// ResourceMetadata provides the metadata of result resources
+type ResourceMetadata interface {
+	Get(resource *corev1.ObjectReference) (*metav1.PartialObjectMetadata, bool)
+}
+
+// Terminating reports whether the resource or its namespace has a deletion timestamp,
+// a namespace in phase Terminating always has a deletion timestamp
+func Terminating(metadata ResourceMetadata, resource *corev1.ObjectReference) bool {
+	if resource == nil {
+		return false
+	}
+
+	if meta, ok := metadata.Get(resource); ok && meta.DeletionTimestamp != nil {
+		return true
+	}
+
+	if resource.Namespace == "" {
+		return false
+	}
+
+	namespace, ok := metadata.Get(&corev1.ObjectReference{APIVersion: "v1", Kind: "Namespace", Name: resource.Namespace})
+
+	return ok && namespace.DeletionTimestamp != nil
+}
+
 type ResultValidation = func(v1alpha2.PolicyReportResult) bool
 
+// ReportResultValidation validates a result in the context of its report
+type ReportResultValidation = func(v1alpha2.ReportInterface, v1alpha2.PolicyReportResult) bool
+
 type ResultFilter struct {
-	validations     []ResultValidation
-	Sources         []string
-	MinimumPriority string
+	validations       []ResultValidation
+	reportValidations []ReportResultValidation
+	Sources           []string
+	MinimumPriority   string
 }
 
 func (rf *ResultFilter) AddValidation(v ResultValidation) {
 	rf.validations = append(rf.validations, v)
 }
 
+func (rf *ResultFilter) AddReportValidation(v ReportResultValidation) {
+	rf.reportValidations = append(rf.reportValidations, v)
+}
+
+// Validate the result without a report context
 func (rf *ResultFilter) Validate(result v1alpha2.PolicyReportResult) bool {
+	return rf.ValidateReportResult(nil, result)
+}
+
+func (rf *ResultFilter) ValidateReportResult(report v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) bool {
 	for _, validation := range rf.validations {
 		if !validation(result) {
 			return false
 		}
 	}
 
+	for _, validation := range rf.reportValidations {
+		if !validation(report, result) {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -2,6 +2,10 @@ package report_test
 
 import (
 	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
 	"github.com/kyverno/policy-reporter/pkg/fixtures"
@@ -75,6 +79,18 @@ func Test_ResultFilter(t *testing.T) {
 			t.Error("Expected result validates to false")
 		}
 	})
+	t.Run("filter result with a report validation", func(t *testing.T) {
+		filter := report.NewResultFilter()
+		filter.AddReportValidation(func(r v1alpha2.ReportInterface, _ v1alpha2.PolicyReportResult) bool {
+			return r != nil && r.GetNamespace() == preport.GetNamespace()
+		})
+		if !filter.ValidateReportResult(preport, fixtures.FailResult) {
+			t.Error("Expected result validates to true")
+		}
+		if filter.Validate(fixtures.FailResult) {
+			t.Error("Expected result without report validates to false")
+		}
+	})
 }
 
 func Test_ReportFilter(t *testing.T) {
@@ -92,3 +108,79 @@ func Test_ReportFilter(t *testing.T) {
 		}
 	})
 }
+
+type metadata map[string]*metav1.PartialObjectMetadata
+
+func (m metadata) Get(res *corev1.ObjectReference) (*metav1.PartialObjectMetadata, bool) {
+	meta, ok := m[res.Kind+"/"+res.Name]
+
+	return meta, ok
+}
+
+func Test_Terminating(t *testing.T) {
+	deleted := &metav1.Time{Time: time.Now()}
+
+	t.Run("active resource", func(t *testing.T) {
+		cache := metadata{"Deployment/nginx": {}, "Namespace/test": {}}
+		if report.Terminating(cache, fixtures.FailResult.GetResource()) {
+			t.Error("Expected active resource not to be terminating")
+		}
+	})
+	t.Run("unknown resource", func(t *testing.T) {
+		if report.Terminating(metadata{}, fixtures.FailResult.GetResource()) {
+			t.Error("Expected unknown resource not to be terminating")
+		}
+		if report.Terminating(metadata{}, nil) {
+			t.Error("Expected missing resource not to be terminating")
+		}
+	})
+	t.Run("deleted resource", func(t *testing.T) {
+		cache := metadata{"Deployment/nginx": {ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: deleted}}}
+		if !report.Terminating(cache, fixtures.FailResult.GetResource()) {
+			t.Error("Expected resource with deletion timestamp to be terminating")
+		}
+	})
+	t.Run("terminating namespace", func(t *testing.T) {
+		cache := metadata{"Deployment/nginx": {}, "Namespace/test": {ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: deleted}}}
+		if !report.Terminating(cache, fixtures.FailResult.GetResource()) {
+			t.Error("Expected resource of a terminating namespace to be terminating")
+		}
+	})
+}
+
+func Test_SeverityFloor(t *testing.T) {
+	floor := report.NewSeverityFloor(map[string]string{
+		"kube-system": "critical",
+		"kube-*":      "high",
+		"prod-*":      "medium",
+	})
+
+	result := func(namespace string, severity v1alpha2.PolicySeverity) v1alpha2.PolicyReportResult {
+		return v1alpha2.PolicyReportResult{
+			Severity:  severity,
+			Resources: []corev1.ObjectReference{{Kind: "Pod", Name: "nginx", Namespace: namespace}},
+		}
+	}
+
+	cases := []struct {
+		name   string
+		result v1alpha2.PolicyReportResult
+		valid  bool
+	}{
+		{"exact namespace", result("kube-system", v1alpha2.SeverityHigh), false},
+		{"exact namespace severity", result("kube-system", v1alpha2.SeverityCritical), true},
+		{"wildcard namespace", result("kube-public", v1alpha2.SeverityHigh), true},
+		{"below wildcard severity", result("prod-payments", v1alpha2.SeverityLow), false},
+		{"without severity", result("prod-payments", ""), false},
+		{"namespace without floor", result("develop", v1alpha2.SeverityInfo), true},
+		{"cluster scoped", v1alpha2.PolicyReportResult{Severity: v1alpha2.SeverityInfo}, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if floor.Validate(c.result) != c.valid {
+				t.Errorf("Expected result to be valid: %v", c.valid)
+			}
+		})
+	}
+}
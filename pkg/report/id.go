@@ -0,0 +1,121 @@
+package report
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/segmentio/fasthash/fnv1a"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+const propertyPrefix = "properties."
+
+var idFields = map[string]func(r v1alpha2.PolicyReportResult) string{
+	"resource.apiVersion": func(r v1alpha2.PolicyReportResult) string { return resource(r).APIVersion },
+	"resource.kind":       func(r v1alpha2.PolicyReportResult) string { return resource(r).Kind },
+	"resource.name":       func(r v1alpha2.PolicyReportResult) string { return resource(r).Name },
+	"resource.namespace":  func(r v1alpha2.PolicyReportResult) string { return resource(r).Namespace },
+	"resource.uid":        func(r v1alpha2.PolicyReportResult) string { return string(resource(r).UID) },
+	"source":              func(r v1alpha2.PolicyReportResult) string { return r.Source },
+	"policy":              func(r v1alpha2.PolicyReportResult) string { return r.Policy },
+	"rule":                func(r v1alpha2.PolicyReportResult) string { return r.Rule },
+	"result":              func(r v1alpha2.PolicyReportResult) string { return string(r.Result) },
+	"category":            func(r v1alpha2.PolicyReportResult) string { return r.Category },
+	"severity":            func(r v1alpha2.PolicyReportResult) string { return string(r.Severity) },
+	"message":             func(r v1alpha2.PolicyReportResult) string { return r.Message },
+}
+
+func resource(r v1alpha2.PolicyReportResult) corev1.ObjectReference {
+	if res := r.GetResource(); res != nil {
+		return *res
+	}
+
+	return corev1.ObjectReference{}
+}
+
+// IDStrategy generates result IDs from a configurable list of fields, e.g. without the message to keep the ID of results with changing messages
+type IDStrategy struct {
+	fields []func(r v1alpha2.PolicyReportResult) string
+}
+
+// ID hashes the configured fields of the result
+func (s *IDStrategy) ID(r v1alpha2.PolicyReportResult) string {
+	h1 := fnv1a.Init64
+	for _, field := range s.fields {
+		h1 = fnv1a.AddString64(h1, field(r))
+	}
+
+	return strconv.FormatUint(h1, 10)
+}
+
+// NewIDStrategy creates an IDStrategy, supported fields are source, policy, rule, result, category, severity, message,
+// resource.apiVersion, resource.kind, resource.name, resource.namespace, resource.uid and properties.<key>
+func NewIDStrategy(fields []string) (*IDStrategy, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("at least one field required")
+	}
+
+	strategy := &IDStrategy{fields: make([]func(r v1alpha2.PolicyReportResult) string, 0, len(fields))}
+
+	for _, name := range fields {
+		if strings.HasPrefix(name, propertyPrefix) && len(name) > len(propertyPrefix) {
+			key := strings.TrimPrefix(name, propertyPrefix)
+			strategy.fields = append(strategy.fields, func(r v1alpha2.PolicyReportResult) string { return r.Properties[key] })
+			continue
+		}
+
+		field, ok := idFields[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown field '%s'", name)
+		}
+
+		strategy.fields = append(strategy.fields, field)
+	}
+
+	return strategy, nil
+}
+
+// IDMapper sets the ID of each report result with the IDStrategy of its source,
+// results with an explicit resultID property keep their ID
+type IDMapper struct {
+	sources  map[string]*IDStrategy
+	fallback *IDStrategy
+}
+
+// Register the strategy for results of the source, an empty source configures the fallback for all other sources
+func (m *IDMapper) Register(source string, strategy *IDStrategy) {
+	if source == "" {
+		m.fallback = strategy
+		return
+	}
+
+	m.sources[strings.ToLower(source)] = strategy
+}
+
+// Map the result IDs of the report in place
+func (m *IDMapper) Map(rep v1alpha2.ReportInterface) {
+	results := rep.GetResults()
+
+	for i := range results {
+		if _, ok := results[i].Properties[v1alpha2.ResultIDKey]; ok {
+			continue
+		}
+
+		strategy, ok := m.sources[strings.ToLower(results[i].Source)]
+		if !ok {
+			strategy = m.fallback
+		}
+
+		if strategy != nil {
+			results[i].ID = strategy.ID(results[i])
+		}
+	}
+}
+
+// NewIDMapper creates an IDMapper without strategies, results keep their default ID
+func NewIDMapper() *IDMapper {
+	return &IDMapper{sources: make(map[string]*IDStrategy)}
+}
@@ -0,0 +1,81 @@
+package report_test
+
+import (
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+func Test_IDStrategy(t *testing.T) {
+	t.Run("ignore message changes", func(t *testing.T) {
+		strategy, err := report.NewIDStrategy([]string{"resource.uid", "policy", "rule"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		changed := fixtures.FailResult
+		changed.Message = "another message"
+
+		if strategy.ID(fixtures.FailResult) != strategy.ID(changed) {
+			t.Error("expected same ID for results with different messages")
+		}
+		if strategy.ID(fixtures.FailResult) == strategy.ID(fixtures.FailPodResult) {
+			t.Error("expected different IDs for results of different resources")
+		}
+	})
+	t.Run("use property", func(t *testing.T) {
+		strategy, err := report.NewIDStrategy([]string{"policy", "properties.vulnerabilityID"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r1 := v1alpha2.PolicyReportResult{Policy: "trivy", Properties: map[string]string{"vulnerabilityID": "CVE-1"}}
+		r2 := v1alpha2.PolicyReportResult{Policy: "trivy", Properties: map[string]string{"vulnerabilityID": "CVE-2"}}
+
+		if strategy.ID(r1) == strategy.ID(r2) {
+			t.Error("expected different IDs for different property values")
+		}
+	})
+	t.Run("unknown field", func(t *testing.T) {
+		if _, err := report.NewIDStrategy([]string{"timestamp"}); err == nil {
+			t.Error("expected unknown field error")
+		}
+		if _, err := report.NewIDStrategy(nil); err == nil {
+			t.Error("expected error without fields")
+		}
+	})
+}
+
+func Test_IDMapper(t *testing.T) {
+	kyverno, _ := report.NewIDStrategy([]string{"policy", "rule"})
+	fallback, _ := report.NewIDStrategy([]string{"message"})
+
+	mapper := report.NewIDMapper()
+	mapper.Register("kyverno", kyverno)
+	mapper.Register("", fallback)
+
+	withID := fixtures.FailResult
+	withID.Source = "Kyverno"
+	withID.Properties = map[string]string{v1alpha2.ResultIDKey: "fixed"}
+
+	kyvernoResult := fixtures.FailResult
+	kyvernoResult.ID = ""
+
+	trivyResult := fixtures.TrivyResult
+	trivyResult.ID = ""
+
+	rep := &v1alpha2.PolicyReport{Results: []v1alpha2.PolicyReportResult{kyvernoResult, trivyResult, withID}}
+	mapper.Map(rep)
+
+	if rep.Results[0].GetID() != kyverno.ID(kyvernoResult) {
+		t.Error("expected ID of the source strategy")
+	}
+	if rep.Results[1].GetID() != fallback.ID(trivyResult) {
+		t.Error("expected ID of the fallback strategy")
+	}
+	if rep.Results[2].ID != withID.ID {
+		t.Error("expected result with resultID property to keep its ID")
+	}
+}
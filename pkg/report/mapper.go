@@ -1,6 +1,8 @@
 package report
 
 import (
+	"regexp"
+
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
 )
 
@@ -8,8 +10,25 @@ type Mapper interface {
 	ResolvePriority(policy string, severity v1alpha2.PolicySeverity) v1alpha2.Priority
 }
 
+// PriorityRule overrides the priority of policies matching the exact name or the pattern
+type PriorityRule struct {
+	Policy   string
+	Pattern  *regexp.Regexp
+	Priority v1alpha2.Priority
+}
+
+func (r PriorityRule) Matches(policy string) bool {
+	if r.Policy != "" && r.Policy == policy {
+		return true
+	}
+
+	return r.Pattern != nil && r.Pattern.MatchString(policy)
+}
+
 type mapper struct {
 	priorityMap map[string]string
+	severityMap map[v1alpha2.PolicySeverity]v1alpha2.Priority
+	rules       []PriorityRule
 }
 
 func (m *mapper) ResolvePriority(policy string, severity v1alpha2.PolicySeverity) v1alpha2.Priority {
@@ -17,7 +36,17 @@ func (m *mapper) ResolvePriority(policy string, severity v1alpha2.PolicySeverity
 		return v1alpha2.NewPriority(priority)
 	}
 
+	for _, rule := range m.rules {
+		if rule.Matches(policy) {
+			return rule.Priority
+		}
+	}
+
 	if severity != "" {
+		if priority, ok := m.severityMap[severity]; ok {
+			return priority
+		}
+
 		return v1alpha2.PriorityFromSeverity(severity)
 	}
 
@@ -30,5 +59,11 @@ func (m *mapper) ResolvePriority(policy string, severity v1alpha2.PolicySeverity
 
 // NewMapper creates an new Mapper instance
 func NewMapper(priorities map[string]string) Mapper {
-	return &mapper{priorityMap: priorities}
+	return NewRuleMapper(priorities, nil, nil)
+}
+
+// NewRuleMapper creates a Mapper which resolves the priority by the exact policy name, the first matching rule
+// and the severity, the severities map overrides the default priority of a severity
+func NewRuleMapper(priorities map[string]string, severities map[v1alpha2.PolicySeverity]v1alpha2.Priority, rules []PriorityRule) Mapper {
+	return &mapper{priorityMap: priorities, severityMap: severities, rules: rules}
 }
@@ -1,6 +1,7 @@
 package report_test
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
@@ -43,3 +44,39 @@ func Test_ResolvePriority(t *testing.T) {
 		}
 	})
 }
+
+func Test_ResolvePriorityWithRules(t *testing.T) {
+	mapper := report.NewRuleMapper(
+		map[string]string{"priority-test": "warning"},
+		map[v1alpha2.PolicySeverity]v1alpha2.Priority{v1alpha2.SeverityHigh: v1alpha2.CriticalPriority},
+		[]report.PriorityRule{
+			{Policy: "require-labels", Priority: v1alpha2.InfoPriority},
+			{Pattern: regexp.MustCompile(`^disallow-`), Priority: v1alpha2.ErrorPriority},
+			{Pattern: regexp.MustCompile(`-privileged$`), Priority: v1alpha2.DebugPriority},
+		},
+	)
+
+	t.Run("priority map before rules", func(t *testing.T) {
+		if priority := mapper.ResolvePriority("priority-test", v1alpha2.SeverityHigh); priority != v1alpha2.WarningPriority {
+			t.Errorf("expected priority warning, got %s", priority)
+		}
+	})
+	t.Run("priority from exact rule", func(t *testing.T) {
+		if priority := mapper.ResolvePriority("require-labels", v1alpha2.SeverityHigh); priority != v1alpha2.InfoPriority {
+			t.Errorf("expected priority info, got %s", priority)
+		}
+	})
+	t.Run("priority from first matching pattern", func(t *testing.T) {
+		if priority := mapper.ResolvePriority("disallow-privileged", ""); priority != v1alpha2.ErrorPriority {
+			t.Errorf("expected priority error, got %s", priority)
+		}
+	})
+	t.Run("priority from severity mapping", func(t *testing.T) {
+		if priority := mapper.ResolvePriority("test", v1alpha2.SeverityHigh); priority != v1alpha2.CriticalPriority {
+			t.Errorf("expected priority critical, got %s", priority)
+		}
+		if priority := mapper.ResolvePriority("test", v1alpha2.SeverityMedium); priority != v1alpha2.WarningPriority {
+			t.Errorf("expected default priority warning for unmapped severity, got %s", priority)
+		}
+	})
+}
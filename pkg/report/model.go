@@ -1,7 +1,10 @@
 package report
 
 import (
+	"time"
+
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Event Enum
@@ -31,8 +34,18 @@ const (
 type LifecycleEvent struct {
 	Type         Event
 	PolicyReport v1alpha2.ReportInterface
+	// Observed is the time the change of the PolicyReport was first observed by the informer
+	Observed time.Time
+	// Trace of the received change, invalid if tracing is disabled
+	Trace trace.SpanContext
 }
 
+// ClusterLabel identifies the cluster of PolicyReports pushed by federated edge instances
+const ClusterLabel = "policy-reporter.kyverno.io/cluster"
+
+// ClusterScope pseudo namespace of cluster scoped results in summaries, email groupings and namespace filters
+const ClusterScope = "cluster"
+
 // ResourceType Enum defined for PolicyReport
 type ResourceType = string
 
@@ -0,0 +1,43 @@
+package report
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// NodeKind of the resource of node scoped results
+const NodeKind = "Node"
+
+// NodeName of a node scoped result, the name of its Node resource or the node property of host level results
+func NodeName(result v1alpha2.PolicyReportResult) string {
+	if result.HasResource() {
+		if res := result.GetResource(); res.Kind == NodeKind {
+			return res.Name
+		}
+
+		return ""
+	}
+
+	return result.Properties[v1alpha2.NodeKey]
+}
+
+// MapNode attributes host level results without resources to the Node of their node property,
+// e.g. of kube-bench or node scanners, so they are listed as results of the node
+func MapNode(rep v1alpha2.ReportInterface) {
+	results := rep.GetResults()
+
+	for i := range results {
+		if results[i].HasResource() {
+			continue
+		}
+
+		node := results[i].Properties[v1alpha2.NodeKey]
+		if node == "" {
+			continue
+		}
+
+		results[i].Resources = []corev1.ObjectReference{{APIVersion: "v1", Kind: NodeKind, Name: node}}
+		results[i].ID = ""
+	}
+}
@@ -0,0 +1,52 @@
+package report_test
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+func Test_MapNode(t *testing.T) {
+	rep := &v1alpha2.ClusterPolicyReport{
+		ObjectMeta: v1.ObjectMeta{Name: "kube-bench-worker-1"},
+		Results: []v1alpha2.PolicyReportResult{
+			{Policy: "4.1.1", Result: v1alpha2.StatusFail, Properties: map[string]string{v1alpha2.NodeKey: "worker-1"}},
+			{Policy: "4.1.2", Result: v1alpha2.StatusPass},
+			{Policy: "4.1.3", Result: v1alpha2.StatusFail, Properties: map[string]string{v1alpha2.NodeKey: "worker-1"}, Resources: []corev1.ObjectReference{{Kind: "Pod", Name: "nginx"}}},
+		},
+	}
+
+	hostID := rep.Results[0].GetID()
+
+	report.MapNode(rep)
+
+	res := rep.Results[0].GetResource()
+	if res == nil || res.Kind != "Node" || res.Name != "worker-1" {
+		t.Fatalf("expected node resource, got %v", res)
+	}
+	if rep.Results[0].GetID() == hostID {
+		t.Error("expected the result ID to be hashed with the node resource")
+	}
+	if rep.Results[1].HasResource() {
+		t.Error("expected results without node property to stay without resource")
+	}
+	if rep.Results[2].GetResource().Kind != "Pod" {
+		t.Error("expected results with resources to keep them")
+	}
+}
+
+func Test_NodeName(t *testing.T) {
+	if name := report.NodeName(v1alpha2.PolicyReportResult{Resources: []corev1.ObjectReference{{Kind: "Node", Name: "worker-1"}}}); name != "worker-1" {
+		t.Errorf("expected name of the node resource, got %s", name)
+	}
+	if name := report.NodeName(v1alpha2.PolicyReportResult{Properties: map[string]string{v1alpha2.NodeKey: "worker-2"}}); name != "worker-2" {
+		t.Errorf("expected node property, got %s", name)
+	}
+	if name := report.NodeName(v1alpha2.PolicyReportResult{Resources: []corev1.ObjectReference{{Kind: "Pod", Name: "nginx"}}, Properties: map[string]string{v1alpha2.NodeKey: "worker-2"}}); name != "" {
+		t.Errorf("expected no node for other resources, got %s", name)
+	}
+}
@@ -0,0 +1,52 @@
+package report
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// OrphanedKey is the property of results whose resource no longer exists
+const OrphanedKey = "orphaned"
+
+// ResourceChecker checks the existence of result resources
+type ResourceChecker interface {
+	// Exists reports whether the resource exists, known is false if the existence can not be checked
+	Exists(resource *corev1.ObjectReference) (exists bool, known bool)
+}
+
+// OrphanDetector flags the results of resources which no longer exist with the orphaned property
+type OrphanDetector struct {
+	checker ResourceChecker
+}
+
+// Map flags the results of the PolicyReport before it is published, results of resources with an unknown existence are not flagged
+func (d *OrphanDetector) Map(r v1alpha2.ReportInterface) {
+	results := r.GetResults()
+
+	for i := range results {
+		if !results[i].HasResource() {
+			continue
+		}
+
+		if exists, known := d.checker.Exists(results[i].GetResource()); exists || !known {
+			continue
+		}
+
+		if results[i].Properties == nil {
+			results[i].Properties = make(map[string]string, 1)
+		}
+
+		results[i].Properties[OrphanedKey] = "true"
+	}
+}
+
+// Orphaned reports whether the result was flagged by the OrphanDetector
+func Orphaned(r v1alpha2.PolicyReportResult) bool {
+	return r.Properties[OrphanedKey] == "true"
+}
+
+// NewOrphanDetector creates an OrphanDetector with the checker of the resource existence
+func NewOrphanDetector(checker ResourceChecker) *OrphanDetector {
+	return &OrphanDetector{checker: checker}
+}
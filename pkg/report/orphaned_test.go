@@ -0,0 +1,44 @@
+package report_test
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+type checker map[string]bool
+
+func (c checker) Exists(resource *corev1.ObjectReference) (bool, bool) {
+	exists, known := c[resource.Name]
+
+	return exists, known
+}
+
+func Test_OrphanDetector(t *testing.T) {
+	resource := func(name string) []corev1.ObjectReference {
+		return []corev1.ObjectReference{{APIVersion: "v1", Kind: "Pod", Name: name, Namespace: "test"}}
+	}
+
+	rep := &v1alpha2.PolicyReport{
+		Results: []v1alpha2.PolicyReportResult{
+			{Policy: "require-labels", Result: v1alpha2.StatusFail, Resources: resource("nginx")},
+			{Policy: "require-labels", Result: v1alpha2.StatusFail, Resources: resource("deleted")},
+			{Policy: "require-labels", Result: v1alpha2.StatusFail, Resources: resource("unknown")},
+			{Policy: "require-labels", Result: v1alpha2.StatusFail},
+		},
+	}
+
+	report.NewOrphanDetector(checker{"nginx": true, "deleted": false}).Map(rep)
+
+	for i, expected := range []bool{false, true, false, false} {
+		if orphaned := report.Orphaned(rep.Results[i]); orphaned != expected {
+			t.Errorf("results[%d]: expected orphaned %v, got %v", i, expected, orphaned)
+		}
+	}
+	if rep.Results[1].Properties[report.OrphanedKey] != "true" {
+		t.Errorf("expected the orphaned property, got %v", rep.Results[1].Properties)
+	}
+}
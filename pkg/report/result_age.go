@@ -0,0 +1,97 @@
+package report
+
+import (
+	"strings"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// ResultAge is the maximum age of result timestamps, with overrides per source
+type ResultAge struct {
+	maxAge  time.Duration
+	sources map[string]time.Duration
+}
+
+// MaxAge of the results of the source, zero disables the check
+func (a *ResultAge) MaxAge(source string) time.Duration {
+	if age, ok := a.sources[strings.ToLower(source)]; ok {
+		return age
+	}
+
+	return a.maxAge
+}
+
+// Validate returns false for results with a timestamp older than the maximum age of their source,
+// results without timestamp are kept
+func (a *ResultAge) Validate(r v1alpha2.PolicyReportResult) bool {
+	maxAge := a.MaxAge(r.Source)
+	if maxAge <= 0 || r.Timestamp.Seconds == 0 {
+		return true
+	}
+
+	return time.Since(time.Unix(r.Timestamp.Seconds, int64(r.Timestamp.Nanos))) <= maxAge
+}
+
+// Map removes the stale results from the PolicyReport and its summary before it is published
+func (a *ResultAge) Map(r v1alpha2.ReportInterface) {
+	var summary *v1alpha2.PolicyReportSummary
+	var results *[]v1alpha2.PolicyReportResult
+
+	switch report := r.(type) {
+	case *v1alpha2.PolicyReport:
+		summary, results = &report.Summary, &report.Results
+	case *v1alpha2.ClusterPolicyReport:
+		summary, results = &report.Summary, &report.Results
+	default:
+		return
+	}
+
+	list := make([]v1alpha2.PolicyReportResult, 0, len(*results))
+	for _, result := range *results {
+		if a.Validate(result) {
+			list = append(list, result)
+			continue
+		}
+
+		decreaseSummary(summary, result.Result)
+	}
+
+	if len(list) != len(*results) {
+		*results = list
+	}
+}
+
+// decreaseSummary by a removed result with the given status
+func decreaseSummary(summary *v1alpha2.PolicyReportSummary, status v1alpha2.PolicyResult) {
+	switch status {
+	case v1alpha2.StatusPass:
+		summary.Pass = decrease(summary.Pass)
+	case v1alpha2.StatusSkip:
+		summary.Skip = decrease(summary.Skip)
+	case v1alpha2.StatusWarn:
+		summary.Warn = decrease(summary.Warn)
+	case v1alpha2.StatusFail:
+		summary.Fail = decrease(summary.Fail)
+	case v1alpha2.StatusError:
+		summary.Error = decrease(summary.Error)
+	}
+}
+
+func decrease(count int) int {
+	if count > 0 {
+		return count - 1
+	}
+
+	return 0
+}
+
+// NewResultAge creates a ResultAge with the default maximum age and the overrides per source, zero disables the check
+func NewResultAge(maxAge time.Duration, sources map[string]time.Duration) *ResultAge {
+	lower := make(map[string]time.Duration, len(sources))
+	for source, age := range sources {
+		lower[strings.ToLower(source)] = age
+	}
+
+	return &ResultAge{maxAge: maxAge, sources: lower}
+}
@@ -0,0 +1,62 @@
+package report_test
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+func aged(source string, status v1alpha2.PolicyResult, age time.Duration) v1alpha2.PolicyReportResult {
+	return v1alpha2.PolicyReportResult{
+		Source:    source,
+		Policy:    "require-labels",
+		Result:    status,
+		Timestamp: metav1.Timestamp{Seconds: time.Now().Add(-age).Unix()},
+	}
+}
+
+func Test_ResultAge(t *testing.T) {
+	age := report.NewResultAge(24*time.Hour, map[string]time.Duration{"Kube-Bench": 7 * 24 * time.Hour, "trivy": 0})
+
+	t.Run("Validate", func(t *testing.T) {
+		if !age.Validate(aged("kyverno", v1alpha2.StatusFail, time.Hour)) {
+			t.Error("expected recent result to be valid")
+		}
+		if age.Validate(aged("kyverno", v1alpha2.StatusFail, 48*time.Hour)) {
+			t.Error("expected stale result to be invalid")
+		}
+		if !age.Validate(aged("kube-bench", v1alpha2.StatusFail, 48*time.Hour)) {
+			t.Error("expected the source override to allow older results")
+		}
+		if !age.Validate(aged("trivy", v1alpha2.StatusFail, 365*24*time.Hour)) {
+			t.Error("expected the check to be disabled for the source")
+		}
+		if !age.Validate(v1alpha2.PolicyReportResult{Source: "kyverno", Result: v1alpha2.StatusFail}) {
+			t.Error("expected result without timestamp to be valid")
+		}
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		polr := &v1alpha2.PolicyReport{
+			Results: []v1alpha2.PolicyReportResult{
+				aged("kyverno", v1alpha2.StatusFail, time.Hour),
+				aged("kyverno", v1alpha2.StatusFail, 48*time.Hour),
+				aged("kyverno", v1alpha2.StatusPass, 48*time.Hour),
+			},
+			Summary: v1alpha2.PolicyReportSummary{Fail: 2, Pass: 1},
+		}
+
+		age.Map(polr)
+
+		if len(polr.Results) != 1 {
+			t.Fatalf("expected stale results to be removed, got %d", len(polr.Results))
+		}
+		if polr.Summary.Fail != 1 || polr.Summary.Pass != 0 {
+			t.Errorf("expected summary without the stale results, got %+v", polr.Summary)
+		}
+	})
+}
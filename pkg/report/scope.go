@@ -0,0 +1,32 @@
+package report
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// MapScope attributes results without resources to the scope resource of the report,
+// e.g. of reports created per workload, the IDs of mapped results are hashed with the scope resource
+func MapScope(rep v1alpha2.ReportInterface) {
+	scope := rep.GetScope()
+	if scope == nil {
+		return
+	}
+
+	resource := *scope
+	if resource.Namespace == "" {
+		resource.Namespace = rep.GetNamespace()
+	}
+
+	results := rep.GetResults()
+
+	for i := range results {
+		if results[i].HasResource() {
+			continue
+		}
+
+		results[i].Resources = []corev1.ObjectReference{resource}
+		results[i].ID = ""
+	}
+}
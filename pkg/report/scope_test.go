@@ -0,0 +1,43 @@
+package report_test
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+func Test_MapScope(t *testing.T) {
+	pod := corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Name: "nginx", UID: "536ab69f-1b3c-4bd9-9ba4-274a56188411"}
+	deployment := corev1.ObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "app", Namespace: "test"}
+
+	rep := &v1alpha2.PolicyReport{
+		ObjectMeta: v1.ObjectMeta{Name: "scoped", Namespace: "test"},
+		Scope:      &pod,
+		Results: []v1alpha2.PolicyReportResult{
+			{Policy: "require-labels", Result: v1alpha2.StatusFail},
+			{Policy: "require-labels", Result: v1alpha2.StatusFail, Resources: []corev1.ObjectReference{deployment}},
+		},
+	}
+
+	unscopedID := rep.Results[0].GetID()
+
+	report.MapScope(rep)
+
+	res := rep.Results[0].GetResource()
+	if res == nil || res.Name != "nginx" || res.Namespace != "test" {
+		t.Fatalf("expected scope resource with the report namespace, got %v", res)
+	}
+	if rep.Results[0].GetID() == unscopedID {
+		t.Error("expected the result ID to be hashed with the scope resource")
+	}
+	if rep.Results[1].GetResource().Name != "app" {
+		t.Error("expected results with resources to keep them")
+	}
+	if rep.Scope.Namespace != "" {
+		t.Error("expected the report scope to be unchanged")
+	}
+}
@@ -0,0 +1,63 @@
+package report
+
+import (
+	"sort"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/query"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+type severityFloorRule struct {
+	namespace string
+	rank      int
+}
+
+// SeverityFloor is the minimum severity of results per namespace, namespaces support wildcards and "regex:" expressions
+type SeverityFloor struct {
+	rules []severityFloorRule
+}
+
+// Validate returns false for results below the minimum severity of their namespace,
+// results without severity are below every minimum. Cluster scoped results use the empty namespace
+func (f *SeverityFloor) Validate(r v1alpha2.PolicyReportResult) bool {
+	var namespace string
+	if res := r.GetResource(); res != nil {
+		namespace = res.Namespace
+	}
+
+	for _, rule := range f.rules {
+		if validate.Match(rule.namespace, namespace) {
+			return query.SeverityRank(string(r.Severity)) >= rule.rank
+		}
+	}
+
+	return true
+}
+
+// NewSeverityFloor creates a SeverityFloor of namespace patterns and their minimum severity.
+// Exact namespaces take precedence over patterns, longer patterns over shorter ones
+func NewSeverityFloor(namespaces map[string]string) *SeverityFloor {
+	rules := make([]severityFloorRule, 0, len(namespaces))
+	for namespace, severity := range namespaces {
+		rules = append(rules, severityFloorRule{namespace: namespace, rank: query.SeverityRank(severity)})
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		a, b := rules[i].namespace, rules[j].namespace
+		if isPattern(a) != isPattern(b) {
+			return isPattern(b)
+		}
+		if len(a) != len(b) {
+			return len(a) > len(b)
+		}
+
+		return a < b
+	})
+
+	return &SeverityFloor{rules: rules}
+}
+
+func isPattern(namespace string) bool {
+	return validate.IsGlob(namespace) || validate.IsRegex(namespace)
+}
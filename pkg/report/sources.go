@@ -0,0 +1,303 @@
+package report
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// SourceListener name of the LifecycleEvent listener of the SourceFilter
+const SourceListener = "source_filter_listener"
+
+// SourceFilter removes the results of disabled sources on ingestion and collapses the findings of overlapping sources,
+// a policy and resource pair reported by several sources of the precedence list is kept only for the source with the highest precedence.
+// When the highest source of a pair changes, the other reports of the pair are queued again, so the result is independent of the order
+// in which the reports were received and the findings of a lower source are restored when the higher source no longer reports them
+type SourceFilter struct {
+	disabled   map[string]bool
+	precedence map[string]int
+	mx         *sync.Mutex
+	// sources by policy and resource pair with the reports of each source
+	pairs map[string]map[string]map[string]bool
+	// pairs of each report
+	reports map[string][]string
+	// namespace/name keys of the indexed reports
+	keys    map[string]string
+	requeue func(key string)
+}
+
+// Requeue registers the function which queues a report again by its namespace/name key
+func (f *SourceFilter) Requeue(requeue func(key string)) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	f.requeue = requeue
+}
+
+// Map removes the results of disabled sources and of sources with a lower precedence from the PolicyReport and its summary
+func (f *SourceFilter) Map(r v1alpha2.ReportInterface) {
+	var summary *v1alpha2.PolicyReportSummary
+	var results *[]v1alpha2.PolicyReportResult
+
+	switch report := r.(type) {
+	case *v1alpha2.PolicyReport:
+		summary, results = &report.Summary, &report.Results
+	case *v1alpha2.ClusterPolicyReport:
+		summary, results = &report.Summary, &report.Results
+	default:
+		return
+	}
+
+	list := make([]v1alpha2.PolicyReportResult, 0, len(*results))
+	for _, result := range *results {
+		if !f.disabled[sourceOf(r, result)] {
+			list = append(list, result)
+			continue
+		}
+
+		decreaseSummary(summary, result.Result)
+	}
+
+	if len(f.precedence) > 0 {
+		f.index(r, list)
+
+		kept := make([]v1alpha2.PolicyReportResult, 0, len(list))
+		for _, result := range list {
+			if f.superseded(r, result) {
+				decreaseSummary(summary, result.Result)
+				continue
+			}
+
+			kept = append(kept, result)
+		}
+
+		list = kept
+	}
+
+	if len(list) != len(*results) {
+		*results = list
+	}
+}
+
+// Listen removes the pairs of deleted PolicyReports and queues the reports of the pairs they superseded again
+func (f *SourceFilter) Listen(event LifecycleEvent) {
+	if event.Type != Deleted || len(f.precedence) == 0 {
+		return
+	}
+
+	id := event.PolicyReport.GetID()
+
+	f.mx.Lock()
+	winners := f.winners(f.reports[id])
+	f.forget(id)
+	delete(f.keys, id)
+	keys, requeue := f.changed(winners, id), f.requeue
+	f.mx.Unlock()
+
+	enqueue(requeue, keys)
+}
+
+// index replaces the pairs reported by the PolicyReport with the pairs of its results
+// and queues the other reports of each pair whose highest source changed again
+func (f *SourceFilter) index(r v1alpha2.ReportInterface, results []v1alpha2.PolicyReportResult) {
+	id := r.GetID()
+
+	sources := make([]string, 0, len(results))
+	pairs := make([]string, 0, len(results))
+	for _, result := range results {
+		source := sourceOf(r, result)
+		if _, ok := f.precedence[source]; !ok {
+			continue
+		}
+
+		key := pairKey(r, result)
+		if key == "" {
+			continue
+		}
+
+		sources = append(sources, source)
+		pairs = append(pairs, key)
+	}
+
+	f.mx.Lock()
+
+	winners := f.winners(append(pairs, f.reports[id]...))
+	f.forget(id)
+
+	for i, key := range pairs {
+		source := sources[i]
+
+		if _, ok := f.pairs[key]; !ok {
+			f.pairs[key] = make(map[string]map[string]bool)
+		}
+		if _, ok := f.pairs[key][source]; !ok {
+			f.pairs[key][source] = make(map[string]bool)
+		}
+
+		f.pairs[key][source][id] = true
+		f.reports[id] = append(f.reports[id], key)
+	}
+
+	f.keys[id] = reportKey(r)
+	keys, requeue := f.changed(winners, id), f.requeue
+	f.mx.Unlock()
+
+	enqueue(requeue, keys)
+}
+
+// winner of the pair is the source with the highest precedence, empty if no source reports the pair
+func (f *SourceFilter) winner(key string) string {
+	winner := ""
+	for source := range f.pairs[key] {
+		if winner == "" || f.precedence[source] < f.precedence[winner] {
+			winner = source
+		}
+	}
+
+	return winner
+}
+
+func (f *SourceFilter) winners(pairs []string) map[string]string {
+	winners := make(map[string]string, len(pairs))
+	for _, key := range pairs {
+		winners[key] = f.winner(key)
+	}
+
+	return winners
+}
+
+// changed returns the sorted keys of the reports, except the given one, of each pair whose winner differs from the previous winner
+func (f *SourceFilter) changed(winners map[string]string, except string) []string {
+	set := make(map[string]bool)
+	for key, winner := range winners {
+		if f.winner(key) == winner {
+			continue
+		}
+
+		for _, reports := range f.pairs[key] {
+			for id := range reports {
+				if id != except {
+					set[f.keys[id]] = true
+				}
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func enqueue(requeue func(key string), keys []string) {
+	if requeue == nil {
+		return
+	}
+
+	for _, key := range keys {
+		requeue(key)
+	}
+}
+
+func (f *SourceFilter) forget(id string) {
+	for _, key := range f.reports[id] {
+		for source, reports := range f.pairs[key] {
+			delete(reports, id)
+			if len(reports) == 0 {
+				delete(f.pairs[key], source)
+			}
+		}
+		if len(f.pairs[key]) == 0 {
+			delete(f.pairs, key)
+		}
+	}
+
+	delete(f.reports, id)
+}
+
+// superseded reports if a source with a higher precedence reports the same policy and resource pair
+func (f *SourceFilter) superseded(r v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) bool {
+	rank, ok := f.precedence[sourceOf(r, result)]
+	if !ok {
+		return false
+	}
+
+	key := pairKey(r, result)
+	if key == "" {
+		return false
+	}
+
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	for source := range f.pairs[key] {
+		if f.precedence[source] < rank {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sourceOf the result in lower case, results without source use the source of the report
+func sourceOf(r v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) string {
+	if result.Source != "" {
+		return strings.ToLower(result.Source)
+	}
+
+	return strings.ToLower(r.GetSource())
+}
+
+// reportKey is the namespace/name key of the report, cluster reports are queued by name
+func reportKey(r v1alpha2.ReportInterface) string {
+	if r.GetNamespace() == "" {
+		return r.GetName()
+	}
+
+	return r.GetNamespace() + "/" + r.GetName()
+}
+
+// pairKey of the policy and resource of the result, the scope of the report is used for results without resource
+func pairKey(r v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) string {
+	resource := result.GetResource()
+	if resource == nil {
+		resource = r.GetScope()
+	}
+	if resource == nil {
+		return ""
+	}
+
+	if resource.UID != "" {
+		return result.Policy + "/" + string(resource.UID)
+	}
+
+	return strings.Join([]string{result.Policy, resource.Namespace, resource.Kind, resource.Name}, "/")
+}
+
+// NewSourceFilter creates a SourceFilter, precedence lists the overlapping sources from the highest to the lowest precedence
+func NewSourceFilter(disabled, precedence []string) *SourceFilter {
+	f := &SourceFilter{
+		disabled:   make(map[string]bool, len(disabled)),
+		precedence: make(map[string]int, len(precedence)),
+		mx:         new(sync.Mutex),
+		pairs:      make(map[string]map[string]map[string]bool),
+		reports:    make(map[string][]string),
+		keys:       make(map[string]string),
+	}
+
+	for _, source := range disabled {
+		f.disabled[strings.ToLower(source)] = true
+	}
+	for i, source := range precedence {
+		if _, ok := f.precedence[strings.ToLower(source)]; !ok {
+			f.precedence[strings.ToLower(source)] = i
+		}
+	}
+
+	return f
+}
@@ -0,0 +1,151 @@
+package report_test
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+func scannerReport(name, source string, results ...v1alpha2.PolicyReportResult) *v1alpha2.PolicyReport {
+	for i := range results {
+		results[i].Source = source
+	}
+
+	return &v1alpha2.PolicyReport{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Scope:      &corev1.ObjectReference{Namespace: "test", Kind: "Deployment", Name: "nginx", UID: "123"},
+		Summary:    report.Summarize(results),
+		Results:    results,
+	}
+}
+
+func Test_SourceFilter(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		filter := report.NewSourceFilter([]string{"Falco"}, nil)
+
+		rep := scannerReport("falco", "falco", v1alpha2.PolicyReportResult{Policy: "shell-in-container", Result: v1alpha2.StatusWarn})
+		rep.Results = append(rep.Results, v1alpha2.PolicyReportResult{Source: "kyverno", Policy: "require-labels", Result: v1alpha2.StatusFail})
+		rep.Summary.Fail = 1
+
+		filter.Map(rep)
+
+		if len(rep.Results) != 1 || rep.Results[0].Source != "kyverno" {
+			t.Fatalf("expected only the kyverno result, got %v", rep.Results)
+		}
+		if rep.Summary.Warn != 0 || rep.Summary.Fail != 1 {
+			t.Errorf("expected the summary without the falco result, got %+v", rep.Summary)
+		}
+	})
+
+	t.Run("Precedence", func(t *testing.T) {
+		filter := report.NewSourceFilter(nil, []string{"trivy", "grype"})
+
+		grype := scannerReport("grype", "grype",
+			v1alpha2.PolicyReportResult{Policy: "CVE-2023-1234", Result: v1alpha2.StatusFail},
+			v1alpha2.PolicyReportResult{Policy: "CVE-2023-5678", Result: v1alpha2.StatusFail},
+		)
+		trivy := scannerReport("trivy", "Trivy", v1alpha2.PolicyReportResult{Policy: "CVE-2023-1234", Result: v1alpha2.StatusFail})
+
+		filter.Map(grype)
+		if len(grype.Results) != 2 {
+			t.Errorf("expected all grype results without overlapping source, got %d", len(grype.Results))
+		}
+
+		filter.Map(trivy)
+		if len(trivy.Results) != 1 {
+			t.Errorf("expected the trivy result to be kept, got %d", len(trivy.Results))
+		}
+
+		grype = scannerReport("grype", "grype",
+			v1alpha2.PolicyReportResult{Policy: "CVE-2023-1234", Result: v1alpha2.StatusFail},
+			v1alpha2.PolicyReportResult{Policy: "CVE-2023-5678", Result: v1alpha2.StatusFail},
+		)
+		filter.Map(grype)
+		if len(grype.Results) != 1 || grype.Results[0].Policy != "CVE-2023-5678" {
+			t.Errorf("expected the finding reported by trivy to be collapsed, got %v", grype.Results)
+		}
+		if grype.Summary.Fail != 1 {
+			t.Errorf("expected the summary without the collapsed finding, got %+v", grype.Summary)
+		}
+
+		filter.Listen(report.LifecycleEvent{Type: report.Deleted, PolicyReport: trivy})
+
+		grype = scannerReport("grype", "grype", v1alpha2.PolicyReportResult{Policy: "CVE-2023-1234", Result: v1alpha2.StatusFail})
+		filter.Map(grype)
+		if len(grype.Results) != 1 {
+			t.Errorf("expected the grype finding after the trivy report was deleted, got %d", len(grype.Results))
+		}
+	})
+	t.Run("Requeue", func(t *testing.T) {
+		filter := report.NewSourceFilter(nil, []string{"trivy", "grype"})
+
+		queued := make([]string, 0)
+		filter.Requeue(func(key string) { queued = append(queued, key) })
+
+		grype := scannerReport("grype", "grype", v1alpha2.PolicyReportResult{Policy: "CVE-2023-1234", Result: v1alpha2.StatusFail})
+		filter.Map(grype)
+		if len(queued) != 0 {
+			t.Fatalf("expected no requeue without overlapping source, got %v", queued)
+		}
+
+		trivy := scannerReport("trivy", "trivy", v1alpha2.PolicyReportResult{Policy: "CVE-2023-1234", Result: v1alpha2.StatusFail})
+		filter.Map(trivy)
+		if len(queued) != 1 || queued[0] != "test/grype" {
+			t.Fatalf("expected the grype report to be queued again, got %v", queued)
+		}
+
+		grype = scannerReport("grype", "grype", v1alpha2.PolicyReportResult{Policy: "CVE-2023-1234", Result: v1alpha2.StatusFail})
+		filter.Map(grype)
+		if len(grype.Results) != 0 {
+			t.Errorf("expected the requeued grype finding to be collapsed, got %v", grype.Results)
+		}
+		if len(queued) != 1 {
+			t.Errorf("expected no requeue if the winner is unchanged, got %v", queued)
+		}
+
+		filter.Listen(report.LifecycleEvent{Type: report.Deleted, PolicyReport: trivy})
+		if len(queued) != 2 || queued[1] != "test/grype" {
+			t.Fatalf("expected the grype report to be queued again after the trivy report was deleted, got %v", queued)
+		}
+
+		grype = scannerReport("grype", "grype", v1alpha2.PolicyReportResult{Policy: "CVE-2023-1234", Result: v1alpha2.StatusFail})
+		filter.Map(grype)
+		if len(grype.Results) != 1 {
+			t.Errorf("expected the grype finding to be restored, got %v", grype.Results)
+		}
+	})
+
+	t.Run("Order", func(t *testing.T) {
+		for _, order := range [][]string{{"trivy", "grype"}, {"grype", "trivy"}} {
+			filter := report.NewSourceFilter(nil, []string{"trivy", "grype"})
+
+			reports := make(map[string]*v1alpha2.PolicyReport)
+			fetch := func(name string) *v1alpha2.PolicyReport {
+				return scannerReport(name, name, v1alpha2.PolicyReportResult{Policy: "CVE-2023-1234", Result: v1alpha2.StatusFail})
+			}
+
+			queued := make([]string, 0)
+			filter.Requeue(func(key string) { queued = append(queued, key) })
+
+			for _, name := range order {
+				reports[name] = fetch(name)
+				filter.Map(reports[name])
+			}
+			for len(queued) > 0 {
+				name := queued[0][len("test/"):]
+				queued = queued[1:]
+
+				reports[name] = fetch(name)
+				filter.Map(reports[name])
+			}
+
+			if len(reports["trivy"].Results) != 1 || len(reports["grype"].Results) != 0 {
+				t.Errorf("expected only the trivy finding for order %v, got trivy %v and grype %v", order, reports["trivy"].Results, reports["grype"].Results)
+			}
+		}
+	})
+}
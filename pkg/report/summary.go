@@ -0,0 +1,66 @@
+package report
+
+import (
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// Summarize counts the results by their status
+func Summarize(results []v1alpha2.PolicyReportResult) v1alpha2.PolicyReportSummary {
+	summary := v1alpha2.PolicyReportSummary{}
+
+	for _, r := range results {
+		switch r.Result {
+		case v1alpha2.StatusPass:
+			summary.Pass++
+		case v1alpha2.StatusFail:
+			summary.Fail++
+		case v1alpha2.StatusWarn:
+			summary.Warn++
+		case v1alpha2.StatusError:
+			summary.Error++
+		case v1alpha2.StatusSkip:
+			summary.Skip++
+		}
+	}
+
+	return summary
+}
+
+// SummaryCorrector detects PolicyReports with a summary which does not match their results
+type SummaryCorrector struct {
+	recompute bool
+	drift     []func(v1alpha2.ReportInterface, v1alpha2.PolicyReportSummary)
+}
+
+// Map compares the summary of the PolicyReport with its results before it is published,
+// the drift callbacks are called with the recomputed summary and the summary is replaced if recompute is enabled
+func (c *SummaryCorrector) Map(r v1alpha2.ReportInterface) {
+	var summary *v1alpha2.PolicyReportSummary
+
+	switch report := r.(type) {
+	case *v1alpha2.PolicyReport:
+		summary = &report.Summary
+	case *v1alpha2.ClusterPolicyReport:
+		summary = &report.Summary
+	default:
+		return
+	}
+
+	expected := Summarize(r.GetResults())
+	if *summary == expected {
+		return
+	}
+
+	for _, drift := range c.drift {
+		drift(r, expected)
+	}
+
+	if c.recompute {
+		*summary = expected
+	}
+}
+
+// NewSummaryCorrector creates a SummaryCorrector, the drift callbacks are called for each PolicyReport with a wrong summary
+func NewSummaryCorrector(recompute bool, drift ...func(v1alpha2.ReportInterface, v1alpha2.PolicyReportSummary)) *SummaryCorrector {
+	return &SummaryCorrector{recompute: recompute, drift: drift}
+}
@@ -0,0 +1,65 @@
+package report_test
+
+import (
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+func drifted() *v1alpha2.PolicyReport {
+	return &v1alpha2.PolicyReport{
+		Summary: v1alpha2.PolicyReportSummary{Pass: 3},
+		Results: []v1alpha2.PolicyReportResult{
+			{Source: "kyverno", Policy: "require-labels", Result: v1alpha2.StatusPass},
+			{Source: "kyverno", Policy: "require-limits", Result: v1alpha2.StatusFail},
+			{Source: "kyverno", Policy: "require-requests", Result: v1alpha2.StatusSkip},
+		},
+	}
+}
+
+func Test_Summarize(t *testing.T) {
+	summary := report.Summarize(drifted().Results)
+	if summary != (v1alpha2.PolicyReportSummary{Pass: 1, Fail: 1, Skip: 1}) {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+}
+
+func Test_SummaryCorrector(t *testing.T) {
+	t.Run("Detect", func(t *testing.T) {
+		var drifts int
+
+		rep := drifted()
+		report.NewSummaryCorrector(false, func(_ v1alpha2.ReportInterface, summary v1alpha2.PolicyReportSummary) {
+			drifts++
+			if summary.Fail != 1 {
+				t.Errorf("expected the recomputed summary, got %+v", summary)
+			}
+		}).Map(rep)
+
+		if drifts != 1 {
+			t.Errorf("expected 1 drift, got %d", drifts)
+		}
+		if rep.Summary.Pass != 3 {
+			t.Errorf("expected the summary to be kept without recompute, got %+v", rep.Summary)
+		}
+	})
+
+	t.Run("Recompute", func(t *testing.T) {
+		rep := &v1alpha2.ClusterPolicyReport{Summary: v1alpha2.PolicyReportSummary{Pass: 3}, Results: drifted().Results}
+		report.NewSummaryCorrector(true).Map(rep)
+
+		if rep.Summary != (v1alpha2.PolicyReportSummary{Pass: 1, Fail: 1, Skip: 1}) {
+			t.Errorf("expected the recomputed summary, got %+v", rep.Summary)
+		}
+	})
+
+	t.Run("NoDrift", func(t *testing.T) {
+		rep := drifted()
+		rep.Summary = v1alpha2.PolicyReportSummary{Pass: 1, Fail: 1, Skip: 1}
+
+		report.NewSummaryCorrector(true, func(v1alpha2.ReportInterface, v1alpha2.PolicyReportSummary) {
+			t.Error("expected no drift for a matching summary")
+		}).Map(rep)
+	})
+}
@@ -0,0 +1,305 @@
+// Package rollup maintains a ClusterComplianceSummary resource with the counts and scores of all results of the cluster
+package rollup
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/compliance/v1alpha1"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/score"
+)
+
+var logger = logging.Component("rollup")
+
+// Listener is the name of the PolicyReport listener feeding the Controller
+const Listener = "compliance_summary_listener"
+
+// DefaultName of the ClusterComplianceSummary
+const DefaultName = "cluster"
+
+// GVR of the ClusterComplianceSummary resource
+var GVR = v1alpha1.SchemeGroupVersion.WithResource("clustercompliancesummaries")
+
+type key struct {
+	namespace string
+	source    string
+	category  string
+	severity  string
+	status    string
+}
+
+// Controller counts the results of all PolicyReports and writes the roll-up into a ClusterComplianceSummary
+type Controller struct {
+	client   dynamic.Interface
+	name     string
+	weights  score.Weights
+	interval time.Duration
+	leading  func() bool
+	now      func() time.Time
+	mx       *sync.Mutex
+	reports  map[string]map[key]int
+	dirty    bool
+	written  *v1alpha1.ComplianceSummary
+}
+
+// Listen is the PolicyReportListener of the Controller
+func (c *Controller) Listen(event report.LifecycleEvent) {
+	current := make(map[key]int)
+	if event.Type != report.Deleted {
+		for _, r := range event.PolicyReport.GetResults() {
+			source := r.Source
+			if source == "" {
+				source = event.PolicyReport.GetSource()
+			}
+
+			current[key{
+				namespace: event.PolicyReport.GetNamespace(),
+				source:    source,
+				category:  r.Category,
+				severity:  string(r.Severity),
+				status:    string(r.Result),
+			}]++
+		}
+	}
+
+	id := event.PolicyReport.GetID()
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if reflect.DeepEqual(c.reports[id], current) {
+		return
+	}
+
+	if len(current) == 0 {
+		delete(c.reports, id)
+	} else {
+		c.reports[id] = current
+	}
+	c.dirty = true
+}
+
+// Summary of the current results without timestamp, namespaces and sources are ordered by name
+func (c *Controller) Summary() v1alpha1.ComplianceSummary {
+	c.mx.Lock()
+	total := make(map[key]int)
+	for _, counts := range c.reports {
+		for k, count := range counts {
+			total[k] += count
+		}
+	}
+	c.mx.Unlock()
+
+	all := make([]score.Count, 0, len(total))
+	bySource := make(map[string][]score.Count)
+	results := v1alpha1.StatusCounts{}
+	namespaces := make(map[string]*v1alpha1.StatusCounts)
+	sources := make(map[string]*v1alpha1.StatusCounts)
+
+	for k, count := range total {
+		sc := score.Count{Namespace: k.namespace, Category: k.category, Severity: k.severity, Status: k.status, Count: count}
+		all = append(all, sc)
+		bySource[k.source] = append(bySource[k.source], sc)
+
+		add(&results, k.status, count)
+
+		if k.namespace != "" {
+			if _, ok := namespaces[k.namespace]; !ok {
+				namespaces[k.namespace] = &v1alpha1.StatusCounts{}
+			}
+			add(namespaces[k.namespace], k.status, count)
+		}
+
+		if _, ok := sources[k.source]; !ok {
+			sources[k.source] = &v1alpha1.StatusCounts{}
+		}
+		add(sources[k.source], k.status, count)
+	}
+
+	scores := score.Calculate(all, c.weights)
+
+	summary := v1alpha1.ComplianceSummary{
+		Score:      scores.Cluster.Score,
+		Results:    results,
+		Namespaces: make([]v1alpha1.NamedSummary, 0, len(namespaces)),
+		Sources:    make([]v1alpha1.NamedSummary, 0, len(sources)),
+	}
+
+	for _, s := range scores.Namespaces {
+		summary.Namespaces = append(summary.Namespaces, v1alpha1.NamedSummary{Name: s.Name, Score: s.Score, Results: *namespaces[s.Name]})
+	}
+
+	for name, counts := range sources {
+		summary.Sources = append(summary.Sources, v1alpha1.NamedSummary{
+			Name:    name,
+			Score:   score.Calculate(bySource[name], c.weights).Cluster.Score,
+			Results: *counts,
+		})
+	}
+
+	sort.Slice(summary.Sources, func(i, j int) bool { return summary.Sources[i].Name < summary.Sources[j].Name })
+
+	return summary
+}
+
+func add(counts *v1alpha1.StatusCounts, status string, count int) {
+	switch status {
+	case v1alpha2.StatusPass:
+		counts.Pass += count
+	case v1alpha2.StatusFail:
+		counts.Fail += count
+	case v1alpha2.StatusWarn:
+		counts.Warn += count
+	case v1alpha2.StatusError:
+		counts.Error += count
+	case v1alpha2.StatusSkip:
+		counts.Skip += count
+	}
+}
+
+// Sync writes the current summary into the ClusterComplianceSummary if it changed since the last write,
+// the resource is created if it does not exist
+func (c *Controller) Sync(ctx context.Context) error {
+	c.mx.Lock()
+	c.dirty = false
+	c.mx.Unlock()
+
+	summary := c.Summary()
+	if c.written != nil && reflect.DeepEqual(*c.written, summary) {
+		return nil
+	}
+
+	if err := c.write(ctx, summary); err != nil {
+		c.mx.Lock()
+		c.dirty = true
+		c.mx.Unlock()
+
+		return err
+	}
+
+	c.written = &summary
+
+	return nil
+}
+
+func (c *Controller) write(ctx context.Context, summary v1alpha1.ComplianceSummary) error {
+	obj, err := c.client.Resource(GVR).Get(ctx, c.name, metav1.GetOptions{})
+	notFound := errors.IsNotFound(err)
+	if err != nil && !notFound {
+		return err
+	}
+
+	// a resource written by a previous leader is kept if the counts did not change
+	if !notFound && unchanged(obj, summary) {
+		return nil
+	}
+
+	summary.LastUpdated = metav1.NewTime(c.now().UTC())
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&summary)
+	if err != nil {
+		return err
+	}
+
+	if notFound {
+		obj = &unstructured.Unstructured{}
+		obj.SetAPIVersion(v1alpha1.SchemeGroupVersion.String())
+		obj.SetKind("ClusterComplianceSummary")
+		obj.SetName(c.name)
+		obj.Object["summary"] = content
+
+		_, err = c.client.Resource(GVR).Create(ctx, obj, metav1.CreateOptions{})
+		return err
+	}
+
+	obj.Object["summary"] = content
+	_, err = c.client.Resource(GVR).Update(ctx, obj, metav1.UpdateOptions{})
+
+	return err
+}
+
+// unchanged reports if the written summary of the resource equals the summary, ignoring the timestamp
+func unchanged(obj *unstructured.Unstructured, summary v1alpha1.ComplianceSummary) bool {
+	content, ok := obj.Object["summary"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	written := v1alpha1.ComplianceSummary{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, &written); err != nil {
+		return false
+	}
+
+	written.LastUpdated = summary.LastUpdated
+	if len(written.Namespaces) == 0 && len(summary.Namespaces) == 0 {
+		written.Namespaces = summary.Namespaces
+	}
+	if len(written.Sources) == 0 && len(summary.Sources) == 0 {
+		written.Sources = summary.Sources
+	}
+
+	return reflect.DeepEqual(written, summary)
+}
+
+// Run syncs the ClusterComplianceSummary each interval after a change until the context is canceled.
+// With a leading check only the leader writes, the results are counted on all replicas to take over at once
+func (c *Controller) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if c.leading != nil && !c.leading() {
+				// the current leader maintains the resource, it is compared again after a takeover
+				c.written = nil
+				continue
+			}
+
+			c.mx.Lock()
+			dirty := c.dirty || (c.written == nil && len(c.reports) > 0)
+			c.mx.Unlock()
+
+			if !dirty {
+				continue
+			}
+
+			if err := c.Sync(ctx); err != nil {
+				logger.Errorf("failed to update ClusterComplianceSummary %s: %s", c.name, err)
+			}
+		}
+	}
+}
+
+// NewController creates a Controller for the ClusterComplianceSummary with the given name, defaults to DefaultName.
+// With leading the resource is only written while it returns true, nil always writes
+func NewController(client dynamic.Interface, name string, weights score.Weights, interval time.Duration, leading func() bool) *Controller {
+	if name == "" {
+		name = DefaultName
+	}
+
+	return &Controller{
+		client:   client,
+		name:     name,
+		weights:  weights,
+		interval: interval,
+		leading:  leading,
+		now:      time.Now,
+		mx:       new(sync.Mutex),
+		reports:  make(map[string]map[key]int),
+	}
+}
@@ -0,0 +1,195 @@
+package rollup_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/compliance/v1alpha1"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/rollup"
+)
+
+func newReport(name, namespace string, results ...v1alpha2.PolicyReportResult) *v1alpha2.PolicyReport {
+	return &v1alpha2.PolicyReport{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Results:    results,
+	}
+}
+
+func newResult(source string, status v1alpha2.PolicyResult, severity v1alpha2.PolicySeverity) v1alpha2.PolicyReportResult {
+	return v1alpha2.PolicyReportResult{Policy: "policy", Source: source, Result: status, Severity: severity}
+}
+
+func newClient() *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		rollup.GVR: "ClusterComplianceSummaryList",
+	})
+}
+
+func Test_Summary(t *testing.T) {
+	controller := rollup.NewController(newClient(), "", nil, 0, nil)
+
+	controller.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: newReport("kyverno", "test",
+		newResult("kyverno", v1alpha2.StatusPass, v1alpha2.SeverityHigh),
+		newResult("kyverno", v1alpha2.StatusFail, v1alpha2.SeverityLow),
+		newResult("trivy", v1alpha2.StatusSkip, v1alpha2.SeverityHigh),
+	)})
+	controller.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: newReport("trivy", "",
+		newResult("trivy", v1alpha2.StatusWarn, v1alpha2.SeverityHigh),
+	)})
+
+	summary := controller.Summary()
+	if summary.Results != (v1alpha1.StatusCounts{Pass: 1, Fail: 1, Warn: 1, Skip: 1}) {
+		t.Errorf("unexpected cluster counts: %+v", summary.Results)
+	}
+	if summary.Score != 44.44 {
+		t.Errorf("expected cluster score 44.44, got %v", summary.Score)
+	}
+	if len(summary.Namespaces) != 1 || summary.Namespaces[0].Name != "test" || summary.Namespaces[0].Score != 80 {
+		t.Errorf("unexpected namespaces: %+v", summary.Namespaces)
+	}
+	if len(summary.Sources) != 2 || summary.Sources[0].Name != "kyverno" || summary.Sources[1].Name != "trivy" {
+		t.Fatalf("unexpected sources: %+v", summary.Sources)
+	}
+	if summary.Sources[1].Score != 0 || summary.Sources[1].Results != (v1alpha1.StatusCounts{Warn: 1, Skip: 1}) {
+		t.Errorf("unexpected trivy summary: %+v", summary.Sources[1])
+	}
+
+	controller.Listen(report.LifecycleEvent{Type: report.Deleted, PolicyReport: newReport("trivy", "")})
+
+	summary = controller.Summary()
+	if summary.Results.Warn != 0 || summary.Sources[1].Results != (v1alpha1.StatusCounts{Skip: 1}) {
+		t.Errorf("expected results of the deleted report to be removed, got %+v", summary)
+	}
+}
+
+func Test_Sync(t *testing.T) {
+	ctx := context.Background()
+	client := newClient()
+	controller := rollup.NewController(client, "compliance", nil, 0, nil)
+
+	controller.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: newReport("kyverno", "test",
+		newResult("kyverno", v1alpha2.StatusFail, v1alpha2.SeverityHigh),
+	)})
+
+	if err := controller.Sync(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	obj, err := client.Resource(rollup.GVR).Get(ctx, "compliance", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected created ClusterComplianceSummary: %s", err)
+	}
+
+	resource := &v1alpha1.ClusterComplianceSummary{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, resource); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if resource.Summary.Results.Fail != 1 || resource.Summary.LastUpdated.IsZero() {
+		t.Errorf("unexpected summary: %+v", resource.Summary)
+	}
+
+	client.ClearActions()
+	if err := controller.Sync(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(client.Actions()) != 0 {
+		t.Errorf("expected unchanged summary not to be written, got %d actions", len(client.Actions()))
+	}
+
+	controller.Listen(report.LifecycleEvent{Type: report.Updated, PolicyReport: newReport("kyverno", "test",
+		newResult("kyverno", v1alpha2.StatusPass, v1alpha2.SeverityHigh),
+	)})
+	if err := controller.Sync(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var updated bool
+	for _, action := range client.Actions() {
+		if u, ok := action.(k8stesting.UpdateAction); ok && u.GetResource() == rollup.GVR {
+			updated = true
+		}
+	}
+	if !updated {
+		t.Error("expected ClusterComplianceSummary to be updated")
+	}
+
+	obj, _ = client.Resource(rollup.GVR).Get(ctx, "compliance", metav1.GetOptions{})
+	runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, resource)
+	if resource.Summary.Results != (v1alpha1.StatusCounts{Pass: 1}) || resource.Summary.Score != 100 {
+		t.Errorf("unexpected updated summary: %+v", resource.Summary)
+	}
+}
+
+func Test_SyncKeepsUnchangedResource(t *testing.T) {
+	ctx := context.Background()
+	client := newClient()
+
+	event := report.LifecycleEvent{Type: report.Added, PolicyReport: newReport("kyverno", "test",
+		newResult("kyverno", v1alpha2.StatusFail, v1alpha2.SeverityHigh),
+	)}
+
+	previous := rollup.NewController(client, "", nil, 0, nil)
+	previous.Listen(event)
+	if err := previous.Sync(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	client.ClearActions()
+
+	controller := rollup.NewController(client, "", nil, 0, nil)
+	controller.Listen(event)
+	if err := controller.Sync(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, action := range client.Actions() {
+		if action.GetVerb() != "get" {
+			t.Errorf("expected unchanged resource not to be written, got %s", action.GetVerb())
+		}
+	}
+}
+
+func Test_RunOnlyAsLeader(t *testing.T) {
+	client := newClient()
+
+	leading := &atomic.Bool{}
+	controller := rollup.NewController(client, "", nil, 10*time.Millisecond, leading.Load)
+	controller.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: newReport("kyverno", "test",
+		newResult("kyverno", v1alpha2.StatusFail, v1alpha2.SeverityHigh),
+	)})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- controller.Run(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if len(client.Actions()) != 0 {
+		t.Fatalf("expected no requests without leadership, got %d", len(client.Actions()))
+	}
+
+	leading.Store(true)
+
+	for i := 0; i < 50; i++ {
+		if _, err := client.Resource(rollup.GVR).Get(context.Background(), rollup.DefaultName, metav1.GetOptions{}); err == nil {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Error("expected the leader to create the ClusterComplianceSummary")
+}
@@ -0,0 +1,148 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/kyverno/policy-reporter/pkg/api/auth"
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/rpc/pb"
+)
+
+var logger = logging.Component("rpc")
+
+// UnaryAuthenticator requires an identity with read access, authenticated by the Authenticator of the REST API
+func UnaryAuthenticator(authenticator auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, authenticator, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthenticator requires an identity with read access for streams, authenticated by the Authenticator of the REST API
+func StreamAuthenticator(authenticator auth.Authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(stream.Context(), authenticator, info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate maps the metadata of the call to the headers of an HTTP request, so the REST API Authenticators can validate the credentials
+func authenticate(ctx context.Context, authenticator auth.Authenticator, method string) (context.Context, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, method, nil)
+	if err != nil {
+		return ctx, status.Error(codes.Internal, err.Error())
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for key, values := range md {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	identity, err := authenticator.Authenticate(req)
+	if err != nil {
+		if !errors.Is(err, auth.ErrNoCredentials) {
+			logger.Warnf("failed to authenticate gRPC call of %s: %s", method, err)
+		}
+
+		return ctx, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	if identity.Level < auth.Read {
+		return ctx, status.Errorf(codes.PermissionDenied, "%s requires %s access", identity.Name, auth.Read)
+	}
+
+	return auth.WithIdentity(ctx, identity), nil
+}
+
+// scope restricts the filter to the namespaces the authenticated identity is allowed to view, like auth.Scoped for the REST API,
+// admins and calls without AccessReviewer are not restricted
+func (s *Server) scope(ctx context.Context, filter v1.Filter, scope pb.Scope) (v1.Filter, error) {
+	identity, ok := auth.IdentityFrom(ctx)
+	if s.access == nil || !ok || identity.Level == auth.Admin {
+		return filter, nil
+	}
+
+	if scope == pb.Scope_SCOPE_CLUSTER {
+		allowed, err := s.access.CanView(ctx, identity, "")
+		if err != nil {
+			return filter, status.Error(codes.Internal, err.Error())
+		}
+		if !allowed {
+			return filter, status.Errorf(codes.PermissionDenied, "%s is not allowed to view cluster scoped results", identity.Name)
+		}
+
+		return filter, nil
+	}
+
+	namespaces := filter.Namespaces
+	if len(namespaces) == 0 {
+		list, err := s.finder.FetchNamespaces(v1.Filter{})
+		if err != nil {
+			return filter, status.Error(codes.Internal, err.Error())
+		}
+
+		namespaces = list
+	}
+
+	accessible := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		allowed, err := s.access.CanView(ctx, identity, ns)
+		if err != nil {
+			return filter, status.Error(codes.Internal, err.Error())
+		}
+		if allowed {
+			accessible = append(accessible, ns)
+		}
+	}
+
+	if len(accessible) == 0 {
+		return filter, status.Errorf(codes.PermissionDenied, "%s is not allowed to view the results of any requested namespace", identity.Name)
+	}
+
+	filter.Namespaces = accessible
+
+	return filter, nil
+}
+
+// visible returns whether the watching identity may view the results of the namespace, an empty namespace checks cluster scoped results
+func (s *Server) visible(ctx context.Context, namespace string) bool {
+	identity, ok := auth.IdentityFrom(ctx)
+	if s.access == nil || !ok || identity.Level == auth.Admin {
+		return true
+	}
+
+	allowed, err := s.access.CanView(ctx, identity, namespace)
+	if err != nil {
+		logger.Errorf("failed to review the access of %s to namespace %s: %s", identity.Name, namespace, err)
+		return false
+	}
+
+	return allowed
+}
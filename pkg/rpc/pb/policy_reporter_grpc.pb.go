@@ -0,0 +1,256 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: policy_reporter.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	PolicyReporter_ListPolicyReports_FullMethodName = "/policyreporter.v1.PolicyReporter/ListPolicyReports"
+	PolicyReporter_ListResults_FullMethodName       = "/policyreporter.v1.PolicyReporter/ListResults"
+	PolicyReporter_GetStatusCounts_FullMethodName   = "/policyreporter.v1.PolicyReporter/GetStatusCounts"
+	PolicyReporter_WatchResults_FullMethodName      = "/policyreporter.v1.PolicyReporter/WatchResults"
+)
+
+// PolicyReporterClient is the client API for PolicyReporter service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PolicyReporterClient interface {
+	// ListPolicyReports returns a page of PolicyReports or ClusterPolicyReports
+	ListPolicyReports(ctx context.Context, in *ListPolicyReportsRequest, opts ...grpc.CallOption) (*ListPolicyReportsResponse, error)
+	// ListResults returns a page of namespaced or cluster scoped results
+	ListResults(ctx context.Context, in *ListResultsRequest, opts ...grpc.CallOption) (*ListResultsResponse, error)
+	// GetStatusCounts returns the result count per status and namespace
+	GetStatusCounts(ctx context.Context, in *StatusCountsRequest, opts ...grpc.CallOption) (*StatusCountsResponse, error)
+	// WatchResults streams new results as soon as they are reported
+	WatchResults(ctx context.Context, in *WatchResultsRequest, opts ...grpc.CallOption) (PolicyReporter_WatchResultsClient, error)
+}
+
+type policyReporterClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPolicyReporterClient(cc grpc.ClientConnInterface) PolicyReporterClient {
+	return &policyReporterClient{cc}
+}
+
+func (c *policyReporterClient) ListPolicyReports(ctx context.Context, in *ListPolicyReportsRequest, opts ...grpc.CallOption) (*ListPolicyReportsResponse, error) {
+	out := new(ListPolicyReportsResponse)
+	err := c.cc.Invoke(ctx, PolicyReporter_ListPolicyReports_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *policyReporterClient) ListResults(ctx context.Context, in *ListResultsRequest, opts ...grpc.CallOption) (*ListResultsResponse, error) {
+	out := new(ListResultsResponse)
+	err := c.cc.Invoke(ctx, PolicyReporter_ListResults_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *policyReporterClient) GetStatusCounts(ctx context.Context, in *StatusCountsRequest, opts ...grpc.CallOption) (*StatusCountsResponse, error) {
+	out := new(StatusCountsResponse)
+	err := c.cc.Invoke(ctx, PolicyReporter_GetStatusCounts_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *policyReporterClient) WatchResults(ctx context.Context, in *WatchResultsRequest, opts ...grpc.CallOption) (PolicyReporter_WatchResultsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PolicyReporter_ServiceDesc.Streams[0], PolicyReporter_WatchResults_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &policyReporterWatchResultsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PolicyReporter_WatchResultsClient interface {
+	Recv() (*ResultEvent, error)
+	grpc.ClientStream
+}
+
+type policyReporterWatchResultsClient struct {
+	grpc.ClientStream
+}
+
+func (x *policyReporterWatchResultsClient) Recv() (*ResultEvent, error) {
+	m := new(ResultEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// PolicyReporterServer is the server API for PolicyReporter service.
+// All implementations must embed UnimplementedPolicyReporterServer
+// for forward compatibility
+type PolicyReporterServer interface {
+	// ListPolicyReports returns a page of PolicyReports or ClusterPolicyReports
+	ListPolicyReports(context.Context, *ListPolicyReportsRequest) (*ListPolicyReportsResponse, error)
+	// ListResults returns a page of namespaced or cluster scoped results
+	ListResults(context.Context, *ListResultsRequest) (*ListResultsResponse, error)
+	// GetStatusCounts returns the result count per status and namespace
+	GetStatusCounts(context.Context, *StatusCountsRequest) (*StatusCountsResponse, error)
+	// WatchResults streams new results as soon as they are reported
+	WatchResults(*WatchResultsRequest, PolicyReporter_WatchResultsServer) error
+	mustEmbedUnimplementedPolicyReporterServer()
+}
+
+// UnimplementedPolicyReporterServer must be embedded to have forward compatible implementations.
+type UnimplementedPolicyReporterServer struct {
+}
+
+func (UnimplementedPolicyReporterServer) ListPolicyReports(context.Context, *ListPolicyReportsRequest) (*ListPolicyReportsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPolicyReports not implemented")
+}
+func (UnimplementedPolicyReporterServer) ListResults(context.Context, *ListResultsRequest) (*ListResultsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListResults not implemented")
+}
+func (UnimplementedPolicyReporterServer) GetStatusCounts(context.Context, *StatusCountsRequest) (*StatusCountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatusCounts not implemented")
+}
+func (UnimplementedPolicyReporterServer) WatchResults(*WatchResultsRequest, PolicyReporter_WatchResultsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchResults not implemented")
+}
+func (UnimplementedPolicyReporterServer) mustEmbedUnimplementedPolicyReporterServer() {}
+
+// UnsafePolicyReporterServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PolicyReporterServer will
+// result in compilation errors.
+type UnsafePolicyReporterServer interface {
+	mustEmbedUnimplementedPolicyReporterServer()
+}
+
+func RegisterPolicyReporterServer(s grpc.ServiceRegistrar, srv PolicyReporterServer) {
+	s.RegisterService(&PolicyReporter_ServiceDesc, srv)
+}
+
+func _PolicyReporter_ListPolicyReports_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPolicyReportsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolicyReporterServer).ListPolicyReports(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PolicyReporter_ListPolicyReports_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolicyReporterServer).ListPolicyReports(ctx, req.(*ListPolicyReportsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PolicyReporter_ListResults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListResultsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolicyReporterServer).ListResults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PolicyReporter_ListResults_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolicyReporterServer).ListResults(ctx, req.(*ListResultsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PolicyReporter_GetStatusCounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusCountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolicyReporterServer).GetStatusCounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PolicyReporter_GetStatusCounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolicyReporterServer).GetStatusCounts(ctx, req.(*StatusCountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PolicyReporter_WatchResults_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchResultsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PolicyReporterServer).WatchResults(m, &policyReporterWatchResultsServer{stream})
+}
+
+type PolicyReporter_WatchResultsServer interface {
+	Send(*ResultEvent) error
+	grpc.ServerStream
+}
+
+type policyReporterWatchResultsServer struct {
+	grpc.ServerStream
+}
+
+func (x *policyReporterWatchResultsServer) Send(m *ResultEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// PolicyReporter_ServiceDesc is the grpc.ServiceDesc for PolicyReporter service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PolicyReporter_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "policyreporter.v1.PolicyReporter",
+	HandlerType: (*PolicyReporterServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListPolicyReports",
+			Handler:    _PolicyReporter_ListPolicyReports_Handler,
+		},
+		{
+			MethodName: "ListResults",
+			Handler:    _PolicyReporter_ListResults_Handler,
+		},
+		{
+			MethodName: "GetStatusCounts",
+			Handler:    _PolicyReporter_GetStatusCounts_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchResults",
+			Handler:       _PolicyReporter_WatchResults_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "policy_reporter.proto",
+}
@@ -0,0 +1,446 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/api/auth"
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/query"
+	"github.com/kyverno/policy-reporter/pkg/rpc/pb"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+const (
+	// WatchListener is the name of the PolicyReport listener publishing new results to watching clients
+	WatchListener = "grpc_watch_listener"
+
+	defaultLimit = 50
+	maxLimit     = 1000
+	bufferSize   = 100
+)
+
+// Finder combines the v1 and v2 query APIs used by the gRPC server
+type Finder interface {
+	v1.PolicyReportFinder
+	v2.PolicyReportFinder
+}
+
+// Server for the gRPC API
+type Server struct {
+	pb.UnimplementedPolicyReporterServer
+
+	finder  Finder
+	address string
+	access  auth.AccessReviewer
+	grpc    *grpc.Server
+
+	mx          *sync.RWMutex
+	subscribers map[int]chan *pb.ResultEvent
+	nextID      int
+}
+
+// Start the gRPC Server
+func (s *Server) Start() error {
+	lis, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("gRPC server listen on %s", s.address)
+
+	return s.Serve(lis)
+}
+
+// Serve the gRPC API on the given listener
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpc.Serve(lis)
+}
+
+// Stop the gRPC Server, open streams are closed
+func (s *Server) Stop() {
+	s.grpc.GracefulStop()
+}
+
+// ListPolicyReports returns a page of PolicyReports or ClusterPolicyReports
+func (s *Server) ListPolicyReports(ctx context.Context, req *pb.ListPolicyReportsRequest) (*pb.ListPolicyReportsResponse, error) {
+	filter, err := mapFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err = s.scope(ctx, filter, req.GetScope())
+	if err != nil {
+		return nil, err
+	}
+
+	pagination := mapPagination(req.GetPagination())
+
+	var list []*v1.PolicyReport
+	var cursor *v2.Cursor
+	var count int
+
+	if req.GetScope() == pb.Scope_SCOPE_CLUSTER {
+		count, _ = s.finder.CountClusterPolicyReports(filter)
+		list, cursor, err = s.finder.FetchClusterPolicyReportPage(filter, pagination)
+	} else {
+		count, _ = s.finder.CountPolicyReports(filter)
+		list, cursor, err = s.finder.FetchPolicyReportPage(filter, pagination)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	items := make([]*pb.PolicyReport, 0, len(list))
+	for _, r := range list {
+		items = append(items, mapPolicyReport(r))
+	}
+
+	return &pb.ListPolicyReportsResponse{Items: items, TotalCount: int32(count), NextCursor: v2.EncodeCursor(cursor)}, nil
+}
+
+// ListResults returns a page of namespaced or cluster scoped results
+func (s *Server) ListResults(ctx context.Context, req *pb.ListResultsRequest) (*pb.ListResultsResponse, error) {
+	filter, err := mapFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err = s.scope(ctx, filter, req.GetScope())
+	if err != nil {
+		return nil, err
+	}
+
+	pagination := mapPagination(req.GetPagination())
+
+	var list []*v1.ListResult
+	var cursor *v2.Cursor
+	var count int
+
+	if req.GetScope() == pb.Scope_SCOPE_CLUSTER {
+		count, _ = s.finder.CountClusterResults(filter)
+		list, cursor, err = s.finder.FetchClusterResultPage(filter, pagination)
+	} else {
+		count, _ = s.finder.CountNamespacedResults(filter)
+		list, cursor, err = s.finder.FetchNamespacedResultPage(filter, pagination)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	items := make([]*pb.Result, 0, len(list))
+	for _, r := range list {
+		items = append(items, mapListResult(r))
+	}
+
+	return &pb.ListResultsResponse{Items: items, TotalCount: int32(count), NextCursor: v2.EncodeCursor(cursor)}, nil
+}
+
+// GetStatusCounts returns the result count per status and namespace
+func (s *Server) GetStatusCounts(ctx context.Context, req *pb.StatusCountsRequest) (*pb.StatusCountsResponse, error) {
+	filter, err := mapFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err = s.scope(ctx, filter, req.GetScope())
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*pb.StatusCount, 0)
+
+	if req.GetScope() == pb.Scope_SCOPE_CLUSTER {
+		list, err := s.finder.FetchStatusCounts(filter)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		for _, c := range list {
+			items = append(items, &pb.StatusCount{Status: c.Status, Count: int32(c.Count)})
+		}
+
+		return &pb.StatusCountsResponse{Items: items}, nil
+	}
+
+	list, err := s.finder.FetchNamespacedStatusCounts(filter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	for _, c := range list {
+		for _, n := range c.Items {
+			items = append(items, &pb.StatusCount{Status: c.Status, Namespace: n.Namespace, Count: int32(n.Count)})
+		}
+	}
+
+	return &pb.StatusCountsResponse{Items: items}, nil
+}
+
+// WatchResults streams new results matching the filter until the client disconnects
+func (s *Server) WatchResults(req *pb.WatchResultsRequest, stream pb.PolicyReporter_WatchResultsServer) error {
+	filter, err := mapFilter(req.GetFilter())
+	if err != nil {
+		return err
+	}
+
+	var node query.Node
+	if filter.Expression != "" {
+		node, _ = query.Parse(filter.Expression)
+	}
+
+	id, events := s.subscribe()
+	defer s.unsubscribe(id)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event := <-events:
+			if !matches(filter, node, event.GetResult()) || !s.visible(stream.Context(), event.GetResult().GetNamespace()) {
+				continue
+			}
+
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Publish a new result to all watching clients, clients which can not keep up miss the result
+func (s *Server) Publish(rep v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult, _ bool) {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	if len(s.subscribers) == 0 {
+		return
+	}
+
+	event := &pb.ResultEvent{
+		ReportId:   rep.GetID(),
+		ReportName: rep.GetName(),
+		Result:     mapResult(rep, r),
+	}
+
+	for id, events := range s.subscribers {
+		select {
+		case events <- event:
+		default:
+			logger.Warnf("gRPC watch %d is too slow, result %s dropped", id, r.GetID())
+		}
+	}
+}
+
+func (s *Server) subscribe() (int, chan *pb.ResultEvent) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.nextID++
+	events := make(chan *pb.ResultEvent, bufferSize)
+	s.subscribers[s.nextID] = events
+
+	return s.nextID, events
+}
+
+func (s *Server) unsubscribe(id int) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	delete(s.subscribers, id)
+}
+
+func mapFilter(filter *pb.Filter) (v1.Filter, error) {
+	f := v1.Filter{
+		Namespaces:  filter.GetNamespaces(),
+		Kinds:       filter.GetKinds(),
+		Resources:   filter.GetResources(),
+		Sources:     filter.GetSources(),
+		Categories:  filter.GetCategories(),
+		Severities:  filter.GetSeverities(),
+		Policies:    filter.GetPolicies(),
+		Rules:       filter.GetRules(),
+		Status:      filter.GetStatus(),
+		ReportLabel: filter.GetReportLabels(),
+		Search:      filter.GetSearch(),
+		Expression:  filter.GetExpression(),
+	}
+
+	if f.ReportLabel == nil {
+		f.ReportLabel = map[string]string{}
+	}
+
+	if f.Expression != "" {
+		if _, err := query.Parse(f.Expression); err != nil {
+			return f, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	return f, nil
+}
+
+func mapPagination(pagination *pb.Pagination) v2.Pagination {
+	limit := int(pagination.GetLimit())
+	if limit < 1 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	direction := "ASC"
+	if strings.ToLower(pagination.GetDirection()) == "desc" {
+		direction = "DESC"
+	}
+
+	return v2.Pagination{
+		Limit:     limit,
+		SortBy:    pagination.GetSortBy(),
+		Direction: direction,
+		Cursor:    v2.DecodeCursor(pagination.GetCursor()),
+	}
+}
+
+func mapPolicyReport(r *v1.PolicyReport) *pb.PolicyReport {
+	return &pb.PolicyReport{
+		Id:        r.ID,
+		Name:      r.Name,
+		Namespace: r.Namespace,
+		Source:    r.Source,
+		Labels:    r.Labels,
+		Summary: &pb.Summary{
+			Pass:  int32(r.Pass),
+			Skip:  int32(r.Skip),
+			Warn:  int32(r.Warn),
+			Error: int32(r.Error),
+			Fail:  int32(r.Fail),
+		},
+	}
+}
+
+func mapListResult(r *v1.ListResult) *pb.Result {
+	return &pb.Result{
+		Id:         r.ID,
+		Namespace:  r.Namespace,
+		Kind:       r.Kind,
+		ApiVersion: r.APIVersion,
+		Name:       r.Name,
+		Message:    r.Message,
+		Category:   r.Category,
+		Policy:     r.Policy,
+		Rule:       r.Rule,
+		Status:     r.Status,
+		Severity:   r.Severity,
+		Timestamp:  int64(r.Timestamp),
+		Properties: r.Properties,
+	}
+}
+
+func mapResult(rep v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult) *pb.Result {
+	res := r.GetResource()
+	if res == nil && rep.GetScope() != nil {
+		res = rep.GetScope()
+	} else if res == nil {
+		res = &corev1.ObjectReference{}
+	}
+
+	return &pb.Result{
+		Id:         r.GetID(),
+		Namespace:  rep.GetNamespace(),
+		Kind:       res.Kind,
+		ApiVersion: res.APIVersion,
+		Name:       res.Name,
+		Message:    r.Message,
+		Category:   r.Category,
+		Policy:     r.Policy,
+		Rule:       r.Rule,
+		Status:     string(r.Result),
+		Severity:   string(r.Severity),
+		Source:     r.Source,
+		Timestamp:  r.Timestamp.Seconds,
+		Properties: r.Properties,
+	}
+}
+
+func matches(filter v1.Filter, node query.Node, r *pb.Result) bool {
+	checks := []struct {
+		values []string
+		value  string
+	}{
+		{filter.Namespaces, r.GetNamespace()},
+		{filter.Kinds, r.GetKind()},
+		{filter.Resources, r.GetName()},
+		{filter.Sources, r.GetSource()},
+		{filter.Categories, r.GetCategory()},
+		{filter.Severities, r.GetSeverity()},
+		{filter.Policies, r.GetPolicy()},
+		{filter.Rules, r.GetRule()},
+		{filter.Status, r.GetStatus()},
+	}
+
+	for _, check := range checks {
+		if len(check.values) > 0 && !validate.MatchAny(check.values, check.value) {
+			return false
+		}
+	}
+
+	if node == nil {
+		return true
+	}
+
+	return query.Match(node, func(field string) string {
+		switch field {
+		case "namespace":
+			return r.GetNamespace()
+		case "kind":
+			return r.GetKind()
+		case "name":
+			return r.GetName()
+		case "policy":
+			return r.GetPolicy()
+		case "rule":
+			return r.GetRule()
+		case "severity":
+			return r.GetSeverity()
+		case "status":
+			return r.GetStatus()
+		case "category":
+			return r.GetCategory()
+		case "source":
+			return r.GetSource()
+		case "message":
+			return r.GetMessage()
+		case "timestamp":
+			return strconv.FormatInt(r.GetTimestamp(), 10)
+		}
+
+		return ""
+	})
+}
+
+// NewServer creates a new gRPC Server listening on the address, calls authenticated by the UnaryAuthenticator and
+// StreamAuthenticator options are restricted to the namespaces allowed by the AccessReviewer, nil disables the restriction
+func NewServer(finder Finder, address string, access auth.AccessReviewer, options ...grpc.ServerOption) *Server {
+	s := &Server{
+		finder:      finder,
+		address:     address,
+		access:      access,
+		grpc:        grpc.NewServer(options...),
+		mx:          new(sync.RWMutex),
+		subscribers: make(map[int]chan *pb.ResultEvent),
+	}
+
+	pb.RegisterPolicyReporterServer(s.grpc, s)
+
+	return s
+}
@@ -0,0 +1,251 @@
+package rpc_test
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/api/auth"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/rpc"
+	"github.com/kyverno/policy-reporter/pkg/rpc/pb"
+	"github.com/kyverno/policy-reporter/pkg/secret"
+	"github.com/kyverno/policy-reporter/pkg/sqlite3"
+)
+
+var preport = &v1alpha2.PolicyReport{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:              "polr-test",
+		Namespace:         "test",
+		CreationTimestamp: metav1.Now(),
+	},
+	Results: []v1alpha2.PolicyReportResult{fixtures.FailResult, fixtures.PassPodResult},
+	Summary: v1alpha2.PolicyReportSummary{Fail: 1, Pass: 1},
+}
+
+func Test_GRPC_API(t *testing.T) {
+	db, err := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store, err := sqlite3.NewPolicyReportStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Add(preport)
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := rpc.NewServer(store, "", nil)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.Dial("bufnet", grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	}), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	client := pb.NewPolicyReporterClient(conn)
+	ctx := context.Background()
+
+	t.Run("ListResults", func(t *testing.T) {
+		resp, err := client.ListResults(ctx, &pb.ListResultsRequest{
+			Filter:     &pb.Filter{Namespaces: []string{"test"}},
+			Pagination: &pb.Pagination{Limit: 1, SortBy: "severity", Direction: "desc"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if resp.GetTotalCount() != 2 {
+			t.Errorf("expected total count of 2, got %d", resp.GetTotalCount())
+		}
+		if len(resp.GetItems()) != 1 || resp.GetItems()[0].GetSeverity() != v1alpha2.SeverityHigh {
+			t.Errorf("expected the high severity result first, got %v", resp.GetItems())
+		}
+		if resp.GetNextCursor() == "" {
+			t.Fatal("expected next cursor")
+		}
+
+		next, err := client.ListResults(ctx, &pb.ListResultsRequest{
+			Filter:     &pb.Filter{Namespaces: []string{"test"}},
+			Pagination: &pb.Pagination{Limit: 1, SortBy: "severity", Direction: "desc", Cursor: resp.GetNextCursor()},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if len(next.GetItems()) != 1 || next.GetItems()[0].GetStatus() != v1alpha2.StatusPass {
+			t.Errorf("expected the pass result on the second page, got %v", next.GetItems())
+		}
+		if next.GetNextCursor() != "" {
+			t.Error("expected no next cursor on the last page")
+		}
+	})
+
+	t.Run("ListResults with invalid expression", func(t *testing.T) {
+		_, err := client.ListResults(ctx, &pb.ListResultsRequest{Filter: &pb.Filter{Expression: "severity>=unknown"}})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("expected InvalidArgument, got %v", err)
+		}
+	})
+
+	t.Run("ListPolicyReports", func(t *testing.T) {
+		resp, err := client.ListPolicyReports(ctx, &pb.ListPolicyReportsRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if len(resp.GetItems()) != 1 || resp.GetItems()[0].GetSummary().GetFail() != 1 {
+			t.Errorf("expected one PolicyReport with one failed result, got %v", resp.GetItems())
+		}
+	})
+
+	t.Run("GetStatusCounts", func(t *testing.T) {
+		resp, err := client.GetStatusCounts(ctx, &pb.StatusCountsRequest{Filter: &pb.Filter{Status: []string{v1alpha2.StatusFail}}})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if len(resp.GetItems()) != 1 || resp.GetItems()[0].GetNamespace() != "test" || resp.GetItems()[0].GetCount() != 1 {
+			t.Errorf("expected one failed result in namespace test, got %v", resp.GetItems())
+		}
+	})
+
+	t.Run("WatchResults", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		stream, err := client.WatchResults(ctx, &pb.WatchResultsRequest{Filter: &pb.Filter{Expression: "severity>=medium"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		go func() {
+			// publish until the subscription is registered
+			for ctx.Err() == nil {
+				server.Publish(preport, fixtures.PassPodResult, false)
+				server.Publish(preport, fixtures.FailResult, false)
+				time.Sleep(50 * time.Millisecond)
+			}
+		}()
+
+		event, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if event.GetReportName() != "polr-test" {
+			t.Errorf("expected report name polr-test, got %s", event.GetReportName())
+		}
+		if event.GetResult().GetSeverity() != v1alpha2.SeverityHigh {
+			t.Errorf("expected only results with severity >= medium, got %v", event.GetResult())
+		}
+	})
+}
+
+type accessReviewer map[string]bool
+
+func (r accessReviewer) CanView(_ context.Context, _ auth.Identity, namespace string) (bool, error) {
+	return r[namespace], nil
+}
+
+func Test_GRPC_Auth(t *testing.T) {
+	db, err := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store, err := sqlite3.NewPolicyReportStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Add(preport)
+
+	authenticator := auth.NewTokenAuthenticator([]auth.Token{
+		{Name: "reader", Token: secret.New("read-token"), Level: auth.Read},
+		{Name: "admin", Token: secret.New("admin-token"), Level: auth.Admin},
+	})
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := rpc.NewServer(store, "", accessReviewer{"other": true}, grpc.UnaryInterceptor(rpc.UnaryAuthenticator(authenticator)), grpc.StreamInterceptor(rpc.StreamAuthenticator(authenticator)))
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.Dial("bufnet", grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	}), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	client := pb.NewPolicyReporterClient(conn)
+	withToken := func(token string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+	}
+
+	t.Run("Without credentials", func(t *testing.T) {
+		_, err := client.ListResults(context.Background(), &pb.ListResultsRequest{})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Errorf("expected Unauthenticated, got %v", err)
+		}
+
+		stream, err := client.WatchResults(context.Background(), &pb.WatchResultsRequest{})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if status.Code(err) != codes.Unauthenticated {
+			t.Errorf("expected Unauthenticated for the stream, got %v", err)
+		}
+	})
+
+	t.Run("Denied namespace", func(t *testing.T) {
+		_, err := client.ListResults(withToken("read-token"), &pb.ListResultsRequest{Filter: &pb.Filter{Namespaces: []string{"test"}}})
+		if status.Code(err) != codes.PermissionDenied {
+			t.Errorf("expected PermissionDenied, got %v", err)
+		}
+	})
+
+	t.Run("Denied cluster scope", func(t *testing.T) {
+		_, err := client.ListPolicyReports(withToken("read-token"), &pb.ListPolicyReportsRequest{Scope: pb.Scope_SCOPE_CLUSTER})
+		if status.Code(err) != codes.PermissionDenied {
+			t.Errorf("expected PermissionDenied, got %v", err)
+		}
+	})
+
+	t.Run("Scoped to the accessible namespaces", func(t *testing.T) {
+		resp, err := client.ListResults(withToken("read-token"), &pb.ListResultsRequest{Filter: &pb.Filter{Namespaces: []string{"test", "other"}}})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if resp.GetTotalCount() != 0 {
+			t.Errorf("expected no results of the denied namespace test, got %d", resp.GetTotalCount())
+		}
+	})
+
+	t.Run("Admin", func(t *testing.T) {
+		resp, err := client.ListResults(withToken("admin-token"), &pb.ListResultsRequest{Filter: &pb.Filter{Namespaces: []string{"test"}}})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if resp.GetTotalCount() != 2 {
+			t.Errorf("expected total count of 2, got %d", resp.GetTotalCount())
+		}
+	})
+}
@@ -0,0 +1,240 @@
+// Package sarif maps PolicyReportResults to the Static Analysis Results Interchange Format 2.1.0,
+// each source is a run and each policy a rule of its tool
+package sarif
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+const (
+	Version = "2.1.0"
+	Schema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	// FingerprintKey of the result ID, used by consumers to track results across uploads
+	FingerprintKey = "policyReporterResultID/v1"
+)
+
+type Log struct {
+	Schema  string `json:"$schema,omitempty"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+type Driver struct {
+	Name           string `json:"name"`
+	InformationURI string `json:"informationUri,omitempty"`
+	Rules          []Rule `json:"rules,omitempty"`
+}
+
+type Message struct {
+	Text string `json:"text"`
+}
+
+type Rule struct {
+	ID                   string          `json:"id"`
+	Name                 string          `json:"name,omitempty"`
+	ShortDescription     *Message        `json:"shortDescription,omitempty"`
+	DefaultConfiguration *Configuration  `json:"defaultConfiguration,omitempty"`
+	Properties           *RuleProperties `json:"properties,omitempty"`
+}
+
+type Configuration struct {
+	Level string `json:"level,omitempty"`
+}
+
+type RuleProperties struct {
+	Tags []string `json:"tags,omitempty"`
+	// SecuritySeverity is a score between 0.0 and 10.0, used by GitHub code scanning to rank security alerts
+	SecuritySeverity string `json:"security-severity,omitempty"`
+}
+
+type Result struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Kind                string            `json:"kind,omitempty"`
+	Level               string            `json:"level,omitempty"`
+	Message             Message           `json:"message"`
+	Locations           []Location        `json:"locations,omitempty"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+	Properties          map[string]string `json:"properties,omitempty"`
+}
+
+type Location struct {
+	PhysicalLocation *PhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []LogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           *Region          `json:"region,omitempty"`
+}
+
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type Region struct {
+	StartLine int `json:"startLine"`
+}
+
+type LogicalLocation struct {
+	Name               string `json:"name,omitempty"`
+	FullyQualifiedName string `json:"fullyQualifiedName,omitempty"`
+	Kind               string `json:"kind,omitempty"`
+}
+
+// NewLog groups the results by source into runs, the policies of a source are the rules of the run
+func NewLog(results []v1alpha2.PolicyReportResult) Log {
+	runs := make(map[string]*Run)
+	rules := make(map[string]map[string]int)
+	sources := make([]string, 0)
+
+	for _, r := range results {
+		run, ok := runs[r.Source]
+		if !ok {
+			run = &Run{Tool: Tool{Driver: Driver{Name: r.Source}}, Results: make([]Result, 0)}
+			runs[r.Source] = run
+			rules[r.Source] = make(map[string]int)
+			sources = append(sources, r.Source)
+		}
+
+		index, ok := rules[r.Source][r.Policy]
+		if !ok {
+			index = len(run.Tool.Driver.Rules)
+			rules[r.Source][r.Policy] = index
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, newRule(r))
+		}
+
+		run.Results = append(run.Results, newResult(r, index))
+	}
+
+	sort.Strings(sources)
+
+	log := Log{Schema: Schema, Version: Version, Runs: make([]Run, 0, len(sources))}
+	for _, source := range sources {
+		run := runs[source]
+		if run.Tool.Driver.Name == "" {
+			run.Tool.Driver.Name = "Policy Reporter"
+		}
+
+		log.Runs = append(log.Runs, *run)
+	}
+
+	return log
+}
+
+func newRule(r v1alpha2.PolicyReportResult) Rule {
+	rule := Rule{
+		ID:                   r.Policy,
+		Name:                 r.Policy,
+		DefaultConfiguration: &Configuration{Level: Level(r.Result)},
+	}
+
+	properties := &RuleProperties{SecuritySeverity: SecuritySeverity(r.Severity)}
+	if r.Category != "" {
+		properties.Tags = []string{r.Category}
+	}
+	if properties.SecuritySeverity != "" || len(properties.Tags) > 0 {
+		rule.Properties = properties
+	}
+
+	return rule
+}
+
+func newResult(r v1alpha2.PolicyReportResult, index int) Result {
+	result := Result{
+		RuleID:              r.Policy,
+		RuleIndex:           index,
+		Level:               Level(r.Result),
+		Message:             Message{Text: r.Message},
+		PartialFingerprints: map[string]string{FingerprintKey: r.GetID()},
+		Properties:          map[string]string{"status": string(r.Result)},
+	}
+
+	switch r.Result {
+	case v1alpha2.StatusPass:
+		result.Kind = "pass"
+	case v1alpha2.StatusSkip:
+		result.Kind = "notApplicable"
+	default:
+		result.Kind = "fail"
+	}
+
+	if result.Message.Text == "" {
+		result.Message.Text = strings.Trim(r.Policy+"/"+r.Rule, "/")
+	}
+
+	if r.Rule != "" {
+		result.Properties["rule"] = r.Rule
+	}
+	if r.Severity != "" {
+		result.Properties["severity"] = string(r.Severity)
+	}
+	for key, value := range r.Properties {
+		if _, ok := result.Properties[key]; !ok {
+			result.Properties[key] = value
+		}
+	}
+
+	if location, ok := r.Properties["location"]; ok && location != "" {
+		physical := &PhysicalLocation{ArtifactLocation: ArtifactLocation{URI: location}}
+		if line, err := strconv.Atoi(r.Properties["line"]); err == nil && line > 0 {
+			physical.Region = &Region{StartLine: line}
+		}
+
+		result.Locations = append(result.Locations, Location{PhysicalLocation: physical})
+	}
+
+	if res := r.GetResource(); res != nil {
+		name := strings.Trim(strings.Join([]string{res.Namespace, res.Kind, res.Name}, "/"), "/")
+
+		result.Locations = append(result.Locations, Location{
+			PhysicalLocation: &PhysicalLocation{ArtifactLocation: ArtifactLocation{URI: name}},
+			LogicalLocations: []LogicalLocation{{Name: res.Name, FullyQualifiedName: name, Kind: "resource"}},
+		})
+	}
+
+	return result
+}
+
+// Level of the result status, results with pass or skip status have no level
+func Level(status v1alpha2.PolicyResult) string {
+	switch status {
+	case v1alpha2.StatusFail, v1alpha2.StatusError:
+		return "error"
+	case v1alpha2.StatusWarn:
+		return "warning"
+	default:
+		return "none"
+	}
+}
+
+// SecuritySeverity score of the result severity
+func SecuritySeverity(severity v1alpha2.PolicySeverity) string {
+	switch severity {
+	case v1alpha2.SeverityCritical:
+		return "9.5"
+	case v1alpha2.SeverityHigh:
+		return "8.0"
+	case v1alpha2.SeverityMedium:
+		return "5.5"
+	case v1alpha2.SeverityLow:
+		return "2.0"
+	case v1alpha2.SeverityInfo:
+		return "0.0"
+	default:
+		return ""
+	}
+}
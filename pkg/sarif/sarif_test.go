@@ -0,0 +1,50 @@
+package sarif_test
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/sarif"
+)
+
+func Test_NewLog(t *testing.T) {
+	resource := []corev1.ObjectReference{{APIVersion: "v1", Kind: "Pod", Name: "nginx", Namespace: "test"}}
+
+	log := sarif.NewLog([]v1alpha2.PolicyReportResult{
+		{Source: "Kyverno", Policy: "require-limits", Rule: "validate", Result: v1alpha2.StatusFail, Severity: v1alpha2.SeverityHigh, Category: "Best Practices", Resources: resource},
+		{Source: "Kyverno", Policy: "require-limits", Rule: "validate", Result: v1alpha2.StatusPass, Resources: resource},
+		{Source: "Trivy", Policy: "CVE-2022-1234", Result: v1alpha2.StatusWarn, Properties: map[string]string{"location": "go.sum", "line": "4"}},
+	})
+
+	if log.Version != sarif.Version || len(log.Runs) != 2 {
+		t.Fatalf("expected 2 runs, got %+v", log)
+	}
+
+	kyverno := log.Runs[0]
+	if kyverno.Tool.Driver.Name != "Kyverno" || len(kyverno.Tool.Driver.Rules) != 1 || len(kyverno.Results) != 2 {
+		t.Fatalf("expected one rule with 2 results, got %+v", kyverno)
+	}
+
+	rule := kyverno.Tool.Driver.Rules[0]
+	if rule.ID != "require-limits" || rule.Properties.SecuritySeverity != "8.0" || rule.Properties.Tags[0] != "Best Practices" {
+		t.Errorf("unexpected rule: %+v", rule)
+	}
+
+	fail := kyverno.Results[0]
+	if fail.Level != "error" || fail.Kind != "fail" || fail.Message.Text != "require-limits/validate" {
+		t.Errorf("unexpected result: %+v", fail)
+	}
+	if fail.Locations[0].LogicalLocations[0].FullyQualifiedName != "test/Pod/nginx" {
+		t.Errorf("unexpected location: %+v", fail.Locations)
+	}
+	if kyverno.Results[1].Kind != "pass" || kyverno.Results[1].Level != "none" {
+		t.Errorf("unexpected pass result: %+v", kyverno.Results[1])
+	}
+
+	trivy := log.Runs[1].Results[0]
+	if trivy.Level != "warning" || trivy.Locations[0].PhysicalLocation.ArtifactLocation.URI != "go.sum" || trivy.Locations[0].PhysicalLocation.Region.StartLine != 4 {
+		t.Errorf("unexpected trivy result: %+v", trivy)
+	}
+}
@@ -0,0 +1,139 @@
+// Package score calculates compliance scores from 0 to 100 out of the result counts, weighted by severity
+package score
+
+import (
+	"math"
+	"sort"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// Weights of each severity, results without severity have the weight of an empty severity
+type Weights map[string]float64
+
+// DefaultWeights doubles the impact with each severity level
+var DefaultWeights = Weights{
+	"":                        1,
+	v1alpha2.SeverityInfo:     1,
+	v1alpha2.SeverityLow:      2,
+	v1alpha2.SeverityMedium:   4,
+	v1alpha2.SeverityHigh:     8,
+	v1alpha2.SeverityCritical: 16,
+}
+
+// Count of results with the same namespace, category, severity and status, the namespace is empty for cluster scoped results
+type Count struct {
+	Namespace string
+	Category  string
+	Severity  string
+	Status    string
+	Count     int
+}
+
+// Score of a namespace or category
+type Score struct {
+	Name string `json:"name"`
+	// Score from 0 to 100, 100 without evaluated results
+	Score float64 `json:"score"`
+	// Results counts all evaluated results, skipped results are not evaluated
+	Results int `json:"results"`
+	// Violations counts fail, warn and error results
+	Violations int `json:"violations"`
+}
+
+// Scores of the cluster, each namespace and each policy category
+type Scores struct {
+	Cluster    Score   `json:"cluster"`
+	Namespaces []Score `json:"namespaces"`
+	Categories []Score `json:"categories"`
+}
+
+type accumulator struct {
+	passed     float64
+	total      float64
+	results    int
+	violations int
+}
+
+func (a *accumulator) add(weight float64, c Count) {
+	switch c.Status {
+	case v1alpha2.StatusPass:
+		a.passed += weight * float64(c.Count)
+	case v1alpha2.StatusFail, v1alpha2.StatusWarn, v1alpha2.StatusError:
+		a.violations += c.Count
+	default:
+		return
+	}
+
+	a.total += weight * float64(c.Count)
+	a.results += c.Count
+}
+
+func (a *accumulator) score(name string) Score {
+	value := 100.0
+	if a.total > 0 {
+		value = math.Round(a.passed/a.total*10000) / 100
+	}
+
+	return Score{Name: name, Score: value, Results: a.results, Violations: a.violations}
+}
+
+func list(accumulators map[string]*accumulator) []Score {
+	scores := make([]Score, 0, len(accumulators))
+	for name, a := range accumulators {
+		scores = append(scores, a.score(name))
+	}
+
+	sort.Slice(scores, func(i, j int) bool { return scores[i].Name < scores[j].Name })
+
+	return scores
+}
+
+// Weight of the severity, unknown severities have the weight of results without severity
+func (w Weights) Weight(severity string) float64 {
+	if weight, ok := w[severity]; ok {
+		return weight
+	}
+	if weight, ok := w[""]; ok {
+		return weight
+	}
+
+	return 1
+}
+
+// Calculate the scores of the counts, categories of cluster scoped results are included
+func Calculate(counts []Count, weights Weights) Scores {
+	if weights == nil {
+		weights = DefaultWeights
+	}
+
+	cluster := &accumulator{}
+	namespaces := make(map[string]*accumulator)
+	categories := make(map[string]*accumulator)
+
+	for _, c := range counts {
+		weight := weights.Weight(c.Severity)
+
+		cluster.add(weight, c)
+
+		if c.Namespace != "" {
+			if _, ok := namespaces[c.Namespace]; !ok {
+				namespaces[c.Namespace] = &accumulator{}
+			}
+			namespaces[c.Namespace].add(weight, c)
+		}
+
+		if c.Category != "" {
+			if _, ok := categories[c.Category]; !ok {
+				categories[c.Category] = &accumulator{}
+			}
+			categories[c.Category].add(weight, c)
+		}
+	}
+
+	return Scores{
+		Cluster:    cluster.score("cluster"),
+		Namespaces: list(namespaces),
+		Categories: list(categories),
+	}
+}
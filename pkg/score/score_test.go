@@ -0,0 +1,66 @@
+package score_test
+
+import (
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/score"
+)
+
+var counts = []score.Count{
+	{Namespace: "test", Category: "Pod Security", Severity: v1alpha2.SeverityHigh, Status: v1alpha2.StatusPass, Count: 3},
+	{Namespace: "test", Category: "Pod Security", Severity: v1alpha2.SeverityHigh, Status: v1alpha2.StatusFail, Count: 1},
+	{Namespace: "test", Category: "Best Practices", Severity: v1alpha2.SeverityLow, Status: v1alpha2.StatusWarn, Count: 2},
+	{Namespace: "test", Category: "Best Practices", Severity: v1alpha2.SeverityLow, Status: v1alpha2.StatusSkip, Count: 5},
+	{Namespace: "clean", Category: "Best Practices", Status: v1alpha2.StatusPass, Count: 2},
+	{Category: "Convention", Severity: v1alpha2.SeverityCritical, Status: v1alpha2.StatusFail, Count: 1},
+}
+
+func Test_Calculate(t *testing.T) {
+	scores := score.Calculate(counts, score.DefaultWeights)
+
+	t.Run("Cluster", func(t *testing.T) {
+		// passed: 3*8 + 2*1 = 26, total: 26 + 8 + 2*2 + 16 = 54
+		if scores.Cluster.Score != 48.15 {
+			t.Errorf("expected cluster score 48.15, got %v", scores.Cluster.Score)
+		}
+		if scores.Cluster.Results != 9 || scores.Cluster.Violations != 4 {
+			t.Errorf("expected 9 evaluated results with 4 violations, got %d with %d", scores.Cluster.Results, scores.Cluster.Violations)
+		}
+	})
+
+	t.Run("Namespaces", func(t *testing.T) {
+		if len(scores.Namespaces) != 2 {
+			t.Fatalf("expected 2 namespace scores, got %d", len(scores.Namespaces))
+		}
+		if scores.Namespaces[0].Name != "clean" || scores.Namespaces[0].Score != 100 {
+			t.Errorf("unexpected score %v", scores.Namespaces[0])
+		}
+		// passed: 24, total: 24 + 8 + 4 = 36
+		if scores.Namespaces[1].Name != "test" || scores.Namespaces[1].Score != 66.67 {
+			t.Errorf("unexpected score %v", scores.Namespaces[1])
+		}
+	})
+
+	t.Run("Categories", func(t *testing.T) {
+		if len(scores.Categories) != 3 {
+			t.Fatalf("expected 3 category scores, got %d", len(scores.Categories))
+		}
+		if scores.Categories[1].Name != "Convention" || scores.Categories[1].Score != 0 {
+			t.Errorf("expected cluster scoped results in the categories, got %v", scores.Categories[1])
+		}
+	})
+
+	t.Run("Without Results", func(t *testing.T) {
+		if s := score.Calculate(nil, nil); s.Cluster.Score != 100 || len(s.Namespaces) != 0 {
+			t.Errorf("expected full score without results, got %v", s.Cluster)
+		}
+	})
+
+	t.Run("Custom Weights", func(t *testing.T) {
+		s := score.Calculate(counts[:2], score.Weights{v1alpha2.SeverityHigh: 1})
+		if s.Cluster.Score != 75 {
+			t.Errorf("expected score 75, got %v", s.Cluster.Score)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+// Package secret redacts credentials like webhook URLs and tokens in logs, API responses and error messages.
+//
+// Target, API authentication, database and push options hold credentials as String, which is redacted when formatted
+// or marshaled. The configuration creates them with New, which additionally replaces the values in all log messages and
+// recorded delivery errors, e.g. a webhook URL contained in the error of a failed HTTP request.
+package secret
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces the secret values
+const Redacted = "[REDACTED]"
+
+// minLength of registered values, shorter values would redact unrelated text
+const minLength = 4
+
+// String is a secret value which is redacted when formatted, logged or marshaled, Value returns the plain value
+type String string
+
+// Value returns the plain secret value
+func (s String) Value() string {
+	return string(s)
+}
+
+// String returns Redacted for non empty values, so fmt and log never print the secret value
+func (s String) String() string {
+	if s == "" {
+		return ""
+	}
+
+	return Redacted
+}
+
+// GoString redacts the value for the %#v verb
+func (s String) GoString() string {
+	return `"` + s.String() + `"`
+}
+
+// MarshalText redacts the value in JSON and YAML output
+func (s String) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// New creates a String and registers its value for redaction
+func New(value string) String {
+	Register(value)
+
+	return String(value)
+}
+
+type registry struct {
+	mx     sync.RWMutex
+	values map[string]bool
+	sorted []string
+}
+
+var global = &registry{values: make(map[string]bool)}
+
+// Register values which are replaced in log messages and delivery errors, values shorter than 4 characters are ignored
+func Register(values ...string) {
+	global.mx.Lock()
+	defer global.mx.Unlock()
+
+	changed := false
+	for _, value := range values {
+		if len(value) < minLength || global.values[value] {
+			continue
+		}
+
+		global.values[value] = true
+		changed = true
+	}
+
+	if !changed {
+		return
+	}
+
+	sorted := make([]string, 0, len(global.values))
+	for value := range global.values {
+		sorted = append(sorted, value)
+	}
+
+	// longer values first, so a value containing another value is redacted completely
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+
+	global.sorted = sorted
+}
+
+// Redact replaces all registered values in the text
+func Redact(text string) string {
+	global.mx.RLock()
+	defer global.mx.RUnlock()
+
+	for _, value := range global.sorted {
+		if strings.Contains(text, value) {
+			text = strings.ReplaceAll(text, value, Redacted)
+		}
+	}
+
+	return text
+}
+
+// RedactError returns an error with the redacted message of err, err is returned unchanged if it contains no secret
+func RedactError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	msg := err.Error()
+	if redacted := Redact(msg); redacted != msg {
+		return errors.New(redacted)
+	}
+
+	return err
+}
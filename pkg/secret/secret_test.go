@@ -0,0 +1,58 @@
+package secret_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/secret"
+)
+
+func Test_String(t *testing.T) {
+	s := secret.String("xoxb-token")
+
+	if s.Value() != "xoxb-token" {
+		t.Errorf("expected the plain value, got %s", s.Value())
+	}
+
+	for _, text := range []string{fmt.Sprintf("%s", s), fmt.Sprintf("%v", s), fmt.Sprintf("%+v", struct{ Token secret.String }{s}), fmt.Sprintf("%#v", s)} {
+		if text == "" || strings.Contains(text, "xoxb") {
+			t.Errorf("expected a redacted value, got %s", text)
+		}
+	}
+
+	content, err := json.Marshal(map[string]secret.String{"token": s, "empty": ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != `{"empty":"","token":"[REDACTED]"}` {
+		t.Errorf("unexpected JSON %s", content)
+	}
+}
+
+func Test_Redact(t *testing.T) {
+	webhook := secret.New("https://discord.com/api/webhooks/123/abc")
+	secret.Register("abc", "https://discord.com/api/webhooks/123")
+
+	if text := secret.Redact(`Post "https://discord.com/api/webhooks/123/abc": EOF`); text != `Post "[REDACTED]": EOF` {
+		t.Errorf("expected the complete webhook to be redacted, got %s", text)
+	}
+	if text := secret.Redact("abc"); text != "abc" {
+		t.Errorf("expected short values to be ignored, got %s", text)
+	}
+
+	err := secret.RedactError(fmt.Errorf("request to %s failed", webhook.Value()))
+	if err.Error() != "request to [REDACTED] failed" {
+		t.Errorf("unexpected error %s", err)
+	}
+
+	original := errors.New("status code 500")
+	if secret.RedactError(original) != original {
+		t.Error("expected errors without secrets to be returned unchanged")
+	}
+	if secret.RedactError(nil) != nil {
+		t.Error("expected nil for nil errors")
+	}
+}
@@ -0,0 +1,115 @@
+// Package severity ranks the result severities. The default levels can be extended by custom levels,
+// e.g. "unknown", "negligible" or CVSS bands emitted by some sources.
+package severity
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Defaults are the default severities in ascending order
+var Defaults = []string{"info", "low", "medium", "high", "critical"}
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// Level is an additional severity level
+type Level struct {
+	Name string
+	// Above is the severity ranked directly below the level, empty ranks the level below all other severities
+	Above string
+	// Color like "#9e9e9e" used by targets with colored messages
+	Color string
+}
+
+var (
+	order  = Defaults
+	colors = map[string]string{}
+	mx     sync.RWMutex
+)
+
+// Order returns the default severities extended by the levels in ascending order.
+// Levels are inserted in the given order, so a level can be ranked above a preceding level
+func Order(levels []Level) ([]string, error) {
+	list := append(make([]string, 0, len(Defaults)+len(levels)), Defaults...)
+
+	for _, l := range levels {
+		name := strings.ToLower(l.Name)
+		if !namePattern.MatchString(name) {
+			return Defaults, fmt.Errorf("invalid severity name '%s', expected letters, digits, '.', '_' or '-'", l.Name)
+		}
+		if RankIn(list, name) >= 0 {
+			return Defaults, fmt.Errorf("severity '%s' is already defined", l.Name)
+		}
+
+		pos := 0
+		if l.Above != "" {
+			pos = RankIn(list, l.Above) + 1
+			if pos == 0 {
+				return Defaults, fmt.Errorf("severity '%s': unknown severity '%s' to rank above", l.Name, l.Above)
+			}
+		}
+
+		list = append(list[:pos], append([]string{name}, list[pos:]...)...)
+	}
+
+	return list, nil
+}
+
+// Use the levels for the package functions
+func Use(levels []Level) error {
+	list, err := Order(levels)
+	if err != nil {
+		return err
+	}
+
+	c := make(map[string]string, len(levels))
+	for _, l := range levels {
+		if l.Color != "" {
+			c[strings.ToLower(l.Name)] = l.Color
+		}
+	}
+
+	mx.Lock()
+	defer mx.Unlock()
+
+	order = list
+	colors = c
+
+	return nil
+}
+
+// List returns the severities in ascending order
+func List() []string {
+	mx.RLock()
+	defer mx.RUnlock()
+
+	return order
+}
+
+// Rank returns the position of the severity in List, -1 for unknown severities
+func Rank(severity string) int {
+	return RankIn(List(), severity)
+}
+
+// RankIn returns the position of the severity in the list, -1 for unknown severities
+func RankIn(list []string, severity string) int {
+	for i, s := range list {
+		if strings.EqualFold(s, severity) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Color of a custom level, false for default severities and levels without color
+func Color(severity string) (string, bool) {
+	mx.RLock()
+	defer mx.RUnlock()
+
+	color, ok := colors[strings.ToLower(severity)]
+
+	return color, ok
+}
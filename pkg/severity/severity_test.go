@@ -0,0 +1,66 @@
+package severity_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/severity"
+)
+
+func Test_Order(t *testing.T) {
+	list, err := severity.Order([]severity.Level{
+		{Name: "unknown"},
+		{Name: "Negligible", Above: "unknown"},
+		{Name: "cvss-9", Above: "high"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []string{"unknown", "negligible", "info", "low", "medium", "high", "cvss-9", "critical"}
+	if !reflect.DeepEqual(list, expected) {
+		t.Errorf("expected %v, got %v", expected, list)
+	}
+
+	for _, levels := range [][]severity.Level{
+		{{Name: "high"}},
+		{{Name: "very high"}},
+		{{Name: "urgent", Above: "unknown"}},
+	} {
+		if _, err := severity.Order(levels); err == nil {
+			t.Errorf("expected error for %v", levels)
+		}
+	}
+
+	if !reflect.DeepEqual(severity.Defaults, []string{"info", "low", "medium", "high", "critical"}) {
+		t.Errorf("expected defaults to be unchanged, got %v", severity.Defaults)
+	}
+}
+
+func Test_Use(t *testing.T) {
+	defer severity.Use(nil)
+
+	if err := severity.Use([]severity.Level{{Name: "unknown", Color: "#9e9e9e"}, {Name: "cvss-9", Above: "high"}}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if severity.Rank("unknown") != 0 || severity.Rank("info") != 1 {
+		t.Errorf("expected unknown to be ranked below info")
+	}
+	if severity.Rank("CVSS-9") <= severity.Rank("high") || severity.Rank("cvss-9") >= severity.Rank("critical") {
+		t.Errorf("expected cvss-9 to be ranked between high and critical")
+	}
+	if color, ok := severity.Color("unknown"); !ok || color != "#9e9e9e" {
+		t.Errorf("expected color of unknown, got %s", color)
+	}
+	if _, ok := severity.Color("high"); ok {
+		t.Errorf("expected no custom color for default severities")
+	}
+
+	if err := severity.Use([]severity.Level{{Name: "high"}}); err == nil {
+		t.Errorf("expected error for duplicated severity")
+	}
+	if severity.Rank("cvss-9") < 0 {
+		t.Errorf("expected invalid levels to keep the current severities")
+	}
+}
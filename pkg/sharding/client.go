@@ -0,0 +1,207 @@
+package sharding
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "k8s.io/client-go/kubernetes/typed/coordination/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/logging"
+)
+
+var logger = logging.Component("sharding")
+
+// GroupLabel identifies all leases of replicas sharing the same work
+const GroupLabel = "policy-reporter.kyverno.io/shard-group"
+
+// Sharder decides if the current replica is responsible for a given key
+type Sharder interface {
+	// Owns returns true if the current replica is responsible for the given key
+	Owns(key string) bool
+}
+
+// Client maintains a lease per replica and distributes keys over all replicas with a valid lease
+type Client struct {
+	client        v1.LeaseInterface
+	group         string
+	identity      string
+	leaseDuration time.Duration
+	renewPeriod   time.Duration
+	ring          *Ring
+	onChange      func(members []string)
+	// refreshed after the members were listed once
+	refreshed atomic.Bool
+}
+
+// RegisterOnChange callback is executed whenever the set of active replicas changes
+func (c *Client) RegisterOnChange(callback func(members []string)) *Client {
+	c.onChange = callback
+
+	return c
+}
+
+// Owns returns true if the current replica is responsible for the given key.
+// Before the members were listed once no key is owned, so replicas starting together do not send duplicates,
+// afterwards the current replica owns all keys as long as no member is known
+func (c *Client) Owns(key string) bool {
+	if !c.refreshed.Load() {
+		return false
+	}
+
+	owner := c.ring.Owner(key)
+
+	return owner == "" || owner == c.identity
+}
+
+// Members returns all currently active replicas
+func (c *Client) Members() []string {
+	return c.ring.Members()
+}
+
+// Run renews the own lease and refreshes the members until the context is canceled
+func (c *Client) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.renewPeriod)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Renew(ctx); err != nil {
+			logger.Errorf("failed to renew shard lease: %s", err)
+		}
+
+		if err := c.Refresh(ctx); err != nil {
+			logger.Errorf("failed to refresh shard members: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			c.release()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Renew creates or updates the lease of the current replica
+func (c *Client) Renew(ctx context.Context) error {
+	now := metav1.NewMicroTime(time.Now())
+	duration := int32(c.leaseDuration.Seconds())
+
+	lease, err := c.client.Get(ctx, c.leaseName(), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = c.client.Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   c.leaseName(),
+				Labels: map[string]string{GroupLabel: c.group},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &c.identity,
+				LeaseDurationSeconds: &duration,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}, metav1.CreateOptions{})
+
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	lease.Spec.HolderIdentity = &c.identity
+	lease.Spec.LeaseDurationSeconds = &duration
+	lease.Spec.RenewTime = &now
+
+	_, err = c.client.Update(ctx, lease, metav1.UpdateOptions{})
+
+	return err
+}
+
+// Refresh updates the hash ring with all replicas holding a valid lease
+func (c *Client) Refresh(ctx context.Context) error {
+	list, err := c.client.List(ctx, metav1.ListOptions{LabelSelector: GroupLabel + "=" + c.group})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	members := make([]string, 0, len(list.Items))
+
+	for _, lease := range list.Items {
+		if lease.Spec.HolderIdentity == nil || lease.Spec.RenewTime == nil {
+			continue
+		}
+
+		duration := c.leaseDuration
+		if lease.Spec.LeaseDurationSeconds != nil {
+			duration = time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+		}
+
+		if lease.Spec.RenewTime.Add(duration).Before(now) {
+			continue
+		}
+
+		members = append(members, *lease.Spec.HolderIdentity)
+	}
+
+	if !equal(members, c.ring.Members()) {
+		c.ring.SetMembers(members)
+		c.onChange(c.ring.Members())
+	}
+
+	c.refreshed.Store(true)
+
+	return nil
+}
+
+func (c *Client) release() {
+	err := c.client.Delete(context.Background(), c.leaseName(), metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		logger.Errorf("failed to release shard lease: %s", err)
+	}
+}
+
+func (c *Client) leaseName() string {
+	return c.group + "-" + c.identity
+}
+
+func equal(members, current []string) bool {
+	if len(members) != len(current) {
+		return false
+	}
+
+	lookup := make(map[string]bool, len(current))
+	for _, m := range current {
+		lookup[m] = true
+	}
+
+	for _, m := range members {
+		if !lookup[m] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// New creates a sharding client for the given replica identity
+func New(
+	client v1.LeaseInterface,
+	group string,
+	identity string,
+	leaseDuration time.Duration,
+	renewPeriod time.Duration,
+	virtualNodes int,
+) *Client {
+	return &Client{
+		client:        client,
+		group:         group,
+		identity:      identity,
+		leaseDuration: leaseDuration,
+		renewPeriod:   renewPeriod,
+		ring:          NewRing(virtualNodes),
+		onChange:      func(members []string) {},
+	}
+}
@@ -0,0 +1,96 @@
+package sharding_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kyverno/policy-reporter/pkg/sharding"
+)
+
+func Test_Client(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Owns no keys before the first refresh", func(t *testing.T) {
+		leases := fake.NewSimpleClientset().CoordinationV1().Leases("policy-reporter")
+		client := sharding.New(leases, "policy-reporter", "pod-a", 15*time.Second, 5*time.Second, 16)
+
+		if client.Owns("default") {
+			t.Error("Expected to own no keys before the members were listed")
+		}
+
+		if err := client.Refresh(ctx); err != nil {
+			t.Fatalf("Unexpected refresh error: %s", err)
+		}
+		if !client.Owns("default") {
+			t.Error("Expected to own all keys as long as no member is known")
+		}
+	})
+	t.Run("Distribute over active Members", func(t *testing.T) {
+		leases := fake.NewSimpleClientset().CoordinationV1().Leases("policy-reporter")
+
+		podA := sharding.New(leases, "policy-reporter", "pod-a", 15*time.Second, 5*time.Second, 16)
+		podB := sharding.New(leases, "policy-reporter", "pod-b", 15*time.Second, 5*time.Second, 16)
+
+		var changed []string
+		podA.RegisterOnChange(func(members []string) { changed = members })
+
+		for _, c := range []*sharding.Client{podA, podB} {
+			if err := c.Renew(ctx); err != nil {
+				t.Fatalf("Unexpected renew error: %s", err)
+			}
+			if err := c.Renew(ctx); err != nil {
+				t.Fatalf("Unexpected error on updating an existing lease: %s", err)
+			}
+		}
+
+		for _, c := range []*sharding.Client{podA, podB} {
+			if err := c.Refresh(ctx); err != nil {
+				t.Fatalf("Unexpected refresh error: %s", err)
+			}
+		}
+
+		if len(changed) != 2 {
+			t.Fatalf("Expected onChange called with 2 members, got %v", changed)
+		}
+
+		for _, ns := range []string{"default", "kube-system", "test", ""} {
+			if podA.Owns(ns) == podB.Owns(ns) {
+				t.Errorf("Expected exactly one replica to own %s", ns)
+			}
+		}
+	})
+	t.Run("Ignore expired Leases", func(t *testing.T) {
+		leases := fake.NewSimpleClientset().CoordinationV1().Leases("policy-reporter")
+
+		expired := sharding.New(leases, "policy-reporter", "pod-a", -1*time.Second, 5*time.Second, 16)
+		active := sharding.New(leases, "policy-reporter", "pod-b", 15*time.Second, 5*time.Second, 16)
+
+		expired.Renew(ctx)
+		active.Renew(ctx)
+		active.Refresh(ctx)
+
+		if members := active.Members(); len(members) != 1 || members[0] != "pod-b" {
+			t.Errorf("Expected only the active member, got %v", members)
+		}
+	})
+	t.Run("Release Lease on shutdown", func(t *testing.T) {
+		leases := fake.NewSimpleClientset().CoordinationV1().Leases("policy-reporter")
+		client := sharding.New(leases, "policy-reporter", "pod-a", 15*time.Second, time.Second, 16)
+
+		cctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		if err := client.Run(cctx); err != nil {
+			t.Fatalf("Unexpected run error: %s", err)
+		}
+
+		list, _ := leases.List(ctx, metav1.ListOptions{})
+		if len(list.Items) != 0 {
+			t.Error("Expected lease to be released")
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package sharding
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/segmentio/fasthash/fnv1a"
+)
+
+// Ring is a consistent hash ring to distribute keys like namespaces over multiple members
+type Ring struct {
+	virtualNodes int
+	hashes       []uint64
+	nodes        map[uint64]string
+	members      []string
+	mx           *sync.RWMutex
+}
+
+// SetMembers replaces the current members of the ring
+func (r *Ring) SetMembers(members []string) {
+	sorted := append(make([]string, 0, len(members)), members...)
+	sort.Strings(sorted)
+
+	hashes := make([]uint64, 0, len(sorted)*r.virtualNodes)
+	nodes := make(map[uint64]string, len(sorted)*r.virtualNodes)
+
+	for _, member := range sorted {
+		for i := 0; i < r.virtualNodes; i++ {
+			hash := hash(member + "#" + strconv.Itoa(i))
+
+			hashes = append(hashes, hash)
+			nodes[hash] = member
+		}
+	}
+
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.hashes = hashes
+	r.nodes = nodes
+	r.members = sorted
+}
+
+// Members returns the sorted list of current members
+func (r *Ring) Members() []string {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	return r.members
+}
+
+// Owner returns the member responsible for the given key, empty if the ring has no members
+func (r *Ring) Owner(key string) string {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	if len(r.hashes) == 0 {
+		return ""
+	}
+
+	hash := hash(key)
+
+	index := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= hash })
+	if index == len(r.hashes) {
+		index = 0
+	}
+
+	return r.nodes[r.hashes[index]]
+}
+
+// hash mixes the fnv1a hash to spread similar keys like "pod-a#1" and "pod-a#2" over the whole ring
+func hash(key string) uint64 {
+	h := fnv1a.HashString64(key)
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+
+	return h
+}
+
+// NewRing creates a new Ring with the given amount of virtual nodes per member
+func NewRing(virtualNodes int) *Ring {
+	if virtualNodes < 1 {
+		virtualNodes = 1
+	}
+
+	return &Ring{
+		virtualNodes: virtualNodes,
+		nodes:        make(map[uint64]string),
+		mx:           new(sync.RWMutex),
+	}
+}
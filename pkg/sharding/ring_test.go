@@ -0,0 +1,52 @@
+package sharding_test
+
+import (
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/sharding"
+)
+
+func Test_Ring(t *testing.T) {
+	t.Run("Empty Ring", func(t *testing.T) {
+		ring := sharding.NewRing(16)
+
+		if owner := ring.Owner("default"); owner != "" {
+			t.Errorf("Expected no owner for an empty ring, got %s", owner)
+		}
+	})
+	t.Run("Stable Owner", func(t *testing.T) {
+		ring := sharding.NewRing(16)
+		ring.SetMembers([]string{"pod-b", "pod-a"})
+
+		other := sharding.NewRing(16)
+		other.SetMembers([]string{"pod-a", "pod-b"})
+
+		for _, ns := range []string{"default", "kube-system", "test", ""} {
+			if ring.Owner(ns) != other.Owner(ns) {
+				t.Errorf("Expected same owner for %s independent of the member order", ns)
+			}
+		}
+	})
+	t.Run("Distribute Keys", func(t *testing.T) {
+		ring := sharding.NewRing(64)
+		ring.SetMembers([]string{"pod-a", "pod-b", "pod-c"})
+
+		owners := make(map[string]int)
+		for i := 0; i < 300; i++ {
+			owners[ring.Owner("namespace-"+string(rune('a'+i%26))+string(rune('a'+i/26)))]++
+		}
+
+		if len(owners) != 3 {
+			t.Errorf("Expected keys distributed over all 3 members, got %d", len(owners))
+		}
+	})
+	t.Run("Members", func(t *testing.T) {
+		ring := sharding.NewRing(0)
+		ring.SetMembers([]string{"pod-b", "pod-a"})
+
+		members := ring.Members()
+		if len(members) != 2 || members[0] != "pod-a" || members[1] != "pod-b" {
+			t.Errorf("Expected sorted members, got %v", members)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package snooze
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/api/auth"
+	"github.com/kyverno/policy-reporter/pkg/helper"
+)
+
+const (
+	// Path lists, creates and removes snoozes
+	Path = "/v2/snoozes"
+	// EventsPath lists the audit trail of the snoozes
+	EventsPath = "/v2/snoozes/events"
+)
+
+// Handler of the requests sent to Path, POST expects the resultId and duration query parameters with an optional reason,
+// DELETE expects the resultId query parameter
+func (r *Registry) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+
+		switch req.Method {
+		case http.MethodGet:
+			helper.SendJSONResponse(w, r.List(), nil)
+		case http.MethodPost:
+			duration, err := time.ParseDuration(query.Get("duration"))
+			if err != nil {
+				helper.SendBadRequest(w, fmt.Errorf("invalid duration '%s', expected a duration like 24h", query.Get("duration")))
+				return
+			}
+
+			s, err := r.Snooze(query.Get("resultId"), duration, query.Get("reason"), actor(req))
+			if errors.Is(err, ErrInvalid) {
+				helper.SendBadRequest(w, err)
+				return
+			}
+
+			helper.SendJSONResponse(w, s, err)
+		case http.MethodDelete:
+			resultID := query.Get("resultId")
+
+			removed, err := r.Unsnooze(resultID, actor(req))
+			if err != nil {
+				helper.SendError(w, http.StatusInternalServerError, err)
+				return
+			}
+			if !removed {
+				helper.SendError(w, http.StatusNotFound, fmt.Errorf("result %s is not snoozed", resultID))
+				return
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Allow", "GET, POST, DELETE")
+			helper.SendError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+		}
+	}
+}
+
+// EventsHandler lists the audit trail of the resultId query parameter, of all results without it
+func (r *Registry) EventsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		list, err := r.Events(req.URL.Query().Get("resultId"))
+		helper.SendJSONResponse(w, list, err)
+	}
+}
+
+func actor(req *http.Request) string {
+	if identity, ok := auth.IdentityFrom(req.Context()); ok {
+		return identity.Name
+	}
+
+	return ""
+}
@@ -0,0 +1,246 @@
+// Package snooze mutes the notifications of individual results until the snooze expires.
+//
+// Snoozed results stay in the store and the API, only the target notifications and optionally the email summaries
+// skip them. Each snooze and unsnooze is recorded as Event with the acting identity.
+package snooze
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+var logger = logging.Component("snooze")
+
+// Actions of the recorded events
+const (
+	ActionSnooze   = "snooze"
+	ActionUnsnooze = "unsnooze"
+)
+
+// DefaultMaxDuration of a single snooze
+const DefaultMaxDuration = 30 * 24 * time.Hour
+
+// ErrInvalid is returned for snoozes without result ID or with an invalid duration
+var ErrInvalid = errors.New("invalid snooze")
+
+// Snooze of a result until the expiry
+type Snooze struct {
+	ResultID  string    `json:"resultId"`
+	Until     time.Time `json:"until"`
+	Reason    string    `json:"reason,omitempty"`
+	SnoozedBy string    `json:"snoozedBy,omitempty"`
+	Created   time.Time `json:"created"`
+}
+
+// Event of the audit trail, records who snoozed or unsnoozed a result
+type Event struct {
+	ResultID string     `json:"resultId"`
+	Action   string     `json:"action"`
+	Actor    string     `json:"actor,omitempty"`
+	Until    *time.Time `json:"until,omitempty"`
+	Reason   string     `json:"reason,omitempty"`
+	Time     time.Time  `json:"time"`
+}
+
+// Store persists the snoozes and their audit trail
+type Store interface {
+	// SaveSnooze creates or replaces the snooze of the result
+	SaveSnooze(s Snooze) error
+	// DeleteSnooze removes the snooze of the result and returns false if it did not exist
+	DeleteSnooze(resultID string) (bool, error)
+	// FetchSnoozes returns the snoozes which expire after now
+	FetchSnoozes(now time.Time) ([]Snooze, error)
+	// PruneSnoozes removes the snoozes expired before the given time, the events are kept
+	PruneSnoozes(before time.Time) error
+	// AddSnoozeEvent appends the event to the audit trail
+	AddSnoozeEvent(e Event) error
+	// FetchSnoozeEvents returns the events of the result, of all results if empty, latest first
+	FetchSnoozeEvents(resultID string) ([]Event, error)
+}
+
+// Registry caches the active snoozes of the store, so each notification is checked without a query
+type Registry struct {
+	store       Store
+	maxDuration time.Duration
+	now         func() time.Time
+	mx          *sync.RWMutex
+	active      map[string]Snooze
+}
+
+// Load replaces the cached snoozes with the active snoozes of the store, e.g. created by another instance
+func (r *Registry) Load() error {
+	list, err := r.store.FetchSnoozes(r.now())
+	if err != nil {
+		return err
+	}
+
+	active := make(map[string]Snooze, len(list))
+	for _, s := range list {
+		active[s.ResultID] = s
+	}
+
+	r.mx.Lock()
+	r.active = active
+	r.mx.Unlock()
+
+	return nil
+}
+
+// Snooze the result for the duration, snoozing a snoozed result replaces its expiry
+func (r *Registry) Snooze(resultID string, duration time.Duration, reason, actor string) (Snooze, error) {
+	resultID = strings.TrimSpace(resultID)
+	if resultID == "" {
+		return Snooze{}, fmt.Errorf("%w: resultId is required", ErrInvalid)
+	}
+	if duration <= 0 {
+		return Snooze{}, fmt.Errorf("%w: duration must be positive", ErrInvalid)
+	}
+	if r.maxDuration > 0 && duration > r.maxDuration {
+		return Snooze{}, fmt.Errorf("%w: duration exceeds the maximum of %s", ErrInvalid, r.maxDuration)
+	}
+
+	now := r.now()
+	s := Snooze{ResultID: resultID, Until: now.Add(duration), Reason: reason, SnoozedBy: actor, Created: now}
+
+	if err := r.store.SaveSnooze(s); err != nil {
+		return s, err
+	}
+
+	r.mx.Lock()
+	r.active[resultID] = s
+	r.mx.Unlock()
+
+	r.record(Event{ResultID: resultID, Action: ActionSnooze, Actor: actor, Until: &s.Until, Reason: reason, Time: now})
+	logger.Infof("result %s snoozed by %s until %s", resultID, actorName(actor), s.Until.Format(time.RFC3339))
+
+	return s, nil
+}
+
+// Unsnooze removes the snooze of the result, returns false if the result was not snoozed
+func (r *Registry) Unsnooze(resultID, actor string) (bool, error) {
+	removed, err := r.store.DeleteSnooze(resultID)
+	if err != nil || !removed {
+		return removed, err
+	}
+
+	r.mx.Lock()
+	delete(r.active, resultID)
+	r.mx.Unlock()
+
+	r.record(Event{ResultID: resultID, Action: ActionUnsnooze, Actor: actor, Time: r.now()})
+	logger.Infof("result %s unsnoozed by %s", resultID, actorName(actor))
+
+	return true, nil
+}
+
+// Snoozed reports if the result is snoozed
+func (r *Registry) Snoozed(resultID string) bool {
+	r.mx.RLock()
+	s, ok := r.active[resultID]
+	r.mx.RUnlock()
+
+	return ok && r.now().Before(s.Until)
+}
+
+// Validate returns false for snoozed results, used to exclude them from the email summaries
+func (r *Registry) Validate(_ v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) bool {
+	return !r.Snoozed(result.GetID())
+}
+
+// Results wraps a result listener, notifications of snoozed results are dropped
+func (r *Registry) Results(callback report.PolicyReportResultListener) report.PolicyReportResultListener {
+	return func(rep v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult, preExisted bool) {
+		if r.Snoozed(result.GetID()) {
+			return
+		}
+
+		callback(rep, result, preExisted)
+	}
+}
+
+// List returns the active snoozes ordered by expiry
+func (r *Registry) List() []Snooze {
+	now := r.now()
+
+	r.mx.RLock()
+	list := make([]Snooze, 0, len(r.active))
+	for _, s := range r.active {
+		if now.Before(s.Until) {
+			list = append(list, s)
+		}
+	}
+	r.mx.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Until.Equal(list[j].Until) {
+			return list[i].ResultID < list[j].ResultID
+		}
+
+		return list[i].Until.Before(list[j].Until)
+	})
+
+	return list
+}
+
+// Events returns the audit trail of the result, of all results if empty
+func (r *Registry) Events(resultID string) ([]Event, error) {
+	return r.store.FetchSnoozeEvents(resultID)
+}
+
+// Run reloads the snoozes and removes the expired snoozes in the given interval until the context is canceled
+func (r *Registry) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.store.PruneSnoozes(r.now()); err != nil {
+				logger.Errorf("failed to remove expired snoozes: %s", err)
+			}
+			if err := r.Load(); err != nil {
+				logger.Errorf("failed to load snoozes: %s", err)
+			}
+		}
+	}
+}
+
+func (r *Registry) record(e Event) {
+	if err := r.store.AddSnoozeEvent(e); err != nil {
+		logger.Errorf("failed to record %s of result %s: %s", e.Action, e.ResultID, err)
+	}
+}
+
+func actorName(actor string) string {
+	if actor == "" {
+		return "anonymous"
+	}
+
+	return actor
+}
+
+// NewRegistry creates a Registry of the store, maxDuration limits each snooze and defaults to DefaultMaxDuration
+func NewRegistry(store Store, maxDuration time.Duration) *Registry {
+	if maxDuration == 0 {
+		maxDuration = DefaultMaxDuration
+	}
+
+	return &Registry{
+		store:       store,
+		maxDuration: maxDuration,
+		now:         time.Now,
+		mx:          new(sync.RWMutex),
+		active:      make(map[string]Snooze),
+	}
+}
@@ -0,0 +1,169 @@
+package snooze_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/api/auth"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/snooze"
+)
+
+type store struct {
+	snoozes map[string]snooze.Snooze
+	events  []snooze.Event
+}
+
+func (s *store) SaveSnooze(sn snooze.Snooze) error {
+	s.snoozes[sn.ResultID] = sn
+	return nil
+}
+
+func (s *store) DeleteSnooze(resultID string) (bool, error) {
+	_, ok := s.snoozes[resultID]
+	delete(s.snoozes, resultID)
+
+	return ok, nil
+}
+
+func (s *store) FetchSnoozes(now time.Time) ([]snooze.Snooze, error) {
+	list := make([]snooze.Snooze, 0)
+	for _, sn := range s.snoozes {
+		if sn.Until.After(now) {
+			list = append(list, sn)
+		}
+	}
+
+	return list, nil
+}
+
+func (s *store) PruneSnoozes(before time.Time) error {
+	for id, sn := range s.snoozes {
+		if !sn.Until.After(before) {
+			delete(s.snoozes, id)
+		}
+	}
+
+	return nil
+}
+
+func (s *store) AddSnoozeEvent(e snooze.Event) error {
+	s.events = append(s.events, e)
+	return nil
+}
+
+func (s *store) FetchSnoozeEvents(resultID string) ([]snooze.Event, error) {
+	list := make([]snooze.Event, 0)
+	for _, e := range s.events {
+		if resultID == "" || e.ResultID == resultID {
+			list = append(list, e)
+		}
+	}
+
+	return list, nil
+}
+
+func newStore() *store {
+	return &store{snoozes: make(map[string]snooze.Snooze)}
+}
+
+func Test_Registry(t *testing.T) {
+	s := newStore()
+	registry := snooze.NewRegistry(s, 24*time.Hour)
+
+	if _, err := registry.Snooze("123", 48*time.Hour, "", "jane"); !errors.Is(err, snooze.ErrInvalid) {
+		t.Errorf("expected durations above the maximum to be invalid, got %v", err)
+	}
+	if _, err := registry.Snooze(" ", time.Hour, "", "jane"); !errors.Is(err, snooze.ErrInvalid) {
+		t.Errorf("expected resultId to be required, got %v", err)
+	}
+
+	if _, err := registry.Snooze("123", time.Hour, "fix scheduled", "jane"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	sent := make([]string, 0)
+	listener := registry.Results(func(_ v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult, _ bool) {
+		sent = append(sent, r.ID)
+	})
+
+	listener(&v1alpha2.PolicyReport{}, v1alpha2.PolicyReportResult{ID: "123"}, false)
+	listener(&v1alpha2.PolicyReport{}, v1alpha2.PolicyReportResult{ID: "456"}, false)
+
+	if len(sent) != 1 || sent[0] != "456" {
+		t.Errorf("expected only the result which is not snoozed to be sent, got %v", sent)
+	}
+	if registry.Validate(&v1alpha2.PolicyReport{}, v1alpha2.PolicyReportResult{ID: "123"}) {
+		t.Errorf("expected snoozed results to be excluded from the summaries")
+	}
+
+	// snoozes of other instances are loaded from the store
+	s.snoozes["789"] = snooze.Snooze{ResultID: "789", Until: time.Now().Add(time.Hour)}
+	if err := registry.Load(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !registry.Snoozed("789") || len(registry.List()) != 2 {
+		t.Errorf("expected the snoozes of the store, got %v", registry.List())
+	}
+
+	removed, _ := registry.Unsnooze("123", "john")
+	if !removed || registry.Snoozed("123") {
+		t.Errorf("expected result unsnoozed")
+	}
+	if removed, _ := registry.Unsnooze("123", "john"); removed {
+		t.Errorf("expected false for results which are not snoozed")
+	}
+
+	events, _ := registry.Events("123")
+	if len(events) != 2 || events[0].Actor != "jane" || events[0].Action != snooze.ActionSnooze || events[1].Actor != "john" || events[1].Action != snooze.ActionUnsnooze {
+		t.Errorf("expected snooze and unsnooze recorded with their actors, got %+v", events)
+	}
+}
+
+func Test_Handler(t *testing.T) {
+	registry := snooze.NewRegistry(newStore(), 0)
+	handler := registry.Handler()
+
+	req := httptest.NewRequest(http.MethodPost, snooze.Path+"?resultId=123&duration=24h&reason=planned", nil)
+	req = req.WithContext(auth.WithIdentity(req.Context(), auth.Identity{Name: "jane", Level: auth.Admin}))
+
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `"snoozedBy":"jane"`) {
+		t.Errorf("expected snooze by jane, got %d %s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodPost, snooze.Path+"?resultId=123&duration=tomorrow", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid durations, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodGet, snooze.Path, nil))
+	if !strings.Contains(rr.Body.String(), `"resultId":"123"`) {
+		t.Errorf("expected snoozed result listed, got %s", rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodDelete, snooze.Path+"?resultId=123", nil))
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodDelete, snooze.Path+"?resultId=123", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	registry.EventsHandler()(rr, httptest.NewRequest(http.MethodGet, snooze.EventsPath+"?resultId=123", nil))
+	if strings.Count(rr.Body.String(), `"action"`) != 2 {
+		t.Errorf("expected 2 events, got %s", rr.Body.String())
+	}
+}
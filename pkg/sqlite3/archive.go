@@ -0,0 +1,267 @@
+package sqlite3
+
+import (
+	"bufio"
+	"compress/gzip"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ArchiveVersion of the export format, archives of newer versions are rejected by Import
+const ArchiveVersion = 1
+
+// archiveTable of the store, exported and imported in the order of archiveTables so referenced rows exist first
+type archiveTable struct {
+	name string
+	// order of the exported rows, keeps the order of the history entries
+	order string
+	// generated columns are not imported, the target database assigns new values
+	generated []string
+}
+
+var archiveTables = []archiveTable{
+	{name: "policy_report"},
+	{name: "policy_report_result"},
+	{name: "policy_report_history", order: "id", generated: []string{"id"}},
+	{name: "policy_report_result_history", order: "id", generated: []string{"id"}},
+	{name: "policy_report_tombstone"},
+	{name: "policy_report_subscription"},
+	{name: "policy_report_triage"},
+	{name: "policy_report_snooze"},
+	{name: "policy_report_snooze_event", order: "timestamp"},
+}
+
+// ArchiveHeader is the first line of an archive
+type ArchiveHeader struct {
+	Version int       `json:"version"`
+	Dialect string    `json:"dialect"`
+	Created time.Time `json:"created"`
+}
+
+type archiveRow struct {
+	Table string                 `json:"table"`
+	Row   map[string]interface{} `json:"row"`
+}
+
+// ArchiveStats counts the exported or imported rows per table
+type ArchiveStats map[string]int
+
+func (s ArchiveStats) String() string {
+	tables := make([]string, 0, len(archiveTables))
+	for _, table := range archiveTables {
+		tables = append(tables, fmt.Sprintf("%s: %d", table.name, s[table.name]))
+	}
+
+	return strings.Join(tables, ", ")
+}
+
+// Export writes all reports, results, history entries, sent notifications and tombstones of the store as gzip compressed
+// JSON lines to w. Encrypted columns are exported encrypted, the importing instance requires the same encryption key
+func Export(db *sql.DB, dialect Dialect, w io.Writer) (ArchiveStats, error) {
+	gz := gzip.NewWriter(w)
+	encoder := json.NewEncoder(gz)
+
+	if err := encoder.Encode(ArchiveHeader{Version: ArchiveVersion, Dialect: dialect.Name(), Created: time.Now().UTC()}); err != nil {
+		return nil, err
+	}
+
+	stats := ArchiveStats{}
+
+	for _, table := range archiveTables {
+		count, err := exportTable(db, table, encoder)
+		if err != nil {
+			return stats, fmt.Errorf("failed to export %s: %w", table.name, err)
+		}
+
+		stats[table.name] = count
+	}
+
+	return stats, gz.Close()
+}
+
+func exportTable(db *sql.DB, table archiveTable, encoder *json.Encoder) (int, error) {
+	query := "SELECT * FROM " + table.name
+	if table.order != "" {
+		query += " ORDER BY " + table.order
+	}
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	count := 0
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return count, err
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			// text columns are returned as bytes by some drivers
+			if b, ok := values[i].([]byte); ok {
+				row[column] = string(b)
+				continue
+			}
+
+			row[column] = values[i]
+		}
+
+		if err := encoder.Encode(archiveRow{Table: table.name, Row: row}); err != nil {
+			return count, err
+		}
+
+		count++
+	}
+
+	return count, rows.Err()
+}
+
+// Import reads an archive created by Export into the store, the schema is created if it does not exist.
+// The import runs in one transaction, rows with existing primary keys are skipped so an interrupted import can be repeated.
+// History entries have no natural key and are imported again by a repeated import
+func Import(db *sql.DB, dialect Dialect, r io.Reader) (ArchiveStats, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive: %w", err)
+	}
+	defer gz.Close()
+
+	decoder := json.NewDecoder(bufio.NewReader(gz))
+	decoder.UseNumber()
+
+	header := ArchiveHeader{}
+	if err := decoder.Decode(&header); err != nil {
+		return nil, fmt.Errorf("invalid archive header: %w", err)
+	}
+	if header.Version < 1 || header.Version > ArchiveVersion {
+		return nil, fmt.Errorf("unsupported archive version %d", header.Version)
+	}
+
+	if err := dialect.Setup(db); err != nil {
+		return nil, err
+	}
+	if err := migrate(db, dialect); err != nil {
+		return nil, err
+	}
+
+	tables := make(map[string]archiveTable, len(archiveTables))
+	for _, table := range archiveTables {
+		tables[table.name] = table
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	statements := make(map[string]*sql.Stmt)
+	defer func() {
+		for _, stmt := range statements {
+			stmt.Close()
+		}
+	}()
+
+	stats := ArchiveStats{}
+
+	for {
+		row := archiveRow{}
+		if err := decoder.Decode(&row); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return stats, fmt.Errorf("invalid archive row: %w", err)
+		}
+
+		table, ok := tables[row.Table]
+		if !ok {
+			return stats, fmt.Errorf("unknown table %s in archive", row.Table)
+		}
+
+		columns, args, err := importColumns(table, row.Row)
+		if err != nil {
+			return stats, err
+		}
+
+		query := dialect.InsertIgnore(fmt.Sprintf(
+			"INSERT INTO %s (%s) VALUES (%s)",
+			table.name,
+			strings.Join(columns, ", "),
+			strings.TrimSuffix(strings.Repeat("?,", len(columns)), ","),
+		))
+		query, args = dialect.Rebind(query, args)
+
+		stmt, ok := statements[query]
+		if !ok {
+			stmt, err = tx.Prepare(query)
+			if err != nil {
+				return stats, fmt.Errorf("failed to import %s: %w", table.name, err)
+			}
+
+			statements[query] = stmt
+		}
+
+		if _, err := stmt.Exec(args...); err != nil {
+			return stats, fmt.Errorf("failed to import %s: %w", table.name, err)
+		}
+
+		stats[table.name]++
+	}
+
+	return stats, tx.Commit()
+}
+
+// columnName of the archived rows, column names are part of the insert statements and must not contain SQL
+var columnName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// importColumns returns the sorted columns of the row without the generated columns and their values
+func importColumns(table archiveTable, row map[string]interface{}) ([]string, []interface{}, error) {
+	columns := make([]string, 0, len(row))
+	for column := range row {
+		if !columnName.MatchString(column) {
+			return nil, nil, fmt.Errorf("invalid column %q of table %s in archive", column, table.name)
+		}
+		if !contains(column, table.generated) {
+			columns = append(columns, column)
+		}
+	}
+
+	sort.Strings(columns)
+
+	args := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		value := row[column]
+		if number, ok := value.(json.Number); ok {
+			if i, err := number.Int64(); err == nil {
+				value = i
+			} else if f, err := number.Float64(); err == nil {
+				value = f
+			}
+		}
+
+		args = append(args, value)
+	}
+
+	return columns, args, nil
+}
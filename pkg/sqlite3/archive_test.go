@@ -0,0 +1,101 @@
+package sqlite3_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/sqlite3"
+)
+
+func Test_Archive(t *testing.T) {
+	source, _ := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "archive-source.db"))
+	defer source.Close()
+
+	store, _ := sqlite3.NewPolicyReportStore(source)
+	store.EnableHistory()
+	store.EnableTombstones()
+
+	store.Add(preport)
+	store.Add(creport)
+	store.Remove(creport.GetID())
+
+	expected, err := store.Stats()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	archive := new(bytes.Buffer)
+
+	exported, err := sqlite3.Export(source, sqlite3.SQLite, archive)
+	if err != nil {
+		t.Fatalf("Unexpected export error: %s", err)
+	}
+	for table, count := range expected.Rows {
+		if exported[table] != count {
+			t.Errorf("Expected %d exported rows of %s, got %d", count, table, exported[table])
+		}
+	}
+
+	target, _ := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "archive-target.db"))
+	defer target.Close()
+
+	t.Run("Import", func(t *testing.T) {
+		imported, err := sqlite3.Import(target, sqlite3.SQLite, bytes.NewReader(archive.Bytes()))
+		if err != nil {
+			t.Fatalf("Unexpected import error: %s", err)
+		}
+
+		restored, _ := sqlite3.NewPolicyReportStore(target)
+		stats, err := restored.Stats()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		for table, count := range expected.Rows {
+			if stats.Rows[table] != count || imported[table] != count {
+				t.Errorf("Expected %d rows of %s, got %d, imported %d", count, table, stats.Rows[table], imported[table])
+			}
+		}
+
+		report, ok := restored.Get(preport.GetID())
+		if !ok || len(report.GetResults()) != len(preport.GetResults()) {
+			t.Errorf("Expected the imported report with its results, got %+v", report)
+		}
+	})
+
+	t.Run("Repeated Import", func(t *testing.T) {
+		if _, err := sqlite3.Import(target, sqlite3.SQLite, bytes.NewReader(archive.Bytes())); err != nil {
+			t.Fatalf("Unexpected import error: %s", err)
+		}
+
+		restored, _ := sqlite3.NewPolicyReportStore(target)
+		stats, _ := restored.Stats()
+		if stats.Rows["policy_report_result"] != expected.Rows["policy_report_result"] {
+			t.Errorf("Expected existing results to be skipped, got %d", stats.Rows["policy_report_result"])
+		}
+	})
+
+	t.Run("Invalid Archive", func(t *testing.T) {
+		if _, err := sqlite3.Import(target, sqlite3.SQLite, strings.NewReader("plain text")); err == nil {
+			t.Error("Expected error for an uncompressed archive")
+		}
+
+		for name, content := range map[string]string{
+			"version": `{"version": 99}` + "\n",
+			"table":   `{"version": 1}` + "\n" + `{"table": "users", "row": {"id": "1"}}` + "\n",
+			"column":  `{"version": 1}` + "\n" + `{"table": "policy_report", "row": {"id) VALUES (1); DROP TABLE policy_report; --": "1"}}` + "\n",
+		} {
+			buf := new(bytes.Buffer)
+			gz := gzip.NewWriter(buf)
+			gz.Write([]byte(content))
+			gz.Close()
+
+			if _, err := sqlite3.Import(target, sqlite3.SQLite, buf); err == nil {
+				t.Errorf("Expected error for invalid %s", name)
+			}
+		}
+	})
+}
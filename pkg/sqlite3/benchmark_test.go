@@ -0,0 +1,89 @@
+package sqlite3_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/loadgen"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/sqlite3"
+)
+
+func newBenchmarkStore(b *testing.B) sqlite3.PolicyReportStore {
+	// the store logs the duration of each write
+	logging.Use(io.Discard, logging.Console, logging.Info, nil)
+	b.Cleanup(func() { logging.Use(os.Stderr, logging.Console, logging.Info, nil) })
+
+	db, err := sqlite3.NewDatabase(filepath.Join(b.TempDir(), "benchmark.db"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { db.Close() })
+
+	store, err := sqlite3.NewPolicyReportStore(db)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return store
+}
+
+// BenchmarkAdd stores new reports with 20 results each
+func BenchmarkAdd(b *testing.B) {
+	store := newBenchmarkStore(b)
+	options := loadgen.Options{Namespaces: 50, Reports: b.N, Results: 20}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := store.Add(options.Report(i, 0)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkUpdate updates stored reports with 20 results each, about a tenth of the results changed
+func BenchmarkUpdate(b *testing.B) {
+	store := newBenchmarkStore(b)
+	options := loadgen.Options{Namespaces: 50, Reports: 500, Results: 20}
+
+	for i := 0; i < options.Reports; i++ {
+		if err := store.Add(options.Report(i, 0)); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := store.Update(options.Report(i%options.Reports, i/options.Reports+1)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkFetchNamespacedResults pages the results of a namespace from 10000 stored results
+func BenchmarkFetchNamespacedResults(b *testing.B) {
+	store := newBenchmarkStore(b)
+	options := loadgen.Options{Namespaces: 10, Reports: 500, Results: 20}
+
+	for i := 0; i < options.Reports; i++ {
+		if err := store.Add(options.Report(i, 0)); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := store.FetchNamespacedResults(v1.Filter{Namespaces: []string{options.Namespace(i)}}, v1.Pagination{Page: 1, Offset: 50, Direction: "ASC", SortBy: []string{"resource_name"}}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package sqlite3
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/secret"
+)
+
+// encryptedPrefix marks encrypted values, values without prefix were persisted before the encryption was enabled
+const encryptedPrefix = "enc:v1:"
+
+// FieldCipher encrypts single column values with AES-256-GCM
+type FieldCipher struct {
+	aead cipher.AEAD
+}
+
+// Encrypt the value, each call uses a new random nonce
+func (c *FieldCipher) Encrypt(value string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(value), nil)
+
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt an encrypted value, unencrypted values are returned unchanged
+func (c *FieldCipher) Decrypt(value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedPrefix) {
+		return value, nil
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil {
+		return "", err
+	}
+
+	size := c.aead.NonceSize()
+	if len(sealed) < size {
+		return "", errors.New("encrypted value too short")
+	}
+
+	plain, err := c.aead.Open(nil, sealed[:size], sealed[size:], nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plain), nil
+}
+
+// NewFieldCipher creates a FieldCipher, the AES key is derived from the SHA-256 hash of the given key
+func NewFieldCipher(key secret.String) (*FieldCipher, error) {
+	if key == "" {
+		return nil, errors.New("encryption key is empty")
+	}
+
+	hash := sha256.Sum256([]byte(key.Value()))
+
+	block, err := aes.NewCipher(hash[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return &FieldCipher{aead: aead}, nil
+}
+
+// plaintextProperties are filtered in SQL, by the teams filter and the vulnerability list, and stay unencrypted
+var plaintextProperties = map[string]bool{
+	v1alpha2.TeamKey:            true,
+	v1alpha2.VulnerabilityIDKey: true,
+}
+
+// EnableEncryption encrypts the message and the property values of all persisted results with the cipher,
+// messages are not searchable by filter expressions while encrypted.
+//
+// All other columns stay plaintext because the REST APIs filter, sort, group and count by them in SQL and the
+// random nonce of each encrypted value prevents comparisons: the policy, rule, status, severity, category, source,
+// resource and timestamps of results, the property keys, the team and vulnerabilityID property values, the report
+// tables and the history, tombstone, notification, triage, snooze and subscription tables.
+func (s *policyReportStore) EnableEncryption(c *FieldCipher) {
+	s.cipher = c
+}
+
+func (s *policyReportStore) encrypt(message string) (string, error) {
+	if s.cipher == nil {
+		return message, nil
+	}
+
+	return s.cipher.Encrypt(message)
+}
+
+func (s *policyReportStore) decrypt(message string) string {
+	if s.cipher == nil {
+		return message
+	}
+
+	value, err := s.cipher.Decrypt(message)
+	if err != nil {
+		logger.Errorf("failed to decrypt result column: %s", err)
+	}
+
+	return value
+}
+
+// encryptProperties encrypts the values of the JSON encoded properties, the keys and the plaintextProperties
+// are kept, so the column is still a JSON object for the SQL filters
+func (s *policyReportStore) encryptProperties(props string) (string, error) {
+	if s.cipher == nil || props == "" {
+		return props, nil
+	}
+
+	values := map[string]string{}
+	if err := json.Unmarshal([]byte(props), &values); err != nil || len(values) == 0 {
+		return props, nil
+	}
+
+	for key, value := range values {
+		if plaintextProperties[key] {
+			continue
+		}
+
+		encrypted, err := s.cipher.Encrypt(value)
+		if err != nil {
+			return "", err
+		}
+
+		values[key] = encrypted
+	}
+
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// decryptProperties replaces the encrypted property values, plain values of results persisted before the
+// encryption was enabled are kept
+func (s *policyReportStore) decryptProperties(values map[string]string) {
+	if s.cipher == nil {
+		return
+	}
+
+	for key, value := range values {
+		values[key] = s.decrypt(value)
+	}
+}
@@ -0,0 +1,113 @@
+package sqlite3_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/sqlite3"
+)
+
+func Test_FieldCipher(t *testing.T) {
+	c, err := sqlite3.NewFieldCipher("secret")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	encrypted, _ := c.Encrypt("message")
+	if encrypted == "message" {
+		t.Fatal("Expected encrypted value")
+	}
+
+	if value, _ := c.Decrypt(encrypted); value != "message" {
+		t.Errorf("Expected decrypted message, got %s", value)
+	}
+	if value, _ := c.Decrypt("plain"); value != "plain" {
+		t.Errorf("Expected unencrypted values to be unchanged, got %s", value)
+	}
+
+	other, _ := sqlite3.NewFieldCipher("other")
+	if _, err := other.Decrypt(encrypted); err == nil {
+		t.Error("Expected error for a different key")
+	}
+
+	if _, err := sqlite3.NewFieldCipher(""); err == nil {
+		t.Error("Expected error for an empty key")
+	}
+}
+
+func Test_EncryptedStore(t *testing.T) {
+	db, _ := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "encrypted.db"))
+	defer db.Close()
+	store, _ := sqlite3.NewPolicyReportStore(db)
+
+	c, _ := sqlite3.NewFieldCipher("secret")
+	store.EnableEncryption(c)
+
+	if err := store.Add(preport); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	var message string
+	if err := db.QueryRow("SELECT message FROM policy_report_result").Scan(&message); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !strings.HasPrefix(message, "enc:") {
+		t.Errorf("Expected encrypted message in the database, got %s", message)
+	}
+
+	r, _ := store.Get(preport.GetID())
+	if r.GetResults()[0].Message != preport.Results[0].Message {
+		t.Errorf("Expected decrypted message, got %s", r.GetResults()[0].Message)
+	}
+
+	if err := store.Update(preport); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	var encrypted string
+	db.QueryRow("SELECT message FROM policy_report_result").Scan(&encrypted)
+	if encrypted != message {
+		t.Error("Expected unchanged result not to be rewritten")
+	}
+}
+
+func Test_EncryptedProperties(t *testing.T) {
+	db, _ := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "encrypted.db"))
+	defer db.Close()
+	store, _ := sqlite3.NewPolicyReportStore(db)
+
+	c, _ := sqlite3.NewFieldCipher("secret")
+	store.EnableEncryption(c)
+
+	owned := fixtures.FailPodResult
+	owned.Properties = map[string]string{v1alpha2.TeamKey: "Payments", "image": "registry.internal/payments:1.0"}
+
+	if err := store.Add(&v1alpha2.PolicyReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "polr-team", Namespace: "test", CreationTimestamp: metav1.Now()},
+		Results:    []v1alpha2.PolicyReportResult{fixtures.FailResult, owned},
+	}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	var props string
+	if err := db.QueryRow("SELECT properties FROM policy_report_result WHERE id=?", owned.GetID()).Scan(&props); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if strings.Contains(props, "registry.internal") || !strings.Contains(props, `"team":"Payments"`) {
+		t.Errorf("Expected encrypted property values except the team, got %s", props)
+	}
+
+	items, err := store.FetchNamespacedResults(v1.Filter{Teams: []string{"payments"}}, pagination)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(items) != 1 || items[0].Properties["image"] != "registry.internal/payments:1.0" {
+		t.Fatalf("Expected the decrypted result of the team, got %v", items)
+	}
+}
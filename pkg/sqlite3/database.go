@@ -0,0 +1,107 @@
+package sqlite3
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+
+	"github.com/kyverno/policy-reporter/pkg/secret"
+)
+
+// Connection of an external database
+type Connection struct {
+	Host     string
+	Database string
+	Username string
+	Password secret.String
+	SSLMode  string
+	// DSN may contain the password as well
+	DSN secret.String
+}
+
+// PostgresDSN builds the connection string of a PostgreSQL database
+func (c Connection) PostgresDSN() string {
+	if c.DSN != "" {
+		return c.DSN.Value()
+	}
+
+	dsn := url.URL{
+		Scheme: "postgres",
+		Host:   c.Host,
+		Path:   "/" + c.Database,
+	}
+
+	if c.Username != "" {
+		dsn.User = url.UserPassword(c.Username, c.Password.Value())
+	}
+
+	if c.SSLMode != "" {
+		dsn.RawQuery = url.Values{"sslmode": []string{c.SSLMode}}.Encode()
+	}
+
+	return dsn.String()
+}
+
+// MySQLDSN builds the connection string of a MySQL database
+func (c Connection) MySQLDSN() string {
+	if c.DSN != "" {
+		return c.DSN.Value()
+	}
+
+	config := mysqldriver.NewConfig()
+	config.Net = "tcp"
+	config.Addr = c.Host
+	config.DBName = c.Database
+	config.User = c.Username
+	config.Passwd = c.Password.Value()
+
+	return config.FormatDSN()
+}
+
+// NewExternalDatabase opens a connection pool of an external PostgreSQL or MySQL database
+func NewExternalDatabase(dialect Dialect, connection Connection) (*sql.DB, error) {
+	switch dialect.Name() {
+	case PostgresType:
+		return sql.Open("postgres", connection.PostgresDSN())
+	case MySQLType:
+		return sql.Open("mysql", connection.MySQLDSN())
+	}
+
+	return nil, fmt.Errorf("unsupported external database: %s", dialect.Name())
+}
+
+// migrate applies all migrations of the dialect which are not yet recorded in the schema_migrations table
+func migrate(db *sql.DB, dialect Dialect) error {
+	_, err := db.Exec("CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER NOT NULL)")
+	if err != nil {
+		return err
+	}
+
+	var version int
+
+	row := db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations")
+	if err := row.Scan(&version); err != nil {
+		return err
+	}
+
+	insert, _ := dialect.Rebind("INSERT INTO schema_migrations (version) VALUES (?)", nil)
+
+	for index, migration := range dialect.Migrations() {
+		if index < version {
+			continue
+		}
+
+		if _, err := db.Exec(migration); err != nil {
+			return fmt.Errorf("failed to apply migration %d: %w", index+1, err)
+		}
+
+		if _, err := db.Exec(insert, index+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
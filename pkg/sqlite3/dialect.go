@@ -0,0 +1,598 @@
+package sqlite3
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	SQLiteType   = "sqlite"
+	PostgresType = "postgres"
+	MySQLType    = "mysql"
+)
+
+// Dialect encapsulates the differences of the supported database systems
+type Dialect interface {
+	// Name of the dialect
+	Name() string
+	// Setup configures a new database connection before the migrations are applied
+	Setup(db *sql.DB) error
+	// Migrations returns all schema migrations in the order they have to be applied
+	Migrations() []string
+	// Rebind converts SQLite style $N and ? placeholders into the placeholder style of the dialect
+	Rebind(query string, args []interface{}) (string, []interface{})
+	// JSONExtract returns an expression to select the string value of key from a JSON column
+	JSONExtract(column, key string) string
+	// Regexp returns a case sensitive regular expression match of the column against the placeholder
+	Regexp(column, placeholder string) string
+	// InsertIgnore converts an INSERT statement into an INSERT which ignores existing primary keys
+	InsertIgnore(query string) string
+	// Upsert converts an INSERT statement into an INSERT which updates the given columns of existing primary keys
+	Upsert(query, key string, columns []string) string
+}
+
+type sqlite struct{}
+
+func (d sqlite) Name() string {
+	return SQLiteType
+}
+
+func (d sqlite) Setup(db *sql.DB) error {
+	_, err := db.Exec("PRAGMA foreign_keys = ON")
+
+	return err
+}
+
+func (d sqlite) Migrations() []string {
+	return []string{
+		reportSQL,
+		resultSQL,
+		`CREATE TABLE IF NOT EXISTS policy_report_history (
+    "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+    "report_id" TEXT NOT NULL,
+    "namespace" TEXT,
+    "source" TEXT,
+    "pass" INTEGER DEFAULT 0,
+    "skip" INTEGER DEFAULT 0,
+    "warn" INTEGER DEFAULT 0,
+    "fail" INTEGER DEFAULT 0,
+    "error" INTEGER DEFAULT 0,
+    "deleted" INTEGER DEFAULT 0,
+    "timestamp" INTEGER
+  );`,
+		`CREATE TABLE IF NOT EXISTS policy_report_result_history (
+    "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+    "report_id" TEXT NOT NULL,
+    "result_id" TEXT NOT NULL,
+    "source" TEXT,
+    "policy" TEXT,
+    "rule" TEXT,
+    "resource_kind" TEXT,
+    "resource_name" TEXT,
+    "resource_namespace" TEXT,
+    "previous_status" TEXT,
+    "status" TEXT,
+    "superseded" INTEGER DEFAULT 0,
+    "timestamp" INTEGER
+  );`,
+		`CREATE INDEX IF NOT EXISTS policy_report_history_report ON policy_report_history (report_id, timestamp);`,
+		`CREATE INDEX IF NOT EXISTS policy_report_result_history_result ON policy_report_result_history (report_id, result_id, superseded);`,
+		`ALTER TABLE policy_report_result ADD COLUMN first_seen INTEGER;`,
+		`ALTER TABLE policy_report_result ADD COLUMN checksum TEXT;`,
+		`ALTER TABLE policy_report ADD COLUMN updated INTEGER;`,
+		`CREATE TABLE IF NOT EXISTS policy_report_notification (
+    "target" TEXT NOT NULL,
+    "result_id" TEXT NOT NULL,
+    "timestamp" INTEGER NOT NULL,
+    PRIMARY KEY (target, result_id)
+  );`,
+		`ALTER TABLE policy_report ADD COLUMN scope TEXT;`,
+		`CREATE TABLE IF NOT EXISTS policy_report_tombstone (
+    "report_id" TEXT NOT NULL PRIMARY KEY,
+    "type" TEXT,
+    "namespace" TEXT,
+    "name" TEXT,
+    "source" TEXT,
+    "labels" TEXT,
+    "pass" INTEGER DEFAULT 0,
+    "skip" INTEGER DEFAULT 0,
+    "warn" INTEGER DEFAULT 0,
+    "fail" INTEGER DEFAULT 0,
+    "error" INTEGER DEFAULT 0,
+    "created" INTEGER,
+    "deleted" INTEGER NOT NULL
+  );`,
+		`CREATE TABLE IF NOT EXISTS policy_report_subscription (
+    "email" TEXT NOT NULL,
+    "namespace" TEXT NOT NULL,
+    "token" TEXT NOT NULL UNIQUE,
+    "confirmed" INTEGER DEFAULT 0,
+    "created" INTEGER NOT NULL,
+    PRIMARY KEY (email, namespace)
+  );`,
+		`CREATE TABLE IF NOT EXISTS policy_report_triage (
+    "result_id" TEXT NOT NULL PRIMARY KEY,
+    "status" TEXT NOT NULL,
+    "comments" TEXT,
+    "updated" INTEGER NOT NULL,
+    "updated_by" TEXT
+  );`,
+		`CREATE TABLE IF NOT EXISTS policy_report_snooze (
+    "result_id" TEXT NOT NULL PRIMARY KEY,
+    "expires" INTEGER NOT NULL,
+    "reason" TEXT,
+    "snoozed_by" TEXT,
+    "created" INTEGER NOT NULL
+  );`,
+		`CREATE TABLE IF NOT EXISTS policy_report_snooze_event (
+    "result_id" TEXT NOT NULL,
+    "action" TEXT NOT NULL,
+    "actor" TEXT,
+    "expires" INTEGER,
+    "reason" TEXT,
+    "timestamp" INTEGER NOT NULL
+  );`,
+		`CREATE INDEX IF NOT EXISTS policy_report_snooze_event_result ON policy_report_snooze_event (result_id, timestamp);`,
+		`ALTER TABLE policy_report_result_history ADD COLUMN resource_uid TEXT;`,
+		`CREATE INDEX IF NOT EXISTS policy_report_result_history_resource ON policy_report_result_history (resource_uid, id);`,
+		`CREATE INDEX IF NOT EXISTS policy_report_result_history_name ON policy_report_result_history (resource_namespace, resource_kind, resource_name);`,
+		`CREATE INDEX IF NOT EXISTS policy_report_result_resource_uid ON policy_report_result (resource_uid);`,
+		`CREATE INDEX IF NOT EXISTS policy_report_result_resource_name ON policy_report_result (resource_namespace, resource_kind, resource_name);`,
+		// the sent notifications moved to the deduplication store
+		`DROP TABLE IF EXISTS policy_report_notification;`,
+	}
+}
+
+func (d sqlite) Rebind(query string, args []interface{}) (string, []interface{}) {
+	return query, args
+}
+
+func (d sqlite) JSONExtract(column, key string) string {
+	return fmt.Sprintf(`json_extract(%s, '$."%s"')`, column, escapeLiteral(key))
+}
+
+func (d sqlite) Regexp(column, placeholder string) string {
+	return fmt.Sprintf("%s REGEXP %s", column, placeholder)
+}
+
+func (d sqlite) InsertIgnore(query string) string {
+	return strings.Replace(query, "INSERT INTO", "INSERT OR IGNORE INTO", 1)
+}
+
+func (d sqlite) Upsert(query, key string, columns []string) string {
+	return onConflictUpdate(query, key, columns)
+}
+
+type postgres struct{}
+
+func (d postgres) Name() string {
+	return PostgresType
+}
+
+func (d postgres) Setup(db *sql.DB) error {
+	return nil
+}
+
+func (d postgres) Migrations() []string {
+	return []string{
+		`CREATE TABLE IF NOT EXISTS policy_report (
+    id TEXT NOT NULL PRIMARY KEY,
+    type TEXT,
+    namespace TEXT,
+    name TEXT NOT NULL,
+    source TEXT,
+    labels TEXT DEFAULT '{}',
+    kinds TEXT DEFAULT '[]',
+    severities TEXT DEFAULT '[]',
+    skip INTEGER DEFAULT 0,
+    pass INTEGER DEFAULT 0,
+    warn INTEGER DEFAULT 0,
+    fail INTEGER DEFAULT 0,
+    error INTEGER DEFAULT 0,
+    created BIGINT
+  );`,
+		`CREATE TABLE IF NOT EXISTS policy_report_result (
+    policy_report_id TEXT NOT NULL REFERENCES policy_report(id) ON DELETE CASCADE,
+    id TEXT NOT NULL,
+    policy TEXT,
+    rule TEXT,
+    message TEXT,
+    scored BOOLEAN,
+    status TEXT,
+    severity TEXT,
+    category TEXT,
+    source TEXT,
+    resource_api_version TEXT,
+    resource_kind TEXT,
+    resource_name TEXT,
+    resource_namespace TEXT,
+    resource_uid TEXT,
+    properties TEXT,
+    timestamp BIGINT,
+    PRIMARY KEY (policy_report_id, id)
+  );`,
+		`CREATE TABLE IF NOT EXISTS policy_report_history (
+    id BIGSERIAL PRIMARY KEY,
+    report_id TEXT NOT NULL,
+    namespace TEXT,
+    source TEXT,
+    pass INTEGER DEFAULT 0,
+    skip INTEGER DEFAULT 0,
+    warn INTEGER DEFAULT 0,
+    fail INTEGER DEFAULT 0,
+    error INTEGER DEFAULT 0,
+    deleted INTEGER DEFAULT 0,
+    timestamp BIGINT
+  );`,
+		`CREATE TABLE IF NOT EXISTS policy_report_result_history (
+    id BIGSERIAL PRIMARY KEY,
+    report_id TEXT NOT NULL,
+    result_id TEXT NOT NULL,
+    source TEXT,
+    policy TEXT,
+    rule TEXT,
+    resource_kind TEXT,
+    resource_name TEXT,
+    resource_namespace TEXT,
+    previous_status TEXT,
+    status TEXT,
+    superseded INTEGER DEFAULT 0,
+    timestamp BIGINT
+  );`,
+		`CREATE INDEX IF NOT EXISTS policy_report_history_report ON policy_report_history (report_id, timestamp);`,
+		`CREATE INDEX IF NOT EXISTS policy_report_result_history_result ON policy_report_result_history (report_id, result_id, superseded);`,
+		`ALTER TABLE policy_report_result ADD COLUMN first_seen BIGINT;`,
+		`ALTER TABLE policy_report_result ADD COLUMN checksum TEXT;`,
+		`ALTER TABLE policy_report ADD COLUMN updated BIGINT;`,
+		`CREATE TABLE IF NOT EXISTS policy_report_notification (
+    target TEXT NOT NULL,
+    result_id TEXT NOT NULL,
+    timestamp BIGINT NOT NULL,
+    PRIMARY KEY (target, result_id)
+  );`,
+		`ALTER TABLE policy_report ADD COLUMN scope TEXT;`,
+		`CREATE TABLE IF NOT EXISTS policy_report_tombstone (
+    report_id TEXT NOT NULL PRIMARY KEY,
+    type TEXT,
+    namespace TEXT,
+    name TEXT,
+    source TEXT,
+    labels TEXT,
+    pass INTEGER DEFAULT 0,
+    skip INTEGER DEFAULT 0,
+    warn INTEGER DEFAULT 0,
+    fail INTEGER DEFAULT 0,
+    error INTEGER DEFAULT 0,
+    created BIGINT,
+    deleted BIGINT NOT NULL
+  );`,
+		`CREATE TABLE IF NOT EXISTS policy_report_subscription (
+    email TEXT NOT NULL,
+    namespace TEXT NOT NULL,
+    token TEXT NOT NULL UNIQUE,
+    confirmed INTEGER DEFAULT 0,
+    created BIGINT NOT NULL,
+    PRIMARY KEY (email, namespace)
+  );`,
+		`CREATE TABLE IF NOT EXISTS policy_report_triage (
+    result_id TEXT NOT NULL PRIMARY KEY,
+    status TEXT NOT NULL,
+    comments TEXT,
+    updated BIGINT NOT NULL,
+    updated_by TEXT
+  );`,
+		`CREATE TABLE IF NOT EXISTS policy_report_snooze (
+    result_id TEXT NOT NULL PRIMARY KEY,
+    expires BIGINT NOT NULL,
+    reason TEXT,
+    snoozed_by TEXT,
+    created BIGINT NOT NULL
+  );`,
+		`CREATE TABLE IF NOT EXISTS policy_report_snooze_event (
+    result_id TEXT NOT NULL,
+    action TEXT NOT NULL,
+    actor TEXT,
+    expires BIGINT,
+    reason TEXT,
+    timestamp BIGINT NOT NULL
+  );`,
+		`CREATE INDEX IF NOT EXISTS policy_report_snooze_event_result ON policy_report_snooze_event (result_id, timestamp);`,
+		`ALTER TABLE policy_report_result_history ADD COLUMN resource_uid TEXT;`,
+		`CREATE INDEX IF NOT EXISTS policy_report_result_history_resource ON policy_report_result_history (resource_uid, id);`,
+		`CREATE INDEX IF NOT EXISTS policy_report_result_history_name ON policy_report_result_history (resource_namespace, resource_kind, resource_name);`,
+		`CREATE INDEX IF NOT EXISTS policy_report_result_resource_uid ON policy_report_result (resource_uid);`,
+		`CREATE INDEX IF NOT EXISTS policy_report_result_resource_name ON policy_report_result (resource_namespace, resource_kind, resource_name);`,
+		// the sent notifications moved to the deduplication store
+		`DROP TABLE IF EXISTS policy_report_notification;`,
+	}
+}
+
+func (d postgres) Rebind(query string, args []interface{}) (string, []interface{}) {
+	return rebind(query, args, func(index int) string { return "$" + strconv.Itoa(index) }, false)
+}
+
+func (d postgres) JSONExtract(column, key string) string {
+	return fmt.Sprintf(`(%s::jsonb ->> '%s')`, column, escapeLiteral(key))
+}
+
+func (d postgres) Regexp(column, placeholder string) string {
+	return fmt.Sprintf("%s ~ %s", column, placeholder)
+}
+
+func (d postgres) InsertIgnore(query string) string {
+	return query + " ON CONFLICT DO NOTHING"
+}
+
+func (d postgres) Upsert(query, key string, columns []string) string {
+	return onConflictUpdate(query, key, columns)
+}
+
+type mysql struct{}
+
+func (d mysql) Name() string {
+	return MySQLType
+}
+
+func (d mysql) Setup(db *sql.DB) error {
+	return nil
+}
+
+func (d mysql) Migrations() []string {
+	return []string{
+		`CREATE TABLE IF NOT EXISTS policy_report (
+    id VARCHAR(255) NOT NULL PRIMARY KEY,
+    type VARCHAR(255),
+    namespace VARCHAR(255),
+    name VARCHAR(255) NOT NULL,
+    source VARCHAR(255),
+    labels TEXT,
+    kinds TEXT,
+    severities TEXT,
+    skip INTEGER DEFAULT 0,
+    pass INTEGER DEFAULT 0,
+    warn INTEGER DEFAULT 0,
+    fail INTEGER DEFAULT 0,
+    error INTEGER DEFAULT 0,
+    created BIGINT
+  );`,
+		`CREATE TABLE IF NOT EXISTS policy_report_result (
+    policy_report_id VARCHAR(255) NOT NULL,
+    id VARCHAR(255) NOT NULL,
+    policy VARCHAR(255),
+    rule VARCHAR(255),
+    message TEXT,
+    scored BOOLEAN,
+    status VARCHAR(255),
+    severity VARCHAR(255),
+    category VARCHAR(255),
+    source VARCHAR(255),
+    resource_api_version VARCHAR(255),
+    resource_kind VARCHAR(255),
+    resource_name VARCHAR(255),
+    resource_namespace VARCHAR(255),
+    resource_uid VARCHAR(255),
+    properties TEXT,
+    timestamp BIGINT,
+    PRIMARY KEY (policy_report_id, id),
+    FOREIGN KEY (policy_report_id) REFERENCES policy_report(id) ON DELETE CASCADE
+  );`,
+		`CREATE TABLE IF NOT EXISTS policy_report_history (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    report_id VARCHAR(255) NOT NULL,
+    namespace VARCHAR(255),
+    source VARCHAR(255),
+    pass INTEGER DEFAULT 0,
+    skip INTEGER DEFAULT 0,
+    warn INTEGER DEFAULT 0,
+    fail INTEGER DEFAULT 0,
+    error INTEGER DEFAULT 0,
+    deleted INTEGER DEFAULT 0,
+    timestamp BIGINT,
+    INDEX policy_report_history_report (report_id, timestamp)
+  );`,
+		`CREATE TABLE IF NOT EXISTS policy_report_result_history (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    report_id VARCHAR(255) NOT NULL,
+    result_id VARCHAR(255) NOT NULL,
+    source VARCHAR(255),
+    policy VARCHAR(255),
+    rule VARCHAR(255),
+    resource_kind VARCHAR(255),
+    resource_name VARCHAR(255),
+    resource_namespace VARCHAR(255),
+    previous_status VARCHAR(255),
+    status VARCHAR(255),
+    superseded INTEGER DEFAULT 0,
+    timestamp BIGINT,
+    INDEX policy_report_result_history_result (report_id, result_id, superseded)
+  );`,
+		`ALTER TABLE policy_report_result ADD COLUMN first_seen BIGINT;`,
+		`ALTER TABLE policy_report_result ADD COLUMN checksum VARCHAR(16);`,
+		`ALTER TABLE policy_report ADD COLUMN updated BIGINT;`,
+		`CREATE TABLE IF NOT EXISTS policy_report_notification (
+    target VARCHAR(255) NOT NULL,
+    result_id VARCHAR(255) NOT NULL,
+    timestamp BIGINT NOT NULL,
+    PRIMARY KEY (target, result_id)
+  );`,
+		`ALTER TABLE policy_report ADD COLUMN scope TEXT;`,
+		`CREATE TABLE IF NOT EXISTS policy_report_tombstone (
+    report_id VARCHAR(255) NOT NULL PRIMARY KEY,
+    type VARCHAR(255),
+    namespace VARCHAR(255),
+    name VARCHAR(255),
+    source VARCHAR(255),
+    labels TEXT,
+    pass INTEGER DEFAULT 0,
+    skip INTEGER DEFAULT 0,
+    warn INTEGER DEFAULT 0,
+    fail INTEGER DEFAULT 0,
+    error INTEGER DEFAULT 0,
+    created BIGINT,
+    deleted BIGINT NOT NULL,
+    INDEX policy_report_tombstone_deleted (deleted)
+  );`,
+		`CREATE TABLE IF NOT EXISTS policy_report_subscription (
+    email VARCHAR(255) NOT NULL,
+    namespace VARCHAR(255) NOT NULL,
+    token VARCHAR(64) NOT NULL UNIQUE,
+    confirmed INTEGER DEFAULT 0,
+    created BIGINT NOT NULL,
+    PRIMARY KEY (email, namespace)
+  );`,
+		`CREATE TABLE IF NOT EXISTS policy_report_triage (
+    result_id VARCHAR(255) NOT NULL PRIMARY KEY,
+    status VARCHAR(32) NOT NULL,
+    comments TEXT,
+    updated BIGINT NOT NULL,
+    updated_by VARCHAR(255)
+  );`,
+		`CREATE TABLE IF NOT EXISTS policy_report_snooze (
+    result_id VARCHAR(255) NOT NULL PRIMARY KEY,
+    expires BIGINT NOT NULL,
+    reason TEXT,
+    snoozed_by VARCHAR(255),
+    created BIGINT NOT NULL
+  );`,
+		`CREATE TABLE IF NOT EXISTS policy_report_snooze_event (
+    result_id VARCHAR(255) NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    actor VARCHAR(255),
+    expires BIGINT,
+    reason TEXT,
+    timestamp BIGINT NOT NULL,
+    INDEX policy_report_snooze_event_result (result_id, timestamp)
+  );`,
+		`ALTER TABLE policy_report_result_history ADD COLUMN resource_uid VARCHAR(255);`,
+		`CREATE INDEX policy_report_result_history_resource ON policy_report_result_history (resource_uid, id);`,
+		`CREATE INDEX policy_report_result_history_name ON policy_report_result_history (resource_namespace, resource_kind, resource_name);`,
+		`CREATE INDEX policy_report_result_resource_uid ON policy_report_result (resource_uid);`,
+		`CREATE INDEX policy_report_result_resource_name ON policy_report_result (resource_namespace, resource_kind, resource_name);`,
+		// the sent notifications moved to the deduplication store
+		`DROP TABLE IF EXISTS policy_report_notification;`,
+	}
+}
+
+func (d mysql) Rebind(query string, args []interface{}) (string, []interface{}) {
+	return rebind(query, args, func(_ int) string { return "?" }, true)
+}
+
+func (d mysql) JSONExtract(column, key string) string {
+	return fmt.Sprintf(`JSON_UNQUOTE(JSON_EXTRACT(%s, '$."%s"'))`, column, escapeLiteral(key))
+}
+
+func (d mysql) Regexp(column, placeholder string) string {
+	return fmt.Sprintf("REGEXP_LIKE(%s, %s, 'c')", column, placeholder)
+}
+
+func (d mysql) InsertIgnore(query string) string {
+	return strings.Replace(query, "INSERT INTO", "INSERT IGNORE INTO", 1)
+}
+
+func (d mysql) Upsert(query, key string, columns []string) string {
+	updates := make([]string, 0, len(columns))
+	for _, column := range columns {
+		updates = append(updates, fmt.Sprintf("%s=VALUES(%s)", column, column))
+	}
+
+	return query + " ON DUPLICATE KEY UPDATE " + strings.Join(updates, ", ")
+}
+
+var (
+	// SQLite dialect, used for the embedded database
+	SQLite Dialect = sqlite{}
+	// PostgreSQL dialect
+	PostgreSQL Dialect = postgres{}
+	// MySQL dialect
+	MySQL Dialect = mysql{}
+)
+
+// DialectFor returns the Dialect of the given database type, defaults to SQLite
+func DialectFor(dbType string) Dialect {
+	switch strings.ToLower(dbType) {
+	case PostgresType, "postgresql":
+		return PostgreSQL
+	case MySQLType, "mariadb":
+		return MySQL
+	default:
+		return SQLite
+	}
+}
+
+func onConflictUpdate(query, key string, columns []string) string {
+	updates := make([]string, 0, len(columns))
+	for _, column := range columns {
+		updates = append(updates, fmt.Sprintf("%s=excluded.%s", column, column))
+	}
+
+	return fmt.Sprintf("%s ON CONFLICT(%s) DO UPDATE SET %s", query, key, strings.Join(updates, ", "))
+}
+
+// rebind replaces all placeholders outside of string literals with the SQLite parameter semantics:
+// each distinct $N gets the next free index on its first occurrence, each ? gets its own next free index.
+// With expand, args are repeated for each occurrence of a placeholder, as required by MySQL.
+func rebind(query string, args []interface{}, placeholder func(index int) string, expand bool) (string, []interface{}) {
+	var builder strings.Builder
+	builder.Grow(len(query))
+
+	named := make(map[string]int)
+	expanded := make([]interface{}, 0, len(args))
+	counter := 0
+	quoted := false
+
+	bind := func(index int) {
+		builder.WriteString(placeholder(index))
+		if expand && index <= len(args) {
+			expanded = append(expanded, args[index-1])
+		}
+	}
+
+	for i := 0; i < len(query); i++ {
+		char := query[i]
+
+		switch {
+		case char == '\'':
+			quoted = !quoted
+			builder.WriteByte(char)
+		case quoted:
+			builder.WriteByte(char)
+		case char == '?':
+			counter++
+			bind(counter)
+		case char == '$' && i+1 < len(query) && isDigit(query[i+1]):
+			end := i + 1
+			for end < len(query) && isDigit(query[end]) {
+				end++
+			}
+
+			name := query[i+1 : end]
+			index, ok := named[name]
+			if !ok {
+				counter++
+				index = counter
+				named[name] = index
+			}
+
+			bind(index)
+			i = end - 1
+		default:
+			builder.WriteByte(char)
+		}
+	}
+
+	if !expand {
+		return builder.String(), args
+	}
+
+	return builder.String(), expanded
+}
+
+func isDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
+
+func escapeLiteral(value string) string {
+	return strings.ReplaceAll(value, "'", "''")
+}
@@ -0,0 +1,30 @@
+//go:build cgo
+
+package sqlite3
+
+// the SQLite driver requires cgo, binaries built without cgo only support external databases and the in-memory store
+import (
+	"database/sql"
+
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+func init() {
+	sql.Register(sqliteDriver, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("regexp", regexpMatch, true)
+		},
+	})
+}
+
+// regexpMatch implements the REGEXP operator of SQLite, "X REGEXP Y" calls regexp(Y, X)
+func regexpMatch(expr, value string) (bool, error) {
+	re, err := validate.Compile(expr)
+	if err != nil {
+		return false, err
+	}
+
+	return re.MatchString(value), nil
+}
@@ -0,0 +1,58 @@
+package sqlite3_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/sqlite3"
+)
+
+func Test_FetchUnresolvedResults(t *testing.T) {
+	db, _ := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "escalation.db"))
+	defer db.Close()
+	store, _ := sqlite3.NewPolicyReportStore(db)
+
+	if err := store.Add(preport); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	results, err := store.FetchUnresolvedResults(time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 unresolved result, got %d", len(results))
+	}
+
+	res := results[0]
+	if res.Report.GetName() != "polr-test" || res.Report.GetNamespace() != "test" || res.Report.GetLabels()["app"] != "policy-reporter" {
+		t.Errorf("Unexpected report of the result: %s/%s %v", res.Report.GetNamespace(), res.Report.GetName(), res.Report.GetLabels())
+	}
+	if res.Result.GetID() != preport.Results[0].GetID() {
+		t.Errorf("Unexpected result %s", res.Result.GetID())
+	}
+
+	if err := store.Update(ureport); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	updated, err := store.FetchUnresolvedResults(time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(updated) != 1 {
+		t.Fatalf("Expected passed results to be ignored, got %d results", len(updated))
+	}
+	if !updated[0].FirstSeen.Equal(res.FirstSeen) {
+		t.Errorf("Expected first seen to be kept on update, got %s instead of %s", updated[0].FirstSeen, res.FirstSeen)
+	}
+
+	results, err = store.FetchUnresolvedResults(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Expected no results first seen an hour ago, got %d", len(results))
+	}
+}
@@ -0,0 +1,78 @@
+package sqlite3
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kyverno/policy-reporter/pkg/query"
+)
+
+var expressionFields = map[string]string{
+	"namespace": "result.resource_namespace",
+	"kind":      "result.resource_kind",
+	"name":      "result.resource_name",
+	"policy":    "result.policy",
+	"rule":      "result.rule",
+	"severity":  "result.severity",
+	"status":    "result.status",
+	"category":  "result.category",
+	"source":    "result.source",
+	"message":   "result.message",
+	"timestamp": "result.timestamp",
+}
+
+var fullTextFields = []string{"result.message", "result.policy", "result.rule", "result.resource_name", "result.resource_namespace"}
+
+// expressionWhere translates a parsed filter expression into a where condition with numbered placeholders starting after argCounter
+func expressionWhere(node query.Node, argCounter int) (int, string, []interface{}) {
+	switch n := node.(type) {
+	case *query.Binary:
+		argCounter, left, leftArgs := expressionWhere(n.Left, argCounter)
+		argCounter, right, rightArgs := expressionWhere(n.Right, argCounter)
+
+		return argCounter, fmt.Sprintf("(%s %s %s)", left, n.Operator, right), append(leftArgs, rightArgs...)
+	case *query.Not:
+		argCounter, where, args := expressionWhere(n.Node, argCounter)
+
+		return argCounter, fmt.Sprintf("NOT %s", where), args
+	case *query.Term:
+		argCounter++
+
+		conditions := make([]string, 0, len(fullTextFields))
+		for _, field := range fullTextFields {
+			conditions = append(conditions, fmt.Sprintf("LOWER(%s) LIKE $%d", field, argCounter))
+		}
+
+		return argCounter, "(" + strings.Join(conditions, " OR ") + ")", []interface{}{"%" + strings.ToLower(n.Value) + "%"}
+	case *query.Comparison:
+		argCounter++
+		column := expressionFields[n.Field]
+
+		switch {
+		case n.Field == "severity" && isOrdering(n.Operator):
+			return argCounter, fmt.Sprintf("%s %s $%d", severityRank(), n.Operator, argCounter), []interface{}{query.SeverityRank(n.Value) + 1}
+		case n.Field == "timestamp":
+			timestamp, _ := strconv.ParseInt(n.Value, 10, 64)
+
+			return argCounter, fmt.Sprintf("%s %s $%d", column, n.Operator, argCounter), []interface{}{timestamp}
+		case n.Operator == "~":
+			return argCounter, fmt.Sprintf("LOWER(%s) LIKE $%d", column, argCounter), []interface{}{wildcardPattern(n.Value)}
+		case n.Operator == "!~":
+			return argCounter, fmt.Sprintf("LOWER(%s) NOT LIKE $%d", column, argCounter), []interface{}{wildcardPattern(n.Value)}
+		}
+
+		return argCounter, fmt.Sprintf("LOWER(%s) %s $%d", column, n.Operator, argCounter), []interface{}{strings.ToLower(n.Value)}
+	}
+
+	return argCounter, "1 = 1", []interface{}{}
+}
+
+func isOrdering(operator string) bool {
+	return operator == ">" || operator == ">=" || operator == "<" || operator == "<="
+}
+
+// wildcardPattern converts * and ? wildcards into a LIKE pattern
+func wildcardPattern(value string) string {
+	return strings.NewReplacer("*", "%", "?", "_").Replace(strings.ToLower(value))
+}
@@ -0,0 +1,599 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	api "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+var historyStatus = []string{
+	v1alpha2.StatusPass,
+	v1alpha2.StatusWarn,
+	v1alpha2.StatusFail,
+	v1alpha2.StatusError,
+	v1alpha2.StatusSkip,
+}
+
+type snapshot struct {
+	reportID  string
+	namespace string
+	summary   v1alpha2.PolicyReportSummary
+	deleted   bool
+	timestamp int64
+}
+
+type transition struct {
+	resultID  string
+	source    string
+	policy    string
+	rule      string
+	kind      string
+	name      string
+	namespace string
+	uid       string
+	status    string
+}
+
+// EnableHistory records summary snapshots and result transitions for each change of a PolicyReport
+func (s *policyReportStore) EnableHistory() {
+	s.history = true
+}
+
+// PruneHistory removes all history entries older than the given time,
+// the last snapshot of each existing report is kept as baseline for later queries
+func (s *policyReportStore) PruneHistory(before time.Time) error {
+	rows, err := s.query(`
+    SELECT report.id FROM policy_report_history as report
+    WHERE report.timestamp < $1 AND (report.deleted = 1 OR EXISTS (
+      SELECT 1 FROM policy_report_history as newer WHERE newer.report_id = report.report_id AND newer.id > report.id AND newer.timestamp < $1
+    ))`, before.Unix())
+	if err != nil {
+		return err
+	}
+
+	ids := make([]interface{}, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	for i := 0; i < len(ids); i += 500 {
+		end := i + 500
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", end-i), ",")
+		if _, err := s.exec("DELETE FROM policy_report_history WHERE id IN ("+placeholders+")", ids[i:end]...); err != nil {
+			return err
+		}
+	}
+
+	_, err = s.exec("DELETE FROM policy_report_result_history WHERE timestamp < ? AND superseded = 1", before.Unix())
+
+	return err
+}
+
+// RunHistoryPruning removes history entries older than the retention in the given interval until the context is canceled
+func RunHistoryPruning(ctx context.Context, store PolicyReportStore, retention, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := store.PruneHistory(time.Now().Add(-retention)); err != nil {
+			logger.Errorf("failed to prune PolicyReport history: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// FetchStatusHistory returns the result counts per namespace and status at the end of each interval of the query
+func (s *policyReportStore) FetchStatusHistory(filter api.Filter, query api.HistoryQuery) ([]*api.StatusHistory, error) {
+	list := make([]*api.StatusHistory, 0)
+	if query.Interval <= 0 || !query.Since.Before(query.Until) {
+		return list, nil
+	}
+
+	where, args := s.generateFilterWhere(api.Filter{Namespaces: filter.Namespaces, Sources: filter.Sources}, []string{"report_namespaces", "report_sources"})
+	if len(where) > 0 {
+		where = " AND " + where
+	}
+
+	// placeholders are bound in the order of their first occurrence, the time range follows the filter arguments
+	since := fmt.Sprintf("$%d", len(args)+1000)
+	until := fmt.Sprintf("$%d", len(args)+1001)
+
+	snapshots, err := s.fetchSnapshots(`
+    SELECT report.report_id, report.namespace, report.pass, report.skip, report.warn, report.fail, report.error, report.deleted, report.timestamp, report.id
+    FROM policy_report_history as report
+    WHERE 1=1`+where+` AND report.timestamp < `+since+` AND NOT EXISTS (
+      SELECT 1 FROM policy_report_history as newer WHERE newer.report_id = report.report_id AND newer.id > report.id AND newer.timestamp < `+since+`
+    )
+    UNION ALL
+    SELECT report.report_id, report.namespace, report.pass, report.skip, report.warn, report.fail, report.error, report.deleted, report.timestamp, report.id
+    FROM policy_report_history as report
+    WHERE 1=1`+where+` AND report.timestamp >= `+since+` AND report.timestamp <= `+until+`
+    ORDER BY timestamp ASC, id ASC`, append(args, query.Since.Unix(), query.Until.Unix())...)
+	if err != nil {
+		return list, err
+	}
+
+	status := historyStatus
+	if len(filter.Status) > 0 {
+		status = filter.Status
+	}
+
+	current := make(map[string]snapshot)
+	series := make(map[string]map[string]*api.StatusHistory)
+	namespaces := make([]string, 0)
+	index := 0
+
+	for start := query.Since; start.Before(query.Until); start = start.Add(query.Interval) {
+		end := start.Add(query.Interval)
+		if end.After(query.Until) {
+			end = query.Until
+		}
+
+		for ; index < len(snapshots) && snapshots[index].timestamp <= end.Unix(); index++ {
+			if snapshots[index].deleted {
+				delete(current, snapshots[index].reportID)
+				continue
+			}
+
+			current[snapshots[index].reportID] = snapshots[index]
+		}
+
+		counts := make(map[string]map[string]int)
+		for _, snap := range current {
+			if _, ok := counts[snap.namespace]; !ok {
+				counts[snap.namespace] = make(map[string]int)
+			}
+
+			counts[snap.namespace][v1alpha2.StatusPass] += snap.summary.Pass
+			counts[snap.namespace][v1alpha2.StatusWarn] += snap.summary.Warn
+			counts[snap.namespace][v1alpha2.StatusFail] += snap.summary.Fail
+			counts[snap.namespace][v1alpha2.StatusError] += snap.summary.Error
+			counts[snap.namespace][v1alpha2.StatusSkip] += snap.summary.Skip
+
+			if _, ok := series[snap.namespace]; !ok {
+				series[snap.namespace] = make(map[string]*api.StatusHistory)
+				namespaces = append(namespaces, snap.namespace)
+
+				for _, st := range status {
+					series[snap.namespace][st] = &api.StatusHistory{Namespace: snap.namespace, Status: st, Items: make([]api.HistoryItem, 0)}
+				}
+			}
+		}
+
+		for namespace, statusSeries := range series {
+			for st, history := range statusSeries {
+				history.Items = append(history.Items, api.HistoryItem{Timestamp: end.Unix(), Count: counts[namespace][strings.ToLower(st)]})
+			}
+		}
+	}
+
+	sort.Strings(namespaces)
+	for _, namespace := range namespaces {
+		for _, st := range status {
+			history := series[namespace][st]
+			history.Items = fillHistory(history.Items, query)
+
+			list = append(list, history)
+		}
+	}
+
+	return list, nil
+}
+
+// FetchResultTransitions returns all status changes of results within the query
+func (s *policyReportStore) FetchResultTransitions(filter api.Filter, query api.HistoryQuery, pagination api.Pagination) ([]*api.ResultTransition, error) {
+	list := make([]*api.ResultTransition, 0)
+
+	filter.Search = ""
+	where, args := s.generateFilterWhere(filter, []string{"namespaces", "sources", "policies", "rules", "kinds", "resources", "status"})
+	if len(where) > 0 {
+		where = " AND " + where
+	}
+
+	since := fmt.Sprintf("$%d", len(args)+1000)
+	until := fmt.Sprintf("$%d", len(args)+1001)
+
+	rows, err := s.query(`
+    SELECT result.report_id, result.result_id, result.source, result.policy, result.rule, result.resource_kind, result.resource_name, result.resource_namespace, COALESCE(result.resource_uid, ''), result.previous_status, result.status, result.timestamp
+    FROM policy_report_result_history as result
+    WHERE 1=1`+where+` AND result.timestamp >= `+since+` AND result.timestamp <= `+until+` `+generatePagination(pagination), append(args, query.Since.Unix(), query.Until.Unix())...)
+	if err != nil {
+		return list, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t := &api.ResultTransition{}
+
+		err := rows.Scan(&t.ReportID, &t.ResultID, &t.Source, &t.Policy, &t.Rule, &t.Kind, &t.Name, &t.Namespace, &t.UID, &t.PreviousStatus, &t.Status, &t.Timestamp)
+		if err != nil {
+			return list, err
+		}
+
+		list = append(list, t)
+	}
+
+	return list, nil
+}
+
+// FetchResultDiff compares the status of each result of the namespace at from and to, based on the recorded transitions
+func (s *policyReportStore) FetchResultDiff(namespace string, from, to time.Time) (*v2.ResultDiff, error) {
+	diff := &v2.ResultDiff{
+		Namespace:    namespace,
+		From:         from.Unix(),
+		To:           to.Unix(),
+		Added:        make([]v2.DiffResult, 0),
+		Resolved:     make([]v2.DiffResult, 0),
+		StillFailing: make([]v2.DiffResult, 0),
+	}
+
+	rows, err := s.query(`
+    SELECT report_id, result_id, source, policy, rule, resource_kind, resource_name, status, timestamp
+    FROM policy_report_result_history
+    WHERE resource_namespace=$1 AND timestamp <= $2
+    ORDER BY id ASC`, namespace, to.Unix())
+	if err != nil {
+		return diff, err
+	}
+	defer rows.Close()
+
+	keys := make([]string, 0)
+	before := make(map[string]string)
+	after := make(map[string]v2.DiffResult)
+
+	for rows.Next() {
+		r := v2.DiffResult{}
+		if err := rows.Scan(&r.ReportID, &r.ResultID, &r.Source, &r.Policy, &r.Rule, &r.Kind, &r.Name, &r.Status, &r.Timestamp); err != nil {
+			return diff, err
+		}
+
+		key := r.ReportID + "/" + r.ResultID
+		if _, ok := after[key]; !ok {
+			keys = append(keys, key)
+		}
+		if r.Timestamp <= diff.From {
+			before[key] = r.Status
+		}
+
+		after[key] = r
+	}
+	if err := rows.Err(); err != nil {
+		return diff, err
+	}
+
+	for _, key := range keys {
+		r := after[key]
+		r.PreviousStatus = before[key]
+
+		switch failing, failed := isFailing(r.Status), isFailing(r.PreviousStatus); {
+		case failing && failed:
+			diff.StillFailing = append(diff.StillFailing, r)
+		case failing:
+			diff.Added = append(diff.Added, r)
+		case failed:
+			diff.Resolved = append(diff.Resolved, r)
+		}
+	}
+
+	deleted, err := s.fetchDeletedReports(namespace, diff.From, diff.To)
+	if err != nil {
+		return diff, err
+	}
+
+	diff.Deleted = deleted
+
+	return diff, nil
+}
+
+// FetchResourceHistory returns the recorded transitions of the resource grouped by UID, oldest first.
+// Transitions recorded before the UID was tracked are grouped with an empty UID
+func (s *policyReportStore) FetchResourceHistory(query v2.ResourceQuery) ([]*v2.ResourceHistory, error) {
+	list := make([]*v2.ResourceHistory, 0)
+
+	where := "resource_uid=$1"
+	args := []interface{}{query.UID}
+	if query.UID == "" {
+		where = "resource_namespace=$1 AND resource_kind=$2 AND resource_name=$3"
+		args = []interface{}{query.Namespace, query.Kind, query.Name}
+	} else if query.Namespace != "" {
+		where += " AND resource_namespace=$2"
+		args = append(args, query.Namespace)
+	}
+
+	rows, err := s.query(`
+    SELECT report_id, result_id, source, policy, rule, resource_kind, resource_name, resource_namespace, COALESCE(resource_uid, ''), previous_status, status, timestamp
+    FROM policy_report_result_history
+    WHERE `+where+`
+    ORDER BY id ASC`, args...)
+	if err != nil {
+		return list, err
+	}
+	defer rows.Close()
+
+	resources := make(map[string]*v2.ResourceHistory)
+
+	for rows.Next() {
+		t := &api.ResultTransition{}
+		if err := rows.Scan(&t.ReportID, &t.ResultID, &t.Source, &t.Policy, &t.Rule, &t.Kind, &t.Name, &t.Namespace, &t.UID, &t.PreviousStatus, &t.Status, &t.Timestamp); err != nil {
+			return list, err
+		}
+
+		resource, ok := resources[t.UID]
+		if !ok {
+			resource = &v2.ResourceHistory{UID: t.UID, Namespace: t.Namespace, FirstSeen: t.Timestamp, Transitions: make([]*api.ResultTransition, 0)}
+			resources[t.UID] = resource
+			list = append(list, resource)
+		}
+
+		// the latest name is kept, so a resource found by UID is returned with its current name
+		resource.Kind = t.Kind
+		resource.Name = t.Name
+		resource.LastSeen = t.Timestamp
+		resource.Transitions = append(resource.Transitions, t)
+	}
+
+	return list, rows.Err()
+}
+
+func isFailing(status string) bool {
+	return status == v1alpha2.StatusFail || status == v1alpha2.StatusWarn || status == v1alpha2.StatusError
+}
+
+func (s *policyReportStore) recordHistory(r v1alpha2.ReportInterface) error {
+	if !s.history {
+		return nil
+	}
+
+	now := time.Now().Unix()
+
+	err := s.recordSnapshot(snapshot{
+		reportID:  r.GetID(),
+		namespace: r.GetNamespace(),
+		summary:   r.GetSummary(),
+		timestamp: now,
+	}, r.GetSource())
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]transition, len(r.GetResults()))
+	for _, result := range r.GetResults() {
+		t := transition{
+			resultID:  result.GetID(),
+			source:    result.Source,
+			policy:    result.Policy,
+			rule:      result.Rule,
+			namespace: r.GetNamespace(),
+			status:    string(result.Result),
+		}
+
+		if res := result.GetResource(); res != nil {
+			t.kind = res.Kind
+			t.name = res.Name
+			t.uid = string(res.UID)
+		} else if scope := r.GetScope(); scope != nil {
+			t.kind = scope.Kind
+			t.name = scope.Name
+			t.uid = string(scope.UID)
+		}
+
+		current[t.resultID] = t
+	}
+
+	return s.recordTransitions(r.GetID(), current, now)
+}
+
+func (s *policyReportStore) recordRemoval(id string) error {
+	if !s.history {
+		return nil
+	}
+
+	now := time.Now().Unix()
+
+	var namespace, source string
+
+	err := s.queryRow("SELECT namespace, source FROM policy_report WHERE id=$1", id).Scan(&namespace, &source)
+	if err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	err = s.recordSnapshot(snapshot{reportID: id, namespace: namespace, deleted: true, timestamp: now}, source)
+	if err != nil {
+		return err
+	}
+
+	return s.recordTransitions(id, map[string]transition{}, now)
+}
+
+func (s *policyReportStore) recordSnapshot(snap snapshot, source string) error {
+	if !snap.deleted {
+		var latest v1alpha2.PolicyReportSummary
+		var deleted int
+
+		err := s.queryRow("SELECT pass, skip, warn, fail, error, deleted FROM policy_report_history WHERE report_id=$1 ORDER BY id DESC LIMIT 1", snap.reportID).
+			Scan(&latest.Pass, &latest.Skip, &latest.Warn, &latest.Fail, &latest.Error, &deleted)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		if err == nil && deleted == 0 && latest == snap.summary {
+			return nil
+		}
+	}
+
+	deleted := 0
+	if snap.deleted {
+		deleted = 1
+	}
+
+	_, err := s.exec(
+		"INSERT INTO policy_report_history(report_id, namespace, source, pass, skip, warn, fail, error, deleted, timestamp) values(?,?,?,?,?,?,?,?,?,?)",
+		snap.reportID,
+		snap.namespace,
+		source,
+		snap.summary.Pass,
+		snap.summary.Skip,
+		snap.summary.Warn,
+		snap.summary.Fail,
+		snap.summary.Error,
+		deleted,
+		snap.timestamp,
+	)
+
+	return err
+}
+
+func (s *policyReportStore) recordTransitions(reportID string, current map[string]transition, timestamp int64) error {
+	rows, err := s.query(`
+    SELECT result_id, source, policy, rule, resource_kind, resource_name, resource_namespace, COALESCE(resource_uid, ''), status
+    FROM policy_report_result_history
+    WHERE report_id=$1 AND superseded = 0`, reportID)
+	if err != nil {
+		return err
+	}
+
+	previous := make(map[string]transition)
+	for rows.Next() {
+		t := transition{}
+		if err := rows.Scan(&t.resultID, &t.source, &t.policy, &t.rule, &t.kind, &t.name, &t.namespace, &t.uid, &t.status); err != nil {
+			rows.Close()
+			return err
+		}
+
+		previous[t.resultID] = t
+	}
+	rows.Close()
+
+	changes := make([]transition, 0)
+	for id, t := range current {
+		// a recreated resource keeps its result IDs, so the change of the UID is recorded as well
+		if prev, ok := previous[id]; !ok || prev.status != t.status || prev.uid != t.uid {
+			changes = append(changes, t)
+		}
+	}
+
+	for id, t := range previous {
+		if _, ok := current[id]; !ok {
+			t.status = ""
+			changes = append(changes, t)
+		}
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, t := range changes {
+		superseded := 0
+		if t.status == "" {
+			superseded = 1
+		}
+
+		if _, err := s.execTx(tx, "UPDATE policy_report_result_history SET superseded = 1 WHERE report_id=? AND result_id=? AND superseded = 0", reportID, t.resultID); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		_, err := s.execTx(
+			tx,
+			"INSERT INTO policy_report_result_history(report_id, result_id, source, policy, rule, resource_kind, resource_name, resource_namespace, resource_uid, previous_status, status, superseded, timestamp) values(?,?,?,?,?,?,?,?,?,?,?,?,?)",
+			reportID,
+			t.resultID,
+			t.source,
+			t.policy,
+			t.rule,
+			t.kind,
+			t.name,
+			t.namespace,
+			t.uid,
+			previous[t.resultID].status,
+			t.status,
+			superseded,
+			timestamp,
+		)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *policyReportStore) fetchSnapshots(query string, args ...interface{}) ([]snapshot, error) {
+	list := make([]snapshot, 0)
+
+	rows, err := s.query(query, args...)
+	if err != nil {
+		return list, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var deleted int
+		var id int64
+		snap := snapshot{}
+
+		err := rows.Scan(&snap.reportID, &snap.namespace, &snap.summary.Pass, &snap.summary.Skip, &snap.summary.Warn, &snap.summary.Fail, &snap.summary.Error, &deleted, &snap.timestamp, &id)
+		if err != nil {
+			return list, err
+		}
+
+		snap.deleted = deleted == 1
+
+		list = append(list, snap)
+	}
+
+	return list, nil
+}
+
+// fillHistory prepends empty items for intervals before the first snapshot of a namespace
+func fillHistory(items []api.HistoryItem, query api.HistoryQuery) []api.HistoryItem {
+	buckets := make([]api.HistoryItem, 0, len(items))
+
+	for start := query.Since; start.Before(query.Until); start = start.Add(query.Interval) {
+		end := start.Add(query.Interval)
+		if end.After(query.Until) {
+			end = query.Until
+		}
+
+		buckets = append(buckets, api.HistoryItem{Timestamp: end.Unix()})
+	}
+
+	offset := len(buckets) - len(items)
+	for i, item := range items {
+		buckets[offset+i] = item
+	}
+
+	return buckets
+}
@@ -0,0 +1,209 @@
+package sqlite3_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/sqlite3"
+)
+
+func Test_PolicyReportHistory(t *testing.T) {
+	db, _ := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "history.db"))
+	defer db.Close()
+	store, _ := sqlite3.NewPolicyReportStore(db)
+	store.EnableHistory()
+
+	query := func() v1.HistoryQuery {
+		return v1.HistoryQuery{Since: time.Now().Add(-2 * time.Hour), Until: time.Now().Add(time.Minute), Interval: time.Hour}
+	}
+
+	transitionPagination := v1.Pagination{SortBy: []string{"id"}, Direction: "ASC"}
+
+	t.Run("Record Snapshots and Transitions", func(t *testing.T) {
+		if err := store.Add(preport); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if err := store.Update(ureport); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		history, err := store.FetchStatusHistory(v1.Filter{Namespaces: []string{"test"}, Status: []string{"fail", "pass"}}, query())
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(history) != 2 {
+			t.Fatalf("Expected 2 series for fail and pass, got %d", len(history))
+		}
+		if len(history[0].Items) != 3 {
+			t.Fatalf("Expected 3 intervals, got %d", len(history[0].Items))
+		}
+		if history[0].Status != "fail" || history[0].Items[2].Count != 1 {
+			t.Errorf("Expected 1 fail result in the last interval, got %d", history[0].Items[2].Count)
+		}
+		if history[1].Status != "pass" || history[1].Items[2].Count != 1 {
+			t.Errorf("Expected 1 pass result in the last interval, got %d", history[1].Items[2].Count)
+		}
+		if history[0].Items[0].Count != 0 {
+			t.Errorf("Expected no results before the report was created, got %d", history[0].Items[0].Count)
+		}
+
+		transitions, err := store.FetchResultTransitions(v1.Filter{Namespaces: []string{"test"}}, query(), transitionPagination)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(transitions) != 2 {
+			t.Fatalf("Expected 2 transitions for 2 new results, got %d", len(transitions))
+		}
+		if transitions[0].PreviousStatus != "" || transitions[0].Status != "fail" {
+			t.Errorf("Unexpected first transition: %s -> %s", transitions[0].PreviousStatus, transitions[0].Status)
+		}
+	})
+	t.Run("Skip unchanged Reports", func(t *testing.T) {
+		if err := store.Update(ureport); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		transitions, _ := store.FetchResultTransitions(v1.Filter{}, query(), transitionPagination)
+		if len(transitions) != 2 {
+			t.Errorf("Expected no new transitions for an unchanged report, got %d", len(transitions))
+		}
+	})
+	t.Run("Result Diff", func(t *testing.T) {
+		diff, err := store.FetchResultDiff("test", time.Now().Add(-time.Hour), time.Now().Add(time.Minute))
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(diff.Added) != 1 || diff.Added[0].Status != "fail" || diff.Added[0].PreviousStatus != "" {
+			t.Errorf("Expected the fail result to be added, got %+v", diff.Added)
+		}
+		if len(diff.Resolved) != 0 || len(diff.StillFailing) != 0 {
+			t.Errorf("Expected no resolved or still failing results, got %+v", diff)
+		}
+
+		diff, _ = store.FetchResultDiff("test", time.Now().Add(time.Second), time.Now().Add(time.Minute))
+		if len(diff.StillFailing) != 1 || len(diff.Added) != 0 {
+			t.Errorf("Expected the fail result to be still failing, got %+v", diff)
+		}
+
+		diff, _ = store.FetchResultDiff("other", time.Now().Add(-time.Hour), time.Now().Add(time.Minute))
+		if len(diff.Added) != 0 || len(diff.StillFailing) != 0 {
+			t.Errorf("Expected no results of another namespace, got %+v", diff)
+		}
+	})
+	t.Run("Keep Baseline on Prune", func(t *testing.T) {
+		if err := store.PruneHistory(time.Now().Add(time.Hour)); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		history, _ := store.FetchStatusHistory(v1.Filter{Status: []string{"fail"}}, query())
+		if len(history) != 1 || history[0].Items[2].Count != 1 {
+			t.Errorf("Expected the latest snapshot to be kept after pruning")
+		}
+
+		transitions, _ := store.FetchResultTransitions(v1.Filter{}, query(), transitionPagination)
+		if len(transitions) != 2 {
+			t.Errorf("Expected the latest transition of each result to be kept, got %d", len(transitions))
+		}
+	})
+	t.Run("Record Removal", func(t *testing.T) {
+		if err := store.Remove(ureport.GetID()); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		history, _ := store.FetchStatusHistory(v1.Filter{Status: []string{"fail"}}, query())
+		for _, series := range history {
+			if series.Items[len(series.Items)-1].Count != 0 {
+				t.Errorf("Expected no fail results after the report was removed")
+			}
+		}
+
+		transitions, _ := store.FetchResultTransitions(v1.Filter{Status: []string{""}}, query(), transitionPagination)
+		if len(transitions) != 2 {
+			t.Errorf("Expected 2 transitions of removed results, got %d", len(transitions))
+		}
+	})
+	t.Run("Prune History", func(t *testing.T) {
+		if err := store.PruneHistory(time.Now().Add(time.Hour)); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		history, _ := store.FetchStatusHistory(v1.Filter{}, query())
+		if len(history) != 0 {
+			t.Errorf("Expected no history after pruning, got %d series", len(history))
+		}
+
+		transitions, _ := store.FetchResultTransitions(v1.Filter{}, query(), transitionPagination)
+		if len(transitions) != 0 {
+			t.Errorf("Expected all transitions of removed results to be pruned, got %d", len(transitions))
+		}
+	})
+}
+
+func Test_ResourceHistory(t *testing.T) {
+	db, _ := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "resource_history.db"))
+	defer db.Close()
+	store, _ := sqlite3.NewPolicyReportStore(db)
+	store.EnableHistory()
+
+	recreated := fixtures.FailResult
+	recreated.Resources = []corev1.ObjectReference{recreated.Resources[0]}
+	recreated.Resources[0].UID = "9c2ffc2d-a1a5-4b1e-8f7a-6ec4e7b1e2a0"
+
+	rreport := preport.DeepCopy()
+	rreport.Results = []v1alpha2.PolicyReportResult{recreated}
+
+	if err := store.Add(preport); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if err := store.Update(rreport); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	t.Run("By Name", func(t *testing.T) {
+		list, err := store.FetchResourceHistory(v2.ResourceQuery{Namespace: "test", Kind: "Deployment", Name: "nginx"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(list) != 2 {
+			t.Fatalf("Expected 2 incarnations of the recreated resource, got %d", len(list))
+		}
+		if list[0].UID != string(fixtures.FailResult.Resources[0].UID) || list[1].UID != string(recreated.Resources[0].UID) {
+			t.Errorf("Unexpected UIDs %s and %s", list[0].UID, list[1].UID)
+		}
+		if len(list[1].Transitions) != 1 || list[1].Transitions[0].Status != "fail" {
+			t.Errorf("Unexpected transitions of the recreated resource %+v", list[1].Transitions)
+		}
+	})
+
+	t.Run("By UID", func(t *testing.T) {
+		list, err := store.FetchResourceHistory(v2.ResourceQuery{UID: string(recreated.Resources[0].UID)})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(list) != 1 || list[0].Name != "nginx" || list[0].Namespace != "test" {
+			t.Errorf("Expected the history of the recreated resource, got %+v", list)
+		}
+
+		list, _ = store.FetchResourceHistory(v2.ResourceQuery{UID: string(recreated.Resources[0].UID), Namespace: "other"})
+		if len(list) != 0 {
+			t.Errorf("Expected no history within another namespace, got %d", len(list))
+		}
+	})
+
+	t.Run("Resource Status by UID", func(t *testing.T) {
+		list, err := store.FetchResourceStatus(v1.Filter{Namespaces: []string{"test"}, UIDs: []string{string(recreated.Resources[0].UID)}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(list) != 1 || list[0].UID != string(recreated.Resources[0].UID) || list[0].Fail != 1 {
+			t.Errorf("Unexpected resources %+v", list)
+		}
+	})
+}
@@ -0,0 +1,244 @@
+package sqlite3
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	api "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+	"github.com/kyverno/policy-reporter/pkg/severity"
+)
+
+// severityRank ranks the severities including custom levels from 1, unknown severities are ranked 0.
+// Custom level names are restricted to letters, digits, '.', '_' and '-', so they are safe to inline
+func severityRank() string {
+	var b strings.Builder
+	b.WriteString("(CASE LOWER(result.severity)")
+	for i, s := range severity.List() {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", strings.ToLower(s), i+1)
+	}
+	b.WriteString(" ELSE 0 END)")
+
+	return b.String()
+}
+
+func resultSortFields() map[string]string {
+	return map[string]string{
+		"namespace": "result.resource_namespace",
+		"kind":      "result.resource_kind",
+		"name":      "result.resource_name",
+		"policy":    "result.policy",
+		"rule":      "result.rule",
+		"status":    "result.status",
+		"severity":  severityRank(),
+		"timestamp": "result.timestamp",
+	}
+}
+
+var reportSortFields = map[string]string{
+	"namespace": "report.namespace",
+	"name":      "report.name",
+	"source":    "report.source",
+	"pass":      "report.pass",
+	"fail":      "report.fail",
+	"warn":      "report.warn",
+	"error":     "report.error",
+	"skip":      "report.skip",
+	"created":   "report.created",
+	"updated":   "report.updated",
+}
+
+// keyset builds a seek condition behind the cursor, sorted by an expression and unique key columns
+type keyset struct {
+	sort      string
+	keys      []string
+	direction string
+}
+
+func newKeyset(fields map[string]string, pagination v2.Pagination, fallback string, keys ...string) keyset {
+	sort, ok := fields[strings.ToLower(pagination.SortBy)]
+	if !ok {
+		sort = fields[fallback]
+	}
+
+	direction := "ASC"
+	if pagination.Direction == "DESC" {
+		direction = "DESC"
+	}
+
+	return keyset{sort: sort, keys: keys, direction: direction}
+}
+
+// where returns the seek condition, all placeholders are named behind the given offset
+func (k keyset) where(cursor *v2.Cursor, offset int) (string, []interface{}) {
+	if cursor == nil || len(cursor.Keys) != len(k.keys) {
+		return "", nil
+	}
+
+	operator := ">"
+	if k.direction == "DESC" {
+		operator = "<"
+	}
+
+	columns := append([]string{k.sort}, k.keys...)
+	args := make([]interface{}, 0, len(columns))
+	args = append(args, cursor.Value)
+	for _, key := range cursor.Keys {
+		args = append(args, key)
+	}
+
+	// (c1 > v1) OR (c1 = v1 AND c2 > v2) OR (c1 = v1 AND c2 = v2 AND c3 > v3)
+	conditions := make([]string, 0, len(columns))
+	for i := range columns {
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, fmt.Sprintf("%s = $%d", columns[j], offset+j))
+		}
+		parts = append(parts, fmt.Sprintf("%s %s $%d", columns[i], operator, offset+i))
+
+		conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return " AND (" + strings.Join(conditions, " OR ") + ")", args
+}
+
+func (k keyset) orderBy(limit int) string {
+	columns := append([]string{k.sort}, k.keys...)
+	for i, column := range columns {
+		columns[i] = column + " " + k.direction
+	}
+
+	return fmt.Sprintf("ORDER BY %s LIMIT %d", strings.Join(columns, ", "), limit+1)
+}
+
+// FetchPolicyReportPage by filter and cursor pagination
+func (s *policyReportStore) FetchPolicyReportPage(filter api.Filter, pagination v2.Pagination) ([]*api.PolicyReport, *v2.Cursor, error) {
+	return s.fetchReportPage(`report.namespace != ''`, filter, pagination)
+}
+
+// FetchClusterPolicyReportPage by filter and cursor pagination
+func (s *policyReportStore) FetchClusterPolicyReportPage(filter api.Filter, pagination v2.Pagination) ([]*api.PolicyReport, *v2.Cursor, error) {
+	filter.Namespaces = nil
+
+	return s.fetchReportPage(`report.namespace = ''`, filter, pagination)
+}
+
+// FetchNamespacedResultPage by filter and cursor pagination
+func (s *policyReportStore) FetchNamespacedResultPage(filter api.Filter, pagination v2.Pagination) ([]*api.ListResult, *v2.Cursor, error) {
+	return s.fetchResultPage(`result.resource_namespace != ''`, filter, []string{"sources", "categories", "policies", "rules", "kinds", "resources", "status", "severities", "namespaces", "expression", "teams", "ids", "uids"}, pagination)
+}
+
+// FetchClusterResultPage by filter and cursor pagination
+func (s *policyReportStore) FetchClusterResultPage(filter api.Filter, pagination v2.Pagination) ([]*api.ListResult, *v2.Cursor, error) {
+	return s.fetchResultPage(`result.resource_namespace = ''`, filter, []string{"sources", "categories", "policies", "rules", "kinds", "resources", "status", "severities", "expression", "teams", "ids", "uids"}, pagination)
+}
+
+func (s *policyReportStore) fetchReportPage(scope string, filter api.Filter, pagination v2.Pagination) ([]*api.PolicyReport, *v2.Cursor, error) {
+	list := make([]*api.PolicyReport, 0)
+
+	where, args := s.generateFilterWhere(api.Filter{Namespaces: filter.Namespaces, Sources: filter.Sources, ReportLabel: filter.ReportLabel}, []string{"report_namespaces", "report_sources"})
+	if len(where) > 0 {
+		where = " AND " + where
+	}
+
+	keys := newKeyset(reportSortFields, pagination, "name", "report.id")
+	seek, seekArgs := keys.where(pagination.Cursor, len(args)+1000)
+
+	rows, err := s.query(`
+    SELECT report.id, report.namespace, report.source, report.name, report.labels, report.pass, report.skip, report.warn, report.fail, report.error, `+keys.sort+`
+    FROM policy_report as report WHERE `+scope+where+seek+` `+keys.orderBy(pagination.Limit), append(args, seekArgs...)...)
+	if err != nil {
+		return list, nil, err
+	}
+	defer rows.Close()
+
+	var last interface{}
+
+	for rows.Next() {
+		var labels string
+		var value interface{}
+
+		r := &api.PolicyReport{}
+		if err := rows.Scan(&r.ID, &r.Namespace, &r.Source, &r.Name, &labels, &r.Pass, &r.Skip, &r.Warn, &r.Fail, &r.Error, &value); err != nil {
+			return list, nil, err
+		}
+
+		if len(list) == pagination.Limit {
+			return list, &v2.Cursor{Value: cursorValue(last), Keys: []string{list[len(list)-1].ID}}, nil
+		}
+
+		r.Labels = convertJSONToMap(labels)
+		last = value
+
+		list = append(list, r)
+	}
+
+	return list, nil, nil
+}
+
+func (s *policyReportStore) fetchResultPage(scope string, filter api.Filter, active []string, pagination v2.Pagination) ([]*api.ListResult, *v2.Cursor, error) {
+	list := make([]*api.ListResult, 0)
+
+	where, args := s.generateFilterWhere(filter, active)
+	if len(where) > 0 {
+		where = " AND " + where
+	}
+
+	join := ""
+	if len(filter.ReportLabel) > 0 {
+		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
+	}
+
+	keys := newKeyset(resultSortFields(), pagination, "namespace", "result.policy_report_id", "result.id")
+	seek, seekArgs := keys.where(pagination.Cursor, len(args)+1000)
+
+	rows, err := s.query(`
+    SELECT result.id, result.resource_namespace, result.resource_kind, result.resource_api_version, result.resource_name, result.message, result.policy, result.rule, result.severity, result.properties, result.status, result.category, result.timestamp, result.policy_report_id, `+keys.sort+`
+    FROM policy_report_result as result`+join+` WHERE `+scope+where+seek+` `+keys.orderBy(pagination.Limit), append(args, seekArgs...)...)
+	if err != nil {
+		return list, nil, err
+	}
+	defer rows.Close()
+
+	var last interface{}
+	var lastReport string
+
+	for rows.Next() {
+		var props []byte
+		var reportID string
+		var value interface{}
+
+		result := &api.ListResult{}
+		err := rows.Scan(&result.ID, &result.Namespace, &result.Kind, &result.APIVersion, &result.Name, &result.Message, &result.Policy, &result.Rule, &result.Severity, &props, &result.Status, &result.Category, &result.Timestamp, &reportID, &value)
+		if err != nil {
+			return list, nil, err
+		}
+
+		if len(list) == pagination.Limit {
+			list, err = s.withTriage(list)
+
+			return list, &v2.Cursor{Value: cursorValue(last), Keys: []string{lastReport, list[len(list)-1].ID}}, err
+		}
+
+		result.Message = s.decrypt(result.Message)
+		json.Unmarshal(props, &result.Properties)
+		s.decryptProperties(result.Properties)
+		last = value
+		lastReport = reportID
+
+		list = append(list, result)
+	}
+
+	list, err = s.withTriage(list)
+
+	return list, nil, err
+}
+
+func cursorValue(value interface{}) interface{} {
+	if b, ok := value.([]byte); ok {
+		return string(b)
+	}
+
+	return value
+}
@@ -0,0 +1,82 @@
+package sqlite3_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+	"github.com/kyverno/policy-reporter/pkg/sqlite3"
+)
+
+func Test_CursorPagination(t *testing.T) {
+	db, _ := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "keyset.db"))
+	defer db.Close()
+	store, _ := sqlite3.NewPolicyReportStore(db)
+
+	store.Add(preport)
+	store.Add(scopeReport)
+	store.Add(creport)
+
+	t.Run("Page through namespaced Results", func(t *testing.T) {
+		for _, sortBy := range []string{"namespace", "severity", "timestamp", "unknown"} {
+			pagination := v2.Pagination{Limit: 1, SortBy: sortBy, Direction: "DESC"}
+			ids := make(map[string]bool)
+
+			for page := 0; page < 5; page++ {
+				list, cursor, err := store.FetchNamespacedResultPage(v1.Filter{}, pagination)
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+
+				for _, r := range list {
+					ids[r.ID] = true
+				}
+
+				if cursor == nil {
+					break
+				}
+
+				pagination.Cursor = v2.DecodeCursor(v2.EncodeCursor(cursor))
+			}
+
+			if len(ids) != 2 {
+				t.Errorf("Expected 2 distinct results sorted by %s, got %d", sortBy, len(ids))
+			}
+		}
+	})
+	t.Run("Last Page has no Cursor", func(t *testing.T) {
+		list, cursor, err := store.FetchClusterResultPage(v1.Filter{}, v2.Pagination{Limit: 10})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(list) != 2 {
+			t.Errorf("Expected 2 cluster results, got %d", len(list))
+		}
+		if cursor != nil {
+			t.Error("Expected no cursor on the last page")
+		}
+	})
+	t.Run("Page through PolicyReports", func(t *testing.T) {
+		list, cursor, err := store.FetchPolicyReportPage(v1.Filter{}, v2.Pagination{Limit: 1, SortBy: "fail"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(list) != 1 || cursor == nil {
+			t.Fatalf("Expected first page with a cursor")
+		}
+
+		next, cursor, _ := store.FetchPolicyReportPage(v1.Filter{}, v2.Pagination{Limit: 1, SortBy: "fail", Cursor: cursor})
+		if len(next) != 1 || next[0].ID == list[0].ID {
+			t.Errorf("Expected second page with another report")
+		}
+		if cursor != nil {
+			t.Error("Expected no cursor on the last page")
+		}
+
+		clusterReports, _, _ := store.FetchClusterPolicyReportPage(v1.Filter{}, v2.Pagination{Limit: 10})
+		if len(clusterReports) != 1 {
+			t.Errorf("Expected 1 ClusterPolicyReport, got %d", len(clusterReports))
+		}
+	})
+}
@@ -0,0 +1,217 @@
+package sqlite3
+
+import (
+	"context"
+	"time"
+)
+
+// pruneBatchSize of reports removed at once to enforce the size limit
+const pruneBatchSize = 20
+
+// storeTables with their row counts in the StoreStats
+var storeTables = []string{"policy_report", "policy_report_result", "policy_report_history", "policy_report_result_history", "policy_report_tombstone"}
+
+// Limits of the PolicyReport store, a zero value disables the limit
+type Limits struct {
+	// MaxAge removes reports which were not updated within the duration
+	MaxAge time.Duration
+	// MaxRows of the result table, the least recently updated reports are removed first
+	MaxRows int
+	// MaxSize in bytes of the used pages of the SQLite database, not supported by external databases
+	MaxSize int64
+}
+
+// Pruned reports per exceeded limit
+type Pruned struct {
+	Age  int
+	Rows int
+	Size int
+}
+
+// StoreStats with the row count per table and the database size in bytes
+type StoreStats struct {
+	Rows map[string]int
+	Size int64
+}
+
+// Prune removes reports and their results which exceed the limits
+func (s *policyReportStore) Prune(limits Limits) (Pruned, error) {
+	pruned := Pruned{}
+
+	if limits.MaxAge > 0 {
+		ids, err := s.queryIDs("SELECT id FROM policy_report WHERE COALESCE(updated, created) < $1", time.Now().Add(-limits.MaxAge).Unix())
+		if err != nil {
+			return pruned, err
+		}
+
+		pruned.Age, err = s.removeAll(ids)
+		if err != nil {
+			return pruned, err
+		}
+	}
+
+	if limits.MaxRows > 0 {
+		var err error
+
+		pruned.Rows, err = s.pruneRows(limits.MaxRows)
+		if err != nil {
+			return pruned, err
+		}
+	}
+
+	if limits.MaxSize > 0 && s.dialect == SQLite {
+		for {
+			size, err := s.size()
+			if err != nil || size <= limits.MaxSize {
+				return pruned, err
+			}
+
+			ids, err := s.queryIDs("SELECT id FROM policy_report ORDER BY COALESCE(updated, created) ASC LIMIT $1", pruneBatchSize)
+			if err != nil || len(ids) == 0 {
+				return pruned, err
+			}
+
+			count, err := s.removeAll(ids)
+			pruned.Size += count
+			if err != nil {
+				return pruned, err
+			}
+		}
+	}
+
+	return pruned, nil
+}
+
+// Stats returns the row count of all store tables and the used size of the database
+func (s *policyReportStore) Stats() (StoreStats, error) {
+	stats := StoreStats{Rows: make(map[string]int, len(storeTables))}
+
+	for _, table := range storeTables {
+		var count int
+		if err := s.queryRow("SELECT COUNT(*) FROM " + table).Scan(&count); err != nil {
+			return stats, err
+		}
+
+		stats.Rows[table] = count
+	}
+
+	size, err := s.size()
+	stats.Size = size
+
+	return stats, err
+}
+
+// pruneRows removes the least recently updated reports until the result table is within the limit
+func (s *policyReportStore) pruneRows(maxRows int) (int, error) {
+	var total int
+	if err := s.queryRow("SELECT COUNT(*) FROM policy_report_result").Scan(&total); err != nil {
+		return 0, err
+	}
+	if total <= maxRows {
+		return 0, nil
+	}
+
+	rows, err := s.query(`
+    SELECT report.id, COUNT(result.id)
+    FROM policy_report as report LEFT JOIN policy_report_result as result ON result.policy_report_id = report.id
+    GROUP BY report.id, report.updated, report.created
+    ORDER BY COALESCE(report.updated, report.created) ASC`)
+	if err != nil {
+		return 0, err
+	}
+
+	ids := make([]string, 0)
+	for rows.Next() && total > maxRows {
+		var id string
+		var count int
+
+		if err := rows.Scan(&id, &count); err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		ids = append(ids, id)
+		total -= count
+	}
+	rows.Close()
+
+	return s.removeAll(ids)
+}
+
+// size of the used database pages, free pages of the SQLite file are reused for new rows
+func (s *policyReportStore) size() (int64, error) {
+	var size int64
+	var err error
+
+	switch s.dialect.Name() {
+	case SQLiteType:
+		err = s.queryRow("SELECT (page_count - freelist_count) * page_size FROM pragma_page_count(), pragma_freelist_count(), pragma_page_size()").Scan(&size)
+	case PostgresType:
+		err = s.queryRow("SELECT pg_database_size(current_database())").Scan(&size)
+	case MySQLType:
+		err = s.queryRow("SELECT COALESCE(SUM(data_length + index_length), 0) FROM information_schema.tables WHERE table_schema = DATABASE()").Scan(&size)
+	}
+
+	return size, err
+}
+
+func (s *policyReportStore) queryIDs(query string, args ...interface{}) ([]string, error) {
+	rows, err := s.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return ids, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (s *policyReportStore) removeAll(ids []string) (int, error) {
+	for index, id := range ids {
+		// pruned reports still exist in the cluster, no tombstone is recorded
+		if err := s.remove(id, false); err != nil {
+			return index, err
+		}
+	}
+
+	return len(ids), nil
+}
+
+// RunPruning enforces the limits in the given interval until the context is canceled,
+// observe is called with the pruned reports and the current stats after each run
+func RunPruning(ctx context.Context, store PolicyReportStore, limits Limits, interval time.Duration, observe func(Pruned, StoreStats)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		pruned, err := store.Prune(limits)
+		if err != nil {
+			logger.Errorf("failed to prune PolicyReport store: %s", err)
+		}
+		if total := pruned.Age + pruned.Rows + pruned.Size; total > 0 {
+			logger.Infof("pruned %d PolicyReports exceeding the store limits", total)
+		}
+
+		stats, err := store.Stats()
+		if err != nil {
+			logger.Errorf("failed to get PolicyReport store stats: %s", err)
+		}
+
+		observe(pruned, stats)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package sqlite3_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/sqlite3"
+)
+
+func Test_Prune(t *testing.T) {
+	db, _ := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "prune.db"))
+	defer db.Close()
+	store, _ := sqlite3.NewPolicyReportStore(db)
+
+	t.Run("MaxAge", func(t *testing.T) {
+		_ = store.Add(preport)
+		_ = store.Add(creport)
+
+		db.Exec("UPDATE policy_report SET updated = ? WHERE id = ?", time.Now().Add(-2*time.Hour).Unix(), preport.GetID())
+
+		pruned, err := store.Prune(sqlite3.Limits{MaxAge: time.Hour})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if pruned.Age != 1 {
+			t.Errorf("Expected 1 pruned report, got %d", pruned.Age)
+		}
+		if _, ok := store.Get(preport.GetID()); ok {
+			t.Error("Expected outdated report to be removed")
+		}
+		if _, ok := store.Get(creport.GetID()); !ok {
+			t.Error("Expected updated report to be kept")
+		}
+	})
+
+	t.Run("MaxRows", func(t *testing.T) {
+		_ = store.Add(preport)
+
+		db.Exec("UPDATE policy_report SET updated = ? WHERE id = ?", time.Now().Add(-time.Minute).Unix(), creport.GetID())
+
+		pruned, err := store.Prune(sqlite3.Limits{MaxRows: 2})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if pruned.Rows != 1 {
+			t.Errorf("Expected 1 pruned report, got %d", pruned.Rows)
+		}
+		if _, ok := store.Get(creport.GetID()); ok {
+			t.Error("Expected least recently updated report to be removed")
+		}
+
+		pruned, _ = store.Prune(sqlite3.Limits{MaxRows: 2})
+		if pruned.Rows != 0 {
+			t.Errorf("Expected no pruned reports within the limit, got %d", pruned.Rows)
+		}
+	})
+
+	t.Run("MaxSize", func(t *testing.T) {
+		pruned, err := store.Prune(sqlite3.Limits{MaxSize: 1})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if pruned.Size != 1 {
+			t.Errorf("Expected all reports to be pruned, got %d", pruned.Size)
+		}
+	})
+
+	t.Run("Stats", func(t *testing.T) {
+		_ = store.Add(creport)
+
+		stats, err := store.Stats()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if stats.Rows["policy_report"] != 1 || stats.Rows["policy_report_result"] != len(creport.Results) {
+			t.Errorf("Unexpected row counts: %v", stats.Rows)
+		}
+		if stats.Size <= 0 {
+			t.Errorf("Expected database size, got %d", stats.Size)
+		}
+	})
+}
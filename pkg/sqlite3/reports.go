@@ -0,0 +1,132 @@
+package sqlite3
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	api "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+const reportColumns = "report.id, report.type, report.namespace, report.name, report.source, report.labels, report.scope, report.pass, report.skip, report.warn, report.fail, report.error, report.created, report.updated"
+
+type reportScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanReport(row reportScanner, extra ...interface{}) (*v2.Report, error) {
+	var labels string
+	var scope sql.NullString
+	var updated sql.NullInt64
+
+	r := &v2.Report{}
+	dest := []interface{}{&r.ID, &r.Kind, &r.Namespace, &r.Name, &r.Source, &labels, &scope, &r.Pass, &r.Skip, &r.Warn, &r.Fail, &r.Error, &r.Created, &updated}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	r.Labels = convertJSONToMap(labels)
+	r.Scope = convertJSONToScope(scope.String)
+	r.Updated = updated.Int64
+
+	return r, nil
+}
+
+func (s *policyReportStore) reportWhere(filter api.Filter) (string, []interface{}) {
+	where, args := s.generateFilterWhere(api.Filter{Namespaces: filter.Namespaces, Sources: filter.Sources, ReportLabel: filter.ReportLabel}, []string{"report_namespaces", "report_sources"})
+	if len(where) == 0 {
+		return "1=1", args
+	}
+
+	return where, args
+}
+
+// FetchReportPage of PolicyReports and ClusterPolicyReports with their metadata by filter and cursor pagination
+func (s *policyReportStore) FetchReportPage(filter api.Filter, pagination v2.Pagination) ([]*v2.Report, *v2.Cursor, error) {
+	list := make([]*v2.Report, 0)
+
+	where, args := s.reportWhere(filter)
+
+	keys := newKeyset(reportSortFields, pagination, "name", "report.id")
+	seek, seekArgs := keys.where(pagination.Cursor, len(args)+1000)
+
+	rows, err := s.query(`SELECT `+reportColumns+`, `+keys.sort+` FROM policy_report as report WHERE `+where+seek+` `+keys.orderBy(pagination.Limit), append(args, seekArgs...)...)
+	if err != nil {
+		return list, nil, err
+	}
+	defer rows.Close()
+
+	var last interface{}
+
+	for rows.Next() {
+		var value interface{}
+
+		r, err := scanReport(rows, &value)
+		if err != nil {
+			return list, nil, err
+		}
+
+		if len(list) == pagination.Limit {
+			return list, &v2.Cursor{Value: cursorValue(last), Keys: []string{list[len(list)-1].ID}}, nil
+		}
+
+		last = value
+
+		list = append(list, r)
+	}
+
+	return list, nil, nil
+}
+
+// CountReports of PolicyReports and ClusterPolicyReports by filter
+func (s *policyReportStore) CountReports(filter api.Filter) (int, error) {
+	where, args := s.reportWhere(filter)
+
+	var count int
+
+	err := s.queryRow(`SELECT count(report.id) FROM policy_report as report WHERE `+where, args...).Scan(&count)
+
+	return count, err
+}
+
+// FetchReport with its metadata by ID, the raw report is reconstructed with its results if requested.
+// Returns nil if the report does not exist
+func (s *policyReportStore) FetchReport(id string, raw bool) (*v2.Report, error) {
+	r, err := scanReport(s.queryRow(`SELECT `+reportColumns+` FROM policy_report as report WHERE report.id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if !raw {
+		return r, nil
+	}
+
+	stored, ok := s.Get(id)
+	if !ok {
+		return nil, nil
+	}
+
+	polr := stored.(*v1alpha2.PolicyReport)
+	polr.Kind = r.Kind
+	polr.APIVersion = v1alpha2.SchemeGroupVersion.String()
+
+	var content interface{} = polr
+	if r.Kind == string(report.ClusterPolicyReportType) {
+		content = &v1alpha2.ClusterPolicyReport{
+			TypeMeta:   polr.TypeMeta,
+			ObjectMeta: polr.ObjectMeta,
+			Scope:      polr.Scope,
+			Summary:    polr.Summary,
+			Results:    polr.Results,
+		}
+	}
+
+	r.Raw, err = json.Marshal(content)
+
+	return r, err
+}
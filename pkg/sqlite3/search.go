@@ -0,0 +1,125 @@
+package sqlite3
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	api "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+)
+
+var searchFilters = []string{"sources", "categories", "policies", "rules", "kinds", "resources", "status", "severities", "namespaces", "expression", "teams"}
+
+// Search the policies, namespaces, resources and results containing the term, up to limit hits per type.
+// Result messages are only searched without encryption
+func (s *policyReportStore) Search(term string, filter api.Filter, limit int) ([]*v2.SearchHit, error) {
+	where, args := s.generateFilterWhere(filter, searchFilters)
+	if len(where) > 0 {
+		where = " AND " + where
+	}
+
+	join := ""
+	if len(filter.ReportLabel) > 0 {
+		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
+	}
+
+	search := strings.ToLower(term)
+	placeholder := fmt.Sprintf("$%d", len(args)+1)
+	args = append(args, "%"+search+"%")
+
+	hits := make([]*v2.SearchHit, 0)
+
+	rows, err := s.query(`
+    SELECT policy, MAX(result.source), COUNT(result.id)
+    FROM policy_report_result as result`+join+` WHERE policy != ''`+where+` AND LOWER(policy) LIKE `+placeholder+`
+    GROUP BY policy ORDER BY policy ASC LIMIT `+fmt.Sprint(limit), args...)
+	if err != nil {
+		return hits, err
+	}
+	for rows.Next() {
+		hit := &v2.SearchHit{Type: v2.SearchTypePolicy}
+		if err := rows.Scan(&hit.Name, &hit.Source, &hit.Results); err != nil {
+			rows.Close()
+			return hits, err
+		}
+
+		hit.Match = hit.Name
+		hits = append(hits, hit)
+	}
+	rows.Close()
+
+	rows, err = s.query(`
+    SELECT resource_namespace, COUNT(result.id)
+    FROM policy_report_result as result`+join+` WHERE resource_namespace != ''`+where+` AND LOWER(resource_namespace) LIKE `+placeholder+`
+    GROUP BY resource_namespace ORDER BY resource_namespace ASC LIMIT `+fmt.Sprint(limit), args...)
+	if err != nil {
+		return hits, err
+	}
+	for rows.Next() {
+		hit := &v2.SearchHit{Type: v2.SearchTypeNamespace}
+		if err := rows.Scan(&hit.Name, &hit.Results); err != nil {
+			rows.Close()
+			return hits, err
+		}
+
+		hit.Match = hit.Name
+		hits = append(hits, hit)
+	}
+	rows.Close()
+
+	rows, err = s.query(`
+    SELECT resource_namespace, resource_kind, resource_name, COUNT(result.id)
+    FROM policy_report_result as result`+join+` WHERE resource_name != ''`+where+` AND LOWER(resource_name) LIKE `+placeholder+`
+    GROUP BY resource_namespace, resource_kind, resource_name ORDER BY resource_name ASC, resource_namespace ASC LIMIT `+fmt.Sprint(limit), args...)
+	if err != nil {
+		return hits, err
+	}
+	for rows.Next() {
+		hit := &v2.SearchHit{Type: v2.SearchTypeResource}
+		if err := rows.Scan(&hit.Namespace, &hit.Kind, &hit.Name, &hit.Results); err != nil {
+			rows.Close()
+			return hits, err
+		}
+
+		hit.Match = hit.Name
+		hits = append(hits, hit)
+	}
+	rows.Close()
+
+	condition := "LOWER(rule) LIKE " + placeholder
+	if s.cipher == nil {
+		condition = "(LOWER(rule) LIKE " + placeholder + " OR LOWER(message) LIKE " + placeholder + ")"
+	}
+
+	rows, err = s.query(`
+    SELECT result.id, resource_namespace, resource_kind, resource_api_version, resource_name, message, policy, rule, severity, properties, status, category, result.source, timestamp
+    FROM policy_report_result as result`+join+` WHERE 1 = 1`+where+` AND `+condition+`
+    ORDER BY policy ASC, result.id ASC LIMIT `+fmt.Sprint(limit), args...)
+	if err != nil {
+		return hits, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		result := api.ListResult{}
+		var props []byte
+
+		err := rows.Scan(&result.ID, &result.Namespace, &result.Kind, &result.APIVersion, &result.Name, &result.Message, &result.Policy, &result.Rule, &result.Severity, &props, &result.Status, &result.Category, &result.Source, &result.Timestamp)
+		if err != nil {
+			return hits, err
+		}
+
+		result.Message = s.decrypt(result.Message)
+		json.Unmarshal(props, &result.Properties)
+		s.decryptProperties(result.Properties)
+
+		hit := &v2.SearchHit{Type: v2.SearchTypeResult, Name: result.Policy, Namespace: result.Namespace, Kind: result.Kind, Source: result.Source, Result: &result, Match: result.Message}
+		if strings.Contains(strings.ToLower(result.Rule), search) {
+			hit.Match = result.Rule
+		}
+
+		hits = append(hits, hit)
+	}
+
+	return hits, nil
+}
@@ -0,0 +1,72 @@
+package sqlite3_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+	"github.com/kyverno/policy-reporter/pkg/sqlite3"
+)
+
+func Test_Search(t *testing.T) {
+	db, _ := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "search.db"))
+	defer db.Close()
+	store, _ := sqlite3.NewPolicyReportStore(db)
+
+	_ = store.Add(preport)
+	_ = store.Add(creport)
+
+	count := func(hits []*v2.SearchHit, kind string) int {
+		var c int
+		for _, hit := range hits {
+			if hit.Type == kind {
+				c++
+			}
+		}
+		return c
+	}
+
+	t.Run("Policies and results", func(t *testing.T) {
+		hits, err := store.Search("Requests", v1.Filter{}, 10)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if count(hits, v2.SearchTypePolicy) != 1 || hits[0].Name != "require-requests-and-limits-required" || hits[0].Results != 1 {
+			t.Errorf("Expected the matching policy as first hit, got %+v", hits[0])
+		}
+		if count(hits, v2.SearchTypeResult) != 1 {
+			t.Errorf("Expected 1 matching result, got %d", count(hits, v2.SearchTypeResult))
+		}
+	})
+
+	t.Run("Namespaces and messages", func(t *testing.T) {
+		hits, _ := store.Search("test", v1.Filter{}, 10)
+
+		if count(hits, v2.SearchTypeNamespace) != 1 {
+			t.Errorf("Expected the test namespace, got %+v", hits)
+		}
+		if count(hits, v2.SearchTypeResource) != 1 {
+			t.Errorf("Expected the test namespace resource, got %+v", hits)
+		}
+		if count(hits, v2.SearchTypeResult) != 2 {
+			t.Errorf("Expected the results with the test label in their message, got %+v", hits)
+		}
+	})
+
+	t.Run("Resources", func(t *testing.T) {
+		hits, _ := store.Search("ngin", v1.Filter{}, 10)
+
+		if len(hits) != 1 || hits[0].Type != v2.SearchTypeResource || hits[0].Kind != "Deployment" || hits[0].Namespace != "test" {
+			t.Errorf("Expected the nginx Deployment, got %+v", hits)
+		}
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		hits, _ := store.Search("test", v1.Filter{Namespaces: []string{"test"}}, 10)
+
+		if count(hits, v2.SearchTypeResult) != 0 {
+			t.Errorf("Expected cluster scoped results to be filtered, got %+v", hits)
+		}
+	})
+}
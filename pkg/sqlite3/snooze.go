@@ -0,0 +1,120 @@
+package sqlite3
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/snooze"
+)
+
+// maxSnoozeEvents limits the returned audit trail
+const maxSnoozeEvents = 1000
+
+// SaveSnooze creates or replaces the snooze of the result
+func (s *policyReportStore) SaveSnooze(sn snooze.Snooze) error {
+	query := s.dialect.Upsert("INSERT INTO policy_report_snooze (result_id, expires, reason, snoozed_by, created) VALUES (?,?,?,?,?)", "result_id", []string{"expires", "reason", "snoozed_by", "created"})
+	_, err := s.exec(query, sn.ResultID, sn.Until.Unix(), sn.Reason, sn.SnoozedBy, sn.Created.Unix())
+
+	return err
+}
+
+// DeleteSnooze removes the snooze of the result
+func (s *policyReportStore) DeleteSnooze(resultID string) (bool, error) {
+	result, err := s.exec("DELETE FROM policy_report_snooze WHERE result_id=$1", resultID)
+	if err != nil {
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+
+	return count > 0, err
+}
+
+// FetchSnoozes returns the snoozes which expire after now
+func (s *policyReportStore) FetchSnoozes(now time.Time) ([]snooze.Snooze, error) {
+	rows, err := s.query("SELECT result_id, expires, reason, snoozed_by, created FROM policy_report_snooze WHERE expires > $1 ORDER BY expires, result_id", now.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]snooze.Snooze, 0)
+	for rows.Next() {
+		var until, created int64
+		var reason, snoozedBy sql.NullString
+
+		sn := snooze.Snooze{}
+		if err := rows.Scan(&sn.ResultID, &until, &reason, &snoozedBy, &created); err != nil {
+			return list, err
+		}
+
+		sn.Until = time.Unix(until, 0)
+		sn.Created = time.Unix(created, 0)
+		sn.Reason = reason.String
+		sn.SnoozedBy = snoozedBy.String
+
+		list = append(list, sn)
+	}
+
+	return list, rows.Err()
+}
+
+// PruneSnoozes removes the snoozes expired before the given time
+func (s *policyReportStore) PruneSnoozes(before time.Time) error {
+	_, err := s.exec("DELETE FROM policy_report_snooze WHERE expires <= $1", before.Unix())
+
+	return err
+}
+
+// AddSnoozeEvent appends the event to the audit trail
+func (s *policyReportStore) AddSnoozeEvent(e snooze.Event) error {
+	var until interface{}
+	if e.Until != nil {
+		until = e.Until.Unix()
+	}
+
+	_, err := s.exec("INSERT INTO policy_report_snooze_event (result_id, action, actor, expires, reason, timestamp) VALUES (?,?,?,?,?,?)", e.ResultID, e.Action, e.Actor, until, e.Reason, e.Time.Unix())
+
+	return err
+}
+
+// FetchSnoozeEvents returns the latest events of the result, of all results if empty
+func (s *policyReportStore) FetchSnoozeEvents(resultID string) ([]snooze.Event, error) {
+	query := "SELECT result_id, action, actor, expires, reason, timestamp FROM policy_report_snooze_event"
+	args := make([]interface{}, 0, 1)
+	if resultID != "" {
+		query += " WHERE result_id=$1"
+		args = append(args, resultID)
+	}
+
+	rows, err := s.query(query+" ORDER BY timestamp DESC LIMIT "+strconv.Itoa(maxSnoozeEvents), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]snooze.Event, 0)
+	for rows.Next() {
+		var until sql.NullInt64
+		var timestamp int64
+		var actor, reason sql.NullString
+
+		e := snooze.Event{}
+		if err := rows.Scan(&e.ResultID, &e.Action, &actor, &until, &reason, &timestamp); err != nil {
+			return list, err
+		}
+
+		if until.Valid {
+			t := time.Unix(until.Int64, 0)
+			e.Until = &t
+		}
+		e.Time = time.Unix(timestamp, 0)
+		e.Actor = actor.String
+		e.Reason = reason.String
+
+		list = append(list, e)
+	}
+
+	return list, rows.Err()
+}
@@ -0,0 +1,69 @@
+package sqlite3_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/snooze"
+	"github.com/kyverno/policy-reporter/pkg/sqlite3"
+)
+
+func Test_Snoozes(t *testing.T) {
+	db, _ := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "snooze.db"))
+	defer db.Close()
+	store, _ := sqlite3.NewPolicyReportStore(db)
+
+	now := time.Now()
+
+	for _, sn := range []snooze.Snooze{
+		{ResultID: "123", Until: now.Add(time.Hour), Reason: "planned", SnoozedBy: "jane", Created: now},
+		{ResultID: "456", Until: now.Add(-time.Hour), Created: now.Add(-2 * time.Hour)},
+	} {
+		if err := store.SaveSnooze(sn); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+
+	t.Run("Fetch active", func(t *testing.T) {
+		list, err := store.FetchSnoozes(now)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(list) != 1 || list[0].ResultID != "123" || list[0].SnoozedBy != "jane" || list[0].Until.Unix() != now.Add(time.Hour).Unix() {
+			t.Errorf("Expected the active snooze, got %+v", list)
+		}
+	})
+
+	t.Run("Prune", func(t *testing.T) {
+		if err := store.PruneSnoozes(now); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		removed, _ := store.DeleteSnooze("456")
+		if removed {
+			t.Errorf("Expected expired snooze to be pruned")
+		}
+	})
+
+	t.Run("Events", func(t *testing.T) {
+		until := now.Add(time.Hour)
+
+		store.AddSnoozeEvent(snooze.Event{ResultID: "123", Action: snooze.ActionSnooze, Actor: "jane", Until: &until, Time: now.Add(-time.Minute)})
+		store.AddSnoozeEvent(snooze.Event{ResultID: "123", Action: snooze.ActionUnsnooze, Actor: "john", Time: now})
+		store.AddSnoozeEvent(snooze.Event{ResultID: "456", Action: snooze.ActionSnooze, Time: now})
+
+		list, err := store.FetchSnoozeEvents("123")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(list) != 2 || list[0].Action != snooze.ActionUnsnooze || list[0].Until != nil || list[1].Until == nil {
+			t.Errorf("Expected latest event first, got %+v", list)
+		}
+
+		all, _ := store.FetchSnoozeEvents("")
+		if len(all) != 3 {
+			t.Errorf("Expected 3 events, got %d", len(all))
+		}
+	})
+}
@@ -4,22 +4,35 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"log"
 	"os"
 	"strings"
 	"time"
 
-	_ "github.com/mattn/go-sqlite3"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	api "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/escalation"
+	"github.com/kyverno/policy-reporter/pkg/federation"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/query"
 	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/score"
+	"github.com/kyverno/policy-reporter/pkg/snooze"
+	"github.com/kyverno/policy-reporter/pkg/subscription"
+	"github.com/kyverno/policy-reporter/pkg/triage"
+	"github.com/kyverno/policy-reporter/pkg/validate"
 )
 
+var logger = logging.Component(logging.Store)
+
+// sqliteDriver is the SQLite driver with the regexp function used by regex filter values, see driver.go
+const sqliteDriver = "sqlite3_policy_reporter"
+
 const (
-	reportSQL = `CREATE TABLE policy_report (
+	reportSQL = `CREATE TABLE IF NOT EXISTS policy_report (
     "id" TEXT NOT NULL PRIMARY KEY,   
     "type" TEXT,
     "namespace" TEXT,
@@ -36,7 +49,7 @@ const (
     "created" INTEGER
   );`
 
-	resultSQL = `CREATE TABLE policy_report_result (
+	resultSQL = `CREATE TABLE IF NOT EXISTS policy_report_result (
     "policy_report_id" TEXT NOT NULL,
     "id" TEXT NOT NULL,
     "policy" TEXT,
@@ -58,59 +71,92 @@ const (
     FOREIGN KEY (policy_report_id) REFERENCES policy_report(id) ON DELETE CASCADE
   );`
 
-	resultInsertBaseSQL = "INSERT OR IGNORE INTO policy_report_result(policy_report_id, id, policy, rule, message, scored, status, severity, category, source, resource_api_version, resource_kind, resource_name, resource_namespace, resource_uid, properties, timestamp) VALUES "
+	reportInsertSQL = "INSERT INTO policy_report(id, type, namespace, source, name, labels, kinds, severities, pass, skip, warn, fail, error, created, updated, scope) values(?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)"
+
+	resultInsertBaseSQL = "INSERT INTO policy_report_result(policy_report_id, id, policy, rule, message, scored, status, severity, category, source, resource_api_version, resource_kind, resource_name, resource_namespace, resource_uid, properties, timestamp, first_seen, checksum) VALUES "
 )
 
+var reportUpdateColumns = []string{"type", "namespace", "source", "name", "labels", "kinds", "severities", "pass", "skip", "warn", "fail", "error", "created", "updated", "scope"}
+
 type PolicyReportStore interface {
 	report.PolicyReportStore
 	api.PolicyReportFinder
+	api.TaxonomyFinder
+	api.HistoryFinder
+	api.ScoreFinder
+	v2.PolicyReportFinder
+	v2.HistoryFinder
+	v2.TombstoneFinder
+	// EnableHistory records summary snapshots and result transitions for each change of a PolicyReport
+	EnableHistory()
+	// EnableEncryption encrypts the messages and property values of all persisted results with the cipher
+	EnableEncryption(cipher *FieldCipher)
+	// ObserveWrites calls the observer with the operation and the duration of each add, update and remove
+	ObserveWrites(observer WriteObserver)
+	// PruneHistory removes all history entries older than the given time
+	PruneHistory(before time.Time) error
+	// EnableTombstones records a tombstone with the deletion time and the last summary for each removed PolicyReport
+	EnableTombstones()
+	// PruneTombstones removes all tombstones of reports deleted before the given time
+	PruneTombstones(before time.Time) error
+	// Prune removes reports and their results which exceed the limits
+	Prune(limits Limits) (Pruned, error)
+	// Stats returns the row count of all store tables and the used size of the database
+	Stats() (StoreStats, error)
+	escalation.Finder
+	subscription.Store
+	triage.Store
+	// EnableTriage adds the triage of each result to the fetched result lists
+	EnableTriage()
+	snooze.Store
 }
 
 // policyReportStore caches the latest version of an PolicyReport
 type policyReportStore struct {
-	db *sql.DB
+	db         *sql.DB
+	dialect    Dialect
+	history    bool
+	tombstones bool
+	triage     bool
+	cipher     *FieldCipher
+	statements *statements
+	observer   WriteObserver
 }
 
 func (s *policyReportStore) CreateSchemas() error {
-	_, err := s.db.Exec("PRAGMA foreign_keys = ON")
-	if err != nil {
-		return err
-	}
-
-	_, err = s.db.Exec(reportSQL)
-	if err != nil {
+	if err := s.dialect.Setup(s.db); err != nil {
 		return err
 	}
 
-	_, err = s.db.Exec(resultSQL)
-
-	return err
+	return migrate(s.db, s.dialect)
 }
 
 // Get an PolicyReport by Type and ID
 func (s *policyReportStore) Get(id string) (v1alpha2.ReportInterface, bool) {
 	var created int64
 	var labels string
+	var scope sql.NullString
 
 	r := &v1alpha2.PolicyReport{
 		Summary: v1alpha2.PolicyReportSummary{},
 	}
 
-	row := s.db.QueryRow("SELECT namespace, name, labels, pass, skip, warn, fail, error, created FROM policy_report WHERE id=$1", id)
-	err := row.Scan(&r.Namespace, &r.Name, &labels, &r.Summary.Pass, &r.Summary.Skip, &r.Summary.Warn, &r.Summary.Fail, &r.Summary.Error, &created)
+	row := s.queryRow("SELECT namespace, name, labels, pass, skip, warn, fail, error, created, scope FROM policy_report WHERE id=$1", id)
+	err := row.Scan(&r.Namespace, &r.Name, &labels, &r.Summary.Pass, &r.Summary.Skip, &r.Summary.Warn, &r.Summary.Fail, &r.Summary.Error, &created, &scope)
 	if err == sql.ErrNoRows {
 		return r, false
 	} else if err != nil {
-		log.Printf("[ERROR] failed to select PolicyReport: %s", err)
+		logger.Errorf("failed to select PolicyReport: %s", err)
 		return r, false
 	}
 
 	r.CreationTimestamp = v1.NewTime(time.Unix(created, 0))
 	r.Labels = convertJSONToMap(labels)
+	r.Scope = convertJSONToScope(scope.String)
 
 	results, err := s.fetchResults(id)
 	if err != nil {
-		log.Printf("[ERROR] failed to fetch Reports: %s\n", err)
+		logger.Errorf("failed to fetch Reports: %s", err)
 		return r, false
 	}
 
@@ -119,103 +165,8 @@ func (s *policyReportStore) Get(id string) (v1alpha2.ReportInterface, bool) {
 	return r, true
 }
 
-// Add a PolicyReport to the Store
-func (s *policyReportStore) Add(r v1alpha2.ReportInterface) error {
-	stmt, err := s.db.Prepare("INSERT INTO policy_report(id, type, namespace, source, name, labels, kinds, severities, pass, skip, warn, fail, error, created) values(?,?,?,?,?,?,?,?,?,?,?,?,?,?)")
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
-
-	sum := r.GetSummary()
-
-	_, err = stmt.Exec(
-		r.GetID(),
-		report.GetType(r),
-		r.GetNamespace(),
-		r.GetSource(),
-		r.GetName(),
-		convertMapToJSON(r.GetLabels()),
-		convertSliveToJSON(r.GetKinds()),
-		convertSliveToJSON(r.GetSeverities()),
-		sum.Pass,
-		sum.Skip,
-		sum.Warn,
-		sum.Fail,
-		sum.Error,
-		r.GetCreationTimestamp().Unix(),
-	)
-	if err != nil {
-		return err
-	}
-
-	return s.persistResults(r)
-}
-
-func (s *policyReportStore) Update(r v1alpha2.ReportInterface) error {
-	stmt, err := s.db.Prepare("UPDATE policy_report SET labels=?, kinds=?, severities=?, pass=?, skip=?, warn=?, fail=?, error=?, created=? WHERE id=?")
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
-
-	sum := r.GetSummary()
-
-	_, err = stmt.Exec(
-		convertMapToJSON(r.GetLabels()),
-		convertSliveToJSON(r.GetKinds()),
-		convertSliveToJSON(r.GetSeverities()),
-		sum.Pass,
-		sum.Skip,
-		sum.Warn,
-		sum.Fail,
-		sum.Error,
-		r.GetCreationTimestamp().Unix(),
-		r.GetID(),
-	)
-	if err != nil {
-		return err
-	}
-
-	dstmt, err := s.db.Prepare("DELETE FROM policy_report_result WHERE policy_report_id=?")
-	if err != nil {
-		return err
-	}
-	defer dstmt.Close()
-
-	_, err = dstmt.Exec(r.GetID())
-	if err != nil {
-		return err
-	}
-
-	return s.persistResults(r)
-}
-
-// Remove a PolicyReport with the given Type and ID from the Store
-func (s *policyReportStore) Remove(id string) error {
-	stmt, err := s.db.Prepare("DELETE FROM policy_report WHERE id=?")
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
-
-	_, err = stmt.Exec(id)
-	if err != nil {
-		return err
-	}
-
-	stmt, err = s.db.Prepare("DELETE FROM policy_report_result WHERE policy_report_id=?")
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
-
-	_, err = stmt.Exec(id)
-	return err
-}
-
 func (s *policyReportStore) CleanUp() error {
-	stmt, err := s.db.Prepare("DELETE FROM policy_report")
+	stmt, err := s.prepare("DELETE FROM policy_report")
 	if err != nil {
 		return err
 	}
@@ -226,7 +177,7 @@ func (s *policyReportStore) CleanUp() error {
 		return err
 	}
 
-	dstmt, err := s.db.Prepare("DELETE FROM policy_report_result")
+	dstmt, err := s.prepare("DELETE FROM policy_report_result")
 	if err != nil {
 		return err
 	}
@@ -245,16 +196,16 @@ func (s *policyReportStore) FetchPolicyReports(filter api.Filter, pagination api
 	var where string
 
 	if len(filter.Namespaces) > 0 {
-		argCounter, whereParts, args = appendWhere(filter.Namespaces, "namespace", whereParts, args, argCounter)
+		argCounter, whereParts, args = s.appendWhere(filter.Namespaces, "namespace", whereParts, args, argCounter)
 	} else {
-		whereParts = append(whereParts, `namespace != ""`)
+		whereParts = append(whereParts, `namespace != ''`)
 	}
 
 	if len(filter.ReportLabel) > 0 {
 		for key, value := range filter.ReportLabel {
 			argCounter++
 
-			whereParts = append(whereParts, fmt.Sprintf("json_extract(labels, '$.\"%s\"') = $%d", key, argCounter))
+			whereParts = append(whereParts, fmt.Sprintf("%s = $%d", s.dialect.JSONExtract("labels", key), argCounter))
 			args = append(args, value)
 		}
 	}
@@ -264,7 +215,7 @@ func (s *policyReportStore) FetchPolicyReports(filter api.Filter, pagination api
 
 	list := make([]*api.PolicyReport, 0)
 
-	rows, err := s.db.Query(`SELECT id, namespace, source, name, labels, pass, skip, warn, fail, error FROM policy_report as report WHERE `+where+" "+paginationString, args...)
+	rows, err := s.query(`SELECT id, namespace, source, name, labels, pass, skip, warn, fail, error FROM policy_report as report WHERE `+where+" "+paginationString, args...)
 	if err != nil {
 		return list, err
 	}
@@ -275,7 +226,7 @@ func (s *policyReportStore) FetchPolicyReports(filter api.Filter, pagination api
 		r := &api.PolicyReport{}
 		err := rows.Scan(&r.ID, &r.Namespace, &r.Source, &r.Name, &labels, &r.Pass, &r.Skip, &r.Warn, &r.Fail, &r.Error)
 		if err != nil {
-			log.Printf("[ERROR] failed to scan PolicyReport: %s", err)
+			logger.Errorf("failed to scan PolicyReport: %s", err)
 			return list, err
 		}
 
@@ -296,16 +247,16 @@ func (s *policyReportStore) CountPolicyReports(filter api.Filter) (int, error) {
 	var where string
 
 	if len(filter.Namespaces) > 0 {
-		argCounter, whereParts, args = appendWhere(filter.Namespaces, "namespace", whereParts, args, argCounter)
+		argCounter, whereParts, args = s.appendWhere(filter.Namespaces, "namespace", whereParts, args, argCounter)
 	} else {
-		whereParts = append(whereParts, `namespace != ""`)
+		whereParts = append(whereParts, `namespace != ''`)
 	}
 
 	if len(filter.ReportLabel) > 0 {
 		for key, value := range filter.ReportLabel {
 			argCounter++
 
-			whereParts = append(whereParts, fmt.Sprintf("json_extract(labels, '$.\"%s\"') = $%d", key, argCounter))
+			whereParts = append(whereParts, fmt.Sprintf("%s = $%d", s.dialect.JSONExtract("labels", key), argCounter))
 			args = append(args, value)
 		}
 	}
@@ -313,7 +264,7 @@ func (s *policyReportStore) CountPolicyReports(filter api.Filter) (int, error) {
 	where = strings.Join(whereParts, " AND ")
 	var count int
 
-	row := s.db.QueryRow(`SELECT count(id) FROM policy_report as report WHERE `+where, args...)
+	row := s.queryRow(`SELECT count(id) FROM policy_report as report WHERE `+where, args...)
 	err := row.Scan(&count)
 	if err != nil {
 		return count, err
@@ -330,13 +281,13 @@ func (s *policyReportStore) FetchClusterPolicyReports(filter api.Filter, paginat
 	var argCounter int
 	var where string
 
-	whereParts = append(whereParts, `namespace = ""`)
+	whereParts = append(whereParts, `namespace = ''`)
 
 	if len(filter.ReportLabel) > 0 {
 		for key, value := range filter.ReportLabel {
 			argCounter++
 
-			whereParts = append(whereParts, fmt.Sprintf("json_extract(labels, '$.\"%s\"') = $%d", key, argCounter))
+			whereParts = append(whereParts, fmt.Sprintf("%s = $%d", s.dialect.JSONExtract("labels", key), argCounter))
 			args = append(args, value)
 		}
 	}
@@ -346,7 +297,7 @@ func (s *policyReportStore) FetchClusterPolicyReports(filter api.Filter, paginat
 
 	list := make([]*api.PolicyReport, 0)
 
-	rows, err := s.db.Query(`SELECT id, source, name, labels, pass, skip, warn, fail, error FROM policy_report as report WHERE `+where+" "+paginationString, args...)
+	rows, err := s.query(`SELECT id, source, name, labels, pass, skip, warn, fail, error FROM policy_report as report WHERE `+where+" "+paginationString, args...)
 	if err != nil {
 		return list, err
 	}
@@ -357,7 +308,7 @@ func (s *policyReportStore) FetchClusterPolicyReports(filter api.Filter, paginat
 		r := &api.PolicyReport{}
 		err := rows.Scan(&r.ID, &r.Source, &r.Name, &labels, &r.Pass, &r.Skip, &r.Warn, &r.Fail, &r.Error)
 		if err != nil {
-			log.Printf("[ERROR] failed to scan PolicyReport: %s", err)
+			logger.Errorf("failed to scan PolicyReport: %s", err)
 			return list, err
 		}
 
@@ -377,13 +328,13 @@ func (s *policyReportStore) CountClusterPolicyReports(filter api.Filter) (int, e
 	var argCounter int
 	var where string
 
-	whereParts = append(whereParts, `namespace = ""`)
+	whereParts = append(whereParts, `namespace = ''`)
 
 	if len(filter.ReportLabel) > 0 {
 		for key, value := range filter.ReportLabel {
 			argCounter++
 
-			whereParts = append(whereParts, fmt.Sprintf("json_extract(labels, '$.\"%s\"') = $%d", key, argCounter))
+			whereParts = append(whereParts, fmt.Sprintf("%s = $%d", s.dialect.JSONExtract("labels", key), argCounter))
 			args = append(args, value)
 		}
 	}
@@ -392,7 +343,7 @@ func (s *policyReportStore) CountClusterPolicyReports(filter api.Filter) (int, e
 
 	var count int
 
-	row := s.db.QueryRow(`SELECT count(id) FROM policy_report as report WHERE `+where, args...)
+	row := s.queryRow(`SELECT count(id) FROM policy_report as report WHERE `+where, args...)
 	err := row.Scan(&count)
 	if err != nil {
 		return count, err
@@ -404,7 +355,7 @@ func (s *policyReportStore) CountClusterPolicyReports(filter api.Filter) (int, e
 func (s *policyReportStore) FetchClusterPolicies(filter api.Filter) ([]string, error) {
 	list := make([]string, 0)
 
-	where, args := generateFilterWhere(filter, []string{"sources", "categories"})
+	where, args := s.generateFilterWhere(filter, []string{"sources", "categories"})
 	if len(where) > 0 {
 		where = " AND " + where
 	}
@@ -414,7 +365,7 @@ func (s *policyReportStore) FetchClusterPolicies(filter api.Filter) ([]string, e
 		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
 	}
 
-	rows, err := s.db.Query(`SELECT DISTINCT policy FROM policy_report_result as result`+join+` WHERE resource_namespace == ""`+where+` ORDER BY policy ASC`, args...)
+	rows, err := s.query(`SELECT DISTINCT policy FROM policy_report_result as result`+join+` WHERE resource_namespace = ''`+where+` ORDER BY policy ASC`, args...)
 	if err != nil {
 		return list, err
 	}
@@ -435,7 +386,7 @@ func (s *policyReportStore) FetchClusterPolicies(filter api.Filter) ([]string, e
 func (s *policyReportStore) FetchClusterRules(filter api.Filter) ([]string, error) {
 	list := make([]string, 0)
 
-	where, args := generateFilterWhere(filter, []string{"sources", "categories", "policies"})
+	where, args := s.generateFilterWhere(filter, []string{"sources", "categories", "policies"})
 	if len(where) > 0 {
 		where = " AND " + where
 	}
@@ -445,7 +396,7 @@ func (s *policyReportStore) FetchClusterRules(filter api.Filter) ([]string, erro
 		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
 	}
 
-	rows, err := s.db.Query(`SELECT DISTINCT rule FROM policy_report_result as result`+join+` WHERE resource_namespace == ""`+where+` ORDER BY rule ASC`, args...)
+	rows, err := s.query(`SELECT DISTINCT rule FROM policy_report_result as result`+join+` WHERE resource_namespace = ''`+where+` ORDER BY rule ASC`, args...)
 	if err != nil {
 		return list, err
 	}
@@ -466,7 +417,7 @@ func (s *policyReportStore) FetchClusterRules(filter api.Filter) ([]string, erro
 func (s *policyReportStore) FetchNamespacedPolicies(filter api.Filter) ([]string, error) {
 	list := make([]string, 0)
 
-	where, args := generateFilterWhere(filter, []string{"sources", "categories"})
+	where, args := s.generateFilterWhere(filter, []string{"sources", "categories", "namespaces"})
 	if len(where) > 0 {
 		where = " AND " + where
 	}
@@ -476,7 +427,7 @@ func (s *policyReportStore) FetchNamespacedPolicies(filter api.Filter) ([]string
 		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
 	}
 
-	rows, err := s.db.Query(`SELECT DISTINCT policy FROM policy_report_result as result`+join+` WHERE resource_namespace != ""`+where+` ORDER BY policy ASC`, args...)
+	rows, err := s.query(`SELECT DISTINCT policy FROM policy_report_result as result`+join+` WHERE resource_namespace != ''`+where+` ORDER BY policy ASC`, args...)
 	if err != nil {
 		return list, err
 	}
@@ -497,7 +448,7 @@ func (s *policyReportStore) FetchNamespacedPolicies(filter api.Filter) ([]string
 func (s *policyReportStore) FetchNamespacedRules(filter api.Filter) ([]string, error) {
 	list := make([]string, 0)
 
-	where, args := generateFilterWhere(filter, []string{"sources", "categories", "policies"})
+	where, args := s.generateFilterWhere(filter, []string{"sources", "categories", "policies", "namespaces"})
 	if len(where) > 0 {
 		where = " AND " + where
 	}
@@ -507,7 +458,7 @@ func (s *policyReportStore) FetchNamespacedRules(filter api.Filter) ([]string, e
 		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
 	}
 
-	rows, err := s.db.Query(`SELECT DISTINCT rule FROM policy_report_result as result`+join+` WHERE resource_namespace != ""`+where+` ORDER BY rule ASC`, args...)
+	rows, err := s.query(`SELECT DISTINCT rule FROM policy_report_result as result`+join+` WHERE resource_namespace != ''`+where+` ORDER BY rule ASC`, args...)
 	if err != nil {
 		return list, err
 	}
@@ -528,7 +479,15 @@ func (s *policyReportStore) FetchNamespacedRules(filter api.Filter) ([]string, e
 func (s *policyReportStore) FetchCategories(filter api.Filter) ([]string, error) {
 	list := make([]string, 0)
 
-	where, args := generateFilterWhere(filter, []string{"sources"})
+	where, args := s.generateFilterWhere(filter, []string{"sources"})
+	if scope, scopeArgs := s.scopeWhere(filter.Namespaces, len(args)); scope != "" {
+		if where != "" {
+			where += " AND "
+		}
+
+		where += scope
+		args = append(args, scopeArgs...)
+	}
 	if len(where) > 0 {
 		where = " AND " + where
 	}
@@ -538,7 +497,7 @@ func (s *policyReportStore) FetchCategories(filter api.Filter) ([]string, error)
 		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
 	}
 
-	rows, err := s.db.Query(`SELECT DISTINCT category FROM policy_report_result as result`+join+` WHERE category != ""`+where+` ORDER BY category ASC`, args...)
+	rows, err := s.query(`SELECT DISTINCT category FROM policy_report_result as result`+join+` WHERE category != ''`+where+` ORDER BY category ASC`, args...)
 	if err != nil {
 		return list, err
 	}
@@ -556,15 +515,75 @@ func (s *policyReportStore) FetchCategories(filter api.Filter) ([]string, error)
 	return list, nil
 }
 
+// FetchTaxonomy counts the results per source, category, policy and rule, rules are grouped by their policy
+func (s *policyReportStore) FetchTaxonomy(filter api.Filter) (*api.Taxonomy, error) {
+	taxonomy := &api.Taxonomy{}
+
+	where, args := s.generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "kinds", "resources", "status", "severities", "namespaces", "expression", "teams"})
+	if len(where) > 0 {
+		where = " AND " + where
+	}
+
+	join := ""
+	if len(filter.ReportLabel) > 0 {
+		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
+	}
+
+	fields := []struct {
+		field string
+		group string
+		items *[]api.TaxonomyItem
+	}{
+		{field: "result.source", items: &taxonomy.Sources},
+		{field: "category", items: &taxonomy.Categories},
+		{field: "policy", items: &taxonomy.Policies},
+		{field: "rule", group: "policy", items: &taxonomy.Rules},
+	}
+
+	for _, f := range fields {
+		*f.items = make([]api.TaxonomyItem, 0)
+
+		group, columns := "''", f.field
+		if f.group != "" {
+			group, columns = f.group, f.group+", "+f.field
+		}
+
+		rows, err := s.query(`
+    SELECT `+f.field+`, `+group+`, COUNT(result.id)
+    FROM policy_report_result as result`+join+` WHERE `+f.field+` != ''`+where+`
+    GROUP BY `+columns+`
+    ORDER BY `+columns, args...)
+		if err != nil {
+			return taxonomy, err
+		}
+
+		for rows.Next() {
+			item := api.TaxonomyItem{}
+			if err := rows.Scan(&item.Name, &item.Group, &item.Count); err != nil {
+				rows.Close()
+				return taxonomy, err
+			}
+
+			item.DisplayName = item.Name
+
+			*f.items = append(*f.items, item)
+		}
+
+		rows.Close()
+	}
+
+	return taxonomy, nil
+}
+
 func (s *policyReportStore) FetchNamespacedKinds(filter api.Filter) ([]string, error) {
 	list := make([]string, 0)
 
-	where, args := generateFilterWhere(filter, []string{"report_sources", "report_namespaces"})
+	where, args := s.generateFilterWhere(filter, []string{"report_sources", "report_namespaces"})
 	if len(where) > 0 {
 		where = " AND " + where
 	}
 
-	rows, err := s.db.Query(`SELECT report.kinds FROM policy_report as report WHERE report.namespace != ""`+where, args...)
+	rows, err := s.query(`SELECT report.kinds FROM policy_report as report WHERE report.namespace != ''`+where, args...)
 	if err != nil {
 		return list, err
 	}
@@ -585,12 +604,12 @@ func (s *policyReportStore) FetchNamespacedKinds(filter api.Filter) ([]string, e
 func (s *policyReportStore) FetchClusterKinds(filter api.Filter) ([]string, error) {
 	list := make([]string, 0)
 
-	where, args := generateFilterWhere(filter, []string{"report_sources"})
+	where, args := s.generateFilterWhere(filter, []string{"report_sources"})
 	if len(where) > 0 {
 		where = " AND " + where
 	}
 
-	rows, err := s.db.Query(`SELECT report.kinds FROM policy_report as report WHERE report.namespace = ""`+where, args...)
+	rows, err := s.query(`SELECT report.kinds FROM policy_report as report WHERE report.namespace = ''`+where, args...)
 	if err != nil {
 		return list, err
 	}
@@ -611,7 +630,7 @@ func (s *policyReportStore) FetchClusterKinds(filter api.Filter) ([]string, erro
 func (s *policyReportStore) FetchNamespacedResources(filter api.Filter) ([]*api.Resource, error) {
 	list := make([]*api.Resource, 0)
 
-	where, args := generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "namespaces", "kind"})
+	where, args := s.generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "namespaces", "kind"})
 	if len(where) > 0 {
 		where = " AND " + where
 	}
@@ -621,7 +640,7 @@ func (s *policyReportStore) FetchNamespacedResources(filter api.Filter) ([]*api.
 		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
 	}
 
-	rows, err := s.db.Query(`SELECT DISTINCT resource_kind, resource_name FROM policy_report_result as result`+join+` WHERE resource_name != "" AND resource_namespace != ""`+where+` ORDER BY resource_kind, resource_name ASC`, args...)
+	rows, err := s.query(`SELECT DISTINCT resource_kind, resource_name FROM policy_report_result as result`+join+` WHERE resource_name != '' AND resource_namespace != ''`+where+` ORDER BY resource_kind, resource_name ASC`, args...)
 	if err != nil {
 		return list, err
 	}
@@ -642,7 +661,7 @@ func (s *policyReportStore) FetchNamespacedResources(filter api.Filter) ([]*api.
 func (s *policyReportStore) FetchClusterResources(filter api.Filter) ([]*api.Resource, error) {
 	list := make([]*api.Resource, 0)
 
-	where, args := generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "kind"})
+	where, args := s.generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "kind"})
 	if len(where) > 0 {
 		where = " AND " + where
 	}
@@ -652,7 +671,7 @@ func (s *policyReportStore) FetchClusterResources(filter api.Filter) ([]*api.Res
 		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
 	}
 
-	rows, err := s.db.Query(`SELECT DISTINCT resource_kind, resource_name FROM policy_report_result as result`+join+` WHERE resource_name != "" AND resource_namespace == ""`+where+` ORDER BY resource_kind, resource_name ASC`, args...)
+	rows, err := s.query(`SELECT DISTINCT resource_kind, resource_name FROM policy_report_result as result`+join+` WHERE resource_name != '' AND resource_namespace = ''`+where+` ORDER BY resource_kind, resource_name ASC`, args...)
 	if err != nil {
 		return list, err
 	}
@@ -672,7 +691,7 @@ func (s *policyReportStore) FetchClusterResources(filter api.Filter) ([]*api.Res
 
 func (s *policyReportStore) FetchClusterSources() ([]string, error) {
 	list := make([]string, 0)
-	rows, err := s.db.Query(`SELECT DISTINCT source FROM policy_report_result as result WHERE source != "" AND resource_namespace == "" ORDER BY source ASC`)
+	rows, err := s.query(`SELECT DISTINCT source FROM policy_report_result as result WHERE source != '' AND resource_namespace = '' ORDER BY source ASC`)
 	if err != nil {
 		return list, err
 	}
@@ -690,9 +709,15 @@ func (s *policyReportStore) FetchClusterSources() ([]string, error) {
 	return list, nil
 }
 
-func (s *policyReportStore) FetchNamespacedSources() ([]string, error) {
+func (s *policyReportStore) FetchNamespacedSources(filter api.Filter) ([]string, error) {
 	list := make([]string, 0)
-	rows, err := s.db.Query(`SELECT DISTINCT source FROM policy_report_result as result WHERE source != "" AND resource_namespace != "" ORDER BY source ASC`)
+
+	where, args := s.generateFilterWhere(filter, []string{"namespaces"})
+	if len(where) > 0 {
+		where = " AND " + where
+	}
+
+	rows, err := s.query(`SELECT DISTINCT source FROM policy_report_result as result WHERE source != '' AND resource_namespace != ''`+where+` ORDER BY source ASC`, args...)
 	if err != nil {
 		return list, err
 	}
@@ -713,7 +738,7 @@ func (s *policyReportStore) FetchNamespacedSources() ([]string, error) {
 func (s *policyReportStore) FetchNamespaces(filter api.Filter) ([]string, error) {
 	list := make([]string, 0)
 
-	where, args := generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules"})
+	where, args := s.generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "namespaces"})
 	if len(where) > 0 {
 		where = " AND " + where
 	}
@@ -723,7 +748,7 @@ func (s *policyReportStore) FetchNamespaces(filter api.Filter) ([]string, error)
 		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
 	}
 
-	rows, err := s.db.Query(`SELECT DISTINCT resource_namespace FROM policy_report_result as result`+join+` WHERE resource_namespace != ""`+where+` ORDER BY resource_namespace ASC`, args...)
+	rows, err := s.query(`SELECT DISTINCT resource_namespace FROM policy_report_result as result`+join+` WHERE resource_namespace != ''`+where+` ORDER BY resource_namespace ASC`, args...)
 	if err != nil {
 		return list, err
 	}
@@ -762,7 +787,7 @@ func (s *policyReportStore) FetchNamespacedStatusCounts(filter api.Filter) ([]ap
 
 	statusCounts := make([]api.NamespacedStatusCount, 0, 5)
 
-	where, args := generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "kinds", "namespaces", "status", "severities"})
+	where, args := s.generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "kinds", "namespaces", "status", "severities", "expression", "teams"})
 	if len(where) > 0 {
 		where = " AND " + where
 	}
@@ -772,9 +797,9 @@ func (s *policyReportStore) FetchNamespacedStatusCounts(filter api.Filter) ([]ap
 		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
 	}
 
-	rows, err := s.db.Query(`
+	rows, err := s.query(`
     SELECT COUNT(result.id) as counter, resource_namespace, status 
-    FROM policy_report_result as result`+join+` WHERE resource_namespace != ""`+where+`
+    FROM policy_report_result as result`+join+` WHERE resource_namespace != ''`+where+`
     GROUP BY resource_namespace, status 
     ORDER BY resource_namespace ASC`, args...)
 	if err != nil {
@@ -802,6 +827,69 @@ func (s *policyReportStore) FetchNamespacedStatusCounts(filter api.Filter) ([]ap
 	return statusCounts, nil
 }
 
+func (s *policyReportStore) FetchScoreCounts(filter api.Filter) ([]score.Count, error) {
+	counts := make([]score.Count, 0)
+
+	where, args := s.generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "kinds", "namespaces", "severities", "expression", "teams"})
+	if len(where) > 0 {
+		where = " WHERE " + where
+	}
+
+	join := ""
+	if len(filter.ReportLabel) > 0 {
+		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
+	}
+
+	rows, err := s.query(`
+    SELECT COUNT(result.id) as counter, resource_namespace, category, severity, status
+    FROM policy_report_result as result`+join+where+`
+    GROUP BY resource_namespace, category, severity, status`, args...)
+	if err != nil {
+		return counts, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		count := score.Count{}
+		err := rows.Scan(&count.Count, &count.Namespace, &count.Category, &count.Severity, &count.Status)
+		if err != nil {
+			return counts, err
+		}
+
+		counts = append(counts, count)
+	}
+
+	return counts, nil
+}
+
+// FetchClusterSummaries aggregates the status counts of all PolicyReports by their cluster label
+func (s *policyReportStore) FetchClusterSummaries() ([]federation.ClusterSummary, error) {
+	list := make([]federation.ClusterSummary, 0)
+
+	cluster := "COALESCE(" + s.dialect.JSONExtract("labels", report.ClusterLabel) + ", '')"
+
+	rows, err := s.query(`
+    SELECT ` + cluster + ` as cluster, count(id), SUM(pass), SUM(skip), SUM(warn), SUM(fail), SUM(error)
+    FROM policy_report
+    GROUP BY ` + cluster + `
+    ORDER BY cluster ASC`)
+	if err != nil {
+		return list, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := federation.ClusterSummary{}
+		err := rows.Scan(&item.Name, &item.Reports, &item.Pass, &item.Skip, &item.Warn, &item.Fail, &item.Error)
+		if err != nil {
+			return list, err
+		}
+
+		list = append(list, item)
+	}
+
+	return list, nil
+}
+
 func (s *policyReportStore) FetchRuleStatusCounts(policy, rule string) ([]api.StatusCount, error) {
 	list := map[string]api.StatusCount{
 		v1alpha2.StatusPass:  {Status: v1alpha2.StatusPass},
@@ -818,15 +906,15 @@ func (s *policyReportStore) FetchRuleStatusCounts(policy, rule string) ([]api.St
 
 	var argCounter int
 
-	argCounter, where, args = appendWhere([]string{policy}, "policy", where, args, argCounter)
-	_, where, args = appendWhere([]string{rule}, "rule", where, args, argCounter)
+	argCounter, where, args = s.appendWhere([]string{policy}, "policy", where, args, argCounter)
+	_, where, args = s.appendWhere([]string{rule}, "rule", where, args, argCounter)
 
 	whereClause := ""
 	if len(where) > 0 {
 		whereClause = " WHERE " + strings.Join(where, " AND ")
 	}
 
-	rows, err := s.db.Query(`
+	rows, err := s.query(`
     SELECT COUNT(id) as counter, status 
     FROM policy_report_result as result`+whereClause+`
     GROUP BY status`, args...)
@@ -872,7 +960,7 @@ func (s *policyReportStore) FetchStatusCounts(filter api.Filter) ([]api.StatusCo
 
 	statusCounts := make([]api.StatusCount, 0, len(list))
 
-	where, args := generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "kinds", "status", "severities"})
+	where, args := s.generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "kinds", "status", "severities", "expression", "teams"})
 	if len(where) > 0 {
 		where = " AND " + where
 	}
@@ -882,9 +970,9 @@ func (s *policyReportStore) FetchStatusCounts(filter api.Filter) ([]api.StatusCo
 		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
 	}
 
-	rows, err := s.db.Query(`
+	rows, err := s.query(`
     SELECT COUNT(result.id) as counter, status 
-    FROM policy_report_result as result`+join+` WHERE resource_namespace = ""`+where+`
+    FROM policy_report_result as result`+join+` WHERE resource_namespace = ''`+where+`
     GROUP BY status`, args...)
 	if err != nil {
 		return statusCounts, err
@@ -910,7 +998,7 @@ func (s *policyReportStore) FetchStatusCounts(filter api.Filter) ([]api.StatusCo
 func (s *policyReportStore) FetchNamespacedResults(filter api.Filter, pagination api.Pagination) ([]*api.ListResult, error) {
 	list := []*api.ListResult{}
 
-	where, args := generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "kinds", "resources", "status", "severities", "namespaces"})
+	where, args := s.generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "kinds", "resources", "status", "severities", "namespaces", "expression", "teams", "ids", "uids"})
 	if len(where) > 0 {
 		where = " AND " + where
 	}
@@ -921,9 +1009,9 @@ func (s *policyReportStore) FetchNamespacedResults(filter api.Filter, pagination
 		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
 	}
 
-	rows, err := s.db.Query(`
+	rows, err := s.query(`
     SELECT result.id, resource_namespace, resource_kind, resource_api_version, resource_name, message, policy, rule, severity, properties, status, category, timestamp
-    FROM policy_report_result as result`+join+` WHERE resource_namespace != ""`+where+` `+paginationString, args...)
+    FROM policy_report_result as result`+join+` WHERE resource_namespace != ''`+where+` `+paginationString, args...)
 	if err != nil {
 		return list, err
 	}
@@ -937,7 +1025,50 @@ func (s *policyReportStore) FetchNamespacedResults(filter api.Filter, pagination
 			return list, err
 		}
 
+		result.Message = s.decrypt(result.Message)
+
 		json.Unmarshal(props, &result.Properties)
+		s.decryptProperties(result.Properties)
+
+		list = append(list, &result)
+	}
+
+	return s.withTriage(list)
+}
+
+func (s *policyReportStore) FetchVulnerabilityResults(filter api.Filter) ([]*api.ListResult, error) {
+	list := []*api.ListResult{}
+
+	where, args := s.generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "kinds", "resources", "status", "severities", "namespaces", "expression", "teams", "ids", "uids"})
+	if len(where) > 0 {
+		where = " AND " + where
+	}
+
+	join := ""
+	if len(filter.ReportLabel) > 0 {
+		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
+	}
+
+	rows, err := s.query(`
+    SELECT result.id, resource_namespace, resource_kind, resource_api_version, resource_name, message, policy, rule, severity, properties, status, category, timestamp
+    FROM policy_report_result as result`+join+` WHERE COALESCE(`+s.dialect.JSONExtract("properties", v1alpha2.VulnerabilityIDKey)+`, '') != ''`+where+` ORDER BY result.id ASC`, args...)
+	if err != nil {
+		return list, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		result := api.ListResult{}
+		var props []byte
+
+		err := rows.Scan(&result.ID, &result.Namespace, &result.Kind, &result.APIVersion, &result.Name, &result.Message, &result.Policy, &result.Rule, &result.Severity, &props, &result.Status, &result.Category, &result.Timestamp)
+		if err != nil {
+			return list, err
+		}
+
+		result.Message = s.decrypt(result.Message)
+
+		json.Unmarshal(props, &result.Properties)
+		s.decryptProperties(result.Properties)
 
 		list = append(list, &result)
 	}
@@ -945,10 +1076,274 @@ func (s *policyReportStore) FetchNamespacedResults(filter api.Filter, pagination
 	return list, nil
 }
 
+// FetchResults of PolicyReports and ClusterPolicyReports by filter, ordered by source and policy
+func (s *policyReportStore) FetchResults(filter api.Filter) ([]*api.ListResult, error) {
+	list := []*api.ListResult{}
+
+	where, args := s.generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "kinds", "resources", "status", "severities", "namespaces", "expression", "teams", "ids", "uids"})
+	if len(where) > 0 {
+		where = " WHERE " + where
+	}
+
+	join := ""
+	if len(filter.ReportLabel) > 0 {
+		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
+	}
+
+	rows, err := s.query(`
+    SELECT result.id, resource_namespace, resource_kind, resource_api_version, resource_name, message, policy, rule, severity, properties, status, category, result.source, timestamp
+    FROM policy_report_result as result`+join+where+` ORDER BY result.source ASC, policy ASC, result.id ASC`, args...)
+	if err != nil {
+		return list, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		result := api.ListResult{}
+		var props []byte
+
+		err := rows.Scan(&result.ID, &result.Namespace, &result.Kind, &result.APIVersion, &result.Name, &result.Message, &result.Policy, &result.Rule, &result.Severity, &props, &result.Status, &result.Category, &result.Source, &result.Timestamp)
+		if err != nil {
+			return list, err
+		}
+
+		result.Message = s.decrypt(result.Message)
+
+		json.Unmarshal(props, &result.Properties)
+		s.decryptProperties(result.Properties)
+
+		list = append(list, &result)
+	}
+
+	return s.withTriage(list)
+}
+
+// FetchResourceStatus groups the results by resource with the status counts across all sources, ordered by namespace, kind and name.
+// Resources are keyed by UID, so a resource recreated with the same name is not mixed with results of its predecessor
+func (s *policyReportStore) FetchResourceStatus(filter api.Filter) ([]*v2.ResourceStatus, error) {
+	list := []*v2.ResourceStatus{}
+
+	where, args := s.generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "kinds", "resources", "severities", "namespaces", "expression", "teams", "uids"})
+	if len(where) > 0 {
+		where = " AND " + where
+	}
+
+	join := ""
+	if len(filter.ReportLabel) > 0 {
+		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
+	}
+
+	rows, err := s.query(`
+    SELECT resource_namespace, resource_kind, resource_name, MAX(resource_api_version), COALESCE(resource_uid, ''), result.source, status, COUNT(result.id)
+    FROM policy_report_result as result`+join+` WHERE resource_name != ''`+where+`
+    GROUP BY resource_namespace, resource_kind, resource_name, COALESCE(resource_uid, ''), result.source, status
+    ORDER BY resource_namespace ASC, resource_kind ASC, resource_name ASC, COALESCE(resource_uid, '') ASC, result.source ASC`, args...)
+	if err != nil {
+		return list, err
+	}
+	defer rows.Close()
+
+	resources := make(map[string]*v2.ResourceStatus)
+
+	for rows.Next() {
+		var namespace, kind, name, apiVersion, uid, source, status string
+		var count int
+
+		if err := rows.Scan(&namespace, &kind, &name, &apiVersion, &uid, &source, &status, &count); err != nil {
+			return list, err
+		}
+
+		key := namespace + "/" + kind + "/" + name + "/" + uid
+
+		resource, ok := resources[key]
+		if !ok {
+			resource = &v2.ResourceStatus{Namespace: namespace, Kind: kind, Name: name, UID: uid, Sources: []string{}}
+			resources[key] = resource
+			list = append(list, resource)
+		}
+		if resource.APIVersion == "" {
+			resource.APIVersion = apiVersion
+		}
+		if len(resource.Sources) == 0 || resource.Sources[len(resource.Sources)-1] != source {
+			resource.Sources = append(resource.Sources, source)
+		}
+
+		switch status {
+		case v1alpha2.StatusPass:
+			resource.Pass += count
+		case v1alpha2.StatusSkip:
+			resource.Skip += count
+		case v1alpha2.StatusWarn:
+			resource.Warn += count
+		case v1alpha2.StatusFail:
+			resource.Fail += count
+		case v1alpha2.StatusError:
+			resource.Error += count
+		}
+	}
+
+	return list, nil
+}
+
+// FetchSeverityMatrix counts the results by source and severity and by namespace and severity
+func (s *policyReportStore) FetchSeverityMatrix(filter api.Filter) (*v2.SeverityMatrix, error) {
+	matrix := v2.NewSeverityMatrix()
+
+	where, args := s.generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "kinds", "resources", "status", "severities", "namespaces", "expression", "teams"})
+	if len(where) > 0 {
+		where = " AND " + where
+	}
+
+	join := ""
+	if len(filter.ReportLabel) > 0 {
+		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
+	}
+
+	for _, group := range []struct {
+		column string
+		add    func(string, string, int)
+	}{
+		{column: "result.source", add: matrix.AddSource},
+		{column: "resource_namespace", add: matrix.AddNamespace},
+	} {
+		rows, err := s.query(`
+    SELECT `+group.column+`, COALESCE(severity, ''), COUNT(result.id)
+    FROM policy_report_result as result`+join+` WHERE `+group.column+` != ''`+where+`
+    GROUP BY `+group.column+`, severity
+    ORDER BY `+group.column+` ASC`, args...)
+		if err != nil {
+			return matrix, err
+		}
+
+		for rows.Next() {
+			var name, severity string
+			var count int
+
+			if err := rows.Scan(&name, &severity, &count); err != nil {
+				rows.Close()
+				return matrix, err
+			}
+
+			group.add(name, severity, count)
+		}
+		rows.Close()
+	}
+
+	return matrix, nil
+}
+
+// FetchPolicyStatus groups the results by policy with the status counts, the severity counts of the fail, warn and error results
+// and the namespaces and resources affected by them, ordered by policy
+func (s *policyReportStore) FetchPolicyStatus(filter api.Filter) ([]*v2.PolicyStatus, error) {
+	list := []*v2.PolicyStatus{}
+
+	where, args := s.generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "kinds", "resources", "status", "severities", "namespaces", "expression", "teams"})
+	if len(where) > 0 {
+		where = " AND " + where
+	}
+
+	join := ""
+	if len(filter.ReportLabel) > 0 {
+		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
+	}
+
+	rows, err := s.query(`
+    SELECT policy, result.source, status, COALESCE(severity, ''), COUNT(result.id)
+    FROM policy_report_result as result`+join+` WHERE policy != ''`+where+`
+    GROUP BY policy, result.source, status, severity
+    ORDER BY policy ASC, result.source ASC`, args...)
+	if err != nil {
+		return list, err
+	}
+
+	policies := make(map[string]*v2.PolicyStatus)
+
+	for rows.Next() {
+		var policy, source, status, severity string
+		var count int
+
+		if err := rows.Scan(&policy, &source, &status, &severity, &count); err != nil {
+			rows.Close()
+			return list, err
+		}
+
+		item, ok := policies[policy]
+		if !ok {
+			columns := v2.MatrixSeverities()
+
+			item = &v2.PolicyStatus{Policy: policy, Sources: []string{}, Namespaces: []string{}, Severities: make(map[string]int, len(columns))}
+			for _, s := range columns {
+				item.Severities[s] = 0
+			}
+
+			policies[policy] = item
+			list = append(list, item)
+		}
+		if len(item.Sources) == 0 || item.Sources[len(item.Sources)-1] != source {
+			item.Sources = append(item.Sources, source)
+		}
+
+		switch status {
+		case v1alpha2.StatusPass:
+			item.Pass += count
+		case v1alpha2.StatusSkip:
+			item.Skip += count
+		case v1alpha2.StatusWarn:
+			item.Warn += count
+		case v1alpha2.StatusFail:
+			item.Fail += count
+		case v1alpha2.StatusError:
+			item.Error += count
+		}
+
+		if status != v1alpha2.StatusFail && status != v1alpha2.StatusWarn && status != v1alpha2.StatusError {
+			continue
+		}
+		if _, ok := item.Severities[severity]; !ok {
+			severity = v2.SeverityUnknown
+		}
+
+		item.Severities[severity] += count
+	}
+	rows.Close()
+
+	rows, err = s.query(`
+    SELECT policy, resource_namespace, COUNT(*) FROM (
+      SELECT DISTINCT policy, resource_namespace, resource_kind, resource_name
+      FROM policy_report_result as result`+join+` WHERE policy != '' AND resource_name != '' AND status IN ('fail', 'warn', 'error')`+where+`
+    ) as affected
+    GROUP BY policy, resource_namespace
+    ORDER BY policy ASC, resource_namespace ASC`, args...)
+	if err != nil {
+		return list, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var policy, namespace string
+		var count int
+
+		if err := rows.Scan(&policy, &namespace, &count); err != nil {
+			return list, err
+		}
+
+		item, ok := policies[policy]
+		if !ok {
+			continue
+		}
+
+		item.Resources += count
+		if namespace != "" {
+			item.Namespaces = append(item.Namespaces, namespace)
+		}
+	}
+
+	return list, nil
+}
+
 func (s *policyReportStore) CountNamespacedResults(filter api.Filter) (int, error) {
 	var count int
 
-	where, args := generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "kinds", "resources", "status", "severities", "namespaces"})
+	where, args := s.generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "kinds", "resources", "status", "severities", "namespaces", "expression", "teams", "ids", "uids"})
 	if len(where) > 0 {
 		where = " AND " + where
 	}
@@ -958,7 +1353,7 @@ func (s *policyReportStore) CountNamespacedResults(filter api.Filter) (int, erro
 		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
 	}
 
-	row := s.db.QueryRow(`SELECT count(result.id) FROM policy_report_result as result`+join+` WHERE resource_namespace != ""`+where, args...)
+	row := s.queryRow(`SELECT count(result.id) FROM policy_report_result as result`+join+` WHERE resource_namespace != ''`+where, args...)
 	err := row.Scan(&count)
 	if err != nil {
 		return 0, err
@@ -970,7 +1365,7 @@ func (s *policyReportStore) CountNamespacedResults(filter api.Filter) (int, erro
 func (s *policyReportStore) FetchClusterResults(filter api.Filter, pagination api.Pagination) ([]*api.ListResult, error) {
 	list := []*api.ListResult{}
 
-	where, args := generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "kinds", "resources", "status", "severities"})
+	where, args := s.generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "kinds", "resources", "status", "severities", "expression", "teams", "ids", "uids"})
 	if len(where) > 0 {
 		where = " AND " + where
 	}
@@ -981,9 +1376,9 @@ func (s *policyReportStore) FetchClusterResults(filter api.Filter, pagination ap
 		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
 	}
 
-	rows, err := s.db.Query(`
+	rows, err := s.query(`
     SELECT result.id, resource_namespace, resource_kind, resource_api_version, resource_name, message, policy, rule, severity, properties, status, category, timestamp
-    FROM policy_report_result as result`+join+` WHERE resource_namespace =""`+where+` `+paginationString, args...)
+    FROM policy_report_result as result`+join+` WHERE resource_namespace =''`+where+` `+paginationString, args...)
 	if err != nil {
 		return list, err
 	}
@@ -997,18 +1392,21 @@ func (s *policyReportStore) FetchClusterResults(filter api.Filter, pagination ap
 			return list, err
 		}
 
+		result.Message = s.decrypt(result.Message)
+
 		json.Unmarshal(props, &result.Properties)
+		s.decryptProperties(result.Properties)
 
 		list = append(list, &result)
 	}
 
-	return list, nil
+	return s.withTriage(list)
 }
 
 func (s *policyReportStore) CountClusterResults(filter api.Filter) (int, error) {
 	var count int
 
-	where, args := generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "kinds", "resources", "status", "severities"})
+	where, args := s.generateFilterWhere(filter, []string{"sources", "categories", "policies", "rules", "kinds", "resources", "status", "severities", "expression", "teams", "ids", "uids"})
 	if len(where) > 0 {
 		where = " AND " + where
 	}
@@ -1018,7 +1416,7 @@ func (s *policyReportStore) CountClusterResults(filter api.Filter) (int, error)
 		join = " JOIN policy_report as report ON result.policy_report_id = report.id"
 	}
 
-	row := s.db.QueryRow(`SELECT count(result.id) FROM policy_report_result as result`+join+` WHERE resource_namespace =""`+where, args...)
+	row := s.queryRow(`SELECT count(result.id) FROM policy_report_result as result`+join+` WHERE resource_namespace =''`+where, args...)
 	err := row.Scan(&count)
 	if err != nil {
 		return 0, err
@@ -1030,12 +1428,12 @@ func (s *policyReportStore) CountClusterResults(filter api.Filter) (int, error)
 func (s *policyReportStore) FetchNamespacedReportLabels(filter api.Filter) (map[string][]string, error) {
 	list := make(map[string][]string)
 
-	where, args := generateFilterWhere(filter, []string{"report_sources", "report_namespaces"})
+	where, args := s.generateFilterWhere(filter, []string{"report_sources", "report_namespaces"})
 	if len(where) > 0 {
 		where = " AND " + where
 	}
 
-	rows, err := s.db.Query(`SELECT DISTINCT report.labels from policy_report as report WHERE report.namespace != ""`+where+` ORDER BY report.namespace ASC`, args...)
+	rows, err := s.query(`SELECT report.labels from policy_report as report WHERE report.namespace != ''`+where+` ORDER BY report.namespace ASC`, args...)
 	if err != nil {
 		return list, err
 	}
@@ -1072,12 +1470,12 @@ func (s *policyReportStore) FetchNamespacedReportLabels(filter api.Filter) (map[
 func (s *policyReportStore) FetchClusterReportLabels(filter api.Filter) (map[string][]string, error) {
 	list := make(map[string][]string)
 
-	where, args := generateFilterWhere(filter, []string{"report_sources"})
+	where, args := s.generateFilterWhere(filter, []string{"report_sources"})
 	if len(where) > 0 {
 		where = " AND " + where
 	}
 
-	rows, err := s.db.Query(`SELECT DISTINCT report.labels from policy_report as report WHERE report.namespace = ""`+where, args...)
+	rows, err := s.query(`SELECT report.labels from policy_report as report WHERE report.namespace = ''`+where, args...)
 	if err != nil {
 		return list, err
 	}
@@ -1107,74 +1505,10 @@ func (s *policyReportStore) FetchClusterReportLabels(filter api.Filter) (map[str
 	return list, nil
 }
 
-func (s *policyReportStore) persistResults(report v1alpha2.ReportInterface) error {
-	var vals []interface{}
-	var sqlStr string
-
-	bulks := chunkSlice(report.GetResults(), 50)
-
-	for _, list := range bulks {
-		sqlStr = resultInsertBaseSQL
-		vals = make([]interface{}, 0, len(list)*18)
-
-		for _, result := range list {
-			sqlStr += "(?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?),"
-
-			var props string
-			b, err := json.Marshal(result.Properties)
-			if err == nil {
-				props = string(b)
-			}
-
-			res := result.GetResource()
-			if res == nil && report.GetScope() != nil {
-				res = report.GetScope()
-			} else if res == nil {
-				res = &corev1.ObjectReference{}
-			}
-
-			vals = append(
-				vals,
-				report.GetID(),
-				result.GetID(),
-				result.Policy,
-				result.Rule,
-				result.Message,
-				result.Scored,
-				string(result.Result),
-				result.Severity,
-				result.Category,
-				result.Source,
-				res.APIVersion,
-				res.Kind,
-				res.Name,
-				report.GetNamespace(),
-				res.UID,
-				props,
-				result.Timestamp.Seconds,
-			)
-		}
-
-		sqlStr = sqlStr[0 : len(sqlStr)-1]
-		rstmt, err := s.db.Prepare(sqlStr)
-		if err != nil {
-			return err
-		}
-		defer rstmt.Close()
-
-		_, err = rstmt.Exec(vals...)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func (s *policyReportStore) fetchResults(reportID string) ([]v1alpha2.PolicyReportResult, error) {
 	results := make([]v1alpha2.PolicyReportResult, 0)
 
-	rows, err := s.db.Query(`
+	rows, err := s.query(`
     SELECT 
       id,
       policy,
@@ -1232,10 +1566,13 @@ func (s *policyReportStore) fetchResults(reportID string) ([]v1alpha2.PolicyRepo
 			return results, err
 		}
 
+		result.Message = s.decrypt(result.Message)
+
 		err = json.Unmarshal(props, &result.Properties)
 		if err != nil {
 			result.Properties = make(map[string]string)
 		}
+		s.decryptProperties(result.Properties)
 
 		result.Timestamp = v1.Timestamp{
 			Seconds: timestamp,
@@ -1249,76 +1586,231 @@ func (s *policyReportStore) fetchResults(reportID string) ([]v1alpha2.PolicyRepo
 	return results, nil
 }
 
-func appendWhere(options []string, field string, where []string, args []interface{}, argCounter int) (int, []string, []interface{}) {
-	length := len(options)
+// FetchUnresolvedResults with a fail, warn or error status first seen before the given time
+func (s *policyReportStore) FetchUnresolvedResults(firstSeenBefore time.Time) ([]escalation.Result, error) {
+	results := make([]escalation.Result, 0)
 
-	if length == 0 {
-		return argCounter, where, args
+	rows, err := s.query(`
+    SELECT 
+      r.type,
+      r.name,
+      r.labels,
+      res.policy_report_id,
+      res.id,
+      res.policy,
+      res.rule,
+      res.message,
+      res.scored,
+      res.status,
+      res.severity, 
+      res.category, 
+      res.source, 
+      res.resource_api_version,
+      res.resource_kind,
+      res.resource_name,
+      res.resource_namespace,
+      res.resource_uid, 
+      res.properties,
+      res.timestamp,
+      res.first_seen
+    FROM policy_report_result AS res
+    JOIN policy_report AS r ON r.id = res.policy_report_id
+    WHERE res.status IN ('fail', 'warn', 'error') AND res.first_seen <= $1
+    ORDER BY res.first_seen ASC
+  `, firstSeenBefore.Unix())
+	if err != nil {
+		return results, err
 	}
+	defer rows.Close()
 
-	if length == 1 {
-		option := options[0]
-		argCounter++
+	reports := make(map[string]v1alpha2.ReportInterface)
 
-		args = append(args, strings.ToLower(option))
+	for rows.Next() {
+		var rType, name, labels, reportID string
+		var props []byte
+		var timestamp, firstSeen int64
+
+		result := v1alpha2.PolicyReportResult{}
+		resource := corev1.ObjectReference{}
 
-		where = append(where, fmt.Sprintf("LOWER(%s)=$%d", field, argCounter))
+		err = rows.Scan(
+			&rType,
+			&name,
+			&labels,
+			&reportID,
+			&result.ID,
+			&result.Policy,
+			&result.Rule,
+			&result.Message,
+			&result.Scored,
+			&result.Result,
+			&result.Severity,
+			&result.Category,
+			&result.Source,
+			&resource.APIVersion,
+			&resource.Kind,
+			&resource.Name,
+			&resource.Namespace,
+			&resource.UID,
+			&props,
+			&timestamp,
+			&firstSeen,
+		)
+		if err != nil {
+			return results, err
+		}
 
-		return argCounter + length, where, args
+		result.Message = s.decrypt(result.Message)
+
+		if err := json.Unmarshal(props, &result.Properties); err != nil {
+			result.Properties = make(map[string]string)
+		}
+		s.decryptProperties(result.Properties)
+
+		result.Timestamp = v1.Timestamp{Seconds: timestamp}
+		result.Resources = []corev1.ObjectReference{resource}
+
+		rep, ok := reports[reportID]
+		if !ok {
+			meta := v1.ObjectMeta{Name: name, Labels: convertJSONToMap(labels)}
+			if rType == string(report.ClusterPolicyReportType) {
+				rep = &v1alpha2.ClusterPolicyReport{ObjectMeta: meta}
+			} else {
+				meta.Namespace = resource.Namespace
+				rep = &v1alpha2.PolicyReport{ObjectMeta: meta}
+			}
+
+			reports[reportID] = rep
+		}
+
+		results = append(results, escalation.Result{Report: rep, Result: result, FirstSeen: time.Unix(firstSeen, 0)})
 	}
 
-	arguments := make([]string, 0, length)
+	return results, rows.Err()
+}
+
+// appendWhere matches the field against the filter values, exact values are compared case insensitive,
+// values with wildcards use LIKE and values with the "regex:" prefix the regular expression match of the dialect
+func (s *policyReportStore) appendWhere(options []string, field string, where []string, args []interface{}, argCounter int) (int, []string, []interface{}) {
+	if len(options) == 0 {
+		return argCounter, where, args
+	}
+
+	// SQLite binds the arguments in the order of their first placeholder, so the exact values come first
+	exact := make([]string, 0, len(options))
+	patterns := make([]string, 0)
 
 	for _, option := range options {
-		argCounter++
+		if validate.IsRegex(option) || validate.IsGlob(option) {
+			patterns = append(patterns, option)
+			continue
+		}
 
-		arguments = append(arguments, fmt.Sprintf("$%d", argCounter))
+		argCounter++
 		args = append(args, strings.ToLower(option))
+		exact = append(exact, fmt.Sprintf("$%d", argCounter))
 	}
 
-	where = append(where, "LOWER("+field+") IN ("+strings.Join(arguments, ",")+")")
+	conditions := make([]string, 0, len(patterns)+1)
+	if len(exact) == 1 {
+		conditions = append(conditions, fmt.Sprintf("LOWER(%s)=%s", field, exact[0]))
+	} else if len(exact) > 1 {
+		conditions = append(conditions, "LOWER("+field+") IN ("+strings.Join(exact, ",")+")")
+	}
 
-	return argCounter + length, where, args
+	for _, pattern := range patterns {
+		argCounter++
+
+		if validate.IsRegex(pattern) {
+			args = append(args, strings.TrimPrefix(pattern, validate.RegexPrefix))
+			conditions = append(conditions, s.dialect.Regexp(field, fmt.Sprintf("$%d", argCounter)))
+			continue
+		}
+
+		args = append(args, wildcardPattern(pattern))
+		conditions = append(conditions, fmt.Sprintf("LOWER(%s) LIKE $%d", field, argCounter))
+	}
+
+	if len(conditions) == 1 {
+		return argCounter, append(where, conditions[0]), args
+	}
+
+	return argCounter, append(where, "("+strings.Join(conditions, " OR ")+")"), args
 }
 
-func generateFilterWhere(filter api.Filter, active []string) (string, []interface{}) {
+// scopeWhere restricts the results of namespaced and cluster scoped resources to the namespaces,
+// the report.ClusterScope pseudo namespace includes the cluster scoped results
+func (s *policyReportStore) scopeWhere(namespaces []string, argCounter int) (string, []interface{}) {
+	if len(namespaces) == 0 {
+		return "", nil
+	}
+
+	cluster := false
+	list := make([]string, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		if namespace == report.ClusterScope {
+			cluster = true
+			continue
+		}
+
+		list = append(list, namespace)
+	}
+
+	_, where, args := s.appendWhere(list, "result.resource_namespace", make([]string, 0, 2), make([]interface{}, 0, len(list)), argCounter)
+	if cluster {
+		where = append(where, "result.resource_namespace = ''")
+	}
+
+	return "(" + strings.Join(where, " OR ") + ")", args
+}
+
+func (s *policyReportStore) generateFilterWhere(filter api.Filter, active []string) (string, []interface{}) {
 	where := make([]string, 0)
 	args := make([]interface{}, 0)
 
 	var argCounter int
 
 	if contains("report_namespaces", active) {
-		argCounter, where, args = appendWhere(filter.Namespaces, "report.namespace", where, args, argCounter)
+		argCounter, where, args = s.appendWhere(filter.Namespaces, "report.namespace", where, args, argCounter)
 	}
 	if contains("report_sources", active) {
-		argCounter, where, args = appendWhere(filter.Sources, "report.source", where, args, argCounter)
+		argCounter, where, args = s.appendWhere(filter.Sources, "report.source", where, args, argCounter)
 	}
 	if contains("namespaces", active) {
-		argCounter, where, args = appendWhere(filter.Namespaces, "result.resource_namespace", where, args, argCounter)
+		argCounter, where, args = s.appendWhere(filter.Namespaces, "result.resource_namespace", where, args, argCounter)
 	}
 	if contains("policies", active) {
-		argCounter, where, args = appendWhere(filter.Policies, "result.policy", where, args, argCounter)
+		argCounter, where, args = s.appendWhere(filter.Policies, "result.policy", where, args, argCounter)
 	}
 	if contains("rules", active) {
-		argCounter, where, args = appendWhere(filter.Rules, "result.rule", where, args, argCounter)
+		argCounter, where, args = s.appendWhere(filter.Rules, "result.rule", where, args, argCounter)
 	}
 	if contains("kinds", active) {
-		argCounter, where, args = appendWhere(filter.Kinds, "result.resource_kind", where, args, argCounter)
+		argCounter, where, args = s.appendWhere(filter.Kinds, "result.resource_kind", where, args, argCounter)
 	}
 	if contains("resources", active) {
-		argCounter, where, args = appendWhere(filter.Resources, "result.resource_name", where, args, argCounter)
+		argCounter, where, args = s.appendWhere(filter.Resources, "result.resource_name", where, args, argCounter)
 	}
 	if contains("sources", active) {
-		argCounter, where, args = appendWhere(filter.Sources, "result.source", where, args, argCounter)
+		argCounter, where, args = s.appendWhere(filter.Sources, "result.source", where, args, argCounter)
 	}
 	if contains("categories", active) {
-		argCounter, where, args = appendWhere(filter.Categories, "result.category", where, args, argCounter)
+		argCounter, where, args = s.appendWhere(filter.Categories, "result.category", where, args, argCounter)
 	}
 	if contains("severities", active) {
-		argCounter, where, args = appendWhere(filter.Severities, "result.severity", where, args, argCounter)
+		argCounter, where, args = s.appendWhere(filter.Severities, "result.severity", where, args, argCounter)
 	}
 	if contains("status", active) {
-		argCounter, where, args = appendWhere(filter.Status, "result.status", where, args, argCounter)
+		argCounter, where, args = s.appendWhere(filter.Status, "result.status", where, args, argCounter)
+	}
+	if contains("ids", active) {
+		argCounter, where, args = s.appendWhere(filter.IDs, "result.id", where, args, argCounter)
+	}
+	if contains("uids", active) {
+		argCounter, where, args = s.appendWhere(filter.UIDs, "result.resource_uid", where, args, argCounter)
+	}
+	if contains("teams", active) {
+		argCounter, where, args = s.appendWhere(filter.Teams, s.dialect.JSONExtract("result.properties", v1alpha2.TeamKey), where, args, argCounter)
 	}
 	if filter.Search != "" {
 		likeIndex := argCounter + 1
@@ -1337,11 +1829,25 @@ func generateFilterWhere(filter api.Filter, active []string) (string, []interfac
 		))
 		args = append(args, filter.Search+"%", filter.Search)
 	}
+	if contains("expression", active) && filter.Expression != "" {
+		node, err := query.Parse(filter.Expression)
+		if err != nil {
+			logger.Errorf("invalid filter expression %q: %s", filter.Expression, err)
+			where = append(where, "1 = 0")
+		} else {
+			var condition string
+			var conditionArgs []interface{}
+
+			argCounter, condition, conditionArgs = expressionWhere(node, argCounter)
+			where = append(where, condition)
+			args = append(args, conditionArgs...)
+		}
+	}
 	if len(filter.ReportLabel) > 0 {
 		for key, value := range filter.ReportLabel {
 			argCounter++
 
-			where = append(where, fmt.Sprintf("json_extract(report.labels, '$.\"%s\"') = $%d", key, argCounter))
+			where = append(where, fmt.Sprintf("%s = $%d", s.dialect.JSONExtract("report.labels", key), argCounter))
 			args = append(args, value)
 		}
 	}
@@ -1402,6 +1908,33 @@ func convertJSONToMap(s string) map[string]string {
 	return m
 }
 
+// convertScopeToJSON returns an empty string for reports without scope
+func convertScopeToJSON(scope *corev1.ObjectReference) string {
+	if scope == nil {
+		return ""
+	}
+
+	str, err := json.Marshal(scope)
+	if err != nil {
+		return ""
+	}
+
+	return string(str)
+}
+
+func convertJSONToScope(s string) *corev1.ObjectReference {
+	if s == "" {
+		return nil
+	}
+
+	scope := &corev1.ObjectReference{}
+	if err := json.Unmarshal([]byte(s), scope); err != nil {
+		return nil
+	}
+
+	return scope
+}
+
 func convertJSONToSlice(s string) []string {
 	m := make([]string, 0)
 	_ = json.Unmarshal([]byte(s), &m)
@@ -1421,11 +1954,52 @@ func appendUnique(list, values []string) []string {
 	return list
 }
 
+func (s *policyReportStore) prepare(query string) (*sql.Stmt, error) {
+	query, _ = s.dialect.Rebind(query, nil)
+
+	return s.db.Prepare(query)
+}
+
+func (s *policyReportStore) query(query string, args ...interface{}) (*sql.Rows, error) {
+	query, args = s.dialect.Rebind(query, args)
+
+	return s.db.Query(query, args...)
+}
+
+func (s *policyReportStore) exec(query string, args ...interface{}) (sql.Result, error) {
+	query, args = s.dialect.Rebind(query, args)
+
+	return s.db.Exec(query, args...)
+}
+
+func (s *policyReportStore) execTx(tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
+	query, args = s.dialect.Rebind(query, args)
+
+	return tx.Exec(query, args...)
+}
+
+func (s *policyReportStore) queryTx(tx *sql.Tx, query string, args ...interface{}) (*sql.Rows, error) {
+	query, args = s.dialect.Rebind(query, args)
+
+	return tx.Query(query, args...)
+}
+
+func (s *policyReportStore) queryRow(query string, args ...interface{}) *sql.Row {
+	query, args = s.dialect.Rebind(query, args)
+
+	return s.db.QueryRow(query, args...)
+}
+
 // NewPolicyReportStore construct a PolicyReportStore
 func NewPolicyReportStore(db *sql.DB) (PolicyReportStore, error) {
+	return NewPolicyReportStoreWithDialect(db, SQLite)
+}
+
+// NewPolicyReportStoreWithDialect construct a PolicyReportStore for the given database dialect
+func NewPolicyReportStoreWithDialect(db *sql.DB, dialect Dialect) (PolicyReportStore, error) {
 	var err error
 
-	s := &policyReportStore{db}
+	s := &policyReportStore{db: db, dialect: dialect, statements: newStatements()}
 	if db != nil {
 		err = s.CreateSchemas()
 	}
@@ -1435,26 +2009,34 @@ func NewPolicyReportStore(db *sql.DB) (PolicyReportStore, error) {
 
 func NewDatabase(dbFile string) (*sql.DB, error) {
 	os.Remove(dbFile)
+	os.Remove(dbFile + "-wal")
+	os.Remove(dbFile + "-shm")
+
 	file, err := os.Create(dbFile)
 	if err != nil {
 		return nil, err
 	}
 	file.Close()
 
-	return sql.Open("sqlite3", dbFile)
+	return sql.Open(sqliteDriver, sqliteDSN(dbFile))
 }
 
-func chunkSlice(slice []v1alpha2.PolicyReportResult, chunkSize int) [][]v1alpha2.PolicyReportResult {
-	var chunks [][]v1alpha2.PolicyReportResult
-	for i := 0; i < len(slice); i += chunkSize {
-		end := i + chunkSize
+// OpenDatabase opens an existing SQLite database or creates a new one
+func OpenDatabase(dbFile string) (*sql.DB, error) {
+	return sql.Open(sqliteDriver, sqliteDSN(dbFile))
+}
 
-		if end > len(slice) {
-			end = len(slice)
-		}
+// sqliteDSN enables the WAL journal, so readers do not block the writer, and waits for locks instead of failing immediately.
+// Transactions take the write lock on begin to avoid failing lock upgrades of concurrent writers
+func sqliteDSN(dbFile string) string {
+	return dbFile + "?_journal_mode=WAL&_busy_timeout=5000&_synchronous=NORMAL&_foreign_keys=1&_txlock=immediate"
+}
 
-		chunks = append(chunks, slice[i:end])
+// CloseDatabase writes pending WAL changes into the SQLite database file before it is closed
+func CloseDatabase(db *sql.DB) error {
+	if _, err := db.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		logger.Errorf("failed to checkpoint the SQLite WAL: %s", err)
 	}
 
-	return chunks
+	return db.Close()
 }
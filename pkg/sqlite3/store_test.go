@@ -1,14 +1,21 @@
 package sqlite3_test
 
 import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
 	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/severity"
 	"github.com/kyverno/policy-reporter/pkg/sqlite3"
 )
 
@@ -77,7 +84,7 @@ var scopeReport = &v1alpha2.PolicyReport{
 }
 
 func Test_PolicyReportStore(t *testing.T) {
-	db, _ := sqlite3.NewDatabase("test.db")
+	db, _ := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "test.db"))
 	defer db.Close()
 	store, _ := sqlite3.NewPolicyReportStore(db)
 
@@ -113,6 +120,16 @@ func Test_PolicyReportStore(t *testing.T) {
 		if r2.GetLabels()["owner"] != "team-a" {
 			t.Errorf("Expected Labels are updated")
 		}
+
+		err = store.Add(ureport)
+		if err != nil {
+			t.Fatalf("Expected Add of an existing report to update it, got: %s", err)
+		}
+
+		r3, _ := store.Get(preport.GetID())
+		if len(r3.GetResults()) != len(ureport.GetResults()) {
+			t.Errorf("Expected %d Results after adding an existing report, got %d", len(ureport.GetResults()), len(r3.GetResults()))
+		}
 	})
 	t.Run("Add/Get ClusterPolicyReport", func(t *testing.T) {
 		_, ok := store.Get(creport.GetID())
@@ -213,6 +230,69 @@ func Test_PolicyReportStore(t *testing.T) {
 		}
 	})
 
+	t.Run("FetchResourceStatus", func(t *testing.T) {
+		items, err := store.FetchResourceStatus(v1.Filter{Namespaces: []string{"test"}})
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+		if len(items) != 2 {
+			t.Fatalf("Should find 2 resources in the namespace, got %d", len(items))
+		}
+		if items[0].Kind != "Deployment" || items[0].Fail != 1 || items[0].Pass != 0 {
+			t.Errorf("Should return the failing Deployment as first resource, got %+v", items[0])
+		}
+		if items[1].Kind != "Pod" || items[1].Pass != 1 || len(items[1].Sources) != 1 {
+			t.Errorf("Should return the passing Pod as second resource, got %+v", items[1])
+		}
+
+		items, _ = store.FetchResourceStatus(v1.Filter{Namespaces: []string{""}, Kinds: []string{"namespace"}, Resources: []string{"dev"}})
+		if len(items) != 1 || items[0].Namespace != "" || items[0].Fail != 1 {
+			t.Errorf("Should find the cluster scoped namespace resource, got %+v", items)
+		}
+	})
+
+	t.Run("FetchSeverityMatrix", func(t *testing.T) {
+		matrix, err := store.FetchSeverityMatrix(v1.Filter{})
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+		if len(matrix.Sources) != 1 || matrix.Sources[0].Name != "Kyverno" || matrix.Sources[0].Total != 4 {
+			t.Fatalf("Should count all results of the source, got %+v", matrix.Sources)
+		}
+		if matrix.Sources[0].Severities[v1alpha2.SeverityHigh] != 2 || matrix.Sources[0].Severities[v1alpha2.SeverityMedium] != 1 || matrix.Sources[0].Severities[v2.SeverityUnknown] != 1 {
+			t.Errorf("Unexpected severity counts of the source: %+v", matrix.Sources[0].Severities)
+		}
+		if len(matrix.Namespaces) != 1 || matrix.Namespaces[0].Name != "test" || matrix.Namespaces[0].Total != 2 {
+			t.Errorf("Should only count namespaced results per namespace, got %+v", matrix.Namespaces)
+		}
+
+		matrix, _ = store.FetchSeverityMatrix(v1.Filter{Status: []string{v1alpha2.StatusFail}})
+		if matrix.Sources[0].Total != 2 || matrix.Namespaces[0].Severities[v1alpha2.SeverityHigh] != 1 || matrix.Namespaces[0].Severities[v1alpha2.SeverityCritical] != 0 {
+			t.Errorf("Should count the failed results, got %+v, %+v", matrix.Sources[0], matrix.Namespaces[0])
+		}
+	})
+
+	t.Run("FetchPolicyStatus", func(t *testing.T) {
+		items, err := store.FetchPolicyStatus(v1.Filter{})
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+		if len(items) != 2 {
+			t.Fatalf("Should find 2 policies, got %d", len(items))
+		}
+		if items[0].Policy != "require-ns-GetLabels()" || items[0].Pass != 1 || items[0].Fail != 1 || len(items[0].Namespaces) != 0 || items[0].Resources != 1 {
+			t.Errorf("Should return the cluster scoped policy with the failing namespace as affected resource, got %+v", items[0])
+		}
+		if items[1].Severities[v1alpha2.SeverityHigh] != 1 || len(items[1].Namespaces) != 1 || items[1].Namespaces[0] != "test" || len(items[1].Sources) != 1 {
+			t.Errorf("Should count the severities of failing results and the affected namespaces, got %+v", items[1])
+		}
+
+		items, _ = store.FetchPolicyStatus(v1.Filter{Namespaces: []string{"test"}})
+		if len(items) != 1 || items[0].Policy != "require-requests-and-limits-required" {
+			t.Errorf("Should filter the policies by namespace, got %+v", items)
+		}
+	})
+
 	t.Run("FetchNamespacedStatusCounts", func(t *testing.T) {
 		items, err := store.FetchNamespacedStatusCounts(v1.Filter{ReportLabel: map[string]string{"app": "policy-reporter"}})
 		if err != nil {
@@ -248,6 +328,25 @@ func Test_PolicyReportStore(t *testing.T) {
 		}
 	})
 
+	t.Run("FetchScoreCounts", func(t *testing.T) {
+		items, err := store.FetchScoreCounts(v1.Filter{Namespaces: []string{"test"}})
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+		if len(items) == 0 {
+			t.Fatalf("Expected score counts for namespace test")
+		}
+
+		for _, item := range items {
+			if item.Namespace != "test" {
+				t.Errorf("Expected only counts of namespace test, got %s", item.Namespace)
+			}
+			if item.Count < 1 {
+				t.Errorf("Expected a count for %s/%s/%s", item.Category, item.Severity, item.Status)
+			}
+		}
+	})
+
 	t.Run("FetchNamespacedStatusCounts with StatusFilter", func(t *testing.T) {
 		items, err := store.FetchNamespacedStatusCounts(v1.Filter{Status: []string{v1alpha2.StatusPass}, ReportLabel: map[string]string{"app": "policy-reporter"}})
 		if err != nil {
@@ -356,6 +455,48 @@ func Test_PolicyReportStore(t *testing.T) {
 		}
 	})
 
+	t.Run("FetchNamespacedResults with IDFilter", func(t *testing.T) {
+		all, _ := store.FetchNamespacedResults(v1.Filter{}, pagination)
+
+		items, err := store.FetchNamespacedResults(v1.Filter{IDs: []string{all[0].ID}}, pagination)
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		if len(items) != 1 || items[0].ID != all[0].ID {
+			t.Fatalf("Should return the result with ID %s", all[0].ID)
+		}
+	})
+
+	t.Run("FetchNamespacedResults with PolicyPatterns", func(t *testing.T) {
+		exact, _ := store.FetchNamespacedResults(v1.Filter{Policies: []string{fixtures.FailResult.Policy}}, pagination)
+		if len(exact) == 0 {
+			t.Fatal("expected results of the exact policy filter")
+		}
+
+		for _, policies := range [][]string{
+			{"REQUIRE-requests-*"},
+			{"regex:^require-.+-required$"},
+			{"unknown", "regex:^unknown$", "require-*-limits-required"},
+		} {
+			items, err := store.FetchNamespacedResults(v1.Filter{Policies: policies}, pagination)
+			if err != nil {
+				t.Fatalf("Unexpected Error: %s", err)
+			}
+			if len(items) != len(exact) {
+				t.Errorf("expected %d results for %v, got %d", len(exact), policies, len(items))
+			}
+		}
+
+		items, err := store.FetchNamespacedResults(v1.Filter{Policies: []string{"regex:^Require"}}, pagination)
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+		if len(items) != 0 {
+			t.Errorf("expected case sensitive regex match, got %d results", len(items))
+		}
+	})
+
 	t.Run("CountNamespacedResults", func(t *testing.T) {
 		count, err := store.CountNamespacedResults(v1.Filter{ReportLabel: map[string]string{"app": "policy-reporter"}})
 		if err != nil {
@@ -406,6 +547,84 @@ func Test_PolicyReportStore(t *testing.T) {
 		}
 	})
 
+	t.Run("FetchNamespacedResults with Expression", func(t *testing.T) {
+		items, err := store.FetchNamespacedResults(v1.Filter{Expression: `severity>=medium AND kind~"deploy*" AND namespace!=kube-system`}, pagination)
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		if len(items) != 1 {
+			t.Fatalf("Should return 1 namespaced result, got %d", len(items))
+		}
+		if items[0].Kind != "Deployment" {
+			t.Fatalf("result with kind Deployment expected")
+		}
+	})
+
+	t.Run("FetchNamespacedResults with Expression::Not", func(t *testing.T) {
+		items, err := store.FetchNamespacedResults(v1.Filter{Expression: `NOT (status=fail OR policy~"*registry*")`}, pagination)
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		if len(items) != 1 {
+			t.Fatalf("Should return 1 namespaced result, got %d", len(items))
+		}
+		if items[0].Status != v1alpha2.StatusPass {
+			t.Fatalf("result with status pass expected")
+		}
+	})
+
+	t.Run("CountNamespacedResults with Expression::FullText", func(t *testing.T) {
+		count, err := store.CountNamespacedResults(v1.Filter{Expression: `"requests and limits" status=fail`})
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		if count != 1 {
+			t.Fatalf("Should return 1 namespaced result, got %d", count)
+		}
+	})
+
+	t.Run("FetchClusterResults with Expression", func(t *testing.T) {
+		items, err := store.FetchClusterResults(v1.Filter{Expression: `severity>medium`}, pagination)
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		if len(items) != 1 {
+			t.Fatalf("Should return 1 cluster result, got %d", len(items))
+		}
+		if items[0].Severity != v1alpha2.SeverityHigh {
+			t.Fatalf("result with severity high expected")
+		}
+	})
+
+	t.Run("FetchClusterResults with invalid Expression", func(t *testing.T) {
+		items, err := store.FetchClusterResults(v1.Filter{Expression: `severity>=unknown`}, pagination)
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		if len(items) != 0 {
+			t.Fatalf("Should return no results for an invalid expression, got %d", len(items))
+		}
+	})
+
+	t.Run("FetchClusterResults with Expression::CustomSeverity", func(t *testing.T) {
+		severity.Use([]severity.Level{{Name: "cvss-6", Above: "medium"}})
+		defer severity.Use(nil)
+
+		items, err := store.FetchClusterResults(v1.Filter{Expression: `severity>cvss-6`}, pagination)
+		if err != nil {
+			t.Fatalf("Unexpected Error: %s", err)
+		}
+
+		if len(items) != 1 || items[0].Severity != v1alpha2.SeverityHigh {
+			t.Fatalf("result with severity high expected, got %d results", len(items))
+		}
+	})
+
 	t.Run("FetchClusterResults", func(t *testing.T) {
 		items, err := store.FetchClusterResults(v1.Filter{Status: []string{v1alpha2.StatusPass, v1alpha2.StatusFail}, ReportLabel: map[string]string{"app": "policy-reporter"}}, pagination)
 		if err != nil {
@@ -564,7 +783,7 @@ func Test_PolicyReportStore(t *testing.T) {
 	})
 
 	t.Run("FetchNamespacedSources", func(t *testing.T) {
-		items, err := store.FetchNamespacedSources()
+		items, err := store.FetchNamespacedSources(v1.Filter{})
 		if err != nil {
 			t.Fatalf("Unexpected Error: %s", err)
 		}
@@ -576,6 +795,32 @@ func Test_PolicyReportStore(t *testing.T) {
 		}
 	})
 
+	t.Run("Lists scoped by Namespaces", func(t *testing.T) {
+		if items, _ := store.FetchNamespacedPolicies(v1.Filter{Namespaces: []string{"other"}}); len(items) != 0 {
+			t.Errorf("Should find no policies outside of the namespace filter, got %v", items)
+		}
+		if items, _ := store.FetchNamespacedRules(v1.Filter{Namespaces: []string{"other"}}); len(items) != 0 {
+			t.Errorf("Should find no rules outside of the namespace filter, got %v", items)
+		}
+		if items, _ := store.FetchNamespacedSources(v1.Filter{Namespaces: []string{"other"}}); len(items) != 0 {
+			t.Errorf("Should find no sources outside of the namespace filter, got %v", items)
+		}
+		if items, _ := store.FetchNamespacedSources(v1.Filter{Namespaces: []string{"test"}}); len(items) != 1 {
+			t.Errorf("Should find the source of the namespace filter, got %v", items)
+		}
+
+		all, _ := store.FetchCategories(v1.Filter{})
+		if items, _ := store.FetchCategories(v1.Filter{Namespaces: []string{"other"}}); len(items) != 0 {
+			t.Errorf("Should find no categories outside of the namespace filter, got %v", items)
+		}
+		if items, _ := store.FetchCategories(v1.Filter{Namespaces: []string{"test", report.ClusterScope}}); len(items) != len(all) {
+			t.Errorf("Should find the categories of namespaced and cluster scoped results, got %v of %v", items, all)
+		}
+		if items, _ := store.FetchCategories(v1.Filter{Namespaces: []string{"test"}}); len(items) == 0 || len(items) >= len(all) {
+			t.Errorf("Should find only the categories of namespaced results, got %v of %v", items, all)
+		}
+	})
+
 	t.Run("NamespacedResults: ReportLabel Filter", func(t *testing.T) {
 		items, err := store.FetchNamespacedResults(v1.Filter{ReportLabel: map[string]string{"app": "policy-reporter"}}, pagination)
 		if err != nil {
@@ -747,3 +992,157 @@ func Test_PolicyReportStore(t *testing.T) {
 		}
 	})
 }
+
+func Test_TeamFilter(t *testing.T) {
+	db, _ := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "team.db"))
+	defer db.Close()
+	store, _ := sqlite3.NewPolicyReportStore(db)
+
+	owned := fixtures.FailPodResult
+	owned.Properties = map[string]string{v1alpha2.TeamKey: "Payments"}
+
+	store.Add(&v1alpha2.PolicyReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "polr-team", Namespace: "test", CreationTimestamp: metav1.Now()},
+		Results:    []v1alpha2.PolicyReportResult{fixtures.FailResult, owned},
+	})
+	defer store.CleanUp()
+
+	items, err := store.FetchNamespacedResults(v1.Filter{Teams: []string{"payments"}}, pagination)
+	if err != nil {
+		t.Fatalf("Unexpected Error: %s", err)
+	}
+
+	if len(items) != 1 || items[0].ID != owned.GetID() {
+		t.Fatalf("Should return the result of the team, got %v", items)
+	}
+}
+
+func Test_UpdateRewritesOnlyChangedResults(t *testing.T) {
+	db, _ := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "upsert.db"))
+	defer db.Close()
+	store, _ := sqlite3.NewPolicyReportStore(db)
+
+	if err := store.Add(dreport); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	before, err := store.FetchUnresolvedResults(time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(before) != 2 {
+		t.Fatalf("Expected duplicated results to be stored once, got %d results", len(before))
+	}
+
+	changed := preport.DeepCopy()
+	changed.Results[0].Message = "changed message"
+
+	if err := store.Update(changed); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	r, _ := store.Get(preport.GetID())
+	if len(r.GetResults()) != 1 {
+		t.Fatalf("Expected removed results to be deleted, got %d results", len(r.GetResults()))
+	}
+	if r.GetResults()[0].Message != "changed message" {
+		t.Errorf("Expected changed result to be rewritten, got message: %s", r.GetResults()[0].Message)
+	}
+
+	after, err := store.FetchUnresolvedResults(time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(after) != 1 || !after[0].FirstSeen.Equal(before[0].FirstSeen) {
+		t.Errorf("Expected first seen to be kept for changed results")
+	}
+
+	if err := store.Update(changed); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if r, _ := store.Get(preport.GetID()); len(r.GetResults()) != 1 {
+		t.Errorf("Expected unchanged update to keep the results, got %d results", len(r.GetResults()))
+	}
+}
+
+func Test_Reports(t *testing.T) {
+	db, _ := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "reports.db"))
+	defer db.Close()
+	store, _ := sqlite3.NewPolicyReportStore(db)
+
+	store.Add(scopeReport)
+	store.Add(creport)
+
+	t.Run("FetchReportPage", func(t *testing.T) {
+		items, cursor, err := store.FetchReportPage(v1.Filter{}, v2.Pagination{Limit: 1, SortBy: "name"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(items) != 1 || items[0].Name != "cpolr" || items[0].Kind != "ClusterPolicyReport" || cursor == nil {
+			t.Fatalf("Expected first page with the ClusterPolicyReport, got %+v", items)
+		}
+		if items[0].Labels["scope"] != "cluster" || items[0].Created == 0 {
+			t.Errorf("Expected report metadata, got %+v", items[0])
+		}
+
+		items, _, _ = store.FetchReportPage(v1.Filter{}, v2.Pagination{Limit: 1, SortBy: "name", Cursor: cursor})
+		if len(items) != 1 || items[0].Name != "polr-scope-test" {
+			t.Fatalf("Expected second page with the PolicyReport, got %+v", items)
+		}
+		if items[0].Scope == nil || items[0].Scope.Kind != "Deployment" {
+			t.Errorf("Expected report scope, got %+v", items[0].Scope)
+		}
+
+		items, _, _ = store.FetchReportPage(v1.Filter{Namespaces: []string{""}}, v2.Pagination{Limit: 10})
+		if len(items) != 1 || items[0].Namespace != "" {
+			t.Errorf("Expected only the ClusterPolicyReport, got %+v", items)
+		}
+
+		if count, _ := store.CountReports(v1.Filter{}); count != 2 {
+			t.Errorf("Expected 2 reports, got %d", count)
+		}
+	})
+
+	t.Run("FetchReport", func(t *testing.T) {
+		r, err := store.FetchReport(scopeReport.GetID(), false)
+		if err != nil || r == nil {
+			t.Fatalf("Expected report, got %v", err)
+		}
+		if r.Raw != nil {
+			t.Errorf("Expected no raw report")
+		}
+
+		r, _ = store.FetchReport(creport.GetID(), true)
+		raw := &v1alpha2.ClusterPolicyReport{}
+		if err := json.Unmarshal(r.Raw, raw); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if raw.Kind != "ClusterPolicyReport" || raw.Name != "cpolr" || len(raw.Results) != 2 {
+			t.Errorf("Expected raw ClusterPolicyReport with results, got %+v", raw)
+		}
+
+		if r, _ := store.FetchReport("unknown", true); r != nil {
+			t.Errorf("Expected no report for unknown ID")
+		}
+	})
+}
+
+func Test_ObserveWrites(t *testing.T) {
+	db, _ := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "observe.db"))
+	defer db.Close()
+	store, _ := sqlite3.NewPolicyReportStore(db)
+
+	operations := make([]string, 0, 3)
+	store.ObserveWrites(func(operation string, duration time.Duration) {
+		operations = append(operations, operation)
+	})
+
+	_ = store.Add(preport)
+	_ = store.Update(preport)
+	_ = store.Remove(preport.GetID())
+
+	if strings.Join(operations, ",") != "add,update,remove" {
+		t.Errorf("expected observed add, update and remove, got %v", operations)
+	}
+}
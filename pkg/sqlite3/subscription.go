@@ -0,0 +1,88 @@
+package sqlite3
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/subscription"
+)
+
+const subscriptionColumns = "email, namespace, token, confirmed, created"
+
+// SaveSubscription creates or updates the subscription of the email address and namespace
+func (s *policyReportStore) SaveSubscription(sub subscription.Subscription) error {
+	confirmed := 0
+	if sub.Confirmed {
+		confirmed = 1
+	}
+
+	query := s.dialect.Upsert("INSERT INTO policy_report_subscription ("+subscriptionColumns+") VALUES (?,?,?,?,?)", "email, namespace", []string{"token", "confirmed", "created"})
+	_, err := s.exec(query, sub.Email, sub.Namespace, sub.Token, confirmed, sub.Created.Unix())
+
+	return err
+}
+
+// FetchSubscriptions returns the subscriptions of the namespace ordered by email, of all namespaces if empty
+func (s *policyReportStore) FetchSubscriptions(namespace string) ([]subscription.Subscription, error) {
+	query := "SELECT " + subscriptionColumns + " FROM policy_report_subscription"
+	args := make([]interface{}, 0, 1)
+	if namespace != "" {
+		query += " WHERE namespace=$1"
+		args = append(args, namespace)
+	}
+
+	rows, err := s.query(query+" ORDER BY namespace, email", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]subscription.Subscription, 0)
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return list, err
+		}
+
+		list = append(list, sub)
+	}
+
+	return list, rows.Err()
+}
+
+// FetchSubscription returns the subscription of the token
+func (s *policyReportStore) FetchSubscription(token string) (subscription.Subscription, error) {
+	sub, err := scanSubscription(s.queryRow("SELECT "+subscriptionColumns+" FROM policy_report_subscription WHERE token=$1", token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return sub, subscription.ErrNotFound
+	}
+
+	return sub, err
+}
+
+// DeleteSubscription removes the subscription of the email address and namespace
+func (s *policyReportStore) DeleteSubscription(email, namespace string) (bool, error) {
+	result, err := s.exec("DELETE FROM policy_report_subscription WHERE email=$1 AND namespace=$2", email, namespace)
+	if err != nil {
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+
+	return count > 0, err
+}
+
+func scanSubscription(row reportScanner) (subscription.Subscription, error) {
+	var confirmed, created int64
+
+	sub := subscription.Subscription{}
+	if err := row.Scan(&sub.Email, &sub.Namespace, &sub.Token, &confirmed, &created); err != nil {
+		return sub, err
+	}
+
+	sub.Confirmed = confirmed == 1
+	sub.Created = time.Unix(created, 0)
+
+	return sub, nil
+}
@@ -0,0 +1,85 @@
+package sqlite3_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/sqlite3"
+	"github.com/kyverno/policy-reporter/pkg/subscription"
+)
+
+func Test_Subscriptions(t *testing.T) {
+	db, _ := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "subscription.db"))
+	defer db.Close()
+	store, _ := sqlite3.NewPolicyReportStore(db)
+
+	now := time.Now()
+
+	for _, sub := range []subscription.Subscription{
+		{Email: "dev@example.com", Namespace: "test", Token: "token-1", Created: now},
+		{Email: "ops@example.com", Namespace: "test", Token: "token-2", Confirmed: true, Created: now},
+		{Email: "dev@example.com", Namespace: "default", Token: "token-3", Created: now},
+	} {
+		if err := store.SaveSubscription(sub); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+
+	t.Run("Fetch by namespace", func(t *testing.T) {
+		list, err := store.FetchSubscriptions("test")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(list) != 2 {
+			t.Errorf("Expected 2 subscriptions, got %d", len(list))
+		}
+
+		all, _ := store.FetchSubscriptions("")
+		if len(all) != 3 {
+			t.Errorf("Expected 3 subscriptions, got %d", len(all))
+		}
+	})
+
+	t.Run("Fetch by token", func(t *testing.T) {
+		sub, err := store.FetchSubscription("token-2")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if sub.Email != "ops@example.com" || sub.Namespace != "test" || !sub.Confirmed {
+			t.Errorf("Unexpected subscription: %+v", sub)
+		}
+
+		if _, err := store.FetchSubscription("unknown"); !errors.Is(err, subscription.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Update existing", func(t *testing.T) {
+		if err := store.SaveSubscription(subscription.Subscription{Email: "dev@example.com", Namespace: "test", Token: "token-4", Confirmed: true, Created: now}); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		if _, err := store.FetchSubscription("token-1"); !errors.Is(err, subscription.ErrNotFound) {
+			t.Errorf("Expected the previous token to be replaced")
+		}
+
+		sub, _ := store.FetchSubscription("token-4")
+		if !sub.Confirmed {
+			t.Errorf("Expected confirmed subscription")
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		removed, err := store.DeleteSubscription("dev@example.com", "default")
+		if err != nil || !removed {
+			t.Fatalf("Expected subscription removed, got %v %v", removed, err)
+		}
+
+		removed, _ = store.DeleteSubscription("dev@example.com", "default")
+		if removed {
+			t.Errorf("Expected false for unknown subscriptions")
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	api "github.com/kyverno/policy-reporter/pkg/api/v1"
+	v2 "github.com/kyverno/policy-reporter/pkg/api/v2"
+)
+
+// EnableTombstones records a tombstone with the deletion time and the last summary for each removed PolicyReport
+func (s *policyReportStore) EnableTombstones() {
+	s.tombstones = true
+}
+
+// recordTombstone copies the metadata and summary of the report before it is removed, a recreated report replaces its tombstone
+func (s *policyReportStore) recordTombstone(tx *sql.Tx, id string, deleted time.Time) error {
+	if err := s.execPrepared(tx, "DELETE FROM policy_report_tombstone WHERE report_id=?", id); err != nil {
+		return err
+	}
+
+	return s.execPrepared(tx, `
+    INSERT INTO policy_report_tombstone(report_id, type, namespace, name, source, labels, pass, skip, warn, fail, error, created, deleted)
+    SELECT id, type, namespace, name, source, labels, pass, skip, warn, fail, error, created, ? FROM policy_report WHERE id=?`, deleted.Unix(), id)
+}
+
+// PruneTombstones removes all tombstones of reports deleted before the given time
+func (s *policyReportStore) PruneTombstones(before time.Time) error {
+	_, err := s.exec("DELETE FROM policy_report_tombstone WHERE deleted < ?", before.Unix())
+
+	return err
+}
+
+// FetchTombstones of the reports deleted after since by namespace, source and report label filter, latest deletion first
+func (s *policyReportStore) FetchTombstones(filter api.Filter, since time.Time) ([]*v2.Tombstone, error) {
+	where, args := s.generateFilterWhere(api.Filter{Namespaces: filter.Namespaces, Sources: filter.Sources, ReportLabel: filter.ReportLabel}, []string{"report_namespaces", "report_sources"})
+	if len(where) > 0 {
+		where += " AND "
+	}
+
+	where += fmt.Sprintf("report.deleted > $%d", len(args)+1)
+
+	return s.fetchTombstones(where+` ORDER BY report.deleted DESC, report.report_id ASC`, append(args, since.Unix())...)
+}
+
+// fetchDeletedReports of the namespace deleted within from and to
+func (s *policyReportStore) fetchDeletedReports(namespace string, from, to int64) ([]*v2.Tombstone, error) {
+	return s.fetchTombstones(`report.namespace = $1 AND report.deleted > $2 AND report.deleted <= $3 ORDER BY report.deleted ASC, report.report_id ASC`, namespace, from, to)
+}
+
+func (s *policyReportStore) fetchTombstones(condition string, args ...interface{}) ([]*v2.Tombstone, error) {
+	list := make([]*v2.Tombstone, 0)
+
+	rows, err := s.query(`
+    SELECT report.report_id, report.type, report.namespace, report.name, report.source, report.labels, report.pass, report.skip, report.warn, report.fail, report.error, report.created, report.deleted
+    FROM policy_report_tombstone as report WHERE `+condition, args...)
+	if err != nil {
+		return list, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var labels string
+		var created sql.NullInt64
+
+		t := &v2.Tombstone{}
+		if err := rows.Scan(&t.ReportID, &t.Kind, &t.Namespace, &t.Name, &t.Source, &labels, &t.Pass, &t.Skip, &t.Warn, &t.Fail, &t.Error, &created, &t.Deleted); err != nil {
+			return list, err
+		}
+
+		t.Labels = convertJSONToMap(labels)
+		t.Created = created.Int64
+
+		list = append(list, t)
+	}
+
+	return list, rows.Err()
+}
+
+// RunTombstonePruning removes tombstones older than the retention in the given interval until the context is canceled
+func RunTombstonePruning(ctx context.Context, store PolicyReportStore, retention, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := store.PruneTombstones(time.Now().Add(-retention)); err != nil {
+			logger.Errorf("failed to prune PolicyReport tombstones: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package sqlite3_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/sqlite3"
+)
+
+func Test_Tombstones(t *testing.T) {
+	db, _ := sqlite3.NewDatabase(filepath.Join(t.TempDir(), "tombstone.db"))
+	defer db.Close()
+	store, _ := sqlite3.NewPolicyReportStore(db)
+	store.EnableTombstones()
+
+	start := time.Now().Add(-time.Minute)
+
+	store.Add(preport)
+	store.Add(creport)
+
+	t.Run("Record Tombstones", func(t *testing.T) {
+		if err := store.Remove(preport.GetID()); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if err := store.Remove(creport.GetID()); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		tombstones, err := store.FetchTombstones(v1.Filter{}, start)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(tombstones) != 2 {
+			t.Fatalf("Expected 2 tombstones, got %d", len(tombstones))
+		}
+
+		tombstones, _ = store.FetchTombstones(v1.Filter{Namespaces: []string{"test"}}, start)
+		if len(tombstones) != 1 {
+			t.Fatalf("Expected 1 tombstone of namespace test, got %d", len(tombstones))
+		}
+
+		tombstone := tombstones[0]
+		if tombstone.ReportID != preport.GetID() || tombstone.Kind != "PolicyReport" || tombstone.Name != "polr-test" || tombstone.Fail != 1 {
+			t.Errorf("Unexpected tombstone %+v", tombstone)
+		}
+		if tombstone.Labels["app"] != "policy-reporter" || tombstone.Deleted < start.Unix() {
+			t.Errorf("Expected labels and deletion time, got %+v", tombstone)
+		}
+
+		diff, err := store.FetchResultDiff("test", start, time.Now().Add(time.Minute))
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(diff.Deleted) != 1 || diff.Deleted[0].ReportID != preport.GetID() {
+			t.Errorf("Expected the deleted report in the diff, got %+v", diff.Deleted)
+		}
+	})
+
+	t.Run("Recreated Report", func(t *testing.T) {
+		store.Add(preport)
+
+		if tombstones, _ := store.FetchTombstones(v1.Filter{Namespaces: []string{"test"}}, start); len(tombstones) != 0 {
+			t.Errorf("Expected the tombstone to be removed for the recreated report, got %d", len(tombstones))
+		}
+	})
+
+	t.Run("Pruning", func(t *testing.T) {
+		store.Update(dreport)
+
+		pruned, err := store.Prune(sqlite3.Limits{MaxRows: 1})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if pruned.Rows != 1 {
+			t.Fatalf("Expected the report to be pruned, got %d", pruned.Rows)
+		}
+
+		if tombstones, _ := store.FetchTombstones(v1.Filter{Namespaces: []string{"test"}}, start); len(tombstones) != 0 {
+			t.Errorf("Expected no tombstone for pruned reports, got %d", len(tombstones))
+		}
+
+		if err := store.PruneTombstones(time.Now().Add(time.Minute)); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		if tombstones, _ := store.FetchTombstones(v1.Filter{}, start); len(tombstones) != 0 {
+			t.Errorf("Expected all tombstones to be pruned, got %d", len(tombstones))
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package sqlite3
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	api "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/triage"
+)
+
+const triageColumns = "result_id, status, comments, updated, updated_by"
+
+// EnableTriage adds the triage of each result to the fetched result lists
+func (s *policyReportStore) EnableTriage() {
+	s.triage = true
+}
+
+// SaveTriage creates or replaces the triage of the result
+func (s *policyReportStore) SaveTriage(t triage.Triage) error {
+	comments, err := json.Marshal(t.Comments)
+	if err != nil {
+		return err
+	}
+
+	query := s.dialect.Upsert("INSERT INTO policy_report_triage ("+triageColumns+") VALUES (?,?,?,?,?)", "result_id", []string{"status", "comments", "updated", "updated_by"})
+	_, err = s.exec(query, t.ResultID, string(t.Status), string(comments), t.Updated, t.UpdatedBy)
+
+	return err
+}
+
+// FetchTriage returns the triage of the result
+func (s *policyReportStore) FetchTriage(resultID string) (triage.Triage, error) {
+	t, err := scanTriage(s.queryRow("SELECT "+triageColumns+" FROM policy_report_triage WHERE result_id=$1", resultID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return t, triage.ErrNotFound
+	}
+
+	return t, err
+}
+
+// FetchTriages returns the triage of the given results by result ID
+func (s *policyReportStore) FetchTriages(resultIDs []string) (map[string]triage.Triage, error) {
+	triages := make(map[string]triage.Triage)
+
+	for i := 0; i < len(resultIDs); i += 500 {
+		end := i + 500
+		if end > len(resultIDs) {
+			end = len(resultIDs)
+		}
+
+		args := make([]interface{}, 0, end-i)
+		for _, id := range resultIDs[i:end] {
+			args = append(args, id)
+		}
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", end-i), ",")
+		rows, err := s.query("SELECT "+triageColumns+" FROM policy_report_triage WHERE result_id IN ("+placeholders+")", args...)
+		if err != nil {
+			return triages, err
+		}
+
+		for rows.Next() {
+			t, err := scanTriage(rows)
+			if err != nil {
+				rows.Close()
+				return triages, err
+			}
+
+			triages[t.ResultID] = t
+		}
+
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return triages, err
+		}
+	}
+
+	return triages, nil
+}
+
+// withTriage adds the triage to the results if enabled, the same result ID may occur in several reports
+func (s *policyReportStore) withTriage(list []*api.ListResult) ([]*api.ListResult, error) {
+	if !s.triage || len(list) == 0 {
+		return list, nil
+	}
+
+	ids := make([]string, 0, len(list))
+	seen := make(map[string]bool, len(list))
+	for _, result := range list {
+		if !seen[result.ID] {
+			seen[result.ID] = true
+			ids = append(ids, result.ID)
+		}
+	}
+
+	triages, err := s.FetchTriages(ids)
+	if err != nil {
+		return list, err
+	}
+
+	for _, result := range list {
+		if t, ok := triages[result.ID]; ok {
+			result.Triage = &t
+		}
+	}
+
+	return list, nil
+}
+
+func scanTriage(row reportScanner) (triage.Triage, error) {
+	var status string
+	var comments, updatedBy sql.NullString
+
+	t := triage.Triage{}
+	if err := row.Scan(&t.ResultID, &status, &comments, &t.Updated, &updatedBy); err != nil {
+		return t, err
+	}
+
+	t.Status = triage.Status(status)
+	t.UpdatedBy = updatedBy.String
+
+	if comments.String != "" {
+		json.Unmarshal([]byte(comments.String), &t.Comments)
+	}
+
+	return t, nil
+}
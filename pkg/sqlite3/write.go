@@ -0,0 +1,394 @@
+package sqlite3
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/segmentio/fasthash/fnv1a"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+// WriteObserver receives the operation and the duration of each write of a PolicyReport
+type WriteObserver func(operation string, duration time.Duration)
+
+// resultChunkSize limits the rows of a single INSERT or DELETE statement
+const resultChunkSize = 50
+
+const resultValuesSQL = "(?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)"
+
+// resultMessageIndex and resultPropertiesIndex are the positions of the encrypted columns in the values of a resultRow
+const (
+	resultMessageIndex    = 4
+	resultPropertiesIndex = 15
+)
+
+// statements caches prepared statements, they are reused for all writes of the store
+type statements struct {
+	mx    *sync.Mutex
+	cache map[string]*sql.Stmt
+}
+
+func newStatements() *statements {
+	return &statements{mx: new(sync.Mutex), cache: make(map[string]*sql.Stmt)}
+}
+
+// storedResult is the persisted state of a result used to detect changes
+type storedResult struct {
+	checksum  string
+	firstSeen sql.NullInt64
+}
+
+// resultRow are the column values of a result
+type resultRow struct {
+	id       string
+	checksum string
+	values   []interface{}
+}
+
+// Add a PolicyReport to the Store
+func (s *policyReportStore) Add(r v1alpha2.ReportInterface) error {
+	defer s.observe("add", time.Now())
+
+	sum := r.GetSummary()
+
+	// an external database can already contain the report, e.g. written by another replica
+	err := s.write(
+		r,
+		s.dialect.Upsert(reportInsertSQL, "id", reportUpdateColumns),
+		r.GetID(),
+		report.GetType(r),
+		r.GetNamespace(),
+		r.GetSource(),
+		r.GetName(),
+		convertMapToJSON(r.GetLabels()),
+		convertSliveToJSON(r.GetKinds()),
+		convertSliveToJSON(r.GetSeverities()),
+		sum.Pass,
+		sum.Skip,
+		sum.Warn,
+		sum.Fail,
+		sum.Error,
+		r.GetCreationTimestamp().Unix(),
+		time.Now().Unix(),
+		convertScopeToJSON(r.GetScope()),
+	)
+	if err != nil {
+		return err
+	}
+
+	if s.tombstones {
+		if _, err := s.exec("DELETE FROM policy_report_tombstone WHERE report_id=?", r.GetID()); err != nil {
+			logger.Errorf("failed to remove tombstone of recreated PolicyReport: %s", err)
+		}
+	}
+
+	return s.recordHistory(r)
+}
+
+func (s *policyReportStore) Update(r v1alpha2.ReportInterface) error {
+	defer s.observe("update", time.Now())
+
+	sum := r.GetSummary()
+
+	err := s.write(
+		r,
+		"UPDATE policy_report SET labels=?, kinds=?, severities=?, pass=?, skip=?, warn=?, fail=?, error=?, created=?, updated=?, scope=? WHERE id=?",
+		convertMapToJSON(r.GetLabels()),
+		convertSliveToJSON(r.GetKinds()),
+		convertSliveToJSON(r.GetSeverities()),
+		sum.Pass,
+		sum.Skip,
+		sum.Warn,
+		sum.Fail,
+		sum.Error,
+		r.GetCreationTimestamp().Unix(),
+		time.Now().Unix(),
+		convertScopeToJSON(r.GetScope()),
+		r.GetID(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.recordHistory(r)
+}
+
+// Remove a PolicyReport with the given Type and ID from the Store
+func (s *policyReportStore) Remove(id string) error {
+	return s.remove(id, s.tombstones)
+}
+
+func (s *policyReportStore) remove(id string, tombstone bool) error {
+	defer s.observe("remove", time.Now())
+
+	if err := s.recordRemoval(id); err != nil {
+		logger.Errorf("failed to record history of removed PolicyReport: %s", err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if tombstone {
+		if err := s.recordTombstone(tx, id, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	if err := s.execPrepared(tx, "DELETE FROM policy_report WHERE id=?", id); err != nil {
+		return err
+	}
+
+	if err := s.execPrepared(tx, "DELETE FROM policy_report_result WHERE policy_report_id=?", id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ObserveWrites calls the observer with the operation and the duration of each add, update and remove
+func (s *policyReportStore) ObserveWrites(observer WriteObserver) {
+	s.observer = observer
+}
+
+func (s *policyReportStore) observe(operation string, start time.Time) {
+	duration := time.Since(start)
+
+	logger.Debugf("store %s took %s", operation, duration)
+
+	if s.observer != nil {
+		s.observer(operation, duration)
+	}
+}
+
+// write persists the report and its results in one transaction,
+// only new and changed results are written, unchanged results are kept with their first_seen
+func (s *policyReportStore) write(r v1alpha2.ReportInterface, reportQuery string, args ...interface{}) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := s.execPrepared(tx, reportQuery, args...); err != nil {
+		return err
+	}
+
+	stored, err := s.storedResults(tx, r.GetID())
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	rows := resultRows(r)
+
+	stale := make([]interface{}, 0)
+	inserts := make([]resultRow, 0, len(rows))
+
+	current := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		current[row.id] = true
+
+		previous, ok := stored[row.id]
+		if ok && previous.checksum == row.checksum {
+			continue
+		}
+		if ok {
+			stale = append(stale, row.id)
+		}
+
+		seen := now
+		if ok && previous.firstSeen.Valid {
+			seen = previous.firstSeen.Int64
+		}
+
+		// the checksum is based on the plain message, the encryption uses a random nonce for each write
+		message, err := s.encrypt(row.values[resultMessageIndex].(string))
+		if err != nil {
+			return err
+		}
+
+		props, err := s.encryptProperties(row.values[resultPropertiesIndex].(string))
+		if err != nil {
+			return err
+		}
+
+		row.values[resultMessageIndex] = message
+		row.values[resultPropertiesIndex] = props
+		row.values = append(row.values, seen, row.checksum)
+		inserts = append(inserts, row)
+	}
+
+	for id := range stored {
+		if !current[id] {
+			stale = append(stale, id)
+		}
+	}
+
+	for _, ids := range chunkSlice(stale, resultChunkSize) {
+		query := "DELETE FROM policy_report_result WHERE policy_report_id=? AND id IN (?" + strings.Repeat(",?", len(ids)-1) + ")"
+
+		if err := s.execPrepared(tx, query, append([]interface{}{r.GetID()}, ids...)...); err != nil {
+			return err
+		}
+	}
+
+	for _, list := range chunkSlice(inserts, resultChunkSize) {
+		query := s.dialect.InsertIgnore(resultInsertBaseSQL + resultValuesSQL + strings.Repeat(","+resultValuesSQL, len(list)-1))
+
+		vals := make([]interface{}, 0, len(list)*19)
+		for _, row := range list {
+			vals = append(vals, row.values...)
+		}
+
+		if err := s.execPrepared(tx, query, vals...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// storedResults returns the persisted state of all results of the report
+func (s *policyReportStore) storedResults(tx *sql.Tx, reportID string) (map[string]storedResult, error) {
+	list := make(map[string]storedResult)
+
+	rows, err := s.queryTx(tx, "SELECT id, checksum, first_seen FROM policy_report_result WHERE policy_report_id=$1", reportID)
+	if err != nil {
+		return list, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var checksum sql.NullString
+		var seen sql.NullInt64
+
+		if err := rows.Scan(&id, &checksum, &seen); err != nil {
+			return list, err
+		}
+
+		list[id] = storedResult{checksum: checksum.String, firstSeen: seen}
+	}
+
+	return list, rows.Err()
+}
+
+// resultRows converts the results into their column values without first_seen and checksum,
+// duplicated IDs are ignored like with INSERT IGNORE
+func resultRows(r v1alpha2.ReportInterface) []resultRow {
+	results := r.GetResults()
+	rows := make([]resultRow, 0, len(results))
+	ids := make(map[string]bool, len(results))
+
+	for _, result := range results {
+		id := result.GetID()
+		if ids[id] {
+			continue
+		}
+		ids[id] = true
+
+		var props string
+		b, err := json.Marshal(result.Properties)
+		if err == nil {
+			props = string(b)
+		}
+
+		res := result.GetResource()
+		if res == nil && r.GetScope() != nil {
+			res = r.GetScope()
+		} else if res == nil {
+			res = &corev1.ObjectReference{}
+		}
+
+		values := make([]interface{}, 0, 19)
+		values = append(
+			values,
+			r.GetID(),
+			id,
+			result.Policy,
+			result.Rule,
+			result.Message,
+			result.Scored,
+			string(result.Result),
+			result.Severity,
+			result.Category,
+			result.Source,
+			res.APIVersion,
+			res.Kind,
+			res.Name,
+			r.GetNamespace(),
+			string(res.UID),
+			props,
+			result.Timestamp.Seconds,
+		)
+
+		rows = append(rows, resultRow{id: id, checksum: checksum(values), values: values})
+	}
+
+	return rows
+}
+
+func checksum(values []interface{}) string {
+	h1 := fnv1a.Init64
+	for _, v := range values {
+		h1 = fnv1a.AddString64(h1, fmt.Sprintf("%v\x00", v))
+	}
+
+	return fmt.Sprintf("%016x", h1)
+}
+
+// execPrepared executes the query within the transaction with a cached prepared statement
+func (s *policyReportStore) execPrepared(tx *sql.Tx, query string, args ...interface{}) error {
+	stmt, err := s.prepared(query)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Stmt(stmt).Exec(args...)
+
+	return err
+}
+
+// prepared returns the cached prepared statement of the query, the statement is created on first use
+func (s *policyReportStore) prepared(query string) (*sql.Stmt, error) {
+	s.statements.mx.Lock()
+	defer s.statements.mx.Unlock()
+
+	if stmt, ok := s.statements.cache[query]; ok {
+		return stmt, nil
+	}
+
+	stmt, err := s.prepare(query)
+	if err != nil {
+		return nil, err
+	}
+
+	s.statements.cache[query] = stmt
+
+	return stmt, nil
+}
+
+func chunkSlice[T any](slice []T, chunkSize int) [][]T {
+	var chunks [][]T
+	for i := 0; i < len(slice); i += chunkSize {
+		end := i + chunkSize
+
+		if end > len(slice) {
+			end = len(slice)
+		}
+
+		chunks = append(chunks, slice[i:end])
+	}
+
+	return chunks
+}
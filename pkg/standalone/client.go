@@ -0,0 +1,338 @@
+// Package standalone reads PolicyReports and ClusterPolicyReports from JSON or YAML files instead of a cluster,
+// to develop and test target configurations and templates locally.
+//
+// The directory is scanned recursively for .json, .yaml and .yml files, a file may contain multiple YAML documents
+// and List kinds with items. Each scan publishes added, changed and removed reports like the informers of a cluster.
+// Files which fail to parse keep their previously loaded reports, so a half saved file does not delete them.
+package standalone
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/segmentio/fasthash/fnv1a"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+var logger = logging.Component("standalone")
+
+// DefaultInterval of the directory scans
+const DefaultInterval = 2 * time.Second
+
+// DefaultNamespace of PolicyReports without namespace
+const DefaultNamespace = "default"
+
+var extensions = map[string]bool{".json": true, ".yaml": true, ".yml": true}
+
+type entry struct {
+	report v1alpha2.ReportInterface
+	hash   uint64
+}
+
+type fileClient struct {
+	dir       string
+	interval  time.Duration
+	filter    *report.Filter
+	publisher report.EventPublisher
+	mappers   []func(v1alpha2.ReportInterface)
+
+	mx     *sync.Mutex
+	synced bool
+	// files maps each file to the keys of its reports
+	files map[string][]string
+	// reports by their namespace/name key
+	reports map[string]entry
+}
+
+func (c *fileClient) HasSynced() bool {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return c.synced
+}
+
+// Sync loads and publishes all reports of the directory
+func (c *fileClient) Sync(_ chan struct{}) error {
+	if err := c.scan(); err != nil {
+		return err
+	}
+
+	c.mx.Lock()
+	c.synced = true
+	c.mx.Unlock()
+
+	return nil
+}
+
+// Run loads the reports and scans the directory for changes until the stopper is closed, the worker count is ignored
+func (c *fileClient) Run(_ int, stopper chan struct{}) error {
+	if err := c.Sync(stopper); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopper:
+			return nil
+		case <-ticker.C:
+			if err := c.scan(); err != nil {
+				logger.Errorf("failed to scan reports directory %s: %s", c.dir, err)
+			}
+		}
+	}
+}
+
+func (c *fileClient) scan() error {
+	files, err := c.list()
+	if err != nil {
+		return err
+	}
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	next := make(map[string][]string, len(files))
+	found := make(map[string]entry, len(c.reports))
+
+	for _, file := range files {
+		reports, err := readFile(file)
+		if err != nil {
+			logger.Errorf("failed to read reports of %s: %s", file, err)
+
+			// keep the reports of the last successful read
+			if keys, ok := c.files[file]; ok {
+				next[file] = keys
+				for _, key := range keys {
+					if e, ok := c.reports[key]; ok {
+						found[key] = e
+					}
+				}
+			}
+			continue
+		}
+
+		keys := make([]string, 0, len(reports))
+		for _, r := range reports {
+			key := reportKey(r.report)
+			if _, ok := found[key]; ok {
+				logger.Warnf("report %s is defined multiple times, %s overrides the previous definition", key, file)
+			}
+
+			found[key] = r
+			keys = append(keys, key)
+		}
+
+		next[file] = keys
+	}
+
+	now := time.Now()
+
+	for key, e := range found {
+		if !c.allow(e.report) {
+			continue
+		}
+
+		event := report.Added
+		if prev, ok := c.reports[key]; ok {
+			if prev.hash == e.hash {
+				// keep the mapped report of the previous scan
+				found[key] = prev
+				continue
+			}
+
+			event = report.Updated
+		}
+
+		for _, mapper := range c.mappers {
+			mapper(e.report)
+		}
+
+		c.publisher.Publish(report.LifecycleEvent{Type: event, PolicyReport: e.report, Observed: now})
+	}
+
+	for key, e := range c.reports {
+		if _, ok := found[key]; ok || !c.allow(e.report) {
+			continue
+		}
+
+		c.publisher.Publish(report.LifecycleEvent{Type: report.Deleted, PolicyReport: e.report, Observed: now})
+	}
+
+	c.files = next
+	c.reports = found
+
+	return nil
+}
+
+func (c *fileClient) allow(r v1alpha2.ReportInterface) bool {
+	if c.filter == nil {
+		return true
+	}
+	if r.GetNamespace() == "" {
+		return !c.filter.DisableClusterReports()
+	}
+
+	return c.filter.AllowReport(r)
+}
+
+// list returns the report files of the directory in lexical order
+func (c *fileClient) list() ([]string, error) {
+	files := make([]string, 0)
+
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// skip hidden files and directories like the ..data symlinks of mounted ConfigMaps
+		if path != c.dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && extensions[strings.ToLower(filepath.Ext(path))] {
+			files = append(files, path)
+		}
+
+		return nil
+	})
+
+	sort.Strings(files)
+
+	return files, err
+}
+
+type object struct {
+	v1.TypeMeta `json:",inline"`
+	Items       []json.RawMessage `json:"items"`
+}
+
+// readFile decodes all PolicyReports and ClusterPolicyReports of the file, other kinds are ignored
+func readFile(file string) ([]entry, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096)
+	entries := make([]entry, 0)
+
+	for {
+		raw := json.RawMessage{}
+		if err := decoder.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				return entries, nil
+			}
+
+			return nil, err
+		}
+
+		decoded, err := decode(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, decoded...)
+	}
+}
+
+func decode(raw json.RawMessage) ([]entry, error) {
+	// empty YAML documents
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	obj := object{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, err
+	}
+
+	var r v1alpha2.ReportInterface
+
+	switch obj.Kind {
+	case "PolicyReport":
+		polr := &v1alpha2.PolicyReport{}
+		if err := json.Unmarshal(raw, polr); err != nil {
+			return nil, err
+		}
+		if polr.Namespace == "" {
+			polr.Namespace = DefaultNamespace
+		}
+
+		r = polr
+	case "ClusterPolicyReport":
+		cpolr := &v1alpha2.ClusterPolicyReport{}
+		if err := json.Unmarshal(raw, cpolr); err != nil {
+			return nil, err
+		}
+
+		r = cpolr
+	default:
+		if !strings.HasSuffix(obj.Kind, "List") {
+			return nil, nil
+		}
+
+		entries := make([]entry, 0, len(obj.Items))
+		for _, item := range obj.Items {
+			decoded, err := decode(item)
+			if err != nil {
+				return nil, err
+			}
+
+			entries = append(entries, decoded...)
+		}
+
+		return entries, nil
+	}
+
+	if r.GetName() == "" {
+		return nil, fmt.Errorf("%s without metadata.name", obj.Kind)
+	}
+
+	return []entry{{report: r, hash: fnv1a.HashBytes64(raw)}}, nil
+}
+
+func reportKey(r v1alpha2.ReportInterface) string {
+	if r.GetNamespace() == "" {
+		return r.GetName()
+	}
+
+	return r.GetNamespace() + "/" + r.GetName()
+}
+
+// NewPolicyReportClient creates a client publishing the reports of the directory, the mappers are applied to each
+// added or changed report before it is published
+func NewPolicyReportClient(dir string, interval time.Duration, filter *report.Filter, publisher report.EventPublisher, mappers []func(v1alpha2.ReportInterface)) report.PolicyReportClient {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	return &fileClient{
+		dir:       dir,
+		interval:  interval,
+		filter:    filter,
+		publisher: publisher,
+		mappers:   mappers,
+		mx:        new(sync.Mutex),
+		files:     make(map[string][]string),
+		reports:   make(map[string]entry),
+	}
+}
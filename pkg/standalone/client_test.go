@@ -0,0 +1,243 @@
+package standalone_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/standalone"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+const reports = `apiVersion: wgpolicyk8s.io/v1alpha2
+kind: PolicyReport
+metadata:
+  name: polr-1
+  namespace: test
+results:
+- policy: require-labels
+  rule: check-labels
+  result: fail
+  source: kyverno
+---
+apiVersion: wgpolicyk8s.io/v1alpha2
+kind: PolicyReport
+metadata:
+  name: polr-2
+results:
+- policy: require-labels
+  rule: check-labels
+  result: pass
+  source: kyverno
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ignored
+`
+
+const updated = `apiVersion: wgpolicyk8s.io/v1alpha2
+kind: PolicyReport
+metadata:
+  name: polr-1
+  namespace: test
+results:
+- policy: require-labels
+  rule: check-labels
+  result: fail
+  source: kyverno
+  message: changed
+`
+
+const clusterReports = `{
+  "apiVersion": "v1",
+  "kind": "List",
+  "items": [{
+    "apiVersion": "wgpolicyk8s.io/v1alpha2",
+    "kind": "ClusterPolicyReport",
+    "metadata": {"name": "cpolr"},
+    "results": [{"policy": "require-ns-labels", "result": "fail", "source": "kyverno"}]
+  }]
+}`
+
+type events struct {
+	mx     sync.Mutex
+	events map[string]report.Event
+}
+
+func (e *events) listen(event report.LifecycleEvent) {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	key := event.PolicyReport.GetName()
+	if event.PolicyReport.GetNamespace() != "" {
+		key = event.PolicyReport.GetNamespace() + "/" + key
+	}
+
+	e.events[key] = event.Type
+}
+
+func (e *events) reset() map[string]report.Event {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	events := e.events
+	e.events = make(map[string]report.Event)
+
+	return events
+}
+
+func newClient(t *testing.T, dir string, filter *report.Filter, mappers ...func(v1alpha2.ReportInterface)) (report.PolicyReportClient, *events) {
+	t.Helper()
+
+	e := &events{events: make(map[string]report.Event)}
+
+	publisher := report.NewEventPublisher()
+	publisher.RegisterListener("test", e.listen)
+
+	return standalone.NewPolicyReportClient(dir, time.Millisecond, filter, publisher, mappers), e
+}
+
+func write(t *testing.T, file, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_Sync(t *testing.T) {
+	dir := t.TempDir()
+	write(t, filepath.Join(dir, "reports.yaml"), reports)
+	write(t, filepath.Join(dir, "cluster.json"), clusterReports)
+	write(t, filepath.Join(dir, "README.md"), "not a report")
+
+	mapped := 0
+	client, e := newClient(t, dir, nil, func(v1alpha2.ReportInterface) { mapped++ })
+
+	if client.HasSynced() {
+		t.Error("expected client not synced before the first scan")
+	}
+	if err := client.Sync(nil); err != nil {
+		t.Fatal(err)
+	}
+	if !client.HasSynced() {
+		t.Error("expected client synced")
+	}
+
+	events := e.reset()
+	if len(events) != 3 {
+		t.Fatalf("expected 3 added reports, got %v", events)
+	}
+	for _, key := range []string{"test/polr-1", "default/polr-2", "cpolr"} {
+		if events[key] != report.Added {
+			t.Errorf("expected %s added, got %v", key, events[key])
+		}
+	}
+	if mapped != 3 {
+		t.Errorf("expected mappers applied to 3 reports, got %d", mapped)
+	}
+}
+
+func Test_Filter(t *testing.T) {
+	dir := t.TempDir()
+	write(t, filepath.Join(dir, "reports.yaml"), reports)
+	write(t, filepath.Join(dir, "cluster.json"), clusterReports)
+
+	client, e := newClient(t, dir, report.NewFilter(true, validate.RuleSets{Exclude: []string{"test"}}))
+	if err := client.Sync(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	events := e.reset()
+	if len(events) != 1 || events["default/polr-2"] != report.Added {
+		t.Errorf("expected only default/polr-2 added, got %v", events)
+	}
+}
+
+func Test_Changes(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "reports.yaml")
+	write(t, file, reports)
+	write(t, filepath.Join(dir, "cluster.json"), clusterReports)
+
+	client, e := newClient(t, dir, nil)
+
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() { done <- client.Run(1, stop) }()
+
+	wait := func(count int) map[string]report.Event {
+		t.Helper()
+
+		all := make(map[string]report.Event)
+		timeout := time.After(2 * time.Second)
+		for len(all) < count {
+			select {
+			case <-timeout:
+				t.Fatalf("expected %d events, got %v", count, all)
+			case <-time.After(5 * time.Millisecond):
+				for key, event := range e.reset() {
+					all[key] = event
+				}
+			}
+		}
+
+		return all
+	}
+
+	wait(3)
+
+	// update polr-1, remove polr-2 and break the cluster report file
+	write(t, file, updated)
+	write(t, filepath.Join(dir, "cluster.json"), `{"kind": `)
+
+	events := wait(2)
+	if events["test/polr-1"] != report.Updated {
+		t.Errorf("expected test/polr-1 updated, got %v", events)
+	}
+	if events["default/polr-2"] != report.Deleted {
+		t.Errorf("expected default/polr-2 deleted, got %v", events)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if events := e.reset(); len(events) != 0 {
+		t.Errorf("expected the reports of the invalid file to be kept, got %v", events)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "cluster.json")); err != nil {
+		t.Fatal(err)
+	}
+	if events := wait(1); events["cpolr"] != report.Deleted {
+		t.Errorf("expected cpolr deleted, got %v", events)
+	}
+
+	close(stop)
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_InvalidReport(t *testing.T) {
+	dir := t.TempDir()
+	write(t, filepath.Join(dir, "report.yaml"), "kind: PolicyReport\nmetadata:\n  namespace: test\n")
+
+	client, e := newClient(t, dir, nil)
+	if err := client.Sync(nil); err != nil {
+		t.Fatal(err)
+	}
+	if events := e.reset(); len(events) != 0 {
+		t.Errorf("expected reports without name to be skipped, got %v", events)
+	}
+}
+
+func Test_MissingDirectory(t *testing.T) {
+	client, _ := newClient(t, filepath.Join(t.TempDir(), "missing"), nil)
+	if err := client.Sync(nil); err == nil {
+		t.Error("expected error for a missing directory")
+	}
+}
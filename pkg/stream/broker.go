@@ -0,0 +1,197 @@
+package stream
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/report"
+)
+
+var logger = logging.Component("stream")
+
+// EventType of a streamed result
+type EventType = string
+
+const (
+	// New result for a resource, policy and rule
+	New EventType = "new"
+	// Updated result with a changed status or message
+	Updated EventType = "updated"
+	// Resolved result which was removed from its PolicyReport
+	Resolved EventType = "resolved"
+)
+
+// Listener is the name of the PolicyReport listener feeding the Broker
+const Listener = "result_stream_listener"
+
+// Event streamed to subscribed clients
+type Event struct {
+	Type      EventType     `json:"type"`
+	ReportID  string        `json:"reportId"`
+	Source    string        `json:"source,omitempty"`
+	Timestamp int64         `json:"timestamp"`
+	Result    v1.ListResult `json:"result"`
+}
+
+// Broker tracks the results of each PolicyReport and publishes the differences to all subscribers
+type Broker struct {
+	mx          *sync.RWMutex
+	reports     map[string]map[string]v1alpha2.PolicyReportResult
+	namespaces  map[string]string
+	subscribers map[int]chan Event
+	nextID      int
+	bufferSize  int
+}
+
+// Listen is the PolicyReportListener of the Broker
+func (b *Broker) Listen(event report.LifecycleEvent) {
+	current := make(map[string]v1alpha2.PolicyReportResult)
+	if event.Type != report.Deleted {
+		for _, r := range event.PolicyReport.GetResults() {
+			current[resultKey(event.PolicyReport, r)] = r
+		}
+	}
+
+	b.mx.Lock()
+	previous := b.reports[event.PolicyReport.GetID()]
+	if event.Type == report.Deleted {
+		delete(b.reports, event.PolicyReport.GetID())
+		delete(b.namespaces, event.PolicyReport.GetID())
+	} else {
+		b.reports[event.PolicyReport.GetID()] = current
+		b.namespaces[event.PolicyReport.GetID()] = event.PolicyReport.GetNamespace()
+	}
+	b.mx.Unlock()
+
+	now := time.Now().Unix()
+
+	for key, r := range current {
+		prev, ok := previous[key]
+		if !ok {
+			b.publish(newEvent(New, event.PolicyReport, r, now))
+		} else if prev.GetID() != r.GetID() {
+			b.publish(newEvent(Updated, event.PolicyReport, r, now))
+		}
+	}
+
+	for key, r := range previous {
+		if _, ok := current[key]; !ok {
+			b.publish(newEvent(Resolved, event.PolicyReport, r, now))
+		}
+	}
+}
+
+// Namespaces of the tracked PolicyReports
+func (b *Broker) Namespaces() ([]string, error) {
+	b.mx.RLock()
+	defer b.mx.RUnlock()
+
+	unique := make(map[string]bool)
+	list := make([]string, 0)
+	for _, ns := range b.namespaces {
+		if ns != "" && !unique[ns] {
+			unique[ns] = true
+			list = append(list, ns)
+		}
+	}
+
+	sort.Strings(list)
+
+	return list, nil
+}
+
+// Subscribe returns the ID and the event channel of the new subscription
+func (b *Broker) Subscribe() (int, <-chan Event) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	b.nextID++
+	events := make(chan Event, b.bufferSize)
+	b.subscribers[b.nextID] = events
+
+	return b.nextID, events
+}
+
+// Unsubscribe removes the subscription
+func (b *Broker) Unsubscribe(id int) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	delete(b.subscribers, id)
+}
+
+// publish the event to all subscribers, subscribers which can not keep up miss the event
+func (b *Broker) publish(event Event) {
+	b.mx.RLock()
+	defer b.mx.RUnlock()
+
+	for id, events := range b.subscribers {
+		select {
+		case events <- event:
+		default:
+			logger.Warnf("result stream %d is too slow, %s event for result %s dropped", id, event.Type, event.Result.ID)
+		}
+	}
+}
+
+// resultKey identifies a result of a resource, policy and rule independent of its status
+func resultKey(rep v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult) string {
+	res := resource(rep, r)
+
+	return strings.Join([]string{r.Source, r.Policy, r.Rule, res.Kind, res.Name, string(res.UID)}, "/")
+}
+
+func resource(rep v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult) *corev1.ObjectReference {
+	res := r.GetResource()
+	if res == nil && rep.GetScope() != nil {
+		res = rep.GetScope()
+	} else if res == nil {
+		res = &corev1.ObjectReference{}
+	}
+
+	return res
+}
+
+func newEvent(eventType EventType, rep v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult, timestamp int64) Event {
+	res := resource(rep, r)
+
+	return Event{
+		Type:      eventType,
+		ReportID:  rep.GetID(),
+		Source:    r.Source,
+		Timestamp: timestamp,
+		Result: v1.ListResult{
+			ID:         r.GetID(),
+			Namespace:  rep.GetNamespace(),
+			Kind:       res.Kind,
+			APIVersion: res.APIVersion,
+			Name:       res.Name,
+			Message:    r.Message,
+			Category:   r.Category,
+			Policy:     r.Policy,
+			Rule:       r.Rule,
+			Status:     string(r.Result),
+			Severity:   string(r.Severity),
+			Timestamp:  int(r.Timestamp.Seconds),
+			Properties: r.Properties,
+		},
+	}
+}
+
+// NewBroker creates a new Broker, bufferSize is the amount of events buffered per subscriber
+func NewBroker(bufferSize int) *Broker {
+	return &Broker{
+		mx:          new(sync.RWMutex),
+		reports:     make(map[string]map[string]v1alpha2.PolicyReportResult),
+		namespaces:  make(map[string]string),
+		subscribers: make(map[int]chan Event),
+		bufferSize:  bufferSize,
+	}
+}
@@ -0,0 +1,144 @@
+package stream_test
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/stream"
+)
+
+func newReport(results ...v1alpha2.PolicyReportResult) *v1alpha2.PolicyReport {
+	return &v1alpha2.PolicyReport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "polr-test",
+			Namespace: "test",
+		},
+		Results: results,
+	}
+}
+
+func receive(t *testing.T, events <-chan stream.Event, count int) []stream.Event {
+	list := make([]stream.Event, 0, count)
+	for i := 0; i < count; i++ {
+		select {
+		case e := <-events:
+			list = append(list, e)
+		default:
+			t.Fatalf("expected %d events, got %d", count, len(list))
+		}
+	}
+
+	select {
+	case e := <-events:
+		t.Fatalf("unexpected event %s for %s", e.Type, e.Result.ID)
+	default:
+	}
+
+	return list
+}
+
+func Test_Broker(t *testing.T) {
+	broker := stream.NewBroker(10)
+	_, events := broker.Subscribe()
+
+	t.Run("New Results", func(t *testing.T) {
+		broker.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: newReport(fixtures.FailResult, fixtures.PassPodResult)})
+
+		list := receive(t, events, 2)
+		for _, e := range list {
+			if e.Type != stream.New {
+				t.Errorf("expected new event, got %s", e.Type)
+			}
+			if e.Result.Namespace != "test" {
+				t.Errorf("expected namespace test, got %s", e.Result.Namespace)
+			}
+		}
+
+		if namespaces, _ := broker.Namespaces(); len(namespaces) != 1 || namespaces[0] != "test" {
+			t.Errorf("expected tracked namespace test, got %v", namespaces)
+		}
+	})
+
+	t.Run("Updated Result", func(t *testing.T) {
+		fixed := fixtures.FailResult
+		fixed.ID = ""
+		fixed.Result = v1alpha2.StatusPass
+
+		broker.Listen(report.LifecycleEvent{Type: report.Updated, PolicyReport: newReport(fixed, fixtures.PassPodResult)})
+
+		list := receive(t, events, 1)
+		if list[0].Type != stream.Updated || list[0].Result.Status != v1alpha2.StatusPass {
+			t.Errorf("expected updated event with status pass, got %s with %s", list[0].Type, list[0].Result.Status)
+		}
+	})
+
+	t.Run("Resolved Results", func(t *testing.T) {
+		broker.Listen(report.LifecycleEvent{Type: report.Deleted, PolicyReport: newReport(fixtures.PassPodResult)})
+
+		list := receive(t, events, 2)
+		for _, e := range list {
+			if e.Type != stream.Resolved {
+				t.Errorf("expected resolved event, got %s", e.Type)
+			}
+		}
+
+		if namespaces, _ := broker.Namespaces(); len(namespaces) != 0 {
+			t.Errorf("expected no tracked namespaces, got %v", namespaces)
+		}
+	})
+
+	t.Run("Unsubscribe", func(t *testing.T) {
+		id, events := broker.Subscribe()
+		broker.Unsubscribe(id)
+
+		broker.Listen(report.LifecycleEvent{Type: report.Added, PolicyReport: newReport(fixtures.FailResult)})
+
+		receive(t, events, 0)
+	})
+}
+
+func Test_Filter(t *testing.T) {
+	event := stream.Event{
+		Type:   stream.New,
+		Source: "Kyverno",
+		Result: v1.ListResult{Namespace: "test", Policy: "require-limits", Status: v1alpha2.StatusFail, Severity: v1alpha2.SeverityHigh},
+	}
+
+	t.Run("Match", func(t *testing.T) {
+		filter, err := stream.NewFilter(v1.Filter{Namespaces: []string{"test"}, Sources: []string{"kyverno"}, Expression: `severity>=medium AND policy~"require-*"`}, []string{stream.New})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if !filter.Match(event) {
+			t.Error("expected event to match")
+		}
+	})
+
+	t.Run("Mismatch", func(t *testing.T) {
+		for _, f := range []struct {
+			filter v1.Filter
+			types  []string
+		}{
+			{v1.Filter{}, []string{stream.Resolved}},
+			{v1.Filter{Namespaces: []string{"default"}}, nil},
+			{v1.Filter{Expression: "status=pass"}, nil},
+		} {
+			filter, _ := stream.NewFilter(f.filter, f.types)
+			if filter.Match(event) {
+				t.Errorf("expected event not to match %v", f)
+			}
+		}
+	})
+
+	t.Run("Invalid Expression", func(t *testing.T) {
+		if _, err := stream.NewFilter(v1.Filter{Expression: "severity>unknown"}, nil); err == nil {
+			t.Error("expected error for invalid expression")
+		}
+	})
+}
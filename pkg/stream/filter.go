@@ -0,0 +1,92 @@
+package stream
+
+import (
+	"strconv"
+
+	v1 "github.com/kyverno/policy-reporter/pkg/api/v1"
+	"github.com/kyverno/policy-reporter/pkg/query"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+// Filter of streamed events, based on the REST API filter and the optional event types
+type Filter struct {
+	v1.Filter
+	Types []EventType
+	node  query.Node
+}
+
+// Match returns true if the event passes all configured filters
+func (f Filter) Match(event Event) bool {
+	r := event.Result
+
+	checks := []struct {
+		values []string
+		value  string
+	}{
+		{f.Types, event.Type},
+		{f.Namespaces, r.Namespace},
+		{f.Kinds, r.Kind},
+		{f.Resources, r.Name},
+		{f.Sources, event.Source},
+		{f.Categories, r.Category},
+		{f.Severities, r.Severity},
+		{f.Policies, r.Policy},
+		{f.Rules, r.Rule},
+		{f.Status, r.Status},
+	}
+
+	for _, check := range checks {
+		if len(check.values) > 0 && !validate.MatchAny(check.values, check.value) {
+			return false
+		}
+	}
+
+	if f.node == nil {
+		return true
+	}
+
+	return query.Match(f.node, func(field string) string {
+		switch field {
+		case "namespace":
+			return r.Namespace
+		case "kind":
+			return r.Kind
+		case "name":
+			return r.Name
+		case "policy":
+			return r.Policy
+		case "rule":
+			return r.Rule
+		case "severity":
+			return r.Severity
+		case "status":
+			return r.Status
+		case "category":
+			return r.Category
+		case "source":
+			return event.Source
+		case "message":
+			return r.Message
+		case "timestamp":
+			return strconv.Itoa(r.Timestamp)
+		}
+
+		return ""
+	})
+}
+
+// NewFilter creates a Filter and parses the optional filter expression
+func NewFilter(filter v1.Filter, types []EventType) (Filter, error) {
+	f := Filter{Filter: filter, Types: types}
+
+	if filter.Expression != "" {
+		node, err := query.Parse(filter.Expression)
+		if err != nil {
+			return f, err
+		}
+
+		f.node = node
+	}
+
+	return f, nil
+}
@@ -0,0 +1,119 @@
+package subscription
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kyverno/policy-reporter/pkg/helper"
+)
+
+type request struct {
+	Email string `json:"email"`
+}
+
+// Handler of /v2/namespaces/{namespace}/subscriptions, POST subscribes the email query parameter or the email of the JSON body,
+// DELETE removes the subscription of the email query parameter
+func (m *Manager) Handler(namespace func(path string) (string, bool)) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ns, ok := namespace(req.URL.Path)
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+
+		switch req.Method {
+		case http.MethodGet:
+			list, err := m.List(ns)
+			helper.SendJSONResponse(w, list, err)
+		case http.MethodPost:
+			address := req.URL.Query().Get("email")
+			if address == "" && req.Body != nil {
+				body := request{}
+				if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, 1<<16)).Decode(&body); err != nil {
+					helper.SendBadRequest(w, fmt.Errorf("email is required"))
+					return
+				}
+
+				address = body.Email
+			}
+
+			sub, err := m.Subscribe(address, ns)
+			if errors.Is(err, ErrInvalid) {
+				helper.SendBadRequest(w, err)
+				return
+			} else if err != nil {
+				helper.SendError(w, http.StatusInternalServerError, err)
+				return
+			}
+
+			status := http.StatusAccepted
+			if sub.Confirmed {
+				status = http.StatusOK
+			}
+
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+			w.WriteHeader(status)
+
+			_ = json.NewEncoder(w).Encode(sub)
+		case http.MethodDelete:
+			address := req.URL.Query().Get("email")
+
+			removed, err := m.Remove(address, ns)
+			if err != nil {
+				helper.SendError(w, http.StatusInternalServerError, err)
+				return
+			}
+			if !removed {
+				helper.SendError(w, http.StatusNotFound, fmt.Errorf("subscription of %s to namespace %s not found", address, ns))
+				return
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Allow", "GET, POST, DELETE")
+			helper.SendError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+		}
+	}
+}
+
+// ConfirmHandler confirms the subscription of the token query parameter, opened by the link of the confirmation email
+func (m *Manager) ConfirmHandler() http.HandlerFunc {
+	return m.tokenHandler(m.Confirm, "Your subscription of %s to the digest of namespace %s is confirmed.")
+}
+
+// UnsubscribeHandler removes the subscription of the token query parameter, opened by the link of each digest.
+// POST supports one-click unsubscribe of email clients
+func (m *Manager) UnsubscribeHandler() http.HandlerFunc {
+	return m.tokenHandler(m.Unsubscribe, "%s is unsubscribed from the digest of namespace %s.")
+}
+
+func (m *Manager) tokenHandler(action func(token string) (Subscription, error), message string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodPost {
+			w.Header().Set("Allow", "GET, POST")
+			helper.SendError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+			return
+		}
+
+		token := strings.TrimSpace(req.URL.Query().Get("token"))
+		if token == "" {
+			helper.SendBadRequest(w, errors.New("token is required"))
+			return
+		}
+
+		sub, err := action(token)
+		if errors.Is(err, ErrNotFound) {
+			helper.SendError(w, http.StatusNotFound, errors.New("the link is invalid or the subscription was removed"))
+			return
+		} else if err != nil {
+			helper.SendError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+		fmt.Fprintf(w, message+"\n", sub.Email, sub.Namespace)
+	}
+}
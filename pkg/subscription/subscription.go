@@ -0,0 +1,247 @@
+// Package subscription lets teams subscribe an email address to the summary digest of a namespace.
+//
+// A new subscription is pending until the link of the confirmation email is opened, each digest contains
+// a link to unsubscribe. Both links carry the random token of the subscription instead of an API credential.
+package subscription
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/email"
+)
+
+const (
+	// Path suffix of the namespaced API /v2/namespaces/{namespace}/subscriptions
+	Path = "subscriptions"
+	// ConfirmPath confirms the subscription of the token query parameter
+	ConfirmPath = "/v2/subscriptions/confirm"
+	// UnsubscribePath removes the subscription of the token query parameter
+	UnsubscribePath = "/v2/subscriptions/unsubscribe"
+)
+
+var (
+	// ErrNotFound is returned for unknown tokens
+	ErrNotFound = errors.New("subscription not found")
+	// ErrInvalid is returned for invalid email addresses and namespaces
+	ErrInvalid = errors.New("invalid subscription")
+)
+
+// Subscription of an email address to the digest of a namespace
+type Subscription struct {
+	Email     string    `json:"email"`
+	Namespace string    `json:"namespace"`
+	Confirmed bool      `json:"confirmed"`
+	Created   time.Time `json:"created"`
+	// Token of the confirmation and unsubscribe links
+	Token string `json:"-"`
+}
+
+// Store persists the subscriptions, the email address and namespace identify a subscription
+type Store interface {
+	// SaveSubscription creates or updates the subscription of the email address and namespace
+	SaveSubscription(sub Subscription) error
+	// FetchSubscriptions returns the subscriptions of the namespace, of all namespaces if empty
+	FetchSubscriptions(namespace string) ([]Subscription, error)
+	// FetchSubscription returns the subscription of the token or ErrNotFound
+	FetchSubscription(token string) (Subscription, error)
+	// DeleteSubscription removes the subscription and returns false if it did not exist
+	DeleteSubscription(email, namespace string) (bool, error)
+}
+
+// Mailer sends the confirmation email
+type Mailer interface {
+	Send(report email.Report, to []string) error
+}
+
+// Options of the Manager
+type Options struct {
+	// URL of the Policy Reporter API used for the confirmation and unsubscribe links
+	URL string
+	// Domains of the allowed email addresses, all domains if empty
+	Domains     []string
+	ClusterName string
+	Mailer      Mailer
+}
+
+// Manager creates, confirms and removes subscriptions
+type Manager struct {
+	store   Store
+	options Options
+}
+
+// Subscribe creates a pending subscription and sends the confirmation email,
+// subscribing again sends a new confirmation of a pending subscription and keeps confirmed subscriptions
+func (m *Manager) Subscribe(address, namespace string) (Subscription, error) {
+	address, err := m.validate(address)
+	if err != nil {
+		return Subscription{}, err
+	}
+	if namespace == "" {
+		return Subscription{}, fmt.Errorf("%w: namespace is required", ErrInvalid)
+	}
+
+	list, err := m.store.FetchSubscriptions(namespace)
+	if err != nil {
+		return Subscription{}, err
+	}
+	for _, sub := range list {
+		if sub.Email == address && sub.Confirmed {
+			return sub, nil
+		}
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	sub := Subscription{Email: address, Namespace: namespace, Created: time.Now(), Token: token}
+	if err := m.store.SaveSubscription(sub); err != nil {
+		return Subscription{}, err
+	}
+
+	if m.options.Mailer != nil {
+		if err := m.options.Mailer.Send(m.confirmation(sub), []string{sub.Email}); err != nil {
+			return sub, fmt.Errorf("failed to send the confirmation email: %w", err)
+		}
+	}
+
+	return sub, nil
+}
+
+// Confirm activates the subscription of the token
+func (m *Manager) Confirm(token string) (Subscription, error) {
+	sub, err := m.store.FetchSubscription(token)
+	if err != nil {
+		return sub, err
+	}
+	if sub.Confirmed {
+		return sub, nil
+	}
+
+	sub.Confirmed = true
+
+	return sub, m.store.SaveSubscription(sub)
+}
+
+// Unsubscribe removes the subscription of the token
+func (m *Manager) Unsubscribe(token string) (Subscription, error) {
+	sub, err := m.store.FetchSubscription(token)
+	if err != nil {
+		return sub, err
+	}
+
+	_, err = m.store.DeleteSubscription(sub.Email, sub.Namespace)
+
+	return sub, err
+}
+
+// Remove deletes the subscription of the email address and namespace without token, e.g. by the team of the namespace
+func (m *Manager) Remove(address, namespace string) (bool, error) {
+	return m.store.DeleteSubscription(strings.ToLower(strings.TrimSpace(address)), namespace)
+}
+
+// List returns the subscriptions of the namespace
+func (m *Manager) List(namespace string) ([]Subscription, error) {
+	return m.store.FetchSubscriptions(namespace)
+}
+
+// Subscribers returns the confirmed subscriptions by namespace
+func (m *Manager) Subscribers() (map[string][]Subscription, error) {
+	list, err := m.store.FetchSubscriptions("")
+	if err != nil {
+		return nil, err
+	}
+
+	subscribers := make(map[string][]Subscription)
+	for _, sub := range list {
+		if sub.Confirmed {
+			subscribers[sub.Namespace] = append(subscribers[sub.Namespace], sub)
+		}
+	}
+
+	return subscribers, nil
+}
+
+// WithUnsubscribeLink appends the unsubscribe link of the subscription to the digest
+func (m *Manager) WithUnsubscribeLink(report email.Report, sub Subscription) email.Report {
+	link := m.link(UnsubscribePath, sub.Token)
+
+	if strings.ToLower(report.Format) == "html" || report.Format == "" {
+		footer := fmt.Sprintf(`<p style="font-size: 12px; color: #666;">You receive this digest of namespace %s as subscriber. <a href="%s">Unsubscribe</a></p>`, html.EscapeString(sub.Namespace), html.EscapeString(link))
+
+		if index := strings.LastIndex(report.Message, "</body>"); index >= 0 {
+			report.Message = report.Message[:index] + footer + report.Message[index:]
+		} else {
+			report.Message += footer
+		}
+
+		return report
+	}
+
+	report.Message += fmt.Sprintf("\n\nYou receive this digest of namespace %s as subscriber, unsubscribe: %s\n", sub.Namespace, link)
+
+	return report
+}
+
+func (m *Manager) confirmation(sub Subscription) email.Report {
+	title := fmt.Sprintf("Confirm your Policy Reporter subscription of namespace %s", sub.Namespace)
+	if m.options.ClusterName != "" {
+		title = fmt.Sprintf("%s on %s", title, m.options.ClusterName)
+	}
+
+	return email.Report{
+		Title:       title,
+		Message:     fmt.Sprintf("Open the following link to receive the summary digest of namespace %s:\n\n%s\n\nIgnore this email if you did not subscribe.\n", sub.Namespace, m.link(ConfirmPath, sub.Token)),
+		Format:      "text",
+		ClusterName: m.options.ClusterName,
+	}
+}
+
+func (m *Manager) link(path, token string) string {
+	return strings.TrimSuffix(m.options.URL, "/") + path + "?token=" + url.QueryEscape(token)
+}
+
+// validate returns the normalized address, display names and domains which are not allowed are rejected
+func (m *Manager) validate(address string) (string, error) {
+	parsed, err := mail.ParseAddress(strings.TrimSpace(address))
+	if err != nil || parsed.Name != "" {
+		return "", fmt.Errorf("%w: invalid email address %q", ErrInvalid, address)
+	}
+
+	normalized := strings.ToLower(parsed.Address)
+	if len(m.options.Domains) == 0 {
+		return normalized, nil
+	}
+
+	domain := normalized[strings.LastIndex(normalized, "@")+1:]
+	for _, allowed := range m.options.Domains {
+		if strings.EqualFold(domain, allowed) {
+			return normalized, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: email domain %s is not allowed", ErrInvalid, domain)
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// NewManager creates a Manager for the subscriptions of the store
+func NewManager(store Store, options Options) *Manager {
+	return &Manager{store: store, options: options}
+}
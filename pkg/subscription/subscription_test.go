@@ -0,0 +1,202 @@
+package subscription_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/email"
+	"github.com/kyverno/policy-reporter/pkg/subscription"
+)
+
+type store struct {
+	items map[string]subscription.Subscription
+}
+
+func (s *store) SaveSubscription(sub subscription.Subscription) error {
+	s.items[sub.Email+"/"+sub.Namespace] = sub
+	return nil
+}
+
+func (s *store) FetchSubscriptions(namespace string) ([]subscription.Subscription, error) {
+	list := make([]subscription.Subscription, 0)
+	for _, sub := range s.items {
+		if namespace == "" || sub.Namespace == namespace {
+			list = append(list, sub)
+		}
+	}
+
+	return list, nil
+}
+
+func (s *store) FetchSubscription(token string) (subscription.Subscription, error) {
+	for _, sub := range s.items {
+		if sub.Token == token {
+			return sub, nil
+		}
+	}
+
+	return subscription.Subscription{}, subscription.ErrNotFound
+}
+
+func (s *store) DeleteSubscription(address, namespace string) (bool, error) {
+	_, ok := s.items[address+"/"+namespace]
+	delete(s.items, address+"/"+namespace)
+
+	return ok, nil
+}
+
+type mailer struct {
+	reports []email.Report
+	to      []string
+}
+
+func (m *mailer) Send(report email.Report, to []string) error {
+	m.reports = append(m.reports, report)
+	m.to = append(m.to, to...)
+	return nil
+}
+
+func newManager(domains ...string) (*subscription.Manager, *store, *mailer) {
+	s := &store{items: make(map[string]subscription.Subscription)}
+	m := &mailer{}
+
+	return subscription.NewManager(s, subscription.Options{URL: "https://policy-reporter.example.com/", Domains: domains, Mailer: m}), s, m
+}
+
+func tokenFromLink(t *testing.T, message string) string {
+	index := strings.Index(message, "?token=")
+	if index < 0 {
+		t.Fatalf("expected a link with token in %q", message)
+	}
+
+	return strings.Fields(message[index+len("?token="):])[0]
+}
+
+func Test_Subscribe(t *testing.T) {
+	manager, _, mail := newManager("example.com")
+
+	sub, err := manager.Subscribe(" Dev@Example.com ", "test")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if sub.Email != "dev@example.com" || sub.Confirmed {
+		t.Errorf("expected normalized pending subscription, got %+v", sub)
+	}
+	if len(mail.reports) != 1 || mail.to[0] != "dev@example.com" {
+		t.Fatalf("expected confirmation email, got %v", mail.to)
+	}
+	if !strings.Contains(mail.reports[0].Message, "https://policy-reporter.example.com"+subscription.ConfirmPath+"?token=") {
+		t.Errorf("expected confirmation link, got %s", mail.reports[0].Message)
+	}
+
+	for _, address := range []string{"invalid", "Dev <dev@example.com>", "dev@other.com"} {
+		if _, err := manager.Subscribe(address, "test"); !errors.Is(err, subscription.ErrInvalid) {
+			t.Errorf("expected %q to be invalid, got %v", address, err)
+		}
+	}
+	if _, err := manager.Subscribe("dev@example.com", ""); !errors.Is(err, subscription.ErrInvalid) {
+		t.Errorf("expected namespace to be required")
+	}
+}
+
+func Test_Lifecycle(t *testing.T) {
+	manager, _, mail := newManager()
+
+	if _, err := manager.Subscribe("dev@example.com", "test"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if subscribers, _ := manager.Subscribers(); len(subscribers) != 0 {
+		t.Errorf("expected pending subscriptions not to receive digests")
+	}
+
+	sub, err := manager.Confirm(tokenFromLink(t, mail.reports[0].Message))
+	if err != nil || !sub.Confirmed {
+		t.Fatalf("expected confirmed subscription, got %+v, %v", sub, err)
+	}
+
+	again, _ := manager.Subscribe("dev@example.com", "test")
+	if !again.Confirmed || len(mail.reports) != 1 {
+		t.Errorf("expected confirmed subscriptions to be kept without new confirmation")
+	}
+
+	subscribers, _ := manager.Subscribers()
+	if len(subscribers["test"]) != 1 {
+		t.Fatalf("expected one subscriber of namespace test, got %v", subscribers)
+	}
+
+	digest := manager.WithUnsubscribeLink(email.Report{Message: "<html><body><p>digest</p></body></html>", Format: "html"}, subscribers["test"][0])
+	if !strings.Contains(digest.Message, subscription.UnsubscribePath) || !strings.HasSuffix(digest.Message, "</body></html>") {
+		t.Errorf("expected unsubscribe link in the body, got %s", digest.Message)
+	}
+
+	text := manager.WithUnsubscribeLink(email.Report{Message: "digest", Format: "text"}, subscribers["test"][0])
+
+	if _, err := manager.Unsubscribe(tokenFromLink(t, text.Message)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if list, _ := manager.List("test"); len(list) != 0 {
+		t.Errorf("expected subscription removed")
+	}
+	if _, err := manager.Unsubscribe(tokenFromLink(t, text.Message)); !errors.Is(err, subscription.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for removed subscriptions, got %v", err)
+	}
+}
+
+func Test_Handler(t *testing.T) {
+	manager, _, mail := newManager("example.com")
+
+	handler := manager.Handler(func(path string) (string, bool) {
+		return "test", true
+	})
+
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodPost, "/v2/namespaces/test/subscriptions", strings.NewReader(`{"email":"dev@example.com"}`)))
+	if rr.Code != http.StatusAccepted {
+		t.Errorf("expected 202, got %d", rr.Code)
+	}
+	if strings.Contains(rr.Body.String(), "token") {
+		t.Errorf("expected the token not to be exposed, got %s", rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodPost, "/v2/namespaces/test/subscriptions?email=dev@other.com", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodGet, "/v2/namespaces/test/subscriptions", nil))
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "dev@example.com") {
+		t.Errorf("expected subscription list, got %d %s", rr.Code, rr.Body.String())
+	}
+
+	confirm := manager.ConfirmHandler()
+
+	rr = httptest.NewRecorder()
+	confirm(rr, httptest.NewRequest(http.MethodGet, subscription.ConfirmPath+"?token=unknown", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown tokens, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	confirm(rr, httptest.NewRequest(http.MethodGet, subscription.ConfirmPath+"?token="+tokenFromLink(t, mail.reports[0].Message), nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodDelete, "/v2/namespaces/test/subscriptions?email=dev@example.com", nil))
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodDelete, "/v2/namespaces/test/subscriptions?email=dev@example.com", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,168 @@
+package alertmanager
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/secret"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/http"
+	"github.com/kyverno/policy-reporter/pkg/tracing"
+)
+
+// DefaultExpiry of a firing alert without resolve, Alertmanager resolves alerts after their endsAt
+const DefaultExpiry = 24 * time.Hour
+
+// Options to configure the Alertmanager target
+type Options struct {
+	target.ClientOptions
+	Host string
+	// Expiry of a firing alert, defaults to DefaultExpiry
+	Expiry       time.Duration
+	CustomFields map[string]string
+	Headers      map[string]secret.String
+	Token        secret.String
+	HTTPClient   http.Client
+}
+
+// Alert as expected by the Alertmanager v2 API
+type Alert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     time.Time         `json:"startsAt,omitempty"`
+	EndsAt       time.Time         `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+var invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// LabelName sanitizes the name to a valid Alertmanager label name
+func LabelName(name string) string {
+	name = invalidLabelChars.ReplaceAllString(name, "_")
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+
+	return name
+}
+
+// Labels of the alert, identify the alert of a result so the resolve matches the firing alert
+func Labels(result v1alpha2.PolicyReportResult, customFields map[string]string) map[string]string {
+	labels := make(map[string]string, len(customFields)+10)
+	for field, value := range customFields {
+		labels[LabelName(field)] = value
+	}
+
+	labels["alertname"] = result.Policy
+	labels["policy"] = result.Policy
+	labels["status"] = string(result.Result)
+	labels["source"] = result.Source
+
+	if result.Rule != "" {
+		labels["rule"] = result.Rule
+	}
+	if result.Severity != "" {
+		labels["severity"] = string(result.Severity)
+	}
+	if result.Category != "" {
+		labels["category"] = result.Category
+	}
+	if result.HasResource() {
+		res := result.GetResource()
+		if res.Namespace != "" {
+			labels["namespace"] = res.Namespace
+		}
+
+		labels["kind"] = res.Kind
+		labels["name"] = res.Name
+	}
+
+	return labels
+}
+
+// Annotations of the alert with the message and the result properties
+func Annotations(result v1alpha2.PolicyReportResult) map[string]string {
+	annotations := make(map[string]string, len(result.Properties)+3)
+	for property, value := range result.Properties {
+		annotations[LabelName(property)] = value
+	}
+
+	annotations["summary"] = result.Message
+	annotations["priority"] = result.Priority.String()
+	annotations["resultID"] = result.GetID()
+
+	return annotations
+}
+
+type client struct {
+	target.BaseClient
+	host         string
+	expiry       time.Duration
+	customFields map[string]string
+	headers      map[string]secret.String
+	token        string
+	client       http.Client
+}
+
+func (c *client) push(result v1alpha2.PolicyReportResult, endsAt time.Time) {
+	startsAt := time.Now()
+	if result.Timestamp.Seconds > 0 {
+		startsAt = time.Unix(result.Timestamp.Seconds, int64(result.Timestamp.Nanos))
+	}
+	if startsAt.After(endsAt) {
+		startsAt = endsAt
+	}
+
+	req, err := http.CreateJSONRequest(c.Name(), "POST", c.host+"/api/v2/alerts", []Alert{{
+		Labels:       Labels(result, c.customFields),
+		Annotations:  Annotations(result),
+		StartsAt:     startsAt,
+		EndsAt:       endsAt,
+		GeneratorURL: result.Properties[v1alpha2.PolicyURLKey],
+	}})
+	if err != nil {
+		return
+	}
+
+	tracing.Inject(req, result.TraceParent)
+
+	for header, value := range c.headers {
+		req.Header.Set(header, value.Value())
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	http.ProcessHTTPResponse(c.Name(), result.GetID(), resp, err)
+}
+
+// Send fires the alert of the result until it is resolved or expires
+func (c *client) Send(result v1alpha2.PolicyReportResult) {
+	c.push(result, time.Now().Add(c.expiry))
+}
+
+// Resolve ends the alert of the result
+func (c *client) Resolve(result v1alpha2.PolicyReportResult) {
+	c.push(result, time.Now())
+}
+
+// NewClient creates a new alertmanager.client to send Results as alerts to an Alertmanager instance
+func NewClient(options Options) target.ResolvingClient {
+	expiry := options.Expiry
+	if expiry <= 0 {
+		expiry = DefaultExpiry
+	}
+
+	return &client{
+		target.NewBaseClient(options.ClientOptions),
+		strings.TrimSuffix(options.Host, "/"),
+		expiry,
+		options.CustomFields,
+		options.Headers,
+		options.Token.Value(),
+		options.HTTPClient,
+	}
+}
@@ -0,0 +1,101 @@
+package alertmanager_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/secret"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/alertmanager"
+)
+
+type testClient struct {
+	requests []*http.Request
+	alerts   [][]alertmanager.Alert
+}
+
+func (c *testClient) Do(req *http.Request) (*http.Response, error) {
+	alerts := make([]alertmanager.Alert, 0)
+	json.NewDecoder(req.Body).Decode(&alerts)
+
+	c.requests = append(c.requests, req)
+	c.alerts = append(c.alerts, alerts)
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader("")),
+	}, nil
+}
+
+func Test_AlertmanagerTarget(t *testing.T) {
+	http := &testClient{}
+	client := alertmanager.NewClient(alertmanager.Options{
+		ClientOptions: target.ClientOptions{
+			Name: "Alertmanager",
+		},
+		Host:         "http://alertmanager:9093",
+		Token:        "token",
+		Expiry:       time.Hour,
+		CustomFields: map[string]string{"cluster": "prod", "k8s.region": "eu"},
+		Headers:      map[string]secret.String{"X-Scope-OrgID": "tenant"},
+		HTTPClient:   http,
+	})
+
+	t.Run("Send", func(t *testing.T) {
+		client.Send(fixtures.CompleteTargetSendResult)
+
+		req := http.requests[0]
+		if url := req.URL.String(); req.Method != "POST" || url != "http://alertmanager:9093/api/v2/alerts" {
+			t.Errorf("unexpected request: %s %s", req.Method, url)
+		}
+		if value := req.Header.Get("Authorization"); value != "Bearer token" {
+			t.Errorf("unexpected Authorization Header: %s", value)
+		}
+		if value := req.Header.Get("X-Scope-OrgID"); value != "tenant" {
+			t.Errorf("unexpected X-Scope-OrgID Header: %s", value)
+		}
+
+		if len(http.alerts[0]) != 1 {
+			t.Fatalf("expected one alert, got %d", len(http.alerts[0]))
+		}
+
+		alert := http.alerts[0][0]
+		if alert.Labels["alertname"] != fixtures.CompleteTargetSendResult.Policy || alert.Labels["namespace"] != "default" || alert.Labels["kind"] != "Deployment" {
+			t.Errorf("unexpected labels: %v", alert.Labels)
+		}
+		if alert.Labels["cluster"] != "prod" || alert.Labels["k8s_region"] != "eu" {
+			t.Errorf("expected sanitized custom field labels: %v", alert.Labels)
+		}
+		if alert.Annotations["summary"] != fixtures.CompleteTargetSendResult.Message {
+			t.Errorf("unexpected annotations: %v", alert.Annotations)
+		}
+		if expiry := alert.EndsAt.Sub(time.Now()); expiry < 59*time.Minute || expiry > time.Hour {
+			t.Errorf("expected alert to expire in 1h, got %s", expiry)
+		}
+	})
+
+	t.Run("Resolve", func(t *testing.T) {
+		client.Resolve(fixtures.CompleteTargetSendResult)
+
+		alert := http.alerts[1][0]
+		if alert.EndsAt.After(time.Now()) {
+			t.Errorf("expected ended alert, got endsAt %s", alert.EndsAt)
+		}
+		if len(alert.Labels) != len(http.alerts[0][0].Labels) {
+			t.Errorf("expected the labels of the firing alert, got %v", alert.Labels)
+		}
+	})
+}
+
+func Test_LabelName(t *testing.T) {
+	for name, expected := range map[string]string{"app.kubernetes.io/name": "app_kubernetes_io_name", "1st": "_1st", "cluster": "cluster"} {
+		if label := alertmanager.LabelName(name); label != expected {
+			t.Errorf("expected %s, got %s", expected, label)
+		}
+	}
+}
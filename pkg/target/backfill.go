@@ -0,0 +1,79 @@
+package target
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// Backfill decides which results of the PolicyReports existing on startup are sent to a target
+type Backfill struct {
+	// all sends every existing result, otherwise only results newer than since
+	all   bool
+	since time.Duration
+	now   func() time.Time
+}
+
+// None reports if no existing result is sent
+func (b *Backfill) None() bool {
+	return b != nil && !b.all && b.since == 0
+}
+
+// Send reports if the existing result is sent, results without timestamp use the creation time of their report.
+// A nil Backfill sends all results
+func (b *Backfill) Send(rep v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) bool {
+	if b == nil || b.all {
+		return true
+	}
+	if b.since == 0 {
+		return false
+	}
+
+	created := time.Unix(result.Timestamp.Seconds, int64(result.Timestamp.Nanos))
+	if result.Timestamp.Seconds == 0 {
+		if rep == nil {
+			return false
+		}
+
+		created = rep.GetCreationTimestamp().Time
+	}
+
+	return created.After(b.now().Add(-b.since))
+}
+
+// ParseBackfill parses the sendExistingResults option: true, false or since=<duration>,
+// an empty value returns nil to keep the skipExistingOnStartup behavior of the target
+func ParseBackfill(value string) (*Backfill, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	if duration, ok := cutPrefix(value, "since="); ok {
+		since, err := time.ParseDuration(duration)
+		if err != nil || since <= 0 {
+			return nil, fmt.Errorf("invalid duration '%s', expected a positive duration like since=24h", duration)
+		}
+
+		return &Backfill{since: since, now: time.Now}, nil
+	}
+
+	all, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("unknown value '%s', expected true, false or since=<duration>", value)
+	}
+
+	return &Backfill{all: all, now: time.Now}, nil
+}
+
+// cutPrefix is strings.CutPrefix, which requires Go 1.20
+func cutPrefix(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+
+	return s[len(prefix):], true
+}
@@ -0,0 +1,62 @@
+package target_test
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/target"
+)
+
+func Test_ParseBackfill(t *testing.T) {
+	if b, err := target.ParseBackfill(""); b != nil || err != nil {
+		t.Errorf("expected no backfill for an empty value, got %v, %v", b, err)
+	}
+
+	for _, value := range []string{"since=", "since=-1h", "maybe"} {
+		if _, err := target.ParseBackfill(value); err == nil {
+			t.Errorf("expected error for %s", value)
+		}
+	}
+
+	// viper decodes a YAML boolean into 0 or 1
+	for value, none := range map[string]bool{"false": true, "0": true, "true": false, "1": false, "since=24h": false} {
+		b, err := target.ParseBackfill(value)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", value, err)
+		}
+		if b.None() != none {
+			t.Errorf("expected None() %v for %s", none, value)
+		}
+	}
+}
+
+func Test_BackfillSend(t *testing.T) {
+	recent := v1alpha2.PolicyReportResult{Timestamp: metav1.Timestamp{Seconds: time.Now().Add(-time.Hour).Unix()}}
+	old := v1alpha2.PolicyReportResult{Timestamp: metav1.Timestamp{Seconds: time.Now().Add(-48 * time.Hour).Unix()}}
+
+	b, _ := target.ParseBackfill("since=24h")
+	if !b.Send(nil, recent) {
+		t.Error("expected result within the window to be sent")
+	}
+	if b.Send(nil, old) {
+		t.Error("expected result before the window to be skipped")
+	}
+
+	rep := &v1alpha2.PolicyReport{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Minute))}}
+	if !b.Send(rep, v1alpha2.PolicyReportResult{}) {
+		t.Error("expected the creation time of the report for results without timestamp")
+	}
+
+	none, _ := target.ParseBackfill("false")
+	if none.Send(rep, recent) {
+		t.Error("expected no result to be sent")
+	}
+
+	var unset *target.Backfill
+	if !unset.Send(rep, old) || unset.None() {
+		t.Error("expected a nil backfill to send all results")
+	}
+}
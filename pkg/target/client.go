@@ -1,12 +1,9 @@
 package target
 
 import (
-	"strings"
-
-	"github.com/kyverno/go-wildcard"
+	"time"
 
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
-	"github.com/kyverno/policy-reporter/pkg/helper"
 	"github.com/kyverno/policy-reporter/pkg/report"
 	"github.com/kyverno/policy-reporter/pkg/validate"
 )
@@ -25,6 +22,43 @@ type Client interface {
 	MinimumPriority() string
 	// Sources of the Results which should send to this target, empty means all sources
 	Sources() []string
+	// Concurrency is the amount of parallel Send calls, zero uses the default of the dispatcher
+	Concurrency() int
+	// NotificationTTL is the time a sent result is not sent again, zero uses the global TTL of the notification cache
+	NotificationTTL() time.Duration
+	// Sampler limits the results per policy, nil if sampling is not configured
+	Sampler() *Sampler
+	// Grouper merges results into one notification, nil if grouping is not configured
+	Grouper() *Grouper
+	// Schedule holds results outside of the delivery windows, nil if the target delivers at any time
+	Schedule() *Schedule
+	// Scrubber removes sensitive values from the results before they are sent, nil if scrubbing is not configured
+	Scrubber() *Scrubber
+	// Backfill decides which results existing on startup are sent, nil keeps the SkipExistingOnStartup behavior
+	Backfill() *Backfill
+}
+
+// ReportClient is a Client which receives changed PolicyReports instead of single results
+type ReportClient interface {
+	Client
+	// SendReport of the event, previous is the summary of the last version of the PolicyReport, empty for new reports
+	SendReport(event report.LifecycleEvent, previous v1alpha2.PolicyReportSummary)
+	// ValidateReport if the PolicyReport should send
+	ValidateReport(rep v1alpha2.ReportInterface) bool
+}
+
+// ResolvingClient is a Client which is notified when a previously sent violation is resolved
+type ResolvingClient interface {
+	Client
+	// Resolve the given Result which passed or was removed from its PolicyReport
+	Resolve(result v1alpha2.PolicyReportResult)
+}
+
+// FlushClient is a Client which sends the results in batches
+type FlushClient interface {
+	Client
+	// Flush sends the pending results
+	Flush()
 }
 
 func NewResultFilter(namespace, priority, policy validate.RuleSets, minimumPriority string, sources []string) *report.ResultFilter {
@@ -34,7 +68,7 @@ func NewResultFilter(namespace, priority, policy validate.RuleSets, minimumPrior
 
 	if len(sources) > 0 {
 		f.AddValidation(func(r v1alpha2.PolicyReportResult) bool {
-			return helper.Contains(r.Source, sources)
+			return validate.MatchAny(sources, r.Source)
 		})
 	}
 
@@ -62,7 +96,7 @@ func NewResultFilter(namespace, priority, policy validate.RuleSets, minimumPrior
 
 	if priority.Count() > 0 {
 		f.AddValidation(func(r v1alpha2.PolicyReportResult) bool {
-			return validate.ContainsRuleSet(r.Priority.String(), priority)
+			return validate.MatchRuleSet(r.Priority.String(), priority)
 		})
 	}
 
@@ -73,43 +107,7 @@ func NewReportFilter(labels validate.RuleSets) *report.ReportFilter {
 	f := report.NewReportFilter()
 	if labels.Count() > 0 {
 		f.AddValidation(func(r v1alpha2.ReportInterface) bool {
-			if len(labels.Include) > 0 {
-				for _, label := range labels.Include {
-					parts := strings.Split(label, ":")
-					if len(parts) == 1 {
-						parts = append(parts, "*")
-					}
-
-					labelName := strings.TrimSpace(parts[0])
-					labelValue := strings.TrimSpace(parts[1])
-
-					for key, value := range r.GetLabels() {
-						if labelName == key && wildcard.Match(labelValue, value) {
-							return true
-						}
-					}
-				}
-
-				return false
-			} else if len(labels.Exclude) > 0 {
-				for _, label := range labels.Exclude {
-					parts := strings.Split(label, ":")
-					if len(parts) == 1 {
-						parts = append(parts, "*")
-					}
-
-					labelName := strings.TrimSpace(parts[0])
-					labelValue := strings.TrimSpace(parts[1])
-
-					for key, value := range r.GetLabels() {
-						if labelName == key && wildcard.Match(labelValue, value) {
-							return false
-						}
-					}
-				}
-			}
-
-			return true
+			return validate.MatchLabels(r.GetLabels(), labels)
 		})
 	}
 
@@ -121,11 +119,25 @@ type BaseClient struct {
 	skipExistingOnStartup bool
 	resultFilter          *report.ResultFilter
 	reportFilter          *report.ReportFilter
+	concurrency           int
+	notificationTTL       time.Duration
+	sampler               *Sampler
+	grouper               *Grouper
+	schedule              *Schedule
+	scrubber              *Scrubber
+	backfill              *Backfill
 }
 
 type ClientOptions struct {
 	Name                  string
 	SkipExistingOnStartup bool
+	Concurrency           int
+	NotificationTTL       time.Duration
+	Sampler               *Sampler
+	Grouper               *Grouper
+	Schedule              *Schedule
+	Scrubber              *Scrubber
+	Backfill              *Backfill
 	ResultFilter          *report.ResultFilter
 	ReportFilter          *report.ReportFilter
 }
@@ -159,17 +171,58 @@ func (c *BaseClient) Validate(rep v1alpha2.ReportInterface, result v1alpha2.Poli
 		return false
 	}
 
-	if c.resultFilter != nil && !c.resultFilter.Validate(result) {
+	if c.resultFilter != nil && !c.resultFilter.ValidateReportResult(rep, result) {
 		return false
 	}
 
 	return true
 }
 
+func (c *BaseClient) ValidateReport(rep v1alpha2.ReportInterface) bool {
+	if rep == nil {
+		return false
+	}
+
+	return c.reportFilter == nil || c.reportFilter.Validate(rep)
+}
+
+// SkipExistingOnStartup is overridden by the Backfill if configured
 func (c *BaseClient) SkipExistingOnStartup() bool {
+	if c.backfill != nil {
+		return c.backfill.None()
+	}
+
 	return c.skipExistingOnStartup
 }
 
+func (c *BaseClient) Concurrency() int {
+	return c.concurrency
+}
+
+func (c *BaseClient) NotificationTTL() time.Duration {
+	return c.notificationTTL
+}
+
+func (c *BaseClient) Sampler() *Sampler {
+	return c.sampler
+}
+
+func (c *BaseClient) Grouper() *Grouper {
+	return c.grouper
+}
+
+func (c *BaseClient) Schedule() *Schedule {
+	return c.schedule
+}
+
+func (c *BaseClient) Scrubber() *Scrubber {
+	return c.scrubber
+}
+
+func (c *BaseClient) Backfill() *Backfill {
+	return c.backfill
+}
+
 func NewBaseClient(options ClientOptions) BaseClient {
-	return BaseClient{options.Name, options.SkipExistingOnStartup, options.ResultFilter, options.ReportFilter}
+	return BaseClient{options.Name, options.SkipExistingOnStartup, options.ResultFilter, options.ReportFilter, options.Concurrency, options.NotificationTTL, options.Sampler, options.Grouper, options.Schedule, options.Scrubber, options.Backfill}
 }
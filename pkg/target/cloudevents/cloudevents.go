@@ -0,0 +1,153 @@
+// Package cloudevents wraps the target payloads in CloudEvents 1.0 envelopes, for consumers like Knative eventing
+package cloudevents
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// SpecVersion of the envelopes
+const SpecVersion = "1.0"
+
+// Modes of the HTTP binding
+const (
+	// ModeStructured sends the envelope with the payload as data in the JSON body
+	ModeStructured = "structured"
+	// ModeBinary sends the payload as body and the attributes as ce- headers
+	ModeBinary = "binary"
+)
+
+// DefaultSource of the events
+const DefaultSource = "policy-reporter"
+
+// Types of the events, the report and summary types are suffixed with the lifecycle event like .add
+const (
+	TypeResult  = "io.kyverno.policyreporter.result"
+	TypeReport  = "io.kyverno.policyreporter.report"
+	TypeSummary = "io.kyverno.policyreporter.summary"
+)
+
+const (
+	structuredContentType = "application/cloudevents+json; charset=utf-8"
+	dataContentType       = "application/json"
+)
+
+// Event is a CloudEvents 1.0 envelope with JSON data
+type Event struct {
+	SpecVersion     string      `json:"specversion"`
+	ID              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            string      `json:"type"`
+	Subject         string      `json:"subject,omitempty"`
+	Time            time.Time   `json:"time"`
+	DataContentType string      `json:"datacontenttype"`
+	Data            interface{} `json:"data"`
+}
+
+// Options of the envelopes
+type Options struct {
+	// Mode defaults to ModeStructured, targets without HTTP binding always use ModeStructured
+	Mode string
+	// Source attribute, defaults to DefaultSource
+	Source string
+}
+
+// New creates an Event with a random ID
+func (o *Options) New(eventType, subject string, data interface{}) Event {
+	source := o.Source
+	if source == "" {
+		source = DefaultSource
+	}
+
+	return Event{
+		SpecVersion:     SpecVersion,
+		ID:              newID(),
+		Source:          source,
+		Type:            eventType,
+		Subject:         subject,
+		Time:            time.Now().UTC(),
+		DataContentType: dataContentType,
+		Data:            data,
+	}
+}
+
+// Request creates the HTTP request of the event in the configured mode
+func (o *Options) Request(method, host string, e Event) (*http.Request, error) {
+	if o.Mode != ModeBinary {
+		body := new(bytes.Buffer)
+		if err := json.NewEncoder(body).Encode(e); err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequest(method, host, body)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Content-Type", structuredContentType)
+		req.Header.Set("User-Agent", "Policy-Reporter")
+
+		return req, nil
+	}
+
+	body := new(bytes.Buffer)
+	if err := json.NewEncoder(body).Encode(e.Data); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, host, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", e.DataContentType)
+	req.Header.Set("User-Agent", "Policy-Reporter")
+	req.Header.Set("ce-specversion", e.SpecVersion)
+	req.Header.Set("ce-id", e.ID)
+	req.Header.Set("ce-source", e.Source)
+	req.Header.Set("ce-type", e.Type)
+	req.Header.Set("ce-time", e.Time.Format(time.RFC3339Nano))
+	if e.Subject != "" {
+		req.Header.Set("ce-subject", e.Subject)
+	}
+
+	return req, nil
+}
+
+// ResultSubject is the namespace, kind and name of the result resource, the policy for results without resource
+func ResultSubject(result v1alpha2.PolicyReportResult) string {
+	res := result.GetResource()
+	if res == nil {
+		return result.Policy
+	}
+
+	parts := make([]string, 0, 3)
+	if res.Namespace != "" {
+		parts = append(parts, res.Namespace)
+	}
+
+	return strings.Join(append(parts, res.Kind, res.Name), "/")
+}
+
+// ReportSubject is the namespace and name of the report
+func ReportSubject(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+
+	return namespace + "/" + name
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
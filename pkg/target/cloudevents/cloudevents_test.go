@@ -0,0 +1,75 @@
+package cloudevents_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/target/cloudevents"
+)
+
+func Test_Request(t *testing.T) {
+	options := &cloudevents.Options{}
+	event := options.New(cloudevents.TypeResult, "default/Deployment/nginx", map[string]string{"policy": "require-labels"})
+
+	if event.ID == "" || event.ID == options.New(cloudevents.TypeResult, "", nil).ID {
+		t.Errorf("expected unique event IDs")
+	}
+	if event.Source != cloudevents.DefaultSource {
+		t.Errorf("expected default source, got %s", event.Source)
+	}
+
+	t.Run("Structured", func(t *testing.T) {
+		req, err := options.Request("POST", "http://localhost:8080", event)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if req.Header.Get("Content-Type") != "application/cloudevents+json; charset=utf-8" {
+			t.Errorf("unexpected content type %s", req.Header.Get("Content-Type"))
+		}
+
+		body := map[string]interface{}{}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		for _, attribute := range []string{"specversion", "id", "source", "type", "subject", "time", "datacontenttype", "data"} {
+			if _, ok := body[attribute]; !ok {
+				t.Errorf("expected attribute %s", attribute)
+			}
+		}
+	})
+
+	t.Run("Binary", func(t *testing.T) {
+		binary := &cloudevents.Options{Mode: cloudevents.ModeBinary}
+
+		req, err := binary.Request("POST", "http://localhost:8080", event)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if req.Header.Get("Content-Type") != "application/json" || req.Header.Get("ce-id") != event.ID || req.Header.Get("ce-subject") != event.Subject {
+			t.Errorf("unexpected headers %v", req.Header)
+		}
+
+		body := map[string]string{}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if body["policy"] != "require-labels" {
+			t.Errorf("expected the data as body, got %v", body)
+		}
+	})
+}
+
+func Test_Subject(t *testing.T) {
+	if subject := cloudevents.ResultSubject(fixtures.CompleteTargetSendResult); subject != "default/Deployment/nginx" {
+		t.Errorf("unexpected subject %s", subject)
+	}
+	if subject := cloudevents.ResultSubject(fixtures.MinimalTargetSendResult); subject != fixtures.MinimalTargetSendResult.Policy {
+		t.Errorf("expected the policy for results without resource, got %s", subject)
+	}
+	if subject := cloudevents.ReportSubject("", "cpolr"); subject != "cpolr" {
+		t.Errorf("unexpected subject %s", subject)
+	}
+}
@@ -0,0 +1,434 @@
+package defectdojo
+
+import (
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/audit"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/secret"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/http"
+	"github.com/kyverno/policy-reporter/pkg/target/issues"
+)
+
+var logger = logging.Component(logging.Targets)
+
+// DefaultEngagement name, used without cluster name
+const DefaultEngagement = "Policy Reporter"
+
+// DefaultTestType of the created tests and findings
+const DefaultTestType = "Policy Reporter"
+
+// Options to configure the DefectDojo target
+type Options struct {
+	target.ClientOptions
+	Host   string
+	APIKey secret.String
+	// Product the engagement is created in, it has to exist
+	Product string
+	// Engagement of the findings, one per cluster. Created if missing
+	Engagement string
+	// TestType of the tests created per source. Created if missing
+	TestType     string
+	CustomFields map[string]string
+	Remediation  *target.RemediationTemplate
+	HTTPClient   http.Client
+}
+
+// Finding of the DefectDojo API
+type Finding struct {
+	ID                int      `json:"id,omitempty"`
+	Test              int      `json:"test"`
+	FoundBy           []int    `json:"found_by"`
+	Title             string   `json:"title"`
+	Date              string   `json:"date"`
+	Severity          string   `json:"severity"`
+	NumericalSeverity string   `json:"numerical_severity"`
+	Description       string   `json:"description"`
+	Mitigation        string   `json:"mitigation,omitempty"`
+	References        string   `json:"references,omitempty"`
+	ComponentName     string   `json:"component_name,omitempty"`
+	UniqueIDFromTool  string   `json:"unique_id_from_tool"`
+	Active            bool     `json:"active"`
+	Verified          bool     `json:"verified"`
+	StaticFinding     bool     `json:"static_finding"`
+	IsMitigated       bool     `json:"is_mitigated"`
+	Tags              []string `json:"tags,omitempty"`
+}
+
+type object struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Active bool   `json:"active"`
+}
+
+type page struct {
+	Count   int      `json:"count"`
+	Results []object `json:"results"`
+}
+
+// Severity of the finding, the priority is used for results without severity
+func Severity(result v1alpha2.PolicyReportResult) (string, string) {
+	severity := ""
+	switch result.Severity {
+	case v1alpha2.SeverityCritical:
+		severity = "Critical"
+	case v1alpha2.SeverityHigh:
+		severity = "High"
+	case v1alpha2.SeverityMedium:
+		severity = "Medium"
+	case v1alpha2.SeverityLow:
+		severity = "Low"
+	case v1alpha2.SeverityInfo:
+		severity = "Info"
+	default:
+		switch result.Priority {
+		case v1alpha2.CriticalPriority:
+			severity = "Critical"
+		case v1alpha2.ErrorPriority:
+			severity = "High"
+		case v1alpha2.WarningPriority:
+			severity = "Medium"
+		case v1alpha2.InfoPriority:
+			severity = "Low"
+		default:
+			severity = "Info"
+		}
+	}
+
+	numerical := map[string]string{"Critical": "S0", "High": "S1", "Medium": "S2", "Low": "S3", "Info": "S4"}
+
+	return severity, numerical[severity]
+}
+
+type client struct {
+	target.BaseClient
+	host         string
+	apiKey       string
+	product      string
+	engagement   string
+	testType     string
+	customFields map[string]string
+	remediation  *target.RemediationTemplate
+	client       http.Client
+	mx           *sync.Mutex
+	engagementID int
+	testTypeID   int
+	tests        map[string]int
+	findings     map[string]int
+}
+
+func (c *client) finding(test int, result v1alpha2.PolicyReportResult) Finding {
+	date := time.Unix(result.Timestamp.Seconds, int64(result.Timestamp.Nanos)).UTC()
+	if result.Timestamp.Seconds == 0 {
+		date = time.Now().UTC()
+	}
+
+	severity, numerical := Severity(result)
+
+	title := result.Policy
+	if result.Rule != "" {
+		title = result.Policy + ": " + result.Rule
+	}
+
+	component := ""
+	if result.HasResource() {
+		res := result.GetResource()
+
+		component = res.Kind + "/" + res.Name
+		if res.Namespace != "" {
+			component = res.Namespace + "/" + component
+		}
+	}
+
+	tags := make([]string, 0, len(c.customFields)+2)
+	if result.Category != "" {
+		tags = append(tags, result.Category)
+	}
+	for property, value := range c.customFields {
+		tags = append(tags, property+":"+value)
+	}
+	sort.Strings(tags)
+
+	return Finding{
+		Test:              test,
+		FoundBy:           []int{c.testTypeID},
+		Title:             title,
+		Date:              date.Format("2006-01-02"),
+		Severity:          severity,
+		NumericalSeverity: numerical,
+		Description:       issues.Body(result, ""),
+		Mitigation:        c.remediation.Render(result),
+		References:        result.Properties[v1alpha2.PolicyURLKey],
+		ComponentName:     component,
+		UniqueIDFromTool:  result.GetID(),
+		Active:            true,
+		StaticFinding:     true,
+		Tags:              tags,
+	}
+}
+
+// lookup returns the first object of the list endpoint matching the query, or an empty object
+func (c *client) lookup(path string, query url.Values) (object, error) {
+	req, err := c.request("GET", c.host+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return object{}, err
+	}
+
+	list := page{}
+	if err := issues.Do(c.client, req, &list); err != nil {
+		return object{}, err
+	}
+	if len(list.Results) == 0 {
+		return object{}, nil
+	}
+
+	return list.Results[0], nil
+}
+
+func (c *client) create(path string, payload interface{}) (int, error) {
+	req, err := c.request("POST", c.host+path, payload)
+	if err != nil {
+		return 0, err
+	}
+
+	created := object{}
+	if err := issues.Do(c.client, req, &created); err != nil {
+		return 0, err
+	}
+
+	return created.ID, nil
+}
+
+// findOrCreate looks up the object and creates it if missing
+func (c *client) findOrCreate(path string, query url.Values, payload interface{}) (int, error) {
+	existing, err := c.lookup(path, query)
+	if err != nil {
+		return 0, err
+	}
+	if existing.ID != 0 {
+		return existing.ID, nil
+	}
+
+	logger.Infof("%s creating %s", c.Name(), strings.Trim(path, "/"))
+
+	return c.create(path, payload)
+}
+
+// context returns the test of the source, the engagement, test type and test are created if missing
+func (c *client) context(source string) (int, error) {
+	if source == "" {
+		source = DefaultTestType
+	}
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if id, ok := c.tests[source]; ok {
+		return id, nil
+	}
+
+	now := time.Now().UTC()
+
+	if c.engagementID == 0 {
+		product, err := c.lookup("/api/v2/products/", url.Values{"name": {c.product}})
+		if err != nil {
+			return 0, fmt.Errorf("failed to get product: %w", err)
+		}
+		if product.ID == 0 {
+			return 0, fmt.Errorf("product %s not found", c.product)
+		}
+
+		c.engagementID, err = c.findOrCreate("/api/v2/engagements/", url.Values{"product": {fmt.Sprint(product.ID)}, "name": {c.engagement}}, map[string]interface{}{
+			"name":            c.engagement,
+			"product":         product.ID,
+			"engagement_type": "CI/CD",
+			"status":          "In Progress",
+			"target_start":    now.Format("2006-01-02"),
+			"target_end":      now.AddDate(1, 0, 0).Format("2006-01-02"),
+			"description":     "Kubernetes policy findings of PolicyReports",
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to get engagement: %w", err)
+		}
+	}
+
+	if c.testTypeID == 0 {
+		var err error
+		c.testTypeID, err = c.findOrCreate("/api/v2/test_types/", url.Values{"name": {c.testType}}, map[string]interface{}{
+			"name":        c.testType,
+			"static_tool": true,
+			"active":      true,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to get test type: %w", err)
+		}
+	}
+
+	id, err := c.findOrCreate("/api/v2/tests/", url.Values{"engagement": {fmt.Sprint(c.engagementID)}, "title": {source}}, map[string]interface{}{
+		"engagement":   c.engagementID,
+		"test_type":    c.testTypeID,
+		"title":        source,
+		"target_start": now.Format(time.RFC3339),
+		"target_end":   now.AddDate(1, 0, 0).Format(time.RFC3339),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get test: %w", err)
+	}
+
+	c.tests[source] = id
+
+	return id, nil
+}
+
+// existing returns the finding of the result, deduplicated by unique_id_from_tool
+func (c *client) existing(test int, result v1alpha2.PolicyReportResult) (object, error) {
+	c.mx.Lock()
+	id, ok := c.findings[result.GetID()]
+	c.mx.Unlock()
+
+	if ok {
+		return object{ID: id, Active: true}, nil
+	}
+
+	return c.lookup("/api/v2/findings/", url.Values{"test": {fmt.Sprint(test)}, "unique_id_from_tool": {result.GetID()}})
+}
+
+func (c *client) track(result v1alpha2.PolicyReportResult, id int) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if id == 0 {
+		delete(c.findings, result.GetID())
+		return
+	}
+
+	c.findings[result.GetID()] = id
+}
+
+func (c *client) update(id int, payload interface{}) error {
+	req, err := c.request("PATCH", fmt.Sprintf("%s/api/v2/findings/%d/", c.host, id), payload)
+	if err != nil {
+		return err
+	}
+
+	return issues.Do(c.client, req, nil)
+}
+
+// Send creates the finding of the result, an existing mitigated finding is reactivated
+func (c *client) Send(result v1alpha2.PolicyReportResult) {
+	test, err := c.context(result.Source)
+	if err != nil {
+		c.failed(result, err)
+		return
+	}
+
+	finding, err := c.existing(test, result)
+	if err != nil {
+		c.failed(result, err)
+		return
+	}
+
+	switch {
+	case finding.ID != 0 && finding.Active:
+		c.track(result, finding.ID)
+		return
+	case finding.ID != 0:
+		err = c.update(finding.ID, map[string]interface{}{"active": true, "is_mitigated": false, "mitigated": nil})
+	default:
+		finding.ID, err = c.create("/api/v2/findings/", c.finding(test, result))
+	}
+
+	if err != nil {
+		c.failed(result, err)
+		return
+	}
+
+	c.track(result, finding.ID)
+	c.succeeded(result)
+}
+
+// Resolve mitigates the finding of the result
+func (c *client) Resolve(result v1alpha2.PolicyReportResult) {
+	test, err := c.context(result.Source)
+	if err != nil {
+		c.failed(result, err)
+		return
+	}
+
+	finding, err := c.existing(test, result)
+	if err != nil {
+		c.failed(result, err)
+		return
+	}
+	if finding.ID == 0 || !finding.Active {
+		c.track(result, 0)
+		return
+	}
+
+	if err := c.update(finding.ID, map[string]interface{}{"active": false, "is_mitigated": true, "mitigated": time.Now().UTC().Format(time.RFC3339)}); err != nil {
+		c.failed(result, err)
+		return
+	}
+
+	c.track(result, 0)
+	c.succeeded(result)
+}
+
+func (c *client) request(method, endpoint string, payload interface{}) (*nethttp.Request, error) {
+	req, err := issues.NewRequest(c.Name(), method, endpoint, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Token "+c.apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	return req, nil
+}
+
+func (c *client) failed(result v1alpha2.PolicyReportResult, err error) {
+	logger.Errorf("%s PUSH failed: %s", c.Name(), err.Error())
+	audit.Delivery(c.Name(), result.GetID(), 0, err)
+}
+
+func (c *client) succeeded(result v1alpha2.PolicyReportResult) {
+	logger.Infof("%s PUSH OK", c.Name())
+	audit.Delivery(c.Name(), result.GetID(), 0, nil)
+}
+
+// NewClient creates a new DefectDojo.client to import results as findings into DefectDojo
+func NewClient(options Options) target.ResolvingClient {
+	engagement := options.Engagement
+	if engagement == "" {
+		engagement = DefaultEngagement
+	}
+
+	testType := options.TestType
+	if testType == "" {
+		testType = DefaultTestType
+	}
+
+	return &client{
+		BaseClient:   target.NewBaseClient(options.ClientOptions),
+		host:         strings.TrimSuffix(options.Host, "/"),
+		apiKey:       options.APIKey.Value(),
+		product:      options.Product,
+		engagement:   engagement,
+		testType:     testType,
+		customFields: options.CustomFields,
+		remediation:  options.Remediation,
+		client:       options.HTTPClient,
+		mx:           new(sync.Mutex),
+		tests:        make(map[string]int),
+		findings:     make(map[string]int),
+	}
+}
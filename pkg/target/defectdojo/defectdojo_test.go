@@ -0,0 +1,174 @@
+package defectdojo_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/defectdojo"
+)
+
+type testClient struct {
+	requests  []*http.Request
+	payloads  []map[string]interface{}
+	responses map[string]string
+}
+
+func (c *testClient) Do(req *http.Request) (*http.Response, error) {
+	payload := map[string]interface{}{}
+	if req.Body != nil {
+		json.NewDecoder(req.Body).Decode(&payload)
+	}
+
+	c.requests = append(c.requests, req)
+	c.payloads = append(c.payloads, payload)
+
+	body, ok := c.responses[req.Method+" "+req.URL.Path]
+	if !ok {
+		body = `{"count":0,"results":[]}`
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}, nil
+}
+
+func (c *testClient) find(method, path string) (*http.Request, map[string]interface{}) {
+	for i, req := range c.requests {
+		if req.Method == method && req.URL.Path == path {
+			return req, c.payloads[i]
+		}
+	}
+
+	return nil, nil
+}
+
+func newClient(http *testClient) target.ResolvingClient {
+	return defectdojo.NewClient(defectdojo.Options{
+		ClientOptions: target.ClientOptions{
+			Name: "DefectDojo",
+		},
+		Host:         "https://defectdojo.local/",
+		APIKey:       "key",
+		Product:      "platform",
+		Engagement:   "prod",
+		CustomFields: map[string]string{"cluster": "prod"},
+		HTTPClient:   http,
+	})
+}
+
+func Test_DefectDojoTarget(t *testing.T) {
+	t.Run("Send", func(t *testing.T) {
+		http := &testClient{responses: map[string]string{
+			"GET /api/v2/products/":     `{"count":1,"results":[{"id":1,"name":"platform"}]}`,
+			"POST /api/v2/engagements/": `{"id":2}`,
+			"POST /api/v2/test_types/":  `{"id":3}`,
+			"POST /api/v2/tests/":       `{"id":4}`,
+			"POST /api/v2/findings/":    `{"id":5}`,
+		}}
+		client := newClient(http)
+
+		client.Send(fixtures.CompleteTargetSendResult)
+
+		if len(http.requests) != 9 {
+			t.Fatalf("expected 9 requests, got %d", len(http.requests))
+		}
+		if value := http.requests[0].Header.Get("Authorization"); value != "Token key" {
+			t.Errorf("unexpected Authorization Header: %s", value)
+		}
+
+		_, engagement := http.find("POST", "/api/v2/engagements/")
+		if engagement["name"] != "prod" || engagement["product"] != float64(1) {
+			t.Errorf("unexpected engagement: %v", engagement)
+		}
+
+		_, test := http.find("POST", "/api/v2/tests/")
+		if test["title"] != "Kyverno" || test["engagement"] != float64(2) || test["test_type"] != float64(3) {
+			t.Errorf("unexpected test: %v", test)
+		}
+
+		lookup, finding := http.find("POST", "/api/v2/findings/")
+		if lookup == nil {
+			t.Fatal("expected finding to be created")
+		}
+		if finding["unique_id_from_tool"] != fixtures.CompleteTargetSendResult.GetID() || finding["test"] != float64(4) {
+			t.Errorf("unexpected finding: %v", finding)
+		}
+		if finding["severity"] != "High" || finding["numerical_severity"] != "S1" {
+			t.Errorf("unexpected severity: %v", finding["severity"])
+		}
+		if finding["component_name"] != "default/Deployment/nginx" {
+			t.Errorf("unexpected component_name: %v", finding["component_name"])
+		}
+		if tags := finding["tags"].([]interface{}); len(tags) != 2 || tags[0] != "cluster:prod" || tags[1] != "resources" {
+			t.Errorf("unexpected tags: %v", tags)
+		}
+
+		client.Send(fixtures.CompleteTargetSendResult)
+		if len(http.requests) != 9 {
+			t.Errorf("expected no requests for a tracked finding, got %d", len(http.requests))
+		}
+
+		client.Resolve(fixtures.CompleteTargetSendResult)
+		if len(http.requests) != 10 {
+			t.Fatalf("expected mitigation request, got %d requests", len(http.requests))
+		}
+
+		mitigate := http.requests[9]
+		if mitigate.Method != "PATCH" || mitigate.URL.Path != "/api/v2/findings/5/" {
+			t.Errorf("unexpected mitigation request: %s %s", mitigate.Method, mitigate.URL.Path)
+		}
+		if http.payloads[9]["active"] != false || http.payloads[9]["is_mitigated"] != true {
+			t.Errorf("unexpected mitigation: %v", http.payloads[9])
+		}
+	})
+	t.Run("Existing Context and Finding", func(t *testing.T) {
+		http := &testClient{responses: map[string]string{
+			"GET /api/v2/products/":    `{"count":1,"results":[{"id":1}]}`,
+			"GET /api/v2/engagements/": `{"count":1,"results":[{"id":2}]}`,
+			"GET /api/v2/test_types/":  `{"count":1,"results":[{"id":3}]}`,
+			"GET /api/v2/tests/":       `{"count":1,"results":[{"id":4}]}`,
+			"GET /api/v2/findings/":    `{"count":1,"results":[{"id":5,"active":false}]}`,
+		}}
+		client := newClient(http)
+
+		client.Send(fixtures.CompleteTargetSendResult)
+
+		if req, _ := http.find("POST", "/api/v2/findings/"); req != nil {
+			t.Error("expected no duplicate finding")
+		}
+
+		lookup, _ := http.find("GET", "/api/v2/findings/")
+		if id := lookup.URL.Query().Get("unique_id_from_tool"); id != fixtures.CompleteTargetSendResult.GetID() {
+			t.Errorf("unexpected lookup: %s", id)
+		}
+
+		req, payload := http.find("PATCH", "/api/v2/findings/5/")
+		if req == nil || payload["active"] != true {
+			t.Errorf("expected mitigated finding to be reactivated, got %v", payload)
+		}
+	})
+	t.Run("Missing Product", func(t *testing.T) {
+		http := &testClient{}
+		client := newClient(http)
+
+		client.Send(fixtures.CompleteTargetSendResult)
+
+		if len(http.requests) != 1 {
+			t.Errorf("expected only the product lookup, got %d requests", len(http.requests))
+		}
+	})
+}
+
+func Test_Severity(t *testing.T) {
+	severity, numerical := defectdojo.Severity(v1alpha2.PolicyReportResult{Priority: v1alpha2.CriticalPriority})
+	if severity != "Critical" || numerical != "S0" {
+		t.Errorf("unexpected severity: %s %s", severity, numerical)
+	}
+}
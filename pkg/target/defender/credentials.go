@@ -0,0 +1,78 @@
+package defender
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"golang.org/x/oauth2/microsoft"
+
+	"github.com/kyverno/policy-reporter/pkg/secret"
+)
+
+// Scope of the access tokens for the Azure Resource Manager
+const Scope = "https://management.azure.com/.default"
+
+// FederatedTokenFileEnv is set by the Azure Workload Identity webhook
+const FederatedTokenFileEnv = "AZURE_FEDERATED_TOKEN_FILE"
+
+const clientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// federatedSource exchanges the projected service account token of the Workload Identity,
+// the file is read for each exchange because the kubelet rotates the token
+type federatedSource struct {
+	config clientcredentials.Config
+	file   string
+}
+
+func (f *federatedSource) Token() (*oauth2.Token, error) {
+	assertion, err := os.ReadFile(f.file)
+	if err != nil {
+		return nil, err
+	}
+
+	config := f.config
+	config.EndpointParams = url.Values{
+		"client_assertion_type": {clientAssertionType},
+		"client_assertion":      {strings.TrimSpace(string(assertion))},
+	}
+
+	return config.Token(context.Background())
+}
+
+// NewTokenSource creates a cached token source of the Azure AD application, without client secret the
+// token of the Workload Identity is used. Tenant and client ID default to AZURE_TENANT_ID and AZURE_CLIENT_ID
+func NewTokenSource(tenantID, clientID string, clientSecret secret.String) (oauth2.TokenSource, error) {
+	if tenantID == "" {
+		tenantID = os.Getenv("AZURE_TENANT_ID")
+	}
+	if clientID == "" {
+		clientID = os.Getenv("AZURE_CLIENT_ID")
+	}
+	if tenantID == "" || clientID == "" {
+		return nil, errors.New("tenantID and clientID are required")
+	}
+
+	config := clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret.Value(),
+		TokenURL:     microsoft.AzureADEndpoint(tenantID).TokenURL,
+		Scopes:       []string{Scope},
+		AuthStyle:    oauth2.AuthStyleInParams,
+	}
+
+	if clientSecret != "" {
+		return config.TokenSource(context.Background()), nil
+	}
+
+	file := os.Getenv(FederatedTokenFileEnv)
+	if file == "" {
+		return nil, errors.New("clientSecret or Workload Identity required")
+	}
+
+	return oauth2.ReuseTokenSource(nil, &federatedSource{config: config, file: file}), nil
+}
@@ -0,0 +1,340 @@
+package defender
+
+import (
+	"crypto/sha1"
+	"fmt"
+	nethttp "net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+
+	"github.com/kyverno/policy-reporter/pkg/audit"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/http"
+	"github.com/kyverno/policy-reporter/pkg/target/issues"
+)
+
+var logger = logging.Component(logging.Targets)
+
+// DefaultHost of the Azure Resource Manager
+const DefaultHost = "https://management.azure.com"
+
+// APIVersion of the Microsoft.Security assessments API
+const APIVersion = "2021-06-01"
+
+// Status codes of an assessment
+const (
+	StatusHealthy   = "Healthy"
+	StatusUnhealthy = "Unhealthy"
+)
+
+// maxListedResources in the status description of an assessment
+const maxListedResources = 10
+
+// Options to configure the Defender for Cloud target
+type Options struct {
+	target.ClientOptions
+	// Host of the API, defaults to DefaultHost
+	Host           string
+	SubscriptionID string
+	// ResourceID of the assessed Azure resource, e.g. the AKS cluster. Defaults to the subscription
+	ResourceID   string
+	CustomFields map[string]string
+	TokenSource  oauth2.TokenSource
+	HTTPClient   http.Client
+}
+
+// AssessmentMetadata of a custom assessment, one per policy rule
+type AssessmentMetadata struct {
+	DisplayName            string   `json:"displayName"`
+	Description            string   `json:"description,omitempty"`
+	RemediationDescription string   `json:"remediationDescription,omitempty"`
+	Severity               string   `json:"severity"`
+	AssessmentType         string   `json:"assessmentType"`
+	Categories             []string `json:"categories,omitempty"`
+}
+
+// Status of an assessment
+type Status struct {
+	Code        string `json:"code"`
+	Cause       string `json:"cause,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// ResourceDetails of an assessment
+type ResourceDetails struct {
+	Source string `json:"source"`
+	ID     string `json:"id"`
+}
+
+// Assessment of the resource by a policy rule
+type Assessment struct {
+	ResourceDetails ResourceDetails   `json:"resourceDetails"`
+	Status          Status            `json:"status"`
+	AdditionalData  map[string]string `json:"additionalData,omitempty"`
+}
+
+type properties struct {
+	Properties interface{} `json:"properties"`
+}
+
+// AssessmentName of the policy rule, a name based UUID as required for custom assessments
+func AssessmentName(result v1alpha2.PolicyReportResult) string {
+	h := sha1.Sum([]byte(strings.Join([]string{result.Source, result.Policy, result.Rule}, "/")))
+	h[6] = (h[6] & 0x0f) | 0x50
+	h[8] = (h[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", h[0:4], h[4:6], h[6:8], h[8:10], h[10:16])
+}
+
+// Severity of the assessment, the priority is used for results without severity
+func Severity(result v1alpha2.PolicyReportResult) string {
+	switch result.Severity {
+	case v1alpha2.SeverityCritical, v1alpha2.SeverityHigh:
+		return "High"
+	case v1alpha2.SeverityMedium:
+		return "Medium"
+	case v1alpha2.SeverityLow, v1alpha2.SeverityInfo:
+		return "Low"
+	}
+
+	switch result.Priority {
+	case v1alpha2.CriticalPriority, v1alpha2.ErrorPriority:
+		return "High"
+	case v1alpha2.WarningPriority:
+		return "Medium"
+	default:
+		return "Low"
+	}
+}
+
+func resourceKey(result v1alpha2.PolicyReportResult) string {
+	if !result.HasResource() {
+		return result.GetID()
+	}
+
+	res := result.GetResource()
+	if res.Namespace == "" {
+		return res.Kind + "/" + res.Name
+	}
+
+	return strings.Join([]string{res.Namespace, res.Kind, res.Name}, "/")
+}
+
+type client struct {
+	target.BaseClient
+	host           string
+	subscriptionID string
+	resourceID     string
+	customFields   map[string]string
+	tokens         oauth2.TokenSource
+	client         http.Client
+	mx             *sync.Mutex
+	metadata       map[string]bool
+	violations     map[string]map[string]v1alpha2.PolicyReportResult
+}
+
+// registerMetadata creates the assessment metadata of the policy rule once
+func (c *client) registerMetadata(name string, result v1alpha2.PolicyReportResult) error {
+	c.mx.Lock()
+	registered := c.metadata[name]
+	c.mx.Unlock()
+
+	if registered {
+		return nil
+	}
+
+	displayName := result.Policy
+	if result.Rule != "" {
+		displayName = result.Policy + ": " + result.Rule
+	}
+
+	description := fmt.Sprintf("Kubernetes policy %s", result.Policy)
+	if result.Source != "" {
+		description = fmt.Sprintf("%s reported by %s", description, result.Source)
+	}
+	if result.Category != "" {
+		description = fmt.Sprintf("%s, category %s", description, result.Category)
+	}
+
+	req, err := c.request("PUT", fmt.Sprintf("%s/subscriptions/%s/providers/Microsoft.Security/assessmentMetadata/%s", c.host, c.subscriptionID, name), properties{AssessmentMetadata{
+		DisplayName:            displayName,
+		Description:            description,
+		RemediationDescription: result.Properties[v1alpha2.PolicyURLKey],
+		Severity:               Severity(result),
+		AssessmentType:         "CustomerManaged",
+		Categories:             []string{"Compute"},
+	}})
+	if err != nil {
+		return err
+	}
+
+	if err := issues.Do(c.client, req, nil); err != nil {
+		return fmt.Errorf("failed to register assessment metadata: %w", err)
+	}
+
+	c.mx.Lock()
+	c.metadata[name] = true
+	c.mx.Unlock()
+
+	return nil
+}
+
+func (c *client) assessment(result v1alpha2.PolicyReportResult, violations []string) Assessment {
+	data := map[string]string{}
+	for property, value := range c.customFields {
+		data[property] = value
+	}
+	for property, value := range result.Properties {
+		data[property] = value
+	}
+
+	data["policy"] = result.Policy
+	data["source"] = result.Source
+	if result.Rule != "" {
+		data["rule"] = result.Rule
+	}
+	if result.Category != "" {
+		data["category"] = result.Category
+	}
+
+	status := Status{Code: StatusHealthy}
+	if len(violations) > 0 {
+		listed := violations
+		if len(listed) > maxListedResources {
+			listed = listed[:maxListedResources]
+		}
+
+		status = Status{
+			Code:        StatusUnhealthy,
+			Cause:       "PolicyViolation",
+			Description: fmt.Sprintf("%d resources violate the policy: %s", len(violations), strings.Join(listed, ", ")),
+		}
+
+		data["violations"] = strconv.Itoa(len(violations))
+		data["resources"] = strings.Join(violations, ", ")
+		data["message"] = result.Message
+	}
+
+	return Assessment{
+		ResourceDetails: ResourceDetails{Source: "Azure", ID: c.resourceID},
+		Status:          status,
+		AdditionalData:  data,
+	}
+}
+
+// track adds or removes the violation of the result and returns the sorted resources of the open violations
+func (c *client) track(name string, result v1alpha2.PolicyReportResult, open bool) []string {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	violations, ok := c.violations[name]
+	if !ok {
+		violations = make(map[string]v1alpha2.PolicyReportResult)
+		c.violations[name] = violations
+	}
+
+	if open {
+		violations[result.GetID()] = result
+	} else {
+		delete(violations, result.GetID())
+	}
+
+	resources := make([]string, 0, len(violations))
+	for _, r := range violations {
+		resources = append(resources, resourceKey(r))
+	}
+	sort.Strings(resources)
+
+	return resources
+}
+
+func (c *client) assess(result v1alpha2.PolicyReportResult, open bool) {
+	name := AssessmentName(result)
+
+	if err := c.registerMetadata(name, result); err != nil {
+		c.failed(result, err)
+		return
+	}
+
+	req, err := c.request("PUT", fmt.Sprintf("%s%s/providers/Microsoft.Security/assessments/%s", c.host, c.resourceID, name), properties{c.assessment(result, c.track(name, result, open))})
+	if err != nil {
+		return
+	}
+
+	if err := issues.Do(c.client, req, nil); err != nil {
+		c.failed(result, err)
+		return
+	}
+
+	c.succeeded(result)
+}
+
+// Send marks the assessment of the policy rule as unhealthy
+func (c *client) Send(result v1alpha2.PolicyReportResult) {
+	c.assess(result, true)
+}
+
+// Resolve removes the violation from the assessment, the assessment becomes healthy without open violations
+func (c *client) Resolve(result v1alpha2.PolicyReportResult) {
+	c.assess(result, false)
+}
+
+func (c *client) request(method, endpoint string, payload interface{}) (*nethttp.Request, error) {
+	req, err := issues.NewRequest(c.Name(), method, endpoint+"?api-version="+APIVersion, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.tokens != nil {
+		token, err := c.tokens.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		token.SetAuthHeader(req)
+	}
+
+	return req, nil
+}
+
+func (c *client) failed(result v1alpha2.PolicyReportResult, err error) {
+	logger.Errorf("%s PUSH failed: %s", c.Name(), err.Error())
+	audit.Delivery(c.Name(), result.GetID(), 0, err)
+}
+
+func (c *client) succeeded(result v1alpha2.PolicyReportResult) {
+	logger.Infof("%s PUSH OK", c.Name())
+	audit.Delivery(c.Name(), result.GetID(), 0, nil)
+}
+
+// NewClient creates a new Defender.client to publish results as assessments to Microsoft Defender for Cloud
+func NewClient(options Options) target.ResolvingClient {
+	host := options.Host
+	if host == "" {
+		host = DefaultHost
+	}
+
+	resourceID := options.ResourceID
+	if resourceID == "" {
+		resourceID = "/subscriptions/" + options.SubscriptionID
+	}
+
+	return &client{
+		target.NewBaseClient(options.ClientOptions),
+		strings.TrimSuffix(host, "/"),
+		options.SubscriptionID,
+		"/" + strings.Trim(resourceID, "/"),
+		options.CustomFields,
+		options.TokenSource,
+		options.HTTPClient,
+		new(sync.Mutex),
+		make(map[string]bool),
+		make(map[string]map[string]v1alpha2.PolicyReportResult),
+	}
+}
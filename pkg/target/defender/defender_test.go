@@ -0,0 +1,166 @@
+package defender_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/defender"
+)
+
+type testClient struct {
+	requests []*http.Request
+	payloads []map[string]interface{}
+}
+
+func (c *testClient) Do(req *http.Request) (*http.Response, error) {
+	payload := map[string]interface{}{}
+	if req.Body != nil {
+		json.NewDecoder(req.Body).Decode(&payload)
+	}
+
+	c.requests = append(c.requests, req)
+	c.payloads = append(c.payloads, payload)
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader("{}")),
+	}, nil
+}
+
+const resourceID = "/subscriptions/00000000-0000-0000-0000-000000000001/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/prod"
+
+func newClient(http *testClient) target.ResolvingClient {
+	return defender.NewClient(defender.Options{
+		ClientOptions: target.ClientOptions{
+			Name: "DefenderForCloud",
+		},
+		SubscriptionID: "00000000-0000-0000-0000-000000000001",
+		ResourceID:     resourceID,
+		CustomFields:   map[string]string{"cluster": "prod"},
+		TokenSource:    oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}),
+		HTTPClient:     http,
+	})
+}
+
+func status(payload map[string]interface{}) map[string]interface{} {
+	return payload["properties"].(map[string]interface{})["status"].(map[string]interface{})
+}
+
+func Test_DefenderTarget(t *testing.T) {
+	name := defender.AssessmentName(fixtures.CompleteTargetSendResult)
+
+	second := fixtures.CompleteTargetSendResult
+	second.Resources = []corev1.ObjectReference{{APIVersion: "v1", Kind: "Deployment", Name: "redis", Namespace: "default"}}
+
+	t.Run("Send", func(t *testing.T) {
+		http := &testClient{}
+		client := newClient(http)
+
+		client.Send(fixtures.CompleteTargetSendResult)
+
+		if len(http.requests) != 2 {
+			t.Fatalf("expected metadata and assessment requests, got %d", len(http.requests))
+		}
+
+		metadata := http.requests[0]
+		if url := metadata.URL.String(); metadata.Method != "PUT" || url != "https://management.azure.com/subscriptions/00000000-0000-0000-0000-000000000001/providers/Microsoft.Security/assessmentMetadata/"+name+"?api-version="+defender.APIVersion {
+			t.Errorf("unexpected metadata request: %s %s", metadata.Method, url)
+		}
+		if value := metadata.Header.Get("Authorization"); value != "Bearer token" {
+			t.Errorf("unexpected Authorization Header: %s", value)
+		}
+		if properties := http.payloads[0]["properties"].(map[string]interface{}); properties["severity"] != "High" || properties["assessmentType"] != "CustomerManaged" {
+			t.Errorf("unexpected metadata: %v", properties)
+		}
+
+		assessment := http.requests[1]
+		if url := assessment.URL.String(); url != "https://management.azure.com"+resourceID+"/providers/Microsoft.Security/assessments/"+name+"?api-version="+defender.APIVersion {
+			t.Errorf("unexpected assessment URL: %s", url)
+		}
+		if code := status(http.payloads[1])["code"]; code != defender.StatusUnhealthy {
+			t.Errorf("unexpected status: %v", code)
+		}
+		if data := http.payloads[1]["properties"].(map[string]interface{})["additionalData"].(map[string]interface{}); data["cluster"] != "prod" || data["resources"] != "default/Deployment/nginx" {
+			t.Errorf("unexpected additionalData: %v", data)
+		}
+
+		client.Send(second)
+		if len(http.requests) != 3 {
+			t.Fatalf("expected metadata to be registered once, got %d requests", len(http.requests))
+		}
+		if description := status(http.payloads[2])["description"].(string); !strings.HasPrefix(description, "2 resources violate the policy") {
+			t.Errorf("unexpected description: %s", description)
+		}
+	})
+	t.Run("Resolve", func(t *testing.T) {
+		http := &testClient{}
+		client := newClient(http)
+
+		client.Send(fixtures.CompleteTargetSendResult)
+		client.Send(second)
+
+		client.Resolve(fixtures.CompleteTargetSendResult)
+		if code := status(http.payloads[3])["code"]; code != defender.StatusUnhealthy {
+			t.Errorf("expected unhealthy assessment with open violations, got %v", code)
+		}
+
+		client.Resolve(second)
+		if code := status(http.payloads[4])["code"]; code != defender.StatusHealthy {
+			t.Errorf("expected healthy assessment without open violations, got %v", code)
+		}
+	})
+	t.Run("Subscription Resource", func(t *testing.T) {
+		http := &testClient{}
+		client := defender.NewClient(defender.Options{SubscriptionID: "00000000-0000-0000-0000-000000000001", HTTPClient: http})
+
+		client.Send(fixtures.MinimalTargetSendResult)
+		if url := http.requests[1].URL.Path; !strings.HasPrefix(url, "/subscriptions/00000000-0000-0000-0000-000000000001/providers/Microsoft.Security/assessments/") {
+			t.Errorf("unexpected assessment path: %s", url)
+		}
+	})
+}
+
+func Test_AssessmentName(t *testing.T) {
+	name := defender.AssessmentName(fixtures.CompleteTargetSendResult)
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(name) {
+		t.Errorf("expected name based UUID, got %s", name)
+	}
+	if name != defender.AssessmentName(fixtures.CompleteTargetSendResult) {
+		t.Error("expected a stable name")
+	}
+	if name == defender.AssessmentName(fixtures.MinimalTargetSendResult) {
+		t.Error("expected different names for different policies")
+	}
+}
+
+func Test_Severity(t *testing.T) {
+	if severity := defender.Severity(v1alpha2.PolicyReportResult{Priority: v1alpha2.WarningPriority}); severity != "Medium" {
+		t.Errorf("unexpected severity: %s", severity)
+	}
+}
+
+func Test_NewTokenSource(t *testing.T) {
+	if _, err := defender.NewTokenSource("tenant", "client", "secret"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	t.Setenv(defender.FederatedTokenFileEnv, "")
+	if _, err := defender.NewTokenSource("tenant", "client", ""); err == nil {
+		t.Error("expected error without client secret and Workload Identity")
+	}
+
+	t.Setenv(defender.FederatedTokenFileEnv, "/var/run/secrets/azure/tokens/azure-identity-token")
+	if _, err := defender.NewTokenSource("tenant", "client", ""); err != nil {
+		t.Errorf("unexpected error with Workload Identity: %s", err)
+	}
+}
@@ -1,49 +1,97 @@
 package discord
 
 import (
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/secret"
+	"github.com/kyverno/policy-reporter/pkg/severity"
 	"github.com/kyverno/policy-reporter/pkg/target"
 	"github.com/kyverno/policy-reporter/pkg/target/http"
+	"github.com/kyverno/policy-reporter/pkg/tracing"
 )
 
 // Options to configure the Discord target
 type Options struct {
 	target.ClientOptions
-	Webhook      string
+	Webhook      secret.String
 	CustomFields map[string]string
+	Remediation  *target.RemediationTemplate
 	HTTPClient   http.Client
+	// Mentions maps severities to the IDs of the roles mentioned in the message
+	Mentions map[string][]string
 }
 
 type payload struct {
-	Content string  `json:"content"`
-	Embeds  []embed `json:"embeds"`
+	Content         string           `json:"content"`
+	Embeds          []embed          `json:"embeds"`
+	AllowedMentions *allowedMentions `json:"allowed_mentions,omitempty"`
+}
+
+// allowedMentions restricts the notifications to the mentioned roles
+type allowedMentions struct {
+	Parse []string `json:"parse"`
+	Roles []string `json:"roles"`
 }
 
 type embed struct {
 	Title       string       `json:"title"`
 	Description string       `json:"description"`
-	Color       string       `json:"color"`
+	URL         string       `json:"url,omitempty"`
+	Color       int          `json:"color"`
+	Timestamp   string       `json:"timestamp,omitempty"`
+	Footer      *embedFooter `json:"footer,omitempty"`
 	Fields      []embedField `json:"fields"`
 }
 
+type embedFooter struct {
+	Text string `json:"text"`
+}
+
 type embedField struct {
 	Name   string `json:"name"`
 	Value  string `json:"value"`
 	Inline bool   `json:"inline"`
 }
 
-var colors = map[v1alpha2.Priority]string{
-	v1alpha2.DebugPriority:    "12370112",
-	v1alpha2.InfoPriority:     "3066993",
-	v1alpha2.WarningPriority:  "15105570",
-	v1alpha2.CriticalPriority: "15158332",
-	v1alpha2.ErrorPriority:    "15158332",
+var colors = map[v1alpha2.Priority]int{
+	v1alpha2.DebugPriority:    12370112,
+	v1alpha2.InfoPriority:     3066993,
+	v1alpha2.WarningPriority:  15105570,
+	v1alpha2.CriticalPriority: 15158332,
+	v1alpha2.ErrorPriority:    15158332,
+}
+
+var severityColors = map[v1alpha2.PolicySeverity]int{
+	v1alpha2.SeverityInfo:     3447003,
+	v1alpha2.SeverityLow:      3066993,
+	v1alpha2.SeverityMedium:   15844367,
+	v1alpha2.SeverityHigh:     15105570,
+	v1alpha2.SeverityCritical: 15158332,
+}
+
+// customColor converts the hex color of a custom severity into the decimal color of the embed
+func customColor(s v1alpha2.PolicySeverity) (int, bool) {
+	hex, ok := severity.Color(string(s))
+	if !ok {
+		return 0, false
+	}
+
+	color, err := strconv.ParseInt(strings.TrimPrefix(hex, "#"), 16, 32)
+
+	return int(color), err == nil
 }
 
-func newPayload(result v1alpha2.PolicyReportResult, customFields map[string]string) payload {
-	color := colors[result.Priority]
+func newPayload(result v1alpha2.PolicyReportResult, customFields map[string]string, remediation string, roles []string) payload {
+	color, ok := severityColors[result.Severity]
+	if !ok {
+		color, ok = customColor(result.Severity)
+	}
+	if !ok {
+		color = colors[result.Priority]
+	}
 
 	embedFields := make([]embedField, 0)
 
@@ -75,7 +123,20 @@ func newPayload(result v1alpha2.PolicyReportResult, customFields map[string]stri
 		}
 	}
 
+	if description := result.Properties[v1alpha2.PolicyDescriptionKey]; description != "" {
+		embedFields = append(embedFields, embedField{"Description", description, false})
+	}
+	if remediation != "" {
+		embedFields = append(embedFields, embedField{"How to fix", remediation, false})
+	}
+	if url := result.Properties[v1alpha2.ResultURLKey]; url != "" {
+		embedFields = append(embedFields, embedField{"Details", "[View in Policy Reporter](" + url + ")", false})
+	}
+
 	for property, value := range result.Properties {
+		if v1alpha2.IsPolicyGuidanceKey(property) {
+			continue
+		}
 		embedFields = append(embedFields, embedField{strings.Title(property), value, true})
 	}
 
@@ -83,43 +144,66 @@ func newPayload(result v1alpha2.PolicyReportResult, customFields map[string]stri
 		embedFields = append(embedFields, embedField{strings.Title(property), value, true})
 	}
 
-	embeds := make([]embed, 0, 1)
-	embeds = append(embeds, embed{
+	e := embed{
 		Title:       "New Policy Report Result",
 		Description: result.Message,
+		URL:         result.Properties[v1alpha2.PolicyURLKey],
 		Color:       color,
+		Footer:      &embedFooter{Text: "Policy Reporter"},
 		Fields:      embedFields,
-	})
+	}
+
+	if result.Timestamp.Seconds > 0 {
+		e.Timestamp = time.Unix(result.Timestamp.Seconds, int64(result.Timestamp.Nanos)).UTC().Format(time.RFC3339)
+	}
 
-	return payload{
+	p := payload{
 		Content: "",
-		Embeds:  embeds,
+		Embeds:  []embed{e},
 	}
+
+	if len(roles) > 0 {
+		mentions := make([]string, 0, len(roles))
+		for _, role := range roles {
+			mentions = append(mentions, "<@&"+role+">")
+		}
+
+		p.Content = strings.Join(mentions, " ")
+		p.AllowedMentions = &allowedMentions{Parse: []string{}, Roles: roles}
+	}
+
+	return p
 }
 
 type client struct {
 	target.BaseClient
 	webhook      string
 	customFields map[string]string
+	remediation  *target.RemediationTemplate
 	client       http.Client
+	mentions     map[string][]string
 }
 
 func (d *client) Send(result v1alpha2.PolicyReportResult) {
-	req, err := http.CreateJSONRequest(d.Name(), "POST", d.webhook, newPayload(result, d.customFields))
+	req, err := http.CreateJSONRequest(d.Name(), "POST", d.webhook, newPayload(result, d.customFields, d.remediation.Render(result), d.mentions[string(result.Severity)]))
 	if err != nil {
 		return
 	}
 
+	tracing.Inject(req, result.TraceParent)
+
 	resp, err := d.client.Do(req)
-	http.ProcessHTTPResponse(d.Name(), resp, err)
+	http.ProcessHTTPResponse(d.Name(), result.GetID(), resp, err)
 }
 
 // NewClient creates a new loki.client to send Results to Discord
 func NewClient(options Options) target.Client {
 	return &client{
 		target.NewBaseClient(options.ClientOptions),
-		options.Webhook,
+		options.Webhook.Value(),
 		options.CustomFields,
+		options.Remediation,
 		options.HTTPClient,
+		options.Mentions,
 	}
 }
@@ -1,6 +1,7 @@
 package discord_test
 
 import (
+	"encoding/json"
 	"net/http"
 	"testing"
 
@@ -72,6 +73,48 @@ func Test_LokiTarget(t *testing.T) {
 		})
 		client.Send(fixtures.MinimalTargetSendResult)
 	})
+	t.Run("Mention Roles by Severity", func(t *testing.T) {
+		callback := func(req *http.Request) {
+			payload := struct {
+				Content string `json:"content"`
+				Embeds  []struct {
+					Color     int    `json:"color"`
+					Timestamp string `json:"timestamp"`
+				} `json:"embeds"`
+				AllowedMentions struct {
+					Roles []string `json:"roles"`
+				} `json:"allowed_mentions"`
+			}{}
+
+			if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+				t.Fatal(err)
+			}
+
+			if payload.Content != "<@&123> <@&456>" {
+				t.Errorf("Unexpected Content: %s", payload.Content)
+			}
+			if len(payload.AllowedMentions.Roles) != 2 {
+				t.Errorf("Unexpected allowed mentions: %v", payload.AllowedMentions.Roles)
+			}
+			if payload.Embeds[0].Color != 15105570 {
+				t.Errorf("Unexpected severity color: %d", payload.Embeds[0].Color)
+			}
+			if payload.Embeds[0].Timestamp == "" {
+				t.Error("Expected embed timestamp")
+			}
+		}
+
+		client := discord.NewClient(discord.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Discord",
+			},
+			Webhook:    "http://hook.discord:80",
+			HTTPClient: testClient{callback, 200},
+			Mentions:   map[string][]string{"high": {"123", "456"}, "critical": {"789"}},
+		})
+		client.Send(fixtures.CompleteTargetSendResult)
+	})
+
 	t.Run("Name", func(t *testing.T) {
 		client := discord.NewClient(discord.Options{
 			ClientOptions: target.ClientOptions{
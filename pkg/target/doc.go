@@ -0,0 +1,21 @@
+// Package target defines the Client interface implemented by all targets.
+//
+// A target embeds BaseClient, created with NewBaseClient from the ClientOptions, which provides the
+// name, the result and report filters, the sampling and the grouping of the target. It only has to
+// implement Send, which is called concurrently for each new result that passed the filters:
+//
+//	type client struct {
+//		target.BaseClient
+//	}
+//
+//	func (c *client) Send(result v1alpha2.PolicyReportResult) {
+//		// deliver the result, record the outcome with audit.Delivery
+//	}
+//
+// Targets can additionally implement ResolvingClient to be notified about resolved results,
+// ReportClient to receive changed PolicyReports instead of single results and FlushClient to send
+// results in batches.
+//
+// Integrations which should not be compiled into Policy Reporter can use the exec target, which
+// writes each result as JSON to the stdin of an external command, see package exec.
+package target
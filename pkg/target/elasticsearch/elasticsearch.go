@@ -4,8 +4,10 @@ import (
 	"time"
 
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/secret"
 	"github.com/kyverno/policy-reporter/pkg/target"
 	"github.com/kyverno/policy-reporter/pkg/target/http"
+	"github.com/kyverno/policy-reporter/pkg/tracing"
 )
 
 // Options to configure elasticsearch target
@@ -13,7 +15,7 @@ type Options struct {
 	target.ClientOptions
 	Host         string
 	Username     string
-	Password     string
+	Password     secret.String
 	Index        string
 	Rotation     string
 	CustomFields map[string]string
@@ -74,12 +76,14 @@ func (e *client) Send(result v1alpha2.PolicyReportResult) {
 		return
 	}
 
+	tracing.Inject(req, result.TraceParent)
+
 	if e.username != "" {
 		req.SetBasicAuth(e.username, e.password)
 	}
 
 	resp, err := e.client.Do(req)
-	http.ProcessHTTPResponse(e.Name(), resp, err)
+	http.ProcessHTTPResponse(e.Name(), result.GetID(), resp, err)
 }
 
 // NewClient creates a new elasticsearch.client to send Results to Elasticsearch
@@ -89,7 +93,7 @@ func NewClient(options Options) target.Client {
 		options.Host,
 		options.Index,
 		options.Username,
-		options.Password,
+		options.Password.Value(),
 		options.Rotation,
 		options.CustomFields,
 		options.HTTPClient,
@@ -0,0 +1,39 @@
+package events
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kyverno/policy-reporter/pkg/logging"
+)
+
+var logger = logging.Component(logging.Targets)
+
+type dryRunRecorder struct {
+	target string
+}
+
+func (r *dryRunRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	ref, _ := object.(*corev1.ObjectReference)
+	if ref == nil {
+		ref = &corev1.ObjectReference{}
+	}
+
+	logger.Infof("%s DRY RUN : %s %s on %s %s/%s: %s", r.target, eventtype, reason, ref.Kind, ref.Namespace, ref.Name, message)
+}
+
+func (r *dryRunRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *dryRunRecorder) AnnotatedEventf(object runtime.Object, _ map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Eventf(object, eventtype, reason, messageFmt, args...)
+}
+
+// NewDryRunRecorder creates an EventRecorder which logs the events of the target instead of creating them
+func NewDryRunRecorder(target string) record.EventRecorder {
+	return &dryRunRecorder{target: target}
+}
@@ -0,0 +1,78 @@
+package events
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
+
+	"github.com/kyverno/policy-reporter/pkg/audit"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/target"
+)
+
+// Reason of the created Kubernetes Events
+const Reason = "PolicyViolation"
+
+// Options to configure the Kubernetes Events target
+type Options struct {
+	target.ClientOptions
+	// Recorder aggregates repeated events of the same resource, see record.CorrelatorOptions
+	Recorder record.EventRecorder
+	// RateLimiter drops events above the configured rate, no limit if nil
+	RateLimiter flowcontrol.RateLimiter
+	// OnNamespace emits the events on the namespace of the resource instead of the resource itself
+	OnNamespace bool
+}
+
+type client struct {
+	target.BaseClient
+	recorder    record.EventRecorder
+	limiter     flowcontrol.RateLimiter
+	onNamespace bool
+}
+
+func (e *client) Send(result v1alpha2.PolicyReportResult) {
+	if result.Result != v1alpha2.StatusFail && result.Result != v1alpha2.StatusError {
+		return
+	}
+
+	if !result.HasResource() {
+		return
+	}
+
+	res := result.GetResource()
+
+	ref := res.DeepCopy()
+	if e.onNamespace {
+		if res.Namespace == "" {
+			return
+		}
+
+		ref = &corev1.ObjectReference{APIVersion: "v1", Kind: "Namespace", Name: res.Namespace}
+	}
+
+	if e.limiter != nil && !e.limiter.TryAccept() {
+		logger.Warnf("%s : rate limit exceeded, skip event for %s/%s", e.Name(), res.Kind, res.Name)
+		return
+	}
+
+	message := fmt.Sprintf("policy %s/%s %s: %s", result.Policy, result.Rule, result.Result, result.Message)
+	if e.onNamespace {
+		message = fmt.Sprintf("%s %s %s", res.Kind, res.Name, message)
+	}
+
+	e.recorder.Event(ref, corev1.EventTypeWarning, Reason, message)
+	audit.Record(e.Name(), result.GetID(), 0, nil)
+}
+
+// NewClient creates a new events.client to emit Kubernetes Events for failed results
+func NewClient(options Options) target.Client {
+	return &client{
+		target.NewBaseClient(options.ClientOptions),
+		options.Recorder,
+		options.RateLimiter,
+		options.OnNamespace,
+	}
+}
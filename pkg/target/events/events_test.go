@@ -0,0 +1,99 @@
+package events_test
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/events"
+)
+
+func Test_EventsTarget(t *testing.T) {
+	t.Run("Send", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+
+		client := events.NewClient(events.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Events",
+			},
+			Recorder: recorder,
+		})
+		client.Send(fixtures.CompleteTargetSendResult)
+
+		event := <-recorder.Events
+		if !strings.HasPrefix(event, "Warning PolicyViolation policy require-requests-and-limits-required/autogen-check-for-requests-and-limits fail") {
+			t.Errorf("Unexpected event: %s", event)
+		}
+	})
+	t.Run("Skip passed results and results without resource", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+
+		client := events.NewClient(events.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Events",
+			},
+			Recorder: recorder,
+		})
+
+		result := fixtures.CompleteTargetSendResult
+		result.Result = v1alpha2.StatusPass
+
+		client.Send(result)
+		client.Send(fixtures.MinimalTargetSendResult)
+
+		if len(recorder.Events) != 0 {
+			t.Errorf("Expected no events, got %d", len(recorder.Events))
+		}
+	})
+	t.Run("OnNamespace", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+
+		client := events.NewClient(events.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Events",
+			},
+			Recorder:    recorder,
+			OnNamespace: true,
+		})
+		client.Send(fixtures.CompleteTargetSendResult)
+
+		event := <-recorder.Events
+		if !strings.HasPrefix(event, "Warning PolicyViolation Deployment nginx policy") {
+			t.Errorf("Unexpected event: %s", event)
+		}
+	})
+	t.Run("RateLimiter", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+
+		client := events.NewClient(events.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Events",
+			},
+			Recorder:    recorder,
+			RateLimiter: flowcontrol.NewTokenBucketRateLimiter(0.001, 1),
+		})
+		client.Send(fixtures.CompleteTargetSendResult)
+		client.Send(fixtures.CompleteTargetSendResult)
+
+		if len(recorder.Events) != 1 {
+			t.Errorf("Expected 1 event after the burst, got %d", len(recorder.Events))
+		}
+	})
+	t.Run("Name", func(t *testing.T) {
+		client := events.NewClient(events.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Events",
+			},
+			Recorder: record.NewFakeRecorder(1),
+		})
+
+		if client.Name() != "Events" {
+			t.Errorf("Unexpected Name %s", client.Name())
+		}
+	})
+}
@@ -0,0 +1,176 @@
+// Package exec sends results to an external process, so integrations can be added without changing Policy Reporter.
+//
+// The command is executed once per result. The result is written as JSON to its stdin, in the format
+// of the webhook target with an additional "properties" object containing the result properties and
+// the custom fields. The environment contains the environment of Policy Reporter, the configured
+// variables and POLICY_REPORTER_TARGET with the name of the target. A non zero exit code or
+// exceeding the timeout marks the delivery as failed, the output of the command is logged.
+package exec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/audit"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/http"
+)
+
+var logger = logging.Component(logging.Targets)
+
+// DefaultTimeout of a single execution
+const DefaultTimeout = 30 * time.Second
+
+// TargetEnv is the environment variable with the name of the target
+const TargetEnv = "POLICY_REPORTER_TARGET"
+
+// maxOutput of the command included in the log
+const maxOutput = 1024
+
+// Runner executes the command with the payload as stdin and returns its combined output
+type Runner interface {
+	Run(ctx context.Context, command string, args, env []string, stdin []byte) ([]byte, error)
+}
+
+type processRunner struct{}
+
+func (processRunner) Run(ctx context.Context, command string, args, env []string, stdin []byte) ([]byte, error) {
+	cmd := osexec.CommandContext(ctx, command, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = bytes.NewReader(stdin)
+
+	return cmd.CombinedOutput()
+}
+
+// NewProcessRunner creates a Runner which starts the command as child process
+func NewProcessRunner() Runner {
+	return processRunner{}
+}
+
+type dryRunRunner struct {
+	target string
+}
+
+func (r dryRunRunner) Run(_ context.Context, command string, _, _ []string, stdin []byte) ([]byte, error) {
+	logger.Infof("%s DRY RUN : %s payload: %s", r.target, command, bytes.TrimSpace(stdin))
+
+	return nil, nil
+}
+
+// NewDryRunRunner creates a Runner which logs the command and the payload of the target instead of executing it
+func NewDryRunRunner(target string) Runner {
+	return dryRunRunner{target: target}
+}
+
+// Options to configure the exec target
+type Options struct {
+	target.ClientOptions
+	Command string
+	Args    []string
+	// Env variables in addition to the environment of Policy Reporter
+	Env          map[string]string
+	CustomFields map[string]string
+	// Timeout of a single execution, defaults to DefaultTimeout
+	Timeout time.Duration
+	// Runner defaults to a process runner
+	Runner Runner
+}
+
+type client struct {
+	target.BaseClient
+	command      string
+	args         []string
+	env          []string
+	customFields map[string]string
+	timeout      time.Duration
+	runner       Runner
+}
+
+func (c *client) Send(result v1alpha2.PolicyReportResult) {
+	if len(c.customFields) > 0 {
+		props := make(map[string]string, len(c.customFields)+len(result.Properties))
+		for property, value := range c.customFields {
+			props[property] = value
+		}
+		for property, value := range result.Properties {
+			props[property] = value
+		}
+
+		result.Properties = props
+	}
+
+	res := http.NewJSONResult(result)
+	if len(result.Properties) > 0 {
+		if res.Fields == nil {
+			res.Fields = make(map[string]interface{}, 1)
+		}
+		res.Fields["properties"] = result.Properties
+	}
+
+	payload, err := json.Marshal(res)
+	if err != nil {
+		c.failed(result, err, nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	output, err := c.runner.Run(ctx, c.command, c.args, c.env, payload)
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timeout after %s", c.timeout)
+	}
+	if err != nil {
+		c.failed(result, err, output)
+		return
+	}
+
+	logger.Infof("%s PUSH OK", c.Name())
+	audit.Delivery(c.Name(), result.GetID(), 0, nil)
+}
+
+func (c *client) failed(result v1alpha2.PolicyReportResult, err error, output []byte) {
+	output = bytes.TrimSpace(output)
+	if len(output) > maxOutput {
+		output = output[:maxOutput]
+	}
+
+	logger.Errorf("%s PUSH failed: %s: %s", c.Name(), err, output)
+	audit.Delivery(c.Name(), result.GetID(), 0, err)
+}
+
+// NewClient creates a new exec.client to send Results to an external process
+func NewClient(options Options) target.Client {
+	env := make([]string, 0, len(options.Env)+1)
+	for name, value := range options.Env {
+		env = append(env, name+"="+value)
+	}
+	env = append(env, TargetEnv+"="+options.Name)
+
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	runner := options.Runner
+	if runner == nil {
+		runner = NewProcessRunner()
+	}
+
+	return &client{
+		target.NewBaseClient(options.ClientOptions),
+		options.Command,
+		options.Args,
+		env,
+		options.CustomFields,
+		timeout,
+		runner,
+	}
+}
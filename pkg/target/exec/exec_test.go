@@ -0,0 +1,96 @@
+package exec_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/exec"
+)
+
+type testRunner struct {
+	command string
+	args    []string
+	env     []string
+	stdin   []byte
+	err     error
+}
+
+func (r *testRunner) Run(_ context.Context, command string, args, env []string, stdin []byte) ([]byte, error) {
+	r.command, r.args, r.env, r.stdin = command, args, env, stdin
+
+	return []byte("output"), r.err
+}
+
+func Test_ExecTarget(t *testing.T) {
+	t.Run("Send", func(t *testing.T) {
+		runner := &testRunner{}
+		client := exec.NewClient(exec.Options{
+			ClientOptions: target.ClientOptions{Name: "Exec"},
+			Command:       "/plugins/notify",
+			Args:          []string{"--channel", "security"},
+			Env:           map[string]string{"TOKEN": "secret"},
+			CustomFields:  map[string]string{"cluster": "prod"},
+			Runner:        runner,
+		})
+
+		client.Send(fixtures.CompleteTargetSendResult)
+
+		if runner.command != "/plugins/notify" || len(runner.args) != 2 {
+			t.Errorf("unexpected command %s %v", runner.command, runner.args)
+		}
+		if strings.Join(runner.env, ",") != "TOKEN=secret,"+exec.TargetEnv+"=Exec" {
+			t.Errorf("unexpected env %v", runner.env)
+		}
+
+		result := make(map[string]interface{})
+		if err := json.Unmarshal(runner.stdin, &result); err != nil {
+			t.Fatal(err)
+		}
+		if result["policy"] != fixtures.CompleteTargetSendResult.Policy || result["properties"].(map[string]interface{})["cluster"] != "prod" {
+			t.Errorf("unexpected payload %s", runner.stdin)
+		}
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		runner := &testRunner{err: errors.New("exit status 1")}
+		client := exec.NewClient(exec.Options{ClientOptions: target.ClientOptions{Name: "Exec"}, Command: "/plugins/notify", Runner: runner})
+
+		client.Send(fixtures.CompleteTargetSendResult)
+
+		if runner.command != "/plugins/notify" {
+			t.Error("expected the command to be executed")
+		}
+	})
+}
+
+func Test_ProcessRunner(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "payload")
+
+	output, err := exec.NewProcessRunner().Run(context.Background(), "sh", []string{"-c", `cat > "$OUT"; echo "$NAME"`}, []string{"OUT=" + out, "NAME=plugin"}, []byte(`{"policy":"test"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if strings.TrimSpace(string(output)) != "plugin" {
+		t.Errorf("expected the configured env, got %s", output)
+	}
+
+	content, _ := os.ReadFile(out)
+	if string(content) != `{"policy":"test"}` {
+		t.Errorf("expected the payload on stdin, got %s", content)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := exec.NewProcessRunner().Run(ctx, "sleep", []string{"1"}, nil, nil); err == nil {
+		t.Error("expected the process to be killed after the timeout")
+	}
+}
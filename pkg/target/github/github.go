@@ -0,0 +1,189 @@
+package github
+
+import (
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+
+	"github.com/kyverno/policy-reporter/pkg/audit"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/secret"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/http"
+	"github.com/kyverno/policy-reporter/pkg/target/issues"
+)
+
+var logger = logging.Component(logging.Targets)
+
+// Options to configure the GitHub target
+type Options struct {
+	target.ClientOptions
+	// Host of the GitHub API, e.g. https://api.github.com or https://github.example.com/api/v3
+	Host        string
+	Token       secret.String
+	Labels      []string
+	Repository  *issues.RepositoryResolver
+	Remediation *target.RemediationTemplate
+	HTTPClient  http.Client
+}
+
+type issue struct {
+	Title  string   `json:"title,omitempty"`
+	Body   string   `json:"body,omitempty"`
+	Labels []string `json:"labels,omitempty"`
+	State  string   `json:"state,omitempty"`
+	Reason string   `json:"state_reason,omitempty"`
+	Number int      `json:"number,omitempty"`
+}
+
+type searchResult struct {
+	Items []issue `json:"items"`
+}
+
+type client struct {
+	target.BaseClient
+	host        string
+	token       string
+	labels      []string
+	repository  *issues.RepositoryResolver
+	remediation *target.RemediationTemplate
+	issues      *issues.Tracker
+	client      http.Client
+}
+
+// Send opens an issue for the result, if no open issue of the result exists
+func (c *client) Send(result v1alpha2.PolicyReportResult) {
+	repository := c.repository.Resolve(result)
+	if repository == "" {
+		logger.Warnf("%s : no repository for result %s of policy %s", c.Name(), result.GetID(), result.Policy)
+		return
+	}
+
+	if _, ok := c.issues.Get(result.GetID()); ok {
+		return
+	}
+
+	existing, err := c.find(repository, result.GetID())
+	if err != nil {
+		c.failed(result, err)
+		return
+	}
+	if existing != nil {
+		c.issues.Add(result.GetID(), *existing)
+		return
+	}
+
+	req, err := c.request("POST", fmt.Sprintf("%s/repos/%s/issues", c.host, repository), issue{
+		Title:  issues.Title(result),
+		Body:   issues.Body(result, c.remediation.Render(result)),
+		Labels: issues.Labels(c.labels, result),
+	})
+	if err != nil {
+		return
+	}
+
+	created := issue{}
+	if err := issues.Do(c.client, req, &created); err != nil {
+		c.failed(result, err)
+		return
+	}
+
+	c.issues.Add(result.GetID(), issues.Issue{Repository: repository, Number: created.Number})
+	c.succeeded(result)
+}
+
+// Resolve closes the open issue of the result
+func (c *client) Resolve(result v1alpha2.PolicyReportResult) {
+	existing, ok := c.issues.Get(result.GetID())
+	if !ok {
+		repository := c.repository.Resolve(result)
+		if repository == "" {
+			return
+		}
+
+		found, err := c.find(repository, result.GetID())
+		if err != nil {
+			c.failed(result, err)
+			return
+		}
+		if found == nil {
+			return
+		}
+
+		existing = *found
+	}
+
+	req, err := c.request("PATCH", fmt.Sprintf("%s/repos/%s/issues/%d", c.host, existing.Repository, existing.Number), issue{
+		State:  "closed",
+		Reason: "completed",
+	})
+	if err != nil {
+		return
+	}
+
+	if err := issues.Do(c.client, req, nil); err != nil {
+		c.failed(result, err)
+		return
+	}
+
+	c.issues.Remove(result.GetID())
+	c.succeeded(result)
+}
+
+func (c *client) find(repository, id string) (*issues.Issue, error) {
+	query := fmt.Sprintf(`"%s" in:body repo:%s is:issue is:open`, id, repository)
+
+	req, err := c.request("GET", fmt.Sprintf("%s/search/issues?q=%s", c.host, url.QueryEscape(query)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := searchResult{}
+	if err := issues.Do(c.client, req, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Items) == 0 {
+		return nil, nil
+	}
+
+	return &issues.Issue{Repository: repository, Number: result.Items[0].Number}, nil
+}
+
+func (c *client) request(method, endpoint string, payload interface{}) (*nethttp.Request, error) {
+	req, err := issues.NewRequest(c.Name(), method, endpoint, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	return req, nil
+}
+
+func (c *client) failed(result v1alpha2.PolicyReportResult, err error) {
+	logger.Errorf("%s PUSH failed: %s", c.Name(), err.Error())
+	audit.Delivery(c.Name(), result.GetID(), 0, err)
+}
+
+func (c *client) succeeded(result v1alpha2.PolicyReportResult) {
+	logger.Infof("%s PUSH OK", c.Name())
+	audit.Delivery(c.Name(), result.GetID(), 0, nil)
+}
+
+// NewClient creates a new GitHub.client to open issues for violations
+func NewClient(options Options) target.ResolvingClient {
+	return &client{
+		target.NewBaseClient(options.ClientOptions),
+		options.Host,
+		options.Token.Value(),
+		options.Labels,
+		options.Repository,
+		options.Remediation,
+		issues.NewTracker(),
+		options.HTTPClient,
+	}
+}
@@ -0,0 +1,121 @@
+package github_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/github"
+	"github.com/kyverno/policy-reporter/pkg/target/issues"
+)
+
+type testClient struct {
+	requests  []*http.Request
+	payloads  []map[string]interface{}
+	responses map[string]string
+}
+
+func (c *testClient) Do(req *http.Request) (*http.Response, error) {
+	payload := map[string]interface{}{}
+	if req.Body != nil {
+		json.NewDecoder(req.Body).Decode(&payload)
+	}
+
+	c.requests = append(c.requests, req)
+	c.payloads = append(c.payloads, payload)
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(c.responses[req.Method])),
+	}, nil
+}
+
+func newClient(http *testClient) target.ResolvingClient {
+	return github.NewClient(github.Options{
+		ClientOptions: target.ClientOptions{
+			Name: "GitHub",
+		},
+		Host:       "https://api.github.com",
+		Token:      "token",
+		Labels:     []string{"policy-reporter"},
+		Repository: issues.NewRepositoryResolver(nil, issues.RepositoryAnnotation, "kyverno/policy-reporter"),
+		HTTPClient: http,
+	})
+}
+
+func Test_GitHubTarget(t *testing.T) {
+	t.Run("Send", func(t *testing.T) {
+		http := &testClient{responses: map[string]string{"GET": `{"items":[]}`, "POST": `{"number":42}`}}
+		client := newClient(http)
+
+		client.Send(fixtures.CompleteTargetSendResult)
+
+		if len(http.requests) != 2 {
+			t.Fatalf("expected search and create requests, got %d", len(http.requests))
+		}
+
+		search := http.requests[0]
+		if q := search.URL.Query().Get("q"); !strings.Contains(q, "repo:kyverno/policy-reporter") || !strings.Contains(q, fixtures.CompleteTargetSendResult.GetID()) {
+			t.Errorf("unexpected search query: %s", q)
+		}
+		if value := search.Header.Get("Authorization"); value != "Bearer token" {
+			t.Errorf("unexpected Authorization Header: %s", value)
+		}
+
+		create := http.requests[1]
+		if url := create.URL.String(); url != "https://api.github.com/repos/kyverno/policy-reporter/issues" {
+			t.Errorf("unexpected URL: %s", url)
+		}
+		if body := http.payloads[1]["body"].(string); !strings.Contains(body, issues.Marker(fixtures.CompleteTargetSendResult.GetID())) {
+			t.Errorf("expected result ID in body: %s", body)
+		}
+		if labels := http.payloads[1]["labels"].([]interface{}); len(labels) != 3 || labels[1] != "severity:high" || labels[2] != "category:resources" {
+			t.Errorf("unexpected labels: %v", labels)
+		}
+
+		client.Send(fixtures.CompleteTargetSendResult)
+		if len(http.requests) != 2 {
+			t.Error("expected no requests for an already opened issue")
+		}
+
+		client.Resolve(fixtures.CompleteTargetSendResult)
+		if len(http.requests) != 3 {
+			t.Fatalf("expected close request, got %d requests", len(http.requests))
+		}
+
+		closing := http.requests[2]
+		if url := closing.URL.String(); closing.Method != "PATCH" || url != "https://api.github.com/repos/kyverno/policy-reporter/issues/42" {
+			t.Errorf("unexpected close request: %s %s", closing.Method, url)
+		}
+		if http.payloads[2]["state"] != "closed" {
+			t.Errorf("unexpected state: %v", http.payloads[2]["state"])
+		}
+	})
+	t.Run("Skip existing issue", func(t *testing.T) {
+		http := &testClient{responses: map[string]string{"GET": `{"items":[{"number":7}]}`}}
+		client := newClient(http)
+
+		client.Send(fixtures.CompleteTargetSendResult)
+		if len(http.requests) != 1 {
+			t.Errorf("expected only the search request, got %d", len(http.requests))
+		}
+
+		client.Resolve(fixtures.CompleteTargetSendResult)
+		if url := http.requests[1].URL.String(); url != "https://api.github.com/repos/kyverno/policy-reporter/issues/7" {
+			t.Errorf("unexpected URL: %s", url)
+		}
+	})
+	t.Run("Resolve without open issue", func(t *testing.T) {
+		http := &testClient{responses: map[string]string{"GET": `{"items":[]}`}}
+		client := newClient(http)
+
+		client.Resolve(fixtures.CompleteTargetSendResult)
+		if len(http.requests) != 1 || http.requests[0].Method != "GET" {
+			t.Errorf("expected only the search request, got %d", len(http.requests))
+		}
+	})
+}
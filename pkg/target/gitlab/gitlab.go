@@ -0,0 +1,186 @@
+package gitlab
+
+import (
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+	"strings"
+
+	"github.com/kyverno/policy-reporter/pkg/audit"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/secret"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/http"
+	"github.com/kyverno/policy-reporter/pkg/target/issues"
+)
+
+var logger = logging.Component(logging.Targets)
+
+// Options to configure the GitLab target
+type Options struct {
+	target.ClientOptions
+	// Host of the GitLab instance, e.g. https://gitlab.com
+	Host        string
+	Token       secret.String
+	Labels      []string
+	Repository  *issues.RepositoryResolver
+	Remediation *target.RemediationTemplate
+	HTTPClient  http.Client
+}
+
+type issue struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Labels      string `json:"labels,omitempty"`
+	StateEvent  string `json:"state_event,omitempty"`
+	IID         int    `json:"iid,omitempty"`
+}
+
+type client struct {
+	target.BaseClient
+	host        string
+	token       string
+	labels      []string
+	repository  *issues.RepositoryResolver
+	remediation *target.RemediationTemplate
+	issues      *issues.Tracker
+	client      http.Client
+}
+
+// Send opens an issue for the result, if no open issue of the result exists
+func (c *client) Send(result v1alpha2.PolicyReportResult) {
+	repository := c.repository.Resolve(result)
+	if repository == "" {
+		logger.Warnf("%s : no repository for result %s of policy %s", c.Name(), result.GetID(), result.Policy)
+		return
+	}
+
+	if _, ok := c.issues.Get(result.GetID()); ok {
+		return
+	}
+
+	existing, err := c.find(repository, result.GetID())
+	if err != nil {
+		c.failed(result, err)
+		return
+	}
+	if existing != nil {
+		c.issues.Add(result.GetID(), *existing)
+		return
+	}
+
+	req, err := c.request("POST", c.issuesURL(repository), issue{
+		Title:       issues.Title(result),
+		Description: issues.Body(result, c.remediation.Render(result)),
+		Labels:      strings.Join(issues.Labels(c.labels, result), ","),
+	})
+	if err != nil {
+		return
+	}
+
+	created := issue{}
+	if err := issues.Do(c.client, req, &created); err != nil {
+		c.failed(result, err)
+		return
+	}
+
+	c.issues.Add(result.GetID(), issues.Issue{Repository: repository, Number: created.IID})
+	c.succeeded(result)
+}
+
+// Resolve closes the open issue of the result
+func (c *client) Resolve(result v1alpha2.PolicyReportResult) {
+	existing, ok := c.issues.Get(result.GetID())
+	if !ok {
+		repository := c.repository.Resolve(result)
+		if repository == "" {
+			return
+		}
+
+		found, err := c.find(repository, result.GetID())
+		if err != nil {
+			c.failed(result, err)
+			return
+		}
+		if found == nil {
+			return
+		}
+
+		existing = *found
+	}
+
+	req, err := c.request("PUT", fmt.Sprintf("%s/%d", c.issuesURL(existing.Repository), existing.Number), issue{
+		StateEvent: "close",
+	})
+	if err != nil {
+		return
+	}
+
+	if err := issues.Do(c.client, req, nil); err != nil {
+		c.failed(result, err)
+		return
+	}
+
+	c.issues.Remove(result.GetID())
+	c.succeeded(result)
+}
+
+func (c *client) find(repository, id string) (*issues.Issue, error) {
+	req, err := c.request("GET", fmt.Sprintf("%s?state=opened&in=description&search=%s", c.issuesURL(repository), url.QueryEscape(id)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]issue, 0)
+	if err := issues.Do(c.client, req, &result); err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+
+	return &issues.Issue{Repository: repository, Number: result[0].IID}, nil
+}
+
+// issuesURL of the project, the repository is the project path like group/project
+func (c *client) issuesURL(repository string) string {
+	return fmt.Sprintf("%s/api/v4/projects/%s/issues", c.host, url.PathEscape(repository))
+}
+
+func (c *client) request(method, endpoint string, payload interface{}) (*nethttp.Request, error) {
+	req, err := issues.NewRequest(c.Name(), method, endpoint, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+
+	return req, nil
+}
+
+func (c *client) failed(result v1alpha2.PolicyReportResult, err error) {
+	logger.Errorf("%s PUSH failed: %s", c.Name(), err.Error())
+	audit.Delivery(c.Name(), result.GetID(), 0, err)
+}
+
+func (c *client) succeeded(result v1alpha2.PolicyReportResult) {
+	logger.Infof("%s PUSH OK", c.Name())
+	audit.Delivery(c.Name(), result.GetID(), 0, nil)
+}
+
+// NewClient creates a new GitLab.client to open issues for violations
+func NewClient(options Options) target.ResolvingClient {
+	return &client{
+		target.NewBaseClient(options.ClientOptions),
+		options.Host,
+		options.Token.Value(),
+		options.Labels,
+		options.Repository,
+		options.Remediation,
+		issues.NewTracker(),
+		options.HTTPClient,
+	}
+}
@@ -0,0 +1,124 @@
+package gitlab_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/gitlab"
+	"github.com/kyverno/policy-reporter/pkg/target/issues"
+)
+
+type testClient struct {
+	requests  []*http.Request
+	payloads  []map[string]interface{}
+	responses map[string]string
+}
+
+func (c *testClient) Do(req *http.Request) (*http.Response, error) {
+	payload := map[string]interface{}{}
+	if req.Body != nil {
+		json.NewDecoder(req.Body).Decode(&payload)
+	}
+
+	c.requests = append(c.requests, req)
+	c.payloads = append(c.payloads, payload)
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(c.responses[req.Method])),
+	}, nil
+}
+
+func newClient(http *testClient) target.ResolvingClient {
+	return gitlab.NewClient(gitlab.Options{
+		ClientOptions: target.ClientOptions{
+			Name: "GitLab",
+		},
+		Host:       "https://gitlab.com",
+		Token:      "token",
+		Labels:     []string{"policy-reporter"},
+		Repository: issues.NewRepositoryResolver(nil, issues.RepositoryAnnotation, "kyverno/policy-reporter"),
+		HTTPClient: http,
+	})
+}
+
+func Test_GitLabTarget(t *testing.T) {
+	t.Run("Send", func(t *testing.T) {
+		http := &testClient{responses: map[string]string{"GET": `[]`, "POST": `{"iid":42}`}}
+		client := newClient(http)
+
+		client.Send(fixtures.CompleteTargetSendResult)
+
+		if len(http.requests) != 2 {
+			t.Fatalf("expected search and create requests, got %d", len(http.requests))
+		}
+
+		search := http.requests[0]
+		if url := search.URL.String(); !strings.HasPrefix(url, "https://gitlab.com/api/v4/projects/kyverno%2Fpolicy-reporter/issues?state=opened") {
+			t.Errorf("unexpected search URL: %s", url)
+		}
+		if q := search.URL.Query().Get("search"); q != fixtures.CompleteTargetSendResult.GetID() {
+			t.Errorf("unexpected search: %s", q)
+		}
+		if value := search.Header.Get("PRIVATE-TOKEN"); value != "token" {
+			t.Errorf("unexpected PRIVATE-TOKEN Header: %s", value)
+		}
+
+		create := http.requests[1]
+		if url := create.URL.String(); url != "https://gitlab.com/api/v4/projects/kyverno%2Fpolicy-reporter/issues" {
+			t.Errorf("unexpected URL: %s", url)
+		}
+		if body := http.payloads[1]["description"].(string); !strings.Contains(body, issues.Marker(fixtures.CompleteTargetSendResult.GetID())) {
+			t.Errorf("expected result ID in body: %s", body)
+		}
+		if labels := http.payloads[1]["labels"]; labels != "policy-reporter,severity:high,category:resources" {
+			t.Errorf("unexpected labels: %v", labels)
+		}
+
+		client.Send(fixtures.CompleteTargetSendResult)
+		if len(http.requests) != 2 {
+			t.Error("expected no requests for an already opened issue")
+		}
+
+		client.Resolve(fixtures.CompleteTargetSendResult)
+		if len(http.requests) != 3 {
+			t.Fatalf("expected close request, got %d requests", len(http.requests))
+		}
+
+		closing := http.requests[2]
+		if url := closing.URL.String(); closing.Method != "PUT" || url != "https://gitlab.com/api/v4/projects/kyverno%2Fpolicy-reporter/issues/42" {
+			t.Errorf("unexpected close request: %s %s", closing.Method, url)
+		}
+		if http.payloads[2]["state_event"] != "close" {
+			t.Errorf("unexpected state_event: %v", http.payloads[2]["state_event"])
+		}
+	})
+	t.Run("Skip existing issue", func(t *testing.T) {
+		http := &testClient{responses: map[string]string{"GET": `[{"iid":7}]`}}
+		client := newClient(http)
+
+		client.Send(fixtures.CompleteTargetSendResult)
+		if len(http.requests) != 1 {
+			t.Errorf("expected only the search request, got %d", len(http.requests))
+		}
+
+		client.Resolve(fixtures.CompleteTargetSendResult)
+		if url := http.requests[1].URL.String(); url != "https://gitlab.com/api/v4/projects/kyverno%2Fpolicy-reporter/issues/7" {
+			t.Errorf("unexpected URL: %s", url)
+		}
+	})
+	t.Run("Resolve without open issue", func(t *testing.T) {
+		http := &testClient{responses: map[string]string{"GET": `[]`}}
+		client := newClient(http)
+
+		client.Resolve(fixtures.CompleteTargetSendResult)
+		if len(http.requests) != 1 || http.requests[0].Method != "GET" {
+			t.Errorf("expected only the search request, got %d", len(http.requests))
+		}
+	})
+}
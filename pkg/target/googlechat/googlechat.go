@@ -0,0 +1,236 @@
+package googlechat
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/secret"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/http"
+	"github.com/kyverno/policy-reporter/pkg/timeformat"
+	"github.com/kyverno/policy-reporter/pkg/tracing"
+)
+
+// Options to configure the Google Chat target
+type Options struct {
+	target.ClientOptions
+	Webhook      secret.String
+	CustomFields map[string]string
+	Remediation  *target.RemediationTemplate
+	HTTPClient   http.Client
+}
+
+type payload struct {
+	Text    string   `json:"text"`
+	CardsV2 []cardV2 `json:"cardsV2"`
+	Thread  *thread  `json:"thread,omitempty"`
+}
+
+type thread struct {
+	ThreadKey string `json:"threadKey"`
+}
+
+type cardV2 struct {
+	CardID string `json:"cardId"`
+	Card   card   `json:"card"`
+}
+
+type card struct {
+	Header   header    `json:"header"`
+	Sections []section `json:"sections"`
+}
+
+type header struct {
+	Title    string `json:"title"`
+	Subtitle string `json:"subtitle,omitempty"`
+}
+
+type section struct {
+	Header                    string   `json:"header,omitempty"`
+	Collapsible               bool     `json:"collapsible,omitempty"`
+	UncollapsibleWidgetsCount int      `json:"uncollapsibleWidgetsCount,omitempty"`
+	Widgets                   []widget `json:"widgets"`
+}
+
+type widget struct {
+	DecoratedText *decoratedText `json:"decoratedText,omitempty"`
+	TextParagraph *textParagraph `json:"textParagraph,omitempty"`
+	ButtonList    *buttonList    `json:"buttonList,omitempty"`
+}
+
+type decoratedText struct {
+	TopLabel string `json:"topLabel"`
+	Text     string `json:"text"`
+	WrapText bool   `json:"wrapText"`
+}
+
+type textParagraph struct {
+	Text string `json:"text"`
+}
+
+type buttonList struct {
+	Buttons []button `json:"buttons"`
+}
+
+type button struct {
+	Text    string  `json:"text"`
+	OnClick onClick `json:"onClick"`
+}
+
+type onClick struct {
+	OpenLink openLink `json:"openLink"`
+}
+
+type openLink struct {
+	URL string `json:"url"`
+}
+
+func field(label, value string) widget {
+	return widget{DecoratedText: &decoratedText{TopLabel: label, Text: value, WrapText: true}}
+}
+
+func sortedFields(values map[string]string, skip func(string) bool) []widget {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if skip(key) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	widgets := make([]widget, 0, len(keys))
+	for _, key := range keys {
+		widgets = append(widgets, field(strings.Title(key), values[key]))
+	}
+
+	return widgets
+}
+
+// ThreadKey groups the messages of a policy and namespace in one thread of the space
+func ThreadKey(result v1alpha2.PolicyReportResult) string {
+	key := result.Policy
+	if res := result.GetResource(); res != nil && res.Namespace != "" {
+		key += "/" + res.Namespace
+	}
+
+	return key
+}
+
+func newPayload(result v1alpha2.PolicyReportResult, customFields map[string]string, remediation string) payload {
+	sections := make([]section, 0, 5)
+
+	policy := []widget{field("Policy", result.Policy)}
+	if result.Rule != "" {
+		policy = append(policy, field("Rule", result.Rule))
+	}
+	policy = append(policy, field("Priority", result.Priority.String()))
+	if result.Category != "" {
+		policy = append(policy, field("Category", result.Category))
+	}
+	if result.Severity != "" {
+		policy = append(policy, field("Severity", string(result.Severity)))
+	}
+	if result.Timestamp.Seconds > 0 {
+		policy = append(policy, field("Time", timeformat.FormatResult(result.Timestamp)))
+	}
+	sections = append(sections, section{Header: "Policy", Widgets: policy})
+
+	if result.HasResource() {
+		res := result.GetResource()
+
+		resource := []widget{field("Kind", res.Kind), field("Name", res.Name)}
+		if res.Namespace != "" {
+			resource = append(resource, field("Namespace", res.Namespace))
+		}
+		if res.APIVersion != "" {
+			resource = append(resource, field("API Version", res.APIVersion))
+		}
+		sections = append(sections, section{Header: "Resource", Widgets: resource})
+	}
+
+	if description := result.Properties[v1alpha2.PolicyDescriptionKey]; description != "" {
+		sections = append(sections, section{Header: "Description", Widgets: []widget{{TextParagraph: &textParagraph{Text: description}}}})
+	}
+	if remediation != "" {
+		sections = append(sections, section{Header: "How to fix", Widgets: []widget{{TextParagraph: &textParagraph{Text: remediation}}}})
+	}
+
+	properties := sortedFields(result.Properties, v1alpha2.IsPolicyGuidanceKey)
+	properties = append(properties, sortedFields(customFields, func(string) bool { return false })...)
+	if len(properties) > 0 {
+		sections = append(sections, section{Header: "Properties", Collapsible: len(properties) > 3, UncollapsibleWidgetsCount: 3, Widgets: properties})
+	}
+
+	buttons := make([]button, 0, 2)
+	if url := result.Properties[v1alpha2.PolicyURLKey]; url != "" {
+		buttons = append(buttons, button{Text: "Policy Documentation", OnClick: onClick{openLink{url}}})
+	}
+	if url := result.Properties[v1alpha2.ResultURLKey]; url != "" {
+		buttons = append(buttons, button{Text: "View in Policy Reporter", OnClick: onClick{openLink{url}}})
+	}
+	if len(buttons) > 0 {
+		sections = append(sections, section{Widgets: []widget{{ButtonList: &buttonList{Buttons: buttons}}}})
+	}
+
+	return payload{
+		Text: result.Message,
+		CardsV2: []cardV2{{
+			CardID: result.GetID(),
+			Card: card{
+				Header:   header{Title: "New Policy Report Result", Subtitle: "Policy Reporter"},
+				Sections: sections,
+			},
+		}},
+		Thread: &thread{ThreadKey: ThreadKey(result)},
+	}
+}
+
+// threadedWebhook replies to the thread of the threadKey and starts a new thread for unknown keys
+func threadedWebhook(webhook string) string {
+	u, err := url.Parse(webhook)
+	if err != nil {
+		return webhook
+	}
+
+	query := u.Query()
+	query.Set("messageReplyOption", "REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD")
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+type client struct {
+	target.BaseClient
+	webhook      string
+	customFields map[string]string
+	remediation  *target.RemediationTemplate
+	client       http.Client
+}
+
+func (g *client) Send(result v1alpha2.PolicyReportResult) {
+	req, err := http.CreateJSONRequest(g.Name(), "POST", g.webhook, newPayload(result, g.customFields, g.remediation.Render(result)))
+	if err != nil {
+		return
+	}
+
+	tracing.Inject(req, result.TraceParent)
+
+	resp, err := g.client.Do(req)
+	http.ProcessHTTPResponse(g.Name(), result.GetID(), resp, err)
+}
+
+// NewClient creates a new googlechat.client to send Results to a Google Chat space
+func NewClient(options Options) target.Client {
+	webhook := secret.New(threadedWebhook(options.Webhook.Value()))
+
+	return &client{
+		target.NewBaseClient(options.ClientOptions),
+		webhook.Value(),
+		options.CustomFields,
+		options.Remediation,
+		options.HTTPClient,
+	}
+}
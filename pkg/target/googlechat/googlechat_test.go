@@ -0,0 +1,170 @@
+package googlechat_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/googlechat"
+)
+
+type testClient struct {
+	callback   func(req *http.Request)
+	statusCode int
+}
+
+func (c testClient) Do(req *http.Request) (*http.Response, error) {
+	c.callback(req)
+
+	return &http.Response{
+		StatusCode: c.statusCode,
+	}, nil
+}
+
+type payload struct {
+	Text    string `json:"text"`
+	CardsV2 []struct {
+		CardID string `json:"cardId"`
+		Card   struct {
+			Sections []struct {
+				Header  string `json:"header"`
+				Widgets []struct {
+					DecoratedText *struct {
+						TopLabel string `json:"topLabel"`
+						Text     string `json:"text"`
+					} `json:"decoratedText"`
+					TextParagraph *struct {
+						Text string `json:"text"`
+					} `json:"textParagraph"`
+				} `json:"widgets"`
+			} `json:"sections"`
+		} `json:"card"`
+	} `json:"cardsV2"`
+	Thread struct {
+		ThreadKey string `json:"threadKey"`
+	} `json:"thread"`
+}
+
+func Test_GoogleChatTarget(t *testing.T) {
+	t.Run("Send Complete Result", func(t *testing.T) {
+		callback := func(req *http.Request) {
+			if contentType := req.Header.Get("Content-Type"); contentType != "application/json; charset=utf-8" {
+				t.Errorf("Unexpected Content-Type: %s", contentType)
+			}
+
+			if url := req.URL.String(); url != "https://chat.googleapis.com/v1/spaces/AAA/messages?key=abc&messageReplyOption=REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD&token=xyz" {
+				t.Errorf("Unexpected Host: %s", url)
+			}
+
+			p := payload{}
+			if err := json.NewDecoder(req.Body).Decode(&p); err != nil {
+				t.Fatal(err)
+			}
+
+			if p.Text != fixtures.CompleteTargetSendResult.Message {
+				t.Errorf("Unexpected text: %s", p.Text)
+			}
+			if p.Thread.ThreadKey != "require-requests-and-limits-required/default" {
+				t.Errorf("Unexpected thread key: %s", p.Thread.ThreadKey)
+			}
+			if len(p.CardsV2) != 1 {
+				t.Fatalf("Expected one card, got %d", len(p.CardsV2))
+			}
+
+			headers := make([]string, 0)
+			for _, s := range p.CardsV2[0].Card.Sections {
+				headers = append(headers, s.Header)
+			}
+			if len(headers) != 3 || headers[0] != "Policy" || headers[1] != "Resource" || headers[2] != "Properties" {
+				t.Errorf("Unexpected sections: %v", headers)
+			}
+
+			resource := p.CardsV2[0].Card.Sections[1].Widgets
+			if resource[0].DecoratedText.TopLabel != "Kind" || resource[0].DecoratedText.Text != "Deployment" {
+				t.Errorf("Unexpected resource field: %+v", resource[0].DecoratedText)
+			}
+		}
+
+		client := googlechat.NewClient(googlechat.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Google Chat",
+			},
+			Webhook:    "https://chat.googleapis.com/v1/spaces/AAA/messages?key=abc&token=xyz",
+			HTTPClient: testClient{callback, 200},
+		})
+		client.Send(fixtures.CompleteTargetSendResult)
+	})
+
+	t.Run("Send Minimal Result", func(t *testing.T) {
+		callback := func(req *http.Request) {
+			p := payload{}
+			if err := json.NewDecoder(req.Body).Decode(&p); err != nil {
+				t.Fatal(err)
+			}
+
+			if p.Thread.ThreadKey != "app-label-requirement" {
+				t.Errorf("Unexpected thread key: %s", p.Thread.ThreadKey)
+			}
+			if sections := p.CardsV2[0].Card.Sections; len(sections) != 1 {
+				t.Errorf("Expected only the policy section, got %d", len(sections))
+			}
+		}
+
+		client := googlechat.NewClient(googlechat.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Google Chat",
+			},
+			Webhook:    "https://chat.googleapis.com/v1/spaces/AAA/messages",
+			HTTPClient: testClient{callback, 200},
+		})
+		client.Send(fixtures.MinimalTargetSendResult)
+	})
+
+	t.Run("Remediation", func(t *testing.T) {
+		result := fixtures.CompleteTargetSendResult
+		result.Properties = map[string]string{v1alpha2.RemediationKey: "set resource limits"}
+
+		callback := func(req *http.Request) {
+			p := payload{}
+			if err := json.NewDecoder(req.Body).Decode(&p); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, s := range p.CardsV2[0].Card.Sections {
+				if s.Header == "How to fix" && s.Widgets[0].TextParagraph.Text == "fix: set resource limits" {
+					return
+				}
+			}
+			t.Error("Expected remediation section")
+		}
+
+		tmpl, _ := target.ParseRemediationTemplate("fix: {{ .Remediation }}")
+
+		client := googlechat.NewClient(googlechat.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Google Chat",
+			},
+			Webhook:     "https://chat.googleapis.com/v1/spaces/AAA/messages",
+			Remediation: tmpl,
+			HTTPClient:  testClient{callback, 200},
+		})
+		client.Send(result)
+	})
+
+	t.Run("Name", func(t *testing.T) {
+		client := googlechat.NewClient(googlechat.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Google Chat",
+			},
+			Webhook:    "https://chat.googleapis.com/v1/spaces/AAA/messages",
+			HTTPClient: testClient{},
+		})
+
+		if client.Name() != "Google Chat" {
+			t.Errorf("Unexpected Name %s", client.Name())
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package grafana
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/secret"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/http"
+	"github.com/kyverno/policy-reporter/pkg/tracing"
+)
+
+// Options to configure the Grafana target
+type Options struct {
+	target.ClientOptions
+	Host         string
+	Token        secret.String
+	DashboardUID string
+	PanelID      int
+	Tags         []string
+	Headers      map[string]secret.String
+	HTTPClient   http.Client
+}
+
+type annotation struct {
+	DashboardUID string   `json:"dashboardUID,omitempty"`
+	PanelID      int      `json:"panelId,omitempty"`
+	Time         int64    `json:"time"`
+	Tags         []string `json:"tags"`
+	Text         string   `json:"text"`
+}
+
+type client struct {
+	target.BaseClient
+	host         string
+	token        string
+	dashboardUID string
+	panelID      int
+	tags         []string
+	headers      map[string]secret.String
+	client       http.Client
+}
+
+func (e *client) Send(result v1alpha2.PolicyReportResult) {
+	tags := append(make([]string, 0, len(e.tags)+4), e.tags...)
+	tags = append(tags, "policy-reporter", string(result.Result), "policy:"+result.Policy)
+	if result.Severity != "" {
+		tags = append(tags, "severity:"+string(result.Severity))
+	}
+
+	text := fmt.Sprintf("<b>%s</b> %s: %s", result.Policy, result.Result, result.Message)
+	if result.HasResource() {
+		res := result.GetResource()
+		if res.Namespace != "" {
+			tags = append(tags, "namespace:"+res.Namespace)
+		}
+
+		text = fmt.Sprintf("%s<br>%s %s", text, res.Kind, res.Name)
+	}
+
+	timestamp := time.Now()
+	if result.Timestamp.Seconds > 0 {
+		timestamp = time.Unix(result.Timestamp.Seconds, int64(result.Timestamp.Nanos))
+	}
+
+	req, err := http.CreateJSONRequest(e.Name(), "POST", e.host+"/api/annotations", annotation{
+		DashboardUID: e.dashboardUID,
+		PanelID:      e.panelID,
+		Time:         timestamp.UnixMilli(),
+		Tags:         tags,
+		Text:         text,
+	})
+	if err != nil {
+		return
+	}
+
+	tracing.Inject(req, result.TraceParent)
+
+	for header, value := range e.headers {
+		req.Header.Set(header, value.Value())
+	}
+	if e.token != "" {
+		req.Header.Set("Authorization", "Bearer "+e.token)
+	}
+
+	resp, err := e.client.Do(req)
+	http.ProcessHTTPResponse(e.Name(), result.GetID(), resp, err)
+}
+
+// NewClient creates a new grafana.client to send Results as Grafana annotations
+func NewClient(options Options) target.Client {
+	return &client{
+		target.NewBaseClient(options.ClientOptions),
+		options.Host,
+		options.Token.Value(),
+		options.DashboardUID,
+		options.PanelID,
+		options.Tags,
+		options.Headers,
+		options.HTTPClient,
+	}
+}
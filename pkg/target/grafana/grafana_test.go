@@ -0,0 +1,86 @@
+package grafana_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/grafana"
+)
+
+type testClient struct {
+	callback   func(req *http.Request) error
+	statusCode int
+}
+
+func (c testClient) Do(req *http.Request) (*http.Response, error) {
+	err := c.callback(req)
+
+	return &http.Response{
+		StatusCode: c.statusCode,
+		Body:       io.NopCloser(strings.NewReader("")),
+	}, err
+}
+
+func Test_GrafanaTarget(t *testing.T) {
+	t.Run("Send", func(t *testing.T) {
+		callback := func(req *http.Request) error {
+			if url := req.URL.String(); url != "http://localhost:3000/api/annotations" {
+				t.Errorf("Unexpected Host: %s", url)
+			}
+
+			if value := req.Header.Get("Authorization"); value != "Bearer token" {
+				t.Errorf("Unexpected Authorization Header: %s", value)
+			}
+
+			payload := map[string]interface{}{}
+			if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+				t.Fatal(err)
+			}
+
+			if payload["dashboardUID"] != "compliance" {
+				t.Errorf("Unexpected dashboardUID: %v", payload["dashboardUID"])
+			}
+
+			tags := payload["tags"].([]interface{})
+			if tags[0] != "cluster:dev" || tags[1] != "policy-reporter" {
+				t.Errorf("Unexpected tags: %v", tags)
+			}
+
+			if text := payload["text"].(string); !strings.Contains(text, fixtures.CompleteTargetSendResult.Policy) {
+				t.Errorf("Unexpected text: %s", text)
+			}
+
+			return nil
+		}
+
+		client := grafana.NewClient(grafana.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Grafana",
+			},
+			Host:         "http://localhost:3000",
+			Token:        "token",
+			DashboardUID: "compliance",
+			Tags:         []string{"cluster:dev"},
+			HTTPClient:   testClient{callback, 200},
+		})
+		client.Send(fixtures.CompleteTargetSendResult)
+	})
+	t.Run("Name", func(t *testing.T) {
+		client := grafana.NewClient(grafana.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Grafana",
+			},
+			Host:       "http://localhost:3000",
+			HTTPClient: testClient{},
+		})
+
+		if client.Name() != "Grafana" {
+			t.Errorf("Unexpected Name %s", client.Name())
+		}
+	})
+}
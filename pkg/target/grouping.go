@@ -0,0 +1,291 @@
+package target
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/segmentio/fasthash/fnv1a"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// GroupedKey is the property of a grouped notification with the number of merged results
+const GroupedKey = "grouped"
+
+// DefaultGroupingWindow is used for groupings without window
+const DefaultGroupingWindow = 30 * time.Second
+
+// maxGroupedResults listed in the message of a grouped notification
+const maxGroupedResults = 20
+
+// GroupValue of the result for the key, supported keys are policy, rule, source, category, severity, status,
+// namespace, kind and owner. Other keys group by the result property with this name
+func GroupValue(result v1alpha2.PolicyReportResult, key string) string {
+	switch key {
+	case "policy":
+		return result.Policy
+	case "rule":
+		return result.Rule
+	case "source":
+		return result.Source
+	case "category":
+		return result.Category
+	case "severity":
+		return string(result.Severity)
+	case "status":
+		return string(result.Result)
+	case "namespace":
+		if result.HasResource() {
+			return result.GetResource().Namespace
+		}
+		return ""
+	case "kind":
+		if result.HasResource() {
+			return result.GetResource().Kind
+		}
+		return ""
+	case "owner":
+		if name := result.Properties[v1alpha2.OwnerNameKey]; name != "" {
+			return result.Properties[v1alpha2.OwnerKindKey] + "/" + name
+		}
+		if result.HasResource() {
+			return result.GetResource().Kind + "/" + result.GetResource().Name
+		}
+		return ""
+	default:
+		return result.Properties[key]
+	}
+}
+
+type resultGroup struct {
+	results []v1alpha2.PolicyReportResult
+}
+
+// Grouper merges the results with the same values of the group keys within a window into one notification,
+// the window of a group starts with its first result
+type Grouper struct {
+	keys   []string
+	window time.Duration
+	groups map[string]*resultGroup
+	mx     *sync.Mutex
+}
+
+// Group adds the result to its group, send is called once with the merged result when the window of the group ends.
+// A group with a single result sends the result unchanged
+func (g *Grouper) Group(result v1alpha2.PolicyReportResult, send func(v1alpha2.PolicyReportResult)) {
+	key := g.key(result)
+
+	g.mx.Lock()
+	defer g.mx.Unlock()
+
+	if group, ok := g.groups[key]; ok {
+		group.results = append(group.results, result)
+		return
+	}
+
+	group := &resultGroup{results: []v1alpha2.PolicyReportResult{result}}
+	g.groups[key] = group
+
+	time.AfterFunc(g.window, func() {
+		send(g.merge(key, group))
+	})
+}
+
+func (g *Grouper) key(result v1alpha2.PolicyReportResult) string {
+	values := make([]string, 0, len(g.keys))
+	for _, key := range g.keys {
+		values = append(values, GroupValue(result, key))
+	}
+
+	return strings.Join(values, "\x00")
+}
+
+func (g *Grouper) merge(key string, group *resultGroup) v1alpha2.PolicyReportResult {
+	g.mx.Lock()
+	if g.groups[key] == group {
+		delete(g.groups, key)
+	}
+	results := group.results
+	g.mx.Unlock()
+
+	return g.combine(key, results)
+}
+
+// combine merges the results into one notification, a single result is returned unchanged
+func (g *Grouper) combine(key string, results []v1alpha2.PolicyReportResult) v1alpha2.PolicyReportResult {
+	if len(results) == 1 {
+		return results[0]
+	}
+
+	first := results[0]
+	policies := distinct(results, func(r v1alpha2.PolicyReportResult) string { return r.Policy })
+	now := time.Now()
+
+	merged := v1alpha2.PolicyReportResult{
+		Source:      common(results, func(r v1alpha2.PolicyReportResult) string { return r.Source }),
+		Policy:      strings.Join(policies, ", "),
+		Rule:        common(results, func(r v1alpha2.PolicyReportResult) string { return r.Rule }),
+		Category:    common(results, func(r v1alpha2.PolicyReportResult) string { return r.Category }),
+		Result:      first.Result,
+		Severity:    first.Severity,
+		Priority:    first.Priority,
+		Timestamp:   metav1.Timestamp{Seconds: now.Unix(), Nanos: int32(now.Nanosecond())},
+		Resources:   groupResources(results),
+		Properties:  commonProperties(results),
+		TraceParent: first.TraceParent,
+	}
+
+	for _, r := range results[1:] {
+		if r.Priority > merged.Priority {
+			merged.Priority = r.Priority
+			merged.Severity = r.Severity
+		}
+		if r.Result == v1alpha2.StatusFail || r.Result == v1alpha2.StatusError {
+			merged.Result = r.Result
+		}
+	}
+
+	merged.Properties[GroupedKey] = strconv.Itoa(len(results))
+	merged.Message = g.message(results, len(policies) > 1)
+
+	h := fnv1a.HashString64(key)
+	h = fnv1a.AddString64(h, strconv.FormatInt(now.UnixNano(), 10))
+	merged.ID = strconv.FormatUint(h, 10)
+
+	return merged
+}
+
+func (g *Grouper) message(results []v1alpha2.PolicyReportResult, withPolicy bool) string {
+	groups := make([]string, 0, len(g.keys))
+	for _, key := range g.keys {
+		if value := GroupValue(results[0], key); value != "" {
+			groups = append(groups, fmt.Sprintf("%s %s", key, value))
+		}
+	}
+
+	lines := make([]string, 0, maxGroupedResults+2)
+	if len(groups) > 0 {
+		lines = append(lines, fmt.Sprintf("%d results of %s:", len(results), strings.Join(groups, ", ")))
+	} else {
+		lines = append(lines, fmt.Sprintf("%d results:", len(results)))
+	}
+
+	for i, r := range results {
+		if i == maxGroupedResults {
+			lines = append(lines, fmt.Sprintf("- and %d more", len(results)-maxGroupedResults))
+			break
+		}
+
+		line := "-"
+		if withPolicy {
+			line += " " + r.Policy + ":"
+		}
+		if r.HasResource() {
+			res := r.GetResource()
+			line += fmt.Sprintf(" %s %s", res.Kind, strings.TrimPrefix(res.Namespace+"/"+res.Name, "/"))
+		}
+		if r.Message != "" {
+			line += " " + r.Message
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// groupResources returns the common resource of the results, the common namespace or no resource
+func groupResources(results []v1alpha2.PolicyReportResult) []corev1.ObjectReference {
+	for _, r := range results {
+		if !r.HasResource() {
+			return nil
+		}
+	}
+
+	first := *results[0].GetResource()
+	same := true
+
+	for _, r := range results[1:] {
+		res := r.GetResource()
+		if res.Namespace != first.Namespace {
+			return nil
+		}
+		if res.Kind != first.Kind || res.Name != first.Name {
+			same = false
+		}
+	}
+
+	if same {
+		return []corev1.ObjectReference{first}
+	}
+	if first.Namespace == "" {
+		return nil
+	}
+
+	return []corev1.ObjectReference{{APIVersion: "v1", Kind: "Namespace", Name: first.Namespace}}
+}
+
+func commonProperties(results []v1alpha2.PolicyReportResult) map[string]string {
+	properties := make(map[string]string)
+	for key, value := range results[0].Properties {
+		if key == v1alpha2.ResultIDKey {
+			continue
+		}
+
+		properties[key] = value
+	}
+
+	for _, r := range results[1:] {
+		for key, value := range properties {
+			if r.Properties[key] != value {
+				delete(properties, key)
+			}
+		}
+	}
+
+	return properties
+}
+
+func common(results []v1alpha2.PolicyReportResult, value func(v1alpha2.PolicyReportResult) string) string {
+	first := value(results[0])
+	for _, r := range results[1:] {
+		if value(r) != first {
+			return ""
+		}
+	}
+
+	return first
+}
+
+func distinct(results []v1alpha2.PolicyReportResult, value func(v1alpha2.PolicyReportResult) string) []string {
+	seen := make(map[string]bool)
+	list := make([]string, 0)
+	for _, r := range results {
+		if v := value(r); !seen[v] {
+			seen[v] = true
+			list = append(list, v)
+		}
+	}
+
+	sort.Strings(list)
+
+	return list
+}
+
+// NewGrouper creates a Grouper for the keys, returns nil without keys
+func NewGrouper(keys []string, window time.Duration) *Grouper {
+	if len(keys) == 0 {
+		return nil
+	}
+	if window <= 0 {
+		window = DefaultGroupingWindow
+	}
+
+	return &Grouper{keys: keys, window: window, groups: make(map[string]*resultGroup), mx: new(sync.Mutex)}
+}
@@ -0,0 +1,91 @@
+package target_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/target"
+)
+
+func pod(id, policy, namespace, name string, priority v1alpha2.Priority) v1alpha2.PolicyReportResult {
+	return v1alpha2.PolicyReportResult{
+		ID:         id,
+		Policy:     policy,
+		Source:     "kyverno",
+		Result:     v1alpha2.StatusFail,
+		Priority:   priority,
+		Message:    "label app is required",
+		Resources:  []corev1.ObjectReference{{APIVersion: "v1", Kind: "Pod", Namespace: namespace, Name: name}},
+		Properties: map[string]string{"team": "platform"},
+	}
+}
+
+func Test_Grouper(t *testing.T) {
+	t.Run("Without keys", func(t *testing.T) {
+		if target.NewGrouper(nil, time.Minute) != nil {
+			t.Error("Expected no grouper without keys")
+		}
+	})
+
+	t.Run("Group by policy", func(t *testing.T) {
+		grouper := target.NewGrouper([]string{"policy"}, 50*time.Millisecond)
+		sent := make(chan v1alpha2.PolicyReportResult, 2)
+		send := func(r v1alpha2.PolicyReportResult) { sent <- r }
+
+		grouper.Group(pod("1", "require-labels", "prod", "nginx-1", v1alpha2.WarningPriority), send)
+		grouper.Group(pod("2", "require-labels", "prod", "nginx-2", v1alpha2.CriticalPriority), send)
+		grouper.Group(pod("3", "require-labels", "prod", "nginx-3", v1alpha2.WarningPriority), send)
+		grouper.Group(pod("4", "disallow-latest", "dev", "nginx", v1alpha2.WarningPriority), send)
+
+		results := map[string]v1alpha2.PolicyReportResult{}
+		for i := 0; i < 2; i++ {
+			select {
+			case r := <-sent:
+				results[r.Policy] = r
+			case <-time.After(time.Second):
+				t.Fatal("Expected a notification per group")
+			}
+		}
+
+		merged := results["require-labels"]
+		if merged.Properties[target.GroupedKey] != "3" || merged.Properties["team"] != "platform" {
+			t.Errorf("Expected 3 grouped results with common properties, got %v", merged.Properties)
+		}
+		if merged.Priority != v1alpha2.CriticalPriority {
+			t.Errorf("Expected highest priority of the group, got %s", merged.Priority)
+		}
+		if res := merged.GetResource(); res == nil || res.Kind != "Namespace" || res.Name != "prod" {
+			t.Errorf("Expected common namespace as resource, got %v", res)
+		}
+		if !strings.HasPrefix(merged.Message, "3 results of policy require-labels:\n- Pod prod/nginx-1 label app is required") {
+			t.Errorf("Unexpected message: %s", merged.Message)
+		}
+		if merged.GetID() == "1" {
+			t.Error("Expected a new ID for the grouped result")
+		}
+
+		if single := results["disallow-latest"]; single.GetID() != "4" || single.Properties[target.GroupedKey] != "" {
+			t.Errorf("Expected a single result to be sent unchanged, got %v", single)
+		}
+	})
+
+	t.Run("Group by owner", func(t *testing.T) {
+		first := pod("1", "require-labels", "prod", "nginx-1", v1alpha2.WarningPriority)
+		first.Properties[v1alpha2.OwnerKindKey] = "Deployment"
+		first.Properties[v1alpha2.OwnerNameKey] = "nginx"
+
+		if value := target.GroupValue(first, "owner"); value != "Deployment/nginx" {
+			t.Errorf("Expected owner of the resource, got %s", value)
+		}
+		if value := target.GroupValue(pod("2", "require-labels", "prod", "nginx-2", 0), "owner"); value != "Pod/nginx-2" {
+			t.Errorf("Expected the resource without owner, got %s", value)
+		}
+		if value := target.GroupValue(first, "team"); value != "platform" {
+			t.Errorf("Expected the property for unknown keys, got %s", value)
+		}
+	})
+}
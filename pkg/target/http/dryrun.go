@@ -0,0 +1,36 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+type dryRunClient struct {
+	target string
+}
+
+// Do logs the rendered request instead of sending it and responds with an empty 200 response,
+// only the host is logged because paths of webhook URLs and headers can contain credentials
+func (c *dryRunClient) Do(req *http.Request) (*http.Response, error) {
+	body := new(bytes.Buffer)
+	if req.Body != nil {
+		body.ReadFrom(req.Body)
+		req.Body.Close()
+	}
+
+	logger.Infof("%s DRY RUN : %s %s payload: %s", c.target, req.Method, req.URL.Host, bytes.TrimSpace(body.Bytes()))
+
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewBufferString("null")),
+		Request:    req,
+	}, nil
+}
+
+// NewDryRunClient creates a Client which logs the requests of the target instead of sending them
+func NewDryRunClient(target string) Client {
+	return &dryRunClient{target: target}
+}
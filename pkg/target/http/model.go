@@ -3,6 +3,8 @@ package http
 import (
 	"net/http"
 	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/fields"
 )
 
 // Client Interface definition for HTTP based targets
@@ -10,6 +12,23 @@ type Client interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+type roundTripper struct {
+	client Client
+}
+
+func (r roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return r.client.Do(req)
+}
+
+// StdClient returns the client as *http.Client for libraries expecting the std client, e.g. golang.org/x/oauth2
+func StdClient(client Client) *http.Client {
+	if c, ok := client.(*http.Client); ok {
+		return c
+	}
+
+	return &http.Client{Transport: roundTripper{client: client}}
+}
+
 // Resource JSON structure for HTTP Requests
 type Resource struct {
 	APIVersion string `json:"apiVersion"`
@@ -31,4 +50,15 @@ type Result struct {
 	Scored            bool      `json:"scored"`
 	Resource          Resource  `json:"resource"`
 	CreationTimestamp time.Time `json:"creationTimestamp"`
+	// Timestamp is the CreationTimestamp in the configured format
+	Timestamp string `json:"timestamp"`
+	// Fields are the configured properties as typed top-level fields
+	Fields map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON adds the Fields on the top level
+func (r Result) MarshalJSON() ([]byte, error) {
+	type result Result
+
+	return fields.Marshal(result(r), r.Fields)
 }
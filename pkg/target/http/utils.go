@@ -2,18 +2,23 @@ package http
 
 import (
 	"bytes"
-	"crypto/tls"
-	"crypto/x509"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"log"
+	"net"
 	"net/http"
+	"net/http/httptrace"
 	"time"
 
+	"github.com/kyverno/policy-reporter/pkg/audit"
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fields"
+	"github.com/kyverno/policy-reporter/pkg/helper"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/timeformat"
 )
 
+var logger = logging.Component(logging.Targets)
+
 // CreateJSONRequest for the given configuration
 func CreateJSONRequest(target, method, host string, payload interface{}) (*http.Request, error) {
 	body := new(bytes.Buffer)
@@ -22,18 +27,20 @@ func CreateJSONRequest(target, method, host string, payload interface{}) (*http.
 
 	req, err := http.NewRequest(method, host, body)
 	if err != nil {
-		log.Printf("[ERROR] %s : %v\n", target, err.Error())
+		logger.Errorf("%s : %v", target, err.Error())
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Set("User-Agent", "Policy-Reporter")
 
+	logger.Debugf("%s : %s request with %d bytes payload", target, method, body.Len())
+
 	return req, nil
 }
 
-// ProcessHTTPResponse Logs Error or Success messages
-func ProcessHTTPResponse(target string, resp *http.Response, err error) {
+// ProcessHTTPResponse Logs Error or Success messages and records the delivery of the result, the result ID is empty for PolicyReport payloads
+func ProcessHTTPResponse(target, resultID string, resp *http.Response, err error) {
 	defer func() {
 		if resp != nil && resp.Body != nil {
 			resp.Body.Close()
@@ -41,15 +48,18 @@ func ProcessHTTPResponse(target string, resp *http.Response, err error) {
 	}()
 
 	if err != nil {
-		log.Printf("[ERROR] %s PUSH failed: %s\n", target, err.Error())
+		logger.Errorf("%s PUSH failed: %s", target, err.Error())
+		audit.Delivery(target, resultID, 0, err)
 	} else if resp.StatusCode >= 400 {
 		fmt.Printf("StatusCode: %d\n", resp.StatusCode)
 		buf := new(bytes.Buffer)
 		buf.ReadFrom(resp.Body)
 
-		log.Printf("[ERROR] %s PUSH failed [%d]: %s\n", target, resp.StatusCode, buf.String())
+		logger.Errorf("%s PUSH failed [%d]: %s", target, resp.StatusCode, buf.String())
+		audit.Delivery(target, resultID, resp.StatusCode, fmt.Errorf("status code %d", resp.StatusCode))
 	} else {
-		log.Printf("[INFO] %s PUSH OK\n", target)
+		logger.Infof("%s PUSH OK", target)
+		audit.Delivery(target, resultID, resp.StatusCode, nil)
 	}
 }
 
@@ -64,6 +74,8 @@ func NewJSONResult(r v1alpha2.PolicyReportResult) Result {
 		res.Name = resOb.Name
 		res.UID = string(resOb.UID)
 	}
+	created := timeformat.In(time.Unix(r.Timestamp.Seconds, int64(r.Timestamp.Nanos)))
+
 	return Result{
 		Message:           r.Message,
 		Policy:            r.Policy,
@@ -74,32 +86,86 @@ func NewJSONResult(r v1alpha2.PolicyReportResult) Result {
 		Category:          r.Category,
 		Scored:            r.Scored,
 		Resource:          res,
-		CreationTimestamp: time.Unix(r.Timestamp.Seconds, int64(r.Timestamp.Nanos)),
+		CreationTimestamp: created,
+		Timestamp:         timeformat.Format(created),
+		Fields:            fields.Map(r.Properties),
 	}
 }
 
+// Defaults of the ClientOptions
+const (
+	DefaultTimeout             = 30 * time.Second
+	DefaultMaxIdleConns        = 100
+	DefaultMaxIdleConnsPerHost = 10
+	DefaultIdleConnTimeout     = 90 * time.Second
+	DefaultKeepAlive           = 30 * time.Second
+)
+
+// ClientOptions of the HTTP client and its connection pool, zero values use the defaults
+type ClientOptions struct {
+	// Timeout of a request including the response body, a slow receiver only delays its own target
+	Timeout             time.Duration
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	// KeepAlive period of the TCP connections
+	KeepAlive         time.Duration
+	DisableKeepAlives bool
+}
+
 func NewClient(certificatePath string, skipTLS bool) *http.Client {
-	transport := http.DefaultTransport.(*http.Transport).Clone()
-	transport.TLSClientConfig = &tls.Config{
-		InsecureSkipVerify: skipTLS,
+	return NewClientWithOptions(certificatePath, skipTLS, ClientOptions{})
+}
+
+// NewClientWithOptions creates a client with its own connection pool
+func NewClientWithOptions(certificatePath string, skipTLS bool, options ClientOptions) *http.Client {
+	if options.Timeout == 0 {
+		options.Timeout = DefaultTimeout
+	}
+	if options.MaxIdleConns == 0 {
+		options.MaxIdleConns = DefaultMaxIdleConns
+	}
+	if options.MaxIdleConnsPerHost == 0 {
+		options.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	}
+	if options.IdleConnTimeout == 0 {
+		options.IdleConnTimeout = DefaultIdleConnTimeout
+	}
+	if options.KeepAlive == 0 {
+		options.KeepAlive = DefaultKeepAlive
 	}
 
-	client := &http.Client{
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = helper.NewTLSConfig(certificatePath, skipTLS)
+	transport.DialContext = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: options.KeepAlive}).DialContext
+	transport.MaxIdleConns = options.MaxIdleConns
+	transport.MaxIdleConnsPerHost = options.MaxIdleConnsPerHost
+	transport.IdleConnTimeout = options.IdleConnTimeout
+	transport.DisableKeepAlives = options.DisableKeepAlives
+
+	return &http.Client{
 		Transport: transport,
+		Timeout:   options.Timeout,
 	}
+}
 
-	if certificatePath != "" {
-		caCert, err := ioutil.ReadFile(certificatePath)
-		if err != nil {
-			log.Printf("[ERROR] failed to read certificate: %s\n", certificatePath)
-			return client
-		}
-
-		caCertPool := x509.NewCertPool()
-		caCertPool.AppendCertsFromPEM(caCert)
+type tracedClient struct {
+	client Client
+	target string
+	conn   func(target string, reused bool)
+}
 
-		transport.TLSClientConfig.RootCAs = caCertPool
+func (c *tracedClient) Do(req *http.Request) (*http.Response, error) {
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			c.conn(c.target, info.Reused)
+		},
 	}
 
-	return client
+	return c.client.Do(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+}
+
+// WithConnectionTrace calls conn with the target and whether a pooled connection was reused for each request
+func WithConnectionTrace(target string, client Client, conn func(target string, reused bool)) Client {
+	return &tracedClient{client: client, target: target, conn: conn}
 }
@@ -0,0 +1,56 @@
+package http_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	targethttp "github.com/kyverno/policy-reporter/pkg/target/http"
+)
+
+func Test_ConnectionTrace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	reused := make([]bool, 0, 2)
+
+	client := targethttp.WithConnectionTrace("Webhook", targethttp.NewClientWithOptions("", false, targethttp.ClientOptions{}), func(target string, r bool) {
+		if target != "Webhook" {
+			t.Errorf("Unexpected target: %s", target)
+		}
+		reused = append(reused, r)
+	})
+
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest("GET", server.URL, nil)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	if len(reused) != 2 || reused[0] || !reused[1] {
+		t.Errorf("Expected the second request to reuse the connection, got %v", reused)
+	}
+}
+
+func Test_NewClientWithOptions(t *testing.T) {
+	client := targethttp.NewClientWithOptions("", false, targethttp.ClientOptions{MaxIdleConnsPerHost: 2})
+	if client.Timeout != targethttp.DefaultTimeout {
+		t.Errorf("Expected default timeout, got %s", client.Timeout)
+	}
+
+	transport := client.Transport.(*http.Transport)
+	if transport.MaxIdleConnsPerHost != 2 {
+		t.Errorf("Unexpected MaxIdleConnsPerHost: %d", transport.MaxIdleConnsPerHost)
+	}
+	if transport.IdleConnTimeout != targethttp.DefaultIdleConnTimeout {
+		t.Errorf("Unexpected IdleConnTimeout: %s", transport.IdleConnTimeout)
+	}
+}
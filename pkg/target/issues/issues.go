@@ -0,0 +1,244 @@
+// Package issues contains the shared logic of the issue tracker targets: the repository resolution,
+// the issue content and the tracking of the issues opened for each result
+package issues
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/target/http"
+)
+
+var logger = logging.Component(logging.Targets)
+
+// RepositoryAnnotation is the default annotation referencing the repository of a resource
+const RepositoryAnnotation = "policy-reporter.kyverno.io/repository"
+
+// RepositoryResolver resolves the repository to open the issue of a result in
+type RepositoryResolver struct {
+	metadata   report.ResourceMetadata
+	annotation string
+	repository string
+}
+
+// Resolve the repository from the annotation of the result resource, its owner or its namespace,
+// falls back to the static repository
+func (r *RepositoryResolver) Resolve(result v1alpha2.PolicyReportResult) string {
+	if r.metadata == nil || r.annotation == "" || !result.HasResource() {
+		return r.repository
+	}
+
+	res := result.GetResource()
+
+	refs := []*corev1.ObjectReference{res}
+	if name := result.Properties[v1alpha2.OwnerNameKey]; name != "" {
+		refs = append(refs, &corev1.ObjectReference{
+			APIVersion: result.Properties[v1alpha2.OwnerAPIVersionKey],
+			Kind:       result.Properties[v1alpha2.OwnerKindKey],
+			Name:       name,
+			Namespace:  res.Namespace,
+		})
+	}
+	if res.Namespace != "" {
+		refs = append(refs, &corev1.ObjectReference{APIVersion: "v1", Kind: "Namespace", Name: res.Namespace})
+	}
+
+	for _, ref := range refs {
+		if meta, ok := r.metadata.Get(ref); ok {
+			if repository := meta.GetAnnotations()[r.annotation]; repository != "" {
+				return repository
+			}
+		}
+	}
+
+	return r.repository
+}
+
+// NewRepositoryResolver creates a RepositoryResolver, the metadata is required for the annotation
+func NewRepositoryResolver(metadata report.ResourceMetadata, annotation, repository string) *RepositoryResolver {
+	return &RepositoryResolver{metadata: metadata, annotation: annotation, repository: repository}
+}
+
+// Issue opened for a result
+type Issue struct {
+	Repository string
+	Number     int
+}
+
+// Tracker of the issues opened for each result ID
+type Tracker struct {
+	mx     *sync.Mutex
+	issues map[string]Issue
+}
+
+func (t *Tracker) Get(id string) (Issue, bool) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	issue, ok := t.issues[id]
+
+	return issue, ok
+}
+
+func (t *Tracker) Add(id string, issue Issue) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	t.issues[id] = issue
+}
+
+func (t *Tracker) Remove(id string) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	delete(t.issues, id)
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{mx: new(sync.Mutex), issues: make(map[string]Issue)}
+}
+
+// Marker identifies the issue of a result, it is part of each issue body to find existing issues
+func Marker(id string) string {
+	return fmt.Sprintf("Result ID: `%s`", id)
+}
+
+// Title of the issue
+func Title(result v1alpha2.PolicyReportResult) string {
+	title := fmt.Sprintf("[%s] %s", result.Result, result.Policy)
+	if result.Rule != "" {
+		title = fmt.Sprintf("%s/%s", title, result.Rule)
+	}
+
+	if result.HasResource() {
+		res := result.GetResource()
+		if res.Namespace != "" {
+			return fmt.Sprintf("%s: %s %s/%s", title, res.Kind, res.Namespace, res.Name)
+		}
+
+		return fmt.Sprintf("%s: %s %s", title, res.Kind, res.Name)
+	}
+
+	return title
+}
+
+// Body of the issue as markdown, remediation is the rendered remediation hint
+func Body(result v1alpha2.PolicyReportResult, remediation string) string {
+	b := new(strings.Builder)
+
+	fmt.Fprintf(b, "%s\n\n", result.Message)
+	fmt.Fprintf(b, "| | |\n|---|---|\n")
+	fmt.Fprintf(b, "| Policy | %s |\n", result.Policy)
+	if result.Rule != "" {
+		fmt.Fprintf(b, "| Rule | %s |\n", result.Rule)
+	}
+	fmt.Fprintf(b, "| Status | %s |\n", result.Result)
+	if result.Severity != "" {
+		fmt.Fprintf(b, "| Severity | %s |\n", result.Severity)
+	}
+	if result.Category != "" {
+		fmt.Fprintf(b, "| Category | %s |\n", result.Category)
+	}
+	if result.Source != "" {
+		fmt.Fprintf(b, "| Source | %s |\n", result.Source)
+	}
+	if result.HasResource() {
+		res := result.GetResource()
+		fmt.Fprintf(b, "| Resource | %s %s |\n", res.APIVersion, res.Kind)
+		if res.Namespace != "" {
+			fmt.Fprintf(b, "| Namespace | %s |\n", res.Namespace)
+		}
+		fmt.Fprintf(b, "| Name | %s |\n", res.Name)
+	}
+
+	keys := make([]string, 0, len(result.Properties))
+	for key := range result.Properties {
+		if key != v1alpha2.ResultIDKey && !v1alpha2.IsPolicyGuidanceKey(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fmt.Fprintf(b, "| %s | %s |\n", key, result.Properties[key])
+	}
+
+	if description := result.Properties[v1alpha2.PolicyDescriptionKey]; description != "" {
+		fmt.Fprintf(b, "\n**Description**\n\n%s\n", description)
+	}
+	if remediation != "" {
+		fmt.Fprintf(b, "\n**How to fix**\n\n%s\n", remediation)
+	}
+	if url := result.Properties[v1alpha2.PolicyURLKey]; url != "" {
+		fmt.Fprintf(b, "\n**Documentation**: %s\n", url)
+	}
+	if url := result.Properties[v1alpha2.ResultURLKey]; url != "" {
+		fmt.Fprintf(b, "\n**Details**: [View in Policy Reporter](%s)\n", url)
+	}
+
+	fmt.Fprintf(b, "\n---\n%s\n", Marker(result.GetID()))
+
+	return b.String()
+}
+
+// Labels of the issue, the configured labels with the severity and category of the result
+func Labels(labels []string, result v1alpha2.PolicyReportResult) []string {
+	list := append(make([]string, 0, len(labels)+2), labels...)
+	if result.Severity != "" {
+		list = append(list, "severity:"+string(result.Severity))
+	}
+	if result.Category != "" {
+		list = append(list, "category:"+result.Category)
+	}
+
+	return list
+}
+
+// NewRequest creates an API request, the payload is encoded as JSON if not nil
+func NewRequest(target, method, url string, payload interface{}) (*nethttp.Request, error) {
+	if payload != nil {
+		return http.CreateJSONRequest(target, method, url, payload)
+	}
+
+	req, err := nethttp.NewRequest(method, url, nil)
+	if err != nil {
+		logger.Errorf("%s : %v", target, err.Error())
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", "Policy-Reporter")
+
+	return req, nil
+}
+
+// Do sends the request and decodes the JSON response into out, if not nil
+func Do(client http.Client, req *nethttp.Request, out interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		buf := new(bytes.Buffer)
+		buf.ReadFrom(resp.Body)
+
+		return fmt.Errorf("status code %d: %s", resp.StatusCode, buf.String())
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
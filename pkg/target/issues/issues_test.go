@@ -0,0 +1,78 @@
+package issues_test
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/target/issues"
+)
+
+type metadata map[string]*metav1.PartialObjectMetadata
+
+func (m metadata) Get(resource *corev1.ObjectReference) (*metav1.PartialObjectMetadata, bool) {
+	meta, ok := m[resource.Kind+"/"+resource.Name]
+
+	return meta, ok
+}
+
+func annotated(repository string) *metav1.PartialObjectMetadata {
+	return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{issues.RepositoryAnnotation: repository}}}
+}
+
+func Test_RepositoryResolver(t *testing.T) {
+	resolver := issues.NewRepositoryResolver(metadata{
+		"Pod/nginx":        annotated("team/pod"),
+		"Deployment/api":   annotated("team/api"),
+		"Namespace/shop":   annotated("team/shop"),
+		"Pod/api-abcde":    {},
+		"Pod/unannotated":  {},
+		"Namespace/static": {},
+	}, issues.RepositoryAnnotation, "team/static")
+
+	pod := func(name, namespace string, props map[string]string) v1alpha2.PolicyReportResult {
+		return v1alpha2.PolicyReportResult{Resources: []corev1.ObjectReference{{Kind: "Pod", Name: name, Namespace: namespace}}, Properties: props}
+	}
+
+	cases := map[string]struct {
+		result   v1alpha2.PolicyReportResult
+		expected string
+	}{
+		"resource":  {pod("nginx", "shop", nil), "team/pod"},
+		"owner":     {pod("api-abcde", "shop", map[string]string{v1alpha2.OwnerKindKey: "Deployment", v1alpha2.OwnerNameKey: "api"}), "team/api"},
+		"namespace": {pod("unannotated", "shop", nil), "team/shop"},
+		"static":    {pod("unannotated", "static", nil), "team/static"},
+		"scope":     {v1alpha2.PolicyReportResult{}, "team/static"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if repository := resolver.Resolve(c.result); repository != c.expected {
+				t.Errorf("expected %s, got %s", c.expected, repository)
+			}
+		})
+	}
+}
+
+func Test_Content(t *testing.T) {
+	result := fixtures.CompleteTargetSendResult
+
+	if title := issues.Title(result); title != "[fail] require-requests-and-limits-required/autogen-check-for-requests-and-limits: Deployment default/nginx" {
+		t.Errorf("unexpected title: %s", title)
+	}
+
+	body := issues.Body(result, "set resources")
+	for _, expected := range []string{result.Message, "| version | 1.2.0 |", "set resources", issues.Marker(result.GetID())} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("expected %q in body: %s", expected, body)
+		}
+	}
+
+	if labels := issues.Labels([]string{"compliance"}, result); strings.Join(labels, ",") != "compliance,severity:high,category:resources" {
+		t.Errorf("unexpected labels: %v", labels)
+	}
+}
@@ -0,0 +1,52 @@
+package kinesis
+
+import (
+	"bytes"
+	"crypto/md5"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// MaxRecordSize of an aggregated record, below the 1 MiB limit of Kinesis Data Streams and the 1000 KiB limit of Firehose
+const MaxRecordSize = 1000 * 1024
+
+// AggregationMagic prefixes records in the KPL aggregation format
+var AggregationMagic = []byte{0xF3, 0x89, 0x9A, 0xC2}
+
+// Aggregate the records to a single record in the KPL aggregation format:
+// magic bytes, the AggregatedRecord protobuf message and the MD5 checksum of the message.
+// Consumers using the KCL or the kinesis-aggregation libraries deaggregate the records
+func Aggregate(keys []string, records [][]byte) *bytes.Buffer {
+	message := make([]byte, 0)
+	index := make(map[string]uint64, len(keys))
+
+	for _, key := range keys {
+		if _, ok := index[key]; ok {
+			continue
+		}
+
+		index[key] = uint64(len(index))
+		// partition_key_table
+		message = protowire.AppendTag(message, 1, protowire.BytesType)
+		message = protowire.AppendString(message, key)
+	}
+
+	for i, data := range records {
+		record := protowire.AppendTag(nil, 1, protowire.VarintType)
+		record = protowire.AppendVarint(record, index[keys[i]])
+		record = protowire.AppendTag(record, 3, protowire.BytesType)
+		record = protowire.AppendBytes(record, data)
+
+		// records
+		message = protowire.AppendTag(message, 3, protowire.BytesType)
+		message = protowire.AppendBytes(message, record)
+	}
+
+	checksum := md5.Sum(message)
+
+	body := bytes.NewBuffer(append([]byte{}, AggregationMagic...))
+	body.Write(message)
+	body.Write(checksum[:])
+
+	return body
+}
@@ -4,26 +4,60 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"log"
+	"sync"
 	"time"
 
+	"github.com/kyverno/policy-reporter/pkg/audit"
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
 	"github.com/kyverno/policy-reporter/pkg/helper"
+	"github.com/kyverno/policy-reporter/pkg/logging"
 	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/cloudevents"
 	"github.com/kyverno/policy-reporter/pkg/target/http"
 )
 
+var logger = logging.Component(logging.Targets)
+
+// DefaultFlushInterval of aggregated records
+const DefaultFlushInterval = time.Second
+
 // Options to configure the Kinesis target
 type Options struct {
 	target.ClientOptions
 	CustomFields map[string]string
 	Kinesis      helper.AWSClient
+	// PartitionKey template of the records, defaults to <policy>-<id>-<timestamp>
+	PartitionKey *target.Template
+	// Aggregate the results to records of up to MaxRecordSize, KPL aggregated records for Kinesis Data Streams
+	// and newline delimited JSON records for Firehose delivery streams
+	Aggregate bool
+	// Firehose delivery stream instead of a Kinesis Data Stream
+	Firehose bool
+	// FlushInterval of aggregated records, a record is sent at the latest after this interval
+	FlushInterval time.Duration
+	// CloudEvents wraps each result in a structured CloudEvents envelope, optional
+	CloudEvents *cloudevents.Options
+}
+
+type entry struct {
+	result v1alpha2.PolicyReportResult
+	key    string
+	data   []byte
 }
 
 type client struct {
 	target.BaseClient
 	customFields map[string]string
 	kinesis      helper.AWSClient
+	partitionKey *target.Template
+	aggregate    bool
+	firehose     bool
+	interval     time.Duration
+	cloudEvents  *cloudevents.Options
+	mx           *sync.Mutex
+	pending      []entry
+	size         int
+	timer        *time.Timer
 }
 
 func (c *client) Send(result v1alpha2.PolicyReportResult) {
@@ -41,29 +75,113 @@ func (c *client) Send(result v1alpha2.PolicyReportResult) {
 		result.Properties = props
 	}
 
+	var payload interface{} = http.NewJSONResult(result)
+	if c.cloudEvents != nil {
+		payload = c.cloudEvents.New(cloudevents.TypeResult, cloudevents.ResultSubject(result), payload)
+	}
+
 	body := new(bytes.Buffer)
 
-	if err := json.NewEncoder(body).Encode(http.NewJSONResult(result)); err != nil {
-		log.Printf("[ERROR] %s : %v\n", c.Name(), err.Error())
+	if err := json.NewEncoder(body).Encode(payload); err != nil {
+		logger.Errorf("%s : %v", c.Name(), err.Error())
 		return
 	}
 	t := time.Unix(result.Timestamp.Seconds, int64(result.Timestamp.Nanos))
-	key := fmt.Sprintf("%s-%s-%s", result.Policy, result.ID, t.Format(time.RFC3339Nano))
+	key := c.partitionKey.Render(result, fmt.Sprintf("%s-%s-%s", result.Policy, result.ID, t.Format(time.RFC3339Nano)))
+
+	if c.aggregate {
+		c.enqueue(entry{result, key, body.Bytes()})
+		return
+	}
 
 	err := c.kinesis.Upload(body, key)
 	if err != nil {
-		log.Printf("[ERROR] %s : Kinesis Upload error %v \n", c.Name(), err.Error())
+		logger.Errorf("%s : Kinesis Upload error %v ", c.Name(), err.Error())
+		audit.Delivery(c.Name(), result.GetID(), 0, err)
 		return
 	}
 
-	log.Printf("[INFO] %s PUSH OK", c.Name())
+	audit.Delivery(c.Name(), result.GetID(), 0, nil)
+	logger.Infof("%s PUSH OK", c.Name())
+}
+
+func (c *client) enqueue(e entry) {
+	// partition key, data and the protobuf overhead of the aggregated record
+	size := len(e.key) + len(e.data) + 16
+
+	c.mx.Lock()
+	if c.size+size > MaxRecordSize && len(c.pending) > 0 {
+		c.mx.Unlock()
+		c.Flush()
+		c.mx.Lock()
+	}
+
+	c.pending = append(c.pending, e)
+	c.size += size
+
+	if c.timer == nil {
+		c.timer = time.AfterFunc(c.interval, c.Flush)
+	}
+	c.mx.Unlock()
+}
+
+// Flush sends the pending results as aggregated record
+func (c *client) Flush() {
+	c.mx.Lock()
+	pending := c.pending
+	c.pending = nil
+	c.size = 0
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	c.mx.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(pending))
+	records := make([][]byte, 0, len(pending))
+	for _, e := range pending {
+		keys = append(keys, e.key)
+		records = append(records, e.data)
+	}
+
+	var body *bytes.Buffer
+	if c.firehose {
+		body = bytes.NewBuffer(bytes.Join(records, nil))
+	} else {
+		body = Aggregate(keys, records)
+	}
+
+	err := c.kinesis.Upload(body, keys[0])
+	if err != nil {
+		logger.Errorf("%s : Kinesis Upload of %d results failed %v ", c.Name(), len(pending), err.Error())
+	} else {
+		logger.Infof("%s PUSH OK: %d results", c.Name(), len(pending))
+	}
+
+	for _, e := range pending {
+		audit.Delivery(c.Name(), e.result.GetID(), 0, err)
+	}
 }
 
 // NewClient creates a new Kinesis.client to send Results to AWS Kinesis compatible source
 func NewClient(options Options) target.Client {
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = DefaultFlushInterval
+	}
+
 	return &client{
-		target.NewBaseClient(options.ClientOptions),
-		options.CustomFields,
-		options.Kinesis,
+		BaseClient:   target.NewBaseClient(options.ClientOptions),
+		customFields: options.CustomFields,
+		kinesis:      options.Kinesis,
+		partitionKey: options.PartitionKey,
+		aggregate:    options.Aggregate,
+		firehose:     options.Firehose,
+		interval:     options.FlushInterval,
+		cloudEvents:  options.CloudEvents,
+		mx:           new(sync.Mutex),
 	}
 }
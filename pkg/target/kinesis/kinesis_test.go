@@ -2,8 +2,13 @@ package kinesis_test
 
 import (
 	"bytes"
+	"crypto/md5"
 	"encoding/json"
+	"sync"
 	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
 
 	"github.com/kyverno/policy-reporter/pkg/fixtures"
 	"github.com/kyverno/policy-reporter/pkg/target"
@@ -64,4 +69,131 @@ func Test_KinesisTarget(t *testing.T) {
 			t.Errorf("Unexpected Name %s", client.Name())
 		}
 	})
+	t.Run("Partition Key", func(t *testing.T) {
+		upload := &uploadClient{}
+		key, _ := target.ParseTemplate("partitionKey", "{{ .Resource.Namespace }}")
+
+		client := kinesis.NewClient(kinesis.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Kinesis",
+			},
+			Kinesis:      upload,
+			PartitionKey: key,
+		})
+		client.Send(fixtures.CompleteTargetSendResult)
+
+		if upload.keys[0] != "default" {
+			t.Errorf("Unexpected partition key %s", upload.keys[0])
+		}
+	})
+	t.Run("Aggregation", func(t *testing.T) {
+		upload := &uploadClient{}
+
+		client := kinesis.NewClient(kinesis.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Kinesis",
+			},
+			Kinesis:   upload,
+			Aggregate: true,
+		})
+		client.Send(fixtures.CompleteTargetSendResult)
+		client.Send(fixtures.MinimalTargetSendResult)
+
+		if len(upload.keys) != 0 {
+			t.Fatal("Expected the results to be aggregated")
+		}
+
+		client.(target.FlushClient).Flush()
+
+		if len(upload.keys) != 1 {
+			t.Fatalf("Expected one aggregated record, got %d", len(upload.keys))
+		}
+
+		body := upload.bodies[0]
+		if !bytes.HasPrefix(body, kinesis.AggregationMagic) {
+			t.Fatal("Expected KPL magic bytes")
+		}
+
+		message := body[len(kinesis.AggregationMagic) : len(body)-md5.Size]
+		if checksum := md5.Sum(message); !bytes.Equal(checksum[:], body[len(body)-md5.Size:]) {
+			t.Error("Unexpected checksum")
+		}
+
+		keys, records := 0, 0
+		for len(message) > 0 {
+			num, typ, n := protowire.ConsumeTag(message)
+			if typ != protowire.BytesType || n < 0 {
+				t.Fatal("Unexpected field")
+			}
+			message = message[n:]
+
+			_, n = protowire.ConsumeBytes(message)
+			if n < 0 {
+				t.Fatal("Unexpected field length")
+			}
+			message = message[n:]
+
+			switch num {
+			case 1:
+				keys++
+			case 3:
+				records++
+			}
+		}
+
+		if keys != 2 || records != 2 {
+			t.Errorf("Expected 2 partition keys and 2 records, got %d and %d", keys, records)
+		}
+	})
+	t.Run("Firehose", func(t *testing.T) {
+		upload := &uploadClient{}
+
+		client := kinesis.NewClient(kinesis.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Kinesis",
+			},
+			Kinesis:       upload,
+			Aggregate:     true,
+			Firehose:      true,
+			FlushInterval: 10 * time.Millisecond,
+		})
+		client.Send(fixtures.CompleteTargetSendResult)
+		client.Send(fixtures.MinimalTargetSendResult)
+
+		deadline := time.Now().Add(time.Second)
+		for upload.uploads() == 0 && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		if upload.uploads() != 1 {
+			t.Fatal("Expected the record to be sent after the flush interval")
+		}
+
+		if lines := bytes.Split(bytes.TrimSpace(upload.bodies[0]), []byte("\n")); len(lines) != 2 {
+			t.Errorf("Expected 2 newline delimited results, got %d", len(lines))
+		}
+	})
+}
+
+type uploadClient struct {
+	mx     sync.Mutex
+	keys   []string
+	bodies [][]byte
+}
+
+func (c *uploadClient) Upload(body *bytes.Buffer, key string) error {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.keys = append(c.keys, key)
+	c.bodies = append(c.bodies, body.Bytes())
+
+	return nil
+}
+
+func (c *uploadClient) uploads() int {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return len(c.keys)
 }
@@ -5,14 +5,17 @@ import (
 	"time"
 
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/secret"
 	"github.com/kyverno/policy-reporter/pkg/target"
 	"github.com/kyverno/policy-reporter/pkg/target/http"
+	"github.com/kyverno/policy-reporter/pkg/tracing"
 )
 
 // Options to configure the Loko target
 type Options struct {
 	target.ClientOptions
-	Host         string
+	// Host of the push API, may contain basic auth credentials
+	Host         secret.String
 	CustomLabels map[string]string
 	HTTPClient   http.Client
 }
@@ -100,17 +103,19 @@ func (l *client) Send(result v1alpha2.PolicyReportResult) {
 		return
 	}
 
+	tracing.Inject(req, result.TraceParent)
+
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := l.client.Do(req)
-	http.ProcessHTTPResponse(l.Name(), resp, err)
+	http.ProcessHTTPResponse(l.Name(), result.GetID(), resp, err)
 }
 
 // NewClient creates a new loki.client to send Results to Loki
 func NewClient(options Options) target.Client {
 	return &client{
 		target.NewBaseClient(options.ClientOptions),
-		options.Host,
+		options.Host.Value(),
 		options.HTTPClient,
 		options.CustomLabels,
 	}
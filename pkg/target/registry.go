@@ -0,0 +1,33 @@
+package target
+
+import "sync"
+
+// Registry of the configured clients, the clients are replaced as a whole on configuration reloads
+type Registry struct {
+	mx      *sync.RWMutex
+	clients []Client
+}
+
+// Clients currently registered
+func (r *Registry) Clients() []Client {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	return r.clients
+}
+
+// Swap replaces all clients and returns the previous clients
+func (r *Registry) Swap(clients []Client) []Client {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	previous := r.clients
+	r.clients = clients
+
+	return previous
+}
+
+// NewRegistry creates a Registry with the initial clients
+func NewRegistry(clients []Client) *Registry {
+	return &Registry{mx: new(sync.RWMutex), clients: clients}
+}
@@ -0,0 +1,61 @@
+package target
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// DefaultRemediationTemplate renders the remediation property, e.g. added from the annotations of Kyverno policies
+const DefaultRemediationTemplate = `{{ .Remediation }}`
+
+// RemediationData available in the remediation template, the fields of the result are embedded
+type RemediationData struct {
+	v1alpha2.PolicyReportResult
+	Remediation string
+	Description string
+	URL         string
+}
+
+// RemediationTemplate renders the remediation hint of a result for chat targets
+type RemediationTemplate struct {
+	tmpl *template.Template
+}
+
+// Render the remediation hint, an empty string means the result has no remediation
+func (t *RemediationTemplate) Render(result v1alpha2.PolicyReportResult) string {
+	data := RemediationData{
+		PolicyReportResult: result,
+		Remediation:        result.Properties[v1alpha2.RemediationKey],
+		Description:        result.Properties[v1alpha2.PolicyDescriptionKey],
+		URL:                result.Properties[v1alpha2.PolicyURLKey],
+	}
+
+	if t == nil || t.tmpl == nil {
+		return data.Remediation
+	}
+
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		logger.Errorf("failed to render remediation template: %s", err)
+		return data.Remediation
+	}
+
+	return strings.TrimSpace(buf.String())
+}
+
+// ParseRemediationTemplate parses the text as Go template, an empty text uses the DefaultRemediationTemplate
+func ParseRemediationTemplate(text string) (*RemediationTemplate, error) {
+	if text == "" {
+		text = DefaultRemediationTemplate
+	}
+
+	tmpl, err := template.New("remediation").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RemediationTemplate{tmpl: tmpl}, nil
+}
@@ -0,0 +1,58 @@
+package target_test
+
+import (
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/target"
+)
+
+func Test_RemediationTemplate(t *testing.T) {
+	result := v1alpha2.PolicyReportResult{
+		Policy: "require-labels",
+		Properties: map[string]string{
+			v1alpha2.RemediationKey: "Add the app label.",
+			v1alpha2.PolicyURLKey:   "https://kyverno.io/policies/require-labels",
+			"fixedVersion":          "1.2.3",
+		},
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		tmpl, err := target.ParseRemediationTemplate("")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if hint := tmpl.Render(result); hint != "Add the app label." {
+			t.Errorf("unexpected remediation: %s", hint)
+		}
+		if hint := tmpl.Render(v1alpha2.PolicyReportResult{Policy: "require-labels"}); hint != "" {
+			t.Errorf("expected empty remediation, got %s", hint)
+		}
+	})
+
+	t.Run("Custom", func(t *testing.T) {
+		tmpl, err := target.ParseRemediationTemplate(`{{ .Policy }}: upgrade to {{ index .Properties "fixedVersion" }}`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if hint := tmpl.Render(result); hint != "require-labels: upgrade to 1.2.3" {
+			t.Errorf("unexpected remediation: %s", hint)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		if _, err := target.ParseRemediationTemplate("{{ .Remediation "); err == nil {
+			t.Error("expected parse error")
+		}
+	})
+
+	t.Run("Nil Template", func(t *testing.T) {
+		var tmpl *target.RemediationTemplate
+
+		if hint := tmpl.Render(result); hint != "Add the app label." {
+			t.Errorf("expected remediation property, got %s", hint)
+		}
+	})
+}
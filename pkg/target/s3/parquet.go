@@ -0,0 +1,262 @@
+package s3
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// Parquet physical and converted types
+const (
+	parquetBoolean         int32 = 0
+	parquetInt64           int32 = 2
+	parquetByteArray       int32 = 6
+	parquetUTF8            int32 = 0
+	parquetTimestampMillis int32 = 9
+	parquetNone            int32 = -1
+)
+
+// thrift compact protocol types
+const (
+	thriftI32    byte = 5
+	thriftI64    byte = 6
+	thriftBinary byte = 8
+	thriftList   byte = 9
+	thriftStruct byte = 12
+)
+
+var parquetMagic = []byte("PAR1")
+
+type parquetColumn struct {
+	name      string
+	physical  int32
+	converted int32
+	encode    func(buf *bytes.Buffer, results []v1alpha2.PolicyReportResult)
+}
+
+func stringColumn(name string, value func(r v1alpha2.PolicyReportResult) string) parquetColumn {
+	return parquetColumn{name, parquetByteArray, parquetUTF8, func(buf *bytes.Buffer, results []v1alpha2.PolicyReportResult) {
+		for _, r := range results {
+			v := value(r)
+			binary.Write(buf, binary.LittleEndian, uint32(len(v)))
+			buf.WriteString(v)
+		}
+	}}
+}
+
+func resourceColumn(name string, value func(r *v1alpha2.PolicyReportResult) string) parquetColumn {
+	return stringColumn(name, func(r v1alpha2.PolicyReportResult) string {
+		if !r.HasResource() {
+			return ""
+		}
+
+		return value(&r)
+	})
+}
+
+// parquetColumns of the result schema, all columns are required and plain encoded
+var parquetColumns = []parquetColumn{
+	stringColumn("id", func(r v1alpha2.PolicyReportResult) string { return r.GetID() }),
+	stringColumn("source", func(r v1alpha2.PolicyReportResult) string { return r.Source }),
+	stringColumn("policy", func(r v1alpha2.PolicyReportResult) string { return r.Policy }),
+	stringColumn("rule", func(r v1alpha2.PolicyReportResult) string { return r.Rule }),
+	stringColumn("status", func(r v1alpha2.PolicyReportResult) string { return string(r.Result) }),
+	stringColumn("priority", func(r v1alpha2.PolicyReportResult) string { return r.Priority.String() }),
+	stringColumn("severity", func(r v1alpha2.PolicyReportResult) string { return string(r.Severity) }),
+	stringColumn("category", func(r v1alpha2.PolicyReportResult) string { return r.Category }),
+	stringColumn("message", func(r v1alpha2.PolicyReportResult) string { return r.Message }),
+	resourceColumn("api_version", func(r *v1alpha2.PolicyReportResult) string { return r.GetResource().APIVersion }),
+	resourceColumn("kind", func(r *v1alpha2.PolicyReportResult) string { return r.GetResource().Kind }),
+	resourceColumn("namespace", func(r *v1alpha2.PolicyReportResult) string { return r.GetResource().Namespace }),
+	resourceColumn("name", func(r *v1alpha2.PolicyReportResult) string { return r.GetResource().Name }),
+	resourceColumn("uid", func(r *v1alpha2.PolicyReportResult) string { return string(r.GetResource().UID) }),
+	stringColumn("properties", func(r v1alpha2.PolicyReportResult) string {
+		if len(r.Properties) == 0 {
+			return "{}"
+		}
+
+		props, _ := json.Marshal(r.Properties)
+		return string(props)
+	}),
+	{"scored", parquetBoolean, parquetNone, func(buf *bytes.Buffer, results []v1alpha2.PolicyReportResult) {
+		packed := make([]byte, (len(results)+7)/8)
+		for i, r := range results {
+			if r.Scored {
+				packed[i/8] |= 1 << (i % 8)
+			}
+		}
+		buf.Write(packed)
+	}},
+	{"timestamp", parquetInt64, parquetTimestampMillis, func(buf *bytes.Buffer, results []v1alpha2.PolicyReportResult) {
+		for _, r := range results {
+			binary.Write(buf, binary.LittleEndian, r.Timestamp.Seconds*1000+int64(r.Timestamp.Nanos)/1e6)
+		}
+	}},
+}
+
+// compactWriter writes thrift structs with the compact protocol used by the Parquet metadata
+type compactWriter struct {
+	buf  bytes.Buffer
+	last []int16
+}
+
+func (w *compactWriter) varint(v uint64) {
+	b := make([]byte, binary.MaxVarintLen64)
+	w.buf.Write(b[:binary.PutUvarint(b, v)])
+}
+
+func (w *compactWriter) zigzag(v int64) {
+	w.varint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (w *compactWriter) field(id int16, typ byte) {
+	last := w.last[len(w.last)-1]
+	if delta := id - last; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.zigzag(int64(id))
+	}
+
+	w.last[len(w.last)-1] = id
+}
+
+func (w *compactWriter) i32(id int16, v int32) {
+	w.field(id, thriftI32)
+	w.zigzag(int64(v))
+}
+
+func (w *compactWriter) i64(id int16, v int64) {
+	w.field(id, thriftI64)
+	w.zigzag(v)
+}
+
+func (w *compactWriter) str(v string) {
+	w.varint(uint64(len(v)))
+	w.buf.WriteString(v)
+}
+
+func (w *compactWriter) binary(id int16, v string) {
+	w.field(id, thriftBinary)
+	w.str(v)
+}
+
+func (w *compactWriter) list(id int16, typ byte, size int) {
+	w.field(id, thriftList)
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | typ)
+		return
+	}
+
+	w.buf.WriteByte(0xf0 | typ)
+	w.varint(uint64(size))
+}
+
+// begin a struct, a list element or the top level struct if id is 0
+func (w *compactWriter) begin(id int16) {
+	if id > 0 {
+		w.field(id, thriftStruct)
+	}
+
+	w.last = append(w.last, 0)
+}
+
+func (w *compactWriter) end() {
+	w.buf.WriteByte(0)
+	w.last = w.last[:len(w.last)-1]
+}
+
+type columnChunk struct {
+	offset int64
+	size   int64
+}
+
+// encodeParquet writes the results as Parquet file with a single row group and one uncompressed data page per column
+func encodeParquet(results []v1alpha2.PolicyReportResult) *bytes.Buffer {
+	file := bytes.NewBuffer(append([]byte{}, parquetMagic...))
+	chunks := make([]columnChunk, 0, len(parquetColumns))
+
+	for _, column := range parquetColumns {
+		values := new(bytes.Buffer)
+		column.encode(values, results)
+
+		header := &compactWriter{}
+		header.begin(0)
+		header.i32(1, 0) // DATA_PAGE
+		header.i32(2, int32(values.Len()))
+		header.i32(3, int32(values.Len()))
+		header.begin(5)
+		header.i32(1, int32(len(results)))
+		header.i32(2, 0) // PLAIN
+		header.i32(3, 3) // RLE
+		header.i32(4, 3) // RLE
+		header.end()
+		header.end()
+
+		chunk := columnChunk{offset: int64(file.Len()), size: int64(header.buf.Len() + values.Len())}
+		file.Write(header.buf.Bytes())
+		file.Write(values.Bytes())
+
+		chunks = append(chunks, chunk)
+	}
+
+	meta := &compactWriter{}
+	meta.begin(0)
+	meta.i32(1, 1)
+
+	meta.list(2, thriftStruct, len(parquetColumns)+1)
+	meta.begin(0)
+	meta.binary(4, "schema")
+	meta.i32(5, int32(len(parquetColumns)))
+	meta.end()
+	for _, column := range parquetColumns {
+		meta.begin(0)
+		meta.i32(1, column.physical)
+		meta.i32(3, 0) // REQUIRED
+		meta.binary(4, column.name)
+		if column.converted != parquetNone {
+			meta.i32(6, column.converted)
+		}
+		meta.end()
+	}
+
+	meta.i64(3, int64(len(results)))
+
+	var total int64
+	meta.list(4, thriftStruct, 1)
+	meta.begin(0)
+	meta.list(1, thriftStruct, len(chunks))
+	for i, chunk := range chunks {
+		total += chunk.size
+
+		meta.begin(0)
+		meta.i64(2, chunk.offset)
+		meta.begin(3)
+		meta.i32(1, parquetColumns[i].physical)
+		meta.list(2, thriftI32, 1)
+		meta.zigzag(0) // PLAIN
+		meta.list(3, thriftBinary, 1)
+		meta.str(parquetColumns[i].name)
+		meta.i32(4, 0) // UNCOMPRESSED
+		meta.i64(5, int64(len(results)))
+		meta.i64(6, chunk.size)
+		meta.i64(7, chunk.size)
+		meta.i64(9, chunk.offset)
+		meta.end()
+		meta.end()
+	}
+	meta.i64(2, total)
+	meta.i64(3, int64(len(results)))
+	meta.end()
+
+	meta.binary(6, "policy-reporter")
+	meta.end()
+
+	file.Write(meta.buf.Bytes())
+	binary.Write(file, binary.LittleEndian, uint32(meta.buf.Len()))
+	file.Write(parquetMagic)
+
+	return file
+}
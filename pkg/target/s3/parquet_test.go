@@ -0,0 +1,334 @@
+package s3_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/s3"
+)
+
+// compactReader decodes thrift structs of the compact protocol into maps of field id to value,
+// integers are decoded as int64, binaries as string, lists as []interface{} and structs as map[int16]interface{}
+type compactReader struct {
+	buf []byte
+	pos int
+	err error
+}
+
+func (r *compactReader) byte() byte {
+	if r.pos >= len(r.buf) {
+		r.err = fmt.Errorf("unexpected end of data at %d", r.pos)
+		return 0
+	}
+
+	b := r.buf[r.pos]
+	r.pos++
+
+	return b
+}
+
+func (r *compactReader) varint() uint64 {
+	if r.pos >= len(r.buf) {
+		r.err = fmt.Errorf("unexpected end of data at %d", r.pos)
+		return 0
+	}
+
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	if n <= 0 {
+		r.err = fmt.Errorf("invalid varint at %d", r.pos)
+		return 0
+	}
+	r.pos += n
+
+	return v
+}
+
+func (r *compactReader) zigzag() int64 {
+	v := r.varint()
+
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *compactReader) value(typ byte) interface{} {
+	switch typ {
+	case 1, 2:
+		return typ == 1
+	case 3:
+		return int64(int8(r.byte()))
+	case 4, 5, 6:
+		return r.zigzag()
+	case 8:
+		size := int(r.varint())
+		if r.err != nil || r.pos+size > len(r.buf) {
+			r.err = fmt.Errorf("invalid binary length %d at %d", size, r.pos)
+			return ""
+		}
+		v := string(r.buf[r.pos : r.pos+size])
+		r.pos += size
+
+		return v
+	case 9:
+		header := r.byte()
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.varint())
+		}
+
+		list := make([]interface{}, 0, size)
+		for i := 0; i < size && r.err == nil; i++ {
+			if elem := header & 0x0f; elem == 1 || elem == 2 {
+				list = append(list, r.byte() == 1)
+				continue
+			}
+			list = append(list, r.value(header&0x0f))
+		}
+
+		return list
+	case 12:
+		return r.structure()
+	}
+
+	r.err = fmt.Errorf("unsupported thrift type %d at %d", typ, r.pos)
+
+	return nil
+}
+
+func (r *compactReader) structure() map[int16]interface{} {
+	fields := make(map[int16]interface{})
+
+	var last int16
+	for r.err == nil {
+		header := r.byte()
+		if header == 0 {
+			break
+		}
+
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.zigzag())
+		}
+
+		fields[id] = r.value(header & 0x0f)
+		last = id
+	}
+
+	return fields
+}
+
+func decodeColumn(values []byte, physical int64, rows int) ([]interface{}, error) {
+	decoded := make([]interface{}, 0, rows)
+	pos := 0
+
+	for i := 0; i < rows; i++ {
+		switch physical {
+		case 0:
+			decoded = append(decoded, values[i/8]&(1<<(i%8)) != 0)
+		case 2:
+			if pos+8 > len(values) {
+				return nil, fmt.Errorf("unexpected end of int64 values")
+			}
+			decoded = append(decoded, int64(binary.LittleEndian.Uint64(values[pos:])))
+			pos += 8
+		case 6:
+			if pos+4 > len(values) {
+				return nil, fmt.Errorf("unexpected end of byte array values")
+			}
+			size := int(binary.LittleEndian.Uint32(values[pos:]))
+			pos += 4
+			if pos+size > len(values) {
+				return nil, fmt.Errorf("invalid byte array length %d", size)
+			}
+			decoded = append(decoded, string(values[pos:pos+size]))
+			pos += size
+		default:
+			return nil, fmt.Errorf("unsupported physical type %d", physical)
+		}
+	}
+
+	return decoded, nil
+}
+
+func Test_ParquetRoundTrip(t *testing.T) {
+	upload := &uploadClient{}
+
+	client := s3.NewClient(s3.Options{
+		ClientOptions: target.ClientOptions{
+			Name: "S3",
+		},
+		S3:     upload,
+		Prefix: "policy-reporter",
+		Format: s3.FormatParquet,
+	})
+
+	unscored := fixtures.EnforceTargetSendResult
+	unscored.Scored = false
+	unscored.Timestamp.Nanos = 250e6
+
+	results := []v1alpha2.PolicyReportResult{fixtures.CompleteTargetSendResult, unscored}
+	for _, result := range results {
+		client.Send(result)
+	}
+	client.(target.FlushClient).Flush()
+
+	if upload.uploads() != 1 {
+		t.Fatalf("Expected both results in one partition, got %v", upload.keys)
+	}
+
+	body := upload.body
+	if !bytes.HasPrefix(body, []byte("PAR1")) || !bytes.HasSuffix(body, []byte("PAR1")) {
+		t.Fatal("Expected Parquet magic bytes")
+	}
+
+	size := int(binary.LittleEndian.Uint32(body[len(body)-8:]))
+	if size <= 0 || size > len(body)-12 {
+		t.Fatalf("Unexpected footer length %d", size)
+	}
+
+	footer := &compactReader{buf: body[len(body)-8-size : len(body)-8]}
+	meta := footer.structure()
+	if footer.err != nil {
+		t.Fatalf("Unexpected error decoding the FileMetaData: %s", footer.err)
+	}
+	if footer.pos != size {
+		t.Errorf("Expected the FileMetaData to fill the footer, decoded %d of %d bytes", footer.pos, size)
+	}
+
+	if meta[1] != int64(1) {
+		t.Errorf("Expected version 1, got %v", meta[1])
+	}
+	if meta[3] != int64(len(results)) {
+		t.Errorf("Expected %d rows, got %v", len(results), meta[3])
+	}
+	if meta[6] != "policy-reporter" {
+		t.Errorf("Expected created_by policy-reporter, got %v", meta[6])
+	}
+
+	columns := []struct {
+		name      string
+		physical  int64
+		converted int64
+	}{
+		{"id", 6, 0}, {"source", 6, 0}, {"policy", 6, 0}, {"rule", 6, 0}, {"status", 6, 0},
+		{"priority", 6, 0}, {"severity", 6, 0}, {"category", 6, 0}, {"message", 6, 0},
+		{"api_version", 6, 0}, {"kind", 6, 0}, {"namespace", 6, 0}, {"name", 6, 0}, {"uid", 6, 0},
+		{"properties", 6, 0}, {"scored", 0, -1}, {"timestamp", 2, 9},
+	}
+
+	schema := meta[2].([]interface{})
+	if len(schema) != len(columns)+1 {
+		t.Fatalf("Expected the root and %d column elements, got %d", len(columns), len(schema))
+	}
+	if root := schema[0].(map[int16]interface{}); root[4] != "schema" || root[5] != int64(len(columns)) {
+		t.Errorf("Unexpected root schema element %v", root)
+	}
+	for i, column := range columns {
+		element := schema[i+1].(map[int16]interface{})
+		if element[4] != column.name || element[1] != column.physical || element[3] != int64(0) {
+			t.Errorf("Unexpected schema element %v for column %s", element, column.name)
+		}
+
+		converted, ok := element[6]
+		if column.converted < 0 && ok {
+			t.Errorf("Expected no converted type for column %s, got %v", column.name, converted)
+		} else if column.converted >= 0 && converted != column.converted {
+			t.Errorf("Expected converted type %d for column %s, got %v", column.converted, column.name, converted)
+		}
+	}
+
+	groups := meta[4].([]interface{})
+	if len(groups) != 1 {
+		t.Fatalf("Expected one row group, got %d", len(groups))
+	}
+	group := groups[0].(map[int16]interface{})
+	if group[3] != int64(len(results)) {
+		t.Errorf("Expected %d rows in the row group, got %v", len(results), group[3])
+	}
+
+	chunks := group[1].([]interface{})
+	if len(chunks) != len(columns) {
+		t.Fatalf("Expected %d column chunks, got %d", len(columns), len(chunks))
+	}
+
+	var total int64
+	values := make(map[string][]interface{}, len(columns))
+	for i, column := range columns {
+		chunk := chunks[i].(map[int16]interface{})
+		md := chunk[3].(map[int16]interface{})
+
+		if path := md[3].([]interface{}); len(path) != 1 || path[0] != column.name {
+			t.Errorf("Unexpected path %v for column %s", path, column.name)
+		}
+		if md[1] != column.physical || md[4] != int64(0) || md[5] != int64(len(results)) {
+			t.Errorf("Unexpected column metadata %v for column %s", md, column.name)
+		}
+
+		offset := md[9].(int64)
+		if chunk[2] != offset {
+			t.Errorf("Expected the file offset %d of column %s, got %v", offset, column.name, chunk[2])
+		}
+		total += md[6].(int64)
+
+		page := &compactReader{buf: body[:len(body)-8-size], pos: int(offset)}
+		header := page.structure()
+		if page.err != nil {
+			t.Fatalf("Unexpected error decoding the page header of column %s: %s", column.name, page.err)
+		}
+		if header[1] != int64(0) {
+			t.Errorf("Expected a data page for column %s, got type %v", column.name, header[1])
+		}
+		if data := header[5].(map[int16]interface{}); data[1] != int64(len(results)) || data[2] != int64(0) {
+			t.Errorf("Unexpected data page header %v for column %s", data, column.name)
+		}
+
+		length := int(header[3].(int64))
+		if int64(page.pos)+int64(length)-offset != md[6] {
+			t.Errorf("Expected the chunk size %v for column %s, got %d", md[6], column.name, int64(page.pos)+int64(length)-offset)
+		}
+
+		decoded, err := decodeColumn(body[page.pos:page.pos+length], column.physical, len(results))
+		if err != nil {
+			t.Fatalf("Unexpected error decoding the values of column %s: %s", column.name, err)
+		}
+
+		values[column.name] = decoded
+	}
+
+	if group[2] != total {
+		t.Errorf("Expected the total byte size %d, got %v", total, group[2])
+	}
+
+	for i, result := range results {
+		res := result.GetResource()
+		expected := map[string]interface{}{
+			"id":          result.GetID(),
+			"source":      result.Source,
+			"policy":      result.Policy,
+			"rule":        result.Rule,
+			"status":      string(result.Result),
+			"priority":    result.Priority.String(),
+			"severity":    string(result.Severity),
+			"category":    result.Category,
+			"message":     result.Message,
+			"api_version": res.APIVersion,
+			"kind":        res.Kind,
+			"namespace":   res.Namespace,
+			"name":        res.Name,
+			"uid":         string(res.UID),
+			"properties":  `{"version":"1.2.0"}`,
+			"scored":      result.Scored,
+			"timestamp":   result.Timestamp.Seconds*1000 + int64(result.Timestamp.Nanos)/1e6,
+		}
+
+		for name, value := range expected {
+			if values[name][i] != value {
+				t.Errorf("Expected %v in column %s of row %d, got %v", value, name, i, values[name][i])
+			}
+		}
+	}
+
+}
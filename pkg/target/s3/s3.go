@@ -4,21 +4,53 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"log"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/kyverno/policy-reporter/pkg/audit"
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
 	"github.com/kyverno/policy-reporter/pkg/helper"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/sarif"
 	"github.com/kyverno/policy-reporter/pkg/target"
 	"github.com/kyverno/policy-reporter/pkg/target/http"
 )
 
+var logger = logging.Component(logging.Targets)
+
+// Formats of the uploaded files
+const (
+	// FormatSARIF uploads each result as SARIF log instead of JSON
+	FormatSARIF = "sarif"
+	// FormatNDJSON uploads batches of results as newline delimited JSON
+	FormatNDJSON = "ndjson"
+	// FormatParquet uploads batches of results as Parquet file
+	FormatParquet = "parquet"
+)
+
+// Defaults of the batched formats
+const (
+	DefaultFlushInterval = time.Minute
+	DefaultFlushSize     = 1000
+)
+
+// defaultPartition is the Hive partition value of results without source
+const defaultPartition = "__HIVE_DEFAULT_PARTITION__"
+
 // Options to configure the Kinesis target
 type Options struct {
 	target.ClientOptions
 	CustomFields map[string]string
 	S3           helper.AWSClient
 	Prefix       string
+	// Format of the uploaded files, json, sarif, ndjson or parquet
+	Format string
+	// FlushInterval of the batched formats, a batch is uploaded at the latest after this interval
+	FlushInterval time.Duration
+	// FlushSize of the batched formats, a batch is uploaded when it contains this amount of results
+	FlushSize int
 }
 
 type client struct {
@@ -26,6 +58,12 @@ type client struct {
 	customFields map[string]string
 	s3           helper.AWSClient
 	prefix       string
+	format       string
+	interval     time.Duration
+	size         int
+	mx           *sync.Mutex
+	pending      []v1alpha2.PolicyReportResult
+	timer        *time.Timer
 }
 
 func (c *client) Send(result v1alpha2.PolicyReportResult) {
@@ -43,30 +81,152 @@ func (c *client) Send(result v1alpha2.PolicyReportResult) {
 		result.Properties = props
 	}
 
+	if c.batched() {
+		c.enqueue(result)
+		return
+	}
+
+	var content interface{} = http.NewJSONResult(result)
+	extension := "json"
+	if c.format == FormatSARIF {
+		content = sarif.NewLog([]v1alpha2.PolicyReportResult{result})
+		extension = "sarif"
+	}
+
 	body := new(bytes.Buffer)
 
-	if err := json.NewEncoder(body).Encode(http.NewJSONResult(result)); err != nil {
-		log.Printf("[ERROR] %s : %v\n", c.Name(), err.Error())
+	if err := json.NewEncoder(body).Encode(content); err != nil {
+		logger.Errorf("%s : %v", c.Name(), err.Error())
 		return
 	}
 	t := time.Unix(result.Timestamp.Seconds, int64(result.Timestamp.Nanos))
-	key := fmt.Sprintf("%s/%s/%s-%s-%s.json", c.prefix, t.Format("2006-01-02"), result.Policy, result.ID, t.Format(time.RFC3339Nano))
+	key := fmt.Sprintf("%s/%s/%s-%s-%s.%s", c.prefix, t.Format("2006-01-02"), result.Policy, result.ID, t.Format(time.RFC3339Nano), extension)
 
 	err := c.s3.Upload(body, key)
 	if err != nil {
-		log.Printf("[ERROR] %s : S3 Upload error %v \n", c.Name(), err.Error())
+		logger.Errorf("%s : S3 Upload error %v ", c.Name(), err.Error())
+		audit.Delivery(c.Name(), result.GetID(), 0, err)
 		return
 	}
 
-	log.Printf("[INFO] %s PUSH OK", c.Name())
+	audit.Delivery(c.Name(), result.GetID(), 0, nil)
+	logger.Infof("%s PUSH OK", c.Name())
 }
 
-// NewClient creates a new S3.client to send Results to S3. It doesnt' work right now
+func (c *client) batched() bool {
+	return c.format == FormatNDJSON || c.format == FormatParquet
+}
+
+func (c *client) enqueue(result v1alpha2.PolicyReportResult) {
+	c.mx.Lock()
+	c.pending = append(c.pending, result)
+
+	if len(c.pending) < c.size {
+		if c.timer == nil {
+			c.timer = time.AfterFunc(c.interval, c.Flush)
+		}
+
+		c.mx.Unlock()
+		return
+	}
+	c.mx.Unlock()
+
+	c.Flush()
+}
+
+// Flush uploads the pending results of the batched formats, one file per partition
+func (c *client) Flush() {
+	c.mx.Lock()
+	results := c.pending
+	c.pending = nil
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	c.mx.Unlock()
+
+	if len(results) == 0 {
+		return
+	}
+
+	partitions := make(map[string][]v1alpha2.PolicyReportResult)
+	for _, result := range results {
+		key := c.partition(result)
+		partitions[key] = append(partitions[key], result)
+	}
+
+	keys := make([]string, 0, len(partitions))
+	for key := range partitions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	name := time.Now().UTC().Format("20060102T150405.000000000") + "Z"
+
+	for _, key := range keys {
+		c.upload(fmt.Sprintf("%s/%s.%s", key, name, c.format), partitions[key])
+	}
+}
+
+func (c *client) upload(key string, results []v1alpha2.PolicyReportResult) {
+	var body *bytes.Buffer
+	if c.format == FormatParquet {
+		body = encodeParquet(results)
+	} else {
+		body = new(bytes.Buffer)
+		encoder := json.NewEncoder(body)
+
+		for _, result := range results {
+			if err := encoder.Encode(http.NewJSONResult(result)); err != nil {
+				logger.Errorf("%s : %v", c.Name(), err.Error())
+				return
+			}
+		}
+	}
+
+	err := c.s3.Upload(body, key)
+	if err != nil {
+		logger.Errorf("%s : S3 Upload of %d results failed %v ", c.Name(), len(results), err.Error())
+	} else {
+		logger.Infof("%s PUSH OK: %d results", c.Name(), len(results))
+	}
+
+	for _, result := range results {
+		audit.Delivery(c.Name(), result.GetID(), 0, err)
+	}
+}
+
+// partition of the result in the Hive layout <prefix>/source=<source>/date=<YYYY-MM-DD>/hour=<HH>
+func (c *client) partition(result v1alpha2.PolicyReportResult) string {
+	t := time.Unix(result.Timestamp.Seconds, int64(result.Timestamp.Nanos)).UTC()
+
+	source := strings.ReplaceAll(result.Source, "/", "_")
+	if source == "" {
+		source = defaultPartition
+	}
+
+	return fmt.Sprintf("%s/source=%s/date=%s/hour=%s", c.prefix, source, t.Format("2006-01-02"), t.Format("15"))
+}
+
+// NewClient creates a new S3.client to send Results to S3
 func NewClient(options Options) target.Client {
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = DefaultFlushInterval
+	}
+	if options.FlushSize <= 0 {
+		options.FlushSize = DefaultFlushSize
+	}
+
 	return &client{
 		target.NewBaseClient(options.ClientOptions),
 		options.CustomFields,
 		options.S3,
 		options.Prefix,
+		options.Format,
+		options.FlushInterval,
+		options.FlushSize,
+		new(sync.Mutex),
+		nil,
+		nil,
 	}
 }
@@ -2,10 +2,15 @@ package s3_test
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/json"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/sarif"
 	"github.com/kyverno/policy-reporter/pkg/target"
 	"github.com/kyverno/policy-reporter/pkg/target/s3"
 )
@@ -60,4 +65,144 @@ func Test_S3Target(t *testing.T) {
 			t.Errorf("Unexpected Name %s", client.Name())
 		}
 	})
+	t.Run("SARIF", func(t *testing.T) {
+		upload := &uploadClient{}
+
+		client := s3.NewClient(s3.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "S3",
+			},
+			S3:     upload,
+			Prefix: "policy-reporter",
+			Format: s3.FormatSARIF,
+		})
+		client.Send(fixtures.CompleteTargetSendResult)
+
+		if !strings.HasSuffix(upload.key, ".sarif") {
+			t.Errorf("Unexpected key %s", upload.key)
+		}
+
+		log := sarif.Log{}
+		if err := json.Unmarshal(upload.body, &log); err != nil {
+			t.Fatal(err)
+		}
+		if len(log.Runs) != 1 || len(log.Runs[0].Results) != 1 {
+			t.Errorf("Expected SARIF log with one result, got %+v", log)
+		}
+	})
+	t.Run("NDJSON", func(t *testing.T) {
+		upload := &uploadClient{}
+
+		client := s3.NewClient(s3.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "S3",
+			},
+			S3:        upload,
+			Prefix:    "policy-reporter",
+			Format:    s3.FormatNDJSON,
+			FlushSize: 2,
+		})
+		client.Send(fixtures.CompleteTargetSendResult)
+
+		if upload.uploads() != 0 {
+			t.Fatal("Expected the result to be batched")
+		}
+
+		client.Send(fixtures.CompleteTargetSendResult)
+
+		if !strings.HasPrefix(upload.key, "policy-reporter/source=Kyverno/date=2021-02-23/hour=15/") || !strings.HasSuffix(upload.key, ".ndjson") {
+			t.Errorf("Unexpected key %s", upload.key)
+		}
+
+		lines := strings.Split(strings.TrimSpace(string(upload.body)), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("Expected 2 lines, got %d", len(lines))
+		}
+
+		result := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(lines[0]), &result); err != nil {
+			t.Fatal(err)
+		}
+		if result["policy"] != fixtures.CompleteTargetSendResult.Policy {
+			t.Errorf("Unexpected result %v", result)
+		}
+	})
+	t.Run("Parquet", func(t *testing.T) {
+		upload := &uploadClient{}
+
+		client := s3.NewClient(s3.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "S3",
+			},
+			S3:     upload,
+			Prefix: "policy-reporter",
+			Format: s3.FormatParquet,
+		})
+		client.Send(fixtures.CompleteTargetSendResult)
+		client.Send(fixtures.MinimalTargetSendResult)
+		client.(target.FlushClient).Flush()
+
+		if upload.uploads() != 2 {
+			t.Fatalf("Expected one file per partition, got %v", upload.keys)
+		}
+		if !strings.HasPrefix(upload.keys[0], "policy-reporter/source=Kyverno/") || !strings.HasPrefix(upload.keys[1], "policy-reporter/source=__HIVE_DEFAULT_PARTITION__/") {
+			t.Errorf("Unexpected keys %v", upload.keys)
+		}
+
+		body := upload.body
+		if !bytes.HasPrefix(body, []byte("PAR1")) || !bytes.HasSuffix(body, []byte("PAR1")) {
+			t.Fatal("Expected Parquet magic bytes")
+		}
+		if footer := binary.LittleEndian.Uint32(body[len(body)-8:]); int(footer) >= len(body)-12 {
+			t.Errorf("Unexpected footer length %d", footer)
+		}
+	})
+	t.Run("Flush Interval", func(t *testing.T) {
+		upload := &uploadClient{}
+
+		client := s3.NewClient(s3.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "S3",
+			},
+			S3:            upload,
+			Prefix:        "policy-reporter",
+			Format:        s3.FormatNDJSON,
+			FlushInterval: 10 * time.Millisecond,
+		})
+		client.Send(fixtures.CompleteTargetSendResult)
+
+		deadline := time.Now().Add(time.Second)
+		for upload.uploads() == 0 && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		if upload.uploads() != 1 {
+			t.Error("Expected the batch to be uploaded after the flush interval")
+		}
+	})
+}
+
+type uploadClient struct {
+	mx   sync.Mutex
+	key  string
+	body []byte
+	keys []string
+}
+
+func (c *uploadClient) Upload(body *bytes.Buffer, key string) error {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.key = key
+	c.body = body.Bytes()
+	c.keys = append(c.keys, key)
+
+	return nil
+}
+
+func (c *uploadClient) uploads() int {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return len(c.keys)
 }
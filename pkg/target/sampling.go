@@ -0,0 +1,144 @@
+package target
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+// SampledKey is the property of a sampling summary with the number of results which were not sent
+const SampledKey = "sampled"
+
+// DefaultSamplingWindow is used for sampling rules without window
+const DefaultSamplingWindow = time.Hour
+
+// SamplingRule limits the results sent per policy within a window
+type SamplingRule struct {
+	// Policies the rule applies to, supports globs and regular expressions, empty matches all policies
+	Policies []string
+	// Limit of results per policy and window, further results are counted and summarized when the window ends
+	Limit int
+	// Window of the limit, defaults to one hour
+	Window time.Duration
+}
+
+type sampleWindow struct {
+	start   time.Time
+	sent    int
+	sampled int
+	first   v1alpha2.PolicyReportResult
+	highest v1alpha2.Priority
+}
+
+// Sampler protects noisy targets from policies which produce a lot of results,
+// the first matching rule of a policy is used
+type Sampler struct {
+	rules   []SamplingRule
+	windows map[string]*sampleWindow
+	mx      *sync.Mutex
+}
+
+// Sample counts the result against the window of its policy and returns if it should be sent.
+// summarize is called once with a summary of the sampled results when the window of the policy ends
+func (s *Sampler) Sample(result v1alpha2.PolicyReportResult, summarize func(v1alpha2.PolicyReportResult)) bool {
+	rule, ok := s.rule(result.Policy)
+	if !ok {
+		return true
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	now := time.Now()
+
+	w, ok := s.windows[result.Policy]
+	if !ok || now.Sub(w.start) >= rule.Window {
+		w = &sampleWindow{start: now}
+		s.windows[result.Policy] = w
+	}
+
+	if w.sent < rule.Limit {
+		w.sent++
+		return true
+	}
+
+	if w.sampled == 0 {
+		w.first = result
+		time.AfterFunc(rule.Window-now.Sub(w.start), func() {
+			if summary, ok := s.summary(result.Policy, w, rule.Window); ok {
+				summarize(summary)
+			}
+		})
+	}
+
+	w.sampled++
+	if result.Priority > w.highest {
+		w.highest = result.Priority
+	}
+
+	return false
+}
+
+func (s *Sampler) summary(policy string, w *sampleWindow, window time.Duration) (v1alpha2.PolicyReportResult, bool) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.windows[policy] == w {
+		delete(s.windows, policy)
+	}
+
+	if w.sampled == 0 {
+		return v1alpha2.PolicyReportResult{}, false
+	}
+
+	return v1alpha2.PolicyReportResult{
+		Source:    w.first.Source,
+		Policy:    policy,
+		Category:  w.first.Category,
+		Severity:  w.first.Severity,
+		Result:    w.first.Result,
+		Priority:  w.highest,
+		Message:   fmt.Sprintf("%d further results of policy %s were not sent within %s, %d results were sent", w.sampled, policy, window, w.sent),
+		Timestamp: metav1.Timestamp{Seconds: time.Now().Unix()},
+		Properties: map[string]string{
+			SampledKey: strconv.Itoa(w.sampled),
+		},
+	}, true
+}
+
+func (s *Sampler) rule(policy string) (SamplingRule, bool) {
+	for _, rule := range s.rules {
+		if len(rule.Policies) == 0 || validate.MatchAny(rule.Policies, policy) {
+			return rule, true
+		}
+	}
+
+	return SamplingRule{}, false
+}
+
+// NewSampler creates a Sampler for the rules, returns nil without rules
+func NewSampler(rules []SamplingRule) *Sampler {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	list := make([]SamplingRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Window <= 0 {
+			rule.Window = DefaultSamplingWindow
+		}
+		if rule.Limit < 0 {
+			rule.Limit = 0
+		}
+
+		list = append(list, rule)
+	}
+
+	return &Sampler{rules: list, windows: make(map[string]*sampleWindow), mx: new(sync.Mutex)}
+}
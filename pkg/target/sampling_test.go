@@ -0,0 +1,69 @@
+package target_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/target"
+)
+
+func Test_Sampler(t *testing.T) {
+	t.Run("Without rules", func(t *testing.T) {
+		if target.NewSampler(nil) != nil {
+			t.Error("Expected no sampler without rules")
+		}
+	})
+
+	t.Run("Limit per policy", func(t *testing.T) {
+		sampler := target.NewSampler([]target.SamplingRule{{Policies: []string{"audit-*"}, Limit: 2, Window: time.Hour}})
+
+		sent := 0
+		for i := 0; i < 5; i++ {
+			if sampler.Sample(v1alpha2.PolicyReportResult{Policy: "audit-labels"}, func(v1alpha2.PolicyReportResult) {}) {
+				sent++
+			}
+		}
+		if sent != 2 {
+			t.Errorf("Expected 2 sent results, got %d", sent)
+		}
+
+		if !sampler.Sample(v1alpha2.PolicyReportResult{Policy: "audit-images"}, func(v1alpha2.PolicyReportResult) {}) {
+			t.Error("Expected separate limit per policy")
+		}
+		for i := 0; i < 5; i++ {
+			if !sampler.Sample(v1alpha2.PolicyReportResult{Policy: "require-labels"}, func(v1alpha2.PolicyReportResult) {}) {
+				t.Fatal("Expected results of policies without rule not to be sampled")
+			}
+		}
+	})
+
+	t.Run("Summary after window", func(t *testing.T) {
+		sampler := target.NewSampler([]target.SamplingRule{{Limit: 1, Window: 50 * time.Millisecond}})
+		summaries := make(chan v1alpha2.PolicyReportResult, 1)
+		summarize := func(r v1alpha2.PolicyReportResult) { summaries <- r }
+
+		sampler.Sample(v1alpha2.PolicyReportResult{Policy: "audit", Priority: v1alpha2.WarningPriority}, summarize)
+		sampler.Sample(v1alpha2.PolicyReportResult{Policy: "audit", Priority: v1alpha2.WarningPriority}, summarize)
+		sampler.Sample(v1alpha2.PolicyReportResult{Policy: "audit", Priority: v1alpha2.ErrorPriority}, summarize)
+
+		select {
+		case summary := <-summaries:
+			if summary.Properties[target.SampledKey] != "2" {
+				t.Errorf("Expected 2 sampled results, got %s", summary.Properties[target.SampledKey])
+			}
+			if summary.Priority != v1alpha2.ErrorPriority {
+				t.Errorf("Expected highest priority of the sampled results, got %s", summary.Priority)
+			}
+			if summary.Policy != "audit" {
+				t.Errorf("Expected policy of the sampled results, got %s", summary.Policy)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected summary when the window ends")
+		}
+
+		if !sampler.Sample(v1alpha2.PolicyReportResult{Policy: "audit"}, summarize) {
+			t.Error("Expected new window after the summary")
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package scc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+
+	"github.com/kyverno/policy-reporter/pkg/secret"
+)
+
+// Scope of the access tokens
+const Scope = "https://www.googleapis.com/auth/cloud-platform"
+
+// MetadataTokenURL of the GKE metadata server, used with Workload Identity
+const MetadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+type serviceAccount struct {
+	Type         string `json:"type"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+}
+
+type metadataSource struct {
+	client *http.Client
+}
+
+func (m *metadataSource) Token() (*oauth2.Token, error) {
+	req, err := http.NewRequest("GET", MetadataTokenURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch token from the metadata server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch token from the metadata server: status code %d", resp.StatusCode)
+	}
+
+	token := struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+		TokenType   string `json:"token_type"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		Expiry:      time.Now().Add(time.Duration(token.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// NewTokenSource creates a cached token source of the service account key in JSON format,
+// without credentials the token of the Workload Identity is fetched from the GKE metadata server
+func NewTokenSource(credentials secret.String) (oauth2.TokenSource, error) {
+	if credentials == "" {
+		return oauth2.ReuseTokenSource(nil, &metadataSource{client: &http.Client{Timeout: 10 * time.Second}}), nil
+	}
+
+	account := serviceAccount{}
+	if err := json.Unmarshal([]byte(credentials.Value()), &account); err != nil {
+		return nil, fmt.Errorf("invalid service account key: %w", err)
+	}
+	if account.Type != "service_account" || account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, errors.New("invalid service account key: expected type service_account with client_email and private_key")
+	}
+
+	config := &jwt.Config{
+		Email:        account.ClientEmail,
+		PrivateKey:   []byte(account.PrivateKey),
+		PrivateKeyID: account.PrivateKeyID,
+		Scopes:       []string{Scope},
+		TokenURL:     account.TokenURI,
+	}
+	if config.TokenURL == "" {
+		config.TokenURL = "https://oauth2.googleapis.com/token"
+	}
+
+	return config.TokenSource(context.Background()), nil
+}
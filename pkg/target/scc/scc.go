@@ -0,0 +1,381 @@
+package scc
+
+import (
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/kyverno/policy-reporter/pkg/audit"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/http"
+	"github.com/kyverno/policy-reporter/pkg/target/issues"
+)
+
+var logger = logging.Component(logging.Targets)
+
+// DefaultHost of the Security Command Center API
+const DefaultHost = "https://securitycenter.googleapis.com"
+
+// DefaultSourceName is the display name of the registered source
+const DefaultSourceName = "Policy Reporter"
+
+// States of a finding
+const (
+	StateActive   = "ACTIVE"
+	StateInactive = "INACTIVE"
+)
+
+var findingID = regexp.MustCompile(`[^a-zA-Z0-9]`)
+
+// Options to configure the Security Command Center target
+type Options struct {
+	target.ClientOptions
+	// Host of the API, defaults to DefaultHost
+	Host           string
+	OrganizationID string
+	// Source of the findings, e.g. organizations/123/sources/456. Registered with the SourceName if empty
+	Source     string
+	SourceName string
+	// Cluster of the resource names, resources are mapped to the organization without the cluster
+	Cluster      Cluster
+	CustomFields map[string]string
+	TokenSource  oauth2.TokenSource
+	HTTPClient   http.Client
+}
+
+// Cluster identifies the GKE cluster in the resource names
+type Cluster struct {
+	ProjectID string
+	Location  string
+	Name      string
+}
+
+// Finding of the Security Command Center API
+type Finding struct {
+	State            string                 `json:"state"`
+	ResourceName     string                 `json:"resourceName"`
+	Category         string                 `json:"category"`
+	ExternalURI      string                 `json:"externalUri,omitempty"`
+	SourceProperties map[string]interface{} `json:"sourceProperties,omitempty"`
+	EventTime        string                 `json:"eventTime"`
+	Severity         string                 `json:"severity"`
+	FindingClass     string                 `json:"findingClass"`
+	Description      string                 `json:"description,omitempty"`
+}
+
+type source struct {
+	Name        string `json:"name,omitempty"`
+	DisplayName string `json:"displayName"`
+	Description string `json:"description,omitempty"`
+}
+
+type sourceList struct {
+	Sources       []source `json:"sources"`
+	NextPageToken string   `json:"nextPageToken"`
+}
+
+// Plural of the kind as used in the Kubernetes API paths
+func Plural(kind string) string {
+	plural := strings.ToLower(kind)
+
+	switch {
+	case plural == "endpoints":
+		return plural
+	case strings.HasSuffix(plural, "s"), strings.HasSuffix(plural, "x"), strings.HasSuffix(plural, "ch"), strings.HasSuffix(plural, "sh"):
+		return plural + "es"
+	case len(plural) > 1 && strings.HasSuffix(plural, "y") && !strings.ContainsAny(plural[len(plural)-2:len(plural)-1], "aeiou"):
+		return plural[:len(plural)-1] + "ies"
+	default:
+		return plural + "s"
+	}
+}
+
+// ResourceName of the result, resources of GKE clusters are mapped to the resource names of the Cloud Asset Inventory:
+// //container.googleapis.com/projects/<project>/locations/<location>/clusters/<cluster>/k8s/namespaces/<namespace>/<group>/<kinds>/<name>.
+// Results without resource or cluster are mapped to the organization
+func ResourceName(organizationID string, cluster Cluster, result v1alpha2.PolicyReportResult) string {
+	if cluster.ProjectID == "" || cluster.Location == "" || cluster.Name == "" {
+		return "//cloudresourcemanager.googleapis.com/organizations/" + organizationID
+	}
+
+	name := fmt.Sprintf("//container.googleapis.com/projects/%s/locations/%s/clusters/%s", cluster.ProjectID, cluster.Location, cluster.Name)
+	if !result.HasResource() {
+		return name
+	}
+
+	res := result.GetResource()
+	if res.Kind == "Namespace" {
+		return name + "/k8s/namespaces/" + res.Name
+	}
+
+	path := []string{name, "k8s"}
+	if res.Namespace != "" {
+		path = append(path, "namespaces", res.Namespace)
+	}
+	if group := strings.Split(res.APIVersion, "/"); len(group) == 2 {
+		path = append(path, group[0])
+	}
+
+	return strings.Join(append(path, Plural(res.Kind), res.Name), "/")
+}
+
+// Severity of the finding, the priority is used for results without severity
+func Severity(result v1alpha2.PolicyReportResult) string {
+	switch result.Severity {
+	case v1alpha2.SeverityCritical:
+		return "CRITICAL"
+	case v1alpha2.SeverityHigh:
+		return "HIGH"
+	case v1alpha2.SeverityMedium:
+		return "MEDIUM"
+	case v1alpha2.SeverityLow, v1alpha2.SeverityInfo:
+		return "LOW"
+	}
+
+	switch result.Priority {
+	case v1alpha2.CriticalPriority:
+		return "CRITICAL"
+	case v1alpha2.ErrorPriority:
+		return "HIGH"
+	case v1alpha2.WarningPriority:
+		return "MEDIUM"
+	default:
+		return "LOW"
+	}
+}
+
+type client struct {
+	target.BaseClient
+	host           string
+	organizationID string
+	sourceName     string
+	cluster        Cluster
+	customFields   map[string]string
+	tokens         oauth2.TokenSource
+	client         http.Client
+	mx             *sync.Mutex
+	source         string
+}
+
+func (c *client) finding(result v1alpha2.PolicyReportResult) Finding {
+	eventTime := time.Unix(result.Timestamp.Seconds, int64(result.Timestamp.Nanos)).UTC()
+	if result.Timestamp.Seconds == 0 {
+		eventTime = time.Now().UTC()
+	}
+
+	properties := map[string]interface{}{
+		"policy":   result.Policy,
+		"status":   string(result.Result),
+		"priority": result.Priority.String(),
+		"source":   result.Source,
+	}
+	if result.Rule != "" {
+		properties["rule"] = result.Rule
+	}
+	if result.Category != "" {
+		properties["category"] = result.Category
+	}
+	if result.HasResource() {
+		res := result.GetResource()
+
+		properties["kind"] = res.Kind
+		properties["name"] = res.Name
+		if res.Namespace != "" {
+			properties["namespace"] = res.Namespace
+		}
+	}
+	for property, value := range c.customFields {
+		properties[property] = value
+	}
+	for property, value := range result.Properties {
+		properties[property] = value
+	}
+
+	return Finding{
+		State:            StateActive,
+		ResourceName:     ResourceName(c.organizationID, c.cluster, result),
+		Category:         strings.ToUpper(strings.NewReplacer("-", "_", " ", "_").Replace(result.Policy)),
+		ExternalURI:      result.Properties[v1alpha2.PolicyURLKey],
+		SourceProperties: properties,
+		EventTime:        eventTime.Format(time.RFC3339Nano),
+		Severity:         Severity(result),
+		FindingClass:     "MISCONFIGURATION",
+		Description:      result.Message,
+	}
+}
+
+// registeredSource returns the configured source or registers a source with the display name in the organization
+func (c *client) registeredSource() (string, error) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if c.source != "" {
+		return c.source, nil
+	}
+
+	parent := fmt.Sprintf("%s/v1/organizations/%s/sources", c.host, c.organizationID)
+
+	pageToken := ""
+	for {
+		req, err := c.request("GET", parent+"?pageSize=100&pageToken="+url.QueryEscape(pageToken), nil)
+		if err != nil {
+			return "", err
+		}
+
+		list := sourceList{}
+		if err := issues.Do(c.client, req, &list); err != nil {
+			return "", fmt.Errorf("failed to list sources: %w", err)
+		}
+
+		for _, s := range list.Sources {
+			if s.DisplayName == c.sourceName {
+				c.source = s.Name
+				return c.source, nil
+			}
+		}
+
+		if list.NextPageToken == "" {
+			break
+		}
+		pageToken = list.NextPageToken
+	}
+
+	req, err := c.request("POST", parent, source{DisplayName: c.sourceName, Description: "Kubernetes policy findings of PolicyReports"})
+	if err != nil {
+		return "", err
+	}
+
+	created := source{}
+	if err := issues.Do(c.client, req, &created); err != nil {
+		return "", fmt.Errorf("failed to register source: %w", err)
+	}
+	if created.Name == "" {
+		return "", fmt.Errorf("failed to register source %s", c.sourceName)
+	}
+
+	logger.Infof("%s registered source %s", c.Name(), created.Name)
+	c.source = created.Name
+
+	return c.source, nil
+}
+
+func (c *client) findingURL(result v1alpha2.PolicyReportResult) (string, error) {
+	source, err := c.registeredSource()
+	if err != nil {
+		return "", err
+	}
+
+	id := findingID.ReplaceAllString(result.GetID(), "")
+	if len(id) > 32 {
+		id = id[:32]
+	}
+
+	return fmt.Sprintf("%s/v1/%s/findings/%s", c.host, source, id), nil
+}
+
+// Send creates or updates the active finding of the result
+func (c *client) Send(result v1alpha2.PolicyReportResult) {
+	endpoint, err := c.findingURL(result)
+	if err != nil {
+		c.failed(result, err)
+		return
+	}
+
+	req, err := c.request("PATCH", endpoint, c.finding(result))
+	if err != nil {
+		return
+	}
+
+	if err := issues.Do(c.client, req, nil); err != nil {
+		c.failed(result, err)
+		return
+	}
+
+	c.succeeded(result)
+}
+
+// Resolve sets the state of the finding to INACTIVE
+func (c *client) Resolve(result v1alpha2.PolicyReportResult) {
+	endpoint, err := c.findingURL(result)
+	if err != nil {
+		c.failed(result, err)
+		return
+	}
+
+	req, err := c.request("POST", endpoint+":setState", map[string]string{
+		"state":     StateInactive,
+		"startTime": time.Now().UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return
+	}
+
+	if err := issues.Do(c.client, req, nil); err != nil {
+		c.failed(result, err)
+		return
+	}
+
+	c.succeeded(result)
+}
+
+func (c *client) request(method, endpoint string, payload interface{}) (*nethttp.Request, error) {
+	req, err := issues.NewRequest(c.Name(), method, endpoint, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.tokens != nil {
+		token, err := c.tokens.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		token.SetAuthHeader(req)
+	}
+
+	return req, nil
+}
+
+func (c *client) failed(result v1alpha2.PolicyReportResult, err error) {
+	logger.Errorf("%s PUSH failed: %s", c.Name(), err.Error())
+	audit.Delivery(c.Name(), result.GetID(), 0, err)
+}
+
+func (c *client) succeeded(result v1alpha2.PolicyReportResult) {
+	logger.Infof("%s PUSH OK", c.Name())
+	audit.Delivery(c.Name(), result.GetID(), 0, nil)
+}
+
+// NewClient creates a new SCC.client to publish results as findings to the GCP Security Command Center
+func NewClient(options Options) target.ResolvingClient {
+	host := options.Host
+	if host == "" {
+		host = DefaultHost
+	}
+
+	sourceName := options.SourceName
+	if sourceName == "" {
+		sourceName = DefaultSourceName
+	}
+
+	return &client{
+		target.NewBaseClient(options.ClientOptions),
+		strings.TrimSuffix(host, "/"),
+		options.OrganizationID,
+		sourceName,
+		options.Cluster,
+		options.CustomFields,
+		options.TokenSource,
+		options.HTTPClient,
+		new(sync.Mutex),
+		options.Source,
+	}
+}
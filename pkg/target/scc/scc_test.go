@@ -0,0 +1,194 @@
+package scc_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/scc"
+)
+
+type testClient struct {
+	requests  []*http.Request
+	payloads  []map[string]interface{}
+	responses map[string]string
+}
+
+func (c *testClient) Do(req *http.Request) (*http.Response, error) {
+	payload := map[string]interface{}{}
+	if req.Body != nil {
+		json.NewDecoder(req.Body).Decode(&payload)
+	}
+
+	c.requests = append(c.requests, req)
+	c.payloads = append(c.payloads, payload)
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(c.responses[req.Method])),
+	}, nil
+}
+
+var cluster = scc.Cluster{ProjectID: "project", Location: "europe-west1", Name: "prod"}
+
+func newClient(http *testClient, source string) target.ResolvingClient {
+	return scc.NewClient(scc.Options{
+		ClientOptions: target.ClientOptions{
+			Name: "SecurityCommandCenter",
+		},
+		OrganizationID: "123",
+		Source:         source,
+		Cluster:        cluster,
+		CustomFields:   map[string]string{"cluster": "prod"},
+		TokenSource:    oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}),
+		HTTPClient:     http,
+	})
+}
+
+func Test_SecurityCenterTarget(t *testing.T) {
+	t.Run("Send", func(t *testing.T) {
+		http := &testClient{}
+		client := newClient(http, "organizations/123/sources/456")
+
+		client.Send(fixtures.CompleteTargetSendResult)
+
+		if len(http.requests) != 1 {
+			t.Fatalf("expected one request, got %d", len(http.requests))
+		}
+
+		req := http.requests[0]
+		if req.Method != "PATCH" || !strings.HasPrefix(req.URL.String(), "https://securitycenter.googleapis.com/v1/organizations/123/sources/456/findings/") {
+			t.Errorf("unexpected request: %s %s", req.Method, req.URL.String())
+		}
+		if value := req.Header.Get("Authorization"); value != "Bearer token" {
+			t.Errorf("unexpected Authorization Header: %s", value)
+		}
+
+		finding := http.payloads[0]
+		if finding["state"] != scc.StateActive {
+			t.Errorf("unexpected state: %v", finding["state"])
+		}
+		if finding["severity"] != "HIGH" {
+			t.Errorf("unexpected severity: %v", finding["severity"])
+		}
+		if finding["category"] != "REQUIRE_REQUESTS_AND_LIMITS_REQUIRED" {
+			t.Errorf("unexpected category: %v", finding["category"])
+		}
+		if name := finding["resourceName"]; name != "//container.googleapis.com/projects/project/locations/europe-west1/clusters/prod/k8s/namespaces/default/deployments/nginx" {
+			t.Errorf("unexpected resourceName: %v", name)
+		}
+		if properties := finding["sourceProperties"].(map[string]interface{}); properties["cluster"] != "prod" || properties["version"] != "1.2.0" {
+			t.Errorf("unexpected sourceProperties: %v", properties)
+		}
+	})
+	t.Run("Register Source", func(t *testing.T) {
+		http := &testClient{responses: map[string]string{
+			"GET":  `{"sources":[{"name":"organizations/123/sources/1","displayName":"Other"}]}`,
+			"POST": `{"name":"organizations/123/sources/789","displayName":"Policy Reporter"}`,
+		}}
+		client := newClient(http, "")
+
+		client.Send(fixtures.CompleteTargetSendResult)
+		client.Send(fixtures.CompleteTargetSendResult)
+
+		if len(http.requests) != 4 {
+			t.Fatalf("expected list, create and two finding requests, got %d", len(http.requests))
+		}
+		if url := http.requests[1].URL.String(); http.requests[1].Method != "POST" || url != "https://securitycenter.googleapis.com/v1/organizations/123/sources" {
+			t.Errorf("unexpected register request: %s %s", http.requests[1].Method, url)
+		}
+		if http.payloads[1]["displayName"] != scc.DefaultSourceName {
+			t.Errorf("unexpected displayName: %v", http.payloads[1]["displayName"])
+		}
+		if url := http.requests[3].URL.String(); !strings.HasPrefix(url, "https://securitycenter.googleapis.com/v1/organizations/123/sources/789/findings/") {
+			t.Errorf("unexpected finding URL: %s", url)
+		}
+	})
+	t.Run("Existing Source", func(t *testing.T) {
+		http := &testClient{responses: map[string]string{
+			"GET": `{"sources":[{"name":"organizations/123/sources/321","displayName":"Policy Reporter"}]}`,
+		}}
+		client := newClient(http, "")
+
+		client.Send(fixtures.CompleteTargetSendResult)
+
+		if len(http.requests) != 2 || http.requests[1].Method != "PATCH" {
+			t.Fatalf("expected list and finding requests, got %d", len(http.requests))
+		}
+		if url := http.requests[1].URL.String(); !strings.Contains(url, "/sources/321/findings/") {
+			t.Errorf("unexpected finding URL: %s", url)
+		}
+	})
+	t.Run("Resolve", func(t *testing.T) {
+		http := &testClient{}
+		client := newClient(http, "organizations/123/sources/456")
+
+		client.Resolve(fixtures.CompleteTargetSendResult)
+
+		if len(http.requests) != 1 {
+			t.Fatalf("expected one request, got %d", len(http.requests))
+		}
+		if req := http.requests[0]; req.Method != "POST" || !strings.HasSuffix(req.URL.String(), ":setState") {
+			t.Errorf("unexpected request: %s %s", req.Method, req.URL.String())
+		}
+		if http.payloads[0]["state"] != scc.StateInactive {
+			t.Errorf("unexpected state: %v", http.payloads[0]["state"])
+		}
+	})
+}
+
+func Test_ResourceName(t *testing.T) {
+	result := func(res corev1.ObjectReference) v1alpha2.PolicyReportResult {
+		return v1alpha2.PolicyReportResult{Resources: []corev1.ObjectReference{res}}
+	}
+
+	prefix := "//container.googleapis.com/projects/project/locations/europe-west1/clusters/prod"
+
+	tests := map[string]struct {
+		cluster scc.Cluster
+		result  v1alpha2.PolicyReportResult
+		name    string
+	}{
+		"Organization":   {scc.Cluster{}, fixtures.CompleteTargetSendResult, "//cloudresourcemanager.googleapis.com/organizations/123"},
+		"Cluster":        {cluster, fixtures.MinimalTargetSendResult, prefix},
+		"Namespace":      {cluster, result(corev1.ObjectReference{APIVersion: "v1", Kind: "Namespace", Name: "default"}), prefix + "/k8s/namespaces/default"},
+		"Grouped":        {cluster, result(corev1.ObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "nginx", Namespace: "default"}), prefix + "/k8s/namespaces/default/apps/deployments/nginx"},
+		"Cluster Scoped": {cluster, result(corev1.ObjectReference{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole", Name: "admin"}), prefix + "/k8s/rbac.authorization.k8s.io/clusterroles/admin"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if resourceName := scc.ResourceName("123", test.cluster, test.result); resourceName != test.name {
+				t.Errorf("unexpected resource name: %s", resourceName)
+			}
+		})
+	}
+}
+
+func Test_Plural(t *testing.T) {
+	for kind, plural := range map[string]string{"Pod": "pods", "Ingress": "ingresses", "NetworkPolicy": "networkpolicies", "Endpoints": "endpoints", "Gateway": "gateways"} {
+		if result := scc.Plural(kind); result != plural {
+			t.Errorf("expected %s for %s, got %s", plural, kind, result)
+		}
+	}
+}
+
+func Test_NewTokenSource(t *testing.T) {
+	if _, err := scc.NewTokenSource(""); err != nil {
+		t.Errorf("expected metadata token source, got error: %s", err)
+	}
+	if _, err := scc.NewTokenSource(`{"type":"authorized_user"}`); err == nil {
+		t.Error("expected error for a non service account key")
+	}
+	if _, err := scc.NewTokenSource("invalid"); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
@@ -0,0 +1,119 @@
+package target
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/maintenance"
+)
+
+var logger = logging.Component(logging.Targets)
+
+// scheduleInterval in which held results are checked against the delivery windows
+const scheduleInterval = time.Minute
+
+// maxHeldResults of a schedule, further results outside of the delivery windows are counted in the digest but dropped
+const maxHeldResults = 1000
+
+// Schedule restricts the delivery of a target to recurring windows like business hours, results outside of the windows
+// are held in memory and delivered as one digest when the next window starts
+type Schedule struct {
+	name     string
+	windows  []maintenance.QuietHours
+	interval time.Duration
+	now      func() time.Time
+	mx       *sync.Mutex
+	held     []v1alpha2.PolicyReportResult
+	dropped  int
+	timer    *time.Timer
+}
+
+// Open reports if the time is within a delivery window
+func (s *Schedule) Open(t time.Time) bool {
+	for _, w := range s.windows {
+		if w.Contains(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Hold returns false within a delivery window. Outside of the windows the result is held and deliver is called
+// with the digest of all held results when the next window starts
+func (s *Schedule) Hold(result v1alpha2.PolicyReportResult, deliver func(v1alpha2.PolicyReportResult)) bool {
+	if s.Open(s.now()) {
+		return false
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if len(s.held) < maxHeldResults {
+		s.held = append(s.held, result)
+	} else {
+		if s.dropped == 0 {
+			logger.Warnf("%s: more than %d results held until the next delivery window, further results are only counted", s.name, maxHeldResults)
+		}
+
+		s.dropped++
+	}
+
+	if s.timer == nil {
+		s.timer = time.AfterFunc(s.interval, func() { s.release(deliver) })
+	}
+
+	return true
+}
+
+// Held returns the number of results waiting for the next delivery window
+func (s *Schedule) Held() int {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	return len(s.held) + s.dropped
+}
+
+func (s *Schedule) release(deliver func(v1alpha2.PolicyReportResult)) {
+	s.mx.Lock()
+	if !s.Open(s.now()) {
+		s.timer.Reset(s.interval)
+		s.mx.Unlock()
+		return
+	}
+
+	held, dropped := s.held, s.dropped
+	s.held, s.dropped, s.timer = nil, 0, nil
+	s.mx.Unlock()
+
+	if len(held) == 0 {
+		return
+	}
+
+	digest := (&Grouper{}).combine("schedule:"+s.name, held)
+	if dropped > 0 {
+		digest.Message += fmt.Sprintf("\n%d further results were held but not kept", dropped)
+	}
+
+	logger.Infof("%s: delivery window started, sending digest of %d held results", s.name, len(held)+dropped)
+
+	deliver(digest)
+}
+
+// NewSchedule creates a Schedule of the delivery windows, nil without windows
+func NewSchedule(name string, windows []maintenance.QuietHours) *Schedule {
+	if len(windows) == 0 {
+		return nil
+	}
+
+	return &Schedule{
+		name:     name,
+		windows:  windows,
+		interval: scheduleInterval,
+		now:      time.Now,
+		mx:       new(sync.Mutex),
+	}
+}
@@ -0,0 +1,55 @@
+package target_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/maintenance"
+	"github.com/kyverno/policy-reporter/pkg/target"
+)
+
+func Test_Schedule(t *testing.T) {
+	now := time.Now().UTC()
+	send := func(r v1alpha2.PolicyReportResult) { t.Error("Expected no digest before the window starts") }
+
+	t.Run("Without windows", func(t *testing.T) {
+		if target.NewSchedule("slack", nil) != nil {
+			t.Error("Expected no schedule without windows")
+		}
+	})
+
+	t.Run("Within window", func(t *testing.T) {
+		schedule := target.NewSchedule("slack", []maintenance.QuietHours{{From: 0, To: 24 * 60, Location: time.UTC}})
+
+		if !schedule.Open(now) {
+			t.Error("Expected the schedule to be open")
+		}
+		if schedule.Hold(pod("1", "require-labels", "prod", "nginx-1", v1alpha2.WarningPriority), send) {
+			t.Error("Expected the result not to be held within the window")
+		}
+		if schedule.Held() != 0 {
+			t.Errorf("Expected no held results, got %d", schedule.Held())
+		}
+	})
+
+	t.Run("Outside of window", func(t *testing.T) {
+		tomorrow := now.AddDate(0, 0, 1).Weekday()
+		schedule := target.NewSchedule("slack", []maintenance.QuietHours{{Days: []time.Weekday{tomorrow}, From: 0, To: 24 * 60, Location: time.UTC}})
+
+		if schedule.Open(now) {
+			t.Error("Expected the schedule to be closed")
+		}
+		if !schedule.Open(now.AddDate(0, 0, 1)) {
+			t.Error("Expected the schedule to open tomorrow")
+		}
+
+		schedule.Hold(pod("1", "require-labels", "prod", "nginx-1", v1alpha2.WarningPriority), send)
+		if !schedule.Hold(pod("2", "require-labels", "prod", "nginx-2", v1alpha2.CriticalPriority), send) {
+			t.Error("Expected the result to be held outside of the window")
+		}
+		if schedule.Held() != 2 {
+			t.Errorf("Expected 2 held results, got %d", schedule.Held())
+		}
+	})
+}
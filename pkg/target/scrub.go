@@ -0,0 +1,106 @@
+package target
+
+import (
+	"regexp"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/expression"
+)
+
+// DefaultScrubReplacement replaces the matches of a ScrubRule without replacement
+const DefaultScrubReplacement = "[SCRUBBED]"
+
+// ScrubRule replaces the matches of the pattern in the message and properties of a result
+type ScrubRule struct {
+	Pattern *regexp.Regexp
+	// Replacement supports the capture groups of the pattern like $1, defaults to DefaultScrubReplacement
+	Replacement string
+	// Properties to scrub, empty scrubs all properties
+	Properties []string
+	// Condition restricts the rule to matching results, nil applies the rule to all results
+	Condition *expression.Expression
+}
+
+func (r ScrubRule) property(key string) bool {
+	if len(r.Properties) == 0 {
+		return true
+	}
+
+	for _, p := range r.Properties {
+		if p == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Scrubber removes identifiers and other sensitive values from results before they are sent to a target
+type Scrubber struct {
+	rules []ScrubRule
+}
+
+// Scrub returns a copy of the result with the rules applied, the result is returned unchanged by a nil Scrubber
+func (s *Scrubber) Scrub(result v1alpha2.PolicyReportResult) v1alpha2.PolicyReportResult {
+	if s == nil {
+		return result
+	}
+
+	properties := result.Properties
+	copied := false
+
+	for _, rule := range s.rules {
+		if rule.Condition != nil && !rule.Condition.Validate(nil, result) {
+			continue
+		}
+
+		result.Message = rule.Pattern.ReplaceAllString(result.Message, rule.Replacement)
+
+		for key, value := range properties {
+			if !rule.property(key) {
+				continue
+			}
+
+			scrubbed := rule.Pattern.ReplaceAllString(value, rule.Replacement)
+			if scrubbed == value {
+				continue
+			}
+
+			// properties are shared with the other targets, so the map is copied before the first change
+			if !copied {
+				properties = copyProperties(properties)
+				copied = true
+			}
+
+			properties[key] = scrubbed
+		}
+
+		result.Properties = properties
+	}
+
+	return result
+}
+
+func copyProperties(properties map[string]string) map[string]string {
+	copied := make(map[string]string, len(properties))
+	for k, v := range properties {
+		copied[k] = v
+	}
+
+	return copied
+}
+
+// NewScrubber creates a Scrubber of the rules, nil without rules
+func NewScrubber(rules []ScrubRule) *Scrubber {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	for i := range rules {
+		if rules[i].Replacement == "" {
+			rules[i].Replacement = DefaultScrubReplacement
+		}
+	}
+
+	return &Scrubber{rules: rules}
+}
@@ -0,0 +1,57 @@
+package target_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/expression"
+	"github.com/kyverno/policy-reporter/pkg/target"
+)
+
+func Test_Scrubber(t *testing.T) {
+	result := pod("1", "require-labels", "prod", "nginx-1", v1alpha2.WarningPriority)
+	result.Message = "secret db-password of user jane@example.com is mounted"
+	result.Properties = map[string]string{"team": "platform", "owner": "jane@example.com"}
+
+	t.Run("Without rules", func(t *testing.T) {
+		scrubber := target.NewScrubber(nil)
+		if scrubber != nil {
+			t.Fatal("Expected no scrubber without rules")
+		}
+		if scrubber.Scrub(result).Message != result.Message {
+			t.Error("Expected an unchanged result")
+		}
+	})
+
+	t.Run("Scrub message and properties", func(t *testing.T) {
+		scrubber := target.NewScrubber([]target.ScrubRule{
+			{Pattern: regexp.MustCompile(`[a-z]+@example\.com`)},
+			{Pattern: regexp.MustCompile(`secret ([a-z-]+)`), Replacement: "secret ***", Properties: []string{"team"}},
+		})
+
+		scrubbed := scrubber.Scrub(result)
+		if scrubbed.Message != "secret *** of user [SCRUBBED] is mounted" {
+			t.Errorf("Unexpected message: %s", scrubbed.Message)
+		}
+		if scrubbed.Properties["owner"] != target.DefaultScrubReplacement {
+			t.Errorf("Unexpected owner property: %s", scrubbed.Properties["owner"])
+		}
+		if result.Properties["owner"] != "jane@example.com" {
+			t.Error("Expected the properties of the original result to be unchanged")
+		}
+	})
+
+	t.Run("Condition", func(t *testing.T) {
+		condition, err := expression.Compile("result.policy == 'other-policy'")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		scrubber := target.NewScrubber([]target.ScrubRule{{Pattern: regexp.MustCompile(`[a-z]+@example\.com`), Condition: condition}})
+
+		if scrubbed := scrubber.Scrub(result); scrubbed.Message != result.Message {
+			t.Errorf("Expected the message of a not matching result to be unchanged, got %s", scrubbed.Message)
+		}
+	})
+}
@@ -0,0 +1,369 @@
+package securityhub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/securityhub"
+
+	"github.com/kyverno/policy-reporter/pkg/audit"
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/target"
+)
+
+var logger = logging.Component(logging.Targets)
+
+// SchemaVersion of the AWS Security Finding Format
+const SchemaVersion = "2018-10-08"
+
+// Limits of the ASFF fields
+const (
+	maxTitleLength       = 256
+	maxDescriptionLength = 1024
+	maxProductFields     = 50
+)
+
+// API of Security Hub used by the target
+type API interface {
+	BatchImportFindings(input *securityhub.BatchImportFindingsInput) (*securityhub.BatchImportFindingsOutput, error)
+	BatchUpdateFindings(input *securityhub.BatchUpdateFindingsInput) (*securityhub.BatchUpdateFindingsOutput, error)
+}
+
+// Options to configure the Security Hub target
+type Options struct {
+	target.ClientOptions
+	AccountID string
+	Region    string
+	// ProductARN of the findings, defaults to the default product of the account
+	ProductARN string
+	// ClusterARN adds the EKS cluster as AwsEksCluster resource to each finding
+	ClusterARN   string
+	CustomFields map[string]string
+	SecurityHub  API
+}
+
+// ProductARN of the default product of the account, used for findings imported by the account itself
+func ProductARN(region, accountID string) string {
+	return fmt.Sprintf("arn:aws:securityhub:%s:%s:product/%s/default", region, accountID, accountID)
+}
+
+// Severity label of the result, the priority is used for results without severity
+func Severity(result v1alpha2.PolicyReportResult) string {
+	switch result.Severity {
+	case v1alpha2.SeverityCritical:
+		return securityhub.SeverityLabelCritical
+	case v1alpha2.SeverityHigh:
+		return securityhub.SeverityLabelHigh
+	case v1alpha2.SeverityMedium:
+		return securityhub.SeverityLabelMedium
+	case v1alpha2.SeverityLow:
+		return securityhub.SeverityLabelLow
+	case v1alpha2.SeverityInfo:
+		return securityhub.SeverityLabelInformational
+	}
+
+	switch result.Priority {
+	case v1alpha2.CriticalPriority:
+		return securityhub.SeverityLabelCritical
+	case v1alpha2.ErrorPriority:
+		return securityhub.SeverityLabelHigh
+	case v1alpha2.WarningPriority:
+		return securityhub.SeverityLabelMedium
+	case v1alpha2.InfoPriority:
+		return securityhub.SeverityLabelLow
+	default:
+		return securityhub.SeverityLabelInformational
+	}
+}
+
+func compliance(result v1alpha2.PolicyReportResult) string {
+	switch result.Result {
+	case v1alpha2.StatusFail:
+		return securityhub.ComplianceStatusFailed
+	case v1alpha2.StatusWarn:
+		return securityhub.ComplianceStatusWarning
+	case v1alpha2.StatusPass:
+		return securityhub.ComplianceStatusPassed
+	default:
+		return securityhub.ComplianceStatusNotAvailable
+	}
+}
+
+func truncate(text string, length int) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+
+	return string(runes[:length-3]) + "..."
+}
+
+type client struct {
+	target.BaseClient
+	accountID    string
+	region       string
+	productARN   string
+	clusterARN   string
+	customFields map[string]string
+	api          API
+}
+
+func (c *client) finding(result v1alpha2.PolicyReportResult, resolved bool) *securityhub.AwsSecurityFinding {
+	created := time.Unix(result.Timestamp.Seconds, int64(result.Timestamp.Nanos)).UTC()
+	if result.Timestamp.Seconds == 0 {
+		created = time.Now().UTC()
+	}
+	updated := time.Now().UTC()
+	if updated.Before(created) {
+		updated = created
+	}
+
+	title := result.Policy
+	if result.Rule != "" {
+		title = result.Policy + ": " + result.Rule
+	}
+
+	description := result.Message
+	if description == "" {
+		description = title
+	}
+
+	source := result.Source
+	if source == "" {
+		source = "Policy Reporter"
+	}
+
+	finding := &securityhub.AwsSecurityFinding{
+		SchemaVersion: aws.String(SchemaVersion),
+		Id:            aws.String(result.GetID()),
+		ProductArn:    aws.String(c.productARN),
+		GeneratorId:   aws.String(strings.Join([]string{source, result.Policy, result.Rule}, "/")),
+		AwsAccountId:  aws.String(c.accountID),
+		Region:        aws.String(c.region),
+		Types:         aws.StringSlice([]string{"Software and Configuration Checks/Kubernetes Policies/" + source}),
+		CreatedAt:     aws.String(created.Format(time.RFC3339)),
+		UpdatedAt:     aws.String(updated.Format(time.RFC3339)),
+		Severity:      &securityhub.Severity{Label: aws.String(Severity(result))},
+		Title:         aws.String(truncate(title, maxTitleLength)),
+		Description:   aws.String(truncate(description, maxDescriptionLength)),
+		Compliance:    &securityhub.Compliance{Status: aws.String(compliance(result))},
+		RecordState:   aws.String(securityhub.RecordStateActive),
+		Resources:     c.resources(result),
+		ProductFields: c.productFields(result),
+	}
+
+	if result.Category != "" {
+		finding.Types = append(finding.Types, aws.String("Software and Configuration Checks/Kubernetes Policies/"+result.Category))
+	}
+	if url := result.Properties[v1alpha2.PolicyURLKey]; url != "" {
+		finding.SourceUrl = aws.String(url)
+	}
+
+	if resolved {
+		finding.Compliance.Status = aws.String(securityhub.ComplianceStatusPassed)
+		finding.RecordState = aws.String(securityhub.RecordStateArchived)
+	}
+
+	return finding
+}
+
+// resources of the finding, Kubernetes resources have no ASFF type and are mapped as Other resources
+func (c *client) resources(result v1alpha2.PolicyReportResult) []*securityhub.Resource {
+	resources := make([]*securityhub.Resource, 0, 2)
+
+	if result.HasResource() {
+		res := result.GetResource()
+
+		details := map[string]*string{
+			"apiVersion": aws.String(res.APIVersion),
+			"kind":       aws.String(res.Kind),
+			"name":       aws.String(res.Name),
+		}
+		if res.Namespace != "" {
+			details["namespace"] = aws.String(res.Namespace)
+		}
+		if res.UID != "" {
+			details["uid"] = aws.String(string(res.UID))
+		}
+
+		id := strings.Join([]string{res.Kind, res.Name}, "/")
+		if res.Namespace != "" {
+			id = strings.Join([]string{res.Namespace, res.Kind, res.Name}, "/")
+		}
+		if c.clusterARN != "" {
+			id = c.clusterARN + "/" + id
+		}
+
+		resources = append(resources, &securityhub.Resource{
+			Type:      aws.String("Other"),
+			Id:        aws.String(id),
+			Partition: aws.String(securityhub.PartitionAws),
+			Region:    aws.String(c.region),
+			Details:   &securityhub.ResourceDetails{Other: details},
+		})
+	}
+
+	if c.clusterARN != "" {
+		resources = append(resources, &securityhub.Resource{
+			Type:      aws.String("AwsEksCluster"),
+			Id:        aws.String(c.clusterARN),
+			Partition: aws.String(securityhub.PartitionAws),
+			Region:    aws.String(c.region),
+		})
+	}
+
+	if len(resources) == 0 {
+		resources = append(resources, &securityhub.Resource{
+			Type:      aws.String("Other"),
+			Id:        aws.String(result.Policy),
+			Partition: aws.String(securityhub.PartitionAws),
+			Region:    aws.String(c.region),
+		})
+	}
+
+	return resources
+}
+
+func (c *client) productFields(result v1alpha2.PolicyReportResult) map[string]*string {
+	fields := map[string]*string{
+		"policy-reporter/source": aws.String(result.Source),
+		"policy-reporter/policy": aws.String(result.Policy),
+	}
+	if result.Rule != "" {
+		fields["policy-reporter/rule"] = aws.String(result.Rule)
+	}
+
+	for property, value := range c.customFields {
+		if len(fields) >= maxProductFields {
+			break
+		}
+		fields[property] = aws.String(value)
+	}
+	for property, value := range result.Properties {
+		if len(fields) >= maxProductFields {
+			break
+		}
+		fields[property] = aws.String(value)
+	}
+
+	return fields
+}
+
+func (c *client) importFinding(finding *securityhub.AwsSecurityFinding) error {
+	output, err := c.api.BatchImportFindings(&securityhub.BatchImportFindingsInput{
+		Findings: []*securityhub.AwsSecurityFinding{finding},
+	})
+	if err != nil {
+		return err
+	}
+
+	if aws.Int64Value(output.FailedCount) > 0 && len(output.FailedFindings) > 0 {
+		failed := output.FailedFindings[0]
+		return fmt.Errorf("import of finding failed: %s %s", aws.StringValue(failed.ErrorCode), aws.StringValue(failed.ErrorMessage))
+	}
+
+	return nil
+}
+
+// Send imports the result as active finding
+func (c *client) Send(result v1alpha2.PolicyReportResult) {
+	if err := c.importFinding(c.finding(result, false)); err != nil {
+		c.failed(result, err)
+		return
+	}
+
+	c.succeeded(result)
+}
+
+// Resolve archives the finding of the result and sets its workflow status to RESOLVED
+func (c *client) Resolve(result v1alpha2.PolicyReportResult) {
+	if err := c.importFinding(c.finding(result, true)); err != nil {
+		c.failed(result, err)
+		return
+	}
+
+	output, err := c.api.BatchUpdateFindings(&securityhub.BatchUpdateFindingsInput{
+		FindingIdentifiers: []*securityhub.AwsSecurityFindingIdentifier{{
+			Id:         aws.String(result.GetID()),
+			ProductArn: aws.String(c.productARN),
+		}},
+		Workflow: &securityhub.WorkflowUpdate{Status: aws.String(securityhub.WorkflowStatusResolved)},
+		Note: &securityhub.NoteUpdate{
+			Text:      aws.String("The violation no longer exists in the PolicyReport"),
+			UpdatedBy: aws.String("policy-reporter"),
+		},
+	})
+	if err == nil && len(output.UnprocessedFindings) > 0 {
+		err = errors.New(aws.StringValue(output.UnprocessedFindings[0].ErrorMessage))
+	}
+	if err != nil {
+		c.failed(result, fmt.Errorf("update of workflow status failed: %w", err))
+		return
+	}
+
+	c.succeeded(result)
+}
+
+func (c *client) failed(result v1alpha2.PolicyReportResult, err error) {
+	logger.Errorf("%s PUSH failed: %s", c.Name(), err.Error())
+	audit.Delivery(c.Name(), result.GetID(), 0, err)
+}
+
+func (c *client) succeeded(result v1alpha2.PolicyReportResult) {
+	logger.Infof("%s PUSH OK", c.Name())
+	audit.Delivery(c.Name(), result.GetID(), 0, nil)
+}
+
+type dryRunAPI struct {
+	target string
+}
+
+func (d *dryRunAPI) BatchImportFindings(input *securityhub.BatchImportFindingsInput) (*securityhub.BatchImportFindingsOutput, error) {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("%s DRY RUN : BatchImportFindings payload: %s", d.target, body)
+
+	return &securityhub.BatchImportFindingsOutput{FailedCount: aws.Int64(0), SuccessCount: aws.Int64(int64(len(input.Findings)))}, nil
+}
+
+func (d *dryRunAPI) BatchUpdateFindings(input *securityhub.BatchUpdateFindingsInput) (*securityhub.BatchUpdateFindingsOutput, error) {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("%s DRY RUN : BatchUpdateFindings payload: %s", d.target, body)
+
+	return &securityhub.BatchUpdateFindingsOutput{}, nil
+}
+
+// NewDryRunAPI creates an API which logs the findings of the target instead of importing them
+func NewDryRunAPI(target string) API {
+	return &dryRunAPI{target: target}
+}
+
+// NewClient creates a new SecurityHub.client to import results as findings into AWS Security Hub
+func NewClient(options Options) target.ResolvingClient {
+	productARN := options.ProductARN
+	if productARN == "" {
+		productARN = ProductARN(options.Region, options.AccountID)
+	}
+
+	return &client{
+		target.NewBaseClient(options.ClientOptions),
+		options.AccountID,
+		options.Region,
+		productARN,
+		options.ClusterARN,
+		options.CustomFields,
+		options.SecurityHub,
+	}
+}
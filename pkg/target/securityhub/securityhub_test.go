@@ -0,0 +1,133 @@
+package securityhub_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sdk "github.com/aws/aws-sdk-go/service/securityhub"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/securityhub"
+)
+
+type apiClient struct {
+	imports []*sdk.AwsSecurityFinding
+	updates []*sdk.BatchUpdateFindingsInput
+	failed  bool
+}
+
+func (c *apiClient) BatchImportFindings(input *sdk.BatchImportFindingsInput) (*sdk.BatchImportFindingsOutput, error) {
+	c.imports = append(c.imports, input.Findings...)
+
+	if c.failed {
+		return &sdk.BatchImportFindingsOutput{
+			FailedCount:    aws.Int64(1),
+			FailedFindings: []*sdk.ImportFindingsError{{Id: input.Findings[0].Id, ErrorCode: aws.String("InvalidInput"), ErrorMessage: aws.String("invalid")}},
+		}, nil
+	}
+
+	return &sdk.BatchImportFindingsOutput{FailedCount: aws.Int64(0), SuccessCount: aws.Int64(1)}, nil
+}
+
+func (c *apiClient) BatchUpdateFindings(input *sdk.BatchUpdateFindingsInput) (*sdk.BatchUpdateFindingsOutput, error) {
+	c.updates = append(c.updates, input)
+
+	return &sdk.BatchUpdateFindingsOutput{}, nil
+}
+
+func Test_SecurityHubTarget(t *testing.T) {
+	options := func(api securityhub.API) securityhub.Options {
+		return securityhub.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "SecurityHub",
+			},
+			AccountID:    "123456789012",
+			Region:       "eu-central-1",
+			ClusterARN:   "arn:aws:eks:eu-central-1:123456789012:cluster/prod",
+			CustomFields: map[string]string{"cluster": "prod"},
+			SecurityHub:  api,
+		}
+	}
+
+	t.Run("Send Complete Result", func(t *testing.T) {
+		api := &apiClient{}
+
+		client := securityhub.NewClient(options(api))
+		client.Send(fixtures.CompleteTargetSendResult)
+
+		if len(api.imports) != 1 {
+			t.Fatalf("Expected one imported finding, got %d", len(api.imports))
+		}
+
+		finding := api.imports[0]
+		if arn := aws.StringValue(finding.ProductArn); arn != "arn:aws:securityhub:eu-central-1:123456789012:product/123456789012/default" {
+			t.Errorf("Unexpected ProductArn: %s", arn)
+		}
+		if id := aws.StringValue(finding.Id); id != fixtures.CompleteTargetSendResult.GetID() {
+			t.Errorf("Unexpected Id: %s", id)
+		}
+		if label := aws.StringValue(finding.Severity.Label); label != sdk.SeverityLabelHigh {
+			t.Errorf("Unexpected Severity: %s", label)
+		}
+		if status := aws.StringValue(finding.Compliance.Status); status != sdk.ComplianceStatusFailed {
+			t.Errorf("Unexpected Compliance: %s", status)
+		}
+		if len(finding.Resources) != 2 {
+			t.Fatalf("Expected resource and cluster, got %d resources", len(finding.Resources))
+		}
+		if id := aws.StringValue(finding.Resources[0].Id); id != "arn:aws:eks:eu-central-1:123456789012:cluster/prod/default/Deployment/nginx" {
+			t.Errorf("Unexpected resource Id: %s", id)
+		}
+		if typ := aws.StringValue(finding.Resources[1].Type); typ != "AwsEksCluster" {
+			t.Errorf("Unexpected resource Type: %s", typ)
+		}
+		if aws.StringValue(finding.ProductFields["cluster"]) != "prod" {
+			t.Error("Expected custom field in the product fields")
+		}
+	})
+
+	t.Run("Severity from Priority", func(t *testing.T) {
+		result := v1alpha2.PolicyReportResult{Priority: v1alpha2.CriticalPriority}
+		if label := securityhub.Severity(result); label != sdk.SeverityLabelCritical {
+			t.Errorf("Unexpected Severity: %s", label)
+		}
+	})
+
+	t.Run("Resolve", func(t *testing.T) {
+		api := &apiClient{}
+
+		client := securityhub.NewClient(options(api))
+		client.Resolve(fixtures.CompleteTargetSendResult)
+
+		if len(api.imports) != 1 || aws.StringValue(api.imports[0].RecordState) != sdk.RecordStateArchived {
+			t.Fatal("Expected archived finding")
+		}
+		if status := aws.StringValue(api.imports[0].Compliance.Status); status != sdk.ComplianceStatusPassed {
+			t.Errorf("Unexpected Compliance: %s", status)
+		}
+		if len(api.updates) != 1 || aws.StringValue(api.updates[0].Workflow.Status) != sdk.WorkflowStatusResolved {
+			t.Error("Expected workflow status RESOLVED")
+		}
+	})
+
+	t.Run("Failed Import", func(t *testing.T) {
+		api := &apiClient{failed: true}
+
+		client := securityhub.NewClient(options(api))
+		client.Resolve(fixtures.CompleteTargetSendResult)
+
+		if len(api.updates) != 0 {
+			t.Error("Expected no workflow update after a failed import")
+		}
+	})
+
+	t.Run("Name", func(t *testing.T) {
+		client := securityhub.NewClient(options(&apiClient{}))
+
+		if client.Name() != "SecurityHub" {
+			t.Errorf("Unexpected Name %s", client.Name())
+		}
+	})
+}
@@ -1,30 +1,63 @@
 package slack
 
 import (
+	"encoding/json"
+	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/secret"
+	"github.com/kyverno/policy-reporter/pkg/severity"
 	"github.com/kyverno/policy-reporter/pkg/target"
 	"github.com/kyverno/policy-reporter/pkg/target/http"
+	"github.com/kyverno/policy-reporter/pkg/tracing"
 )
 
 // Options to configure the Slack target
 type Options struct {
 	target.ClientOptions
-	Webhook      string
+	Webhook      secret.String
 	CustomFields map[string]string
+	Remediation  *target.RemediationTemplate
 	HTTPClient   http.Client
+	// Title and Message templates, the defaults are used if nil
+	Title   *target.Template
+	Message *target.Template
+	// Actions are rendered as buttons, e.g. linking to the Policy Reporter UI or the resource
+	Actions []target.Action
+	// Token and Channel send the messages with the Slack Web API instead of the Webhook,
+	// results of the same policy are grouped in a thread
+	Token   secret.String
+	Channel string
+	// ThreadTTL starts a new thread for a policy after the duration, 0 keeps the thread
+	ThreadTTL time.Duration
 }
 
+// PostMessageURL of the Slack Web API
+const PostMessageURL = "https://slack.com/api/chat.postMessage"
+
+// DefaultTitle of the message header
+const DefaultTitle = "New Policy Report Result"
+
 type text struct {
 	Type string `json:"type"`
 	Text string `json:"text"`
 }
 
 type block struct {
-	Type   string  `json:"type"`
-	Text   *text   `json:"text,omitempty"`
-	Fields []field `json:"fields,omitempty"`
+	Type     string    `json:"type"`
+	Text     *text     `json:"text,omitempty"`
+	Fields   []field   `json:"fields,omitempty"`
+	Elements []element `json:"elements,omitempty"`
+}
+
+type element struct {
+	Type  string `json:"type"`
+	Text  *text  `json:"text,omitempty"`
+	URL   string `json:"url,omitempty"`
+	Style string `json:"style,omitempty"`
 }
 
 type field struct {
@@ -38,15 +71,46 @@ type attachment struct {
 }
 
 type payload struct {
+	Channel     string       `json:"channel,omitempty"`
+	ThreadTS    string       `json:"thread_ts,omitempty"`
+	Text        string       `json:"text,omitempty"`
 	Username    string       `json:"username,omitempty"`
 	Attachments []attachment `json:"attachments,omitempty"`
 }
 
+type apiResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+	TS    string `json:"ts"`
+}
+
+type thread struct {
+	ts      string
+	started time.Time
+}
+
 type client struct {
 	target.BaseClient
 	webhook      string
 	client       http.Client
 	customFields map[string]string
+	remediation  *target.RemediationTemplate
+	title        *target.Template
+	message      *target.Template
+	actions      []target.Action
+	token        string
+	channel      string
+	threadTTL    time.Duration
+	threads      map[string]thread
+	mx           *sync.Mutex
+}
+
+var severityColors = map[v1alpha2.PolicySeverity]string{
+	v1alpha2.SeverityInfo:     "#68c2ff",
+	v1alpha2.SeverityLow:      "#36a64f",
+	v1alpha2.SeverityMedium:   "#f2c744",
+	v1alpha2.SeverityHigh:     "#e20b0b",
+	v1alpha2.SeverityCritical: "#b80707",
 }
 
 var colors = map[v1alpha2.Priority]string{
@@ -58,12 +122,23 @@ var colors = map[v1alpha2.Priority]string{
 }
 
 func (s *client) newPayload(result v1alpha2.PolicyReportResult) payload {
+	title := s.title.Render(result, DefaultTitle)
+
 	p := payload{
+		Text:        title + ": " + result.Policy,
 		Attachments: make([]attachment, 0, 1),
 	}
 
+	color, ok := severityColors[result.Severity]
+	if !ok {
+		color, ok = severity.Color(string(result.Severity))
+	}
+	if !ok {
+		color = colors[result.Priority]
+	}
+
 	att := attachment{
-		Color:  colors[result.Priority],
+		Color:  color,
 		Blocks: make([]block, 0),
 	}
 
@@ -78,13 +153,13 @@ func (s *client) newPayload(result v1alpha2.PolicyReportResult) payload {
 
 	att.Blocks = append(
 		att.Blocks,
-		block{Type: "header", Text: &text{Type: "plain_text", Text: "New Policy Report Result"}},
+		block{Type: "header", Text: &text{Type: "plain_text", Text: title}},
 		policyBlock,
 	)
 
 	att.Blocks = append(
 		att.Blocks,
-		block{Type: "section", Text: &text{Type: "mrkdwn", Text: "*Message*\n" + result.Message}},
+		block{Type: "section", Text: &text{Type: "mrkdwn", Text: "*Message*\n" + s.message.Render(result, result.Message)}},
 		block{
 			Type: "section",
 			Fields: []field{
@@ -110,6 +185,16 @@ func (s *client) newPayload(result v1alpha2.PolicyReportResult) payload {
 		att.Blocks = append(att.Blocks, b)
 	}
 
+	if description := result.Properties[v1alpha2.PolicyDescriptionKey]; description != "" {
+		att.Blocks = append(att.Blocks, block{Type: "section", Text: &text{Type: "mrkdwn", Text: "*Description*\n" + description}})
+	}
+	if remediation := s.remediation.Render(result); remediation != "" {
+		att.Blocks = append(att.Blocks, block{Type: "section", Text: &text{Type: "mrkdwn", Text: "*How to fix*\n" + remediation}})
+	}
+	if url := result.Properties[v1alpha2.PolicyURLKey]; url != "" {
+		att.Blocks = append(att.Blocks, block{Type: "section", Text: &text{Type: "mrkdwn", Text: "*Documentation*\n<" + url + ">"}})
+	}
+
 	if result.HasResource() {
 		res := result.GetResource()
 
@@ -160,25 +245,44 @@ func (s *client) newPayload(result v1alpha2.PolicyReportResult) payload {
 		}
 	}
 
-	if len(result.Properties) > 0 || len(s.customFields) > 0 {
+	propBlock := block{
+		Type:   "section",
+		Fields: []field{},
+	}
+
+	for property, value := range result.Properties {
+		if v1alpha2.IsPolicyGuidanceKey(property) {
+			continue
+		}
+		propBlock.Fields = append(propBlock.Fields, field{Type: "mrkdwn", Text: "*" + strings.Title(property) + "*\n" + value})
+	}
+	for property, value := range s.customFields {
+		propBlock.Fields = append(propBlock.Fields, field{Type: "mrkdwn", Text: "*" + strings.Title(property) + "*\n" + value})
+	}
+
+	if len(propBlock.Fields) > 0 {
 		att.Blocks = append(
 			att.Blocks,
 			block{Type: "section", Text: &text{Type: "mrkdwn", Text: "*Properties*"}},
+			propBlock,
 		)
+	}
 
-		propBlock := block{
-			Type:   "section",
-			Fields: []field{},
+	buttons := block{Type: "actions", Elements: make([]element, 0, len(s.actions)+1)}
+	if url := result.Properties[v1alpha2.ResultURLKey]; url != "" {
+		buttons.Elements = append(buttons.Elements, element{Type: "button", Text: &text{Type: "plain_text", Text: "View in Policy Reporter"}, URL: url})
+	}
+	for _, action := range s.actions {
+		url := action.URL.Render(result, "")
+		if url == "" {
+			continue
 		}
 
-		for property, value := range result.Properties {
-			propBlock.Fields = append(propBlock.Fields, field{Type: "mrkdwn", Text: "*" + strings.Title(property) + "*\n" + value})
-		}
-		for property, value := range s.customFields {
-			propBlock.Fields = append(propBlock.Fields, field{Type: "mrkdwn", Text: "*" + strings.Title(property) + "*\n" + value})
-		}
+		buttons.Elements = append(buttons.Elements, element{Type: "button", Text: &text{Type: "plain_text", Text: action.Text}, URL: url})
+	}
 
-		att.Blocks = append(att.Blocks, propBlock)
+	if len(buttons.Elements) > 0 {
+		att.Blocks = append(att.Blocks, block{Type: "divider"}, buttons)
 	}
 
 	p.Attachments = append(p.Attachments, att)
@@ -187,21 +291,91 @@ func (s *client) newPayload(result v1alpha2.PolicyReportResult) payload {
 }
 
 func (s *client) Send(result v1alpha2.PolicyReportResult) {
+	if s.token != "" {
+		s.post(result)
+		return
+	}
+
 	req, err := http.CreateJSONRequest(s.Name(), "POST", s.webhook, s.newPayload(result))
 	if err != nil {
 		return
 	}
 
+	tracing.Inject(req, result.TraceParent)
+
 	resp, err := s.client.Do(req)
-	http.ProcessHTTPResponse(s.Name(), resp, err)
+	http.ProcessHTTPResponse(s.Name(), result.GetID(), resp, err)
+}
+
+// post sends the result with the Web API as reply to the thread of its policy, the first result starts the thread
+func (s *client) post(result v1alpha2.PolicyReportResult) {
+	p := s.newPayload(result)
+	p.Channel = s.channel
+	p.ThreadTS = s.thread(result.Policy)
+
+	req, err := http.CreateJSONRequest(s.Name(), "POST", PostMessageURL, p)
+	if err != nil {
+		return
+	}
+
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	tracing.Inject(req, result.TraceParent)
+
+	resp, err := s.client.Do(req)
+	if err != nil || resp.StatusCode >= 400 {
+		http.ProcessHTTPResponse(s.Name(), result.GetID(), resp, err)
+		return
+	}
+
+	// the Web API responds with 200 and the error in the body
+	body := apiResponse{}
+	if resp.Body != nil {
+		json.NewDecoder(resp.Body).Decode(&body)
+	}
+
+	if !body.OK {
+		http.ProcessHTTPResponse(s.Name(), result.GetID(), resp, fmt.Errorf("slack api error: %s", body.Error))
+		return
+	}
+
+	http.ProcessHTTPResponse(s.Name(), result.GetID(), resp, nil)
+
+	if p.ThreadTS == "" && body.TS != "" {
+		s.mx.Lock()
+		s.threads[result.Policy] = thread{ts: body.TS, started: time.Now()}
+		s.mx.Unlock()
+	}
+}
+
+// thread returns the timestamp of the current thread of the policy, empty if a new thread has to be started
+func (s *client) thread(policy string) string {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	t, ok := s.threads[policy]
+	if !ok || (s.threadTTL > 0 && time.Since(t.started) > s.threadTTL) {
+		delete(s.threads, policy)
+		return ""
+	}
+
+	return t.ts
 }
 
 // NewClient creates a new slack.client to send Results to Slack
 func NewClient(options Options) target.Client {
 	return &client{
-		target.NewBaseClient(options.ClientOptions),
-		options.Webhook,
-		options.HTTPClient,
-		options.CustomFields,
+		BaseClient:   target.NewBaseClient(options.ClientOptions),
+		webhook:      options.Webhook.Value(),
+		client:       options.HTTPClient,
+		customFields: options.CustomFields,
+		remediation:  options.Remediation,
+		title:        options.Title,
+		message:      options.Message,
+		actions:      options.Actions,
+		token:        options.Token.Value(),
+		channel:      options.Channel,
+		threadTTL:    options.ThreadTTL,
+		threads:      make(map[string]thread),
+		mx:           new(sync.Mutex),
 	}
 }
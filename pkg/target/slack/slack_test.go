@@ -1,7 +1,10 @@
 package slack_test
 
 import (
+	"encoding/json"
+	"io"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/kyverno/policy-reporter/pkg/fixtures"
@@ -22,6 +25,17 @@ func (c testClient) Do(req *http.Request) (*http.Response, error) {
 	}, nil
 }
 
+type apiClient func(req *http.Request)
+
+func (c apiClient) Do(req *http.Request) (*http.Response, error) {
+	c(req)
+
+	return &http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(strings.NewReader(`{"ok":true,"ts":"1700000000.000100"}`)),
+	}, nil
+}
+
 func Test_SlackTarget(t *testing.T) {
 	t.Run("Send Complete Result", func(t *testing.T) {
 		callback := func(req *http.Request) {
@@ -151,6 +165,85 @@ func Test_SlackTarget(t *testing.T) {
 		client.Send(fixtures.MissingAPIVersionSendResult)
 	})
 
+	t.Run("Send Block Kit Result", func(t *testing.T) {
+		title, _ := target.ParseTemplate("title", "{{ .Policy }} failed")
+		message, _ := target.ParseTemplate("message", "{{ .Resource.Kind }}/{{ .Resource.Name }}: {{ .Message }}")
+		resource, _ := target.ParseTemplate("url", "https://console.example.com/{{ with .Resource }}{{ .Namespace }}/{{ .Name }}{{ end }}")
+		missing, _ := target.ParseTemplate("url", "{{ .Properties.missing }}")
+
+		callback := func(req *http.Request) {
+			body, _ := io.ReadAll(req.Body)
+			content := string(body)
+
+			for _, expected := range []string{
+				`"color":"#e20b0b"`,
+				`"text":"require-requests-and-limits-required failed"`,
+				`Deployment/nginx: validation error`,
+				`"type":"actions"`,
+				`"url":"https://console.example.com/default/nginx"`,
+			} {
+				if !strings.Contains(content, expected) {
+					t.Errorf("Expected %s in payload: %s", expected, content)
+				}
+			}
+			if strings.Contains(content, "Missing") {
+				t.Errorf("Expected the action with empty URL to be omitted: %s", content)
+			}
+		}
+
+		client := slack.NewClient(slack.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Slack",
+			},
+			Webhook:    "http://hook.slack:80",
+			HTTPClient: testClient{callback, 200},
+			Title:      title,
+			Message:    message,
+			Actions:    []target.Action{{Text: "View Resource", URL: resource}, {Text: "Missing", URL: missing}},
+		})
+		client.Send(fixtures.CompleteTargetSendResult)
+	})
+
+	t.Run("Group Results of a Policy in a Thread", func(t *testing.T) {
+		threads := make([]string, 0, 3)
+
+		client := slack.NewClient(slack.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Slack",
+			},
+			Token:   "xoxb-token",
+			Channel: "C123",
+			HTTPClient: apiClient(func(req *http.Request) {
+				if url := req.URL.String(); url != slack.PostMessageURL {
+					t.Errorf("Unexpected Host: %s", url)
+				}
+				if auth := req.Header.Get("Authorization"); auth != "Bearer xoxb-token" {
+					t.Errorf("Unexpected Authorization: %s", auth)
+				}
+
+				payload := struct {
+					Channel  string `json:"channel"`
+					ThreadTS string `json:"thread_ts"`
+				}{}
+				json.NewDecoder(req.Body).Decode(&payload)
+
+				if payload.Channel != "C123" {
+					t.Errorf("Unexpected Channel: %s", payload.Channel)
+				}
+
+				threads = append(threads, payload.ThreadTS)
+			}),
+		})
+
+		client.Send(fixtures.CompleteTargetSendResult)
+		client.Send(fixtures.CompleteTargetSendResult)
+		client.Send(fixtures.MinimalTargetSendResult)
+
+		if threads[0] != "" || threads[1] != "1700000000.000100" || threads[2] != "" {
+			t.Errorf("Expected the second result of the policy to be sent to the thread, got %v", threads)
+		}
+	})
+
 	t.Run("Name", func(t *testing.T) {
 		client := slack.NewClient(slack.Options{
 			ClientOptions: target.ClientOptions{
@@ -0,0 +1,99 @@
+package teams
+
+import (
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/timeformat"
+)
+
+type adaptiveFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type adaptiveElement struct {
+	Type     string         `json:"type"`
+	Text     string         `json:"text,omitempty"`
+	Size     string         `json:"size,omitempty"`
+	Weight   string         `json:"weight,omitempty"`
+	Color    string         `json:"color,omitempty"`
+	IsSubtle bool           `json:"isSubtle,omitempty"`
+	Wrap     bool           `json:"wrap,omitempty"`
+	Facts    []adaptiveFact `json:"facts,omitempty"`
+}
+
+type adaptiveAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+type adaptiveCard struct {
+	Schema  string            `json:"$schema"`
+	Type    string            `json:"type"`
+	Version string            `json:"version"`
+	Body    []adaptiveElement `json:"body"`
+	Actions []adaptiveAction  `json:"actions,omitempty"`
+}
+
+type adaptiveAttachment struct {
+	ContentType string       `json:"contentType"`
+	Content     adaptiveCard `json:"content"`
+}
+
+type adaptivePayload struct {
+	Type        string               `json:"type"`
+	Attachments []adaptiveAttachment `json:"attachments"`
+}
+
+// adaptiveColors are the text colors of the Adaptive Card host config
+var adaptiveColors = map[v1alpha2.Priority]string{
+	v1alpha2.DebugPriority:    "accent",
+	v1alpha2.InfoPriority:     "good",
+	v1alpha2.WarningPriority:  "warning",
+	v1alpha2.CriticalPriority: "attention",
+	v1alpha2.ErrorPriority:    "attention",
+}
+
+func newAdaptivePayload(result v1alpha2.PolicyReportResult, customFields map[string]string, remediation string, actions []target.Action) adaptivePayload {
+	facts := newFacts(result, customFields, remediation)
+
+	factSet := make([]adaptiveFact, 0, len(facts))
+	for _, f := range facts {
+		factSet = append(factSet, adaptiveFact{Title: f.Name, Value: f.Value})
+	}
+
+	card := adaptiveCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body: []adaptiveElement{
+			{Type: "TextBlock", Text: "New Policy Report Result", Size: "Large", Weight: "Bolder", Color: adaptiveColors[result.Priority], Wrap: true},
+			{Type: "TextBlock", Text: timeformat.FormatResult(result.Timestamp), IsSubtle: true},
+			{Type: "TextBlock", Text: result.Message, Wrap: true},
+			{Type: "FactSet", Facts: factSet},
+		},
+		Actions: make([]adaptiveAction, 0, len(actions)+2),
+	}
+
+	if url := result.Properties[v1alpha2.PolicyURLKey]; url != "" {
+		card.Actions = append(card.Actions, adaptiveAction{Type: "Action.OpenUrl", Title: "Documentation", URL: url})
+	}
+	if url := result.Properties[v1alpha2.ResultURLKey]; url != "" {
+		card.Actions = append(card.Actions, adaptiveAction{Type: "Action.OpenUrl", Title: "View in Policy Reporter", URL: url})
+	}
+
+	for _, action := range actions {
+		if url := action.URL.Render(result, ""); url != "" {
+			card.Actions = append(card.Actions, adaptiveAction{Type: "Action.OpenUrl", Title: action.Text, URL: url})
+		}
+	}
+
+	return adaptivePayload{
+		Type: "message",
+		Attachments: []adaptiveAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content:     card,
+		}},
+	}
+}
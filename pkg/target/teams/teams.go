@@ -2,19 +2,34 @@ package teams
 
 import (
 	"strings"
-	"time"
 
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/secret"
 	"github.com/kyverno/policy-reporter/pkg/target"
 	"github.com/kyverno/policy-reporter/pkg/target/http"
+	"github.com/kyverno/policy-reporter/pkg/timeformat"
+	"github.com/kyverno/policy-reporter/pkg/tracing"
 )
 
-// Options to configure the Slack target
+// Payload formats
+const (
+	// FormatMessageCard is the legacy format of Office 365 connectors
+	FormatMessageCard = "messageCard"
+	// FormatAdaptiveCard is the format of Power Automate workflow webhooks
+	FormatAdaptiveCard = "adaptiveCard"
+)
+
+// Options to configure the Teams target
 type Options struct {
 	target.ClientOptions
-	Webhook      string
+	Webhook      secret.String
 	CustomFields map[string]string
+	Remediation  *target.RemediationTemplate
 	HTTPClient   http.Client
+	// Format of the payload, defaults to FormatMessageCard
+	Format string
+	// Actions are rendered as links of Adaptive Cards
+	Actions []target.Action
 }
 
 type fact struct {
@@ -45,7 +60,7 @@ var colors = map[v1alpha2.Priority]string{
 	v1alpha2.ErrorPriority:    "e20b0b",
 }
 
-func newPayload(result v1alpha2.PolicyReportResult, customFields map[string]string) payload {
+func newFacts(result v1alpha2.PolicyReportResult, customFields map[string]string, remediation string) []fact {
 	facts := make([]fact, 0)
 
 	facts = append(facts, fact{"Policy", result.Policy})
@@ -79,24 +94,39 @@ func newPayload(result v1alpha2.PolicyReportResult, customFields map[string]stri
 		}
 	}
 
+	if description := result.Properties[v1alpha2.PolicyDescriptionKey]; description != "" {
+		facts = append(facts, fact{"Description", description})
+	}
+	if remediation != "" {
+		facts = append(facts, fact{"How to fix", remediation})
+	}
+	if url := result.Properties[v1alpha2.PolicyURLKey]; url != "" {
+		facts = append(facts, fact{"Documentation", "[" + url + "](" + url + ")"})
+	}
+	if url := result.Properties[v1alpha2.ResultURLKey]; url != "" {
+		facts = append(facts, fact{"Details", "[View in Policy Reporter](" + url + ")"})
+	}
+
 	for property, value := range result.Properties {
+		if v1alpha2.IsPolicyGuidanceKey(property) {
+			continue
+		}
 		facts = append(facts, fact{strings.Title(property), value})
 	}
 	for property, value := range customFields {
 		facts = append(facts, fact{strings.Title(property), value})
 	}
 
-	timestamp := time.Now()
-	if result.Timestamp.Seconds == 0 {
-		timestamp = time.Unix(result.Timestamp.Seconds, int64(result.Timestamp.Nanos))
-	}
+	return facts
+}
 
+func newPayload(result v1alpha2.PolicyReportResult, customFields map[string]string, remediation string) payload {
 	sections := make([]section, 0, 1)
 	sections = append(sections, section{
 		Title:    "New Policy Report Result",
-		SubTitle: timestamp.Format(time.RFC3339),
+		SubTitle: timeformat.FormatResult(result.Timestamp),
 		Text:     result.Message,
-		Facts:    facts,
+		Facts:    newFacts(result, customFields, remediation),
 	})
 
 	return payload{
@@ -112,25 +142,40 @@ type client struct {
 	target.BaseClient
 	webhook      string
 	customFields map[string]string
+	remediation  *target.RemediationTemplate
 	client       http.Client
+	format       string
+	actions      []target.Action
 }
 
 func (s *client) Send(result v1alpha2.PolicyReportResult) {
-	req, err := http.CreateJSONRequest(s.Name(), "POST", s.webhook, newPayload(result, s.customFields))
+	var body interface{}
+	if s.format == FormatAdaptiveCard {
+		body = newAdaptivePayload(result, s.customFields, s.remediation.Render(result), s.actions)
+	} else {
+		body = newPayload(result, s.customFields, s.remediation.Render(result))
+	}
+
+	req, err := http.CreateJSONRequest(s.Name(), "POST", s.webhook, body)
 	if err != nil {
 		return
 	}
 
+	tracing.Inject(req, result.TraceParent)
+
 	resp, err := s.client.Do(req)
-	http.ProcessHTTPResponse(s.Name(), resp, err)
+	http.ProcessHTTPResponse(s.Name(), result.GetID(), resp, err)
 }
 
 // NewClient creates a new teams.client to send Results to MS Teams
 func NewClient(options Options) target.Client {
 	return &client{
 		target.NewBaseClient(options.ClientOptions),
-		options.Webhook,
+		options.Webhook.Value(),
 		options.CustomFields,
+		options.Remediation,
 		options.HTTPClient,
+		options.Format,
+		options.Actions,
 	}
 }
@@ -181,6 +181,67 @@ func Test_TeamsTarget(t *testing.T) {
 		})
 		client.Send(fixtures.DebugSendResult)
 	})
+	t.Run("Send Adaptive Card", func(t *testing.T) {
+		resource, _ := target.ParseTemplate("url", "https://console.example.com/{{ with .Resource }}{{ .Namespace }}/{{ .Name }}{{ end }}")
+
+		callback := func(req *http.Request) {
+			payload := struct {
+				Type        string `json:"type"`
+				Attachments []struct {
+					ContentType string `json:"contentType"`
+					Content     struct {
+						Type string `json:"type"`
+						Body []struct {
+							Type  string `json:"type"`
+							Color string `json:"color"`
+							Facts []struct {
+								Title string `json:"title"`
+								Value string `json:"value"`
+							} `json:"facts"`
+						} `json:"body"`
+						Actions []struct {
+							Type  string `json:"type"`
+							Title string `json:"title"`
+							URL   string `json:"url"`
+						} `json:"actions"`
+					} `json:"content"`
+				} `json:"attachments"`
+			}{}
+
+			if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+				t.Fatal(err)
+			}
+
+			if payload.Type != "message" || len(payload.Attachments) != 1 {
+				t.Fatalf("Unexpected payload %+v", payload)
+			}
+
+			card := payload.Attachments[0]
+			if card.ContentType != "application/vnd.microsoft.card.adaptive" || card.Content.Type != "AdaptiveCard" {
+				t.Errorf("Unexpected card %s %s", card.ContentType, card.Content.Type)
+			}
+			if card.Content.Body[0].Color != "warning" {
+				t.Errorf("Unexpected title color %s", card.Content.Body[0].Color)
+			}
+			if facts := card.Content.Body[3].Facts; len(facts) == 0 || facts[0].Title != "Policy" || facts[0].Value != fixtures.CompleteTargetSendResult.Policy {
+				t.Errorf("Unexpected facts %+v", facts)
+			}
+			if actions := card.Content.Actions; len(actions) != 1 || actions[0].Type != "Action.OpenUrl" || actions[0].URL != "https://console.example.com/default/nginx" {
+				t.Errorf("Unexpected actions %+v", actions)
+			}
+		}
+
+		client := teams.NewClient(teams.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Teams",
+			},
+			Webhook:    "http://hook.teams:80",
+			HTTPClient: testClient{callback, 200},
+			Format:     teams.FormatAdaptiveCard,
+			Actions:    []target.Action{{Text: "View Resource", URL: resource}},
+		})
+		client.Send(fixtures.CompleteTargetSendResult)
+	})
 	t.Run("Name", func(t *testing.T) {
 		client := teams.NewClient(teams.Options{
 			ClientOptions: target.ClientOptions{
@@ -0,0 +1,169 @@
+package telegram
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/secret"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/http"
+	"github.com/kyverno/policy-reporter/pkg/tracing"
+)
+
+// DefaultHost of the Telegram Bot API
+const DefaultHost = "https://api.telegram.org"
+
+// maxMessageLength of the result message, Telegram rejects texts with more than 4096 characters
+const maxMessageLength = 3000
+
+// Options to configure the Telegram target
+type Options struct {
+	target.ClientOptions
+	// Host of the Bot API, defaults to DefaultHost
+	Host         string
+	Token        secret.String
+	ChatID       string
+	CustomFields map[string]string
+	Remediation  *target.RemediationTemplate
+	HTTPClient   http.Client
+}
+
+type payload struct {
+	ChatID                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	ParseMode             string `json:"parse_mode"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+}
+
+var replacer = strings.NewReplacer(
+	`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`, "~", `\~`, "`", "\\`",
+	">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`, "|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+)
+
+// Escape the text for the MarkdownV2 parse mode
+func Escape(text string) string {
+	return replacer.Replace(text)
+}
+
+func line(name, value string) string {
+	return "*" + Escape(name) + ":* " + Escape(value)
+}
+
+func newText(result v1alpha2.PolicyReportResult, customFields map[string]string, remediation string) string {
+	lines := []string{"*New Policy Report Result*", "", line("Policy", result.Policy)}
+
+	if result.Rule != "" {
+		lines = append(lines, line("Rule", result.Rule))
+	}
+
+	lines = append(lines, line("Status", string(result.Result)), line("Priority", result.Priority.String()))
+
+	if result.Severity != "" {
+		lines = append(lines, line("Severity", string(result.Severity)))
+	}
+	if result.Category != "" {
+		lines = append(lines, line("Category", result.Category))
+	}
+
+	if result.HasResource() {
+		res := result.GetResource()
+
+		name := res.Name
+		if res.Namespace != "" {
+			name = res.Namespace + "/" + res.Name
+		}
+
+		lines = append(lines, line("Resource", res.Kind+" "+name))
+	}
+
+	message := []rune(result.Message)
+	if len(message) > maxMessageLength {
+		message = append(message[:maxMessageLength], []rune("...")...)
+	}
+
+	lines = append(lines, "", "*Message*", Escape(string(message)))
+
+	if description := result.Properties[v1alpha2.PolicyDescriptionKey]; description != "" {
+		lines = append(lines, "", "*Description*", Escape(description))
+	}
+	if remediation != "" {
+		lines = append(lines, "", "*How to fix*", Escape(remediation))
+	}
+	if url := result.Properties[v1alpha2.PolicyURLKey]; url != "" {
+		lines = append(lines, "", "[Documentation]("+strings.NewReplacer(`\`, `\\`, ")", `\)`).Replace(url)+")")
+	}
+	if url := result.Properties[v1alpha2.ResultURLKey]; url != "" {
+		lines = append(lines, "", "[View in Policy Reporter]("+strings.NewReplacer(`\`, `\\`, ")", `\)`).Replace(url)+")")
+	}
+
+	properties := make([]string, 0, len(result.Properties)+len(customFields))
+	for property, value := range result.Properties {
+		if v1alpha2.IsPolicyGuidanceKey(property) {
+			continue
+		}
+		properties = append(properties, line(strings.Title(property), value))
+	}
+	for property, value := range customFields {
+		properties = append(properties, line(strings.Title(property), value))
+	}
+
+	if len(properties) > 0 {
+		sort.Strings(properties)
+		lines = append(lines, "")
+		lines = append(lines, properties...)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+type client struct {
+	target.BaseClient
+	host         string
+	token        string
+	chatID       string
+	customFields map[string]string
+	remediation  *target.RemediationTemplate
+	client       http.Client
+}
+
+func (c *client) Send(result v1alpha2.PolicyReportResult) {
+	req, err := http.CreateJSONRequest(c.Name(), "POST", c.host+"/bot"+c.token+"/sendMessage", payload{
+		ChatID:                c.chatID,
+		Text:                  newText(result, c.customFields, c.remediation.Render(result)),
+		ParseMode:             "MarkdownV2",
+		DisableWebPagePreview: true,
+	})
+	if err != nil {
+		return
+	}
+
+	tracing.Inject(req, result.TraceParent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// the request URL contains the bot token
+		err = errors.New(strings.ReplaceAll(err.Error(), c.token, secret.Redacted))
+	}
+
+	http.ProcessHTTPResponse(c.Name(), result.GetID(), resp, err)
+}
+
+// NewClient creates a new telegram.client to send Results to a Telegram chat
+func NewClient(options Options) target.Client {
+	host := options.Host
+	if host == "" {
+		host = DefaultHost
+	}
+
+	return &client{
+		target.NewBaseClient(options.ClientOptions),
+		strings.TrimSuffix(host, "/"),
+		options.Token.Value(),
+		options.ChatID,
+		options.CustomFields,
+		options.Remediation,
+		options.HTTPClient,
+	}
+}
@@ -0,0 +1,116 @@
+package telegram_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/telegram"
+)
+
+type testClient struct {
+	callback   func(req *http.Request)
+	statusCode int
+}
+
+func (c testClient) Do(req *http.Request) (*http.Response, error) {
+	c.callback(req)
+
+	return &http.Response{
+		StatusCode: c.statusCode,
+	}, nil
+}
+
+func Test_TelegramTarget(t *testing.T) {
+	t.Run("Send Complete Result", func(t *testing.T) {
+		callback := func(req *http.Request) {
+			if contentType := req.Header.Get("Content-Type"); contentType != "application/json; charset=utf-8" {
+				t.Errorf("Unexpected Content-Type: %s", contentType)
+			}
+
+			if url := req.URL.String(); url != "https://api.telegram.org/bot123:token/sendMessage" {
+				t.Errorf("Unexpected Host: %s", url)
+			}
+
+			payload := struct {
+				ChatID    string `json:"chat_id"`
+				Text      string `json:"text"`
+				ParseMode string `json:"parse_mode"`
+			}{}
+
+			if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+				t.Fatal(err)
+			}
+
+			if payload.ChatID != "-100123" {
+				t.Errorf("Unexpected ChatID: %s", payload.ChatID)
+			}
+			if payload.ParseMode != "MarkdownV2" {
+				t.Errorf("Unexpected ParseMode: %s", payload.ParseMode)
+			}
+			if !strings.Contains(payload.Text, `*Policy:* require\-requests\-and\-limits\-required`) {
+				t.Errorf("Expected escaped policy in text: %s", payload.Text)
+			}
+			if !strings.Contains(payload.Text, `*Resource:* Deployment default/nginx`) {
+				t.Errorf("Expected resource in text: %s", payload.Text)
+			}
+			if !strings.Contains(payload.Text, `*Cluster:* Name`) {
+				t.Errorf("Expected custom field in text: %s", payload.Text)
+			}
+		}
+
+		client := telegram.NewClient(telegram.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Telegram",
+			},
+			Token:        "123:token",
+			ChatID:       "-100123",
+			CustomFields: map[string]string{"Cluster": "Name"},
+			HTTPClient:   testClient{callback, 200},
+		})
+		client.Send(fixtures.CompleteTargetSendResult)
+	})
+
+	t.Run("Send Minimal Result", func(t *testing.T) {
+		callback := func(req *http.Request) {
+			if url := req.URL.String(); url != "http://telegram.local/bot123:token/sendMessage" {
+				t.Errorf("Unexpected Host: %s", url)
+			}
+		}
+
+		client := telegram.NewClient(telegram.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Telegram",
+			},
+			Host:       "http://telegram.local/",
+			Token:      "123:token",
+			ChatID:     "-100123",
+			HTTPClient: testClient{callback, 200},
+		})
+		client.Send(fixtures.MinimalTargetSendResult)
+	})
+
+	t.Run("Name", func(t *testing.T) {
+		client := telegram.NewClient(telegram.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Telegram",
+			},
+			Token:      "123:token",
+			ChatID:     "-100123",
+			HTTPClient: testClient{},
+		})
+
+		if client.Name() != "Telegram" {
+			t.Errorf("Unexpected Name %s", client.Name())
+		}
+	})
+}
+
+func Test_Escape(t *testing.T) {
+	if escaped := telegram.Escape("v1.2_beta (rc-1)!"); escaped != `v1\.2\_beta \(rc\-1\)\!` {
+		t.Errorf("Unexpected escaped text: %s", escaped)
+	}
+}
@@ -0,0 +1,63 @@
+package target
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+)
+
+// TemplateData available in the message templates of chat targets, the fields of the result are embedded
+type TemplateData struct {
+	v1alpha2.PolicyReportResult
+	// Resource is the first resource of the result, nil for results without resource
+	Resource *corev1.ObjectReference
+}
+
+// Template renders a text of a chat message, e.g. the title or the URL of an action
+type Template struct {
+	tmpl *template.Template
+}
+
+// Render the template with the result, a nil template or a failed rendering returns the fallback
+func (t *Template) Render(result v1alpha2.PolicyReportResult, fallback string) string {
+	if t == nil || t.tmpl == nil {
+		return fallback
+	}
+
+	data := TemplateData{PolicyReportResult: result}
+	if result.HasResource() {
+		data.Resource = result.GetResource()
+	}
+
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		logger.Errorf("failed to render template %s: %s", t.tmpl.Name(), err)
+		return fallback
+	}
+
+	return strings.TrimSpace(buf.String())
+}
+
+// ParseTemplate parses the text as Go template, an empty text returns a nil template which renders the fallback
+func ParseTemplate(name, text string) (*Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Template{tmpl: tmpl}, nil
+}
+
+// Action is a button or link of a chat message, the button is omitted if the URL renders empty
+type Action struct {
+	Text string
+	URL  *Template
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
 	"github.com/kyverno/policy-reporter/pkg/target"
 	"github.com/kyverno/policy-reporter/pkg/target/http"
+	"github.com/kyverno/policy-reporter/pkg/tracing"
 )
 
 // Options to configure the Discord target
@@ -25,8 +26,10 @@ func (e *client) Send(result v1alpha2.PolicyReportResult) {
 		return
 	}
 
+	tracing.Inject(req, result.TraceParent)
+
 	resp, err := e.client.Do(req)
-	http.ProcessHTTPResponse(e.Name(), resp, err)
+	http.ProcessHTTPResponse(e.Name(), result.GetID(), resp, err)
 }
 
 // NewClient creates a new loki.client to send Results to Elasticsearch
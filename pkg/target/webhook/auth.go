@@ -0,0 +1,153 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+
+	"github.com/kyverno/policy-reporter/pkg/secret"
+	targethttp "github.com/kyverno/policy-reporter/pkg/target/http"
+)
+
+// Defaults of the authentication and signature headers
+const (
+	DefaultAuthHeader      = "Authorization"
+	DefaultAuthTemplate    = "Bearer {{ .Token }}"
+	DefaultSignatureHeader = "X-Policy-Reporter-Signature"
+)
+
+// Signer adds the HMAC-SHA256 signature of the request body as "sha256=<hex>" header,
+// receivers verify the authenticity of the request with the shared secret
+type Signer struct {
+	secret []byte
+	header string
+}
+
+// Sign the body of the request
+func (s *Signer) Sign(req *http.Request) error {
+	body := []byte{}
+	if req.GetBody != nil {
+		reader, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+
+		body, err = io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+	}
+
+	req.Header.Set(s.header, Signature(s.secret, body))
+
+	return nil
+}
+
+// Signature of the body with the key in the format of the signature header
+func Signature(key, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewSigner creates a Signer, the header defaults to DefaultSignatureHeader
+func NewSigner(key secret.String, header string) *Signer {
+	if header == "" {
+		header = DefaultSignatureHeader
+	}
+
+	return &Signer{secret: []byte(key.Value()), header: header}
+}
+
+// AuthData available in the header template
+type AuthData struct {
+	Token string
+}
+
+// Auth renders the authentication header with a static token or an access token fetched with the OAuth2 client credentials flow
+type Auth struct {
+	header string
+	tmpl   *template.Template
+	token  secret.String
+	source oauth2.TokenSource
+}
+
+// Apply sets the authentication header of the request
+func (a *Auth) Apply(req *http.Request) error {
+	token := a.token.Value()
+	if a.source != nil {
+		t, err := a.source.Token()
+		if err != nil {
+			return fmt.Errorf("failed to fetch oauth2 token: %w", err)
+		}
+
+		token = t.AccessToken
+	}
+
+	var buf bytes.Buffer
+	if err := a.tmpl.Execute(&buf, AuthData{Token: token}); err != nil {
+		return fmt.Errorf("failed to render auth header: %w", err)
+	}
+
+	req.Header.Set(a.header, strings.TrimSpace(buf.String()))
+
+	return nil
+}
+
+// OAuth2 client credentials configuration
+type OAuth2 struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret secret.String
+	Scopes       []string
+	// Client fetches the tokens, the proxy, certificate and TLS settings of the target apply to the token endpoint as well
+	Client targethttp.Client
+}
+
+// NewAuth creates an Auth, the token is fetched with the client credentials flow if a token URL is configured.
+// The header defaults to DefaultAuthHeader and the template to DefaultAuthTemplate
+func NewAuth(header, text string, token secret.String, oauth OAuth2) (*Auth, error) {
+	if header == "" {
+		header = DefaultAuthHeader
+	}
+	if text == "" {
+		text = DefaultAuthTemplate
+	}
+
+	tmpl, err := template.New("auth").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	auth := &Auth{header: header, tmpl: tmpl, token: token}
+
+	if oauth.TokenURL != "" {
+		config := clientcredentials.Config{
+			ClientID:     oauth.ClientID,
+			ClientSecret: oauth.ClientSecret.Value(),
+			TokenURL:     oauth.TokenURL,
+			Scopes:       oauth.Scopes,
+		}
+
+		ctx := context.Background()
+		if oauth.Client != nil {
+			ctx = context.WithValue(ctx, oauth2.HTTPClient, targethttp.StdClient(oauth.Client))
+		}
+
+		// the token source caches the token until it expires
+		auth.source = config.TokenSource(ctx)
+	}
+
+	return auth, nil
+}
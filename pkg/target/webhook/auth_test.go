@@ -0,0 +1,149 @@
+package webhook_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/webhook"
+)
+
+func Test_Auth(t *testing.T) {
+	t.Run("Sign Body", func(t *testing.T) {
+		callback := func(req *http.Request) error {
+			body, _ := io.ReadAll(req.Body)
+
+			if signature := req.Header.Get(webhook.DefaultSignatureHeader); signature != webhook.Signature([]byte("secret"), body) {
+				t.Errorf("Unexpected signature %s", signature)
+			}
+
+			return nil
+		}
+
+		client := webhook.NewClient(webhook.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Webhook",
+			},
+			Host:       "http://localhost:8080/webhook",
+			HTTPClient: testClient{callback, 200},
+			Signer:     webhook.NewSigner("secret", ""),
+		})
+		client.Send(fixtures.CompleteTargetSendResult)
+	})
+
+	t.Run("API Key Template", func(t *testing.T) {
+		auth, err := webhook.NewAuth("X-API-Key", "key-{{ .Token }}", "1234", webhook.OAuth2{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		callback := func(req *http.Request) error {
+			if value := req.Header.Get("X-API-Key"); value != "key-1234" {
+				t.Errorf("Unexpected Header X-API-Key: %s", value)
+			}
+
+			return nil
+		}
+
+		client := webhook.NewClient(webhook.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Webhook",
+			},
+			Host:       "http://localhost:8080/webhook",
+			HTTPClient: testClient{callback, 200},
+			Auth:       auth,
+		})
+		client.Send(fixtures.CompleteTargetSendResult)
+	})
+
+	t.Run("OAuth2 Client Credentials", func(t *testing.T) {
+		requests := 0
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			requests++
+
+			if id, secret, _ := req.BasicAuth(); id != "policy-reporter" || secret != "secret" {
+				t.Errorf("Unexpected client credentials %s:%s", id, secret)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token":"access","token_type":"bearer","expires_in":3600}`)
+		}))
+		defer server.Close()
+
+		auth, err := webhook.NewAuth("", "", "", webhook.OAuth2{TokenURL: server.URL, ClientID: "policy-reporter", ClientSecret: "secret"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		callback := func(req *http.Request) error {
+			if value := req.Header.Get("Authorization"); value != "Bearer access" {
+				t.Errorf("Unexpected Header Authorization: %s", value)
+			}
+
+			return nil
+		}
+
+		client := webhook.NewClient(webhook.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Webhook",
+			},
+			Host:       "http://localhost:8080/webhook",
+			HTTPClient: testClient{callback, 200},
+			Auth:       auth,
+		})
+		client.Send(fixtures.CompleteTargetSendResult)
+		client.Send(fixtures.MinimalTargetSendResult)
+
+		if requests != 1 {
+			t.Errorf("Expected the token to be cached, got %d token requests", requests)
+		}
+	})
+
+	t.Run("OAuth2 Target Client", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token":"access","token_type":"bearer","expires_in":3600}`)
+		}))
+		defer server.Close()
+
+		client := &countingClient{client: server.Client()}
+
+		auth, err := webhook.NewAuth("", "", "", webhook.OAuth2{TokenURL: server.URL, ClientID: "policy-reporter", ClientSecret: "secret", Client: client})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req, _ := http.NewRequest(http.MethodPost, "http://localhost:8080/webhook", nil)
+		if err := auth.Apply(req); err != nil {
+			t.Fatal(err)
+		}
+
+		if client.requests != 1 {
+			t.Errorf("Expected the token to be fetched with the target client, got %d requests", client.requests)
+		}
+		if value := req.Header.Get("Authorization"); value != "Bearer access" {
+			t.Errorf("Unexpected Header Authorization: %s", value)
+		}
+	})
+
+	t.Run("Invalid Template", func(t *testing.T) {
+		if _, err := webhook.NewAuth("", "{{ .Token", "", webhook.OAuth2{}); err == nil {
+			t.Error("Expected template error")
+		}
+	})
+}
+
+type countingClient struct {
+	client   *http.Client
+	requests int
+}
+
+func (c *countingClient) Do(req *http.Request) (*http.Response, error) {
+	c.requests++
+	return c.client.Do(req)
+}
@@ -1,26 +1,109 @@
 package webhook
 
 import (
+	"fmt"
+	nethttp "net/http"
+	"time"
+
 	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/secret"
 	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/cloudevents"
 	"github.com/kyverno/policy-reporter/pkg/target/http"
+	"github.com/kyverno/policy-reporter/pkg/timeformat"
+	"github.com/kyverno/policy-reporter/pkg/tracing"
+)
+
+var logger = logging.Component(logging.Targets)
+
+// Payload modes of the webhook target
+const (
+	// PayloadResult sends each new result
+	PayloadResult = "result"
+	// PayloadReport sends the PolicyReport with all validated results on each change
+	PayloadReport = "report"
+	// PayloadSummary sends the summary of a PolicyReport and its difference to the previous version if it changed
+	PayloadSummary = "summary"
 )
 
 // Options to configure the Discord target
 type Options struct {
 	target.ClientOptions
 	Host         string
-	Headers      map[string]string
+	Headers      map[string]secret.String
 	CustomFields map[string]string
-	HTTPClient   http.Client
+	// Payload mode, defaults to PayloadResult
+	Payload    string
+	HTTPClient http.Client
+	// Auth sets the authentication header, optional
+	Auth *Auth
+	// Signer signs the request body, optional
+	Signer *Signer
+	// CloudEvents wraps the payloads in CloudEvents envelopes, optional
+	CloudEvents *cloudevents.Options
+}
+
+// Report JSON structure of the report and summary payload
+type Report struct {
+	Event      string                        `json:"event"`
+	Kind       string                        `json:"kind"`
+	Name       string                        `json:"name"`
+	Namespace  string                        `json:"namespace,omitempty"`
+	Source     string                        `json:"source,omitempty"`
+	Labels     map[string]string             `json:"labels,omitempty"`
+	Summary    v1alpha2.PolicyReportSummary  `json:"summary"`
+	Delta      *v1alpha2.PolicyReportSummary `json:"delta,omitempty"`
+	Results    []http.Result                 `json:"results,omitempty"`
+	Properties map[string]string             `json:"properties,omitempty"`
+	Timestamp  time.Time                     `json:"timestamp"`
 }
 
 type client struct {
 	target.BaseClient
 	host         string
-	headers      map[string]string
+	headers      map[string]secret.String
 	customFields map[string]string
 	client       http.Client
+	auth         *Auth
+	signer       *Signer
+	cloudEvents  *cloudevents.Options
+}
+
+// prepare adds the headers, the authentication and the signature to the request
+func (e *client) prepare(req *nethttp.Request) error {
+	for header, value := range e.headers {
+		req.Header.Set(header, value.Value())
+	}
+
+	if e.auth != nil {
+		if err := e.auth.Apply(req); err != nil {
+			return err
+		}
+	}
+
+	if e.signer != nil {
+		if err := e.signer.Sign(req); err != nil {
+			return fmt.Errorf("failed to sign request: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// request creates the POST request of the payload, wrapped in a CloudEvents envelope if configured
+func (e *client) request(eventType, subject string, payload interface{}) (*nethttp.Request, error) {
+	if e.cloudEvents == nil {
+		return http.CreateJSONRequest(e.Name(), "POST", e.host, payload)
+	}
+
+	req, err := e.cloudEvents.Request("POST", e.host, e.cloudEvents.New(eventType, subject, payload))
+	if err != nil {
+		logger.Errorf("%s : %v", e.Name(), err.Error())
+	}
+
+	return req, err
 }
 
 func (e *client) Send(result v1alpha2.PolicyReportResult) {
@@ -38,26 +121,112 @@ func (e *client) Send(result v1alpha2.PolicyReportResult) {
 		result.Properties = props
 	}
 
-	req, err := http.CreateJSONRequest(e.Name(), "POST", e.host, http.NewJSONResult(result))
+	req, err := e.request(cloudevents.TypeResult, cloudevents.ResultSubject(result), http.NewJSONResult(result))
 	if err != nil {
 		return
 	}
 
-	for header, value := range e.headers {
-		req.Header.Set(header, value)
+	tracing.Inject(req, result.TraceParent)
+
+	if err := e.prepare(req); err != nil {
+		http.ProcessHTTPResponse(e.Name(), result.GetID(), nil, err)
+		return
 	}
 
 	resp, err := e.client.Do(req)
-	http.ProcessHTTPResponse(e.Name(), resp, err)
+	http.ProcessHTTPResponse(e.Name(), result.GetID(), resp, err)
+}
+
+type reportClient struct {
+	*client
+	payload string
 }
 
-// NewClient creates a new loki.client to send Results to Elasticsearch
+// Send is a noop, results are sent as part of their PolicyReport
+func (e *reportClient) Send(result v1alpha2.PolicyReportResult) {}
+
+func (e *reportClient) SendReport(event report.LifecycleEvent, previous v1alpha2.PolicyReportSummary) {
+	rep := event.PolicyReport
+
+	payload := Report{
+		Event:      event.Type.String(),
+		Kind:       report.GetType(rep),
+		Name:       rep.GetName(),
+		Namespace:  rep.GetNamespace(),
+		Source:     rep.GetSource(),
+		Labels:     rep.GetLabels(),
+		Properties: e.customFields,
+		Timestamp:  timeformat.In(time.Now()),
+	}
+
+	if event.Type != report.Deleted {
+		payload.Summary = rep.GetSummary()
+	}
+
+	if e.payload == PayloadSummary {
+		delta := diff(payload.Summary, previous)
+		if event.Type == report.Updated && delta == (v1alpha2.PolicyReportSummary{}) {
+			return
+		}
+
+		payload.Delta = &delta
+	} else if event.Type != report.Deleted {
+		payload.Results = make([]http.Result, 0, len(rep.GetResults()))
+		for _, result := range rep.GetResults() {
+			if e.Validate(rep, result) {
+				payload.Results = append(payload.Results, http.NewJSONResult(result))
+			}
+		}
+	}
+
+	eventType := cloudevents.TypeReport
+	if e.payload == PayloadSummary {
+		eventType = cloudevents.TypeSummary
+	}
+
+	req, err := e.request(eventType+"."+payload.Event, cloudevents.ReportSubject(payload.Namespace, payload.Name), payload)
+	if err != nil {
+		return
+	}
+
+	tracing.Inject(req, tracing.TraceParent(event.Trace))
+
+	if err := e.prepare(req); err != nil {
+		http.ProcessHTTPResponse(e.Name(), "", nil, err)
+		return
+	}
+
+	resp, err := e.client.client.Do(req)
+	http.ProcessHTTPResponse(e.Name(), "", resp, err)
+}
+
+func diff(current, previous v1alpha2.PolicyReportSummary) v1alpha2.PolicyReportSummary {
+	return v1alpha2.PolicyReportSummary{
+		Pass:  current.Pass - previous.Pass,
+		Fail:  current.Fail - previous.Fail,
+		Warn:  current.Warn - previous.Warn,
+		Error: current.Error - previous.Error,
+		Skip:  current.Skip - previous.Skip,
+	}
+}
+
+// NewClient creates a new loki.client to send Results to Elasticsearch,
+// with the report or summary payload the client receives changed PolicyReports instead
 func NewClient(options Options) target.Client {
-	return &client{
+	c := &client{
 		target.NewBaseClient(options.ClientOptions),
 		options.Host,
 		options.Headers,
 		options.CustomFields,
 		options.HTTPClient,
+		options.Auth,
+		options.Signer,
+		options.CloudEvents,
 	}
+
+	if options.Payload == PayloadReport || options.Payload == PayloadSummary {
+		return &reportClient{c, options.Payload}
+	}
+
+	return c
 }
@@ -1,14 +1,20 @@
 package webhook_test
 
 import (
+	"encoding/json"
 	"io"
 	"net/http"
 	"strings"
 	"testing"
 
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
 	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/secret"
 	"github.com/kyverno/policy-reporter/pkg/target"
+	"github.com/kyverno/policy-reporter/pkg/target/cloudevents"
 	"github.com/kyverno/policy-reporter/pkg/target/webhook"
+	"github.com/kyverno/policy-reporter/pkg/tracing"
 )
 
 type testClient struct {
@@ -52,7 +58,7 @@ func Test_UITarget(t *testing.T) {
 				Name: "UI",
 			},
 			Host:         "http://localhost:8080/webhook",
-			Headers:      map[string]string{"X-Code": "1234"},
+			Headers:      map[string]secret.String{"X-Code": "1234"},
 			CustomFields: map[string]string{"cluster": "name"},
 			HTTPClient:   testClient{callback, 200},
 		})
@@ -62,13 +68,37 @@ func Test_UITarget(t *testing.T) {
 			t.Error("expected customFields are not added to the actuel result")
 		}
 	})
+	t.Run("Propagate trace context", func(t *testing.T) {
+		traceParent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+		callback := func(req *http.Request) error {
+			if value := req.Header.Get(tracing.Header); value != traceParent {
+				t.Errorf("Unexpected traceparent header: %s", value)
+			}
+
+			return nil
+		}
+
+		client := webhook.NewClient(webhook.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "UI",
+			},
+			Host:       "http://localhost:8080/webhook",
+			HTTPClient: testClient{callback, 200},
+		})
+
+		result := fixtures.CompleteTargetSendResult
+		result.TraceParent = traceParent
+
+		client.Send(result)
+	})
 	t.Run("Name", func(t *testing.T) {
 		client := webhook.NewClient(webhook.Options{
 			ClientOptions: target.ClientOptions{
 				Name: "HTTP",
 			},
 			Host:       "http://localhost:8080",
-			Headers:    map[string]string{"X-Code": "1234"},
+			Headers:    map[string]secret.String{"X-Code": "1234"},
 			HTTPClient: testClient{},
 		})
 
@@ -76,4 +106,162 @@ func Test_UITarget(t *testing.T) {
 			t.Errorf("Unexpected Name %s", client.Name())
 		}
 	})
+	t.Run("CloudEvents structured", func(t *testing.T) {
+		callback := func(req *http.Request) error {
+			if contentType := req.Header.Get("Content-Type"); contentType != "application/cloudevents+json; charset=utf-8" {
+				t.Errorf("Unexpected Content-Type: %s", contentType)
+			}
+
+			event := struct {
+				SpecVersion string                 `json:"specversion"`
+				Type        string                 `json:"type"`
+				Source      string                 `json:"source"`
+				Subject     string                 `json:"subject"`
+				Data        map[string]interface{} `json:"data"`
+			}{}
+			if err := json.NewDecoder(req.Body).Decode(&event); err != nil {
+				t.Fatal(err)
+			}
+
+			if event.SpecVersion != "1.0" || event.Type != cloudevents.TypeResult || event.Source != "//cluster-a/policy-reporter" {
+				t.Errorf("Unexpected attributes: %+v", event)
+			}
+			if event.Subject != "default/Deployment/nginx" {
+				t.Errorf("Unexpected subject: %s", event.Subject)
+			}
+			if event.Data["policy"] != fixtures.CompleteTargetSendResult.Policy {
+				t.Errorf("Expected the result as data, got %v", event.Data)
+			}
+
+			return nil
+		}
+
+		client := webhook.NewClient(webhook.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Webhook",
+			},
+			Host:        "http://localhost:8080/webhook",
+			HTTPClient:  testClient{callback, 200},
+			CloudEvents: &cloudevents.Options{Source: "//cluster-a/policy-reporter"},
+		})
+		client.Send(fixtures.CompleteTargetSendResult)
+	})
+	t.Run("CloudEvents binary", func(t *testing.T) {
+		callback := func(req *http.Request) error {
+			if value := req.Header.Get("ce-type"); value != cloudevents.TypeReport+".add" {
+				t.Errorf("Unexpected ce-type: %s", value)
+			}
+			if value := req.Header.Get("ce-subject"); value != "test/polr-test" {
+				t.Errorf("Unexpected ce-subject: %s", value)
+			}
+			if req.Header.Get("ce-id") == "" || req.Header.Get("ce-specversion") != "1.0" || req.Header.Get("ce-source") != cloudevents.DefaultSource {
+				t.Errorf("Expected id, specversion and default source headers")
+			}
+
+			payload := webhook.Report{}
+			if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+				t.Fatal(err)
+			}
+			if payload.Name != "polr-test" {
+				t.Errorf("Expected the report as body, got %+v", payload)
+			}
+
+			return nil
+		}
+
+		client := webhook.NewClient(webhook.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Webhook",
+			},
+			Host:        "http://localhost:8080/webhook",
+			Payload:     webhook.PayloadReport,
+			HTTPClient:  testClient{callback, 200},
+			CloudEvents: &cloudevents.Options{Mode: cloudevents.ModeBinary},
+		})
+
+		polr := &v1alpha2.PolicyReport{}
+		polr.SetName("polr-test")
+		polr.SetNamespace("test")
+
+		client.(target.ReportClient).SendReport(report.LifecycleEvent{Type: report.Added, PolicyReport: polr}, v1alpha2.PolicyReportSummary{})
+	})
+}
+
+func Test_ReportPayload(t *testing.T) {
+	rep := &v1alpha2.PolicyReport{
+		Results: []v1alpha2.PolicyReportResult{fixtures.FailResult, fixtures.PassResult},
+		Summary: v1alpha2.PolicyReportSummary{Pass: 1, Fail: 1},
+	}
+	rep.SetName("polr-test")
+	rep.SetNamespace("test")
+
+	newClient := func(payload string, callback func(req *http.Request) error) target.ReportClient {
+		client, ok := webhook.NewClient(webhook.Options{
+			ClientOptions: target.ClientOptions{
+				Name: "Webhook",
+			},
+			Host:       "http://localhost:8080/webhook",
+			Payload:    payload,
+			HTTPClient: testClient{callback, 200},
+		}).(target.ReportClient)
+		if !ok {
+			t.Fatalf("expected ReportClient for the %s payload", payload)
+		}
+
+		return client
+	}
+
+	t.Run("Report", func(t *testing.T) {
+		called := false
+		client := newClient(webhook.PayloadReport, func(req *http.Request) error {
+			called = true
+
+			payload := webhook.Report{}
+			if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+				t.Fatal(err)
+			}
+
+			if payload.Event != "add" || payload.Kind != "PolicyReport" || payload.Name != "polr-test" || payload.Namespace != "test" {
+				t.Errorf("unexpected report: %+v", payload)
+			}
+			if len(payload.Results) != 2 || payload.Summary.Fail != 1 || payload.Delta != nil {
+				t.Errorf("unexpected results or summary: %+v", payload)
+			}
+
+			return nil
+		})
+
+		client.SendReport(report.LifecycleEvent{Type: report.Added, PolicyReport: rep}, v1alpha2.PolicyReportSummary{})
+		if !called {
+			t.Error("expected report request")
+		}
+	})
+	t.Run("Summary", func(t *testing.T) {
+		var payload webhook.Report
+		calls := 0
+		client := newClient(webhook.PayloadSummary, func(req *http.Request) error {
+			calls++
+			payload = webhook.Report{}
+
+			return json.NewDecoder(req.Body).Decode(&payload)
+		})
+
+		client.SendReport(report.LifecycleEvent{Type: report.Updated, PolicyReport: rep}, v1alpha2.PolicyReportSummary{Pass: 2})
+		if calls != 1 {
+			t.Fatal("expected summary request")
+		}
+		if len(payload.Results) != 0 || payload.Delta == nil || payload.Delta.Pass != -1 || payload.Delta.Fail != 1 {
+			t.Errorf("unexpected summary payload: %+v", payload)
+		}
+
+		client.SendReport(report.LifecycleEvent{Type: report.Updated, PolicyReport: rep}, rep.Summary)
+		if calls != 1 {
+			t.Error("expected no request for an unchanged summary")
+		}
+
+		client.SendReport(report.LifecycleEvent{Type: report.Deleted, PolicyReport: rep}, rep.Summary)
+		if calls != 2 || payload.Event != "delete" || payload.Delta.Fail != -1 || payload.Summary.Fail != 0 {
+			t.Errorf("unexpected payload of the deleted report: %+v", payload)
+		}
+	})
 }
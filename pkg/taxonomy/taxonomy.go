@@ -0,0 +1,150 @@
+// Package taxonomy maps sources and categories to display names, groups their variants together and defines their order
+package taxonomy
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+// Entry maps all matching values to one display name
+type Entry struct {
+	// Name displayed for the matching values
+	Name string
+	// Match values case insensitive with wildcard and regex support, matches the name if empty
+	Match []string
+	// Group of related entries, e.g. all scanners
+	Group string
+	// Order of the entry, configured entries are sorted before unknown values
+	Order int
+}
+
+func (e Entry) matches(value string) bool {
+	if len(e.Match) == 0 {
+		return strings.EqualFold(e.Name, value)
+	}
+
+	return validate.MatchAny(e.Match, value)
+}
+
+// Dimension of configured entries, the first matching entry wins
+type Dimension []Entry
+
+func (d Dimension) lookup(value string) (Entry, bool) {
+	for _, e := range d {
+		if e.matches(value) {
+			return e, true
+		}
+	}
+
+	return Entry{}, false
+}
+
+// Name returns the display name of the value, the value itself if no entry matches
+func (d Dimension) Name(value string) string {
+	if e, ok := d.lookup(value); ok {
+		return e.Name
+	}
+
+	return value
+}
+
+// Group returns the group of the value, empty if no entry matches
+func (d Dimension) Group(value string) string {
+	e, _ := d.lookup(value)
+
+	return e.Group
+}
+
+// Sort the values by the order of their entries, unknown values by name behind them
+func (d Dimension) Sort(values []string) {
+	sort.SliceStable(values, func(i, j int) bool {
+		return d.Less(values[i], values[j])
+	})
+}
+
+// Less compares two values by the order of their entries and their display names
+func (d Dimension) Less(a, b string) bool {
+	ea, oka := d.lookup(a)
+	eb, okb := d.lookup(b)
+
+	if oka != okb {
+		return oka
+	}
+	if oka && ea.Order != eb.Order {
+		return ea.Order < eb.Order
+	}
+
+	return strings.ToLower(d.Name(a)) < strings.ToLower(d.Name(b))
+}
+
+// Taxonomy of sources and categories
+type Taxonomy struct {
+	Sources    Dimension
+	Categories Dimension
+}
+
+// Empty reports if no display names are configured
+func (t *Taxonomy) Empty() bool {
+	return t == nil || (len(t.Sources) == 0 && len(t.Categories) == 0)
+}
+
+// Result returns a copy of the result with the display names of its source and category, the result ID is kept
+func (t *Taxonomy) Result(result v1alpha2.PolicyReportResult) v1alpha2.PolicyReportResult {
+	if t.Empty() {
+		return result
+	}
+
+	result.ID = result.GetID()
+	result.Source = t.Sources.Name(result.Source)
+	result.Category = t.Categories.Name(result.Category)
+
+	return result
+}
+
+// Report wraps the report to return its results with display names
+func (t *Taxonomy) Report(rep v1alpha2.ReportInterface) v1alpha2.ReportInterface {
+	if t.Empty() || rep == nil {
+		return rep
+	}
+
+	results := make([]v1alpha2.PolicyReportResult, 0, len(rep.GetResults()))
+	for _, r := range rep.GetResults() {
+		results = append(results, t.Result(r))
+	}
+
+	return &displayReport{ReportInterface: rep, results: results}
+}
+
+// Reports wraps a report listener, the listener receives the reports with display names
+func (t *Taxonomy) Reports(callback report.PolicyReportListener) report.PolicyReportListener {
+	if t.Empty() {
+		return callback
+	}
+
+	return func(event report.LifecycleEvent) {
+		event.PolicyReport = t.Report(event.PolicyReport)
+
+		callback(event)
+	}
+}
+
+type displayReport struct {
+	v1alpha2.ReportInterface
+	results []v1alpha2.PolicyReportResult
+}
+
+func (r *displayReport) GetResults() []v1alpha2.PolicyReportResult {
+	return r.results
+}
+
+func (r *displayReport) GetSource() string {
+	if len(r.results) == 0 {
+		return ""
+	}
+
+	return r.results[0].Source
+}
@@ -0,0 +1,83 @@
+package taxonomy_test
+
+import (
+	"testing"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/fixtures"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/taxonomy"
+)
+
+var tx = &taxonomy.Taxonomy{
+	Sources: taxonomy.Dimension{
+		{Name: "Trivy", Match: []string{"trivy*"}, Group: "Scanner", Order: 2},
+		{Name: "Kyverno", Group: "Policy Engine", Order: 1},
+	},
+	Categories: taxonomy.Dimension{
+		{Name: "Pod Security Standards", Match: []string{"Pod Security Standards*"}},
+	},
+}
+
+func Test_Dimension(t *testing.T) {
+	cases := map[string]string{
+		"kyverno":                           "Kyverno",
+		"Trivy Vulnerability":               "Trivy",
+		"trivy ConfigAudit":                 "Trivy",
+		"Falco":                             "Falco",
+		"Pod Security Standards (Baseline)": "Pod Security Standards (Baseline)",
+	}
+
+	for value, expected := range cases {
+		if name := tx.Sources.Name(value); name != expected {
+			t.Errorf("expected %s for %s, got %s", expected, value, name)
+		}
+	}
+
+	if name := tx.Categories.Name("Pod Security Standards (Restricted)"); name != "Pod Security Standards" {
+		t.Errorf("expected the variants to be grouped, got %s", name)
+	}
+	if group := tx.Sources.Group("Trivy Vulnerability"); group != "Scanner" {
+		t.Errorf("expected the group of the entry, got %s", group)
+	}
+
+	values := []string{"falco", "Trivy Vulnerability", "Alpha", "Kyverno"}
+	tx.Sources.Sort(values)
+
+	expected := []string{"Kyverno", "Trivy Vulnerability", "Alpha", "falco"}
+	for i := range values {
+		if values[i] != expected[i] {
+			t.Fatalf("expected order %v, got %v", expected, values)
+		}
+	}
+}
+
+func Test_Reports(t *testing.T) {
+	result := fixtures.FailResult
+	result.Source = "Trivy Vulnerability"
+	id := result.GetID()
+
+	rep := &v1alpha2.PolicyReport{Results: []v1alpha2.PolicyReportResult{result}}
+
+	var received v1alpha2.ReportInterface
+	listener := tx.Reports(func(event report.LifecycleEvent) { received = event.PolicyReport })
+	listener(report.LifecycleEvent{Type: report.Added, PolicyReport: rep})
+
+	if received.GetSource() != "Trivy" || received.GetResults()[0].Source != "Trivy" {
+		t.Errorf("expected the display name of the source, got %s", received.GetSource())
+	}
+	if received.GetResults()[0].GetID() != id {
+		t.Errorf("expected the result ID to be kept")
+	}
+	if rep.Results[0].Source != "Trivy Vulnerability" {
+		t.Errorf("expected the original report to be unchanged")
+	}
+	if received.GetID() != rep.GetID() {
+		t.Errorf("expected the report ID to be kept")
+	}
+
+	var empty *taxonomy.Taxonomy
+	if !empty.Empty() || empty.Report(rep) != rep {
+		t.Errorf("expected reports to be unchanged without taxonomy")
+	}
+}
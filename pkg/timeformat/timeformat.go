@@ -0,0 +1,125 @@
+package timeformat
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Supported formats, other values are used as Go time layout
+const (
+	RFC3339     = "rfc3339"
+	RFC3339Nano = "rfc3339nano"
+	Unix        = "unix"
+	UnixMilli   = "unixmilli"
+)
+
+// sample time to detect layouts without any element of the Go reference time, they always render the layout itself
+var sample = time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+
+// Formatter renders timestamps in a uniform format and timezone
+type Formatter struct {
+	format   string
+	location *time.Location
+}
+
+// In converts the time into the configured timezone
+func (f *Formatter) In(t time.Time) time.Time {
+	return t.In(f.location)
+}
+
+// Format the time in the configured format and timezone
+func (f *Formatter) Format(t time.Time) string {
+	switch f.format {
+	case Unix:
+		return strconv.FormatInt(t.Unix(), 10)
+	case UnixMilli:
+		return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+	case RFC3339:
+		return t.In(f.location).Format(time.RFC3339)
+	case RFC3339Nano:
+		return t.In(f.location).Format(time.RFC3339Nano)
+	default:
+		return t.In(f.location).Format(f.format)
+	}
+}
+
+// New creates a Formatter, the format defaults to RFC3339 and the timezone to UTC.
+// The timezone is an IANA name like "Europe/Berlin" or "Local"
+func New(format, timezone string) (*Formatter, error) {
+	if format == "" {
+		format = RFC3339
+	}
+	if lower := strings.ToLower(format); lower == RFC3339 || lower == RFC3339Nano || lower == Unix || lower == UnixMilli {
+		format = lower
+	} else if sample.Format(format) == format {
+		return nil, fmt.Errorf("invalid format '%s', expected rfc3339, rfc3339nano, unix, unixmilli or a Go time layout", format)
+	}
+
+	location := time.UTC
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("unknown timezone '%s': %w", timezone, err)
+		}
+
+		location = loc
+	}
+
+	return &Formatter{format: format, location: location}, nil
+}
+
+var (
+	global, _ = New(RFC3339, "UTC")
+	globalMx  sync.RWMutex
+)
+
+// Use the format and timezone for the package functions
+func Use(format, timezone string) error {
+	f, err := New(format, timezone)
+	if err != nil {
+		return err
+	}
+
+	globalMx.Lock()
+	defer globalMx.Unlock()
+
+	global = f
+
+	return nil
+}
+
+func formatter() *Formatter {
+	globalMx.RLock()
+	defer globalMx.RUnlock()
+
+	return global
+}
+
+// In converts the time into the configured timezone
+func In(t time.Time) time.Time {
+	return formatter().In(t)
+}
+
+// Format the time with the configured formatter
+func Format(t time.Time) string {
+	return formatter().Format(t)
+}
+
+// Result converts the timestamp of a result into the configured timezone, results without timestamp use the current time
+func Result(ts metav1.Timestamp) time.Time {
+	if ts.Seconds == 0 {
+		return In(time.Now())
+	}
+
+	return In(time.Unix(ts.Seconds, int64(ts.Nanos)))
+}
+
+// FormatResult formats the timestamp of a result with the configured formatter, results without timestamp use the current time
+func FormatResult(ts metav1.Timestamp) string {
+	return Format(Result(ts))
+}
@@ -0,0 +1,65 @@
+package timeformat_test
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/timeformat"
+)
+
+var ts = time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+
+func Test_Formatter(t *testing.T) {
+	cases := []struct {
+		format   string
+		timezone string
+		expected string
+	}{
+		{format: "", timezone: "", expected: "2024-03-01T12:30:00Z"},
+		{format: "RFC3339", timezone: "Europe/Berlin", expected: "2024-03-01T13:30:00+01:00"},
+		{format: "unix", timezone: "Europe/Berlin", expected: "1709296200"},
+		{format: "unixmilli", timezone: "", expected: "1709296200000"},
+		{format: "2006-01-02 15:04 MST", timezone: "America/New_York", expected: "2024-03-01 07:30 EST"},
+	}
+
+	for _, c := range cases {
+		f, err := timeformat.New(c.format, c.timezone)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", c.format, err)
+		}
+		if value := f.Format(ts); value != c.expected {
+			t.Errorf("expected %s for format %s, got %s", c.expected, c.format, value)
+		}
+	}
+}
+
+func Test_New(t *testing.T) {
+	if _, err := timeformat.New("rfc3339", "Mars/Olympus"); err == nil {
+		t.Error("expected error for unknown timezone")
+	}
+	if _, err := timeformat.New("iso", ""); err == nil {
+		t.Error("expected error for format without layout elements")
+	}
+	if _, err := timeformat.New("Monday", ""); err != nil {
+		t.Errorf("expected weekday layout to be valid, got %s", err)
+	}
+}
+
+func Test_Use(t *testing.T) {
+	if err := timeformat.Use("unix", ""); err != nil {
+		t.Fatal(err)
+	}
+	defer timeformat.Use("", "")
+
+	if value := timeformat.FormatResult(metav1.Timestamp{Seconds: ts.Unix()}); value != "1709296200" {
+		t.Errorf("expected the configured format, got %s", value)
+	}
+	if timeformat.Result(metav1.Timestamp{}).IsZero() {
+		t.Error("expected the current time for results without timestamp")
+	}
+	if err := timeformat.Use("", "Mars/Olympus"); err == nil {
+		t.Error("expected error for unknown timezone")
+	}
+}
@@ -0,0 +1,80 @@
+package tracing
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kyverno/policy-reporter/pkg/logging"
+)
+
+var logger = logging.Component("tracing")
+
+// Options of the TracerProvider
+type Options struct {
+	// Endpoint of the collector traces API, e.g. http://otel-collector:4318/v1/traces
+	Endpoint    string
+	Headers     map[string]string
+	ServiceName string
+	// SampleRatio of new traces, child spans follow the decision of their parent
+	SampleRatio float64
+	// Interval of the batched exports
+	Interval time.Duration
+	// QueueSize of the spans waiting for export, further spans are dropped
+	QueueSize int
+}
+
+// NewProvider exports the sampled spans in batches to the collector with the OTLP/HTTP protocol
+func NewProvider(options Options) (*sdktrace.TracerProvider, error) {
+	endpoint, err := url.Parse(options.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	clientOptions := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(endpoint.Host),
+		otlptracehttp.WithHeaders(options.Headers),
+		otlptracehttp.WithTimeout(30 * time.Second),
+	}
+	if endpoint.Path != "" {
+		clientOptions = append(clientOptions, otlptracehttp.WithURLPath(endpoint.Path))
+	}
+	if endpoint.Scheme == "http" {
+		clientOptions = append(clientOptions, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(), clientOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	batchOptions := []sdktrace.BatchSpanProcessorOption{}
+	if options.Interval > 0 {
+		batchOptions = append(batchOptions, sdktrace.WithBatchTimeout(options.Interval))
+	}
+	if options.QueueSize > 0 {
+		batchOptions = append(batchOptions, sdktrace.WithMaxQueueSize(options.QueueSize))
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter, batchOptions...),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(options.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(options.ServiceName))),
+	), nil
+}
+
+// Use the provider for all spans of the result pipeline, failed exports are logged
+func Use(provider trace.TracerProvider) {
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagator)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Errorf("failed to export spans: %s", err)
+	}))
+}
@@ -0,0 +1,57 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kyverno/policy-reporter/pkg/tracing"
+)
+
+func Test_NewProvider(t *testing.T) {
+	type request struct {
+		path        string
+		contentType string
+		tenant      string
+	}
+
+	requests := make(chan request, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests <- request{path: req.URL.Path, contentType: req.Header.Get("Content-Type"), tenant: req.Header.Get("X-Tenant")}
+	}))
+	defer server.Close()
+
+	provider, err := tracing.NewProvider(tracing.Options{
+		Endpoint:    server.URL + "/v1/traces",
+		Headers:     map[string]string{"X-Tenant": "team-a"},
+		ServiceName: "policy-reporter",
+		SampleRatio: 1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tracing.Use(provider)
+	defer tracing.Use(trace.NewNoopTracerProvider())
+
+	tracing.Start(trace.SpanContext{}, "report.receive").End()
+
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	req := <-requests
+	if req.path != "/v1/traces" || req.contentType != "application/x-protobuf" || req.tenant != "team-a" {
+		t.Errorf("unexpected export request: %+v", req)
+	}
+}
+
+func Test_NewProviderInvalidEndpoint(t *testing.T) {
+	if _, err := tracing.NewProvider(tracing.Options{Endpoint: "http://[::1"}); err == nil {
+		t.Error("expected error for an invalid endpoint")
+	}
+}
@@ -0,0 +1,59 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Header of the W3C trace context propagated into outgoing HTTP requests
+const Header = "traceparent"
+
+// Name of the tracer of the result pipeline
+const Name = "github.com/kyverno/policy-reporter"
+
+var propagator = propagation.TraceContext{}
+
+// Start a span as child of the parent with the global TracerProvider, spans with an invalid parent start a new trace
+func Start(parent trace.SpanContext, name string, options ...trace.SpanStartOption) trace.Span {
+	_, span := otel.Tracer(Name).Start(trace.ContextWithSpanContext(context.Background(), parent), name, options...)
+
+	return span
+}
+
+// StartClient starts a span of an outgoing request
+func StartClient(parent trace.SpanContext, name string) trace.Span {
+	return Start(parent, name, trace.WithSpanKind(trace.SpanKindClient))
+}
+
+// RecordError marks the span as failed, nil errors are ignored
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// Extract the span context of a traceparent header value, invalid for an empty or malformed value
+func Extract(traceParent string) trace.SpanContext {
+	return trace.SpanContextFromContext(propagator.Extract(context.Background(), propagation.MapCarrier{Header: traceParent}))
+}
+
+// TraceParent header value of the span context, empty for an invalid context
+func TraceParent(span trace.SpanContext) string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(trace.ContextWithSpanContext(context.Background(), span), carrier)
+
+	return carrier.Get(Header)
+}
+
+// Inject the trace context into the headers of the request, invalid contexts are ignored
+func Inject(req *http.Request, traceParent string) {
+	propagator.Inject(trace.ContextWithRemoteSpanContext(req.Context(), Extract(traceParent)), propagation.HeaderCarrier(req.Header))
+}
@@ -0,0 +1,101 @@
+package tracing_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kyverno/policy-reporter/pkg/tracing"
+)
+
+func Test_TraceParent(t *testing.T) {
+	value := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	c := tracing.Extract(value)
+	if !c.IsValid() || !c.IsSampled() {
+		t.Fatalf("expected valid sampled context, got %v", c)
+	}
+	if tracing.TraceParent(c) != value {
+		t.Errorf("expected %s, got %s", value, tracing.TraceParent(c))
+	}
+
+	for _, invalid := range []string{"", "00-4bf92f3577b34da6a3ce929d0e0e4736", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", "00-4bf92f3577b34da6a3ce929d0e0e47zz-00f067aa0ba902b7-01"} {
+		if tracing.Extract(invalid).IsValid() {
+			t.Errorf("expected %q to be invalid", invalid)
+		}
+	}
+
+	if tracing.TraceParent(trace.SpanContext{}) != "" {
+		t.Error("expected empty traceparent of an invalid context")
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "http://localhost", nil)
+	tracing.Inject(req, value)
+	if req.Header.Get(tracing.Header) != value {
+		t.Errorf("expected injected traceparent header, got %s", req.Header.Get(tracing.Header))
+	}
+
+	req, _ = http.NewRequest(http.MethodPost, "http://localhost", nil)
+	tracing.Inject(req, "")
+	if _, ok := req.Header[http.CanonicalHeaderKey(tracing.Header)]; ok {
+		t.Error("expected no traceparent header for an invalid context")
+	}
+}
+
+func Test_Start(t *testing.T) {
+	if span := tracing.Start(trace.SpanContext{}, "disabled"); span.IsRecording() || span.SpanContext().IsValid() {
+		t.Fatal("expected a noop span without TracerProvider")
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	tracing.Use(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer tracing.Use(trace.NewNoopTracerProvider())
+
+	parent := tracing.Start(trace.SpanContext{}, "report.receive")
+	child := tracing.StartClient(parent.SpanContext(), "target.send")
+	tracing.RecordError(child, nil)
+	tracing.RecordError(child, errors.New("timeout"))
+	child.End()
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 ended spans, got %d", len(spans))
+	}
+
+	send := spans[0]
+	if send.SpanContext().TraceID() != parent.SpanContext().TraceID() || send.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("expected child span in the trace of the parent")
+	}
+	if send.SpanKind() != trace.SpanKindClient || send.Status().Code != codes.Error || send.Status().Description != "timeout" || len(send.Events()) != 1 {
+		t.Errorf("unexpected span: %+v", send)
+	}
+}
+
+func Test_Sampling(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracing.Use(sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(recorder),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(0))),
+	))
+	defer tracing.Use(trace.NewNoopTracerProvider())
+
+	span := tracing.Start(trace.SpanContext{}, "report.receive")
+	span.End()
+
+	if span.SpanContext().IsSampled() || len(recorder.Ended()) != 0 {
+		t.Error("expected unsampled span not to be recorded")
+	}
+
+	sampled := tracing.Start(tracing.Extract("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"), "result.dispatch")
+	sampled.End()
+
+	if !sampled.SpanContext().IsSampled() || len(recorder.Ended()) != 1 {
+		t.Error("expected child span to follow the sampled parent")
+	}
+}
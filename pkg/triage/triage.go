@@ -0,0 +1,97 @@
+// Package triage tracks the investigation state of individual results with a triage status and comments,
+// so teams can acknowledge results or mark them as false positive without leaving Policy Reporter.
+package triage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Status of the investigation of a result
+type Status string
+
+const (
+	Open          Status = "open"
+	Acknowledged  Status = "acknowledged"
+	FalsePositive Status = "false-positive"
+	Fixed         Status = "fixed"
+)
+
+// Statuses lists all valid triage statuses
+var Statuses = []Status{Open, Acknowledged, FalsePositive, Fixed}
+
+// MaxCommentLength of a single comment in bytes
+const MaxCommentLength = 4096
+
+var (
+	// ErrNotFound is returned for results without triage
+	ErrNotFound = errors.New("triage not found")
+	// ErrInvalid is returned for unknown statuses and invalid comments
+	ErrInvalid = errors.New("invalid triage")
+)
+
+// Comment on the triage of a result
+type Comment struct {
+	Author  string `json:"author,omitempty"`
+	Text    string `json:"text"`
+	Created int64  `json:"created"`
+}
+
+// Triage of a result identified by the result ID
+type Triage struct {
+	ResultID  string    `json:"resultId"`
+	Status    Status    `json:"status"`
+	Comments  []Comment `json:"comments,omitempty"`
+	Updated   int64     `json:"updated"`
+	UpdatedBy string    `json:"updatedBy,omitempty"`
+}
+
+// Store persists the triage of results
+type Store interface {
+	// SaveTriage creates or replaces the triage of the result
+	SaveTriage(t Triage) error
+	// FetchTriage returns the triage of the result or ErrNotFound
+	FetchTriage(resultID string) (Triage, error)
+	// FetchTriages returns the triage of the given results by result ID, results without triage are omitted
+	FetchTriages(resultIDs []string) (map[string]Triage, error)
+}
+
+// ParseStatus returns the status of the value, case insensitive
+func ParseStatus(value string) (Status, error) {
+	for _, status := range Statuses {
+		if strings.EqualFold(string(status), strings.TrimSpace(value)) {
+			return status, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: unknown status %q, expected one of open, acknowledged, false-positive or fixed", ErrInvalid, value)
+}
+
+// Update sets the status if not empty and appends the comment if not empty, results without triage start as open
+func Update(t Triage, status Status, comment, author string, now time.Time) (Triage, error) {
+	comment = strings.TrimSpace(comment)
+	if status == "" && comment == "" {
+		return t, fmt.Errorf("%w: status or comment is required", ErrInvalid)
+	}
+	if len(comment) > MaxCommentLength {
+		return t, fmt.Errorf("%w: comment exceeds %d bytes", ErrInvalid, MaxCommentLength)
+	}
+
+	if t.Status == "" {
+		t.Status = Open
+	}
+	if status != "" {
+		t.Status = status
+	}
+	if comment != "" {
+		// copy the comments, so the previous version stays unchanged
+		t.Comments = append(append(make([]Comment, 0, len(t.Comments)+1), t.Comments...), Comment{Author: author, Text: comment, Created: now.Unix()})
+	}
+
+	t.Updated = now.Unix()
+	t.UpdatedBy = author
+
+	return t, nil
+}
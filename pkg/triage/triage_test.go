@@ -0,0 +1,48 @@
+package triage_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyverno/policy-reporter/pkg/triage"
+)
+
+func Test_ParseStatus(t *testing.T) {
+	if status, err := triage.ParseStatus(" False-Positive "); err != nil || status != triage.FalsePositive {
+		t.Errorf("expected false-positive, got %s %v", status, err)
+	}
+	if _, err := triage.ParseStatus("ignored"); !errors.Is(err, triage.ErrInvalid) {
+		t.Errorf("expected ErrInvalid for unknown statuses, got %v", err)
+	}
+}
+
+func Test_Update(t *testing.T) {
+	now := time.Now()
+
+	first, err := triage.Update(triage.Triage{ResultID: "123"}, "", "looking into it", "jane", now)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if first.Status != triage.Open || len(first.Comments) != 1 || first.UpdatedBy != "jane" {
+		t.Errorf("expected open triage with comment, got %+v", first)
+	}
+
+	second, _ := triage.Update(first, triage.Fixed, "", "john", now.Add(time.Hour))
+	if second.Status != triage.Fixed || len(second.Comments) != 1 || second.Updated != now.Add(time.Hour).Unix() {
+		t.Errorf("expected fixed triage, got %+v", second)
+	}
+
+	third, _ := triage.Update(second, "", "reopened", "", now)
+	if len(third.Comments) != 2 || len(second.Comments) != 1 {
+		t.Errorf("expected the comment appended to a copy, got %d and %d comments", len(third.Comments), len(second.Comments))
+	}
+
+	if _, err := triage.Update(first, "", " ", "jane", now); !errors.Is(err, triage.ErrInvalid) {
+		t.Errorf("expected ErrInvalid without status and comment")
+	}
+	if _, err := triage.Update(first, "", strings.Repeat("a", triage.MaxCommentLength+1), "jane", now); !errors.Is(err, triage.ErrInvalid) {
+		t.Errorf("expected ErrInvalid for long comments")
+	}
+}
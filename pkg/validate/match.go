@@ -0,0 +1,86 @@
+package validate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/kyverno/go-wildcard"
+)
+
+// RegexPrefix marks a filter value as regular expression, e.g. "regex:^kyverno|falco$"
+const RegexPrefix = "regex:"
+
+var expressions sync.Map
+
+// Match validates a value against a filter pattern.
+// Patterns prefixed with "regex:" are unanchored regular expressions, patterns with "*" or "?" are case insensitive globs,
+// all other patterns are case insensitive exact matches. Invalid regular expressions never match.
+func Match(pattern, value string) bool {
+	if IsRegex(pattern) {
+		re, err := Compile(strings.TrimPrefix(pattern, RegexPrefix))
+		if err != nil {
+			return false
+		}
+
+		return re.MatchString(value)
+	}
+
+	if IsGlob(pattern) {
+		return wildcard.Match(strings.ToLower(pattern), strings.ToLower(value))
+	}
+
+	return strings.EqualFold(pattern, value)
+}
+
+// MatchAny validates a value against a list of filter patterns, see Match
+func MatchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if Match(pattern, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsGlob returns if the pattern contains wildcards
+func IsGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?")
+}
+
+// IsRegex returns if the pattern is a regular expression
+func IsRegex(pattern string) bool {
+	return strings.HasPrefix(pattern, RegexPrefix)
+}
+
+// ValidatePattern returns an error for invalid regular expressions
+func ValidatePattern(pattern string) error {
+	if !IsRegex(pattern) {
+		return nil
+	}
+
+	expr := strings.TrimPrefix(pattern, RegexPrefix)
+	if _, err := Compile(expr); err != nil {
+		return fmt.Errorf("invalid regular expression %q: %w", expr, err)
+	}
+
+	return nil
+}
+
+// Compile returns the cached regular expression of expr
+func Compile(expr string) (*regexp.Regexp, error) {
+	if re, ok := expressions.Load(expr); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	expressions.Store(expr, re)
+
+	return re, nil
+}
@@ -1,9 +1,7 @@
 package validate
 
 import (
-	"github.com/kyverno/go-wildcard"
-
-	"github.com/kyverno/policy-reporter/pkg/helper"
+	"strings"
 )
 
 func Namespace(namespace string, namespaces RuleSets) bool {
@@ -14,32 +12,51 @@ func Namespace(namespace string, namespaces RuleSets) bool {
 	return MatchRuleSet(namespace, namespaces)
 }
 
+// MatchRuleSet validates a value against include and exclude patterns, see Match for the supported syntax
 func MatchRuleSet(value string, rules RuleSets) bool {
 	if len(rules.Include) > 0 {
-		for _, ns := range rules.Include {
-			if wildcard.Match(ns, value) {
-				return true
-			}
-		}
-
-		return false
+		return MatchAny(rules.Include, value)
 	} else if len(rules.Exclude) > 0 {
-		for _, ns := range rules.Exclude {
-			if wildcard.Match(ns, value) {
-				return false
-			}
-		}
+		return !MatchAny(rules.Exclude, value)
 	}
 
 	return true
 }
 
+// ContainsRuleSet is an alias of MatchRuleSet
+//
+// Deprecated: use MatchRuleSet, both support the same pattern syntax
 func ContainsRuleSet(value string, rules RuleSets) bool {
+	return MatchRuleSet(value, rules)
+}
+
+// MatchLabels validates a label set against "key:value" rules, the value supports patterns and defaults to "*"
+func MatchLabels(labels map[string]string, rules RuleSets) bool {
 	if len(rules.Include) > 0 {
-		return helper.Contains(value, rules.Include)
-	} else if len(rules.Exclude) > 0 && helper.Contains(value, rules.Exclude) {
-		return false
+		return containsLabel(labels, rules.Include)
+	} else if len(rules.Exclude) > 0 {
+		return !containsLabel(labels, rules.Exclude)
 	}
 
 	return true
 }
+
+func containsLabel(labels map[string]string, rules []string) bool {
+	for _, label := range rules {
+		parts := strings.SplitN(label, ":", 2)
+		if len(parts) == 1 {
+			parts = append(parts, "*")
+		}
+
+		labelName := strings.TrimSpace(parts[0])
+		labelValue := strings.TrimSpace(parts[1])
+
+		for key, value := range labels {
+			if labelName == key && Match(labelValue, value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
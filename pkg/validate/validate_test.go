@@ -89,6 +89,37 @@ func Test_Validations(t *testing.T) {
 	})
 }
 
+func Test_MatchLabels(t *testing.T) {
+	labels := map[string]string{"policy-exemption": "true", "team": "team-a"}
+
+	t.Run("MatchLabels include", func(t *testing.T) {
+		if !validate.MatchLabels(labels, validate.RuleSets{Include: []string{"team:team-*"}}) {
+			t.Errorf("Unexpected Validation Result")
+		}
+		if validate.MatchLabels(labels, validate.RuleSets{Include: []string{"team:team-b"}}) {
+			t.Errorf("Unexpected Validation Result")
+		}
+	})
+	t.Run("MatchLabels exclude", func(t *testing.T) {
+		if validate.MatchLabels(labels, validate.RuleSets{Exclude: []string{"policy-exemption:true"}}) {
+			t.Errorf("Unexpected Validation Result")
+		}
+		if !validate.MatchLabels(map[string]string{}, validate.RuleSets{Exclude: []string{"policy-exemption"}}) {
+			t.Errorf("Unexpected Validation Result")
+		}
+	})
+	t.Run("MatchLabels key only", func(t *testing.T) {
+		if !validate.MatchLabels(labels, validate.RuleSets{Include: []string{"team"}}) {
+			t.Errorf("Unexpected Validation Result")
+		}
+	})
+	t.Run("MatchLabels empty rules", func(t *testing.T) {
+		if !validate.MatchLabels(nil, validate.RuleSets{}) {
+			t.Errorf("Unexpected Validation Result")
+		}
+	})
+}
+
 func Test_RulesCount(t *testing.T) {
 	r1 := validate.RuleSets{}
 	if r1.Count() != 0 {
@@ -100,3 +131,63 @@ func Test_RulesCount(t *testing.T) {
 		t.Errorf("Unexpected Rules.Count")
 	}
 }
+
+func Test_Match(t *testing.T) {
+	cases := []struct {
+		pattern string
+		value   string
+		match   bool
+	}{
+		{"kyverno", "Kyverno", true},
+		{"kyverno", "kyverno-json", false},
+		{"kyv*", "Kyverno", true},
+		{"team-?", "team-a", true},
+		{"team-?", "team-ab", false},
+		{"regex:^team-(a|b)$", "team-b", true},
+		{"regex:^team-(a|b)$", "team-c", false},
+		{"regex:ver", "kyverno", true},
+		{"regex:^Kyverno$", "kyverno", false},
+		{"regex:(?i)^Kyverno$", "kyverno", true},
+		{"regex:(", "(", false},
+	}
+
+	for _, c := range cases {
+		if validate.Match(c.pattern, c.value) != c.match {
+			t.Errorf("expected Match(%q, %q) to be %v", c.pattern, c.value, c.match)
+		}
+	}
+
+	if !validate.MatchAny([]string{"falco", "regex:^kyv"}, "kyverno") {
+		t.Error("expected match of any pattern")
+	}
+	if validate.MatchAny(nil, "kyverno") {
+		t.Error("expected no match without patterns")
+	}
+}
+
+func Test_ValidatePattern(t *testing.T) {
+	for _, pattern := range []string{"kyverno", "kyv*", "regex:^kyv(erno)?$"} {
+		if err := validate.ValidatePattern(pattern); err != nil {
+			t.Errorf("unexpected error for %q: %s", pattern, err)
+		}
+	}
+
+	if err := validate.ValidatePattern("regex:(kyverno"); err == nil {
+		t.Error("expected error for invalid regular expression")
+	}
+}
+
+func Test_MatchRuleSetPatterns(t *testing.T) {
+	if !validate.MatchRuleSet("Kyverno", validate.RuleSets{Include: []string{"regex:^Kyv"}}) {
+		t.Errorf("Unexpected Validation Result")
+	}
+	if validate.MatchRuleSet("policy-reporter", validate.RuleSets{Exclude: []string{"regex:-reporter$"}}) {
+		t.Errorf("Unexpected Validation Result")
+	}
+	if validate.MatchLabels(map[string]string{"team": "team-a"}, validate.RuleSets{Include: []string{"team:regex:^team-[bc]$"}}) {
+		t.Errorf("Unexpected Validation Result")
+	}
+	if !validate.MatchLabels(map[string]string{"team": "team-b"}, validate.RuleSets{Include: []string{"team:regex:^team-[bc]$"}}) {
+		t.Errorf("Unexpected Validation Result")
+	}
+}
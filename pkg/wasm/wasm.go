@@ -0,0 +1,239 @@
+// Package wasm runs WASI modules as filter and transformer plugins on each new result before it is dispatched to the targets.
+//
+// The modules are executed in process by the wazero runtime, so they run sandboxed without access to the
+// filesystem, the network or the environment of Policy Reporter. Each module is compiled once before the first
+// result is processed, each result instantiates the compiled module. A module reads an Input as JSON from stdin
+// and writes an Output as JSON to stdout. An empty output keeps the result unchanged, "drop" suppresses the
+// notification of the result and "result" replaces the result with the transformed one.
+package wasm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/logging"
+	"github.com/kyverno/policy-reporter/pkg/report"
+	"github.com/kyverno/policy-reporter/pkg/validate"
+)
+
+var logger = logging.Component("wasm")
+
+// DefaultTimeout of a single module execution
+const DefaultTimeout = 5 * time.Second
+
+// Module is a WASI module applied to the results of the configured sources
+type Module struct {
+	Name string
+	Path string
+	// Args passed to the module after its name
+	Args []string
+	// Sources of the processed results with wildcard and "regex:" support, all sources if empty
+	Sources []string
+}
+
+// Report of the result, passed to the module
+type Report struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace,omitempty"`
+	Source    string            `json:"source,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+// Input written to the stdin of a module
+type Input struct {
+	Report Report                      `json:"report"`
+	Result v1alpha2.PolicyReportResult `json:"result"`
+}
+
+// Output read from the stdout of a module
+type Output struct {
+	Drop   bool                         `json:"drop,omitempty"`
+	Result *v1alpha2.PolicyReportResult `json:"result,omitempty"`
+}
+
+// Options of the Pipeline
+type Options struct {
+	// Timeout of a single module execution, defaults to DefaultTimeout
+	Timeout time.Duration
+	// CacheDir persists the compiled modules across restarts, the modules are compiled on each start if empty
+	CacheDir string
+	Modules  []Module
+}
+
+// Pipeline applies the modules in order, a dropped result is not passed to the following modules.
+// Failing modules are skipped, so a broken plugin does not suppress notifications
+type Pipeline struct {
+	options Options
+	once    sync.Once
+	runtime wazero.Runtime
+	// compiled module of each module, nil for modules which failed to compile
+	compiled []wazero.CompiledModule
+}
+
+// compile creates the runtime and compiles each module. Modules which can not be read or compiled
+// are skipped instead of failing the execution of every result
+func (p *Pipeline) compile() {
+	ctx := context.Background()
+	p.compiled = make([]wazero.CompiledModule, len(p.options.Modules))
+
+	config := wazero.NewRuntimeConfig().WithCloseOnContextDone(true)
+	if p.options.CacheDir != "" {
+		cache, err := wazero.NewCompilationCacheWithDir(p.options.CacheDir)
+		if err != nil {
+			logger.Warnf("failed to create the cache for the compiled wasm modules in %s: %s", p.options.CacheDir, err)
+		} else {
+			config = config.WithCompilationCache(cache)
+		}
+	}
+
+	p.runtime = wazero.NewRuntimeWithConfig(ctx, config)
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, p.runtime); err != nil {
+		logger.Errorf("failed to instantiate WASI, all wasm modules are skipped: %s", err)
+		return
+	}
+
+	for i, module := range p.options.Modules {
+		code, err := os.ReadFile(module.Path)
+		if err != nil {
+			logger.Errorf("wasm module %s is skipped, failed to read it: %s", module.Name, err)
+			continue
+		}
+
+		compiled, err := p.runtime.CompileModule(ctx, code)
+		if err != nil {
+			logger.Errorf("wasm module %s is skipped, failed to compile it: %s", module.Name, err)
+			continue
+		}
+
+		logger.Infof("wasm module %s compiled", module.Name)
+		p.compiled[i] = compiled
+	}
+}
+
+// Process applies the modules to the result, returns false if a module dropped the result
+func (p *Pipeline) Process(rep v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) (v1alpha2.PolicyReportResult, bool) {
+	p.once.Do(p.compile)
+
+	for i, module := range p.options.Modules {
+		if p.compiled[i] == nil {
+			continue
+		}
+		if len(module.Sources) > 0 && !validate.MatchAny(module.Sources, result.Source) {
+			continue
+		}
+
+		output, err := p.run(module, p.compiled[i], rep, result)
+		if err != nil {
+			logger.Errorf("wasm module %s failed for result %s: %s", module.Name, result.GetID(), err)
+			continue
+		}
+		if output.Drop {
+			logger.Debugf("wasm module %s dropped result %s", module.Name, result.GetID())
+			return result, false
+		}
+		if output.Result != nil {
+			result = transformed(result, *output.Result)
+		}
+	}
+
+	return result, true
+}
+
+// Results wraps a result listener, only results kept by the modules are forwarded in their transformed version
+func (p *Pipeline) Results(callback report.PolicyReportResultListener) report.PolicyReportResultListener {
+	return func(rep v1alpha2.ReportInterface, r v1alpha2.PolicyReportResult, preExisted bool) {
+		if result, ok := p.Process(rep, r); ok {
+			callback(rep, result, preExisted)
+		}
+	}
+}
+
+func (p *Pipeline) run(module Module, compiled wazero.CompiledModule, rep v1alpha2.ReportInterface, result v1alpha2.PolicyReportResult) (Output, error) {
+	payload, err := json.Marshal(Input{
+		Report: Report{
+			ID:        rep.GetID(),
+			Name:      rep.GetName(),
+			Namespace: rep.GetNamespace(),
+			Source:    rep.GetSource(),
+			Labels:    rep.GetLabels(),
+		},
+		Result: result,
+	})
+	if err != nil {
+		return Output{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.options.Timeout)
+	defer cancel()
+
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+
+	// anonymous instances, so the module can be instantiated for concurrent results
+	config := wazero.NewModuleConfig().
+		WithName("").
+		WithArgs(append([]string{module.Name}, module.Args...)...).
+		WithStdin(bytes.NewReader(payload)).
+		WithStdout(stdout).
+		WithStderr(stderr)
+
+	instance, err := p.runtime.InstantiateModule(ctx, compiled, config)
+	if instance != nil {
+		instance.Close(ctx)
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return Output{}, fmt.Errorf("timeout after %s", p.options.Timeout)
+	}
+
+	exit := &sys.ExitError{}
+	if errors.As(err, &exit) && exit.ExitCode() == 0 {
+		err = nil
+	}
+	if err != nil {
+		return Output{}, fmt.Errorf("%w %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	output := Output{}
+
+	out := bytes.TrimSpace(stdout.Bytes())
+	if len(out) == 0 {
+		return output, nil
+	}
+	if err := json.Unmarshal(out, &output); err != nil {
+		return Output{}, fmt.Errorf("invalid output: %w", err)
+	}
+
+	return output, nil
+}
+
+// transformed keeps the internal fields of the result which are not part of the JSON representation
+func transformed(original, result v1alpha2.PolicyReportResult) v1alpha2.PolicyReportResult {
+	result.ID = original.ID
+	result.Priority = original.Priority
+	result.TraceParent = original.TraceParent
+
+	return result
+}
+
+// NewPipeline creates a Pipeline for the modules, returns nil without modules
+func NewPipeline(options Options) *Pipeline {
+	if len(options.Modules) == 0 {
+		return nil
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultTimeout
+	}
+
+	return &Pipeline{options: options}
+}
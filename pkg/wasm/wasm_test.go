@@ -0,0 +1,262 @@
+package wasm_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyverno/policy-reporter/pkg/crd/api/policyreport/v1alpha2"
+	"github.com/kyverno/policy-reporter/pkg/wasm"
+)
+
+// WASI modules encoded in the binary format, the memory layout is the iovec at 0, the result count at 8 and the data at 16
+
+func uleb(v uint32) []byte {
+	b := make([]byte, 0, 5)
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+func sleb(v int32) []byte {
+	b := make([]byte, 0, 5)
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && c&0x40 == 0) || (v == -1 && c&0x40 != 0) {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+func name(n string) []byte {
+	return append(uleb(uint32(len(n))), n...)
+}
+
+func section(id byte, content ...byte) []byte {
+	return append(append([]byte{id}, uleb(uint32(len(content)))...), content...)
+}
+
+func i32(v int32) []byte {
+	return append([]byte{0x41}, sleb(v)...)
+}
+
+func concat(parts ...[]byte) []byte {
+	b := make([]byte, 0)
+	for _, p := range parts {
+		b = append(b, p...)
+	}
+
+	return b
+}
+
+const (
+	fdWrite  = 0
+	fdRead   = 1
+	procExit = 2
+)
+
+// iovec stores the buffer of the next read or write
+func iovec(length []byte) []byte {
+	return concat(i32(0), i32(16), []byte{0x36, 0x02, 0x00}, i32(4), length, []byte{0x36, 0x02, 0x00})
+}
+
+// call a WASI function with the fd and the iovec, the result count is ignored
+func call(fn byte, fd int32) []byte {
+	return concat(i32(fd), i32(0), i32(1), i32(8), []byte{0x10, fn, 0x1a})
+}
+
+// module with the given code of _start and the data at offset 16
+func module(code []byte, data string) []byte {
+	fn := concat(
+		[]byte{3},
+		[]byte{0x60, 4, 0x7f, 0x7f, 0x7f, 0x7f, 1, 0x7f},
+		[]byte{0x60, 0, 0},
+		[]byte{0x60, 1, 0x7f, 0},
+	)
+	imports := concat(
+		[]byte{3},
+		name("wasi_snapshot_preview1"), name("fd_write"), []byte{0x00, 0},
+		name("wasi_snapshot_preview1"), name("fd_read"), []byte{0x00, 0},
+		name("wasi_snapshot_preview1"), name("proc_exit"), []byte{0x00, 2},
+	)
+	exports := concat([]byte{2}, name("memory"), []byte{0x02, 0}, name("_start"), []byte{0x00, 3})
+	body := concat([]byte{0}, code, []byte{0x0b})
+
+	return concat(
+		[]byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00},
+		section(1, fn...),
+		section(2, imports...),
+		section(3, 1, 1),
+		section(5, 1, 0x00, 1),
+		section(7, exports...),
+		section(10, concat([]byte{1}, uleb(uint32(len(body))), body)...),
+		section(11, concat([]byte{1, 0x00}, i32(16), []byte{0x0b}, name(data))...),
+	)
+}
+
+// output writes the data to stdout and exits with the code
+func output(data string, code int32) []byte {
+	return module(concat(iovec(i32(int32(len(data)))), call(fdWrite, 1), i32(code), []byte{0x10, procExit}), data)
+}
+
+// echo copies stdin to stdout
+func echo() []byte {
+	return module(concat(
+		[]byte{0x02, 0x40, 0x03, 0x40},
+		iovec(i32(60000)),
+		call(fdRead, 0),
+		// stop at the end of stdin
+		i32(8), []byte{0x28, 0x02, 0x00, 0x45, 0x0d, 1},
+		iovec(concat(i32(8), []byte{0x28, 0x02, 0x00})),
+		call(fdWrite, 1),
+		[]byte{0x0c, 0, 0x0b, 0x0b},
+	), "")
+}
+
+// spin never returns
+func spin() []byte {
+	return module([]byte{0x03, 0x40, 0x0c, 0, 0x0b}, "")
+}
+
+func write(t *testing.T, file string, code []byte) string {
+	path := filepath.Join(t.TempDir(), file)
+	if err := os.WriteFile(path, code, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+var rep = &v1alpha2.PolicyReport{
+	ObjectMeta: metav1.ObjectMeta{Name: "polr-test", Namespace: "test", Labels: map[string]string{"app": "policy-reporter"}},
+}
+
+var result = v1alpha2.PolicyReportResult{
+	ID:       "123",
+	Source:   "kyverno",
+	Policy:   "require-labels",
+	Result:   v1alpha2.StatusFail,
+	Priority: v1alpha2.WarningPriority,
+}
+
+func Test_Pipeline(t *testing.T) {
+	t.Run("Transform", func(t *testing.T) {
+		pipeline := wasm.NewPipeline(wasm.Options{
+			Modules: []wasm.Module{
+				{Name: "enrich", Path: write(t, "enrich.wasm", output(`{"result":{"source":"kyverno","policy":"require-labels","result":"fail","properties":{"team":"platform"}}}`, 0))},
+				{Name: "echo", Path: write(t, "echo.wasm", echo())},
+				{Name: "trivy", Path: write(t, "trivy.wasm", output(`{"drop":true}`, 0)), Sources: []string{"trivy*"}},
+			},
+			CacheDir: t.TempDir(),
+		})
+
+		var sent []v1alpha2.PolicyReportResult
+		pipeline.Results(func(_ v1alpha2.ReportInterface, res v1alpha2.PolicyReportResult, _ bool) {
+			sent = append(sent, res)
+		})(rep, result, false)
+
+		if len(sent) != 1 {
+			t.Fatalf("expected the result not to be dropped by the module of another source, got %+v", sent)
+		}
+		if sent[0].Properties["team"] != "platform" {
+			t.Error("expected the transformed result to be passed to the next module")
+		}
+		if sent[0].GetID() != "123" || sent[0].Priority != v1alpha2.WarningPriority {
+			t.Errorf("expected the internal fields to be kept, got %+v", sent[0])
+		}
+	})
+
+	t.Run("Drop", func(t *testing.T) {
+		pipeline := wasm.NewPipeline(wasm.Options{Modules: []wasm.Module{{Name: "suppress", Path: write(t, "suppress.wasm", output(`{"drop":true}`, 0))}}})
+
+		if _, ok := pipeline.Process(rep, result); ok {
+			t.Error("expected the result to be dropped")
+		}
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		pipeline := wasm.NewPipeline(wasm.Options{Modules: []wasm.Module{{Name: "broken", Path: write(t, "broken.wasm", output(`{"drop":true}`, 1))}}})
+
+		if res, ok := pipeline.Process(rep, result); !ok || res.Policy != result.Policy {
+			t.Error("expected a failing module to keep the result")
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		pipeline := wasm.NewPipeline(wasm.Options{Modules: []wasm.Module{{Name: "spin", Path: write(t, "spin.wasm", spin())}}, Timeout: 100 * time.Millisecond})
+
+		start := time.Now()
+		if _, ok := pipeline.Process(rep, result); !ok {
+			t.Error("expected a timed out module to keep the result")
+		}
+		if time.Since(start) > 10*time.Second {
+			t.Error("expected the module to be stopped after the timeout")
+		}
+	})
+
+	t.Run("CompileOnce", func(t *testing.T) {
+		keep := write(t, "keep.wasm", output("", 0))
+
+		pipeline := wasm.NewPipeline(wasm.Options{Modules: []wasm.Module{
+			{Name: "invalid", Path: write(t, "invalid.wasm", []byte("drop"))},
+			{Name: "suppress", Path: keep},
+		}})
+
+		if _, ok := pipeline.Process(rep, result); !ok {
+			t.Error("expected the result to be kept")
+		}
+
+		// the compiled module is instantiated for the following results
+		if err := os.WriteFile(keep, output(`{"drop":true}`, 0), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < 3; i++ {
+			if _, ok := pipeline.Process(rep, result); !ok {
+				t.Error("expected the result to be kept by the compiled module")
+			}
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		pipeline := wasm.NewPipeline(wasm.Options{Modules: []wasm.Module{{Name: "echo", Path: write(t, "echo.wasm", echo())}}})
+
+		wg := &sync.WaitGroup{}
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				if res, ok := pipeline.Process(rep, result); !ok || res.Policy != result.Policy {
+					t.Error("expected the echoed result to be kept")
+				}
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("InvalidOutput", func(t *testing.T) {
+		pipeline := wasm.NewPipeline(wasm.Options{Modules: []wasm.Module{{Name: "invalid", Path: write(t, "invalid.wasm", output("drop", 0))}}})
+
+		if _, ok := pipeline.Process(rep, result); !ok {
+			t.Error("expected an invalid output to keep the result")
+		}
+	})
+
+	t.Run("WithoutModules", func(t *testing.T) {
+		if wasm.NewPipeline(wasm.Options{}) != nil {
+			t.Error("expected no pipeline without modules")
+		}
+	})
+}